@@ -0,0 +1,193 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// AdaptiveVolumeConfig turns on closed-loop playback volume: the ambient
+// noise level is metered from a microphone between clips, playback gain
+// follows it, and the level actually heard during each clip corrects the
+// gain until the target signal to noise ratio is reached.
+type AdaptiveVolumeConfig struct {
+	// Input is the Audio resource to meter, the output itself if empty.
+	Input       string  `json:"input,omitempty"`
+	TargetSNRDB float64 `json:"target_snr_db,omitempty"` // 15 if zero
+	MinGainDB   float64 `json:"min_gain_db,omitempty"`   // -20 if zero
+	MaxGainDB   float64 `json:"max_gain_db,omitempty"`   // 12 if zero
+	// SmoothingMs is the time constant of the ambient level, so a door slam
+	// doesn't make the robot shout.
+	SmoothingMs int `json:"smoothing_ms,omitempty"` // 3s if zero
+	// MaxStepDB limits how much one clip's measurement can change the gain.
+	MaxStepDB float64 `json:"max_step_db,omitempty"` // 3 if zero
+}
+
+// Defaults for AdaptiveVolumeConfig.
+const (
+	defaultAdaptiveSNR       = 15
+	defaultAdaptiveMinGain   = -20
+	defaultAdaptiveMaxGain   = 12
+	defaultAdaptiveSmoothing = 3 * time.Second
+	defaultAdaptiveMaxStep   = 3
+	adaptiveTail             = 300 * time.Millisecond // playback still ringing in the room after Play returns
+	adaptiveFloorDBFS        = -70                    // quieter ambient is treated as this
+)
+
+func (c *AdaptiveVolumeConfig) validate() error {
+	if c.MinGainDB != 0 && c.MaxGainDB != 0 && c.MinGainDB > c.MaxGainDB {
+		return errors.New("min_gain_db is above max_gain_db")
+	}
+	if c.SmoothingMs < 0 || c.MaxStepDB < 0 {
+		return errors.New("smoothing_ms and max_step_db cannot be negative")
+	}
+	return nil
+}
+
+// adaptiveVolume meters an input and computes the playback gain.
+type adaptiveVolume struct {
+	cfg    AdaptiveVolumeConfig
+	logger logging.Logger
+
+	mu        sync.Mutex
+	ambient   float64 // smoothed ambient power, zero until metered
+	offset    float64 // learned gain above the ambient level in dB
+	learned   bool
+	playing   int       // clips in flight
+	quietAt   time.Time // when the last clip stopped ringing
+	heard     float64   // power metered during the current clips
+	heardN    int
+	heardFrom float64 // ambient power when the clips started
+}
+
+func newAdaptiveVolume(cfg AdaptiveVolumeConfig, logger logging.Logger) *adaptiveVolume {
+	if cfg.TargetSNRDB == 0 {
+		cfg.TargetSNRDB = defaultAdaptiveSNR
+	}
+	if cfg.MinGainDB == 0 {
+		cfg.MinGainDB = defaultAdaptiveMinGain
+	}
+	if cfg.MaxGainDB == 0 {
+		cfg.MaxGainDB = defaultAdaptiveMaxGain
+	}
+	if cfg.SmoothingMs == 0 {
+		cfg.SmoothingMs = int(defaultAdaptiveSmoothing / time.Millisecond)
+	}
+	if cfg.MaxStepDB == 0 {
+		cfg.MaxStepDB = defaultAdaptiveMaxStep
+	}
+	return &adaptiveVolume{cfg: cfg, logger: logger}
+}
+
+// meter follows the input's level until ctx is done, reopening the capture
+// if it ends.
+func (v *adaptiveVolume) meter(ctx context.Context, input Audio) {
+	for {
+		if err := v.meterOnce(ctx, input); err != nil && ctx.Err() == nil {
+			v.logger.Warnf("ambient noise metering stopped: %v", err)
+		}
+		if sleepCtx(ctx, time.Second) != nil {
+			return
+		}
+	}
+}
+
+// meterOnce meters one capture session of input at its own rate and layout,
+// measured the same way the capture path's silence trimming measures it.
+func (v *adaptiveVolume) meterOnce(ctx context.Context, input Audio) error {
+	chunks, err := sharedCaptureHub.open(ctx, input, captureRequest{target: AudioInfo{Format: Pcm16}})
+	if err != nil {
+		return err
+	}
+	for {
+		var chunk *AudioChunk
+		var ok bool
+		select {
+		case chunk, ok = <-chunks:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if !ok {
+			return nil
+		}
+		if chunk.Err != nil {
+			return chunk.Err
+		}
+		dur, ok := chunkDuration(chunk)
+		if !ok || dur == 0 {
+			continue // a gap, or a source that doesn't report its format
+		}
+		level, err := chunkRMS(chunk)
+		if err != nil {
+			return err
+		}
+		v.add(level*level, dur)
+	}
+}
+
+// add takes the power of one block of captured audio.
+func (v *adaptiveVolume) add(power float64, dur time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.playing > 0 {
+		v.heard += power
+		v.heardN++
+		return
+	}
+	if time.Now().Before(v.quietAt) {
+		return
+	}
+	floor := math.Pow(10, adaptiveFloorDBFS/10)
+	power = math.Max(power, floor)
+	if v.ambient == 0 {
+		v.ambient = power
+		return
+	}
+	alpha := 1 - math.Exp(-dur.Seconds()/(float64(v.cfg.SmoothingMs)/1000))
+	v.ambient += alpha * (power - v.ambient)
+}
+
+// start returns the gain in dB for the next clip and begins metering what
+// is heard while it plays.
+func (v *adaptiveVolume) start() float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.playing == 0 {
+		v.heard, v.heardN, v.heardFrom = 0, 0, v.ambient
+	}
+	v.playing++
+	if v.ambient == 0 {
+		return 0 // nothing metered yet
+	}
+	ambientDB := 10 * math.Log10(v.ambient)
+	if !v.learned {
+		v.offset, v.learned = -ambientDB, true
+	}
+	return math.Max(v.cfg.MinGainDB, math.Min(v.cfg.MaxGainDB, ambientDB+v.offset))
+}
+
+// done ends a clip started with start and corrects the gain by the signal to
+// noise ratio that was heard.
+func (v *adaptiveVolume) done() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.playing--
+	v.quietAt = time.Now().Add(adaptiveTail)
+	if v.playing > 0 || v.heardN == 0 || v.heardFrom == 0 || !v.learned {
+		return
+	}
+	signal := v.heard/float64(v.heardN) - v.heardFrom
+	snr := -120.0
+	if signal > 0 {
+		snr = 10 * math.Log10(signal/v.heardFrom)
+	}
+	step := math.Max(-v.cfg.MaxStepDB, math.Min(v.cfg.MaxStepDB, v.cfg.TargetSNRDB-snr))
+	// don't wind up past the limits, or the gain would stick there long after the room changed
+	ambientDB := 10 * math.Log10(v.heardFrom)
+	v.offset = math.Max(v.cfg.MinGainDB-ambientDB, math.Min(v.cfg.MaxGainDB-ambientDB, v.offset+step))
+	v.logger.Debugf("heard %.1f dB over ambient %.1f dBFS, adaptive gain now %+.1f dB", snr, ambientDB, ambientDB+v.offset)
+}
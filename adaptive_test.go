@@ -0,0 +1,95 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// adaptiveRoom plays clips through v into a room with a fixed ambient level
+// and returns the gain v chose for each clip and the SNR heard during it.
+// sensitivityDB is the level, in dBFS, a clip played at 0 dB gain is heard at.
+func adaptiveRoom(v *adaptiveVolume, ambientDBFS, sensitivityDB float64, clips int) (gains, snrs []float64) {
+	ambient := math.Pow(10, ambientDBFS/10)
+	for i := 0; i < clips; i++ {
+		gain := v.start()
+		signal := math.Pow(10, (sensitivityDB+gain)/10)
+		v.add(ambient+signal, 100*time.Millisecond)
+		v.add(ambient+signal, 100*time.Millisecond)
+		v.done()
+		gains = append(gains, gain)
+		snrs = append(snrs, 10*math.Log10(signal/ambient))
+	}
+	return gains, snrs
+}
+
+func TestAdaptiveVolumeConverges(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		ambient       float64
+		sensitivityDB float64
+	}{
+		{"quiet room", -60, -50},
+		{"loud room", -30, -25},
+		{"loud speaker", -50, -20},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			v := newAdaptiveVolume(AdaptiveVolumeConfig{}, logging.NewTestLogger(t))
+			v.add(math.Pow(10, tc.ambient/10), time.Second)
+			_, snrs := adaptiveRoom(v, tc.ambient, tc.sensitivityDB, 30)
+			if got := snrs[len(snrs)-1]; math.Abs(got-defaultAdaptiveSNR) > 0.5 {
+				t.Fatalf("heard %.1f dB over ambient after 30 clips, want %d", got, defaultAdaptiveSNR)
+			}
+		})
+	}
+}
+
+func TestAdaptiveVolumeStepsAreLimited(t *testing.T) {
+	v := newAdaptiveVolume(AdaptiveVolumeConfig{MaxStepDB: 2}, logging.NewTestLogger(t))
+	v.add(math.Pow(10, -60.0/10), time.Second)
+	gains, _ := adaptiveRoom(v, -60, -70, 5)
+	for i := 1; i < len(gains); i++ {
+		if step := gains[i] - gains[i-1]; step > 2+1e-9 || step < 0 {
+			t.Fatalf("gain went from %.1f to %.1f dB, want steps up of at most 2 dB", gains[i-1], gains[i])
+		}
+	}
+}
+
+func TestAdaptiveVolumeClamps(t *testing.T) {
+	cfg := AdaptiveVolumeConfig{MinGainDB: -10, MaxGainDB: 6}
+	v := newAdaptiveVolume(cfg, logging.NewTestLogger(t))
+	v.add(math.Pow(10, -40.0/10), time.Second)
+
+	// a speaker too quiet to ever reach the target stays at the maximum
+	gains, _ := adaptiveRoom(v, -40, -60, 30)
+	if got := gains[len(gains)-1]; got != cfg.MaxGainDB {
+		t.Fatalf("gain %.1f dB for a quiet speaker, want the %.1f dB maximum", got, cfg.MaxGainDB)
+	}
+	// the gain didn't wind up past the limit, so it comes down on the next clip
+	gains, _ = adaptiveRoom(v, -40, 0, 2)
+	if want := cfg.MaxGainDB - defaultAdaptiveMaxStep; gains[1] != want {
+		t.Fatalf("gain %.1f dB one clip after the speaker got loud, want %.1f", gains[1], want)
+	}
+	gains, _ = adaptiveRoom(v, -40, 0, 30)
+	if got := gains[len(gains)-1]; got != cfg.MinGainDB {
+		t.Fatalf("gain %.1f dB for a loud speaker, want the %.1f dB minimum", got, cfg.MinGainDB)
+	}
+}
+
+func TestAdaptiveVolumeValidate(t *testing.T) {
+	for _, tc := range []struct {
+		cfg AdaptiveVolumeConfig
+		ok  bool
+	}{
+		{AdaptiveVolumeConfig{}, true},
+		{AdaptiveVolumeConfig{MinGainDB: 3, MaxGainDB: -3}, false},
+		{AdaptiveVolumeConfig{SmoothingMs: -1}, false},
+		{AdaptiveVolumeConfig{MaxStepDB: -1}, false},
+	} {
+		if err := tc.cfg.validate(); (err == nil) != tc.ok {
+			t.Errorf("%+v: got error %v, want ok %v", tc.cfg, err, tc.ok)
+		}
+	}
+}
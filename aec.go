@@ -0,0 +1,351 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// Defaults for the aec model.
+const (
+	defaultEchoTail = 100 * time.Millisecond
+	// how much played audio is kept as reference for captures to catch up on
+	echoReferenceKeep = 2 * time.Second
+	echoStep          = 0.2 // NLMS step size, small enough not to chase near-end talk
+	// reference power per tap below which the filter holds still
+	echoSilence = 1e-8
+)
+
+// echoCanceller removes the echo of a reference signal from one channel of
+// capture with a normalized LMS adaptive filter spanning the echo tail. The
+// filter only adapts while the reference is playing, so it doesn't unlearn
+// the room during silence.
+type echoCanceller struct {
+	w     []float64
+	x     []float64 // reference history twice over, newest first from pos
+	pos   int
+	power float64 // sum of squares of the history window
+}
+
+func newEchoCanceller(taps int) *echoCanceller {
+	return &echoCanceller{w: make([]float64, taps), x: make([]float64, 2*taps)}
+}
+
+// process takes the next reference and capture sample and returns the
+// capture with the echo estimate removed.
+func (e *echoCanceller) process(ref, mic float32) float32 {
+	taps := len(e.w)
+	e.pos = (e.pos - 1 + taps) % taps
+	old := e.x[e.pos]
+	e.x[e.pos], e.x[e.pos+taps] = float64(ref), float64(ref)
+	e.power += float64(ref)*float64(ref) - old*old
+	if e.power < 0 {
+		e.power = 0 // rounding
+	}
+	window := e.x[e.pos : e.pos+taps]
+
+	var y float64
+	for i, w := range e.w {
+		y += w * window[i]
+	}
+	err := float64(mic) - y
+	if e.power > echoSilence*float64(taps) {
+		k := echoStep * err / e.power
+		for i := range e.w {
+			e.w[i] += k * window[i]
+		}
+	}
+	return float32(err)
+}
+
+// echoReference is the timeline of what was played, mixed to mono at the
+// capture rate, for the cancellers to line up with the capture.
+type echoReference struct {
+	mu      sync.Mutex
+	rate    int       // zero until a capture reports its rate
+	start   time.Time // time of samples[0]
+	samples []float32
+}
+
+// setRate starts the reference over at a new capture rate.
+func (r *echoReference) setRate(rate int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rate != rate {
+		r.rate, r.samples = rate, nil
+	}
+}
+
+// captureRate returns the rate references have to be written at, zero if
+// nothing is capturing.
+func (r *echoReference) captureRate() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rate
+}
+
+// write adds mono audio at rate played from at, or after the audio already
+// queued if that ends later.
+func (r *echoReference) write(at time.Time, rate int, samples []float32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rate != r.rate {
+		return
+	}
+	if len(r.samples) == 0 {
+		r.start = at
+	}
+	end := r.start.Add(r.duration(len(r.samples)))
+	if gap := r.frames(at.Sub(end)); gap > 0 {
+		r.samples = append(r.samples, make([]float32, gap)...)
+	}
+	r.samples = append(r.samples, samples...)
+	if drop := len(r.samples) - r.frames(echoReferenceKeep); drop > 0 {
+		r.samples = append(r.samples[:0:0], r.samples[drop:]...)
+		r.start = r.start.Add(r.duration(drop))
+	}
+}
+
+// read returns n samples of the reference from at, silent where nothing was
+// played.
+func (r *echoReference) read(at time.Time, n int) []float32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]float32, n)
+	if len(r.samples) == 0 {
+		return out
+	}
+	from := r.frames(at.Sub(r.start))
+	for i := max(0, -from); i < n && from+i < len(r.samples); i++ {
+		out[i] = r.samples[from+i]
+	}
+	return out
+}
+
+func (r *echoReference) frames(d time.Duration) int {
+	return int(d.Seconds()*float64(r.rate) + 0.5)
+}
+
+func (r *echoReference) duration(frames int) time.Duration {
+	return time.Duration(frames) * time.Second / time.Duration(r.rate)
+}
+
+// EchoCancelModel wraps a resource that plays and captures at the same time
+// and removes what it played from what it captures, so two-way audio doesn't
+// hear itself.
+var EchoCancelModel = resource.NewModel("olivia", "audio", "aec")
+
+// EchoCancelConfig is the configuration of the aec model.
+type EchoCancelConfig struct {
+	Input string `json:"input"` // Audio resource to play and capture through
+	// DelayMs is the part of the play-to-capture latency the filter doesn't
+	// need to cover, as measured by DuplexCheck less a margin.
+	DelayMs int `json:"delay_ms,omitempty"`
+	// TailMs is the length of the echo the filter cancels, from the delay
+	// to the last audible reflection. 100 if zero.
+	TailMs int `json:"tail_ms,omitempty"`
+}
+
+// Validate checks the aec configuration and returns the input as a
+// dependency.
+func (c *EchoCancelConfig) Validate(path string) ([]string, []string, error) {
+	if c.Input == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "input")
+	}
+	if c.DelayMs < 0 || c.TailMs < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("delay_ms and tail_ms can't be negative"))
+	}
+	return []string{c.Input}, nil, nil
+}
+
+func init() {
+	registerModel(EchoCancelModel, resource.Registration[Audio, *EchoCancelConfig]{
+		AttributeMapConverter: migratingConverter[*EchoCancelConfig](EchoCancelModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*EchoCancelConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			input, err := resource.FromDependencies[Audio](deps, Named(cfg.Input))
+			if err != nil {
+				return nil, err
+			}
+			return NewEchoCancel(conf.ResourceName(), input, *cfg, logger), nil
+		},
+	})
+}
+
+type echoCancelled struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	input  Audio
+	delay  time.Duration
+	tail   time.Duration
+	ref    *echoReference
+	logger logging.Logger
+
+	streams streamGroup
+}
+
+// NewEchoCancel returns a resource playing and capturing through input with
+// the echo of its own playback cancelled from the capture.
+func NewEchoCancel(name resource.Name, input Audio, cfg EchoCancelConfig, logger logging.Logger) Audio {
+	tail := time.Duration(cfg.TailMs) * time.Millisecond
+	if tail == 0 {
+		tail = defaultEchoTail
+	}
+	return &echoCancelled{
+		Named:  name.AsNamed(),
+		input:  input,
+		delay:  time.Duration(cfg.DelayMs) * time.Millisecond,
+		tail:   tail,
+		ref:    &echoReference{},
+		logger: logger,
+	}
+}
+
+func (e *echoCancelled) deviceClock() ClockSource { return clockOf(e.input) }
+
+// Play records the clip as the echo reference and plays it on the input.
+// Only raw pcm can be cancelled; other codecs play with their echo intact.
+func (e *echoCancelled) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	if rate := e.ref.captureRate(); rate != 0 {
+		if samples, err := e.reference(data, codec, sampleRate, channels, rate); err != nil {
+			e.logger.Debugw("playing without echo cancellation", "codec", codec, "error", err)
+		} else {
+			e.ref.write(clockOf(e.input).Now().Add(e.delay), rate, samples)
+		}
+	}
+	return e.input.Play(ctx, data, codec, sampleRate, channels)
+}
+
+// reference converts a clip to mono at the capture rate.
+func (e *echoCancelled) reference(data []byte, codec string, sampleRate, channels, rate int) ([]float32, error) {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return nil, errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	samples, err := decodePCM(data, format)
+	if err != nil {
+		return nil, err
+	}
+	samples = mono(samples, channels)
+	if sampleRate != rate {
+		samples = newResampler(sampleRate, rate, 1).process(samples)
+	}
+	return samples, nil
+}
+
+// GetAudio captures raw pcm from the input with the echo of Play removed.
+func (e *echoCancelled) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, errorf(ErrUnsupportedCodec, "echo cancellation needs a raw pcm codec, got %q", codec)
+	}
+	ctx, done, err := e.streams.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	src, err := e.input.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		var (
+			info    AudioInfo
+			filters []*echoCanceller
+			next    time.Time // where the previous chunk ended
+		)
+		for chunk := range src {
+			if chunk.Err == nil {
+				if chunk.Info == nil || chunk.Info.Channels == 0 || chunk.Info.SampleRate == 0 {
+					chunk = &AudioChunk{Err: errUnknownSourceFormat}
+				} else {
+					if *chunk.Info != info {
+						info = *chunk.Info
+						e.ref.setRate(info.SampleRate)
+						filters = make([]*echoCanceller, info.Channels)
+						for c := range filters {
+							filters[c] = newEchoCanceller(max(1, int(e.tail.Seconds()*float64(info.SampleRate))))
+						}
+					}
+					at := chunk.Timestamp
+					if at.IsZero() {
+						at = next.Add(chunk.Gap)
+						if next.IsZero() {
+							at = clockOf(e.input).Now()
+						}
+					}
+					cleaned, end, err := e.cancel(chunk, at, filters)
+					if err != nil {
+						cleaned = &AudioChunk{Err: err}
+					}
+					chunk, next = cleaned, end
+				}
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+			if chunk.Err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// cancel removes the echo from a chunk captured at at and returns the
+// cleaned copy and the time it ends.
+func (e *echoCancelled) cancel(chunk *AudioChunk, at time.Time, filters []*echoCanceller) (*AudioChunk, time.Time, error) {
+	info := *chunk.Info
+	samples, err := decodePCM(chunk.AudioData, info.Format)
+	if err != nil {
+		return nil, at, err
+	}
+	frames := len(samples) / info.Channels
+	ref := e.ref.read(at, frames)
+	for i := 0; i < frames; i++ {
+		for c, f := range filters {
+			samples[i*info.Channels+c] = f.process(ref[i], samples[i*info.Channels+c])
+		}
+	}
+	data, err := encodePCM(samples, info.Format)
+	if err != nil {
+		return nil, at, err
+	}
+	out := *chunk
+	out.AudioData = data
+	return &out, at.Add(time.Duration(frames) * time.Second / time.Duration(info.SampleRate)), nil
+}
+
+func (e *echoCancelled) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, e, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close ends every stream and waits for them to return.
+func (e *echoCancelled) Close(ctx context.Context) error {
+	return e.streams.close(ctx)
+}
@@ -0,0 +1,92 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestEchoCancellerKeepsNearEnd(t *testing.T) {
+	const rate = 16000
+	ref := hiss(2*rate, 0.3)
+	near := tone(rate, 2*rate, 300, 0.05)
+	e := newEchoCanceller(rate / 100)
+	var echo, residual []float32
+	for i := range ref {
+		// a direct path and one reflection
+		var y float32
+		if i >= 40 {
+			y += 0.6 * ref[i-40]
+		}
+		if i >= 90 {
+			y += 0.2 * ref[i-90]
+		}
+		out := e.process(ref[i], y+near[i])
+		if i >= 3*rate/2 {
+			echo = append(echo, y)
+			residual = append(residual, out-near[i])
+		}
+	}
+	if erle := dbfs(rms(echo)) - dbfs(rms(residual)); erle < 20 {
+		t.Fatalf("echo reduced by %.1f dB, want at least 20", erle)
+	}
+}
+
+func TestEchoCancelOverLoopback(t *testing.T) {
+	const (
+		rate    = 16000
+		latency = 20 * time.Millisecond
+	)
+	clock := NewManualClock(time.Unix(1000, 0))
+	loop := NewLoopback(Named("loop"), LoopbackConfig{SampleRate: rate, Channels: 1, LatencyMs: int(latency / time.Millisecond), Clock: clock}, logging.NewTestLogger(t))
+	defer loop.Close(context.Background())
+	a := NewEchoCancel(Named("aec"), loop, EchoCancelConfig{TailMs: 50}, logging.NewTestLogger(t))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm32Float.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := runClock(clock, 2*time.Millisecond)
+	defer stop()
+	// the reference is only kept once a capture has reported its rate
+	for a.(*echoCancelled).ref.captureRate() == 0 {
+		select {
+		case <-chunks:
+		case <-ctx.Done():
+			t.Fatal("capture never started")
+		}
+	}
+
+	clip := hiss(3*rate/2, 0.3)
+	data, _ := encodePCM(clip, Pcm32Float)
+	played := make(chan error, 1)
+	go func() { played <- a.Play(ctx, data, Pcm32Float.String(), rate, 1) }()
+
+	var captured []float32
+	for {
+		select {
+		case chunk := <-chunks:
+			if chunk.Err != nil {
+				t.Fatal(chunk.Err)
+			}
+			samples, _ := decodePCM(chunk.AudioData, Pcm32Float)
+			captured = append(captured, samples...)
+			continue
+		case err := <-played:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-ctx.Done():
+			t.Fatal("Play never returned")
+		}
+		break
+	}
+	// the last half second of the clip, before it ended
+	tail := captured[max(0, len(captured)-rate/2-rate/20) : len(captured)-rate/20]
+	if got := dbfs(rms(tail)); got > dbfs(rms(clip))-20 {
+		t.Fatalf("echo at %.1f dBFS, want 20 dB below the %.1f dBFS played", got, dbfs(rms(clip)))
+	}
+}
@@ -0,0 +1,235 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// Defaults for WithAGC and the agc model.
+const (
+	defaultAGCTarget  = -20.0 // dBFS
+	defaultAGCMaxGain = 30.0  // dB
+)
+
+const (
+	agcBlock      = 10 * time.Millisecond
+	agcAttack     = 50 * time.Millisecond // time constant when the level rises
+	agcRelease    = time.Second           // and when it falls
+	agcNoiseFloor = -55.0                 // dBFS, quieter blocks don't move the gain
+	agcCeiling    = 0.98                  // peak the gain may push a block to
+)
+
+// agc keeps the level of a raw pcm stream near a target by following a
+// smoothed level of every 10ms block and applying the gain that brings it to
+// the target, within ±maxGainDB. Blocks below the noise floor hold the gain,
+// so background noise between words isn't pumped up, and the gain is cut at
+// once for any block it would clip. The gain ramps across each block to
+// avoid zipper noise.
+type agc struct {
+	targetDBFS float64
+	maxGainDB  float64
+	level      float64 // smoothed level in dBFS
+	primed     bool    // level has seen a block above the noise floor
+	gain       float64 // linear gain at the end of the last block
+}
+
+func newAGC(targetDBFS, maxGainDB float64) *agc {
+	if targetDBFS == 0 {
+		targetDBFS = defaultAGCTarget
+	}
+	if maxGainDB == 0 {
+		maxGainDB = defaultAGCMaxGain
+	}
+	return &agc{targetDBFS: targetDBFS, maxGainDB: maxGainDB, gain: 1}
+}
+
+// process returns a copy of a raw pcm chunk with the gain applied.
+func (a *agc) process(chunk *AudioChunk) (*AudioChunk, error) {
+	if chunk.Info == nil {
+		return nil, errUnknownSourceFormat
+	}
+	info := *chunk.Info
+	if info.Channels == 0 || info.SampleRate == 0 {
+		return nil, errUnknownSourceFormat
+	}
+	samples, err := decodePCM(chunk.AudioData, info.Format)
+	if err != nil {
+		return nil, err
+	}
+	block := max(int(agcBlock.Seconds()*float64(info.SampleRate)), 1) * info.Channels
+	for start := 0; start < len(samples); start += block {
+		a.apply(samples[start:min(start+block, len(samples))], info)
+	}
+	data, err := encodePCM(samples, info.Format)
+	if err != nil {
+		return nil, err
+	}
+	out := *chunk
+	out.AudioData = data
+	return &out, nil
+}
+
+// apply adjusts the gain for one block and applies it in place.
+func (a *agc) apply(block []float32, info AudioInfo) {
+	frames := len(block) / info.Channels
+	if frames == 0 {
+		return
+	}
+	if level := dbfs(rms(block)); level > agcNoiseFloor {
+		if !a.primed {
+			a.level, a.primed = level, true
+		} else {
+			tau := agcRelease
+			if level > a.level {
+				tau = agcAttack
+			}
+			dur := float64(frames) / float64(info.SampleRate)
+			a.level += (1 - math.Exp(-dur/tau.Seconds())) * (level - a.level)
+		}
+	}
+	want := a.gain
+	if a.primed {
+		db := math.Max(-a.maxGainDB, math.Min(a.maxGainDB, a.targetDBFS-a.level))
+		want = math.Pow(10, db/20)
+	}
+	// the ramp starts from the old gain, so both ends have to stay under the ceiling
+	from := a.gain
+	if p := peak(block); p > 0 {
+		want = math.Min(want, agcCeiling/p)
+		from = math.Min(from, agcCeiling/p)
+	}
+	for i := 0; i < frames; i++ {
+		g := float32(from + (want-from)*float64(i+1)/float64(frames))
+		for c := 0; c < info.Channels; c++ {
+			block[i*info.Channels+c] *= g
+		}
+	}
+	a.gain = want
+}
+
+// AGCModel wraps a microphone and keeps its capture level near a target
+// however far away the talker is.
+var AGCModel = resource.NewModel("olivia", "audio", "agc")
+
+// AGCConfig is the configuration of the agc model.
+type AGCConfig struct {
+	Input      string  `json:"input"`                 // Audio resource to capture from
+	TargetDBFS float64 `json:"target_dbfs,omitempty"` // level to hold speech at, -20 if zero
+	MaxGainDB  float64 `json:"max_gain_db,omitempty"` // most the level is raised or lowered, 30 if zero
+}
+
+// Validate checks the agc configuration and returns the input as a
+// dependency.
+func (c *AGCConfig) Validate(path string) ([]string, []string, error) {
+	if c.Input == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "input")
+	}
+	if c.TargetDBFS > 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("target_dbfs must be below full scale"))
+	}
+	if c.MaxGainDB < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("max_gain_db can't be negative"))
+	}
+	return []string{c.Input}, nil, nil
+}
+
+func init() {
+	registerModel(AGCModel, resource.Registration[Audio, *AGCConfig]{
+		AttributeMapConverter: migratingConverter[*AGCConfig](AGCModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*AGCConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			input, err := resource.FromDependencies[Audio](deps, Named(cfg.Input))
+			if err != nil {
+				return nil, err
+			}
+			return NewAGC(conf.ResourceName(), input, *cfg, logger), nil
+		},
+	})
+}
+
+type leveled struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	input  Audio
+	cfg    AGCConfig
+	logger logging.Logger
+
+	streams streamGroup
+}
+
+// NewAGC returns a resource capturing from input with automatic gain control.
+func NewAGC(name resource.Name, input Audio, cfg AGCConfig, logger logging.Logger) Audio {
+	return &leveled{Named: name.AsNamed(), input: input, cfg: cfg, logger: logger}
+}
+
+// GetAudio captures raw pcm from the input and levels it.
+func (l *leveled) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, errorf(ErrUnsupportedCodec, "automatic gain control needs a raw pcm codec, got %q", codec)
+	}
+	ctx, done, err := l.streams.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	src, err := l.input.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		gc := newAGC(l.cfg.TargetDBFS, l.cfg.MaxGainDB)
+		for chunk := range src {
+			if chunk.Err == nil {
+				leveled, err := gc.process(chunk)
+				if err != nil {
+					leveled = &AudioChunk{Err: err}
+				}
+				chunk = leveled
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+			if chunk.Err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (l *leveled) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return l.input.Play(ctx, data, codec, sampleRate, channels)
+}
+
+func (l *leveled) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, l, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close ends every stream and waits for them to return.
+func (l *leveled) Close(ctx context.Context) error {
+	return l.streams.close(ctx)
+}
@@ -0,0 +1,63 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+// levelRun feeds samples through g in 20ms pcm32_float chunks and returns
+// the output.
+func levelRun(t *testing.T, g *agc, rate int, samples []float32) []float32 {
+	t.Helper()
+	info := AudioInfo{Format: Pcm32Float, SampleRate: rate, Channels: 1}
+	var out []float32
+	for start := 0; start < len(samples); start += rate / 50 {
+		data, _ := encodePCM(samples[start:min(start+rate/50, len(samples))], Pcm32Float)
+		chunk, err := g.process(&AudioChunk{AudioData: data, Info: &info})
+		if err != nil {
+			t.Fatal(err)
+		}
+		leveled, err := decodePCM(chunk.AudioData, Pcm32Float)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, leveled...)
+	}
+	return out
+}
+
+func TestAGCLevels(t *testing.T) {
+	const rate = 16000
+	for _, tc := range []struct {
+		name    string
+		in      float64 // RMS dBFS of the input tone
+		maxGain float64
+		want    float64
+	}{
+		{"distant talker raised", -40, 0, -20},
+		{"close talker lowered", -8, 0, -20},
+		{"gain capped", -50, 20, -30},
+		{"noise left alone", -65, 0, -65},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			in := tone(rate, 3*rate, 440, math.Pow(10, tc.in/20)*math.Sqrt2)
+			out := levelRun(t, newAGC(0, tc.maxGain), rate, in)
+			if got := dbfs(rms(out[len(out)-rate/2:])); math.Abs(got-tc.want) > 1 {
+				t.Fatalf("settled at %.1f dBFS, want %.1f", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAGCDoesNotClip(t *testing.T) {
+	const rate = 16000
+	// a second of a quiet talker pushes the gain up, then they shout
+	in := append(tone(rate, rate, 300, 0.008), tone(rate, rate, 300, 0.99)...)
+	out := levelRun(t, newAGC(0, 0), rate, in)
+	if p := peak(out); p > agcCeiling+1e-6 {
+		t.Fatalf("peak %.3f, want at most %.2f", p, agcCeiling)
+	}
+	if got := dbfs(rms(out[len(out)-rate/2:])); math.Abs(got-defaultAGCTarget) > 1 {
+		t.Fatalf("shout settled at %.1f dBFS, want %.1f", got, defaultAGCTarget)
+	}
+}
@@ -0,0 +1,140 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// ALSAModel captures and plays directly through ALSA on Linux. It needs a
+// build with the alsa tag and libasound; other builds know the model but
+// fail to construct it.
+var ALSAModel = resource.NewModel("olivia", "audio", "alsa")
+
+// Defaults for ALSAConfig.
+const (
+	defaultALSAPeriod  = 10 * time.Millisecond
+	defaultALSAPeriods = 4 // periods per buffer
+)
+
+// ALSAConfig is the configuration of the alsa model. Devices are ALSA names
+// like "plughw:1,0" or "default"; an empty device is chosen with
+// DefaultDevice when the resource is built.
+type ALSAConfig struct {
+	Device string `json:"device,omitempty"` // for both directions
+	// CaptureDevice and PlaybackDevice override Device for one direction,
+	// "none" turns that direction off.
+	CaptureDevice    string `json:"capture_device,omitempty"`
+	PlaybackDevice   string `json:"playback_device,omitempty"`
+	SampleRate       int    `json:"sample_rate,omitempty"`       // 48 kHz if zero
+	Channels         int    `json:"channels,omitempty"`          // capture channels, 1 if zero
+	PlaybackChannels int    `json:"playback_channels,omitempty"` // 2 if zero
+	PeriodFrames     int    `json:"period_frames,omitempty"`     // frames per transfer, 10ms if zero
+	BufferFrames     int    `json:"buffer_frames,omitempty"`     // device buffer, 4 periods if zero
+}
+
+// Validate checks the alsa configuration.
+func (c *ALSAConfig) Validate(path string) ([]string, []string, error) {
+	if c.SampleRate < 0 || c.Channels < 0 || c.PlaybackChannels < 0 || c.PeriodFrames < 0 || c.BufferFrames < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate, channels, playback_channels, period_frames and buffer_frames cannot be negative"))
+	}
+	if c.BufferFrames != 0 && c.BufferFrames < 2*c.periodFrames() {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("buffer_frames must hold at least two periods"))
+	}
+	return nil, nil, nil
+}
+
+func (c *ALSAConfig) sampleRate() int {
+	if c.SampleRate == 0 {
+		return defaultPCMSampleRate
+	}
+	return c.SampleRate
+}
+
+func (c *ALSAConfig) periodFrames() int {
+	if c.PeriodFrames == 0 {
+		return int(defaultALSAPeriod.Seconds() * float64(c.sampleRate()))
+	}
+	return c.PeriodFrames
+}
+
+// openALSA opens a pcm, set by builds with ALSA support.
+var openALSA func(name string, capture bool, p pcmParams) (pcmDevice, error)
+
+func init() {
+	registerModel(ALSAModel, resource.Registration[Audio, *ALSAConfig]{
+		AttributeMapConverter: migratingConverter[*ALSAConfig](ALSAModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*ALSAConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewALSA(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+// NewALSA returns a resource on the configured ALSA devices, choosing any
+// that aren't configured.
+func NewALSA(name resource.Name, cfg ALSAConfig, logger logging.Logger) (Audio, error) {
+	if openALSA == nil {
+		return nil, errors.New("this build has no ALSA support, build with -tags alsa and libasound")
+	}
+	rate, period := cfg.sampleRate(), cfg.periodFrames()
+	buffer := cfg.BufferFrames
+	if buffer == 0 {
+		buffer = defaultALSAPeriods * period
+	}
+	channels, playbackChannels := cfg.Channels, cfg.PlaybackChannels
+	if channels == 0 {
+		channels = defaultPCMChannels
+	}
+	if playbackChannels == 0 {
+		playbackChannels = defaultPCMPlaybackChannels
+	}
+	a := &pcmAudio{
+		Named:          name.AsNamed(),
+		backend:        "alsa",
+		open:           openALSA,
+		captureParams:  pcmParams{rate: rate, channels: channels, periodFrames: period, bufferFrames: buffer},
+		playbackParams: pcmParams{rate: rate, channels: playbackChannels, periodFrames: period, bufferFrames: buffer},
+		logger:         logger,
+		readers:        map[chan pcmBlock]struct{}{},
+	}
+	var err error
+	if a.capture, err = chooseALSADevice(cfg.CaptureDevice, cfg.Device, true, logger); err != nil {
+		return nil, err
+	}
+	if a.playback, err = chooseALSADevice(cfg.PlaybackDevice, cfg.Device, false, logger); err != nil {
+		return nil, err
+	}
+	if a.capture == "" && a.playback == "" {
+		return nil, errors.New("alsa has neither a capture nor a playback device")
+	}
+	return a, nil
+}
+
+// chooseALSADevice returns the device for one direction, empty for none.
+// Unconfigured directions use the default device, or none if there isn't
+// one, so a board with only a speaker still plays.
+func chooseALSADevice(direction, both string, capture bool, logger logging.Logger) (string, error) {
+	switch {
+	case direction == noPCMDevice:
+		return "", nil
+	case direction != "":
+		return direction, nil
+	case both != "":
+		return both, nil
+	}
+	d, reason, err := DefaultDevice(capture)
+	if err != nil {
+		logger.Warnw("no default ALSA device", "capture", capture, "error", err)
+		return "", nil
+	}
+	logger.Infow("using the default ALSA device", "device", d.ID, "reason", reason)
+	// plughw converts to the rate and channels the stream asks for
+	return "plug" + d.ID, nil
+}
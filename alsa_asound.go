@@ -0,0 +1,112 @@
+//go:build alsa
+
+package audio
+
+/*
+#cgo LDFLAGS: -lasound
+#include <stdlib.h>
+#include <alsa/asoundlib.h>
+
+static int set_params(snd_pcm_t *pcm, unsigned int rate, unsigned int channels,
+		snd_pcm_uframes_t period, snd_pcm_uframes_t buffer) {
+	snd_pcm_hw_params_t *hw;
+	snd_pcm_hw_params_alloca(&hw);
+	int err;
+	if ((err = snd_pcm_hw_params_any(pcm, hw)) < 0) return err;
+	if ((err = snd_pcm_hw_params_set_access(pcm, hw, SND_PCM_ACCESS_RW_INTERLEAVED)) < 0) return err;
+	if ((err = snd_pcm_hw_params_set_format(pcm, hw, SND_PCM_FORMAT_S16_LE)) < 0) return err;
+	if ((err = snd_pcm_hw_params_set_channels(pcm, hw, channels)) < 0) return err;
+	if ((err = snd_pcm_hw_params_set_rate(pcm, hw, rate, 0)) < 0) return err;
+	if ((err = snd_pcm_hw_params_set_period_size_near(pcm, hw, &period, 0)) < 0) return err;
+	if ((err = snd_pcm_hw_params_set_buffer_size_near(pcm, hw, &buffer)) < 0) return err;
+	return snd_pcm_hw_params(pcm, hw);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+func init() {
+	openALSA = openASound
+}
+
+// asoundPCM is a pcm opened through libasound. Its methods block and are
+// called from one goroutine at a time.
+type asoundPCM struct {
+	pcm      *C.snd_pcm_t
+	channels int
+}
+
+// asoundError is a negative errno from libasound.
+type asoundError C.int
+
+func (e asoundError) Error() string {
+	return C.GoString(C.snd_strerror(C.int(e)))
+}
+
+func openASound(name string, capture bool, p pcmParams) (pcmDevice, error) {
+	stream := C.snd_pcm_stream_t(C.SND_PCM_STREAM_PLAYBACK)
+	if capture {
+		stream = C.SND_PCM_STREAM_CAPTURE
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var pcm *C.snd_pcm_t
+	if err := C.snd_pcm_open(&pcm, cname, stream, 0); err < 0 {
+		return nil, asoundError(err)
+	}
+	if err := C.set_params(pcm, C.uint(p.rate), C.uint(p.channels), C.snd_pcm_uframes_t(p.periodFrames), C.snd_pcm_uframes_t(p.bufferFrames)); err < 0 {
+		C.snd_pcm_close(pcm)
+		return nil, fmt.Errorf("%d Hz, %d channels: %w", p.rate, p.channels, asoundError(err))
+	}
+	return &asoundPCM{pcm: pcm, channels: p.channels}, nil
+}
+
+func (d *asoundPCM) read(buf []int16) (int, error) {
+	n := C.snd_pcm_readi(d.pcm, unsafe.Pointer(&buf[0]), C.snd_pcm_uframes_t(len(buf)/d.channels))
+	if n < 0 {
+		return 0, asoundError(n)
+	}
+	return int(n), nil
+}
+
+func (d *asoundPCM) write(buf []int16) (int, error) {
+	n := C.snd_pcm_writei(d.pcm, unsafe.Pointer(&buf[0]), C.snd_pcm_uframes_t(len(buf)/d.channels))
+	if n < 0 {
+		return 0, asoundError(n)
+	}
+	return int(n), nil
+}
+
+// recover handles xruns and suspends, and returns any other error.
+func (d *asoundPCM) recover(err error) error {
+	e, ok := err.(asoundError)
+	if !ok {
+		return err
+	}
+	if r := C.snd_pcm_recover(d.pcm, C.int(e), 1); r < 0 {
+		return asoundError(r)
+	}
+	return nil
+}
+
+func (d *asoundPCM) drain() error {
+	if err := C.snd_pcm_drain(d.pcm); err < 0 {
+		return asoundError(err)
+	}
+	// a drained pcm has stopped and must be prepared before the next write
+	if err := C.snd_pcm_prepare(d.pcm); err < 0 {
+		return asoundError(err)
+	}
+	return nil
+}
+
+func (d *asoundPCM) close() error {
+	if err := C.snd_pcm_close(d.pcm); err < 0 {
+		return asoundError(err)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+var errFakeXrun = errors.New("broken pipe")
+
+// fakePCM is a pcm in memory. Capture reads take a period of wall time and
+// return a ramp; reads and writes listed in fail return errFakeXrun, after
+// the time the lost period would have taken.
+type fakePCM struct {
+	p      pcmParams
+	period time.Duration
+
+	mu        sync.Mutex
+	calls     int
+	fail      map[int]bool
+	failAll   bool
+	lost      bool // recover fails, as for a dropped connection
+	recovered int
+	written   []int16
+	drained   int
+	closed    bool
+}
+
+func (f *fakePCM) transfer() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.failAll || f.fail[f.calls] {
+		return errFakeXrun
+	}
+	return nil
+}
+
+func (f *fakePCM) read(buf []int16) (int, error) {
+	time.Sleep(f.period)
+	if err := f.transfer(); err != nil {
+		return 0, err
+	}
+	for i := range buf {
+		buf[i] = int16(i)
+	}
+	return len(buf) / f.p.channels, nil
+}
+
+func (f *fakePCM) write(buf []int16) (int, error) {
+	if err := f.transfer(); err != nil {
+		return 0, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.written = append(f.written, buf...)
+	return len(buf) / f.p.channels, nil
+}
+
+func (f *fakePCM) recover(err error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lost || !errors.Is(err, errFakeXrun) {
+		return err
+	}
+	f.recovered++
+	return nil
+}
+
+func (f *fakePCM) drain() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drained++
+	return nil
+}
+
+func (f *fakePCM) close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+// useFakePCM has the alsa model open fresh fake devices configured by
+// setup, and returns them by device name.
+func useFakePCM(t *testing.T, setup func(name string, f *fakePCM)) map[string]*fakePCM {
+	var mu sync.Mutex
+	opened := map[string]*fakePCM{}
+	prev := openALSA
+	openALSA = func(name string, capture bool, p pcmParams) (pcmDevice, error) {
+		f := &fakePCM{p: p, period: time.Duration(p.periodFrames) * time.Second / time.Duration(p.rate), fail: map[int]bool{}}
+		if setup != nil {
+			setup(name, f)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		opened[name] = f
+		return f, nil
+	}
+	t.Cleanup(func() { openALSA = prev })
+	return opened
+}
+
+func TestALSACaptureRecoversFromXrun(t *testing.T) {
+	opened := useFakePCM(t, func(name string, f *fakePCM) {
+		f.fail[3] = true
+	})
+	a, err := NewALSA(Named("mic"), ALSAConfig{CaptureDevice: "hw:1,0", PlaybackDevice: "none", SampleRate: 8000, PeriodFrames: 80}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// five 10ms periods, one lost to the overrun
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), 0.05, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	var gap time.Duration
+	var last time.Time
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatal(c.Err)
+		}
+		if !last.IsZero() && c.Gap == 0 && c.Timestamp.Sub(last) != 10*time.Millisecond {
+			t.Errorf("chunk %d is %v after the last, want 10ms", n, c.Timestamp.Sub(last))
+		}
+		gap += c.Gap
+		last = c.Timestamp
+		n++
+	}
+	if n != 5 {
+		t.Fatalf("got %d chunks, want 5", n)
+	}
+	// the failed read took 10ms and so did the read after it
+	if gap < 10*time.Millisecond {
+		t.Errorf("overrun left a %v gap, want at least 10ms", gap)
+	}
+
+	f := opened["hw:1,0"]
+	deadline := time.Now().Add(time.Second)
+	for {
+		f.mu.Lock()
+		recovered, closed := f.recovered, f.closed
+		f.mu.Unlock()
+		if closed {
+			if recovered != 1 {
+				t.Errorf("recovered %d times, want 1", recovered)
+			}
+			if over, _ := a.(XrunCounter).Xruns(); over != 1 {
+				t.Errorf("counted %d overruns", over)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("capture device still open with no readers")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestALSACaptureGivesUp(t *testing.T) {
+	useFakePCM(t, func(name string, f *fakePCM) {
+		f.failAll = true
+	})
+	a, err := NewALSA(Named("mic"), ALSAConfig{Device: "hw:1,0", SampleRate: 8000, PeriodFrames: 8}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	a.(*pcmAudio).reopenWait = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := <-chunks
+	if !ok || !errors.Is(c.Err, errFakeXrun) {
+		t.Fatalf("got %+v, want the capture error", c)
+	}
+	if _, ok := <-chunks; ok {
+		t.Error("stream went on after failing")
+	}
+	// the device kept failing however often it was opened again
+	h, _ := a.(HealthChecker).Health(ctx)
+	if h.Capture.State != DeviceUnavailable || h.Capture.Reopens != maxDeviceReopens || h.Ready() {
+		t.Errorf("health %+v", h)
+	}
+}
+
+func TestALSAPlayRecoversFromUnderrun(t *testing.T) {
+	opened := useFakePCM(t, func(name string, f *fakePCM) {
+		f.fail[2] = true
+	})
+	a, err := NewALSA(Named("speaker"), ALSAConfig{CaptureDevice: "none", PlaybackDevice: "hw:0,0", SampleRate: 48000}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 100ms of mono at 16 kHz, resampled and upmixed to the stereo device
+	clip, _ := encodePCM(tone(16000, 1600, 440, 0.5), Pcm16)
+	if err := a.Play(context.Background(), clip, Pcm16.String(), 16000, 1); err != nil {
+		t.Fatal(err)
+	}
+	f := opened["hw:0,0"]
+	f.mu.Lock()
+	frames, recovered, drained := len(f.written)/2, f.recovered, f.drained
+	for i := 0; i < len(f.written); i += 2 {
+		if f.written[i] != f.written[i+1] {
+			t.Fatalf("frame %d has %d and %d, want the clip on both channels", i/2, f.written[i], f.written[i+1])
+		}
+	}
+	f.mu.Unlock()
+	if frames < 4700 || frames > 4900 {
+		t.Errorf("wrote %d frames, want about 4800", frames)
+	}
+	if recovered != 1 || drained != 1 {
+		t.Errorf("recovered %d times and drained %d, want once each", recovered, drained)
+	}
+	if over, under := a.(XrunCounter).Xruns(); over != 0 || under != 1 {
+		t.Errorf("counted %d overruns and %d underruns", over, under)
+	}
+
+	if err := a.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.closed {
+		t.Error("Close left the playback device open")
+	}
+}
+
+func TestALSAConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg ALSAConfig
+		ok  bool
+	}{
+		{ALSAConfig{}, true},
+		{ALSAConfig{PeriodFrames: 256, BufferFrames: 1024}, true},
+		{ALSAConfig{PeriodFrames: 256, BufferFrames: 300}, false},
+		// 480 frame default period
+		{ALSAConfig{BufferFrames: 500}, false},
+		{ALSAConfig{SampleRate: -1}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+
+	prev := openALSA
+	openALSA = nil
+	defer func() { openALSA = prev }()
+	if _, err := NewALSA(Named("mic"), ALSAConfig{Device: "hw:0,0"}, logging.NewTestLogger(t)); err == nil {
+		t.Error("built without ALSA support")
+	}
+}
@@ -0,0 +1,372 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// recordingChunk is the length of the chunks StreamRecording sends.
+const recordingChunk = 100 * time.Millisecond
+
+// RecordingReader is implemented by clients of servers that serve the
+// recordings in their store by ID, see StoredRecording.ID.
+type RecordingReader interface {
+	// GetRecording returns a recording with what its file says about its
+	// audio.
+	GetRecording(ctx context.Context, id string) (StoredRecording, error)
+	// StreamRecording decodes a recording as fast as it is read.
+	StreamRecording(ctx context.Context, id string, opts StreamRecordingOptions) (<-chan *AudioChunk, error)
+}
+
+// StreamRecordingOptions configures StreamRecording. Saved streams are
+// replayed chunk for chunk as they were saved, whatever the Codec.
+type StreamRecordingOptions struct {
+	Codec string        // "pcm16" (the default), "pcm32" or "pcm32_float"
+	Start time.Duration // into the recording
+}
+
+// ID is the recording's file in the store.
+func (r StoredRecording) ID() string {
+	return r.Name + recordingExt(r.Format)
+}
+
+// validRecordingID reports whether id names a recording file in the store.
+func validRecordingID(id string) bool {
+	_, ok := recordingFormats[filepath.Ext(id)]
+	return ok && id == filepath.Base(id) && !strings.HasPrefix(id, ".")
+}
+
+// get returns the recording id, described.
+func (s *RecordingStore) get(id string) (StoredRecording, error) {
+	if s.Dir == "" {
+		return StoredRecording{}, errNoRecordingStore
+	}
+	if !validRecordingID(id) {
+		return StoredRecording{}, errorf(ErrInvalidArgument, "invalid recording id %q", id)
+	}
+	info, err := os.Stat(filepath.Join(s.Dir, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return StoredRecording{}, fmt.Errorf("no recording %q", id)
+	}
+	if err != nil {
+		return StoredRecording{}, err
+	}
+	ext := filepath.Ext(id)
+	r := StoredRecording{Name: strings.TrimSuffix(id, ext), Format: recordingFormats[ext], Size: info.Size(), Modified: info.ModTime()}
+	if err := s.describe(&r); err != nil {
+		return StoredRecording{}, err
+	}
+	return r, nil
+}
+
+// describe fills in what r's file says about its audio. Files are read once
+// for each size and modification time.
+func (s *RecordingStore) describe(r *StoredRecording) error {
+	s.mu.Lock()
+	known, ok := s.described[r.ID()]
+	s.mu.Unlock()
+	if !ok || known.Size != r.Size || !known.Modified.Equal(r.Modified) {
+		known = *r
+		if err := probeRecording(filepath.Join(s.Dir, r.ID()), &known); err != nil {
+			return fmt.Errorf("%s: %w", r.ID(), err)
+		}
+		s.mu.Lock()
+		if s.described == nil {
+			s.described = map[string]StoredRecording{}
+		}
+		s.described[r.ID()] = known
+		s.mu.Unlock()
+	}
+	r.Codec, r.SampleRate, r.Channels, r.Duration = known.Codec, known.SampleRate, known.Channels, known.Duration
+	return nil
+}
+
+// forget drops what describe read of recordings no longer in the store.
+func (s *RecordingStore) forget(recordings []StoredRecording) {
+	present := make(map[string]bool, len(recordings))
+	for _, r := range recordings {
+		present[r.ID()] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.described {
+		if !present[id] {
+			delete(s.described, id)
+		}
+	}
+}
+
+// probeRecording reads the codec, format and duration of the recording at
+// path from its header, or for a saved stream from its chunks.
+func probeRecording(path string, r *StoredRecording) error {
+	switch r.Format {
+	case "wav":
+		d, err := openWAVFile(path)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		r.Codec = map[int]string{1: "pcm8", 2: "pcm16", 3: "pcm24", 4: "pcm32"}[d.width]
+		if d.float {
+			r.Codec = Pcm32Float.String()
+		}
+		left := d.left
+		if left == math.MaxInt64 {
+			// still being written, the data runs to the end of the file
+			pos, err := d.f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			info, err := d.f.Stat()
+			if err != nil {
+				return err
+			}
+			left = info.Size() - (pos - int64(d.r.Buffered()))
+		}
+		r.SampleRate, r.Channels = d.info.SampleRate, d.info.Channels
+		r.Duration = framesDuration(left/int64(d.info.Channels*d.width), d.info.SampleRate)
+	case "flac":
+		d, err := openFLACFile(path)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		r.Codec = "flac"
+		r.SampleRate, r.Channels = d.info.SampleRate, d.info.Channels
+		r.Duration = framesDuration(int64(d.stream.Info.NSamples), d.info.SampleRate)
+	case "chunks":
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		br := bufio.NewReader(f)
+		for {
+			var msg pb.AudioChunk
+			if err := protodelim.UnmarshalFrom(br, &msg); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			chunk := chunkFromProto(&msg)
+			if r.Codec == "" && chunk.Info != nil {
+				r.Codec, r.SampleRate, r.Channels = chunk.Info.Format.String(), chunk.Info.SampleRate, chunk.Info.Channels
+			}
+			if d, ok := chunkDuration(chunk); ok {
+				r.Duration += d
+			}
+		}
+	}
+	return nil
+}
+
+func framesDuration(frames int64, rate int) time.Duration {
+	return time.Duration(frames) * time.Second / time.Duration(rate)
+}
+
+func storedRecordingToProto(r StoredRecording) *pb.StoredRecording {
+	return &pb.StoredRecording{
+		Name:                r.Name,
+		Format:              r.Format,
+		SizeBytes:           r.Size,
+		ModifiedNanoseconds: r.Modified.UnixNano(),
+		Id:                  r.ID(),
+		Codec:               r.Codec,
+		SampleRate:          int32(r.SampleRate),
+		NumChannels:         int32(r.Channels),
+		DurationNanoseconds: int64(r.Duration),
+	}
+}
+
+func storedRecordingFromProto(r *pb.StoredRecording) StoredRecording {
+	return StoredRecording{
+		Name:       r.Name,
+		Format:     r.Format,
+		Size:       r.SizeBytes,
+		Modified:   time.Unix(0, r.ModifiedNanoseconds),
+		Codec:      r.Codec,
+		SampleRate: int(r.SampleRate),
+		Channels:   int(r.NumChannels),
+		Duration:   time.Duration(r.DurationNanoseconds),
+	}
+}
+
+// recordingResource returns the resource a recording in the store is of,
+// as its name starts with, or its name if it isn't a recording's.
+func recordingResource(id string) string {
+	name := strings.TrimSuffix(id, filepath.Ext(id))
+	if i := strings.LastIndexByte(name, '-'); i > 0 {
+		if _, err := time.Parse(recordingTimeLayout, name[i+1:]); err == nil {
+			return name[:i]
+		}
+	}
+	return name
+}
+
+func (s *audioServer) GetRecording(ctx context.Context, req *pb.GetRecordingRequest) (*pb.GetRecordingResponse, error) {
+	r, err := ServerRecordings.get(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit(ctx, req.Name, AuditRecordingRead, req.Id, "described", nil); err != nil {
+		return nil, err
+	}
+	return &pb.GetRecordingResponse{Recording: storedRecordingToProto(r)}, nil
+}
+
+func (s *audioServer) StreamRecording(req *pb.StreamRecordingRequest, stream pb.AudioService_StreamRecordingServer) error {
+	r, err := ServerRecordings.get(req.Id)
+	if err != nil {
+		return err
+	}
+	start := time.Duration(req.StartSeconds * float64(time.Second))
+	if start < 0 {
+		return errorf(ErrInvalidArgument, "start cannot be negative, got %gs", req.StartSeconds)
+	}
+	if err := s.audit(stream.Context(), req.Name, AuditRecordingRead, req.Id, fmt.Sprintf("streamed from %gs", req.StartSeconds), nil); err != nil {
+		return err
+	}
+	path := filepath.Join(ServerRecordings.Dir, r.ID())
+	if r.Format == "chunks" {
+		return replaySavedStream(path, start, stream.Send)
+	}
+
+	codec := req.Codec
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err == nil {
+		_, err = bytesPerSample(format)
+	}
+	if err != nil {
+		return errorf(ErrUnsupportedCodec, "invalid codec %q, want \"pcm16\", \"pcm32\" or \"pcm32_float\"", req.Codec)
+	}
+	dec, err := openAudioFile(path)
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+	info := dec.Info()
+	frames := max(int(recordingChunk.Seconds()*float64(info.SampleRate)), 1)
+	for skip := int(start.Seconds() * float64(info.SampleRate)); skip > 0; skip -= frames {
+		if _, err := dec.Read(min(skip, frames)); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+	pbInfo := &pb.AudioInfo{Codec: format.String(), SampleRate: int32(info.SampleRate), NumChannels: int32(info.Channels)}
+	for seq := int32(0); ; seq++ {
+		samples, err := dec.Read(frames)
+		if len(samples) > 0 {
+			data, encErr := encodePCM(samples, format)
+			if encErr != nil {
+				return encErr
+			}
+			msg := &pb.AudioChunk{AudioData: data, Info: pbInfo, Sequence: seq}
+			if seq == 0 {
+				msg.Header = &pb.StreamHeader{Info: pbInfo}
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// replaySavedStream sends the chunks of a saved stream from start on, the
+// first with a header as a stream starting there would have.
+func replaySavedStream(path string, start time.Duration, send func(*pb.AudioChunk) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	var at time.Duration
+	sent := false
+	for {
+		var msg pb.AudioChunk
+		if err := protodelim.UnmarshalFrom(br, &msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		d, _ := chunkDuration(chunkFromProto(&msg))
+		at += d
+		if at <= start {
+			continue
+		}
+		if !sent && msg.Header == nil && msg.Info != nil {
+			msg.Header = &pb.StreamHeader{Info: msg.Info}
+		}
+		if err := send(&msg); err != nil {
+			return err
+		}
+		sent = true
+	}
+}
+
+func (c *audioClient) GetRecording(ctx context.Context, id string) (StoredRecording, error) {
+	resp, err := c.client.GetRecording(ctx, &pb.GetRecordingRequest{Name: c.name, Id: id})
+	if err != nil {
+		return StoredRecording{}, err
+	}
+	return storedRecordingFromProto(resp.Recording), nil
+}
+
+func (c *audioClient) StreamRecording(ctx context.Context, id string, opts StreamRecordingOptions) (<-chan *AudioChunk, error) {
+	stream, err := c.client.StreamRecording(ctx, &pb.StreamRecordingRequest{
+		Name:         c.name,
+		Id:           id,
+		Codec:        opts.Codec,
+		StartSeconds: opts.Start.Seconds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *AudioChunk)
+	go func() {
+		defer close(ch)
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				if err = streamError(err); err != nil {
+					select {
+					case ch <- &AudioChunk{Err: err}:
+					case <-ctx.Done():
+					}
+				}
+				return
+			}
+			select {
+			case ch <- chunkFromProto(msg):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
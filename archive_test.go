@@ -0,0 +1,95 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+func TestRecordingArchive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = t.TempDir()
+	// a second at 8kHz, and a stream of three 100ms chunks
+	if err := ServerRecordings.saveWAV("tone", tone(8000, 8000, 440, 0.5), 8000, 1); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := ServerRecordings.save("stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		data, _ := encodePCM(tone(16000, 1600, 440, 0.5), Pcm16)
+		if err := saved.write(&pb.AudioChunk{AudioData: data, Info: &pb.AudioInfo{Codec: "pcm16", SampleRate: 16000, NumChannels: 1}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saved.Close()
+
+	res := serveAudio(t, newBurstSource(1, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}))
+	c := res.(RecordingReader)
+	r, err := c.GetRecording(ctx, "tone.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID() != "tone.wav" || r.Codec != "pcm16" || r.SampleRate != 8000 || r.Channels != 1 || r.Duration != time.Second {
+		t.Errorf("got %+v", r)
+	}
+	if r, err = c.GetRecording(ctx, "stream.chunks"); err != nil || r.Codec != "pcm16" || r.Duration != 300*time.Millisecond {
+		t.Errorf("got %+v: %v", r, err)
+	}
+	for _, id := range []string{"missing.wav", "../tone.wav", "tone"} {
+		if _, err := c.GetRecording(ctx, id); err == nil {
+			t.Errorf("got recording %q", id)
+		}
+	}
+
+	lister := res.(RecordingLister)
+	for _, tc := range []struct {
+		filter RecordingFilter
+		want   string
+	}{
+		{RecordingFilter{MinDuration: 500 * time.Millisecond}, "tone"},
+		{RecordingFilter{MaxDuration: 500 * time.Millisecond, Codec: "pcm16"}, "stream"},
+		{RecordingFilter{MaxSize: 10000}, "stream"},
+	} {
+		page, _, err := lister.ListRecordings(ctx, 10, "", tc.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) != 1 || page[0].Name != tc.want || page[0].Duration == 0 {
+			t.Errorf("%+v listed %+v, want %s", tc.filter, page, tc.want)
+		}
+	}
+
+	stream := func(id string, opts StreamRecordingOptions) (chunks, bytes int) {
+		t.Helper()
+		ch, err := c.StreamRecording(ctx, id, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for chunk := range ch {
+			if chunk.Err != nil {
+				t.Fatal(chunk.Err)
+			}
+			if chunks == 0 && (chunk.Header == nil || chunk.Info == nil) {
+				t.Errorf("first chunk of %s has no header", id)
+			}
+			chunks++
+			bytes += len(chunk.AudioData)
+		}
+		return chunks, bytes
+	}
+	if chunks, n := stream("tone.wav", StreamRecordingOptions{Start: 500 * time.Millisecond}); chunks != 5 || n != 8000 {
+		t.Errorf("streamed %d chunks of %d bytes, want the last 500ms in 100ms chunks", chunks, n)
+	}
+	if _, n := stream("tone.wav", StreamRecordingOptions{Codec: "pcm32_float"}); n != 32000 {
+		t.Errorf("streamed %d bytes of pcm32_float, want 32000", n)
+	}
+	if chunks, _ := stream("stream.chunks", StreamRecordingOptions{Start: 100 * time.Millisecond}); chunks != 2 {
+		t.Errorf("replayed %d chunks, want the last 2", chunks)
+	}
+}
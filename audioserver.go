@@ -2,13 +2,22 @@ package audio
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net"
-
+	"net/http"
+	"os"
+	ossignal "os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/utils/rpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
@@ -54,6 +63,38 @@ const (
 	Mp3
 )
 
+// String returns the codec name used for the format on the wire.
+func (f AudioFormat) String() string {
+	switch f {
+	case Pcm16:
+		return "pcm16"
+	case Pcm32:
+		return "pcm32"
+	case Pcm32Float:
+		return "pcm32_float"
+	case Mp3:
+		return "mp3"
+	default:
+		return fmt.Sprintf("AudioFormat(%d)", int(f))
+	}
+}
+
+// formatFromCodec maps a wire codec name back to an AudioFormat.
+func formatFromCodec(codec string) (AudioFormat, error) {
+	switch codec {
+	case "pcm16":
+		return Pcm16, nil
+	case "pcm32":
+		return Pcm32, nil
+	case "pcm32_float":
+		return Pcm32Float, nil
+	case "mp3":
+		return Mp3, nil
+	default:
+		return 0, errorf(ErrUnsupportedCodec, "unknown codec %q", codec)
+	}
+}
+
 type AudioInfo struct {
 	Format     AudioFormat
 	SampleRate int
@@ -63,22 +104,67 @@ type AudioInfo struct {
 type Properties struct {
 	SupportedFormats []AudioFormat
 	maxChannels      int
+	// What Play takes, each anything if empty. Resources whose Play
+	// converts clips to the device's rate and channels leave those empty.
+	PlaybackFormats     []AudioFormat
+	PlaybackSampleRates []int
+	PlaybackChannels    []int
 }
 
 type Audio interface {
 	resource.Resource
-	GetAudio(ctx context.Context, codec string, durationSeconds float32, max_duration float32, previous_timestamp int64) (<-chan *AudioChunk, error)
+	// GetAudio streams the capture. The int64 argument is unused and kept
+	// so implementations keep compiling; dropped streams are resumed with
+	// WithResumable and WithResumeToken.
+	//
+	// Once ctx is done the implementation stops capturing, releases the
+	// device, unless other streams still share it, and closes the channel,
+	// promptly and whether or not anyone is still reading. The server holds
+	// to the same for the context of each GetAudio call, except that a
+	// resumable stream keeps capturing for its resume window. CaptureStreams
+	// gets this right for implementations that embed it.
+	GetAudio(ctx context.Context, codec string, durationSeconds float32, max_duration float32, previous_timestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error)
+	// Play returns once the clip has played out. Implementations must let
+	// it run alongside GetAudio streams. The server plays one clip at a
+	// time on a resource, queueing Play and CommitPlayback calls in about
+	// the order they come in, the wait counting against their deadlines,
+	// so implementations only see concurrent calls from local callers.
 	Play(ctx context.Context, data []byte, codec string, sampleRate int, channels int) error
 }
 
+// serverLogger logs for the server, which isn't given a logger of its own.
+var serverLogger = logging.NewLogger("audio-server")
+
 type audioServer struct {
 	pb.UnimplementedAudioServiceServer
-	coll resource.APIResourceCollection[Audio]
+	logger     logging.Logger
+	coll       resource.APIResourceCollection[Audio]
+	hub        *captureHub
+	streams    *streamRegistry
+	prepared   *preparedClips // clips prepared for resources without native support
+	recordings *serverRecordings
+	schedules  *serverSchedules
+	resumes    *resumeRegistry
+	sessions   *serverSessions
+	latency    *latencyTrackers
+	drain      *serverDrain
+	playback   *playbackLocks
 }
 
 // NewRPCServiceServer returns a new RPC server for the Audio API.
 func NewRPCServiceServer(coll resource.APIResourceCollection[Audio]) interface{} {
-	return &audioServer{coll: coll}
+	startServerJanitor()
+	return &audioServer{logger: serverLogger, coll: coll, hub: sharedCaptureHub, streams: newStreamRegistry(), prepared: newPreparedClips(), recordings: newServerRecordings(), schedules: newServerSchedules(), resumes: newResumeRegistry(), sessions: newServerSessions(), latency: newLatencyTrackers(), drain: newServerDrain(), playback: newPlaybackLocks()}
+}
+
+// resource returns the named resource, failing with ErrNotFound when the
+// server has none by that name.
+func (s *audioServer) resource(name string) (Audio, error) {
+	a, err := s.coll.Resource(name)
+	if err != nil {
+		return nil, errorf(ErrNotFound, "%w", err)
+	}
+	return a, nil
 }
 
 // WAV header structure
@@ -98,64 +184,350 @@ type wavHeader struct {
 	Subchunk2Size uint32  // Size of data
 }
 
-func (s *audioServer) GetAudio(req *pb.GetAudioRequest, stream pb.AudioService_GetAudioServer) error {
-	fmt.Printf("Starting audio recording stream for %d seconds\n", req.DurationSeconds)
-
+func (s *audioServer) GetAudio(req *pb.GetAudioRequest, stream pb.AudioService_GetAudioServer) (err error) {
+	// the span lasts as long as the stream
+	ctx, span := startServerSpan(stream.Context(), "audio.GetAudio", req.Name,
+		attribute.String("audio.codec", req.Codec), attribute.String("audio.request_id", req.RequestId), attribute.Bool("audio.resumed", req.ResumeToken != ""))
+	defer func() { endSpan(span, err) }()
 	// Get audio chunks from the resource
-	a, err := s.coll.Resource(req.Name)
+	a, err := s.resource(req.Name)
 	if err != nil {
 		return err
 	}
+	// a resumed stream continues as it was first requested
+	var resumable *resumableStream
+	after := int64(-1)
+	if req.ResumeToken != "" {
+		if resumable, after, err = s.resumes.resume(req.Name, req.ResumeToken); err != nil {
+			return err
+		}
+		req = resumable.req
+	}
+	codec := req.Codec
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	metrics := sharedMetrics.open(stream.Context(), metricLabels{resource: req.Name, codec: codec, profile: captureProfile(req), direction: captureDirection}, req.RequestId)
+	defer func() {
+		if err != nil {
+			metrics.failed(err)
+			s.logger.Warnw("GetAudio stream failed", "resource", req.Name, "request_id", req.RequestId, "error", err)
+		} else {
+			s.logger.Debugw("GetAudio stream ended", "resource", req.Name, "request_id", req.RequestId)
+		}
+		metrics.close()
+	}()
+	if err := checkCompression(req.Compression); err != nil {
+		return err
+	}
+	batchDelay := secondsToDuration(req.BatchMaxDelaySeconds)
+	if err := checkBatch(int(req.BatchChunks), batchDelay); err != nil {
+		return err
+	}
+	heartbeatInterval := secondsToDuration(req.HeartbeatSeconds)
+	if err := checkHeartbeat(heartbeatInterval); err != nil {
+		return err
+	}
+	// strict chunks are checked as they are sent too, as a resource can ignore WithStrict
+	var strict AudioInfo
+	if req.Strict {
+		if strict, err = strictCaptureFormat(req); err != nil {
+			return err
+		}
+	}
 
-	chunkChan, err := a.GetAudio(stream.Context(), req.Codec, req.DurationSeconds, req.MaxDurationSeconds, int64(req.PreviousTimestamp))
+	// streams started with a request id can be paused and resumed
+	var st *activeStream
+	if req.RequestId != "" {
+		if st, err = s.streams.add(req.Name, req.RequestId); err != nil {
+			return err
+		}
+		if id, ok := IdentityFromContext(stream.Context()); ok {
+			st.setOwner(id)
+		}
+		defer s.streams.remove(req.Name, req.RequestId)
+	}
+	// nothing is captured without a record of who asked for it
+	if err := s.audit(ctx, req.Name, AuditCaptureStarted, req.RequestId, codec, nil); err != nil {
+		return err
+	}
+	defer func() { s.audit(ctx, req.Name, AuditCaptureEnded, req.RequestId, codec, err) }()
+	// the resource can power its device up before the capture opens it
+	streamEnded := observeStream(ctx, a, StreamEvent{Resource: req.Name, Direction: captureDirection, RequestID: req.RequestId, Codec: codec})
+	defer func() { streamEnded(err) }()
+
+	if req.Resumable && resumable == nil {
+		// the capture outlives this call, and so what it drops isn't counted
+		// against it
+		if resumable, err = s.resumes.start(req, func(ctx context.Context) (<-chan *AudioChunk, error) {
+			return s.openCapture(ctx, a, req, nil)
+		}); err != nil {
+			return err
+		}
+	}
+	var chunkChan <-chan *AudioChunk
+	var dropped atomic.Int64
+	if resumable != nil {
+		chunkChan, err = resumable.attach(ctx, after)
+	} else {
+		chunkChan, err = s.openCapture(ctx, a, req, func(n int) {
+			dropped.Add(int64(n))
+			metrics.dropped(n)
+		})
+	}
 	if err != nil {
 		return err
 	}
+	// the copy is written from the messages as sent, so it can't differ from what the client got
+	var saved *savedStream
+	if req.AlsoSaveAs != "" {
+		if saved, err = ServerRecordings.save(req.AlsoSaveAs); err != nil {
+			return err
+		}
+		defer saved.Close()
+	}
+	headers := &headerTracker{codec: codec}
+	if req.Compression != "" {
+		if err := grpc.SetSendCompressor(stream.Context(), req.Compression); err != nil {
+			return fmt.Errorf("cannot compress the stream with %s: %w", req.Compression, err)
+		}
+	}
+	// the headers tell the client the stream is set up, which can be long
+	// before the first chunk when OnlyWhen or SpeechOnly hold audio back
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	s.logger.Debugw("GetAudio stream started", "resource", req.Name, "request_id", req.RequestId, "codec", codec, "resumed", after >= 0)
+
+	// the messages are reused from one batch to the next, and their
+	// AudioInfo while the format holds, as Send has marshalled them by the
+	// time it returns
+	batch := newChunkBatch(int(req.BatchChunks), batchDelay)
+	defer batch.reset()
+	var info *pb.AudioInfo
+	latency := s.latency.get(req.Name)
+	beat := newHeartbeat(heartbeatInterval)
+	defer beat.stop()
+	send := func() error {
+		msg := batch.message()
+		if msg == nil {
+			return nil
+		}
+		// Send marshalled the chunks before returning
+		defer batch.reset()
+		sent := time.Now().UnixNano()
+		for _, audioChunk := range batch.sent() {
+			if audioChunk.EndTimestampNanoseconds != 0 {
+				audioChunk.SentTimestampNanoseconds = sent
+			}
+		}
+		if err := stream.Send(msg); err != nil {
+			return fmt.Errorf("failed to send audio chunk: %w", err)
+		}
+		beat.sent()
+		logChunks := sharedChunkLogging.enabled(req.Name)
+		for _, audioChunk := range batch.sent() {
+			if logChunks {
+				s.logger.Infow("sent chunk", "resource", req.Name, "request_id", req.RequestId, "sequence", audioChunk.Sequence,
+					"bytes", len(audioChunk.AudioData), "start_ns", audioChunk.StartTimestampNanoseconds,
+					"gap", time.Duration(audioChunk.GapNanoseconds), "dropped", audioChunk.DroppedChunks, "batched", len(msg.Batch))
+			}
+			if audioChunk.SentTimestampNanoseconds != 0 {
+				latency.Add(time.Duration(sent - audioChunk.EndTimestampNanoseconds))
+			}
+			metrics.transferred(len(audioChunk.AudioData), time.Duration(audioChunk.GapNanoseconds))
+			if saved != nil {
+				if err := saved.write(audioChunk); err != nil {
+					return fmt.Errorf("failed to save audio chunk: %w", err)
+				}
+			}
+		}
+		return nil
+	}
+
+	// chunks are numbered as sent, resumable ones by their place in the
+	// capture so that the numbers carry on when the stream is resumed
+	var sequence int64
 
 	// Stream audio chunks
 	for {
 		select {
 		case <-stream.Context().Done():
-			fmt.Println("Client disconnected, stopping recording")
+			// a server shutting down still has a client to send the batch to
+			if s.drain.draining() {
+				return send()
+			}
 			return nil
 
+		case <-batch.due():
+			if err := send(); err != nil {
+				return err
+			}
+
+		case <-beat.due():
+			// a batch waiting to go out does as well
+			if batch.message() != nil {
+				if err := send(); err != nil {
+					return err
+				}
+			} else if err := stream.Send(beat.message()); err != nil {
+				return fmt.Errorf("failed to send heartbeat: %w", err)
+			} else {
+				beat.sent()
+			}
+
 		case chunk, ok := <-chunkChan:
 			if !ok {
-				fmt.Println("Audio capture channel closed")
-				return nil
+				return send()
 			}
 			if chunk.Err != nil {
-				return fmt.Errorf("audio capture error: %w", err)
+				if err := send(); err != nil {
+					return err
+				}
+				return fmt.Errorf("audio capture error: %w", chunk.Err)
+			}
+			if req.Strict {
+				if err := strictMismatch(captureDirection, strict, chunk.Info); err != nil {
+					return err
+				}
+			}
+			var gap time.Duration
+			if st != nil {
+				var send bool
+				if send, gap = st.admit(chunk); !send {
+					chunk.Release()
+					continue
+				}
 			}
+			seq := sequence
+			if resumable != nil {
+				seq = chunk.Sequence
+			}
+			sequence++
 			// convert the chunk struct to a pb.audiochunk
-			audioChunk := &pb.AudioChunk{
-				AudioData: chunk.AudioData,
+			audioChunk, full := batch.next(chunk)
+			*audioChunk = pb.AudioChunk{
+				AudioData:      chunk.AudioData,
+				Sequence:       int32(seq),
+				GapNanoseconds: (chunk.Gap + gap).Nanoseconds(),
+				Speech:         chunk.Speech,
+				Timecode:       timecodeToProto(chunk.Timecode),
+				DroppedChunks:  dropped.Load(),
+				BitrateKbps:    int32(chunk.BitrateKbps),
+			}
+			if resumable != nil {
+				audioChunk.ResumeToken = resumable.token(chunk.Sequence)
+			}
+			if req.Checksums {
+				setChecksum(audioChunk)
+			}
+			if !chunk.Timestamp.IsZero() {
+				audioChunk.StartTimestampNanoseconds = chunk.Timestamp.UnixNano()
+				dur, _ := chunkDuration(chunk)
+				audioChunk.EndTimestampNanoseconds = chunk.Timestamp.Add(dur).UnixNano()
+			}
+			if chunk.Info != nil {
+				if info == nil || info.Codec != chunk.Info.Format.String() || info.SampleRate != int32(chunk.Info.SampleRate) || info.NumChannels != int32(chunk.Info.Channels) {
+					info = &pb.AudioInfo{
+						Codec:       chunk.Info.Format.String(),
+						SampleRate:  int32(chunk.Info.SampleRate),
+						NumChannels: int32(chunk.Info.Channels),
+					}
+				}
+				audioChunk.Info = info
+			}
+			if h := headers.next(chunk); h != nil {
+				audioChunk.Header = &pb.StreamHeader{
+					Info: &pb.AudioInfo{
+						Codec:       h.Codec,
+						SampleRate:  int32(h.SampleRate),
+						NumChannels: int32(h.Channels),
+					},
+					Extradata:   h.Extradata,
+					ChunkFrames: int32(h.ChunkFrames),
+				}
 			}
 
-			// Send chunk to client
-			if err := stream.Send(audioChunk); err != nil {
-				return fmt.Errorf("failed to send audio chunk: %w", err)
+			if full {
+				if err := send(); err != nil {
+					return err
+				}
 			}
 		}
 	}
 }
 
-func (s *audioServer) Play(ctx context.Context, req *pb.PlayRequest) (*pb.PlayResponse, error) {
-	a, err := s.coll.Resource(req.Name)
+func (s *audioServer) Play(ctx context.Context, req *pb.PlayRequest) (_ *pb.PlayResponse, err error) {
+	ctx, span := startServerSpan(ctx, "audio.Play", req.Name,
+		attribute.String("audio.codec", req.Info.GetCodec()), attribute.Int("audio.bytes", len(req.AudioData)))
+	defer func() { endSpan(span, err) }()
+	a, err := s.resource(req.Name)
 	if err != nil {
 		return nil, err
 	}
+	metrics := sharedMetrics.open(ctx, metricLabels{resource: req.Name, codec: req.Info.Codec, profile: playbackProfile(ctx, a), direction: playbackDirection}, "")
+	defer metrics.close()
 
-	err = a.Play(ctx, req.AudioData, req.Info.Codec, int(req.Info.SampleRate), int(req.Info.NumChannels))
+	if req.Strict {
+		ctx = StrictPlayback(ctx)
+	}
+	data := req.AudioData
+	codec, rate, channels := req.Info.GetCodec(), int(req.Info.GetSampleRate()), int(req.Info.GetNumChannels())
+	props := playbackProperties(ctx, a)
+	if err := checkPlayback(req.Name, props, codec, rate, channels); err != nil {
+		if !req.Convert || req.Strict {
+			metrics.failed(err)
+			return nil, err
+		}
+		_, convertSpan := startSpan(ctx, "audio.convert")
+		converted, info, ok := convertPlayback(props, data, codec, rate, channels)
+		if !ok {
+			endSpan(convertSpan, err)
+			metrics.failed(err)
+			return nil, err
+		}
+		convertSpan.SetAttributes(attribute.String("audio.codec", info.Format.String()), attribute.Int("audio.sample_rate", info.SampleRate), attribute.Int("audio.channels", info.Channels))
+		convertSpan.End()
+		data, codec, rate, channels = converted, info.Format.String(), info.SampleRate, info.Channels
+	}
+	if req.NormalizeLufs != 0 {
+		_, normalizeSpan := startSpan(ctx, "audio.normalize", attribute.Float64("audio.target_lufs", float64(req.NormalizeLufs)))
+		data, err = NormalizeLoudness(data, codec, rate, channels, float64(req.NormalizeLufs))
+		endSpan(normalizeSpan, err)
+		if err != nil {
+			metrics.failed(err)
+			return nil, err
+		}
+	}
+	// waiting for a clip already playing on the resource
+	_, waitSpan := startSpan(ctx, "audio.wait_device")
+	release, err := s.playback.acquire(ctx, req.Name)
+	endSpan(waitSpan, err)
+	if err != nil {
+		metrics.failed(err)
+		return nil, err
+	}
+	defer release()
+	if err := s.audit(ctx, req.Name, AuditPlay, "", fmt.Sprintf("%s, %d Hz, %d channels, %d bytes", codec, rate, channels, len(data)), nil); err != nil {
+		metrics.failed(err)
+		return nil, err
+	}
+	playbackDone := observeStream(ctx, a, StreamEvent{Resource: req.Name, Direction: playbackDirection, Codec: codec, SampleRate: rate, Channels: channels})
+	playCtx, playSpan := startSpan(ctx, "audio.play_device", attribute.Int("audio.sample_rate", rate), attribute.Int("audio.channels", channels))
+	err = a.Play(playCtx, data, codec, rate, channels)
+	endSpan(playSpan, err)
+	playbackDone(err)
 	if err != nil {
+		metrics.failed(err)
 		return nil, err
 	}
+	metrics.transferred(len(req.AudioData), 0)
 	return &pb.PlayResponse{}, nil
 
 }
 
 // func (s *audioServer) Properties(ctx context.Context, req *pb.PropertiesRequest) (*pb.PropertiesResponse, error) {
-// 	// a, err := s.coll.Resource(req.Name)
+// 	// a, err := s.resource(req.Name)
 // 	// if err != nil {
 // 	// 	return nil, err
 // 	// }
@@ -170,25 +542,32 @@ func (s *audioServer) Play(ctx context.Context, req *pb.PlayRequest) (*pb.PlayRe
 // }
 
 func newServer() *audioServer {
-	return &audioServer{}
+	coll, err := resource.NewAPIResourceCollection[Audio](API, nil)
+	if err != nil {
+		serverLogger.Errorw("failed to create resource collection", "error", err)
+		os.Exit(1)
+	}
+	startServerJanitor()
+	return &audioServer{logger: serverLogger, coll: coll, hub: sharedCaptureHub, streams: newStreamRegistry(), prepared: newPreparedClips(), recordings: newServerRecordings(), schedules: newServerSchedules(), resumes: newResumeRegistry(), sessions: newServerSessions(), latency: newLatencyTrackers(), drain: newServerDrain(), playback: newPlaybackLocks()}
 }
 
 type serviceClient struct {
 	resource.Named
-	resource.AlwaysRebuild
 	resource.TriviallyCloseable
 	client pb.AudioServiceClient
 	logger logging.Logger
 }
 
-// NewClientFromConn creates a new Speech RPC client from an existing connection.
-func NewClientFromConn(conn rpc.ClientConn, remoteName string, name resource.Name, logger logging.Logger) Audio {
-	sc := newSvcClientFromConn(conn, remoteName, name, logger)
+// NewClientFromConn creates a new Speech RPC client from an existing
+// connection. Options set the client's timeouts, retries, message size and
+// interceptors apart from the connection's.
+func NewClientFromConn(conn rpc.ClientConn, remoteName string, name resource.Name, logger logging.Logger, opts ...ClientOption) Audio {
+	sc := newSvcClientFromConn(conn, remoteName, name, logger, NewClientOptions(opts...))
 	return clientFromSvcClient(sc, name.ShortName())
 }
 
-func newSvcClientFromConn(conn rpc.ClientConn, remoteName string, name resource.Name, logger logging.Logger) *serviceClient {
-	client := pb.NewAudioServiceClient(conn)
+func newSvcClientFromConn(conn rpc.ClientConn, remoteName string, name resource.Name, logger logging.Logger, o ClientOptions) *serviceClient {
+	client := pb.NewAudioServiceClient(typedErrorsConn{tracingConn{withClientOptions(conn, o, name.String())}})
 	sc := &serviceClient{
 		Named:  name.PrependRemote(remoteName).AsNamed(),
 		client: client,
@@ -199,70 +578,251 @@ func newSvcClientFromConn(conn rpc.ClientConn, remoteName string, name resource.
 
 type audioClient struct {
 	*serviceClient
-	name string
+	name    string
+	release func() error // gives up a pooled connection, nil if the client doesn't own one
 }
 
 func clientFromSvcClient(sc *serviceClient, name string) Audio {
-	return &audioClient{sc, name}
+	return &audioClient{serviceClient: sc, name: name}
 }
 
 func (c *audioClient) Name() resource.Name {
 	return Named(c.name)
 }
 
+func (c *audioClient) Close(ctx context.Context) error {
+	if c.release == nil {
+		return nil
+	}
+	return c.release()
+}
+
+// Reconfigure keeps the client, and the streams it has open, for as long
+// as conf names the same resource; the server's resource takes its own
+// config changes. A new name rebuilds the client.
 func (c *audioClient) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	if conf.ResourceName().ShortName() != c.name {
+		return resource.AlwaysRebuild{}.Reconfigure(ctx, deps, conf)
+	}
 	return nil
 }
 
+// DoCommand sends cmd to the resource through the server, which answers
+// dump_state, list_sessions and reset_device itself. Numbers in the result
+// are float64s, as they are from JSON.
 func (c *audioClient) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
-	return nil, nil
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, errorf(ErrInvalidArgument, "invalid command: %w", err)
+	}
+	resp, err := c.client.DoCommand(ctx, &pb.DoCommandRequest{Name: c.name, CommandJson: string(data)})
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(resp.ResultJson), &result); err != nil {
+		return nil, fmt.Errorf("invalid result from %s: %w", c.name, err)
+	}
+	return result, nil
 }
 
 type AudioChunk struct {
 	Sequence  int64
 	AudioData []byte
-	Err       error // send errors through the channel
+	Info      *AudioInfo    // format of AudioData, nil if the implementation doesn't report it
+	Gap       time.Duration // audio skipped right before this chunk, e.g. while the stream was paused
+	Header    *StreamHeader // set when the stream starts or its format changes
+	Timestamp time.Time     // capture time of the first sample on the source's clock, zero if unknown
+	Speech    *bool         // whether the chunk contains speech, nil unless requested with WithVAD
+	Timecode  *Timecode     // the last LTC frame read in the chunk, nil if there was none
+	// ResumeToken resumes the stream right after this chunk with
+	// WithResumeToken, set on streams requested WithResumable.
+	ResumeToken string
+	// Dropped counts the chunks the stream dropped so far because its
+	// consumer fell behind, see WithDropPolicy.
+	Dropped int64
+	// BitrateKbps is the bitrate of the chunk on streams requested
+	// WithAdaptiveBitrate, zero otherwise.
+	BitrateKbps int
+	// Sent is when the server sent the chunk and Received when the client
+	// got it, zero on chunks that didn't come from a server. See Latency.
+	Sent     time.Time
+	Received time.Time
+	// Discontinuity is set on the first chunk after the timeline jumped,
+	// on streams from a server.
+	Discontinuity *Discontinuity
+	// Reconnected marks the first chunk after a stream requested
+	// WithReconnect was re-opened.
+	Reconnected bool
+	// End marks the empty chunk that closes a stream requested
+	// WithEndOfStream once the server ended it cleanly.
+	End bool
+	// Err ends the stream with what went wrong, a *StreamError for streams
+	// from a server that failed.
+	Err    error
+	pooled bool // AudioData is borrowed, see Release
 }
 
-func (c *audioClient) GetAudio(ctx context.Context, codec string, durationSeconds float32, max_duration float32, previous_timestamp int64) (<-chan *AudioChunk, error) {
-	stream, err := c.client.GetAudio(ctx, &pb.GetAudioRequest{
-		Name:               c.name,
-		DurationSeconds:    durationSeconds,
-		Codec:              codec,
-		MaxDurationSeconds: max_duration,
-		PreviousTimestamp:  float32(previous_timestamp),
-	})
+func (c *audioClient) GetAudio(ctx context.Context, codec string, durationSeconds float32, max_duration float32, previous_timestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	o := NewGetAudioOptions(opts...)
+	req := &pb.GetAudioRequest{
+		Name:                   c.name,
+		DurationSeconds:        durationSeconds,
+		Codec:                  codec,
+		MaxDurationSeconds:     max_duration,
+		SampleRate:             int32(o.SampleRate),
+		NumChannels:            int32(o.Channels),
+		RequestId:              o.RequestID,
+		OnlyWhen:               o.OnlyWhen,
+		PreRollSeconds:         float32(o.PreRoll.Seconds()),
+		PostRollSeconds:        float32(o.PostRoll.Seconds()),
+		Vad:                    o.VAD,
+		SpeechOnly:             o.SpeechOnly,
+		TrimSilence:            o.TrimSilence,
+		SilenceThresholdDbfs:   float32(o.SilenceThresholdDBFS),
+		SilenceHangoverSeconds: float32(o.SilenceHangover.Seconds()),
+		NoiseSuppression:       o.NoiseSuppression,
+		Agc:                    o.AGC,
+		AgcTargetDbfs:          float32(o.AGCTargetDBFS),
+		AlsoSaveAs:             o.AlsoSaveAs,
+		Strict:                 o.Strict,
+		Resumable:              o.Resumable,
+		ResumeToken:            o.ResumeToken,
+		ChunkDurationSeconds:   float32(o.ChunkDuration.Seconds()),
+		DropPolicy:             o.DropPolicy,
+		BufferChunks:           int32(o.BufferChunks),
+		Compression:            o.Compression,
+		BatchChunks:            int32(o.BatchChunks),
+		BatchMaxDelaySeconds:   float32(o.BatchDelay.Seconds()),
+		AdaptiveBitrate:        o.AdaptiveBitrate,
+		MinBitrateKbps:         int32(o.MinBitrateKbps),
+		MaxBitrateKbps:         int32(o.MaxBitrateKbps),
+		HeartbeatSeconds:       float32(o.Heartbeat.Seconds()),
+		Checksums:              o.Checksums,
+	}
+	stream, err := openWatched(ctx, c.client, req, o.Heartbeat)
 
 	if err != nil {
 		return nil, err
 	}
 
 	ch := make(chan *AudioChunk)
+	var rc *reconnection
+	if o.Reconnect != nil {
+		rc = &reconnection{c: c, req: req, policy: *o.Reconnect, heartbeat: o.Heartbeat}
+	}
 
-	// Receive and process audio chunks
+	// Receive and process audio chunks until the server ends the stream or
+	// it fails; once ctx is done nobody may be reading any more
 	go func() {
 		defer close(ch)
+		deliver := func(chunk *AudioChunk) bool {
+			select {
+			case ch <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		reconnected := false
+		var timeline discontinuityTracker
 		for {
 			chunk, err := stream.Recv()
-			if err != nil {
-				if err.Error() != "EOF" {
-					ch <- &AudioChunk{Err: err} // propagate error
+			if err = streamError(err); err != nil && rc != nil && ctx.Err() == nil {
+				stream, chunk, err = rc.reopen(ctx, err)
+				reconnected = chunk != nil
+			}
+			if chunk == nil {
+				if err != nil {
+					deliver(&AudioChunk{Err: err})
+				} else if o.EndOfStream {
+					deliver(&AudioChunk{End: true})
 				}
-				fmt.Println("backgorund routine returning")
-				fmt.Println(err.Error())
 				return
 			}
 
-			ch <- &AudioChunk{
-				AudioData: chunk.AudioData,
+			// the codec borrowed the audio for this chunk alone
+			received := time.Now()
+			for _, msg := range unbatch(chunk) {
+				// a corrupted chunk ends the stream rather than play as noise
+				if o.Checksums {
+					if err := verifyChecksum(msg); err != nil {
+						deliver(&AudioChunk{Err: err})
+						return
+					}
+				}
+				out := chunkFromProto(msg)
+				out.Received = received
+				out.pooled = out.AudioData != nil
+				if rc != nil {
+					if reconnected {
+						rc.reconnected(out)
+						reconnected = false
+					}
+					rc.received(out)
+				}
+				timeline.check(out)
+				if !deliver(out) {
+					out.Release()
+					return
+				}
 			}
 		}
 	}()
 
-	fmt.Println("client is returning")
 	return ch, nil
 }
 
+func chunkFromProto(chunk *pb.AudioChunk) *AudioChunk {
+	out := &AudioChunk{
+		Sequence:    int64(chunk.Sequence),
+		AudioData:   chunk.AudioData,
+		Info:        infoFromProto(chunk.Info),
+		Gap:         time.Duration(chunk.GapNanoseconds),
+		Header:      headerFromProto(chunk.Header),
+		Speech:      chunk.Speech,
+		Timecode:    timecodeFromProto(chunk.Timecode),
+		ResumeToken: chunk.ResumeToken,
+		Dropped:     chunk.DroppedChunks,
+		BitrateKbps: int(chunk.BitrateKbps),
+	}
+	if chunk.StartTimestampNanoseconds != 0 {
+		out.Timestamp = time.Unix(0, chunk.StartTimestampNanoseconds)
+	}
+	if chunk.SentTimestampNanoseconds != 0 {
+		out.Sent = time.Unix(0, chunk.SentTimestampNanoseconds)
+	}
+	return out
+}
+
+func infoFromProto(info *pb.AudioInfo) *AudioInfo {
+	if info == nil {
+		return nil
+	}
+	format, err := formatFromCodec(info.Codec)
+	if err != nil {
+		return nil
+	}
+	return &AudioInfo{
+		Format:     format,
+		SampleRate: int(info.SampleRate),
+		Channels:   int(info.NumChannels),
+	}
+}
+
+func headerFromProto(h *pb.StreamHeader) *StreamHeader {
+	if h == nil {
+		return nil
+	}
+	return &StreamHeader{
+		Codec:       h.GetInfo().GetCodec(),
+		SampleRate:  int(h.GetInfo().GetSampleRate()),
+		Channels:    int(h.GetInfo().GetNumChannels()),
+		Extradata:   h.Extradata,
+		ChunkFrames: int(h.ChunkFrames),
+	}
+}
+
 func (c *audioClient) Play(ctx context.Context, audio []byte, codec string, sampleRate int, channels int) error {
 
 	info := &pb.AudioInfo{
@@ -274,6 +834,8 @@ func (c *audioClient) Play(ctx context.Context, audio []byte, codec string, samp
 		Name:      c.name,
 		AudioData: audio,
 		Info:      info,
+		Strict:    isStrictPlayback(ctx),
+		Convert:   isConvertPlayback(ctx),
 	})
 
 	if err != nil {
@@ -297,13 +859,99 @@ func (c *audioClient) Play(ctx context.Context, audio []byte, codec string, samp
 // }
 
 func main() {
+	logger := serverLogger
 	lis, err := net.Listen("tcp", "localhost:50051")
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		logger.Errorw("failed to listen", "error", err)
+		os.Exit(1)
+	}
+
+	server := newServer()
+	// with a secret, only requests bearing HS256 tokens signed with it are served
+	var opts []grpc.ServerOption
+	var auth Authenticator
+	if secret := os.Getenv("AUDIO_JWT_SECRET"); secret != "" {
+		auth = &JWTAuthenticator{Key: []byte(secret)}
+		opts = AuthServerOptions(auth)
+	}
+	grpcServer := grpc.NewServer(append(opts, server.drainOptions()...)...)
+	pb.RegisterAudioServiceServer(grpcServer, server)
+
+	// Plain HTTP access to live capture, e.g. curl localhost:8080/audio/mic > mic.wav
+	// its requests end as the server starts shutting down, as streams do, and
+	// with a secret they need a token too
+	httpHandler := NewHTTPHandler(server.coll.Resource, serverVerbosity.sublogger("http"))
+	if auth != nil {
+		httpHandler = AuthHTTPHandler(httpHandler, auth)
+	}
+	httpServer := &http.Server{
+		Addr:        "localhost:8080",
+		Handler:     httpHandler,
+		BaseContext: func(net.Listener) context.Context { return server.drain.ctx },
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorw("http server stopped", "error", err)
+		}
+	}()
+
+	// metrics alone on an address of their own, e.g. AUDIO_METRICS_ADDR=:9100
+	// for a Prometheus off the host; they're on the HTTP server above too
+	var metricsServer *http.Server
+	if addr := os.Getenv("AUDIO_METRICS_ADDR"); addr != "" {
+		metricsServer = &http.Server{Addr: addr, Handler: MetricsHandler()}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Errorw("metrics server stopped", "error", err)
+			}
+		}()
+	}
+
+	// RTP over RTSP for VLC, ffmpeg and NVRs, e.g. ffplay rtsp://localhost:8554/mic
+	rtspServer, err := NewRTSPServer(server.coll.Resource, RTSPConfig{}, serverVerbosity.sublogger("rtsp"))
+	if err != nil {
+		logger.Errorw("failed to create rtsp server", "error", err)
+	} else {
+		go func() {
+			rtspLis, err := net.Listen("tcp", "localhost:8554")
+			if err != nil {
+				logger.Errorw("failed to listen for rtsp", "error", err)
+				return
+			}
+			if err := rtspServer.Serve(rtspLis); err != nil {
+				logger.Errorw("rtsp server stopped", "error", err)
+			}
+		}()
 	}
 
-	grpcServer := grpc.NewServer()
-	pb.RegisterAudioServiceServer(grpcServer, newServer())
-	fmt.Println("serving....")
-	grpcServer.Serve(lis)
+	// on SIGINT or SIGTERM, streams end with a status clients can act on and
+	// recordings are completed before the process exits
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		sigs := make(chan os.Signal, 1)
+		ossignal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+		logger.Infow("shutting down", "signal", <-sigs)
+		ossignal.Stop(sigs)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		if err := server.shutdown(ctx); err != nil {
+			logger.Errorw("failed to stop recordings", "error", err)
+		}
+		if rtspServer != nil {
+			rtspServer.Close()
+		}
+		httpServer.Shutdown(ctx)
+		if metricsServer != nil {
+			metricsServer.Shutdown(ctx)
+		}
+		gracefulStop(ctx, grpcServer)
+	}()
+
+	logger.Infow("serving", "address", lis.Addr().String())
+	if err := grpcServer.Serve(lis); err != nil {
+		logger.Errorw("failed to serve", "error", err)
+		os.Exit(1)
+	}
+	<-stopped
 }
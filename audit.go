@@ -0,0 +1,394 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"google.golang.org/grpc/peer"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Actions of an AuditRecord.
+const (
+	AuditCaptureStarted     = "capture_started"
+	AuditCaptureEnded       = "capture_ended"
+	AuditPlay               = "play"
+	AuditClipCaptured       = "clip_captured"
+	AuditRecordingStarted   = "recording_started"
+	AuditRecordingStopped   = "recording_stopped"
+	AuditSessionStarted     = "session_started"
+	AuditSessionStopped     = "session_stopped"
+	AuditRecordingScheduled = "recording_scheduled"
+	AuditRecordingRead      = "recording_read"
+)
+
+// defaultAuditCapacity is how many records an AuditLog without a file
+// keeps.
+const defaultAuditCapacity = 10000
+
+// AuditRecord is an operation on a resource's audio: who did what, when.
+// Records are chained by their hashes, each the SHA-256 of the one before's
+// and of the record, so a record removed or changed breaks the chain.
+type AuditRecord struct {
+	Seq       uint64    `json:"seq"` // counts the records of the whole log from 1
+	Time      time.Time `json:"time"`
+	Resource  string    `json:"resource"`
+	Action    string    `json:"action"`
+	Subject   string    `json:"subject,omitempty"`    // the caller's identity when the server authenticates
+	Peer      string    `json:"peer,omitempty"`       // the caller's address
+	RequestID string    `json:"request_id,omitempty"` // the stream's, or the recording's or session's id
+	Detail    string    `json:"detail,omitempty"`
+	Err       string    `json:"error,omitempty"`
+	Hash      string    `json:"hash"`
+}
+
+// AuditLister is implemented by clients of servers that keep an audit log.
+// Pages are newest first; an empty token starts from the newest and an
+// empty next token ends the log.
+type AuditLister interface {
+	ListAuditLog(ctx context.Context, pageSize int, pageToken string, filter AuditFilter) ([]AuditRecord, string, error)
+}
+
+// AuditLog is an append-only log of the operations on the server's audio,
+// so that operators can show when a microphone was listened to and by
+// whom. In a file, it keeps every record, one JSON object per line, synced
+// as it is written; in memory it keeps the newest 10000.
+//
+// The log records the gRPC calls that capture, play, clip, record or read
+// recordings, prepared clips as they are committed, scheduled recordings
+// and those a level trigger starts, and the streams of the HTTP, HLS,
+// RTSP, WebRTC, Icecast, RTP and SIP bridges. Starting any of them is
+// refused when its record can't be written, so nothing is captured or
+// played without a record of it.
+type AuditLog struct {
+	path string // empty when the log is kept in memory
+
+	mu     sync.Mutex
+	err    error // loading the file, which fails every record
+	memory []AuditRecord
+	size   int64 // of the records in the file
+	next   uint64
+	last   string // hash of the newest record, or of the newest dropped from memory
+}
+
+// ServerAudit is the audit log of the RPC server, kept in the file named
+// by AUDIO_AUDIT_LOG if it is set.
+var ServerAudit = NewAuditLog(os.Getenv("AUDIO_AUDIT_LOG"))
+
+// NewAuditLog returns a log appending to the file at path, continuing the
+// records already there, or kept in memory if path is empty. A file whose
+// records don't chain fails every record until it is dealt with.
+func NewAuditLog(path string) *AuditLog {
+	l := &AuditLog{path: path, next: 1}
+	if path != "" {
+		l.err = l.load()
+	}
+	return l
+}
+
+// load continues the records in the file, checking their chain. A last
+// record cut short, as a crash or power loss while it was written leaves,
+// is cut off: its operation never went ahead.
+func (l *AuditLog) load() error {
+	f, err := os.OpenFile(l.path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	var prev AuditRecord
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				serverLogger.Warnw("cutting off a record the audit log was left writing", "path", l.path, "bytes", len(line))
+				if err := f.Truncate(l.size); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var r AuditRecord
+		if err := json.Unmarshal(line, &r); err != nil {
+			return fmt.Errorf("audit record after %d in %s: %w", prev.Seq, l.path, err)
+		}
+		if err := auditFollows(prev, r); err != nil {
+			return err
+		}
+		prev, l.size = r, l.size+int64(len(line))
+	}
+	l.next, l.last = prev.Seq+1, prev.Hash
+	return nil
+}
+
+// auditFollows checks that r is the record after prev, or the first if
+// prev is zero.
+func auditFollows(prev, r AuditRecord) error {
+	if r.Seq != prev.Seq+1 {
+		return fmt.Errorf("audit record %d follows record %d", r.Seq, prev.Seq)
+	}
+	if r.Hash != auditHash(prev.Hash, r) {
+		return fmt.Errorf("audit record %d doesn't match its hash", r.Seq)
+	}
+	return nil
+}
+
+// append records r, numbering, timing and chaining it.
+func (l *AuditLog) append(r AuditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return fmt.Errorf("the audit log is broken: %w", l.err)
+	}
+	r.Seq, r.Time = l.next, time.Now().UTC()
+	r.Hash = auditHash(l.last, r)
+	if l.path == "" {
+		if len(l.memory) == defaultAuditCapacity {
+			l.memory = l.memory[1:]
+		}
+		l.memory = append(l.memory, r)
+	} else if err := l.write(r); err != nil {
+		return fmt.Errorf("cannot write the audit log: %w", err)
+	}
+	l.next, l.last = r.Seq+1, r.Hash
+	return nil
+}
+
+func (l *AuditLog) write(r AuditRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	// the log says who captured audio, so only the server reads it
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	n, werr := f.Write(append(b, '\n'))
+	if werr = errors.Join(werr, f.Sync(), f.Close()); werr != nil {
+		// a record only partly written would break the chain of the next
+		if n > 0 {
+			werr = errors.Join(werr, os.Truncate(l.path, l.size))
+		}
+		return werr
+	}
+	l.size += int64(n)
+	return nil
+}
+
+// snapshot returns what scan reads of the records appended so far, which
+// it can then read while records are appended.
+func (l *AuditLog) snapshot() ([]AuditRecord, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.memory, l.size
+}
+
+// auditHash chains r to the record hashed prev.
+func auditHash(prev string, r AuditRecord) string {
+	r.Hash = ""
+	b, _ := json.Marshal(r)
+	h := sha256.New()
+	h.Write([]byte(prev))
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// scan calls fn with the records of a snapshot, oldest first, stopping at
+// its first error. Appends only add to the file and memory, so it reads
+// them without holding up the records being appended meanwhile.
+func (l *AuditLog) scan(memory []AuditRecord, size int64, fn func(AuditRecord) error) error {
+	if l.path == "" {
+		for _, r := range memory {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if size == 0 {
+		return nil
+	}
+	f, err := os.Open(l.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(io.LimitReader(f, size))
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		var r AuditRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return fmt.Errorf("line %d of %s: %w", line, l.path, err)
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// Verify checks the chain of records, returning where it first breaks.
+func (l *AuditLog) Verify() error {
+	l.mu.Lock()
+	err := l.err
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	memory, size := l.snapshot()
+	var prev AuditRecord
+	return l.scan(memory, size, func(r AuditRecord) error {
+		// the records before the oldest in memory are gone
+		if prev.Seq != 0 || l.path != "" {
+			if err := auditFollows(prev, r); err != nil {
+				return err
+			}
+		}
+		prev = r
+		return nil
+	})
+}
+
+// page returns up to size records matching keep that are older than record
+// before, or from the newest if before is 0, newest first. It also returns
+// where the next page starts, 0 if there are no older records.
+func (l *AuditLog) page(before uint64, size int, keep func(AuditRecord) bool) ([]AuditRecord, uint64, error) {
+	memory, fileSize := l.snapshot()
+	var window []AuditRecord // the newest matches so far, oldest first
+	err := l.scan(memory, fileSize, func(r AuditRecord) error {
+		if (before == 0 || r.Seq < before) && keep(r) {
+			if window = append(window, r); len(window) > size {
+				window = window[1:]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]AuditRecord, 0, len(window))
+	for i := len(window) - 1; i >= 0; i-- {
+		out = append(out, window[i])
+	}
+	if len(out) < size {
+		return out, 0, nil
+	}
+	return out, window[0].Seq, nil
+}
+
+// audit records action on the resource by the caller of ctx in the
+// server's audit log.
+func (s *audioServer) audit(ctx context.Context, resource, action, requestID, detail string, err error) error {
+	return auditOperation(ctx, s.logger, resource, action, requestID, detail, err)
+}
+
+// auditOperation records action on the resource by the caller of ctx, as
+// the gRPC and HTTP servers and the RTSP, WebRTC, Icecast and SIP bridges
+// establish it, in the server's audit log.
+func auditOperation(ctx context.Context, logger logging.Logger, resource, action, requestID, detail string, err error) error {
+	r := AuditRecord{Resource: resource, Action: action, RequestID: requestID, Detail: detail}
+	if id, ok := IdentityFromContext(ctx); ok {
+		r.Subject = id.Subject
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.Peer = p.Addr.String()
+	}
+	if err != nil {
+		r.Err = err.Error()
+	}
+	if err := ServerAudit.append(r); err != nil {
+		logger.Errorw("cannot audit an operation", "resource", resource, "action", action, "error", err)
+		return err
+	}
+	return nil
+}
+
+// withPeer returns ctx as from addr, for the audit records of calls that
+// don't come in over gRPC.
+func withPeer(ctx context.Context, addr net.Addr) context.Context {
+	if _, ok := peer.FromContext(ctx); ok || addr == nil {
+		return ctx
+	}
+	return peer.NewContext(ctx, &peer.Peer{Addr: addr})
+}
+
+func (s *audioServer) ListAuditLog(ctx context.Context, req *pb.ListAuditLogRequest) (*pb.ListAuditLogResponse, error) {
+	before, size, err := seqPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	filter := AuditFilter{Action: req.Action, Subject: req.Subject, After: fromUnixNano(req.AfterNanoseconds), Before: fromUnixNano(req.BeforeNanoseconds)}
+	records, next, err := ServerAudit.page(before, size, func(r AuditRecord) bool { return r.Resource == req.Name && filter.match(r) })
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListAuditLogResponse{Records: make([]*pb.AuditRecord, len(records)), NextPageToken: seqPageToken(next)}
+	for i, r := range records {
+		resp.Records[i] = &pb.AuditRecord{
+			Seq:             r.Seq,
+			TimeNanoseconds: toUnixNano(r.Time),
+			Action:          r.Action,
+			Subject:         r.Subject,
+			Peer:            r.Peer,
+			RequestId:       r.RequestID,
+			Detail:          r.Detail,
+			Error:           r.Err,
+			Hash:            r.Hash,
+		}
+	}
+	return resp, nil
+}
+
+func (c *audioClient) ListAuditLog(ctx context.Context, pageSize int, pageToken string, filter AuditFilter) ([]AuditRecord, string, error) {
+	resp, err := c.client.ListAuditLog(ctx, &pb.ListAuditLogRequest{
+		Name:              c.name,
+		PageSize:          int32(pageSize),
+		PageToken:         pageToken,
+		Action:            filter.Action,
+		Subject:           filter.Subject,
+		AfterNanoseconds:  toUnixNano(filter.After),
+		BeforeNanoseconds: toUnixNano(filter.Before),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	records := make([]AuditRecord, len(resp.Records))
+	for i, r := range resp.Records {
+		records[i] = AuditRecord{
+			Seq:       r.Seq,
+			Time:      fromUnixNano(r.TimeNanoseconds),
+			Resource:  c.name,
+			Action:    r.Action,
+			Subject:   r.Subject,
+			Peer:      r.Peer,
+			RequestID: r.RequestId,
+			Detail:    r.Detail,
+			Err:       r.Error,
+			Hash:      r.Hash,
+		}
+	}
+	return records, resp.NextPageToken, nil
+}
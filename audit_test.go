@@ -0,0 +1,163 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("audited"), FakeConfig{Unpaced: true}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	c := serveAudio(t, f)
+
+	clip, _ := encodePCM(tone(8000, 800, 440, 0.5), Pcm16)
+	if err := c.Play(ctx, clip, "pcm16", 8000, 1); err != nil {
+		t.Fatal(err)
+	}
+	ch, err := c.GetAudio(ctx, "pcm16", 0.1, 0, 0, WithRequestID("proof"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	drain(t, ch)
+
+	// the server records the end of the stream as it returns, which can be
+	// after the client has seen it end
+	var records []AuditRecord
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if records, _, err = c.(AuditLister).ListAuditLog(ctx, 0, "", AuditFilter{}); err != nil {
+			t.Fatal(err)
+		}
+		if len(records) == 3 {
+			break
+		}
+	}
+	var actions []string
+	for _, r := range records {
+		actions = append(actions, r.Action)
+		if r.Peer == "" || r.Hash == "" || r.Time.IsZero() {
+			t.Errorf("recorded %+v", r)
+		}
+	}
+	if strings.Join(actions, " ") != "capture_ended capture_started play" || records[0].RequestID != "proof" || records[0].Seq != records[1].Seq+1 {
+		t.Fatalf("audited %+v", records)
+	}
+	page, next, err := c.(AuditLister).ListAuditLog(ctx, 1, "", AuditFilter{Action: AuditPlay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].Detail != "pcm16, 8000 Hz, 1 channels, 1600 bytes" || next == "" {
+		t.Errorf("listed %+v, next %q", page, next)
+	}
+	if err := ServerAudit.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	// nothing is captured or played when it can't be audited
+	handle, err := c.(PlaybackPreparer).PreparePlayback(ctx, clip, "pcm16", 8000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewHTTPHandler(func(string) (Audio, error) { return f, nil }, logging.NewTestLogger(t)))
+	defer srv.Close()
+	ServerAudit.mu.Lock()
+	ServerAudit.err = errors.New("disk full")
+	ServerAudit.mu.Unlock()
+	defer func() {
+		ServerAudit.mu.Lock()
+		ServerAudit.err = nil
+		ServerAudit.mu.Unlock()
+	}()
+	if err := c.Play(ctx, clip, "pcm16", 8000, 1); err == nil {
+		t.Error("played without auditing")
+	}
+	if ch, err = c.GetAudio(ctx, "pcm16", 0.1, 0, 0); err == nil {
+		if chunk := <-ch; chunk == nil || chunk.Err == nil {
+			t.Error("captured without auditing")
+		}
+	}
+	if err := c.(PlaybackPreparer).CommitPlayback(ctx, handle, time.Time{}); err == nil {
+		t.Error("committed a clip without auditing")
+	}
+	for _, path := range []string{"/audio/audited", "/hls/audited/index.m3u8"} {
+		if resp, err := http.Get(srv.URL + path); err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("GET %s without auditing: %v, %v", path, resp, err)
+		} else {
+			resp.Body.Close()
+		}
+	}
+}
+
+func TestAuditLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	l := NewAuditLog(path)
+	for _, action := range []string{AuditCaptureStarted, AuditCaptureEnded} {
+		if err := l.append(AuditRecord{Resource: "mic", Action: action, Subject: "operator"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a new log carries on the chain
+	l = NewAuditLog(path)
+	if err := l.append(AuditRecord{Resource: "mic", Action: AuditPlay}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	records, next, err := l.page(0, 2, func(r AuditRecord) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Seq != 3 || records[1].Seq != 2 || next != 2 {
+		t.Fatalf("paged %+v, next %d", records, next)
+	}
+	if records, _, _ = l.page(next, 2, func(r AuditRecord) bool { return true }); len(records) != 1 || records[0].Subject != "operator" {
+		t.Errorf("paged %+v", records)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("log file %v, %v", info, err)
+	}
+
+	// a record cut short as it was written is cut off, and the chain goes on
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"seq":4,"time":"2026-`)
+	f.Close()
+	l = NewAuditLog(path)
+	if err := l.append(AuditRecord{Resource: "mic", Action: AuditPlay}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if records, _, _ = l.page(0, 1, func(r AuditRecord) bool { return true }); len(records) != 1 || records[0].Seq != 4 {
+		t.Errorf("paged %+v", records)
+	}
+
+	// changing a record breaks the chain
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), `"subject":"operator"`, `"subject":"someone"`, 1)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewAuditLog(path).Verify(); err == nil || !strings.Contains(err.Error(), "record 1") {
+		t.Errorf("verified a changed log: %v", err)
+	}
+}
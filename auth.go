@@ -0,0 +1,292 @@
+package audio
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Identity is who made a request to the standalone server, as established
+// by its Authenticator. Handlers find it with IdentityFromContext.
+type Identity struct {
+	Subject string
+	Issuer  string // the token's issuer or the client certificate's
+	Scopes  []string
+	Method  string // "jwt" or "mtls"
+}
+
+// sameAs reports whether i and o are the same principal.
+func (i Identity) sameAs(o Identity) bool {
+	return i.Subject == o.Subject && i.Issuer == o.Issuer
+}
+
+// Authenticator establishes the identity behind a request from its
+// metadata and connection, so the standalone server can be put behind an
+// organization's own identity provider.
+type Authenticator interface {
+	ValidateToken(ctx context.Context, md metadata.MD) (Identity, error)
+}
+
+type identityKey struct{}
+
+// IdentityFromContext returns the identity a request was authenticated as,
+// if the server authenticates requests.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// AuthServerOptions has a gRPC server reject requests a doesn't accept,
+// and attach the identity of the ones it does to their contexts.
+func AuthServerOptions(a Authenticator) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			ctx, err := authenticate(ctx, a)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := authenticate(ss.Context(), a)
+			if err != nil {
+				return err
+			}
+			return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+		}),
+	}
+}
+
+func authenticate(ctx context.Context, a Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id, err := a.ValidateToken(ctx, md)
+	if err != nil {
+		return nil, fmt.Errorf("unauthenticated: %w", err)
+	}
+	return context.WithValue(ctx, identityKey{}, id), nil
+}
+
+// authenticatedStream is a server stream whose context carries its identity.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// AnyAuthenticator accepts a request any of authenticators accepts, trying
+// them in order, e.g. client certificates for robots and tokens for people.
+func AnyAuthenticator(authenticators ...Authenticator) Authenticator {
+	return anyAuthenticator(authenticators)
+}
+
+type anyAuthenticator []Authenticator
+
+func (as anyAuthenticator) ValidateToken(ctx context.Context, md metadata.MD) (Identity, error) {
+	var errs []error
+	for _, a := range as {
+		id, err := a.ValidateToken(ctx, md)
+		if err == nil {
+			return id, nil
+		}
+		errs = append(errs, err)
+	}
+	return Identity{}, errors.Join(errs...)
+}
+
+// JWTAuthenticator accepts requests with a JSON web token in a bearer
+// authorization header. Tokens must be signed with Key, must expire, and
+// must name their subject.
+type JWTAuthenticator struct {
+	// Key verifies signatures, and decides the one algorithm accepted: a
+	// []byte secret for HS256, an *rsa.PublicKey for RS256 or an
+	// *ecdsa.PublicKey on P-256 for ES256.
+	Key      any
+	Issuer   string        // the iss a token must have, any if empty
+	Audience string        // a value aud must have, any if empty
+	Leeway   time.Duration // allowed clock skew for exp and nbf
+}
+
+// jwtClaims are the registered claims the authenticator reads, and scope
+// as used by OAuth 2.0 access tokens.
+type jwtClaims struct {
+	Subject   string      `json:"sub"`
+	Issuer    string      `json:"iss"`
+	Audience  jwtAudience `json:"aud"`
+	ExpiresAt *float64    `json:"exp"`
+	NotBefore *float64    `json:"nbf"`
+	Scope     string      `json:"scope"`
+}
+
+// jwtAudience is aud, which is a string or an array of them.
+type jwtAudience []string
+
+func (a *jwtAudience) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*a = jwtAudience{one}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(a))
+}
+
+func (j *JWTAuthenticator) ValidateToken(ctx context.Context, md metadata.MD) (Identity, error) {
+	var token string
+	for _, v := range md.Get("authorization") {
+		if t, ok := strings.CutPrefix(v, "Bearer "); ok {
+			token = strings.TrimSpace(t)
+		}
+	}
+	if token == "" {
+		return Identity{}, errors.New("no bearer token")
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, errors.New("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return Identity{}, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, errors.New("malformed token signature")
+	}
+	if err := j.verify(header.Alg, parts[0]+"."+parts[1], sig); err != nil {
+		return Identity{}, err
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return Identity{}, err
+	}
+	now := time.Now()
+	switch {
+	case claims.ExpiresAt == nil:
+		return Identity{}, errors.New("token doesn't expire")
+	case now.After(numericDate(*claims.ExpiresAt).Add(j.Leeway)):
+		return Identity{}, errors.New("token expired")
+	case claims.NotBefore != nil && now.Add(j.Leeway).Before(numericDate(*claims.NotBefore)):
+		return Identity{}, errors.New("token not valid yet")
+	case j.Issuer != "" && claims.Issuer != j.Issuer:
+		return Identity{}, fmt.Errorf("token issued by %q, want %q", claims.Issuer, j.Issuer)
+	case j.Audience != "" && !slices.Contains(claims.Audience, j.Audience):
+		return Identity{}, fmt.Errorf("token not for %q", j.Audience)
+	case claims.Subject == "":
+		return Identity{}, errors.New("token has no subject")
+	}
+	return Identity{Subject: claims.Subject, Issuer: claims.Issuer, Scopes: strings.Fields(claims.Scope), Method: "jwt"}, nil
+}
+
+// verify checks sig over signed with the key, which must be for alg.
+func (j *JWTAuthenticator) verify(alg, signed string, sig []byte) error {
+	sum := sha256.Sum256([]byte(signed))
+	switch key := j.Key.(type) {
+	case []byte:
+		if alg != "HS256" {
+			break
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return errors.New("bad token signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			break
+		}
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig) != nil {
+			return errors.New("bad token signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if alg != "ES256" {
+			break
+		}
+		if len(sig) != 64 || !ecdsa.Verify(key, sum[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+			return errors.New("bad token signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported token key %T", j.Key)
+	}
+	return fmt.Errorf("token signed with %q, which the key isn't for", alg)
+}
+
+func decodeJWTPart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.New("malformed token")
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("malformed token: %w", err)
+	}
+	return nil
+}
+
+// numericDate converts seconds since the epoch, possibly fractional.
+func numericDate(s float64) time.Time {
+	return time.Unix(0, int64(s*float64(time.Second)))
+}
+
+// MTLSAuthenticator identifies clients by the certificate they connected
+// with, which the server's TLS configuration must require and verify. The
+// subject is the certificate's first URI name, such as a SPIFFE ID, or its
+// common name.
+type MTLSAuthenticator struct {
+	// TrustedIssuers, if set, are the common names of the only issuing CAs
+	// accepted, for servers whose client CA pool is shared with others.
+	TrustedIssuers []string
+}
+
+func (m *MTLSAuthenticator) ValidateToken(ctx context.Context, md metadata.MD) (Identity, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, errors.New("no peer")
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return Identity{}, errors.New("not a TLS connection")
+	}
+	return m.identify(info.State)
+}
+
+func (m *MTLSAuthenticator) identify(state tls.ConnectionState) (Identity, error) {
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return Identity{}, errors.New("no verified client certificate")
+	}
+	cert := state.VerifiedChains[0][0]
+	if len(m.TrustedIssuers) > 0 && !slices.Contains(m.TrustedIssuers, cert.Issuer.CommonName) {
+		return Identity{}, fmt.Errorf("client certificate issued by untrusted %q", cert.Issuer.CommonName)
+	}
+	id := Identity{Subject: cert.Subject.CommonName, Issuer: cert.Issuer.String(), Method: "mtls"}
+	if len(cert.URIs) > 0 {
+		id.Subject = cert.URIs[0].String()
+	}
+	if id.Subject == "" {
+		return Identity{}, errors.New("client certificate names no subject")
+	}
+	return id, nil
+}
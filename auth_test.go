@@ -0,0 +1,199 @@
+package audio
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// signJWT returns a token with claims, signed with an HMAC secret or an
+// ECDSA key.
+func signJWT(t *testing.T, key any, claims map[string]any) string {
+	t.Helper()
+	alg := "HS256"
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		alg = "ES256"
+	}
+	enc := func(v any) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]string{"alg": alg, "typ": "JWT"}) + "." + enc(claims)
+	var sig []byte
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *ecdsa.PrivateKey:
+		sum := sha256.Sum256([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func bearer(token string) metadata.MD {
+	return metadata.Pairs("authorization", "Bearer "+token)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	secret := []byte("s3cret")
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := map[string]any{"sub": "alice", "iss": "https://idp.example", "aud": []string{"audio", "video"}, "exp": exp, "scope": "audio:read audio:play"}
+	with := func(k string, v any) map[string]any {
+		c := map[string]any{}
+		for k, v := range valid {
+			c[k] = v
+		}
+		if v == nil {
+			delete(c, k)
+		} else {
+			c[k] = v
+		}
+		return c
+	}
+	hs := &JWTAuthenticator{Key: secret, Issuer: "https://idp.example", Audience: "audio"}
+
+	id, err := hs.ValidateToken(context.Background(), bearer(signJWT(t, secret, valid)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Subject != "alice" || id.Issuer != "https://idp.example" || strings.Join(id.Scopes, " ") != "audio:read audio:play" || id.Method != "jwt" {
+		t.Errorf("got %+v", id)
+	}
+	es := &JWTAuthenticator{Key: &ecKey.PublicKey}
+	if _, err := es.ValidateToken(context.Background(), bearer(signJWT(t, ecKey, valid))); err != nil {
+		t.Errorf("ES256: %v", err)
+	}
+
+	for name, md := range map[string]metadata.MD{
+		"no token":        {},
+		"wrong secret":    bearer(signJWT(t, []byte("guess"), valid)),
+		"expired":         bearer(signJWT(t, secret, with("exp", time.Now().Add(-time.Minute).Unix()))),
+		"no expiry":       bearer(signJWT(t, secret, with("exp", nil))),
+		"not yet valid":   bearer(signJWT(t, secret, with("nbf", time.Now().Add(time.Hour).Unix()))),
+		"other issuer":    bearer(signJWT(t, secret, with("iss", "https://evil.example"))),
+		"other audience":  bearer(signJWT(t, secret, with("aud", "video"))),
+		"no subject":      bearer(signJWT(t, secret, with("sub", nil))),
+		"wrong algorithm": bearer(signJWT(t, ecKey, valid)),
+		"tampered":        bearer(strings.Replace(signJWT(t, secret, valid), ".", ".e30", 1)),
+	} {
+		if _, err := hs.ValidateToken(context.Background(), md); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestMTLSAuthenticator(t *testing.T) {
+	robot, _ := url.Parse("spiffe://example.org/robot/7")
+	cert := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "robot-7"},
+		Issuer:  pkix.Name{CommonName: "fleet-ca"},
+		URIs:    []*url.URL{robot},
+	}
+	state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	id, err := (&MTLSAuthenticator{TrustedIssuers: []string{"fleet-ca"}}).identify(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Subject != robot.String() || id.Method != "mtls" {
+		t.Errorf("got %+v", id)
+	}
+	cert.URIs = nil
+	if id, _ := (&MTLSAuthenticator{}).identify(state); id.Subject != "robot-7" {
+		t.Errorf("without a URI got %+v, want the common name", id)
+	}
+	if _, err := (&MTLSAuthenticator{TrustedIssuers: []string{"other-ca"}}).identify(state); err == nil {
+		t.Error("accepted an untrusted issuer")
+	}
+	if _, err := (&MTLSAuthenticator{}).identify(tls.ConnectionState{}); err == nil {
+		t.Error("accepted a connection without a verified certificate")
+	}
+}
+
+func TestAuthenticatedServer(t *testing.T) {
+	secret := []byte("s3cret")
+	token := func(sub string) context.Context {
+		tok := signJWT(t, secret, map[string]any{"sub": sub, "exp": time.Now().Add(time.Hour).Unix()})
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+tok)
+	}
+	src := newBurstSource(1, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	coll, err := resource.NewAPIResourceCollection[Audio](API, map[resource.Name]Audio{src.Name(): src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer(AuthServerOptions(&JWTAuthenticator{Key: secret})...)
+	pb.RegisterAudioServiceServer(gs, NewRPCServiceServer(coll).(pb.AudioServiceServer))
+	go gs.Serve(lis)
+	defer gs.Stop()
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := NewClientFromConn(&rpc.GrpcOverHTTPClientConn{ClientConn: conn}, "", src.Name(), logging.NewTestLogger(t))
+
+	if err := c.Play(context.Background(), make([]byte, 16), Pcm16.String(), 8000, 1); err == nil {
+		t.Error("served a request without a token")
+	}
+
+	ctx, cancel := context.WithCancel(token("alice"))
+	defer cancel()
+	if _, err := c.GetAudio(ctx, Pcm16.String(), 0, 0, 0, WithRequestID("r1")); err != nil {
+		t.Fatal(err)
+	}
+	// only alice can pause the stream alice started, once it has started
+	sc := c.(StreamController)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := sc.PauseStream(token("bob"), "r1")
+		if err == nil {
+			t.Fatal("bob paused alice's stream")
+		}
+		if strings.Contains(err.Error(), "someone else") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stream never started: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := sc.PauseStream(token("alice"), "r1"); err != nil {
+		t.Errorf("alice couldn't pause the stream: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package audio
+
+import (
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// The batches WithBatching accepts, and how long a chunk waits for its
+// batch to fill by default.
+const (
+	maxBatchChunks    = 100
+	defaultBatchDelay = time.Second
+)
+
+func checkBatch(chunks int, delay time.Duration) error {
+	if chunks < 0 || chunks > maxBatchChunks {
+		return errorf(ErrInvalidArgument, "batch must be of up to %d chunks, got %d", maxBatchChunks, chunks)
+	}
+	if delay < 0 {
+		return errorf(ErrInvalidArgument, "batch delay must not be negative, got %v", delay)
+	}
+	return nil
+}
+
+// chunkBatch collects the messages of a GetAudio stream until there are
+// enough to send in one. A batch of one is sent as the chunk's own message,
+// so streams that don't batch look as they always have. The messages are
+// reused from one batch to the next, as Send has marshalled them by the
+// time it returns.
+type chunkBatch struct {
+	size  int
+	delay time.Duration
+
+	msgs   []*pb.AudioChunk
+	chunks []*AudioChunk // the chunks of the batch, released once it's sent
+	out    pb.AudioChunk
+	timer  *time.Timer
+}
+
+func newChunkBatch(size int, delay time.Duration) *chunkBatch {
+	if delay == 0 {
+		delay = defaultBatchDelay
+	}
+	return &chunkBatch{size: max(size, 1), delay: delay}
+}
+
+// next returns the message to fill in for chunk, and whether the batch is
+// full with it.
+func (b *chunkBatch) next(chunk *AudioChunk) (*pb.AudioChunk, bool) {
+	n := len(b.chunks)
+	if n == len(b.msgs) {
+		b.msgs = append(b.msgs, &pb.AudioChunk{})
+	}
+	if n == 0 && b.size > 1 {
+		b.timer = time.NewTimer(b.delay)
+	}
+	b.chunks = append(b.chunks, chunk)
+	return b.msgs[n], n+1 == b.size
+}
+
+// due fires once the first chunk of the batch has waited as long as it may.
+func (b *chunkBatch) due() <-chan time.Time {
+	if b.timer == nil {
+		return nil
+	}
+	return b.timer.C
+}
+
+// message returns what sends the batch, nil when it's empty.
+func (b *chunkBatch) message() *pb.AudioChunk {
+	switch len(b.chunks) {
+	case 0:
+		return nil
+	case 1:
+		return b.msgs[0]
+	}
+	b.out = pb.AudioChunk{Batch: b.msgs[:len(b.chunks)]}
+	return &b.out
+}
+
+// sent returns the messages of the batch, one per chunk.
+func (b *chunkBatch) sent() []*pb.AudioChunk {
+	return b.msgs[:len(b.chunks)]
+}
+
+// reset releases the chunks of the batch and starts the next.
+func (b *chunkBatch) reset() {
+	for i, chunk := range b.chunks {
+		chunk.Release()
+		b.chunks[i] = nil
+	}
+	b.chunks = b.chunks[:0]
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
+
+// unbatch returns the chunks msg carries, itself unless it's a batch.
+func unbatch(msg *pb.AudioChunk) []*pb.AudioChunk {
+	if len(msg.Batch) > 0 {
+		return msg.Batch
+	}
+	return []*pb.AudioChunk{msg}
+}
@@ -0,0 +1,88 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+func TestChunkBatch(t *testing.T) {
+	b := newChunkBatch(3, 20*time.Millisecond)
+	if b.message() != nil || b.due() != nil {
+		t.Fatal("an empty batch is ready to send")
+	}
+	at := time.Unix(100, 0)
+	for i := range 3 {
+		msg, full := b.next(&AudioChunk{})
+		*msg = pb.AudioChunk{StartTimestampNanoseconds: at.Add(time.Duration(i) * 10 * time.Millisecond).UnixNano()}
+		if full != (i == 2) {
+			t.Errorf("batch full after %d chunks: %v", i+1, full)
+		}
+	}
+	got := unbatch(b.message())
+	if len(got) != 3 {
+		t.Fatalf("batch carries %d chunks, want 3", len(got))
+	}
+	for i, msg := range got {
+		if want := at.Add(time.Duration(i) * 10 * time.Millisecond).UnixNano(); msg.StartTimestampNanoseconds != want {
+			t.Errorf("chunk %d starts at %d, want %d", i, msg.StartTimestampNanoseconds, want)
+		}
+	}
+	b.reset()
+
+	// a batch that doesn't fill goes out once its first chunk has waited
+	b.next(&AudioChunk{})
+	select {
+	case <-b.due():
+	case <-time.After(time.Second):
+		t.Fatal("a partial batch never came due")
+	}
+	if msg := b.message(); len(msg.Batch) != 0 {
+		t.Error("a batch of one chunk wasn't sent as the chunk")
+	}
+	b.reset()
+	if b.due() != nil {
+		t.Error("a sent batch is still due")
+	}
+}
+
+func TestStreamBatching(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 { return tone(8000, 80, 300+float64(i)*20, 0.5) }
+	c := serveAudio(t, src)
+	close(src.start)
+
+	// 50 chunks in batches of 8 still come out one by one, in order
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithBatching(8, 50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][]byte
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		got = append(got, chunk.AudioData)
+	}
+	if len(got) != src.n {
+		t.Fatalf("got %d chunks, want %d", len(got), src.n)
+	}
+	for i, data := range got {
+		if want, _ := encodePCM(src.samples(i), Pcm16); !bytes.Equal(data, want) {
+			t.Errorf("chunk %d has different audio", i)
+		}
+	}
+
+	ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0, WithBatching(maxBatchChunks+1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || chunk.Err == nil {
+		t.Error("streamed batches over the limit")
+	}
+}
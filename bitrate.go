@@ -0,0 +1,186 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Defaults and pacing of adaptive bitrate, see WithAdaptiveBitrate. The
+// bitrate steps down while the stream's queue holds bitrateQueueHigh chunks
+// or more, at most once per bitrateStepDown so the queue can drain, and
+// back up once it has held no more than bitrateQueueLow for bitrateStepUp.
+const (
+	defaultMinBitrate = 32
+	defaultMaxBitrate = 128
+	bitrateQueueHigh  = 4
+	bitrateQueueLow   = 1
+	bitrateStepDown   = 500 * time.Millisecond
+	bitrateStepUp     = 5 * time.Second
+)
+
+// bitrateControl picks the bitrate of an adaptive stream from the depth of
+// its queue.
+type bitrateControl struct {
+	min, max int // kbps
+	ladder   []int
+	rung     int
+	lastDown time.Time
+	short    time.Time // since when the queue has been short, zero while it isn't
+}
+
+func newBitrateControl(minKbps, maxKbps int) (*bitrateControl, error) {
+	if minKbps == 0 {
+		minKbps = defaultMinBitrate
+	}
+	if maxKbps == 0 {
+		maxKbps = defaultMaxBitrate
+	}
+	if minKbps < 0 || minKbps > maxKbps {
+		return nil, errorf(ErrInvalidArgument, "bitrate range %d to %d kbps is empty", minKbps, maxKbps)
+	}
+	return &bitrateControl{min: minKbps, max: maxKbps}, nil
+}
+
+// use sets the bitrates the encoder offers, starting from the highest in
+// range. It fails when none of them are.
+func (c *bitrateControl) use(offered []int) error {
+	c.ladder = slices.DeleteFunc(slices.Clone(offered), func(kbps int) bool { return kbps < c.min || kbps > c.max })
+	if len(c.ladder) == 0 {
+		return fmt.Errorf("no mp3 bitrate between %d and %d kbps at this sample rate, expected one of %v", c.min, c.max, offered)
+	}
+	c.rung = len(c.ladder) - 1
+	return nil
+}
+
+// update takes the queue's depth at now and returns the bitrate to encode at.
+func (c *bitrateControl) update(depth int, now time.Time) int {
+	switch {
+	case depth >= bitrateQueueHigh:
+		c.short = time.Time{}
+		if c.rung > 0 && now.Sub(c.lastDown) >= bitrateStepDown {
+			c.rung--
+			c.lastDown = now
+		}
+	case depth <= bitrateQueueLow:
+		if c.short.IsZero() {
+			c.short = now
+		} else if c.rung < len(c.ladder)-1 && now.Sub(c.short) >= bitrateStepUp {
+			c.rung++
+			c.short = now
+		}
+	default:
+		c.short = time.Time{}
+	}
+	return c.ladder[c.rung]
+}
+
+// openAdaptiveBitrate encodes the shared pcm capture to MP3 for req, at a
+// bitrate that follows how far the client has fallen behind. Only MP3 is
+// encoded on the server; resources capturing opus pick its bitrate
+// themselves.
+func (s *audioServer) openAdaptiveBitrate(ctx context.Context, a Audio, req *pb.GetAudioRequest, queue queuePolicy) (<-chan *AudioChunk, error) {
+	if req.Codec != Mp3.String() {
+		return nil, errorf(ErrUnsupportedCodec, "adaptive bitrate is only encoded for mp3, got codec %q", req.Codec)
+	}
+	ctl, err := newBitrateControl(int(req.MinBitrateKbps), int(req.MaxBitrateKbps))
+	if err != nil {
+		return nil, err
+	}
+	// the bitrates depend on the rate, so check them before capturing
+	if req.SampleRate != 0 {
+		if err := ctl.use(newMP3Encoder(int(req.SampleRate), 2).bitrates()); err != nil {
+			return nil, err
+		}
+	}
+	pcm := proto.CloneOf(req)
+	pcm.Codec, pcm.AdaptiveBitrate = Pcm16.String(), false
+	queue.depth = &atomic.Int64{}
+	src, err := s.openQueued(ctx, a, pcm, queue)
+	if err != nil {
+		return nil, err
+	}
+	return encodeAdaptiveMP3(ctx, src, queue.depth, ctl), nil
+}
+
+// encodeAdaptiveMP3 encodes the pcm chunks of src to MP3, setting the
+// bitrate from depth before each chunk. Chunks only go out once they
+// complete a frame; the ones that don't pass their gap on.
+func encodeAdaptiveMP3(ctx context.Context, src <-chan *AudioChunk, depth *atomic.Int64, ctl *bitrateControl) <-chan *AudioChunk {
+	out := make(chan *AudioChunk)
+	go func() {
+		defer close(out)
+		var (
+			enc *mp3Encoder
+			in  AudioInfo // format enc was made for
+			gap time.Duration
+		)
+		send := func(chunk *AudioChunk) bool {
+			select {
+			case out <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for chunk := range src {
+			if chunk.Err != nil {
+				send(chunk)
+				return
+			}
+			if chunk.Info == nil {
+				send(&AudioChunk{Err: errUnknownSourceFormat})
+				return
+			}
+			gap += chunk.Gap
+			if enc == nil || *chunk.Info != in {
+				in = *chunk.Info
+				enc = newMP3Encoder(in.SampleRate, in.Channels)
+				if err := ctl.use(enc.bitrates()); err != nil {
+					send(&AudioChunk{Err: err})
+					return
+				}
+			}
+			kbps := ctl.update(int(depth.Load()), time.Now())
+			if err := enc.setBitrate(kbps); err != nil {
+				send(&AudioChunk{Err: err})
+				return
+			}
+			samples, err := decodePCM(chunk.AudioData, in.Format)
+			chunk.Release()
+			if err != nil {
+				send(&AudioChunk{Err: err})
+				return
+			}
+			held := len(enc.pending) / mp3Channels
+			data, frames := enc.encode(samples)
+			if frames == 0 {
+				continue
+			}
+			encoded := &AudioChunk{
+				Sequence:    chunk.Sequence,
+				AudioData:   data,
+				Info:        &AudioInfo{Format: Mp3, SampleRate: enc.sampleRate, Channels: mp3Channels},
+				Gap:         gap,
+				Speech:      chunk.Speech,
+				Timecode:    chunk.Timecode,
+				BitrateKbps: kbps,
+			}
+			// the frames start with the samples held from earlier chunks
+			if !chunk.Timestamp.IsZero() {
+				encoded.Timestamp = chunk.Timestamp.Add(-framesDuration(int64(held), enc.sampleRate))
+			}
+			gap = 0
+			if !send(encoded) {
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,120 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBitrateControl(t *testing.T) {
+	ctl, err := newBitrateControl(64, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.use(mp3BitratesMPEG1); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Unix(100, 0)
+	steps := []struct {
+		after time.Duration
+		depth int
+		want  int
+	}{
+		{0, 0, 128},
+		// a long queue steps down at once, then once per half second
+		{0, 10, 112},
+		{100 * time.Millisecond, 10, 112},
+		{600 * time.Millisecond, 10, 96},
+		{1200 * time.Millisecond, 10, 80},
+		{1800 * time.Millisecond, 10, 64},
+		{2400 * time.Millisecond, 10, 64},
+		// and back up once it has stayed short for five seconds
+		{2500 * time.Millisecond, 0, 64},
+		{7 * time.Second, 1, 64},
+		{7500 * time.Millisecond, 0, 80},
+		{8 * time.Second, 2, 80},
+		{12 * time.Second, 0, 80},
+	}
+	for _, s := range steps {
+		if got := ctl.update(s.depth, at.Add(s.after)); got != s.want {
+			t.Errorf("%v in with %d queued: %d kbps, want %d", s.after, s.depth, got, s.want)
+		}
+	}
+
+	if _, err := newBitrateControl(128, 64); err == nil {
+		t.Error("made a control for an empty range")
+	}
+	ctl, _ = newBitrateControl(170, 190)
+	if err := ctl.use(mp3BitratesMPEG2); err == nil {
+		t.Error("used a ladder without a bitrate in range")
+	}
+}
+
+func TestMP3SetBitrate(t *testing.T) {
+	samples := tone(44100, 44100, 440, 0.5)
+	size := map[int]int{}
+	for _, kbps := range []int{64, 128} {
+		enc := newMP3Encoder(44100, 1)
+		if err := enc.setBitrate(kbps); err != nil {
+			t.Fatal(err)
+		}
+		data, frames := enc.encode(samples)
+		if frames == 0 || data[0] != 0xff {
+			t.Fatalf("%d kbps encoded %d frames", kbps, frames)
+		}
+		// the header's bitrate index
+		if index := int(data[2] >> 4); mp3BitratesMPEG1[index-1] != kbps {
+			t.Errorf("%d kbps frames are marked %d kbps", kbps, mp3BitratesMPEG1[index-1])
+		}
+		size[kbps] = len(data)
+	}
+	if ratio := float64(size[64]) / float64(size[128]); ratio < 0.45 || ratio > 0.55 {
+		t.Errorf("64 kbps is %.2f the size of 128 kbps", ratio)
+	}
+	if err := newMP3Encoder(8000, 1).setBitrate(96); err == nil {
+		t.Error("set a bitrate 8 kHz can't carry")
+	}
+}
+
+func TestAdaptiveBitrateStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(100, AudioInfo{Format: Pcm16, SampleRate: 44100, Channels: 1})
+	src.samples = func(i int) []float32 { return tone(44100, 441, 440, 0.5) }
+	c := serveAudio(t, src)
+
+	ch, err := c.GetAudio(ctx, "mp3", 0, 0, 0, WithAdaptiveBitrate(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the whole burst queues up behind a client reading slowly
+	time.Sleep(200 * time.Millisecond)
+	close(src.start)
+	lowest := defaultMaxBitrate
+	n := 0
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		if n == 0 && (chunk.Header == nil || chunk.Header.Codec != "mp3") {
+			t.Errorf("first chunk has header %+v", chunk.Header)
+		}
+		if chunk.BitrateKbps < defaultMinBitrate || chunk.BitrateKbps > defaultMaxBitrate {
+			t.Errorf("chunk %d at %d kbps", n, chunk.BitrateKbps)
+		}
+		lowest = min(lowest, chunk.BitrateKbps)
+		n++
+		time.Sleep(30 * time.Millisecond)
+	}
+	if n == 0 || lowest == defaultMaxBitrate {
+		t.Errorf("%d chunks never went below %d kbps", n, lowest)
+	}
+
+	ch, err = c.GetAudio(ctx, "opus", 0, 0, 0, WithAdaptiveBitrate(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || chunk.Err == nil {
+		t.Error("adapted the bitrate of opus")
+	}
+}
@@ -0,0 +1,29 @@
+package audio
+
+import (
+	"hash/crc32"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// setChecksum sets the CRC-32 of msg's audio, for streams requested with
+// checksums.
+func setChecksum(msg *pb.AudioChunk) {
+	sum := crc32.ChecksumIEEE(msg.AudioData)
+	msg.Crc32 = &sum
+}
+
+// verifyChecksum checks msg's audio against the CRC-32 the server sent with
+// it. Heartbeats carry no audio and aren't checked.
+func verifyChecksum(msg *pb.AudioChunk) error {
+	if msg.Heartbeat {
+		return nil
+	}
+	if msg.Crc32 == nil {
+		return errorf(ErrUnsupported, "the server sent chunk %d without a checksum", msg.Sequence)
+	}
+	if sum := crc32.ChecksumIEEE(msg.AudioData); sum != *msg.Crc32 {
+		return errorf(ErrChecksumMismatch, "chunk %d has CRC-32 %08x, the server sent %08x", msg.Sequence, sum, *msg.Crc32)
+	}
+	return nil
+}
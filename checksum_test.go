@@ -0,0 +1,74 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// corruptingStream flips a bit of the fourth chunk it receives while
+// corrupt is set, as a buggy proxy might.
+type corruptingStream struct {
+	grpc.ClientStream
+	corrupt *atomic.Bool
+}
+
+func (s corruptingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if msg, ok := m.(*pb.AudioChunk); ok && err == nil && s.corrupt.Load() && msg.Sequence == 3 && len(msg.AudioData) > 0 {
+		msg.AudioData[0] ^= 1
+	}
+	return err
+}
+
+func TestChecksums(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(10, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	close(src.start)
+	var corrupt atomic.Bool
+	c := serveAudio(t, src, WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		return corruptingStream{ClientStream: s, corrupt: &corrupt}, err
+	}))
+
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithChecksums())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunks, _, _ := drain(t, ch); chunks != src.n {
+		t.Errorf("got %d chunks, want %d", chunks, src.n)
+	}
+
+	// the corrupted chunk ends the stream instead of being delivered
+	corrupt.Store(true)
+	ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0, WithChecksums())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks int
+	var last *AudioChunk
+	for chunk := range ch {
+		if chunk.Err == nil {
+			chunks++
+		}
+		last = chunk
+	}
+	if chunks != 3 || last == nil || !errors.Is(last.Err, ErrChecksumMismatch) {
+		t.Errorf("got %d chunks ending with %+v, want 3 and a checksum mismatch", chunks, last)
+	}
+
+	// a server that doesn't send checksums can't be verified
+	if err := verifyChecksum(&pb.AudioChunk{AudioData: []byte{1, 2}}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("a chunk without a checksum failed with %v", err)
+	}
+	if err := verifyChecksum(&pb.AudioChunk{Heartbeat: true}); err != nil {
+		t.Errorf("a heartbeat failed with %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package audio
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ClientOptions tunes a client's calls on their own, rather than leaving
+// them to whatever the rpc.ClientConn shared with the rest of the robot was
+// dialed with. Clients collect them with NewClientOptions.
+type ClientOptions struct {
+	// PlayTimeout bounds Play calls whose context has no deadline, retries
+	// included. Zero leaves them unbounded.
+	PlayTimeout time.Duration
+	// CallTimeout does the same for the other unary calls.
+	CallTimeout time.Duration
+	// StreamTimeout bounds how long a stream such as GetAudio takes to be
+	// set up on the server. Once it is, it runs for as long as its context
+	// allows, however long the server holds audio back.
+	StreamTimeout time.Duration
+	Retry         RetryPolicy
+	// MaxMessageSize caps the bytes of one message in either direction,
+	// gRPC's defaults if zero.
+	MaxMessageSize int
+	// Interceptors wrap every attempt at a call, the first outermost. They
+	// are given a nil *grpc.ClientConn, as the client may not be on one.
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+	// StreamLimiter, when shared between clients, caps the streams they
+	// have open at once. Waiting for a slot isn't part of StreamTimeout.
+	StreamLimiter *StreamLimiter
+}
+
+// RetryPolicy retries unary calls that fail with one of Codes, backing off
+// exponentially. Streams aren't retried, as one that failed part way can't
+// in general be picked up where it stopped; GetAudio streams can be
+// re-opened by a policy of their own, see WithReconnect.
+type RetryPolicy struct {
+	MaxAttempts    int           // the first call included, no retries if below 2
+	InitialBackoff time.Duration // before the first retry, 100ms if zero
+	MaxBackoff     time.Duration // 5s if zero
+	Codes          []codes.Code  // Unavailable if empty
+}
+
+const (
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+)
+
+// ClientOption configures a client.
+type ClientOption func(*ClientOptions)
+
+// NewClientOptions applies opts to a zero ClientOptions.
+func NewClientOptions(opts ...ClientOption) ClientOptions {
+	var o ClientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithPlayTimeout bounds Play calls made without a deadline.
+func WithPlayTimeout(d time.Duration) ClientOption {
+	return func(o *ClientOptions) {
+		o.PlayTimeout = d
+	}
+}
+
+// WithCallTimeout bounds the other unary calls made without a deadline.
+func WithCallTimeout(d time.Duration) ClientOption {
+	return func(o *ClientOptions) {
+		o.CallTimeout = d
+	}
+}
+
+// WithStreamTimeout bounds how long a stream takes to be set up.
+func WithStreamTimeout(d time.Duration) ClientOption {
+	return func(o *ClientOptions) {
+		o.StreamTimeout = d
+	}
+}
+
+// WithRetry retries failed unary calls by p.
+func WithRetry(p RetryPolicy) ClientOption {
+	return func(o *ClientOptions) {
+		o.Retry = p
+	}
+}
+
+// WithMaxMessageSize caps messages in both directions at n bytes.
+func WithMaxMessageSize(n int) ClientOption {
+	return func(o *ClientOptions) {
+		o.MaxMessageSize = n
+	}
+}
+
+// WithUnaryInterceptor adds interceptors to unary calls.
+func WithUnaryInterceptor(i ...grpc.UnaryClientInterceptor) ClientOption {
+	return func(o *ClientOptions) {
+		o.UnaryInterceptors = append(o.UnaryInterceptors, i...)
+	}
+}
+
+// WithStreamInterceptor adds interceptors to streams.
+func WithStreamInterceptor(i ...grpc.StreamClientInterceptor) ClientOption {
+	return func(o *ClientOptions) {
+		o.StreamInterceptors = append(o.StreamInterceptors, i...)
+	}
+}
+
+// WithStreamLimiter has the client's streams wait for a slot on l.
+func WithStreamLimiter(l *StreamLimiter) ClientOption {
+	return func(o *ClientOptions) {
+		o.StreamLimiter = l
+	}
+}
+
+// playMethod is the full name of Play, which has its own timeout.
+const playMethod = "/AudioService/Play"
+
+// optionsConn applies ClientOptions to every call made on a connection.
+type optionsConn struct {
+	conn     grpc.ClientConnInterface
+	o        ClientOptions
+	owner    string // the client, to the stream limiter
+	callOpts []grpc.CallOption
+}
+
+// withClientOptions returns conn itself when o changes nothing.
+func withClientOptions(conn grpc.ClientConnInterface, o ClientOptions, owner string) grpc.ClientConnInterface {
+	c := &optionsConn{conn: conn, o: o, owner: owner}
+	if o.MaxMessageSize > 0 {
+		c.callOpts = []grpc.CallOption{grpc.MaxCallRecvMsgSize(o.MaxMessageSize), grpc.MaxCallSendMsgSize(o.MaxMessageSize)}
+	}
+	if o.PlayTimeout == 0 && o.CallTimeout == 0 && o.StreamTimeout == 0 && o.Retry.MaxAttempts < 2 &&
+		len(c.callOpts) == 0 && len(o.UnaryInterceptors) == 0 && len(o.StreamInterceptors) == 0 && o.StreamLimiter == nil {
+		return conn
+	}
+	return c
+}
+
+func (c *optionsConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	timeout := c.o.CallTimeout
+	if method == playMethod {
+		timeout = c.o.PlayTimeout
+	}
+	if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	opts = append(slices.Clip(c.callOpts), opts...)
+	p := c.o.Retry
+	backoff := p.InitialBackoff
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := c.invoke(ctx, 0, method, args, reply, opts)
+		if err == nil || attempt >= p.MaxAttempts || !p.retries(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+// invoke runs the unary interceptors from the i'th on, then the call.
+func (c *optionsConn) invoke(ctx context.Context, i int, method string, args, reply any, opts []grpc.CallOption) error {
+	if i == len(c.o.UnaryInterceptors) {
+		return c.conn.Invoke(ctx, method, args, reply, opts...)
+	}
+	next := func(ctx context.Context, method string, args, reply any, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return c.invoke(ctx, i+1, method, args, reply, opts)
+	}
+	return c.o.UnaryInterceptors[i](ctx, method, args, reply, nil, next, opts...)
+}
+
+func (p RetryPolicy) retries(err error) bool {
+	code := status.Code(err)
+	if len(p.Codes) == 0 {
+		return code == codes.Unavailable
+	}
+	return slices.Contains(p.Codes, code)
+}
+
+func (c *optionsConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	opts = append(slices.Clip(c.callOpts), opts...)
+	if c.o.StreamLimiter == nil {
+		return c.setUpStream(ctx, desc, method, opts)
+	}
+	release, err := c.o.StreamLimiter.acquire(ctx, c.owner)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := c.setUpStream(ctx, desc, method, opts)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// the stream's context ends with it, however it ends
+	context.AfterFunc(cs.Context(), release)
+	return cs, nil
+}
+
+// setUpStream opens a stream within the stream timeout.
+func (c *optionsConn) setUpStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts []grpc.CallOption) (grpc.ClientStream, error) {
+	if c.o.StreamTimeout <= 0 {
+		return c.newStream(ctx, 0, desc, method, opts)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &setupStream{method: method, timeout: c.o.StreamTimeout}
+	s.timer = time.AfterFunc(c.o.StreamTimeout, func() {
+		s.mu.Lock()
+		s.timedOut = true
+		s.mu.Unlock()
+		cancel()
+	})
+	cs, err := c.newStream(ctx, 0, desc, method, opts)
+	if err != nil {
+		s.timer.Stop()
+		cancel()
+		return nil, s.err(err)
+	}
+	s.ClientStream = cs
+	// the server sends its headers once the stream is set up
+	go func() {
+		if _, err := cs.Header(); err == nil {
+			s.timer.Stop()
+		}
+	}()
+	context.AfterFunc(cs.Context(), cancel)
+	return s, nil
+}
+
+// newStream runs the stream interceptors from the i'th on, then opens the
+// stream.
+func (c *optionsConn) newStream(ctx context.Context, i int, desc *grpc.StreamDesc, method string, opts []grpc.CallOption) (grpc.ClientStream, error) {
+	if i == len(c.o.StreamInterceptors) {
+		return c.conn.NewStream(ctx, desc, method, opts...)
+	}
+	next := func(ctx context.Context, desc *grpc.StreamDesc, _ *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return c.newStream(ctx, i+1, desc, method, opts)
+	}
+	return c.o.StreamInterceptors[i](ctx, desc, nil, method, next, opts...)
+}
+
+// setupStream fails a stream the server didn't set up within the timeout
+// with DeadlineExceeded rather than the cancellation that stopped it.
+type setupStream struct {
+	grpc.ClientStream
+	method  string
+	timeout time.Duration
+	timer   *time.Timer
+
+	mu       sync.Mutex
+	timedOut bool
+}
+
+func (s *setupStream) err(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil || !s.timedOut {
+		return err
+	}
+	return status.Errorf(codes.DeadlineExceeded, "%s wasn't set up within %v", s.method, s.timeout)
+}
+
+func (s *setupStream) RecvMsg(m any) error {
+	return s.err(s.ClientStream.RecvMsg(m))
+}
+
+func (s *setupStream) SendMsg(m any) error {
+	return s.err(s.ClientStream.SendMsg(m))
+}
@@ -0,0 +1,179 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// recordingConn fails the first failures unary calls with Unavailable and
+// records the deadline and options of every call.
+type recordingConn struct {
+	mu        sync.Mutex
+	failures  int
+	calls     int
+	deadlines []time.Duration // from the call, 0 for none
+	opts      [][]grpc.CallOption
+}
+
+func (c *recordingConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	var left time.Duration
+	if d, ok := ctx.Deadline(); ok {
+		left = time.Until(d)
+	}
+	c.deadlines = append(c.deadlines, left)
+	c.opts = append(c.opts, opts)
+	if c.calls <= c.failures {
+		return status.Error(codes.Unavailable, "connection reset")
+	}
+	return nil
+}
+
+func (c *recordingConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("no streams")
+}
+
+func TestClientOptionsUnary(t *testing.T) {
+	conn := &recordingConn{failures: 2}
+	var order []string
+	interceptor := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			order = append(order, name)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+	client := pb.NewAudioServiceClient(withClientOptions(conn, NewClientOptions(
+		WithPlayTimeout(time.Minute),
+		WithCallTimeout(time.Second),
+		WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		WithMaxMessageSize(8<<20),
+		WithUnaryInterceptor(interceptor("outer"), interceptor("inner")),
+	), "mic"))
+	ctx := context.Background()
+	if _, err := client.Play(ctx, &pb.PlayRequest{}); err != nil {
+		t.Fatalf("retried Play failed: %v", err)
+	}
+	if conn.calls != 3 || len(order) != 6 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("made %d calls through %v", conn.calls, order)
+	}
+	if d := conn.deadlines[0]; d < 50*time.Second || d > time.Minute {
+		t.Errorf("Play had %v left, want the play timeout", d)
+	}
+	found := false
+	for _, o := range conn.opts[0] {
+		if m, ok := o.(grpc.MaxRecvMsgSizeCallOption); ok && m.MaxRecvMsgSize == 8<<20 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("called with %v, want the max message size", conn.opts[0])
+	}
+
+	if _, err := client.GetEQ(ctx, &pb.GetEQRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if d := conn.deadlines[3]; d <= 0 || d > time.Second {
+		t.Errorf("GetEQ had %v left, want the call timeout", d)
+	}
+	// a caller's own deadline is kept
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+	if _, err := client.Play(ctx, &pb.PlayRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if d := conn.deadlines[4]; d < 59*time.Minute {
+		t.Errorf("Play had %v left, want the caller's hour", d)
+	}
+
+	// only the configured codes are retried
+	conn = &recordingConn{failures: 5}
+	client = pb.NewAudioServiceClient(withClientOptions(conn, NewClientOptions(
+		WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Codes: []codes.Code{codes.ResourceExhausted}}),
+	), "mic"))
+	if _, err := client.Play(context.Background(), &pb.PlayRequest{}); status.Code(err) != codes.Unavailable || conn.calls != 1 {
+		t.Errorf("made %d calls, ending with %v", conn.calls, err)
+	}
+
+	if c := withClientOptions(conn, ClientOptions{}, "mic"); c != grpc.ClientConnInterface(conn) {
+		t.Error("wrapped a connection without options")
+	}
+}
+
+// stalledServer never sets up a GetAudio stream.
+type stalledServer struct {
+	pb.UnimplementedAudioServiceServer
+}
+
+func (stalledServer) GetAudio(req *pb.GetAudioRequest, stream pb.AudioService_GetAudioServer) error {
+	<-stream.Context().Done()
+	return nil
+}
+
+// serveWithOptions serves srv and returns a client for name made with opts.
+func serveWithOptions(t *testing.T, srv pb.AudioServiceServer, name resource.Name, opts ...ClientOption) Audio {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer()
+	pb.RegisterAudioServiceServer(gs, srv)
+	go gs.Serve(lis)
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		gs.Stop()
+	})
+	return NewClientFromConn(&rpc.GrpcOverHTTPClientConn{ClientConn: conn}, "", name, logging.NewTestLogger(t), opts...)
+}
+
+func TestClientStreamTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := serveWithOptions(t, stalledServer{}, Named("mic"), WithStreamTimeout(50*time.Millisecond))
+	chunks, err := c.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := <-chunks
+	if chunk == nil || status.Code(chunk.Err) != codes.DeadlineExceeded {
+		t.Fatalf("got %+v, want a deadline exceeded", chunk)
+	}
+
+	// a stream that is set up but holds audio back runs past the timeout
+	src := newBurstSource(3, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	coll, err := resource.NewAPIResourceCollection[Audio](API, map[resource.Name]Audio{src.Name(): src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = serveWithOptions(t, NewRPCServiceServer(coll).(pb.AudioServiceServer), src.Name(), WithStreamTimeout(50*time.Millisecond))
+	chunks, err = c.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	close(src.start)
+	if n, _, _ := drain(t, chunks); n != 3 {
+		t.Errorf("got %d chunks, want 3", n)
+	}
+}
@@ -0,0 +1,248 @@
+package audio
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/status"
+)
+
+// Dialer connects to the robot at address.
+type Dialer func(ctx context.Context, address string) (rpc.ClientConn, error)
+
+// ClientPool creates clients for audio resources that share one connection
+// per address, so a process talking to many resources on the same robots
+// holds one socket to each. A connection is dialed for the first client on
+// it and closed with the last. Clients a robot creates already share its
+// connection and need no pool; they can still share a StreamLimiter.
+type ClientPool struct {
+	dial       Dialer
+	maxStreams int
+	opts       []ClientOption
+
+	mu    sync.Mutex
+	conns map[string]*pooledConn
+}
+
+// pooledConn is a connection and the clients on it.
+type pooledConn struct {
+	ready   chan struct{} // closed once dialed
+	conn    rpc.ClientConn
+	err     error
+	limiter *StreamLimiter
+	clients int
+	closed  bool // by the pool's Close
+}
+
+// NewClientPool returns a pool that dials with dial and makes its clients
+// with opts. maxStreams caps the streams open at once on each connection,
+// shared fairly between its clients; zero leaves them uncapped.
+func NewClientPool(dial Dialer, maxStreams int, opts ...ClientOption) *ClientPool {
+	return &ClientPool{dial: dial, maxStreams: maxStreams, opts: opts, conns: map[string]*pooledConn{}}
+}
+
+// Client returns a client for the resource name on the robot at address,
+// dialing it unless another client is already connected. Closing the client
+// gives up its share of the connection.
+func (p *ClientPool) Client(ctx context.Context, address string, name resource.Name, logger logging.Logger) (Audio, error) {
+	p.mu.Lock()
+	pc, ok := p.conns[address]
+	if !ok {
+		pc = &pooledConn{ready: make(chan struct{})}
+		if p.maxStreams > 0 {
+			pc.limiter = NewStreamLimiter(p.maxStreams)
+		}
+		p.conns[address] = pc
+	}
+	pc.clients++
+	p.mu.Unlock()
+
+	if !ok {
+		pc.conn, pc.err = p.dial(ctx, address)
+		if pc.err != nil {
+			// the next client dials again
+			p.mu.Lock()
+			if p.conns[address] == pc {
+				delete(p.conns, address)
+			}
+			p.mu.Unlock()
+		}
+		close(pc.ready)
+	}
+	select {
+	case <-pc.ready:
+	case <-ctx.Done():
+		p.release(address, pc)
+		return nil, ctx.Err()
+	}
+	if pc.err != nil {
+		p.release(address, pc)
+		return nil, pc.err
+	}
+	opts := p.opts
+	if pc.limiter != nil {
+		opts = append(slices.Clip(opts), WithStreamLimiter(pc.limiter))
+	}
+	sc := newSvcClientFromConn(pc.conn, "", name, logger, NewClientOptions(opts...))
+	c := &audioClient{serviceClient: sc, name: name.ShortName()}
+	var once sync.Once
+	c.release = func() error {
+		var err error
+		once.Do(func() { err = p.release(address, pc) })
+		return err
+	}
+	return c, nil
+}
+
+// release drops a client from pc, closing the connection after the last.
+func (p *ClientPool) release(address string, pc *pooledConn) error {
+	p.mu.Lock()
+	pc.clients--
+	last := pc.clients == 0 && !pc.closed
+	if last && p.conns[address] == pc {
+		delete(p.conns, address)
+	}
+	p.mu.Unlock()
+	if !last || pc.conn == nil {
+		return nil
+	}
+	return closeConn(pc.conn)
+}
+
+// Close closes every connection, whether or not its clients were closed.
+func (p *ClientPool) Close() error {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = map[string]*pooledConn{}
+	for _, pc := range conns {
+		pc.closed = true
+	}
+	p.mu.Unlock()
+	var err error
+	for _, pc := range conns {
+		<-pc.ready
+		if pc.conn != nil {
+			if cerr := closeConn(pc.conn); err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
+}
+
+func closeConn(conn rpc.ClientConn) error {
+	if c, ok := conn.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// StreamLimiter caps the streams open at once across the clients given it
+// with WithStreamLimiter. When a stream ends, its slot goes to the clients
+// waiting for one in turn, oldest request first within a client, so one
+// client opening many streams can't starve the rest.
+type StreamLimiter struct {
+	max int
+
+	mu     sync.Mutex
+	active int
+	queues map[string][]chan struct{} // by client
+	order  []string                   // clients with waiters, the next served first
+}
+
+// NewStreamLimiter returns a limiter of max streams.
+func NewStreamLimiter(max int) *StreamLimiter {
+	return &StreamLimiter{max: max, queues: map[string][]chan struct{}{}}
+}
+
+// Active returns the number of streams open.
+func (l *StreamLimiter) Active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.active
+}
+
+// Waiting returns the number of streams waiting for a slot.
+func (l *StreamLimiter) Waiting() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, q := range l.queues {
+		n += len(q)
+	}
+	return n
+}
+
+// acquire waits for a slot for owner and returns the function that gives
+// it up.
+func (l *StreamLimiter) acquire(ctx context.Context, owner string) (func(), error) {
+	l.mu.Lock()
+	if l.active < l.max && len(l.order) == 0 {
+		l.active++
+		l.mu.Unlock()
+		return l.releaser(), nil
+	}
+	ready := make(chan struct{})
+	if len(l.queues[owner]) == 0 {
+		l.order = append(l.order, owner)
+	}
+	l.queues[owner] = append(l.queues[owner], ready)
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return l.releaser(), nil
+	case <-ctx.Done():
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-ready:
+		// granted as ctx ended, so it passes on
+		l.releaseLocked()
+	default:
+		q := slices.DeleteFunc(l.queues[owner], func(c chan struct{}) bool { return c == ready })
+		if len(q) == 0 {
+			delete(l.queues, owner)
+			l.order = slices.DeleteFunc(l.order, func(o string) bool { return o == owner })
+		} else {
+			l.queues[owner] = q
+		}
+	}
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func (l *StreamLimiter) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.releaseLocked()
+		})
+	}
+}
+
+// releaseLocked hands a freed slot to the next client waiting, which goes
+// to the back of the line if it has more.
+func (l *StreamLimiter) releaseLocked() {
+	if len(l.order) == 0 {
+		l.active--
+		return
+	}
+	owner := l.order[0]
+	l.order = l.order[1:]
+	q := l.queues[owner]
+	close(q[0])
+	if len(q) == 1 {
+		delete(l.queues, owner)
+	} else {
+		l.queues[owner] = q[1:]
+		l.order = append(l.order, owner)
+	}
+}
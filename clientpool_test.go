@@ -0,0 +1,181 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+func TestStreamLimiterFairness(t *testing.T) {
+	ctx := context.Background()
+	l := NewStreamLimiter(1)
+	release, err := l.acquire(ctx, "busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// busy queues three streams before quiet queues one
+	granted := make(chan string, 4)
+	releases := make(chan func(), 4)
+	for _, owner := range []string{"busy", "busy", "busy", "quiet"} {
+		before := l.Waiting()
+		go func() {
+			r, err := l.acquire(ctx, owner)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			granted <- owner
+			releases <- r
+		}()
+		for deadline := time.Now().Add(5 * time.Second); l.Waiting() == before && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// a cancelled wait gives up its place
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := l.acquire(cctx, "impatient"); status.Code(err) != codes.Canceled {
+		t.Errorf("a cancelled wait returned %v", err)
+	}
+	if l.Waiting() != 4 || l.Active() != 1 {
+		t.Fatalf("%d waiting and %d active, want 4 and 1", l.Waiting(), l.Active())
+	}
+
+	var order []string
+	release()
+	release() // only the first counts
+	for range 4 {
+		order = append(order, <-granted)
+		(<-releases)()
+	}
+	if want := []string{"busy", "quiet", "busy", "busy"}; !slices.Equal(order, want) {
+		t.Errorf("granted %v, want %v", order, want)
+	}
+	if l.Active() != 0 || l.Waiting() != 0 {
+		t.Errorf("%d active and %d waiting at the end", l.Active(), l.Waiting())
+	}
+}
+
+// countedConn counts the times it is closed.
+type countedConn struct {
+	*grpc.ClientConn
+	closes *atomic.Int32
+}
+
+func (c countedConn) Close() error {
+	c.closes.Add(1)
+	return c.ClientConn.Close()
+}
+
+func TestClientPool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	info := AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}
+	first, second := newBurstSource(2, info), newBurstSource(2, info)
+	second.Named = Named("second").AsNamed()
+	coll, err := resource.NewAPIResourceCollection[Audio](API, map[resource.Name]Audio{first.Name(): first, second.Name(): second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer()
+	pb.RegisterAudioServiceServer(gs, NewRPCServiceServer(coll).(pb.AudioServiceServer))
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	var dials, closes atomic.Int32
+	pool := NewClientPool(func(ctx context.Context, address string) (rpc.ClientConn, error) {
+		if address != lis.Addr().String() {
+			return nil, errors.New("no such robot")
+		}
+		dials.Add(1)
+		conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, err
+		}
+		return countedConn{conn, &closes}, nil
+	}, 1)
+	defer pool.Close()
+
+	logger := logging.NewTestLogger(t)
+	a, err := pool.Client(ctx, lis.Addr().String(), first.Name(), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := pool.Client(ctx, lis.Addr().String(), second.Name(), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Client(ctx, "nowhere:1", first.Name(), logger); err == nil {
+		t.Error("got a client for an unreachable robot")
+	}
+	if dials.Load() != 1 {
+		t.Fatalf("dialed %d times, want one connection", dials.Load())
+	}
+
+	// one stream at a time: the second waits for the first to end
+	chunksA, err := a.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened := make(chan (<-chan *AudioChunk))
+	go func() {
+		chunks, err := b.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+		if err != nil {
+			t.Error(err)
+		}
+		opened <- chunks
+	}()
+	select {
+	case <-opened:
+		t.Fatal("a second stream opened past the cap")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(first.start)
+	if n, _, _ := drain(t, chunksA); n != 2 {
+		t.Errorf("first got %d chunks, want 2", n)
+	}
+	chunksB := <-opened
+	close(second.start)
+	if n, _, _ := drain(t, chunksB); n != 2 {
+		t.Errorf("second got %d chunks, want 2", n)
+	}
+
+	// the connection closes with its last client
+	if err := a.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	a.Close(ctx)
+	if closes.Load() != 0 {
+		t.Fatal("closed the connection with a client still on it")
+	}
+	if err := b.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if closes.Load() != 1 {
+		t.Fatalf("closed the connection %d times, want once", closes.Load())
+	}
+	if c, err := pool.Client(ctx, lis.Addr().String(), first.Name(), logger); err != nil || dials.Load() != 2 {
+		t.Fatalf("reconnecting made %d dials: %v", dials.Load(), err)
+	} else {
+		defer c.Close(ctx)
+	}
+}
@@ -0,0 +1,509 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Limits and defaults for triggered clips.
+const (
+	defaultClipPreRoll  = 5 * time.Second
+	defaultClipPostRoll = 5 * time.Second
+	maxClipPreRoll      = maxLoopLength
+	maxClipPostRoll     = time.Minute
+)
+
+// ClipModel wraps a microphone and keeps the last few seconds of its capture,
+// so a clip saved when something happens starts before it did.
+var ClipModel = resource.NewModel("olivia", "audio", "clip")
+
+// ClipConfig is the configuration of the clip model.
+type ClipConfig struct {
+	Input string `json:"input"` // Audio resource to capture from
+	// PreRollSeconds of capture are kept to go before a trigger, 5 if zero
+	// and at most 300.
+	PreRollSeconds float64 `json:"pre_roll_seconds,omitempty"`
+	// PostRollSeconds are captured after a trigger, 5 if zero and at most 60.
+	PostRollSeconds float64 `json:"post_roll_seconds,omitempty"`
+	// Triggers are the sound events, from DetectorNames, that save a clip to
+	// the server's recording store by themselves as they start. Events while
+	// one is being captured are part of it.
+	Triggers []string `json:"triggers,omitempty"`
+}
+
+// Validate checks the clip configuration and returns the input as a
+// dependency.
+func (c *ClipConfig) Validate(path string) ([]string, []string, error) {
+	if c.Input == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "input")
+	}
+	if c.PreRollSeconds < 0 || c.PreRollSeconds > maxClipPreRoll.Seconds() {
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("pre_roll_seconds must be from 0 to %v", maxClipPreRoll.Seconds()))
+	}
+	if c.PostRollSeconds < 0 || c.PostRollSeconds > maxClipPostRoll.Seconds() {
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("post_roll_seconds must be from 0 to %v", maxClipPostRoll.Seconds()))
+	}
+	for _, trigger := range c.Triggers {
+		if _, ok := detectorFactories[trigger]; !ok {
+			return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("unknown trigger %q, expected one of %v", trigger, DetectorNames()))
+		}
+	}
+	return []string{c.Input}, nil, nil
+}
+
+func init() {
+	registerModel(ClipModel, resource.Registration[Audio, *ClipConfig]{
+		AttributeMapConverter: migratingConverter[*ClipConfig](ClipModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*ClipConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			input, err := resource.FromDependencies[Audio](deps, Named(cfg.Input))
+			if err != nil {
+				return nil, err
+			}
+			return NewClipper(conf.ResourceName(), input, *cfg, logger), nil
+		},
+	})
+}
+
+// ClipOptions sets the clip CaptureClip returns. Zero durations take the
+// resource's configured pre-roll and post-roll.
+type ClipOptions struct {
+	PreRoll  time.Duration // at most the configured pre-roll
+	PostRoll time.Duration // at most a minute
+	Codec    string        // a raw pcm codec, pcm16 if empty
+}
+
+// Clip is audio from before and after a trigger.
+type Clip struct {
+	Data    []byte
+	Info    AudioInfo
+	Start   time.Time // capture time of the first sample, zero if unknown
+	Trigger time.Time // capture time the pre-roll ends at, zero if unknown
+}
+
+// ClipCapturer is implemented by Audio resources and clients that keep
+// capture from before a trigger.
+type ClipCapturer interface {
+	// CaptureClip returns the pre-roll kept up to now followed by the
+	// post-roll captured from now. The clip is cut short if the capture
+	// changes format, pauses or ends during the post-roll.
+	CaptureClip(ctx context.Context, opts ClipOptions) (Clip, error)
+}
+
+type clipper struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	input    Audio
+	preRoll  time.Duration
+	postRoll time.Duration
+	triggers []string
+	logger   logging.Logger
+
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	mu      sync.Mutex
+	ring    sampleRing
+	waiters []*clipWaiter
+	saving  bool // a triggered clip is being captured
+}
+
+// clipWaiter collects the post-roll of one clip.
+type clipWaiter struct {
+	postRoll time.Duration
+	info     AudioInfo // zero until the format is known
+	need     int       // interleaved samples
+	samples  []float32
+	done     chan struct{}
+}
+
+// NewClipper returns a resource that passes capture and playback through to
+// input while keeping cfg.PreRollSeconds of its capture for clips.
+func NewClipper(name resource.Name, input Audio, cfg ClipConfig, logger logging.Logger) Audio {
+	c := &clipper{
+		Named:    name.AsNamed(),
+		input:    input,
+		preRoll:  time.Duration(cfg.PreRollSeconds * float64(time.Second)),
+		postRoll: time.Duration(cfg.PostRollSeconds * float64(time.Second)),
+		triggers: cfg.Triggers,
+		logger:   logger,
+	}
+	if c.preRoll == 0 {
+		c.preRoll = defaultClipPreRoll
+	}
+	if c.postRoll == 0 {
+		c.postRoll = defaultClipPostRoll
+	}
+	c.ring.length = c.preRoll
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.workers.Add(1)
+	go func() {
+		defer c.workers.Done()
+		c.keep(ctx)
+	}()
+	return c
+}
+
+// keep feeds the ring until ctx is done, reopening the capture if it ends.
+func (c *clipper) keep(ctx context.Context) {
+	for {
+		if err := c.keepOnce(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Warnf("pre-roll capture stopped: %v", err)
+		}
+		c.finishWaiters()
+		if sleepCtx(ctx, time.Second) != nil {
+			return
+		}
+	}
+}
+
+// keepOnce feeds one capture session of the input to the ring, the waiters
+// and the triggers.
+func (c *clipper) keepOnce(ctx context.Context) error {
+	chunks, err := sharedCaptureHub.open(ctx, c.input, captureRequest{target: AudioInfo{Format: Pcm32Float}})
+	if err != nil {
+		return err
+	}
+	// a trigger fires as a detector starts matching, so a sound that lasts
+	// past its clip doesn't save another
+	matching := make([]bool, len(c.triggers))
+	detectors := make([]Detector, len(c.triggers))
+	for i, trigger := range c.triggers {
+		if detectors[i], err = newDetector(trigger); err != nil {
+			return err
+		}
+	}
+	for {
+		var chunk *AudioChunk
+		var ok bool
+		select {
+		case chunk, ok = <-chunks:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if !ok {
+			return nil
+		}
+		if chunk.Err != nil {
+			return chunk.Err
+		}
+		samples, info, err := c.add(chunk)
+		if err != nil {
+			return err
+		}
+		for i, d := range detectors {
+			was := matching[i]
+			if matching[i] = d.Detect(samples, info); matching[i] && !was {
+				c.trigger(ctx, c.triggers[i])
+			}
+		}
+	}
+}
+
+// add puts a chunk in the ring and hands it to the clips waiting for their
+// post-roll.
+func (c *clipper) add(chunk *AudioChunk) ([]float32, AudioInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	samples, reset, err := c.ring.add(chunk)
+	if err != nil {
+		return nil, AudioInfo{}, err
+	}
+	info := c.ring.info
+	c.waiters = slices.DeleteFunc(c.waiters, func(w *clipWaiter) bool {
+		if w.info.SampleRate == 0 {
+			// nothing was kept when the clip was asked for
+			w.setInfo(info)
+		} else if reset {
+			close(w.done)
+			return true
+		}
+		w.samples = append(w.samples, samples[:min(len(samples), w.need-len(w.samples))]...)
+		if len(w.samples) == w.need {
+			close(w.done)
+			return true
+		}
+		return false
+	})
+	return samples, info, nil
+}
+
+func (w *clipWaiter) setInfo(info AudioInfo) {
+	w.info, w.need = info, max(1, int(w.postRoll.Seconds()*float64(info.SampleRate)))*info.Channels
+}
+
+// finishWaiters ends every clip waiting for post-roll with what it has.
+func (c *clipper) finishWaiters() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, w := range c.waiters {
+		close(w.done)
+	}
+	c.waiters = nil
+}
+
+// trigger saves a clip for a sound event unless one is being saved already.
+// Its pre-roll is taken before the next chunk is kept.
+func (c *clipper) trigger(ctx context.Context, name string) {
+	c.mu.Lock()
+	if c.saving {
+		c.mu.Unlock()
+		return
+	}
+	c.saving = true
+	c.mu.Unlock()
+	done := func() {
+		c.mu.Lock()
+		c.saving = false
+		c.mu.Unlock()
+	}
+	if ServerRecordings.Dir == "" {
+		c.logger.Warnf("cannot save %s clip: %v", name, errNoRecordingStore)
+		done()
+		return
+	}
+	p, err := c.begin(ClipOptions{})
+	if err != nil {
+		c.logger.Warnf("cannot save %s clip: %v", name, err)
+		done()
+		return
+	}
+	c.workers.Add(1)
+	go func() {
+		defer c.workers.Done()
+		defer done()
+		clip, err := c.saveClip(ctx, name, p)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Warnf("cannot save %s clip: %v", name, err)
+			}
+			return
+		}
+		ServerWebhooks.Notify(WebhookEvent{Type: WebhookDetection, Resource: c.Name().ShortName(), Timestamp: p.trigger, Detector: name, Clip: clip})
+	}()
+}
+
+// saveClip completes a clip and saves it as a 16-bit WAV in the server's
+// recording store, named <resource>-<trigger>-<time>.wav.
+func (c *clipper) saveClip(ctx context.Context, trigger string, p *pendingClip) (string, error) {
+	if err := c.collect(ctx, p); err != nil {
+		return "", err
+	}
+	if len(p.samples) == 0 {
+		return "", errors.New("nothing was captured")
+	}
+	at := p.trigger
+	if at.IsZero() {
+		at = time.Now()
+	}
+	name := fmt.Sprintf("%s-%s-%s", c.Name().ShortName(), trigger, at.UTC().Format("20060102T150405.000Z"))
+	if err := ServerRecordings.saveWAV(name, p.samples, p.info.SampleRate, p.info.Channels); err != nil {
+		return "", err
+	}
+	return name + ".wav", nil
+}
+
+// CaptureClip returns the kept pre-roll and the post-roll that follows it.
+func (c *clipper) CaptureClip(ctx context.Context, opts ClipOptions) (Clip, error) {
+	codec := opts.Codec
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return Clip{}, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return Clip{}, errorf(ErrUnsupportedCodec, "clips need a raw pcm codec, got %q", codec)
+	}
+	p, err := c.begin(opts)
+	if err != nil {
+		return Clip{}, err
+	}
+	if err := c.collect(ctx, p); err != nil {
+		return Clip{}, err
+	}
+	data, err := encodePCM(p.samples, format)
+	if err != nil {
+		return Clip{}, err
+	}
+	info := p.info
+	info.Format = format
+	return Clip{Data: data, Info: info, Start: p.start, Trigger: p.trigger}, nil
+}
+
+// pendingClip is a clip whose pre-roll has been taken and whose post-roll
+// is being captured.
+type pendingClip struct {
+	samples        []float32 // interleaved, the pre-roll until collected
+	info           AudioInfo
+	start, trigger time.Time // capture times of the first sample and of the trigger
+	w              *clipWaiter
+}
+
+// begin takes the pre-roll of a clip and starts capturing its post-roll.
+func (c *clipper) begin(opts ClipOptions) (*pendingClip, error) {
+	if opts.PreRoll < 0 || opts.PreRoll > c.preRoll {
+		return nil, errorf(ErrInvalidArgument, "pre-roll must be from 0 to the %v kept, got %v", c.preRoll, opts.PreRoll)
+	}
+	if opts.PostRoll < 0 || opts.PostRoll > maxClipPostRoll {
+		return nil, errorf(ErrInvalidArgument, "post-roll must be from 0 to %v, got %v", maxClipPostRoll, opts.PostRoll)
+	}
+	if opts.PreRoll == 0 {
+		opts.PreRoll = c.preRoll
+	}
+	if opts.PostRoll == 0 {
+		opts.PostRoll = c.postRoll
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p := &pendingClip{trigger: c.ring.end, w: &clipWaiter{postRoll: opts.PostRoll, done: make(chan struct{})}}
+	p.samples, p.info, p.start = c.ring.last(opts.PreRoll)
+	if p.info.SampleRate != 0 {
+		p.w.setInfo(p.info)
+	}
+	c.waiters = append(c.waiters, p.w)
+	return p, nil
+}
+
+// collect waits for the post-roll of p and appends it.
+func (c *clipper) collect(ctx context.Context, p *pendingClip) error {
+	select {
+	case <-p.w.done:
+	case <-ctx.Done():
+		c.mu.Lock()
+		c.waiters = slices.DeleteFunc(c.waiters, func(w *clipWaiter) bool { return w == p.w })
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+	if p.info.SampleRate == 0 {
+		// nothing was kept, so the clip is all post-roll
+		p.samples, p.info = p.w.samples, p.w.info
+		return nil
+	}
+	p.samples = append(p.samples, p.w.samples...)
+	return nil
+}
+
+// GetAudio passes through to the input.
+func (c *clipper) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	return c.input.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+}
+
+func (c *clipper) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return c.input.Play(ctx, data, codec, sampleRate, channels)
+}
+
+// DoCommand takes {"capture_clip": {"pre_roll_seconds": 2,
+// "post_roll_seconds": 3}}, which saves a clip to the server's recording
+// store and returns its name.
+func (c *clipper) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, c, cmd); ok {
+		return resp, err
+	}
+	if _, ok := cmd["capture_clip"]; !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	params, err := commandParams(cmd, "capture_clip")
+	if err != nil {
+		return nil, err
+	}
+	pre, err := commandNumber(params, "capture_clip", "pre_roll_seconds", 0)
+	if err != nil {
+		return nil, err
+	}
+	post, err := commandNumber(params, "capture_clip", "post_roll_seconds", 0)
+	if err != nil {
+		return nil, err
+	}
+	if ServerRecordings.Dir == "" {
+		return nil, errNoRecordingStore
+	}
+	p, err := c.begin(ClipOptions{
+		PreRoll:  time.Duration(pre * float64(time.Second)),
+		PostRoll: time.Duration(post * float64(time.Second)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	name, err := c.saveClip(ctx, "clip", p)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"recording": name}, nil
+}
+
+// Close stops keeping capture and waits for clips being saved.
+func (c *clipper) Close(ctx context.Context) error {
+	c.cancel()
+	c.workers.Wait()
+	return nil
+}
+
+func (s *audioServer) CaptureClip(ctx context.Context, req *pb.CaptureClipRequest) (*pb.CaptureClipResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	cc, ok := a.(ClipCapturer)
+	if !ok {
+		return nil, errorf(ErrUnsupported, "%s does not keep a pre-roll for clips", req.Name)
+	}
+	clip, err := cc.CaptureClip(ctx, ClipOptions{
+		PreRoll:  time.Duration(req.PreRollSeconds * float64(time.Second)),
+		PostRoll: time.Duration(req.PostRollSeconds * float64(time.Second)),
+		Codec:    req.Codec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit(ctx, req.Name, AuditClipCaptured, "", fmt.Sprintf("%s, %d bytes, %gs pre-roll, %gs post-roll", clip.Info.Format, len(clip.Data), req.PreRollSeconds, req.PostRollSeconds), nil); err != nil {
+		return nil, err
+	}
+	return &pb.CaptureClipResponse{
+		AudioData: clip.Data,
+		Info: &pb.AudioInfo{
+			Codec:       clip.Info.Format.String(),
+			SampleRate:  int32(clip.Info.SampleRate),
+			NumChannels: int32(clip.Info.Channels),
+		},
+		StartTimestampNanoseconds:   toUnixNano(clip.Start),
+		TriggerTimestampNanoseconds: toUnixNano(clip.Trigger),
+	}, nil
+}
+
+func (c *audioClient) CaptureClip(ctx context.Context, opts ClipOptions) (Clip, error) {
+	resp, err := c.client.CaptureClip(ctx, &pb.CaptureClipRequest{
+		Name:            c.name,
+		PreRollSeconds:  opts.PreRoll.Seconds(),
+		PostRollSeconds: opts.PostRoll.Seconds(),
+		Codec:           opts.Codec,
+	})
+	if err != nil {
+		return Clip{}, err
+	}
+	clip := Clip{
+		Data:    resp.AudioData,
+		Start:   fromUnixNano(resp.StartTimestampNanoseconds),
+		Trigger: fromUnixNano(resp.TriggerTimestampNanoseconds),
+	}
+	if info := infoFromProto(resp.Info); info != nil {
+		clip.Info = *info
+	}
+	return clip, nil
+}
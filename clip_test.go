@@ -0,0 +1,112 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestCaptureClip(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// 10ms chunks, each at its own level
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 {
+		s := make([]float32, 80)
+		for j := range s {
+			s[j] = float32(i+1) / 100
+		}
+		return s
+	}
+	res := NewClipper(Named("clip"), src, ClipConfig{PreRollSeconds: 0.5, PostRollSeconds: 0.3}, logging.NewTestLogger(t))
+	defer res.Close(ctx)
+	c := serveAudio(t, res).(ClipCapturer)
+	if _, err := c.CaptureClip(ctx, ClipOptions{PreRoll: time.Second}); err == nil {
+		t.Error("captured more pre-roll than is kept")
+	}
+
+	close(src.start)
+	kept := func() int {
+		cl := res.(*clipper)
+		cl.mu.Lock()
+		defer cl.mu.Unlock()
+		return cl.ring.count
+	}
+	for ctx.Err() == nil && kept() < 4000 {
+		time.Sleep(time.Millisecond)
+	}
+	// the source ends, so the post-roll comes from the next capture session
+	clip, err := c.CaptureClip(ctx, ClipOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clip.Info != (AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}) {
+		t.Errorf("clip is %+v", clip.Info)
+	}
+	samples, err := decodePCM(clip.Data, Pcm16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 6400 {
+		t.Fatalf("clip has %d samples, want 0.8s", len(samples))
+	}
+	for _, want := range []struct {
+		at    int
+		level float64
+	}{{0, 0.01}, {3999, 0.5}, {4000, 0.01}, {6399, 0.3}} {
+		if got := float64(samples[want.at]); math.Abs(got-want.level) > 0.001 {
+			t.Errorf("sample %d is %.3f, want %.2f", want.at, got, want.level)
+		}
+	}
+}
+
+func TestClipTrigger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	dir := t.TempDir()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = dir
+
+	cfg := ClipConfig{Input: "mic", Triggers: []string{"thunder"}}
+	if _, _, err := cfg.Validate("path"); err == nil {
+		t.Error("validated an unknown trigger")
+	}
+	cfg = ClipConfig{Input: "mic", PreRollSeconds: 0.5, PostRollSeconds: 0.1, Triggers: []string{"sound"}}
+	if _, _, err := cfg.Validate("path"); err != nil {
+		t.Fatal(err)
+	}
+	// quiet until a tone at 300ms
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 {
+		if i < 30 {
+			return make([]float32, 80)
+		}
+		return tone(8000, 80, 1000, 0.5)
+	}
+	res := NewClipper(Named("clip"), src, cfg, logging.NewTestLogger(t))
+	close(src.start)
+	var saved []string
+	for ctx.Err() == nil && len(saved) == 0 {
+		saved, _ = filepath.Glob(filepath.Join(dir, "clip-sound-*.wav"))
+		time.Sleep(time.Millisecond)
+	}
+	if err := res.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 1 {
+		t.Fatalf("saved %v, want one clip", saved)
+	}
+	// the 310ms up to and including the first loud chunk, then 100ms
+	fi, err := os.Stat(saved[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != 44+3280*2 {
+		t.Errorf("the clip is %d bytes, want %d", fi.Size(), 44+3280*2)
+	}
+}
@@ -0,0 +1,138 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A ClockSource is a backend's notion of now. Hardware backends follow the
+// wall clock; file and network sources and simulations pace their audio on
+// whichever clock they're given, so the same code runs in real time on a
+// robot and as fast as a test advances it.
+type ClockSource interface {
+	Now() time.Time
+	// Sleep blocks until d has passed on this clock or ctx is done.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SystemClock is the wall clock.
+var SystemClock ClockSource = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error { return sleepCtx(ctx, d) }
+
+// clocked is implemented by resources that run on a ClockSource rather than
+// the wall clock.
+type clocked interface {
+	deviceClock() ClockSource
+}
+
+// clockOf returns the clock a resource runs on.
+func clockOf(a Audio) ClockSource {
+	if c, ok := a.(clocked); ok {
+		return c.deviceClock()
+	}
+	return SystemClock
+}
+
+// ManualClock only moves when Advance is called, for tests and offline
+// processing.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters map[chan struct{}]time.Time
+}
+
+// NewManualClock returns a ManualClock reading start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start, waiters: map[chan struct{}]time.Time{}}
+}
+
+// Now returns the clock's current time.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep blocks until Advance has moved the clock past d from now.
+func (c *ManualClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.mu.Lock()
+	if d <= 0 {
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+	wake := make(chan struct{})
+	c.waiters[wake] = c.now.Add(d)
+	c.mu.Unlock()
+
+	select {
+	case <-wake:
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.waiters, wake)
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Advance moves the clock forward by d and wakes every sleeper that is due.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for wake, at := range c.waiters {
+		if !at.After(c.now) {
+			close(wake)
+			delete(c.waiters, wake)
+		}
+	}
+}
+
+// samplePacer releases audio at its sample rate on a clock. Frame n is due
+// at start + n/rate, so time spent producing a block doesn't accumulate as
+// drift.
+type samplePacer struct {
+	clock  ClockSource
+	rate   int
+	start  time.Time
+	frames int64 // frames released so far
+}
+
+func newSamplePacer(clock ClockSource, rate int) *samplePacer {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &samplePacer{clock: clock, rate: rate, start: clock.Now()}
+}
+
+// wait blocks until the next frames frames have been played out in real
+// time, then returns the capture time of the first of them.
+func (p *samplePacer) wait(ctx context.Context, frames int) (time.Time, error) {
+	first := p.timestamp(p.frames)
+	p.frames += int64(frames)
+	if err := p.clock.Sleep(ctx, p.timestamp(p.frames).Sub(p.clock.Now())); err != nil {
+		return time.Time{}, err
+	}
+	return first, nil
+}
+
+// timestamp returns when frame n of the stream was due.
+func (p *samplePacer) timestamp(n int64) time.Time {
+	// whole seconds first, so n*time.Second can't overflow on streams
+	// running for days
+	rate := int64(p.rate)
+	return p.start.Add(time.Duration(n/rate)*time.Second + time.Duration(n%rate)*time.Second/time.Duration(rate))
+}
+
+// frame returns the first frame due at or after t.
+func (p *samplePacer) frame(t time.Time) int64 {
+	d := t.Sub(p.start)
+	rate := time.Duration(p.rate)
+	return int64(d/time.Second*rate + (d%time.Second*rate+time.Second-1)/time.Second)
+}
@@ -0,0 +1,88 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManualClockSleep(t *testing.T) {
+	c := NewManualClock(time.Unix(1000, 0))
+	ctx := context.Background()
+	if err := c.Sleep(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	woke := make(chan error, 1)
+	go func() { woke <- c.Sleep(ctx, 30*time.Millisecond) }()
+	waitForSleeper(t, c, time.Unix(1000, 0).Add(30*time.Millisecond))
+	c.Advance(20 * time.Millisecond)
+	select {
+	case <-woke:
+		t.Fatal("woke before the clock reached the deadline")
+	case <-time.After(10 * time.Millisecond):
+	}
+	c.Advance(10 * time.Millisecond)
+	if err := <-woke; err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	go func() { woke <- c.Sleep(cancelled, time.Hour) }()
+	waitForSleeper(t, c, c.Now().Add(time.Hour))
+	cancel()
+	if err := <-woke; err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.waiters) != 0 {
+		t.Fatal("a cancelled sleeper was left waiting")
+	}
+}
+
+func TestSamplePacer(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewManualClock(start)
+	p := newSamplePacer(c, 44100)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 10ms blocks at 44.1 kHz don't divide a nanosecond evenly, but the
+	// timestamps of a thousand of them still land exactly on ten seconds
+	for i := 0; i < 1000; i++ {
+		due := p.timestamp(p.frames + 441)
+		done := make(chan time.Time, 1)
+		go func() {
+			at, err := p.wait(ctx, 441)
+			if err != nil {
+				t.Error(err)
+			}
+			done <- at
+		}()
+		waitForSleeper(t, c, due)
+		c.Advance(due.Sub(c.Now()))
+		if at := <-done; at != start.Add(time.Duration(i*441)*time.Second/44100) {
+			t.Fatalf("block %d captured at %v", i, at.Sub(start))
+		}
+	}
+	if got := p.timestamp(p.frames); !got.Equal(start.Add(10 * time.Second)) {
+		t.Fatalf("1000 blocks end %v in, want 10s", got.Sub(start))
+	}
+}
+
+func TestSamplePacerFrames(t *testing.T) {
+	p := &samplePacer{rate: 48000, start: time.Unix(1000, 0)}
+	for _, n := range []int64{0, 1, 47999, 48000, 48001, 12345678, 100 * 3600 * 48000} {
+		if got := p.frame(p.timestamp(n)); got != n {
+			t.Errorf("frame(timestamp(%d)) = %d", n, got)
+		}
+	}
+	// streams outlast the 53 hours after which n*time.Second overflows
+	if got, want := p.timestamp(100*3600*48000), p.start.Add(100*time.Hour); !got.Equal(want) {
+		t.Fatalf("frame 100h in is due %v, want %v", got, want)
+	}
+	if got := p.frame(p.start.Add(time.Nanosecond)); got != 1 {
+		t.Fatalf("first frame due after 1ns is %d, want 1", got)
+	}
+}
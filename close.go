@@ -0,0 +1,111 @@
+package audio
+
+import (
+	"context"
+	"sync"
+)
+
+// streamGroup ties the streams a resource runs to its Close. Every stream
+// runs under a context that close also cancels, and close waits for the
+// streams to return, so the files, devices and cgo state they hold are
+// released before the resource is rebuilt. The zero value is ready to use.
+type streamGroup struct {
+	mu     sync.Mutex
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// start begins a stream that ends with ctx or with the group. The stream
+// calls done once it has released everything it holds.
+func (g *streamGroup) start(ctx context.Context) (context.Context, func(), error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return nil, nil, ErrClosed
+	}
+	if g.ctx == nil {
+		g.ctx, g.cancel = context.WithCancel(context.Background())
+	}
+	g.wg.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(g.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		g.wg.Done()
+	}, nil
+}
+
+// close ends every stream and waits for them to return, or for ctx. Streams
+// can't be started once it's called.
+func (g *streamGroup) close(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CaptureStreams gives an Audio implementation the GetAudio contract on
+// cancellation: embed it, run each stream with Stream, and call
+// CloseStreams from Close. The zero value is ready to use.
+type CaptureStreams struct {
+	group streamGroup
+}
+
+// CaptureFunc captures one stream, handing each chunk to send until send
+// returns false or ctx is done, and releases the device it opened before
+// returning. It must return promptly once ctx is done; a device whose reads
+// block can be closed with context.AfterFunc(ctx, ...) to unblock them.
+type CaptureFunc func(ctx context.Context, send func(*AudioChunk) bool) error
+
+// Stream runs capture in its own goroutine and returns its chunks. The
+// stream ends when capture returns, ctx is done or CloseStreams is called,
+// and the channel closes only once capture has returned, so the device is
+// released by the time a consumer sees the end. A capture that fails ends
+// the stream with a chunk carrying its error, unless ctx was done.
+func (s *CaptureStreams) Stream(ctx context.Context, capture CaptureFunc) (<-chan *AudioChunk, error) {
+	ctx, done, err := s.group.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		send := func(chunk *AudioChunk) bool {
+			select {
+			case out <- chunk:
+				return true
+			case <-ctx.Done():
+				chunk.Release()
+				return false
+			}
+		}
+		if err := capture(ctx, send); err != nil && ctx.Err() == nil {
+			send(&AudioChunk{Err: err})
+		}
+	}()
+	return out, nil
+}
+
+// CloseStreams ends every stream and waits for their captures to return,
+// or for ctx. Stream fails once it's called.
+func (s *CaptureStreams) CloseStreams(ctx context.Context) error {
+	return s.group.close(ctx)
+}
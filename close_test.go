@@ -0,0 +1,274 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// openHandles counts this process's open descriptors of path.
+func openHandles(t *testing.T, path string) int {
+	t.Helper()
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("needs /proc to count open files")
+	}
+	n := 0
+	for _, e := range entries {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", e.Name())); err == nil && target == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestFileSourceCloseReleasesFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestWAV(t, dir, "a.wav", 8000, 8000)
+	path := filepath.Join(dir, "a.wav")
+	cfg := FileSourceConfig{Path: path, Loop: true, ChunkMs: 10}
+	src, err := NewFileSource(Named("file"), cfg, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, err := src.GetAudio(context.Background(), "", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	if n := openHandles(t, path); n != 1 {
+		t.Fatalf("%d handles open while streaming, want 1", n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := src.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := openHandles(t, path); n != 0 {
+		t.Errorf("%d handles still open after Close", n)
+	}
+	for range ch {
+	}
+	if _, err := src.GetAudio(context.Background(), "", 0, 0, 0); !errors.Is(err, ErrClosed) {
+		t.Errorf("GetAudio after Close returned %v, want %v", err, ErrClosed)
+	}
+
+	// the rebuilt resource can use the file straight away
+	again, err := NewFileSource(Named("file"), cfg, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close(context.Background())
+	ch, err = again.GetAudio(ctx, "", 0.05, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunks, _, _ := drain(t, ch); chunks != 5 {
+		t.Errorf("reopened source sent %d chunks, want 5", chunks)
+	}
+}
+
+func TestWrapperCloseEndsStreams(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	sim, err := NewSim(Named("sim"), SimConfig{ChunkMs: 10}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close(context.Background())
+	wrappers := []Audio{
+		NewAGC(Named("agc"), sim, AGCConfig{}, logger),
+		NewEchoCancel(Named("aec"), sim, EchoCancelConfig{}, logger),
+	}
+	dn, err := NewDenoise(Named("denoise"), sim, DenoiseConfig{}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrappers = append(wrappers, dn)
+
+	for _, w := range wrappers {
+		ch, err := w.GetAudio(context.Background(), "", 0, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		<-ch
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		if err := w.Close(ctx); err != nil {
+			t.Errorf("%s: %v", w.Name().ShortName(), err)
+		}
+		cancel()
+		for range ch {
+		}
+	}
+	// closing the wrappers leaves their input running
+	ch, err := sim.GetAudio(context.Background(), "", 0.02, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunks, _, _ := drain(t, ch); chunks != 2 {
+		t.Errorf("sim sent %d chunks after its wrappers closed, want 2", chunks)
+	}
+}
+
+func TestStreamGroupCloseGivesUp(t *testing.T) {
+	var g streamGroup
+	_, done, err := g.start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := g.close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("close with a stuck stream returned %v", err)
+	}
+	done()
+	if err := g.close(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+// deviceSource captures from a device whose reads block, through
+// CaptureStreams, and counts the devices it has open.
+type deviceSource struct {
+	resource.Named
+	resource.AlwaysRebuild
+	CaptureStreams
+	open atomic.Int32
+	fail error // ends every capture after its first chunk
+}
+
+func (d *deviceSource) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	return d.Stream(ctx, func(ctx context.Context, send func(*AudioChunk) bool) error {
+		d.open.Add(1)
+		defer d.open.Add(-1)
+		closed := make(chan struct{})
+		stop := context.AfterFunc(ctx, func() { close(closed) })
+		defer stop()
+		info := AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}
+		for seq := int64(0); ; seq++ {
+			// a read blocks until a period is captured or the device closes
+			select {
+			case <-time.After(5 * time.Millisecond):
+			case <-closed:
+				return nil
+			}
+			if !send(&AudioChunk{Sequence: seq, AudioData: make([]byte, 80), Info: &info}) {
+				return nil
+			}
+			if d.fail != nil {
+				return d.fail
+			}
+		}
+	})
+}
+
+func (d *deviceSource) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return nil
+}
+
+func (d *deviceSource) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (d *deviceSource) Close(ctx context.Context) error {
+	return d.CloseStreams(ctx)
+}
+
+// eventually polls cond for up to two seconds.
+func eventually(cond func() bool) bool {
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return cond()
+}
+
+func TestCaptureStreamsCancel(t *testing.T) {
+	d := &deviceSource{Named: Named("device").AsNamed()}
+	goroutines := runtime.NumGoroutine()
+
+	// nobody reads once the caller gives up, and the stream ends regardless
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := d.GetAudio(ctx, "", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if !eventually(func() bool { return d.open.Load() == 0 }) {
+		t.Fatal("the device stayed open after the stream was cancelled")
+	}
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Errorf("cancelled stream failed with %v", chunk.Err)
+		}
+	}
+	if !eventually(func() bool { return runtime.NumGoroutine() <= goroutines }) {
+		t.Errorf("%d goroutines left running, %d before the stream", runtime.NumGoroutine(), goroutines)
+	}
+
+	// a failed capture ends its stream with the error
+	d.fail = errors.New("device unplugged")
+	ch, err = d.GetAudio(context.Background(), "", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last *AudioChunk
+	for chunk := range ch {
+		last = chunk
+	}
+	if last == nil || !errors.Is(last.Err, d.fail) || d.open.Load() != 0 {
+		t.Errorf("failed capture ended with %+v and %d devices open", last, d.open.Load())
+	}
+	d.fail = nil
+
+	// Close ends the streams still running
+	if ch, err = d.GetAudio(context.Background(), "", 0, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	closeCtx, cancelClose := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelClose()
+	if err := d.Close(closeCtx); err != nil {
+		t.Fatal(err)
+	}
+	if n := d.open.Load(); n != 0 {
+		t.Errorf("%d devices open after Close", n)
+	}
+	for range ch {
+	}
+	if _, err := d.GetAudio(context.Background(), "", 0, 0, 0); !errors.Is(err, ErrClosed) {
+		t.Errorf("GetAudio after Close returned %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestServerCancelClosesCapture(t *testing.T) {
+	d := &deviceSource{Named: Named("device").AsNamed()}
+	c := serveAudio(t, d)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || chunk.Err != nil {
+		t.Fatalf("got %+v", chunk)
+	}
+	if n := d.open.Load(); n != 1 {
+		t.Fatalf("%d devices open while streaming, want 1", n)
+	}
+	// the server sees its call end and stops the capture
+	cancel()
+	if !eventually(func() bool { return d.open.Load() == 0 }) {
+		t.Error("the device stayed open after the client cancelled")
+	}
+}
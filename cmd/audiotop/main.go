@@ -0,0 +1,208 @@
+// Command audiotop shows the live levels, active streams, drops and recent
+// events of the audio resources of a robot, using only the public audio API.
+//
+//	audiotop -address my-robot.viam.cloud -api-key-id ID -api-key KEY
+//
+// The screen is redrawn in place. Type a resource's number followed by m to
+// mute or unmute it, which pauses or resumes its capture streams, or by t to
+// play a test tone through it, then enter. q quits.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/utils/rpc"
+
+	audio "github.com/oliviamiller/audioapi-poc"
+)
+
+const (
+	redrawEvery   = 200 * time.Millisecond
+	actionTimeout = 10 * time.Second
+)
+
+func main() {
+	address := flag.String("address", "", "address of the robot")
+	keyID := flag.String("api-key-id", "", "ID of the API key to connect with")
+	key := flag.String("api-key", "", "API key to connect with")
+	refresh := flag.Duration("refresh", time.Second, "how often streams and events are listed")
+	only := flag.String("resources", "", "comma separated audio resources to show, all of them if empty")
+	flag.Parse()
+	if *address == "" {
+		fmt.Fprintln(os.Stderr, "audiotop: -address is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	logger := logging.NewLogger("audiotop")
+	logger.SetLevel(logging.ERROR) // anything else would scroll the screen
+	var opts []client.RobotClientOption
+	if *keyID != "" {
+		opts = append(opts, client.WithDialOptions(rpc.WithEntityCredentials(*keyID, rpc.Credentials{
+			Type:    rpc.CredentialsTypeAPIKey,
+			Payload: *key,
+		})))
+	}
+	if err := run(ctx, *address, *only, *refresh, logger, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "audiotop:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, address, only string, refresh time.Duration, logger logging.Logger, opts []client.RobotClientOption) error {
+	robot, err := client.New(ctx, address, logger, opts...)
+	if err != nil {
+		return err
+	}
+	defer robot.Close(context.Background())
+
+	monitors, err := watch(robot, only)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for _, m := range monitors {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			m.meter(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			for {
+				m.poll(ctx)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(refresh):
+				}
+			}
+		}()
+	}
+
+	// commands are read a line at a time, so the terminal stays in its
+	// normal mode
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	status := "type <n>m to mute, <n>t for a test tone, q to quit"
+	statuses := make(chan string, 1)
+	ticker := time.NewTicker(redrawEvery)
+	defer ticker.Stop()
+	for {
+		draw(address, monitors, status)
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok || strings.TrimSpace(line) == "q" {
+				return nil
+			}
+			status = "running " + strings.TrimSpace(line)
+			go func() {
+				msg, err := act(ctx, monitors, line)
+				if err != nil {
+					msg = "error: " + err.Error()
+				}
+				select {
+				case statuses <- msg:
+				case <-ctx.Done():
+				}
+			}()
+		case status = <-statuses:
+		case <-ticker.C:
+		}
+	}
+}
+
+// watch returns a monitor for each audio resource of robot, or only for
+// those named in the comma separated list only, in name order.
+func watch(robot *client.RobotClient, only string) ([]*monitor, error) {
+	var names []string
+	if only != "" {
+		names = strings.Split(only, ",")
+	} else {
+		for _, n := range robot.ResourceNames() {
+			if n.API == audio.API {
+				names = append(names, n.ShortName())
+			}
+		}
+	}
+	slices.Sort(names)
+	if len(names) == 0 {
+		return nil, errors.New("the robot has no audio resources")
+	}
+	monitors := make([]*monitor, len(names))
+	for i, name := range names {
+		a, err := audio.FromRobot(robot, strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		monitors[i] = &monitor{name: a.Name().ShortName(), a: a}
+	}
+	return monitors, nil
+}
+
+// act runs a command such as 2m or 1t and returns what it did.
+func act(ctx context.Context, monitors []*monitor, line string) (string, error) {
+	line = strings.TrimSpace(line)
+	if len(line) < 2 {
+		return "", fmt.Errorf("unknown command %q", line)
+	}
+	i, err := strconv.Atoi(line[:len(line)-1])
+	if err != nil || i < 1 || i > len(monitors) {
+		return "", fmt.Errorf("no resource %q", line[:len(line)-1])
+	}
+	m := monitors[i-1]
+	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+	defer cancel()
+	switch line[len(line)-1] {
+	case 'm':
+		return m.toggleMute(ctx)
+	case 't':
+		if err := m.testTone(ctx); err != nil {
+			return "", err
+		}
+		return "played a test tone through " + m.name, nil
+	default:
+		return "", fmt.Errorf("unknown command %q", line)
+	}
+}
+
+// draw redraws the whole screen.
+func draw(address string, monitors []*monitor, status string) {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	now := time.Now()
+	fmt.Fprintf(&b, "audiotop %s  %s\n\n", address, now.Format("15:04:05"))
+	for i, m := range monitors {
+		m.render(&b, i+1, now)
+		b.WriteString("\n")
+	}
+	b.WriteString(status + "\n> ")
+	os.Stdout.WriteString(b.String())
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	audio "github.com/oliviamiller/audioapi-poc"
+)
+
+// How levels are metered and shown.
+const (
+	levelWindow   = 100 * time.Millisecond
+	meterWidth    = 40
+	meterFloor    = -60.0 // dBFS at the left of a meter
+	peakDecay     = 1.5   // dB a held peak falls per window
+	shownStreams  = 5
+	shownEvents   = 3
+	pollTimeout   = 5 * time.Second
+	reopenBackoff = time.Second
+)
+
+// monitor follows one audio resource for the display.
+type monitor struct {
+	name string
+	a    audio.Audio
+
+	mu      sync.Mutex
+	levels  []audio.ChannelLevel
+	peaks   []float64     // held peak of each channel, dBFS
+	next    time.Time     // capture time the next window should start at, zero if unknown
+	drops   int           // windows of capture missing from the level stream
+	dropped time.Duration // capture missing in total
+	streams []audio.ActiveStream
+	events  []audio.EventRecord
+	muted   bool
+	err     error // of the last poll or level stream, nil once one succeeds
+}
+
+// meter follows the levels of the resource until ctx is done, reopening the
+// stream if it ends.
+func (m *monitor) meter(ctx context.Context) {
+	lm, ok := m.a.(audio.LevelMeter)
+	if !ok {
+		m.setErr(errors.New("levels are not metered"))
+		return
+	}
+	for {
+		levels, err := lm.StreamLevels(ctx, levelWindow)
+		if err == nil {
+			for l := range levels {
+				if l.Err != nil {
+					err = l.Err
+					break
+				}
+				m.add(l)
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			m.setErr(err)
+		}
+		m.mu.Lock()
+		m.next = time.Time{} // audio between streams isn't a drop
+		m.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reopenBackoff):
+		}
+	}
+}
+
+// add takes one window of levels, counting capture missing before it as a
+// drop.
+func (m *monitor) add(l audio.Levels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.next.IsZero() && !l.Timestamp.IsZero() {
+		if gap := l.Timestamp.Sub(m.next); gap > levelWindow/2 {
+			m.drops++
+			m.dropped += gap
+		}
+	}
+	m.next = time.Time{}
+	if !l.Timestamp.IsZero() {
+		m.next = l.Timestamp.Add(levelWindow)
+	}
+	if len(m.peaks) != len(l.Channels) {
+		m.peaks = make([]float64, len(l.Channels))
+		for i := range m.peaks {
+			m.peaks[i] = meterFloor
+		}
+	}
+	for i, c := range l.Channels {
+		m.peaks[i] = math.Max(c.PeakDBFS(), m.peaks[i]-peakDecay)
+	}
+	m.levels, m.err = l.Channels, nil
+}
+
+// poll refreshes the streams and events of the resource.
+func (m *monitor) poll(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+	var streams []audio.ActiveStream
+	var events []audio.EventRecord
+	var err error
+	if l, ok := m.a.(audio.ActiveStreamLister); ok {
+		streams, _, err = l.ListActiveStreams(ctx, shownStreams, "", audio.StreamFilter{})
+	}
+	if h, ok := m.a.(audio.HistoryLister); ok && err == nil {
+		events, _, err = h.ListEvents(ctx, shownEvents, "", audio.EventFilter{})
+	}
+	if ctx.Err() != nil && err != nil {
+		err = fmt.Errorf("polling timed out: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.streams, m.events = streams, events
+}
+
+func (m *monitor) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// toggleMute pauses every capture stream of the resource opened with a
+// request ID, or resumes them if they were paused here. Streams without a
+// request ID can't be paused and keep running.
+func (m *monitor) toggleMute(ctx context.Context) (string, error) {
+	sc, ok := m.a.(audio.StreamController)
+	l, lok := m.a.(audio.ActiveStreamLister)
+	if !ok || !lok {
+		return "", fmt.Errorf("%s streams can't be paused", m.name)
+	}
+	m.mu.Lock()
+	mute := !m.muted
+	m.mu.Unlock()
+
+	var n, skipped int
+	token := ""
+	for {
+		streams, next, err := l.ListActiveStreams(ctx, 100, token, audio.StreamFilter{Direction: "capture"})
+		if err != nil {
+			return "", err
+		}
+		for _, s := range streams {
+			switch {
+			case s.RequestID == "":
+				skipped++
+			case mute && !s.Paused:
+				err = sc.PauseStream(ctx, s.RequestID)
+				n++
+			case !mute && s.Paused:
+				err = sc.ResumeStream(ctx, s.RequestID)
+				n++
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		if token = next; token == "" {
+			break
+		}
+	}
+	m.mu.Lock()
+	m.muted = mute
+	m.mu.Unlock()
+	verb := "resumed"
+	if mute {
+		verb = "paused"
+	}
+	msg := fmt.Sprintf("%s %d capture streams of %s", verb, n, m.name)
+	if skipped > 0 {
+		msg += fmt.Sprintf(", %d without a request ID can't be", skipped)
+	}
+	return msg, nil
+}
+
+// testTone plays the resource's built in test tone.
+func (m *monitor) testTone(ctx context.Context) error {
+	_, err := m.a.DoCommand(ctx, map[string]interface{}{"play_test_tone": true})
+	return err
+}
+
+// render draws the state of the resource.
+func (m *monitor) render(w io.Writer, index int, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	title := fmt.Sprintf("%d %s", index, m.name)
+	if m.muted {
+		title += " [muted]"
+	}
+	fmt.Fprintf(w, "%s  drops %d (%v)\n", title, m.drops, m.dropped.Round(time.Millisecond))
+	if m.err != nil {
+		fmt.Fprintf(w, "  error: %v\n", m.err)
+	}
+	for i, c := range m.levels {
+		fmt.Fprintf(w, "  ch%d %s %6.1f dBFS\n", i, meterBar(c.RMSDBFS(), m.peaks[i], meterWidth), c.RMSDBFS())
+	}
+	for _, s := range m.streams {
+		state := "running"
+		if s.Paused {
+			state = "paused"
+		}
+		id := s.RequestID
+		if id == "" {
+			id = "-"
+		}
+		fmt.Fprintf(w, "  %-8s %-6s %-7s %-20s %8s %s\n", s.Direction, s.Codec, state, id,
+			formatBytes(s.Bytes), now.Sub(s.Start).Round(time.Second))
+	}
+	for _, e := range m.events {
+		fmt.Fprintf(w, "  event %-8s %s %6.1f dBFS peak", e.Kind, e.Timestamp.Local().Format("15:04:05"), e.PeakDBFS)
+		if e.Clip != "" {
+			fmt.Fprintf(w, " %s", e.Clip)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// meterBar draws a level from meterFloor to 0 dBFS as a bar, with the held
+// peak marked.
+func meterBar(levelDBFS, peakDBFS float64, width int) string {
+	pos := func(db float64) int {
+		f := (db - meterFloor) / -meterFloor
+		return int(math.Round(math.Max(0, math.Min(1, f)) * float64(width)))
+	}
+	bar := []byte(strings.Repeat("#", pos(levelDBFS)) + strings.Repeat(".", width-pos(levelDBFS)))
+	if p := pos(peakDBFS); p > 0 {
+		bar[p-1] = '|'
+	}
+	return "[" + string(bar) + "]"
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	audio "github.com/oliviamiller/audioapi-poc"
+)
+
+func TestMonitorDrops(t *testing.T) {
+	m := &monitor{name: "mic"}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := func(at time.Duration, peak float64) audio.Levels {
+		return audio.Levels{Timestamp: start.Add(at), Channels: []audio.ChannelLevel{{RMS: peak / 2, Peak: peak}}}
+	}
+	m.add(window(0, 1))
+	m.add(window(100*time.Millisecond, 0.01))
+	m.add(window(500*time.Millisecond, 0.01)) // 300ms missing
+	if m.drops != 1 || m.dropped != 300*time.Millisecond {
+		t.Errorf("counted %d drops of %v, want 1 of 300ms", m.drops, m.dropped)
+	}
+	// the peak falls back slowly
+	if m.peaks[0] != -3 {
+		t.Errorf("held peak is %.1f dBFS, want -3", m.peaks[0])
+	}
+
+	var b strings.Builder
+	m.render(&b, 1, start)
+	if out := b.String(); !strings.HasPrefix(out, "1 mic  drops 1 (300ms)\n") || !strings.Contains(out, "-46.0 dBFS") {
+		t.Errorf("rendered\n%s", out)
+	}
+}
+
+func TestMeterBar(t *testing.T) {
+	for _, c := range []struct {
+		level, peak float64
+		want        string
+	}{
+		{-60, -60, "[..........]"},
+		{-30, -6, "[#####...|.]"},
+		{0, 0, "[#########|]"},
+		{-90, 6, "[.........|]"},
+	} {
+		if got := meterBar(c.level, c.peak, 10); got != c.want {
+			t.Errorf("meterBar(%v, %v) = %s, want %s", c.level, c.peak, got, c.want)
+		}
+	}
+}
+
+func TestAct(t *testing.T) {
+	monitors := []*monitor{{name: "mic"}}
+	for _, line := range []string{"", "m", "2m", "1x"} {
+		if _, err := act(context.Background(), monitors, line); err == nil {
+			t.Errorf("ran %q", line)
+		}
+	}
+}
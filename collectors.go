@@ -0,0 +1,185 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/data"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// getAudioMethod is the method the data manager captures audio clips with.
+const getAudioMethod = "GetAudio"
+
+// Bounds of the GetAudio collector's duration_seconds.
+const (
+	defaultCollectorDuration = 5 * time.Second
+	maxCollectorDuration     = time.Minute
+)
+
+func init() {
+	data.RegisterCollector(data.MethodMetadata{API: API, MethodName: getAudioMethod}, newGetAudioCollector)
+}
+
+// collectorOptions are the additional parameters of a GetAudio capture
+// method: duration_seconds of audio in each capture, 5 by default, and the
+// codec of the file it's saved as, "wav" (the default) or "mp3".
+type collectorOptions struct {
+	duration time.Duration
+	codec    string
+}
+
+func collectorOptionsFrom(params map[string]*anypb.Any) (collectorOptions, error) {
+	opts := collectorOptions{duration: defaultCollectorDuration, codec: "wav"}
+	if v, err := methodParam(params, "duration_seconds"); err != nil {
+		return opts, err
+	} else if v != nil {
+		seconds, ok := v.(float64)
+		d := time.Duration(seconds * float64(time.Second))
+		if !ok || d <= 0 || d > maxCollectorDuration {
+			return opts, errorf(ErrInvalidArgument, "duration_seconds must be a number of seconds up to %v, got %v", maxCollectorDuration.Seconds(), v)
+		}
+		opts.duration = d
+	}
+	if v, err := methodParam(params, "codec"); err != nil {
+		return opts, err
+	} else if v != nil {
+		if v != "wav" && v != "mp3" {
+			return opts, errorf(ErrInvalidArgument, "codec must be \"wav\" or \"mp3\", got %v", v)
+		}
+		opts.codec = v.(string)
+	}
+	return opts, nil
+}
+
+// methodParam returns the capture method parameter key, nil if it isn't
+// set. The data manager wraps parameters given as strings in the wrapper
+// type they parse as, and others in a structpb.Value; numbers are returned
+// as float64 either way.
+func methodParam(params map[string]*anypb.Any, key string) (interface{}, error) {
+	p, ok := params[key]
+	if !ok {
+		return nil, nil
+	}
+	m, err := p.UnmarshalNew()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	switch v := m.(type) {
+	case *structpb.Value:
+		return v.AsInterface(), nil
+	case *wrapperspb.StringValue:
+		return v.Value, nil
+	case *wrapperspb.DoubleValue:
+		return v.Value, nil
+	case *wrapperspb.Int64Value:
+		return float64(v.Value), nil
+	case *wrapperspb.UInt64Value:
+		return float64(v.Value), nil
+	case *wrapperspb.BoolValue:
+		return v.Value, nil
+	default:
+		return nil, fmt.Errorf("%s has unsupported type %s", key, p.GetTypeUrl())
+	}
+}
+
+// newGetAudioCollector captures a clip of the resource each interval, to be
+// synced like a camera's images.
+func newGetAudioCollector(res interface{}, params data.CollectorParams) (data.Collector, error) {
+	a, ok := res.(Audio)
+	if !ok {
+		return nil, data.InvalidInterfaceErr(API)
+	}
+	opts, err := collectorOptionsFrom(params.MethodParams)
+	if err != nil {
+		return nil, err
+	}
+	// the data manager only knows the camera methods capture binary data, so
+	// its capture files are relabelled before the first is written
+	params.DataType = data.CaptureTypeBinary
+	if buf, ok := params.Target.(*data.CaptureBuffer); ok && buf.MetaData != nil {
+		buf.MetaData.Type = data.CaptureTypeBinary.ToProto()
+		buf.MetaData.FileExtension = "." + opts.codec
+	}
+
+	capture := data.CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (data.CaptureResult, error) {
+		var result data.CaptureResult
+		requested := time.Now()
+		file, err := captureFile(ctx, a, opts)
+		if err != nil {
+			// filter modules drop captures with ErrNoCaptureToStore
+			if errors.Is(err, data.ErrNoCaptureToStore) {
+				return result, err
+			}
+			return result, data.NewFailedToReadError(params.ComponentName, getAudioMethod, err)
+		}
+		ts := data.Timestamps{TimeRequested: requested, TimeReceived: time.Now()}
+		return data.NewBinaryCaptureResult(ts, []data.Binary{{Payload: file}}), nil
+	})
+	return data.NewCollector(capture, params)
+}
+
+// captureFile captures opts.duration of a from the shared capture and
+// returns it as a file in opts.codec.
+func captureFile(ctx context.Context, a Audio, opts collectorOptions) ([]byte, error) {
+	chunks, err := sharedCaptureHub.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm32Float}, duration: opts.duration})
+	if err != nil {
+		return nil, err
+	}
+	var samples []float32
+	var info AudioInfo
+	for {
+		var chunk *AudioChunk
+		var ok bool
+		select {
+		case chunk, ok = <-chunks:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !ok {
+			break
+		}
+		if chunk.Err != nil {
+			return nil, chunk.Err
+		}
+		if chunk.Info == nil || chunk.Info.SampleRate == 0 || chunk.Info.Channels == 0 {
+			return nil, errUnknownSourceFormat
+		}
+		if info.SampleRate == 0 {
+			info = *chunk.Info
+		} else if chunk.Info.SampleRate != info.SampleRate || chunk.Info.Channels != info.Channels {
+			return nil, errors.New("the capture format changed during the clip")
+		}
+		s, err := decodePCM(chunk.AudioData, chunk.Info.Format)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, s...)
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("the capture ended without audio")
+	}
+
+	var b bytes.Buffer
+	switch opts.codec {
+	case "mp3":
+		// the last partial frame is dropped
+		frames, _ := newMP3Encoder(info.SampleRate, info.Channels).encode(samples)
+		b.Write(frames)
+	default:
+		pcm, err := encodePCM(samples, Pcm16)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeWAVHeader(&b, newWAVHeader(info.SampleRate, info.Channels, 16, uint32(len(pcm)))); err != nil {
+			return nil, err
+		}
+		b.Write(pcm)
+	}
+	return b.Bytes(), nil
+}
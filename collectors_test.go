@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCollectorOptions(t *testing.T) {
+	params := func(kv map[string]proto.Message) map[string]*anypb.Any {
+		out := map[string]*anypb.Any{}
+		for k, v := range kv {
+			a, err := anypb.New(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out[k] = a
+		}
+		return out
+	}
+	opts, err := collectorOptionsFrom(nil)
+	if err != nil || opts != (collectorOptions{duration: 5 * time.Second, codec: "wav"}) {
+		t.Errorf("defaults are %+v: %v", opts, err)
+	}
+	// as the data manager passes the strings of additional_params
+	opts, err = collectorOptionsFrom(params(map[string]proto.Message{
+		"duration_seconds": wrapperspb.Int64(10),
+		"codec":            wrapperspb.String("mp3"),
+	}))
+	if err != nil || opts != (collectorOptions{duration: 10 * time.Second, codec: "mp3"}) {
+		t.Errorf("parsed %+v: %v", opts, err)
+	}
+	if opts, err = collectorOptionsFrom(params(map[string]proto.Message{"duration_seconds": structpb.NewNumberValue(0.5)})); err != nil || opts.duration != 500*time.Millisecond {
+		t.Errorf("parsed %+v: %v", opts, err)
+	}
+	for _, bad := range []map[string]proto.Message{
+		{"duration_seconds": wrapperspb.Int64(0)},
+		{"duration_seconds": wrapperspb.Int64(61)},
+		{"duration_seconds": wrapperspb.String("long")},
+		{"codec": wrapperspb.String("flac")},
+	} {
+		if _, err := collectorOptionsFrom(params(bad)); err == nil {
+			t.Errorf("parsed %v", bad)
+		}
+	}
+}
+
+func TestCaptureFile(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(100, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	close(src.start)
+	file, err := captureFile(ctx, src, collectorOptions{duration: 50 * time.Millisecond, codec: "wav"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "clip.wav")
+	if err := os.WriteFile(path, file, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	samples, info, err := readAudioFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.SampleRate != 8000 || info.Channels != 1 || len(samples) != 400 {
+		t.Errorf("captured %d samples of %+v, want 50ms at 8kHz", len(samples), info)
+	}
+
+	src = newBurstSource(100, AudioInfo{Format: Pcm16, SampleRate: 48000, Channels: 1})
+	close(src.start)
+	if file, err = captureFile(ctx, src, collectorOptions{duration: 100 * time.Millisecond, codec: "mp3"}); err != nil {
+		t.Fatal(err)
+	}
+	// frame sync, MPEG-1 layer III
+	if len(file) < 2 || file[0] != 0xff || file[1]&0xfe != 0xfa {
+		t.Errorf("captured % x..., want mp3 frames", file[:min(4, len(file))])
+	}
+}
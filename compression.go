@@ -0,0 +1,89 @@
+package audio
+
+import (
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// Wire compressions for GetAudio streams, see WithCompression. They apply
+// to the gRPC messages whatever the codec, so they pay off most on raw PCM.
+const (
+	// GzipCompression shrinks PCM the most, at the most CPU.
+	GzipCompression = gzip.Name
+	// SnappyCompression is cheap enough for the smallest boards.
+	SnappyCompression = "snappy"
+)
+
+// compressions lists the stream compressions the server accepts.
+var compressions = []string{GzipCompression, SnappyCompression}
+
+func init() {
+	encoding.RegisterCompressor(&snappyCompressor{})
+}
+
+func checkCompression(name string) error {
+	if name != "" && !slices.Contains(compressions, name) {
+		return errorf(ErrInvalidArgument, "unknown compression %q, expected one of %v", name, compressions)
+	}
+	return nil
+}
+
+// snappyCompressor is the gRPC compressor for snappy's framing format. Its
+// writers and readers are pooled, as every message takes one of each.
+type snappyCompressor struct {
+	writers, readers sync.Pool
+}
+
+func (c *snappyCompressor) Name() string {
+	return SnappyCompression
+}
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	sw, ok := c.writers.Get().(*snappyWriter)
+	if !ok {
+		sw = &snappyWriter{pool: &c.writers, Writer: snappy.NewBufferedWriter(w)}
+	} else {
+		sw.Reset(w)
+	}
+	return sw, nil
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	sr, ok := c.readers.Get().(*snappyReader)
+	if !ok {
+		sr = &snappyReader{pool: &c.readers, Reader: snappy.NewReader(r)}
+	} else {
+		sr.Reset(r)
+	}
+	return sr, nil
+}
+
+type snappyWriter struct {
+	*snappy.Writer
+	pool *sync.Pool
+}
+
+// Close flushes the message and returns the writer to the pool.
+func (w *snappyWriter) Close() error {
+	defer w.pool.Put(w)
+	return w.Writer.Close()
+}
+
+type snappyReader struct {
+	*snappy.Reader
+	pool *sync.Pool
+}
+
+// Read returns the reader to the pool once the message is read.
+func (r *snappyReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r)
+	}
+	return n, err
+}
@@ -0,0 +1,80 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/encoding"
+)
+
+func TestSnappyCompressor(t *testing.T) {
+	c := encoding.GetCompressor(SnappyCompression)
+	want, _ := encodePCM(tone(8000, 8000, 440, 0.5), Pcm16)
+	// writers and readers are reused from one message to the next
+	for range 3 {
+		var buf bytes.Buffer
+		w, err := c.Compress(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(want); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		r, err := c.Decompress(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("round trip gave %d bytes, want %d", len(got), len(want))
+		}
+	}
+}
+
+func TestStreamCompression(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(10, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 { return tone(8000, 80, 300+float64(i)*50, 0.5) }
+	var want []byte
+	for i := range src.n {
+		data, _ := encodePCM(src.samples(i), Pcm16)
+		want = append(want, data...)
+	}
+	c := serveAudio(t, src)
+	close(src.start)
+
+	for _, name := range []string{GzipCompression, SnappyCompression} {
+		ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithCompression(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []byte
+		for chunk := range ch {
+			if chunk.Err != nil {
+				t.Fatal(chunk.Err)
+			}
+			got = append(got, chunk.AudioData...)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s stream has %d bytes, want the %d captured", name, len(got), len(want))
+		}
+	}
+
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithCompression("zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || chunk.Err == nil {
+		t.Error("streamed with an unknown compression")
+	}
+}
@@ -0,0 +1,118 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// CoreAudioModel captures and plays through CoreAudio on macOS, so a
+// laptop's microphone and speakers can stand in for a robot's while
+// developing. CoreAudio ships with the OS, so every macOS build with cgo
+// supports it; other builds know the model but fail to construct it.
+var CoreAudioModel = resource.NewModel("olivia", "audio", "coreaudio")
+
+// Defaults for CoreAudioConfig.
+const (
+	defaultCoreAudioBuffer = 20 * time.Millisecond
+	// coreAudioDefaultDevice is the device of a direction following the
+	// system's default input or output
+	coreAudioDefaultDevice = "default"
+)
+
+// CoreAudioConfig is the configuration of the coreaudio model. Devices are
+// names as shown in Audio MIDI Setup, or device UIDs.
+type CoreAudioConfig struct {
+	// CaptureDevice and PlaybackDevice follow the system default if empty,
+	// including when it changes; "none" turns a direction off.
+	CaptureDevice    string `json:"capture_device,omitempty"`
+	PlaybackDevice   string `json:"playback_device,omitempty"`
+	SampleRate       int    `json:"sample_rate,omitempty"`       // 48 kHz if zero
+	Channels         int    `json:"channels,omitempty"`          // capture channels, 1 if zero
+	PlaybackChannels int    `json:"playback_channels,omitempty"` // 2 if zero
+	BufferMs         int    `json:"buffer_ms,omitempty"`         // per queued buffer, 20ms if zero
+}
+
+// Validate checks the coreaudio configuration.
+func (c *CoreAudioConfig) Validate(path string) ([]string, []string, error) {
+	if c.SampleRate < 0 || c.Channels < 0 || c.PlaybackChannels < 0 || c.BufferMs < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate, channels, playback_channels and buffer_ms cannot be negative"))
+	}
+	if c.CaptureDevice == noPCMDevice && c.PlaybackDevice == noPCMDevice {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("capture_device and playback_device cannot both be none"))
+	}
+	return nil, nil, nil
+}
+
+// openCoreAudio opens an audio queue on a device, set by builds with
+// CoreAudio support. An empty device is the system default.
+var openCoreAudio func(device string, capture bool, p pcmParams) (pcmDevice, error)
+
+func init() {
+	registerModel(CoreAudioModel, resource.Registration[Audio, *CoreAudioConfig]{
+		AttributeMapConverter: migratingConverter[*CoreAudioConfig](CoreAudioModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*CoreAudioConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewCoreAudio(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+// NewCoreAudio returns a resource on the configured CoreAudio devices.
+func NewCoreAudio(name resource.Name, cfg CoreAudioConfig, logger logging.Logger) (Audio, error) {
+	if openCoreAudio == nil {
+		return nil, errors.New("this build has no CoreAudio support, build on macOS with cgo")
+	}
+	rate := cfg.SampleRate
+	if rate == 0 {
+		rate = defaultPCMSampleRate
+	}
+	channels, playbackChannels := cfg.Channels, cfg.PlaybackChannels
+	if channels == 0 {
+		channels = defaultPCMChannels
+	}
+	if playbackChannels == 0 {
+		playbackChannels = defaultPCMPlaybackChannels
+	}
+	buffer := time.Duration(cfg.BufferMs) * time.Millisecond
+	if buffer == 0 {
+		buffer = defaultCoreAudioBuffer
+	}
+	period := max(1, int(buffer.Seconds()*float64(rate)))
+
+	a := &pcmAudio{
+		Named:   name.AsNamed(),
+		backend: "coreaudio",
+		open: func(device string, capture bool, p pcmParams) (pcmDevice, error) {
+			if device == coreAudioDefaultDevice {
+				device = ""
+			}
+			return openCoreAudio(device, capture, p)
+		},
+		capture:        coreAudioDevice(cfg.CaptureDevice),
+		playback:       coreAudioDevice(cfg.PlaybackDevice),
+		captureParams:  pcmParams{rate: rate, channels: channels, periodFrames: period, bufferFrames: 4 * period},
+		playbackParams: pcmParams{rate: rate, channels: playbackChannels, periodFrames: period, bufferFrames: 4 * period},
+		logger:         logger,
+		readers:        map[chan pcmBlock]struct{}{},
+	}
+	return a, nil
+}
+
+// coreAudioDevice returns the device for one direction, empty for none.
+func coreAudioDevice(configured string) string {
+	switch configured {
+	case noPCMDevice:
+		return ""
+	case "":
+		return coreAudioDefaultDevice
+	default:
+		return configured
+	}
+}
@@ -0,0 +1,379 @@
+//go:build darwin && cgo
+
+package audio
+
+/*
+#cgo LDFLAGS: -framework AudioToolbox -framework CoreAudio -framework CoreFoundation
+#include <pthread.h>
+#include <stdlib.h>
+#include <string.h>
+#include <AudioToolbox/AudioToolbox.h>
+#include <CoreAudio/CoreAudio.h>
+
+// results of vca_read besides OSStatus errors
+#define VCA_OVERRUN 1
+#define VCA_STOPPED 2
+
+#define VCA_BUFFERS 3
+
+// vca is an audio queue with a ring buffer between its callbacks, which run
+// on the queue's own thread, and blocking reads and writes.
+typedef struct {
+	AudioQueueRef queue;
+	AudioQueueBufferRef bufs[VCA_BUFFERS];
+	pthread_mutex_t mu;
+	pthread_cond_t cond;
+	uint8_t *ring;
+	size_t size, head, fill, frame;
+	int capture, overrun, stopped, closing;
+} vca;
+
+static void ring_put(vca *v, const uint8_t *p, size_t n) {
+	size_t tail = (v->head + v->fill) % v->size;
+	size_t first = n < v->size - tail ? n : v->size - tail;
+	memcpy(v->ring + tail, p, first);
+	memcpy(v->ring, p + first, n - first);
+	v->fill += n;
+}
+
+static void ring_get(vca *v, uint8_t *p, size_t n) {
+	size_t first = n < v->size - v->head ? n : v->size - v->head;
+	memcpy(p, v->ring + v->head, first);
+	memcpy(p + first, v->ring, n - first);
+	v->head = (v->head + n) % v->size;
+	v->fill -= n;
+}
+
+static void on_input(void *data, AudioQueueRef q, AudioQueueBufferRef buf, const AudioTimeStamp *start,
+		UInt32 packets, const AudioStreamPacketDescription *desc) {
+	vca *v = data;
+	pthread_mutex_lock(&v->mu);
+	size_t n = buf->mAudioDataByteSize;
+	if (n > v->size - v->fill) {
+		v->overrun = 1;
+		n = v->size - v->fill;
+	}
+	n -= n % v->frame;
+	ring_put(v, buf->mAudioData, n);
+	int closing = v->closing;
+	pthread_cond_broadcast(&v->cond);
+	pthread_mutex_unlock(&v->mu);
+	if (!closing) {
+		AudioQueueEnqueueBuffer(q, buf, 0, NULL);
+	}
+}
+
+static void fill_output(vca *v, AudioQueueBufferRef buf) {
+	size_t n = buf->mAudioDataBytesCapacity;
+	n -= n % v->frame;
+	// the queue keeps running between clips, so running dry is silence
+	size_t have = n < v->fill ? n : v->fill;
+	ring_get(v, buf->mAudioData, have);
+	memset((uint8_t *)buf->mAudioData + have, 0, n - have);
+	buf->mAudioDataByteSize = n;
+}
+
+static void on_output(void *data, AudioQueueRef q, AudioQueueBufferRef buf) {
+	vca *v = data;
+	pthread_mutex_lock(&v->mu);
+	fill_output(v, buf);
+	int closing = v->closing;
+	pthread_cond_broadcast(&v->cond);
+	pthread_mutex_unlock(&v->mu);
+	if (!closing) {
+		AudioQueueEnqueueBuffer(q, buf, 0, NULL);
+	}
+}
+
+// on_running notices the queue stopping on its own, as it does when its
+// device goes away.
+static void on_running(void *data, AudioQueueRef q, AudioQueuePropertyID id) {
+	vca *v = data;
+	UInt32 running = 0, size = sizeof(running);
+	AudioQueueGetProperty(q, kAudioQueueProperty_IsRunning, &running, &size);
+	pthread_mutex_lock(&v->mu);
+	if (!running && !v->closing) {
+		v->stopped = 1;
+	}
+	pthread_cond_broadcast(&v->cond);
+	pthread_mutex_unlock(&v->mu);
+}
+
+static CFStringRef copy_string_property(AudioObjectID id, AudioObjectPropertySelector sel) {
+	AudioObjectPropertyAddress addr = {sel, kAudioObjectPropertyScopeGlobal, kAudioObjectPropertyElementMain};
+	CFStringRef s = NULL;
+	UInt32 size = sizeof(s);
+	if (AudioObjectGetPropertyData(id, &addr, 0, NULL, &size, &s) != noErr) {
+		return NULL;
+	}
+	return s;
+}
+
+// vca_find_uid returns the UID of the device named or identified by want,
+// or NULL if there is none.
+static CFStringRef vca_find_uid(const char *want) {
+	AudioObjectPropertyAddress addr = {kAudioHardwarePropertyDevices, kAudioObjectPropertyScopeGlobal, kAudioObjectPropertyElementMain};
+	UInt32 size = 0;
+	if (AudioObjectGetPropertyDataSize(kAudioObjectSystemObject, &addr, 0, NULL, &size) != noErr) {
+		return NULL;
+	}
+	AudioObjectID *ids = malloc(size);
+	if (ids == NULL || AudioObjectGetPropertyData(kAudioObjectSystemObject, &addr, 0, NULL, &size, ids) != noErr) {
+		free(ids);
+		return NULL;
+	}
+	CFStringRef w = CFStringCreateWithCString(NULL, want, kCFStringEncodingUTF8);
+	CFStringRef found = NULL;
+	for (UInt32 i = 0; i < size / sizeof(AudioObjectID) && found == NULL; i++) {
+		CFStringRef uid = copy_string_property(ids[i], kAudioDevicePropertyDeviceUID);
+		CFStringRef name = copy_string_property(ids[i], kAudioObjectPropertyName);
+		if (uid != NULL && (CFEqual(uid, w) || (name != NULL && CFEqual(name, w)))) {
+			found = CFRetain(uid);
+		}
+		if (uid != NULL) {
+			CFRelease(uid);
+		}
+		if (name != NULL) {
+			CFRelease(name);
+		}
+	}
+	CFRelease(w);
+	free(ids);
+	return found;
+}
+
+static void vca_free(vca *v) {
+	if (v->queue != NULL) {
+		pthread_mutex_lock(&v->mu);
+		v->closing = 1;
+		pthread_mutex_unlock(&v->mu);
+		AudioQueueStop(v->queue, true);
+		AudioQueueDispose(v->queue, true);
+	}
+	pthread_cond_destroy(&v->cond);
+	pthread_mutex_destroy(&v->mu);
+	free(v->ring);
+	free(v);
+}
+
+static OSStatus vca_open(vca **out, const char *device, int capture, int rate, int channels, int period, int buffer) {
+	vca *v = calloc(1, sizeof(vca));
+	if (v == NULL) {
+		return kAudio_MemFullError;
+	}
+	pthread_mutex_init(&v->mu, NULL);
+	pthread_cond_init(&v->cond, NULL);
+	v->capture = capture;
+	v->frame = 2 * channels;
+	v->size = (size_t)buffer * v->frame;
+	v->ring = malloc(v->size);
+	if (v->ring == NULL) {
+		vca_free(v);
+		return kAudio_MemFullError;
+	}
+
+	AudioStreamBasicDescription f = {0};
+	f.mSampleRate = rate;
+	f.mFormatID = kAudioFormatLinearPCM;
+	f.mFormatFlags = kLinearPCMFormatFlagIsSignedInteger | kLinearPCMFormatFlagIsPacked;
+	f.mBitsPerChannel = 16;
+	f.mChannelsPerFrame = channels;
+	f.mFramesPerPacket = 1;
+	f.mBytesPerFrame = v->frame;
+	f.mBytesPerPacket = v->frame;
+	// a NULL run loop has the queue call back on a thread of its own
+	OSStatus st = capture ? AudioQueueNewInput(&f, on_input, v, NULL, NULL, 0, &v->queue)
+			: AudioQueueNewOutput(&f, on_output, v, NULL, NULL, 0, &v->queue);
+	if (st != noErr) {
+		v->queue = NULL;
+		vca_free(v);
+		return st;
+	}
+	if (device[0] != '\0') {
+		CFStringRef uid = vca_find_uid(device);
+		if (uid == NULL) {
+			vca_free(v);
+			return kAudioHardwareBadDeviceError;
+		}
+		st = AudioQueueSetProperty(v->queue, kAudioQueueProperty_CurrentDevice, &uid, sizeof(uid));
+		CFRelease(uid);
+		if (st != noErr) {
+			vca_free(v);
+			return st;
+		}
+	}
+	AudioQueueAddPropertyListener(v->queue, kAudioQueueProperty_IsRunning, on_running, v);
+	for (int i = 0; i < VCA_BUFFERS; i++) {
+		if ((st = AudioQueueAllocateBuffer(v->queue, period * v->frame, &v->bufs[i])) != noErr) {
+			vca_free(v);
+			return st;
+		}
+		if (!capture) {
+			fill_output(v, v->bufs[i]);
+		}
+		AudioQueueEnqueueBuffer(v->queue, v->bufs[i], 0, NULL);
+	}
+	if ((st = AudioQueueStart(v->queue, NULL)) != noErr) {
+		vca_free(v);
+		return st;
+	}
+	*out = v;
+	return noErr;
+}
+
+// vca_read fills p, or reports an overrun since the last recovery or the
+// queue stopping.
+static int vca_read(vca *v, uint8_t *p, size_t n) {
+	int res = 0;
+	pthread_mutex_lock(&v->mu);
+	while (v->fill < n && !v->stopped && !v->overrun) {
+		pthread_cond_wait(&v->cond, &v->mu);
+	}
+	if (v->stopped) {
+		res = VCA_STOPPED;
+	} else if (v->overrun) {
+		res = VCA_OVERRUN;
+	} else {
+		ring_get(v, p, n);
+	}
+	pthread_mutex_unlock(&v->mu);
+	return res;
+}
+
+// vca_write queues as much of p as fits once there is room, and returns
+// how much that was, or -1 if the queue stopped.
+static long vca_write(vca *v, const uint8_t *p, size_t n) {
+	long res;
+	pthread_mutex_lock(&v->mu);
+	while (v->fill == v->size && !v->stopped) {
+		pthread_cond_wait(&v->cond, &v->mu);
+	}
+	if (v->stopped) {
+		res = -1;
+	} else {
+		size_t m = n < v->size - v->fill ? n : v->size - v->fill;
+		m -= m % v->frame;
+		ring_put(v, p, m);
+		res = m;
+	}
+	pthread_mutex_unlock(&v->mu);
+	return res;
+}
+
+static int vca_drain(vca *v) {
+	int res = 0;
+	pthread_mutex_lock(&v->mu);
+	while (v->fill > 0 && !v->stopped) {
+		pthread_cond_wait(&v->cond, &v->mu);
+	}
+	if (v->stopped) {
+		res = VCA_STOPPED;
+	}
+	pthread_mutex_unlock(&v->mu);
+	return res;
+}
+
+// vca_recover drops the capture buffered before an overrun.
+static void vca_recover(vca *v) {
+	pthread_mutex_lock(&v->mu);
+	v->overrun = 0;
+	v->head = 0;
+	v->fill = 0;
+	pthread_mutex_unlock(&v->mu);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+func init() {
+	openCoreAudio = openAudioQueue
+}
+
+var (
+	errCoreAudioOverrun = errors.New("capture overrun")
+	errCoreAudioStopped = errors.New("the audio queue stopped, the device may be gone")
+)
+
+// coreAudioError is an OSStatus from CoreAudio.
+type coreAudioError C.OSStatus
+
+func (e coreAudioError) Error() string {
+	// most are four character codes
+	code := uint32(e)
+	b := []byte{byte(code >> 24), byte(code >> 16), byte(code >> 8), byte(code)}
+	for _, c := range b {
+		if c < ' ' || c > '~' {
+			return fmt.Sprintf("coreaudio error %d", int32(e))
+		}
+	}
+	return fmt.Sprintf("coreaudio error '%s'", b)
+}
+
+// audioQueue is an AudioQueue on a device. It recovers from overruns
+// itself and can't recover from its device going away.
+type audioQueue struct {
+	v        *C.vca
+	channels int
+}
+
+func openAudioQueue(device string, capture bool, p pcmParams) (pcmDevice, error) {
+	cdev := C.CString(device)
+	defer C.free(unsafe.Pointer(cdev))
+	c := C.int(0)
+	if capture {
+		c = 1
+	}
+	var v *C.vca
+	if st := C.vca_open(&v, cdev, c, C.int(p.rate), C.int(p.channels), C.int(p.periodFrames), C.int(p.bufferFrames)); st != C.noErr {
+		return nil, coreAudioError(st)
+	}
+	return &audioQueue{v: v, channels: p.channels}, nil
+}
+
+func readResult(res C.int) error {
+	switch res {
+	case 0:
+		return nil
+	case C.VCA_OVERRUN:
+		return errCoreAudioOverrun
+	default:
+		return errCoreAudioStopped
+	}
+}
+
+func (q *audioQueue) read(buf []int16) (int, error) {
+	if err := readResult(C.vca_read(q.v, (*C.uint8_t)(unsafe.Pointer(&buf[0])), C.size_t(2*len(buf)))); err != nil {
+		return 0, err
+	}
+	return len(buf) / q.channels, nil
+}
+
+func (q *audioQueue) write(buf []int16) (int, error) {
+	res := C.vca_write(q.v, (*C.uint8_t)(unsafe.Pointer(&buf[0])), C.size_t(2*len(buf)))
+	if res < 0 {
+		return 0, errCoreAudioStopped
+	}
+	return int(res) / (2 * q.channels), nil
+}
+
+func (q *audioQueue) recover(err error) error {
+	if !errors.Is(err, errCoreAudioOverrun) {
+		return err
+	}
+	C.vca_recover(q.v)
+	return nil
+}
+
+func (q *audioQueue) drain() error {
+	return readResult(C.vca_drain(q.v))
+}
+
+func (q *audioQueue) close() error {
+	C.vca_free(q.v)
+	return nil
+}
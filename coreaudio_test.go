@@ -0,0 +1,89 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestCoreAudioDevices(t *testing.T) {
+	var mu sync.Mutex
+	opened := map[bool]string{}
+	var played *fakePCM
+	prev := openCoreAudio
+	openCoreAudio = func(device string, capture bool, p pcmParams) (pcmDevice, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		opened[capture] = device
+		f := &fakePCM{p: p, period: time.Duration(p.periodFrames) * time.Second / time.Duration(p.rate), fail: map[int]bool{}}
+		if !capture {
+			played = f
+		}
+		return f, nil
+	}
+	defer func() { openCoreAudio = prev }()
+
+	a, err := NewCoreAudio(Named("laptop"), CoreAudioConfig{PlaybackDevice: "External Headphones", SampleRate: 44100, BufferMs: 10}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), 0.25, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames int
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatal(c.Err)
+		}
+		frames += len(c.AudioData) / 2
+	}
+	if frames != 11025 {
+		t.Errorf("captured %d frames, want 11025", frames)
+	}
+	clip, _ := encodePCM(tone(44100, 441, 440, 0.5), Pcm16)
+	if err := a.Play(ctx, clip, Pcm16.String(), 44100, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// capture follows the system default
+	if opened[true] != "" || opened[false] != "External Headphones" {
+		t.Errorf("opened %q for capture and %q for playback", opened[true], opened[false])
+	}
+	played.mu.Lock()
+	defer played.mu.Unlock()
+	if len(played.written) != 2*441 || played.drained != 1 {
+		t.Errorf("played %d samples and drained %d times, want 882 and once", len(played.written), played.drained)
+	}
+}
+
+func TestCoreAudioConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg CoreAudioConfig
+		ok  bool
+	}{
+		{CoreAudioConfig{}, true},
+		{CoreAudioConfig{CaptureDevice: "MacBook Pro Microphone", PlaybackDevice: "none"}, true},
+		{CoreAudioConfig{CaptureDevice: "none", PlaybackDevice: "none"}, false},
+		{CoreAudioConfig{BufferMs: -5}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+
+	prev := openCoreAudio
+	openCoreAudio = nil
+	defer func() { openCoreAudio = prev }()
+	if _, err := NewCoreAudio(Named("laptop"), CoreAudioConfig{}, logging.NewTestLogger(t)); err == nil {
+		t.Error("built without CoreAudio support")
+	}
+}
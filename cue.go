@@ -0,0 +1,103 @@
+package audio
+
+import (
+	"context"
+	"time"
+
+	"go.viam.com/rdk/resource"
+)
+
+// defaultCueLead is how far ahead PlayWithAction schedules its trigger by
+// default, enough for the commits to reach a resource on the local network.
+const defaultCueLead = 100 * time.Millisecond
+
+// A Cue is a clip played in step with an action by PlayWithAction.
+type Cue struct {
+	Data       []byte
+	Codec      string
+	SampleRate int
+	Channels   int
+	// Offset starts the clip this long after the action is started, or
+	// before it if negative, e.g. to line a sound up with the moment an arm
+	// actually starts moving rather than when the move is requested.
+	Offset time.Duration
+}
+
+// CueOptions tunes PlayWithAction.
+type CueOptions struct {
+	// Lead is how long after the cues are prepared the trigger fires. It has
+	// to cover the commit round trip. 100ms if zero.
+	Lead time.Duration
+	// Clock is the clock the trigger is read on, which must be the one the
+	// resource plays on. SystemClock if nil.
+	Clock ClockSource
+}
+
+// PlayWithAction prepares every cue on p, then starts action and commits the
+// cues against one shared trigger time, so choreographed audio and motion
+// stay in sync without hand-tuned sleeps. The action is called at the trigger
+// and each cue starts at the trigger plus its offset. It returns once the
+// action and every cue have finished; the first error cancels the rest.
+func PlayWithAction(ctx context.Context, p PlaybackPreparer, action func(context.Context) error, opts CueOptions, cues ...Cue) error {
+	if opts.Lead == 0 {
+		opts.Lead = defaultCueLead
+	}
+	if opts.Clock == nil {
+		opts.Clock = SystemClock
+	}
+
+	handles := make([]string, 0, len(cues))
+	release := func() {
+		for _, h := range handles {
+			// a handle that was already committed is gone, so errors are expected
+			_ = p.ReleasePlayback(context.Background(), h)
+		}
+	}
+	var earliest time.Duration
+	for _, cue := range cues {
+		h, err := p.PreparePlayback(ctx, cue.Data, cue.Codec, cue.SampleRate, cue.Channels)
+		if err != nil {
+			release()
+			return err
+		}
+		handles = append(handles, h)
+		earliest = min(earliest, cue.Offset)
+	}
+	// a cue leading the action still gets the full lead
+	trigger := opts.Clock.Now().Add(opts.Lead - earliest)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make(chan error, len(cues)+1)
+	for i, cue := range cues {
+		go func() { errs <- p.CommitPlayback(ctx, handles[i], trigger.Add(cue.Offset)) }()
+	}
+	go func() {
+		if err := opts.Clock.Sleep(ctx, trigger.Sub(opts.Clock.Now())); err != nil {
+			errs <- err
+			return
+		}
+		errs <- action(ctx)
+	}()
+
+	var first error
+	for range len(cues) + 1 {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+			cancel()
+		}
+	}
+	if first != nil {
+		release()
+	}
+	return first
+}
+
+// DoCommandAction returns an action for PlayWithAction that sends cmd to r,
+// for resources whose motion is started through DoCommand.
+func DoCommandAction(r resource.Resource, cmd map[string]interface{}) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := r.DoCommand(ctx, cmd)
+		return err
+	}
+}
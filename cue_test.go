@@ -0,0 +1,108 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestPlayWithActionSharesTrigger(t *testing.T) {
+	const (
+		rate    = 16000
+		latency = 20 * time.Millisecond
+		lead    = 50 * time.Millisecond
+	)
+	clock := NewManualClock(time.Unix(1000, 0))
+	a := NewLoopback(Named("loop"), LoopbackConfig{SampleRate: rate, LatencyMs: int(latency / time.Millisecond), Clock: clock}, logging.NewTestLogger(t))
+	defer a.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm32Float.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clip := func(v float32, offset time.Duration) Cue {
+		samples := make([]float32, rate/100)
+		for i := range samples {
+			samples[i] = v
+		}
+		data, _ := encodePCM(samples, Pcm32Float)
+		return Cue{Data: data, Codec: Pcm32Float.String(), SampleRate: rate, Channels: 1, Offset: offset}
+	}
+	// one cue leads the action, one follows it
+	cues := []Cue{clip(0.5, -20*time.Millisecond), clip(-0.5, 30*time.Millisecond)}
+	trigger := clock.Now().Add(lead + 20*time.Millisecond)
+
+	var acted time.Time
+	done := make(chan error, 1)
+	go func() {
+		done <- PlayWithAction(ctx, a.(PlaybackPreparer), func(context.Context) error {
+			acted = clock.Now()
+			return nil
+		}, CueOptions{Lead: lead, Clock: clock}, cues...)
+	}()
+	waitForSleeper(t, clock, trigger)
+	stop := runClock(clock, time.Millisecond)
+	defer stop()
+
+	starts := map[float32]time.Time{}
+	for len(starts) < len(cues) {
+		var chunk *AudioChunk
+		select {
+		case chunk = <-chunks:
+		case <-ctx.Done():
+			t.Fatal("cues never played")
+		}
+		samples, err := decodePCM(chunk.AudioData, Pcm32Float)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, s := range samples {
+			if _, ok := starts[s]; s != 0 && !ok {
+				starts[s] = chunk.Timestamp.Add(time.Duration(i) * time.Second / rate)
+			}
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if d := acted.Sub(trigger); d < 0 || d > 10*time.Millisecond {
+		t.Fatalf("action started %v after the trigger", d)
+	}
+	for _, cue := range []struct {
+		value  float32
+		offset time.Duration
+	}{{0.5, -20 * time.Millisecond}, {-0.5, 30 * time.Millisecond}} {
+		want := trigger.Add(cue.offset + latency)
+		if d := starts[cue.value].Sub(want); d < 0 || d >= time.Second/rate {
+			t.Fatalf("cue at offset %v heard %v after it was due", cue.offset, d)
+		}
+	}
+}
+
+func TestPlayWithActionFailure(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	a := NewLoopback(Named("loop"), LoopbackConfig{SampleRate: 16000, Clock: clock}, logging.NewTestLogger(t))
+	defer a.Close(context.Background())
+	defer runClock(clock, time.Millisecond)()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	data, _ := encodePCM(make([]float32, 1600), Pcm16)
+	failed := errors.New("arm is e-stopped")
+	err := PlayWithAction(ctx, a.(PlaybackPreparer), func(context.Context) error { return failed },
+		CueOptions{Clock: clock}, Cue{Data: data, Codec: Pcm16.String(), SampleRate: 16000, Channels: 1, Offset: time.Second})
+	if !errors.Is(err, failed) {
+		t.Fatalf("got %v, want the action's error", err)
+	}
+	l := a.(*loopback)
+	l.prepared.mu.Lock()
+	defer l.prepared.mu.Unlock()
+	if len(l.prepared.clips) != 0 {
+		t.Fatalf("%d prepared clips left behind", len(l.prepared.clips))
+	}
+}
@@ -0,0 +1,274 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limits of the debugging commands, which are meant to be safe to run on a
+// robot in production from the Viam app.
+const (
+	maxSampleLevels      = 5 * time.Second
+	defaultTestToneLevel = -30.0 // dBFS peak
+	maxTestToneLevel     = -20.0
+	maxTestToneDuration  = 3 * time.Second
+	testToneRate         = 48000
+	testToneFade         = 20 * time.Millisecond
+	defaultChunkLogging  = time.Minute
+	maxChunkLogging      = 5 * time.Minute
+)
+
+// doBuiltinCommand handles the commands every built-in model accepts
+// through DoCommand: tone injection and the debugging toolbox.
+func doBuiltinCommand(ctx context.Context, a Audio, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if resp, ok, err := doInjectCommand(a, cmd); ok {
+		return resp, ok, err
+	}
+	return doDebugCommand(ctx, a, cmd)
+}
+
+// doDebugCommand handles the commands support uses to triage audio without
+// a shell on the robot, and reports whether cmd was one of them:
+//
+//	{"list_devices": true}
+//	{"sample_levels": {"seconds": 1}}
+//	{"play_test_tone": {"frequency_hz": 440, "level_dbfs": -30, "duration_seconds": 1}}
+//	{"log_chunks": {"seconds": 60}}
+//
+// None of them change the resource's configuration. Levels are sampled from
+// the shared capture, at most 5s of it, and test tones are at most 3s long
+// and no louder than -20 dBFS. log_chunks has the server log every chunk it
+// sends of the resource's GetAudio streams, for at most 5 minutes; 0
+// seconds turns it back off.
+func doDebugCommand(ctx context.Context, a Audio, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	switch {
+	case cmd["list_devices"] != nil:
+		resp, err := listDevicesCommand(a)
+		return resp, true, err
+	case cmd["sample_levels"] != nil:
+		params, err := commandParams(cmd, "sample_levels")
+		if err != nil {
+			return nil, true, err
+		}
+		resp, err := sampleLevelsCommand(ctx, a, params)
+		return resp, true, err
+	case cmd["play_test_tone"] != nil:
+		params, err := commandParams(cmd, "play_test_tone")
+		if err != nil {
+			return nil, true, err
+		}
+		resp, err := playTestToneCommand(ctx, a, params)
+		return resp, true, err
+	case cmd["log_chunks"] != nil:
+		params, err := commandParams(cmd, "log_chunks")
+		if err != nil {
+			return nil, true, err
+		}
+		resp, err := logChunksCommand(a, params)
+		return resp, true, err
+	default:
+		return nil, false, nil
+	}
+}
+
+// commandFlag reports whether a command that takes no parameters was given
+// as true; {"name": false} doesn't run it.
+func commandFlag(cmd map[string]interface{}, name string) bool {
+	on, _ := cmd[name].(bool)
+	return on
+}
+
+// commandParams returns the object a command was given, empty for true.
+func commandParams(cmd map[string]interface{}, name string) (map[string]interface{}, error) {
+	switch v := cmd[name].(type) {
+	case map[string]interface{}:
+		return v, nil
+	case bool:
+		return map[string]interface{}{}, nil
+	default:
+		return nil, errorf(ErrInvalidArgument, "%s takes an object, got %T", name, v)
+	}
+}
+
+// commandNumber returns params[key], def if it isn't set.
+func commandNumber(params map[string]interface{}, name, key string, def float64) (float64, error) {
+	v, ok := params[key]
+	if !ok {
+		return def, nil
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, errorf(ErrInvalidArgument, "%s %s must be a number, got %T", name, key, v)
+	}
+	return f, nil
+}
+
+// chunkLogging holds until when the chunks of each resource's GetAudio
+// streams are logged, by resource name. The log is too busy to be on for
+// long, so it turns itself off.
+type chunkLogging struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+var sharedChunkLogging = &chunkLogging{until: map[string]time.Time{}}
+
+// set logs the chunks of name's streams for d from now, none if d is zero.
+func (l *chunkLogging) set(name string, d time.Duration) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d <= 0 {
+		delete(l.until, name)
+		return time.Time{}
+	}
+	l.until[name] = time.Now().Add(d)
+	return l.until[name]
+}
+
+func (l *chunkLogging) enabled(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until, ok := l.until[name]
+	if ok && time.Now().After(until) {
+		delete(l.until, name)
+		return false
+	}
+	return ok
+}
+
+func logChunksCommand(a Audio, params map[string]interface{}) (map[string]interface{}, error) {
+	seconds, err := commandNumber(params, "log_chunks", "seconds", defaultChunkLogging.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	d := time.Duration(seconds * float64(time.Second))
+	if d < 0 || d > maxChunkLogging {
+		return nil, errorf(ErrInvalidArgument, "log_chunks seconds must be from 0 to %v", maxChunkLogging.Seconds())
+	}
+	until := sharedChunkLogging.set(a.Name().ShortName(), d)
+	if until.IsZero() {
+		return map[string]interface{}{"logging": false}, nil
+	}
+	return map[string]interface{}{"logging": true, "until": until.Format(time.RFC3339)}, nil
+}
+
+func listDevicesCommand(a Audio) (map[string]interface{}, error) {
+	devices, err := resourceDevices(a)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]interface{}, len(devices.devices))
+	for i, d := range devices.devices {
+		list[i] = map[string]interface{}{
+			"id":       d.ID,
+			"name":     d.Name,
+			"driver":   d.Driver,
+			"kind":     d.kind().String(),
+			"capture":  d.Capture,
+			"playback": d.Playback,
+		}
+	}
+	resp := map[string]interface{}{"devices": list}
+	if devices.defaultCapture != "" {
+		resp["default_capture"] = devices.defaultCapture
+	}
+	if devices.defaultPlayback != "" {
+		resp["default_playback"] = devices.defaultPlayback
+	}
+	if devices.captureReason != "" {
+		resp["default_capture_reason"] = devices.captureReason
+	}
+	if devices.playbackReason != "" {
+		resp["default_playback_reason"] = devices.playbackReason
+	}
+	return resp, nil
+}
+
+func sampleLevelsCommand(ctx context.Context, a Audio, params map[string]interface{}) (map[string]interface{}, error) {
+	seconds, err := commandNumber(params, "sample_levels", "seconds", 1)
+	if err != nil {
+		return nil, err
+	}
+	window := time.Duration(seconds * float64(time.Second))
+	if window <= 0 || window > maxSampleLevels {
+		return nil, errorf(ErrInvalidArgument, "sample_levels seconds must be positive and at most %v", maxSampleLevels.Seconds())
+	}
+	// a silent device delivers nothing, which is the answer rather than a hang
+	ctx, cancel := context.WithTimeout(ctx, window+5*time.Second)
+	defer cancel()
+	levels, err := sharedCaptureHub.meterLevels(ctx, a, window)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := <-levels
+	if !ok {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("no audio captured in %v", window+5*time.Second)
+		}
+		return nil, errors.New("capture ended before the sample was taken")
+	}
+	if l.Err != nil {
+		return nil, l.Err
+	}
+	channels := make([]interface{}, len(l.Channels))
+	for i, c := range l.Channels {
+		channels[i] = map[string]interface{}{"rms_dbfs": finiteDBFS(c.RMSDBFS()), "peak_dbfs": finiteDBFS(c.PeakDBFS())}
+	}
+	return map[string]interface{}{"channels": channels, "seconds": window.Seconds()}, nil
+}
+
+// finiteDBFS clamps digital silence to -200 dBFS, which JSON can carry.
+func finiteDBFS(level float64) float64 {
+	return math.Max(level, -200)
+}
+
+func playTestToneCommand(ctx context.Context, a Audio, params map[string]interface{}) (map[string]interface{}, error) {
+	freq, err := commandNumber(params, "play_test_tone", "frequency_hz", 440)
+	if err != nil {
+		return nil, err
+	}
+	level, err := commandNumber(params, "play_test_tone", "level_dbfs", defaultTestToneLevel)
+	if err != nil {
+		return nil, err
+	}
+	seconds, err := commandNumber(params, "play_test_tone", "duration_seconds", 1)
+	if err != nil {
+		return nil, err
+	}
+	if freq < 20 || freq > 20000 {
+		return nil, errors.New("play_test_tone frequency_hz must be from 20 to 20000")
+	}
+	if level > maxTestToneLevel {
+		return nil, fmt.Errorf("play_test_tone level_dbfs can be at most %v", maxTestToneLevel)
+	}
+	duration := time.Duration(seconds * float64(time.Second))
+	if duration <= 0 || duration > maxTestToneDuration {
+		return nil, fmt.Errorf("play_test_tone duration_seconds must be positive and at most %v", maxTestToneDuration.Seconds())
+	}
+
+	data, err := encodePCM(fadedTone(freq, math.Pow(10, level/20), duration), Pcm16)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.Play(ctx, data, Pcm16.String(), testToneRate, 1); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"played_seconds": duration.Seconds()}, nil
+}
+
+// fadedTone renders a mono sine at testToneRate, faded in and out so the
+// speaker doesn't pop.
+func fadedTone(freq, amplitude float64, duration time.Duration) []float32 {
+	n := int(duration.Seconds() * testToneRate)
+	fade := int(testToneFade.Seconds() * testToneRate)
+	samples := make([]float32, n)
+	for i := range samples {
+		env := math.Min(1, float64(min(i, n-1-i))/float64(fade))
+		samples[i] = float32(amplitude * env * math.Sin(2*math.Pi*freq*float64(i)/testToneRate))
+	}
+	return samples
+}
@@ -0,0 +1,108 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestSampleLevelsCommand(t *testing.T) {
+	sim, err := NewSim(Named("sim"), SimConfig{Channels: 2, ChunkMs: 20, NoiseFloorDBFS: -40}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close(context.Background())
+	resp, err := sim.DoCommand(context.Background(), map[string]interface{}{"sample_levels": map[string]interface{}{"seconds": 0.2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	channels, _ := resp["channels"].([]interface{})
+	if len(channels) != 2 {
+		t.Fatalf("got %v", resp)
+	}
+	for i, c := range channels {
+		level := c.(map[string]interface{})["rms_dbfs"].(float64)
+		if math.Abs(level+40) > 1 {
+			t.Errorf("channel %d sampled at %.1f dBFS, want the -40 dBFS noise floor", i, level)
+		}
+	}
+
+	if _, err := sim.DoCommand(context.Background(), map[string]interface{}{"sample_levels": map[string]interface{}{"seconds": 60.0}}); err == nil {
+		t.Error("a minute long sample was allowed")
+	}
+}
+
+func TestPlayTestToneCommand(t *testing.T) {
+	rec := &playRecorder{burstSource: newBurstSource(0, AudioInfo{})}
+	cmd := map[string]interface{}{"play_test_tone": map[string]interface{}{"duration_seconds": 0.5}}
+	if _, ok, err := doDebugCommand(context.Background(), rec, cmd); !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	samples, _ := decodePCM(rec.played, Pcm16)
+	if len(samples) != testToneRate/2 {
+		t.Errorf("played %d samples, want half a second", len(samples))
+	}
+	if p := dbfs(peak(samples)); math.Abs(p-defaultTestToneLevel) > 0.1 {
+		t.Errorf("tone peaks at %.2f dBFS, want %v", p, defaultTestToneLevel)
+	}
+	if math.Abs(float64(samples[0])) > 1e-3 || math.Abs(float64(samples[len(samples)-1])) > 1e-3 {
+		t.Error("the tone isn't faded in and out")
+	}
+
+	for _, params := range []map[string]interface{}{
+		{"level_dbfs": -6.0},
+		{"duration_seconds": 10.0},
+		{"frequency_hz": "loud"},
+	} {
+		if _, _, err := doDebugCommand(context.Background(), rec, map[string]interface{}{"play_test_tone": params}); err == nil {
+			t.Errorf("play_test_tone %v was allowed", params)
+		}
+	}
+}
+
+func TestListDevicesCommand(t *testing.T) {
+	if _, err := os.Stat("/proc/asound/cards"); err != nil {
+		t.Skip("no sound cards to list")
+	}
+	resp, ok, err := doDebugCommand(context.Background(), nil, map[string]interface{}{"list_devices": true})
+	if !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	if _, ok := resp["devices"].([]interface{}); !ok {
+		t.Errorf("got %v", resp)
+	}
+}
+
+func TestLogChunksCommand(t *testing.T) {
+	src := newBurstSource(0, AudioInfo{})
+	name := src.Name().ShortName()
+	cmd := func(seconds float64) (map[string]interface{}, error) {
+		resp, _, err := doDebugCommand(context.Background(), src, map[string]interface{}{"log_chunks": map[string]interface{}{"seconds": seconds}})
+		return resp, err
+	}
+	if _, err := cmd(60); err != nil {
+		t.Fatal(err)
+	}
+	if !sharedChunkLogging.enabled(name) {
+		t.Error("chunks aren't logged")
+	}
+	if _, err := cmd(0); err != nil {
+		t.Fatal(err)
+	}
+	if sharedChunkLogging.enabled(name) {
+		t.Error("chunks are still logged after turning it off")
+	}
+	if _, err := cmd(3600); err == nil {
+		t.Error("chunks were logged for an hour")
+	}
+
+	sharedChunkLogging.set(name, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if sharedChunkLogging.enabled(name) {
+		t.Error("chunk logging didn't turn itself off")
+	}
+}
@@ -0,0 +1,355 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// noiseSuppressor removes steady background noise from an interleaved
+// stream. Output has the same length as input, delayed by a fixed latency.
+type noiseSuppressor interface {
+	process(samples []float32) []float32
+}
+
+// noiseSuppressors holds the engines WithNoiseSuppression accepts. Builds
+// with the rnnoise tag add "rnnoise".
+var noiseSuppressors = map[string]func(rate, channels int) (noiseSuppressor, error){
+	"spectral": func(rate, channels int) (noiseSuppressor, error) { return newSpectralSubtraction(rate, channels), nil },
+}
+
+// NoiseSuppressors lists the engines that can be used with
+// WithNoiseSuppression.
+func NoiseSuppressors() []string {
+	names := make([]string, 0, len(noiseSuppressors))
+	for name := range noiseSuppressors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newNoiseSuppressor(engine string, rate, channels int) (noiseSuppressor, error) {
+	factory, ok := noiseSuppressors[engine]
+	if !ok {
+		return nil, errorf(ErrInvalidArgument, "unknown noise suppressor %q, expected one of %v", engine, NoiseSuppressors())
+	}
+	return factory(rate, channels)
+}
+
+// denoiser runs a noise suppressor over raw pcm chunks, rebuilding it when
+// the format changes.
+type denoiser struct {
+	engine string
+	info   AudioInfo
+	ns     noiseSuppressor
+}
+
+// process returns a copy of chunk with the noise removed.
+func (d *denoiser) process(chunk *AudioChunk) (*AudioChunk, error) {
+	if chunk.Info == nil {
+		return nil, errUnknownSourceFormat
+	}
+	if d.ns == nil || *chunk.Info != d.info {
+		d.close()
+		ns, err := newNoiseSuppressor(d.engine, chunk.Info.SampleRate, chunk.Info.Channels)
+		if err != nil {
+			return nil, err
+		}
+		d.ns, d.info = ns, *chunk.Info
+	}
+	samples, err := decodePCM(chunk.AudioData, chunk.Info.Format)
+	if err != nil {
+		return nil, err
+	}
+	data, err := encodePCM(d.ns.process(samples), chunk.Info.Format)
+	if err != nil {
+		return nil, err
+	}
+	out := *chunk
+	out.AudioData = data
+	return &out, nil
+}
+
+// close frees the suppressor's native state now, if it has any, instead of
+// leaving it to the garbage collector.
+func (d *denoiser) close() {
+	if f, ok := d.ns.(interface{ free() }); ok {
+		f.free()
+	}
+	d.ns = nil
+}
+
+// blockStream adapts a processor of mono blocks to interleaved chunks of
+// any size. Output is delayed by the prefill so it is always ready.
+type blockStream struct {
+	channels int
+	block    int
+	in, out  [][]float32
+	fn       func(channel int, block []float32) []float32
+}
+
+func newBlockStream(channels, block, prefill int, fn func(int, []float32) []float32) *blockStream {
+	b := &blockStream{channels: channels, block: block, fn: fn,
+		in: make([][]float32, channels), out: make([][]float32, channels)}
+	for c := range b.out {
+		b.out[c] = make([]float32, prefill)
+	}
+	return b
+}
+
+func (b *blockStream) process(samples []float32) []float32 {
+	frames := len(samples) / b.channels
+	result := make([]float32, frames*b.channels)
+	for c := 0; c < b.channels; c++ {
+		for i := 0; i < frames; i++ {
+			b.in[c] = append(b.in[c], samples[i*b.channels+c])
+		}
+		for len(b.in[c]) >= b.block {
+			b.out[c] = append(b.out[c], b.fn(c, b.in[c][:b.block])...)
+			b.in[c] = b.in[c][b.block:]
+		}
+		n := min(frames, len(b.out[c]))
+		for i := 0; i < n; i++ {
+			result[i*b.channels+c] = b.out[c][i]
+		}
+		b.out[c] = b.out[c][n:]
+		// keep the buffers from creeping along their backing arrays forever
+		b.in[c] = append(b.in[c][:0:0], b.in[c]...)
+		b.out[c] = append(b.out[c][:0:0], b.out[c]...)
+	}
+	return result
+}
+
+// spectralSubtraction estimates the noise spectrum in each channel by
+// tracking the minimum of every frequency bin's smoothed power, then
+// attenuates bins by how much of their power is noise. Frames are at least
+// 20ms with 50% overlap, and a gain floor keeps the "musical noise" of bins
+// switching on and off down.
+type spectralSubtraction struct {
+	*blockStream
+	size     int
+	window   []float64 // square root Hann, applied before and after
+	channels []*spectralChannel
+}
+
+type spectralChannel struct {
+	prev  []float32 // previous hop of input
+	tail  []float64 // second half of the last frame, to overlap-add
+	power []float64 // per bin power, smoothed over time
+	noise []float64
+	gain  []float64
+	seen  int
+}
+
+const (
+	spectralOversubtract = 3.0
+	spectralFloor        = 0.1 // -20 dB
+	spectralWarmup       = 8   // hops averaged for the first noise estimate
+)
+
+func newSpectralSubtraction(rate, channels int) *spectralSubtraction {
+	size := nextPow2(rate / 50)
+	hop := size / 2
+	s := &spectralSubtraction{size: size, window: make([]float64, size)}
+	for i := range s.window {
+		s.window[i] = math.Sqrt(0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(size)))
+	}
+	for c := 0; c < channels; c++ {
+		s.channels = append(s.channels, &spectralChannel{
+			prev:  make([]float32, hop),
+			tail:  make([]float64, hop),
+			power: make([]float64, size/2+1),
+			noise: make([]float64, size/2+1),
+			gain:  make([]float64, size/2+1),
+		})
+	}
+	s.blockStream = newBlockStream(channels, hop, hop, s.hop)
+	return s
+}
+
+// hop takes the next half frame of one channel and returns the previous
+// half frame, denoised.
+func (s *spectralSubtraction) hop(channel int, in []float32) []float32 {
+	ch := s.channels[channel]
+	half := s.size / 2
+	x := make([]complex128, s.size)
+	for i := 0; i < half; i++ {
+		x[i] = complex(float64(ch.prev[i])*s.window[i], 0)
+		x[half+i] = complex(float64(in[i])*s.window[half+i], 0)
+	}
+	copy(ch.prev, in)
+	fft(x, false)
+
+	ch.seen++
+	for k := 0; k <= half; k++ {
+		re, im := real(x[k]), imag(x[k])
+		power := re*re + im*im
+		switch {
+		case ch.seen <= spectralWarmup:
+			ch.power[k] += (power - ch.power[k]) / float64(ch.seen)
+			ch.noise[k] = ch.power[k]
+		default:
+			ch.power[k] = 0.85*ch.power[k] + 0.15*power
+			if ch.power[k] < ch.noise[k] {
+				ch.noise[k] = ch.power[k]
+			} else {
+				// creep up slowly so speech doesn't get learned as noise
+				ch.noise[k] = 0.995*ch.noise[k] + 0.005*ch.power[k]
+			}
+		}
+
+		g := spectralFloor
+		if power > 0 {
+			g = math.Max(spectralFloor, math.Sqrt(math.Max(0, 1-spectralOversubtract*ch.noise[k]/power)))
+		}
+		if g < ch.gain[k] {
+			g = 0.5*ch.gain[k] + 0.5*g // release gently, attack at once
+		}
+		ch.gain[k] = g
+		x[k] *= complex(g, 0)
+		if k > 0 && k < half {
+			x[s.size-k] *= complex(g, 0)
+		}
+	}
+
+	fft(x, true)
+	out := make([]float32, half)
+	for i := 0; i < half; i++ {
+		out[i] = float32(ch.tail[i] + real(x[i])*s.window[i])
+		ch.tail[i] = real(x[half+i]) * s.window[half+i]
+	}
+	return out
+}
+
+// DenoiseModel wraps a microphone and removes steady background noise, such
+// as fans and motors, from everything captured through it.
+var DenoiseModel = resource.NewModel("olivia", "audio", "denoise")
+
+// DenoiseConfig is the configuration of the denoise model.
+type DenoiseConfig struct {
+	Input  string `json:"input"`            // Audio resource to capture from
+	Engine string `json:"engine,omitempty"` // see NoiseSuppressors, spectral if empty
+}
+
+// Validate checks the denoise configuration and returns the input as a
+// dependency.
+func (c *DenoiseConfig) Validate(path string) ([]string, []string, error) {
+	if c.Input == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "input")
+	}
+	if c.Engine != "" {
+		if _, ok := noiseSuppressors[c.Engine]; !ok {
+			return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("unknown engine %q, expected one of %v", c.Engine, NoiseSuppressors()))
+		}
+	}
+	return []string{c.Input}, nil, nil
+}
+
+func init() {
+	registerModel(DenoiseModel, resource.Registration[Audio, *DenoiseConfig]{
+		AttributeMapConverter: migratingConverter[*DenoiseConfig](DenoiseModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*DenoiseConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			input, err := resource.FromDependencies[Audio](deps, Named(cfg.Input))
+			if err != nil {
+				return nil, err
+			}
+			return NewDenoise(conf.ResourceName(), input, *cfg, logger)
+		},
+	})
+}
+
+type denoised struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	input  Audio
+	engine string
+	logger logging.Logger
+
+	streams streamGroup
+}
+
+// NewDenoise returns a resource capturing from input with noise removed.
+func NewDenoise(name resource.Name, input Audio, cfg DenoiseConfig, logger logging.Logger) (Audio, error) {
+	if cfg.Engine == "" {
+		cfg.Engine = "spectral"
+	}
+	if _, ok := noiseSuppressors[cfg.Engine]; !ok {
+		return nil, errorf(ErrInvalidArgument, "unknown noise suppressor %q, expected one of %v", cfg.Engine, NoiseSuppressors())
+	}
+	return &denoised{Named: name.AsNamed(), input: input, engine: cfg.Engine, logger: logger}, nil
+}
+
+// GetAudio captures raw pcm from the input and removes its noise.
+func (d *denoised) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, errors.New("noise suppression needs a raw pcm codec")
+	}
+	ctx, done, err := d.streams.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	src, err := d.input.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		dn := &denoiser{engine: d.engine}
+		defer dn.close()
+		for chunk := range src {
+			if chunk.Err == nil {
+				cleaned, err := dn.process(chunk)
+				if err != nil {
+					cleaned = &AudioChunk{Err: err}
+				}
+				chunk = cleaned
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+			if chunk.Err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (d *denoised) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return d.input.Play(ctx, data, codec, sampleRate, channels)
+}
+
+func (d *denoised) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, d, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close ends every stream and waits for their suppressors to be freed.
+func (d *denoised) Close(ctx context.Context) error {
+	return d.streams.close(ctx)
+}
@@ -0,0 +1,88 @@
+//go:build rnnoise
+
+package audio
+
+/*
+#cgo LDFLAGS: -lrnnoise
+#include <rnnoise.h>
+*/
+import "C"
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// rnnoiseRate is the only rate RNNoise runs at; other streams are resampled
+// around it.
+const rnnoiseRate = 48000
+
+func init() {
+	noiseSuppressors["rnnoise"] = func(rate, channels int) (noiseSuppressor, error) {
+		return newRNNoise(rate, channels), nil
+	}
+}
+
+// rnnoise is the recurrent neural network suppressor from Xiph, through cgo,
+// one denoiser per channel.
+type rnnoise struct {
+	*blockStream
+	frame  int
+	states *rnnoiseStates
+	up     []*resampler
+	down   []*resampler
+	queued [][]float32 // 48 kHz audio waiting for a full frame
+}
+
+func newRNNoise(rate, channels int) *rnnoise {
+	r := &rnnoise{frame: int(C.rnnoise_get_frame_size()), states: &rnnoiseStates{}, queued: make([][]float32, channels)}
+	for c := 0; c < channels; c++ {
+		r.states.s = append(r.states.s, C.rnnoise_create(nil))
+		r.up = append(r.up, newResampler(rate, rnnoiseRate, 1))
+		r.down = append(r.down, newResampler(rnnoiseRate, rate, 1))
+	}
+	// 10ms blocks, with two blocks of prefill to cover the resamplers'
+	// uneven output
+	block := rate / 100
+	r.blockStream = newBlockStream(channels, block, 2*block, r.process10ms)
+	// the block stream refers back to r, so the C state is freed from an
+	// object outside that cycle
+	runtime.SetFinalizer(r.states, func(st *rnnoiseStates) {
+		for _, s := range st.s {
+			C.rnnoise_destroy(s)
+		}
+	})
+	return r
+}
+
+type rnnoiseStates struct {
+	s []*C.DenoiseState
+}
+
+// free destroys the denoisers as soon as the stream is done with them.
+func (r *rnnoise) free() {
+	for _, s := range r.states.s {
+		C.rnnoise_destroy(s)
+	}
+	r.states.s = nil
+}
+
+func (r *rnnoise) process10ms(channel int, block []float32) []float32 {
+	queued := append(r.queued[channel], r.up[channel].process(block)...)
+	var cleaned []float32
+	in := make([]float32, r.frame)
+	out := make([]float32, r.frame)
+	for len(queued) >= r.frame {
+		// RNNoise works on 16-bit scale floats
+		for i, s := range queued[:r.frame] {
+			in[i] = s * 32768
+		}
+		C.rnnoise_process_frame(r.states.s[channel], (*C.float)(unsafe.Pointer(&out[0])), (*C.float)(unsafe.Pointer(&in[0])))
+		for _, s := range out {
+			cleaned = append(cleaned, s/32768)
+		}
+		queued = queued[r.frame:]
+	}
+	r.queued[channel] = append(queued[:0:0], queued...)
+	return r.down[channel].process(cleaned)
+}
@@ -0,0 +1,105 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBlockStreamDelaysByThePrefill(t *testing.T) {
+	const channels, block, prefill = 2, 64, 64
+	b := newBlockStream(channels, block, prefill, func(_ int, in []float32) []float32 {
+		return append([]float32(nil), in...)
+	})
+	var in, out []float32
+	for i, size := range []int{10, 1, 200, 64, 63, 300, 7} {
+		chunk := make([]float32, size*channels)
+		for j := range chunk {
+			chunk[j] = float32(len(in)+j+1) * float32(1-2*(j%2))
+		}
+		in = append(in, chunk...)
+		got := b.process(chunk)
+		if len(got) != len(chunk) {
+			t.Fatalf("chunk %d: %d samples out for %d in", i, len(got), len(chunk))
+		}
+		out = append(out, got...)
+	}
+	for i := range out {
+		want := float32(0)
+		if j := i - prefill*channels; j >= 0 {
+			want = in[j]
+		}
+		if out[i] != want {
+			t.Fatalf("sample %d is %v, want %v", i, out[i], want)
+		}
+	}
+}
+
+// bandLevel returns the RMS of samples at freq by correlating with a sine
+// and cosine, which ignores everything else.
+func bandLevel(samples []float32, rate int, freq float64) float64 {
+	var re, im float64
+	for i, s := range samples {
+		phase := 2 * math.Pi * freq * float64(i) / float64(rate)
+		re += float64(s) * math.Cos(phase)
+		im += float64(s) * math.Sin(phase)
+	}
+	return math.Hypot(re, im) / float64(len(samples)) * math.Sqrt2
+}
+
+func TestSpectralSubtraction(t *testing.T) {
+	for _, rate := range []int{16000, 48000} {
+		ns, err := newNoiseSuppressor("spectral", rate, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a second of hiss to learn, then 250ms tone bursts in the same hiss;
+		// a tone that never stops would be learned as noise like a fan's whine
+		n := rate
+		in := make([]float32, 2*n)
+		for i := range in {
+			in[i] = 0.02 * simNoise(uint64(i))
+			if i >= n && (i-n)/(rate/4)%2 == 0 {
+				in[i] += 0.2 * float32(math.Sin(2*math.Pi*1000*float64(i)/float64(rate)))
+			}
+		}
+		var out []float32
+		for i := 0; i < len(in); i += rate / 100 {
+			out = append(out, ns.process(in[i:i+rate/100])...)
+		}
+
+		// skip the warmup and the half second the floor needs to settle
+		noiseIn, noiseOut := rms(in[n/2:n]), rms(out[n/2:n])
+		if reduction := dbfs(noiseIn) - dbfs(noiseOut); reduction < 6 || reduction > 21 {
+			t.Errorf("%d Hz: hiss reduced by %.1f dB, want 6 dB to the 20 dB floor", rate, reduction)
+		}
+		toneIn := bandLevel(in[n:], rate, 1000)
+		toneOut := bandLevel(out[n:], rate, 1000)
+		if loss := dbfs(toneIn) - dbfs(toneOut); math.Abs(loss) > 1.5 {
+			t.Errorf("%d Hz: tone bursts changed by %.1f dB, want within 1.5", rate, loss)
+		}
+	}
+	if _, err := newNoiseSuppressor("nope", 16000, 1); err == nil {
+		t.Fatal("unknown noise suppressor accepted")
+	}
+}
+
+func TestDenoiserFollowsTheFormat(t *testing.T) {
+	d := &denoiser{engine: "spectral"}
+	if _, err := d.process(&AudioChunk{AudioData: make([]byte, 320)}); err == nil {
+		t.Fatal("chunk without a format accepted")
+	}
+	for _, info := range []AudioInfo{
+		{Format: Pcm16, SampleRate: 16000, Channels: 1},
+		{Format: Pcm16, SampleRate: 48000, Channels: 2},
+	} {
+		info := info
+		data := make([]byte, info.SampleRate/100*info.Channels*2)
+		out, err := d.process(&AudioChunk{AudioData: data, Info: &info, Sequence: 7})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out.AudioData) != len(data) || out.Sequence != 7 || d.info != info {
+			t.Fatalf("%+v: got %d bytes, sequence %d, suppressor for %+v", info, len(out.AudioData), out.Sequence, d.info)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package audio
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// A Detector reports whether a block of samples matches a condition such as
+// speech or an alarm. Detectors keep state between calls, so every stream
+// gets its own instances.
+type Detector interface {
+	Detect(samples []float32, info AudioInfo) bool
+}
+
+// detectorFactories holds the conditions GetAudio's only_when filter knows.
+var detectorFactories = map[string]func() Detector{
+	"sound":  func() Detector { return &levelDetector{thresholdDBFS: -45} },
+	"speech": func() Detector { return &vad{engine: newEnergyVAD()} }, // no hangover, the gate has a post-roll
+	"alarm":  func() Detector { return &alarmDetector{thresholdDBFS: -30} },
+	// bangs and crashes, see ImpulseOptions
+	"impulse": func() Detector { return &impulseDetector{} },
+}
+
+// DetectorNames lists the conditions that can be used with WithOnlyWhen.
+func DetectorNames() []string {
+	names := make([]string, 0, len(detectorFactories))
+	for name := range detectorFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newDetector(name string) (Detector, error) {
+	factory, ok := detectorFactories[name]
+	if !ok {
+		return nil, errorf(ErrInvalidArgument, "unknown condition %q, expected one of %v", name, DetectorNames())
+	}
+	return factory(), nil
+}
+
+func rms(samples []float32) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+// chunkRMS returns the RMS level of a raw pcm chunk over all its channels.
+func chunkRMS(chunk *AudioChunk) (float64, error) {
+	if chunk.Info == nil {
+		return 0, errUnknownSourceFormat
+	}
+	samples, err := decodePCM(chunk.AudioData, chunk.Info.Format)
+	if err != nil {
+		return 0, err
+	}
+	return rms(samples), nil
+}
+
+func peak(samples []float32) float64 {
+	var p float64
+	for _, s := range samples {
+		p = math.Max(p, math.Abs(float64(s)))
+	}
+	return p
+}
+
+// dbfs converts a linear level to decibels relative to full scale, flooring
+// silence at -120.
+func dbfs(level float64) float64 {
+	if level <= 1e-6 {
+		return -120
+	}
+	return 20 * math.Log10(level)
+}
+
+// mono averages interleaved samples down to one channel for analysis.
+func mono(samples []float32, channels int) []float32 {
+	if channels <= 1 {
+		return samples
+	}
+	return remix(samples, channels, 1)
+}
+
+// levelDetector fires whenever the RMS level is above a fixed threshold.
+type levelDetector struct {
+	thresholdDBFS float64
+}
+
+func (d *levelDetector) Detect(samples []float32, info AudioInfo) bool {
+	return dbfs(rms(samples)) > d.thresholdDBFS
+}
+
+// alarmDetector looks for loud, strongly periodic sound between 500 Hz and
+// 4 kHz, which covers beepers, sirens and smoke alarms. Most of the energy
+// has to be in that band and the autocorrelation has to peak at a period
+// inside it, so mains hum and voiced speech, which are periodic too but
+// below the band, don't count.
+type alarmDetector struct {
+	thresholdDBFS float64
+}
+
+// Alarm band and how much of a block's energy has to fall inside it.
+const (
+	alarmLowHz     = 500
+	alarmHighHz    = 4000
+	alarmBandShare = 0.6
+)
+
+func (d *alarmDetector) Detect(samples []float32, info AudioInfo) bool {
+	m := mono(samples, info.Channels)
+	if dbfs(rms(m)) < d.thresholdDBFS || info.SampleRate == 0 {
+		return false
+	}
+	if bandShare(m, info.SampleRate, alarmLowHz, alarmHighHz) < alarmBandShare {
+		return false
+	}
+	return periodicity(m, info.SampleRate/alarmHighHz, info.SampleRate/alarmLowHz) > 0.8
+}
+
+// bandShare returns the fraction of the energy of samples between lo and hi
+// Hz, from a Hann-windowed FFT of as many samples as fit a power of two.
+func bandShare(samples []float32, rate int, lo, hi float64) float64 {
+	n := nextPow2(len(samples))
+	if n > len(samples) {
+		n /= 2
+	}
+	if n < 2 {
+		return 0
+	}
+	x := make([]complex128, n)
+	for i := range x {
+		w := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+		x[i] = complex(w*float64(samples[i]), 0)
+	}
+	fft(x, false)
+	var in, total float64
+	for k := 1; k <= n/2; k++ {
+		p := real(x[k])*real(x[k]) + imag(x[k])*imag(x[k])
+		total += p
+		if f := float64(k) * float64(rate) / float64(n); f >= lo && f <= hi {
+			in += p
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return in / total
+}
+
+// periodicity returns the highest peak of the normalized autocorrelation of
+// samples at lags in [minLag, maxLag]. Only local maxima count: the
+// autocorrelation of any low-frequency sound is high at short lags but
+// still falling, which says nothing about a period in the range.
+func periodicity(samples []float32, minLag, maxLag int) float64 {
+	if minLag < 1 {
+		minLag = 1
+	}
+	if maxLag >= len(samples)-1 {
+		maxLag = len(samples) - 2
+	}
+	if maxLag < minLag {
+		return 0
+	}
+	var energy float64
+	for _, s := range samples {
+		energy += float64(s) * float64(s)
+	}
+	if energy == 0 {
+		return 0
+	}
+	// one lag either side of the range to tell peaks from slopes
+	ac := make([]float64, maxLag+2)
+	for lag := minLag - 1; lag <= maxLag+1; lag++ {
+		var sum float64
+		for i := lag; i < len(samples); i++ {
+			sum += float64(samples[i]) * float64(samples[i-lag])
+		}
+		ac[lag] = sum / energy
+	}
+	best := 0.0
+	for lag := minLag; lag <= maxLag; lag++ {
+		if ac[lag] > ac[lag-1] && ac[lag] >= ac[lag+1] {
+			best = math.Max(best, ac[lag])
+		}
+	}
+	return best
+}
+
+// Defaults for the audio kept around an only_when match.
+const (
+	defaultPreRoll  = 500 * time.Millisecond
+	defaultPostRoll = time.Second
+)
+
+// eventGate passes chunks only while one of its detectors matches, plus a
+// pre-roll of audio from before the match and a post-roll after it ends.
+// The first chunk after a skipped stretch carries the skipped duration as
+// its Gap.
+type eventGate struct {
+	detectors []Detector
+	preRoll   time.Duration
+	postRoll  time.Duration
+
+	buffered    []*AudioChunk
+	bufferedDur time.Duration
+	skipped     time.Duration
+	open        bool
+	postLeft    time.Duration
+}
+
+func newEventGate(conditions []string, preRoll, postRoll time.Duration) (*eventGate, error) {
+	g := &eventGate{preRoll: preRoll, postRoll: postRoll}
+	if g.preRoll == 0 {
+		g.preRoll = defaultPreRoll
+	}
+	if g.postRoll == 0 {
+		g.postRoll = defaultPostRoll
+	}
+	for _, c := range conditions {
+		d, err := newDetector(c)
+		if err != nil {
+			return nil, err
+		}
+		g.detectors = append(g.detectors, d)
+	}
+	return g, nil
+}
+
+// process takes one raw PCM chunk and returns the chunks to deliver now.
+func (g *eventGate) process(chunk *AudioChunk) ([]*AudioChunk, error) {
+	if chunk.Info == nil {
+		return nil, errUnknownSourceFormat
+	}
+	samples, err := decodePCM(chunk.AudioData, chunk.Info.Format)
+	if err != nil {
+		return nil, err
+	}
+	dur, _ := chunkDuration(chunk)
+
+	matched := false
+	for _, d := range g.detectors {
+		// every detector sees every chunk so their state stays current
+		if d.Detect(samples, *chunk.Info) {
+			matched = true
+		}
+	}
+
+	if matched {
+		out := append(g.buffered, chunk)
+		if !g.open && g.skipped > 0 {
+			first := *out[0]
+			first.Gap += g.skipped
+			out[0] = &first
+		}
+		g.buffered, g.bufferedDur, g.skipped = nil, 0, 0
+		g.open, g.postLeft = true, g.postRoll
+		return out, nil
+	}
+
+	if g.open {
+		g.postLeft -= dur
+		if g.postLeft <= 0 {
+			g.open = false
+		}
+		return []*AudioChunk{chunk}, nil
+	}
+
+	g.buffered = append(g.buffered, chunk)
+	g.bufferedDur += dur
+	for len(g.buffered) > 0 && g.bufferedDur > g.preRoll {
+		oldest, _ := chunkDuration(g.buffered[0])
+		g.bufferedDur -= oldest
+		g.skipped += oldest + g.buffered[0].Gap
+		g.buffered = g.buffered[1:]
+	}
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+// harmonics renders a sum of sines at f0 and its multiples up to 4 kHz with
+// the given amplitude for each harmonic number, scaled to a peak of level.
+func harmonics(rate, n int, f0, level float64, amp func(h int, f float64) float64) []float32 {
+	out := make([]float32, n)
+	var p float64
+	buf := make([]float64, n)
+	for h := 1; float64(h)*f0 <= 4000; h++ {
+		f := float64(h) * f0
+		a := amp(h, f)
+		for i := range buf {
+			buf[i] += a * math.Sin(2*math.Pi*f*float64(i)/float64(rate)+float64(h))
+		}
+	}
+	for _, v := range buf {
+		p = math.Max(p, math.Abs(v))
+	}
+	for i, v := range buf {
+		out[i] = float32(level * v / p)
+	}
+	return out
+}
+
+func tone(rate, n int, f, level float64) []float32 {
+	return harmonics(rate, n, f, level, func(h int, _ float64) float64 {
+		if h == 1 {
+			return 1
+		}
+		return 0
+	})
+}
+
+func resonance(f, center, width float64) float64 {
+	return math.Exp(-(f - center) * (f - center) / (2 * width * width))
+}
+
+func TestAlarmDetector(t *testing.T) {
+	for _, rate := range []int{16000, 48000} {
+		n := rate / 10
+		for _, tc := range []struct {
+			name    string
+			samples []float32
+			want    bool
+		}{
+			{"smoke alarm", tone(rate, n, 3100, 0.5), true},
+			{"beeper", tone(rate, n, 1000, 0.3), true},
+			{"quiet beeper", tone(rate, n, 1000, 0.01), false},
+			{"mains hum", harmonics(rate, n, 100, 0.5, func(h int, _ float64) float64 {
+				return []float64{0, 1, 0.3, 0.2}[min(h, 3)] * float64(min(1, max(0, 4-h)))
+			}), false},
+			{"pure hum", tone(rate, n, 100, 0.8), false},
+			{"voiced speech", harmonics(rate, n, 120, 0.5, func(h int, f float64) float64 {
+				// a glottal source shaped by the first two formants of "ah"
+				return (1 + 4*resonance(f, 700, 100) + 2*resonance(f, 1200, 150)) / float64(h)
+			}), false},
+			{"high voiced speech", harmonics(rate, n, 220, 0.5, func(h int, f float64) float64 {
+				return (1 + 3*resonance(f, 500, 100) + 3*resonance(f, 1800, 200)) / float64(h)
+			}), false},
+		} {
+			d := &alarmDetector{thresholdDBFS: -30}
+			if got := d.Detect(tc.samples, AudioInfo{Format: Pcm32Float, SampleRate: rate, Channels: 1}); got != tc.want {
+				t.Errorf("%d Hz %s: got %v, want %v (band share %.2f, periodicity %.2f)", rate, tc.name, got, tc.want,
+					bandShare(tc.samples, rate, alarmLowHz, alarmHighHz), periodicity(tc.samples, rate/alarmHighHz, rate/alarmLowHz))
+			}
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package audio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SoundDevice is a PCM device found on the system.
+type SoundDevice struct {
+	ID       string // ALSA device name, like "hw:1,0"
+	Card     int
+	CardID   string // the card's short name, like "Device"
+	Name     string // the card's description
+	Driver   string
+	Capture  bool
+	Playback bool
+}
+
+// deviceKind is what a device is wired to, as far as its names tell.
+type deviceKind int
+
+const (
+	otherDevice deviceKind = iota
+	usbDevice
+	analogDevice
+	hdmiDevice
+)
+
+func (k deviceKind) String() string {
+	switch k {
+	case usbDevice:
+		return "usb"
+	case analogDevice:
+		return "analog"
+	case hdmiDevice:
+		return "hdmi"
+	default:
+		return "other"
+	}
+}
+
+func (d SoundDevice) kind() deviceKind {
+	names := strings.ToLower(d.Driver + " " + d.CardID + " " + d.Name)
+	switch {
+	case strings.Contains(names, "usb"):
+		return usbDevice
+	// the Raspberry Pi's HDMI outputs show up as "vc4-hdmi" or "MAI PCM"
+	case strings.Contains(names, "hdmi"), strings.Contains(names, "mai pcm"):
+		return hdmiDevice
+	case strings.Contains(names, "headphones"), strings.Contains(names, "analog"), strings.Contains(names, "bcm2835"):
+		return analogDevice
+	default:
+		return otherDevice
+	}
+}
+
+// ListSoundDevices lists the PCM devices ALSA knows about.
+func ListSoundDevices() ([]SoundDevice, error) {
+	cards, err := os.Open("/proc/asound/cards")
+	if err != nil {
+		return nil, fmt.Errorf("cannot list sound cards: %w", err)
+	}
+	defer cards.Close()
+	pcm, err := os.Open("/proc/asound/pcm")
+	if errors.Is(err, os.ErrNotExist) {
+		// no card has a pcm device
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot list sound devices: %w", err)
+	}
+	defer pcm.Close()
+	return parseSoundDevices(cards, pcm)
+}
+
+var (
+	// " 1 [Device         ]: USB-Audio - USB PnP Sound Device"
+	asoundCardLine = regexp.MustCompile(`^\s*(\d+)\s+\[(.*?)\s*\]:\s*(.*?)\s+-\s+(.*)$`)
+	// "01-00: USB Audio : USB Audio : playback 1 : capture 1"
+	asoundPCMLine = regexp.MustCompile(`^(\d+)-(\d+):`)
+)
+
+// parseSoundDevices reads the formats of /proc/asound/cards and
+// /proc/asound/pcm.
+func parseSoundDevices(cards, pcm io.Reader) ([]SoundDevice, error) {
+	type card struct{ id, driver, name string }
+	byNumber := map[int]card{}
+	sc := bufio.NewScanner(cards)
+	for sc.Scan() {
+		m := asoundCardLine.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		byNumber[n] = card{id: m[2], driver: m[3], name: m[4]}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	var devices []SoundDevice
+	sc = bufio.NewScanner(pcm)
+	for sc.Scan() {
+		line := sc.Text()
+		m := asoundPCMLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		dev, _ := strconv.Atoi(m[2])
+		c := byNumber[n]
+		d := SoundDevice{ID: fmt.Sprintf("hw:%d,%d", n, dev), Card: n, CardID: c.id, Name: c.name, Driver: c.driver}
+		for _, field := range strings.Split(line, ":")[1:] {
+			switch {
+			case strings.HasPrefix(strings.TrimSpace(field), "capture"):
+				d.Capture = true
+			case strings.HasPrefix(strings.TrimSpace(field), "playback"):
+				d.Playback = true
+			}
+		}
+		devices = append(devices, d)
+	}
+	return devices, sc.Err()
+}
+
+// displayConnected reports whether a monitor is plugged into any output, in
+// which case its HDMI audio is likely to be heard.
+func displayConnected() bool {
+	paths, _ := filepath.Glob("/sys/class/drm/card*-*/status")
+	for _, p := range paths {
+		if b, err := os.ReadFile(p); err == nil && strings.TrimSpace(string(b)) == "connected" {
+			return true
+		}
+	}
+	return false
+}
+
+// ChooseDefaultDevice picks the device to use when none is configured, and
+// says why so the choice can be logged. For capture a USB microphone wins
+// and HDMI inputs, which are dummies on a Raspberry Pi, come last. For
+// playback a USB speaker wins, then analog out ahead of HDMI unless a
+// display is connected. Ties go to the lowest card.
+func ChooseDefaultDevice(devices []SoundDevice, capture, display bool) (SoundDevice, string, error) {
+	rank := func(k deviceKind) int {
+		switch {
+		case k == usbDevice:
+			return 0
+		case k == hdmiDevice && (capture || !display):
+			return 3
+		case k == hdmiDevice:
+			return 1
+		default:
+			return 2
+		}
+	}
+	var candidates []SoundDevice
+	for _, d := range devices {
+		if (capture && d.Capture) || (!capture && d.Playback) {
+			candidates = append(candidates, d)
+		}
+	}
+	direction := "playback"
+	if capture {
+		direction = "capture"
+	}
+	if len(candidates) == 0 {
+		return SoundDevice{}, "", fmt.Errorf("no %s devices found", direction)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := rank(candidates[i].kind()), rank(candidates[j].kind())
+		if ri != rj {
+			return ri < rj
+		}
+		return candidates[i].Card < candidates[j].Card
+	})
+	best := candidates[0]
+	reason := fmt.Sprintf("%s device %s (%s) chosen as the only one", direction, best.ID, best.Name)
+	if len(candidates) > 1 {
+		reason = fmt.Sprintf("%s device %s (%s) chosen as the first %s device of %d", direction, best.ID, best.Name, best.kind(), len(candidates))
+		if last := candidates[len(candidates)-1]; !capture && !display && last.kind() == hdmiDevice {
+			reason += ", HDMI passed over with no display connected"
+		}
+	}
+	return best, reason, nil
+}
+
+// DefaultDevice chooses among the system's devices with
+// ChooseDefaultDevice, for models configured without a device.
+func DefaultDevice(capture bool) (SoundDevice, string, error) {
+	devices, err := ListSoundDevices()
+	if err != nil {
+		return SoundDevice{}, "", err
+	}
+	return ChooseDefaultDevice(devices, capture, displayConnected())
+}
+
+// deviceList is the devices a resource's backend can open, and the ones it
+// uses when none is configured.
+type deviceList struct {
+	devices                         []SoundDevice
+	defaultCapture, defaultPlayback string
+	// why the defaults were chosen, when the backend doesn't decide
+	captureReason, playbackReason string
+}
+
+// deviceBackend is implemented by resources whose backend has devices of
+// its own, rather than ALSA's.
+type deviceBackend interface {
+	backendDevices() (deviceList, error)
+}
+
+// resourceDevices lists the devices a can use.
+func resourceDevices(a Audio) (deviceList, error) {
+	if b, ok := a.(deviceBackend); ok {
+		return b.backendDevices()
+	}
+	return alsaDeviceList()
+}
+
+// alsaDeviceList lists ALSA's devices, with the defaults
+// ChooseDefaultDevice picks.
+func alsaDeviceList() (deviceList, error) {
+	devices, err := ListSoundDevices()
+	if err != nil {
+		return deviceList{}, err
+	}
+	list := deviceList{devices: devices}
+	display := displayConnected()
+	if d, reason, err := ChooseDefaultDevice(devices, true, display); err == nil {
+		list.defaultCapture, list.captureReason = d.ID, reason
+	}
+	if d, reason, err := ChooseDefaultDevice(devices, false, display); err == nil {
+		list.defaultPlayback, list.playbackReason = d.ID, reason
+	}
+	return list, nil
+}
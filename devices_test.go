@@ -0,0 +1,82 @@
+package audio
+
+import (
+	"strings"
+	"testing"
+)
+
+// a Raspberry Pi 4 with a USB microphone and speaker
+const (
+	piCards = ` 0 [Headphones     ]: bcm2835_headpho - bcm2835 Headphones
+                      bcm2835 Headphones
+ 1 [vc4hdmi0       ]: vc4-hdmi - vc4-hdmi-0
+                      vc4-hdmi-0
+ 2 [Device         ]: USB-Audio - USB PnP Sound Device
+                      C-Media Electronics Inc. USB PnP Sound Device at usb-0000:01:00.0-1.3, full speed
+`
+	piPCM = `00-00: bcm2835 Headphones : bcm2835 Headphones : playback 8
+01-00: MAI PCM i2s-hifi-0 : MAI PCM i2s-hifi-0 : playback 1
+02-00: USB Audio : USB Audio : playback 1 : capture 1
+`
+)
+
+func TestParseSoundDevices(t *testing.T) {
+	devices, err := parseSoundDevices(strings.NewReader(piCards), strings.NewReader(piPCM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SoundDevice{
+		{ID: "hw:0,0", Card: 0, CardID: "Headphones", Name: "bcm2835 Headphones", Driver: "bcm2835_headpho", Playback: true},
+		{ID: "hw:1,0", Card: 1, CardID: "vc4hdmi0", Name: "vc4-hdmi-0", Driver: "vc4-hdmi", Playback: true},
+		{ID: "hw:2,0", Card: 2, CardID: "Device", Name: "USB PnP Sound Device", Driver: "USB-Audio", Capture: true, Playback: true},
+	}
+	if len(devices) != len(want) {
+		t.Fatalf("got %+v", devices)
+	}
+	for i := range want {
+		if devices[i] != want[i] {
+			t.Errorf("device %d is %+v, want %+v", i, devices[i], want[i])
+		}
+	}
+	kinds := []deviceKind{analogDevice, hdmiDevice, usbDevice}
+	for i, k := range kinds {
+		if devices[i].kind() != k {
+			t.Errorf("%s is %v, want %v", devices[i].ID, devices[i].kind(), k)
+		}
+	}
+}
+
+func TestChooseDefaultDevice(t *testing.T) {
+	devices, err := parseSoundDevices(strings.NewReader(piCards), strings.NewReader(piPCM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	onboard := devices[:2] // without the USB device
+
+	for _, tc := range []struct {
+		devices []SoundDevice
+		capture bool
+		display bool
+		want    string
+	}{
+		{devices, true, false, "hw:2,0"},
+		{devices, false, true, "hw:2,0"},
+		{onboard, false, false, "hw:0,0"},
+		{onboard, false, true, "hw:1,0"},
+	} {
+		d, reason, err := ChooseDefaultDevice(tc.devices, tc.capture, tc.display)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.ID != tc.want {
+			t.Errorf("capture %v display %v chose %s (%s), want %s", tc.capture, tc.display, d.ID, reason, tc.want)
+		}
+	}
+	if _, _, err := ChooseDefaultDevice(onboard, true, false); err == nil {
+		t.Error("chose a capture device on a board without one")
+	}
+	_, reason, _ := ChooseDefaultDevice(onboard, false, false)
+	if !strings.Contains(reason, "no display") {
+		t.Errorf("reason %q doesn't say why HDMI wasn't chosen", reason)
+	}
+}
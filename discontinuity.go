@@ -0,0 +1,89 @@
+package audio
+
+import "time"
+
+// Reasons of a Discontinuity.
+const (
+	// DiscontinuityDropped: the server dropped chunks because the client
+	// fell behind, see WithDropPolicy.
+	DiscontinuityDropped = "dropped"
+	// DiscontinuitySkipped: the server skipped audio, while the stream was
+	// paused or for WithOnlyWhen, WithSpeechOnly or WithTrimSilence.
+	DiscontinuitySkipped = "skipped"
+	// DiscontinuityMissing: chunks are missing from the sequence.
+	DiscontinuityMissing = "missing"
+	// DiscontinuityRestarted: the sequence started over, as a stream that
+	// could no longer be resumed does when reconnected.
+	DiscontinuityRestarted = "restarted"
+	// DiscontinuityTimestamp: the capture timestamps jumped with nothing
+	// else to account for it, as after a device overrun.
+	DiscontinuityTimestamp = "timestamp"
+)
+
+// timestampTolerance is how far capture timestamps can stray from one chunk
+// to the next before they count as a jump.
+const timestampTolerance = 20 * time.Millisecond
+
+// Discontinuity reports that the audio timeline jumped right before a
+// chunk, so that consumers such as speech-to-text can reset what they built
+// up from the audio before it.
+type Discontinuity struct {
+	Reason string
+	// Chunks counts the chunks missing, zero if unknown.
+	Chunks int64
+	// Skipped is how much audio is missing, from the chunk's Gap or the
+	// capture timestamps, zero if unknown.
+	Skipped time.Duration
+}
+
+// discontinuityTracker finds the discontinuities of a stream from a server
+// by comparing each chunk with the one before it.
+type discontinuityTracker struct {
+	started bool
+	seq     int64
+	dropped int64     // chunks dropped that were reported
+	end     time.Time // capture time the last chunk ends at, zero if unknown
+}
+
+// check sets chunk.Discontinuity if the timeline jumped since the last
+// chunk.
+func (t *discontinuityTracker) check(chunk *AudioChunk) {
+	if t.started {
+		chunk.Discontinuity = t.find(chunk)
+	}
+	t.started = true
+	t.seq = chunk.Sequence
+	t.end = time.Time{}
+	if d, ok := chunkDuration(chunk); ok && !chunk.Timestamp.IsZero() {
+		t.end = chunk.Timestamp.Add(d)
+	}
+}
+
+func (t *discontinuityTracker) find(chunk *AudioChunk) *Discontinuity {
+	var jump time.Duration
+	if !t.end.IsZero() && !chunk.Timestamp.IsZero() {
+		jump = chunk.Timestamp.Sub(t.end)
+	}
+	skipped := chunk.Gap
+	if skipped == 0 {
+		skipped = max(jump, 0)
+	}
+	switch {
+	case chunk.Sequence < t.seq:
+		t.dropped = chunk.Dropped
+		return &Discontinuity{Reason: DiscontinuityRestarted, Skipped: skipped}
+	case chunk.Sequence > t.seq+1:
+		return &Discontinuity{Reason: DiscontinuityMissing, Chunks: chunk.Sequence - t.seq - 1, Skipped: skipped}
+	case chunk.Gap > 0 && chunk.Dropped > t.dropped:
+		// the drops are counted as they happen, so ones not yet reported
+		// are put down to the next gap
+		n := chunk.Dropped - t.dropped
+		t.dropped = chunk.Dropped
+		return &Discontinuity{Reason: DiscontinuityDropped, Chunks: n, Skipped: skipped}
+	case chunk.Gap > 0:
+		return &Discontinuity{Reason: DiscontinuitySkipped, Skipped: skipped}
+	case jump.Abs() > timestampTolerance:
+		return &Discontinuity{Reason: DiscontinuityTimestamp, Skipped: skipped}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDiscontinuityTracker(t *testing.T) {
+	info := &AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}
+	at := time.Unix(100, 0)
+	// chunk returns the 10ms chunk seq, captured at 10ms steps from at
+	chunk := func(seq, step int64) *AudioChunk {
+		return &AudioChunk{Sequence: seq, AudioData: make([]byte, 160), Info: info, Timestamp: at.Add(time.Duration(step) * 10 * time.Millisecond)}
+	}
+	gap := func(c *AudioChunk, gap time.Duration, dropped int64) *AudioChunk {
+		c.Gap, c.Dropped = gap, dropped
+		return c
+	}
+	steps := []struct {
+		chunk *AudioChunk
+		want  *Discontinuity
+	}{
+		{chunk(0, 0), nil},
+		{chunk(1, 1), nil},
+		{gap(chunk(2, 5), 30*time.Millisecond, 3), &Discontinuity{Reason: DiscontinuityDropped, Chunks: 3, Skipped: 30 * time.Millisecond}},
+		{gap(chunk(3, 8), 20*time.Millisecond, 3), &Discontinuity{Reason: DiscontinuitySkipped, Skipped: 20 * time.Millisecond}},
+		{gap(chunk(6, 11), 0, 3), &Discontinuity{Reason: DiscontinuityMissing, Chunks: 2, Skipped: 20 * time.Millisecond}},
+		{gap(chunk(7, 15), 0, 3), &Discontinuity{Reason: DiscontinuityTimestamp, Skipped: 30 * time.Millisecond}},
+		{gap(chunk(0, 30), 0, 0), &Discontinuity{Reason: DiscontinuityRestarted, Skipped: 140 * time.Millisecond}},
+		{chunk(1, 31), nil},
+	}
+	var tracker discontinuityTracker
+	for i, s := range steps {
+		tracker.check(s.chunk)
+		got := s.chunk.Discontinuity
+		if (got == nil) != (s.want == nil) || got != nil && *got != *s.want {
+			t.Errorf("step %d: got %+v, want %+v", i, got, s.want)
+		}
+	}
+}
+
+func TestStreamDiscontinuity(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(20, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	link := &flakyLink{after: 5, forget: true}
+	c := serveAudio(t, src, WithStreamInterceptor(link.intercept))
+	close(src.start)
+
+	// the chunks of an unbroken stream are numbered in order
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := int64(0)
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		if chunk.Sequence != n || chunk.Discontinuity != nil {
+			t.Errorf("chunk %d numbered %d with discontinuity %+v", n, chunk.Sequence, chunk.Discontinuity)
+		}
+		n++
+	}
+
+	// one started over when reconnecting is marked where it did
+	link.drops.Store(1)
+	ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0, WithReconnect(RetryPolicy{InitialBackoff: 10 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n = 0
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		restarted := chunk.Discontinuity != nil && chunk.Discontinuity.Reason == DiscontinuityRestarted
+		if restarted != (n == 5) {
+			t.Errorf("chunk %d has discontinuity %+v", n, chunk.Discontinuity)
+		}
+		n++
+	}
+}
@@ -0,0 +1,245 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Defaults for DuplexCheck.
+const (
+	defaultDuplexSampleRate = 16000
+	defaultDuplexMaxLatency = 500 * time.Millisecond
+	duplexProbeDuration     = 250 * time.Millisecond
+	duplexLeadIn            = 200 * time.Millisecond
+	// normalized correlation above which the probe counts as heard
+	duplexEchoThreshold = 0.3
+)
+
+// DuplexCheckConfig bounds what DuplexCheck accepts.
+//
+// With MinAttenuationDB zero the check expects to hear its probe, as on a
+// loopback device or a speaker next to the microphone, and fails if the probe
+// isn't found within MaxLatency. With MinAttenuationDB set the check expects
+// echo cancellation and fails unless the probe is at least that much quieter
+// in the capture than it was played.
+type DuplexCheckConfig struct {
+	SampleRate       int           // rate the probe is played and captured at, 16 kHz if zero
+	MaxLatency       time.Duration // longest acceptable play-to-capture delay, 500ms if zero
+	MinAttenuationDB float64
+}
+
+// DuplexReport is what DuplexCheck measured.
+type DuplexReport struct {
+	// Latency is the delay between the probe being handed to Play and it
+	// showing up in the capture. Zero when the probe wasn't heard.
+	Latency time.Duration
+	// Correlation is the normalized cross-correlation peak between the probe
+	// and the capture, from 0 (not heard) to 1.
+	Correlation float64
+	// AttenuationDB is how much quieter the capture was than the probe while
+	// the probe played.
+	AttenuationDB float64
+}
+
+// DuplexCheck plays a known chirp on a while capturing from it, then reports
+// the round-trip timing and how strongly the chirp came back. It returns an
+// error alongside the report when the result is outside cfg's bounds, so
+// integration tests and health checks of full-duplex devices can't regress
+// silently.
+func DuplexCheck(ctx context.Context, a Audio, cfg DuplexCheckConfig) (DuplexReport, error) {
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = defaultDuplexSampleRate
+	}
+	if cfg.MaxLatency == 0 {
+		cfg.MaxLatency = defaultDuplexMaxLatency
+	}
+
+	// time everything on the resource's clock, so simulated devices can be
+	// checked as fast as a test advances them
+	clock := clockOf(a)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chunks, err := sharedCaptureHub.open(ctx, a, captureRequest{
+		target: AudioInfo{Format: Pcm32Float, SampleRate: cfg.SampleRate, Channels: 1},
+	})
+	if err != nil {
+		return DuplexReport{}, err
+	}
+
+	// collect the capture in the background so Play can block. Gaps are
+	// filled with silence so a sample's index always says when it was
+	// captured.
+	type captured struct {
+		samples []float32
+		start   time.Time // capture time of samples[0]
+		err     error
+	}
+	type request struct {
+		until time.Time
+		reply chan captured
+	}
+	requests := make(chan request)
+	go func() {
+		var got captured
+		var waiting []request
+		for {
+			select {
+			case req := <-requests:
+				waiting = append(waiting, req)
+			case chunk, ok := <-chunks:
+				switch {
+				case !ok:
+					chunks = nil
+					if got.err == nil {
+						got.err = errors.New("capture ended during the duplex check")
+					}
+				case chunk.Err != nil:
+					got.err = chunk.Err
+				default:
+					samples, err := decodePCM(chunk.AudioData, Pcm32Float)
+					if err != nil {
+						got.err = err
+						break
+					}
+					if got.start.IsZero() {
+						got.start = chunk.Timestamp
+						if got.start.IsZero() {
+							got.start = clock.Now().Add(-time.Duration(len(samples)) * time.Second / time.Duration(cfg.SampleRate))
+						}
+					} else if chunk.Gap > 0 {
+						got.samples = append(got.samples, make([]float32, int(chunk.Gap.Seconds()*float64(cfg.SampleRate)))...)
+					}
+					got.samples = append(got.samples, samples...)
+				}
+			case <-ctx.Done():
+				return
+			}
+			covered := got.start.Add(time.Duration(len(got.samples)) * time.Second / time.Duration(cfg.SampleRate))
+			for len(waiting) > 0 && (got.err != nil || (!got.start.IsZero() && !covered.Before(waiting[0].until))) {
+				waiting[0].reply <- captured{samples: append([]float32(nil), got.samples...), start: got.start, err: got.err}
+				waiting = waiting[1:]
+			}
+		}
+	}()
+
+	if err := clock.Sleep(ctx, duplexLeadIn); err != nil {
+		return DuplexReport{}, err
+	}
+	probe := chirp(cfg.SampleRate, duplexProbeDuration, 300, 3000, 0.5)
+	data, err := encodePCM(probe, Pcm16)
+	if err != nil {
+		return DuplexReport{}, err
+	}
+	started := clock.Now()
+	if err := a.Play(ctx, data, Pcm16.String(), cfg.SampleRate, 1); err != nil {
+		return DuplexReport{}, fmt.Errorf("failed to play duplex probe: %w", err)
+	}
+
+	// wait until the whole search window has been captured
+	req := request{until: started.Add(duplexProbeDuration + cfg.MaxLatency), reply: make(chan captured, 1)}
+	var after captured
+	select {
+	case requests <- req:
+	case <-ctx.Done():
+		return DuplexReport{}, ctx.Err()
+	}
+	select {
+	case after = <-req.reply:
+	case <-ctx.Done():
+		return DuplexReport{}, ctx.Err()
+	}
+	if after.err != nil {
+		return DuplexReport{}, after.err
+	}
+	playedAt := min(max(int(started.Sub(after.start).Seconds()*float64(cfg.SampleRate)+0.5), 0), len(after.samples))
+
+	maxLag := int(cfg.MaxLatency.Seconds() * float64(cfg.SampleRate))
+	window := after.samples[playedAt:]
+	if len(window) > maxLag+len(probe) {
+		window = window[:maxLag+len(probe)]
+	}
+	lag, corr := crossCorrelationPeak(probe, window)
+
+	report := DuplexReport{Correlation: corr}
+	heard := corr >= duplexEchoThreshold
+	echoStart := 0
+	if heard {
+		report.Latency = time.Duration(lag) * time.Second / time.Duration(cfg.SampleRate)
+		echoStart = lag
+	}
+	echo := window[min(echoStart, len(window)):min(echoStart+len(probe), len(window))]
+	report.AttenuationDB = dbfs(rms(probe)) - dbfs(rms(echo))
+
+	switch {
+	case cfg.MinAttenuationDB == 0 && !heard:
+		return report, fmt.Errorf("duplex probe not heard within %v (correlation %.2f)", cfg.MaxLatency, corr)
+	case cfg.MinAttenuationDB != 0 && report.AttenuationDB < cfg.MinAttenuationDB:
+		return report, fmt.Errorf("echo attenuated by %.1f dB, need at least %.1f dB", report.AttenuationDB, cfg.MinAttenuationDB)
+	}
+	return report, nil
+}
+
+// chirp returns a linear sweep from f0 to f1 Hz with the given amplitude.
+func chirp(sampleRate int, d time.Duration, f0, f1, amplitude float64) []float32 {
+	n := int(d.Seconds() * float64(sampleRate))
+	out := make([]float32, n)
+	k := (f1 - f0) / d.Seconds()
+	for i := range out {
+		t := float64(i) / float64(sampleRate)
+		out[i] = float32(amplitude * math.Sin(2*math.Pi*(f0*t+k*t*t/2)))
+	}
+	return out
+}
+
+// crossCorrelationPeak slides probe over signal and returns the offset with
+// the highest normalized correlation.
+func crossCorrelationPeak(probe, signal []float32) (int, float64) {
+	var probeEnergy float64
+	for _, p := range probe {
+		probeEnergy += float64(p) * float64(p)
+	}
+	if probeEnergy == 0 || len(signal) < len(probe) {
+		return 0, 0
+	}
+
+	// running energy of the signal under the probe
+	var windowEnergy float64
+	for _, s := range signal[:len(probe)] {
+		windowEnergy += float64(s) * float64(s)
+	}
+	bestLag, best := 0, 0.0
+	for lag := 0; lag+len(probe) <= len(signal); lag++ {
+		if lag > 0 {
+			out, in := float64(signal[lag-1]), float64(signal[lag+len(probe)-1])
+			windowEnergy += in*in - out*out
+		}
+		if windowEnergy <= 1e-12 {
+			continue
+		}
+		var sum float64
+		for i, p := range probe {
+			sum += float64(p) * float64(signal[lag+i])
+		}
+		if c := sum / math.Sqrt(probeEnergy*windowEnergy); c > best {
+			bestLag, best = lag, c
+		}
+	}
+	return bestLag, best
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
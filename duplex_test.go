@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// runClock advances c in device-sized steps until the returned stop is
+// called, giving the resource time to keep up between steps.
+func runClock(c *ManualClock, step time.Duration) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(2 * time.Millisecond):
+				c.Advance(step)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func TestDuplexCheckLoopback(t *testing.T) {
+	const (
+		latency = 40 * time.Millisecond
+		chunk   = 10 * time.Millisecond
+	)
+	for _, tc := range []struct {
+		name           string
+		minAttenuation float64
+		wantErr        bool
+	}{
+		{"probe heard", 0, false},
+		// a loopback doesn't cancel echo, so requiring it fails
+		{"echo cancellation required", 20, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := NewManualClock(time.Unix(1000, 0))
+			a := NewLoopback(Named("loop"), LoopbackConfig{
+				SampleRate: 48000,
+				ChunkMs:    int(chunk / time.Millisecond),
+				LatencyMs:  int(latency / time.Millisecond),
+				Clock:      clock,
+			}, logging.NewTestLogger(t))
+			defer a.Close(context.Background())
+			defer runClock(clock, chunk)()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+			report, err := DuplexCheck(ctx, a, DuplexCheckConfig{MaxLatency: 200 * time.Millisecond, MinAttenuationDB: tc.minAttenuation})
+			if ctx.Err() != nil {
+				t.Fatal("duplex check did not finish")
+			}
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tc.wantErr)
+			}
+			// Play lands on the next device period, which the check may see
+			// one period early or late
+			if d := report.Latency - latency; d < -chunk || d > chunk {
+				t.Fatalf("measured latency %v, want %v within one %v period", report.Latency, latency, chunk)
+			}
+			if report.Correlation < 0.9 {
+				t.Fatalf("correlation %.2f, want the probe clearly heard", report.Correlation)
+			}
+			if math.Abs(report.AttenuationDB) > 1 {
+				t.Fatalf("attenuation %.2f dB, want about 0 through a loopback", report.AttenuationDB)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// EQModel wraps another Audio and filters everything played through it with
+// a chain of EQ bands, so small robot speakers can be tuned. The chain comes
+// from the config and can be replaced at runtime with SetEQ.
+var EQModel = resource.NewModel("olivia", "audio", "eq")
+
+// EQSetter is implemented by Audio resources and clients whose playback runs
+// through an adjustable EQ chain.
+type EQSetter interface {
+	// SetEQ replaces the whole chain. No bands plays audio unfiltered.
+	SetEQ(ctx context.Context, bands []EQBand) error
+	GetEQ(ctx context.Context) ([]EQBand, error)
+}
+
+// EQConfig is the configuration of the eq model.
+type EQConfig struct {
+	Output string   `json:"output"` // Audio resource to play through
+	Bands  []EQBand `json:"bands,omitempty"`
+}
+
+// checkEQ reports the first band that can't be built.
+func checkEQ(bands []EQBand) error {
+	for i, band := range bands {
+		// 48 kHz is only a stand-in to check the band, filters are built for each clip's rate
+		if _, err := newBiquad(band.Type, 48000, band.FrequencyHz, band.GainDB, band.Q, 1); err != nil {
+			return fmt.Errorf("band %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the eq configuration and returns the output as a
+// dependency.
+func (c *EQConfig) Validate(path string) ([]string, []string, error) {
+	if c.Output == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "output")
+	}
+	if err := checkEQ(c.Bands); err != nil {
+		return nil, nil, resource.NewConfigValidationError(path, err)
+	}
+	return []string{c.Output}, nil, nil
+}
+
+func init() {
+	registerModel(EQModel, resource.Registration[Audio, *EQConfig]{
+		AttributeMapConverter: migratingConverter[*EQConfig](EQModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*EQConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			output, err := resource.FromDependencies[Audio](deps, Named(cfg.Output))
+			if err != nil {
+				return nil, err
+			}
+			return NewEQ(conf.ResourceName(), output, *cfg, logger)
+		},
+	})
+}
+
+// equalized takes new bands and a rebuilt output in place, so reconfiguring
+// it doesn't end the streams through it.
+type equalized struct {
+	resource.Named
+	*Reconfigurable[EQConfig]
+	resource.TriviallyCloseable
+
+	logger logging.Logger
+
+	// the chain keeps its state between clips, so audio streamed as
+	// consecutive Play calls doesn't click at the joins
+	mu       sync.Mutex
+	output   Audio
+	bands    []EQBand
+	chain    []*biquad // built for rate and channels, nil until the next clip
+	rate     int
+	channels int
+}
+
+// NewEQ returns a resource playing through output with cfg's EQ chain.
+func NewEQ(name resource.Name, output Audio, cfg EQConfig, logger logging.Logger) (Audio, error) {
+	if err := checkEQ(cfg.Bands); err != nil {
+		return nil, err
+	}
+	e := &equalized{
+		Named:  name.AsNamed(),
+		output: output,
+		logger: logger,
+		bands:  append([]EQBand(nil), cfg.Bands...),
+	}
+	e.Reconfigurable = NewReconfigurable(cfg, e.reconfigure)
+	return e, nil
+}
+
+// reconfigure plays through the output as of deps, and replaces the bands
+// if the config changed them. Bands set with SetEQ are kept otherwise.
+func (e *equalized) reconfigure(ctx context.Context, deps resource.Dependencies, old, new EQConfig) (bool, error) {
+	if err := checkEQ(new.Bands); err != nil {
+		return false, err
+	}
+	output, err := resource.FromDependencies[Audio](deps, Named(new.Output))
+	if err != nil {
+		return false, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.output = output
+	if !slices.Equal(old.Bands, new.Bands) {
+		e.bands = append([]EQBand(nil), new.Bands...)
+		e.chain = nil
+	}
+	return true, nil
+}
+
+// playbackOutput returns the resource the eq plays through.
+func (e *equalized) playbackOutput() Audio {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.output
+}
+
+func (e *equalized) SetEQ(ctx context.Context, bands []EQBand) error {
+	if err := checkEQ(bands); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.bands = append([]EQBand(nil), bands...)
+	e.chain = nil
+	return nil
+}
+
+func (e *equalized) GetEQ(ctx context.Context) ([]EQBand, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]EQBand(nil), e.bands...), nil
+}
+
+func (e *equalized) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	return e.playbackOutput().GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+}
+
+// Play filters raw pcm audio through the chain and plays it on the output.
+func (e *equalized) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return fmt.Errorf("eq can only filter raw pcm: %w", err)
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	samples, err := decodePCM(data, format)
+	if err != nil {
+		return err
+	}
+	if err := e.filter(samples, sampleRate, channels); err != nil {
+		return err
+	}
+	out, err := encodePCM(samples, format)
+	if err != nil {
+		return err
+	}
+	return e.playbackOutput().Play(ctx, out, codec, sampleRate, channels)
+}
+
+// filter runs samples through the chain in place, rebuilding it when the
+// bands or the format changed.
+func (e *equalized) filter(samples []float32, rate, channels int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.chain == nil || rate != e.rate || channels != e.channels {
+		chain := make([]*biquad, 0, len(e.bands))
+		for _, band := range e.bands {
+			f, err := newBiquad(band.Type, rate, band.FrequencyHz, band.GainDB, band.Q, channels)
+			if err != nil {
+				return err
+			}
+			chain = append(chain, f)
+		}
+		e.chain, e.rate, e.channels = chain, rate, channels
+	}
+	for _, f := range e.chain {
+		f.process(samples)
+	}
+	for i, s := range samples {
+		samples[i] = clip(s)
+	}
+	return nil
+}
+
+func (e *equalized) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, e, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (s *audioServer) SetEQ(ctx context.Context, req *pb.SetEQRequest) (*pb.SetEQResponse, error) {
+	es, err := s.eqSetter(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := es.SetEQ(ctx, eqBandsFromProto(req.Bands)); err != nil {
+		return nil, err
+	}
+	return &pb.SetEQResponse{}, nil
+}
+
+func (s *audioServer) GetEQ(ctx context.Context, req *pb.GetEQRequest) (*pb.GetEQResponse, error) {
+	es, err := s.eqSetter(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	bands, err := es.GetEQ(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetEQResponse{Bands: eqBandsToProto(bands)}, nil
+}
+
+func (s *audioServer) eqSetter(name string) (EQSetter, error) {
+	a, err := s.resource(name)
+	if err != nil {
+		return nil, err
+	}
+	es, ok := a.(EQSetter)
+	if !ok {
+		return nil, errorf(ErrUnsupported, "%s does not support an adjustable eq", name)
+	}
+	return es, nil
+}
+
+func (c *audioClient) SetEQ(ctx context.Context, bands []EQBand) error {
+	_, err := c.client.SetEQ(ctx, &pb.SetEQRequest{Name: c.name, Bands: eqBandsToProto(bands)})
+	return err
+}
+
+func (c *audioClient) GetEQ(ctx context.Context) ([]EQBand, error) {
+	resp, err := c.client.GetEQ(ctx, &pb.GetEQRequest{Name: c.name})
+	if err != nil {
+		return nil, err
+	}
+	return eqBandsFromProto(resp.Bands), nil
+}
+
+func eqBandsToProto(bands []EQBand) []*pb.EQBand {
+	out := make([]*pb.EQBand, len(bands))
+	for i, b := range bands {
+		out[i] = &pb.EQBand{Type: b.Type, FrequencyHz: float32(b.FrequencyHz), GainDb: float32(b.GainDB), Q: float32(b.Q)}
+	}
+	return out
+}
+
+func eqBandsFromProto(bands []*pb.EQBand) []EQBand {
+	out := make([]EQBand, len(bands))
+	for i, b := range bands {
+		out[i] = EQBand{Type: b.Type, FrequencyHz: float64(b.FrequencyHz), GainDB: float64(b.GainDb), Q: float64(b.Q)}
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestEQChain(t *testing.T) {
+	const rate = 16000
+	a, err := NewEQ(Named("eq"), newBurstSource(0, AudioInfo{}), EQConfig{
+		Bands: []EQBand{{Type: "highpass", FrequencyHz: 1000}},
+	}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := a.(*equalized)
+	ctx := context.Background()
+
+	mix := func() []float32 {
+		low, high := tone(rate, rate/2, 100, 0.3), tone(rate, rate/2, 3000, 0.3)
+		for i := range low {
+			low[i] += high[i]
+		}
+		return low
+	}
+	// filtered in one go and as two clips, the joins don't show
+	whole, halves := mix(), mix()
+	if err := e.filter(whole, rate, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SetEQ(ctx, []EQBand{{Type: "highpass", FrequencyHz: 1000}}); err != nil {
+		t.Fatal(err)
+	}
+	e.filter(halves[:rate/4], rate, 1)
+	e.filter(halves[rate/4:], rate, 1)
+	for i := range whole {
+		if whole[i] != halves[i] {
+			t.Fatalf("sample %d differs between one clip and two", i)
+		}
+	}
+	settled := whole[rate/10:]
+	if low := dbfs(bandLevel(settled, rate, 100)); low > -50 {
+		t.Fatalf("100 Hz at %.1f dBFS through the highpass", low)
+	}
+	if high := dbfs(bandLevel(settled, rate, 3000)); math.Abs(high-dbfs(0.3/math.Sqrt2)) > 0.5 {
+		t.Fatalf("3 kHz at %.1f dBFS through the highpass", high)
+	}
+
+	boost := []EQBand{{Type: "peaking", FrequencyHz: 100, GainDB: 6, Q: 1}}
+	if err := e.SetEQ(ctx, boost); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SetEQ(ctx, []EQBand{{Type: "notch", FrequencyHz: 100}}); err == nil {
+		t.Fatal("accepted an unknown filter type")
+	}
+	if bands, _ := e.GetEQ(ctx); len(bands) != 1 || bands[0] != boost[0] {
+		t.Fatalf("got bands %+v, want %+v", bands, boost)
+	}
+	samples := tone(rate, rate/2, 100, 0.1)
+	e.filter(samples, rate, 1)
+	if got := dbfs(bandLevel(samples[rate/10:], rate, 100)) - dbfs(0.1/math.Sqrt2); math.Abs(got-6) > 0.5 {
+		t.Fatalf("peaking band boosted 100 Hz by %.1f dB, want 6", got)
+	}
+	if bands := eqBandsFromProto(eqBandsToProto(boost)); bands[0] != boost[0] {
+		t.Fatalf("bands changed over the wire: %+v", bands)
+	}
+}
@@ -0,0 +1,164 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain names this API in the ErrorInfo an Error is sent with.
+const errorDomain = "audio.viam.com"
+
+// The kinds of failure callers can tell apart with errors.Is, on servers
+// and on clients alike. Each goes out as its gRPC status code; clients
+// that get one back from a server return an error of the same kind.
+var (
+	// ErrInvalidArgument is a request the server can't make sense of.
+	ErrInvalidArgument = newKind(codes.InvalidArgument, "INVALID_ARGUMENT", "invalid argument")
+	// ErrUnsupportedCodec is a codec the resource can't stream.
+	ErrUnsupportedCodec = newKind(codes.InvalidArgument, "UNSUPPORTED_CODEC", "unsupported codec")
+	// ErrNotFound is a resource the server doesn't have.
+	ErrNotFound = newKind(codes.NotFound, "NOT_FOUND", "no such resource")
+	// ErrNoDevice is a resource without the device asked of it, or whose
+	// device is gone.
+	ErrNoDevice = newKind(codes.NotFound, "NO_DEVICE", "no such device")
+	// ErrDeviceBusy is a device that is there but couldn't be opened,
+	// usually as something else holds it. Trying again later may work.
+	ErrDeviceBusy = newKind(codes.Unavailable, "DEVICE_BUSY", "the device is busy")
+	// ErrClosed is a resource that was closed, by being reconfigured or
+	// removed.
+	ErrClosed = newKind(codes.Unavailable, "CLOSED", "audio resource is closed")
+	// ErrShuttingDown ends the streams of a server that is shutting down,
+	// and refuses the calls it is asked meanwhile. Clients reconnecting
+	// WithReconnect try again.
+	ErrShuttingDown = newKind(codes.Unavailable, "SHUTTING_DOWN", "the audio server is shutting down")
+	// ErrFormatMismatch is audio that isn't in the format a strict call
+	// needs, see WithStrict.
+	ErrFormatMismatch = newKind(codes.FailedPrecondition, "FORMAT_MISMATCH", "the audio is not in the format needed")
+	// ErrUnsupported is a call the resource or server doesn't implement.
+	ErrUnsupported = newKind(codes.Unimplemented, "UNSUPPORTED", "not supported")
+	// ErrChecksumMismatch is a chunk whose audio doesn't match the checksum
+	// the server sent with it, see WithChecksums.
+	ErrChecksumMismatch = newKind(codes.DataLoss, "CHECKSUM_MISMATCH", "the audio doesn't match its checksum")
+)
+
+// kinds are the Err values by reason, for clients to map errors back.
+var kinds = map[string]*Error{}
+
+func newKind(code codes.Code, reason, msg string) *Error {
+	e := &Error{Code: code, Reason: reason, msg: msg}
+	kinds[reason] = e
+	return e
+}
+
+// Error is a failure of one of the kinds above. errors.Is matches it to
+// its kind whatever its message. status.FromError gives its code, with an
+// ErrorInfo detail naming the kind for clients.
+type Error struct {
+	Code   codes.Code
+	Reason string // the kind, as sent in the ErrorInfo
+	msg    string
+	err    error // wrapped, if any
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is matches errors of the same kind.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Reason == e.Reason
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	s := status.New(e.Code, e.msg)
+	if d, err := s.WithDetails(&errdetails.ErrorInfo{Reason: e.Reason, Domain: errorDomain}); err == nil {
+		return d
+	}
+	return s
+}
+
+// errorf returns an error of kind with the message fmt.Errorf would give,
+// wrapping what it would wrap, one error or several, so errors.Is and
+// errors.As find them all.
+func errorf(kind *Error, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	e := &Error{Code: kind.Code, Reason: kind.Reason, msg: err.Error()}
+	switch err.(type) {
+	case interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		e.err = err
+	}
+	return e
+}
+
+// deviceError is the error of a device that couldn't be opened: missing
+// or busy.
+func deviceError(err error, format string, args ...any) error {
+	kind := ErrDeviceBusy
+	if errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.ENODEV) || errors.Is(err, ErrNoDevice) {
+		kind = ErrNoDevice
+	}
+	return errorf(kind, format+": %w", append(args, err)...)
+}
+
+// errorFromStatus returns err, as received from a server, as the kind of
+// error it was sent as. Errors not sent as one of the kinds are returned
+// as they are.
+func errorFromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() == codes.OK {
+		return err
+	}
+	for _, d := range s.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != errorDomain {
+			continue
+		}
+		if kind, ok := kinds[info.Reason]; ok {
+			return &Error{Code: kind.Code, Reason: kind.Reason, msg: s.Message(), err: err}
+		}
+	}
+	return err
+}
+
+// typedErrorsConn returns the errors of calls on a connection as the kinds
+// of error they were sent as.
+type typedErrorsConn struct {
+	grpc.ClientConnInterface
+}
+
+func (c typedErrorsConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return errorFromStatus(c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...))
+}
+
+func (c typedErrorsConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	cs, err := c.ClientConnInterface.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		return nil, errorFromStatus(err)
+	}
+	return typedErrorsStream{cs}, nil
+}
+
+type typedErrorsStream struct {
+	grpc.ClientStream
+}
+
+func (s typedErrorsStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		return err
+	}
+	return errorFromStatus(err)
+}
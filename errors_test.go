@@ -0,0 +1,103 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+func TestErrorKinds(t *testing.T) {
+	err := errorf(ErrDeviceBusy, "cannot open %s for capture: %w", "hw:1", syscall.EBUSY)
+	if err.Error() != "cannot open hw:1 for capture: "+syscall.EBUSY.Error() {
+		t.Errorf("message is %q", err)
+	}
+	if !errors.Is(err, ErrDeviceBusy) || !errors.Is(err, syscall.EBUSY) || errors.Is(err, ErrClosed) {
+		t.Error("errors.Is doesn't match the kind and what it wraps alone")
+	}
+	// wrapped further, the kind still sets the code
+	if s := status.Convert(fmt.Errorf("capture: %w", err)); s.Code() != codes.Unavailable {
+		t.Errorf("sent as %s", s.Code())
+	}
+
+	// clients get the kind back, telling apart kinds of the same code
+	for _, kind := range []*Error{ErrDeviceBusy, ErrClosed, ErrShuttingDown, ErrNoDevice, ErrNotFound} {
+		received := status.Convert(errorf(kind, "failed")).Err()
+		got := errorFromStatus(received)
+		for _, other := range []*Error{ErrDeviceBusy, ErrClosed, ErrShuttingDown, ErrNoDevice, ErrNotFound} {
+			if errors.Is(got, other) != (other == kind) {
+				t.Errorf("%s received: errors.Is(%s) is %v", kind.Reason, other.Reason, !(other == kind))
+			}
+		}
+		if status.Code(got) != kind.Code || got.Error() != "failed" {
+			t.Errorf("%s received as %s %q", kind.Reason, status.Code(got), got)
+		}
+	}
+	// other errors are left as they came
+	plain := status.Error(codes.Internal, "boom")
+	if got := errorFromStatus(plain); got != plain {
+		t.Errorf("%v received as %v", plain, got)
+	}
+
+	if err := deviceError(syscall.ENOENT, "cannot open %s", "hw:9"); !errors.Is(err, ErrNoDevice) {
+		t.Errorf("a missing device is %v", err)
+	}
+	// every error wrapped is found, not only a lone one
+	err = errorf(ErrNoDevice, "%w, then %w", syscall.ENODEV, context.Canceled)
+	if !errors.Is(err, syscall.ENODEV) || !errors.Is(err, context.Canceled) || !errors.Is(err, ErrNoDevice) {
+		t.Errorf("errors.Is doesn't match what %v wraps", err)
+	}
+	// caller mistakes go out as invalid arguments
+	if err := checkDropPolicy("sometimes", 0); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("an unknown drop policy is sent as %s", status.Code(err))
+	}
+}
+
+func TestErrorsOverTheWire(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("fake"), FakeConfig{}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	c := serveAudio(t, f)
+
+	ch, err := c.GetAudio(ctx, "flac", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := <-ch
+	if chunk == nil || !errors.Is(chunk.Err, ErrUnsupportedCodec) || status.Code(chunk.Err) != codes.InvalidArgument {
+		t.Errorf("an unknown codec failed with %v", chunk)
+	}
+	if err := c.Play(ctx, []byte{0, 0}, "flac", 8000, 1); !errors.Is(err, ErrUnsupportedCodec) {
+		t.Errorf("playing an unknown codec failed with %v", err)
+	}
+	ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0, WithBatching(maxBatchChunks+1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || !errors.Is(chunk.Err, ErrInvalidArgument) {
+		t.Errorf("an oversized batch failed with %v", chunk)
+	}
+
+	coll, err := resource.NewAPIResourceCollection[Audio](API, map[resource.Name]Audio{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewRPCServiceServer(coll).(pb.AudioServiceServer)
+	_, err = s.GetLatencyStats(ctx, &pb.GetLatencyStatsRequest{Name: "nope"})
+	if !errors.Is(err, ErrNotFound) || status.Code(err) != codes.NotFound {
+		t.Errorf("an unknown resource failed with %v", err)
+	}
+}
@@ -0,0 +1,409 @@
+package audio
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// FakeModel is an Audio without hardware for development and CI: GetAudio
+// delivers a deterministic signal, a tone, white noise, silence or a looped
+// file, and Play keeps what it was given in memory. Every stream starts at
+// the start of the signal, so two runs capture the same bytes, unlike sim,
+// whose streams join a scenario already under way.
+var FakeModel = resource.NewModel("olivia", "audio", "fake")
+
+// Defaults for FakeConfig.
+const (
+	defaultFakeSampleRate = 16000
+	defaultFakeToneHz     = 440
+	defaultFakeLevelDBFS  = -20.0
+	defaultFakeMaxPlays   = 100
+)
+
+// FakeConfig is the configuration of the fake model.
+type FakeConfig struct {
+	// Signal is "tone" (the default), "noise", "silence" or "file".
+	Signal      string  `json:"signal,omitempty"`
+	FrequencyHz float64 `json:"frequency_hz,omitempty"` // for tones, 440 Hz if zero
+	// LevelDBFS is the peak of a tone or the RMS of noise, -20 if zero.
+	LevelDBFS float64 `json:"level_dbfs,omitempty"`
+	File      string  `json:"file,omitempty"` // .wav or .flac looped for signal "file"
+	// Seed picks the noise, so differently seeded fakes aren't correlated.
+	Seed       uint64 `json:"seed,omitempty"`
+	SampleRate int    `json:"sample_rate,omitempty"` // 16 kHz if zero
+	Channels   int    `json:"channels,omitempty"`    // 1 if zero, all carrying the same signal
+	ChunkMs    int    `json:"chunk_ms,omitempty"`    // 100ms if zero
+	MaxPlays   int    `json:"max_plays,omitempty"`   // plays kept, oldest dropped first, 100 if zero
+	// Unpaced delivers capture as fast as it's read and returns from Play at
+	// once, for tests that only care about the audio.
+	Unpaced bool `json:"unpaced,omitempty"`
+	// PlaybackSampleRates and PlaybackChannels are what Play takes, any if
+	// empty, to stand in for devices that only play some formats.
+	PlaybackSampleRates []int `json:"playback_sample_rates,omitempty"`
+	PlaybackChannels    []int `json:"playback_channels,omitempty"`
+
+	// Clock paces capture and playback, SystemClock if nil.
+	Clock ClockSource `json:"-"`
+}
+
+// Validate checks the fake configuration.
+func (c *FakeConfig) Validate(path string) ([]string, []string, error) {
+	switch c.Signal {
+	case "", "tone", "noise", "silence":
+	case "file":
+		if c.File == "" {
+			return nil, nil, resource.NewConfigValidationError(path, errors.New("signal file needs a file"))
+		}
+	default:
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("unknown signal %q, expected tone, noise, silence or file", c.Signal))
+	}
+	if c.FrequencyHz < 0 || c.SampleRate < 0 || c.Channels < 0 || c.ChunkMs < 0 || c.MaxPlays < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("frequency_hz, sample_rate, channels, chunk_ms and max_plays cannot be negative"))
+	}
+	if c.LevelDBFS > 0 {
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("level_dbfs must be at most full scale, got %v", c.LevelDBFS))
+	}
+	return nil, nil, nil
+}
+
+func init() {
+	registerModel(FakeModel, resource.Registration[Audio, *FakeConfig]{
+		AttributeMapConverter: migratingConverter[*FakeConfig](FakeModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*FakeConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			f, err := NewFake(conf.ResourceName(), *cfg, logger)
+			if err != nil {
+				return nil, err
+			}
+			return f, nil
+		},
+	})
+}
+
+// FakePlay is a clip played on a Fake.
+type FakePlay struct {
+	Data       []byte
+	Codec      string
+	SampleRate int
+	Channels   int
+	At         time.Time // when Play was called, on the fake's clock
+}
+
+// Fake is a resource of the fake model. Tests building one directly can
+// inspect what was played with Plays. Reconfiguring its signal or what it
+// plays leaves its streams running; its rate, channels, chunks and pacing
+// rebuild it.
+type Fake struct {
+	resource.Named
+	*Reconfigurable[FakeConfig]
+
+	info    AudioInfo
+	chunk   int // frames per chunk
+	unpaced bool
+	clock   ClockSource
+	logger  logging.Logger
+
+	mu       sync.Mutex
+	signal   func(frame int64) float32
+	maxPlays int
+	playback Properties
+	plays    []FakePlay
+
+	streams streamGroup
+}
+
+// NewFake returns a fake resource for cfg. A file signal is decoded here,
+// so a missing file fails the configuration.
+func NewFake(name resource.Name, cfg FakeConfig, logger logging.Logger) (*Fake, error) {
+	cfg = cfg.withDefaults()
+	signal, err := fakeSignal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	f := &Fake{
+		Named:    name.AsNamed(),
+		info:     AudioInfo{Format: Pcm32Float, SampleRate: cfg.SampleRate, Channels: cfg.Channels},
+		chunk:    max(1, cfg.SampleRate*cfg.ChunkMs/1000),
+		unpaced:  cfg.Unpaced,
+		clock:    cfg.Clock,
+		logger:   logger,
+		signal:   signal,
+		maxPlays: cfg.MaxPlays,
+		playback: Properties{PlaybackSampleRates: cfg.PlaybackSampleRates, PlaybackChannels: cfg.PlaybackChannels},
+	}
+	f.Reconfigurable = NewReconfigurable(cfg, f.reconfigure)
+	return f, nil
+}
+
+// withDefaults fills in what c leaves zero.
+func (c FakeConfig) withDefaults() FakeConfig {
+	if c.SampleRate == 0 {
+		c.SampleRate = defaultFakeSampleRate
+	}
+	if c.Channels == 0 {
+		c.Channels = 1
+	}
+	if c.ChunkMs == 0 {
+		c.ChunkMs = int(defaultFileSourceChunk / time.Millisecond)
+	}
+	if c.FrequencyHz == 0 {
+		c.FrequencyHz = defaultFakeToneHz
+	}
+	if c.LevelDBFS == 0 {
+		c.LevelDBFS = defaultFakeLevelDBFS
+	}
+	if c.MaxPlays == 0 {
+		c.MaxPlays = defaultFakeMaxPlays
+	}
+	if c.Clock == nil {
+		c.Clock = SystemClock
+	}
+	return c
+}
+
+// fakeSignal returns the signal cfg, with defaults, generates.
+func fakeSignal(cfg FakeConfig) (func(frame int64) float32, error) {
+	level := float32(math.Pow(10, cfg.LevelDBFS/20))
+	switch cfg.Signal {
+	case "", "tone":
+		step := 2 * math.Pi * cfg.FrequencyHz / float64(cfg.SampleRate)
+		return func(frame int64) float32 { return level * float32(math.Sin(step*float64(frame))) }, nil
+	case "noise":
+		seed := cfg.Seed * 0x9E3779B97F4A7C15
+		return func(frame int64) float32 { return level * simNoise(seed+uint64(frame)) }, nil
+	case "silence":
+		return func(int64) float32 { return 0 }, nil
+	case "file":
+		clip, err := readMonoFile(cfg.File, cfg.SampleRate)
+		if err != nil {
+			return nil, err
+		}
+		if len(clip) == 0 {
+			return nil, fmt.Errorf("%s has no audio", cfg.File)
+		}
+		return func(frame int64) float32 { return clip[frame%int64(len(clip))] }, nil
+	default:
+		return nil, fmt.Errorf("unknown signal %q", cfg.Signal)
+	}
+}
+
+// reconfigure takes a new signal and what the fake plays in place. The
+// clock it was built with is kept.
+func (f *Fake) reconfigure(ctx context.Context, _ resource.Dependencies, old, new FakeConfig) (bool, error) {
+	old, new = old.withDefaults(), new.withDefaults()
+	if new.SampleRate != old.SampleRate || new.Channels != old.Channels || new.ChunkMs != old.ChunkMs || new.Unpaced != old.Unpaced {
+		return false, nil
+	}
+	signal, err := fakeSignal(new)
+	if err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.signal = signal
+	f.maxPlays = new.MaxPlays
+	f.playback = Properties{PlaybackSampleRates: new.PlaybackSampleRates, PlaybackChannels: new.PlaybackChannels}
+	if len(f.plays) > f.maxPlays {
+		f.plays = slices.Delete(f.plays, 0, len(f.plays)-f.maxPlays)
+	}
+	return true, nil
+}
+
+// render returns the frames [from, from+n) of the signal.
+func (f *Fake) render(from int64, n int) []float32 {
+	ch := f.info.Channels
+	f.mu.Lock()
+	signal := f.signal
+	f.mu.Unlock()
+	out := make([]float32, n*ch)
+	for i := 0; i < n; i++ {
+		v := signal(from + int64(i))
+		for c := 0; c < ch; c++ {
+			out[i*ch+c] = v
+		}
+	}
+	return out
+}
+
+// GetAudio streams the signal from its start in the requested raw pcm
+// format.
+func (f *Fake) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, err
+	}
+	conv := transcoderFor(format, NewGetAudioOptions(opts...))
+	remaining := int64(-1)
+	if durationSeconds > 0 {
+		remaining = int64(float64(durationSeconds) * float64(f.info.SampleRate))
+	}
+	ctx, done, err := f.streams.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		pacer := newSamplePacer(f.clock, f.info.SampleRate)
+		var frame int64
+		for seq := int64(0); remaining != 0; seq++ {
+			n := f.chunk
+			if remaining > 0 && int64(n) > remaining {
+				n = int(remaining)
+			}
+			at := pacer.timestamp(frame)
+			if !f.unpaced {
+				var err error
+				if at, err = pacer.wait(ctx, n); err != nil {
+					return
+				}
+			}
+			info := f.info
+			chunk, err := conv.convertSamples(f.render(frame, n), &AudioChunk{Sequence: seq, Info: &info, Timestamp: at})
+			if err != nil {
+				chunk, remaining = &AudioChunk{Err: err}, 0
+			}
+			frame += int64(n)
+			if remaining > 0 {
+				remaining -= int64(n)
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Play records the clip and takes as long as it would to play out, unless
+// the fake is unpaced.
+func (f *Fake) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	props := f.playback
+	f.mu.Unlock()
+	if err := checkPlayback(f.Name().ShortName(), props, codec, sampleRate, channels); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.plays = append(f.plays, FakePlay{Data: slices.Clone(data), Codec: codec, SampleRate: sampleRate, Channels: channels, At: f.clock.Now()})
+	if len(f.plays) > f.maxPlays {
+		f.plays = slices.Delete(f.plays, 0, len(f.plays)-f.maxPlays)
+	}
+	f.mu.Unlock()
+	width, err := bytesPerSample(format)
+	if f.unpaced || err != nil {
+		// encoded clips are kept without being timed
+		return nil
+	}
+	frames := len(data) / (width * channels)
+	return f.clock.Sleep(ctx, time.Duration(frames)*time.Second/time.Duration(sampleRate))
+}
+
+// Plays returns the clips played so far, oldest first.
+func (f *Fake) Plays() []FakePlay {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.plays)
+}
+
+// LastPlay returns the clip played last, if any.
+func (f *Fake) LastPlay() (FakePlay, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.plays) == 0 {
+		return FakePlay{}, false
+	}
+	return f.plays[len(f.plays)-1], true
+}
+
+// ResetPlays forgets the clips played so far.
+func (f *Fake) ResetPlays() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.plays = nil
+}
+
+// Properties returns the formats Play takes, as configured.
+func (f *Fake) Properties(ctx context.Context) (Properties, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.playback, nil
+}
+
+func (f *Fake) deviceClock() ClockSource { return f.clock }
+
+// backendDevices lists the fake as its only device.
+func (f *Fake) backendDevices() (deviceList, error) {
+	name := f.Name().ShortName()
+	return deviceList{
+		devices:         []SoundDevice{{ID: name, Name: name, Driver: "fake", Capture: true, Playback: true}},
+		defaultCapture:  name,
+		defaultPlayback: name,
+	}, nil
+}
+
+// DoCommand supports {"plays": true}, which lists the clips played with a
+// SHA-256 of each so tests over the API can check them without moving
+// them, {"reset_plays": true}, and the commands every built-in model
+// accepts.
+func (f *Fake) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, f, cmd); ok {
+		return resp, err
+	}
+	switch {
+	case cmd["plays"] != nil:
+		plays := f.Plays()
+		list := make([]interface{}, len(plays))
+		for i, p := range plays {
+			sum := sha256.Sum256(p.Data)
+			list[i] = map[string]interface{}{
+				"codec":       p.Codec,
+				"sample_rate": p.SampleRate,
+				"channels":    p.Channels,
+				"bytes":       len(p.Data),
+				"sha256":      hex.EncodeToString(sum[:]),
+				"at":          p.At.Format(time.RFC3339Nano),
+			}
+		}
+		return map[string]interface{}{"plays": list}, nil
+	case cmd["reset_plays"] != nil:
+		f.mu.Lock()
+		n := len(f.plays)
+		f.plays = nil
+		f.mu.Unlock()
+		return map[string]interface{}{"reset": n}, nil
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close ends every stream and waits for them to return.
+func (f *Fake) Close(ctx context.Context) error {
+	return f.streams.close(ctx)
+}
@@ -0,0 +1,185 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// captureFake returns d of pcm16 from a as one buffer.
+func captureFake(t *testing.T, a Audio, d float32) []byte {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), d, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b []byte
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatal(c.Err)
+		}
+		b = append(b, c.AudioData...)
+	}
+	return b
+}
+
+func TestFakeSignals(t *testing.T) {
+	newFake := func(cfg FakeConfig) *Fake {
+		t.Helper()
+		cfg.Unpaced = true
+		f, err := NewFake(Named("fake"), cfg, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { f.Close(context.Background()) })
+		return f
+	}
+
+	tone := newFake(FakeConfig{})
+	first := captureFake(t, tone, 0.25)
+	if len(first) != 2*4000 {
+		t.Fatalf("captured %d bytes, want 8000", len(first))
+	}
+	if again := captureFake(t, tone, 0.25); !bytes.Equal(first, again) {
+		t.Error("two captures of the tone differ")
+	}
+	samples, _ := decodePCM(first, Pcm16)
+	var peak float64
+	for _, s := range samples {
+		peak = max(peak, math.Abs(float64(s)))
+	}
+	if db := 20 * math.Log10(peak); math.Abs(db+20) > 0.1 {
+		t.Errorf("tone peaks at %.2f dBFS, want -20", db)
+	}
+
+	noise := captureFake(t, newFake(FakeConfig{Signal: "noise", Seed: 1}), 0.25)
+	if bytes.Equal(noise, captureFake(t, newFake(FakeConfig{Signal: "noise", Seed: 2}), 0.25)) {
+		t.Error("differently seeded noise is the same")
+	}
+	if !bytes.Equal(noise, captureFake(t, newFake(FakeConfig{Signal: "noise", Seed: 1}), 0.25)) {
+		t.Error("noise with the same seed differs")
+	}
+
+	// a 100 frame ramp loops
+	dir := t.TempDir()
+	var wav bytes.Buffer
+	ramp := make([]float32, 100)
+	for i := range ramp {
+		ramp[i] = float32(i) / 128
+	}
+	data, _ := encodePCM(ramp, Pcm16)
+	if err := writeWAVHeader(&wav, newWAVHeader(8000, 1, 16, uint32(len(data)))); err != nil {
+		t.Fatal(err)
+	}
+	wav.Write(data)
+	path := filepath.Join(dir, "ramp.wav")
+	if err := os.WriteFile(path, wav.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	looped, _ := decodePCM(captureFake(t, newFake(FakeConfig{Signal: "file", File: path, SampleRate: 8000}), 0.025), Pcm16)
+	if len(looped) != 200 {
+		t.Fatalf("captured %d frames of the file, want 200", len(looped))
+	}
+	for i, s := range looped {
+		if math.Abs(float64(s-ramp[i%100])) > 1e-3 {
+			t.Fatalf("frame %d is %v, want %v", i, s, ramp[i%100])
+		}
+	}
+	if _, err := NewFake(Named("fake"), FakeConfig{Signal: "file", File: filepath.Join(dir, "missing.wav")}, logging.NewTestLogger(t)); err == nil {
+		t.Error("built on a missing file")
+	}
+}
+
+func TestFakePaced(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	f, err := NewFake(Named("fake"), FakeConfig{Clock: clock}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := f.GetAudio(ctx, Pcm16.String(), 0.25, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-chunks:
+		t.Fatalf("got a chunk at %v before the clock moved", c.Timestamp)
+	case <-time.After(50 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	var got []time.Time
+	for c := range chunks {
+		got = append(got, c.Timestamp)
+	}
+	if len(got) != 3 || !got[0].Equal(time.Unix(1000, 0)) || !got[2].Equal(time.Unix(1000, 200*int64(time.Millisecond))) {
+		t.Errorf("chunks were captured at %v", got)
+	}
+}
+
+func TestFakePlays(t *testing.T) {
+	f, err := NewFake(Named("fake"), FakeConfig{MaxPlays: 2, Unpaced: true}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := serveAudio(t, f)
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		clip, _ := encodePCM(tone(16000, 160*i, 440, 0.5), Pcm16)
+		if err := c.Play(ctx, clip, Pcm16.String(), 16000, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plays := f.Plays()
+	if len(plays) != 2 || len(plays[0].Data) != 2*320 || len(plays[1].Data) != 2*480 || plays[1].SampleRate != 16000 || plays[1].Codec != "pcm16" {
+		t.Errorf("recorded %d plays: %+v", len(plays), plays)
+	}
+	if last, ok := f.LastPlay(); !ok || len(last.Data) != 2*480 {
+		t.Errorf("last play is %+v", last)
+	}
+
+	// DoCommand isn't forwarded by the client yet
+	resp, err := f.DoCommand(ctx, map[string]interface{}{"plays": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list := resp["plays"].([]interface{}); len(list) != 2 || list[1].(map[string]interface{})["bytes"] != 960 || len(list[1].(map[string]interface{})["sha256"].(string)) != 64 {
+		t.Errorf("listed %v", resp)
+	}
+	if resp, err := f.DoCommand(ctx, map[string]interface{}{"reset_plays": true}); err != nil || resp["reset"] != 2 {
+		t.Errorf("reset %v: %v", resp, err)
+	}
+	if len(f.Plays()) != 0 {
+		t.Error("plays were kept after a reset")
+	}
+	if err := c.Play(ctx, []byte{0, 0}, Pcm16.String(), 0, 1); err == nil {
+		t.Error("played at 0 Hz")
+	}
+}
+
+func TestFakeConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg FakeConfig
+		ok  bool
+	}{
+		{FakeConfig{}, true},
+		{FakeConfig{Signal: "noise", LevelDBFS: -40, Seed: 7}, true},
+		{FakeConfig{Signal: "file"}, false},
+		{FakeConfig{Signal: "square"}, false},
+		{FakeConfig{LevelDBFS: 3}, false},
+		{FakeConfig{MaxPlays: -1}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFFTMatchesDFT(t *testing.T) {
+	for _, n := range []int{1, 2, 4, 8, 64, 512} {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(float64(simNoise(uint64(i))), float64(simNoise(uint64(i+n))))
+		}
+		want := make([]complex128, n)
+		for k := range want {
+			for i, v := range x {
+				want[k] += v * cmplx.Rect(1, -2*math.Pi*float64(k*i)/float64(n))
+			}
+		}
+		got := append([]complex128(nil), x...)
+		fft(got, false)
+		for k := range got {
+			if cmplx.Abs(got[k]-want[k]) > 1e-9*float64(n) {
+				t.Fatalf("n=%d bin %d: got %v, want %v", n, k, got[k], want[k])
+			}
+		}
+		fft(got, true)
+		for i := range got {
+			if cmplx.Abs(got[i]-x[i]) > 1e-12*float64(n) {
+				t.Fatalf("n=%d: inverse gave %v at %d, want %v", n, got[i], i, x[i])
+			}
+		}
+	}
+}
+
+func TestFFTOfTone(t *testing.T) {
+	const n, bin = 256, 10
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(math.Cos(2*math.Pi*bin*float64(i)/n), 0)
+	}
+	fft(x, false)
+	for k, v := range x {
+		want := 0.0
+		if k == bin || k == n-bin {
+			want = n / 2
+		}
+		if math.Abs(cmplx.Abs(v)-want) > 1e-9 {
+			t.Fatalf("bin %d has magnitude %v, want %v", k, cmplx.Abs(v), want)
+		}
+	}
+}
+
+func TestNextPow2(t *testing.T) {
+	for in, want := range map[int]int{-1: 1, 0: 1, 1: 1, 2: 2, 3: 4, 320: 512, 512: 512, 513: 1024} {
+		if got := nextPow2(in); got != want {
+			t.Errorf("nextPow2(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
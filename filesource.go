@@ -0,0 +1,516 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mewkiz/flac"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// FileSourceModel is a capture-only Audio that replays recorded WAV, FLAC or
+// MP3 files as if they were a live microphone, for demos, simulation and
+// reproducing field issues from recordings.
+var FileSourceModel = resource.NewModel("olivia", "audio", "file")
+
+const defaultFileSourceChunk = 100 * time.Millisecond
+
+// FileSourceConfig is the configuration of the file model.
+type FileSourceConfig struct {
+	// Path is a .wav, .flac or .mp3 file, or a directory whose files of
+	// those types are played in name order.
+	Path    string `json:"path"`
+	Loop    bool   `json:"loop,omitempty"`     // start over at the end instead of ending the stream
+	ChunkMs int    `json:"chunk_ms,omitempty"` // audio per chunk, 100ms if zero
+	// Unpaced streams the files as fast as they're read instead of in real
+	// time, to run recordings through a pipeline quicker than they were
+	// made. Timestamps still advance by the audio's duration.
+	Unpaced bool `json:"unpaced,omitempty"`
+
+	// Clock paces the replay, SystemClock if nil.
+	Clock ClockSource `json:"-"`
+}
+
+// Validate checks the file source configuration.
+func (c *FileSourceConfig) Validate(path string) ([]string, []string, error) {
+	if c.Path == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "path")
+	}
+	if c.ChunkMs < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("chunk_ms cannot be negative"))
+	}
+	return nil, nil, nil
+}
+
+func init() {
+	registerModel(FileSourceModel, resource.Registration[Audio, *FileSourceConfig]{
+		AttributeMapConverter: migratingConverter[*FileSourceConfig](FileSourceModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*FileSourceConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewFileSource(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+type fileSource struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	files   []string
+	cfg     FileSourceConfig
+	logger  logging.Logger
+	streams streamGroup
+}
+
+// NewFileSource returns a file source for cfg. Every GetAudio call replays
+// the files from the start on its own.
+func NewFileSource(name resource.Name, cfg FileSourceConfig, logger logging.Logger) (Audio, error) {
+	files, err := audioFiles(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	// fail at construction rather than on the first stream
+	for _, f := range files {
+		dec, err := openAudioFile(f)
+		if err != nil {
+			return nil, err
+		}
+		dec.Close()
+	}
+	if cfg.ChunkMs == 0 {
+		cfg.ChunkMs = int(defaultFileSourceChunk / time.Millisecond)
+	}
+	return &fileSource{Named: name.AsNamed(), files: files, cfg: cfg, logger: logger}, nil
+}
+
+// audioFiles lists the playable files at path.
+func audioFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".wav", ".flac", ".mp3":
+			if !e.IsDir() {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .wav, .flac or .mp3 files in %s", path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// GetAudio replays the files in real time, or as fast as they decode when
+// unpaced, in the requested raw pcm format. A positive durationSeconds ends
+// the stream after that much audio.
+func (s *fileSource) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, err
+	}
+	o := NewGetAudioOptions(opts...)
+	conv := transcoderFor(format, o)
+	limit := secondsToDuration(durationSeconds)
+	ctx, done, err := s.streams.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		send := func(chunk *AudioChunk) bool {
+			select {
+			case out <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var (
+			seq   int64
+			pacer *samplePacer
+			// frames left to send at the current rate, -1 for no limit
+			left int64 = -1
+			// frames sent since the first file, to catch a loop over nothing
+			passFrames int64
+		)
+		for i := 0; ; i++ {
+			if i == len(s.files) {
+				if !s.cfg.Loop {
+					return
+				}
+				if passFrames == 0 {
+					send(&AudioChunk{Err: fmt.Errorf("cannot loop %s, it has no audio", s.cfg.Path)})
+					return
+				}
+				i, passFrames = 0, 0
+			}
+			dec, err := openAudioFile(s.files[i])
+			if err != nil {
+				send(&AudioChunk{Err: err})
+				return
+			}
+			info := dec.Info()
+			// a new pacer keeps timestamps continuous when the next file has a different rate
+			if pacer == nil || pacer.rate != info.SampleRate {
+				next := newSamplePacer(s.cfg.Clock, info.SampleRate)
+				switch {
+				case pacer == nil && limit > 0:
+					left = int64(math.Round(limit.Seconds() * float64(info.SampleRate)))
+				case pacer != nil:
+					next.start = pacer.timestamp(pacer.frames)
+					if left > 0 {
+						left = int64(math.Round(float64(left) * float64(info.SampleRate) / float64(pacer.rate)))
+					}
+				}
+				pacer = next
+			}
+			if left == 0 {
+				dec.Close()
+				return
+			}
+			frames := info.SampleRate * s.cfg.ChunkMs / 1000
+			for {
+				samples, err := dec.Read(frames)
+				if len(samples) > 0 {
+					n := len(samples) / info.Channels
+					if left >= 0 && int64(n) > left {
+						n = int(left)
+						samples = samples[:n*info.Channels]
+					}
+					at := pacer.timestamp(pacer.frames)
+					if s.cfg.Unpaced {
+						pacer.frames += int64(n)
+					} else {
+						var waitErr error
+						if at, waitErr = pacer.wait(ctx, n); waitErr != nil {
+							dec.Close()
+							return
+						}
+					}
+					chunkInfo := info
+					chunk, convErr := conv.convertSamples(samples, &AudioChunk{Sequence: seq, Info: &chunkInfo, Timestamp: at})
+					if convErr != nil {
+						chunk = &AudioChunk{Err: convErr}
+					}
+					seq++
+					passFrames += int64(n)
+					if left > 0 {
+						left -= int64(n)
+					}
+					if !send(chunk) || convErr != nil || left == 0 {
+						dec.Close()
+						return
+					}
+				}
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					dec.Close()
+					send(&AudioChunk{Err: fmt.Errorf("failed to read %s: %w", s.files[i], err)})
+					return
+				}
+			}
+			dec.Close()
+		}
+	}()
+	return out, nil
+}
+
+func (s *fileSource) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return errorf(ErrUnsupported, "file source cannot play audio")
+}
+
+func (s *fileSource) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, s, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close ends every stream and returns once their files are closed.
+func (s *fileSource) Close(ctx context.Context) error {
+	return s.streams.close(ctx)
+}
+
+// audioFileDecoder reads interleaved float32 samples from a recording.
+type audioFileDecoder interface {
+	Info() AudioInfo // Format is always Pcm32Float
+	// Read returns up to frames frames, and io.EOF once the file is done.
+	Read(frames int) ([]float32, error)
+	Close() error
+}
+
+func openAudioFile(path string) (audioFileDecoder, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav":
+		return openWAVFile(path)
+	case ".flac":
+		return openFLACFile(path)
+	case ".mp3":
+		if openMP3File == nil {
+			return nil, fmt.Errorf("%s: this build has no mp3 decoding, build with -tags mp3dec", path)
+		}
+		return openMP3File(path)
+	default:
+		return nil, fmt.Errorf("unsupported audio file %s, expected .wav, .flac or .mp3", path)
+	}
+}
+
+// readAudioFile decodes a whole recording to interleaved float32 samples.
+func readAudioFile(path string) ([]float32, AudioInfo, error) {
+	dec, err := openAudioFile(path)
+	if err != nil {
+		return nil, AudioInfo{}, err
+	}
+	defer dec.Close()
+	info := dec.Info()
+	var all []float32
+	for {
+		samples, err := dec.Read(info.SampleRate)
+		all = append(all, samples...)
+		if errors.Is(err, io.EOF) {
+			return all, info, nil
+		}
+		if err != nil {
+			return nil, AudioInfo{}, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+}
+
+// openMP3File is set by builds with the mp3dec tag.
+var openMP3File func(path string) (audioFileDecoder, error)
+
+// WAV format tags.
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+type wavFileDecoder struct {
+	f     *os.File
+	r     *bufio.Reader
+	info  AudioInfo
+	float bool
+	width int   // bytes per sample
+	left  int64 // bytes of sample data not read yet
+	buf   []byte
+}
+
+func openWAVFile(path string) (*wavFileDecoder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	d, err := newWAVFileDecoder(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
+
+func newWAVFileDecoder(f *os.File) (*wavFileDecoder, error) {
+	r := bufio.NewReader(f)
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, err
+	}
+	if string(riff[:4]) != "RIFF" || string(riff[8:]) != "WAVE" {
+		return nil, errors.New("not a wav file")
+	}
+
+	d := &wavFileDecoder{f: f, r: r}
+	haveFormat := false
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return nil, errors.New("wav file has no data chunk")
+		}
+		id, size := string(hdr[:4]), int64(binary.LittleEndian.Uint32(hdr[4:]))
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, errors.New("wav format chunk is too short")
+			}
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, err
+			}
+			tag := binary.LittleEndian.Uint16(body)
+			if tag == wavFormatExtensible && size >= 26 {
+				// the sub-format GUID starts with the real tag
+				tag = binary.LittleEndian.Uint16(body[24:])
+			}
+			bits := int(binary.LittleEndian.Uint16(body[14:]))
+			d.info = AudioInfo{
+				Format:     Pcm32Float,
+				Channels:   int(binary.LittleEndian.Uint16(body[2:])),
+				SampleRate: int(binary.LittleEndian.Uint32(body[4:])),
+			}
+			d.width = bits / 8
+			switch {
+			case tag == wavFormatPCM && (bits == 8 || bits == 16 || bits == 24 || bits == 32):
+			case tag == wavFormatFloat && bits == 32:
+				d.float = true
+			default:
+				return nil, fmt.Errorf("unsupported wav encoding: format %d, %d bits", tag, bits)
+			}
+			if d.info.Channels == 0 || d.info.SampleRate == 0 {
+				return nil, errors.New("wav file has no channels or sample rate")
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, errors.New("wav data chunk before format chunk")
+			}
+			d.left = size
+			if size == streamingDataSize {
+				// written while streaming, read to the end of the file
+				d.left = math.MaxInt64
+			}
+			return d, nil
+		default:
+			if _, err := r.Discard(int(size)); err != nil {
+				return nil, err
+			}
+		}
+		if size%2 == 1 {
+			// chunks are padded to an even size
+			if _, err := r.Discard(1); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (d *wavFileDecoder) Info() AudioInfo { return d.info }
+
+func (d *wavFileDecoder) Read(frames int) ([]float32, error) {
+	want := int64(frames * d.info.Channels * d.width)
+	if want > d.left {
+		want = d.left
+	}
+	if want == 0 {
+		return nil, io.EOF
+	}
+	if int64(cap(d.buf)) < want {
+		d.buf = make([]byte, want)
+	}
+	buf := d.buf[:want]
+	n, err := io.ReadFull(d.r, buf)
+	// drop a trailing partial frame
+	n -= n % (d.info.Channels * d.width)
+	d.left -= int64(n)
+	buf = buf[:n]
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+
+	samples := make([]float32, n/d.width)
+	for i := range samples {
+		b := buf[i*d.width:]
+		switch {
+		case d.float:
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case d.width == 1:
+			samples[i] = (float32(b[0]) - 128) / 128 // 8-bit wav is unsigned
+		case d.width == 2:
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(b))) / 32768
+		case d.width == 3:
+			v := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+			samples[i] = float32(v) / (1 << 23)
+		case d.width == 4:
+			samples[i] = float32(float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648)
+		}
+	}
+	return samples, err
+}
+
+func (d *wavFileDecoder) Close() error { return d.f.Close() }
+
+type flacFileDecoder struct {
+	stream  *flac.Stream
+	info    AudioInfo
+	scale   float32
+	pending []float32 // decoded samples not returned yet
+}
+
+func openFLACFile(path string) (*flacFileDecoder, error) {
+	stream, err := flac.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	si := stream.Info
+	return &flacFileDecoder{
+		stream: stream,
+		info:   AudioInfo{Format: Pcm32Float, SampleRate: int(si.SampleRate), Channels: int(si.NChannels)},
+		scale:  float32(int64(1) << (si.BitsPerSample - 1)),
+	}, nil
+}
+
+func (d *flacFileDecoder) Info() AudioInfo { return d.info }
+
+func (d *flacFileDecoder) Read(frames int) ([]float32, error) {
+	want := frames * d.info.Channels
+	for len(d.pending) < want {
+		f, err := d.stream.ParseNext()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < int(f.BlockSize); i++ {
+			for _, sub := range f.Subframes {
+				d.pending = append(d.pending, float32(sub.Samples[i])/d.scale)
+			}
+		}
+	}
+	if len(d.pending) == 0 {
+		return nil, io.EOF
+	}
+	n := min(want, len(d.pending))
+	out := append([]float32(nil), d.pending[:n]...)
+	d.pending = d.pending[n:]
+	return out, nil
+}
+
+func (d *flacFileDecoder) Close() error { return d.stream.Close() }
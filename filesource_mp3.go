@@ -0,0 +1,70 @@
+//go:build mp3dec
+
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hajimehoshi/go-mp3"
+)
+
+func init() {
+	openMP3File = func(path string) (audioFileDecoder, error) {
+		return openGoMP3File(path)
+	}
+}
+
+// goMP3FileDecoder decodes MP3 with go-mp3, which always produces stereo
+// pcm16, mono files included.
+type goMP3FileDecoder struct {
+	f    *os.File
+	dec  *mp3.Decoder
+	info AudioInfo
+	buf  []byte
+}
+
+func openGoMP3File(path string) (*goMP3FileDecoder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	dec, err := mp3.NewDecoder(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &goMP3FileDecoder{
+		f:    f,
+		dec:  dec,
+		info: AudioInfo{Format: Pcm32Float, SampleRate: dec.SampleRate(), Channels: 2},
+	}, nil
+}
+
+func (d *goMP3FileDecoder) Info() AudioInfo { return d.info }
+
+func (d *goMP3FileDecoder) Read(frames int) ([]float32, error) {
+	want := 2 * d.info.Channels * frames
+	if cap(d.buf) < want {
+		d.buf = make([]byte, want)
+	}
+	n, err := io.ReadFull(d.dec, d.buf[:want])
+	// a partial frame at the end of a damaged file is dropped
+	n -= n % (2 * d.info.Channels)
+	if n == 0 && err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			err = io.EOF
+		}
+		return nil, err
+	}
+	out := make([]float32, n/2)
+	for i := range out {
+		out[i] = float32(int16(binary.LittleEndian.Uint16(d.buf[2*i:]))) / 32768
+	}
+	return out, nil
+}
+
+func (d *goMP3FileDecoder) Close() error { return d.f.Close() }
@@ -0,0 +1,135 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// writeTestWAV writes frames of mono pcm16 silence at rate to dir/name.
+func writeTestWAV(t *testing.T, dir, name string, rate, frames int) {
+	t.Helper()
+	var b bytes.Buffer
+	if err := writeWAVHeader(&b, newWAVHeader(rate, 1, 16, uint32(frames*2))); err != nil {
+		t.Fatal(err)
+	}
+	b.Write(make([]byte, frames*2))
+	if err := os.WriteFile(filepath.Join(dir, name), b.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileSourceDurationLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		files    []int // frames per 8 kHz file
+		loop     bool
+		duration float32
+		want     int // frames delivered
+	}{
+		{"within one file", []int{8000}, false, 0.025, 200},
+		{"across files", []int{150, 150}, false, 0.025, 200},
+		{"looping", []int{30}, true, 0.025, 200},
+		{"shorter than the files", []int{100}, false, 1, 100},
+		// rounds to no frames at all, which used to spin
+		{"below one frame", []int{100}, true, 0.00001, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i, frames := range tc.files {
+				writeTestWAV(t, dir, string(rune('a'+i))+".wav", 8000, frames)
+			}
+			clock := NewManualClock(time.Unix(0, 0))
+			defer runClock(clock, 10*time.Millisecond)()
+			a, err := NewFileSource(Named("file"), FileSourceConfig{Path: dir, Loop: tc.loop, ChunkMs: 10, Clock: clock}, logging.NewTestLogger(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			ch, err := a.GetAudio(ctx, Pcm16.String(), tc.duration, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			frames := 0
+			for chunk := range ch {
+				if chunk.Err != nil {
+					t.Fatal(chunk.Err)
+				}
+				frames += len(chunk.AudioData) / 2
+			}
+			if ctx.Err() != nil {
+				t.Fatal("stream did not end")
+			}
+			if frames != tc.want {
+				t.Fatalf("got %d frames, want %d", frames, tc.want)
+			}
+		})
+	}
+}
+
+func TestFileSourceLoopingEmptyFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTestWAV(t, dir, "empty.wav", 8000, 0)
+	a, err := NewFileSource(Named("file"), FileSourceConfig{Path: dir, Loop: true, Clock: NewManualClock(time.Unix(0, 0))}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ch, err := a.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := <-ch
+	if chunk == nil || chunk.Err == nil {
+		t.Fatalf("got %+v, want an error instead of looping forever", chunk)
+	}
+}
+
+func TestFileSourceUnpaced(t *testing.T) {
+	dir := t.TempDir()
+	writeTestWAV(t, dir, "a.wav", 8000, 8000)
+	// the clock never moves, an unpaced stream doesn't wait for it
+	a, err := NewFileSource(Named("file"), FileSourceConfig{Path: dir, Unpaced: true, Clock: NewManualClock(time.Unix(0, 0))}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ch, err := a.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last time.Time
+	chunks := 0
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		last = chunk.Timestamp
+		chunks++
+	}
+	if chunks != 10 || !last.Equal(time.Unix(0, 900*int64(time.Millisecond))) {
+		t.Errorf("got %d chunks, the last at %v", chunks, last)
+	}
+}
+
+func TestFileSourceMP3(t *testing.T) {
+	if openMP3File != nil {
+		t.Skip("built with mp3 decoding")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.mp3"), []byte{0xff, 0xfb}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileSource(Named("file"), FileSourceConfig{Path: dir}, logging.NewTestLogger(t)); err == nil || !strings.Contains(err.Error(), "-tags mp3dec") {
+		t.Errorf("built on an mp3 without decoding: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+)
+
+// biquad is a second order IIR filter on interleaved samples, one state per
+// channel, with coefficients from the Audio EQ Cookbook.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	z1, z2             []float64 // transposed direct form II state
+}
+
+// newBiquad returns a "lowshelf", "highshelf", "peaking", "lowpass" or
+// "highpass" filter at freq. gainDB only applies to shelves and peaks; a
+// zero q means 0.707.
+func newBiquad(kind string, rate int, freq, gainDB, q float64, channels int) (*biquad, error) {
+	if freq <= 0 || freq >= float64(rate)/2 {
+		return nil, fmt.Errorf("filter frequency %g Hz is out of range for %d Hz audio", freq, rate)
+	}
+	if q <= 0 {
+		q = math.Sqrt2 / 2
+	}
+	a := math.Pow(10, gainDB/40)
+	w := 2 * math.Pi * freq / float64(rate)
+	cos, alpha := math.Cos(w), math.Sin(w)/(2*q)
+
+	var b0, b1, b2, a0, a1, a2 float64
+	switch kind {
+	case "peaking":
+		b0, b1, b2 = 1+alpha*a, -2*cos, 1-alpha*a
+		a0, a1, a2 = 1+alpha/a, -2*cos, 1-alpha/a
+	case "lowshelf":
+		sq := 2 * math.Sqrt(a) * alpha
+		b0, b1, b2 = a*((a+1)-(a-1)*cos+sq), 2*a*((a-1)-(a+1)*cos), a*((a+1)-(a-1)*cos-sq)
+		a0, a1, a2 = (a+1)+(a-1)*cos+sq, -2*((a-1)+(a+1)*cos), (a+1)+(a-1)*cos-sq
+	case "highshelf":
+		sq := 2 * math.Sqrt(a) * alpha
+		b0, b1, b2 = a*((a+1)+(a-1)*cos+sq), -2*a*((a-1)+(a+1)*cos), a*((a+1)+(a-1)*cos-sq)
+		a0, a1, a2 = (a+1)-(a-1)*cos+sq, 2*((a-1)-(a+1)*cos), (a+1)-(a-1)*cos-sq
+	case "lowpass":
+		b0, b1, b2 = (1-cos)/2, 1-cos, (1-cos)/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case "highpass":
+		b0, b1, b2 = (1+cos)/2, -(1 + cos), (1+cos)/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	default:
+		return nil, fmt.Errorf("unknown filter type %q, expected lowshelf, highshelf, peaking, lowpass or highpass", kind)
+	}
+	return &biquad{
+		b0: b0 / a0, b1: b1 / a0, b2: b2 / a0, a1: a1 / a0, a2: a2 / a0,
+		z1: make([]float64, channels), z2: make([]float64, channels),
+	}, nil
+}
+
+// process filters samples in place.
+func (f *biquad) process(samples []float32) {
+	channels := len(f.z1)
+	for i, s := range samples {
+		c := i % channels
+		x := float64(s)
+		y := f.b0*x + f.z1[c]
+		f.z1[c] = f.b1*x - f.a1*y + f.z2[c]
+		f.z2[c] = f.b2*x - f.a2*y
+		samples[i] = float32(y)
+	}
+}
+
+// compressor is a feed forward peak compressor with a hard knee. Channels
+// share one gain so the stereo image doesn't shift.
+type compressor struct {
+	threshold float64 // dBFS
+	ratio     float64
+	attack    float64 // envelope smoothing per frame
+	release   float64
+	makeup    float64 // linear
+	channels  int
+	env       float64 // linear peak envelope
+}
+
+func newCompressor(cfg CompressorConfig, rate, channels int) *compressor {
+	coeff := func(ms float64) float64 {
+		if ms <= 0 {
+			return 0
+		}
+		return math.Exp(-1 / (ms / 1000 * float64(rate)))
+	}
+	ratio := cfg.Ratio
+	if ratio < 1 {
+		ratio = 1
+	}
+	return &compressor{
+		threshold: cfg.ThresholdDBFS,
+		ratio:     ratio,
+		attack:    coeff(cfg.AttackMs),
+		release:   coeff(cfg.ReleaseMs),
+		makeup:    math.Pow(10, cfg.MakeupDB/20),
+		channels:  channels,
+	}
+}
+
+// process compresses interleaved samples in place.
+func (c *compressor) process(samples []float32) {
+	for f := 0; f+c.channels <= len(samples); f += c.channels {
+		level := 0.0
+		for _, s := range samples[f : f+c.channels] {
+			level = math.Max(level, math.Abs(float64(s)))
+		}
+		coeff := c.release
+		if level > c.env {
+			coeff = c.attack
+		}
+		c.env = coeff*c.env + (1-coeff)*level
+
+		gain := c.makeup
+		if over := dbfs(c.env) - c.threshold; over > 0 {
+			gain *= math.Pow(10, -over*(1-1/c.ratio)/20)
+		}
+		for i := f; i < f+c.channels; i++ {
+			samples[i] = float32(float64(samples[i]) * gain)
+		}
+	}
+}
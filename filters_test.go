@@ -0,0 +1,146 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+// biquadResponse returns the gain in dB of f at freq, measured on a sine
+// after the filter has settled.
+func biquadResponse(f *biquad, rate int, freq float64) float64 {
+	in := make([]float32, rate/2)
+	for i := range in {
+		in[i] = 0.5 * float32(math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+	out := append([]float32(nil), in...)
+	f.process(out)
+	settled := rate / 4
+	return dbfs(bandLevel(out[settled:], rate, freq)) - dbfs(bandLevel(in[settled:], rate, freq))
+}
+
+func TestBiquadResponse(t *testing.T) {
+	const rate = 48000
+	for _, tc := range []struct {
+		kind         string
+		freq, gainDB float64
+		at           float64 // Hz
+		want         float64 // dB
+	}{
+		{"lowpass", 1000, 0, 100, 0},
+		{"lowpass", 1000, 0, 1000, -3},
+		{"lowpass", 1000, 0, 10000, -40},
+		{"highpass", 1000, 0, 10000, 0},
+		{"highpass", 1000, 0, 1000, -3},
+		{"highpass", 1000, 0, 100, -40},
+		{"peaking", 2000, 6, 2000, 6},
+		{"peaking", 2000, -9, 2000, -9},
+		{"peaking", 2000, 6, 50, 0},
+		{"lowshelf", 200, 6, 30, 6},
+		{"lowshelf", 200, 6, 8000, 0},
+		{"lowshelf", 200, 6, 200, 3},
+		{"highshelf", 4000, -6, 16000, -6},
+		{"highshelf", 4000, -6, 200, 0},
+		{"highshelf", 4000, -6, 4000, -3},
+	} {
+		f, err := newBiquad(tc.kind, rate, tc.freq, tc.gainDB, 0, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a decade away from a second order corner is 40 dB, and the bilinear
+		// transform steepens the lowpass a little more toward Nyquist
+		tolerance := 0.25
+		if math.Abs(tc.want) >= 40 {
+			tolerance = 3
+		}
+		if got := biquadResponse(f, rate, tc.at); math.Abs(got-tc.want) > tolerance {
+			t.Errorf("%s at %g Hz (%+g dB): %.2f dB at %g Hz, want %g", tc.kind, tc.freq, tc.gainDB, got, tc.at, tc.want)
+		}
+	}
+}
+
+func TestBiquadChannelsAreIndependent(t *testing.T) {
+	f, err := newBiquad("lowpass", 16000, 500, 0, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mono, err := newBiquad("lowpass", 16000, 500, 0, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := make([]float32, 400)
+	for i := range left {
+		left[i] = simNoise(uint64(i))
+	}
+	stereo := remix(left, 1, 2)
+	for i := 1; i < len(stereo); i += 2 {
+		stereo[i] = 0 // silent right channel
+	}
+	f.process(stereo)
+	mono.process(left)
+	for i, s := range left {
+		if stereo[2*i] != s || stereo[2*i+1] != 0 {
+			t.Fatalf("frame %d: got %v %v, want %v 0", i, stereo[2*i], stereo[2*i+1], s)
+		}
+	}
+}
+
+func TestNewBiquadRejects(t *testing.T) {
+	for _, tc := range []struct {
+		kind string
+		freq float64
+	}{
+		{"lowpass", 0},
+		{"lowpass", 8000},
+		{"notch", 1000},
+	} {
+		if _, err := newBiquad(tc.kind, 16000, tc.freq, 0, 0, 1); err == nil {
+			t.Errorf("%s at %g Hz accepted for 16 kHz audio", tc.kind, tc.freq)
+		}
+	}
+}
+
+func TestCompressor(t *testing.T) {
+	const rate = 16000
+	sine := func(peak float64) []float32 {
+		out := make([]float32, rate)
+		for i := range out {
+			out[i] = float32(peak * math.Sin(2*math.Pi*440*float64(i)/rate))
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		name   string
+		cfg    CompressorConfig
+		inDBFS float64 // sine peak
+		want   float64 // settled output peak
+	}{
+		{"below threshold", CompressorConfig{ThresholdDBFS: -20, Ratio: 4, AttackMs: 1, ReleaseMs: 100}, -30, -30},
+		{"4:1 above threshold", CompressorConfig{ThresholdDBFS: -20, Ratio: 4, AttackMs: 1, ReleaseMs: 100}, -6, -16.5},
+		{"limiter", CompressorConfig{ThresholdDBFS: -12, Ratio: 1000, AttackMs: 1, ReleaseMs: 100}, 0, -12},
+		{"makeup", CompressorConfig{ThresholdDBFS: -20, Ratio: 2, AttackMs: 1, ReleaseMs: 100, MakeupDB: 6}, -10, -9},
+		{"ratio below one", CompressorConfig{ThresholdDBFS: -20, Ratio: 0.5, AttackMs: 1, ReleaseMs: 100}, -6, -6},
+	} {
+		c := newCompressor(tc.cfg, rate, 1)
+		samples := sine(math.Pow(10, tc.inDBFS/20))
+		c.process(samples)
+		// the envelope ripples a little with the sine, so allow for it
+		if got := dbfs(peak(samples[rate/2:])); math.Abs(got-tc.want) > 0.5 {
+			t.Errorf("%s: settled at %.2f dBFS, want %g", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCompressorLinksChannels(t *testing.T) {
+	c := newCompressor(CompressorConfig{ThresholdDBFS: -20, Ratio: 10, AttackMs: 1, ReleaseMs: 50}, 16000, 2)
+	samples := make([]float32, 2*16000)
+	for i := 0; i < len(samples); i += 2 {
+		s := float32(math.Sin(2 * math.Pi * 440 * float64(i/2) / 16000))
+		samples[i], samples[i+1] = 0.9*s, 0.05*s // loud left, quiet right
+	}
+	c.process(samples)
+	for i := 16000; i < len(samples); i += 2 {
+		if samples[i] != 0 && math.Abs(float64(samples[i]/samples[i+1])-18) > 1e-3 {
+			t.Fatalf("frame %d: channels at %v and %v, want the 18:1 balance kept", i/2, samples[i], samples[i+1])
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package audio
+
+// G.711 companding of 16-bit linear samples, as used by RTP payload types 0
+// (PCMU) and 8 (PCMA).
+
+const (
+	mulawBias = 0x84
+	mulawClip = 32635
+)
+
+// linearToMulaw encodes one sample with the µ-law curve.
+func linearToMulaw(sample int16) byte {
+	s := int(sample)
+	sign := 0
+	if s < 0 {
+		s = -s
+		sign = 0x80
+	}
+	if s > mulawClip {
+		s = mulawClip
+	}
+	s += mulawBias
+	exponent := 7
+	for mask := 0x4000; s&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := (s >> (exponent + 3)) & 0x0F
+	return ^byte(sign | exponent<<4 | mantissa)
+}
+
+// linearToAlaw encodes one sample with the A-law curve.
+func linearToAlaw(sample int16) byte {
+	s := int(sample)
+	sign := 0x80
+	if s < 0 {
+		s = -s - 1
+		sign = 0
+	}
+	if s > 0x7FFF {
+		s = 0x7FFF
+	}
+	var b int
+	if s < 256 {
+		b = s >> 4
+	} else {
+		exponent := 7
+		for mask := 0x4000; s&mask == 0 && exponent > 1; mask >>= 1 {
+			exponent--
+		}
+		b = exponent<<4 | (s>>(exponent+3))&0x0F
+	}
+	return byte(sign|b) ^ 0x55
+}
+
+// mulawToLinear decodes one µ-law byte.
+func mulawToLinear(b byte) int16 {
+	b = ^b
+	exponent := int(b>>4) & 0x07
+	s := (int(b&0x0F)<<3 + mulawBias) << exponent
+	s -= mulawBias
+	if b&0x80 != 0 {
+		return int16(-s)
+	}
+	return int16(s)
+}
+
+// alawToLinear decodes one A-law byte.
+func alawToLinear(b byte) int16 {
+	b ^= 0x55
+	exponent := int(b>>4) & 0x07
+	s := int(b&0x0F)<<4 + 8
+	if exponent > 0 {
+		s = (s + 0x100) << (exponent - 1)
+	}
+	if b&0x80 == 0 {
+		return int16(-s)
+	}
+	return int16(s)
+}
@@ -0,0 +1,85 @@
+package audio
+
+import "testing"
+
+// Encoder vectors from the Sun reference implementation, which CPython's
+// audioop also uses.
+var g711EncodeVectors = []struct {
+	sample      int16
+	mulaw, alaw byte
+}{
+	{0, 0xff, 0xd5},
+	{1, 0xff, 0xd5},
+	{8, 0xfe, 0xd5},
+	{-8, 0x7e, 0x55},
+	{100, 0xf2, 0xd3},
+	{-100, 0x72, 0x53},
+	{255, 0xe7, 0xda},
+	{256, 0xe7, 0xc5},
+	{1000, 0xce, 0xfa},
+	{-1000, 0x4e, 0x7a},
+	{4095, 0xaf, 0x9a},
+	{4096, 0xaf, 0x85},
+	{8159, 0x9f, 0x8a},
+	{-8159, 0x1f, 0x0a},
+	{16000, 0x90, 0xba},
+	{-16000, 0x10, 0x3a},
+	{32124, 0x80, 0xaa},
+	{32767, 0x80, 0xaa},
+	{-32768, 0x00, 0x2a},
+}
+
+var g711DecodeVectors = []struct {
+	code        byte
+	mulaw, alaw int16
+}{
+	{0x00, -32124, -5504},
+	{0x0f, -16764, -6784},
+	{0x7f, 0, -848},
+	{0x80, 32124, 5504},
+	{0xff, 0, 848},
+	{0x55, -716, -8},
+	{0xd5, 716, 8},
+	{0xaa, 5372, 32256},
+	{0x2a, -5372, -32256},
+	{0x3c, -2364, -13056},
+}
+
+func TestG711Encode(t *testing.T) {
+	for _, v := range g711EncodeVectors {
+		if got := linearToMulaw(v.sample); got != v.mulaw {
+			t.Errorf("linearToMulaw(%d) = %#02x, want %#02x", v.sample, got, v.mulaw)
+		}
+		if got := linearToAlaw(v.sample); got != v.alaw {
+			t.Errorf("linearToAlaw(%d) = %#02x, want %#02x", v.sample, got, v.alaw)
+		}
+	}
+}
+
+func TestG711Decode(t *testing.T) {
+	for _, v := range g711DecodeVectors {
+		if got := mulawToLinear(v.code); got != v.mulaw {
+			t.Errorf("mulawToLinear(%#02x) = %d, want %d", v.code, got, v.mulaw)
+		}
+		if got := alawToLinear(v.code); got != v.alaw {
+			t.Errorf("alawToLinear(%#02x) = %d, want %d", v.code, got, v.alaw)
+		}
+	}
+}
+
+func TestG711RoundTrip(t *testing.T) {
+	// every code decodes to a level that encodes back to it, except µ-law's
+	// negative zero
+	for i := 0; i < 256; i++ {
+		code := byte(i)
+		if got := linearToAlaw(alawToLinear(code)); got != code {
+			t.Errorf("A-law %#02x round-trips to %#02x", code, got)
+		}
+		if code == 0x7f {
+			continue
+		}
+		if got := linearToMulaw(mulawToLinear(code)); got != code {
+			t.Errorf("µ-law %#02x round-trips to %#02x", code, got)
+		}
+	}
+}
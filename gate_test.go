@@ -0,0 +1,78 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+// levelChunk is 10ms of 16 kHz pcm16 that the "sound" detector matches when
+// loud is set.
+func levelChunk(seq int64, loud bool) *AudioChunk {
+	samples := make([]float32, 160)
+	if loud {
+		for i := range samples {
+			samples[i] = 0.3 * float32(1-2*(i%2))
+		}
+	}
+	data, _ := encodePCM(samples, Pcm16)
+	return &AudioChunk{Sequence: seq, AudioData: data, Info: &AudioInfo{Format: Pcm16, SampleRate: 16000, Channels: 1}}
+}
+
+func TestEventGate(t *testing.T) {
+	g, err := newEventGate([]string{"sound"}, 30*time.Millisecond, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// quiet 0-9, loud 10-11, quiet 12-19, loud 20
+	var got []*AudioChunk
+	for i := int64(0); i < 21; i++ {
+		out, err := g.process(levelChunk(i, i == 10 || i == 11 || i == 20))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, out...)
+	}
+
+	var seqs []int64
+	for _, c := range got {
+		seqs = append(seqs, c.Sequence)
+	}
+	// three chunks of pre-roll, the match and two of post-roll, then the pre-roll of the next match
+	want := []int64{7, 8, 9, 10, 11, 12, 13, 17, 18, 19, 20}
+	if len(seqs) != len(want) {
+		t.Fatalf("delivered %v, want %v", seqs, want)
+	}
+	for i := range want {
+		if seqs[i] != want[i] {
+			t.Fatalf("delivered %v, want %v", seqs, want)
+		}
+	}
+	for _, c := range got {
+		var wantGap time.Duration
+		switch c.Sequence {
+		case 7:
+			wantGap = 70 * time.Millisecond
+		case 17:
+			wantGap = 30 * time.Millisecond
+		}
+		if c.Gap != wantGap {
+			t.Errorf("chunk %d has a %v gap, want %v", c.Sequence, c.Gap, wantGap)
+		}
+	}
+}
+
+func TestEventGateDefaultsAndErrors(t *testing.T) {
+	g, err := newEventGate([]string{"speech", "alarm"}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.preRoll != defaultPreRoll || g.postRoll != defaultPostRoll || len(g.detectors) != 2 {
+		t.Fatalf("unexpected gate %+v", g)
+	}
+	if _, err := newEventGate([]string{"doorbell"}, 0, 0); err == nil {
+		t.Fatal("unknown condition accepted")
+	}
+	if _, err := g.process(&AudioChunk{AudioData: make([]byte, 320)}); err == nil {
+		t.Fatal("chunk without a format accepted")
+	}
+}
@@ -0,0 +1,478 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// GeneratorModel is a signal generator and analyzer for loopback tests and
+// speaker diagnostics: GetAudio delivers a sine, square, sweep or noise,
+// and every clip given to Play is measured for its level, frequency and
+// distortion.
+var GeneratorModel = resource.NewModel("olivia", "audio", "generator")
+
+// Defaults for GeneratorConfig.
+const (
+	defaultGeneratorRate      = 48000
+	defaultGeneratorHz        = 1000
+	defaultGeneratorLevel     = -20.0
+	defaultGeneratorChunk     = 20 * time.Millisecond
+	defaultSweepStartHz       = 20
+	defaultSweepEndHz         = 20000
+	defaultSweepSeconds       = 5
+	generatorHarmonics        = 5 // the highest harmonic counted in THD
+	maxMeasuredFrames         = 65536
+	minMeasuredFrames         = 64
+	generatorClippedThreshold = 0.999
+)
+
+// GeneratorConfig is the configuration of the generator model.
+type GeneratorConfig struct {
+	// Signal is "sine" (the default), "square", "sweep", "white" or
+	// "pink". Pink noise has equal power in every octave.
+	Signal      string  `json:"signal,omitempty"`
+	FrequencyHz float64 `json:"frequency_hz,omitempty"` // of sines and squares, 1 kHz if zero
+	// LevelDBFS is the peak of sines, squares and sweeps and the RMS of
+	// noise, -20 if zero.
+	LevelDBFS float64 `json:"level_dbfs,omitempty"`
+	// A sweep rises logarithmically from SweepStartHz to SweepEndHz over
+	// SweepSeconds, then starts over: 20 Hz to 20 kHz, or just below
+	// Nyquist, in 5s if zero.
+	SweepStartHz float64 `json:"sweep_start_hz,omitempty"`
+	SweepEndHz   float64 `json:"sweep_end_hz,omitempty"`
+	SweepSeconds float64 `json:"sweep_seconds,omitempty"`
+	Seed         uint64  `json:"seed,omitempty"`        // picks the noise
+	SampleRate   int     `json:"sample_rate,omitempty"` // 48 kHz if zero
+	Channels     int     `json:"channels,omitempty"`    // 1 if zero, all carrying the same signal
+	ChunkMs      int     `json:"chunk_ms,omitempty"`    // 20ms if zero
+	// Unpaced delivers capture as fast as it's read and returns from Play
+	// at once.
+	Unpaced bool `json:"unpaced,omitempty"`
+
+	// Clock paces capture and playback, SystemClock if nil.
+	Clock ClockSource `json:"-"`
+}
+
+// Validate checks the generator configuration.
+func (c *GeneratorConfig) Validate(path string) ([]string, []string, error) {
+	var err error
+	switch {
+	case c.Signal != "" && c.Signal != "sine" && c.Signal != "square" && c.Signal != "sweep" && c.Signal != "white" && c.Signal != "pink":
+		err = fmt.Errorf("unknown signal %q, expected sine, square, sweep, white or pink", c.Signal)
+	case c.FrequencyHz < 0 || c.SweepStartHz < 0 || c.SweepEndHz < 0 || c.SweepSeconds < 0 ||
+		c.SampleRate < 0 || c.Channels < 0 || c.ChunkMs < 0:
+		err = errors.New("frequencies, sweep_seconds, sample_rate, channels and chunk_ms cannot be negative")
+	case c.LevelDBFS > 0:
+		err = fmt.Errorf("level_dbfs must be at most full scale, got %v", c.LevelDBFS)
+	}
+	if err == nil {
+		_, err = c.withDefaults()
+	}
+	if err != nil {
+		return nil, nil, resource.NewConfigValidationError(path, err)
+	}
+	return nil, nil, nil
+}
+
+// withDefaults fills in the zero values and checks the frequencies against
+// the rate.
+func (c GeneratorConfig) withDefaults() (GeneratorConfig, error) {
+	if c.Signal == "" {
+		c.Signal = "sine"
+	}
+	if c.SampleRate == 0 {
+		c.SampleRate = defaultGeneratorRate
+	}
+	if c.Channels == 0 {
+		c.Channels = 1
+	}
+	if c.ChunkMs == 0 {
+		c.ChunkMs = int(defaultGeneratorChunk / time.Millisecond)
+	}
+	if c.FrequencyHz == 0 {
+		c.FrequencyHz = defaultGeneratorHz
+	}
+	if c.LevelDBFS == 0 {
+		c.LevelDBFS = defaultGeneratorLevel
+	}
+	nyquist := float64(c.SampleRate) / 2
+	if c.SweepStartHz == 0 {
+		c.SweepStartHz = defaultSweepStartHz
+	}
+	if c.SweepEndHz == 0 {
+		c.SweepEndHz = math.Min(defaultSweepEndHz, 0.95*nyquist)
+	}
+	if c.SweepSeconds == 0 {
+		c.SweepSeconds = defaultSweepSeconds
+	}
+	if c.Clock == nil {
+		c.Clock = SystemClock
+	}
+	switch {
+	case (c.Signal == "sine" || c.Signal == "square") && c.FrequencyHz >= nyquist:
+		return c, fmt.Errorf("frequency_hz %v is above Nyquist at %d Hz", c.FrequencyHz, c.SampleRate)
+	case c.Signal == "sweep" && (c.SweepEndHz >= nyquist || c.SweepStartHz >= c.SweepEndHz):
+		return c, fmt.Errorf("a sweep has to rise and stay below Nyquist, got %v to %v Hz at %d Hz", c.SweepStartHz, c.SweepEndHz, c.SampleRate)
+	}
+	return c, nil
+}
+
+func init() {
+	registerModel(GeneratorModel, resource.Registration[Audio, *GeneratorConfig]{
+		AttributeMapConverter: migratingConverter[*GeneratorConfig](GeneratorModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*GeneratorConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			g, err := NewGenerator(conf.ResourceName(), *cfg, logger)
+			if err != nil {
+				return nil, err
+			}
+			return g, nil
+		},
+	})
+}
+
+// Measurement is what the generator found in a played clip, mixed to mono.
+type Measurement struct {
+	Duration time.Duration
+	RMSDBFS  float64
+	PeakDBFS float64
+	Clipped  int // samples at full scale
+	// FrequencyHz is the strongest component, 0 for a clip too short to
+	// tell. THDPercent is the distortion of the harmonics up to the fifth
+	// relative to it.
+	FrequencyHz float64
+	THDPercent  float64
+	At          time.Time // when Play was called, on the generator's clock
+}
+
+// Generator is a resource of the generator model. Tests building one
+// directly can read measurements with LastMeasurement.
+type Generator struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	cfg    GeneratorConfig
+	info   AudioInfo
+	chunk  int     // frames per chunk
+	level  float64 // linear
+	pink   float64 // scales the pink filter to an RMS of one
+	logger logging.Logger
+
+	mu   sync.Mutex
+	last *Measurement
+
+	streams streamGroup
+}
+
+// NewGenerator returns a generator for cfg.
+func NewGenerator(name resource.Name, cfg GeneratorConfig, logger logging.Logger) (*Generator, error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	g := &Generator{
+		Named:  name.AsNamed(),
+		cfg:    cfg,
+		info:   AudioInfo{Format: Pcm32Float, SampleRate: cfg.SampleRate, Channels: cfg.Channels},
+		chunk:  max(1, cfg.SampleRate*cfg.ChunkMs/1000),
+		level:  math.Pow(10, cfg.LevelDBFS/20),
+		pink:   1,
+		logger: logger,
+	}
+	if cfg.Signal == "pink" {
+		// the filter's gain depends on nothing but its coefficients, so a
+		// few seconds of it set the level well enough
+		s, calibration := g.newSignal(), make([]float32, 10*defaultGeneratorRate)
+		for i := range calibration {
+			calibration[i] = float32(s.pinkNext(float64(simNoise(uint64(i)))))
+		}
+		g.pink = 1 / rms(calibration)
+	}
+	return g, nil
+}
+
+// signal renders one stream of the generator from its start.
+type signal struct {
+	g     *Generator
+	frame int64
+	// Paul Kellet's pink noise filter
+	b [7]float64
+}
+
+func (g *Generator) newSignal() *signal { return &signal{g: g} }
+
+// render fills out with the next mono samples.
+func (s *signal) render(out []float32) {
+	cfg := &s.g.cfg
+	rate := float64(cfg.SampleRate)
+	for i := range out {
+		n := s.frame + int64(i)
+		var v float64
+		switch cfg.Signal {
+		case "sine":
+			v = math.Sin(2 * math.Pi * cfg.FrequencyHz * float64(n) / rate)
+		case "square":
+			v = bandLimitedSquare(cfg.FrequencyHz/rate, n)
+		case "sweep":
+			v = logSweep(cfg.SweepStartHz, cfg.SweepEndHz, cfg.SweepSeconds, float64(n)/rate)
+		case "white":
+			v = float64(simNoise(cfg.Seed*0x9E3779B97F4A7C15 + uint64(n)))
+		case "pink":
+			v = s.pinkNext(float64(simNoise(cfg.Seed*0x9E3779B97F4A7C15+uint64(n)))) * s.g.pink
+		}
+		out[i] = float32(s.g.level * v)
+	}
+	s.frame += int64(len(out))
+}
+
+// pinkNext filters white noise to -3 dB per octave.
+func (s *signal) pinkNext(white float64) float64 {
+	b := &s.b
+	b[0] = 0.99886*b[0] + white*0.0555179
+	b[1] = 0.99332*b[1] + white*0.0750759
+	b[2] = 0.96900*b[2] + white*0.1538520
+	b[3] = 0.86650*b[3] + white*0.3104856
+	b[4] = 0.55000*b[4] + white*0.5329522
+	b[5] = -0.7616*b[5] - white*0.0168980
+	pink := b[0] + b[1] + b[2] + b[3] + b[4] + b[5] + b[6] + white*0.5362
+	b[6] = white * 0.115926
+	return pink
+}
+
+// bandLimitedSquare returns frame n of a square wave of freq cycles per
+// sample, with PolyBLEP corrections at the edges so it doesn't alias.
+func bandLimitedSquare(freq float64, n int64) float64 {
+	phase := math.Mod(freq*float64(n), 1)
+	v := 1.0
+	if phase >= 0.5 {
+		v = -1
+	}
+	return v + polyBLEP(phase, freq) - polyBLEP(math.Mod(phase+0.5, 1), freq)
+}
+
+// polyBLEP is the correction for a step at phase 0 of a wave advancing dt
+// per sample.
+func polyBLEP(phase, dt float64) float64 {
+	switch {
+	case phase < dt:
+		t := phase / dt
+		return 2*t - t*t - 1
+	case phase > 1-dt:
+		t := (phase - 1) / dt
+		return t*t + 2*t + 1
+	}
+	return 0
+}
+
+// logSweep returns the sweep at t seconds, restarting every period.
+func logSweep(from, to, period, t float64) float64 {
+	t = math.Mod(t, period)
+	k := math.Log(to / from)
+	return math.Sin(2 * math.Pi * from * period / k * (math.Exp(t*k/period) - 1))
+}
+
+// GetAudio streams the signal from its start in the requested raw pcm
+// format.
+func (g *Generator) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, err
+	}
+	conv := transcoderFor(format, NewGetAudioOptions(opts...))
+	remaining := int64(-1)
+	if durationSeconds > 0 {
+		remaining = int64(float64(durationSeconds) * float64(g.info.SampleRate))
+	}
+	ctx, done, err := g.streams.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		pacer := newSamplePacer(g.cfg.Clock, g.info.SampleRate)
+		sig := g.newSignal()
+		for seq := int64(0); remaining != 0; seq++ {
+			n := g.chunk
+			if remaining > 0 && int64(n) > remaining {
+				n = int(remaining)
+			}
+			at := pacer.timestamp(sig.frame)
+			if !g.cfg.Unpaced {
+				var err error
+				if at, err = pacer.wait(ctx, n); err != nil {
+					return
+				}
+			}
+			samples := make([]float32, n)
+			sig.render(samples)
+			info := g.info
+			chunk, err := conv.convertSamples(remix(samples, 1, g.info.Channels), &AudioChunk{Sequence: seq, Info: &info, Timestamp: at})
+			if err != nil {
+				chunk, remaining = &AudioChunk{Err: err}, 0
+			}
+			if remaining > 0 {
+				remaining -= int64(n)
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Play measures a raw pcm clip and takes as long as it would to play out,
+// unless the generator is unpaced.
+func (g *Generator) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return fmt.Errorf("the generator can only measure raw pcm: %w", err)
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	samples, err := decodePCM(data, format)
+	if err != nil {
+		return err
+	}
+	m := measure(mono(samples, channels), sampleRate)
+	m.At = g.cfg.Clock.Now()
+	g.mu.Lock()
+	g.last = &m
+	g.mu.Unlock()
+	if g.cfg.Unpaced {
+		return nil
+	}
+	return g.cfg.Clock.Sleep(ctx, m.Duration)
+}
+
+// measure analyzes mono samples at rate.
+func measure(samples []float32, rate int) Measurement {
+	m := Measurement{
+		Duration: time.Duration(len(samples)) * time.Second / time.Duration(rate),
+		RMSDBFS:  dbfs(rms(samples)),
+		PeakDBFS: dbfs(peak(samples)),
+	}
+	for _, s := range samples {
+		if math.Abs(float64(s)) >= generatorClippedThreshold {
+			m.Clipped++
+		}
+	}
+	if len(samples) < minMeasuredFrames {
+		return m
+	}
+	// the largest power of two that fits, from the middle of the clip
+	n := min(nextPow2(len(samples)+1)/2, maxMeasuredFrames)
+	from := (len(samples) - n) / 2
+	x := make([]complex128, n)
+	for i := range x {
+		hann := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n))
+		x[i] = complex(float64(samples[from+i])*hann, 0)
+	}
+	fft(x, false)
+	power := make([]float64, n/2)
+	for i := range power {
+		power[i] = real(x[i] * cmplx.Conj(x[i]))
+	}
+	// the strongest bin above DC, refined between its neighbours
+	best := 1
+	for i := 2; i < len(power)-1; i++ {
+		if power[i] > power[best] {
+			best = i
+		}
+	}
+	if power[best] == 0 {
+		return m
+	}
+	bin := float64(best)
+	if best+1 < len(power) {
+		a, b, c := math.Log(power[best-1]+1e-30), math.Log(power[best]+1e-30), math.Log(power[best+1]+1e-30)
+		if d := a - 2*b + c; d < 0 {
+			bin += 0.5 * (a - c) / d
+		}
+	}
+	m.FrequencyHz = bin * float64(rate) / float64(n)
+	// the Hann window spreads a tone over a few bins, which are summed
+	band := func(center float64) float64 {
+		var sum float64
+		for i := int(math.Round(center)) - 2; i <= int(math.Round(center))+2; i++ {
+			if i > 0 && i < len(power) {
+				sum += power[i]
+			}
+		}
+		return sum
+	}
+	fundamental := band(bin)
+	var harmonics float64
+	for h := 2; h <= generatorHarmonics && float64(h)*bin < float64(len(power)-2); h++ {
+		harmonics += band(float64(h) * bin)
+	}
+	m.THDPercent = 100 * math.Sqrt(harmonics/fundamental)
+	return m
+}
+
+// LastMeasurement returns the measurement of the clip played last, if any.
+func (g *Generator) LastMeasurement() (Measurement, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.last == nil {
+		return Measurement{}, false
+	}
+	return *g.last, true
+}
+
+func (g *Generator) deviceClock() ClockSource { return g.cfg.Clock }
+
+// DoCommand supports {"measurement": true}, which returns the measurement
+// of the clip played last, and the commands every built-in model accepts.
+func (g *Generator) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, g, cmd); ok {
+		return resp, err
+	}
+	if cmd["measurement"] != nil {
+		m, ok := g.LastMeasurement()
+		if !ok {
+			return nil, errors.New("nothing has been played yet")
+		}
+		return map[string]interface{}{
+			"duration_seconds": m.Duration.Seconds(),
+			"rms_dbfs":         m.RMSDBFS,
+			"peak_dbfs":        m.PeakDBFS,
+			"clipped":          m.Clipped,
+			"frequency_hz":     m.FrequencyHz,
+			"thd_percent":      m.THDPercent,
+			"at":               m.At.Format(time.RFC3339Nano),
+		}, nil
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close ends every stream and waits for them to return.
+func (g *Generator) Close(ctx context.Context) error {
+	return g.streams.close(ctx)
+}
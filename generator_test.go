@@ -0,0 +1,135 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go.viam.com/rdk/logging"
+)
+
+// captureGenerator reads seconds of a generator's signal as mono samples.
+func captureGenerator(t *testing.T, cfg GeneratorConfig, seconds float32) ([]float32, *Generator) {
+	t.Helper()
+	cfg.Unpaced = true
+	g, err := NewGenerator(Named("gen"), cfg, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Close(context.Background()) })
+	chunks, err := g.GetAudio(context.Background(), Pcm32Float.String(), seconds, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []float32
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		samples, err := decodePCM(chunk.AudioData, Pcm32Float)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, mono(samples, chunk.Info.Channels)...)
+	}
+	return out, g
+}
+
+func TestGeneratorSine(t *testing.T) {
+	samples, g := captureGenerator(t, GeneratorConfig{FrequencyHz: 997, LevelDBFS: -6, Channels: 2}, 0.5)
+	if len(samples) != 24000 {
+		t.Fatalf("captured %d frames, want 24000", len(samples))
+	}
+	m := measure(samples, 48000)
+	if math.Abs(m.FrequencyHz-997) > 1 || math.Abs(m.PeakDBFS+6) > 0.1 || math.Abs(m.RMSDBFS+9.03) > 0.1 || m.THDPercent > 0.1 {
+		t.Errorf("measured %+v, want a clean 997 Hz sine peaking at -6 dBFS", m)
+	}
+
+	// played back through the generator, at a lower rate
+	data, _ := encodePCM(tone(16000, 16000, 440, 0.5), Pcm16)
+	if err := g.Play(context.Background(), data, Pcm16.String(), 16000, 1); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := g.DoCommand(context.Background(), map[string]interface{}{"measurement": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := resp["frequency_hz"].(float64); math.Abs(f-440) > 1 || resp["duration_seconds"] != 1.0 {
+		t.Errorf("measured %v", resp)
+	}
+}
+
+func TestGeneratorSquare(t *testing.T) {
+	samples, _ := captureGenerator(t, GeneratorConfig{Signal: "square", FrequencyHz: 500, LevelDBFS: -1}, 0.5)
+	m := measure(samples, 48000)
+	// the odd harmonics of a square up to the fifth: sqrt(1/9 + 1/25)
+	if math.Abs(m.FrequencyHz-500) > 1 || math.Abs(m.THDPercent-38.9) > 2 || m.Clipped != 0 {
+		t.Errorf("measured %+v, want 500 Hz with 39%% THD", m)
+	}
+}
+
+func TestGeneratorSweep(t *testing.T) {
+	samples, _ := captureGenerator(t, GeneratorConfig{Signal: "sweep", SweepStartHz: 100, SweepEndHz: 10000, SweepSeconds: 2}, 2)
+	// each second is half the sweep on a log scale, 100 Hz to 1 kHz to 10 kHz
+	var last float64
+	for i, want := range []float64{100 * math.Pow(100, 0.125), 1000 * math.Pow(100, 0.125)} {
+		at := i * 48000
+		f := measure(samples[at:at+24000], 48000).FrequencyHz
+		if f <= last || f < want/2 || f > want*2 {
+			t.Errorf("second %d centres on %v Hz, want about %v", i, f, want)
+		}
+		last = f
+	}
+}
+
+func TestGeneratorNoise(t *testing.T) {
+	// the power in an octave, from the spectrum of the whole capture
+	octave := func(samples []float32, from float64) float64 {
+		n := nextPow2(len(samples)) / 2
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(float64(samples[i]), 0)
+		}
+		fft(x, false)
+		var sum float64
+		for i := int(from * float64(n) / 48000); i < int(2*from*float64(n)/48000); i++ {
+			sum += real(x[i])*real(x[i]) + imag(x[i])*imag(x[i])
+		}
+		return 10 * math.Log10(sum)
+	}
+	for _, tc := range []struct {
+		signal string
+		slope  float64 // dB from the octave at 250 Hz to the one at 4 kHz
+	}{
+		{"white", 12},
+		{"pink", 0},
+	} {
+		samples, _ := captureGenerator(t, GeneratorConfig{Signal: tc.signal, Seed: 3, LevelDBFS: -12}, 2)
+		if level := dbfs(rms(samples)); math.Abs(level+12) > 0.5 {
+			t.Errorf("%s noise is at %.1f dBFS, want -12", tc.signal, level)
+		}
+		if slope := octave(samples, 4000) - octave(samples, 250); math.Abs(slope-tc.slope) > 1.5 {
+			t.Errorf("%s noise rises %.1f dB over four octaves, want %v", tc.signal, slope, tc.slope)
+		}
+	}
+}
+
+func TestGeneratorConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg GeneratorConfig
+		ok  bool
+	}{
+		{GeneratorConfig{}, true},
+		{GeneratorConfig{Signal: "sweep", SampleRate: 16000}, true},
+		{GeneratorConfig{Signal: "pink", LevelDBFS: -3}, true},
+		{GeneratorConfig{Signal: "saw"}, false},
+		{GeneratorConfig{LevelDBFS: 3}, false},
+		{GeneratorConfig{FrequencyHz: 30000}, false},
+		{GeneratorConfig{Signal: "sweep", SweepStartHz: 2000, SweepEndHz: 1000}, false},
+		{GeneratorConfig{Channels: -1}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+}
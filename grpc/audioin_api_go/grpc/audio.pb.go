@@ -89,26 +89,5826 @@ type GetAudioRequest struct {
 	Codec              string                 `protobuf:"bytes,3,opt,name=codec,proto3" json:"codec,omitempty"`
 	RequestId          string                 `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	MaxDurationSeconds float32                `protobuf:"fixed32,5,opt,name=max_duration_seconds,json=maxDurationSeconds,proto3" json:"max_duration_seconds,omitempty"`
-	PreviousTimestamp  float32                `protobuf:"fixed32,6,opt,name=previous_timestamp,json=previousTimestamp,proto3" json:"previous_timestamp,omitempty"`
+	SampleRate         int32                  `protobuf:"varint,7,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`    // 0 keeps the source rate
+	NumChannels        int32                  `protobuf:"varint,8,opt,name=num_channels,json=numChannels,proto3" json:"num_channels,omitempty"` // 0 keeps the source channel count
+	// only deliver audio while one of these conditions holds ("sound", "speech", "alarm", "impulse")
+	OnlyWhen               []string `protobuf:"bytes,9,rep,name=only_when,json=onlyWhen,proto3" json:"only_when,omitempty"`
+	PreRollSeconds         float32  `protobuf:"fixed32,10,opt,name=pre_roll_seconds,json=preRollSeconds,proto3" json:"pre_roll_seconds,omitempty"`                         // audio kept from before an only_when match, defaults to 0.5
+	PostRollSeconds        float32  `protobuf:"fixed32,11,opt,name=post_roll_seconds,json=postRollSeconds,proto3" json:"post_roll_seconds,omitempty"`                      // audio kept after an only_when match ends, defaults to 1
+	Vad                    string   `protobuf:"bytes,12,opt,name=vad,proto3" json:"vad,omitempty"`                                                                         // voice activity detector that sets speech on every chunk ("energy", "webrtc"), empty for none
+	SpeechOnly             bool     `protobuf:"varint,13,opt,name=speech_only,json=speechOnly,proto3" json:"speech_only,omitempty"`                                        // only deliver chunks containing speech, using vad or "energy"
+	TrimSilence            bool     `protobuf:"varint,14,opt,name=trim_silence,json=trimSilence,proto3" json:"trim_silence,omitempty"`                                     // drop stretches quieter than silence_threshold_dbfs for longer than the hangover
+	SilenceThresholdDbfs   float32  `protobuf:"fixed32,15,opt,name=silence_threshold_dbfs,json=silenceThresholdDbfs,proto3" json:"silence_threshold_dbfs,omitempty"`       // defaults to -50
+	SilenceHangoverSeconds float32  `protobuf:"fixed32,16,opt,name=silence_hangover_seconds,json=silenceHangoverSeconds,proto3" json:"silence_hangover_seconds,omitempty"` // defaults to 0.3
+	NoiseSuppression       string   `protobuf:"bytes,17,opt,name=noise_suppression,json=noiseSuppression,proto3" json:"noise_suppression,omitempty"`                       // engine removing background noise first ("spectral", "rnnoise"), empty for none
+	Agc                    bool     `protobuf:"varint,18,opt,name=agc,proto3" json:"agc,omitempty"`                                                                        // automatic gain control holding the level near agc_target_dbfs
+	AgcTargetDbfs          float32  `protobuf:"fixed32,19,opt,name=agc_target_dbfs,json=agcTargetDbfs,proto3" json:"agc_target_dbfs,omitempty"`                            // defaults to -20
+	AlsoSaveAs             string   `protobuf:"bytes,20,opt,name=also_save_as,json=alsoSaveAs,proto3" json:"also_save_as,omitempty"`                                       // also save the chunks as delivered under this name in the server's recording store
+	// fail with FAILED_PRECONDITION instead of converting when the source doesn't capture exactly the
+	// requested codec, sample_rate and num_channels; codec must be set
+	Strict bool `protobuf:"varint,21,opt,name=strict,proto3" json:"strict,omitempty"`
+	// keep the capture going for a while after the client drops, so the stream can be resumed
+	// with the resume_token of the last chunk received
+	Resumable bool `protobuf:"varint,22,opt,name=resumable,proto3" json:"resumable,omitempty"`
+	// continue the resumable stream right after the chunk that carried this token; the other
+	// fields but name are ignored, and an expired token fails with FAILED_PRECONDITION
+	ResumeToken string `protobuf:"bytes,23,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// regroup the audio into chunks this long (0.01 to 0.5), the last one of a stream or before a
+	// gap or format change shorter; 0 delivers chunks as the source captures them. Needs a raw pcm codec
+	ChunkDurationSeconds float32 `protobuf:"fixed32,24,opt,name=chunk_duration_seconds,json=chunkDurationSeconds,proto3" json:"chunk_duration_seconds,omitempty"`
+	// what to do when the client falls more than buffer_chunks behind the capture: "drop_oldest" (the
+	// default) or "drop_newest" chunks, reported as gaps, or "block", which holds up the capture
+	DropPolicy   string `protobuf:"bytes,25,opt,name=drop_policy,json=dropPolicy,proto3" json:"drop_policy,omitempty"`
+	BufferChunks int32  `protobuf:"varint,26,opt,name=buffer_chunks,json=bufferChunks,proto3" json:"buffer_chunks,omitempty"` // defaults to 50
+	// compress the stream's messages on the wire, whatever the codec: "gzip" or "snappy", empty for none.
+	// The client has to accept the compression in grpc-accept-encoding
+	Compression string `protobuf:"bytes,27,opt,name=compression,proto3" json:"compression,omitempty"`
+	// send up to this many chunks (2 to 100) in one message's batch, cutting the per-message overhead of
+	// short chunks on high-latency links; 0 or 1 sends every chunk on its own
+	BatchChunks          int32   `protobuf:"varint,28,opt,name=batch_chunks,json=batchChunks,proto3" json:"batch_chunks,omitempty"`
+	BatchMaxDelaySeconds float32 `protobuf:"fixed32,29,opt,name=batch_max_delay_seconds,json=batchMaxDelaySeconds,proto3" json:"batch_max_delay_seconds,omitempty"` // longest a chunk waits for its batch to fill, defaults to 1
+	// encode mp3 on the server from the shared capture, stepping the bitrate between min_bitrate_kbps
+	// and max_bitrate_kbps (32 and 128 by default) to keep up with the client; needs codec "mp3"
+	AdaptiveBitrate bool  `protobuf:"varint,30,opt,name=adaptive_bitrate,json=adaptiveBitrate,proto3" json:"adaptive_bitrate,omitempty"`
+	MinBitrateKbps  int32 `protobuf:"varint,31,opt,name=min_bitrate_kbps,json=minBitrateKbps,proto3" json:"min_bitrate_kbps,omitempty"`
+	MaxBitrateKbps  int32 `protobuf:"varint,32,opt,name=max_bitrate_kbps,json=maxBitrateKbps,proto3" json:"max_bitrate_kbps,omitempty"`
+	// sends an empty message marked heartbeat whenever the stream has sent nothing for this long,
+	// so that NATs and proxies keep the connection of a quiet stream open; from 1 to 300, 0 for none
+	HeartbeatSeconds float32 `protobuf:"fixed32,33,opt,name=heartbeat_seconds,json=heartbeatSeconds,proto3" json:"heartbeat_seconds,omitempty"`
+	// sets crc32 on each chunk, for the client to catch audio corrupted on the way
+	Checksums     bool `protobuf:"varint,34,opt,name=checksums,proto3" json:"checksums,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAudioRequest) Reset() {
+	*x = GetAudioRequest{}
+	mi := &file_audio_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAudioRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAudioRequest) ProtoMessage() {}
+
+func (x *GetAudioRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAudioRequest.ProtoReflect.Descriptor instead.
+func (*GetAudioRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetAudioRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetDurationSeconds() float32 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetMaxDurationSeconds() float32 {
+	if x != nil {
+		return x.MaxDurationSeconds
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetSampleRate() int32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetNumChannels() int32 {
+	if x != nil {
+		return x.NumChannels
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetOnlyWhen() []string {
+	if x != nil {
+		return x.OnlyWhen
+	}
+	return nil
+}
+
+func (x *GetAudioRequest) GetPreRollSeconds() float32 {
+	if x != nil {
+		return x.PreRollSeconds
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetPostRollSeconds() float32 {
+	if x != nil {
+		return x.PostRollSeconds
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetVad() string {
+	if x != nil {
+		return x.Vad
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetSpeechOnly() bool {
+	if x != nil {
+		return x.SpeechOnly
+	}
+	return false
+}
+
+func (x *GetAudioRequest) GetTrimSilence() bool {
+	if x != nil {
+		return x.TrimSilence
+	}
+	return false
+}
+
+func (x *GetAudioRequest) GetSilenceThresholdDbfs() float32 {
+	if x != nil {
+		return x.SilenceThresholdDbfs
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetSilenceHangoverSeconds() float32 {
+	if x != nil {
+		return x.SilenceHangoverSeconds
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetNoiseSuppression() string {
+	if x != nil {
+		return x.NoiseSuppression
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetAgc() bool {
+	if x != nil {
+		return x.Agc
+	}
+	return false
+}
+
+func (x *GetAudioRequest) GetAgcTargetDbfs() float32 {
+	if x != nil {
+		return x.AgcTargetDbfs
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetAlsoSaveAs() string {
+	if x != nil {
+		return x.AlsoSaveAs
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+func (x *GetAudioRequest) GetResumable() bool {
+	if x != nil {
+		return x.Resumable
+	}
+	return false
+}
+
+func (x *GetAudioRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetChunkDurationSeconds() float32 {
+	if x != nil {
+		return x.ChunkDurationSeconds
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetDropPolicy() string {
+	if x != nil {
+		return x.DropPolicy
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetBufferChunks() int32 {
+	if x != nil {
+		return x.BufferChunks
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *GetAudioRequest) GetBatchChunks() int32 {
+	if x != nil {
+		return x.BatchChunks
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetBatchMaxDelaySeconds() float32 {
+	if x != nil {
+		return x.BatchMaxDelaySeconds
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetAdaptiveBitrate() bool {
+	if x != nil {
+		return x.AdaptiveBitrate
+	}
+	return false
+}
+
+func (x *GetAudioRequest) GetMinBitrateKbps() int32 {
+	if x != nil {
+		return x.MinBitrateKbps
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetMaxBitrateKbps() int32 {
+	if x != nil {
+		return x.MaxBitrateKbps
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetHeartbeatSeconds() float32 {
+	if x != nil {
+		return x.HeartbeatSeconds
+	}
+	return 0
+}
+
+func (x *GetAudioRequest) GetChecksums() bool {
+	if x != nil {
+		return x.Checksums
+	}
+	return false
+}
+
+type AudioChunk struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	AudioData                 []byte                 `protobuf:"bytes,1,opt,name=audio_data,json=audioData,proto3" json:"audio_data,omitempty"`
+	Info                      *AudioInfo             `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	Sequence                  int32                  `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"` // counts the chunks sent from 0; on resumable streams their place in the capture, carrying on across resumes
+	StartTimestampNanoseconds int64                  `protobuf:"varint,4,opt,name=start_timestamp_nanoseconds,json=startTimestampNanoseconds,proto3" json:"start_timestamp_nanoseconds,omitempty"`
+	EndTimestampNanoseconds   int64                  `protobuf:"varint,5,opt,name=end_timestamp_nanoseconds,json=endTimestampNanoseconds,proto3" json:"end_timestamp_nanoseconds,omitempty"`
+	GapNanoseconds            int64                  `protobuf:"varint,6,opt,name=gap_nanoseconds,json=gapNanoseconds,proto3" json:"gap_nanoseconds,omitempty"` // audio skipped right before this chunk, e.g. while the stream was paused
+	Header                    *StreamHeader          `protobuf:"bytes,7,opt,name=header,proto3" json:"header,omitempty"`                                        // set on the first chunk of a stream and whenever the format changes
+	Speech                    *bool                  `protobuf:"varint,8,opt,name=speech,proto3,oneof" json:"speech,omitempty"`                                 // whether the chunk contains speech, unset unless the request named a vad
+	Timecode                  *Timecode              `protobuf:"bytes,9,opt,name=timecode,proto3" json:"timecode,omitempty"`                                    // the last LTC frame read in the chunk, unset when there was none
+	ResumeToken               string                 `protobuf:"bytes,10,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`          // resumes the stream after this chunk, set on resumable streams
+	DroppedChunks             int64                  `protobuf:"varint,11,opt,name=dropped_chunks,json=droppedChunks,proto3" json:"dropped_chunks,omitempty"`   // chunks the stream dropped so far because the client fell behind
+	// on streams requested with batch_chunks, the chunks sent in this message in order, each with its
+	// own timestamps; the other fields are then unset
+	Batch []*AudioChunk `protobuf:"bytes,12,rep,name=batch,proto3" json:"batch,omitempty"`
+	// when the server handed the chunk to the transport; with end_timestamp_nanoseconds this is
+	// the chunk's capture-to-send latency, unset on chunks without capture timestamps
+	SentTimestampNanoseconds int64   `protobuf:"varint,13,opt,name=sent_timestamp_nanoseconds,json=sentTimestampNanoseconds,proto3" json:"sent_timestamp_nanoseconds,omitempty"`
+	BitrateKbps              int32   `protobuf:"varint,14,opt,name=bitrate_kbps,json=bitrateKbps,proto3" json:"bitrate_kbps,omitempty"` // the chunk's bitrate on streams requested with adaptive_bitrate
+	Heartbeat                bool    `protobuf:"varint,15,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`                        // an empty message keeping a quiet stream alive, see heartbeat_seconds
+	Crc32                    *uint32 `protobuf:"fixed32,16,opt,name=crc32,proto3,oneof" json:"crc32,omitempty"`                         // the IEEE CRC-32 of audio_data, set on streams requested with checksums
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *AudioChunk) Reset() {
+	*x = AudioChunk{}
+	mi := &file_audio_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioChunk) ProtoMessage() {}
+
+func (x *AudioChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioChunk.ProtoReflect.Descriptor instead.
+func (*AudioChunk) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AudioChunk) GetAudioData() []byte {
+	if x != nil {
+		return x.AudioData
+	}
+	return nil
+}
+
+func (x *AudioChunk) GetInfo() *AudioInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *AudioChunk) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetStartTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.StartTimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetEndTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.EndTimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetGapNanoseconds() int64 {
+	if x != nil {
+		return x.GapNanoseconds
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetHeader() *StreamHeader {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *AudioChunk) GetSpeech() bool {
+	if x != nil && x.Speech != nil {
+		return *x.Speech
+	}
+	return false
+}
+
+func (x *AudioChunk) GetTimecode() *Timecode {
+	if x != nil {
+		return x.Timecode
+	}
+	return nil
+}
+
+func (x *AudioChunk) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *AudioChunk) GetDroppedChunks() int64 {
+	if x != nil {
+		return x.DroppedChunks
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetBatch() []*AudioChunk {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+func (x *AudioChunk) GetSentTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.SentTimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetBitrateKbps() int32 {
+	if x != nil {
+		return x.BitrateKbps
+	}
+	return 0
+}
+
+func (x *AudioChunk) GetHeartbeat() bool {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return false
+}
+
+func (x *AudioChunk) GetCrc32() uint32 {
+	if x != nil && x.Crc32 != nil {
+		return *x.Crc32
+	}
+	return 0
+}
+
+// StreamHeader describes the audio that follows it so clients can set up
+// decoders and write container files before the first chunk is decoded.
+type StreamHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Info          *AudioInfo             `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Extradata     []byte                 `protobuf:"bytes,2,opt,name=extradata,proto3" json:"extradata,omitempty"`                         // codec initialization data: OpusHead, FLAC STREAMINFO or AAC AudioSpecificConfig
+	ChunkFrames   int32                  `protobuf:"varint,3,opt,name=chunk_frames,json=chunkFrames,proto3" json:"chunk_frames,omitempty"` // frames in every chunk that follows but short ones before a gap or the end, 0 if they vary
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamHeader) Reset() {
+	*x = StreamHeader{}
+	mi := &file_audio_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamHeader) ProtoMessage() {}
+
+func (x *StreamHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamHeader.ProtoReflect.Descriptor instead.
+func (*StreamHeader) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamHeader) GetInfo() *AudioInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *StreamHeader) GetExtradata() []byte {
+	if x != nil {
+		return x.Extradata
+	}
+	return nil
+}
+
+func (x *StreamHeader) GetChunkFrames() int32 {
+	if x != nil {
+		return x.ChunkFrames
+	}
+	return 0
+}
+
+// Timecode is a linear timecode (SMPTE LTC) frame read from the audio.
+type Timecode struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Hours             int32                  `protobuf:"varint,1,opt,name=hours,proto3" json:"hours,omitempty"`
+	Minutes           int32                  `protobuf:"varint,2,opt,name=minutes,proto3" json:"minutes,omitempty"`
+	Seconds           int32                  `protobuf:"varint,3,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	Frames            int32                  `protobuf:"varint,4,opt,name=frames,proto3" json:"frames,omitempty"`
+	DropFrame         bool                   `protobuf:"varint,5,opt,name=drop_frame,json=dropFrame,proto3" json:"drop_frame,omitempty"`
+	FrameRate         int32                  `protobuf:"varint,6,opt,name=frame_rate,json=frameRate,proto3" json:"frame_rate,omitempty"` // nominal: 24, 25 or 30
+	UserBits          uint32                 `protobuf:"varint,7,opt,name=user_bits,json=userBits,proto3" json:"user_bits,omitempty"`
+	OffsetNanoseconds int64                  `protobuf:"varint,8,opt,name=offset_nanoseconds,json=offsetNanoseconds,proto3" json:"offset_nanoseconds,omitempty"` // from the start of the chunk to the start of the frame, negative when it began earlier
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Timecode) Reset() {
+	*x = Timecode{}
+	mi := &file_audio_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Timecode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timecode) ProtoMessage() {}
+
+func (x *Timecode) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timecode.ProtoReflect.Descriptor instead.
+func (*Timecode) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Timecode) GetHours() int32 {
+	if x != nil {
+		return x.Hours
+	}
+	return 0
+}
+
+func (x *Timecode) GetMinutes() int32 {
+	if x != nil {
+		return x.Minutes
+	}
+	return 0
+}
+
+func (x *Timecode) GetSeconds() int32 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+func (x *Timecode) GetFrames() int32 {
+	if x != nil {
+		return x.Frames
+	}
+	return 0
+}
+
+func (x *Timecode) GetDropFrame() bool {
+	if x != nil {
+		return x.DropFrame
+	}
+	return false
+}
+
+func (x *Timecode) GetFrameRate() int32 {
+	if x != nil {
+		return x.FrameRate
+	}
+	return 0
+}
+
+func (x *Timecode) GetUserBits() uint32 {
+	if x != nil {
+		return x.UserBits
+	}
+	return 0
+}
+
+func (x *Timecode) GetOffsetNanoseconds() int64 {
+	if x != nil {
+		return x.OffsetNanoseconds
+	}
+	return 0
+}
+
+type PlayRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AudioData []byte                 `protobuf:"bytes,2,opt,name=audio_data,json=audioData,proto3" json:"audio_data,omitempty"`
+	Info      *AudioInfo             `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
+	// plays raw pcm scaled to this integrated loudness in LUFS, peaking at
+	// most at -1 dBFS; 0 plays the audio as sent
+	NormalizeLufs float32 `protobuf:"fixed32,4,opt,name=normalize_lufs,json=normalizeLufs,proto3" json:"normalize_lufs,omitempty"`
+	// fail with FAILED_PRECONDITION instead of converting when the device doesn't play exactly info's format
+	Strict bool `protobuf:"varint,5,opt,name=strict,proto3" json:"strict,omitempty"`
+	// convert raw pcm the device doesn't play to the nearest format it does,
+	// rather than failing with INVALID_ARGUMENT; ignored when strict
+	Convert       bool `protobuf:"varint,6,opt,name=convert,proto3" json:"convert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayRequest) Reset() {
+	*x = PlayRequest{}
+	mi := &file_audio_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayRequest) ProtoMessage() {}
+
+func (x *PlayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayRequest.ProtoReflect.Descriptor instead.
+func (*PlayRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlayRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PlayRequest) GetAudioData() []byte {
+	if x != nil {
+		return x.AudioData
+	}
+	return nil
+}
+
+func (x *PlayRequest) GetInfo() *AudioInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *PlayRequest) GetNormalizeLufs() float32 {
+	if x != nil {
+		return x.NormalizeLufs
+	}
+	return 0
+}
+
+func (x *PlayRequest) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+func (x *PlayRequest) GetConvert() bool {
+	if x != nil {
+		return x.Convert
+	}
+	return false
+}
+
+type PlayResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayResponse) Reset() {
+	*x = PlayResponse{}
+	mi := &file_audio_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayResponse) ProtoMessage() {}
+
+func (x *PlayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayResponse.ProtoReflect.Descriptor instead.
+func (*PlayResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PlayResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PauseStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // request_id the GetAudio stream was started with
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseStreamRequest) Reset() {
+	*x = PauseStreamRequest{}
+	mi := &file_audio_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseStreamRequest) ProtoMessage() {}
+
+func (x *PauseStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseStreamRequest.ProtoReflect.Descriptor instead.
+func (*PauseStreamRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PauseStreamRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PauseStreamRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type PauseStreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseStreamResponse) Reset() {
+	*x = PauseStreamResponse{}
+	mi := &file_audio_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseStreamResponse) ProtoMessage() {}
+
+func (x *PauseStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseStreamResponse.ProtoReflect.Descriptor instead.
+func (*PauseStreamResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{8}
+}
+
+type ResumeStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeStreamRequest) Reset() {
+	*x = ResumeStreamRequest{}
+	mi := &file_audio_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeStreamRequest) ProtoMessage() {}
+
+func (x *ResumeStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeStreamRequest.ProtoReflect.Descriptor instead.
+func (*ResumeStreamRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ResumeStreamRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ResumeStreamRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type ResumeStreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeStreamResponse) Reset() {
+	*x = ResumeStreamResponse{}
+	mi := &file_audio_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeStreamResponse) ProtoMessage() {}
+
+func (x *ResumeStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeStreamResponse.ProtoReflect.Descriptor instead.
+func (*ResumeStreamResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{10}
+}
+
+type PreparePlaybackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AudioData     []byte                 `protobuf:"bytes,2,opt,name=audio_data,json=audioData,proto3" json:"audio_data,omitempty"`
+	Info          *AudioInfo             `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreparePlaybackRequest) Reset() {
+	*x = PreparePlaybackRequest{}
+	mi := &file_audio_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreparePlaybackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreparePlaybackRequest) ProtoMessage() {}
+
+func (x *PreparePlaybackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreparePlaybackRequest.ProtoReflect.Descriptor instead.
+func (*PreparePlaybackRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PreparePlaybackRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PreparePlaybackRequest) GetAudioData() []byte {
+	if x != nil {
+		return x.AudioData
+	}
+	return nil
+}
+
+func (x *PreparePlaybackRequest) GetInfo() *AudioInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type PreparePlaybackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handle        string                 `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"` // expires after a minute if not committed or released
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreparePlaybackResponse) Reset() {
+	*x = PreparePlaybackResponse{}
+	mi := &file_audio_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreparePlaybackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreparePlaybackResponse) ProtoMessage() {}
+
+func (x *PreparePlaybackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreparePlaybackResponse.ProtoReflect.Descriptor instead.
+func (*PreparePlaybackResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PreparePlaybackResponse) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+type CommitPlaybackRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Handle               string                 `protobuf:"bytes,2,opt,name=handle,proto3" json:"handle,omitempty"`
+	StartTimeNanoseconds int64                  `protobuf:"varint,3,opt,name=start_time_nanoseconds,json=startTimeNanoseconds,proto3" json:"start_time_nanoseconds,omitempty"` // unix time to start playing at, 0 for now
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CommitPlaybackRequest) Reset() {
+	*x = CommitPlaybackRequest{}
+	mi := &file_audio_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitPlaybackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitPlaybackRequest) ProtoMessage() {}
+
+func (x *CommitPlaybackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitPlaybackRequest.ProtoReflect.Descriptor instead.
+func (*CommitPlaybackRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CommitPlaybackRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CommitPlaybackRequest) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *CommitPlaybackRequest) GetStartTimeNanoseconds() int64 {
+	if x != nil {
+		return x.StartTimeNanoseconds
+	}
+	return 0
+}
+
+// sent once the clip has played out
+type CommitPlaybackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitPlaybackResponse) Reset() {
+	*x = CommitPlaybackResponse{}
+	mi := &file_audio_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitPlaybackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitPlaybackResponse) ProtoMessage() {}
+
+func (x *CommitPlaybackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitPlaybackResponse.ProtoReflect.Descriptor instead.
+func (*CommitPlaybackResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{14}
+}
+
+type ReleasePlaybackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Handle        string                 `protobuf:"bytes,2,opt,name=handle,proto3" json:"handle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleasePlaybackRequest) Reset() {
+	*x = ReleasePlaybackRequest{}
+	mi := &file_audio_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleasePlaybackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleasePlaybackRequest) ProtoMessage() {}
+
+func (x *ReleasePlaybackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleasePlaybackRequest.ProtoReflect.Descriptor instead.
+func (*ReleasePlaybackRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReleasePlaybackRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReleasePlaybackRequest) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+type ReleasePlaybackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleasePlaybackResponse) Reset() {
+	*x = ReleasePlaybackResponse{}
+	mi := &file_audio_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleasePlaybackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleasePlaybackResponse) ProtoMessage() {}
+
+func (x *ReleasePlaybackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleasePlaybackResponse.ProtoReflect.Descriptor instead.
+func (*ReleasePlaybackResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{16}
+}
+
+type SetProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Profile       string                 `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"` // empty hands the choice back to the linked sensor
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetProfileRequest) Reset() {
+	*x = SetProfileRequest{}
+	mi := &file_audio_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetProfileRequest) ProtoMessage() {}
+
+func (x *SetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetProfileRequest.ProtoReflect.Descriptor instead.
+func (*SetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SetProfileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetProfileRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+type SetProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetProfileResponse) Reset() {
+	*x = SetProfileResponse{}
+	mi := &file_audio_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetProfileResponse) ProtoMessage() {}
+
+func (x *SetProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetProfileResponse.ProtoReflect.Descriptor instead.
+func (*SetProfileResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{18}
+}
+
+type GetProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileRequest) Reset() {
+	*x = GetProfileRequest{}
+	mi := &file_audio_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileRequest) ProtoMessage() {}
+
+func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetProfileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       string                 `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"` // profile output is rendered with
+	Profiles      []string               `protobuf:"bytes,2,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	Automatic     bool                   `protobuf:"varint,3,opt,name=automatic,proto3" json:"automatic,omitempty"` // chosen from the linked sensor rather than set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileResponse) Reset() {
+	*x = GetProfileResponse{}
+	mi := &file_audio_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfileResponse) ProtoMessage() {}
+
+func (x *GetProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileResponse.ProtoReflect.Descriptor instead.
+func (*GetProfileResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetProfileResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *GetProfileResponse) GetProfiles() []string {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+func (x *GetProfileResponse) GetAutomatic() bool {
+	if x != nil {
+		return x.Automatic
+	}
+	return false
+}
+
+type EQBand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // lowshelf, highshelf, peaking, lowpass or highpass
+	FrequencyHz   float32                `protobuf:"fixed32,2,opt,name=frequency_hz,json=frequencyHz,proto3" json:"frequency_hz,omitempty"`
+	GainDb        float32                `protobuf:"fixed32,3,opt,name=gain_db,json=gainDb,proto3" json:"gain_db,omitempty"` // only for shelves and peaks
+	Q             float32                `protobuf:"fixed32,4,opt,name=q,proto3" json:"q,omitempty"`                         // defaults to 0.707
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EQBand) Reset() {
+	*x = EQBand{}
+	mi := &file_audio_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EQBand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EQBand) ProtoMessage() {}
+
+func (x *EQBand) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EQBand.ProtoReflect.Descriptor instead.
+func (*EQBand) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *EQBand) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EQBand) GetFrequencyHz() float32 {
+	if x != nil {
+		return x.FrequencyHz
+	}
+	return 0
+}
+
+func (x *EQBand) GetGainDb() float32 {
+	if x != nil {
+		return x.GainDb
+	}
+	return 0
+}
+
+func (x *EQBand) GetQ() float32 {
+	if x != nil {
+		return x.Q
+	}
+	return 0
+}
+
+type SetEQRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Bands         []*EQBand              `protobuf:"bytes,2,rep,name=bands,proto3" json:"bands,omitempty"` // replaces the whole chain, empty for none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEQRequest) Reset() {
+	*x = SetEQRequest{}
+	mi := &file_audio_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEQRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEQRequest) ProtoMessage() {}
+
+func (x *SetEQRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEQRequest.ProtoReflect.Descriptor instead.
+func (*SetEQRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetEQRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetEQRequest) GetBands() []*EQBand {
+	if x != nil {
+		return x.Bands
+	}
+	return nil
+}
+
+type SetEQResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEQResponse) Reset() {
+	*x = SetEQResponse{}
+	mi := &file_audio_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEQResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEQResponse) ProtoMessage() {}
+
+func (x *SetEQResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEQResponse.ProtoReflect.Descriptor instead.
+func (*SetEQResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{23}
+}
+
+type GetEQRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEQRequest) Reset() {
+	*x = GetEQRequest{}
+	mi := &file_audio_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEQRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEQRequest) ProtoMessage() {}
+
+func (x *GetEQRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEQRequest.ProtoReflect.Descriptor instead.
+func (*GetEQRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetEQRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetEQResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bands         []*EQBand              `protobuf:"bytes,1,rep,name=bands,proto3" json:"bands,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEQResponse) Reset() {
+	*x = GetEQResponse{}
+	mi := &file_audio_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEQResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEQResponse) ProtoMessage() {}
+
+func (x *GetEQResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEQResponse.ProtoReflect.Descriptor instead.
+func (*GetEQResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetEQResponse) GetBands() []*EQBand {
+	if x != nil {
+		return x.Bands
+	}
+	return nil
+}
+
+type GetLevelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	WindowSeconds float32                `protobuf:"fixed32,2,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"` // capture each report covers, defaults to 0.1
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLevelsRequest) Reset() {
+	*x = GetLevelsRequest{}
+	mi := &file_audio_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLevelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLevelsRequest) ProtoMessage() {}
+
+func (x *GetLevelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLevelsRequest.ProtoReflect.Descriptor instead.
+func (*GetLevelsRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetLevelsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetLevelsRequest) GetWindowSeconds() float32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+type ChannelLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rms           float32                `protobuf:"fixed32,1,opt,name=rms,proto3" json:"rms,omitempty"` // linear, 1 is full scale
+	Peak          float32                `protobuf:"fixed32,2,opt,name=peak,proto3" json:"peak,omitempty"`
+	RmsDbfs       float32                `protobuf:"fixed32,3,opt,name=rms_dbfs,json=rmsDbfs,proto3" json:"rms_dbfs,omitempty"`
+	PeakDbfs      float32                `protobuf:"fixed32,4,opt,name=peak_dbfs,json=peakDbfs,proto3" json:"peak_dbfs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChannelLevel) Reset() {
+	*x = ChannelLevel{}
+	mi := &file_audio_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChannelLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelLevel) ProtoMessage() {}
+
+func (x *ChannelLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelLevel.ProtoReflect.Descriptor instead.
+func (*ChannelLevel) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ChannelLevel) GetRms() float32 {
+	if x != nil {
+		return x.Rms
+	}
+	return 0
+}
+
+func (x *ChannelLevel) GetPeak() float32 {
+	if x != nil {
+		return x.Peak
+	}
+	return 0
+}
+
+func (x *ChannelLevel) GetRmsDbfs() float32 {
+	if x != nil {
+		return x.RmsDbfs
+	}
+	return 0
+}
+
+func (x *ChannelLevel) GetPeakDbfs() float32 {
+	if x != nil {
+		return x.PeakDbfs
+	}
+	return 0
+}
+
+type GetLevelsResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Channels             []*ChannelLevel        `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	TimestampNanoseconds int64                  `protobuf:"varint,2,opt,name=timestamp_nanoseconds,json=timestampNanoseconds,proto3" json:"timestamp_nanoseconds,omitempty"` // capture time of the window's first sample, 0 if unknown
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *GetLevelsResponse) Reset() {
+	*x = GetLevelsResponse{}
+	mi := &file_audio_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLevelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLevelsResponse) ProtoMessage() {}
+
+func (x *GetLevelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLevelsResponse.ProtoReflect.Descriptor instead.
+func (*GetLevelsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetLevelsResponse) GetChannels() []*ChannelLevel {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *GetLevelsResponse) GetTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.TimestampNanoseconds
+	}
+	return 0
+}
+
+type StreamSpectrumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FftSize       int32                  `protobuf:"varint,2,opt,name=fft_size,json=fftSize,proto3" json:"fft_size,omitempty"` // samples per frame, a power of two from 64 to 65536, defaults to 1024
+	HopSize       int32                  `protobuf:"varint,3,opt,name=hop_size,json=hopSize,proto3" json:"hop_size,omitempty"` // samples between the starts of frames, defaults to half the fft size
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSpectrumRequest) Reset() {
+	*x = StreamSpectrumRequest{}
+	mi := &file_audio_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSpectrumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSpectrumRequest) ProtoMessage() {}
+
+func (x *StreamSpectrumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSpectrumRequest.ProtoReflect.Descriptor instead.
+func (*StreamSpectrumRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *StreamSpectrumRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StreamSpectrumRequest) GetFftSize() int32 {
+	if x != nil {
+		return x.FftSize
+	}
+	return 0
+}
+
+func (x *StreamSpectrumRequest) GetHopSize() int32 {
+	if x != nil {
+		return x.HopSize
+	}
+	return 0
+}
+
+type SpectrumFrame struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// fft_size/2+1 bins from 0 Hz of the mono mix, linear with 1 for a full
+	// scale sine
+	Magnitudes           []float32 `protobuf:"fixed32,1,rep,packed,name=magnitudes,proto3" json:"magnitudes,omitempty"`
+	BinHz                float32   `protobuf:"fixed32,2,opt,name=bin_hz,json=binHz,proto3" json:"bin_hz,omitempty"`
+	TimestampNanoseconds int64     `protobuf:"varint,3,opt,name=timestamp_nanoseconds,json=timestampNanoseconds,proto3" json:"timestamp_nanoseconds,omitempty"` // capture time of the frame's first sample, 0 if unknown
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SpectrumFrame) Reset() {
+	*x = SpectrumFrame{}
+	mi := &file_audio_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpectrumFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpectrumFrame) ProtoMessage() {}
+
+func (x *SpectrumFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpectrumFrame.ProtoReflect.Descriptor instead.
+func (*SpectrumFrame) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SpectrumFrame) GetMagnitudes() []float32 {
+	if x != nil {
+		return x.Magnitudes
+	}
+	return nil
+}
+
+func (x *SpectrumFrame) GetBinHz() float32 {
+	if x != nil {
+		return x.BinHz
+	}
+	return 0
+}
+
+func (x *SpectrumFrame) GetTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.TimestampNanoseconds
+	}
+	return 0
+}
+
+// A spectrogram is rendered from the loop recording kept for the resource.
+type GetSpectrogramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Seconds       float64                `protobuf:"fixed64,2,opt,name=seconds,proto3" json:"seconds,omitempty"`                              // of the newest capture, all that is kept if zero
+	Width         int32                  `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`                                   // pixels, 800 if zero
+	Height        int32                  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`                                 // pixels, 256 if zero
+	FftSize       int32                  `protobuf:"varint,5,opt,name=fft_size,json=fftSize,proto3" json:"fft_size,omitempty"`                // 1024 if zero
+	FloorDbfs     float64                `protobuf:"fixed64,6,opt,name=floor_dbfs,json=floorDbfs,proto3" json:"floor_dbfs,omitempty"`         // the level shown black, -100 if zero
+	LogFrequency  bool                   `protobuf:"varint,7,opt,name=log_frequency,json=logFrequency,proto3" json:"log_frequency,omitempty"` // from 20 Hz rather than linear from 0
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpectrogramRequest) Reset() {
+	*x = GetSpectrogramRequest{}
+	mi := &file_audio_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpectrogramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpectrogramRequest) ProtoMessage() {}
+
+func (x *GetSpectrogramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpectrogramRequest.ProtoReflect.Descriptor instead.
+func (*GetSpectrogramRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetSpectrogramRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetSpectrogramRequest) GetSeconds() float64 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+func (x *GetSpectrogramRequest) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *GetSpectrogramRequest) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *GetSpectrogramRequest) GetFftSize() int32 {
+	if x != nil {
+		return x.FftSize
+	}
+	return 0
+}
+
+func (x *GetSpectrogramRequest) GetFloorDbfs() float64 {
+	if x != nil {
+		return x.FloorDbfs
+	}
+	return 0
+}
+
+func (x *GetSpectrogramRequest) GetLogFrequency() bool {
+	if x != nil {
+		return x.LogFrequency
+	}
+	return false
+}
+
+type GetSpectrogramResponse struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Png                       []byte                 `protobuf:"bytes,1,opt,name=png,proto3" json:"png,omitempty"`                                                                                 // time left to right, frequency bottom to top
+	StartTimestampNanoseconds int64                  `protobuf:"varint,2,opt,name=start_timestamp_nanoseconds,json=startTimestampNanoseconds,proto3" json:"start_timestamp_nanoseconds,omitempty"` // capture time of the first sample, 0 if unknown
+	DurationNanoseconds       int64                  `protobuf:"varint,3,opt,name=duration_nanoseconds,json=durationNanoseconds,proto3" json:"duration_nanoseconds,omitempty"`
+	SampleRate                int32                  `protobuf:"varint,4,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *GetSpectrogramResponse) Reset() {
+	*x = GetSpectrogramResponse{}
+	mi := &file_audio_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpectrogramResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpectrogramResponse) ProtoMessage() {}
+
+func (x *GetSpectrogramResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpectrogramResponse.ProtoReflect.Descriptor instead.
+func (*GetSpectrogramResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetSpectrogramResponse) GetPng() []byte {
+	if x != nil {
+		return x.Png
+	}
+	return nil
+}
+
+func (x *GetSpectrogramResponse) GetStartTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.StartTimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *GetSpectrogramResponse) GetDurationNanoseconds() int64 {
+	if x != nil {
+		return x.DurationNanoseconds
+	}
+	return 0
+}
+
+func (x *GetSpectrogramResponse) GetSampleRate() int32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+// A clip is the pre-roll a clip resource has kept up to the request
+// followed by the post-roll captured after it.
+type CaptureClipRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PreRollSeconds  float64                `protobuf:"fixed64,2,opt,name=pre_roll_seconds,json=preRollSeconds,proto3" json:"pre_roll_seconds,omitempty"`    // at most the configured pre-roll, all of it if zero
+	PostRollSeconds float64                `protobuf:"fixed64,3,opt,name=post_roll_seconds,json=postRollSeconds,proto3" json:"post_roll_seconds,omitempty"` // at most 60, the configured post-roll if zero
+	Codec           string                 `protobuf:"bytes,4,opt,name=codec,proto3" json:"codec,omitempty"`                                                // a raw pcm codec, pcm16 if empty
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CaptureClipRequest) Reset() {
+	*x = CaptureClipRequest{}
+	mi := &file_audio_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptureClipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptureClipRequest) ProtoMessage() {}
+
+func (x *CaptureClipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptureClipRequest.ProtoReflect.Descriptor instead.
+func (*CaptureClipRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CaptureClipRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CaptureClipRequest) GetPreRollSeconds() float64 {
+	if x != nil {
+		return x.PreRollSeconds
+	}
+	return 0
+}
+
+func (x *CaptureClipRequest) GetPostRollSeconds() float64 {
+	if x != nil {
+		return x.PostRollSeconds
+	}
+	return 0
+}
+
+func (x *CaptureClipRequest) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+type CaptureClipResponse struct {
+	state                       protoimpl.MessageState `protogen:"open.v1"`
+	AudioData                   []byte                 `protobuf:"bytes,1,opt,name=audio_data,json=audioData,proto3" json:"audio_data,omitempty"`
+	Info                        *AudioInfo             `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	StartTimestampNanoseconds   int64                  `protobuf:"varint,3,opt,name=start_timestamp_nanoseconds,json=startTimestampNanoseconds,proto3" json:"start_timestamp_nanoseconds,omitempty"`       // capture time of the first sample, 0 if unknown
+	TriggerTimestampNanoseconds int64                  `protobuf:"varint,4,opt,name=trigger_timestamp_nanoseconds,json=triggerTimestampNanoseconds,proto3" json:"trigger_timestamp_nanoseconds,omitempty"` // capture time the pre-roll ends at, 0 if unknown
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *CaptureClipResponse) Reset() {
+	*x = CaptureClipResponse{}
+	mi := &file_audio_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptureClipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptureClipResponse) ProtoMessage() {}
+
+func (x *CaptureClipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptureClipResponse.ProtoReflect.Descriptor instead.
+func (*CaptureClipResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CaptureClipResponse) GetAudioData() []byte {
+	if x != nil {
+		return x.AudioData
+	}
+	return nil
+}
+
+func (x *CaptureClipResponse) GetInfo() *AudioInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *CaptureClipResponse) GetStartTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.StartTimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *CaptureClipResponse) GetTriggerTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.TriggerTimestampNanoseconds
+	}
+	return 0
+}
+
+type StreamImpulsesRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RiseDb             float32                `protobuf:"fixed32,2,opt,name=rise_db,json=riseDb,proto3" json:"rise_db,omitempty"`                                       // rise above the background that starts an impulse, defaults to 20
+	MinPeakDbfs        float32                `protobuf:"fixed32,3,opt,name=min_peak_dbfs,json=minPeakDbfs,proto3" json:"min_peak_dbfs,omitempty"`                      // quieter impulses are ignored, defaults to -40
+	MaxDurationSeconds float32                `protobuf:"fixed32,4,opt,name=max_duration_seconds,json=maxDurationSeconds,proto3" json:"max_duration_seconds,omitempty"` // longer is sustained loudness, defaults to 0.5
+	SaveClips          bool                   `protobuf:"varint,5,opt,name=save_clips,json=saveClips,proto3" json:"save_clips,omitempty"`                               // save a clip of every impulse in the server's recording store
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
 
-func (x *GetAudioRequest) Reset() {
-	*x = GetAudioRequest{}
-	mi := &file_audio_proto_msgTypes[1]
+func (x *StreamImpulsesRequest) Reset() {
+	*x = StreamImpulsesRequest{}
+	mi := &file_audio_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamImpulsesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamImpulsesRequest) ProtoMessage() {}
+
+func (x *StreamImpulsesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamImpulsesRequest.ProtoReflect.Descriptor instead.
+func (*StreamImpulsesRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *StreamImpulsesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StreamImpulsesRequest) GetRiseDb() float32 {
+	if x != nil {
+		return x.RiseDb
+	}
+	return 0
+}
+
+func (x *StreamImpulsesRequest) GetMinPeakDbfs() float32 {
+	if x != nil {
+		return x.MinPeakDbfs
+	}
+	return 0
+}
+
+func (x *StreamImpulsesRequest) GetMaxDurationSeconds() float32 {
+	if x != nil {
+		return x.MaxDurationSeconds
+	}
+	return 0
+}
+
+func (x *StreamImpulsesRequest) GetSaveClips() bool {
+	if x != nil {
+		return x.SaveClips
+	}
+	return false
+}
+
+type ImpulseEvent struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TimestampNanoseconds int64                  `protobuf:"varint,1,opt,name=timestamp_nanoseconds,json=timestampNanoseconds,proto3" json:"timestamp_nanoseconds,omitempty"` // capture time of the onset, 0 if unknown
+	DurationSeconds      float32                `protobuf:"fixed32,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	PeakDbfs             float32                `protobuf:"fixed32,3,opt,name=peak_dbfs,json=peakDbfs,proto3" json:"peak_dbfs,omitempty"`
+	RiseDb               float32                `protobuf:"fixed32,4,opt,name=rise_db,json=riseDb,proto3" json:"rise_db,omitempty"`
+	BackgroundDbfs       float32                `protobuf:"fixed32,5,opt,name=background_dbfs,json=backgroundDbfs,proto3" json:"background_dbfs,omitempty"`
+	Kurtosis             float32                `protobuf:"fixed32,6,opt,name=kurtosis,proto3" json:"kurtosis,omitempty"`
+	Clip                 string                 `protobuf:"bytes,7,opt,name=clip,proto3" json:"clip,omitempty"` // name of the saved clip, empty unless save_clips was set
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ImpulseEvent) Reset() {
+	*x = ImpulseEvent{}
+	mi := &file_audio_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImpulseEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImpulseEvent) ProtoMessage() {}
+
+func (x *ImpulseEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImpulseEvent.ProtoReflect.Descriptor instead.
+func (*ImpulseEvent) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ImpulseEvent) GetTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.TimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *ImpulseEvent) GetDurationSeconds() float32 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *ImpulseEvent) GetPeakDbfs() float32 {
+	if x != nil {
+		return x.PeakDbfs
+	}
+	return 0
+}
+
+func (x *ImpulseEvent) GetRiseDb() float32 {
+	if x != nil {
+		return x.RiseDb
+	}
+	return 0
+}
+
+func (x *ImpulseEvent) GetBackgroundDbfs() float32 {
+	if x != nil {
+		return x.BackgroundDbfs
+	}
+	return 0
+}
+
+func (x *ImpulseEvent) GetKurtosis() float32 {
+	if x != nil {
+		return x.Kurtosis
+	}
+	return 0
+}
+
+func (x *ImpulseEvent) GetClip() string {
+	if x != nil {
+		return x.Clip
+	}
+	return ""
+}
+
+type GetLevelStatsRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Period           string                 `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`                                              // "hour" or "day", defaults to "hour"
+	StartNanoseconds int64                  `protobuf:"varint,3,opt,name=start_nanoseconds,json=startNanoseconds,proto3" json:"start_nanoseconds,omitempty"` // earliest bucket start, 0 for the oldest kept
+	EndNanoseconds   int64                  `protobuf:"varint,4,opt,name=end_nanoseconds,json=endNanoseconds,proto3" json:"end_nanoseconds,omitempty"`       // buckets starting before this, 0 for up to now
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetLevelStatsRequest) Reset() {
+	*x = GetLevelStatsRequest{}
+	mi := &file_audio_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLevelStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLevelStatsRequest) ProtoMessage() {}
+
+func (x *GetLevelStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLevelStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetLevelStatsRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetLevelStatsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetLevelStatsRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *GetLevelStatsRequest) GetStartNanoseconds() int64 {
+	if x != nil {
+		return x.StartNanoseconds
+	}
+	return 0
+}
+
+func (x *GetLevelStatsRequest) GetEndNanoseconds() int64 {
+	if x != nil {
+		return x.EndNanoseconds
+	}
+	return 0
+}
+
+// Level statistics of one hour or day, in dBFS from 125ms windows. Ln is
+// the level exceeded n% of the time.
+type LevelStatsBucket struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	StartNanoseconds int64                  `protobuf:"varint,1,opt,name=start_nanoseconds,json=startNanoseconds,proto3" json:"start_nanoseconds,omitempty"`
+	CoveredSeconds   float32                `protobuf:"fixed32,2,opt,name=covered_seconds,json=coveredSeconds,proto3" json:"covered_seconds,omitempty"` // capture that went into the bucket
+	LeqDbfs          float32                `protobuf:"fixed32,3,opt,name=leq_dbfs,json=leqDbfs,proto3" json:"leq_dbfs,omitempty"`
+	L10Dbfs          float32                `protobuf:"fixed32,4,opt,name=l10_dbfs,json=l10Dbfs,proto3" json:"l10_dbfs,omitempty"`
+	L50Dbfs          float32                `protobuf:"fixed32,5,opt,name=l50_dbfs,json=l50Dbfs,proto3" json:"l50_dbfs,omitempty"`
+	L90Dbfs          float32                `protobuf:"fixed32,6,opt,name=l90_dbfs,json=l90Dbfs,proto3" json:"l90_dbfs,omitempty"`
+	MaxDbfs          float32                `protobuf:"fixed32,7,opt,name=max_dbfs,json=maxDbfs,proto3" json:"max_dbfs,omitempty"`
+	MinDbfs          float32                `protobuf:"fixed32,8,opt,name=min_dbfs,json=minDbfs,proto3" json:"min_dbfs,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *LevelStatsBucket) Reset() {
+	*x = LevelStatsBucket{}
+	mi := &file_audio_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LevelStatsBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LevelStatsBucket) ProtoMessage() {}
+
+func (x *LevelStatsBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LevelStatsBucket.ProtoReflect.Descriptor instead.
+func (*LevelStatsBucket) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *LevelStatsBucket) GetStartNanoseconds() int64 {
+	if x != nil {
+		return x.StartNanoseconds
+	}
+	return 0
+}
+
+func (x *LevelStatsBucket) GetCoveredSeconds() float32 {
+	if x != nil {
+		return x.CoveredSeconds
+	}
+	return 0
+}
+
+func (x *LevelStatsBucket) GetLeqDbfs() float32 {
+	if x != nil {
+		return x.LeqDbfs
+	}
+	return 0
+}
+
+func (x *LevelStatsBucket) GetL10Dbfs() float32 {
+	if x != nil {
+		return x.L10Dbfs
+	}
+	return 0
+}
+
+func (x *LevelStatsBucket) GetL50Dbfs() float32 {
+	if x != nil {
+		return x.L50Dbfs
+	}
+	return 0
+}
+
+func (x *LevelStatsBucket) GetL90Dbfs() float32 {
+	if x != nil {
+		return x.L90Dbfs
+	}
+	return 0
+}
+
+func (x *LevelStatsBucket) GetMaxDbfs() float32 {
+	if x != nil {
+		return x.MaxDbfs
+	}
+	return 0
+}
+
+func (x *LevelStatsBucket) GetMinDbfs() float32 {
+	if x != nil {
+		return x.MinDbfs
+	}
+	return 0
+}
+
+type GetLevelStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*LevelStatsBucket    `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLevelStatsResponse) Reset() {
+	*x = GetLevelStatsResponse{}
+	mi := &file_audio_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLevelStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLevelStatsResponse) ProtoMessage() {}
+
+func (x *GetLevelStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLevelStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetLevelStatsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetLevelStatsResponse) GetBuckets() []*LevelStatsBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// Every list takes a page size and token, and filters that match
+// anything when unset.
+type ListHistoryRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PageSize          int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                            // defaults to 100, at most 1000
+	PageToken         string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                          // next_page_token of the previous page, empty for the newest
+	Direction         string                 `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"`                                           // streams: "capture" or "playback"
+	RequestId         string                 `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                          // streams
+	Subject           string                 `protobuf:"bytes,6,opt,name=subject,proto3" json:"subject,omitempty"`                                               // streams
+	Kind              string                 `protobuf:"bytes,7,opt,name=kind,proto3" json:"kind,omitempty"`                                                     // events
+	AfterNanoseconds  int64                  `protobuf:"varint,8,opt,name=after_nanoseconds,json=afterNanoseconds,proto3" json:"after_nanoseconds,omitempty"`    // streams started or events at or after
+	BeforeNanoseconds int64                  `protobuf:"varint,9,opt,name=before_nanoseconds,json=beforeNanoseconds,proto3" json:"before_nanoseconds,omitempty"` // streams started or events before
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListHistoryRequest) Reset() {
+	*x = ListHistoryRequest{}
+	mi := &file_audio_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHistoryRequest) ProtoMessage() {}
+
+func (x *ListHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListHistoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListHistoryRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ListHistoryRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ListHistoryRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ListHistoryRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ListHistoryRequest) GetAfterNanoseconds() int64 {
+	if x != nil {
+		return x.AfterNanoseconds
+	}
+	return 0
+}
+
+func (x *ListHistoryRequest) GetBeforeNanoseconds() int64 {
+	if x != nil {
+		return x.BeforeNanoseconds
+	}
+	return 0
+}
+
+// A GetAudio stream or Play call. Active ones have no end yet.
+type StreamRecord struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Direction        string                 `protobuf:"bytes,1,opt,name=direction,proto3" json:"direction,omitempty"` // "capture" or "playback"
+	Codec            string                 `protobuf:"bytes,2,opt,name=codec,proto3" json:"codec,omitempty"`
+	Profile          string                 `protobuf:"bytes,3,opt,name=profile,proto3" json:"profile,omitempty"`
+	RequestId        string                 `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Subject          string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"` // identity of the caller when the server authenticates
+	StartNanoseconds int64                  `protobuf:"varint,6,opt,name=start_nanoseconds,json=startNanoseconds,proto3" json:"start_nanoseconds,omitempty"`
+	EndNanoseconds   int64                  `protobuf:"varint,7,opt,name=end_nanoseconds,json=endNanoseconds,proto3" json:"end_nanoseconds,omitempty"`
+	Bytes            int64                  `protobuf:"varint,8,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Messages         int64                  `protobuf:"varint,9,opt,name=messages,proto3" json:"messages,omitempty"`
+	Error            string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`                                       // empty if it ended cleanly
+	Paused           bool                   `protobuf:"varint,11,opt,name=paused,proto3" json:"paused,omitempty"`                                    // active streams paused with PauseStream
+	DroppedChunks    int64                  `protobuf:"varint,12,opt,name=dropped_chunks,json=droppedChunks,proto3" json:"dropped_chunks,omitempty"` // dropped because the client fell behind
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StreamRecord) Reset() {
+	*x = StreamRecord{}
+	mi := &file_audio_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRecord) ProtoMessage() {}
+
+func (x *StreamRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRecord.ProtoReflect.Descriptor instead.
+func (*StreamRecord) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *StreamRecord) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *StreamRecord) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *StreamRecord) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *StreamRecord) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *StreamRecord) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *StreamRecord) GetStartNanoseconds() int64 {
+	if x != nil {
+		return x.StartNanoseconds
+	}
+	return 0
+}
+
+func (x *StreamRecord) GetEndNanoseconds() int64 {
+	if x != nil {
+		return x.EndNanoseconds
+	}
+	return 0
+}
+
+func (x *StreamRecord) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *StreamRecord) GetMessages() int64 {
+	if x != nil {
+		return x.Messages
+	}
+	return 0
+}
+
+func (x *StreamRecord) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *StreamRecord) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *StreamRecord) GetDroppedChunks() int64 {
+	if x != nil {
+		return x.DroppedChunks
+	}
+	return 0
+}
+
+type ListStreamHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*StreamRecord        `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`                                    // newest first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStreamHistoryResponse) Reset() {
+	*x = ListStreamHistoryResponse{}
+	mi := &file_audio_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStreamHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStreamHistoryResponse) ProtoMessage() {}
+
+func (x *ListStreamHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStreamHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListStreamHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListStreamHistoryResponse) GetRecords() []*StreamRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListStreamHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// An event reported to a client, such as a StreamImpulses impulse.
+type EventRecord struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Kind                 string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // "impulse" or "level"
+	TimestampNanoseconds int64                  `protobuf:"varint,2,opt,name=timestamp_nanoseconds,json=timestampNanoseconds,proto3" json:"timestamp_nanoseconds,omitempty"`
+	DurationSeconds      float32                `protobuf:"fixed32,3,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	PeakDbfs             float32                `protobuf:"fixed32,4,opt,name=peak_dbfs,json=peakDbfs,proto3" json:"peak_dbfs,omitempty"`
+	Clip                 string                 `protobuf:"bytes,5,opt,name=clip,proto3" json:"clip,omitempty"` // saved clip in the server's recording store, if any
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *EventRecord) Reset() {
+	*x = EventRecord{}
+	mi := &file_audio_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventRecord) ProtoMessage() {}
+
+func (x *EventRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventRecord.ProtoReflect.Descriptor instead.
+func (*EventRecord) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *EventRecord) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *EventRecord) GetTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.TimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *EventRecord) GetDurationSeconds() float32 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *EventRecord) GetPeakDbfs() float32 {
+	if x != nil {
+		return x.PeakDbfs
+	}
+	return 0
+}
+
+func (x *EventRecord) GetClip() string {
+	if x != nil {
+		return x.Clip
+	}
+	return ""
+}
+
+type ListEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*EventRecord         `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`                                      // newest first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsResponse) Reset() {
+	*x = ListEventsResponse{}
+	mi := &file_audio_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsResponse) ProtoMessage() {}
+
+func (x *ListEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListEventsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListEventsResponse) GetEvents() []*EventRecord {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ListAuditLogRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PageSize          int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // defaults to 100, at most 1000
+	PageToken         string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // empty for the newest
+	Action            string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Subject           string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	AfterNanoseconds  int64                  `protobuf:"varint,6,opt,name=after_nanoseconds,json=afterNanoseconds,proto3" json:"after_nanoseconds,omitempty"`    // recorded at or after
+	BeforeNanoseconds int64                  `protobuf:"varint,7,opt,name=before_nanoseconds,json=beforeNanoseconds,proto3" json:"before_nanoseconds,omitempty"` // recorded before
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListAuditLogRequest) Reset() {
+	*x = ListAuditLogRequest{}
+	mi := &file_audio_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogRequest) ProtoMessage() {}
+
+func (x *ListAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListAuditLogRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditLogRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetAfterNanoseconds() int64 {
+	if x != nil {
+		return x.AfterNanoseconds
+	}
+	return 0
+}
+
+func (x *ListAuditLogRequest) GetBeforeNanoseconds() int64 {
+	if x != nil {
+		return x.BeforeNanoseconds
+	}
+	return 0
+}
+
+// An operation on a resource's audio, as the server's audit log recorded it. Each record's hash
+// chains it to the one before, so records can't be removed or changed unnoticed
+type AuditRecord struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Seq             uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"` // counts the records of the whole log from 1
+	TimeNanoseconds int64                  `protobuf:"varint,2,opt,name=time_nanoseconds,json=timeNanoseconds,proto3" json:"time_nanoseconds,omitempty"`
+	Action          string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`                        // e.g. "capture_started", "play" or "recording_stopped"
+	Subject         string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`                      // identity of the caller when the server authenticates
+	Peer            string                 `protobuf:"bytes,5,opt,name=peer,proto3" json:"peer,omitempty"`                            // the caller's address
+	RequestId       string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // the stream's request id, or the recording's or session's id
+	Detail          string                 `protobuf:"bytes,7,opt,name=detail,proto3" json:"detail,omitempty"`
+	Error           string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"` // what the operation failed with, if it did
+	Hash            string                 `protobuf:"bytes,9,opt,name=hash,proto3" json:"hash,omitempty"`   // hex SHA-256 of the previous record's hash and this record
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AuditRecord) Reset() {
+	*x = AuditRecord{}
+	mi := &file_audio_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditRecord) ProtoMessage() {}
+
+func (x *AuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditRecord.ProtoReflect.Descriptor instead.
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *AuditRecord) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetTimeNanoseconds() int64 {
+	if x != nil {
+		return x.TimeNanoseconds
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type ListAuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*AuditRecord         `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`                                    // newest first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogResponse) Reset() {
+	*x = ListAuditLogResponse{}
+	mi := &file_audio_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogResponse) ProtoMessage() {}
+
+func (x *ListAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListAuditLogResponse) GetRecords() []*AuditRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListAuditLogResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ListActiveStreamsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PageSize          int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // defaults to 100, at most 1000
+	PageToken         string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // empty for the newest
+	Direction         string                 `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"`                  // "capture" or "playback"
+	RequestId         string                 `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Subject           string                 `protobuf:"bytes,6,opt,name=subject,proto3" json:"subject,omitempty"`
+	AfterNanoseconds  int64                  `protobuf:"varint,7,opt,name=after_nanoseconds,json=afterNanoseconds,proto3" json:"after_nanoseconds,omitempty"`    // started at or after
+	BeforeNanoseconds int64                  `protobuf:"varint,8,opt,name=before_nanoseconds,json=beforeNanoseconds,proto3" json:"before_nanoseconds,omitempty"` // started before
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListActiveStreamsRequest) Reset() {
+	*x = ListActiveStreamsRequest{}
+	mi := &file_audio_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveStreamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveStreamsRequest) ProtoMessage() {}
+
+func (x *ListActiveStreamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveStreamsRequest.ProtoReflect.Descriptor instead.
+func (*ListActiveStreamsRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListActiveStreamsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListActiveStreamsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListActiveStreamsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListActiveStreamsRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ListActiveStreamsRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ListActiveStreamsRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ListActiveStreamsRequest) GetAfterNanoseconds() int64 {
+	if x != nil {
+		return x.AfterNanoseconds
+	}
+	return 0
+}
+
+func (x *ListActiveStreamsRequest) GetBeforeNanoseconds() int64 {
+	if x != nil {
+		return x.BeforeNanoseconds
+	}
+	return 0
+}
+
+type ListActiveStreamsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Streams       []*StreamRecord        `protobuf:"bytes,1,rep,name=streams,proto3" json:"streams,omitempty"`                                    // newest first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveStreamsResponse) Reset() {
+	*x = ListActiveStreamsResponse{}
+	mi := &file_audio_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveStreamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveStreamsResponse) ProtoMessage() {}
+
+func (x *ListActiveStreamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveStreamsResponse.ProtoReflect.Descriptor instead.
+func (*ListActiveStreamsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListActiveStreamsResponse) GetStreams() []*StreamRecord {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+func (x *ListActiveStreamsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Recordings are in the server's recording store, shared by its resources.
+type ListRecordingsRequest struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Name                   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PageSize               int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                            // defaults to 100, at most 1000
+	PageToken              string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                          // empty for the first
+	Prefix                 string                 `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`                                                 // of the recording name
+	Format                 string                 `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`                                                 // "wav", "flac" or "chunks"
+	AfterNanoseconds       int64                  `protobuf:"varint,6,opt,name=after_nanoseconds,json=afterNanoseconds,proto3" json:"after_nanoseconds,omitempty"`    // modified at or after
+	BeforeNanoseconds      int64                  `protobuf:"varint,7,opt,name=before_nanoseconds,json=beforeNanoseconds,proto3" json:"before_nanoseconds,omitempty"` // modified before
+	Codec                  string                 `protobuf:"bytes,8,opt,name=codec,proto3" json:"codec,omitempty"`                                                   // of the audio, see StoredRecording
+	MinDurationNanoseconds int64                  `protobuf:"varint,9,opt,name=min_duration_nanoseconds,json=minDurationNanoseconds,proto3" json:"min_duration_nanoseconds,omitempty"`
+	MaxDurationNanoseconds int64                  `protobuf:"varint,10,opt,name=max_duration_nanoseconds,json=maxDurationNanoseconds,proto3" json:"max_duration_nanoseconds,omitempty"` // 0 for no bound
+	MinSizeBytes           int64                  `protobuf:"varint,11,opt,name=min_size_bytes,json=minSizeBytes,proto3" json:"min_size_bytes,omitempty"`
+	MaxSizeBytes           int64                  `protobuf:"varint,12,opt,name=max_size_bytes,json=maxSizeBytes,proto3" json:"max_size_bytes,omitempty"` // 0 for no bound
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ListRecordingsRequest) Reset() {
+	*x = ListRecordingsRequest{}
+	mi := &file_audio_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecordingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecordingsRequest) ProtoMessage() {}
+
+func (x *ListRecordingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecordingsRequest.ProtoReflect.Descriptor instead.
+func (*ListRecordingsRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListRecordingsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListRecordingsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRecordingsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListRecordingsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ListRecordingsRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ListRecordingsRequest) GetAfterNanoseconds() int64 {
+	if x != nil {
+		return x.AfterNanoseconds
+	}
+	return 0
+}
+
+func (x *ListRecordingsRequest) GetBeforeNanoseconds() int64 {
+	if x != nil {
+		return x.BeforeNanoseconds
+	}
+	return 0
+}
+
+func (x *ListRecordingsRequest) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *ListRecordingsRequest) GetMinDurationNanoseconds() int64 {
+	if x != nil {
+		return x.MinDurationNanoseconds
+	}
+	return 0
+}
+
+func (x *ListRecordingsRequest) GetMaxDurationNanoseconds() int64 {
+	if x != nil {
+		return x.MaxDurationNanoseconds
+	}
+	return 0
+}
+
+func (x *ListRecordingsRequest) GetMinSizeBytes() int64 {
+	if x != nil {
+		return x.MinSizeBytes
+	}
+	return 0
+}
+
+func (x *ListRecordingsRequest) GetMaxSizeBytes() int64 {
+	if x != nil {
+		return x.MaxSizeBytes
+	}
+	return 0
+}
+
+type StoredRecording struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Name                string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`     // as saved, without the extension
+	Format              string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"` // "wav", "flac" or "chunks"
+	SizeBytes           int64                  `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	ModifiedNanoseconds int64                  `protobuf:"varint,4,opt,name=modified_nanoseconds,json=modifiedNanoseconds,proto3" json:"modified_nanoseconds,omitempty"`
+	Id                  string                 `protobuf:"bytes,5,opt,name=id,proto3" json:"id,omitempty"` // the file in the store, as GetRecording and StreamRecording take it
+	// of the audio: "pcm8", "pcm16", "pcm24", "pcm32" or "pcm32_float" in a wav file, "flac", or
+	// the codec a stream was saved in
+	Codec               string `protobuf:"bytes,6,opt,name=codec,proto3" json:"codec,omitempty"`
+	SampleRate          int32  `protobuf:"varint,7,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	NumChannels         int32  `protobuf:"varint,8,opt,name=num_channels,json=numChannels,proto3" json:"num_channels,omitempty"`
+	DurationNanoseconds int64  `protobuf:"varint,9,opt,name=duration_nanoseconds,json=durationNanoseconds,proto3" json:"duration_nanoseconds,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *StoredRecording) Reset() {
+	*x = StoredRecording{}
+	mi := &file_audio_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoredRecording) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredRecording) ProtoMessage() {}
+
+func (x *StoredRecording) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredRecording.ProtoReflect.Descriptor instead.
+func (*StoredRecording) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *StoredRecording) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StoredRecording) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *StoredRecording) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *StoredRecording) GetModifiedNanoseconds() int64 {
+	if x != nil {
+		return x.ModifiedNanoseconds
+	}
+	return 0
+}
+
+func (x *StoredRecording) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StoredRecording) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *StoredRecording) GetSampleRate() int32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *StoredRecording) GetNumChannels() int32 {
+	if x != nil {
+		return x.NumChannels
+	}
+	return 0
+}
+
+func (x *StoredRecording) GetDurationNanoseconds() int64 {
+	if x != nil {
+		return x.DurationNanoseconds
+	}
+	return 0
+}
+
+type GetRecordingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordingRequest) Reset() {
+	*x = GetRecordingRequest{}
+	mi := &file_audio_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordingRequest) ProtoMessage() {}
+
+func (x *GetRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordingRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetRecordingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetRecordingRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetRecordingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Recording     *StoredRecording       `protobuf:"bytes,1,opt,name=recording,proto3" json:"recording,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordingResponse) Reset() {
+	*x = GetRecordingResponse{}
+	mi := &file_audio_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordingResponse) ProtoMessage() {}
+
+func (x *GetRecordingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordingResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordingResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetRecordingResponse) GetRecording() *StoredRecording {
+	if x != nil {
+		return x.Recording
+	}
+	return nil
+}
+
+// The recording is streamed as fast as the client reads it, in chunks of
+// 100ms; saved streams are replayed chunk for chunk as they were saved.
+type StreamRecordingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Codec         string                 `protobuf:"bytes,3,opt,name=codec,proto3" json:"codec,omitempty"`                                     // "pcm16" (the default), "pcm32" or "pcm32_float", ignored for saved streams
+	StartSeconds  float64                `protobuf:"fixed64,4,opt,name=start_seconds,json=startSeconds,proto3" json:"start_seconds,omitempty"` // into the recording
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRecordingRequest) Reset() {
+	*x = StreamRecordingRequest{}
+	mi := &file_audio_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRecordingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRecordingRequest) ProtoMessage() {}
+
+func (x *StreamRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRecordingRequest.ProtoReflect.Descriptor instead.
+func (*StreamRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *StreamRecordingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StreamRecordingRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StreamRecordingRequest) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *StreamRecordingRequest) GetStartSeconds() float64 {
+	if x != nil {
+		return x.StartSeconds
+	}
+	return 0
+}
+
+type ListRecordingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Recordings    []*StoredRecording     `protobuf:"bytes,1,rep,name=recordings,proto3" json:"recordings,omitempty"`                              // by name
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecordingsResponse) Reset() {
+	*x = ListRecordingsResponse{}
+	mi := &file_audio_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecordingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecordingsResponse) ProtoMessage() {}
+
+func (x *ListRecordingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecordingsResponse.ProtoReflect.Descriptor instead.
+func (*ListRecordingsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListRecordingsResponse) GetRecordings() []*StoredRecording {
+	if x != nil {
+		return x.Recordings
+	}
+	return nil
+}
+
+func (x *ListRecordingsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetLatencyStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatencyStatsRequest) Reset() {
+	*x = GetLatencyStatsRequest{}
+	mi := &file_audio_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatencyStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatencyStatsRequest) ProtoMessage() {}
+
+func (x *GetLatencyStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatencyStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetLatencyStatsRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *GetLatencyStatsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// GetLatencyStatsResponse is the capture-to-send latency of the latest chunks the server sent of
+// the resource's GetAudio streams: from the capture of each chunk's last frame to it being sent
+type GetLatencyStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunks        int64                  `protobuf:"varint,1,opt,name=chunks,proto3" json:"chunks,omitempty"` // measured, the latest 1000 at most; 0 before any chunk was sent
+	P50Seconds    float64                `protobuf:"fixed64,2,opt,name=p50_seconds,json=p50Seconds,proto3" json:"p50_seconds,omitempty"`
+	P95Seconds    float64                `protobuf:"fixed64,3,opt,name=p95_seconds,json=p95Seconds,proto3" json:"p95_seconds,omitempty"`
+	P99Seconds    float64                `protobuf:"fixed64,4,opt,name=p99_seconds,json=p99Seconds,proto3" json:"p99_seconds,omitempty"`
+	MaxSeconds    float64                `protobuf:"fixed64,5,opt,name=max_seconds,json=maxSeconds,proto3" json:"max_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatencyStatsResponse) Reset() {
+	*x = GetLatencyStatsResponse{}
+	mi := &file_audio_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatencyStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatencyStatsResponse) ProtoMessage() {}
+
+func (x *GetLatencyStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatencyStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetLatencyStatsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetLatencyStatsResponse) GetChunks() int64 {
+	if x != nil {
+		return x.Chunks
+	}
+	return 0
+}
+
+func (x *GetLatencyStatsResponse) GetP50Seconds() float64 {
+	if x != nil {
+		return x.P50Seconds
+	}
+	return 0
+}
+
+func (x *GetLatencyStatsResponse) GetP95Seconds() float64 {
+	if x != nil {
+		return x.P95Seconds
+	}
+	return 0
+}
+
+func (x *GetLatencyStatsResponse) GetP99Seconds() float64 {
+	if x != nil {
+		return x.P99Seconds
+	}
+	return 0
+}
+
+func (x *GetLatencyStatsResponse) GetMaxSeconds() float64 {
+	if x != nil {
+		return x.MaxSeconds
+	}
+	return 0
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_audio_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetStatsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// GetStatsResponse is what the server has counted of the resource since it started, and what of
+// it is in progress now
+type GetStatsResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	UptimeSeconds            float64                `protobuf:"fixed64,1,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`                                      // of the server
+	ActiveStreams            int32                  `protobuf:"varint,2,opt,name=active_streams,json=activeStreams,proto3" json:"active_streams,omitempty"`                                       // GetAudio streams in progress
+	ActivePlays              int32                  `protobuf:"varint,3,opt,name=active_plays,json=activePlays,proto3" json:"active_plays,omitempty"`                                             // Play calls in progress
+	ActiveSessions           int32                  `protobuf:"varint,4,opt,name=active_sessions,json=activeSessions,proto3" json:"active_sessions,omitempty"`                                    // recording sessions in progress
+	BytesCaptured            int64                  `protobuf:"varint,5,opt,name=bytes_captured,json=bytesCaptured,proto3" json:"bytes_captured,omitempty"`                                       // audio sent by GetAudio streams
+	BytesPlayed              int64                  `protobuf:"varint,6,opt,name=bytes_played,json=bytesPlayed,proto3" json:"bytes_played,omitempty"`                                             // audio received by Play
+	DroppedChunks            int64                  `protobuf:"varint,7,opt,name=dropped_chunks,json=droppedChunks,proto3" json:"dropped_chunks,omitempty"`                                       // chunks GetAudio streams dropped because their client fell behind
+	Overruns                 int64                  `protobuf:"varint,8,opt,name=overruns,proto3" json:"overruns,omitempty"`                                                                      // times capture overran the device's buffer, 0 if the resource doesn't count them
+	Underruns                int64                  `protobuf:"varint,9,opt,name=underruns,proto3" json:"underruns,omitempty"`                                                                    // times playback ran the device's buffer dry, 0 if the resource doesn't count them
+	LastError                string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`                                                   // of the last GetAudio stream or Play call that failed, empty if none has
+	LastErrorTimeNanoseconds int64                  `protobuf:"varint,11,opt,name=last_error_time_nanoseconds,json=lastErrorTimeNanoseconds,proto3" json:"last_error_time_nanoseconds,omitempty"` // unix time of last_error, 0 if none
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_audio_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetStatsResponse) GetUptimeSeconds() float64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetActiveStreams() int32 {
+	if x != nil {
+		return x.ActiveStreams
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetActivePlays() int32 {
+	if x != nil {
+		return x.ActivePlays
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetActiveSessions() int32 {
+	if x != nil {
+		return x.ActiveSessions
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetBytesCaptured() int64 {
+	if x != nil {
+		return x.BytesCaptured
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetBytesPlayed() int64 {
+	if x != nil {
+		return x.BytesPlayed
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetDroppedChunks() int64 {
+	if x != nil {
+		return x.DroppedChunks
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetOverruns() int64 {
+	if x != nil {
+		return x.Overruns
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetUnderruns() int64 {
+	if x != nil {
+		return x.Underruns
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *GetStatsResponse) GetLastErrorTimeNanoseconds() int64 {
+	if x != nil {
+		return x.LastErrorTimeNanoseconds
+	}
+	return 0
+}
+
+type GetHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHealthRequest) Reset() {
+	*x = GetHealthRequest{}
+	mi := &file_audio_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHealthRequest) ProtoMessage() {}
+
+func (x *GetHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHealthRequest.ProtoReflect.Descriptor instead.
+func (*GetHealthRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *GetHealthRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// DeviceHealth is the state of one of a resource's devices as last seen
+type DeviceHealth struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	State            string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`                                                // healthy, recovering, unavailable or off
+	Reason           string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`                                              // why it isn't healthy, empty if it is
+	SinceNanoseconds int64                  `protobuf:"varint,3,opt,name=since_nanoseconds,json=sinceNanoseconds,proto3" json:"since_nanoseconds,omitempty"` // when it entered the state, 0 if it always was
+	ReopenAttempts   int32                  `protobuf:"varint,4,opt,name=reopen_attempts,json=reopenAttempts,proto3" json:"reopen_attempts,omitempty"`       // times it has been opened again since it was lost
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeviceHealth) Reset() {
+	*x = DeviceHealth{}
+	mi := &file_audio_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceHealth) ProtoMessage() {}
+
+func (x *DeviceHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceHealth.ProtoReflect.Descriptor instead.
+func (*DeviceHealth) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *DeviceHealth) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *DeviceHealth) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DeviceHealth) GetSinceNanoseconds() int64 {
+	if x != nil {
+		return x.SinceNanoseconds
+	}
+	return 0
+}
+
+func (x *DeviceHealth) GetReopenAttempts() int32 {
+	if x != nil {
+		return x.ReopenAttempts
+	}
+	return 0
+}
+
+// GetHealthResponse is the health of the resource's devices. A resource is ready when none of its
+// devices is recovering or unavailable
+type GetHealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ready         bool                   `protobuf:"varint,1,opt,name=ready,proto3" json:"ready,omitempty"`
+	Capture       *DeviceHealth          `protobuf:"bytes,2,opt,name=capture,proto3" json:"capture,omitempty"`
+	Playback      *DeviceHealth          `protobuf:"bytes,3,opt,name=playback,proto3" json:"playback,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHealthResponse) Reset() {
+	*x = GetHealthResponse{}
+	mi := &file_audio_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHealthResponse) ProtoMessage() {}
+
+func (x *GetHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHealthResponse.ProtoReflect.Descriptor instead.
+func (*GetHealthResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetHealthResponse) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *GetHealthResponse) GetCapture() *DeviceHealth {
+	if x != nil {
+		return x.Capture
+	}
+	return nil
+}
+
+func (x *GetHealthResponse) GetPlayback() *DeviceHealth {
+	if x != nil {
+		return x.Playback
+	}
+	return nil
+}
+
+// DoCommandRequest carries a resource's DoCommand. The server handles dump_state, list_sessions
+// and reset_device itself and passes other commands to the resource
+type DoCommandRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CommandJson   string                 `protobuf:"bytes,2,opt,name=command_json,json=commandJson,proto3" json:"command_json,omitempty"` // a JSON object
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DoCommandRequest) Reset() {
+	*x = DoCommandRequest{}
+	mi := &file_audio_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DoCommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DoCommandRequest) ProtoMessage() {}
+
+func (x *DoCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DoCommandRequest.ProtoReflect.Descriptor instead.
+func (*DoCommandRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *DoCommandRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DoCommandRequest) GetCommandJson() string {
+	if x != nil {
+		return x.CommandJson
+	}
+	return ""
+}
+
+type DoCommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ResultJson    string                 `protobuf:"bytes,1,opt,name=result_json,json=resultJson,proto3" json:"result_json,omitempty"` // a JSON object
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DoCommandResponse) Reset() {
+	*x = DoCommandResponse{}
+	mi := &file_audio_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DoCommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DoCommandResponse) ProtoMessage() {}
+
+func (x *DoCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DoCommandResponse.ProtoReflect.Descriptor instead.
+func (*DoCommandResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *DoCommandResponse) GetResultJson() string {
+	if x != nil {
+		return x.ResultJson
+	}
+	return ""
+}
+
+type GetRecordingStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordingStatsRequest) Reset() {
+	*x = GetRecordingStatsRequest{}
+	mi := &file_audio_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordingStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordingStatsRequest) ProtoMessage() {}
+
+func (x *GetRecordingStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordingStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordingStatsRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetRecordingStatsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetRecordingStatsResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Recordings        int32                  `protobuf:"varint,1,opt,name=recordings,proto3" json:"recordings,omitempty"`
+	TotalBytes        int64                  `protobuf:"varint,2,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`                      // of the recordings
+	OldestNanoseconds int64                  `protobuf:"varint,3,opt,name=oldest_nanoseconds,json=oldestNanoseconds,proto3" json:"oldest_nanoseconds,omitempty"` // last modified, 0 without recordings
+	NewestNanoseconds int64                  `protobuf:"varint,4,opt,name=newest_nanoseconds,json=newestNanoseconds,proto3" json:"newest_nanoseconds,omitempty"`
+	DiskFreeBytes     int64                  `protobuf:"varint,5,opt,name=disk_free_bytes,json=diskFreeBytes,proto3" json:"disk_free_bytes,omitempty"` // on the store's filesystem, 0 if unknown
+	DiskSizeBytes     int64                  `protobuf:"varint,6,opt,name=disk_size_bytes,json=diskSizeBytes,proto3" json:"disk_size_bytes,omitempty"`
+	MaxAgeSeconds     float64                `protobuf:"fixed64,7,opt,name=max_age_seconds,json=maxAgeSeconds,proto3" json:"max_age_seconds,omitempty"`       // of the retention policy, 0 if unbounded
+	MaxBytes          int64                  `protobuf:"varint,8,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`                         // 0 if unbounded
+	PrunedRecordings  int32                  `protobuf:"varint,9,opt,name=pruned_recordings,json=prunedRecordings,proto3" json:"pruned_recordings,omitempty"` // deleted by the retention policy since the server started
+	PrunedBytes       int64                  `protobuf:"varint,10,opt,name=pruned_bytes,json=prunedBytes,proto3" json:"pruned_bytes,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetRecordingStatsResponse) Reset() {
+	*x = GetRecordingStatsResponse{}
+	mi := &file_audio_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordingStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordingStatsResponse) ProtoMessage() {}
+
+func (x *GetRecordingStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordingStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordingStatsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *GetRecordingStatsResponse) GetRecordings() int32 {
+	if x != nil {
+		return x.Recordings
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetOldestNanoseconds() int64 {
+	if x != nil {
+		return x.OldestNanoseconds
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetNewestNanoseconds() int64 {
+	if x != nil {
+		return x.NewestNanoseconds
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetDiskFreeBytes() int64 {
+	if x != nil {
+		return x.DiskFreeBytes
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetDiskSizeBytes() int64 {
+	if x != nil {
+		return x.DiskSizeBytes
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetMaxAgeSeconds() float64 {
+	if x != nil {
+		return x.MaxAgeSeconds
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetPrunedRecordings() int32 {
+	if x != nil {
+		return x.PrunedRecordings
+	}
+	return 0
+}
+
+func (x *GetRecordingStatsResponse) GetPrunedBytes() int64 {
+	if x != nil {
+		return x.PrunedBytes
+	}
+	return 0
+}
+
+// A recording writes a resource's capture into the recording store in
+// segments of a fixed length, until it is stopped.
+type StartRecordingRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SegmentSeconds float64                `protobuf:"fixed64,2,opt,name=segment_seconds,json=segmentSeconds,proto3" json:"segment_seconds,omitempty"` // 60 if zero
+	Format         string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`                                         // "wav" (the default) or "flac"
+	NormalizeLufs  float64                `protobuf:"fixed64,4,opt,name=normalize_lufs,json=normalizeLufs,proto3" json:"normalize_lufs,omitempty"`    // the loudness each segment is scaled to, if negative
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StartRecordingRequest) Reset() {
+	*x = StartRecordingRequest{}
+	mi := &file_audio_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRecordingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRecordingRequest) ProtoMessage() {}
+
+func (x *StartRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRecordingRequest.ProtoReflect.Descriptor instead.
+func (*StartRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *StartRecordingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StartRecordingRequest) GetSegmentSeconds() float64 {
+	if x != nil {
+		return x.SegmentSeconds
+	}
+	return 0
+}
+
+func (x *StartRecordingRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *StartRecordingRequest) GetNormalizeLufs() float64 {
+	if x != nil {
+		return x.NormalizeLufs
+	}
+	return 0
+}
+
+type StartRecordingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordingId   string                 `protobuf:"bytes,1,opt,name=recording_id,json=recordingId,proto3" json:"recording_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRecordingResponse) Reset() {
+	*x = StartRecordingResponse{}
+	mi := &file_audio_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRecordingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRecordingResponse) ProtoMessage() {}
+
+func (x *StartRecordingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRecordingResponse.ProtoReflect.Descriptor instead.
+func (*StartRecordingResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *StartRecordingResponse) GetRecordingId() string {
+	if x != nil {
+		return x.RecordingId
+	}
+	return ""
+}
+
+type StopRecordingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RecordingId   string                 `protobuf:"bytes,2,opt,name=recording_id,json=recordingId,proto3" json:"recording_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRecordingRequest) Reset() {
+	*x = StopRecordingRequest{}
+	mi := &file_audio_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRecordingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRecordingRequest) ProtoMessage() {}
+
+func (x *StopRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRecordingRequest.ProtoReflect.Descriptor instead.
+func (*StopRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *StopRecordingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StopRecordingRequest) GetRecordingId() string {
+	if x != nil {
+		return x.RecordingId
+	}
+	return ""
+}
+
+type StopRecordingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Segments      []*RecordingSegment    `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRecordingResponse) Reset() {
+	*x = StopRecordingResponse{}
+	mi := &file_audio_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRecordingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRecordingResponse) ProtoMessage() {}
+
+func (x *StopRecordingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRecordingResponse.ProtoReflect.Descriptor instead.
+func (*StopRecordingResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *StopRecordingResponse) GetSegments() []*RecordingSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+type ListSegmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RecordingId   string                 `protobuf:"bytes,2,opt,name=recording_id,json=recordingId,proto3" json:"recording_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSegmentsRequest) Reset() {
+	*x = ListSegmentsRequest{}
+	mi := &file_audio_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSegmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSegmentsRequest) ProtoMessage() {}
+
+func (x *ListSegmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSegmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListSegmentsRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ListSegmentsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListSegmentsRequest) GetRecordingId() string {
+	if x != nil {
+		return x.RecordingId
+	}
+	return ""
+}
+
+type RecordingSegment struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	File                      string                 `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`                                                                               // in the recording store
+	StartTimestampNanoseconds int64                  `protobuf:"varint,2,opt,name=start_timestamp_nanoseconds,json=startTimestampNanoseconds,proto3" json:"start_timestamp_nanoseconds,omitempty"` // capture time of the first sample
+	DurationNanoseconds       int64                  `protobuf:"varint,3,opt,name=duration_nanoseconds,json=durationNanoseconds,proto3" json:"duration_nanoseconds,omitempty"`
+	Complete                  bool                   `protobuf:"varint,4,opt,name=complete,proto3" json:"complete,omitempty"` // false while it is being written
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *RecordingSegment) Reset() {
+	*x = RecordingSegment{}
+	mi := &file_audio_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordingSegment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordingSegment) ProtoMessage() {}
+
+func (x *RecordingSegment) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordingSegment.ProtoReflect.Descriptor instead.
+func (*RecordingSegment) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *RecordingSegment) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *RecordingSegment) GetStartTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.StartTimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *RecordingSegment) GetDurationNanoseconds() int64 {
+	if x != nil {
+		return x.DurationNanoseconds
+	}
+	return 0
+}
+
+func (x *RecordingSegment) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+type ListSegmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Segments      []*RecordingSegment    `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"` // oldest first
+	Active        bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // why the recording ended on its own, if it did
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSegmentsResponse) Reset() {
+	*x = ListSegmentsResponse{}
+	mi := &file_audio_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSegmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSegmentsResponse) ProtoMessage() {}
+
+func (x *ListSegmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSegmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListSegmentsResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ListSegmentsResponse) GetSegments() []*RecordingSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *ListSegmentsResponse) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *ListSegmentsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// A track of a recording session records a resource's capture, or some of
+// its channels, to segments of its own.
+type RecordingTrack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resource      string                 `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`         // the resource the request names if empty
+	Channels      []int32                `protobuf:"varint,2,rep,packed,name=channels,proto3" json:"channels,omitempty"` // zero based channels of the capture to keep, all if empty
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                 // prefixes the track's files, the resource and its channels if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordingTrack) Reset() {
+	*x = RecordingTrack{}
+	mi := &file_audio_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordingTrack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordingTrack) ProtoMessage() {}
+
+func (x *RecordingTrack) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordingTrack.ProtoReflect.Descriptor instead.
+func (*RecordingTrack) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *RecordingTrack) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *RecordingTrack) GetChannels() []int32 {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *RecordingTrack) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type StartRecordingSessionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tracks         []*RecordingTrack      `protobuf:"bytes,2,rep,name=tracks,proto3" json:"tracks,omitempty"`
+	SegmentSeconds float64                `protobuf:"fixed64,3,opt,name=segment_seconds,json=segmentSeconds,proto3" json:"segment_seconds,omitempty"` // 60 if zero
+	Format         string                 `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`                                         // "wav" (the default) or "flac"
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StartRecordingSessionRequest) Reset() {
+	*x = StartRecordingSessionRequest{}
+	mi := &file_audio_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRecordingSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRecordingSessionRequest) ProtoMessage() {}
+
+func (x *StartRecordingSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRecordingSessionRequest.ProtoReflect.Descriptor instead.
+func (*StartRecordingSessionRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *StartRecordingSessionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StartRecordingSessionRequest) GetTracks() []*RecordingTrack {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+func (x *StartRecordingSessionRequest) GetSegmentSeconds() float64 {
+	if x != nil {
+		return x.SegmentSeconds
+	}
+	return 0
+}
+
+func (x *StartRecordingSessionRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type StartRecordingSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRecordingSessionResponse) Reset() {
+	*x = StartRecordingSessionResponse{}
+	mi := &file_audio_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRecordingSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRecordingSessionResponse) ProtoMessage() {}
+
+func (x *StartRecordingSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRecordingSessionResponse.ProtoReflect.Descriptor instead.
+func (*StartRecordingSessionResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *StartRecordingSessionResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type StopRecordingSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRecordingSessionRequest) Reset() {
+	*x = StopRecordingSessionRequest{}
+	mi := &file_audio_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRecordingSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRecordingSessionRequest) ProtoMessage() {}
+
+func (x *StopRecordingSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRecordingSessionRequest.ProtoReflect.Descriptor instead.
+func (*StopRecordingSessionRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *StopRecordingSessionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StopRecordingSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type StopRecordingSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *RecordingSession      `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRecordingSessionResponse) Reset() {
+	*x = StopRecordingSessionResponse{}
+	mi := &file_audio_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRecordingSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRecordingSessionResponse) ProtoMessage() {}
+
+func (x *StopRecordingSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRecordingSessionResponse.ProtoReflect.Descriptor instead.
+func (*StopRecordingSessionResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *StopRecordingSessionResponse) GetSession() *RecordingSession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+type GetRecordingSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordingSessionRequest) Reset() {
+	*x = GetRecordingSessionRequest{}
+	mi := &file_audio_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordingSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordingSessionRequest) ProtoMessage() {}
+
+func (x *GetRecordingSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordingSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordingSessionRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *GetRecordingSessionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetRecordingSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type GetRecordingSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *RecordingSession      `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordingSessionResponse) Reset() {
+	*x = GetRecordingSessionResponse{}
+	mi := &file_audio_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordingSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordingSessionResponse) ProtoMessage() {}
+
+func (x *GetRecordingSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordingSessionResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordingSessionResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *GetRecordingSessionResponse) GetSession() *RecordingSession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+type RecordingSession struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	StartTimestampNanoseconds int64                  `protobuf:"varint,1,opt,name=start_timestamp_nanoseconds,json=startTimestampNanoseconds,proto3" json:"start_timestamp_nanoseconds,omitempty"` // the origin of the tracks' offsets
+	Tracks                    []*TrackStatus         `protobuf:"bytes,2,rep,name=tracks,proto3" json:"tracks,omitempty"`                                                                           // in the order requested
+	Active                    bool                   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *RecordingSession) Reset() {
+	*x = RecordingSession{}
+	mi := &file_audio_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordingSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordingSession) ProtoMessage() {}
+
+func (x *RecordingSession) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordingSession.ProtoReflect.Descriptor instead.
+func (*RecordingSession) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *RecordingSession) GetStartTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.StartTimestampNanoseconds
+	}
+	return 0
+}
+
+func (x *RecordingSession) GetTracks() []*TrackStatus {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+func (x *RecordingSession) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+type TrackStatus struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Track             *RecordingTrack        `protobuf:"bytes,1,opt,name=track,proto3" json:"track,omitempty"`                                                   // with its resource and name filled in
+	Segments          []*RecordingSegment    `protobuf:"bytes,2,rep,name=segments,proto3" json:"segments,omitempty"`                                             // oldest first
+	OffsetNanoseconds int64                  `protobuf:"varint,3,opt,name=offset_nanoseconds,json=offsetNanoseconds,proto3" json:"offset_nanoseconds,omitempty"` // capture time of the track's first sample after the session start
+	Error             string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                                                   // why the track ended on its own, if it did
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *TrackStatus) Reset() {
+	*x = TrackStatus{}
+	mi := &file_audio_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackStatus) ProtoMessage() {}
+
+func (x *TrackStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackStatus.ProtoReflect.Descriptor instead.
+func (*TrackStatus) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *TrackStatus) GetTrack() *RecordingTrack {
+	if x != nil {
+		return x.Track
+	}
+	return nil
+}
+
+func (x *TrackStatus) GetSegments() []*RecordingSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *TrackStatus) GetOffsetNanoseconds() int64 {
+	if x != nil {
+		return x.OffsetNanoseconds
+	}
+	return 0
+}
+
+func (x *TrackStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// A window records from each time start matches to the next time stop
+// does. Both are five field cron expressions: minute, hour, day of month,
+// month and day of week.
+type RecordingWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Stop          string                 `protobuf:"bytes,2,opt,name=stop,proto3" json:"stop,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordingWindow) Reset() {
+	*x = RecordingWindow{}
+	mi := &file_audio_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordingWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordingWindow) ProtoMessage() {}
+
+func (x *RecordingWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordingWindow.ProtoReflect.Descriptor instead.
+func (*RecordingWindow) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *RecordingWindow) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *RecordingWindow) GetStop() string {
+	if x != nil {
+		return x.Stop
+	}
+	return ""
+}
+
+// A schedule records into the server's recording store whenever one of
+// its windows is open, until it is cancelled.
+type ScheduleRecordingRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Windows        []*RecordingWindow     `protobuf:"bytes,2,rep,name=windows,proto3" json:"windows,omitempty"`
+	TimeZone       string                 `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`                     // IANA name the windows are in, the server's local time if empty
+	SegmentSeconds float64                `protobuf:"fixed64,4,opt,name=segment_seconds,json=segmentSeconds,proto3" json:"segment_seconds,omitempty"` // 60 if zero
+	Format         string                 `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`                                         // "wav" (the default) or "flac"
+	NormalizeLufs  float64                `protobuf:"fixed64,6,opt,name=normalize_lufs,json=normalizeLufs,proto3" json:"normalize_lufs,omitempty"`    // the loudness each segment is scaled to, if negative
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScheduleRecordingRequest) Reset() {
+	*x = ScheduleRecordingRequest{}
+	mi := &file_audio_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleRecordingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleRecordingRequest) ProtoMessage() {}
+
+func (x *ScheduleRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleRecordingRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *ScheduleRecordingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ScheduleRecordingRequest) GetWindows() []*RecordingWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+func (x *ScheduleRecordingRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *ScheduleRecordingRequest) GetSegmentSeconds() float64 {
+	if x != nil {
+		return x.SegmentSeconds
+	}
+	return 0
+}
+
+func (x *ScheduleRecordingRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ScheduleRecordingRequest) GetNormalizeLufs() float64 {
+	if x != nil {
+		return x.NormalizeLufs
+	}
+	return 0
+}
+
+type ScheduleRecordingResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId               string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	NextTimestampNanoseconds int64                  `protobuf:"varint,2,opt,name=next_timestamp_nanoseconds,json=nextTimestampNanoseconds,proto3" json:"next_timestamp_nanoseconds,omitempty"` // when recording next starts or stops, 0 if never
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ScheduleRecordingResponse) Reset() {
+	*x = ScheduleRecordingResponse{}
+	mi := &file_audio_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleRecordingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleRecordingResponse) ProtoMessage() {}
+
+func (x *ScheduleRecordingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleRecordingResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleRecordingResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *ScheduleRecordingResponse) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *ScheduleRecordingResponse) GetNextTimestampNanoseconds() int64 {
+	if x != nil {
+		return x.NextTimestampNanoseconds
+	}
+	return 0
+}
+
+type CancelScheduledRecordingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,2,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledRecordingRequest) Reset() {
+	*x = CancelScheduledRecordingRequest{}
+	mi := &file_audio_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledRecordingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledRecordingRequest) ProtoMessage() {}
+
+func (x *CancelScheduledRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledRecordingRequest.ProtoReflect.Descriptor instead.
+func (*CancelScheduledRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *CancelScheduledRecordingRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CancelScheduledRecordingRequest) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+type CancelScheduledRecordingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Segments      []*RecordingSegment    `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"` // written by the schedule, oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledRecordingResponse) Reset() {
+	*x = CancelScheduledRecordingResponse{}
+	mi := &file_audio_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAudioRequest) String() string {
+func (x *CancelScheduledRecordingResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAudioRequest) ProtoMessage() {}
+func (*CancelScheduledRecordingResponse) ProtoMessage() {}
 
-func (x *GetAudioRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_audio_proto_msgTypes[1]
+func (x *CancelScheduledRecordingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -119,79 +5919,181 @@ func (x *GetAudioRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAudioRequest.ProtoReflect.Descriptor instead.
-func (*GetAudioRequest) Descriptor() ([]byte, []int) {
-	return file_audio_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use CancelScheduledRecordingResponse.ProtoReflect.Descriptor instead.
+func (*CancelScheduledRecordingResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *GetAudioRequest) GetName() string {
+func (x *CancelScheduledRecordingResponse) GetSegments() []*RecordingSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+// Resources following their level with a trigger, such as level_trigger.
+type GetTriggerStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTriggerStateRequest) Reset() {
+	*x = GetTriggerStateRequest{}
+	mi := &file_audio_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTriggerStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTriggerStateRequest) ProtoMessage() {}
+
+func (x *GetTriggerStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTriggerStateRequest.ProtoReflect.Descriptor instead.
+func (*GetTriggerStateRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *GetTriggerStateRequest) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *GetAudioRequest) GetDurationSeconds() float32 {
+type GetTriggerStateResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Active           bool                   `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	LevelDbfs        float64                `protobuf:"fixed64,2,opt,name=level_dbfs,json=levelDbfs,proto3" json:"level_dbfs,omitempty"`                     // RMS level of the last 100ms of capture
+	SinceNanoseconds int64                  `protobuf:"varint,3,opt,name=since_nanoseconds,json=sinceNanoseconds,proto3" json:"since_nanoseconds,omitempty"` // when active last changed, 0 if it never has
+	Triggers         int32                  `protobuf:"varint,4,opt,name=triggers,proto3" json:"triggers,omitempty"`                                         // times the trigger has fired
+	ThresholdDbfs    float64                `protobuf:"fixed64,5,opt,name=threshold_dbfs,json=thresholdDbfs,proto3" json:"threshold_dbfs,omitempty"`
+	ReleaseDbfs      float64                `protobuf:"fixed64,6,opt,name=release_dbfs,json=releaseDbfs,proto3" json:"release_dbfs,omitempty"` // the level it releases below
+	Segments         []*RecordingSegment    `protobuf:"bytes,7,rep,name=segments,proto3" json:"segments,omitempty"`                            // recorded while active, oldest first
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetTriggerStateResponse) Reset() {
+	*x = GetTriggerStateResponse{}
+	mi := &file_audio_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTriggerStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTriggerStateResponse) ProtoMessage() {}
+
+func (x *GetTriggerStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[89]
 	if x != nil {
-		return x.DurationSeconds
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTriggerStateResponse.ProtoReflect.Descriptor instead.
+func (*GetTriggerStateResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *GetTriggerStateResponse) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *GetTriggerStateResponse) GetLevelDbfs() float64 {
+	if x != nil {
+		return x.LevelDbfs
 	}
 	return 0
 }
 
-func (x *GetAudioRequest) GetCodec() string {
+func (x *GetTriggerStateResponse) GetSinceNanoseconds() int64 {
 	if x != nil {
-		return x.Codec
+		return x.SinceNanoseconds
 	}
-	return ""
+	return 0
 }
 
-func (x *GetAudioRequest) GetRequestId() string {
+func (x *GetTriggerStateResponse) GetTriggers() int32 {
 	if x != nil {
-		return x.RequestId
+		return x.Triggers
 	}
-	return ""
+	return 0
 }
 
-func (x *GetAudioRequest) GetMaxDurationSeconds() float32 {
+func (x *GetTriggerStateResponse) GetThresholdDbfs() float64 {
 	if x != nil {
-		return x.MaxDurationSeconds
+		return x.ThresholdDbfs
 	}
 	return 0
 }
 
-func (x *GetAudioRequest) GetPreviousTimestamp() float32 {
+func (x *GetTriggerStateResponse) GetReleaseDbfs() float64 {
 	if x != nil {
-		return x.PreviousTimestamp
+		return x.ReleaseDbfs
 	}
 	return 0
 }
 
-type AudioChunk struct {
-	state                     protoimpl.MessageState `protogen:"open.v1"`
-	AudioData                 []byte                 `protobuf:"bytes,1,opt,name=audio_data,json=audioData,proto3" json:"audio_data,omitempty"`
-	Info                      *AudioInfo             `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
-	Sequence                  int32                  `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"` // Sequence number
-	StartTimestampNanoseconds int64                  `protobuf:"varint,4,opt,name=start_timestamp_nanoseconds,json=startTimestampNanoseconds,proto3" json:"start_timestamp_nanoseconds,omitempty"`
-	EndTimestampNanoseconds   int64                  `protobuf:"varint,5,opt,name=end_timestamp_nanoseconds,json=endTimestampNanoseconds,proto3" json:"end_timestamp_nanoseconds,omitempty"`
-	unknownFields             protoimpl.UnknownFields
-	sizeCache                 protoimpl.SizeCache
+func (x *GetTriggerStateResponse) GetSegments() []*RecordingSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
 }
 
-func (x *AudioChunk) Reset() {
-	*x = AudioChunk{}
-	mi := &file_audio_proto_msgTypes[2]
+type ListDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // defaults to 100, at most 1000
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // empty for the first
+	Direction     string                 `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"`                  // "capture" or "playback"
+	Contains      string                 `protobuf:"bytes,5,opt,name=contains,proto3" json:"contains,omitempty"`                    // in the id or name, ignoring case
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	mi := &file_audio_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AudioChunk) String() string {
+func (x *ListDevicesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AudioChunk) ProtoMessage() {}
+func (*ListDevicesRequest) ProtoMessage() {}
 
-func (x *AudioChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_audio_proto_msgTypes[2]
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -202,70 +6104,75 @@ func (x *AudioChunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AudioChunk.ProtoReflect.Descriptor instead.
-func (*AudioChunk) Descriptor() ([]byte, []int) {
-	return file_audio_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{90}
 }
 
-func (x *AudioChunk) GetAudioData() []byte {
+func (x *ListDevicesRequest) GetName() string {
 	if x != nil {
-		return x.AudioData
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *AudioChunk) GetInfo() *AudioInfo {
+func (x *ListDevicesRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.Info
+		return x.PageSize
 	}
-	return nil
+	return 0
 }
 
-func (x *AudioChunk) GetSequence() int32 {
+func (x *ListDevicesRequest) GetPageToken() string {
 	if x != nil {
-		return x.Sequence
+		return x.PageToken
 	}
-	return 0
+	return ""
 }
 
-func (x *AudioChunk) GetStartTimestampNanoseconds() int64 {
+func (x *ListDevicesRequest) GetDirection() string {
 	if x != nil {
-		return x.StartTimestampNanoseconds
+		return x.Direction
 	}
-	return 0
+	return ""
 }
 
-func (x *AudioChunk) GetEndTimestampNanoseconds() int64 {
+func (x *ListDevicesRequest) GetContains() string {
 	if x != nil {
-		return x.EndTimestampNanoseconds
+		return x.Contains
 	}
-	return 0
+	return ""
 }
 
-type PlayRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	AudioData     []byte                 `protobuf:"bytes,2,opt,name=audio_data,json=audioData,proto3" json:"audio_data,omitempty"`
-	Info          *AudioInfo             `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// A device the resource's backend can open.
+type Device struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Driver          string                 `protobuf:"bytes,3,opt,name=driver,proto3" json:"driver,omitempty"`
+	Capture         bool                   `protobuf:"varint,4,opt,name=capture,proto3" json:"capture,omitempty"`
+	Playback        bool                   `protobuf:"varint,5,opt,name=playback,proto3" json:"playback,omitempty"`
+	DefaultCapture  bool                   `protobuf:"varint,6,opt,name=default_capture,json=defaultCapture,proto3" json:"default_capture,omitempty"`
+	DefaultPlayback bool                   `protobuf:"varint,7,opt,name=default_playback,json=defaultPlayback,proto3" json:"default_playback,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *PlayRequest) Reset() {
-	*x = PlayRequest{}
-	mi := &file_audio_proto_msgTypes[3]
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_audio_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PlayRequest) String() string {
+func (x *Device) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PlayRequest) ProtoMessage() {}
+func (*Device) ProtoMessage() {}
 
-func (x *PlayRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_audio_proto_msgTypes[3]
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -276,54 +6183,83 @@ func (x *PlayRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlayRequest.ProtoReflect.Descriptor instead.
-func (*PlayRequest) Descriptor() ([]byte, []int) {
-	return file_audio_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *PlayRequest) GetName() string {
+func (x *Device) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Device) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *PlayRequest) GetAudioData() []byte {
+func (x *Device) GetDriver() string {
 	if x != nil {
-		return x.AudioData
+		return x.Driver
 	}
-	return nil
+	return ""
 }
 
-func (x *PlayRequest) GetInfo() *AudioInfo {
+func (x *Device) GetCapture() bool {
 	if x != nil {
-		return x.Info
+		return x.Capture
 	}
-	return nil
+	return false
 }
 
-type PlayResponse struct {
+func (x *Device) GetPlayback() bool {
+	if x != nil {
+		return x.Playback
+	}
+	return false
+}
+
+func (x *Device) GetDefaultCapture() bool {
+	if x != nil {
+		return x.DefaultCapture
+	}
+	return false
+}
+
+func (x *Device) GetDefaultPlayback() bool {
+	if x != nil {
+		return x.DefaultPlayback
+	}
+	return false
+}
+
+type ListDevicesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`                                    // by id
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PlayResponse) Reset() {
-	*x = PlayResponse{}
-	mi := &file_audio_proto_msgTypes[4]
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_audio_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PlayResponse) String() string {
+func (x *ListDevicesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PlayResponse) ProtoMessage() {}
+func (*ListDevicesResponse) ProtoMessage() {}
 
-func (x *PlayResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_audio_proto_msgTypes[4]
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,14 +6270,21 @@ func (x *PlayResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlayResponse.ProtoReflect.Descriptor instead.
-func (*PlayResponse) Descriptor() ([]byte, []int) {
-	return file_audio_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *PlayResponse) GetName() string {
+func (x *ListDevicesResponse) GetDevices() []*Device {
 	if x != nil {
-		return x.Name
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *ListDevicesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
 	}
 	return ""
 }
@@ -355,7 +6298,7 @@ type PropertiesRequest struct {
 
 func (x *PropertiesRequest) Reset() {
 	*x = PropertiesRequest{}
-	mi := &file_audio_proto_msgTypes[5]
+	mi := &file_audio_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -367,7 +6310,7 @@ func (x *PropertiesRequest) String() string {
 func (*PropertiesRequest) ProtoMessage() {}
 
 func (x *PropertiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_audio_proto_msgTypes[5]
+	mi := &file_audio_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -380,7 +6323,7 @@ func (x *PropertiesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PropertiesRequest.ProtoReflect.Descriptor instead.
 func (*PropertiesRequest) Descriptor() ([]byte, []int) {
-	return file_audio_proto_rawDescGZIP(), []int{5}
+	return file_audio_proto_rawDescGZIP(), []int{93}
 }
 
 func (x *PropertiesRequest) GetName() string {
@@ -401,7 +6344,7 @@ type PropertiesResponse struct {
 
 func (x *PropertiesResponse) Reset() {
 	*x = PropertiesResponse{}
-	mi := &file_audio_proto_msgTypes[6]
+	mi := &file_audio_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -413,7 +6356,7 @@ func (x *PropertiesResponse) String() string {
 func (*PropertiesResponse) ProtoMessage() {}
 
 func (x *PropertiesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_audio_proto_msgTypes[6]
+	mi := &file_audio_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -426,7 +6369,7 @@ func (x *PropertiesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PropertiesResponse.ProtoReflect.Descriptor instead.
 func (*PropertiesResponse) Descriptor() ([]byte, []int) {
-	return file_audio_proto_rawDescGZIP(), []int{6}
+	return file_audio_proto_rawDescGZIP(), []int{94}
 }
 
 func (x *PropertiesResponse) GetSupportedCodecs() []string {
@@ -459,15 +6402,47 @@ const file_audio_proto_rawDesc = "" +
 	"\x05codec\x18\x01 \x01(\tR\x05codec\x12\x1f\n" +
 	"\vsample_rate\x18\x02 \x01(\x05R\n" +
 	"sampleRate\x12!\n" +
-	"\fnum_channels\x18\x03 \x01(\x05R\vnumChannels\"\xe6\x01\n" +
+	"\fnum_channels\x18\x03 \x01(\x05R\vnumChannels\"\xf2\t\n" +
 	"\x0fGetAudioRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12)\n" +
 	"\x10duration_seconds\x18\x02 \x01(\x02R\x0fdurationSeconds\x12\x14\n" +
 	"\x05codec\x18\x03 \x01(\tR\x05codec\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x04 \x01(\tR\trequestId\x120\n" +
-	"\x14max_duration_seconds\x18\x05 \x01(\x02R\x12maxDurationSeconds\x12-\n" +
-	"\x12previous_timestamp\x18\x06 \x01(\x02R\x11previousTimestamp\"\xe3\x01\n" +
+	"\x14max_duration_seconds\x18\x05 \x01(\x02R\x12maxDurationSeconds\x12\x1f\n" +
+	"\vsample_rate\x18\a \x01(\x05R\n" +
+	"sampleRate\x12!\n" +
+	"\fnum_channels\x18\b \x01(\x05R\vnumChannels\x12\x1b\n" +
+	"\tonly_when\x18\t \x03(\tR\bonlyWhen\x12(\n" +
+	"\x10pre_roll_seconds\x18\n" +
+	" \x01(\x02R\x0epreRollSeconds\x12*\n" +
+	"\x11post_roll_seconds\x18\v \x01(\x02R\x0fpostRollSeconds\x12\x10\n" +
+	"\x03vad\x18\f \x01(\tR\x03vad\x12\x1f\n" +
+	"\vspeech_only\x18\r \x01(\bR\n" +
+	"speechOnly\x12!\n" +
+	"\ftrim_silence\x18\x0e \x01(\bR\vtrimSilence\x124\n" +
+	"\x16silence_threshold_dbfs\x18\x0f \x01(\x02R\x14silenceThresholdDbfs\x128\n" +
+	"\x18silence_hangover_seconds\x18\x10 \x01(\x02R\x16silenceHangoverSeconds\x12+\n" +
+	"\x11noise_suppression\x18\x11 \x01(\tR\x10noiseSuppression\x12\x10\n" +
+	"\x03agc\x18\x12 \x01(\bR\x03agc\x12&\n" +
+	"\x0fagc_target_dbfs\x18\x13 \x01(\x02R\ragcTargetDbfs\x12 \n" +
+	"\falso_save_as\x18\x14 \x01(\tR\n" +
+	"alsoSaveAs\x12\x16\n" +
+	"\x06strict\x18\x15 \x01(\bR\x06strict\x12\x1c\n" +
+	"\tresumable\x18\x16 \x01(\bR\tresumable\x12!\n" +
+	"\fresume_token\x18\x17 \x01(\tR\vresumeToken\x124\n" +
+	"\x16chunk_duration_seconds\x18\x18 \x01(\x02R\x14chunkDurationSeconds\x12\x1f\n" +
+	"\vdrop_policy\x18\x19 \x01(\tR\n" +
+	"dropPolicy\x12#\n" +
+	"\rbuffer_chunks\x18\x1a \x01(\x05R\fbufferChunks\x12 \n" +
+	"\vcompression\x18\x1b \x01(\tR\vcompression\x12!\n" +
+	"\fbatch_chunks\x18\x1c \x01(\x05R\vbatchChunks\x125\n" +
+	"\x17batch_max_delay_seconds\x18\x1d \x01(\x02R\x14batchMaxDelaySeconds\x12)\n" +
+	"\x10adaptive_bitrate\x18\x1e \x01(\bR\x0fadaptiveBitrate\x12(\n" +
+	"\x10min_bitrate_kbps\x18\x1f \x01(\x05R\x0eminBitrateKbps\x12(\n" +
+	"\x10max_bitrate_kbps\x18  \x01(\x05R\x0emaxBitrateKbps\x12+\n" +
+	"\x11heartbeat_seconds\x18! \x01(\x02R\x10heartbeatSeconds\x12\x1c\n" +
+	"\tchecksums\x18\" \x01(\bR\tchecksumsJ\x04\b\x06\x10\aR\x12previous_timestamp\"\x93\x05\n" +
 	"\n" +
 	"AudioChunk\x12\x1d\n" +
 	"\n" +
@@ -476,25 +6451,514 @@ const file_audio_proto_rawDesc = "" +
 	".AudioInfoR\x04info\x12\x1a\n" +
 	"\bsequence\x18\x03 \x01(\x05R\bsequence\x12>\n" +
 	"\x1bstart_timestamp_nanoseconds\x18\x04 \x01(\x03R\x19startTimestampNanoseconds\x12:\n" +
-	"\x19end_timestamp_nanoseconds\x18\x05 \x01(\x03R\x17endTimestampNanoseconds\"`\n" +
+	"\x19end_timestamp_nanoseconds\x18\x05 \x01(\x03R\x17endTimestampNanoseconds\x12'\n" +
+	"\x0fgap_nanoseconds\x18\x06 \x01(\x03R\x0egapNanoseconds\x12%\n" +
+	"\x06header\x18\a \x01(\v2\r.StreamHeaderR\x06header\x12\x1b\n" +
+	"\x06speech\x18\b \x01(\bH\x00R\x06speech\x88\x01\x01\x12%\n" +
+	"\btimecode\x18\t \x01(\v2\t.TimecodeR\btimecode\x12!\n" +
+	"\fresume_token\x18\n" +
+	" \x01(\tR\vresumeToken\x12%\n" +
+	"\x0edropped_chunks\x18\v \x01(\x03R\rdroppedChunks\x12!\n" +
+	"\x05batch\x18\f \x03(\v2\v.AudioChunkR\x05batch\x12<\n" +
+	"\x1asent_timestamp_nanoseconds\x18\r \x01(\x03R\x18sentTimestampNanoseconds\x12!\n" +
+	"\fbitrate_kbps\x18\x0e \x01(\x05R\vbitrateKbps\x12\x1c\n" +
+	"\theartbeat\x18\x0f \x01(\bR\theartbeat\x12\x19\n" +
+	"\x05crc32\x18\x10 \x01(\aH\x01R\x05crc32\x88\x01\x01B\t\n" +
+	"\a_speechB\b\n" +
+	"\x06_crc32\"o\n" +
+	"\fStreamHeader\x12\x1e\n" +
+	"\x04info\x18\x01 \x01(\v2\n" +
+	".AudioInfoR\x04info\x12\x1c\n" +
+	"\textradata\x18\x02 \x01(\fR\textradata\x12!\n" +
+	"\fchunk_frames\x18\x03 \x01(\x05R\vchunkFrames\"\xf6\x01\n" +
+	"\bTimecode\x12\x14\n" +
+	"\x05hours\x18\x01 \x01(\x05R\x05hours\x12\x18\n" +
+	"\aminutes\x18\x02 \x01(\x05R\aminutes\x12\x18\n" +
+	"\aseconds\x18\x03 \x01(\x05R\aseconds\x12\x16\n" +
+	"\x06frames\x18\x04 \x01(\x05R\x06frames\x12\x1d\n" +
+	"\n" +
+	"drop_frame\x18\x05 \x01(\bR\tdropFrame\x12\x1d\n" +
+	"\n" +
+	"frame_rate\x18\x06 \x01(\x05R\tframeRate\x12\x1b\n" +
+	"\tuser_bits\x18\a \x01(\rR\buserBits\x12-\n" +
+	"\x12offset_nanoseconds\x18\b \x01(\x03R\x11offsetNanoseconds\"\xb9\x01\n" +
 	"\vPlayRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
 	"\n" +
 	"audio_data\x18\x02 \x01(\fR\taudioData\x12\x1e\n" +
 	"\x04info\x18\x03 \x01(\v2\n" +
-	".AudioInfoR\x04info\"\"\n" +
+	".AudioInfoR\x04info\x12%\n" +
+	"\x0enormalize_lufs\x18\x04 \x01(\x02R\rnormalizeLufs\x12\x16\n" +
+	"\x06strict\x18\x05 \x01(\bR\x06strict\x12\x18\n" +
+	"\aconvert\x18\x06 \x01(\bR\aconvert\"\"\n" +
 	"\fPlayResponse\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"'\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"G\n" +
+	"\x12PauseStreamRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\"\x15\n" +
+	"\x13PauseStreamResponse\"H\n" +
+	"\x13ResumeStreamRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\"\x16\n" +
+	"\x14ResumeStreamResponse\"k\n" +
+	"\x16PreparePlaybackRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"audio_data\x18\x02 \x01(\fR\taudioData\x12\x1e\n" +
+	"\x04info\x18\x03 \x01(\v2\n" +
+	".AudioInfoR\x04info\"1\n" +
+	"\x17PreparePlaybackResponse\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\tR\x06handle\"y\n" +
+	"\x15CommitPlaybackRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06handle\x18\x02 \x01(\tR\x06handle\x124\n" +
+	"\x16start_time_nanoseconds\x18\x03 \x01(\x03R\x14startTimeNanoseconds\"\x18\n" +
+	"\x16CommitPlaybackResponse\"D\n" +
+	"\x16ReleasePlaybackRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06handle\x18\x02 \x01(\tR\x06handle\"\x19\n" +
+	"\x17ReleasePlaybackResponse\"A\n" +
+	"\x11SetProfileRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aprofile\x18\x02 \x01(\tR\aprofile\"\x14\n" +
+	"\x12SetProfileResponse\"'\n" +
+	"\x11GetProfileRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"h\n" +
+	"\x12GetProfileResponse\x12\x18\n" +
+	"\aprofile\x18\x01 \x01(\tR\aprofile\x12\x1a\n" +
+	"\bprofiles\x18\x02 \x03(\tR\bprofiles\x12\x1c\n" +
+	"\tautomatic\x18\x03 \x01(\bR\tautomatic\"f\n" +
+	"\x06EQBand\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12!\n" +
+	"\ffrequency_hz\x18\x02 \x01(\x02R\vfrequencyHz\x12\x17\n" +
+	"\again_db\x18\x03 \x01(\x02R\x06gainDb\x12\f\n" +
+	"\x01q\x18\x04 \x01(\x02R\x01q\"A\n" +
+	"\fSetEQRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\x05bands\x18\x02 \x03(\v2\a.EQBandR\x05bands\"\x0f\n" +
+	"\rSetEQResponse\"\"\n" +
+	"\fGetEQRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\".\n" +
+	"\rGetEQResponse\x12\x1d\n" +
+	"\x05bands\x18\x01 \x03(\v2\a.EQBandR\x05bands\"M\n" +
+	"\x10GetLevelsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
+	"\x0ewindow_seconds\x18\x02 \x01(\x02R\rwindowSeconds\"l\n" +
+	"\fChannelLevel\x12\x10\n" +
+	"\x03rms\x18\x01 \x01(\x02R\x03rms\x12\x12\n" +
+	"\x04peak\x18\x02 \x01(\x02R\x04peak\x12\x19\n" +
+	"\brms_dbfs\x18\x03 \x01(\x02R\armsDbfs\x12\x1b\n" +
+	"\tpeak_dbfs\x18\x04 \x01(\x02R\bpeakDbfs\"s\n" +
+	"\x11GetLevelsResponse\x12)\n" +
+	"\bchannels\x18\x01 \x03(\v2\r.ChannelLevelR\bchannels\x123\n" +
+	"\x15timestamp_nanoseconds\x18\x02 \x01(\x03R\x14timestampNanoseconds\"a\n" +
+	"\x15StreamSpectrumRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\bfft_size\x18\x02 \x01(\x05R\afftSize\x12\x19\n" +
+	"\bhop_size\x18\x03 \x01(\x05R\ahopSize\"{\n" +
+	"\rSpectrumFrame\x12\x1e\n" +
+	"\n" +
+	"magnitudes\x18\x01 \x03(\x02R\n" +
+	"magnitudes\x12\x15\n" +
+	"\x06bin_hz\x18\x02 \x01(\x02R\x05binHz\x123\n" +
+	"\x15timestamp_nanoseconds\x18\x03 \x01(\x03R\x14timestampNanoseconds\"\xd2\x01\n" +
+	"\x15GetSpectrogramRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aseconds\x18\x02 \x01(\x01R\aseconds\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\x05R\x06height\x12\x19\n" +
+	"\bfft_size\x18\x05 \x01(\x05R\afftSize\x12\x1d\n" +
+	"\n" +
+	"floor_dbfs\x18\x06 \x01(\x01R\tfloorDbfs\x12#\n" +
+	"\rlog_frequency\x18\a \x01(\bR\flogFrequency\"\xbe\x01\n" +
+	"\x16GetSpectrogramResponse\x12\x10\n" +
+	"\x03png\x18\x01 \x01(\fR\x03png\x12>\n" +
+	"\x1bstart_timestamp_nanoseconds\x18\x02 \x01(\x03R\x19startTimestampNanoseconds\x121\n" +
+	"\x14duration_nanoseconds\x18\x03 \x01(\x03R\x13durationNanoseconds\x12\x1f\n" +
+	"\vsample_rate\x18\x04 \x01(\x05R\n" +
+	"sampleRate\"\x94\x01\n" +
+	"\x12CaptureClipRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12(\n" +
+	"\x10pre_roll_seconds\x18\x02 \x01(\x01R\x0epreRollSeconds\x12*\n" +
+	"\x11post_roll_seconds\x18\x03 \x01(\x01R\x0fpostRollSeconds\x12\x14\n" +
+	"\x05codec\x18\x04 \x01(\tR\x05codec\"\xd8\x01\n" +
+	"\x13CaptureClipResponse\x12\x1d\n" +
+	"\n" +
+	"audio_data\x18\x01 \x01(\fR\taudioData\x12\x1e\n" +
+	"\x04info\x18\x02 \x01(\v2\n" +
+	".AudioInfoR\x04info\x12>\n" +
+	"\x1bstart_timestamp_nanoseconds\x18\x03 \x01(\x03R\x19startTimestampNanoseconds\x12B\n" +
+	"\x1dtrigger_timestamp_nanoseconds\x18\x04 \x01(\x03R\x1btriggerTimestampNanoseconds\"\xb9\x01\n" +
+	"\x15StreamImpulsesRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
+	"\arise_db\x18\x02 \x01(\x02R\x06riseDb\x12\"\n" +
+	"\rmin_peak_dbfs\x18\x03 \x01(\x02R\vminPeakDbfs\x120\n" +
+	"\x14max_duration_seconds\x18\x04 \x01(\x02R\x12maxDurationSeconds\x12\x1d\n" +
+	"\n" +
+	"save_clips\x18\x05 \x01(\bR\tsaveClips\"\xfd\x01\n" +
+	"\fImpulseEvent\x123\n" +
+	"\x15timestamp_nanoseconds\x18\x01 \x01(\x03R\x14timestampNanoseconds\x12)\n" +
+	"\x10duration_seconds\x18\x02 \x01(\x02R\x0fdurationSeconds\x12\x1b\n" +
+	"\tpeak_dbfs\x18\x03 \x01(\x02R\bpeakDbfs\x12\x17\n" +
+	"\arise_db\x18\x04 \x01(\x02R\x06riseDb\x12'\n" +
+	"\x0fbackground_dbfs\x18\x05 \x01(\x02R\x0ebackgroundDbfs\x12\x1a\n" +
+	"\bkurtosis\x18\x06 \x01(\x02R\bkurtosis\x12\x12\n" +
+	"\x04clip\x18\a \x01(\tR\x04clip\"\x98\x01\n" +
+	"\x14GetLevelStatsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\tR\x06period\x12+\n" +
+	"\x11start_nanoseconds\x18\x03 \x01(\x03R\x10startNanoseconds\x12'\n" +
+	"\x0fend_nanoseconds\x18\x04 \x01(\x03R\x0eendNanoseconds\"\x8a\x02\n" +
+	"\x10LevelStatsBucket\x12+\n" +
+	"\x11start_nanoseconds\x18\x01 \x01(\x03R\x10startNanoseconds\x12'\n" +
+	"\x0fcovered_seconds\x18\x02 \x01(\x02R\x0ecoveredSeconds\x12\x19\n" +
+	"\bleq_dbfs\x18\x03 \x01(\x02R\aleqDbfs\x12\x19\n" +
+	"\bl10_dbfs\x18\x04 \x01(\x02R\al10Dbfs\x12\x19\n" +
+	"\bl50_dbfs\x18\x05 \x01(\x02R\al50Dbfs\x12\x19\n" +
+	"\bl90_dbfs\x18\x06 \x01(\x02R\al90Dbfs\x12\x19\n" +
+	"\bmax_dbfs\x18\a \x01(\x02R\amaxDbfs\x12\x19\n" +
+	"\bmin_dbfs\x18\b \x01(\x02R\aminDbfs\"D\n" +
+	"\x15GetLevelStatsResponse\x12+\n" +
+	"\abuckets\x18\x01 \x03(\v2\x11.LevelStatsBucketR\abuckets\"\xab\x02\n" +
+	"\x12ListHistoryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x1c\n" +
+	"\tdirection\x18\x04 \x01(\tR\tdirection\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\x12\x18\n" +
+	"\asubject\x18\x06 \x01(\tR\asubject\x12\x12\n" +
+	"\x04kind\x18\a \x01(\tR\x04kind\x12+\n" +
+	"\x11after_nanoseconds\x18\b \x01(\x03R\x10afterNanoseconds\x12-\n" +
+	"\x12before_nanoseconds\x18\t \x01(\x03R\x11beforeNanoseconds\"\xf2\x02\n" +
+	"\fStreamRecord\x12\x1c\n" +
+	"\tdirection\x18\x01 \x01(\tR\tdirection\x12\x14\n" +
+	"\x05codec\x18\x02 \x01(\tR\x05codec\x12\x18\n" +
+	"\aprofile\x18\x03 \x01(\tR\aprofile\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tR\trequestId\x12\x18\n" +
+	"\asubject\x18\x05 \x01(\tR\asubject\x12+\n" +
+	"\x11start_nanoseconds\x18\x06 \x01(\x03R\x10startNanoseconds\x12'\n" +
+	"\x0fend_nanoseconds\x18\a \x01(\x03R\x0eendNanoseconds\x12\x14\n" +
+	"\x05bytes\x18\b \x01(\x03R\x05bytes\x12\x1a\n" +
+	"\bmessages\x18\t \x01(\x03R\bmessages\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12\x16\n" +
+	"\x06paused\x18\v \x01(\bR\x06paused\x12%\n" +
+	"\x0edropped_chunks\x18\f \x01(\x03R\rdroppedChunks\"l\n" +
+	"\x19ListStreamHistoryResponse\x12'\n" +
+	"\arecords\x18\x01 \x03(\v2\r.StreamRecordR\arecords\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xb2\x01\n" +
+	"\vEventRecord\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x123\n" +
+	"\x15timestamp_nanoseconds\x18\x02 \x01(\x03R\x14timestampNanoseconds\x12)\n" +
+	"\x10duration_seconds\x18\x03 \x01(\x02R\x0fdurationSeconds\x12\x1b\n" +
+	"\tpeak_dbfs\x18\x04 \x01(\x02R\bpeakDbfs\x12\x12\n" +
+	"\x04clip\x18\x05 \x01(\tR\x04clip\"b\n" +
+	"\x12ListEventsResponse\x12$\n" +
+	"\x06events\x18\x01 \x03(\v2\f.EventRecordR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xf3\x01\n" +
+	"\x13ListAuditLogRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x18\n" +
+	"\asubject\x18\x05 \x01(\tR\asubject\x12+\n" +
+	"\x11after_nanoseconds\x18\x06 \x01(\x03R\x10afterNanoseconds\x12-\n" +
+	"\x12before_nanoseconds\x18\a \x01(\x03R\x11beforeNanoseconds\"\xf1\x01\n" +
+	"\vAuditRecord\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12)\n" +
+	"\x10time_nanoseconds\x18\x02 \x01(\x03R\x0ftimeNanoseconds\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x12\n" +
+	"\x04peer\x18\x05 \x01(\tR\x04peer\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\x12\x16\n" +
+	"\x06detail\x18\a \x01(\tR\x06detail\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x12\n" +
+	"\x04hash\x18\t \x01(\tR\x04hash\"f\n" +
+	"\x14ListAuditLogResponse\x12&\n" +
+	"\arecords\x18\x01 \x03(\v2\f.AuditRecordR\arecords\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x9d\x02\n" +
+	"\x18ListActiveStreamsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x1c\n" +
+	"\tdirection\x18\x04 \x01(\tR\tdirection\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\x12\x18\n" +
+	"\asubject\x18\x06 \x01(\tR\asubject\x12+\n" +
+	"\x11after_nanoseconds\x18\a \x01(\x03R\x10afterNanoseconds\x12-\n" +
+	"\x12before_nanoseconds\x18\b \x01(\x03R\x11beforeNanoseconds\"l\n" +
+	"\x19ListActiveStreamsResponse\x12'\n" +
+	"\astreams\x18\x01 \x03(\v2\r.StreamRecordR\astreams\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xc9\x03\n" +
+	"\x15ListRecordingsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06prefix\x18\x04 \x01(\tR\x06prefix\x12\x16\n" +
+	"\x06format\x18\x05 \x01(\tR\x06format\x12+\n" +
+	"\x11after_nanoseconds\x18\x06 \x01(\x03R\x10afterNanoseconds\x12-\n" +
+	"\x12before_nanoseconds\x18\a \x01(\x03R\x11beforeNanoseconds\x12\x14\n" +
+	"\x05codec\x18\b \x01(\tR\x05codec\x128\n" +
+	"\x18min_duration_nanoseconds\x18\t \x01(\x03R\x16minDurationNanoseconds\x128\n" +
+	"\x18max_duration_nanoseconds\x18\n" +
+	" \x01(\x03R\x16maxDurationNanoseconds\x12$\n" +
+	"\x0emin_size_bytes\x18\v \x01(\x03R\fminSizeBytes\x12$\n" +
+	"\x0emax_size_bytes\x18\f \x01(\x03R\fmaxSizeBytes\"\xac\x02\n" +
+	"\x0fStoredRecording\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x03 \x01(\x03R\tsizeBytes\x121\n" +
+	"\x14modified_nanoseconds\x18\x04 \x01(\x03R\x13modifiedNanoseconds\x12\x0e\n" +
+	"\x02id\x18\x05 \x01(\tR\x02id\x12\x14\n" +
+	"\x05codec\x18\x06 \x01(\tR\x05codec\x12\x1f\n" +
+	"\vsample_rate\x18\a \x01(\x05R\n" +
+	"sampleRate\x12!\n" +
+	"\fnum_channels\x18\b \x01(\x05R\vnumChannels\x121\n" +
+	"\x14duration_nanoseconds\x18\t \x01(\x03R\x13durationNanoseconds\"9\n" +
+	"\x13GetRecordingRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"F\n" +
+	"\x14GetRecordingResponse\x12.\n" +
+	"\trecording\x18\x01 \x01(\v2\x10.StoredRecordingR\trecording\"w\n" +
+	"\x16StreamRecordingRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05codec\x18\x03 \x01(\tR\x05codec\x12#\n" +
+	"\rstart_seconds\x18\x04 \x01(\x01R\fstartSeconds\"r\n" +
+	"\x16ListRecordingsResponse\x120\n" +
+	"\n" +
+	"recordings\x18\x01 \x03(\v2\x10.StoredRecordingR\n" +
+	"recordings\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\",\n" +
+	"\x16GetLatencyStatsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xb5\x01\n" +
+	"\x17GetLatencyStatsResponse\x12\x16\n" +
+	"\x06chunks\x18\x01 \x01(\x03R\x06chunks\x12\x1f\n" +
+	"\vp50_seconds\x18\x02 \x01(\x01R\n" +
+	"p50Seconds\x12\x1f\n" +
+	"\vp95_seconds\x18\x03 \x01(\x01R\n" +
+	"p95Seconds\x12\x1f\n" +
+	"\vp99_seconds\x18\x04 \x01(\x01R\n" +
+	"p99Seconds\x12\x1f\n" +
+	"\vmax_seconds\x18\x05 \x01(\x01R\n" +
+	"maxSeconds\"%\n" +
+	"\x0fGetStatsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xb5\x03\n" +
+	"\x10GetStatsResponse\x12%\n" +
+	"\x0euptime_seconds\x18\x01 \x01(\x01R\ruptimeSeconds\x12%\n" +
+	"\x0eactive_streams\x18\x02 \x01(\x05R\ractiveStreams\x12!\n" +
+	"\factive_plays\x18\x03 \x01(\x05R\vactivePlays\x12'\n" +
+	"\x0factive_sessions\x18\x04 \x01(\x05R\x0eactiveSessions\x12%\n" +
+	"\x0ebytes_captured\x18\x05 \x01(\x03R\rbytesCaptured\x12!\n" +
+	"\fbytes_played\x18\x06 \x01(\x03R\vbytesPlayed\x12%\n" +
+	"\x0edropped_chunks\x18\a \x01(\x03R\rdroppedChunks\x12\x1a\n" +
+	"\boverruns\x18\b \x01(\x03R\boverruns\x12\x1c\n" +
+	"\tunderruns\x18\t \x01(\x03R\tunderruns\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\x12=\n" +
+	"\x1blast_error_time_nanoseconds\x18\v \x01(\x03R\x18lastErrorTimeNanoseconds\"&\n" +
+	"\x10GetHealthRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x92\x01\n" +
+	"\fDeviceHealth\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12+\n" +
+	"\x11since_nanoseconds\x18\x03 \x01(\x03R\x10sinceNanoseconds\x12'\n" +
+	"\x0freopen_attempts\x18\x04 \x01(\x05R\x0ereopenAttempts\"}\n" +
+	"\x11GetHealthResponse\x12\x14\n" +
+	"\x05ready\x18\x01 \x01(\bR\x05ready\x12'\n" +
+	"\acapture\x18\x02 \x01(\v2\r.DeviceHealthR\acapture\x12)\n" +
+	"\bplayback\x18\x03 \x01(\v2\r.DeviceHealthR\bplayback\"I\n" +
+	"\x10DoCommandRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\fcommand_json\x18\x02 \x01(\tR\vcommandJson\"4\n" +
+	"\x11DoCommandResponse\x12\x1f\n" +
+	"\vresult_json\x18\x01 \x01(\tR\n" +
+	"resultJson\".\n" +
+	"\x18GetRecordingStatsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x9f\x03\n" +
+	"\x19GetRecordingStatsResponse\x12\x1e\n" +
+	"\n" +
+	"recordings\x18\x01 \x01(\x05R\n" +
+	"recordings\x12\x1f\n" +
+	"\vtotal_bytes\x18\x02 \x01(\x03R\n" +
+	"totalBytes\x12-\n" +
+	"\x12oldest_nanoseconds\x18\x03 \x01(\x03R\x11oldestNanoseconds\x12-\n" +
+	"\x12newest_nanoseconds\x18\x04 \x01(\x03R\x11newestNanoseconds\x12&\n" +
+	"\x0fdisk_free_bytes\x18\x05 \x01(\x03R\rdiskFreeBytes\x12&\n" +
+	"\x0fdisk_size_bytes\x18\x06 \x01(\x03R\rdiskSizeBytes\x12&\n" +
+	"\x0fmax_age_seconds\x18\a \x01(\x01R\rmaxAgeSeconds\x12\x1b\n" +
+	"\tmax_bytes\x18\b \x01(\x03R\bmaxBytes\x12+\n" +
+	"\x11pruned_recordings\x18\t \x01(\x05R\x10prunedRecordings\x12!\n" +
+	"\fpruned_bytes\x18\n" +
+	" \x01(\x03R\vprunedBytes\"\x93\x01\n" +
+	"\x15StartRecordingRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x0fsegment_seconds\x18\x02 \x01(\x01R\x0esegmentSeconds\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12%\n" +
+	"\x0enormalize_lufs\x18\x04 \x01(\x01R\rnormalizeLufs\";\n" +
+	"\x16StartRecordingResponse\x12!\n" +
+	"\frecording_id\x18\x01 \x01(\tR\vrecordingId\"M\n" +
+	"\x14StopRecordingRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\frecording_id\x18\x02 \x01(\tR\vrecordingId\"F\n" +
+	"\x15StopRecordingResponse\x12-\n" +
+	"\bsegments\x18\x01 \x03(\v2\x11.RecordingSegmentR\bsegments\"L\n" +
+	"\x13ListSegmentsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\frecording_id\x18\x02 \x01(\tR\vrecordingId\"\xb5\x01\n" +
+	"\x10RecordingSegment\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12>\n" +
+	"\x1bstart_timestamp_nanoseconds\x18\x02 \x01(\x03R\x19startTimestampNanoseconds\x121\n" +
+	"\x14duration_nanoseconds\x18\x03 \x01(\x03R\x13durationNanoseconds\x12\x1a\n" +
+	"\bcomplete\x18\x04 \x01(\bR\bcomplete\"s\n" +
+	"\x14ListSegmentsResponse\x12-\n" +
+	"\bsegments\x18\x01 \x03(\v2\x11.RecordingSegmentR\bsegments\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\\\n" +
+	"\x0eRecordingTrack\x12\x1a\n" +
+	"\bresource\x18\x01 \x01(\tR\bresource\x12\x1a\n" +
+	"\bchannels\x18\x02 \x03(\x05R\bchannels\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"\x9c\x01\n" +
+	"\x1cStartRecordingSessionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x06tracks\x18\x02 \x03(\v2\x0f.RecordingTrackR\x06tracks\x12'\n" +
+	"\x0fsegment_seconds\x18\x03 \x01(\x01R\x0esegmentSeconds\x12\x16\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\">\n" +
+	"\x1dStartRecordingSessionResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"P\n" +
+	"\x1bStopRecordingSessionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"K\n" +
+	"\x1cStopRecordingSessionResponse\x12+\n" +
+	"\asession\x18\x01 \x01(\v2\x11.RecordingSessionR\asession\"O\n" +
+	"\x1aGetRecordingSessionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"J\n" +
+	"\x1bGetRecordingSessionResponse\x12+\n" +
+	"\asession\x18\x01 \x01(\v2\x11.RecordingSessionR\asession\"\x90\x01\n" +
+	"\x10RecordingSession\x12>\n" +
+	"\x1bstart_timestamp_nanoseconds\x18\x01 \x01(\x03R\x19startTimestampNanoseconds\x12$\n" +
+	"\x06tracks\x18\x02 \x03(\v2\f.TrackStatusR\x06tracks\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\"\xa8\x01\n" +
+	"\vTrackStatus\x12%\n" +
+	"\x05track\x18\x01 \x01(\v2\x0f.RecordingTrackR\x05track\x12-\n" +
+	"\bsegments\x18\x02 \x03(\v2\x11.RecordingSegmentR\bsegments\x12-\n" +
+	"\x12offset_nanoseconds\x18\x03 \x01(\x03R\x11offsetNanoseconds\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\";\n" +
+	"\x0fRecordingWindow\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x12\n" +
+	"\x04stop\x18\x02 \x01(\tR\x04stop\"\xdf\x01\n" +
+	"\x18ScheduleRecordingRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12*\n" +
+	"\awindows\x18\x02 \x03(\v2\x10.RecordingWindowR\awindows\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\x12'\n" +
+	"\x0fsegment_seconds\x18\x04 \x01(\x01R\x0esegmentSeconds\x12\x16\n" +
+	"\x06format\x18\x05 \x01(\tR\x06format\x12%\n" +
+	"\x0enormalize_lufs\x18\x06 \x01(\x01R\rnormalizeLufs\"z\n" +
+	"\x19ScheduleRecordingResponse\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\x12<\n" +
+	"\x1anext_timestamp_nanoseconds\x18\x02 \x01(\x03R\x18nextTimestampNanoseconds\"V\n" +
+	"\x1fCancelScheduledRecordingRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vschedule_id\x18\x02 \x01(\tR\n" +
+	"scheduleId\"Q\n" +
+	" CancelScheduledRecordingResponse\x12-\n" +
+	"\bsegments\x18\x01 \x03(\v2\x11.RecordingSegmentR\bsegments\",\n" +
+	"\x16GetTriggerStateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x92\x02\n" +
+	"\x17GetTriggerStateResponse\x12\x16\n" +
+	"\x06active\x18\x01 \x01(\bR\x06active\x12\x1d\n" +
+	"\n" +
+	"level_dbfs\x18\x02 \x01(\x01R\tlevelDbfs\x12+\n" +
+	"\x11since_nanoseconds\x18\x03 \x01(\x03R\x10sinceNanoseconds\x12\x1a\n" +
+	"\btriggers\x18\x04 \x01(\x05R\btriggers\x12%\n" +
+	"\x0ethreshold_dbfs\x18\x05 \x01(\x01R\rthresholdDbfs\x12!\n" +
+	"\frelease_dbfs\x18\x06 \x01(\x01R\vreleaseDbfs\x12-\n" +
+	"\bsegments\x18\a \x03(\v2\x11.RecordingSegmentR\bsegments\"\x9e\x01\n" +
+	"\x12ListDevicesRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x1c\n" +
+	"\tdirection\x18\x04 \x01(\tR\tdirection\x12\x1a\n" +
+	"\bcontains\x18\x05 \x01(\tR\bcontains\"\xce\x01\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06driver\x18\x03 \x01(\tR\x06driver\x12\x18\n" +
+	"\acapture\x18\x04 \x01(\bR\acapture\x12\x1a\n" +
+	"\bplayback\x18\x05 \x01(\bR\bplayback\x12'\n" +
+	"\x0fdefault_capture\x18\x06 \x01(\bR\x0edefaultCapture\x12)\n" +
+	"\x10default_playback\x18\a \x01(\bR\x0fdefaultPlayback\"`\n" +
+	"\x13ListDevicesResponse\x12!\n" +
+	"\adevices\x18\x01 \x03(\v2\a.DeviceR\adevices\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"'\n" +
 	"\x11PropertiesRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\"\x83\x01\n" +
 	"\x12PropertiesResponse\x12)\n" +
 	"\x10supported_codecs\x18\x01 \x03(\tR\x0fsupportedCodecs\x12\x1f\n" +
 	"\vsample_rate\x18\x02 \x03(\x05R\n" +
 	"sampleRate\x12!\n" +
-	"\fnum_channels\x18\x03 \x01(\x05R\vnumChannels2\xb7\x02\n" +
+	"\fnum_channels\x18\x03 \x01(\x05R\vnumChannels2\xbb'\n" +
 	"\fAudioService\x12a\n" +
 	"\bGetAudio\x12\x10.GetAudioRequest\x1a\v.AudioChunk\"4\x82\xd3\xe4\x93\x02.\",/olivia/api/v1/service/audio/{name}/GetAudio0\x01\x12U\n" +
-	"\x04Play\x12\f.PlayRequest\x1a\r.PlayResponse\"0\x82\xd3\xe4\x93\x02*\"(/olivia/api/v1/service/audio/{name}/play\x12m\n" +
+	"\x04Play\x12\f.PlayRequest\x1a\r.PlayResponse\"0\x82\xd3\xe4\x93\x02*\"(/olivia/api/v1/service/audio/{name}/play\x12r\n" +
+	"\vPauseStream\x12\x13.PauseStreamRequest\x1a\x14.PauseStreamResponse\"8\x82\xd3\xe4\x93\x022\"0/olivia/api/v1/service/audio/{name}/pause_stream\x12v\n" +
+	"\fResumeStream\x12\x14.ResumeStreamRequest\x1a\x15.ResumeStreamResponse\"9\x82\xd3\xe4\x93\x023\"1/olivia/api/v1/service/audio/{name}/resume_stream\x12\x82\x01\n" +
+	"\x0fPreparePlayback\x12\x17.PreparePlaybackRequest\x1a\x18.PreparePlaybackResponse\"<\x82\xd3\xe4\x93\x026\"4/olivia/api/v1/service/audio/{name}/prepare_playback\x12~\n" +
+	"\x0eCommitPlayback\x12\x16.CommitPlaybackRequest\x1a\x17.CommitPlaybackResponse\";\x82\xd3\xe4\x93\x025\"3/olivia/api/v1/service/audio/{name}/commit_playback\x12\x82\x01\n" +
+	"\x0fReleasePlayback\x12\x17.ReleasePlaybackRequest\x1a\x18.ReleasePlaybackResponse\"<\x82\xd3\xe4\x93\x026\"4/olivia/api/v1/service/audio/{name}/release_playback\x12n\n" +
+	"\n" +
+	"SetProfile\x12\x12.SetProfileRequest\x1a\x13.SetProfileResponse\"7\x82\xd3\xe4\x93\x021\"//olivia/api/v1/service/audio/{name}/set_profile\x12n\n" +
+	"\n" +
+	"GetProfile\x12\x12.GetProfileRequest\x1a\x13.GetProfileResponse\"7\x82\xd3\xe4\x93\x021\"//olivia/api/v1/service/audio/{name}/get_profile\x12Z\n" +
+	"\x05SetEQ\x12\r.SetEQRequest\x1a\x0e.SetEQResponse\"2\x82\xd3\xe4\x93\x02,\"*/olivia/api/v1/service/audio/{name}/set_eq\x12Z\n" +
+	"\x05GetEQ\x12\r.GetEQRequest\x1a\x0e.GetEQResponse\"2\x82\xd3\xe4\x93\x02,\"*/olivia/api/v1/service/audio/{name}/get_eq\x12j\n" +
+	"\tGetLevels\x12\x11.GetLevelsRequest\x1a\x12.GetLevelsResponse\"6\x82\xd3\xe4\x93\x020\"./olivia/api/v1/service/audio/{name}/get_levels\x12r\n" +
+	"\fStreamLevels\x12\x11.GetLevelsRequest\x1a\x12.GetLevelsResponse\"9\x82\xd3\xe4\x93\x023\"1/olivia/api/v1/service/audio/{name}/stream_levels0\x01\x12w\n" +
+	"\x0eStreamSpectrum\x12\x16.StreamSpectrumRequest\x1a\x0e.SpectrumFrame\";\x82\xd3\xe4\x93\x025\"3/olivia/api/v1/service/audio/{name}/stream_spectrum0\x01\x12z\n" +
+	"\x0eGetSpectrogram\x12\x16.GetSpectrogramRequest\x1a\x17.GetSpectrogramResponse\"7\x82\xd3\xe4\x93\x021\"//olivia/api/v1/service/audio/{name}/spectrogram\x12r\n" +
+	"\vCaptureClip\x12\x13.CaptureClipRequest\x1a\x14.CaptureClipResponse\"8\x82\xd3\xe4\x93\x022\"0/olivia/api/v1/service/audio/{name}/capture_clip\x12v\n" +
+	"\x0eStreamImpulses\x12\x16.StreamImpulsesRequest\x1a\r.ImpulseEvent\";\x82\xd3\xe4\x93\x025\"3/olivia/api/v1/service/audio/{name}/stream_impulses0\x01\x12{\n" +
+	"\rGetLevelStats\x12\x15.GetLevelStatsRequest\x1a\x16.GetLevelStatsResponse\";\x82\xd3\xe4\x93\x025\"3/olivia/api/v1/service/audio/{name}/get_level_stats\x12\x85\x01\n" +
+	"\x11ListStreamHistory\x12\x13.ListHistoryRequest\x1a\x1a.ListStreamHistoryResponse\"?\x82\xd3\xe4\x93\x029\"7/olivia/api/v1/service/audio/{name}/list_stream_history\x12o\n" +
+	"\n" +
+	"ListEvents\x12\x13.ListHistoryRequest\x1a\x13.ListEventsResponse\"7\x82\xd3\xe4\x93\x021\"//olivia/api/v1/service/audio/{name}/list_events\x12w\n" +
+	"\fListAuditLog\x12\x14.ListAuditLogRequest\x1a\x15.ListAuditLogResponse\":\x82\xd3\xe4\x93\x024\"2/olivia/api/v1/service/audio/{name}/list_audit_log\x12\x8b\x01\n" +
+	"\x11ListActiveStreams\x12\x19.ListActiveStreamsRequest\x1a\x1a.ListActiveStreamsResponse\"?\x82\xd3\xe4\x93\x029\"7/olivia/api/v1/service/audio/{name}/list_active_streams\x12~\n" +
+	"\x0eListRecordings\x12\x16.ListRecordingsRequest\x1a\x17.ListRecordingsResponse\";\x82\xd3\xe4\x93\x025\"3/olivia/api/v1/service/audio/{name}/list_recordings\x12\x8b\x01\n" +
+	"\x11GetRecordingStats\x12\x19.GetRecordingStatsRequest\x1a\x1a.GetRecordingStatsResponse\"?\x82\xd3\xe4\x93\x029\"7/olivia/api/v1/service/audio/{name}/get_recording_stats\x12v\n" +
+	"\fGetRecording\x12\x14.GetRecordingRequest\x1a\x15.GetRecordingResponse\"9\x82\xd3\xe4\x93\x023\"1/olivia/api/v1/service/audio/{name}/get_recording\x12w\n" +
+	"\x0fStreamRecording\x12\x17.StreamRecordingRequest\x1a\v.AudioChunk\"<\x82\xd3\xe4\x93\x026\"4/olivia/api/v1/service/audio/{name}/stream_recording0\x01\x12~\n" +
+	"\x0eStartRecording\x12\x16.StartRecordingRequest\x1a\x17.StartRecordingResponse\";\x82\xd3\xe4\x93\x025\"3/olivia/api/v1/service/audio/{name}/start_recording\x12z\n" +
+	"\rStopRecording\x12\x15.StopRecordingRequest\x1a\x16.StopRecordingResponse\":\x82\xd3\xe4\x93\x024\"2/olivia/api/v1/service/audio/{name}/stop_recording\x12v\n" +
+	"\fListSegments\x12\x14.ListSegmentsRequest\x1a\x15.ListSegmentsResponse\"9\x82\xd3\xe4\x93\x023\"1/olivia/api/v1/service/audio/{name}/list_segments\x12\x9b\x01\n" +
+	"\x15StartRecordingSession\x12\x1d.StartRecordingSessionRequest\x1a\x1e.StartRecordingSessionResponse\"C\x82\xd3\xe4\x93\x02=\";/olivia/api/v1/service/audio/{name}/start_recording_session\x12\x97\x01\n" +
+	"\x14StopRecordingSession\x12\x1c.StopRecordingSessionRequest\x1a\x1d.StopRecordingSessionResponse\"B\x82\xd3\xe4\x93\x02<\":/olivia/api/v1/service/audio/{name}/stop_recording_session\x12\x93\x01\n" +
+	"\x13GetRecordingSession\x12\x1b.GetRecordingSessionRequest\x1a\x1c.GetRecordingSessionResponse\"A\x82\xd3\xe4\x93\x02;\"9/olivia/api/v1/service/audio/{name}/get_recording_session\x12\x8a\x01\n" +
+	"\x11ScheduleRecording\x12\x19.ScheduleRecordingRequest\x1a\x1a.ScheduleRecordingResponse\">\x82\xd3\xe4\x93\x028\"6/olivia/api/v1/service/audio/{name}/schedule_recording\x12\xa7\x01\n" +
+	"\x18CancelScheduledRecording\x12 .CancelScheduledRecordingRequest\x1a!.CancelScheduledRecordingResponse\"F\x82\xd3\xe4\x93\x02@\">/olivia/api/v1/service/audio/{name}/cancel_scheduled_recording\x12\x83\x01\n" +
+	"\x0fGetTriggerState\x12\x17.GetTriggerStateRequest\x1a\x18.GetTriggerStateResponse\"=\x82\xd3\xe4\x93\x027\"5/olivia/api/v1/service/audio/{name}/get_trigger_state\x12r\n" +
+	"\vListDevices\x12\x13.ListDevicesRequest\x1a\x14.ListDevicesResponse\"8\x82\xd3\xe4\x93\x022\"0/olivia/api/v1/service/audio/{name}/list_devices\x12\x83\x01\n" +
+	"\x0fGetLatencyStats\x12\x17.GetLatencyStatsRequest\x1a\x18.GetLatencyStatsResponse\"=\x82\xd3\xe4\x93\x027\"5/olivia/api/v1/service/audio/{name}/get_latency_stats\x12f\n" +
+	"\bGetStats\x12\x10.GetStatsRequest\x1a\x11.GetStatsResponse\"5\x82\xd3\xe4\x93\x02/\"-/olivia/api/v1/service/audio/{name}/get_stats\x12j\n" +
+	"\tGetHealth\x12\x11.GetHealthRequest\x1a\x12.GetHealthResponse\"6\x82\xd3\xe4\x93\x020\"./olivia/api/v1/service/audio/{name}/get_health\x12j\n" +
+	"\tDoCommand\x12\x11.DoCommandRequest\x1a\x12.DoCommandResponse\"6\x82\xd3\xe4\x93\x020\"./olivia/api/v1/service/audio/{name}/do_command\x12m\n" +
 	"\n" +
 	"Properties\x12\x12.PropertiesRequest\x1a\x13.PropertiesResponse\"6\x82\xd3\xe4\x93\x020\"./olivia/api/v1/service/audio/{name}/propertiesB\tZ\a./audiob\x06proto3"
 
@@ -510,30 +6974,224 @@ func file_audio_proto_rawDescGZIP() []byte {
 	return file_audio_proto_rawDescData
 }
 
-var file_audio_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_audio_proto_msgTypes = make([]protoimpl.MessageInfo, 95)
 var file_audio_proto_goTypes = []any{
-	(*AudioInfo)(nil),          // 0: AudioInfo
-	(*GetAudioRequest)(nil),    // 1: GetAudioRequest
-	(*AudioChunk)(nil),         // 2: AudioChunk
-	(*PlayRequest)(nil),        // 3: PlayRequest
-	(*PlayResponse)(nil),       // 4: PlayResponse
-	(*PropertiesRequest)(nil),  // 5: PropertiesRequest
-	(*PropertiesResponse)(nil), // 6: PropertiesResponse
+	(*AudioInfo)(nil),                        // 0: AudioInfo
+	(*GetAudioRequest)(nil),                  // 1: GetAudioRequest
+	(*AudioChunk)(nil),                       // 2: AudioChunk
+	(*StreamHeader)(nil),                     // 3: StreamHeader
+	(*Timecode)(nil),                         // 4: Timecode
+	(*PlayRequest)(nil),                      // 5: PlayRequest
+	(*PlayResponse)(nil),                     // 6: PlayResponse
+	(*PauseStreamRequest)(nil),               // 7: PauseStreamRequest
+	(*PauseStreamResponse)(nil),              // 8: PauseStreamResponse
+	(*ResumeStreamRequest)(nil),              // 9: ResumeStreamRequest
+	(*ResumeStreamResponse)(nil),             // 10: ResumeStreamResponse
+	(*PreparePlaybackRequest)(nil),           // 11: PreparePlaybackRequest
+	(*PreparePlaybackResponse)(nil),          // 12: PreparePlaybackResponse
+	(*CommitPlaybackRequest)(nil),            // 13: CommitPlaybackRequest
+	(*CommitPlaybackResponse)(nil),           // 14: CommitPlaybackResponse
+	(*ReleasePlaybackRequest)(nil),           // 15: ReleasePlaybackRequest
+	(*ReleasePlaybackResponse)(nil),          // 16: ReleasePlaybackResponse
+	(*SetProfileRequest)(nil),                // 17: SetProfileRequest
+	(*SetProfileResponse)(nil),               // 18: SetProfileResponse
+	(*GetProfileRequest)(nil),                // 19: GetProfileRequest
+	(*GetProfileResponse)(nil),               // 20: GetProfileResponse
+	(*EQBand)(nil),                           // 21: EQBand
+	(*SetEQRequest)(nil),                     // 22: SetEQRequest
+	(*SetEQResponse)(nil),                    // 23: SetEQResponse
+	(*GetEQRequest)(nil),                     // 24: GetEQRequest
+	(*GetEQResponse)(nil),                    // 25: GetEQResponse
+	(*GetLevelsRequest)(nil),                 // 26: GetLevelsRequest
+	(*ChannelLevel)(nil),                     // 27: ChannelLevel
+	(*GetLevelsResponse)(nil),                // 28: GetLevelsResponse
+	(*StreamSpectrumRequest)(nil),            // 29: StreamSpectrumRequest
+	(*SpectrumFrame)(nil),                    // 30: SpectrumFrame
+	(*GetSpectrogramRequest)(nil),            // 31: GetSpectrogramRequest
+	(*GetSpectrogramResponse)(nil),           // 32: GetSpectrogramResponse
+	(*CaptureClipRequest)(nil),               // 33: CaptureClipRequest
+	(*CaptureClipResponse)(nil),              // 34: CaptureClipResponse
+	(*StreamImpulsesRequest)(nil),            // 35: StreamImpulsesRequest
+	(*ImpulseEvent)(nil),                     // 36: ImpulseEvent
+	(*GetLevelStatsRequest)(nil),             // 37: GetLevelStatsRequest
+	(*LevelStatsBucket)(nil),                 // 38: LevelStatsBucket
+	(*GetLevelStatsResponse)(nil),            // 39: GetLevelStatsResponse
+	(*ListHistoryRequest)(nil),               // 40: ListHistoryRequest
+	(*StreamRecord)(nil),                     // 41: StreamRecord
+	(*ListStreamHistoryResponse)(nil),        // 42: ListStreamHistoryResponse
+	(*EventRecord)(nil),                      // 43: EventRecord
+	(*ListEventsResponse)(nil),               // 44: ListEventsResponse
+	(*ListAuditLogRequest)(nil),              // 45: ListAuditLogRequest
+	(*AuditRecord)(nil),                      // 46: AuditRecord
+	(*ListAuditLogResponse)(nil),             // 47: ListAuditLogResponse
+	(*ListActiveStreamsRequest)(nil),         // 48: ListActiveStreamsRequest
+	(*ListActiveStreamsResponse)(nil),        // 49: ListActiveStreamsResponse
+	(*ListRecordingsRequest)(nil),            // 50: ListRecordingsRequest
+	(*StoredRecording)(nil),                  // 51: StoredRecording
+	(*GetRecordingRequest)(nil),              // 52: GetRecordingRequest
+	(*GetRecordingResponse)(nil),             // 53: GetRecordingResponse
+	(*StreamRecordingRequest)(nil),           // 54: StreamRecordingRequest
+	(*ListRecordingsResponse)(nil),           // 55: ListRecordingsResponse
+	(*GetLatencyStatsRequest)(nil),           // 56: GetLatencyStatsRequest
+	(*GetLatencyStatsResponse)(nil),          // 57: GetLatencyStatsResponse
+	(*GetStatsRequest)(nil),                  // 58: GetStatsRequest
+	(*GetStatsResponse)(nil),                 // 59: GetStatsResponse
+	(*GetHealthRequest)(nil),                 // 60: GetHealthRequest
+	(*DeviceHealth)(nil),                     // 61: DeviceHealth
+	(*GetHealthResponse)(nil),                // 62: GetHealthResponse
+	(*DoCommandRequest)(nil),                 // 63: DoCommandRequest
+	(*DoCommandResponse)(nil),                // 64: DoCommandResponse
+	(*GetRecordingStatsRequest)(nil),         // 65: GetRecordingStatsRequest
+	(*GetRecordingStatsResponse)(nil),        // 66: GetRecordingStatsResponse
+	(*StartRecordingRequest)(nil),            // 67: StartRecordingRequest
+	(*StartRecordingResponse)(nil),           // 68: StartRecordingResponse
+	(*StopRecordingRequest)(nil),             // 69: StopRecordingRequest
+	(*StopRecordingResponse)(nil),            // 70: StopRecordingResponse
+	(*ListSegmentsRequest)(nil),              // 71: ListSegmentsRequest
+	(*RecordingSegment)(nil),                 // 72: RecordingSegment
+	(*ListSegmentsResponse)(nil),             // 73: ListSegmentsResponse
+	(*RecordingTrack)(nil),                   // 74: RecordingTrack
+	(*StartRecordingSessionRequest)(nil),     // 75: StartRecordingSessionRequest
+	(*StartRecordingSessionResponse)(nil),    // 76: StartRecordingSessionResponse
+	(*StopRecordingSessionRequest)(nil),      // 77: StopRecordingSessionRequest
+	(*StopRecordingSessionResponse)(nil),     // 78: StopRecordingSessionResponse
+	(*GetRecordingSessionRequest)(nil),       // 79: GetRecordingSessionRequest
+	(*GetRecordingSessionResponse)(nil),      // 80: GetRecordingSessionResponse
+	(*RecordingSession)(nil),                 // 81: RecordingSession
+	(*TrackStatus)(nil),                      // 82: TrackStatus
+	(*RecordingWindow)(nil),                  // 83: RecordingWindow
+	(*ScheduleRecordingRequest)(nil),         // 84: ScheduleRecordingRequest
+	(*ScheduleRecordingResponse)(nil),        // 85: ScheduleRecordingResponse
+	(*CancelScheduledRecordingRequest)(nil),  // 86: CancelScheduledRecordingRequest
+	(*CancelScheduledRecordingResponse)(nil), // 87: CancelScheduledRecordingResponse
+	(*GetTriggerStateRequest)(nil),           // 88: GetTriggerStateRequest
+	(*GetTriggerStateResponse)(nil),          // 89: GetTriggerStateResponse
+	(*ListDevicesRequest)(nil),               // 90: ListDevicesRequest
+	(*Device)(nil),                           // 91: Device
+	(*ListDevicesResponse)(nil),              // 92: ListDevicesResponse
+	(*PropertiesRequest)(nil),                // 93: PropertiesRequest
+	(*PropertiesResponse)(nil),               // 94: PropertiesResponse
 }
 var file_audio_proto_depIdxs = []int32{
-	0, // 0: AudioChunk.info:type_name -> AudioInfo
-	0, // 1: PlayRequest.info:type_name -> AudioInfo
-	1, // 2: AudioService.GetAudio:input_type -> GetAudioRequest
-	3, // 3: AudioService.Play:input_type -> PlayRequest
-	5, // 4: AudioService.Properties:input_type -> PropertiesRequest
-	2, // 5: AudioService.GetAudio:output_type -> AudioChunk
-	4, // 6: AudioService.Play:output_type -> PlayResponse
-	6, // 7: AudioService.Properties:output_type -> PropertiesResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: AudioChunk.info:type_name -> AudioInfo
+	3,  // 1: AudioChunk.header:type_name -> StreamHeader
+	4,  // 2: AudioChunk.timecode:type_name -> Timecode
+	2,  // 3: AudioChunk.batch:type_name -> AudioChunk
+	0,  // 4: StreamHeader.info:type_name -> AudioInfo
+	0,  // 5: PlayRequest.info:type_name -> AudioInfo
+	0,  // 6: PreparePlaybackRequest.info:type_name -> AudioInfo
+	21, // 7: SetEQRequest.bands:type_name -> EQBand
+	21, // 8: GetEQResponse.bands:type_name -> EQBand
+	27, // 9: GetLevelsResponse.channels:type_name -> ChannelLevel
+	0,  // 10: CaptureClipResponse.info:type_name -> AudioInfo
+	38, // 11: GetLevelStatsResponse.buckets:type_name -> LevelStatsBucket
+	41, // 12: ListStreamHistoryResponse.records:type_name -> StreamRecord
+	43, // 13: ListEventsResponse.events:type_name -> EventRecord
+	46, // 14: ListAuditLogResponse.records:type_name -> AuditRecord
+	41, // 15: ListActiveStreamsResponse.streams:type_name -> StreamRecord
+	51, // 16: GetRecordingResponse.recording:type_name -> StoredRecording
+	51, // 17: ListRecordingsResponse.recordings:type_name -> StoredRecording
+	61, // 18: GetHealthResponse.capture:type_name -> DeviceHealth
+	61, // 19: GetHealthResponse.playback:type_name -> DeviceHealth
+	72, // 20: StopRecordingResponse.segments:type_name -> RecordingSegment
+	72, // 21: ListSegmentsResponse.segments:type_name -> RecordingSegment
+	74, // 22: StartRecordingSessionRequest.tracks:type_name -> RecordingTrack
+	81, // 23: StopRecordingSessionResponse.session:type_name -> RecordingSession
+	81, // 24: GetRecordingSessionResponse.session:type_name -> RecordingSession
+	82, // 25: RecordingSession.tracks:type_name -> TrackStatus
+	74, // 26: TrackStatus.track:type_name -> RecordingTrack
+	72, // 27: TrackStatus.segments:type_name -> RecordingSegment
+	83, // 28: ScheduleRecordingRequest.windows:type_name -> RecordingWindow
+	72, // 29: CancelScheduledRecordingResponse.segments:type_name -> RecordingSegment
+	72, // 30: GetTriggerStateResponse.segments:type_name -> RecordingSegment
+	91, // 31: ListDevicesResponse.devices:type_name -> Device
+	1,  // 32: AudioService.GetAudio:input_type -> GetAudioRequest
+	5,  // 33: AudioService.Play:input_type -> PlayRequest
+	7,  // 34: AudioService.PauseStream:input_type -> PauseStreamRequest
+	9,  // 35: AudioService.ResumeStream:input_type -> ResumeStreamRequest
+	11, // 36: AudioService.PreparePlayback:input_type -> PreparePlaybackRequest
+	13, // 37: AudioService.CommitPlayback:input_type -> CommitPlaybackRequest
+	15, // 38: AudioService.ReleasePlayback:input_type -> ReleasePlaybackRequest
+	17, // 39: AudioService.SetProfile:input_type -> SetProfileRequest
+	19, // 40: AudioService.GetProfile:input_type -> GetProfileRequest
+	22, // 41: AudioService.SetEQ:input_type -> SetEQRequest
+	24, // 42: AudioService.GetEQ:input_type -> GetEQRequest
+	26, // 43: AudioService.GetLevels:input_type -> GetLevelsRequest
+	26, // 44: AudioService.StreamLevels:input_type -> GetLevelsRequest
+	29, // 45: AudioService.StreamSpectrum:input_type -> StreamSpectrumRequest
+	31, // 46: AudioService.GetSpectrogram:input_type -> GetSpectrogramRequest
+	33, // 47: AudioService.CaptureClip:input_type -> CaptureClipRequest
+	35, // 48: AudioService.StreamImpulses:input_type -> StreamImpulsesRequest
+	37, // 49: AudioService.GetLevelStats:input_type -> GetLevelStatsRequest
+	40, // 50: AudioService.ListStreamHistory:input_type -> ListHistoryRequest
+	40, // 51: AudioService.ListEvents:input_type -> ListHistoryRequest
+	45, // 52: AudioService.ListAuditLog:input_type -> ListAuditLogRequest
+	48, // 53: AudioService.ListActiveStreams:input_type -> ListActiveStreamsRequest
+	50, // 54: AudioService.ListRecordings:input_type -> ListRecordingsRequest
+	65, // 55: AudioService.GetRecordingStats:input_type -> GetRecordingStatsRequest
+	52, // 56: AudioService.GetRecording:input_type -> GetRecordingRequest
+	54, // 57: AudioService.StreamRecording:input_type -> StreamRecordingRequest
+	67, // 58: AudioService.StartRecording:input_type -> StartRecordingRequest
+	69, // 59: AudioService.StopRecording:input_type -> StopRecordingRequest
+	71, // 60: AudioService.ListSegments:input_type -> ListSegmentsRequest
+	75, // 61: AudioService.StartRecordingSession:input_type -> StartRecordingSessionRequest
+	77, // 62: AudioService.StopRecordingSession:input_type -> StopRecordingSessionRequest
+	79, // 63: AudioService.GetRecordingSession:input_type -> GetRecordingSessionRequest
+	84, // 64: AudioService.ScheduleRecording:input_type -> ScheduleRecordingRequest
+	86, // 65: AudioService.CancelScheduledRecording:input_type -> CancelScheduledRecordingRequest
+	88, // 66: AudioService.GetTriggerState:input_type -> GetTriggerStateRequest
+	90, // 67: AudioService.ListDevices:input_type -> ListDevicesRequest
+	56, // 68: AudioService.GetLatencyStats:input_type -> GetLatencyStatsRequest
+	58, // 69: AudioService.GetStats:input_type -> GetStatsRequest
+	60, // 70: AudioService.GetHealth:input_type -> GetHealthRequest
+	63, // 71: AudioService.DoCommand:input_type -> DoCommandRequest
+	93, // 72: AudioService.Properties:input_type -> PropertiesRequest
+	2,  // 73: AudioService.GetAudio:output_type -> AudioChunk
+	6,  // 74: AudioService.Play:output_type -> PlayResponse
+	8,  // 75: AudioService.PauseStream:output_type -> PauseStreamResponse
+	10, // 76: AudioService.ResumeStream:output_type -> ResumeStreamResponse
+	12, // 77: AudioService.PreparePlayback:output_type -> PreparePlaybackResponse
+	14, // 78: AudioService.CommitPlayback:output_type -> CommitPlaybackResponse
+	16, // 79: AudioService.ReleasePlayback:output_type -> ReleasePlaybackResponse
+	18, // 80: AudioService.SetProfile:output_type -> SetProfileResponse
+	20, // 81: AudioService.GetProfile:output_type -> GetProfileResponse
+	23, // 82: AudioService.SetEQ:output_type -> SetEQResponse
+	25, // 83: AudioService.GetEQ:output_type -> GetEQResponse
+	28, // 84: AudioService.GetLevels:output_type -> GetLevelsResponse
+	28, // 85: AudioService.StreamLevels:output_type -> GetLevelsResponse
+	30, // 86: AudioService.StreamSpectrum:output_type -> SpectrumFrame
+	32, // 87: AudioService.GetSpectrogram:output_type -> GetSpectrogramResponse
+	34, // 88: AudioService.CaptureClip:output_type -> CaptureClipResponse
+	36, // 89: AudioService.StreamImpulses:output_type -> ImpulseEvent
+	39, // 90: AudioService.GetLevelStats:output_type -> GetLevelStatsResponse
+	42, // 91: AudioService.ListStreamHistory:output_type -> ListStreamHistoryResponse
+	44, // 92: AudioService.ListEvents:output_type -> ListEventsResponse
+	47, // 93: AudioService.ListAuditLog:output_type -> ListAuditLogResponse
+	49, // 94: AudioService.ListActiveStreams:output_type -> ListActiveStreamsResponse
+	55, // 95: AudioService.ListRecordings:output_type -> ListRecordingsResponse
+	66, // 96: AudioService.GetRecordingStats:output_type -> GetRecordingStatsResponse
+	53, // 97: AudioService.GetRecording:output_type -> GetRecordingResponse
+	2,  // 98: AudioService.StreamRecording:output_type -> AudioChunk
+	68, // 99: AudioService.StartRecording:output_type -> StartRecordingResponse
+	70, // 100: AudioService.StopRecording:output_type -> StopRecordingResponse
+	73, // 101: AudioService.ListSegments:output_type -> ListSegmentsResponse
+	76, // 102: AudioService.StartRecordingSession:output_type -> StartRecordingSessionResponse
+	78, // 103: AudioService.StopRecordingSession:output_type -> StopRecordingSessionResponse
+	80, // 104: AudioService.GetRecordingSession:output_type -> GetRecordingSessionResponse
+	85, // 105: AudioService.ScheduleRecording:output_type -> ScheduleRecordingResponse
+	87, // 106: AudioService.CancelScheduledRecording:output_type -> CancelScheduledRecordingResponse
+	89, // 107: AudioService.GetTriggerState:output_type -> GetTriggerStateResponse
+	92, // 108: AudioService.ListDevices:output_type -> ListDevicesResponse
+	57, // 109: AudioService.GetLatencyStats:output_type -> GetLatencyStatsResponse
+	59, // 110: AudioService.GetStats:output_type -> GetStatsResponse
+	62, // 111: AudioService.GetHealth:output_type -> GetHealthResponse
+	64, // 112: AudioService.DoCommand:output_type -> DoCommandResponse
+	94, // 113: AudioService.Properties:output_type -> PropertiesResponse
+	73, // [73:114] is the sub-list for method output_type
+	32, // [32:73] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_audio_proto_init() }
@@ -541,13 +7199,14 @@ func file_audio_proto_init() {
 	if File_audio_proto != nil {
 		return
 	}
+	file_audio_proto_msgTypes[2].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_audio_proto_rawDesc), len(file_audio_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   95,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -24,6 +24,44 @@ const _ = grpc.SupportPackageIsVersion7
 type AudioServiceClient interface {
 	GetAudio(ctx context.Context, in *GetAudioRequest, opts ...grpc.CallOption) (AudioService_GetAudioClient, error)
 	Play(ctx context.Context, in *PlayRequest, opts ...grpc.CallOption) (*PlayResponse, error)
+	PauseStream(ctx context.Context, in *PauseStreamRequest, opts ...grpc.CallOption) (*PauseStreamResponse, error)
+	ResumeStream(ctx context.Context, in *ResumeStreamRequest, opts ...grpc.CallOption) (*ResumeStreamResponse, error)
+	PreparePlayback(ctx context.Context, in *PreparePlaybackRequest, opts ...grpc.CallOption) (*PreparePlaybackResponse, error)
+	CommitPlayback(ctx context.Context, in *CommitPlaybackRequest, opts ...grpc.CallOption) (*CommitPlaybackResponse, error)
+	ReleasePlayback(ctx context.Context, in *ReleasePlaybackRequest, opts ...grpc.CallOption) (*ReleasePlaybackResponse, error)
+	SetProfile(ctx context.Context, in *SetProfileRequest, opts ...grpc.CallOption) (*SetProfileResponse, error)
+	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*GetProfileResponse, error)
+	SetEQ(ctx context.Context, in *SetEQRequest, opts ...grpc.CallOption) (*SetEQResponse, error)
+	GetEQ(ctx context.Context, in *GetEQRequest, opts ...grpc.CallOption) (*GetEQResponse, error)
+	GetLevels(ctx context.Context, in *GetLevelsRequest, opts ...grpc.CallOption) (*GetLevelsResponse, error)
+	StreamLevels(ctx context.Context, in *GetLevelsRequest, opts ...grpc.CallOption) (AudioService_StreamLevelsClient, error)
+	StreamSpectrum(ctx context.Context, in *StreamSpectrumRequest, opts ...grpc.CallOption) (AudioService_StreamSpectrumClient, error)
+	GetSpectrogram(ctx context.Context, in *GetSpectrogramRequest, opts ...grpc.CallOption) (*GetSpectrogramResponse, error)
+	CaptureClip(ctx context.Context, in *CaptureClipRequest, opts ...grpc.CallOption) (*CaptureClipResponse, error)
+	StreamImpulses(ctx context.Context, in *StreamImpulsesRequest, opts ...grpc.CallOption) (AudioService_StreamImpulsesClient, error)
+	GetLevelStats(ctx context.Context, in *GetLevelStatsRequest, opts ...grpc.CallOption) (*GetLevelStatsResponse, error)
+	ListStreamHistory(ctx context.Context, in *ListHistoryRequest, opts ...grpc.CallOption) (*ListStreamHistoryResponse, error)
+	ListEvents(ctx context.Context, in *ListHistoryRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error)
+	ListActiveStreams(ctx context.Context, in *ListActiveStreamsRequest, opts ...grpc.CallOption) (*ListActiveStreamsResponse, error)
+	ListRecordings(ctx context.Context, in *ListRecordingsRequest, opts ...grpc.CallOption) (*ListRecordingsResponse, error)
+	GetRecordingStats(ctx context.Context, in *GetRecordingStatsRequest, opts ...grpc.CallOption) (*GetRecordingStatsResponse, error)
+	GetRecording(ctx context.Context, in *GetRecordingRequest, opts ...grpc.CallOption) (*GetRecordingResponse, error)
+	StreamRecording(ctx context.Context, in *StreamRecordingRequest, opts ...grpc.CallOption) (AudioService_StreamRecordingClient, error)
+	StartRecording(ctx context.Context, in *StartRecordingRequest, opts ...grpc.CallOption) (*StartRecordingResponse, error)
+	StopRecording(ctx context.Context, in *StopRecordingRequest, opts ...grpc.CallOption) (*StopRecordingResponse, error)
+	ListSegments(ctx context.Context, in *ListSegmentsRequest, opts ...grpc.CallOption) (*ListSegmentsResponse, error)
+	StartRecordingSession(ctx context.Context, in *StartRecordingSessionRequest, opts ...grpc.CallOption) (*StartRecordingSessionResponse, error)
+	StopRecordingSession(ctx context.Context, in *StopRecordingSessionRequest, opts ...grpc.CallOption) (*StopRecordingSessionResponse, error)
+	GetRecordingSession(ctx context.Context, in *GetRecordingSessionRequest, opts ...grpc.CallOption) (*GetRecordingSessionResponse, error)
+	ScheduleRecording(ctx context.Context, in *ScheduleRecordingRequest, opts ...grpc.CallOption) (*ScheduleRecordingResponse, error)
+	CancelScheduledRecording(ctx context.Context, in *CancelScheduledRecordingRequest, opts ...grpc.CallOption) (*CancelScheduledRecordingResponse, error)
+	GetTriggerState(ctx context.Context, in *GetTriggerStateRequest, opts ...grpc.CallOption) (*GetTriggerStateResponse, error)
+	ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error)
+	GetLatencyStats(ctx context.Context, in *GetLatencyStatsRequest, opts ...grpc.CallOption) (*GetLatencyStatsResponse, error)
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*GetHealthResponse, error)
+	DoCommand(ctx context.Context, in *DoCommandRequest, opts ...grpc.CallOption) (*DoCommandResponse, error)
 	Properties(ctx context.Context, in *PropertiesRequest, opts ...grpc.CallOption) (*PropertiesResponse, error)
 }
 
@@ -76,86 +114,1368 @@ func (c *audioServiceClient) Play(ctx context.Context, in *PlayRequest, opts ...
 	return out, nil
 }
 
-func (c *audioServiceClient) Properties(ctx context.Context, in *PropertiesRequest, opts ...grpc.CallOption) (*PropertiesResponse, error) {
-	out := new(PropertiesResponse)
-	err := c.cc.Invoke(ctx, "/AudioService/Properties", in, out, opts...)
+func (c *audioServiceClient) PauseStream(ctx context.Context, in *PauseStreamRequest, opts ...grpc.CallOption) (*PauseStreamResponse, error) {
+	out := new(PauseStreamResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/PauseStream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ResumeStream(ctx context.Context, in *ResumeStreamRequest, opts ...grpc.CallOption) (*ResumeStreamResponse, error) {
+	out := new(ResumeStreamResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ResumeStream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) PreparePlayback(ctx context.Context, in *PreparePlaybackRequest, opts ...grpc.CallOption) (*PreparePlaybackResponse, error) {
+	out := new(PreparePlaybackResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/PreparePlayback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) CommitPlayback(ctx context.Context, in *CommitPlaybackRequest, opts ...grpc.CallOption) (*CommitPlaybackResponse, error) {
+	out := new(CommitPlaybackResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/CommitPlayback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ReleasePlayback(ctx context.Context, in *ReleasePlaybackRequest, opts ...grpc.CallOption) (*ReleasePlaybackResponse, error) {
+	out := new(ReleasePlaybackResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ReleasePlayback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) SetProfile(ctx context.Context, in *SetProfileRequest, opts ...grpc.CallOption) (*SetProfileResponse, error) {
+	out := new(SetProfileResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/SetProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*GetProfileResponse, error) {
+	out := new(GetProfileResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) SetEQ(ctx context.Context, in *SetEQRequest, opts ...grpc.CallOption) (*SetEQResponse, error) {
+	out := new(SetEQResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/SetEQ", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetEQ(ctx context.Context, in *GetEQRequest, opts ...grpc.CallOption) (*GetEQResponse, error) {
+	out := new(GetEQResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetEQ", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetLevels(ctx context.Context, in *GetLevelsRequest, opts ...grpc.CallOption) (*GetLevelsResponse, error) {
+	out := new(GetLevelsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetLevels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) StreamLevels(ctx context.Context, in *GetLevelsRequest, opts ...grpc.CallOption) (AudioService_StreamLevelsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioService_ServiceDesc.Streams[1], "/AudioService/StreamLevels", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioServiceStreamLevelsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AudioService_StreamLevelsClient interface {
+	Recv() (*GetLevelsResponse, error)
+	grpc.ClientStream
+}
+
+type audioServiceStreamLevelsClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioServiceStreamLevelsClient) Recv() (*GetLevelsResponse, error) {
+	m := new(GetLevelsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioServiceClient) StreamSpectrum(ctx context.Context, in *StreamSpectrumRequest, opts ...grpc.CallOption) (AudioService_StreamSpectrumClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioService_ServiceDesc.Streams[2], "/AudioService/StreamSpectrum", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioServiceStreamSpectrumClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AudioService_StreamSpectrumClient interface {
+	Recv() (*SpectrumFrame, error)
+	grpc.ClientStream
+}
+
+type audioServiceStreamSpectrumClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioServiceStreamSpectrumClient) Recv() (*SpectrumFrame, error) {
+	m := new(SpectrumFrame)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioServiceClient) GetSpectrogram(ctx context.Context, in *GetSpectrogramRequest, opts ...grpc.CallOption) (*GetSpectrogramResponse, error) {
+	out := new(GetSpectrogramResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetSpectrogram", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) CaptureClip(ctx context.Context, in *CaptureClipRequest, opts ...grpc.CallOption) (*CaptureClipResponse, error) {
+	out := new(CaptureClipResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/CaptureClip", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) StreamImpulses(ctx context.Context, in *StreamImpulsesRequest, opts ...grpc.CallOption) (AudioService_StreamImpulsesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioService_ServiceDesc.Streams[3], "/AudioService/StreamImpulses", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioServiceStreamImpulsesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AudioService_StreamImpulsesClient interface {
+	Recv() (*ImpulseEvent, error)
+	grpc.ClientStream
+}
+
+type audioServiceStreamImpulsesClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioServiceStreamImpulsesClient) Recv() (*ImpulseEvent, error) {
+	m := new(ImpulseEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioServiceClient) GetLevelStats(ctx context.Context, in *GetLevelStatsRequest, opts ...grpc.CallOption) (*GetLevelStatsResponse, error) {
+	out := new(GetLevelStatsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetLevelStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ListStreamHistory(ctx context.Context, in *ListHistoryRequest, opts ...grpc.CallOption) (*ListStreamHistoryResponse, error) {
+	out := new(ListStreamHistoryResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ListStreamHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ListEvents(ctx context.Context, in *ListHistoryRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ListEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error) {
+	out := new(ListAuditLogResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ListAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ListActiveStreams(ctx context.Context, in *ListActiveStreamsRequest, opts ...grpc.CallOption) (*ListActiveStreamsResponse, error) {
+	out := new(ListActiveStreamsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ListActiveStreams", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ListRecordings(ctx context.Context, in *ListRecordingsRequest, opts ...grpc.CallOption) (*ListRecordingsResponse, error) {
+	out := new(ListRecordingsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ListRecordings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetRecordingStats(ctx context.Context, in *GetRecordingStatsRequest, opts ...grpc.CallOption) (*GetRecordingStatsResponse, error) {
+	out := new(GetRecordingStatsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetRecordingStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetRecording(ctx context.Context, in *GetRecordingRequest, opts ...grpc.CallOption) (*GetRecordingResponse, error) {
+	out := new(GetRecordingResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) StreamRecording(ctx context.Context, in *StreamRecordingRequest, opts ...grpc.CallOption) (AudioService_StreamRecordingClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AudioService_ServiceDesc.Streams[4], "/AudioService/StreamRecording", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &audioServiceStreamRecordingClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AudioService_StreamRecordingClient interface {
+	Recv() (*AudioChunk, error)
+	grpc.ClientStream
+}
+
+type audioServiceStreamRecordingClient struct {
+	grpc.ClientStream
+}
+
+func (x *audioServiceStreamRecordingClient) Recv() (*AudioChunk, error) {
+	m := new(AudioChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *audioServiceClient) StartRecording(ctx context.Context, in *StartRecordingRequest, opts ...grpc.CallOption) (*StartRecordingResponse, error) {
+	out := new(StartRecordingResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/StartRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) StopRecording(ctx context.Context, in *StopRecordingRequest, opts ...grpc.CallOption) (*StopRecordingResponse, error) {
+	out := new(StopRecordingResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/StopRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ListSegments(ctx context.Context, in *ListSegmentsRequest, opts ...grpc.CallOption) (*ListSegmentsResponse, error) {
+	out := new(ListSegmentsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ListSegments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) StartRecordingSession(ctx context.Context, in *StartRecordingSessionRequest, opts ...grpc.CallOption) (*StartRecordingSessionResponse, error) {
+	out := new(StartRecordingSessionResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/StartRecordingSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) StopRecordingSession(ctx context.Context, in *StopRecordingSessionRequest, opts ...grpc.CallOption) (*StopRecordingSessionResponse, error) {
+	out := new(StopRecordingSessionResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/StopRecordingSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetRecordingSession(ctx context.Context, in *GetRecordingSessionRequest, opts ...grpc.CallOption) (*GetRecordingSessionResponse, error) {
+	out := new(GetRecordingSessionResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetRecordingSession", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *audioServiceClient) ScheduleRecording(ctx context.Context, in *ScheduleRecordingRequest, opts ...grpc.CallOption) (*ScheduleRecordingResponse, error) {
+	out := new(ScheduleRecordingResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ScheduleRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) CancelScheduledRecording(ctx context.Context, in *CancelScheduledRecordingRequest, opts ...grpc.CallOption) (*CancelScheduledRecordingResponse, error) {
+	out := new(CancelScheduledRecordingResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/CancelScheduledRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetTriggerState(ctx context.Context, in *GetTriggerStateRequest, opts ...grpc.CallOption) (*GetTriggerStateResponse, error) {
+	out := new(GetTriggerStateResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetTriggerState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error) {
+	out := new(ListDevicesResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/ListDevices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetLatencyStats(ctx context.Context, in *GetLatencyStatsRequest, opts ...grpc.CallOption) (*GetLatencyStatsResponse, error) {
+	out := new(GetLatencyStatsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetLatencyStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*GetHealthResponse, error) {
+	out := new(GetHealthResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/GetHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) DoCommand(ctx context.Context, in *DoCommandRequest, opts ...grpc.CallOption) (*DoCommandResponse, error) {
+	out := new(DoCommandResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/DoCommand", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *audioServiceClient) Properties(ctx context.Context, in *PropertiesRequest, opts ...grpc.CallOption) (*PropertiesResponse, error) {
+	out := new(PropertiesResponse)
+	err := c.cc.Invoke(ctx, "/AudioService/Properties", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AudioServiceServer is the server API for AudioService service.
+// All implementations must embed UnimplementedAudioServiceServer
+// for forward compatibility
+type AudioServiceServer interface {
+	GetAudio(*GetAudioRequest, AudioService_GetAudioServer) error
+	Play(context.Context, *PlayRequest) (*PlayResponse, error)
+	PauseStream(context.Context, *PauseStreamRequest) (*PauseStreamResponse, error)
+	ResumeStream(context.Context, *ResumeStreamRequest) (*ResumeStreamResponse, error)
+	PreparePlayback(context.Context, *PreparePlaybackRequest) (*PreparePlaybackResponse, error)
+	CommitPlayback(context.Context, *CommitPlaybackRequest) (*CommitPlaybackResponse, error)
+	ReleasePlayback(context.Context, *ReleasePlaybackRequest) (*ReleasePlaybackResponse, error)
+	SetProfile(context.Context, *SetProfileRequest) (*SetProfileResponse, error)
+	GetProfile(context.Context, *GetProfileRequest) (*GetProfileResponse, error)
+	SetEQ(context.Context, *SetEQRequest) (*SetEQResponse, error)
+	GetEQ(context.Context, *GetEQRequest) (*GetEQResponse, error)
+	GetLevels(context.Context, *GetLevelsRequest) (*GetLevelsResponse, error)
+	StreamLevels(*GetLevelsRequest, AudioService_StreamLevelsServer) error
+	StreamSpectrum(*StreamSpectrumRequest, AudioService_StreamSpectrumServer) error
+	GetSpectrogram(context.Context, *GetSpectrogramRequest) (*GetSpectrogramResponse, error)
+	CaptureClip(context.Context, *CaptureClipRequest) (*CaptureClipResponse, error)
+	StreamImpulses(*StreamImpulsesRequest, AudioService_StreamImpulsesServer) error
+	GetLevelStats(context.Context, *GetLevelStatsRequest) (*GetLevelStatsResponse, error)
+	ListStreamHistory(context.Context, *ListHistoryRequest) (*ListStreamHistoryResponse, error)
+	ListEvents(context.Context, *ListHistoryRequest) (*ListEventsResponse, error)
+	ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error)
+	ListActiveStreams(context.Context, *ListActiveStreamsRequest) (*ListActiveStreamsResponse, error)
+	ListRecordings(context.Context, *ListRecordingsRequest) (*ListRecordingsResponse, error)
+	GetRecordingStats(context.Context, *GetRecordingStatsRequest) (*GetRecordingStatsResponse, error)
+	GetRecording(context.Context, *GetRecordingRequest) (*GetRecordingResponse, error)
+	StreamRecording(*StreamRecordingRequest, AudioService_StreamRecordingServer) error
+	StartRecording(context.Context, *StartRecordingRequest) (*StartRecordingResponse, error)
+	StopRecording(context.Context, *StopRecordingRequest) (*StopRecordingResponse, error)
+	ListSegments(context.Context, *ListSegmentsRequest) (*ListSegmentsResponse, error)
+	StartRecordingSession(context.Context, *StartRecordingSessionRequest) (*StartRecordingSessionResponse, error)
+	StopRecordingSession(context.Context, *StopRecordingSessionRequest) (*StopRecordingSessionResponse, error)
+	GetRecordingSession(context.Context, *GetRecordingSessionRequest) (*GetRecordingSessionResponse, error)
+	ScheduleRecording(context.Context, *ScheduleRecordingRequest) (*ScheduleRecordingResponse, error)
+	CancelScheduledRecording(context.Context, *CancelScheduledRecordingRequest) (*CancelScheduledRecordingResponse, error)
+	GetTriggerState(context.Context, *GetTriggerStateRequest) (*GetTriggerStateResponse, error)
+	ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error)
+	GetLatencyStats(context.Context, *GetLatencyStatsRequest) (*GetLatencyStatsResponse, error)
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	GetHealth(context.Context, *GetHealthRequest) (*GetHealthResponse, error)
+	DoCommand(context.Context, *DoCommandRequest) (*DoCommandResponse, error)
+	Properties(context.Context, *PropertiesRequest) (*PropertiesResponse, error)
+	mustEmbedUnimplementedAudioServiceServer()
+}
+
+// UnimplementedAudioServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAudioServiceServer struct {
+}
+
+func (UnimplementedAudioServiceServer) GetAudio(*GetAudioRequest, AudioService_GetAudioServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetAudio not implemented")
+}
+func (UnimplementedAudioServiceServer) Play(context.Context, *PlayRequest) (*PlayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Play not implemented")
+}
+func (UnimplementedAudioServiceServer) PauseStream(context.Context, *PauseStreamRequest) (*PauseStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseStream not implemented")
+}
+func (UnimplementedAudioServiceServer) ResumeStream(context.Context, *ResumeStreamRequest) (*ResumeStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeStream not implemented")
+}
+func (UnimplementedAudioServiceServer) PreparePlayback(context.Context, *PreparePlaybackRequest) (*PreparePlaybackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreparePlayback not implemented")
+}
+func (UnimplementedAudioServiceServer) CommitPlayback(context.Context, *CommitPlaybackRequest) (*CommitPlaybackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitPlayback not implemented")
+}
+func (UnimplementedAudioServiceServer) ReleasePlayback(context.Context, *ReleasePlaybackRequest) (*ReleasePlaybackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleasePlayback not implemented")
+}
+func (UnimplementedAudioServiceServer) SetProfile(context.Context, *SetProfileRequest) (*SetProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProfile not implemented")
+}
+func (UnimplementedAudioServiceServer) GetProfile(context.Context, *GetProfileRequest) (*GetProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (UnimplementedAudioServiceServer) SetEQ(context.Context, *SetEQRequest) (*SetEQResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEQ not implemented")
+}
+func (UnimplementedAudioServiceServer) GetEQ(context.Context, *GetEQRequest) (*GetEQResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEQ not implemented")
+}
+func (UnimplementedAudioServiceServer) GetLevels(context.Context, *GetLevelsRequest) (*GetLevelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLevels not implemented")
+}
+func (UnimplementedAudioServiceServer) StreamLevels(*GetLevelsRequest, AudioService_StreamLevelsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLevels not implemented")
+}
+func (UnimplementedAudioServiceServer) StreamSpectrum(*StreamSpectrumRequest, AudioService_StreamSpectrumServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSpectrum not implemented")
+}
+func (UnimplementedAudioServiceServer) GetSpectrogram(context.Context, *GetSpectrogramRequest) (*GetSpectrogramResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpectrogram not implemented")
+}
+func (UnimplementedAudioServiceServer) CaptureClip(context.Context, *CaptureClipRequest) (*CaptureClipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CaptureClip not implemented")
+}
+func (UnimplementedAudioServiceServer) StreamImpulses(*StreamImpulsesRequest, AudioService_StreamImpulsesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamImpulses not implemented")
+}
+func (UnimplementedAudioServiceServer) GetLevelStats(context.Context, *GetLevelStatsRequest) (*GetLevelStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLevelStats not implemented")
+}
+func (UnimplementedAudioServiceServer) ListStreamHistory(context.Context, *ListHistoryRequest) (*ListStreamHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStreamHistory not implemented")
+}
+func (UnimplementedAudioServiceServer) ListEvents(context.Context, *ListHistoryRequest) (*ListEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (UnimplementedAudioServiceServer) ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
+func (UnimplementedAudioServiceServer) ListActiveStreams(context.Context, *ListActiveStreamsRequest) (*ListActiveStreamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActiveStreams not implemented")
+}
+func (UnimplementedAudioServiceServer) ListRecordings(context.Context, *ListRecordingsRequest) (*ListRecordingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecordings not implemented")
+}
+func (UnimplementedAudioServiceServer) GetRecordingStats(context.Context, *GetRecordingStatsRequest) (*GetRecordingStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecordingStats not implemented")
+}
+func (UnimplementedAudioServiceServer) GetRecording(context.Context, *GetRecordingRequest) (*GetRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecording not implemented")
+}
+func (UnimplementedAudioServiceServer) StreamRecording(*StreamRecordingRequest, AudioService_StreamRecordingServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamRecording not implemented")
+}
+func (UnimplementedAudioServiceServer) StartRecording(context.Context, *StartRecordingRequest) (*StartRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRecording not implemented")
+}
+func (UnimplementedAudioServiceServer) StopRecording(context.Context, *StopRecordingRequest) (*StopRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopRecording not implemented")
+}
+func (UnimplementedAudioServiceServer) ListSegments(context.Context, *ListSegmentsRequest) (*ListSegmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSegments not implemented")
+}
+func (UnimplementedAudioServiceServer) StartRecordingSession(context.Context, *StartRecordingSessionRequest) (*StartRecordingSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRecordingSession not implemented")
+}
+func (UnimplementedAudioServiceServer) StopRecordingSession(context.Context, *StopRecordingSessionRequest) (*StopRecordingSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopRecordingSession not implemented")
+}
+func (UnimplementedAudioServiceServer) GetRecordingSession(context.Context, *GetRecordingSessionRequest) (*GetRecordingSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecordingSession not implemented")
+}
+func (UnimplementedAudioServiceServer) ScheduleRecording(context.Context, *ScheduleRecordingRequest) (*ScheduleRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleRecording not implemented")
+}
+func (UnimplementedAudioServiceServer) CancelScheduledRecording(context.Context, *CancelScheduledRecordingRequest) (*CancelScheduledRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScheduledRecording not implemented")
+}
+func (UnimplementedAudioServiceServer) GetTriggerState(context.Context, *GetTriggerStateRequest) (*GetTriggerStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTriggerState not implemented")
+}
+func (UnimplementedAudioServiceServer) ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDevices not implemented")
+}
+func (UnimplementedAudioServiceServer) GetLatencyStats(context.Context, *GetLatencyStatsRequest) (*GetLatencyStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatencyStats not implemented")
+}
+func (UnimplementedAudioServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedAudioServiceServer) GetHealth(context.Context, *GetHealthRequest) (*GetHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHealth not implemented")
+}
+func (UnimplementedAudioServiceServer) DoCommand(context.Context, *DoCommandRequest) (*DoCommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DoCommand not implemented")
+}
+func (UnimplementedAudioServiceServer) Properties(context.Context, *PropertiesRequest) (*PropertiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Properties not implemented")
+}
+func (UnimplementedAudioServiceServer) mustEmbedUnimplementedAudioServiceServer() {}
+
+// UnsafeAudioServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AudioServiceServer will
+// result in compilation errors.
+type UnsafeAudioServiceServer interface {
+	mustEmbedUnimplementedAudioServiceServer()
+}
+
+func RegisterAudioServiceServer(s grpc.ServiceRegistrar, srv AudioServiceServer) {
+	s.RegisterService(&AudioService_ServiceDesc, srv)
+}
+
+func _AudioService_GetAudio_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetAudioRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioServiceServer).GetAudio(m, &audioServiceGetAudioServer{stream})
+}
+
+type AudioService_GetAudioServer interface {
+	Send(*AudioChunk) error
+	grpc.ServerStream
+}
+
+type audioServiceGetAudioServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioServiceGetAudioServer) Send(m *AudioChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioService_Play_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).Play(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/Play",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).Play(ctx, req.(*PlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_PauseStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).PauseStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/PauseStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).PauseStream(ctx, req.(*PauseStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_ResumeStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ResumeStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ResumeStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ResumeStream(ctx, req.(*ResumeStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_PreparePlayback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreparePlaybackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).PreparePlayback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/PreparePlayback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).PreparePlayback(ctx, req.(*PreparePlaybackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_CommitPlayback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitPlaybackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).CommitPlayback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/CommitPlayback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).CommitPlayback(ctx, req.(*CommitPlaybackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_ReleasePlayback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleasePlaybackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ReleasePlayback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ReleasePlayback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ReleasePlayback(ctx, req.(*ReleasePlaybackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_SetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).SetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/SetProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).SetProfile(ctx, req.(*SetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetProfile(ctx, req.(*GetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_SetEQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).SetEQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/SetEQ",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).SetEQ(ctx, req.(*SetEQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_GetEQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetEQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetEQ",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetEQ(ctx, req.(*GetEQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_GetLevels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLevelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetLevels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetLevels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetLevels(ctx, req.(*GetLevelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_StreamLevels_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLevelsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioServiceServer).StreamLevels(m, &audioServiceStreamLevelsServer{stream})
+}
+
+type AudioService_StreamLevelsServer interface {
+	Send(*GetLevelsResponse) error
+	grpc.ServerStream
+}
+
+type audioServiceStreamLevelsServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioServiceStreamLevelsServer) Send(m *GetLevelsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioService_StreamSpectrum_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSpectrumRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioServiceServer).StreamSpectrum(m, &audioServiceStreamSpectrumServer{stream})
+}
+
+type AudioService_StreamSpectrumServer interface {
+	Send(*SpectrumFrame) error
+	grpc.ServerStream
+}
+
+type audioServiceStreamSpectrumServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioServiceStreamSpectrumServer) Send(m *SpectrumFrame) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioService_GetSpectrogram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpectrogramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetSpectrogram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetSpectrogram",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetSpectrogram(ctx, req.(*GetSpectrogramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_CaptureClip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptureClipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).CaptureClip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/CaptureClip",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).CaptureClip(ctx, req.(*CaptureClipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_StreamImpulses_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamImpulsesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioServiceServer).StreamImpulses(m, &audioServiceStreamImpulsesServer{stream})
+}
+
+type AudioService_StreamImpulsesServer interface {
+	Send(*ImpulseEvent) error
+	grpc.ServerStream
+}
+
+type audioServiceStreamImpulsesServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioServiceStreamImpulsesServer) Send(m *ImpulseEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioService_GetLevelStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLevelStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetLevelStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetLevelStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetLevelStats(ctx, req.(*GetLevelStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_ListStreamHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ListStreamHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ListStreamHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ListStreamHistory(ctx, req.(*ListHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ListEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ListEvents(ctx, req.(*ListHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ListAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ListAuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ListAuditLog(ctx, req.(*ListAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_ListActiveStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActiveStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ListActiveStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ListActiveStreams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ListActiveStreams(ctx, req.(*ListActiveStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_ListRecordings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecordingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ListRecordings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ListRecordings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ListRecordings(ctx, req.(*ListRecordingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_GetRecordingStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordingStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetRecordingStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetRecordingStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetRecordingStats(ctx, req.(*GetRecordingStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_GetRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetRecording(ctx, req.(*GetRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_StreamRecording_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRecordingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AudioServiceServer).StreamRecording(m, &audioServiceStreamRecordingServer{stream})
+}
+
+type AudioService_StreamRecordingServer interface {
+	Send(*AudioChunk) error
+	grpc.ServerStream
+}
+
+type audioServiceStreamRecordingServer struct {
+	grpc.ServerStream
+}
+
+func (x *audioServiceStreamRecordingServer) Send(m *AudioChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AudioService_StartRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).StartRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/StartRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).StartRecording(ctx, req.(*StartRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AudioService_StopRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).StopRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/StopRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).StopRecording(ctx, req.(*StopRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// AudioServiceServer is the server API for AudioService service.
-// All implementations must embed UnimplementedAudioServiceServer
-// for forward compatibility
-type AudioServiceServer interface {
-	GetAudio(*GetAudioRequest, AudioService_GetAudioServer) error
-	Play(context.Context, *PlayRequest) (*PlayResponse, error)
-	Properties(context.Context, *PropertiesRequest) (*PropertiesResponse, error)
-	mustEmbedUnimplementedAudioServiceServer()
+func _AudioService_ListSegments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSegmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ListSegments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ListSegments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ListSegments(ctx, req.(*ListSegmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedAudioServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedAudioServiceServer struct {
+func _AudioService_StartRecordingSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRecordingSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).StartRecordingSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/StartRecordingSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).StartRecordingSession(ctx, req.(*StartRecordingSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedAudioServiceServer) GetAudio(*GetAudioRequest, AudioService_GetAudioServer) error {
-	return status.Errorf(codes.Unimplemented, "method GetAudio not implemented")
+func _AudioService_StopRecordingSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRecordingSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).StopRecordingSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/StopRecordingSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).StopRecordingSession(ctx, req.(*StopRecordingSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedAudioServiceServer) Play(context.Context, *PlayRequest) (*PlayResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Play not implemented")
+
+func _AudioService_GetRecordingSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordingSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetRecordingSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetRecordingSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetRecordingSession(ctx, req.(*GetRecordingSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedAudioServiceServer) Properties(context.Context, *PropertiesRequest) (*PropertiesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Properties not implemented")
+
+func _AudioService_ScheduleRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ScheduleRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ScheduleRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ScheduleRecording(ctx, req.(*ScheduleRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedAudioServiceServer) mustEmbedUnimplementedAudioServiceServer() {}
 
-// UnsafeAudioServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to AudioServiceServer will
-// result in compilation errors.
-type UnsafeAudioServiceServer interface {
-	mustEmbedUnimplementedAudioServiceServer()
+func _AudioService_CancelScheduledRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScheduledRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).CancelScheduledRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/CancelScheduledRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).CancelScheduledRecording(ctx, req.(*CancelScheduledRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterAudioServiceServer(s grpc.ServiceRegistrar, srv AudioServiceServer) {
-	s.RegisterService(&AudioService_ServiceDesc, srv)
+func _AudioService_GetTriggerState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTriggerStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetTriggerState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetTriggerState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetTriggerState(ctx, req.(*GetTriggerStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _AudioService_GetAudio_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetAudioRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _AudioService_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(AudioServiceServer).GetAudio(m, &audioServiceGetAudioServer{stream})
+	if interceptor == nil {
+		return srv.(AudioServiceServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/ListDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).ListDevices(ctx, req.(*ListDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type AudioService_GetAudioServer interface {
-	Send(*AudioChunk) error
-	grpc.ServerStream
+func _AudioService_GetLatencyStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatencyStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetLatencyStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetLatencyStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetLatencyStats(ctx, req.(*GetLatencyStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type audioServiceGetAudioServer struct {
-	grpc.ServerStream
+func _AudioService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *audioServiceGetAudioServer) Send(m *AudioChunk) error {
-	return x.ServerStream.SendMsg(m)
+func _AudioService_GetHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AudioServiceServer).GetHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AudioService/GetHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AudioServiceServer).GetHealth(ctx, req.(*GetHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _AudioService_Play_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PlayRequest)
+func _AudioService_DoCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoCommandRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AudioServiceServer).Play(ctx, in)
+		return srv.(AudioServiceServer).DoCommand(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/AudioService/Play",
+		FullMethod: "/AudioService/DoCommand",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AudioServiceServer).Play(ctx, req.(*PlayRequest))
+		return srv.(AudioServiceServer).DoCommand(ctx, req.(*DoCommandRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -189,6 +1509,142 @@ var AudioService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Play",
 			Handler:    _AudioService_Play_Handler,
 		},
+		{
+			MethodName: "PauseStream",
+			Handler:    _AudioService_PauseStream_Handler,
+		},
+		{
+			MethodName: "ResumeStream",
+			Handler:    _AudioService_ResumeStream_Handler,
+		},
+		{
+			MethodName: "PreparePlayback",
+			Handler:    _AudioService_PreparePlayback_Handler,
+		},
+		{
+			MethodName: "CommitPlayback",
+			Handler:    _AudioService_CommitPlayback_Handler,
+		},
+		{
+			MethodName: "ReleasePlayback",
+			Handler:    _AudioService_ReleasePlayback_Handler,
+		},
+		{
+			MethodName: "SetProfile",
+			Handler:    _AudioService_SetProfile_Handler,
+		},
+		{
+			MethodName: "GetProfile",
+			Handler:    _AudioService_GetProfile_Handler,
+		},
+		{
+			MethodName: "SetEQ",
+			Handler:    _AudioService_SetEQ_Handler,
+		},
+		{
+			MethodName: "GetEQ",
+			Handler:    _AudioService_GetEQ_Handler,
+		},
+		{
+			MethodName: "GetLevels",
+			Handler:    _AudioService_GetLevels_Handler,
+		},
+		{
+			MethodName: "GetSpectrogram",
+			Handler:    _AudioService_GetSpectrogram_Handler,
+		},
+		{
+			MethodName: "CaptureClip",
+			Handler:    _AudioService_CaptureClip_Handler,
+		},
+		{
+			MethodName: "GetLevelStats",
+			Handler:    _AudioService_GetLevelStats_Handler,
+		},
+		{
+			MethodName: "ListStreamHistory",
+			Handler:    _AudioService_ListStreamHistory_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _AudioService_ListEvents_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _AudioService_ListAuditLog_Handler,
+		},
+		{
+			MethodName: "ListActiveStreams",
+			Handler:    _AudioService_ListActiveStreams_Handler,
+		},
+		{
+			MethodName: "ListRecordings",
+			Handler:    _AudioService_ListRecordings_Handler,
+		},
+		{
+			MethodName: "GetRecordingStats",
+			Handler:    _AudioService_GetRecordingStats_Handler,
+		},
+		{
+			MethodName: "GetRecording",
+			Handler:    _AudioService_GetRecording_Handler,
+		},
+		{
+			MethodName: "StartRecording",
+			Handler:    _AudioService_StartRecording_Handler,
+		},
+		{
+			MethodName: "StopRecording",
+			Handler:    _AudioService_StopRecording_Handler,
+		},
+		{
+			MethodName: "ListSegments",
+			Handler:    _AudioService_ListSegments_Handler,
+		},
+		{
+			MethodName: "StartRecordingSession",
+			Handler:    _AudioService_StartRecordingSession_Handler,
+		},
+		{
+			MethodName: "StopRecordingSession",
+			Handler:    _AudioService_StopRecordingSession_Handler,
+		},
+		{
+			MethodName: "GetRecordingSession",
+			Handler:    _AudioService_GetRecordingSession_Handler,
+		},
+		{
+			MethodName: "ScheduleRecording",
+			Handler:    _AudioService_ScheduleRecording_Handler,
+		},
+		{
+			MethodName: "CancelScheduledRecording",
+			Handler:    _AudioService_CancelScheduledRecording_Handler,
+		},
+		{
+			MethodName: "GetTriggerState",
+			Handler:    _AudioService_GetTriggerState_Handler,
+		},
+		{
+			MethodName: "ListDevices",
+			Handler:    _AudioService_ListDevices_Handler,
+		},
+		{
+			MethodName: "GetLatencyStats",
+			Handler:    _AudioService_GetLatencyStats_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _AudioService_GetStats_Handler,
+		},
+		{
+			MethodName: "GetHealth",
+			Handler:    _AudioService_GetHealth_Handler,
+		},
+		{
+			MethodName: "DoCommand",
+			Handler:    _AudioService_DoCommand_Handler,
+		},
 		{
 			MethodName: "Properties",
 			Handler:    _AudioService_Properties_Handler,
@@ -200,6 +1656,26 @@ var AudioService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _AudioService_GetAudio_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamLevels",
+			Handler:       _AudioService_StreamLevels_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamSpectrum",
+			Handler:       _AudioService_StreamSpectrum_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamImpulses",
+			Handler:       _AudioService_StreamImpulses_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamRecording",
+			Handler:       _AudioService_StreamRecording_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "audio.proto",
 }
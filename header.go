@@ -0,0 +1,119 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// StreamHeader describes the audio that follows it. GetAudio streams carry
+// one on their first chunk and again whenever the format changes, so clients
+// can set up decoders and containers without guessing. Codecs that need
+// out-of-band configuration put it in Extradata: the OpusHead packet for
+// opus, the STREAMINFO block for FLAC and the AudioSpecificConfig for AAC.
+// Streams requested WithChunkDuration report the frames in a full chunk in
+// ChunkFrames, which is zero when chunk sizes are up to the source.
+type StreamHeader struct {
+	Codec       string
+	SampleRate  int
+	Channels    int
+	Extradata   []byte
+	ChunkFrames int
+}
+
+// headerTracker decides which chunks of a stream carry a StreamHeader.
+// Implementations can attach their own header to a chunk, which is forwarded
+// as-is; otherwise one is built from the chunk's AudioInfo.
+type headerTracker struct {
+	codec string // requested codec, used when chunks don't report their format
+	last  *StreamHeader
+}
+
+// next returns the header to send with chunk, or nil if the previous one
+// still applies.
+func (t *headerTracker) next(chunk *AudioChunk) *StreamHeader {
+	h := chunk.Header
+	if h == nil {
+		h = &StreamHeader{Codec: t.codec}
+		if chunk.Info != nil {
+			h.Codec = chunk.Info.Format.String()
+			h.SampleRate, h.Channels = chunk.Info.SampleRate, chunk.Info.Channels
+		} else if t.last != nil {
+			return nil
+		}
+		// a chunk without a header of its own only says what its format is,
+		// so extradata an implementation attached earlier still applies
+		if t.last != nil && sameFormat(t.last, h) {
+			return nil
+		}
+		h.Extradata = defaultExtradata(h.Codec, h.SampleRate, h.Channels)
+	}
+	if t.last != nil && sameFormat(t.last, h) && bytes.Equal(t.last.Extradata, h.Extradata) {
+		return nil
+	}
+	t.last = h
+	return h
+}
+
+func sameFormat(a, b *StreamHeader) bool {
+	return a.Codec == b.Codec && a.SampleRate == b.SampleRate && a.Channels == b.Channels
+}
+
+// headerFor returns the header derived from info alone.
+func headerFor(info AudioInfo) *StreamHeader {
+	codec := info.Format.String()
+	return &StreamHeader{
+		Codec:      codec,
+		SampleRate: info.SampleRate,
+		Channels:   info.Channels,
+		Extradata:  defaultExtradata(codec, info.SampleRate, info.Channels),
+	}
+}
+
+// defaultExtradata builds the initialization data for codecs where it can be
+// derived from the format alone. FLAC's STREAMINFO depends on the encoder, so
+// implementations producing FLAC have to attach their own header.
+func defaultExtradata(codec string, sampleRate, channels int) []byte {
+	switch codec {
+	case "opus":
+		return opusHead(sampleRate, channels)
+	case "aac":
+		return aacAudioSpecificConfig(sampleRate, channels)
+	default:
+		return nil
+	}
+}
+
+// opusHead returns the identification header from RFC 7845 section 5.1 for
+// mono or stereo streams.
+func opusHead(sampleRate, channels int) []byte {
+	if channels < 1 || channels > 2 {
+		return nil
+	}
+	h := make([]byte, 19)
+	copy(h, "OpusHead")
+	h[8] = 1 // version
+	h[9] = byte(channels)
+	binary.LittleEndian.PutUint16(h[10:], 312) // pre-skip of the reference encoder
+	binary.LittleEndian.PutUint32(h[12:], uint32(sampleRate))
+	// output gain and channel mapping family stay zero
+	return h
+}
+
+var aacSampleRates = []int{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+
+// aacAudioSpecificConfig returns the two byte AAC-LC AudioSpecificConfig from
+// ISO 14496-3 section 1.6.2.1.
+func aacAudioSpecificConfig(sampleRate, channels int) []byte {
+	index := -1
+	for i, r := range aacSampleRates {
+		if r == sampleRate {
+			index = i
+		}
+	}
+	if index < 0 || channels < 1 || channels > 7 {
+		return nil
+	}
+	const aacLC = 2
+	asc := uint16(aacLC)<<11 | uint16(index)<<7 | uint16(channels)<<3
+	return []byte{byte(asc >> 8), byte(asc)}
+}
@@ -0,0 +1,94 @@
+package audio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHeaderTracker(t *testing.T) {
+	mono16k := &AudioInfo{Format: Pcm16, SampleRate: 16000, Channels: 1}
+	stereo16k := &AudioInfo{Format: Pcm16, SampleRate: 16000, Channels: 2}
+	initData := []byte("encoder init")
+	mp3 := &AudioInfo{Format: Mp3, SampleRate: 16000, Channels: 1}
+
+	tr := &headerTracker{codec: "pcm16"}
+	for i, step := range []struct {
+		chunk *AudioChunk
+		want  *StreamHeader
+	}{
+		{&AudioChunk{Info: mono16k}, &StreamHeader{Codec: "pcm16", SampleRate: 16000, Channels: 1}},
+		{&AudioChunk{Info: mono16k}, nil},
+		{&AudioChunk{}, nil},
+		{&AudioChunk{Info: stereo16k}, &StreamHeader{Codec: "pcm16", SampleRate: 16000, Channels: 2}},
+		// an implementation's own header is forwarded with its extradata
+		{&AudioChunk{Info: mp3, Header: &StreamHeader{Codec: "mp3", SampleRate: 16000, Channels: 1, Extradata: initData}},
+			&StreamHeader{Codec: "mp3", SampleRate: 16000, Channels: 1, Extradata: initData}},
+		// and later chunks that only report the same format keep it
+		{&AudioChunk{Info: mp3}, nil},
+		{&AudioChunk{Info: mp3, Header: &StreamHeader{Codec: "mp3", SampleRate: 16000, Channels: 1, Extradata: initData}}, nil},
+		{&AudioChunk{Info: mp3, Header: &StreamHeader{Codec: "mp3", SampleRate: 16000, Channels: 1, Extradata: []byte("new")}},
+			&StreamHeader{Codec: "mp3", SampleRate: 16000, Channels: 1, Extradata: []byte("new")}},
+		{&AudioChunk{Info: mono16k}, &StreamHeader{Codec: "pcm16", SampleRate: 16000, Channels: 1}},
+	} {
+		got := tr.next(step.chunk)
+		switch {
+		case got == nil && step.want == nil:
+		case got == nil || step.want == nil || !sameFormat(got, step.want) || !bytes.Equal(got.Extradata, step.want.Extradata):
+			t.Fatalf("step %d: got header %+v, want %+v", i, got, step.want)
+		}
+	}
+}
+
+func TestHeaderTrackerWithoutInfo(t *testing.T) {
+	tr := &headerTracker{codec: "mp3"}
+	if h := tr.next(&AudioChunk{}); h == nil || h.Codec != "mp3" {
+		t.Fatalf("first chunk got header %+v, want the requested codec", h)
+	}
+	if h := tr.next(&AudioChunk{}); h != nil {
+		t.Fatalf("got a second header %+v", h)
+	}
+}
+
+func TestDefaultExtradata(t *testing.T) {
+	head := defaultExtradata("opus", 48000, 2)
+	want := []byte{'O', 'p', 'u', 's', 'H', 'e', 'a', 'd', 1, 2, 0x38, 0x01, 0x80, 0xbb, 0, 0, 0, 0, 0}
+	if !bytes.Equal(head, want) {
+		t.Fatalf("OpusHead\n got % x\nwant % x", head, want)
+	}
+	for _, tc := range []struct {
+		rate, channels int
+		want           []byte
+	}{
+		{44100, 2, []byte{0x12, 0x10}},
+		{48000, 2, []byte{0x11, 0x90}},
+		{16000, 1, []byte{0x14, 0x08}},
+		{44000, 2, nil},
+	} {
+		if got := defaultExtradata("aac", tc.rate, tc.channels); !bytes.Equal(got, tc.want) {
+			t.Errorf("AudioSpecificConfig for %d Hz, %d channels: got % x, want % x", tc.rate, tc.channels, got, tc.want)
+		}
+	}
+	if got := defaultExtradata("pcm16", 48000, 2); got != nil {
+		t.Fatalf("pcm16 got extradata % x", got)
+	}
+}
+
+func TestTranscoderConvertsHeader(t *testing.T) {
+	tc := newTranscoder(AudioInfo{Format: Pcm32Float, SampleRate: 16000})
+	src := AudioInfo{Format: Pcm16, SampleRate: 48000, Channels: 1}
+	out, err := tc.convert(&AudioChunk{AudioData: make([]byte, 960), Info: &src, Header: headerFor(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &StreamHeader{Codec: Pcm32Float.String(), SampleRate: 16000, Channels: 1}
+	if out.Header == nil || !sameFormat(out.Header, want) {
+		t.Fatalf("got header %+v, want %+v", out.Header, want)
+	}
+	out, err = tc.convert(&AudioChunk{AudioData: make([]byte, 960), Info: &src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Header != nil {
+		t.Fatalf("a chunk without a header got %+v", out.Header)
+	}
+}
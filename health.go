@@ -0,0 +1,149 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// States of a DeviceHealth.
+const (
+	DeviceHealthy = "healthy"
+	// DeviceRecovering is a device that was lost, unplugged or stuck in
+	// xruns, and is being opened again.
+	DeviceRecovering = "recovering"
+	// DeviceUnavailable is a device that failed to open, or that couldn't be
+	// opened again after it was lost. The next stream or Play tries again.
+	DeviceUnavailable = "unavailable"
+	// DeviceOff is a direction the resource has no device for.
+	DeviceOff = "off"
+)
+
+// How a lost device is opened again: after deviceReopenWait, doubling up to
+// maxDeviceReopenWait, for up to maxDeviceReopens attempts before its
+// streams end.
+const (
+	deviceReopenWait    = 250 * time.Millisecond
+	maxDeviceReopenWait = 4 * time.Second
+	maxDeviceReopens    = 6
+	// deviceReopenPoll is how often a wait checks whether anything still
+	// uses the device.
+	deviceReopenPoll = 50 * time.Millisecond
+)
+
+// DeviceHealth is the state of one of a resource's devices, as last seen by
+// the streams and Play calls using it. Devices nothing uses aren't opened
+// to check them, as that would take them from other programs.
+type DeviceHealth struct {
+	State  string
+	Reason string    // why it isn't healthy, empty if it is
+	Since  time.Time // when it entered State, zero if it always was
+	// Reopens is the times the device has been opened again after it was
+	// lost.
+	Reopens int
+}
+
+// Health is the health of a resource's devices.
+type Health struct {
+	Capture, Playback DeviceHealth
+}
+
+// Ready reports whether none of the devices is recovering or unavailable.
+func (h Health) Ready() bool {
+	for _, d := range []DeviceHealth{h.Capture, h.Playback} {
+		if d.State == DeviceRecovering || d.State == DeviceUnavailable {
+			return false
+		}
+	}
+	return true
+}
+
+// HealthChecker is implemented by resources that track the health of their
+// devices, as the pcm backends do, and by clients of servers that report
+// it.
+type HealthChecker interface {
+	Health(ctx context.Context) (Health, error)
+}
+
+// deviceHealth tracks the health of a device. The zero value is healthy.
+type deviceHealth struct {
+	mu sync.Mutex
+	h  DeviceHealth
+}
+
+// set moves the device to state, for reason, and returns the state it was
+// in.
+func (d *deviceHealth) set(state, reason string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	was := d.h.State
+	if was == "" {
+		was = DeviceHealthy
+	}
+	if was != state {
+		d.h.State, d.h.Since = state, time.Now()
+	}
+	d.h.Reason = reason
+	return was
+}
+
+func (d *deviceHealth) reopening() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.h.Reopens++
+}
+
+// get returns the health of device, off if it's empty.
+func (d *deviceHealth) get(device string) DeviceHealth {
+	if device == "" {
+		return DeviceHealth{State: DeviceOff}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	h := d.h
+	if h.State == "" {
+		h.State = DeviceHealthy
+	}
+	return h
+}
+
+func (s *audioServer) GetHealth(ctx context.Context, req *pb.GetHealthRequest) (*pb.GetHealthResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	// resources that don't track their devices are taken to be healthy
+	h := Health{Capture: DeviceHealth{State: DeviceHealthy}, Playback: DeviceHealth{State: DeviceHealthy}}
+	if c, ok := a.(HealthChecker); ok {
+		if h, err = c.Health(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.GetHealthResponse{Ready: h.Ready(), Capture: deviceHealthToProto(h.Capture), Playback: deviceHealthToProto(h.Playback)}, nil
+}
+
+func deviceHealthToProto(h DeviceHealth) *pb.DeviceHealth {
+	d := &pb.DeviceHealth{State: h.State, Reason: h.Reason, ReopenAttempts: int32(h.Reopens)}
+	if !h.Since.IsZero() {
+		d.SinceNanoseconds = h.Since.UnixNano()
+	}
+	return d
+}
+
+func deviceHealthFromProto(d *pb.DeviceHealth) DeviceHealth {
+	h := DeviceHealth{State: d.GetState(), Reason: d.GetReason(), Reopens: int(d.GetReopenAttempts())}
+	if d.GetSinceNanoseconds() != 0 {
+		h.Since = time.Unix(0, d.GetSinceNanoseconds())
+	}
+	return h
+}
+
+func (c *audioClient) Health(ctx context.Context) (Health, error) {
+	resp, err := c.client.GetHealth(ctx, &pb.GetHealthRequest{Name: c.name})
+	if err != nil {
+		return Health{}, err
+	}
+	return Health{Capture: deviceHealthFromProto(resp.Capture), Playback: deviceHealthFromProto(resp.Playback)}, nil
+}
@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestDeviceRecovery(t *testing.T) {
+	var mu sync.Mutex
+	opens := map[bool]int{}
+	unplugged := make(chan struct{})
+	prev := openALSA
+	openALSA = func(name string, capture bool, p pcmParams) (pcmDevice, error) {
+		mu.Lock()
+		opens[capture]++
+		n := opens[capture]
+		mu.Unlock()
+		f := &fakePCM{p: p, period: time.Duration(p.periodFrames) * time.Second / time.Duration(p.rate), fail: map[int]bool{}}
+		switch {
+		case n == 1:
+			// unplugged during the third transfer
+			f.fail[3], f.lost = true, true
+		case n == 2 && capture:
+			<-unplugged
+			return nil, syscall.ENODEV
+		}
+		return f, nil
+	}
+	defer func() { openALSA = prev }()
+	a, err := NewALSA(Named("usb"), ALSAConfig{CaptureDevice: "hw:1,0", PlaybackDevice: "hw:1,0", SampleRate: 8000, PeriodFrames: 80}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	a.(*pcmAudio).reopenWait = 10 * time.Millisecond
+	c := serveAudio(t, a)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	h, err := c.(HealthChecker).Health(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Capture.State != DeviceHealthy || h.Playback.State != DeviceHealthy || !h.Ready() {
+		t.Errorf("health before use %+v", h)
+	}
+
+	ch, err := c.GetAudio(ctx, Pcm16.String(), 0.2, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the stream waits while the device is opened again
+	for {
+		if h, err = c.(HealthChecker).Health(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if h.Capture.State == DeviceRecovering {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if h.Ready() || h.Capture.Reason == "" || h.Capture.Since.IsZero() {
+		t.Errorf("health while recovering %+v", h)
+	}
+	close(unplugged)
+	var n int
+	var gap time.Duration
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		gap += chunk.Gap
+		n++
+	}
+	if n != 20 || gap < 10*time.Millisecond {
+		t.Errorf("got %d chunks with a %v gap, want 20 with the outage as a gap", n, gap)
+	}
+	h, _ = c.(HealthChecker).Health(ctx)
+	if h.Capture.State != DeviceHealthy || h.Capture.Reopens != 2 || !h.Ready() {
+		t.Errorf("health after recovering %+v", h)
+	}
+
+	// playback is opened again in the background for the next Play
+	clip, _ := encodePCM(tone(8000, 800, 440, 0.5), Pcm16)
+	if err := c.Play(ctx, clip, Pcm16.String(), 8000, 1); err == nil {
+		t.Fatal("playing on the unplugged device succeeded")
+	}
+	for {
+		if h, _ = c.(HealthChecker).Health(ctx); h.Playback.State == DeviceHealthy {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if h.Playback.Reopens != 1 {
+		t.Errorf("health after recovering playback %+v", h)
+	}
+	if err := c.Play(ctx, clip, Pcm16.String(), 8000, 1); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if opens[false] != 2 {
+		t.Errorf("opened playback %d times, want 2", opens[false])
+	}
+}
+
+func TestHealthOfUntrackedResources(t *testing.T) {
+	f, err := NewFake(Named("untracked"), FakeConfig{}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(context.Background())
+	h, err := serveAudio(t, f).(HealthChecker).Health(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Capture.State != DeviceHealthy || h.Playback.State != DeviceHealthy || !h.Ready() {
+		t.Errorf("health %+v", h)
+	}
+}
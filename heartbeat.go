@@ -0,0 +1,153 @@
+package audio
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// The heartbeat intervals WithHeartbeat accepts, and how many heartbeats
+// the client lets pass without a message before it gives up on the link.
+const (
+	minHeartbeat    = time.Second
+	maxHeartbeat    = 5 * time.Minute
+	heartbeatMisses = 3
+)
+
+func checkHeartbeat(d time.Duration) error {
+	if d != 0 && (d < minHeartbeat || d > maxHeartbeat) {
+		return errorf(ErrInvalidArgument, "heartbeat must be from %v to %v, got %v", minHeartbeat, maxHeartbeat, d)
+	}
+	return nil
+}
+
+// heartbeat times the empty messages a GetAudio stream sends when it has
+// sent nothing for its interval.
+type heartbeat struct {
+	interval time.Duration
+	timer    *time.Timer
+}
+
+func newHeartbeat(interval time.Duration) *heartbeat {
+	h := &heartbeat{interval: interval}
+	if interval > 0 {
+		h.timer = time.NewTimer(interval)
+	}
+	return h
+}
+
+// due fires when a heartbeat is to be sent, never if the stream has none.
+func (h *heartbeat) due() <-chan time.Time {
+	if h.timer == nil {
+		return nil
+	}
+	return h.timer.C
+}
+
+// sent restarts the interval after a message.
+func (h *heartbeat) sent() {
+	if h.timer != nil {
+		h.timer.Reset(h.interval)
+	}
+}
+
+func (h *heartbeat) stop() {
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+}
+
+// message returns the heartbeat to send.
+func (h *heartbeat) message() *pb.AudioChunk {
+	return &pb.AudioChunk{Heartbeat: true, SentTimestampNanoseconds: time.Now().UnixNano()}
+}
+
+// heartbeatWatch ends a stream whose server has sent nothing, heartbeats
+// included, for heartbeatMisses intervals, as happens when a middlebox
+// drops the connection without either end noticing.
+type heartbeatWatch struct {
+	timeout time.Duration
+	cancel  context.CancelFunc
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+// watchHeartbeat returns the context to open a stream heartbeating every
+// interval with, and its watch, nil if interval is zero.
+func watchHeartbeat(ctx context.Context, interval time.Duration) (context.Context, *heartbeatWatch) {
+	if interval == 0 {
+		return ctx, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := &heartbeatWatch{timeout: heartbeatMisses * interval, cancel: cancel}
+	w.timer = time.AfterFunc(w.timeout, func() {
+		w.expired.Store(true)
+		cancel()
+	})
+	return ctx, w
+}
+
+// received restarts the watch after a message.
+func (w *heartbeatWatch) received() {
+	if w != nil {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+// err is the error the stream ended with, Unavailable if the watch ended it.
+func (w *heartbeatWatch) err(err error) error {
+	if w == nil || err == nil || !w.expired.Load() {
+		return err
+	}
+	return status.Errorf(codes.Unavailable, "no message from the server for %v", w.timeout)
+}
+
+// stop ends the watch and its stream.
+func (w *heartbeatWatch) stop() {
+	if w != nil {
+		w.timer.Stop()
+		w.cancel()
+	}
+}
+
+// watchedStream is a GetAudio stream that heartbeats, which it keeps from
+// its receiver.
+type watchedStream struct {
+	pb.AudioService_GetAudioClient
+	watch *heartbeatWatch
+}
+
+// openWatched opens a GetAudio stream for req, watched if it heartbeats
+// every interval.
+func openWatched(ctx context.Context, client pb.AudioServiceClient, req *pb.GetAudioRequest, interval time.Duration) (pb.AudioService_GetAudioClient, error) {
+	ctx, watch := watchHeartbeat(ctx, interval)
+	stream, err := client.GetAudio(ctx, req, pooledChunks)
+	if err != nil {
+		watch.stop()
+		return nil, err
+	}
+	if watch == nil {
+		return stream, nil
+	}
+	return &watchedStream{AudioService_GetAudioClient: stream, watch: watch}, nil
+}
+
+func (s *watchedStream) Recv() (*pb.AudioChunk, error) {
+	for {
+		msg, err := s.AudioService_GetAudioClient.Recv()
+		if err != nil {
+			err = s.watch.err(err)
+			s.watch.stop()
+			return nil, err
+		}
+		s.watch.received()
+		if !msg.Heartbeat {
+			return msg, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// heartbeatCounter counts the heartbeats streams receive.
+type heartbeatCounter struct {
+	grpc.ClientStream
+	n *atomic.Int32
+}
+
+func (s heartbeatCounter) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if msg, ok := m.(*pb.AudioChunk); ok && err == nil && msg.Heartbeat {
+		s.n.Add(1)
+	}
+	return err
+}
+
+func TestHeartbeatStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(3, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	var beats atomic.Int32
+	c := serveAudio(t, src, WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		return heartbeatCounter{ClientStream: s, n: &beats}, err
+	}))
+
+	// a quiet stream heartbeats, but only its audio is delivered
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithHeartbeat(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2500 * time.Millisecond)
+	close(src.start)
+	if chunks, _, _ := drain(t, ch); chunks != src.n {
+		t.Errorf("got %d chunks, want %d", chunks, src.n)
+	}
+	if n := beats.Load(); n < 1 || n > 3 {
+		t.Errorf("got %d heartbeats in 2.5s, want about 2", n)
+	}
+
+	ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0, WithHeartbeat(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || chunk.Err == nil {
+		t.Error("heartbeat more often than allowed")
+	}
+}
+
+func TestHeartbeatWatch(t *testing.T) {
+	ctx, w := watchHeartbeat(context.Background(), 10*time.Millisecond)
+	defer w.stop()
+	// every message puts the deadline off
+	for range 5 {
+		time.Sleep(20 * time.Millisecond)
+		w.received()
+	}
+	if ctx.Err() != nil {
+		t.Fatal("gave up on a server that kept sending")
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("kept waiting on a quiet server")
+	}
+	if err := w.err(errors.New("canceled")); status.Code(err) != codes.Unavailable {
+		t.Errorf("a quiet server ended the stream with %v", err)
+	}
+
+	if ctx, w := watchHeartbeat(context.Background(), 0); w != nil || ctx != context.Background() {
+		t.Error("watched a stream without heartbeats")
+	}
+}
@@ -0,0 +1,389 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Defaults for HistoryConfig.
+const (
+	defaultHistoryCapacity   = 1000
+	defaultHistorySpillBytes = 64 << 20
+)
+
+// HistoryConfig bounds what a History keeps of each kind of entry.
+type HistoryConfig struct {
+	Capacity int // entries kept in memory, 1000 if zero
+	// SpillDir, if set, keeps entries pushed out of memory in files there,
+	// where they can still be listed. Otherwise they are dropped.
+	SpillDir string
+	// SpillBytes caps the files of each kind, 64 MiB if zero. When they are
+	// full the older half is dropped.
+	SpillBytes int64
+}
+
+// StreamRecord is a GetAudio stream or Play call that has ended.
+type StreamRecord struct {
+	Resource  string
+	Direction string // "capture" or "playback"
+	Codec     string
+	Profile   string
+	RequestID string
+	Subject   string // the caller's identity when the server authenticates
+	Start     time.Time
+	End       time.Time
+	Bytes     int64
+	Messages  int64
+	Dropped   int64  // chunks dropped because the client fell behind
+	Err       string // empty if it ended cleanly
+}
+
+// EventRecord is an event reported to a client.
+type EventRecord struct {
+	Resource  string
+	Kind      string // "impulse" or "level"
+	Timestamp time.Time
+	Duration  time.Duration
+	PeakDBFS  float64
+	Clip      string // saved in the server's recording store, if any
+}
+
+// HistoryLister is implemented by clients of servers that keep a history of
+// their resources' streams and events. Pages are newest first; an empty
+// token starts from the newest and an empty next token ends the history.
+type HistoryLister interface {
+	ListStreamHistory(ctx context.Context, pageSize int, pageToken string, filter StreamFilter) ([]StreamRecord, string, error)
+	ListEvents(ctx context.Context, pageSize int, pageToken string, filter EventFilter) ([]EventRecord, string, error)
+}
+
+// History is what a server remembers of past streams and events, in memory
+// up to a fixed number of each and optionally on disk up to a fixed size, so
+// it doesn't grow however long the server runs.
+type History struct {
+	streams *historyLog[StreamRecord]
+	events  *historyLog[EventRecord]
+}
+
+// ServerHistory is the history the RPC server records into. Entries pushed
+// out of memory spill to AUDIO_HISTORY_DIR if it is set.
+var ServerHistory = NewHistory(HistoryConfig{SpillDir: os.Getenv("AUDIO_HISTORY_DIR")})
+
+// NewHistory returns an empty history, or one continuing from the files in
+// cfg.SpillDir.
+func NewHistory(cfg HistoryConfig) *History {
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = defaultHistoryCapacity
+	}
+	if cfg.SpillBytes <= 0 {
+		cfg.SpillBytes = defaultHistorySpillBytes
+	}
+	return &History{
+		streams: newHistoryLog[StreamRecord](cfg, "streams"),
+		events:  newHistoryLog[EventRecord](cfg, "events"),
+	}
+}
+
+func (h *History) recordStream(r StreamRecord) { h.streams.add(r) }
+
+func (h *History) recordEvent(e EventRecord) { h.events.add(e) }
+
+// historyEntry is an entry and its number, which counts up from the first
+// entry ever spilled to the same files.
+type historyEntry[T any] struct {
+	Seq   uint64 `json:"seq"`
+	Entry T      `json:"entry"`
+}
+
+// historyLog is a ring of the newest entries of one kind, and a spill of
+// older ones in path and path.1.
+type historyLog[T any] struct {
+	spillBytes int64
+	path       string // empty when nothing is spilled
+
+	mu    sync.Mutex
+	ring  []historyEntry[T]
+	start int // oldest entry in ring
+	n     int
+	next  uint64 // number of the next entry
+
+	spillMu sync.Mutex // held while the files are written or read
+}
+
+func newHistoryLog[T any](cfg HistoryConfig, kind string) *historyLog[T] {
+	l := &historyLog[T]{spillBytes: cfg.SpillBytes, ring: make([]historyEntry[T], cfg.Capacity), next: 1}
+	if cfg.SpillDir != "" {
+		l.path = filepath.Join(cfg.SpillDir, kind+".history.jsonl")
+		// carry on numbering after the entries already spilled
+		l.scanSpill(func(e historyEntry[T]) { l.next = max(l.next, e.Seq+1) })
+	}
+	return l
+}
+
+func (l *historyLog[T]) add(v T) {
+	l.mu.Lock()
+	e := historyEntry[T]{Seq: l.next, Entry: v}
+	l.next++
+	if l.n < len(l.ring) {
+		l.ring[(l.start+l.n)%len(l.ring)] = e
+		l.n++
+		l.mu.Unlock()
+		return
+	}
+	evicted := l.ring[l.start]
+	l.ring[l.start] = e
+	l.start = (l.start + 1) % len(l.ring)
+	if l.path == "" {
+		l.mu.Unlock()
+		return
+	}
+	// spills are written in order, but without holding up the ring
+	l.spillMu.Lock()
+	l.mu.Unlock()
+	defer l.spillMu.Unlock()
+	// the history is best effort, an entry that can't be spilled is
+	// dropped like one without a spill
+	_ = l.spill(evicted)
+}
+
+// spill appends e to the spill, moving the file aside as path.1 once it
+// holds half of the allowed size. spillMu must be held.
+func (l *historyLog[T]) spill(e historyEntry[T]) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	_, werr := f.Write(append(b, '\n'))
+	info, serr := f.Stat()
+	if err := errors.Join(werr, serr, f.Close()); err != nil {
+		return err
+	}
+	if info.Size() >= l.spillBytes/2 {
+		return os.Rename(l.path, l.path+".1")
+	}
+	return nil
+}
+
+// scanSpill calls fn with the spilled entries, oldest first.
+func (l *historyLog[T]) scanSpill(fn func(historyEntry[T])) error {
+	l.spillMu.Lock()
+	defer l.spillMu.Unlock()
+	for _, path := range []string{l.path + ".1", l.path} {
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		sc := bufio.NewScanner(f)
+		sc.Buffer(nil, 1<<20)
+		for sc.Scan() {
+			var e historyEntry[T]
+			// a line cut short by a crash is skipped
+			if json.Unmarshal(sc.Bytes(), &e) == nil {
+				fn(e)
+			}
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// page returns up to size entries matching keep that are older than entry
+// before, or from the newest if before is 0, newest first. It also returns
+// where the next page starts, 0 if there are no older entries.
+func (l *historyLog[T]) page(before uint64, size int, keep func(T) bool) ([]T, uint64, error) {
+	var out []T
+	var last uint64
+	l.mu.Lock()
+	for i := l.n - 1; i >= 0 && len(out) < size; i-- {
+		e := l.ring[(l.start+i)%len(l.ring)]
+		if (before == 0 || e.Seq < before) && keep(e.Entry) {
+			out = append(out, e.Entry)
+			last = e.Seq
+		}
+	}
+	oldest := l.next
+	if l.n > 0 {
+		oldest = l.ring[l.start].Seq
+	}
+	l.mu.Unlock()
+	if len(out) == size {
+		return out, last, nil
+	}
+	if l.path == "" {
+		return out, 0, nil
+	}
+
+	// the rest comes from the spill, older than anything in memory
+	if before == 0 || before > oldest {
+		before = oldest
+	}
+	want := size - len(out)
+	var window []historyEntry[T] // the newest matches so far, oldest first
+	err := l.scanSpill(func(e historyEntry[T]) {
+		if e.Seq < before && keep(e.Entry) {
+			if window = append(window, e); len(window) > want {
+				window = window[1:]
+			}
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := len(window) - 1; i >= 0; i-- {
+		out = append(out, window[i].Entry)
+	}
+	if len(out) < size {
+		return out, 0, nil
+	}
+	return out, window[0].Seq, nil
+}
+
+func streamRecordToProto(r StreamRecord) *pb.StreamRecord {
+	return &pb.StreamRecord{
+		Direction:        r.Direction,
+		Codec:            r.Codec,
+		Profile:          r.Profile,
+		RequestId:        r.RequestID,
+		Subject:          r.Subject,
+		StartNanoseconds: toUnixNano(r.Start),
+		EndNanoseconds:   toUnixNano(r.End),
+		Bytes:            r.Bytes,
+		Messages:         r.Messages,
+		DroppedChunks:    r.Dropped,
+		Error:            r.Err,
+	}
+}
+
+func streamRecordFromProto(resource string, r *pb.StreamRecord) StreamRecord {
+	return StreamRecord{
+		Resource:  resource,
+		Direction: r.Direction,
+		Codec:     r.Codec,
+		Profile:   r.Profile,
+		RequestID: r.RequestId,
+		Subject:   r.Subject,
+		Start:     fromUnixNano(r.StartNanoseconds),
+		End:       fromUnixNano(r.EndNanoseconds),
+		Bytes:     r.Bytes,
+		Messages:  r.Messages,
+		Dropped:   r.DroppedChunks,
+		Err:       r.Error,
+	}
+}
+
+func (s *audioServer) ListStreamHistory(ctx context.Context, req *pb.ListHistoryRequest) (*pb.ListStreamHistoryResponse, error) {
+	before, size, err := seqPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDirection(req.Direction); err != nil {
+		return nil, err
+	}
+	filter := StreamFilter{
+		Direction: req.Direction,
+		RequestID: req.RequestId,
+		Subject:   req.Subject,
+		After:     fromUnixNano(req.AfterNanoseconds),
+		Before:    fromUnixNano(req.BeforeNanoseconds),
+	}
+	records, next, err := ServerHistory.streams.page(before, size, func(r StreamRecord) bool { return r.Resource == req.Name && filter.match(r) })
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListStreamHistoryResponse{Records: make([]*pb.StreamRecord, len(records)), NextPageToken: seqPageToken(next)}
+	for i, r := range records {
+		resp.Records[i] = streamRecordToProto(r)
+	}
+	return resp, nil
+}
+
+func (s *audioServer) ListEvents(ctx context.Context, req *pb.ListHistoryRequest) (*pb.ListEventsResponse, error) {
+	before, size, err := seqPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	filter := EventFilter{Kind: req.Kind, After: fromUnixNano(req.AfterNanoseconds), Before: fromUnixNano(req.BeforeNanoseconds)}
+	events, next, err := ServerHistory.events.page(before, size, func(e EventRecord) bool { return e.Resource == req.Name && filter.match(e) })
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListEventsResponse{Events: make([]*pb.EventRecord, len(events)), NextPageToken: seqPageToken(next)}
+	for i, e := range events {
+		resp.Events[i] = &pb.EventRecord{
+			Kind:                 e.Kind,
+			TimestampNanoseconds: toUnixNano(e.Timestamp),
+			DurationSeconds:      float32(e.Duration.Seconds()),
+			PeakDbfs:             float32(e.PeakDBFS),
+			Clip:                 e.Clip,
+		}
+	}
+	return resp, nil
+}
+
+func (c *audioClient) ListStreamHistory(ctx context.Context, pageSize int, pageToken string, filter StreamFilter) ([]StreamRecord, string, error) {
+	resp, err := c.client.ListStreamHistory(ctx, &pb.ListHistoryRequest{
+		Name:              c.name,
+		PageSize:          int32(pageSize),
+		PageToken:         pageToken,
+		Direction:         filter.Direction,
+		RequestId:         filter.RequestID,
+		Subject:           filter.Subject,
+		AfterNanoseconds:  toUnixNano(filter.After),
+		BeforeNanoseconds: toUnixNano(filter.Before),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	records := make([]StreamRecord, len(resp.Records))
+	for i, r := range resp.Records {
+		records[i] = streamRecordFromProto(c.name, r)
+	}
+	return records, resp.NextPageToken, nil
+}
+
+func (c *audioClient) ListEvents(ctx context.Context, pageSize int, pageToken string, filter EventFilter) ([]EventRecord, string, error) {
+	resp, err := c.client.ListEvents(ctx, &pb.ListHistoryRequest{
+		Name:              c.name,
+		PageSize:          int32(pageSize),
+		PageToken:         pageToken,
+		Kind:              filter.Kind,
+		AfterNanoseconds:  toUnixNano(filter.After),
+		BeforeNanoseconds: toUnixNano(filter.Before),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	events := make([]EventRecord, len(resp.Events))
+	for i, e := range resp.Events {
+		events[i] = EventRecord{
+			Resource:  c.name,
+			Kind:      e.Kind,
+			Timestamp: fromUnixNano(e.TimestampNanoseconds),
+			Duration:  time.Duration(float64(e.DurationSeconds) * float64(time.Second)),
+			PeakDBFS:  float64(e.PeakDbfs),
+			Clip:      e.Clip,
+		}
+	}
+	return events, resp.NextPageToken, nil
+}
@@ -0,0 +1,137 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// listAll pages through the history of resource, checking the pages come
+// newest first.
+func listAll(t *testing.T, l *historyLog[EventRecord], resource string, size int) []EventRecord {
+	t.Helper()
+	var all []EventRecord
+	var before uint64
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatal("paging never ends")
+		}
+		page, next, err := l.page(before, size, func(e EventRecord) bool { return e.Resource == resource })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) > size {
+			t.Fatalf("got a page of %d, want at most %d", len(page), size)
+		}
+		all = append(all, page...)
+		if next == 0 {
+			return all
+		}
+		before = next
+	}
+}
+
+func checkNewestFirst(t *testing.T, events []EventRecord, newest, n int) {
+	t.Helper()
+	if len(events) != n {
+		t.Fatalf("listed %d events, want %d", len(events), n)
+	}
+	for i, e := range events {
+		if want := fmt.Sprint(newest - 2*i); e.Clip != want {
+			t.Fatalf("event %d is %s, want %s", i, e.Clip, want)
+		}
+	}
+}
+
+func TestHistoryIsBounded(t *testing.T) {
+	// events 0 to 49 alternate between two resources
+	add := func(h *History, from, to int) {
+		for i := from; i < to; i++ {
+			h.recordEvent(EventRecord{Resource: []string{"a", "b"}[i%2], Kind: "impulse", Clip: fmt.Sprint(i)})
+		}
+	}
+
+	h := NewHistory(HistoryConfig{Capacity: 8})
+	add(h, 0, 50)
+	// only the last 8 are kept, 4 of them a's
+	checkNewestFirst(t, listAll(t, h.events, "a", 3), 48, 4)
+
+	dir := t.TempDir()
+	h = NewHistory(HistoryConfig{Capacity: 8, SpillDir: dir})
+	add(h, 0, 50)
+	checkNewestFirst(t, listAll(t, h.events, "a", 3), 48, 25)
+	checkNewestFirst(t, listAll(t, h.events, "b", 7), 49, 25)
+
+	// a restarted server lists what was spilled, and numbers after it
+	h = NewHistory(HistoryConfig{Capacity: 8, SpillDir: dir})
+	add(h, 50, 54)
+	events := listAll(t, h.events, "a", 10)
+	if events[0].Clip != "52" || events[1].Clip != "50" || events[2].Clip != "40" {
+		t.Errorf("after a restart listed %s, %s, %s first", events[0].Clip, events[1].Clip, events[2].Clip)
+	}
+
+	// the spill drops its older half when full
+	dir = t.TempDir()
+	h = NewHistory(HistoryConfig{Capacity: 8, SpillDir: dir, SpillBytes: 2000})
+	add(h, 0, 500)
+	events = listAll(t, h.events, "a", 50)
+	if len(events) < 4 || len(events) > 30 {
+		t.Fatalf("listed %d events from a 2000 byte spill", len(events))
+	}
+	checkNewestFirst(t, events, 498, len(events))
+}
+
+func TestListStreamHistory(t *testing.T) {
+	src := newBurstSource(3, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.Named = Named("history-mic").AsNamed()
+	c := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	close(src.start)
+	for _, id := range []string{"first", "second"} {
+		ch, err := c.GetAudio(ctx, Pcm16.String(), 0, 0, 0, WithRequestID(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		drain(t, ch)
+	}
+
+	lister := c.(HistoryLister)
+	var records []StreamRecord
+	deadline := time.Now().Add(5 * time.Second)
+	for len(records) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("listed %d streams, want 2", len(records))
+		}
+		time.Sleep(10 * time.Millisecond)
+		var err error
+		if records, _, err = lister.ListStreamHistory(ctx, 0, "", StreamFilter{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if records[0].RequestID != "second" || records[1].RequestID != "first" {
+		t.Fatalf("listed %+v", records)
+	}
+	r := records[1]
+	if r.Direction != "capture" || r.Codec != "pcm16" || r.Bytes != 3*160 || r.Messages != 3 || r.Err != "" || !r.End.After(r.Start) {
+		t.Errorf("recorded %+v", r)
+	}
+
+	page, next, err := lister.ListStreamHistory(ctx, 1, "", StreamFilter{})
+	if err != nil || len(page) != 1 || next == "" {
+		t.Fatalf("first page of one is %+v, next %q: %v", page, next, err)
+	}
+	if page, _, err = lister.ListStreamHistory(ctx, 1, next, StreamFilter{}); err != nil || len(page) != 1 || page[0].RequestID != "first" {
+		t.Fatalf("second page of one is %+v: %v", page, err)
+	}
+	if page, _, err = lister.ListStreamHistory(ctx, 0, "", StreamFilter{RequestID: "first", Direction: "capture"}); err != nil || len(page) != 1 || page[0].RequestID != "first" {
+		t.Errorf("filtered to %+v: %v", page, err)
+	}
+	if page, _, _ = lister.ListStreamHistory(ctx, 0, "", StreamFilter{After: time.Now()}); len(page) != 0 {
+		t.Errorf("listed %+v started in the future", page)
+	}
+	if _, _, err := lister.ListStreamHistory(ctx, 1, "nonsense", StreamFilter{}); err == nil {
+		t.Error("accepted a nonsense page token")
+	}
+}
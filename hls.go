@@ -0,0 +1,384 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"google.golang.org/grpc/peer"
+)
+
+// HLS packaging settings. Segments are short so monitoring latency stays a
+// few seconds; the playlist keeps enough of them for players to buffer.
+const (
+	hlsSegmentDuration = 2 * time.Second
+	hlsWindowSize      = 6
+	hlsPESDuration     = 200 * time.Millisecond
+	hlsIdleTimeout     = 30 * time.Second
+)
+
+// hlsServer packages live capture as HLS for the HTTP handler. A packager is
+// started for a resource on the first playlist request and stopped once no
+// player has asked for anything for hlsIdleTimeout.
+type hlsServer struct {
+	lookup func(name string) (Audio, error)
+	logger logging.Logger
+
+	mu        sync.Mutex
+	packagers map[string]*hlsPackager
+}
+
+func newHLSServer(lookup func(name string) (Audio, error), logger logging.Logger) *hlsServer {
+	return &hlsServer{lookup: lookup, logger: logger, packagers: map[string]*hlsPackager{}}
+}
+
+// packager returns the running packager for the named resource, starting one
+// if needed.
+func (s *hlsServer) packager(name string) (*hlsPackager, error) {
+	a, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.packagers[name]; ok && p.audio == a && !p.stopped() {
+		p.touch()
+		return p, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, err := sharedCaptureHub.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm32Float}})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	p := &hlsPackager{
+		name:       name,
+		audio:      a,
+		listeners:  map[string]context.Context{},
+		cancel:     cancel,
+		lastAccess: time.Now(),
+		updated:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	s.packagers[name] = p
+	go p.run(chunks, serverVerbosity.track("hls."+name, s.logger.Sublogger(name)))
+	go p.reapWhenIdle(ctx)
+	return p, nil
+}
+
+func (s *hlsServer) servePlaylist(w http.ResponseWriter, r *http.Request) {
+	p, err := s.packager(r.PathValue("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := p.listen(withPeer(r.Context(), httpRemoteAddr(r)), s.logger); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	// block until there is something to play rather than hand out an empty playlist
+	ctx, cancel := context.WithTimeout(r.Context(), 2*hlsSegmentDuration)
+	defer cancel()
+	playlist, err := p.playlist(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(playlist)
+}
+
+func (s *hlsServer) serveSegment(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	seq, err := strconv.Atoi(strings.TrimSuffix(r.PathValue("segment"), ".ts"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	p, ok := s.packagers[name]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	p.touch()
+	data, ok := p.segment(seq)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "video/mp2t")
+	w.Header().Set("Cache-Control", "max-age=60")
+	_, _ = w.Write(data)
+}
+
+type hlsSegment struct {
+	seq      int
+	duration time.Duration
+	data     []byte
+}
+
+// hlsPackager encodes one resource's capture to MP3 and cuts it into MPEG-TS
+// segments, keeping the most recent hlsWindowSize of them.
+type hlsPackager struct {
+	name   string
+	audio  Audio
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	listeners  map[string]context.Context // of each player's first request, by host
+	segments   []hlsSegment
+	nextSeq    int
+	lastAccess time.Time
+	err        error
+	updated    chan struct{} // closed and replaced whenever a segment is added
+	done       chan struct{}
+}
+
+func (p *hlsPackager) touch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastAccess = time.Now()
+}
+
+// listen records the first playlist request from each player's host in the
+// audit log, as the start of its listening, and refuses the request if it
+// can't be recorded.
+func (p *hlsPackager) listen(ctx context.Context, logger logging.Logger) error {
+	host := ""
+	if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
+		host, _, _ = net.SplitHostPort(pr.Addr.String())
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.listeners[host]; ok {
+		return nil
+	}
+	if err := auditOperation(ctx, logger, p.name, AuditCaptureStarted, "", "hls", nil); err != nil {
+		return err
+	}
+	p.listeners[host] = context.WithoutCancel(ctx)
+	return nil
+}
+
+// endListening records the end of every player's listening as the
+// packager stops.
+func (p *hlsPackager) endListening(logger logging.Logger) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, ctx := range p.listeners {
+		auditOperation(ctx, logger, p.name, AuditCaptureEnded, "", "hls", p.err)
+	}
+}
+
+func (p *hlsPackager) stopped() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// reapWhenIdle stops the packager once players stop polling it.
+func (p *hlsPackager) reapWhenIdle(ctx context.Context) {
+	ticker := time.NewTicker(hlsIdleTimeout / 6)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			idle := time.Since(p.lastAccess)
+			p.mu.Unlock()
+			if idle > hlsIdleTimeout {
+				p.cancel()
+				return
+			}
+		}
+	}
+}
+
+// playlist renders the live playlist, waiting for the first segment if there
+// isn't one yet.
+func (p *hlsPackager) playlist(ctx context.Context) ([]byte, error) {
+	for {
+		// checked before the segments, so the last segment of a capture that
+		// just ended is still seen
+		ended := p.stopped()
+		p.mu.Lock()
+		segments, updated, err := p.segments, p.updated, p.err
+		p.mu.Unlock()
+		if len(segments) > 0 {
+			return renderHLSPlaylist(segments), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ended {
+			return nil, errors.New("audio capture ended")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("no audio segments ready yet: %w", ctx.Err())
+		case <-p.done:
+		case <-updated:
+		}
+	}
+}
+
+func renderHLSPlaylist(segments []hlsSegment) []byte {
+	var target time.Duration
+	for _, seg := range segments {
+		target = max(target, seg.duration)
+	}
+	var b bytes.Buffer
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", int(math.Ceil(target.Seconds())))
+	fmt.Fprintf(&b, "#EXT-X-MEDIA-SEQUENCE:%d\n", segments[0].seq)
+	for _, seg := range segments {
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n%d.ts\n", seg.duration.Seconds(), seg.seq)
+	}
+	return b.Bytes()
+}
+
+func (p *hlsPackager) segment(seq int) ([]byte, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, seg := range p.segments {
+		if seg.seq == seq {
+			return seg.data, true
+		}
+	}
+	return nil, false
+}
+
+func (p *hlsPackager) addSegment(duration time.Duration, data []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.segments = append(p.segments, hlsSegment{seq: p.nextSeq, duration: duration, data: data})
+	p.nextSeq++
+	if len(p.segments) > hlsWindowSize {
+		p.segments = p.segments[len(p.segments)-hlsWindowSize:]
+	}
+	close(p.updated)
+	p.updated = make(chan struct{})
+}
+
+// run encodes the capture until it ends or the packager is stopped.
+func (p *hlsPackager) run(chunks <-chan *AudioChunk, logger logging.Logger) {
+	defer close(p.done)
+	defer p.endListening(logger)
+	defer p.cancel()
+
+	var (
+		enc        *mp3Encoder
+		inRate     int
+		inCh       int
+		seg        bytes.Buffer
+		mux        *tsMuxer
+		segFrames  int
+		pesData    []byte
+		pesFrames  int
+		pesPTS     int64
+		ptsBase    int64 // 90 kHz timestamp where the current encoder started
+		encSamples int64 // samples per channel encoded since then
+	)
+
+	// cut ends the current segment at a frame boundary
+	cut := func() error {
+		if len(pesData) > 0 {
+			if err := mux.writePES(pesPTS, pesData); err != nil {
+				return err
+			}
+			pesData, pesFrames = nil, 0
+		}
+		if segFrames > 0 {
+			p.addSegment(time.Duration(segFrames)*enc.frameDuration(), bytes.Clone(seg.Bytes()))
+			seg.Reset()
+			segFrames = 0
+		}
+		return nil
+	}
+
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			logger.Errorw("audio capture error while packaging hls", "error", chunk.Err)
+			p.mu.Lock()
+			p.err = chunk.Err
+			p.mu.Unlock()
+			return
+		}
+		info := chunk.Info
+		samples, err := decodePCM(chunk.AudioData, info.Format)
+		if err != nil {
+			continue
+		}
+
+		if enc == nil || info.SampleRate != inRate || info.Channels != inCh {
+			// a format change ends the segment and starts a fresh encoder
+			if enc != nil {
+				if err := cut(); err != nil {
+					return
+				}
+				ptsBase += encSamples * 90000 / int64(enc.sampleRate)
+				encSamples = 0
+			}
+			inRate, inCh = info.SampleRate, info.Channels
+			enc = newMP3Encoder(inRate, inCh)
+			streamType := byte(tsStreamTypeMPEG1Audio)
+			if enc.sampleRate < 32000 {
+				streamType = tsStreamTypeMPEG2Audio
+			}
+			mux = newTSMuxer(&seg, streamType)
+		}
+
+		data, frames := enc.encode(samples)
+		if frames == 0 {
+			continue
+		}
+
+		if segFrames == 0 {
+			// every segment starts with the tables so it decodes on its own
+			if err := mux.writeTables(); err != nil {
+				return
+			}
+		}
+		if len(pesData) == 0 {
+			pesPTS = ptsBase + encSamples*90000/int64(enc.sampleRate)
+		}
+		pesData = append(pesData, data...)
+		pesFrames += frames
+		segFrames += frames
+		encSamples += int64(frames * enc.frameSamples)
+
+		if time.Duration(pesFrames)*enc.frameDuration() >= hlsPESDuration {
+			if err := mux.writePES(pesPTS, pesData); err != nil {
+				return
+			}
+			pesData, pesFrames = nil, 0
+		}
+		if time.Duration(segFrames)*enc.frameDuration() >= hlsSegmentDuration {
+			if err := cut(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package audio
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestRenderHLSPlaylist(t *testing.T) {
+	got := string(renderHLSPlaylist([]hlsSegment{
+		{seq: 4, duration: 2016 * time.Millisecond},
+		{seq: 5, duration: 2500 * time.Millisecond},
+	}))
+	want := "#EXTM3U\n" +
+		"#EXT-X-VERSION:3\n" +
+		"#EXT-X-TARGETDURATION:3\n" +
+		"#EXT-X-MEDIA-SEQUENCE:4\n" +
+		"#EXTINF:2.016,\n4.ts\n" +
+		"#EXTINF:2.500,\n5.ts\n"
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHLSPackagerWindow(t *testing.T) {
+	p := &hlsPackager{updated: make(chan struct{})}
+	for i := 0; i < hlsWindowSize+3; i++ {
+		p.addSegment(2*time.Second, []byte{byte(i)})
+	}
+	if len(p.segments) != hlsWindowSize || p.segments[0].seq != 3 {
+		t.Fatalf("kept segments %d to %d, want the last %d", p.segments[0].seq, p.segments[len(p.segments)-1].seq, hlsWindowSize)
+	}
+	if _, ok := p.segment(2); ok {
+		t.Fatal("segment that left the window still served")
+	}
+	if data, ok := p.segment(hlsWindowSize + 2); !ok || data[0] != hlsWindowSize+2 {
+		t.Fatal("newest segment not served")
+	}
+}
+
+func TestHLSServesSegments(t *testing.T) {
+	// a sim paced on a manual clock, so the encoder keeps up however slow it runs
+	clock := NewManualClock(time.Unix(1000, 0))
+	src, err := NewSim(Named("sim"), SimConfig{SampleRate: 16000, ChunkMs: 20, Clock: clock}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer runClock(clock, 20*time.Millisecond)()
+	hls := newHLSServer(func(name string) (Audio, error) {
+		if name != "sim" {
+			return nil, errors.New("no such resource")
+		}
+		return src, nil
+	}, logging.NewTestLogger(t))
+	defer func() {
+		for _, p := range hls.packagers {
+			p.cancel()
+			<-p.done
+		}
+	}()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /hls/{name}/index.m3u8", hls.servePlaylist)
+	mux.HandleFunc("GET /hls/{name}/{segment}", hls.serveSegment)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	get := func(path string) (*http.Response, []byte) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, body
+	}
+
+	resp, body := get("/hls/sim/index.m3u8")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "\n0.ts\n") {
+		t.Fatalf("got %s with playlist\n%s", resp.Status, body)
+	}
+	resp, body = get("/hls/sim/0.ts")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "video/mp2t" {
+		t.Fatalf("segment: got %s, %q", resp.Status, resp.Header.Get("Content-Type"))
+	}
+	if len(body) == 0 || len(body)%188 != 0 {
+		t.Fatalf("segment is %d bytes, want whole transport stream packets", len(body))
+	}
+	for i := 0; i < len(body); i += 188 {
+		if body[i] != 0x47 {
+			t.Fatalf("packet %d doesn't start with the sync byte", i/188)
+		}
+	}
+
+	for _, path := range []string{"/hls/nope/index.m3u8", "/hls/sim/999.ts", "/hls/sim/x.ts", "/hls/other/0.ts"} {
+		if resp, _ := get(path); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: got %s, want 404", path, resp.Status)
+		}
+	}
+}
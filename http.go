@@ -0,0 +1,192 @@
+package audio
+
+import (
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"go.viam.com/rdk/logging"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Defaults used for the WAV header when the implementation doesn't report
+// the format of its chunks and the request doesn't override it.
+const (
+	defaultHTTPSampleRate = 44100
+	defaultHTTPChannels   = 1
+)
+
+// NewHTTPHandler returns a handler serving the server's audio over plain
+// HTTP, so browsers, curl and media players can use it without a gRPC
+// client:
+//
+//   - GET /audio/{name} streams the named resource's capture as endless
+//     chunked WAV.
+//   - GET /hls/{name}/index.m3u8 serves the same capture as a live HLS
+//     playlist of MP3 segments, for players that need one.
+//   - GET /metrics serves the stream metrics for Prometheus.
+//   - GET /recordings/{file} serves a file of the server's recording store,
+//     as linked from webhook events.
+//
+// lookup resolves a resource name; modules can pass a closure over their own
+// resource and the standalone server passes its resource collection.
+//
+// The sample_rate and channels query parameters describe the stream when the
+// implementation doesn't report AudioInfo on its chunks.
+//
+// Every route is open to anyone who can reach it; wrap the handler with
+// AuthHTTPHandler to require what the gRPC server requires.
+func NewHTTPHandler(lookup func(name string) (Audio, error), logger logging.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /audio/{name}", func(w http.ResponseWriter, r *http.Request) {
+		serveWAVStream(w, r, lookup, logger)
+	})
+	hls := newHLSServer(lookup, logger)
+	mux.HandleFunc("GET /hls/{name}/index.m3u8", hls.servePlaylist)
+	mux.HandleFunc("GET /hls/{name}/{segment}", hls.serveSegment)
+	mux.HandleFunc("GET /metrics", serveMetrics)
+	mux.HandleFunc("GET /recordings/{file}", serveRecording)
+	return mux
+}
+
+// AuthHTTPHandler serves only the requests a accepts, as AuthServerOptions
+// does for gRPC, with their identity in their contexts. Requests carry
+// their token in an Authorization header, or their client certificate for
+// servers with mutual TLS.
+func AuthHTTPHandler(h http.Handler, a Authenticator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md := metadata.MD{}
+		for k, v := range r.Header {
+			md.Append(k, v...)
+		}
+		ctx := metadata.NewIncomingContext(r.Context(), md)
+		p := &peer.Peer{Addr: httpRemoteAddr(r)}
+		if r.TLS != nil {
+			p.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+		}
+		ctx, err := authenticate(peer.NewContext(ctx, p), a)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// httpRemoteAddr returns the address r came from, nil if it doesn't parse.
+func httpRemoteAddr(r *http.Request) net.Addr {
+	addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	return addr
+}
+
+func serveRecording(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	if ServerRecordings.Dir == "" {
+		http.Error(w, errNoRecordingStore.Error(), http.StatusNotFound)
+		return
+	}
+	if !validRecordingID(file) {
+		http.Error(w, "invalid recording name", http.StatusBadRequest)
+		return
+	}
+	ctx := withPeer(r.Context(), httpRemoteAddr(r))
+	if err := auditOperation(ctx, serverLogger, recordingResource(file), AuditRecordingRead, file, "http", nil); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	http.ServeFile(w, r, filepath.Join(ServerRecordings.Dir, file))
+}
+
+func serveWAVStream(w http.ResponseWriter, r *http.Request, lookup func(name string) (Audio, error), logger logging.Logger) {
+	name := r.PathValue("name")
+	a, err := lookup(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	sampleRate, err := intQueryParam(r, "sample_rate", defaultHTTPSampleRate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	channels, err := intQueryParam(r, "channels", defaultHTTPChannels)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := withPeer(r.Context(), httpRemoteAddr(r))
+	if err := auditOperation(ctx, logger, name, AuditCaptureStarted, "", "http wav", nil); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	var captureErr error
+	defer func() { auditOperation(ctx, logger, name, AuditCaptureEnded, "", "http wav", captureErr) }()
+	chunkChan, err := sharedCaptureHub.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm16}})
+	if err != nil {
+		captureErr = err
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	headerWritten := false
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case chunk, ok := <-chunkChan:
+			if !ok {
+				return
+			}
+			if chunk.Err != nil {
+				captureErr = chunk.Err
+				logger.Errorw("audio capture error while serving http stream", "name", name, "error", chunk.Err)
+				if !headerWritten {
+					http.Error(w, chunk.Err.Error(), http.StatusInternalServerError)
+				}
+				return
+			}
+
+			if !headerWritten {
+				if chunk.Info != nil {
+					if chunk.Info.Format != Pcm16 {
+						http.Error(w, "resource returned "+chunk.Info.Format.String()+" audio, expected pcm16", http.StatusUnsupportedMediaType)
+						return
+					}
+					sampleRate, channels = chunk.Info.SampleRate, chunk.Info.Channels
+				}
+				w.Header().Set("Content-Type", "audio/wav")
+				w.Header().Set("Cache-Control", "no-store")
+				w.WriteHeader(http.StatusOK)
+				if err := writeWAVHeader(w, newWAVHeader(sampleRate, channels, 16, streamingDataSize)); err != nil {
+					return
+				}
+				headerWritten = true
+			}
+
+			if _, err := w.Write(chunk.AudioData); err != nil {
+				logger.Debugw("http audio client went away", "name", name, "error", err)
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func intQueryParam(r *http.Request, key string, def int) (int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,62 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestHTTPWAVStream(t *testing.T) {
+	src := newBurstSource(5, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 2})
+	close(src.start)
+	srv := httptest.NewServer(NewHTTPHandler(func(name string) (Audio, error) {
+		if name != "burst" {
+			return nil, errors.New("no such resource")
+		}
+		return src, nil
+	}, logging.NewTestLogger(t)))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/audio/burst?sample_rate=44100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "audio/wav" {
+		t.Fatalf("got %s with content type %q", resp.Status, resp.Header.Get("Content-Type"))
+	}
+	if want := 44 + 5*80*2*2; len(body) != want {
+		t.Fatalf("got %d bytes, want %d", len(body), want)
+	}
+	// the chunks' own format wins over the query parameters
+	if rate, channels := binary.LittleEndian.Uint32(body[24:28]), binary.LittleEndian.Uint16(body[22:24]); rate != 8000 || channels != 2 {
+		t.Fatalf("header says %d Hz, %d channels", rate, channels)
+	}
+	if size := binary.LittleEndian.Uint32(body[40:44]); size != streamingDataSize {
+		t.Fatalf("data size %#x, want the streaming size", size)
+	}
+
+	for path, status := range map[string]int{
+		"/audio/nope":                   http.StatusNotFound,
+		"/audio/burst?sample_rate=fast": http.StatusBadRequest,
+		"/audio/burst?channels=two":     http.StatusBadRequest,
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s: got %s, want %d", path, resp.Status, status)
+		}
+	}
+}
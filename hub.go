@@ -0,0 +1,803 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// captureHub shares one device stream per resource between every consumer
+// in the process: GetAudio streams, the HTTP handlers and the other outputs.
+// The device is opened in pcm16 when the first subscriber arrives and closed
+// when the last one leaves; each subscriber transcodes the shared chunks into
+// the format it asked for. Live streams in an encoded codec the resource
+// captures itself share a session of their own per encoding.
+type captureHub struct {
+	mu         sync.Mutex
+	sessions   map[captureKey]*captureSession
+	injections map[Audio]*toneInjection // capture replaced by a test tone
+}
+
+// captureKey identifies a shared capture: the pcm16 one of audio when codec
+// is empty, or one in an encoded codec passed through from the resource.
+type captureKey struct {
+	audio      Audio
+	codec      string
+	sampleRate int
+	channels   int
+	strict     bool
+}
+
+// sharedCaptureHub is the hub every consumer in the process goes through, so
+// a resource is only ever opened once.
+var sharedCaptureHub = newCaptureHub()
+
+type captureSession struct {
+	key    captureKey
+	cancel context.CancelFunc
+	subs   map[*captureSubscriber]struct{}
+	// header is the last StreamHeader of an encoded capture, which late
+	// subscribers need before their first chunk decodes
+	header *StreamHeader
+}
+
+// subscriberBuffer is how many chunks a subscriber can fall behind before
+// its oldest ones are dropped, so one slow consumer never holds up the
+// device or the other subscribers.
+const subscriberBuffer = 50
+
+// Drop policies for GetAudio streams whose client falls behind the capture,
+// see WithDropPolicy.
+const (
+	DropOldest    = "drop_oldest"
+	DropNewest    = "drop_newest"
+	BlockWhenFull = "block"
+)
+
+// queuePolicy is how a subscriber's queue handles its consumer falling
+// behind. The zero value drops the oldest of subscriberBuffer chunks.
+type queuePolicy struct {
+	drop    string      // DropOldest, DropNewest or BlockWhenFull
+	size    int         // chunks queued at most
+	dropped func(n int) // told of chunks dropped, may be nil
+	// depth, if set, follows how many chunks are queued
+	depth *atomic.Int64
+}
+
+func checkDropPolicy(policy string, size int) error {
+	switch policy {
+	case "", DropOldest, DropNewest, BlockWhenFull:
+	default:
+		return errorf(ErrInvalidArgument, "unknown drop policy %q, expected %q, %q or %q", policy, DropOldest, DropNewest, BlockWhenFull)
+	}
+	if size < 0 {
+		return errorf(ErrInvalidArgument, "buffer_chunks cannot be negative, got %d", size)
+	}
+	return nil
+}
+
+type captureSubscriber struct {
+	ch     chan *AudioChunk
+	first  *StreamHeader // put on the first chunk delivered if it has none
+	done   <-chan struct{}
+	wake   chan struct{} // signalled when the queue changes
+	room   chan struct{} // signalled when a chunk leaves the queue
+	policy queuePolicy
+
+	mu      sync.Mutex
+	queue   []*AudioChunk
+	dropped time.Duration // dropped from the front of queue, not yet reported
+	lost    time.Duration // dropped after the back of queue, not yet reported
+	ended   bool          // the device stream ended, deliver what is queued
+}
+
+func newCaptureSubscriber(done <-chan struct{}, policy queuePolicy) *captureSubscriber {
+	if policy.drop == "" {
+		policy.drop = DropOldest
+	}
+	if policy.size == 0 {
+		policy.size = subscriberBuffer
+	}
+	return &captureSubscriber{ch: make(chan *AudioChunk), done: done, wake: make(chan struct{}, 1), room: make(chan struct{}, 1), policy: policy}
+}
+
+func newCaptureHub() *captureHub {
+	return &captureHub{sessions: map[captureKey]*captureSession{}, injections: map[Audio]*toneInjection{}}
+}
+
+// subscribe returns the shared pcm16 capture of a, queued by policy. The
+// channel is closed when the device stream ends; the subscription is dropped
+// once ctx is done.
+func (h *captureHub) subscribe(ctx context.Context, a Audio, policy queuePolicy) (<-chan *AudioChunk, error) {
+	return h.subscribeKey(ctx, captureKey{audio: a}, policy)
+}
+
+// captures describes the shared captures of a in progress, for dump_state.
+func (h *captureHub) captures(a Audio) []map[string]interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	captures := []map[string]interface{}{}
+	for key, sess := range h.sessions {
+		if key.audio != a {
+			continue
+		}
+		queued := 0
+		for sub := range sess.subs {
+			sub.mu.Lock()
+			queued += len(sub.queue)
+			sub.mu.Unlock()
+		}
+		codec := key.codec
+		if codec == "" {
+			codec = Pcm16.String()
+		}
+		captures = append(captures, map[string]interface{}{
+			"codec":       codec,
+			"sample_rate": key.sampleRate,
+			"channels":    key.channels,
+			"subscribers": len(sess.subs),
+			"queued":      queued,
+		})
+	}
+	return captures
+}
+
+// subscribeKey is subscribe for the capture key describes: in key.codec as
+// the resource sends it, shared with every other live stream of the same
+// encoding, unless key.codec is empty.
+func (h *captureHub) subscribeKey(ctx context.Context, key captureKey, policy queuePolicy) (<-chan *AudioChunk, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// sessions are keyed by instance, so a rebuilt resource gets a fresh
+	// session and the old one drains on its own
+	sess, ok := h.sessions[key]
+	if !ok {
+		// the device stream outlives any one subscriber, so it isn't tied to ctx
+		sessCtx, cancel := context.WithCancel(context.Background())
+		codec := key.codec
+		var opts []GetAudioOption
+		if codec == "" {
+			codec = Pcm16.String()
+		} else {
+			opts = append(opts, WithSampleRate(key.sampleRate), WithChannels(key.channels))
+			if key.strict {
+				opts = append(opts, WithStrict())
+			}
+		}
+		src, err := key.audio.GetAudio(sessCtx, codec, 0, 0, 0, opts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		sess = &captureSession{
+			key:    key,
+			cancel: cancel,
+			subs:   map[*captureSubscriber]struct{}{},
+		}
+		h.sessions[key] = sess
+		go h.run(sess, src)
+	}
+
+	sub := newCaptureSubscriber(ctx.Done(), policy)
+	sub.first = sess.header
+	sess.subs[sub] = struct{}{}
+	go sub.deliver()
+	go func() {
+		<-ctx.Done()
+		h.unsubscribe(sess, sub)
+	}()
+	return sub.ch, nil
+}
+
+func (h *captureHub) unsubscribe(sess *captureSession, sub *captureSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := sess.subs[sub]; !ok {
+		return
+	}
+	delete(sess.subs, sub)
+	if len(sess.subs) == 0 {
+		sess.cancel()
+		if h.sessions[sess.key] == sess {
+			delete(h.sessions, sess.key)
+		}
+	}
+}
+
+// run fans device chunks out to the subscribers until the device stream ends.
+func (h *captureHub) run(sess *captureSession, src <-chan *AudioChunk) {
+	for chunk := range src {
+		if sess.key.codec == "" {
+			chunk = h.injected(sess.key.audio, chunk)
+		}
+		// every subscriber shares the chunk, so none may recycle it
+		chunk.pooled = false
+		for _, sub := range h.subscribers(sess, chunk.Header) {
+			sub.push(chunk)
+		}
+	}
+
+	h.mu.Lock()
+	if h.sessions[sess.key] == sess {
+		delete(h.sessions, sess.key)
+	}
+	subs := sess.subs
+	sess.subs = map[*captureSubscriber]struct{}{}
+	h.mu.Unlock()
+
+	sess.cancel()
+	for sub := range subs {
+		sub.end()
+	}
+}
+
+// queueChunks puts a subscriber's queue between src and its consumer, so a
+// consumer falling behind is handled by policy rather than holding up src.
+func queueChunks(ctx context.Context, src <-chan *AudioChunk, policy queuePolicy) <-chan *AudioChunk {
+	sub := newCaptureSubscriber(ctx.Done(), policy)
+	go sub.deliver()
+	go func() {
+		for chunk := range src {
+			sub.push(chunk)
+		}
+		sub.end()
+	}()
+	return sub.ch
+}
+
+// subscribers returns the subscribers of sess, and keeps header, when not
+// nil, for those of an encoded capture that join later.
+func (h *captureHub) subscribers(sess *captureSession, header *StreamHeader) []*captureSubscriber {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if header != nil && sess.key.codec != "" {
+		sess.header = header
+	}
+	subs := make([]*captureSubscriber, 0, len(sess.subs))
+	for sub := range sess.subs {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+// push queues chunk for delivery. Once the queue is full the policy drops
+// the oldest queued chunk or chunk itself, or waits for room. Dropped audio
+// is reported as a gap on the chunk that ends up following it.
+func (s *captureSubscriber) push(chunk *AudioChunk) {
+	s.mu.Lock()
+	for s.policy.drop == BlockWhenFull && len(s.queue) >= s.policy.size {
+		s.mu.Unlock()
+		select {
+		case <-s.room:
+		case <-s.done:
+			return
+		}
+		s.mu.Lock()
+	}
+	dropped := false
+	if len(s.queue) >= s.policy.size {
+		dropped = true
+		if s.policy.drop == DropNewest {
+			d, _ := chunkDuration(chunk)
+			s.lost += d + chunk.Gap
+			s.mu.Unlock()
+			s.drop()
+			return
+		}
+		d, _ := chunkDuration(s.queue[0])
+		s.dropped += d + s.queue[0].Gap
+		s.queue = s.queue[1:]
+	}
+	if s.lost > 0 {
+		marked := *chunk
+		marked.Gap += s.lost
+		chunk, s.lost = &marked, 0
+	}
+	s.queue = append(s.queue, chunk)
+	s.queued()
+	s.mu.Unlock()
+	if dropped {
+		s.drop()
+	}
+	s.signal()
+}
+
+// queued reports the queue's depth to the policy; s.mu must be held.
+func (s *captureSubscriber) queued() {
+	if s.policy.depth != nil {
+		s.policy.depth.Store(int64(len(s.queue)))
+	}
+}
+
+func (s *captureSubscriber) drop() {
+	if s.policy.dropped != nil {
+		s.policy.dropped(1)
+	}
+}
+
+// end closes the subscriber's channel once everything queued is delivered.
+func (s *captureSubscriber) end() {
+	s.mu.Lock()
+	s.ended = true
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *captureSubscriber) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver forwards queued chunks to the subscriber until the device stream
+// ends or the subscriber leaves.
+func (s *captureSubscriber) deliver() {
+	defer close(s.ch)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			ended := s.ended
+			s.mu.Unlock()
+			if ended {
+				return
+			}
+			select {
+			case <-s.wake:
+			case <-s.done:
+				return
+			}
+			continue
+		}
+		chunk := s.queue[0]
+		s.queue = s.queue[1:]
+		s.queued()
+		select {
+		case s.room <- struct{}{}:
+		default:
+		}
+		if s.dropped > 0 {
+			marked := *chunk
+			marked.Gap += s.dropped
+			chunk, s.dropped = &marked, 0
+		}
+		if s.first != nil {
+			if chunk.Header == nil {
+				marked := *chunk
+				marked.Header = s.first
+				chunk = &marked
+			}
+			s.first = nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- chunk:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+var errUnknownSourceFormat = errorf(ErrUnsupportedCodec, "resource does not report the format of its audio, it can only be streamed as-is")
+
+// transcoder converts shared pcm16 capture chunks into one subscriber's
+// requested format. Zero SampleRate or Channels keep the source value. A
+// strict transcoder passes chunks through and fails on any that would need
+// converting.
+type transcoder struct {
+	target    AudioInfo
+	strict    bool
+	resampler *resampler
+	rsFrom    int
+	rsCh      int
+	samples   []float32 // decoded chunk, reused
+}
+
+func newTranscoder(target AudioInfo) *transcoder {
+	return &transcoder{target: target}
+}
+
+// transcoderFor returns the transcoder of a GetAudio call for format.
+func transcoderFor(format AudioFormat, o GetAudioOptions) *transcoder {
+	return &transcoder{target: AudioInfo{Format: format, SampleRate: o.SampleRate, Channels: o.Channels}, strict: o.Strict}
+}
+
+func (t *transcoder) convert(chunk *AudioChunk) (*AudioChunk, error) {
+	out, same, err := t.output(chunk.Info)
+	if err != nil {
+		return nil, err
+	}
+	if same {
+		return chunk, nil
+	}
+	// the samples only live until they are encoded, so one buffer serves
+	// every chunk
+	if t.samples, err = decodePCMTo(t.samples, chunk.AudioData, chunk.Info.Format); err != nil {
+		return nil, err
+	}
+	return t.encode(t.samples, chunk, out)
+}
+
+// convertSamples is convert for sources that render or decode float
+// samples: chunk has no AudioData but the Info of samples as they would be
+// encoded, and the samples are encoded once, straight into the target
+// format, rather than encoded only to be decoded again.
+func (t *transcoder) convertSamples(samples []float32, chunk *AudioChunk) (*AudioChunk, error) {
+	if chunk.Info == nil {
+		return nil, errUnknownSourceFormat
+	}
+	out, same, err := t.output(chunk.Info)
+	if err != nil {
+		return nil, err
+	}
+	if same {
+		encoded := *chunk
+		if encoded.AudioData, err = borrowPCM(samples, chunk.Info.Format); err != nil {
+			return nil, err
+		}
+		encoded.pooled = true
+		return &encoded, nil
+	}
+	return t.encode(samples, chunk, out)
+}
+
+// output returns the format chunks in src are converted to, and whether
+// that is src itself so they pass through as they are.
+func (t *transcoder) output(src *AudioInfo) (AudioInfo, bool, error) {
+	if t.strict {
+		if err := strictMismatch(captureDirection, t.target, src); err != nil {
+			return AudioInfo{}, false, err
+		}
+		return AudioInfo{}, true, nil
+	}
+	if src == nil {
+		if t.target.Format == Pcm16 && t.target.SampleRate == 0 && t.target.Channels == 0 {
+			return AudioInfo{}, true, nil
+		}
+		return AudioInfo{}, false, errUnknownSourceFormat
+	}
+
+	out := *src
+	if t.target.SampleRate != 0 {
+		out.SampleRate = t.target.SampleRate
+	}
+	if t.target.Channels != 0 {
+		out.Channels = t.target.Channels
+	}
+	out.Format = t.target.Format
+	return out, out == *src, nil
+}
+
+// encode remixes, resamples and encodes the samples of chunk into out.
+func (t *transcoder) encode(samples []float32, chunk *AudioChunk, out AudioInfo) (*AudioChunk, error) {
+	src := chunk.Info
+	samples = remix(samples, src.Channels, out.Channels)
+	if src.SampleRate != out.SampleRate {
+		if t.resampler == nil || t.rsFrom != src.SampleRate || t.rsCh != out.Channels {
+			t.resampler = newResampler(src.SampleRate, out.SampleRate, out.Channels)
+			t.rsFrom, t.rsCh = src.SampleRate, out.Channels
+		}
+		samples = t.resampler.process(samples)
+	}
+	// the converted chunk is this subscriber's alone, so its buffer can be
+	// recycled once the chunk is sent
+	data, err := borrowPCM(samples, out.Format)
+	if err != nil {
+		return nil, err
+	}
+	converted := &AudioChunk{
+		pooled:    true,
+		Sequence:  chunk.Sequence,
+		AudioData: data,
+		Info:      &out,
+		Gap:       chunk.Gap,
+		Timestamp: chunk.Timestamp,
+		Speech:    chunk.Speech,
+		Timecode:  chunk.Timecode,
+	}
+	if chunk.Header != nil {
+		// the source's header marks a format change, which the output
+		// needs to announce in its own format
+		converted.Header = headerFor(out)
+	}
+	return converted, nil
+}
+
+// captureRequest describes what one subscriber wants from the shared capture.
+type captureRequest struct {
+	target      AudioInfo       // zero SampleRate or Channels keep the source value
+	gate        *eventGate      // nil delivers everything
+	vad         *vad            // sets Speech on every chunk, nil for none
+	speechOnly  bool            // drop chunks without speech
+	silence     *silenceTrimmer // drops silent stretches, nil keeps them
+	denoise     *denoiser       // runs before everything else, nil for none
+	agc         *agc            // levels delivered chunks, nil for none
+	duration    time.Duration   // zero streams until ctx is done
+	maxDuration time.Duration   // caps the stream like duration, zero for no cap
+	strict      bool            // fail on chunks that would need converting to target
+	chunk       time.Duration   // regroups delivered audio into chunks this long, zero as captured
+	queue       queuePolicy     // for the subscriber falling behind the capture
+}
+
+// open subscribes to the shared capture of a and runs the chunks through the
+// subscriber's gate and transcoder.
+func (h *captureHub) open(ctx context.Context, a Audio, r captureRequest) (<-chan *AudioChunk, error) {
+	// the subscription must end when this stream does, not only when the caller's ctx does
+	ctx, cancel := context.WithCancel(ctx)
+	// opens the device, unless another stream already has
+	_, openSpan := startSpan(ctx, "audio.open_device")
+	src, err := h.subscribe(ctx, a, r.queue)
+	endSpan(openSpan, err)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	tc := newTranscoder(r.target)
+	tc.strict = r.strict
+
+	out := make(chan *AudioChunk)
+	go func() {
+		defer close(out)
+		defer cancel()
+		if r.denoise != nil {
+			defer r.denoise.close()
+		}
+		var rc *rechunker
+		if r.chunk > 0 {
+			rc = newRechunker(r.chunk)
+		}
+		// deliver sends chunk, when not nil, regrouped if the subscriber asked
+		// for a chunk duration; last sends on what is still held back too
+		deliver := func(chunk *AudioChunk, last bool) bool {
+			ready := []*AudioChunk{chunk}
+			if rc != nil {
+				ready = nil
+				if chunk != nil {
+					ready = rc.push(chunk)
+				}
+				if last {
+					ready = append(ready, rc.flush()...)
+				}
+			} else if chunk == nil {
+				return true
+			}
+			for _, chunk := range ready {
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+		limit := r.duration
+		if r.maxDuration > 0 && (limit == 0 || r.maxDuration < limit) {
+			limit = r.maxDuration
+		}
+		// Audio dropped for having no speech or being silent counts against the
+		// limit like delivered audio, so a request for ten seconds ends ten
+		// seconds of capture later however much of it was speech.
+		var (
+			sent, rate  int           // frames delivered and their rate
+			skipped     time.Duration // dropped and not yet reported as a Gap
+			skippedAll  time.Duration // dropped in total
+			lastSkipped *AudioChunk
+			encoded     bool // a chunk was converted to the target format
+		)
+		// flush reports the audio dropped since the last delivered chunk with an
+		// empty chunk in the stream's format, once the stream is ending.
+		flush := func() {
+			if skipped == 0 {
+				deliver(nil, true)
+				return
+			}
+			tail := *lastSkipped
+			if d, ok := chunkDuration(&tail); ok && !tail.Timestamp.IsZero() {
+				tail.Timestamp = tail.Timestamp.Add(d)
+			}
+			tail.AudioData, tail.Gap, tail.Header = nil, skipped, nil
+			chunk, err := tc.convert(&tail)
+			if err != nil {
+				chunk = &AudioChunk{Err: err}
+			}
+			deliver(chunk, true)
+		}
+		for raw := range src {
+			if r.denoise != nil && raw.Err == nil {
+				cleaned, err := r.denoise.process(raw)
+				if err != nil {
+					cleaned = &AudioChunk{Err: err}
+				}
+				raw = cleaned
+			}
+			pending := []*AudioChunk{raw}
+			if r.gate != nil && raw.Err == nil {
+				var err error
+				if pending, err = r.gate.process(raw); err != nil {
+					pending = []*AudioChunk{{Err: err}}
+				}
+			}
+
+			for _, chunk := range pending {
+				if chunk.Err == nil && r.vad != nil {
+					annotated, err := r.vad.annotate(chunk)
+					if err != nil {
+						annotated = &AudioChunk{Err: err}
+					}
+					chunk = annotated
+				}
+				if chunk.Err == nil {
+					keep := !r.speechOnly || *chunk.Speech
+					if r.silence != nil {
+						loud, err := r.silence.keep(chunk)
+						if err != nil {
+							chunk = &AudioChunk{Err: err}
+						}
+						keep = keep && loud
+					}
+					if chunk.Err == nil && !keep {
+						d, _ := chunkDuration(chunk)
+						skipped += d + chunk.Gap
+						skippedAll += d + chunk.Gap
+						lastSkipped = chunk
+						if limit > 0 && time.Duration(sent)*time.Second/time.Duration(max(rate, 1))+skippedAll >= limit {
+							flush()
+							return
+						}
+						continue
+					}
+				}
+				if chunk.Err == nil && r.agc != nil {
+					leveled, err := r.agc.process(chunk)
+					if err != nil {
+						leveled = &AudioChunk{Err: err}
+					}
+					chunk = leveled
+				}
+				if chunk.Err == nil && skipped > 0 {
+					marked := *chunk
+					marked.Gap += skipped
+					chunk, skipped = &marked, 0
+				}
+				if chunk.Err == nil {
+					// the first encode, which sets the encoder up, is traced
+					var encodeSpan trace.Span
+					if !encoded {
+						_, encodeSpan = startSpan(ctx, "audio.encode", attribute.String("audio.codec", r.target.Format.String()))
+					}
+					converted, err := tc.convert(chunk)
+					if encodeSpan != nil {
+						endSpan(encodeSpan, err)
+						encoded = true
+					}
+					if err != nil {
+						converted = &AudioChunk{Err: err}
+					}
+					chunk = converted
+				}
+
+				done := false
+				if chunk.Err == nil && limit > 0 && chunk.Info != nil {
+					width, _ := bytesPerSample(chunk.Info.Format)
+					frameSize := width * chunk.Info.Channels
+					if frameSize == 0 || chunk.Info.SampleRate == 0 {
+						chunk = &AudioChunk{Err: errUnknownSourceFormat}
+					} else {
+						rate = chunk.Info.SampleRate
+						remaining := int((limit-skippedAll).Seconds()*float64(rate)) - sent
+						if frames := len(chunk.AudioData) / frameSize; frames >= remaining {
+							trimmed := *chunk
+							trimmed.AudioData = chunk.AudioData[:max(remaining, 0)*frameSize]
+							chunk = &trimmed
+							done = true
+						} else {
+							sent += frames
+						}
+					}
+				}
+
+				if !deliver(chunk, done) || done || chunk.Err != nil {
+					return
+				}
+			}
+		}
+		flush()
+	}()
+	return out, nil
+}
+
+// openCapture returns the chunks for one GetAudio request. Raw PCM requests
+// share the resource's capture session and are converted per subscriber;
+// other codecs are passed through from the resource, shared between live
+// streams of the same encoding. Either way the chunks are
+// queued by the request's drop policy, which tells dropped of what it drops.
+// Adaptive bitrate requests are encoded from the shared capture.
+func (s *audioServer) openCapture(ctx context.Context, a Audio, req *pb.GetAudioRequest, dropped func(n int)) (<-chan *AudioChunk, error) {
+	if err := checkDropPolicy(req.DropPolicy, int(req.BufferChunks)); err != nil {
+		return nil, err
+	}
+	queue := queuePolicy{drop: req.DropPolicy, size: int(req.BufferChunks), dropped: dropped}
+	if req.AdaptiveBitrate {
+		return s.openAdaptiveBitrate(ctx, a, req, queue)
+	}
+	return s.openQueued(ctx, a, req, queue)
+}
+
+// openQueued is openCapture once the request's queue is set up.
+func (s *audioServer) openQueued(ctx context.Context, a Audio, req *pb.GetAudioRequest, queue queuePolicy) (<-chan *AudioChunk, error) {
+	codec := req.Codec
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if _, rawErr := bytesPerSample(format); err != nil || rawErr != nil {
+		if len(req.OnlyWhen) > 0 || req.Vad != "" || req.SpeechOnly || req.TrimSilence || req.NoiseSuppression != "" || req.Agc || req.ChunkDurationSeconds != 0 {
+			return nil, errorf(ErrUnsupportedCodec, "only_when, vad, trim_silence, noise_suppression, agc and chunk_duration_seconds need a raw pcm codec and a live stream, got codec %q", req.Codec)
+		}
+		// live streams of the same encoding share one capture; ones the
+		// resource has to end are its own
+		if req.DurationSeconds == 0 && req.MaxDurationSeconds == 0 {
+			return s.hub.subscribeKey(ctx, captureKey{audio: a, codec: req.Codec, sampleRate: int(req.SampleRate), channels: int(req.NumChannels), strict: req.Strict}, queue)
+		}
+		opts := []GetAudioOption{WithSampleRate(int(req.SampleRate)), WithChannels(int(req.NumChannels))}
+		if req.Strict {
+			opts = append(opts, WithStrict())
+		}
+		src, err := a.GetAudio(ctx, req.Codec, req.DurationSeconds, req.MaxDurationSeconds, 0, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return queueChunks(ctx, src, queue), nil
+	}
+
+	r := captureRequest{
+		target:      AudioInfo{Format: format, SampleRate: int(req.SampleRate), Channels: int(req.NumChannels)},
+		duration:    secondsToDuration(req.DurationSeconds),
+		maxDuration: secondsToDuration(req.MaxDurationSeconds),
+		strict:      req.Strict,
+		queue:       queue,
+	}
+	if len(req.OnlyWhen) > 0 {
+		if r.gate, err = newEventGate(req.OnlyWhen, secondsToDuration(req.PreRollSeconds), secondsToDuration(req.PostRollSeconds)); err != nil {
+			return nil, err
+		}
+	}
+	if req.Vad != "" || req.SpeechOnly {
+		if r.vad, err = newVAD(req.Vad); err != nil {
+			return nil, err
+		}
+		r.speechOnly = req.SpeechOnly
+	}
+	if req.NoiseSuppression != "" {
+		if _, ok := noiseSuppressors[req.NoiseSuppression]; !ok {
+			return nil, errorf(ErrInvalidArgument, "unknown noise suppressor %q, expected one of %v", req.NoiseSuppression, NoiseSuppressors())
+		}
+		r.denoise = &denoiser{engine: req.NoiseSuppression}
+	}
+	if req.Agc {
+		if req.AgcTargetDbfs > 0 {
+			return nil, errorf(ErrInvalidArgument, "agc_target_dbfs must be below full scale, got %v", req.AgcTargetDbfs)
+		}
+		r.agc = newAGC(float64(req.AgcTargetDbfs), 0)
+	}
+	if req.TrimSilence {
+		r.silence = newSilenceTrimmer(float64(req.SilenceThresholdDbfs), secondsToDuration(req.SilenceHangoverSeconds))
+	}
+	r.chunk = secondsToDuration(req.ChunkDurationSeconds)
+	if err := checkChunkDuration(r.chunk); err != nil {
+		return nil, err
+	}
+	return s.hub.open(ctx, a, r)
+}
+
+func secondsToDuration(seconds float32) time.Duration {
+	return time.Duration(float64(seconds) * float64(time.Second))
+}
@@ -0,0 +1,345 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/resource"
+)
+
+// burstSource sends n pcm16 chunks of 10ms as fast as the hub takes them
+// once start is closed, then ends the stream. The chunks are silent unless
+// samples is set.
+type burstSource struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+	info   AudioInfo
+	frames int
+	n      int
+	start  chan struct{}
+	// samples returns the mono samples of chunk i, repeated on every channel
+	samples func(i int) []float32
+	// captured, if set, stamps each chunk as captured up to now
+	captured bool
+}
+
+func newBurstSource(n int, info AudioInfo) *burstSource {
+	return &burstSource{
+		Named:  Named("burst").AsNamed(),
+		info:   info,
+		frames: info.SampleRate / 100,
+		n:      n,
+		start:  make(chan struct{}),
+	}
+}
+
+func (b *burstSource) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	out := make(chan *AudioChunk)
+	go func() {
+		defer close(out)
+		select {
+		case <-b.start:
+		case <-ctx.Done():
+			return
+		}
+		for i := 0; i < b.n; i++ {
+			info := b.info
+			data := make([]byte, b.frames*2*max(info.Channels, 1))
+			if b.samples != nil {
+				data, _ = encodePCM(remix(b.samples(i), 1, max(info.Channels, 1)), Pcm16)
+			}
+			chunk := &AudioChunk{Sequence: int64(i), AudioData: data, Info: &info}
+			if b.captured {
+				chunk.Timestamp = time.Now().Add(-framesDuration(int64(b.frames), info.SampleRate))
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (b *burstSource) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return nil
+}
+
+func (b *burstSource) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return nil, resource.ErrDoUnimplemented
+}
+
+// drain reads ch to the end and returns the audio and gap it carried.
+func drain(t *testing.T, ch <-chan *AudioChunk) (chunks int, audio, gap time.Duration) {
+	t.Helper()
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		d, _ := chunkDuration(chunk)
+		chunks++
+		audio += d
+		gap += chunk.Gap
+	}
+	return chunks, audio, gap
+}
+
+func TestCaptureHubSlowSubscriberDoesNotStallOthers(t *testing.T) {
+	const n = 200
+	src := newBurstSource(n, AudioInfo{Format: Pcm16, SampleRate: 48000, Channels: 1})
+	h := newCaptureHub()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	fast, err := h.subscribe(ctx, src, queuePolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow, err := h.subscribe(ctx, src, queuePolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+
+	// the slow subscriber isn't read at all until the fast one has everything
+	_, audio, gap := drain(t, fast)
+	if want := n * 10 * time.Millisecond; audio+gap != want {
+		t.Fatalf("fast subscriber accounted for %v, want %v", audio+gap, want)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("fast subscriber was held up by the slow one")
+	}
+
+	chunks, audio, gap := drain(t, slow)
+	if chunks > subscriberBuffer+1 {
+		t.Fatalf("slow subscriber got %d chunks, want at most %d", chunks, subscriberBuffer+1)
+	}
+	if gap == 0 {
+		t.Fatal("dropped audio was not reported as a gap")
+	}
+	if want := n * 10 * time.Millisecond; audio+gap != want {
+		t.Fatalf("slow subscriber accounted for %v of audio and gaps, want %v", audio+gap, want)
+	}
+}
+
+// encodedSource captures opus packets every 5ms until its stream ends,
+// announcing them with a header of its own, and counts its streams.
+type encodedSource struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+	opens atomic.Int32
+}
+
+func (e *encodedSource) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec != "opus" {
+		return nil, errors.New("only opus is captured")
+	}
+	e.opens.Add(1)
+	out := make(chan *AudioChunk)
+	go func() {
+		defer close(out)
+		header := &StreamHeader{Codec: "opus", SampleRate: 48000, Channels: 1, Extradata: []byte("head")}
+		for i := int64(0); ; i++ {
+			chunk := &AudioChunk{Sequence: i, AudioData: []byte{byte(i)}, Header: header}
+			header = nil
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	return out, nil
+}
+
+func (e *encodedSource) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return nil
+}
+
+func (e *encodedSource) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return nil, resource.ErrDoUnimplemented
+}
+
+func TestCaptureHubSharesEncodedCapture(t *testing.T) {
+	src := &encodedSource{Named: Named("opus").AsNamed()}
+	c := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first, err := c.GetAudio(ctx, "opus", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 5 {
+		if chunk := <-first; chunk == nil || chunk.Err != nil {
+			t.Fatalf("first stream got %+v", chunk)
+		}
+	}
+	// a stream joining later shares the capture, and still gets its header
+	second, err := c.GetAudio(ctx, "opus", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := <-second
+	if chunk == nil || chunk.Err != nil || chunk.Header == nil || string(chunk.Header.Extradata) != "head" {
+		t.Fatalf("second stream started with %+v", chunk)
+	}
+	if chunk.AudioData[0] < 5 {
+		t.Errorf("second stream started at packet %d, before it joined", chunk.AudioData[0])
+	}
+	// the first stream isn't read while the second keeps going
+	for range 20 {
+		if chunk := <-second; chunk == nil || chunk.Err != nil {
+			t.Fatalf("second stream got %+v", chunk)
+		}
+	}
+	if n := src.opens.Load(); n != 1 {
+		t.Errorf("the resource was opened %d times", n)
+	}
+
+	// streams of a set length are the resource's to end, so get their own
+	limited, err := c.GetAudio(ctx, "opus", 0.05, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-limited
+	if n := src.opens.Load(); n != 2 {
+		t.Errorf("the resource was opened %d times, want a second time for the limited stream", n)
+	}
+}
+
+func TestCaptureHubDropPolicies(t *testing.T) {
+	const n, size = 40, 5
+	for _, policy := range []string{DropOldest, DropNewest, BlockWhenFull} {
+		t.Run(policy, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			src := newBurstSource(n, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+			var dropped atomic.Int64
+			ch, err := newCaptureHub().subscribe(ctx, src, queuePolicy{drop: policy, size: size, dropped: func(n int) { dropped.Add(int64(n)) }})
+			if err != nil {
+				t.Fatal(err)
+			}
+			close(src.start)
+			if policy != BlockWhenFull {
+				// the capture runs to the end without waiting for the subscriber
+				for ctx.Err() == nil && dropped.Load() < n-size-1 {
+					time.Sleep(time.Millisecond)
+				}
+			}
+			var seqs []int64
+			var gap time.Duration
+			for chunk := range ch {
+				seqs = append(seqs, chunk.Sequence)
+				gap += chunk.Gap
+			}
+			if got := int64(len(seqs)) + dropped.Load(); got != n {
+				t.Fatalf("delivered %d and dropped %d chunks, want %d in all", len(seqs), dropped.Load(), n)
+			}
+			switch policy {
+			case DropOldest:
+				if seqs[len(seqs)-1] != n-1 || gap == 0 {
+					t.Errorf("delivered %v with a %v gap, want the newest after a gap", seqs, gap)
+				}
+			case DropNewest:
+				if seqs[0] != 0 || seqs[len(seqs)-1] >= n-1 {
+					t.Errorf("delivered %v, want the oldest", seqs)
+				}
+			case BlockWhenFull:
+				if len(seqs) != n || gap != 0 {
+					t.Errorf("delivered %d chunks with a %v gap, want all of them", len(seqs), gap)
+				}
+			}
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := serveAudio(t, newBurstSource(1, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}))
+	for _, opt := range []GetAudioOption{WithDropPolicy("drop_everything", 0), WithDropPolicy(DropNewest, -1)} {
+		ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chunk := <-ch; chunk == nil || chunk.Err == nil {
+			t.Errorf("streamed with %+v", NewGetAudioOptions(opt))
+		}
+	}
+}
+
+func TestCaptureHubDurationLimits(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		duration, max time.Duration
+		want          time.Duration
+	}{
+		{"max only", 0, 55 * time.Millisecond, 55 * time.Millisecond},
+		{"max below duration", 100 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond},
+		{"duration below max", 40 * time.Millisecond, time.Second, 40 * time.Millisecond},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src := newBurstSource(20, AudioInfo{Format: Pcm16, SampleRate: 48000, Channels: 1})
+			close(src.start)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			ch, err := newCaptureHub().open(ctx, src, captureRequest{
+				target:      AudioInfo{Format: Pcm16},
+				duration:    tc.duration,
+				maxDuration: tc.max,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, audio, _ := drain(t, ch); audio != tc.want {
+				t.Fatalf("got %v of audio, want %v", audio, tc.want)
+			}
+		})
+	}
+}
+
+func TestCaptureHubLimitNeedsChannels(t *testing.T) {
+	src := newBurstSource(5, AudioInfo{Format: Pcm16, SampleRate: 48000})
+	close(src.start)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ch, err := newCaptureHub().open(ctx, src, captureRequest{target: AudioInfo{Format: Pcm16}, duration: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := <-ch
+	if chunk == nil || !errors.Is(chunk.Err, errUnknownSourceFormat) {
+		t.Fatalf("got %+v, want errUnknownSourceFormat", chunk)
+	}
+}
+
+func TestTranscoderConvertSamples(t *testing.T) {
+	samples := tone(48000, 960, 440, 0.5)
+	src := AudioInfo{Format: Pcm32Float, SampleRate: 48000, Channels: 1}
+	data, _ := encodePCM(samples, src.Format)
+	for _, target := range []AudioInfo{{Format: Pcm16}, {Format: Pcm32Float}, {Format: Pcm16, SampleRate: 16000, Channels: 2}} {
+		// encoding the samples straight away gives what encoding them as the
+		// source format and converting that did
+		want, err := newTranscoder(target).convert(&AudioChunk{Sequence: 3, AudioData: data, Info: &src})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := newTranscoder(target).convertSamples(samples, &AudioChunk{Sequence: 3, Info: &src})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.AudioData, want.AudioData) || *got.Info != *want.Info || got.Sequence != 3 {
+			t.Errorf("%+v: got %d bytes of %+v, want %d of %+v", target, len(got.AudioData), got.Info, len(want.AudioData), want.Info)
+		}
+	}
+	if _, err := newTranscoder(AudioInfo{Format: Pcm16}).convertSamples(samples, &AudioChunk{}); !errors.Is(err, errUnknownSourceFormat) {
+		t.Errorf("converted samples of no format: %v", err)
+	}
+}
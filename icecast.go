@@ -0,0 +1,283 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Codecs an Icecast mountpoint can be fed with.
+const (
+	IcecastCodecMP3  = "mp3"
+	IcecastCodecOpus = "opus" // passed through from a resource that captures opus, in Ogg
+)
+
+// validate checks the configuration and fills in the default codec. Opus is
+// never encoded here, so it is only accepted for resources that capture it
+// themselves; StreamIcecast checks that before connecting.
+func (c *IcecastConfig) validate() (*url.URL, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" {
+		return nil, fmt.Errorf("icecast url must be http, got %q", u.Scheme)
+	}
+	if c.Codec == "" {
+		c.Codec = IcecastCodecMP3
+	}
+	if c.Codec != IcecastCodecMP3 && c.Codec != IcecastCodecOpus {
+		return nil, fmt.Errorf("unsupported icecast codec %q, expected %s or %s", c.Codec, IcecastCodecMP3, IcecastCodecOpus)
+	}
+	return u, nil
+}
+
+var errIcecastNeedsOpus = errorf(ErrUnsupportedCodec, "icecast opus streams pass opus through and need a resource that captures opus itself, use mp3 for other resources")
+
+// IcecastConfig describes the mountpoint capture is published to.
+type IcecastConfig struct {
+	// URL of the mountpoint, e.g. http://icecast.local:8000/robot1.mp3.
+	// Credentials in the URL are used when Password is empty.
+	URL      string
+	Username string // "source" if empty
+	Password string
+	Codec    string // mp3 if empty
+
+	// Stream metadata shown in directories and players.
+	Name        string
+	Description string
+	Genre       string
+	Public      bool
+
+	// Legacy uses the SOURCE method for servers older than Icecast 2.4.
+	Legacy bool
+}
+
+// StreamIcecast publishes the capture of a to an Icecast mountpoint as a
+// source client until ctx is done, the capture ends or the server hangs up.
+// The stream's start and end are written to the server's audit log, and it
+// doesn't start if they can't be.
+func StreamIcecast(ctx context.Context, a Audio, cfg IcecastConfig) (err error) {
+	u, err := cfg.validate()
+	if err != nil {
+		return err
+	}
+	name, detail := a.Name().ShortName(), fmt.Sprintf("icecast %s %s%s", cfg.Codec, u.Host, u.Path)
+	if err := auditOperation(ctx, serverLogger, name, AuditCaptureStarted, "", detail, nil); err != nil {
+		return err
+	}
+	defer func() {
+		auditOperation(context.WithoutCancel(ctx), serverLogger, name, AuditCaptureEnded, "", detail, err)
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var chunks <-chan *AudioChunk
+	var first *AudioChunk
+	if cfg.Codec == IcecastCodecOpus {
+		chunks, err = a.GetAudio(ctx, IcecastCodecOpus, 0, 0, 0)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errIcecastNeedsOpus, err)
+		}
+		// don't claim the mountpoint until the resource has shown it sends opus
+		if first, err = firstOpusChunk(ctx, chunks); err != nil {
+			return err
+		}
+	} else {
+		chunks, err = sharedCaptureHub.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm32Float}})
+		if err != nil {
+			return err
+		}
+	}
+
+	conn, err := dialIcecast(ctx, u, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// unblock writes when ctx ends
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	if cfg.Codec == IcecastCodecOpus {
+		return streamOggOpus(ctx, conn, first, chunks)
+	}
+	return streamMP3(ctx, conn, chunks)
+}
+
+// firstOpusChunk waits for the first chunk of an opus capture and checks that
+// it is opus. Chunks in a raw format report it in their AudioInfo, which opus
+// packets don't have.
+func firstOpusChunk(ctx context.Context, chunks <-chan *AudioChunk) (*AudioChunk, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case chunk, ok := <-chunks:
+		switch {
+		case !ok:
+			return nil, errors.New("capture ended before any audio")
+		case chunk.Err != nil:
+			return nil, chunk.Err
+		case chunk.Info != nil, chunk.Header != nil && chunk.Header.Codec != IcecastCodecOpus:
+			return nil, errIcecastNeedsOpus
+		}
+		return chunk, nil
+	}
+}
+
+// dialIcecast connects and sends the source request, returning once the
+// server has accepted the mountpoint.
+func dialIcecast(ctx context.Context, u *url.URL, cfg IcecastConfig) (net.Conn, error) {
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "8000")
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	user, password := cfg.Username, cfg.Password
+	if password == "" && u.User != nil {
+		user = u.User.Username()
+		password, _ = u.User.Password()
+	}
+	if user == "" {
+		user = "source"
+	}
+	contentType := "audio/mpeg"
+	if cfg.Codec == IcecastCodecOpus {
+		contentType = "audio/ogg"
+	}
+	method, proto := http.MethodPut, "HTTP/1.1"
+	if cfg.Legacy {
+		method, proto = "SOURCE", "HTTP/1.0"
+	}
+	public := "0"
+	if cfg.Public {
+		public = "1"
+	}
+
+	var req strings.Builder
+	fmt.Fprintf(&req, "%s %s %s\r\n", method, u.RequestURI(), proto)
+	fmt.Fprintf(&req, "Host: %s\r\n", u.Host)
+	fmt.Fprintf(&req, "Authorization: Basic %s\r\n", base64.StdEncoding.EncodeToString([]byte(user+":"+password)))
+	fmt.Fprintf(&req, "User-Agent: audioapi\r\n")
+	fmt.Fprintf(&req, "Content-Type: %s\r\n", contentType)
+	fmt.Fprintf(&req, "Ice-Public: %s\r\n", public)
+	for k, v := range map[string]string{"Ice-Name": cfg.Name, "Ice-Description": cfg.Description, "Ice-Genre": cfg.Genre} {
+		if v != "" {
+			fmt.Fprintf(&req, "%s: %s\r\n", k, v)
+		}
+	}
+	if !cfg.Legacy {
+		req.WriteString("Expect: 100-continue\r\n")
+	}
+	req.WriteString("\r\n")
+	if _, err := io.WriteString(conn, req.String()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// the server answers 100 Continue or 200 OK before it accepts any audio
+	status, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("icecast server closed the connection: %w", err)
+	}
+	fields := strings.Fields(status)
+	if len(fields) < 2 || (fields[1] != "100" && fields[1] != "200") {
+		conn.Close()
+		return nil, fmt.Errorf("icecast server refused the source: %s", strings.TrimSpace(status))
+	}
+	return conn, nil
+}
+
+func streamMP3(ctx context.Context, w io.Writer, chunks <-chan *AudioChunk) error {
+	var enc *mp3Encoder
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case chunk, ok := <-chunks:
+			if !ok {
+				return nil
+			}
+			if chunk.Err != nil {
+				return chunk.Err
+			}
+			info := chunk.Info
+			if enc == nil || info.SampleRate != enc.inputRate || info.Channels != enc.channels {
+				// listeners resync on the new frame headers
+				enc = newMP3Encoder(info.SampleRate, info.Channels)
+			}
+			samples, err := decodePCM(chunk.AudioData, info.Format)
+			if err != nil {
+				return err
+			}
+			data, _ := enc.encode(samples)
+			if len(data) == 0 {
+				continue
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// streamOggOpus writes first and the rest of chunks as Ogg Opus, ending the
+// stream with an end-of-stream page when the capture ends.
+func streamOggOpus(ctx context.Context, w io.Writer, first *AudioChunk, chunks <-chan *AudioChunk) error {
+	var serial [4]byte
+	if _, err := rand.Read(serial[:]); err != nil {
+		return err
+	}
+	ogg := newOggWriter(w, binary.LittleEndian.Uint32(serial[:]))
+	head := opusHead(opusClockRate, 2)
+	if first.Header != nil && len(first.Header.Extradata) > 0 {
+		head = first.Header.Extradata
+	}
+	if err := ogg.writeOpusHeaders(head); err != nil {
+		return err
+	}
+
+	var granule int64
+	chunk := first
+	for {
+		frames, err := opusPacketFrames(chunk.AudioData)
+		if err != nil {
+			return err
+		}
+		granule += int64(frames)
+		if err := ogg.writePacket(chunk.AudioData, granule, 0); err != nil {
+			return err
+		}
+
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case chunk, ok = <-chunks:
+		}
+		if !ok {
+			return ogg.close(granule)
+		}
+		if chunk.Err != nil {
+			// listeners can still finish cleanly
+			return errors.Join(chunk.Err, ogg.close(granule))
+		}
+	}
+}
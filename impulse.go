@@ -0,0 +1,403 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Impulse detection compares the level of short frames with a slowly
+// adapting background. A frame far above the background starts an impulse,
+// which has to fall back within MaxDuration to count; anything louder for
+// longer is a change of scene and becomes the new background. Impulses that
+// are peaky enough, by the kurtosis of their samples, are bangs, crashes and
+// things dropped rather than beeps or a burst of steady noise.
+const (
+	defaultImpulseRise        = 20.0  // dB above the background
+	defaultImpulseMinPeak     = -40.0 // dBFS
+	defaultImpulseMaxDuration = 500 * time.Millisecond
+	impulseFrame              = 5 * time.Millisecond
+	impulseBackgroundTau      = time.Second
+	// steady noise has a kurtosis of 3 and tones about 1.5, while the
+	// decaying noise of an impact is well above
+	impulseMinKurtosis = 3.5
+	// audio saved either side of an impulse in its clip
+	impulseClipPadding = 500 * time.Millisecond
+)
+
+// ImpulseEvent is a sudden, short, loud sound in capture.
+type ImpulseEvent struct {
+	Timestamp      time.Time // capture time of the onset, zero if unknown
+	Duration       time.Duration
+	PeakDBFS       float64 // sample peak
+	RiseDB         float64 // loudest 5ms frame above the background
+	BackgroundDBFS float64 // RMS level before the impulse
+	Kurtosis       float64
+	// Clip names the WAV of the impulse, with half a second either side,
+	// saved in the server's recording store. Empty unless clips were asked
+	// for.
+	Clip string
+	Err  error // set on the last event of a stream that failed
+}
+
+// ImpulseOptions tunes impulse detection. Zero values take the defaults.
+type ImpulseOptions struct {
+	// RiseDB is how far above the background level a 5ms frame has to be to
+	// start an impulse, 20 dB by default.
+	RiseDB float64
+	// MinPeakDBFS ignores impulses peaking below it, -40 dBFS by default.
+	MinPeakDBFS float64
+	// MaxDuration is the longest an impulse lasts before it is sustained
+	// loudness instead, 500ms by default.
+	MaxDuration time.Duration
+	// SaveClips saves a clip of every impulse to the server's recording
+	// store, and delays each event until its clip is complete.
+	SaveClips bool
+}
+
+func (o ImpulseOptions) withDefaults() (ImpulseOptions, error) {
+	if o.RiseDB < 0 || o.MaxDuration < 0 || o.MinPeakDBFS > 0 {
+		return o, errors.New("impulse rise and max duration cannot be negative, nor the minimum peak above 0 dBFS")
+	}
+	if o.RiseDB == 0 {
+		o.RiseDB = defaultImpulseRise
+	}
+	if o.MinPeakDBFS == 0 {
+		o.MinPeakDBFS = defaultImpulseMinPeak
+	}
+	if o.MaxDuration == 0 {
+		o.MaxDuration = defaultImpulseMaxDuration
+	}
+	return o, nil
+}
+
+// ImpulseDetector is implemented by clients that can have the server watch
+// capture for impulsive sounds.
+type ImpulseDetector interface {
+	// StreamImpulses reports every impulse until ctx is done.
+	StreamImpulses(ctx context.Context, opts ImpulseOptions) (<-chan ImpulseEvent, error)
+}
+
+// impulse is an impulse in progress or waiting for the end of its clip.
+type impulse struct {
+	ImpulseEvent
+	samples  []float32
+	clip     []float32
+	postLeft int // samples still to add to the clip
+}
+
+// impulseTracker finds impulses in mono audio at one rate.
+type impulseTracker struct {
+	opts      ImpulseOptions
+	rate      int
+	frame     int
+	keepClips bool
+
+	partial []float32 // samples waiting for a full frame
+	at      time.Time // capture time of partial[0], zero if unknown
+	bg      float64   // background mean square, negative until the first frame
+	recent  []float32 // the clip padding before the current frame
+	current *impulse
+	ending  []*impulse // ended, with clips still being recorded
+}
+
+func newImpulseTracker(opts ImpulseOptions, rate int, keepClips bool) *impulseTracker {
+	return &impulseTracker{
+		opts:      opts,
+		rate:      rate,
+		frame:     max(1, int(impulseFrame.Seconds()*float64(rate))),
+		keepClips: keepClips,
+		bg:        -1,
+	}
+}
+
+// add takes mono samples captured from at, which may be zero, and returns
+// the impulses they completed.
+func (t *impulseTracker) add(samples []float32, at time.Time) []*impulse {
+	if len(t.partial) == 0 {
+		t.at = at
+	}
+	t.partial = append(t.partial, samples...)
+	var done []*impulse
+	for len(t.partial) >= t.frame {
+		done = append(done, t.process(t.partial[:t.frame])...)
+		t.partial = t.partial[t.frame:]
+		if !t.at.IsZero() {
+			t.at = t.at.Add(t.duration(t.frame))
+		}
+	}
+	t.partial = append([]float32(nil), t.partial...)
+	return done
+}
+
+func (t *impulseTracker) duration(samples int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(t.rate)
+}
+
+func (t *impulseTracker) process(f []float32) []*impulse {
+	var done []*impulse
+	kept := t.ending[:0]
+	for _, ev := range t.ending {
+		n := min(len(f), ev.postLeft)
+		ev.clip = append(ev.clip, f[:n]...)
+		if ev.postLeft -= n; ev.postLeft == 0 {
+			done = append(done, ev)
+		} else {
+			kept = append(kept, ev)
+		}
+	}
+	t.ending = kept
+
+	level := rms(f)
+	power := level * level
+	if t.bg < 0 {
+		t.bg = power
+	}
+	bgDBFS := dbfs(math.Sqrt(t.bg))
+	rise := dbfs(level) - bgDBFS
+
+	switch ev := t.current; {
+	case ev == nil:
+		if rise >= t.opts.RiseDB && dbfs(peak(f)) >= t.opts.MinPeakDBFS {
+			t.current = &impulse{
+				ImpulseEvent: ImpulseEvent{Timestamp: t.at, RiseDB: rise, PeakDBFS: dbfs(peak(f)), BackgroundDBFS: bgDBFS},
+				samples:      append([]float32(nil), f...),
+				clip:         append([]float32(nil), t.recent...),
+			}
+		}
+	case rise < t.opts.RiseDB/2:
+		// the frame back near the background ends the impulse
+		ev.samples = append(ev.samples, f...)
+		ev.Duration = t.duration(len(ev.samples))
+		ev.Kurtosis = kurtosis(ev.samples)
+		t.current = nil
+		if ev.Kurtosis < impulseMinKurtosis {
+			break
+		}
+		if !t.keepClips {
+			done = append(done, ev)
+			break
+		}
+		ev.clip = append(ev.clip, ev.samples...)
+		ev.postLeft = int(impulseClipPadding.Seconds() * float64(t.rate))
+		t.ending = append(t.ending, ev)
+	case t.duration(len(ev.samples)+len(f)) > t.opts.MaxDuration:
+		// sustained loudness, which the background follows from here
+		t.current = nil
+		t.bg = power
+	default:
+		ev.samples = append(ev.samples, f...)
+		ev.RiseDB = math.Max(ev.RiseDB, rise)
+		ev.PeakDBFS = math.Max(ev.PeakDBFS, dbfs(peak(f)))
+	}
+
+	if t.current == nil {
+		t.bg += (power - t.bg) * impulseFrame.Seconds() / impulseBackgroundTau.Seconds()
+	}
+	if t.keepClips {
+		t.recent = append(t.recent, f...)
+		if keep := int(impulseClipPadding.Seconds() * float64(t.rate)); len(t.recent) > keep {
+			t.recent = append(t.recent[:0], t.recent[len(t.recent)-keep:]...)
+		}
+	}
+	return done
+}
+
+// kurtosis returns the fourth standardized moment of samples, 3 for
+// gaussian noise and higher the more of the energy is in a few peaks.
+func kurtosis(samples []float32) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var mean float64
+	for _, s := range samples {
+		mean += float64(s)
+	}
+	mean /= float64(len(samples))
+	var m2, m4 float64
+	for _, s := range samples {
+		d := float64(s) - mean
+		m2 += d * d
+		m4 += d * d * d * d
+	}
+	if m2 == 0 {
+		return 0
+	}
+	return float64(len(samples)) * m4 / (m2 * m2)
+}
+
+// impulseDetector is the only_when condition for impulses, with the default
+// options.
+type impulseDetector struct {
+	t *impulseTracker
+}
+
+func (d *impulseDetector) Detect(samples []float32, info AudioInfo) bool {
+	if info.SampleRate == 0 {
+		return false
+	}
+	if d.t == nil || d.t.rate != info.SampleRate {
+		opts, _ := ImpulseOptions{}.withDefaults()
+		d.t = newImpulseTracker(opts, info.SampleRate, false)
+	}
+	return len(d.t.add(mono(samples, info.Channels), time.Time{})) > 0
+}
+
+// detectImpulses reports the impulses in a's shared capture until ctx is done
+// or the capture ends. Clips are saved in ServerRecordings as
+// <resource>-impulse-<onset>.wav.
+func (h *captureHub) detectImpulses(ctx context.Context, a Audio, opts ImpulseOptions) (<-chan ImpulseEvent, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := h.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm32Float, Channels: 1}})
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan ImpulseEvent)
+	go func() {
+		defer close(out)
+		var t *impulseTracker
+		for chunk := range chunks {
+			var done []ImpulseEvent
+			if chunk.Err != nil {
+				done = []ImpulseEvent{{Err: chunk.Err}}
+			} else if chunk.Info == nil || chunk.Info.SampleRate == 0 {
+				done = []ImpulseEvent{{Err: errUnknownSourceFormat}}
+			} else if samples, err := decodePCM(chunk.AudioData, Pcm32Float); err != nil {
+				done = []ImpulseEvent{{Err: err}}
+			} else {
+				if t == nil || t.rate != chunk.Info.SampleRate {
+					t = newImpulseTracker(opts, chunk.Info.SampleRate, opts.SaveClips)
+				}
+				for _, ev := range t.add(samples, chunk.Timestamp) {
+					if opts.SaveClips {
+						if ev.Clip, err = saveImpulseClip(a, ev, t.rate); err != nil {
+							ev.Err = err
+						}
+					}
+					done = append(done, ev.ImpulseEvent)
+				}
+			}
+			for _, ev := range done {
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+				if ev.Err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// saveImpulseClip saves the clip of ev as a 16-bit WAV and returns its name.
+func saveImpulseClip(a Audio, ev *impulse, rate int) (string, error) {
+	at := ev.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	name := fmt.Sprintf("%s-impulse-%s", a.Name().ShortName(), at.UTC().Format("20060102T150405.000Z"))
+	if err := ServerRecordings.saveWAV(name, ev.clip, rate, 1); err != nil {
+		return "", fmt.Errorf("cannot save impulse clip: %w", err)
+	}
+	return name + ".wav", nil
+}
+
+func (s *audioServer) StreamImpulses(req *pb.StreamImpulsesRequest, stream pb.AudioService_StreamImpulsesServer) error {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return err
+	}
+	events, err := s.hub.detectImpulses(stream.Context(), a, ImpulseOptions{
+		RiseDB:      float64(req.RiseDb),
+		MinPeakDBFS: float64(req.MinPeakDbfs),
+		MaxDuration: secondsToDuration(req.MaxDurationSeconds),
+		SaveClips:   req.SaveClips,
+	})
+	if err != nil {
+		return err
+	}
+	for ev := range events {
+		if ev.Err != nil {
+			return ev.Err
+		}
+		msg := &pb.ImpulseEvent{
+			DurationSeconds: float32(ev.Duration.Seconds()),
+			PeakDbfs:        float32(ev.PeakDBFS),
+			RiseDb:          float32(ev.RiseDB),
+			BackgroundDbfs:  float32(ev.BackgroundDBFS),
+			Kurtosis:        float32(ev.Kurtosis),
+			Clip:            ev.Clip,
+		}
+		if !ev.Timestamp.IsZero() {
+			msg.TimestampNanoseconds = ev.Timestamp.UnixNano()
+		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+		noteEvent(EventRecord{
+			Resource:  req.Name,
+			Kind:      "impulse",
+			Timestamp: ev.Timestamp,
+			Duration:  ev.Duration,
+			PeakDBFS:  ev.PeakDBFS,
+			Clip:      ev.Clip,
+		})
+	}
+	return nil
+}
+
+func (c *audioClient) StreamImpulses(ctx context.Context, opts ImpulseOptions) (<-chan ImpulseEvent, error) {
+	stream, err := c.client.StreamImpulses(ctx, &pb.StreamImpulsesRequest{
+		Name:               c.name,
+		RiseDb:             float32(opts.RiseDB),
+		MinPeakDbfs:        float32(opts.MinPeakDBFS),
+		MaxDurationSeconds: float32(opts.MaxDuration.Seconds()),
+		SaveClips:          opts.SaveClips,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan ImpulseEvent)
+	go func() {
+		defer close(out)
+		for {
+			msg, err := stream.Recv()
+			ev := ImpulseEvent{Err: err}
+			if err == nil {
+				ev = ImpulseEvent{
+					Duration:       secondsToDuration(msg.DurationSeconds),
+					PeakDBFS:       float64(msg.PeakDbfs),
+					RiseDB:         float64(msg.RiseDb),
+					BackgroundDBFS: float64(msg.BackgroundDbfs),
+					Kurtosis:       float64(msg.Kurtosis),
+					Clip:           msg.Clip,
+				}
+				if msg.TimestampNanoseconds != 0 {
+					ev.Timestamp = time.Unix(0, msg.TimestampNanoseconds)
+				}
+			} else if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return
+			}
+			select {
+			case out <- ev:
+			case <-ctx.Done():
+				return
+			}
+			if ev.Err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
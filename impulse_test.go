@@ -0,0 +1,113 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// impulseScene is 6s of quiet noise at 16 kHz with a bang at 1s, a beep at
+// 2.5s and loud noise from 4s on, only the first of which is an impulse.
+func impulseScene() []float32 {
+	const rate = 16000
+	rng := rand.New(rand.NewSource(1))
+	scene := make([]float32, 6*rate)
+	for i := range scene {
+		t := float64(i) / rate
+		s := 0.001 * rng.NormFloat64()
+		switch {
+		case t >= 1 && t < 1.3:
+			// noise decaying from -14 dBFS
+			s += 0.2 * rng.NormFloat64() * math.Exp(-(t-1)/0.04)
+		case t >= 2.5 && t < 2.6:
+			s += 0.3 * math.Sin(2*math.Pi*1000*t)
+		case t >= 4:
+			s += 0.1 * rng.NormFloat64()
+		}
+		scene[i] = float32(s)
+	}
+	return scene
+}
+
+func TestImpulseTracker(t *testing.T) {
+	opts, _ := ImpulseOptions{}.withDefaults()
+	tr := newImpulseTracker(opts, 16000, false)
+	scene := impulseScene()
+	start := time.Unix(100, 0)
+	var got []*impulse
+	for i := 0; i < len(scene); i += 160 {
+		got = append(got, tr.add(scene[i:i+160], start.Add(time.Duration(i)*time.Second/16000))...)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d impulses, want only the bang", len(got))
+	}
+	ev := got[0]
+	if d := ev.Timestamp.Sub(start.Add(time.Second)); d < 0 || d > 5*time.Millisecond {
+		t.Errorf("onset at %v, want within a frame of 1s", ev.Timestamp.Sub(start))
+	}
+	if ev.PeakDBFS < -10 || ev.RiseDB < 40 || ev.BackgroundDBFS > -55 {
+		t.Errorf("peak %.1f dBFS, rise %.1f dB over %.1f dBFS", ev.PeakDBFS, ev.RiseDB, ev.BackgroundDBFS)
+	}
+	if ev.Duration > 300*time.Millisecond || ev.Kurtosis < impulseMinKurtosis {
+		t.Errorf("lasted %v with kurtosis %.2f", ev.Duration, ev.Kurtosis)
+	}
+}
+
+func TestStreamImpulsesSavesClips(t *testing.T) {
+	dir := t.TempDir()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = dir
+
+	scene := impulseScene()
+	src := newBurstSource(len(scene)/160, AudioInfo{Format: Pcm16, SampleRate: 16000, Channels: 1})
+	src.samples = func(i int) []float32 { return scene[i*160 : (i+1)*160] }
+	client := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events, err := client.(ImpulseDetector).StreamImpulses(ctx, ImpulseOptions{SaveClips: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+	ev, ok := <-events
+	if !ok || ev.Err != nil {
+		t.Fatalf("got %+v, want the bang", ev)
+	}
+	if ev.Clip == "" {
+		t.Fatal("no clip saved")
+	}
+	b, err := os.ReadFile(filepath.Join(dir, ev.Clip))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// padding either side of the impulse, whose duration came as float32 seconds
+	if d := time.Duration(len(b)-44) / 2 * time.Second / 16000; d < time.Second+ev.Duration-time.Millisecond || d > time.Second+ev.Duration+10*time.Millisecond {
+		t.Errorf("clip is %v long for a %v impulse", d, ev.Duration)
+	}
+	for ev := range events {
+		t.Errorf("unexpected %+v", ev)
+	}
+}
+
+func TestImpulseCondition(t *testing.T) {
+	d, err := newDetector("impulse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := AudioInfo{Format: Pcm32Float, SampleRate: 16000, Channels: 2}
+	scene := remix(impulseScene(), 1, 2)
+	var at []int
+	for i := 0; i < len(scene); i += 320 {
+		if d.Detect(scene[i:i+320], info) {
+			at = append(at, i/320)
+		}
+	}
+	// reported on the chunk the bang has decayed by
+	if len(at) != 1 || at[0] < 100 || at[0] > 130 {
+		t.Errorf("matched chunks %v, want one between 1s and 1.3s", at)
+	}
+}
@@ -0,0 +1,147 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Defaults for the inject_tone command.
+const (
+	defaultInjectFrequency = 1000.0 // Hz
+	defaultInjectLevel     = -20.0  // dBFS peak
+	defaultInjectDuration  = 10 * time.Second
+	maxInjectDuration      = 10 * time.Minute
+)
+
+// toneInjection replaces a resource's shared capture with a sine of known
+// frequency and level, so the pipeline downstream of the microphone can be
+// checked end to end. Chunks keep their format, length and timestamps.
+type toneInjection struct {
+	frequency float64
+	amplitude float64
+	until     time.Time // on the resource's clock
+	phase     float64
+}
+
+// render returns chunk with its audio replaced by the next stretch of tone.
+// Chunks that don't report a raw pcm format are passed through.
+func (t *toneInjection) render(chunk *AudioChunk) *AudioChunk {
+	if chunk.Err != nil || chunk.Info == nil || chunk.Info.Channels == 0 || chunk.Info.SampleRate == 0 {
+		return chunk
+	}
+	info := *chunk.Info
+	width, err := bytesPerSample(info.Format)
+	if err != nil {
+		return chunk
+	}
+	frames := len(chunk.AudioData) / (width * info.Channels)
+	samples := make([]float32, frames*info.Channels)
+	step := 2 * math.Pi * t.frequency / float64(info.SampleRate)
+	for i := 0; i < frames; i++ {
+		v := float32(t.amplitude * math.Sin(t.phase))
+		for c := 0; c < info.Channels; c++ {
+			samples[i*info.Channels+c] = v
+		}
+		t.phase = math.Mod(t.phase+step, 2*math.Pi)
+	}
+	data, err := encodePCM(samples, info.Format)
+	if err != nil {
+		return chunk
+	}
+	out := *chunk
+	out.AudioData = data
+	return &out
+}
+
+// inject substitutes a's capture with t until it expires, or stops any
+// injection if t is nil.
+func (h *captureHub) inject(a Audio, t *toneInjection) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if t == nil {
+		delete(h.injections, a)
+		return
+	}
+	h.injections[a] = t
+}
+
+// injected returns the chunk subscribers of a get, the tone while an
+// injection is running. Chunks are timed by their capture time if they have
+// one, so the tone lasts exactly as long as asked however late it is read.
+func (h *captureHub) injected(a Audio, chunk *AudioChunk) *AudioChunk {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t, ok := h.injections[a]
+	if !ok {
+		return chunk
+	}
+	at := chunk.Timestamp
+	if at.IsZero() {
+		at = clockOf(a).Now()
+	}
+	if !at.Before(t.until) {
+		delete(h.injections, a)
+		return chunk
+	}
+	return t.render(chunk)
+}
+
+// doInjectCommand handles the tone injection commands built-in models
+// accept through DoCommand, and reports whether cmd was one of them:
+//
+//	{"inject_tone": {"frequency_hz": 1000, "level_dbfs": -20, "duration_seconds": 10}}
+//	{"stop_tone": true}
+//
+// While a tone is injected every stream shared through the server's capture
+// hub carries the tone instead of the microphone. Streams in codecs the
+// resource encodes itself are not affected.
+func doInjectCommand(a Audio, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if _, ok := cmd["stop_tone"]; ok {
+		sharedCaptureHub.inject(a, nil)
+		return map[string]interface{}{}, true, nil
+	}
+	raw, ok := cmd["inject_tone"]
+	if !ok {
+		return nil, false, nil
+	}
+	params, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, true, fmt.Errorf("inject_tone takes an object, got %T", raw)
+	}
+	number := func(key string, def float64) (float64, error) {
+		v, ok := params[key]
+		if !ok {
+			return def, nil
+		}
+		f, ok := v.(float64)
+		if !ok {
+			return 0, errorf(ErrInvalidArgument, "inject_tone %s must be a number, got %T", key, v)
+		}
+		return f, nil
+	}
+	freq, err := number("frequency_hz", defaultInjectFrequency)
+	if err != nil {
+		return nil, true, err
+	}
+	level, err := number("level_dbfs", defaultInjectLevel)
+	if err != nil {
+		return nil, true, err
+	}
+	seconds, err := number("duration_seconds", defaultInjectDuration.Seconds())
+	if err != nil {
+		return nil, true, err
+	}
+	if freq <= 0 || level > 0 {
+		return nil, true, errors.New("inject_tone needs a positive frequency_hz and a level_dbfs at or below full scale")
+	}
+	duration := time.Duration(seconds * float64(time.Second))
+	if duration <= 0 || duration > maxInjectDuration {
+		return nil, true, errorf(ErrInvalidArgument, "inject_tone duration_seconds must be positive and at most %v", maxInjectDuration.Seconds())
+	}
+
+	until := clockOf(a).Now().Add(duration)
+	sharedCaptureHub.inject(a, &toneInjection{frequency: freq, amplitude: math.Pow(10, level/20), until: until})
+	return map[string]interface{}{"injecting_until": until.UTC().Format(time.RFC3339Nano)}, true, nil
+}
@@ -0,0 +1,78 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestInjectTone(t *testing.T) {
+	const rate = 16000
+	clock := NewManualClock(time.Unix(1000, 0))
+	a := NewLoopback(Named("loop"), LoopbackConfig{SampleRate: rate, Channels: 1, Clock: clock}, logging.NewTestLogger(t))
+	defer a.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := a.DoCommand(ctx, map[string]interface{}{"inject_tone": map[string]interface{}{"frequency_hz": 440.0, "level_dbfs": "loud"}}); err == nil {
+		t.Fatal("accepted a level that isn't a number")
+	}
+	resp, err := a.DoCommand(ctx, map[string]interface{}{"inject_tone": map[string]interface{}{
+		"frequency_hz": 440.0, "level_dbfs": -12.0, "duration_seconds": 0.2,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp["injecting_until"] != clock.Now().Add(200*time.Millisecond).UTC().Format(time.RFC3339Nano) {
+		t.Fatalf("got %v", resp)
+	}
+
+	chunks, err := sharedCaptureHub.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm32Float}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer runClock(clock, 10*time.Millisecond)()
+	var tone, after []float32
+	for len(after) < rate/10 {
+		var chunk *AudioChunk
+		select {
+		case chunk = <-chunks:
+		case <-ctx.Done():
+			t.Fatal("capture ended")
+		}
+		samples, err := decodePCM(chunk.AudioData, Pcm32Float)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chunk.Timestamp.Before(time.Unix(1000, 0).Add(200 * time.Millisecond)) {
+			tone = append(tone, samples...)
+		} else {
+			after = append(after, samples...)
+		}
+	}
+	if len(tone) != rate/5 {
+		t.Fatalf("got %d frames of tone, want 200ms", len(tone))
+	}
+	// a sine peaking at -12 dBFS has an RMS 3 dB lower
+	if got := dbfs(bandLevel(tone, rate, 440)); math.Abs(got+15.01) > 0.1 {
+		t.Fatalf("injected tone at %.2f dBFS RMS, want -15", got)
+	}
+	if p := peak(after); p != 0 {
+		t.Fatalf("capture peaked at %v after the injection expired, want the silent loopback", p)
+	}
+
+	if _, err := a.DoCommand(ctx, map[string]interface{}{"inject_tone": map[string]interface{}{}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.DoCommand(ctx, map[string]interface{}{"stop_tone": true}); err != nil {
+		t.Fatal(err)
+	}
+	sharedCaptureHub.mu.Lock()
+	defer sharedCaptureHub.mu.Unlock()
+	if _, ok := sharedCaptureHub.injections[a]; ok {
+		t.Fatal("stop_tone left the injection running")
+	}
+}
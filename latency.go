@@ -0,0 +1,145 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// latencyWindow is how many of the latest chunks LatencyTracker keeps.
+const latencyWindow = 1000
+
+// LatencyStats is the latency of the latest chunks of a capture, from the
+// capture of each chunk's last frame.
+type LatencyStats struct {
+	Chunks int // measured, the latest 1000 at most
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+	Max    time.Duration
+}
+
+// LatencyTracker keeps the latencies of the latest chunks. The server
+// keeps one per resource for capture to send; clients can keep their own
+// of AudioChunk.Latency for capture to delivery.
+type LatencyTracker struct {
+	mu      sync.Mutex
+	samples []time.Duration // a ring of the latest latencyWindow
+	next    int
+}
+
+// Add accounts the latency of a chunk. Negative latencies, from clocks
+// that disagree, count as none.
+func (t *LatencyTracker) Add(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d = max(d, 0)
+	if len(t.samples) < latencyWindow {
+		t.samples = append(t.samples, d)
+		return
+	}
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % latencyWindow
+}
+
+// Stats returns the percentiles of the latencies kept.
+func (t *LatencyTracker) Stats() LatencyStats {
+	t.mu.Lock()
+	sorted := slices.Clone(t.samples)
+	t.mu.Unlock()
+	if len(sorted) == 0 {
+		return LatencyStats{}
+	}
+	slices.Sort(sorted)
+	// nearest rank
+	at := func(p float64) time.Duration {
+		return sorted[max(int(math.Ceil(p*float64(len(sorted))))-1, 0)]
+	}
+	return LatencyStats{
+		Chunks: len(sorted),
+		P50:    at(0.50),
+		P95:    at(0.95),
+		P99:    at(0.99),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// Latency returns how long after its last frame was captured the chunk
+// was received, false for chunks without capture timestamps or that
+// didn't come from a server. Both clocks have to agree for it to mean
+// anything, as with NTP or PTP.
+func (c *AudioChunk) Latency() (time.Duration, bool) {
+	if c.Timestamp.IsZero() || c.Received.IsZero() {
+		return 0, false
+	}
+	d, ok := chunkDuration(c)
+	if !ok {
+		return 0, false
+	}
+	return c.Received.Sub(c.Timestamp.Add(d)), true
+}
+
+// latencyTrackers holds the capture-to-send latency of each resource's
+// GetAudio streams, by resource name.
+type latencyTrackers struct {
+	mu sync.Mutex
+	m  map[string]*LatencyTracker
+}
+
+func newLatencyTrackers() *latencyTrackers {
+	return &latencyTrackers{m: map[string]*LatencyTracker{}}
+}
+
+func (l *latencyTrackers) get(name string) *LatencyTracker {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, ok := l.m[name]
+	if !ok {
+		t = &LatencyTracker{}
+		l.m[name] = t
+	}
+	return t
+}
+
+// LatencyStatsGetter is implemented by clients of servers that measure
+// the latency of their streams.
+type LatencyStatsGetter interface {
+	// GetLatencyStats returns the capture-to-send latency of the latest
+	// chunks the server sent of the resource's GetAudio streams.
+	GetLatencyStats(ctx context.Context) (LatencyStats, error)
+}
+
+func (s *audioServer) GetLatencyStats(ctx context.Context, req *pb.GetLatencyStatsRequest) (*pb.GetLatencyStatsResponse, error) {
+	if _, err := s.resource(req.Name); err != nil {
+		return nil, err
+	}
+	stats := s.latency.get(req.Name).Stats()
+	return &pb.GetLatencyStatsResponse{
+		Chunks:     int64(stats.Chunks),
+		P50Seconds: stats.P50.Seconds(),
+		P95Seconds: stats.P95.Seconds(),
+		P99Seconds: stats.P99.Seconds(),
+		MaxSeconds: stats.Max.Seconds(),
+	}, nil
+}
+
+func (c *audioClient) GetLatencyStats(ctx context.Context) (LatencyStats, error) {
+	resp, err := c.client.GetLatencyStats(ctx, &pb.GetLatencyStatsRequest{Name: c.name})
+	if err != nil {
+		return LatencyStats{}, err
+	}
+	seconds := func(s float64) time.Duration {
+		return time.Duration(s * float64(time.Second))
+	}
+	return LatencyStats{
+		Chunks: int(resp.Chunks),
+		P50:    seconds(resp.P50Seconds),
+		P95:    seconds(resp.P95Seconds),
+		P99:    seconds(resp.P99Seconds),
+		Max:    seconds(resp.MaxSeconds),
+	}, nil
+}
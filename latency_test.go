@@ -0,0 +1,65 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	var tr LatencyTracker
+	if stats := tr.Stats(); stats.Chunks != 0 {
+		t.Errorf("empty tracker has stats %+v", stats)
+	}
+	// the first 500 fall out of the window, leaving 1ms to 1000ms
+	for range 500 {
+		tr.Add(time.Hour)
+	}
+	for i := 1; i <= latencyWindow; i++ {
+		tr.Add(time.Duration(i) * time.Millisecond)
+	}
+	want := LatencyStats{Chunks: latencyWindow, P50: 500 * time.Millisecond, P95: 950 * time.Millisecond, P99: 990 * time.Millisecond, Max: time.Second}
+	if got := tr.Stats(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestGetLatencyStats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(20, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.captured = true
+	c := serveAudio(t, src)
+	close(src.start)
+
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received LatencyTracker
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		if chunk.Sent.IsZero() || chunk.Received.Before(chunk.Sent) {
+			t.Errorf("chunk sent at %v and received at %v", chunk.Sent, chunk.Received)
+		}
+		d, ok := chunk.Latency()
+		if !ok {
+			t.Fatal("a timestamped chunk has no latency")
+		}
+		received.Add(d)
+	}
+
+	stats, err := c.(LatencyStatsGetter).GetLatencyStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Chunks != src.n || stats.P50 > stats.P99 || stats.P99 > stats.Max || stats.Max > 5*time.Second {
+		t.Errorf("server measured %+v", stats)
+	}
+	// the client received each chunk after the server sent it
+	if got := received.Stats(); got.Chunks != src.n || got.Max < stats.Max {
+		t.Errorf("client measured %+v, server %+v", got, stats)
+	}
+}
@@ -0,0 +1,238 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// defaultLevelWindow is how much capture a level report covers by default,
+// about what a VU meter integrates over.
+const defaultLevelWindow = 100 * time.Millisecond
+
+// Levels is the level of one window of capture.
+type Levels struct {
+	Timestamp time.Time // capture time of the window's first sample, zero if unknown
+	Channels  []ChannelLevel
+	Err       error // set on the last report of a stream that failed
+}
+
+// ChannelLevel is the level of one channel, linear with 1 at full scale.
+type ChannelLevel struct {
+	RMS  float64
+	Peak float64
+}
+
+// RMSDBFS returns the RMS level in dBFS.
+func (l ChannelLevel) RMSDBFS() float64 { return dbfs(l.RMS) }
+
+// PeakDBFS returns the peak level in dBFS.
+func (l ChannelLevel) PeakDBFS() float64 { return dbfs(l.Peak) }
+
+// LevelMeter is implemented by clients that can report capture levels
+// without receiving the audio, so dashboards can render VU meters cheaply.
+type LevelMeter interface {
+	// GetLevels returns the levels of the next window of capture, 100ms if
+	// window is zero.
+	GetLevels(ctx context.Context, window time.Duration) (Levels, error)
+	// StreamLevels reports the levels of every window until ctx is done.
+	StreamLevels(ctx context.Context, window time.Duration) (<-chan Levels, error)
+}
+
+// levelAccumulator sums interleaved samples into per channel windows.
+type levelAccumulator struct {
+	window  time.Duration
+	info    AudioInfo
+	frames  int // frames in the window so far
+	sums    []float64
+	peaks   []float64
+	started time.Time
+}
+
+// add takes a raw pcm chunk and returns the windows it completed.
+func (l *levelAccumulator) add(chunk *AudioChunk) ([]Levels, error) {
+	if chunk.Info == nil || chunk.Info.Channels == 0 || chunk.Info.SampleRate == 0 {
+		return nil, errUnknownSourceFormat
+	}
+	if *chunk.Info != l.info {
+		// a window never spans a format change
+		l.info, l.frames = *chunk.Info, 0
+		l.sums, l.peaks = make([]float64, l.info.Channels), make([]float64, l.info.Channels)
+	}
+	samples, err := decodePCM(chunk.AudioData, l.info.Format)
+	if err != nil {
+		return nil, err
+	}
+	ch := l.info.Channels
+	size := max(1, int(l.window.Seconds()*float64(l.info.SampleRate)))
+	var done []Levels
+	for i := 0; i+ch <= len(samples); i += ch {
+		if l.frames == 0 {
+			l.started = time.Time{}
+			if !chunk.Timestamp.IsZero() {
+				l.started = chunk.Timestamp.Add(time.Duration(i/ch) * time.Second / time.Duration(l.info.SampleRate))
+			}
+		}
+		for c := 0; c < ch; c++ {
+			s := float64(samples[i+c])
+			l.sums[c] += s * s
+			l.peaks[c] = math.Max(l.peaks[c], math.Abs(s))
+		}
+		l.frames++
+		if l.frames == size {
+			levels := Levels{Timestamp: l.started, Channels: make([]ChannelLevel, ch)}
+			for c := range levels.Channels {
+				levels.Channels[c] = ChannelLevel{RMS: math.Sqrt(l.sums[c] / float64(size)), Peak: l.peaks[c]}
+				l.sums[c], l.peaks[c] = 0, 0
+			}
+			l.frames = 0
+			done = append(done, levels)
+		}
+	}
+	return done, nil
+}
+
+// meterLevels reports the levels of a's shared capture in windows until ctx
+// is done or the capture ends.
+func (h *captureHub) meterLevels(ctx context.Context, a Audio, window time.Duration) (<-chan Levels, error) {
+	if window <= 0 {
+		window = defaultLevelWindow
+	}
+	chunks, err := h.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm32Float}})
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Levels)
+	go func() {
+		defer close(out)
+		acc := &levelAccumulator{window: window}
+		for chunk := range chunks {
+			var done []Levels
+			if chunk.Err != nil {
+				done = []Levels{{Err: chunk.Err}}
+			} else if done, err = acc.add(chunk); err != nil {
+				done = []Levels{{Err: err}}
+			}
+			for _, levels := range done {
+				select {
+				case out <- levels:
+				case <-ctx.Done():
+					return
+				}
+				if levels.Err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (s *audioServer) GetLevels(ctx context.Context, req *pb.GetLevelsRequest) (*pb.GetLevelsResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	levels, err := s.hub.meterLevels(ctx, a, secondsToDuration(req.WindowSeconds))
+	if err != nil {
+		return nil, err
+	}
+	l, ok := <-levels
+	if !ok {
+		return nil, errors.New("capture ended before a full window")
+	}
+	if l.Err != nil {
+		return nil, l.Err
+	}
+	return levelsToProto(l), nil
+}
+
+func (s *audioServer) StreamLevels(req *pb.GetLevelsRequest, stream pb.AudioService_StreamLevelsServer) error {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return err
+	}
+	levels, err := s.hub.meterLevels(stream.Context(), a, secondsToDuration(req.WindowSeconds))
+	if err != nil {
+		return err
+	}
+	for l := range levels {
+		if l.Err != nil {
+			return l.Err
+		}
+		if err := stream.Send(levelsToProto(l)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func levelsToProto(l Levels) *pb.GetLevelsResponse {
+	resp := &pb.GetLevelsResponse{Channels: make([]*pb.ChannelLevel, len(l.Channels))}
+	for i, c := range l.Channels {
+		resp.Channels[i] = &pb.ChannelLevel{
+			Rms:      float32(c.RMS),
+			Peak:     float32(c.Peak),
+			RmsDbfs:  float32(c.RMSDBFS()),
+			PeakDbfs: float32(c.PeakDBFS()),
+		}
+	}
+	if !l.Timestamp.IsZero() {
+		resp.TimestampNanoseconds = l.Timestamp.UnixNano()
+	}
+	return resp
+}
+
+func levelsFromProto(resp *pb.GetLevelsResponse) Levels {
+	l := Levels{Channels: make([]ChannelLevel, len(resp.Channels))}
+	for i, c := range resp.Channels {
+		l.Channels[i] = ChannelLevel{RMS: float64(c.Rms), Peak: float64(c.Peak)}
+	}
+	if resp.TimestampNanoseconds != 0 {
+		l.Timestamp = time.Unix(0, resp.TimestampNanoseconds)
+	}
+	return l
+}
+
+func (c *audioClient) GetLevels(ctx context.Context, window time.Duration) (Levels, error) {
+	resp, err := c.client.GetLevels(ctx, &pb.GetLevelsRequest{Name: c.name, WindowSeconds: float32(window.Seconds())})
+	if err != nil {
+		return Levels{}, err
+	}
+	return levelsFromProto(resp), nil
+}
+
+func (c *audioClient) StreamLevels(ctx context.Context, window time.Duration) (<-chan Levels, error) {
+	stream, err := c.client.StreamLevels(ctx, &pb.GetLevelsRequest{Name: c.name, WindowSeconds: float32(window.Seconds())})
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Levels)
+	go func() {
+		defer close(out)
+		for {
+			resp, err := stream.Recv()
+			l := Levels{Err: err}
+			if err == nil {
+				l = levelsFromProto(resp)
+			} else if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return
+			}
+			select {
+			case out <- l:
+			case <-ctx.Done():
+				return
+			}
+			if l.Err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,102 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+// levelSteps is a stereo source alternating 10ms chunks at 0.5 and -0.25.
+func levelSteps(n int) *burstSource {
+	src := newBurstSource(n, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 2})
+	src.samples = func(i int) []float32 {
+		level := float32(0.5)
+		if i%2 == 1 {
+			level = -0.25
+		}
+		out := make([]float32, 80)
+		for f := range out {
+			out[f] = level
+		}
+		return out
+	}
+	return src
+}
+
+func TestLevelAccumulatorSplitsChunks(t *testing.T) {
+	info := AudioInfo{Format: Pcm32Float, SampleRate: 1000, Channels: 1}
+	acc := &levelAccumulator{window: 15 * time.Millisecond}
+	start := time.Unix(100, 0)
+	var got []Levels
+	for i := 0; i < 3; i++ {
+		samples := make([]float32, 10)
+		for j := range samples {
+			samples[j] = float32(i + 1)
+		}
+		data, _ := encodePCM(samples, info.Format)
+		done, err := acc.add(&AudioChunk{AudioData: data, Info: &info, Timestamp: start.Add(time.Duration(i) * 10 * time.Millisecond)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, done...)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d windows from 30 frames, want 2", len(got))
+	}
+	// the first window is 10 frames of 1 and 5 of 2, the second 5 of 2 and 10 of 3
+	if rms := got[0].Channels[0].RMS; math.Abs(rms-math.Sqrt(30.0/15)) > 1e-9 {
+		t.Errorf("first window rms %v", rms)
+	}
+	if peak := got[1].Channels[0].Peak; peak != 3 {
+		t.Errorf("second window peak %v, want 3", peak)
+	}
+	if want := start.Add(15 * time.Millisecond); !got[1].Timestamp.Equal(want) {
+		t.Errorf("second window starts at %v, want %v", got[1].Timestamp, want)
+	}
+}
+
+func TestStreamLevels(t *testing.T) {
+	src := levelSteps(20)
+	client := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	levels, err := client.(LevelMeter).StreamLevels(ctx, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+	wantRMS := math.Sqrt((0.25 + 0.0625) / 2)
+	var n int
+	for l := range levels {
+		if l.Err != nil {
+			t.Fatal(l.Err)
+		}
+		n++
+		if len(l.Channels) != 2 {
+			t.Fatalf("got %d channels, want 2", len(l.Channels))
+		}
+		for _, c := range l.Channels {
+			if math.Abs(c.RMS-wantRMS) > 1e-3 || math.Abs(c.PeakDBFS()-dbfs(0.5)) > 0.01 {
+				t.Errorf("window %d rms %v peak %v dBFS, want %v and %v", n, c.RMS, c.PeakDBFS(), wantRMS, dbfs(0.5))
+			}
+		}
+	}
+	if n != 10 {
+		t.Errorf("got %d windows of 200ms of audio, want 10", n)
+	}
+}
+
+func TestGetLevels(t *testing.T) {
+	src := levelSteps(20)
+	client := serveAudio(t, src)
+	close(src.start)
+	l, err := client.(LevelMeter).GetLevels(context.Background(), 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Channels) != 2 || math.Abs(l.Channels[0].RMS-0.5) > 1e-3 {
+		t.Errorf("got %+v, want the first chunk's 0.5", l.Channels)
+	}
+}
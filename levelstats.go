@@ -0,0 +1,452 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Periods level statistics are kept for. Buckets start on the UTC hour and
+// day.
+const (
+	HourlyLevels = time.Hour
+	DailyLevels  = 24 * time.Hour
+)
+
+// Defaults for LevelStatsConfig, and the resolution of the statistics.
+const (
+	defaultHourlyRetention  = 7 * 24 * time.Hour
+	defaultDailyRetention   = 365 * 24 * time.Hour
+	defaultLevelStatsFlush  = time.Minute
+	levelStatsWindow        = 125 * time.Millisecond // the "fast" time weighting of a sound level meter
+	levelHistogramFloorDBFS = -120.0
+	levelHistogramStep      = 0.5 // dB per bin
+	levelHistogramBins      = int(-levelHistogramFloorDBFS / levelHistogramStep)
+)
+
+// LevelStatsConfig describes where StartLevelStats keeps a resource's level
+// statistics.
+type LevelStatsConfig struct {
+	// Dir is the local directory the statistics are persisted in, as
+	// <resource>.levelstats.json. Statistics already there are continued.
+	Dir string
+	// HourlyRetention and DailyRetention are how long buckets are kept, a
+	// week and a year if zero.
+	HourlyRetention time.Duration
+	DailyRetention  time.Duration
+	// FlushInterval is how often the statistics are written out, a minute if
+	// zero. They are also written when a bucket completes and on Stop.
+	FlushInterval time.Duration
+}
+
+// LevelBucket is the level statistics of one hour or day of capture, in
+// dBFS from 125ms windows with the channels' power averaged. Percentiles are
+// the level exceeded that share of the time, so L10 is the loud events and
+// L90 the background.
+type LevelBucket struct {
+	Start   time.Time
+	Period  time.Duration
+	Covered time.Duration // capture that went into the bucket
+	LeqDBFS float64       // equivalent continuous level
+	L10DBFS float64
+	L50DBFS float64
+	L90DBFS float64
+	MaxDBFS float64
+	MinDBFS float64
+}
+
+// LevelStatsQuerier is implemented by clients that can read the level
+// statistics the server keeps.
+type LevelStatsQuerier interface {
+	// GetLevelStats returns the buckets of period, HourlyLevels or
+	// DailyLevels, starting from from until to, oldest first. Zero times
+	// leave that end open.
+	GetLevelStats(ctx context.Context, period time.Duration, from, to time.Time) ([]LevelBucket, error)
+}
+
+// levelBucket is a bucket as it is accumulated and persisted.
+type levelBucket struct {
+	Start     time.Time `json:"start"`
+	Seconds   float64   `json:"seconds"`
+	Energy    float64   `json:"energy"` // mean square times seconds
+	Max       float64   `json:"max_dbfs"`
+	Min       float64   `json:"min_dbfs"`
+	Histogram []uint32  `json:"histogram"` // windows per 0.5 dB from -120 dBFS
+}
+
+func newLevelBucket(start time.Time) *levelBucket {
+	return &levelBucket{Start: start, Max: math.Inf(-1), Min: math.Inf(1), Histogram: make([]uint32, levelHistogramBins)}
+}
+
+func (b *levelBucket) add(power float64, d time.Duration) {
+	level := dbfs(math.Sqrt(power))
+	b.Seconds += d.Seconds()
+	b.Energy += power * d.Seconds()
+	b.Max, b.Min = math.Max(b.Max, level), math.Min(b.Min, level)
+	bin := int((level - levelHistogramFloorDBFS) / levelHistogramStep)
+	b.Histogram[max(0, min(bin, levelHistogramBins-1))]++
+}
+
+// exceeded returns the level exceeded by share of the windows.
+func (b *levelBucket) exceeded(share float64) float64 {
+	var total uint64
+	for _, n := range b.Histogram {
+		total += uint64(n)
+	}
+	if total == 0 {
+		return math.Inf(-1)
+	}
+	var above uint64
+	for bin := len(b.Histogram) - 1; bin >= 0; bin-- {
+		above += uint64(b.Histogram[bin])
+		if float64(above) >= share*float64(total) {
+			return levelHistogramFloorDBFS + (float64(bin)+0.5)*levelHistogramStep
+		}
+	}
+	return levelHistogramFloorDBFS
+}
+
+func (b *levelBucket) summary(period time.Duration) LevelBucket {
+	s := LevelBucket{
+		Start:   b.Start,
+		Period:  period,
+		Covered: time.Duration(b.Seconds * float64(time.Second)),
+		LeqDBFS: math.Inf(-1),
+		L10DBFS: b.exceeded(0.1),
+		L50DBFS: b.exceeded(0.5),
+		L90DBFS: b.exceeded(0.9),
+		MaxDBFS: b.Max,
+		MinDBFS: b.Min,
+	}
+	if b.Seconds > 0 {
+		s.LeqDBFS = dbfs(math.Sqrt(b.Energy / b.Seconds))
+	}
+	return s
+}
+
+// levelHistory is the buckets of both periods, oldest first, as persisted.
+type levelHistory struct {
+	Hourly []*levelBucket `json:"hourly"`
+	Daily  []*levelBucket `json:"daily"`
+}
+
+// add accounts a window of capture at to the buckets it falls in, and
+// reports whether that started a new bucket.
+func (h *levelHistory) add(at time.Time, power float64, d time.Duration) bool {
+	started := false
+	for _, p := range []struct {
+		buckets *[]*levelBucket
+		period  time.Duration
+	}{{&h.Hourly, HourlyLevels}, {&h.Daily, DailyLevels}} {
+		start := at.UTC().Truncate(p.period)
+		buckets := *p.buckets
+		var b *levelBucket
+		if n := len(buckets); n > 0 && buckets[n-1].Start.Equal(start) {
+			b = buckets[n-1]
+		} else if n > 0 && start.Before(buckets[n-1].Start) {
+			// a clock stepped back, keep counting into the latest bucket
+			b = buckets[n-1]
+		} else {
+			b = newLevelBucket(start)
+			*p.buckets = append(buckets, b)
+			started = true
+		}
+		b.add(power, d)
+	}
+	return started
+}
+
+// expire drops buckets that started before the retention periods.
+func (h *levelHistory) expire(now time.Time, hourly, daily time.Duration) {
+	drop := func(buckets []*levelBucket, keep time.Duration) []*levelBucket {
+		cutoff := now.Add(-keep)
+		for len(buckets) > 0 && buckets[0].Start.Before(cutoff) {
+			buckets = buckets[1:]
+		}
+		return buckets
+	}
+	h.Hourly, h.Daily = drop(h.Hourly, hourly), drop(h.Daily, daily)
+}
+
+// LevelStats is the level statistics of a capture being kept by
+// StartLevelStats.
+type LevelStats struct {
+	name   string
+	path   string
+	cfg    LevelStatsConfig
+	logger logging.Logger
+
+	mu      sync.Mutex
+	history levelHistory
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error // why aggregation ended, readable once done is closed
+}
+
+// levelStatsRunning holds the statistics being kept, by resource name, for
+// the GetLevelStats RPC.
+var levelStatsRunning = struct {
+	sync.Mutex
+	m map[string]*LevelStats
+}{m: map[string]*LevelStats{}}
+
+// StartLevelStats keeps hourly and daily level statistics of the capture of
+// a in cfg.Dir until Stop is called or the capture ends. Only the statistics
+// are kept, no audio. The server answers GetLevelStats for a from them until
+// Stop.
+func StartLevelStats(ctx context.Context, a Audio, cfg LevelStatsConfig, logger logging.Logger) (*LevelStats, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("level statistics need a directory")
+	}
+	if cfg.HourlyRetention < 0 || cfg.DailyRetention < 0 || cfg.FlushInterval < 0 {
+		return nil, errors.New("retention and flush interval cannot be negative")
+	}
+	if cfg.HourlyRetention == 0 {
+		cfg.HourlyRetention = defaultHourlyRetention
+	}
+	if cfg.DailyRetention == 0 {
+		cfg.DailyRetention = defaultDailyRetention
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = defaultLevelStatsFlush
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	name := a.Name().ShortName()
+	s := &LevelStats{
+		name:   name,
+		path:   filepath.Join(cfg.Dir, name+".levelstats.json"),
+		cfg:    cfg,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+	if b, err := os.ReadFile(s.path); err == nil {
+		if err := json.Unmarshal(b, &s.history); err != nil {
+			return nil, fmt.Errorf("cannot read level statistics %s: %w", s.path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	levelStatsRunning.Lock()
+	if _, ok := levelStatsRunning.m[name]; ok {
+		levelStatsRunning.Unlock()
+		return nil, fmt.Errorf("level statistics are already kept for %q", name)
+	}
+	levelStatsRunning.m[name] = s
+	levelStatsRunning.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	levels, err := sharedCaptureHub.meterLevels(ctx, a, levelStatsWindow)
+	if err != nil {
+		cancel()
+		s.unregister()
+		return nil, err
+	}
+	s.cancel = cancel
+	go func() {
+		defer close(s.done)
+		s.err = s.aggregate(levels)
+		if err := s.flush(); err != nil {
+			logger.Warnw("cannot write level statistics", "path", s.path, "error", err)
+		}
+	}()
+	return s, nil
+}
+
+func (s *LevelStats) unregister() {
+	levelStatsRunning.Lock()
+	defer levelStatsRunning.Unlock()
+	if levelStatsRunning.m[s.name] == s {
+		delete(levelStatsRunning.m, s.name)
+	}
+}
+
+// aggregate accounts level windows until the capture ends.
+func (s *LevelStats) aggregate(levels <-chan Levels) error {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		var l Levels
+		select {
+		case <-ticker.C:
+			if err := s.flush(); err != nil {
+				s.logger.Warnw("cannot write level statistics", "path", s.path, "error", err)
+			}
+			continue
+		case got, ok := <-levels:
+			if !ok {
+				return nil
+			}
+			l = got
+		}
+		if l.Err != nil {
+			return l.Err
+		}
+		if len(l.Channels) == 0 {
+			continue
+		}
+		var power float64
+		for _, c := range l.Channels {
+			power += c.RMS * c.RMS
+		}
+		power /= float64(len(l.Channels))
+		at := l.Timestamp
+		if at.IsZero() {
+			at = time.Now()
+		}
+		s.mu.Lock()
+		started := s.history.add(at, power, levelStatsWindow)
+		s.mu.Unlock()
+		if started {
+			if err := s.flush(); err != nil {
+				s.logger.Warnw("cannot write level statistics", "path", s.path, "error", err)
+			}
+		}
+	}
+}
+
+// flush expires old buckets and writes the statistics out, replacing the
+// previous file only once the new one is complete.
+func (s *LevelStats) flush() error {
+	s.mu.Lock()
+	s.history.expire(time.Now(), s.cfg.HourlyRetention, s.cfg.DailyRetention)
+	b, err := json.Marshal(&s.history)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Buckets returns the buckets of period, HourlyLevels or DailyLevels,
+// starting from from until to, oldest first. The current bucket is
+// included as far as it goes. Zero times leave that end open.
+func (s *LevelStats) Buckets(period time.Duration, from, to time.Time) ([]LevelBucket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var buckets []*levelBucket
+	switch period {
+	case HourlyLevels:
+		buckets = s.history.Hourly
+	case DailyLevels:
+		buckets = s.history.Daily
+	default:
+		return nil, fmt.Errorf("level statistics are kept per hour or day, not per %v", period)
+	}
+	var out []LevelBucket
+	for _, b := range buckets {
+		if (!from.IsZero() && b.Start.Before(from)) || (!to.IsZero() && !b.Start.Before(to)) {
+			continue
+		}
+		out = append(out, b.summary(period))
+	}
+	return out, nil
+}
+
+// Stop ends aggregation, writes the statistics out and stops serving them.
+// It returns the error that ended the capture, if any.
+func (s *LevelStats) Stop(ctx context.Context) error {
+	s.cancel()
+	s.unregister()
+	select {
+	case <-s.done:
+		return s.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// levelPeriods are the GetLevelStats periods by name.
+var levelPeriods = map[string]time.Duration{"": HourlyLevels, "hour": HourlyLevels, "day": DailyLevels}
+
+func (s *audioServer) GetLevelStats(ctx context.Context, req *pb.GetLevelStatsRequest) (*pb.GetLevelStatsResponse, error) {
+	period, ok := levelPeriods[req.Period]
+	if !ok {
+		return nil, errorf(ErrInvalidArgument, "unknown period %q, expected hour or day", req.Period)
+	}
+	levelStatsRunning.Lock()
+	stats := levelStatsRunning.m[req.Name]
+	levelStatsRunning.Unlock()
+	if stats == nil {
+		return nil, fmt.Errorf("no level statistics are kept for %q", req.Name)
+	}
+	var from, to time.Time
+	if req.StartNanoseconds != 0 {
+		from = time.Unix(0, req.StartNanoseconds)
+	}
+	if req.EndNanoseconds != 0 {
+		to = time.Unix(0, req.EndNanoseconds)
+	}
+	buckets, err := stats.Buckets(period, from, to)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetLevelStatsResponse{Buckets: make([]*pb.LevelStatsBucket, len(buckets))}
+	for i, b := range buckets {
+		resp.Buckets[i] = &pb.LevelStatsBucket{
+			StartNanoseconds: b.Start.UnixNano(),
+			CoveredSeconds:   float32(b.Covered.Seconds()),
+			LeqDbfs:          float32(finiteDBFS(b.LeqDBFS)),
+			L10Dbfs:          float32(finiteDBFS(b.L10DBFS)),
+			L50Dbfs:          float32(finiteDBFS(b.L50DBFS)),
+			L90Dbfs:          float32(finiteDBFS(b.L90DBFS)),
+			MaxDbfs:          float32(finiteDBFS(b.MaxDBFS)),
+			MinDbfs:          float32(finiteDBFS(b.MinDBFS)),
+		}
+	}
+	return resp, nil
+}
+
+func (c *audioClient) GetLevelStats(ctx context.Context, period time.Duration, from, to time.Time) ([]LevelBucket, error) {
+	name := "hour"
+	switch period {
+	case HourlyLevels:
+	case DailyLevels:
+		name = "day"
+	default:
+		return nil, fmt.Errorf("level statistics are kept per hour or day, not per %v", period)
+	}
+	req := &pb.GetLevelStatsRequest{Name: c.name, Period: name}
+	if !from.IsZero() {
+		req.StartNanoseconds = from.UnixNano()
+	}
+	if !to.IsZero() {
+		req.EndNanoseconds = to.UnixNano()
+	}
+	resp, err := c.client.GetLevelStats(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]LevelBucket, len(resp.Buckets))
+	for i, b := range resp.Buckets {
+		buckets[i] = LevelBucket{
+			Start:   time.Unix(0, b.StartNanoseconds).UTC(),
+			Period:  period,
+			Covered: secondsToDuration(b.CoveredSeconds),
+			LeqDBFS: float64(b.LeqDbfs),
+			L10DBFS: float64(b.L10Dbfs),
+			L50DBFS: float64(b.L50Dbfs),
+			L90DBFS: float64(b.L90Dbfs),
+			MaxDBFS: float64(b.MaxDbfs),
+			MinDBFS: float64(b.MinDbfs),
+		}
+	}
+	return buckets, nil
+}
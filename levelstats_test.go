@@ -0,0 +1,137 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestLevelHistory(t *testing.T) {
+	var h levelHistory
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	// an hour at -60 dBFS with a loud -20 dBFS window in every ten
+	for i := 0; i < 28800; i++ {
+		power := 1e-6
+		if i%10 == 0 {
+			power = 1e-2
+		}
+		h.add(start.Add(time.Duration(i)*levelStatsWindow), power, levelStatsWindow)
+	}
+	if !h.add(start.Add(time.Hour), 1e-6, levelStatsWindow) {
+		t.Error("the next hour didn't start a bucket")
+	}
+
+	// persisted and read back
+	b, err := json.Marshal(&h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read levelHistory
+	if err := json.Unmarshal(b, &read); err != nil {
+		t.Fatal(err)
+	}
+	s := &LevelStats{history: read}
+	hours, err := s.Buckets(HourlyLevels, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hours) != 2 || !hours[0].Start.Equal(start) {
+		t.Fatalf("got %d hourly buckets from %v, want 2 from %v", len(hours), hours[0].Start, start)
+	}
+	got := hours[0]
+	want := LevelBucket{Covered: time.Hour, LeqDBFS: 10 * math.Log10(0.9e-6+0.1e-2), L10DBFS: -20, L50DBFS: -60, L90DBFS: -60, MaxDBFS: -20, MinDBFS: -60}
+	if got.Covered != want.Covered {
+		t.Errorf("covered %v, want %v", got.Covered, want.Covered)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"Leq", got.LeqDBFS, want.LeqDBFS},
+		{"L10", got.L10DBFS, want.L10DBFS},
+		{"L50", got.L50DBFS, want.L50DBFS},
+		{"L90", got.L90DBFS, want.L90DBFS},
+		{"max", got.MaxDBFS, want.MaxDBFS},
+		{"min", got.MinDBFS, want.MinDBFS},
+	} {
+		if math.Abs(c.got-c.want) > levelHistogramStep {
+			t.Errorf("%s is %.2f dBFS, want %.2f", c.name, c.got, c.want)
+		}
+	}
+
+	days, _ := s.Buckets(DailyLevels, time.Time{}, time.Time{})
+	if len(days) != 1 || days[0].Covered != time.Hour+levelStatsWindow {
+		t.Errorf("got daily buckets %+v, want one covering both hours", days)
+	}
+	if later, _ := s.Buckets(HourlyLevels, start.Add(time.Minute), time.Time{}); len(later) != 1 {
+		t.Errorf("got %d buckets after the first hour started, want 1", len(later))
+	}
+	if _, err := s.Buckets(time.Minute, time.Time{}, time.Time{}); err == nil {
+		t.Error("got per minute statistics")
+	}
+
+	s.history.expire(start.Add(3*time.Hour), 2*time.Hour, 30*24*time.Hour)
+	if len(s.history.Hourly) != 1 || len(s.history.Daily) != 1 {
+		t.Errorf("kept %d hours and %d days", len(s.history.Hourly), len(s.history.Daily))
+	}
+}
+
+func TestGetLevelStats(t *testing.T) {
+	dir := t.TempDir()
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 {
+		out := make([]float32, 80)
+		for f := range out {
+			out[f] = 0.1
+		}
+		return out
+	}
+	client := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := StartLevelStats(ctx, src, LevelStatsConfig{Dir: dir}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StartLevelStats(ctx, src, LevelStatsConfig{Dir: dir}, logging.NewTestLogger(t)); err == nil {
+		t.Error("kept statistics twice for one resource")
+	}
+	close(src.start)
+	<-stats.done
+
+	// still served once the capture ended
+	buckets, err := client.(LevelStatsQuerier).GetLevelStats(ctx, HourlyLevels, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 500ms of capture is four full windows
+	if len(buckets) != 1 || buckets[0].Covered != 4*levelStatsWindow || math.Abs(buckets[0].LeqDBFS+20) > 0.1 {
+		t.Fatalf("got %+v, want one bucket of 500ms at -20 dBFS", buckets)
+	}
+	if err := stats.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.(LevelStatsQuerier).GetLevelStats(ctx, DailyLevels, time.Time{}, time.Time{}); err == nil {
+		t.Error("served statistics after Stop")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "burst.levelstats.json")); err != nil {
+		t.Fatal(err)
+	}
+	// a restart continues the persisted statistics
+	again, err := StartLevelStats(ctx, newBurstSource(0, AudioInfo{}), LevelStatsConfig{Dir: dir}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Stop(context.Background())
+	if days, _ := again.Buckets(DailyLevels, time.Time{}, time.Time{}); len(days) != 1 || days[0].Covered != 4*levelStatsWindow {
+		t.Errorf("restarted with %+v", days)
+	}
+}
@@ -0,0 +1,465 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Defaults for LevelTriggerOptions.
+const (
+	defaultTriggerThreshold  = -30.0 // dBFS
+	defaultTriggerHysteresis = 6.0   // dB
+	defaultTriggerAttack     = 500 * time.Millisecond
+	defaultTriggerRelease    = 5 * time.Second
+	// triggerWindow is how much capture each level the trigger follows
+	// covers.
+	triggerWindow = 100 * time.Millisecond
+	// triggerRetry is how long a trigger whose capture ended waits to open
+	// it again.
+	triggerRetry = 10 * time.Second
+)
+
+// LevelTriggerOptions describe when a level trigger fires and what it does.
+// Zero values take the defaults.
+type LevelTriggerOptions struct {
+	// ThresholdDBFS is the RMS level the capture has to rise above, -30 dBFS
+	// by default.
+	ThresholdDBFS float64
+	// HysteresisDB is how far below the threshold the level has to fall for
+	// the trigger to release, 6 dB by default, so a level hovering around
+	// the threshold doesn't flap.
+	HysteresisDB float64
+	// Attack is how long the level has to stay above the threshold to fire,
+	// 500ms by default.
+	Attack time.Duration
+	// Release is how long the level has to stay below the release level to
+	// end, 5s by default.
+	Release time.Duration
+	// Recording, if its Dir is set, records the capture while the trigger
+	// is active. The trigger's events are recorded either way.
+	Recording RecordingConfig
+}
+
+func (o LevelTriggerOptions) withDefaults() (LevelTriggerOptions, error) {
+	if o.ThresholdDBFS > 0 || o.HysteresisDB < 0 || o.Attack < 0 || o.Release < 0 {
+		return o, errors.New("trigger threshold cannot be above 0 dBFS, nor hysteresis, attack or release negative")
+	}
+	if o.ThresholdDBFS == 0 {
+		o.ThresholdDBFS = defaultTriggerThreshold
+	}
+	if o.HysteresisDB == 0 {
+		o.HysteresisDB = defaultTriggerHysteresis
+	}
+	if o.Attack == 0 {
+		o.Attack = defaultTriggerAttack
+	}
+	if o.Release == 0 {
+		o.Release = defaultTriggerRelease
+	}
+	return o, nil
+}
+
+// levelGate follows a level with hysteresis: it opens once the level has
+// been above the threshold for the attack time, and closes once it has been
+// below the threshold less the hysteresis for the release time.
+type levelGate struct {
+	opts    LevelTriggerOptions
+	open    bool
+	pending time.Duration // how long the level has been past the edge that changes the state
+}
+
+// add takes the level of d of capture and returns whether the gate opened or
+// closed.
+func (g *levelGate) add(levelDBFS float64, d time.Duration) bool {
+	crossed := levelDBFS > g.opts.ThresholdDBFS
+	hold := g.opts.Attack
+	if g.open {
+		crossed = levelDBFS < g.opts.ThresholdDBFS-g.opts.HysteresisDB
+		hold = g.opts.Release
+	}
+	if !crossed {
+		g.pending = 0
+		return false
+	}
+	if g.pending += d; g.pending < hold {
+		return false
+	}
+	g.open, g.pending = !g.open, 0
+	return true
+}
+
+// TriggerState is the state of a level trigger.
+type TriggerState struct {
+	Active        bool
+	LevelDBFS     float64   // RMS level of the last window of capture
+	Since         time.Time // when Active last changed, zero if it never has
+	Triggers      int       // times the trigger has fired
+	ThresholdDBFS float64
+	ReleaseDBFS   float64            // the level it releases below
+	Segments      []RecordingSegment // recorded while active, oldest first
+}
+
+// TriggerStateGetter is implemented by resources that follow their capture
+// level with a trigger, and by clients of servers serving them.
+type TriggerStateGetter interface {
+	GetTriggerState(ctx context.Context) (TriggerState, error)
+}
+
+// LevelTrigger fires when a resource's capture gets loud, recording it or
+// noting an event in the server's history until it is quiet again.
+type LevelTrigger struct {
+	name   string
+	opts   LevelTriggerOptions
+	logger logging.Logger
+
+	mu       sync.Mutex
+	gate     levelGate
+	level    float64
+	since    time.Time
+	peak     float64 // sample peak while active
+	triggers int
+	rec      *Recording // nil unless recording
+	segments []RecordingSegment
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartLevelTrigger follows the capture level of a until Stop is called,
+// reopening the capture if it ends. Every activation is noted in
+// ServerHistory as a "level" event once it ends.
+func StartLevelTrigger(a Audio, opts LevelTriggerOptions, logger logging.Logger) (*LevelTrigger, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &LevelTrigger{
+		name:   a.Name().ShortName(),
+		opts:   opts,
+		logger: logger,
+		gate:   levelGate{opts: opts},
+		level:  -120,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(t.done)
+		for {
+			if err := t.follow(ctx, a); err != nil && ctx.Err() == nil {
+				logger.Warnf("level trigger capture ended: %v", err)
+			}
+			t.release(ctx, time.Now())
+			if sleepCtx(ctx, triggerRetry) != nil {
+				return
+			}
+		}
+	}()
+	return t, nil
+}
+
+// follow feeds the levels of one capture session of a to the gate.
+func (t *LevelTrigger) follow(ctx context.Context, a Audio) error {
+	levels, err := sharedCaptureHub.meterLevels(ctx, a, triggerWindow)
+	if err != nil {
+		return err
+	}
+	for l := range levels {
+		if l.Err != nil {
+			return l.Err
+		}
+		if len(l.Channels) == 0 {
+			continue
+		}
+		var power, pk float64
+		for _, c := range l.Channels {
+			power += c.RMS * c.RMS
+			pk = math.Max(pk, c.Peak)
+		}
+		at := l.Timestamp
+		if at.IsZero() {
+			at = time.Now()
+		}
+		t.mu.Lock()
+		t.level = dbfs(math.Sqrt(power / float64(len(l.Channels))))
+		t.peak = math.Max(t.peak, pk)
+		changed := t.gate.add(t.level, triggerWindow)
+		open := t.gate.open
+		t.mu.Unlock()
+		switch {
+		case changed && open:
+			t.fire(ctx, a, at)
+		case changed:
+			t.end(ctx, at)
+		}
+	}
+	return nil
+}
+
+// fire starts an activation at capture time at.
+func (t *LevelTrigger) fire(ctx context.Context, a Audio, at time.Time) {
+	var rec *Recording
+	if t.opts.Recording.Dir != "" {
+		var err error
+		if rec, err = startAuditedRecording(ctx, a, t.opts.Recording, "level trigger", t.logger); err != nil {
+			t.logger.Warnf("cannot start triggered recording: %v", err)
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.since, t.triggers, t.rec = at, t.triggers+1, rec
+	t.peak = 0
+}
+
+// end finishes the activation in progress at capture time at.
+func (t *LevelTrigger) end(ctx context.Context, at time.Time) {
+	t.mu.Lock()
+	rec, start, peak := t.rec, t.since, t.peak
+	t.since = at
+	t.mu.Unlock()
+
+	event := EventRecord{Resource: t.name, Kind: "level", Timestamp: start, Duration: at.Sub(start), PeakDBFS: dbfs(peak)}
+	var segments []RecordingSegment
+	if rec != nil {
+		if err := rec.Stop(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			t.logger.Warnf("triggered recording ended: %v", err)
+		}
+		auditRecordingStopped(t.logger, t.name, rec, "level trigger")
+		if segments = rec.Segments(); len(segments) > 0 {
+			event.Clip = filepath.Base(segments[0].Path)
+		}
+	}
+	noteEvent(event)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.segments = append(t.segments, segments...)
+	t.rec = nil
+}
+
+// release ends an activation left open when the capture stopped.
+func (t *LevelTrigger) release(ctx context.Context, at time.Time) {
+	t.mu.Lock()
+	open := t.gate.open
+	t.gate = levelGate{opts: t.opts}
+	t.level = -120
+	t.mu.Unlock()
+	if open {
+		// a cancelled ctx would keep the last segment from being finished
+		t.end(context.WithoutCancel(ctx), at)
+	}
+}
+
+// State returns the state of the trigger.
+func (t *LevelTrigger) State() TriggerState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := TriggerState{
+		Active:        t.gate.open,
+		LevelDBFS:     t.level,
+		Since:         t.since,
+		Triggers:      t.triggers,
+		ThresholdDBFS: t.opts.ThresholdDBFS,
+		ReleaseDBFS:   t.opts.ThresholdDBFS - t.opts.HysteresisDB,
+		Segments:      slices.Clone(t.segments),
+	}
+	if t.rec != nil {
+		state.Segments = append(state.Segments, t.rec.Segments()...)
+	}
+	return state
+}
+
+// Stop ends the trigger, finishing any recording in progress.
+func (t *LevelTrigger) Stop() {
+	t.cancel()
+	<-t.done
+}
+
+// LevelTriggerModel wraps a microphone and records its capture to the
+// server's recording store, or only notes an event, whenever it gets loud.
+var LevelTriggerModel = resource.NewModel("olivia", "audio", "level_trigger")
+
+// LevelTriggerConfig is the configuration of the level_trigger model.
+type LevelTriggerConfig struct {
+	Input          string  `json:"input"`                     // Audio resource to follow
+	ThresholdDBFS  float64 `json:"threshold_dbfs,omitempty"`  // -30 if zero
+	HysteresisDB   float64 `json:"hysteresis_db,omitempty"`   // 6 if zero
+	AttackSeconds  float64 `json:"attack_seconds,omitempty"`  // 0.5 if zero
+	ReleaseSeconds float64 `json:"release_seconds,omitempty"` // 5 if zero
+	// Action is "record" (the default) to record while triggered, or
+	// "event" to only note events.
+	Action         string  `json:"action,omitempty"`
+	SegmentSeconds float64 `json:"segment_seconds,omitempty"` // 60 if zero
+	Format         string  `json:"format,omitempty"`          // "wav" (the default) or "flac"
+}
+
+func (c *LevelTriggerConfig) options() LevelTriggerOptions {
+	return LevelTriggerOptions{
+		ThresholdDBFS: c.ThresholdDBFS,
+		HysteresisDB:  c.HysteresisDB,
+		Attack:        time.Duration(c.AttackSeconds * float64(time.Second)),
+		Release:       time.Duration(c.ReleaseSeconds * float64(time.Second)),
+	}
+}
+
+// Validate checks the level_trigger configuration and returns the input as
+// a dependency.
+func (c *LevelTriggerConfig) Validate(path string) ([]string, []string, error) {
+	if c.Input == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "input")
+	}
+	if _, err := c.options().withDefaults(); err != nil {
+		return nil, nil, resource.NewConfigValidationError(path, err)
+	}
+	if c.Action != "" && c.Action != "record" && c.Action != "event" {
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("action must be \"record\" or \"event\", got %q", c.Action))
+	}
+	if c.SegmentSeconds < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("segment_seconds cannot be negative"))
+	}
+	if c.Format != "" && c.Format != "wav" && c.Format != "flac" {
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("format must be \"wav\" or \"flac\", got %q", c.Format))
+	}
+	return []string{c.Input}, nil, nil
+}
+
+func init() {
+	registerModel(LevelTriggerModel, resource.Registration[Audio, *LevelTriggerConfig]{
+		AttributeMapConverter: migratingConverter[*LevelTriggerConfig](LevelTriggerModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*LevelTriggerConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			input, err := resource.FromDependencies[Audio](deps, Named(cfg.Input))
+			if err != nil {
+				return nil, err
+			}
+			return NewLevelTriggered(conf.ResourceName(), input, *cfg, logger)
+		},
+	})
+}
+
+type levelTriggered struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	input   Audio
+	trigger *LevelTrigger
+}
+
+// NewLevelTriggered returns a resource that passes capture and playback
+// through to input and follows its level with a trigger configured by cfg.
+func NewLevelTriggered(name resource.Name, input Audio, cfg LevelTriggerConfig, logger logging.Logger) (Audio, error) {
+	opts := cfg.options()
+	if cfg.Action != "event" {
+		if ServerRecordings.Dir == "" {
+			return nil, errNoRecordingStore
+		}
+		opts.Recording = RecordingConfig{
+			Dir:             ServerRecordings.Dir,
+			SegmentDuration: time.Duration(cfg.SegmentSeconds * float64(time.Second)),
+			Format:          cfg.Format,
+		}
+	}
+	trigger, err := StartLevelTrigger(input, opts, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &levelTriggered{Named: name.AsNamed(), input: input, trigger: trigger}, nil
+}
+
+// GetAudio passes through to the input.
+func (l *levelTriggered) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	return l.input.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+}
+
+func (l *levelTriggered) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return l.input.Play(ctx, data, codec, sampleRate, channels)
+}
+
+func (l *levelTriggered) GetTriggerState(ctx context.Context) (TriggerState, error) {
+	return l.trigger.State(), nil
+}
+
+// DoCommand takes {"trigger_state": true}, which returns whether the trigger
+// is active, the last level and the segments recorded.
+func (l *levelTriggered) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, l, cmd); ok {
+		return resp, err
+	}
+	if cmd["trigger_state"] == nil {
+		return nil, resource.ErrDoUnimplemented
+	}
+	state := l.trigger.State()
+	segments := make([]interface{}, len(state.Segments))
+	for i, seg := range state.Segments {
+		segments[i] = seg.Path
+	}
+	resp := map[string]interface{}{
+		"active":     state.Active,
+		"level_dbfs": state.LevelDBFS,
+		"triggers":   state.Triggers,
+		"segments":   segments,
+	}
+	if !state.Since.IsZero() {
+		resp["since"] = state.Since.Format(time.RFC3339Nano)
+	}
+	return resp, nil
+}
+
+// Close stops the trigger, finishing the recording in progress.
+func (l *levelTriggered) Close(ctx context.Context) error {
+	l.trigger.Stop()
+	return nil
+}
+
+func (s *audioServer) GetTriggerState(ctx context.Context, req *pb.GetTriggerStateRequest) (*pb.GetTriggerStateResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	tg, ok := a.(TriggerStateGetter)
+	if !ok {
+		return nil, errorf(ErrUnsupported, "%s does not follow its level with a trigger", req.Name)
+	}
+	state, err := tg.GetTriggerState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetTriggerStateResponse{
+		Active:           state.Active,
+		LevelDbfs:        state.LevelDBFS,
+		SinceNanoseconds: toUnixNano(state.Since),
+		Triggers:         int32(state.Triggers),
+		ThresholdDbfs:    state.ThresholdDBFS,
+		ReleaseDbfs:      state.ReleaseDBFS,
+		Segments:         segmentsToProto(state.Segments),
+	}, nil
+}
+
+func (c *audioClient) GetTriggerState(ctx context.Context) (TriggerState, error) {
+	resp, err := c.client.GetTriggerState(ctx, &pb.GetTriggerStateRequest{Name: c.name})
+	if err != nil {
+		return TriggerState{}, err
+	}
+	return TriggerState{
+		Active:        resp.Active,
+		LevelDBFS:     resp.LevelDbfs,
+		Since:         fromUnixNano(resp.SinceNanoseconds),
+		Triggers:      int(resp.Triggers),
+		ThresholdDBFS: resp.ThresholdDbfs,
+		ReleaseDBFS:   resp.ReleaseDbfs,
+		Segments:      segmentsFromProto(resp.Segments),
+	}, nil
+}
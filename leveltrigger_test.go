@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestLevelGate(t *testing.T) {
+	opts, err := LevelTriggerOptions{Attack: 300 * time.Millisecond, Release: 500 * time.Millisecond}.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := levelGate{opts: opts}
+	feed := func(level float64, windows int) (changes int) {
+		for range windows {
+			if g.add(level, 100*time.Millisecond) {
+				changes++
+			}
+		}
+		return changes
+	}
+	// too short, then long enough
+	if feed(-20, 2)+feed(-40, 1) != 0 || g.open {
+		t.Fatal("opened on 200ms above the threshold")
+	}
+	if feed(-20, 3) != 1 || !g.open {
+		t.Fatal("didn't open on 300ms above the threshold")
+	}
+	// between the threshold and the release level it stays open
+	if feed(-33, 20) != 0 || !g.open {
+		t.Fatal("closed above the release level")
+	}
+	if feed(-40, 4)+feed(-33, 1)+feed(-40, 4) != 0 || !g.open {
+		t.Fatal("closed on 400ms below the release level")
+	}
+	if feed(-40, 1) != 1 || g.open {
+		t.Fatal("didn't close on 500ms below the release level")
+	}
+	if _, err := (LevelTriggerOptions{ThresholdDBFS: 3}).withDefaults(); err == nil {
+		t.Error("took a threshold above full scale")
+	}
+}
+
+func TestLevelTrigger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = t.TempDir()
+	cfg := LevelTriggerConfig{Input: "burst", AttackSeconds: 0.2, ReleaseSeconds: 0.3}
+	if _, _, err := cfg.Validate("path"); err != nil {
+		t.Fatal(err)
+	}
+	// loud for 500ms, then quiet for a second
+	src := newBurstSource(150, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 {
+		if i < 50 {
+			return tone(8000, 80, 1000, 0.5)
+		}
+		return make([]float32, 80)
+	}
+	res, err := NewLevelTriggered(Named("trigger"), src, cfg, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close(ctx)
+	c := serveAudio(t, res).(TriggerStateGetter)
+	close(src.start)
+
+	// the event is noted once the recording is finished
+	var events []EventRecord
+	for ctx.Err() == nil && len(events) == 0 {
+		if events, _, err = ServerHistory.events.page(0, 1, func(e EventRecord) bool { return e.Resource == "burst" && e.Kind == "level" }); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(events) != 1 || events[0].Clip == "" {
+		t.Errorf("noted %+v, want a level event with its clip", events)
+	}
+	state, err := c.GetTriggerState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Triggers != 1 || state.Active || state.ThresholdDBFS != -30 || state.ReleaseDBFS != -36 {
+		t.Errorf("state %+v, want one trigger that has released", state)
+	}
+	if len(state.Segments) == 0 || !state.Segments[0].Complete {
+		t.Errorf("recorded %+v, want a complete segment", state.Segments)
+	}
+
+	if _, _, err := (&LevelTriggerConfig{Input: "burst", Action: "alarm"}).Validate("path"); err == nil {
+		t.Error("validated an unknown action")
+	}
+}
@@ -0,0 +1,58 @@
+package audio
+
+import (
+	"context"
+	"time"
+)
+
+// StreamEvent describes a GetAudio stream, or a Play call or committed clip,
+// to the StreamObserver of its resource.
+type StreamEvent struct {
+	Resource  string
+	Direction string // "capture" for GetAudio, "playback" for Play and CommitPlayback
+	RequestID string // of a GetAudio stream, if it was started with one
+	Codec     string
+	// SampleRate and Channels are of the clip as played, after any
+	// conversion, and zero for GetAudio. Codec and both are unset for clips
+	// a resource prepared natively.
+	SampleRate, Channels int
+	Start                time.Time
+}
+
+// StreamObserver is implemented by resources that act on audio activity,
+// such as powering an amplifier or a microphone up and down or lighting an
+// LED while audio flows. The server calls it around the GetAudio streams,
+// and Play and CommitPlayback calls, it serves for the resource,
+// synchronously, so the methods should return quickly; a slow
+// OnStreamStart holds the stream or clip back.
+type StreamObserver interface {
+	// OnStreamStart is called as a GetAudio stream is about to open the
+	// capture, and as a Play or CommitPlayback call is about to play its
+	// clip, once any clip playing before it is done.
+	OnStreamStart(ctx context.Context, e StreamEvent)
+	// OnStreamEnd is called as a GetAudio stream ends, with the error it
+	// failed with, if any. ctx isn't canceled with the stream.
+	OnStreamEnd(ctx context.Context, e StreamEvent, err error)
+	// OnPlaybackDone is called once a Play or CommitPlayback call's clip
+	// has played out or failed to, with the error it failed with. ctx isn't
+	// canceled with the call.
+	OnPlaybackDone(ctx context.Context, e StreamEvent, err error)
+}
+
+// observeStream calls a's OnStreamStart, if a is a StreamObserver, and
+// returns the function to call as the stream or clip ends.
+func observeStream(ctx context.Context, a Audio, e StreamEvent) func(error) {
+	o, ok := a.(StreamObserver)
+	if !ok {
+		return func(error) {}
+	}
+	e.Start = time.Now()
+	o.OnStreamStart(ctx, e)
+	return func(err error) {
+		if e.Direction == playbackDirection {
+			o.OnPlaybackDone(context.WithoutCancel(ctx), e, err)
+		} else {
+			o.OnStreamEnd(context.WithoutCancel(ctx), e, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// observedFake records the stream events the server tells it of.
+type observedFake struct {
+	*Fake
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *observedFake) note(format string, args ...any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+}
+
+func (o *observedFake) OnStreamStart(ctx context.Context, e StreamEvent) {
+	o.note("start %s %s %s %d %d", e.Direction, e.RequestID, e.Codec, e.SampleRate, e.Channels)
+}
+
+func (o *observedFake) OnStreamEnd(ctx context.Context, e StreamEvent, err error) {
+	o.note("end %s %s %v %v", e.Direction, e.RequestID, err, ctx.Err())
+}
+
+func (o *observedFake) OnPlaybackDone(ctx context.Context, e StreamEvent, err error) {
+	o.note("done %s %d %v %v", e.Direction, e.SampleRate, err, ctx.Err())
+}
+
+func TestStreamObserver(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("observed"), FakeConfig{Unpaced: true, PlaybackSampleRates: []int{16000}}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	o := &observedFake{Fake: f}
+	c := serveAudio(t, o)
+
+	clip, _ := encodePCM(tone(8000, 800, 440, 0.5), Pcm16)
+	if err := c.Play(ConvertPlayback(ctx), clip, "pcm16", 8000, 1); err != nil {
+		t.Fatal(err)
+	}
+	// a clip refused before it plays isn't observed
+	if err := c.Play(ctx, clip, "pcm16", 8000, 1); err == nil {
+		t.Fatal("played an unplayable clip")
+	}
+	// prepared clips are observed as they are committed
+	resampled, _ := encodePCM(tone(16000, 1600, 440, 0.5), Pcm16)
+	handle, err := c.(PlaybackPreparer).PreparePlayback(ctx, resampled, "pcm16", 16000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.(PlaybackPreparer).CommitPlayback(ctx, handle, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	ch, err := c.GetAudio(ctx, "pcm16", 0.1, 0, 0, WithRequestID("lit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+	}
+
+	want := []string{
+		"start playback  pcm16 16000 1",
+		"done playback 16000 <nil> <nil>",
+		"start playback  pcm16 16000 1",
+		"done playback 16000 <nil> <nil>",
+		"start capture lit pcm16 0 0",
+		"end capture lit <nil> <nil>",
+	}
+	// the server ends the stream as it returns, which can be after the
+	// client has seen it end
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		o.mu.Lock()
+		events := slices.Clone(o.events)
+		o.mu.Unlock()
+		if len(events) >= len(want) || time.Now().After(deadline) {
+			if !slices.Equal(events, want) {
+				t.Errorf("observed %q, want %q", events, want)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package audio
+
+import (
+	"context"
+	"encoding/base64"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Every list RPC is paged and filtered on the server, so a client on a slow
+// link asks for no more than it shows. Pages hold up to 100 entries unless
+// asked for another size, and never more than 1000. Lists of things that
+// come and go, streams and events, run newest first and their page tokens
+// are entry numbers; lists of things with names run in name order and
+// their tokens are the last name of the page before.
+
+// Page sizes of list RPCs.
+const (
+	defaultListPageSize = 100
+	maxListPageSize     = 1000
+)
+
+// listPageSize reads the page size a list request asks for.
+func listPageSize(n int32) (int, error) {
+	switch {
+	case n < 0:
+		return 0, errorf(ErrInvalidArgument, "invalid page size %d", n)
+	case n == 0:
+		return defaultListPageSize, nil
+	case n > maxListPageSize:
+		return maxListPageSize, nil
+	default:
+		return int(n), nil
+	}
+}
+
+// seqPage reads the page of a newest first list a request asks for: up to
+// size entries numbered below before, from the newest if before is 0.
+func seqPage(pageSize int32, token string) (before uint64, size int, err error) {
+	if size, err = listPageSize(pageSize); err != nil {
+		return 0, 0, err
+	}
+	if token != "" {
+		if before, err = strconv.ParseUint(token, 10, 64); err != nil || before == 0 {
+			return 0, 0, errorf(ErrInvalidArgument, "invalid page token %q", token)
+		}
+	}
+	return before, size, nil
+}
+
+// seqPageToken is the token of the page starting before entry next.
+func seqPageToken(next uint64) string {
+	if next == 0 {
+		return ""
+	}
+	return strconv.FormatUint(next, 10)
+}
+
+// keyPage reads the page of a list in key order a request asks for: up to
+// size entries after key after, from the first if it is empty.
+func keyPage(pageSize int32, token string) (after string, size int, err error) {
+	if size, err = listPageSize(pageSize); err != nil {
+		return "", 0, err
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", 0, errorf(ErrInvalidArgument, "invalid page token %q", token)
+	}
+	return string(b), size, nil
+}
+
+// pageByKey returns up to size of entries, which are in key order, that
+// come after key after and match keep, with the token of the next page.
+func pageByKey[T any](entries []T, key func(T) string, after string, size int, keep func(T) bool) ([]T, string) {
+	var page []T
+	for _, e := range entries {
+		if (after != "" && key(e) <= after) || !keep(e) {
+			continue
+		}
+		if len(page) == size {
+			return page, base64.RawURLEncoding.EncodeToString([]byte(key(page[size-1])))
+		}
+		page = append(page, e)
+	}
+	return page, ""
+}
+
+// inWindow reports whether t is at or after after and before before, either
+// of which is open if zero.
+func inWindow(t, after, before time.Time) bool {
+	return (after.IsZero() || !t.Before(after)) && (before.IsZero() || t.Before(before))
+}
+
+// fromUnixNano is the time of a timestamp field, zero if it isn't set.
+func fromUnixNano(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// toUnixNano is the timestamp field of t, 0 if t is zero.
+func toUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func checkDirection(direction string) error {
+	switch direction {
+	case "", captureDirection, playbackDirection:
+		return nil
+	default:
+		return errorf(ErrInvalidArgument, "invalid direction %q, want %q or %q", direction, captureDirection, playbackDirection)
+	}
+}
+
+// StreamFilter narrows a list of streams. Fields left zero match any stream.
+type StreamFilter struct {
+	Direction string // "capture" or "playback"
+	RequestID string
+	Subject   string
+	// After and Before bound when the stream started.
+	After, Before time.Time
+}
+
+func (f StreamFilter) match(r StreamRecord) bool {
+	return (f.Direction == "" || r.Direction == f.Direction) &&
+		(f.RequestID == "" || r.RequestID == f.RequestID) &&
+		(f.Subject == "" || r.Subject == f.Subject) &&
+		inWindow(r.Start, f.After, f.Before)
+}
+
+// EventFilter narrows a list of events. Fields left zero match any event.
+type EventFilter struct {
+	Kind          string
+	After, Before time.Time
+}
+
+func (f EventFilter) match(e EventRecord) bool {
+	return (f.Kind == "" || e.Kind == f.Kind) && inWindow(e.Timestamp, f.After, f.Before)
+}
+
+// AuditFilter narrows a list of audit records. Fields left zero match any
+// record.
+type AuditFilter struct {
+	Action        string
+	Subject       string
+	After, Before time.Time
+}
+
+func (f AuditFilter) match(r AuditRecord) bool {
+	return (f.Action == "" || r.Action == f.Action) &&
+		(f.Subject == "" || r.Subject == f.Subject) &&
+		inWindow(r.Time, f.After, f.Before)
+}
+
+// RecordingFilter narrows a list of recordings. Fields left zero match any
+// recording.
+type RecordingFilter struct {
+	Prefix string // of the name
+	Format string // "wav", "flac" or "chunks"
+	// After and Before bound when the recording was last modified.
+	After, Before time.Time
+	Codec         string // see StoredRecording.Codec
+	// MinDuration and MinSize bound the recording from below, MaxDuration
+	// and MaxSize from above unless they are zero.
+	MinDuration, MaxDuration time.Duration
+	MinSize, MaxSize         int64
+}
+
+// match checks what the store lists of r; matchAudio checks the rest once r
+// is described.
+func (f RecordingFilter) match(r StoredRecording) bool {
+	return strings.HasPrefix(r.Name, f.Prefix) &&
+		(f.Format == "" || r.Format == f.Format) &&
+		inWindow(r.Modified, f.After, f.Before) &&
+		r.Size >= f.MinSize && (f.MaxSize == 0 || r.Size <= f.MaxSize)
+}
+
+func (f RecordingFilter) matchAudio(r StoredRecording) bool {
+	return (f.Codec == "" || r.Codec == f.Codec) &&
+		r.Duration >= f.MinDuration && (f.MaxDuration == 0 || r.Duration <= f.MaxDuration)
+}
+
+// describes reports whether matching needs the recordings described.
+func (f RecordingFilter) describes() bool {
+	return f.Codec != "" || f.MinDuration > 0 || f.MaxDuration > 0
+}
+
+// DeviceFilter narrows a list of devices. Fields left zero match any device.
+type DeviceFilter struct {
+	Direction string // "capture" or "playback"
+	Contains  string // in the ID or name, ignoring case
+}
+
+func (f DeviceFilter) match(d Device) bool {
+	contains := strings.ToLower(f.Contains)
+	return (f.Direction == "" || (f.Direction == captureDirection && d.Capture) || (f.Direction == playbackDirection && d.Playback)) &&
+		(strings.Contains(strings.ToLower(d.ID), contains) || strings.Contains(strings.ToLower(d.Name), contains))
+}
+
+// ActiveStream is a GetAudio stream or Play call in progress, its End not
+// set yet.
+type ActiveStream struct {
+	StreamRecord
+	Paused bool // paused with PauseStream
+}
+
+// Device is a device a resource's backend can open.
+type Device struct {
+	ID, Name, Driver  string
+	Capture, Playback bool
+	// DefaultCapture and DefaultPlayback are set on the devices used when
+	// none is configured.
+	DefaultCapture, DefaultPlayback bool
+}
+
+// ActiveStreamLister is implemented by clients of servers that list the
+// streams of a resource in progress, newest first.
+type ActiveStreamLister interface {
+	ListActiveStreams(ctx context.Context, pageSize int, pageToken string, filter StreamFilter) ([]ActiveStream, string, error)
+}
+
+// RecordingLister is implemented by clients of servers that list the
+// recordings in their store, by name.
+type RecordingLister interface {
+	ListRecordings(ctx context.Context, pageSize int, pageToken string, filter RecordingFilter) ([]StoredRecording, string, error)
+}
+
+// DeviceLister is implemented by clients of servers that list the devices
+// a resource's backend can open, by ID.
+type DeviceLister interface {
+	ListDevices(ctx context.Context, pageSize int, pageToken string, filter DeviceFilter) ([]Device, string, error)
+}
+
+func (s *audioServer) ListActiveStreams(ctx context.Context, req *pb.ListActiveStreamsRequest) (*pb.ListActiveStreamsResponse, error) {
+	before, size, err := seqPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDirection(req.Direction); err != nil {
+		return nil, err
+	}
+	filter := StreamFilter{
+		Direction: req.Direction,
+		RequestID: req.RequestId,
+		Subject:   req.Subject,
+		After:     fromUnixNano(req.AfterNanoseconds),
+		Before:    fromUnixNano(req.BeforeNanoseconds),
+	}
+	resp := &pb.ListActiveStreamsResponse{}
+	var last uint64
+	for _, e := range sharedMetrics.activeStreams(req.Name) {
+		if (before != 0 && e.Seq >= before) || !filter.match(e.Entry) {
+			continue
+		}
+		if len(resp.Streams) == size {
+			resp.NextPageToken = seqPageToken(last)
+			break
+		}
+		last = e.Seq
+		r := streamRecordToProto(e.Entry)
+		if e.Entry.RequestID != "" {
+			if st, err := s.streams.get(req.Name, e.Entry.RequestID); err == nil {
+				r.Paused = st.isPaused()
+			}
+		}
+		resp.Streams = append(resp.Streams, r)
+	}
+	return resp, nil
+}
+
+func (s *audioServer) ListRecordings(ctx context.Context, req *pb.ListRecordingsRequest) (*pb.ListRecordingsResponse, error) {
+	after, size, err := keyPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if req.Format != "" && !slices.Contains([]string{"wav", "flac", "chunks"}, req.Format) {
+		return nil, errorf(ErrInvalidArgument, "invalid recording format %q, want \"wav\", \"flac\" or \"chunks\"", req.Format)
+	}
+	recordings, err := ServerRecordings.list()
+	if err != nil {
+		return nil, err
+	}
+	filter := RecordingFilter{
+		Prefix:      req.Prefix,
+		Format:      req.Format,
+		After:       fromUnixNano(req.AfterNanoseconds),
+		Before:      fromUnixNano(req.BeforeNanoseconds),
+		Codec:       req.Codec,
+		MinDuration: time.Duration(req.MinDurationNanoseconds),
+		MaxDuration: time.Duration(req.MaxDurationNanoseconds),
+		MinSize:     req.MinSizeBytes,
+		MaxSize:     req.MaxSizeBytes,
+	}
+	// recordings are only read when the filter needs it and once they're
+	// on the page; those that can't be read are listed without their audio
+	// unless the filter needs it
+	describe := func(r StoredRecording) (StoredRecording, bool) {
+		err := ServerRecordings.describe(&r)
+		return r, err == nil
+	}
+	match := func(r StoredRecording) bool {
+		if !filter.match(r) {
+			return false
+		}
+		if !filter.describes() {
+			return true
+		}
+		r, ok := describe(r)
+		return ok && filter.matchAudio(r)
+	}
+	// names sort before any name they are a prefix of with the NUL
+	key := func(r StoredRecording) string { return r.Name + "\x00" + r.Format }
+	page, next := pageByKey(recordings, key, after, size, match)
+	resp := &pb.ListRecordingsResponse{Recordings: make([]*pb.StoredRecording, len(page)), NextPageToken: next}
+	for i, r := range page {
+		r, _ = describe(r)
+		resp.Recordings[i] = storedRecordingToProto(r)
+	}
+	return resp, nil
+}
+
+func (s *audioServer) ListDevices(ctx context.Context, req *pb.ListDevicesRequest) (*pb.ListDevicesResponse, error) {
+	after, size, err := keyPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDirection(req.Direction); err != nil {
+		return nil, err
+	}
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	list, err := resourceDevices(a)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]Device, len(list.devices))
+	for i, d := range list.devices {
+		devices[i] = Device{
+			ID:              d.ID,
+			Name:            d.Name,
+			Driver:          d.Driver,
+			Capture:         d.Capture,
+			Playback:        d.Playback,
+			DefaultCapture:  d.ID == list.defaultCapture,
+			DefaultPlayback: d.ID == list.defaultPlayback,
+		}
+	}
+	slices.SortFunc(devices, func(a, b Device) int { return strings.Compare(a.ID, b.ID) })
+	filter := DeviceFilter{Direction: req.Direction, Contains: req.Contains}
+	page, next := pageByKey(devices, func(d Device) string { return d.ID }, after, size, filter.match)
+	resp := &pb.ListDevicesResponse{Devices: make([]*pb.Device, len(page)), NextPageToken: next}
+	for i, d := range page {
+		resp.Devices[i] = &pb.Device{
+			Id:              d.ID,
+			Name:            d.Name,
+			Driver:          d.Driver,
+			Capture:         d.Capture,
+			Playback:        d.Playback,
+			DefaultCapture:  d.DefaultCapture,
+			DefaultPlayback: d.DefaultPlayback,
+		}
+	}
+	return resp, nil
+}
+
+func (c *audioClient) ListActiveStreams(ctx context.Context, pageSize int, pageToken string, filter StreamFilter) ([]ActiveStream, string, error) {
+	resp, err := c.client.ListActiveStreams(ctx, &pb.ListActiveStreamsRequest{
+		Name:              c.name,
+		PageSize:          int32(pageSize),
+		PageToken:         pageToken,
+		Direction:         filter.Direction,
+		RequestId:         filter.RequestID,
+		Subject:           filter.Subject,
+		AfterNanoseconds:  toUnixNano(filter.After),
+		BeforeNanoseconds: toUnixNano(filter.Before),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	streams := make([]ActiveStream, len(resp.Streams))
+	for i, r := range resp.Streams {
+		streams[i] = ActiveStream{StreamRecord: streamRecordFromProto(c.name, r), Paused: r.Paused}
+	}
+	return streams, resp.NextPageToken, nil
+}
+
+func (c *audioClient) ListRecordings(ctx context.Context, pageSize int, pageToken string, filter RecordingFilter) ([]StoredRecording, string, error) {
+	resp, err := c.client.ListRecordings(ctx, &pb.ListRecordingsRequest{
+		Name:                   c.name,
+		PageSize:               int32(pageSize),
+		PageToken:              pageToken,
+		Prefix:                 filter.Prefix,
+		Format:                 filter.Format,
+		AfterNanoseconds:       toUnixNano(filter.After),
+		BeforeNanoseconds:      toUnixNano(filter.Before),
+		Codec:                  filter.Codec,
+		MinDurationNanoseconds: int64(filter.MinDuration),
+		MaxDurationNanoseconds: int64(filter.MaxDuration),
+		MinSizeBytes:           filter.MinSize,
+		MaxSizeBytes:           filter.MaxSize,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	recordings := make([]StoredRecording, len(resp.Recordings))
+	for i, r := range resp.Recordings {
+		recordings[i] = storedRecordingFromProto(r)
+	}
+	return recordings, resp.NextPageToken, nil
+}
+
+func (c *audioClient) ListDevices(ctx context.Context, pageSize int, pageToken string, filter DeviceFilter) ([]Device, string, error) {
+	resp, err := c.client.ListDevices(ctx, &pb.ListDevicesRequest{
+		Name:      c.name,
+		PageSize:  int32(pageSize),
+		PageToken: pageToken,
+		Direction: filter.Direction,
+		Contains:  filter.Contains,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	devices := make([]Device, len(resp.Devices))
+	for i, d := range resp.Devices {
+		devices[i] = Device{
+			ID:              d.Id,
+			Name:            d.Name,
+			Driver:          d.Driver,
+			Capture:         d.Capture,
+			Playback:        d.Playback,
+			DefaultCapture:  d.DefaultCapture,
+			DefaultPlayback: d.DefaultPlayback,
+		}
+	}
+	return devices, resp.NextPageToken, nil
+}
@@ -0,0 +1,165 @@
+package audio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestListRecordings(t *testing.T) {
+	dir := t.TempDir()
+	prev := ServerRecordings.Dir
+	ServerRecordings.Dir = dir
+	defer func() { ServerRecordings.Dir = prev }()
+	for _, name := range []string{"door.wav", "door.chunks", "doorbell.wav", "hall.wav", "notes.txt", ".partial.wav"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "hall.wav"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	c := serveAudio(t, newBurstSource(1, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})).(RecordingLister)
+	ctx := context.Background()
+	list := func(size int, filter RecordingFilter) []string {
+		t.Helper()
+		var names []string
+		token := ""
+		for {
+			page, next, err := c.ListRecordings(ctx, size, token, filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page) > size {
+				t.Fatalf("got a page of %d, want at most %d", len(page), size)
+			}
+			for _, r := range page {
+				names = append(names, r.Name+"."+r.Format)
+			}
+			if next == "" {
+				return names
+			}
+			token = next
+		}
+	}
+	for _, tc := range []struct {
+		filter RecordingFilter
+		want   []string
+	}{
+		{RecordingFilter{}, []string{"door.chunks", "door.wav", "doorbell.wav", "hall.wav"}},
+		{RecordingFilter{Prefix: "door"}, []string{"door.chunks", "door.wav", "doorbell.wav"}},
+		{RecordingFilter{Format: "wav"}, []string{"door.wav", "doorbell.wav", "hall.wav"}},
+		{RecordingFilter{Before: time.Now().Add(-time.Minute)}, []string{"hall.wav"}},
+	} {
+		for _, size := range []int{1, 2, 10} {
+			if got := list(size, tc.filter); len(got) != len(tc.want) || (len(got) > 0 && (got[0] != tc.want[0] || got[len(got)-1] != tc.want[len(tc.want)-1])) {
+				t.Errorf("%+v in pages of %d listed %q, want %q", tc.filter, size, got, tc.want)
+			}
+		}
+	}
+
+	page, _, err := c.ListRecordings(ctx, 1, "", RecordingFilter{Prefix: "hall"})
+	if err != nil || len(page) != 1 || page[0].Size != 4 || page[0].Modified.Unix() != old.Unix() {
+		t.Errorf("listed %+v: %v", page, err)
+	}
+	if _, _, err := c.ListRecordings(ctx, 1, "", RecordingFilter{Format: "mp3"}); err == nil {
+		t.Error("accepted an unknown format")
+	}
+	if _, _, err := c.ListRecordings(ctx, -1, "", RecordingFilter{}); err == nil {
+		t.Error("accepted a negative page size")
+	}
+}
+
+func TestListActiveStreams(t *testing.T) {
+	// the source never starts, so streams stay open until canceled
+	src := newBurstSource(1, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.Named = Named("active-mic").AsNamed()
+	c := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	lister := c.(ActiveStreamLister)
+	// each stream is listed before the next starts, so they are in order
+	for i, id := range []string{"one", "two", "three"} {
+		if _, err := c.GetAudio(ctx, Pcm16.String(), 0, 0, 0, WithRequestID(id)); err != nil {
+			t.Fatal(err)
+		}
+		for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+			streams, _, err := lister.ListActiveStreams(ctx, 0, "", StreamFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(streams) == i+1 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("listed %d active streams, want %d", len(streams), i+1)
+			}
+		}
+	}
+	if err := c.(StreamController).PauseStream(ctx, "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	page, next, err := lister.ListActiveStreams(ctx, 2, "", StreamFilter{})
+	if err != nil || len(page) != 2 || next == "" {
+		t.Fatalf("first page of two is %+v, next %q: %v", page, next, err)
+	}
+	if page[0].RequestID != "three" || page[1].RequestID != "two" || !page[1].Paused || page[0].Paused || !page[1].End.IsZero() {
+		t.Errorf("first page is %+v", page)
+	}
+	if page, next, err = lister.ListActiveStreams(ctx, 2, next, StreamFilter{}); err != nil || len(page) != 1 || page[0].RequestID != "one" || next != "" {
+		t.Errorf("second page is %+v, next %q: %v", page, next, err)
+	}
+	if page, _, _ := lister.ListActiveStreams(ctx, 0, "", StreamFilter{Direction: "playback"}); len(page) != 0 {
+		t.Errorf("listed %+v playing", page)
+	}
+	if page, _, _ := lister.ListActiveStreams(ctx, 0, "", StreamFilter{RequestID: "one"}); len(page) != 1 || page[0].Direction != "capture" {
+		t.Errorf("listed %+v for request one", page)
+	}
+	if _, _, err := lister.ListActiveStreams(ctx, 0, "", StreamFilter{Direction: "sideways"}); err == nil {
+		t.Error("accepted an unknown direction")
+	}
+}
+
+func TestListDevices(t *testing.T) {
+	prevOpen, prevList := openWASAPI, listWASAPIEndpoints
+	openWASAPI = func(string, bool, pcmParams) (pcmDevice, error) { return nil, errFakeXrun }
+	listWASAPIEndpoints = func() ([]wasapiEndpoint, error) {
+		return []wasapiEndpoint{
+			{ID: "{0.0.1}.{c}", Name: "USB Microphone", Capture: true},
+			{ID: "{0.0.0}.{a}", Name: "Speakers", Default: true},
+			{ID: "{0.0.1}.{b}", Name: "Microphone Array", Capture: true, Default: true},
+			{ID: "{0.0.0}.{d}", Name: "USB Speaker"},
+		}, nil
+	}
+	defer func() { openWASAPI, listWASAPIEndpoints = prevOpen, prevList }()
+	a, err := NewWASAPI(Named("kiosk"), WASAPIConfig{}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lister := serveAudio(t, a).(DeviceLister)
+	ctx := context.Background()
+
+	page, next, err := lister.ListDevices(ctx, 3, "", DeviceFilter{})
+	if err != nil || len(page) != 3 || next == "" {
+		t.Fatalf("first page of three is %+v, next %q: %v", page, next, err)
+	}
+	if page[0].ID != "{0.0.0}.{a}" || !page[0].DefaultPlayback || page[0].DefaultCapture || page[1].ID != "{0.0.0}.{d}" || page[2].ID != "{0.0.1}.{b}" || !page[2].DefaultCapture {
+		t.Errorf("first page is %+v", page)
+	}
+	if page, next, err = lister.ListDevices(ctx, 3, next, DeviceFilter{}); err != nil || len(page) != 1 || page[0].Name != "USB Microphone" || next != "" {
+		t.Errorf("second page is %+v, next %q: %v", page, next, err)
+	}
+	if page, _, _ := lister.ListDevices(ctx, 0, "", DeviceFilter{Direction: "capture", Contains: "usb"}); len(page) != 1 || page[0].Name != "USB Microphone" || page[0].Driver != "wasapi" {
+		t.Errorf("listed %+v for usb capture", page)
+	}
+	if _, _, err := lister.ListDevices(ctx, 0, "not base64!", DeviceFilter{}); err == nil {
+		t.Error("accepted a nonsense page token")
+	}
+}
@@ -0,0 +1,162 @@
+package audio
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// logVerbosity is the level of the server's logger and of the loggers it
+// keeps with it, the server's subloggers and its resources', which can be
+// changed while the server runs, as restarting it would end its streams. A
+// level set for a while goes back to the ones before once the time is up.
+type logVerbosity struct {
+	logger logging.Logger
+
+	mu      sync.Mutex
+	loggers map[string]logging.Logger // the others, by what they log for
+	level   *logging.Level            // set for all of them, nil unless one is in force
+	bases   map[string]logging.Level  // the levels a temporary one goes back to
+	base    *logging.Level            // the level in force before a temporary one
+	revert  *time.Timer               // nil unless the level is temporary
+	until   time.Time
+}
+
+// serverVerbosity is the level of serverLogger, and of the loggers the
+// server makes from it and the resources it serves.
+var serverVerbosity = &logVerbosity{logger: serverLogger, loggers: map[string]logging.Logger{}}
+
+// sublogger returns serverLogger's sublogger for name, the same one every
+// time, at the level of the server.
+func (v *logVerbosity) sublogger(name string) logging.Logger {
+	v.mu.Lock()
+	l, ok := v.loggers[name]
+	v.mu.Unlock()
+	if ok {
+		return l
+	}
+	return v.track(name, v.logger.Sublogger(name))
+}
+
+// track keeps l at the level set for the server from now on, in place of
+// the logger tracked for name before, and returns it.
+func (v *logVerbosity) track(name string, l logging.Logger) logging.Logger {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.level != nil {
+		if v.revert != nil {
+			v.bases[name] = l.GetLevel()
+		}
+		l.SetLevel(*v.level)
+	}
+	v.loggers[name] = l
+	return l
+}
+
+// registerModel registers an Audio model whose resources log at the level
+// set for the server, along with its own loggers.
+func registerModel[C resource.ConfigValidator](model resource.Model, reg resource.Registration[Audio, C]) {
+	construct := reg.Constructor
+	reg.Constructor = func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+		return construct(ctx, deps, conf, serverVerbosity.track(conf.ResourceName().String(), logger))
+	}
+	resource.RegisterComponent(API, model, reg)
+}
+
+// set changes the level, for d or, if d is zero, until it is changed again,
+// and returns when it goes back, zero if it doesn't.
+func (v *logVerbosity) set(level logging.Level, d time.Duration) time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.revert != nil {
+		v.revert.Stop()
+	} else {
+		v.bases = map[string]logging.Level{"": v.logger.GetLevel()}
+		for name, l := range v.loggers {
+			v.bases[name] = l.GetLevel()
+		}
+		v.base = v.level
+	}
+	v.level = &level
+	v.logger.SetLevel(level)
+	for _, l := range v.loggers {
+		l.SetLevel(level)
+	}
+	if d == 0 {
+		v.revert, v.until, v.bases = nil, time.Time{}, nil
+		return time.Time{}
+	}
+	var t *time.Timer
+	t = time.AfterFunc(d, func() {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		// a timer stopped too late to keep it from firing has been replaced
+		if v.revert == t {
+			v.logger.SetLevel(v.bases[""])
+			for name, l := range v.loggers {
+				if base, ok := v.bases[name]; ok {
+					l.SetLevel(base)
+				}
+			}
+			v.level, v.bases = v.base, nil
+			v.revert, v.until = nil, time.Time{}
+		}
+	})
+	v.revert, v.until = t, time.Now().Add(d)
+	return v.until
+}
+
+// get returns the level and when it goes back, zero if it doesn't.
+func (v *logVerbosity) get() (logging.Level, time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.logger.GetLevel(), v.until
+}
+
+// setLogLevelCommand handles {"set_log_level": {"level": "debug",
+// "seconds": 600, "chunks": true}} for the named resource. Without a level
+// it only reports the current one.
+func setLogLevelCommand(name string, params map[string]interface{}) (map[string]interface{}, error) {
+	seconds, err := commandNumber(params, "set_log_level", "seconds", 0)
+	if err != nil {
+		return nil, err
+	}
+	if seconds < 0 {
+		return nil, errorf(ErrInvalidArgument, "set_log_level seconds can't be negative")
+	}
+	d := time.Duration(seconds * float64(time.Second))
+	if v, ok := params["level"]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errorf(ErrInvalidArgument, "set_log_level level must be a string, got %T", v)
+		}
+		level, err := logging.LevelFromString(s)
+		if err != nil {
+			return nil, errorf(ErrInvalidArgument, "%w", err)
+		}
+		serverVerbosity.set(level, d)
+		serverLogger.Infow("log level changed", "level", level, "seconds", seconds)
+	}
+	level, until := serverVerbosity.get()
+	resp := map[string]interface{}{"level": strings.ToLower(level.String()), "until": formatTime(until)}
+	if chunks, ok := params["chunks"]; ok {
+		on, ok := chunks.(bool)
+		if !ok {
+			return nil, errorf(ErrInvalidArgument, "set_log_level chunks must be true or false, got %T", chunks)
+		}
+		// chunk logging is heavy enough to be always temporary
+		chunkLogging := time.Duration(0)
+		if on {
+			chunkLogging = maxChunkLogging
+			if d > 0 {
+				chunkLogging = min(d, maxChunkLogging)
+			}
+		}
+		resp["chunks_until"] = formatTime(sharedChunkLogging.set(name, chunkLogging))
+	}
+	return resp, nil
+}
@@ -0,0 +1,379 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// LoopbackModel is a pure-software Audio with no hardware behind it: what
+// Play writes comes back out of GetAudio after the configured latency, on a
+// simulated device clock. It lets codecs, mixing and timing be tested end to
+// end on CI machines without sound cards.
+var LoopbackModel = resource.NewModel("olivia", "audio", "loopback")
+
+// Defaults for LoopbackConfig.
+const (
+	defaultLoopbackSampleRate = 48000
+	defaultLoopbackChannels   = 1
+	defaultLoopbackChunk      = 10 * time.Millisecond
+	// chunks a slow GetAudio reader can fall behind before the device drops them
+	loopbackReaderBuffer = 50
+)
+
+// LoopbackConfig is the configuration of the loopback model.
+type LoopbackConfig struct {
+	SampleRate int `json:"sample_rate,omitempty"` // device rate, 48 kHz if zero
+	Channels   int `json:"channels,omitempty"`    // device channels, 1 if zero
+	ChunkMs    int `json:"chunk_ms,omitempty"`    // capture period, 10ms if zero
+	LatencyMs  int `json:"latency_ms,omitempty"`  // delay from Play to capture
+
+	// Clock paces the device, SystemClock if nil. Tests can drive it with a
+	// ManualClock.
+	Clock ClockSource `json:"-"`
+}
+
+// Validate checks the loopback configuration.
+func (c *LoopbackConfig) Validate(path string) ([]string, []string, error) {
+	switch {
+	case c.SampleRate < 0:
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate cannot be negative"))
+	case c.Channels < 0:
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("channels cannot be negative"))
+	case c.ChunkMs < 0:
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("chunk_ms cannot be negative"))
+	case c.LatencyMs < 0:
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("latency_ms cannot be negative"))
+	}
+	return nil, nil, nil
+}
+
+func init() {
+	registerModel(LoopbackModel, resource.Registration[Audio, *LoopbackConfig]{
+		AttributeMapConverter: migratingConverter[*LoopbackConfig](LoopbackModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*LoopbackConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewLoopback(conf.ResourceName(), *cfg, logger), nil
+		},
+	})
+}
+
+// loopback keeps a timeline of device frames. The clock emits one chunk per
+// period starting at frame pos; audio handed to Play is scheduled at
+// pos+latency, or right after whatever is still queued.
+type loopback struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	info     AudioInfo // device format, always pcm32_float internally
+	chunk    int       // frames per capture period
+	latency  int       // frames between Play and capture
+	pacer    *samplePacer
+	prepared *preparedClips // decoded clips waiting for CommitPlayback
+	logger   logging.Logger
+
+	mu           sync.Mutex
+	pos          int64     // first frame of the next chunk
+	pending      []float32 // queued playback, interleaved
+	pendingStart int64     // frame of pending[0]
+	ticked       chan struct{}
+	readers      map[chan loopbackBlock]struct{}
+	closed       bool
+
+	cancel context.CancelFunc
+	done   chan struct{} // closed when the clock stops
+}
+
+// NewLoopback returns a running loopback device. Most callers configure it
+// through LoopbackModel; tests can construct it directly.
+func NewLoopback(name resource.Name, cfg LoopbackConfig, logger logging.Logger) Audio {
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = defaultLoopbackSampleRate
+	}
+	if cfg.Channels == 0 {
+		cfg.Channels = defaultLoopbackChannels
+	}
+	period := defaultLoopbackChunk
+	if cfg.ChunkMs != 0 {
+		period = time.Duration(cfg.ChunkMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &loopback{
+		Named:    name.AsNamed(),
+		info:     AudioInfo{Format: Pcm32Float, SampleRate: cfg.SampleRate, Channels: cfg.Channels},
+		chunk:    int(period.Seconds() * float64(cfg.SampleRate)),
+		latency:  cfg.LatencyMs * cfg.SampleRate / 1000,
+		pacer:    newSamplePacer(cfg.Clock, cfg.SampleRate),
+		prepared: newPreparedClips(),
+		logger:   logger,
+		ticked:   make(chan struct{}),
+		readers:  map[chan loopbackBlock]struct{}{},
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go l.clock(ctx)
+	return l
+}
+
+// loopbackBlock is one period of device output and when it was captured.
+type loopbackBlock struct {
+	samples []float32
+	at      time.Time
+}
+
+// clock emits a chunk every period.
+func (l *loopback) clock(ctx context.Context) {
+	defer close(l.done)
+	for {
+		at, err := l.pacer.wait(ctx, l.chunk)
+		if err != nil {
+			return
+		}
+		l.tick(at)
+	}
+}
+
+func (l *loopback) tick(at time.Time) {
+	ch := l.info.Channels
+	out := make([]float32, l.chunk*ch)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	end := l.pos + int64(l.chunk)
+	// copy the part of the queue that falls inside [pos, end)
+	queuedEnd := l.pendingStart + int64(len(l.pending)/ch)
+	if from, to := max(l.pos, l.pendingStart), min(end, queuedEnd); from < to {
+		copy(out[(from-l.pos)*int64(ch):], l.pending[(from-l.pendingStart)*int64(ch):(to-l.pendingStart)*int64(ch)])
+	}
+	if consumed := min(end, queuedEnd) - l.pendingStart; consumed > 0 {
+		l.pending = l.pending[consumed*int64(ch):]
+		l.pendingStart += consumed
+	}
+	l.pos = end
+
+	for r := range l.readers {
+		select {
+		case r <- loopbackBlock{samples: out, at: at}:
+		default:
+			// a real device overruns when it isn't read in time
+			l.logger.Debugw("loopback reader fell behind, dropping a chunk", "name", l.Name())
+		}
+	}
+	close(l.ticked)
+	l.ticked = make(chan struct{})
+}
+
+// Play schedules data on the device timeline and returns once it has been
+// played out.
+func (l *loopback) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	samples, err := l.decode(ctx, data, codec, sampleRate, channels)
+	if err != nil {
+		return err
+	}
+	return l.play(ctx, samples, 0)
+}
+
+// decode converts a clip to the device format.
+func (l *loopback) decode(ctx context.Context, data []byte, codec string, sampleRate, channels int) ([]float32, error) {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStrictPlayback(ctx, l.info, format, sampleRate, channels); err != nil {
+		return nil, err
+	}
+	samples, err := decodePCM(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return nil, errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	samples = remix(samples, channels, l.info.Channels)
+	if sampleRate != l.info.SampleRate {
+		samples = newResampler(sampleRate, l.info.SampleRate, l.info.Channels).process(samples)
+	}
+	return samples, nil
+}
+
+// play queues samples in the device format to start no earlier than frame
+// from plus the latency, and waits for them to play out.
+func (l *loopback) play(ctx context.Context, samples []float32, from int64) error {
+	ch := l.info.Channels
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return errors.New("loopback device is closed")
+	}
+	queuedEnd := l.pendingStart + int64(len(l.pending)/ch)
+	start := max(l.pos, from) + int64(l.latency)
+	if len(l.pending) == 0 {
+		l.pendingStart = start
+	} else if start > queuedEnd {
+		// keep the queue contiguous with silence up to the new audio
+		l.pending = append(l.pending, make([]float32, (start-queuedEnd)*int64(ch))...)
+	}
+	l.pending = append(l.pending, samples...)
+	end := l.pendingStart + int64(len(l.pending)/ch)
+	l.mu.Unlock()
+
+	for {
+		l.mu.Lock()
+		played, closed, ticked := l.pos >= end, l.closed, l.ticked
+		l.mu.Unlock()
+		if played {
+			return nil
+		}
+		if closed {
+			return errors.New("loopback device closed during playback")
+		}
+		select {
+		case <-ticked:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// PreparePlayback decodes and resamples a clip to the device format so that
+// committing it only has to queue the samples.
+func (l *loopback) PreparePlayback(ctx context.Context, data []byte, codec string, sampleRate, channels int) (string, error) {
+	samples, err := l.decode(ctx, data, codec, sampleRate, channels)
+	if err != nil {
+		return "", err
+	}
+	return l.prepared.add(&preparedClip{resource: l.Name().ShortName(), samples: samples}), nil
+}
+
+// CommitPlayback queues a prepared clip on the frame due at the given time
+// on the device clock, so it comes out of capture exactly the configured
+// latency later.
+func (l *loopback) CommitPlayback(ctx context.Context, handle string, at time.Time) error {
+	clip, err := l.prepared.take(l.Name().ShortName(), handle)
+	if err != nil {
+		return err
+	}
+	clock := l.pacer.clock
+	if at.IsZero() {
+		at = clock.Now()
+	} else if err := clock.Sleep(ctx, at.Sub(clock.Now())); err != nil {
+		return err
+	}
+	return l.play(ctx, clip.samples, l.pacer.frame(at))
+}
+
+// ReleasePlayback drops a prepared clip.
+func (l *loopback) ReleasePlayback(ctx context.Context, handle string) error {
+	_, err := l.prepared.take(l.Name().ShortName(), handle)
+	return err
+}
+
+// GetAudio streams the device output in the requested raw pcm format. A
+// positive durationSeconds ends the stream after that much audio.
+func (l *loopback) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, err
+	}
+	o := NewGetAudioOptions(opts...)
+	conv := transcoderFor(format, o)
+
+	in := make(chan loopbackBlock, loopbackReaderBuffer)
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, errors.New("loopback device is closed")
+	}
+	l.readers[in] = struct{}{}
+	l.mu.Unlock()
+
+	remaining := -1
+	if durationSeconds > 0 {
+		remaining = int(float64(durationSeconds) * float64(l.info.SampleRate))
+	}
+	out := make(chan *AudioChunk)
+	go func() {
+		defer close(out)
+		defer func() {
+			l.mu.Lock()
+			delete(l.readers, in)
+			l.mu.Unlock()
+		}()
+		var seq int64
+		for remaining != 0 {
+			var block loopbackBlock
+			select {
+			case <-ctx.Done():
+				return
+			case b, ok := <-in:
+				if !ok {
+					return
+				}
+				block = b
+			}
+			samples := block.samples
+			if frames := len(samples) / l.info.Channels; remaining > 0 && frames > remaining {
+				samples = samples[:remaining*l.info.Channels]
+			}
+			if remaining > 0 {
+				remaining -= len(samples) / l.info.Channels
+			}
+			info := l.info
+			chunk, err := conv.convertSamples(samples, &AudioChunk{Sequence: seq, Info: &info, Timestamp: block.at})
+			if err != nil {
+				chunk = &AudioChunk{Err: err}
+				remaining = 0
+			}
+			seq++
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (l *loopback) deviceClock() ClockSource { return l.pacer.clock }
+
+func (l *loopback) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, l, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close stops the clock and ends every open stream.
+func (l *loopback) Close(ctx context.Context) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	for r := range l.readers {
+		close(r)
+	}
+	l.readers = map[chan loopbackBlock]struct{}{}
+	// wake any Play waiting on the clock
+	close(l.ticked)
+	l.ticked = make(chan struct{})
+	l.mu.Unlock()
+	l.cancel()
+	<-l.done
+	return nil
+}
@@ -0,0 +1,90 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestLoopbackPlaysIntoCapture(t *testing.T) {
+	const rate = 16000
+	clock := NewManualClock(time.Unix(1000, 0))
+	a := NewLoopback(Named("loop"), LoopbackConfig{SampleRate: rate, Channels: 2, LatencyMs: 30, Clock: clock}, logging.NewTestLogger(t))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 25ms of mono at half scale, upmixed to the device's two channels
+	clip := make([]float32, rate/40)
+	for i := range clip {
+		clip[i] = 0.5
+	}
+	data, _ := encodePCM(clip, Pcm16)
+	started := clock.Now()
+	played := make(chan time.Time, 1)
+	go func() {
+		if err := a.Play(ctx, data, Pcm16.String(), rate, 1); err != nil {
+			t.Error(err)
+		}
+		played <- clock.Now()
+	}()
+	stop := runClock(clock, 10*time.Millisecond)
+
+	// read until a silent chunk follows the clip
+	var heard int
+	var first time.Time
+	for silent := false; !silent || heard == 0; {
+		var chunk *AudioChunk
+		select {
+		case chunk = <-chunks:
+		case <-ctx.Done():
+			t.Fatal("clip never played out")
+		}
+		samples, err := decodePCM(chunk.AudioData, Pcm16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		silent = true
+		for i := 0; i < len(samples); i += 2 {
+			if samples[i] == 0 {
+				continue
+			}
+			silent = false
+			if samples[i] != samples[i+1] || samples[i] < 0.49 || samples[i] > 0.51 {
+				t.Fatalf("captured %v %v, want the clip on both channels", samples[i], samples[i+1])
+			}
+			if heard == 0 {
+				first = chunk.Timestamp.Add(time.Duration(i/2) * time.Second / rate)
+			}
+			heard++
+		}
+	}
+	var returned time.Time
+	select {
+	case returned = <-played:
+	case <-ctx.Done():
+		t.Fatal("Play never returned")
+	}
+	stop()
+
+	if heard != len(clip) {
+		t.Fatalf("captured %d frames of the clip, want %d", heard, len(clip))
+	}
+	if d := first.Sub(started); d < 30*time.Millisecond || d > 40*time.Millisecond {
+		t.Fatalf("clip captured %v after Play, want the 30ms latency within a chunk", d)
+	}
+	if d := returned.Sub(first); d < 25*time.Millisecond {
+		t.Fatalf("Play returned %v after the clip started, before it had played out", d)
+	}
+	if err := a.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Play(ctx, data, Pcm16.String(), rate, 1); err == nil {
+		t.Fatal("closed loopback accepted playback")
+	}
+}
@@ -0,0 +1,194 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Loudness measurement follows EBU R128 / ITU-R BS.1770: K-weighted mean
+// square over 400ms blocks overlapping by 75%, gated at -70 LUFS and then
+// 10 LU below the ungated loudness.
+const (
+	loudnessBlock        = 0.4 // seconds
+	loudnessStep         = 0.1
+	loudnessAbsoluteGate = -70.0 // LUFS
+	loudnessRelativeGate = -10.0 // LU
+	// normalization never raises a clip's sample peak above this
+	normalizeCeilingDBFS = -1.0
+)
+
+// kWeighting returns the two stage filter BS.1770 measures through, a high
+// shelf modelling the head followed by a high pass.
+func kWeighting(rate, channels int) []*biquad {
+	fs := float64(rate)
+
+	// shelf, +4 dB above about 1.7 kHz
+	k := math.Tan(math.Pi * 1681.974450955533 / fs)
+	q := 0.7071752369554196
+	vh := math.Pow(10, 3.999843853973347/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := &biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+		z1: make([]float64, channels),
+		z2: make([]float64, channels),
+	}
+
+	// high pass at 38 Hz
+	k = math.Tan(math.Pi * 38.13547087602444 / fs)
+	q = 0.5003270373238773
+	a0 = 1 + k/q + k*k
+	highpass := &biquad{
+		b0: 1, b1: -2, b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+		z1: make([]float64, channels),
+		z2: make([]float64, channels),
+	}
+	return []*biquad{shelf, highpass}
+}
+
+// channelWeights are the BS.1770 weights of interleaved channels. 5.1 audio
+// drops its LFE and raises the surrounds; every other layout counts each
+// channel once.
+func channelWeights(channels int) []float64 {
+	if channels == 6 {
+		return []float64{1, 1, 1, 0, 1.41, 1.41}
+	}
+	w := make([]float64, channels)
+	for i := range w {
+		w[i] = 1
+	}
+	return w
+}
+
+// MeasureLoudness returns the integrated loudness of interleaved samples in
+// LUFS, or -Inf for silence. Clips shorter than one 400ms block are measured
+// as a single block, so short alert sounds still get a reading.
+func MeasureLoudness(samples []float32, sampleRate, channels int) float64 {
+	if sampleRate <= 0 || channels <= 0 {
+		return math.Inf(-1)
+	}
+	weighted := append([]float32(nil), samples...)
+	for _, f := range kWeighting(sampleRate, channels) {
+		f.process(weighted)
+	}
+	weights := channelWeights(channels)
+	frames := len(weighted) / channels
+	block := int(loudnessBlock * float64(sampleRate))
+	step := int(loudnessStep * float64(sampleRate))
+	if frames < block {
+		block = frames
+	}
+
+	// mean square of every block, summed over the weighted channels
+	var powers []float64
+	for start := 0; start+block <= frames && block > 0; start += step {
+		var z float64
+		for i := start; i < start+block; i++ {
+			for c := 0; c < channels; c++ {
+				s := float64(weighted[i*channels+c])
+				z += weights[c] * s * s
+			}
+		}
+		powers = append(powers, z/float64(block))
+	}
+
+	gated := func(threshold float64) (float64, int) {
+		var sum float64
+		var n int
+		for _, z := range powers {
+			if blockLoudness(z) > threshold {
+				sum += z
+				n++
+			}
+		}
+		return sum, n
+	}
+	sum, n := gated(loudnessAbsoluteGate)
+	if n == 0 {
+		return math.Inf(-1)
+	}
+	relative := blockLoudness(sum/float64(n)) + loudnessRelativeGate
+	if sum, n = gated(relative); n == 0 {
+		return math.Inf(-1)
+	}
+	return blockLoudness(sum / float64(n))
+}
+
+func blockLoudness(z float64) float64 {
+	return -0.691 + 10*math.Log10(z)
+}
+
+// loudnessGain returns the gain in dB that brings samples to targetLUFS,
+// lowered so the sample peak stays at or below -1 dBFS. Silence gets none.
+func loudnessGain(samples []float32, sampleRate, channels int, targetLUFS float64) float64 {
+	loudness := MeasureLoudness(samples, sampleRate, channels)
+	if math.IsInf(loudness, -1) {
+		return 0
+	}
+	gain := targetLUFS - loudness
+	if p := peak(samples); p > 0 {
+		gain = math.Min(gain, normalizeCeilingDBFS-dbfs(p))
+	}
+	return gain
+}
+
+// NormalizeLoudness returns a copy of a raw pcm clip scaled to targetLUFS,
+// -16 LUFS being a common choice for speech and alerts. The gain is limited
+// so the clip never peaks above -1 dBFS, which leaves very peaky clips
+// quieter than the target.
+func NormalizeLoudness(data []byte, codec string, sampleRate, channels int, targetLUFS float64) ([]byte, error) {
+	if targetLUFS >= 0 {
+		return nil, errorf(ErrInvalidArgument, "target loudness must be below 0 LUFS, got %g", targetLUFS)
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, errorf(ErrUnsupportedCodec, "loudness normalization needs raw pcm: %w", err)
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return nil, errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	samples, err := decodePCM(data, format)
+	if err != nil {
+		return nil, err
+	}
+	scale := float32(math.Pow(10, loudnessGain(samples, sampleRate, channels, targetLUFS)/20))
+	for i, s := range samples {
+		samples[i] = clip(s * scale)
+	}
+	return encodePCM(samples, format)
+}
+
+// NormalizedPlayer is implemented by clients that can have the server
+// normalize a clip's loudness before playing it.
+type NormalizedPlayer interface {
+	// PlayNormalized plays a raw pcm clip at targetLUFS, see
+	// NormalizeLoudness.
+	PlayNormalized(ctx context.Context, data []byte, codec string, sampleRate, channels int, targetLUFS float64) error
+}
+
+func (c *audioClient) PlayNormalized(ctx context.Context, data []byte, codec string, sampleRate, channels int, targetLUFS float64) error {
+	if targetLUFS == 0 {
+		return errors.New("PlayNormalized needs a target loudness")
+	}
+	_, err := c.client.Play(ctx, &pb.PlayRequest{
+		Name:          c.name,
+		AudioData:     data,
+		Info:          &pb.AudioInfo{Codec: codec, SampleRate: int32(sampleRate), NumChannels: int32(channels)},
+		NormalizeLufs: float32(targetLUFS),
+		Strict:        isStrictPlayback(ctx),
+		Convert:       isConvertPlayback(ctx),
+	})
+	return err
+}
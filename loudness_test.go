@@ -0,0 +1,132 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestMeasureLoudness(t *testing.T) {
+	level := math.Pow(10, -23.0/20)
+	sine := tone(48000, 5*48000, 1000, level)
+	silence := make([]float32, len(sine))
+	for _, tc := range []struct {
+		name     string
+		samples  []float32
+		channels int
+		want     float64
+	}{
+		// EBU Tech 3341 case 1: a -23 dBFS 1 kHz sine on both channels reads -23 LUFS
+		{"stereo", remix(sine, 1, 2), 2, -23},
+		{"mono", sine, 1, -26},
+		// the silence is gated out, all but the blocks straddling the end of
+		// the sine, where ungated it would read -29
+		{"gated", append(append([]float32(nil), sine...), silence...), 1, -26.1},
+		{"short", sine[:4800], 1, -26},
+	} {
+		if got := MeasureLoudness(tc.samples, 48000, tc.channels); math.Abs(got-tc.want) > 0.1 {
+			t.Errorf("%s: measured %.2f LUFS, want %v", tc.name, got, tc.want)
+		}
+	}
+	if got := MeasureLoudness(silence, 48000, 1); !math.IsInf(got, -1) {
+		t.Errorf("silence measured %v LUFS", got)
+	}
+}
+
+func TestNormalizeLoudness(t *testing.T) {
+	quiet, _ := encodePCM(tone(16000, 16000, 500, 0.01), Pcm16)
+	loud, err := NormalizeLoudness(quiet, "pcm16", 16000, 1, -20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, _ := decodePCM(loud, Pcm16)
+	if got := MeasureLoudness(samples, 16000, 1); math.Abs(got+20) > 0.1 {
+		t.Errorf("normalized to %.2f LUFS, want -20", got)
+	}
+
+	// a sine at -3 LUFS would peak at full scale, so it stops at -1 dBFS
+	loud, err = NormalizeLoudness(quiet, "pcm16", 16000, 1, -3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, _ = decodePCM(loud, Pcm16)
+	if p := dbfs(peak(samples)); math.Abs(p+1) > 0.05 {
+		t.Errorf("peaks at %.2f dBFS, want -1", p)
+	}
+
+	if _, err := NormalizeLoudness(quiet, "mp3", 16000, 1, -20); err == nil {
+		t.Error("normalized mp3")
+	}
+}
+
+// playRecorder is a burstSource remembering what it was asked to play.
+type playRecorder struct {
+	*burstSource
+	mu     sync.Mutex
+	played []byte
+}
+
+func (p *playRecorder) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.played = data
+	return nil
+}
+
+func TestPlayNormalized(t *testing.T) {
+	rec := &playRecorder{burstSource: newBurstSource(0, AudioInfo{})}
+	client := serveAudio(t, rec)
+	clip, _ := encodePCM(remix(tone(16000, 8000, 440, 0.05), 1, 2), Pcm16)
+	if err := client.(NormalizedPlayer).PlayNormalized(context.Background(), clip, "pcm16", 16000, 2, -16); err != nil {
+		t.Fatal(err)
+	}
+	rec.mu.Lock()
+	samples, _ := decodePCM(rec.played, Pcm16)
+	rec.mu.Unlock()
+	if got := MeasureLoudness(samples, 16000, 2); math.Abs(got+16) > 0.1 {
+		t.Errorf("played at %.2f LUFS, want -16", got)
+	}
+
+	// plain Play is untouched
+	if err := client.Play(context.Background(), clip, "pcm16", 16000, 2); err != nil {
+		t.Fatal(err)
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if string(rec.played) != string(clip) {
+		t.Error("Play changed the clip")
+	}
+}
+
+func TestRecordingNormalizesSegments(t *testing.T) {
+	dir := t.TempDir()
+	r := &Recording{logger: logging.NewTestLogger(t)}
+	info := AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}
+	chunks := make(chan *AudioChunk, 10)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		data, _ := encodePCM(tone(8000, 800, 300, 0.02), Pcm16)
+		chunks <- &AudioChunk{AudioData: data, Info: &info, Timestamp: start.Add(time.Duration(i) * 100 * time.Millisecond)}
+	}
+	close(chunks)
+	if err := r.record(chunks, "mic", RecordingConfig{Dir: dir, SegmentDuration: time.Minute, NormalizeLUFS: -18}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "mic-20240101T000000.000Z.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, _ := decodePCM(b[44:], Pcm16)
+	if len(samples) != 8000 {
+		t.Fatalf("segment has %d samples, want 8000", len(samples))
+	}
+	if got := MeasureLoudness(samples, 8000, 1); math.Abs(got+18) > 0.1 {
+		t.Errorf("segment is %.2f LUFS, want -18", got)
+	}
+}
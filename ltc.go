@@ -0,0 +1,327 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Linear timecode (SMPTE 12M LTC) carries one 80-bit frame per video frame,
+// biphase mark coded: the level flips at the start of every bit and again in
+// the middle of a one. Each field is BCD, least significant bit first, with
+// user bits between the fields and a sync word at the end.
+const (
+	ltcFrameBits = 80
+	// the sync word, bits 64 to 79 in order
+	ltcSync = 0b0011111111111101
+)
+
+// Timecode is a decoded LTC frame.
+type Timecode struct {
+	Hours, Minutes, Seconds, Frames int
+	DropFrame                       bool
+	// FrameRate is the nominal rate, 24, 25 or 30, measured from the
+	// signal; drop frame timecode runs at 30000/1001 frames per second.
+	FrameRate int
+	UserBits  uint32
+	// Offset is from the start of the chunk the frame was decoded in to the
+	// start of the frame, negative when it began in an earlier chunk.
+	Offset time.Duration
+}
+
+// String formats the timecode as hh:mm:ss:ff, or hh:mm:ss;ff for drop frame.
+func (tc Timecode) String() string {
+	sep := ":"
+	if tc.DropFrame {
+		sep = ";"
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", tc.Hours, tc.Minutes, tc.Seconds, sep, tc.Frames)
+}
+
+// ltcField is where one BCD digit of the timecode sits in the frame.
+type ltcField struct {
+	bit, width int
+}
+
+var (
+	ltcFrameUnits  = ltcField{0, 4}
+	ltcFrameTens   = ltcField{8, 2}
+	ltcSecondUnits = ltcField{16, 4}
+	ltcSecondTens  = ltcField{24, 3}
+	ltcMinuteUnits = ltcField{32, 4}
+	ltcMinuteTens  = ltcField{40, 3}
+	ltcHourUnits   = ltcField{48, 4}
+	ltcHourTens    = ltcField{56, 2}
+	// the user bits come in eight groups of four
+	ltcUserGroups = [8]int{4, 12, 20, 28, 36, 44, 52, 60}
+)
+
+const (
+	ltcDropFrameBit = 10
+	// the polarity correction bit makes the number of zeros in a frame even,
+	// so every frame starts at the same level. It moved for 25 fps.
+	ltcPolarityBit   = 27
+	ltcPolarityBit25 = 59
+)
+
+// ltcBits is one frame, bit i at index i.
+type ltcBits [ltcFrameBits]bool
+
+func (b *ltcBits) put(f ltcField, v int) {
+	for i := 0; i < f.width; i++ {
+		b[f.bit+i] = v>>i&1 == 1
+	}
+}
+
+func (b *ltcBits) get(f ltcField) int {
+	v := 0
+	for i := 0; i < f.width; i++ {
+		if b[f.bit+i] {
+			v |= 1 << i
+		}
+	}
+	return v
+}
+
+// encodeLTC returns the bits of tc's frame.
+func encodeLTC(tc Timecode) ltcBits {
+	var b ltcBits
+	b.put(ltcFrameUnits, tc.Frames%10)
+	b.put(ltcFrameTens, tc.Frames/10)
+	b.put(ltcSecondUnits, tc.Seconds%10)
+	b.put(ltcSecondTens, tc.Seconds/10)
+	b.put(ltcMinuteUnits, tc.Minutes%10)
+	b.put(ltcMinuteTens, tc.Minutes/10)
+	b.put(ltcHourUnits, tc.Hours%10)
+	b.put(ltcHourTens, tc.Hours/10)
+	b[ltcDropFrameBit] = tc.DropFrame
+	for g, bit := range ltcUserGroups {
+		b.put(ltcField{bit, 4}, int(tc.UserBits>>(4*g)&0xf))
+	}
+	for i := 0; i < 16; i++ {
+		b[64+i] = ltcSync>>(15-i)&1 == 1
+	}
+	zeros := 0
+	for _, one := range b {
+		if !one {
+			zeros++
+		}
+	}
+	if zeros%2 == 1 {
+		if tc.FrameRate == 25 {
+			b[ltcPolarityBit25] = true
+		} else {
+			b[ltcPolarityBit] = true
+		}
+	}
+	return b
+}
+
+// decodeLTC reads the timecode of a frame, without FrameRate and Offset.
+func decodeLTC(b ltcBits) Timecode {
+	tc := Timecode{
+		Frames:    b.get(ltcFrameTens)*10 + b.get(ltcFrameUnits),
+		Seconds:   b.get(ltcSecondTens)*10 + b.get(ltcSecondUnits),
+		Minutes:   b.get(ltcMinuteTens)*10 + b.get(ltcMinuteUnits),
+		Hours:     b.get(ltcHourTens)*10 + b.get(ltcHourUnits),
+		DropFrame: b[ltcDropFrameBit],
+	}
+	for g, bit := range ltcUserGroups {
+		tc.UserBits |= uint32(b.get(ltcField{bit, 4})) << (4 * g)
+	}
+	return tc
+}
+
+// ltcRate is a frame rate timecode can be generated at.
+type ltcRate struct {
+	nominal   int
+	dropFrame bool
+}
+
+// fps returns the frames per second of the rate.
+func (r ltcRate) fps() float64 {
+	if r.dropFrame {
+		return 30000.0 / 1001
+	}
+	return float64(r.nominal)
+}
+
+// label returns the timecode of frame n counted from midnight.
+func (r ltcRate) label(n int64) Timecode {
+	if r.dropFrame {
+		// frames 0 and 1 are skipped every minute except every tenth
+		tens, rem := n/17982, n%17982
+		n += 18 * tens
+		if rem >= 2 {
+			n += 2 * ((rem - 2) / 1798)
+		}
+	}
+	fps := int64(r.nominal)
+	return Timecode{
+		Frames:    int(n % fps),
+		Seconds:   int(n / fps % 60),
+		Minutes:   int(n / (fps * 60) % 60),
+		Hours:     int(n / (fps * 3600) % 24),
+		DropFrame: r.dropFrame,
+		FrameRate: r.nominal,
+	}
+}
+
+// ltcGenerator renders time of day LTC. Every frame starts at the same
+// level, so any stretch of time can be rendered on its own and clips
+// rendered back to back join without a glitch.
+type ltcGenerator struct {
+	rate  ltcRate
+	level float32
+	// the frame last rendered, by half bit
+	frame  int64
+	halves [2 * ltcFrameBits]float32
+	primed bool
+}
+
+// halfLevels returns the level of each half bit of frame n.
+func (g *ltcGenerator) halfLevels(n int64) *[2 * ltcFrameBits]float32 {
+	if g.primed && g.frame == n {
+		return &g.halves
+	}
+	bits := encodeLTC(g.rate.label(n))
+	level := g.level
+	for i, one := range bits {
+		g.halves[2*i] = level
+		if one {
+			level = -level
+		}
+		g.halves[2*i+1] = level
+		level = -level
+	}
+	g.frame, g.primed = n, true
+	return &g.halves
+}
+
+// fill writes the LTC of the time from start on channel ch of interleaved
+// samples at sampleRate.
+func (g *ltcGenerator) fill(samples []float32, channels, ch, sampleRate int, start time.Time) {
+	midnight := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	// in frames since midnight, kept apart from the fraction for precision
+	at := start.Sub(midnight).Seconds() * g.rate.fps()
+	whole := math.Floor(at)
+	frac := at - whole
+	step := g.rate.fps() / float64(sampleRate)
+	for i := ch; i < len(samples); i += channels {
+		pos := frac + float64(i/channels)*step
+		skip := math.Floor(pos)
+		half := int((pos - skip) * 2 * ltcFrameBits)
+		samples[i] = g.halfLevels(int64(whole + skip))[half]
+	}
+}
+
+// ltcDecoder reads LTC from one channel of a stream. It follows the bit
+// period as it goes, so any of the frame rates and varying playback speed
+// are read; 16 kHz and up leaves enough samples per half bit.
+type ltcDecoder struct {
+	sampleRate int
+	period     float64 // of a bit in samples, adapted to the signal
+	peak       float32 // decaying, for the switching threshold
+	decay      float32
+	high       bool
+	pos        int64 // samples seen
+	lastEdge   int64
+	half       bool  // the first half of a one was seen
+	halfStart  int64 // where that one started
+	bits       ltcBits
+	starts     [ltcFrameBits]int64 // where each bit started
+	n          int                 // bits seen, capped at a frame
+}
+
+func newLTCDecoder(sampleRate int) *ltcDecoder {
+	return &ltcDecoder{
+		sampleRate: sampleRate,
+		// 25 fps separates the half and whole bits of every rate
+		period: float64(sampleRate) / (25 * ltcFrameBits),
+		decay:  float32(math.Exp(-1 / (0.5 * float64(sampleRate)))),
+		// far enough back that the first edge is taken for a dropout
+		lastEdge: math.MinInt32,
+	}
+}
+
+// decode reads one channel of interleaved samples and returns the last
+// frame that ended in them.
+func (d *ltcDecoder) decode(samples []float32, channels, ch int) (Timecode, bool) {
+	var (
+		last  Timecode
+		found bool
+	)
+	chunkStart := d.pos
+	for i := ch; i < len(samples); i += channels {
+		s := samples[i]
+		d.peak = max(d.peak*d.decay, float32(math.Abs(float64(s))))
+		threshold := d.peak / 4
+		edge := (d.high && s < -threshold) || (!d.high && s > threshold)
+		if edge {
+			d.high = !d.high
+			if tc, ok := d.edge(d.pos); ok {
+				tc.Offset = time.Duration(d.starts[0]-chunkStart) * time.Second / time.Duration(d.sampleRate)
+				last, found = tc, true
+			}
+		}
+		d.pos++
+	}
+	return last, found
+}
+
+// edge classifies the interval ending at pos as a whole or a half bit, and
+// returns a frame once its sync word has been read.
+func (d *ltcDecoder) edge(pos int64) (Timecode, bool) {
+	interval := float64(pos - d.lastEdge)
+	start := d.lastEdge
+	d.lastEdge = pos
+	if interval > 2*d.period {
+		// silence or a dropout, start over
+		d.half, d.n = false, 0
+		return Timecode{}, false
+	}
+	if interval > 0.75*d.period {
+		d.period += (interval - d.period) / 4
+		if d.half {
+			// a lone half bit, out of step
+			d.half, d.n = false, 0
+		}
+		return d.bit(false, start)
+	}
+	d.period += (2*interval - d.period) / 4
+	if !d.half {
+		d.half, d.halfStart = true, start
+		return Timecode{}, false
+	}
+	d.half = false
+	return d.bit(true, d.halfStart)
+}
+
+// bit shifts a bit in and checks for the end of a frame.
+func (d *ltcDecoder) bit(one bool, start int64) (Timecode, bool) {
+	copy(d.bits[:], d.bits[1:])
+	copy(d.starts[:], d.starts[1:])
+	d.bits[ltcFrameBits-1], d.starts[ltcFrameBits-1] = one, start
+	d.n = min(d.n+1, ltcFrameBits)
+	if d.n < ltcFrameBits {
+		return Timecode{}, false
+	}
+	for i := 0; i < 16; i++ {
+		if d.bits[64+i] != (ltcSync>>(15-i)&1 == 1) {
+			return Timecode{}, false
+		}
+	}
+	tc := decodeLTC(d.bits)
+	if tc.Frames >= 30 || tc.Seconds >= 60 || tc.Minutes >= 60 || tc.Hours >= 24 {
+		return Timecode{}, false
+	}
+	fps := float64(d.sampleRate) / (d.period * ltcFrameBits)
+	tc.FrameRate = 30
+	switch {
+	case fps < 24.5:
+		tc.FrameRate = 24
+	case fps < 27.5:
+		tc.FrameRate = 25
+	}
+	return tc, true
+}
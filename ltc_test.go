@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLTCLabels(t *testing.T) {
+	df := ltcRate{nominal: 30, dropFrame: true}
+	for _, tc := range []struct {
+		rate  ltcRate
+		frame int64
+		want  string
+	}{
+		{ltcRate{nominal: 25}, 25*3600 + 24, "01:00:00:24"},
+		{ltcRate{nominal: 24}, 24*60*60*24 + 1, "00:00:00:01"},
+		{df, 1799, "00:00:59;29"},
+		{df, 1800, "00:01:00;02"},
+		{df, 17981, "00:09:59;29"},
+		{df, 17982, "00:10:00;00"},
+		{df, 17982 + 1800, "00:11:00;02"},
+	} {
+		if got := tc.rate.label(tc.frame).String(); got != tc.want {
+			t.Errorf("frame %d at %+v is %s, want %s", tc.frame, tc.rate, got, tc.want)
+		}
+	}
+
+	in := Timecode{Hours: 23, Minutes: 59, Seconds: 58, Frames: 29, DropFrame: true, UserBits: 0xdeadbeef}
+	bits := encodeLTC(in)
+	zeros := 0
+	for _, one := range bits {
+		if !one {
+			zeros++
+		}
+	}
+	if zeros%2 != 0 {
+		t.Errorf("a frame has %d zeros", zeros)
+	}
+	if out := decodeLTC(bits); out != in {
+		t.Errorf("decoded %+v, want %+v", out, in)
+	}
+}
+
+func TestLTCRoundTrip(t *testing.T) {
+	start := time.Date(2026, 10, 16, 13, 45, 7, 250*int(time.Millisecond), time.UTC)
+	for _, rate := range []ltcRate{{nominal: 24}, {nominal: 25}, {nominal: 30}, {nominal: 30, dropFrame: true}} {
+		for _, sampleRate := range []int{16000, 48000} {
+			// two channels with LTC on the second, rendered in two halves
+			samples := make([]float32, 2*sampleRate)
+			half := len(samples) / 2
+			gen := &ltcGenerator{rate: rate, level: 0.25}
+			gen.fill(samples[:half], 2, 1, sampleRate, start)
+			gen.fill(samples[half:], 2, 1, sampleRate, start.Add(500*time.Millisecond))
+
+			dec := newLTCDecoder(sampleRate)
+			var got []Timecode
+			chunk := 2 * sampleRate / 100
+			for i := 0; i < len(samples); i += chunk {
+				if tc, ok := dec.decode(samples[i:min(i+chunk, len(samples))], 2, 1); ok {
+					got = append(got, tc)
+				}
+			}
+			// every frame after the first is read, each in the 10ms chunk it ends in
+			fps := rate.fps()
+			if want := int(fps) - 2; len(got) < want {
+				t.Fatalf("%+v at %d Hz: read %d frames, want at least %d", rate, sampleRate, len(got), want)
+			}
+			first := int64(math.Ceil((13*3600 + 45*60 + 7.25) * fps))
+			for i, tc := range got {
+				want := rate.label(first + int64(i))
+				if tc.String() != want.String() || tc.FrameRate != rate.nominal {
+					t.Fatalf("%+v at %d Hz: frame %d is %s at %d fps, want %s", rate, sampleRate, i, tc, tc.FrameRate, want)
+				}
+				if tc.Offset > 0 || tc.Offset < -time.Second/time.Duration(fps) {
+					t.Errorf("%+v at %d Hz: frame %d starts %v into its chunk", rate, sampleRate, i, tc.Offset)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package audio
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Stream metrics are kept for each resource, codec, profile and direction,
+// so a robot with several microphones can be watched per device. The HTTP
+// handler serves them on GET /metrics in the Prometheus text format, or as
+// OpenMetrics with exemplars carrying the request ID of the GetAudio stream
+// that last moved a counter when the scraper accepts it. The standalone
+// server also serves them on AUDIO_METRICS_ADDR when set, and modules can
+// serve MetricsHandler themselves or take every observation with
+// AddMetricsHook.
+
+// Values of the direction and profile labels.
+const (
+	captureDirection  = "capture"
+	playbackDirection = "playback"
+	// noProfile is the profile of raw capture and of playback on
+	// resources without environment profiles
+	noProfile = "none"
+)
+
+// metricLabels identify one series of every stream metric.
+type metricLabels struct {
+	resource, codec, profile, direction string
+}
+
+// exemplar is the last observation of a counter made by a stream with a
+// request ID.
+type exemplar struct {
+	requestID string
+	value     float64
+	at        time.Time
+}
+
+// streamSeries holds the metrics of one label set.
+type streamSeries struct {
+	active                                              int
+	bytes, messages, gapSeconds, errors, dropped, plays float64
+	errorCodes                                          map[codes.Code]float64
+	bytesExemplar, messagesExemplar                     exemplar
+}
+
+// MetricEvent is one observation of a stream metric, as passed to hooks.
+type MetricEvent struct {
+	// Metric is the family moved, such as audio_stream_bytes.
+	Metric                              string
+	Resource, Codec, Profile, Direction string
+	// Code is the status code the stream failed with, for
+	// audio_stream_errors_by_code.
+	Code codes.Code
+	// Delta is what the counter or gauge moved by.
+	Delta float64
+	// RequestID is the request ID of the stream, if it has one.
+	RequestID string
+}
+
+// MetricsHook is called with every observation of the server's stream
+// metrics, so module users can count them into a metrics system of their
+// own. It's called synchronously from the stream, so it must be quick.
+type MetricsHook func(MetricEvent)
+
+// streamMetrics are the stream metrics of the process. Series are never
+// dropped; there is one per resource, codec and profile that was used.
+type streamMetrics struct {
+	mu     sync.Mutex
+	series map[metricLabels]*streamSeries
+	active map[uint64]*streamObserver // streams in progress, by number
+	next   uint64                     // number of the last stream opened
+	hooks  []MetricsHook
+	// lastErrors are the errors the last failed stream of each resource
+	// ended in, by resource
+	lastErrors map[string]failure
+}
+
+// failure is the error a stream ended in, and when.
+type failure struct {
+	err string
+	at  time.Time
+}
+
+// sharedMetrics are the metrics the RPC server counts into and the HTTP
+// handler serves.
+var sharedMetrics = newStreamMetrics()
+
+func newStreamMetrics() *streamMetrics {
+	return &streamMetrics{series: map[metricLabels]*streamSeries{}, active: map[uint64]*streamObserver{}, lastErrors: map[string]failure{}}
+}
+
+// MetricsHandler returns a handler serving the server's stream metrics for
+// Prometheus scrapes, for modules serving them on their own listener.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(serveMetrics)
+}
+
+// AddMetricsHook has h called with every observation of the server's stream
+// metrics from now on.
+func AddMetricsHook(h MetricsHook) {
+	sharedMetrics.mu.Lock()
+	defer sharedMetrics.mu.Unlock()
+	sharedMetrics.hooks = append(sharedMetrics.hooks, h)
+}
+
+// streamObserver counts one GetAudio stream or Play call into its series,
+// and records it in ServerHistory when it ends.
+type streamObserver struct {
+	m         *streamMetrics
+	s         *streamSeries
+	l         metricLabels
+	seq       uint64
+	record    StreamRecord // guarded by m.mu
+	requestID string
+}
+
+// open starts counting a stream. requestID is attached to its observations
+// as exemplars and may be empty.
+func (m *streamMetrics) open(ctx context.Context, l metricLabels, requestID string) *streamObserver {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.series[l]
+	if !ok {
+		s = &streamSeries{errorCodes: map[codes.Code]float64{}}
+		m.series[l] = s
+	}
+	s.active++
+	m.next++
+	o := &streamObserver{m: m, s: s, l: l, seq: m.next, requestID: requestID, record: StreamRecord{
+		Resource:  l.resource,
+		Direction: l.direction,
+		Codec:     l.codec,
+		Profile:   l.profile,
+		RequestID: requestID,
+		Start:     time.Now(),
+	}}
+	if id, ok := IdentityFromContext(ctx); ok {
+		o.record.Subject = id.Subject
+	}
+	m.active[o.seq] = o
+	o.observe("audio_streams_active", 0, 1)
+	if l.direction == playbackDirection {
+		s.plays++
+		o.observe("audio_play_operations", 0, 1)
+	}
+	return o
+}
+
+// observe calls the hooks with an observation of metric, with m.mu held.
+func (o *streamObserver) observe(metric string, code codes.Code, delta float64) {
+	for _, h := range o.m.hooks {
+		h(MetricEvent{
+			Metric:    metric,
+			Resource:  o.l.resource,
+			Codec:     o.l.codec,
+			Profile:   o.l.profile,
+			Direction: o.l.direction,
+			Code:      code,
+			Delta:     delta,
+			RequestID: o.requestID,
+		})
+	}
+}
+
+// activeStreams returns the streams of resource in progress and their
+// numbers, newest first.
+func (m *streamMetrics) activeStreams(resource string) []historyEntry[StreamRecord] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var streams []historyEntry[StreamRecord]
+	for seq, o := range m.active {
+		if o.record.Resource == resource {
+			streams = append(streams, historyEntry[StreamRecord]{Seq: seq, Entry: o.record})
+		}
+	}
+	slices.SortFunc(streams, func(a, b historyEntry[StreamRecord]) int { return cmp.Compare(b.Seq, a.Seq) })
+	return streams
+}
+
+// resourceStats returns the totals of resource's series in the fields of
+// Stats the metrics count.
+func (m *streamMetrics) resourceStats(resource string) Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var stats Stats
+	for l, s := range m.series {
+		if l.resource != resource {
+			continue
+		}
+		if l.direction == captureDirection {
+			stats.ActiveStreams += s.active
+			stats.BytesCaptured += int64(s.bytes)
+			stats.DroppedChunks += int64(s.dropped)
+		} else {
+			stats.ActivePlays += s.active
+			stats.BytesPlayed += int64(s.bytes)
+		}
+	}
+	last := m.lastErrors[resource]
+	stats.LastError, stats.LastErrorAt = last.err, last.at
+	return stats
+}
+
+// transferred counts a message of n bytes of audio and the gap before it.
+func (o *streamObserver) transferred(n int, gap time.Duration) {
+	o.m.mu.Lock()
+	defer o.m.mu.Unlock()
+	o.s.bytes += float64(n)
+	o.s.messages++
+	o.record.Bytes += int64(n)
+	o.record.Messages++
+	o.s.gapSeconds += gap.Seconds()
+	o.observe("audio_stream_bytes", 0, float64(n))
+	o.observe("audio_stream_messages", 0, 1)
+	if gap > 0 {
+		o.observe("audio_stream_gap_seconds", 0, gap.Seconds())
+	}
+	if o.requestID != "" {
+		now := time.Now()
+		o.s.bytesExemplar = exemplar{requestID: o.requestID, value: float64(n), at: now}
+		o.s.messagesExemplar = exemplar{requestID: o.requestID, value: 1, at: now}
+	}
+}
+
+// dropped counts n chunks dropped because the client fell behind.
+func (o *streamObserver) dropped(n int) {
+	o.m.mu.Lock()
+	defer o.m.mu.Unlock()
+	o.s.dropped += float64(n)
+	o.record.Dropped += int64(n)
+	o.observe("audio_stream_dropped_chunks", 0, float64(n))
+}
+
+// failed counts the stream ending in err, by its status code.
+func (o *streamObserver) failed(err error) {
+	o.m.mu.Lock()
+	defer o.m.mu.Unlock()
+	code := status.Code(err)
+	if code == codes.Unknown {
+		code = status.FromContextError(err).Code()
+	}
+	o.s.errors++
+	o.s.errorCodes[code]++
+	o.record.Err = err.Error()
+	o.m.lastErrors[o.l.resource] = failure{err: err.Error(), at: time.Now()}
+	o.observe("audio_stream_errors", code, 1)
+	o.observe("audio_stream_errors_by_code", code, 1)
+}
+
+func (o *streamObserver) close() {
+	o.m.mu.Lock()
+	o.s.active--
+	o.observe("audio_streams_active", 0, -1)
+	delete(o.m.active, o.seq)
+	record := o.record
+	o.m.mu.Unlock()
+	record.End = time.Now()
+	ServerHistory.recordStream(record)
+}
+
+// metricFamily is one exported metric and how to read it from a series.
+type metricFamily struct {
+	name, kind, help string
+	value            func(s *streamSeries) (float64, *exemplar)
+}
+
+var streamMetricFamilies = []metricFamily{
+	{"audio_stream_bytes", "counter", "Audio bytes sent by GetAudio streams or received by Play.",
+		func(s *streamSeries) (float64, *exemplar) { return s.bytes, &s.bytesExemplar }},
+	{"audio_stream_messages", "counter", "Chunks sent by GetAudio streams or clips received by Play.",
+		func(s *streamSeries) (float64, *exemplar) { return s.messages, &s.messagesExemplar }},
+	{"audio_stream_gap_seconds", "counter", "Audio skipped within GetAudio streams, while paused or lost by capture.",
+		func(s *streamSeries) (float64, *exemplar) { return s.gapSeconds, nil }},
+	{"audio_stream_dropped_chunks", "counter", "Chunks GetAudio streams dropped because their client fell behind.",
+		func(s *streamSeries) (float64, *exemplar) { return s.dropped, nil }},
+	{"audio_stream_errors", "counter", "GetAudio streams and Play calls that ended in an error.",
+		func(s *streamSeries) (float64, *exemplar) { return s.errors, nil }},
+	{"audio_play_operations", "counter", "Play calls, including those that failed.",
+		func(s *streamSeries) (float64, *exemplar) { return s.plays, nil }},
+	{"audio_streams_active", "gauge", "GetAudio streams and Play calls in progress.",
+		func(s *streamSeries) (float64, *exemplar) { return float64(s.active), nil }},
+}
+
+// write writes every series in the Prometheus text format, or in OpenMetrics
+// with exemplars.
+func (m *streamMetrics) write(w io.Writer, openMetrics bool) error {
+	type labeled struct {
+		l metricLabels
+		s streamSeries
+	}
+	m.mu.Lock()
+	series := make([]labeled, 0, len(m.series))
+	for l, s := range m.series {
+		copied := *s
+		copied.errorCodes = maps.Clone(s.errorCodes)
+		series = append(series, labeled{l, copied})
+	}
+	m.mu.Unlock()
+	slices.SortFunc(series, func(a, b labeled) int {
+		return cmp.Or(
+			strings.Compare(a.l.resource, b.l.resource),
+			strings.Compare(a.l.direction, b.l.direction),
+			strings.Compare(a.l.codec, b.l.codec),
+			strings.Compare(a.l.profile, b.l.profile))
+	})
+
+	var buf bytes.Buffer
+	for _, f := range streamMetricFamilies {
+		sample := writeFamilyHeader(&buf, f, openMetrics)
+		for _, s := range series {
+			v, ex := f.value(&s.s)
+			fmt.Fprintf(&buf, "%s{%s} %s", sample, s.l.format(), formatMetric(v))
+			if openMetrics && ex != nil && ex.requestID != "" {
+				fmt.Fprintf(&buf, " # {request_id=%s} %s %s",
+					quoteLabel(ex.requestID), formatMetric(ex.value), strconv.FormatFloat(float64(ex.at.UnixMilli())/1000, 'f', 3, 64))
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	// errors are also counted by code, in series of their own
+	sample := writeFamilyHeader(&buf, errorsByCodeFamily, openMetrics)
+	for _, s := range series {
+		for _, code := range slices.Sorted(maps.Keys(s.s.errorCodes)) {
+			fmt.Fprintf(&buf, "%s{%s,code=%s} %s\n", sample, s.l.format(), quoteLabel(code.String()), formatMetric(s.s.errorCodes[code]))
+		}
+	}
+	if openMetrics {
+		buf.WriteString("# EOF\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+var errorsByCodeFamily = metricFamily{name: "audio_stream_errors_by_code", kind: "counter",
+	help: "GetAudio streams and Play calls that ended in an error, by gRPC status code."}
+
+// writeFamilyHeader writes the HELP and TYPE lines of f and returns the name
+// of its samples.
+func writeFamilyHeader(buf *bytes.Buffer, f metricFamily, openMetrics bool) string {
+	family, sample := f.name, f.name
+	if f.kind == "counter" {
+		sample += "_total"
+		if !openMetrics {
+			// the classic format names the family after its sample
+			family = sample
+		}
+	}
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", family, f.help, family, f.kind)
+	return sample
+}
+
+// format formats the labels for a sample.
+func (l metricLabels) format() string {
+	return fmt.Sprintf("resource=%s,codec=%s,profile=%s,direction=%s",
+		quoteLabel(l.resource), quoteLabel(l.codec), quoteLabel(l.profile), quoteLabel(l.direction))
+}
+
+func formatMetric(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// quoteLabel quotes a label value with the escapes both formats use.
+func quoteLabel(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+// serveMetrics serves the stream metrics for Prometheus scrapes.
+func serveMetrics(w http.ResponseWriter, r *http.Request) {
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	}
+	sharedMetrics.write(w, openMetrics)
+}
+
+// captureProfile names the processing a GetAudio request asked for, such as
+// "denoise+agc", for the profile label.
+func captureProfile(req *pb.GetAudioRequest) string {
+	var stages []string
+	if req.NoiseSuppression != "" {
+		stages = append(stages, "denoise")
+	}
+	if req.Agc {
+		stages = append(stages, "agc")
+	}
+	if req.Vad != "" || req.SpeechOnly {
+		stages = append(stages, "vad")
+	}
+	if req.TrimSilence {
+		stages = append(stages, "trim")
+	}
+	if len(req.OnlyWhen) > 0 {
+		stages = append(stages, "gated")
+	}
+	if len(stages) == 0 {
+		return noProfile
+	}
+	return strings.Join(stages, "+")
+}
+
+// playbackProfile is the environment profile a resource renders output with,
+// for the profile label.
+func playbackProfile(ctx context.Context, a Audio) string {
+	ps, ok := a.(ProfileSwitcher)
+	if !ok {
+		return noProfile
+	}
+	status, err := ps.GetProfile(ctx)
+	if err != nil || status.Profile == "" {
+		return noProfile
+	}
+	return status.Profile
+}
@@ -0,0 +1,134 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestStreamMetrics(t *testing.T) {
+	src := newBurstSource(5, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.Named = Named("metered-mic").AsNamed()
+	src.samples = func(i int) []float32 { return tone(8000, 80, 440, 0.5) }
+	mic := serveAudio(t, src)
+	rec := &playRecorder{burstSource: newBurstSource(0, AudioInfo{})}
+	rec.Named = Named("metered-speaker").AsNamed()
+	speaker := serveAudio(t, rec)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ch, err := mic.GetAudio(ctx, Pcm16.String(), 0, 0, 0, WithRequestID("trace-1"), WithAGC(-20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+	}
+	if err := speaker.Play(ctx, make([]byte, 1000), Pcm16.String(), 8000, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(NewHTTPHandler(func(name string) (Audio, error) {
+		return nil, errors.New("no resources")
+	}, logging.NewTestLogger(t)))
+	defer srv.Close()
+	scrape := func(accept string) string {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/metrics", nil)
+		req.Header.Set("Accept", accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return string(b)
+	}
+
+	// 5 chunks of 80 frames
+	capture := `audio_stream_bytes_total{resource="metered-mic",codec="pcm16",profile="agc",direction="capture"} 800`
+	playback := `audio_stream_bytes_total{resource="metered-speaker",codec="pcm16",profile="none",direction="playback"} 1000`
+	text := scrape("text/plain")
+	for _, want := range []string{"# TYPE audio_stream_bytes_total counter", capture + "\n", playback + "\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text exposition lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "# {") || strings.Contains(text, "# EOF") {
+		t.Errorf("text exposition has OpenMetrics syntax:\n%s", text)
+	}
+
+	om := scrape("application/openmetrics-text; version=1.0.0")
+	for _, want := range []string{"# TYPE audio_stream_bytes counter", capture + ` # {request_id="trace-1"} 160 `, playback + "\n"} {
+		if !strings.Contains(om, want) {
+			t.Errorf("OpenMetrics exposition lacks %q:\n%s", want, om)
+		}
+	}
+	if !strings.HasSuffix(om, "# EOF\n") {
+		t.Error("OpenMetrics exposition doesn't end with # EOF")
+	}
+}
+
+func TestMetricsHooksAndErrorCodes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("hooked-speaker"), FakeConfig{Unpaced: true, PlaybackSampleRates: []int{48000}}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	speaker := serveAudio(t, f)
+
+	var mu sync.Mutex
+	observed := map[string]float64{}
+	AddMetricsHook(func(e MetricEvent) {
+		if e.Resource != "hooked-speaker" {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		observed[e.Metric+"/"+e.Code.String()] += e.Delta
+	})
+
+	clip := make([]byte, 960)
+	if err := speaker.Play(ctx, clip, Pcm16.String(), 48000, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := speaker.Play(ctx, clip, Pcm16.String(), 8000, 1); err == nil {
+		t.Fatal("an unplayable clip was played")
+	}
+
+	mu.Lock()
+	for metric, want := range map[string]float64{
+		"audio_play_operations/OK":                    2,
+		"audio_stream_bytes/OK":                       960,
+		"audio_stream_errors_by_code/InvalidArgument": 1,
+		"audio_streams_active/OK":                     0,
+	} {
+		if got := observed[metric]; got != want {
+			t.Errorf("hook observed %s %v, want %v", metric, got, want)
+		}
+	}
+	mu.Unlock()
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	for _, want := range []string{
+		`audio_play_operations_total{resource="hooked-speaker",codec="pcm16",profile="none",direction="playback"} 2` + "\n",
+		`audio_stream_errors_by_code_total{resource="hooked-speaker",codec="pcm16",profile="none",direction="playback",code="InvalidArgument"} 1` + "\n",
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("exposition lacks %q:\n%s", want, rec.Body)
+		}
+	}
+}
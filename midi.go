@@ -0,0 +1,441 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// MIDIModel wraps another Audio and plays through it when MIDI arrives, for
+// interactive art and stage robots: mapped notes and program changes play
+// clips, and other notes can play a tone at their pitch.
+var MIDIModel = resource.NewModel("olivia", "audio", "midi")
+
+const (
+	defaultMIDIToneLength = 300 * time.Millisecond
+	defaultMIDIToneLevel  = -12.0 // dBFS at full velocity
+	// triggers waiting while a clip plays, more are dropped
+	midiQueueLength = 8
+	// how long to wait before reopening a device that failed, e.g. a USB
+	// interface that was unplugged
+	midiReopenDelay = time.Second
+	// where ALSA lists raw MIDI ports, USB MIDI interfaces included
+	midiDeviceGlob = "/dev/snd/midiC*D*"
+)
+
+// MIDIConfig is the configuration of the midi model.
+type MIDIConfig struct {
+	Output string `json:"output"` // Audio resource to play through
+	// Device is a raw MIDI port, /dev/snd/midiC1D0 for the first port of
+	// card 1.
+	Device    string        `json:"device"`
+	Channel   int           `json:"channel,omitempty"` // 1 to 16 to listen on, 0 for all
+	Triggers  []MIDITrigger `json:"triggers,omitempty"`
+	Tones     bool          `json:"tones,omitempty"`      // notes without a trigger play a tone at their pitch
+	ToneMs    int           `json:"tone_ms,omitempty"`    // length of tones, 300ms if zero
+	LevelDBFS float64       `json:"level_dbfs,omitempty"` // peak of tones at full velocity, -12 if zero
+}
+
+// MIDITrigger plays a clip on a note or program change.
+type MIDITrigger struct {
+	Event  string `json:"event,omitempty"` // "note" or "program", note if empty
+	Number int    `json:"number"`          // note or program, 0 to 127
+	File   string `json:"file"`            // .wav, .flac or .mp3 to play
+}
+
+// midiKey is what a trigger fires on.
+type midiKey struct {
+	program bool
+	number  int
+}
+
+func (t MIDITrigger) key() (midiKey, error) {
+	if t.Number < 0 || t.Number > 127 {
+		return midiKey{}, fmt.Errorf("number %d isn't from 0 to 127", t.Number)
+	}
+	switch t.Event {
+	case "", "note":
+		return midiKey{number: t.Number}, nil
+	case "program":
+		return midiKey{program: true, number: t.Number}, nil
+	default:
+		return midiKey{}, fmt.Errorf("unknown event %q, expected note or program", t.Event)
+	}
+}
+
+// Validate checks the midi configuration and returns the output as a
+// dependency.
+func (c *MIDIConfig) Validate(path string) ([]string, []string, error) {
+	if c.Output == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "output")
+	}
+	if c.Device == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "device")
+	}
+	var err error
+	switch {
+	case c.Channel < 0 || c.Channel > 16:
+		err = fmt.Errorf("channel %d isn't from 1 to 16", c.Channel)
+	case len(c.Triggers) == 0 && !c.Tones:
+		err = errors.New("set triggers, tones or both")
+	case c.ToneMs < 0:
+		err = errors.New("tone_ms cannot be negative")
+	case c.LevelDBFS > 0:
+		err = errors.New("level_dbfs must be below full scale")
+	}
+	seen := map[midiKey]bool{}
+	for i, t := range c.Triggers {
+		if err != nil {
+			break
+		}
+		var k midiKey
+		if k, err = t.key(); err != nil {
+			err = fmt.Errorf("trigger %d: %w", i, err)
+		} else if t.File == "" {
+			err = fmt.Errorf("trigger %d has no file", i)
+		} else if seen[k] {
+			err = fmt.Errorf("trigger %d repeats an earlier trigger's event", i)
+		}
+		seen[k] = true
+	}
+	if err != nil {
+		return nil, nil, resource.NewConfigValidationError(path, err)
+	}
+	return []string{c.Output}, nil, nil
+}
+
+func init() {
+	registerModel(MIDIModel, resource.Registration[Audio, *MIDIConfig]{
+		AttributeMapConverter: migratingConverter[*MIDIConfig](MIDIModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*MIDIConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			output, err := resource.FromDependencies[Audio](deps, Named(cfg.Output))
+			if err != nil {
+				return nil, err
+			}
+			return NewMIDI(conf.ResourceName(), output, *cfg, logger)
+		},
+	})
+}
+
+// openMIDIDevice opens a raw MIDI port for reading. Tests replace it.
+var openMIDIDevice = func(path string) (io.ReadCloser, error) { return os.Open(path) }
+
+// midiClip is decoded audio ready to play.
+type midiClip struct {
+	data     []byte // pcm16
+	rate     int
+	channels int
+}
+
+type midiPlayer struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	output Audio
+	cfg    MIDIConfig
+	clips  map[midiKey]midiClip
+	logger logging.Logger
+
+	queue  chan midiClip
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	dev    io.Closer // the open port, closed to stop a blocked read
+	closed bool
+}
+
+// NewMIDI decodes every trigger's clip and starts listening on the device.
+func NewMIDI(name resource.Name, output Audio, cfg MIDIConfig, logger logging.Logger) (Audio, error) {
+	if cfg.ToneMs == 0 {
+		cfg.ToneMs = int(defaultMIDIToneLength / time.Millisecond)
+	}
+	if cfg.LevelDBFS == 0 {
+		cfg.LevelDBFS = defaultMIDIToneLevel
+	}
+	clips := map[midiKey]midiClip{}
+	for i, t := range cfg.Triggers {
+		k, err := t.key()
+		if err != nil {
+			return nil, fmt.Errorf("trigger %d: %w", i, err)
+		}
+		samples, info, err := readAudioFile(t.File)
+		if err != nil {
+			return nil, fmt.Errorf("trigger %d: %w", i, err)
+		}
+		data, err := encodePCM(samples, Pcm16)
+		if err != nil {
+			return nil, err
+		}
+		clips[k] = midiClip{data: data, rate: info.SampleRate, channels: info.Channels}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &midiPlayer{
+		Named:  name.AsNamed(),
+		output: output,
+		cfg:    cfg,
+		clips:  clips,
+		logger: logger,
+		queue:  make(chan midiClip, midiQueueLength),
+		cancel: cancel,
+	}
+	m.wg.Add(2)
+	go m.listen(ctx)
+	go m.play(ctx)
+	return m, nil
+}
+
+// listen reads the device until the resource closes, reopening it when it
+// fails.
+func (m *midiPlayer) listen(ctx context.Context) {
+	defer m.wg.Done()
+	for {
+		err := m.read()
+		if ctx.Err() != nil {
+			return
+		}
+		m.logger.Warnw("MIDI input failed, reopening", "device", m.cfg.Device, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(midiReopenDelay):
+		}
+	}
+}
+
+// read opens the device and handles messages until a read fails.
+func (m *midiPlayer) read() error {
+	dev, err := openMIDIDevice(m.cfg.Device)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return dev.Close()
+	}
+	m.dev = dev
+	m.mu.Unlock()
+	defer dev.Close()
+
+	var (
+		p   midiParser
+		buf = make([]byte, 64)
+	)
+	for {
+		n, err := dev.Read(buf)
+		for _, b := range buf[:n] {
+			if msg, ok := p.feed(b); ok {
+				m.handle(msg)
+			}
+		}
+		if err == io.EOF {
+			return errors.New("the device closed")
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle queues what a message triggers.
+func (m *midiPlayer) handle(msg midiMessage) {
+	if m.cfg.Channel != 0 && msg.channel() != m.cfg.Channel {
+		return
+	}
+	switch msg.kind() {
+	case midiNoteOn:
+		// a note on at velocity 0 is a note off
+		if msg.data2 > 0 {
+			m.trigger(midiKey{number: int(msg.data1)}, int(msg.data2))
+		}
+	case midiProgramChange:
+		m.trigger(midiKey{program: true, number: int(msg.data1)}, 127)
+	}
+}
+
+// trigger queues the clip for k, or a tone for an unmapped note, and
+// reports whether anything was queued.
+func (m *midiPlayer) trigger(k midiKey, velocity int) bool {
+	clip, ok := m.clips[k]
+	if !ok {
+		if k.program || !m.cfg.Tones {
+			return false
+		}
+		freq := 440 * math.Pow(2, float64(k.number-69)/12)
+		amplitude := math.Pow(10, m.cfg.LevelDBFS/20) * float64(velocity) / 127
+		data, err := encodePCM(fadedTone(freq, amplitude, time.Duration(m.cfg.ToneMs)*time.Millisecond), Pcm16)
+		if err != nil {
+			return false
+		}
+		clip = midiClip{data: data, rate: testToneRate, channels: 1}
+	}
+	select {
+	case m.queue <- clip:
+		return true
+	default:
+		m.logger.Debugw("dropped a MIDI trigger, too many are waiting", "program", k.program, "number", k.number)
+		return false
+	}
+}
+
+// play plays queued clips one after another.
+func (m *midiPlayer) play(ctx context.Context) {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case clip := <-m.queue:
+			if err := m.output.Play(ctx, clip.data, Pcm16.String(), clip.rate, clip.channels); err != nil && ctx.Err() == nil {
+				m.logger.Warnw("failed to play a MIDI trigger", "error", err)
+			}
+		}
+	}
+}
+
+func (m *midiPlayer) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	return m.output.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+}
+
+func (m *midiPlayer) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return m.output.Play(ctx, data, codec, sampleRate, channels)
+}
+
+// DoCommand fires triggers without a MIDI controller and lists the ports:
+//
+//	{"trigger": {"note": 60, "velocity": 100}}
+//	{"trigger": {"program": 3}}
+//	{"list_midi_devices": true}
+func (m *midiPlayer) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	switch {
+	case cmd["trigger"] != nil:
+		params, err := commandParams(cmd, "trigger")
+		if err != nil {
+			return nil, err
+		}
+		return m.triggerCommand(params)
+	case cmd["list_midi_devices"] != nil:
+		ports, err := filepath.Glob(midiDeviceGlob)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, len(ports))
+		for i, p := range ports {
+			list[i] = p
+		}
+		return map[string]interface{}{"devices": list}, nil
+	}
+	if resp, ok, err := doBuiltinCommand(ctx, m, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (m *midiPlayer) triggerCommand(params map[string]interface{}) (map[string]interface{}, error) {
+	_, program := params["program"]
+	key := "note"
+	if program {
+		key = "program"
+	}
+	number, err := commandNumber(params, "trigger", key, -1)
+	if err != nil {
+		return nil, err
+	}
+	velocity, err := commandNumber(params, "trigger", "velocity", 127)
+	if err != nil {
+		return nil, err
+	}
+	if number < 0 || number > 127 || velocity < 1 || velocity > 127 {
+		return nil, errors.New("trigger needs a note or program from 0 to 127 and a velocity from 1 to 127")
+	}
+	queued := m.trigger(midiKey{program: program, number: int(number)}, int(velocity))
+	return map[string]interface{}{"queued": queued}, nil
+}
+
+// Close stops listening and playing.
+func (m *midiPlayer) Close(ctx context.Context) error {
+	m.cancel()
+	m.mu.Lock()
+	m.closed = true
+	if m.dev != nil {
+		m.dev.Close()
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+	return nil
+}
+
+// MIDI channel messages, by the high nibble of their status byte.
+const (
+	midiNoteOn        = 0x90
+	midiProgramChange = 0xc0
+)
+
+// midiMessage is a channel message.
+type midiMessage struct {
+	status, data1, data2 byte
+}
+
+func (m midiMessage) kind() byte   { return m.status & 0xf0 }
+func (m midiMessage) channel() int { return int(m.status&0x0f) + 1 }
+
+// midiParser splits a raw MIDI byte stream into channel messages. It
+// follows running status, where the status byte is left out while it
+// repeats, and skips system messages, including realtime bytes that may
+// come in the middle of another message.
+type midiParser struct {
+	running byte // status of the message being read, 0 for none
+	data    [2]byte
+	n       int
+	sysex   bool
+}
+
+// feed takes one byte and returns a message once one is complete.
+func (p *midiParser) feed(b byte) (midiMessage, bool) {
+	switch {
+	case b >= 0xf8:
+		// realtime: clock, start, stop and active sensing
+		return midiMessage{}, false
+	case b == 0xf0:
+		p.sysex, p.running, p.n = true, 0, 0
+		return midiMessage{}, false
+	case b >= 0xf0:
+		// system common messages and the end of a sysex cancel running status
+		p.sysex, p.running, p.n = false, 0, 0
+		return midiMessage{}, false
+	case b&0x80 != 0:
+		p.sysex, p.running, p.n = false, b, 0
+		return midiMessage{}, false
+	case p.sysex || p.running == 0:
+		return midiMessage{}, false
+	}
+	p.data[p.n] = b
+	p.n++
+	want := 2
+	if k := p.running & 0xf0; k == midiProgramChange || k == 0xd0 {
+		want = 1
+	}
+	if p.n < want {
+		return midiMessage{}, false
+	}
+	p.n = 0
+	msg := midiMessage{status: p.running, data1: p.data[0]}
+	if want == 2 {
+		msg.data2 = p.data[1]
+	}
+	return msg, true
+}
@@ -0,0 +1,152 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestMIDIParser(t *testing.T) {
+	stream := []byte{
+		0x90, 60, 100, // note on, channel 1
+		62, 90, // running status
+		0xf8,         // a clock tick in the middle of a message
+		64, 0xfe, 80, // with active sensing too
+		0xf0, 0x7e, 0x90, 0xf7, // sysex, the status inside is ignored
+		1, 2, // data without a status is dropped
+		0xc3, 5, // program change on channel 4
+		6,           // running status with one data byte
+		0x80, 60, 0, // note off
+	}
+	var (
+		p   midiParser
+		got []midiMessage
+	)
+	for _, b := range stream {
+		if m, ok := p.feed(b); ok {
+			got = append(got, m)
+		}
+	}
+	want := []midiMessage{
+		{0x90, 60, 100}, {0x90, 62, 90}, {0x90, 64, 80},
+		{0xc3, 5, 0}, {0xc3, 6, 0}, {0x80, 60, 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parsed %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("message %d is %v, want %v", i, got[i], want[i])
+		}
+	}
+	if got[3].kind() != midiProgramChange || got[3].channel() != 4 {
+		t.Errorf("program change is %x on channel %d", got[3].kind(), got[3].channel())
+	}
+}
+
+func TestMIDITriggers(t *testing.T) {
+	dir := t.TempDir()
+	var wav bytes.Buffer
+	clip, _ := encodePCM(tone(8000, 800, 440, 0.5), Pcm16)
+	if err := writeWAVHeader(&wav, newWAVHeader(8000, 1, 16, uint32(len(clip)))); err != nil {
+		t.Fatal(err)
+	}
+	wav.Write(clip)
+	path := filepath.Join(dir, "bell.wav")
+	if err := os.WriteFile(path, wav.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, w := io.Pipe()
+	prev := openMIDIDevice
+	openMIDIDevice = func(string) (io.ReadCloser, error) { return r, nil }
+	defer func() { openMIDIDevice = prev }()
+
+	logger := logging.NewTestLogger(t)
+	f, err := NewFake(Named("speaker"), FakeConfig{Unpaced: true}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMIDI(Named("midi"), f, MIDIConfig{
+		Output:   "speaker",
+		Device:   "/dev/snd/midiC1D0",
+		Channel:  2,
+		Tones:    true,
+		ToneMs:   100,
+		Triggers: []MIDITrigger{{Number: 60, File: path}, {Event: "program", Number: 1, File: path}},
+	}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitPlays := func(n int) []FakePlay {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for len(f.Plays()) < n && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		plays := f.Plays()
+		if len(plays) != n {
+			t.Fatalf("played %d clips, want %d", len(plays), n)
+		}
+		return plays
+	}
+
+	// the mapped note, then the same on channel 1, which isn't listened to
+	w.Write([]byte{0x91, 60, 127, 0x90, 60, 127})
+	plays := waitPlays(1)
+	if plays[0].SampleRate != 8000 || len(plays[0].Data) != len(clip) {
+		t.Errorf("played %d bytes at %d Hz, want the clip", len(plays[0].Data), plays[0].SampleRate)
+	}
+	// an unmapped note plays a tone, a note off nothing
+	w.Write([]byte{0x91, 69, 64, 69, 0})
+	plays = waitPlays(2)
+	if plays[1].SampleRate != testToneRate || len(plays[1].Data) != 2*testToneRate/10 {
+		t.Errorf("played %d bytes at %d Hz, want 100ms of tone", len(plays[1].Data), plays[1].SampleRate)
+	}
+	w.Write([]byte{0xc1, 1})
+	waitPlays(3)
+
+	resp, err := m.DoCommand(context.Background(), map[string]interface{}{"trigger": map[string]interface{}{"program": 2.0}})
+	if err != nil || resp["queued"] != false {
+		t.Errorf("an unmapped program change: %v, %v", resp, err)
+	}
+	if resp, err := m.DoCommand(context.Background(), map[string]interface{}{"trigger": map[string]interface{}{"note": 72.0}}); err != nil || resp["queued"] != true {
+		t.Errorf("a note from DoCommand: %v, %v", resp, err)
+	}
+	waitPlays(4)
+
+	if err := m.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte{0x91, 60, 127}); err == nil {
+		t.Error("the device is still open after Close")
+	}
+}
+
+func TestMIDIConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg MIDIConfig
+		ok  bool
+	}{
+		{MIDIConfig{Output: "s", Device: "d", Tones: true}, true},
+		{MIDIConfig{Output: "s", Device: "d", Channel: 10, Triggers: []MIDITrigger{{Number: 36, File: "kick.wav"}, {Event: "program", Number: 36, File: "b.wav"}}}, true},
+		{MIDIConfig{Device: "d", Tones: true}, false},
+		{MIDIConfig{Output: "s", Tones: true}, false},
+		{MIDIConfig{Output: "s", Device: "d"}, false},
+		{MIDIConfig{Output: "s", Device: "d", Tones: true, Channel: 17}, false},
+		{MIDIConfig{Output: "s", Device: "d", Triggers: []MIDITrigger{{Number: 128, File: "a.wav"}}}, false},
+		{MIDIConfig{Output: "s", Device: "d", Triggers: []MIDITrigger{{Event: "cc", Number: 1, File: "a.wav"}}}, false},
+		{MIDIConfig{Output: "s", Device: "d", Triggers: []MIDITrigger{{Number: 1}}}, false},
+		{MIDIConfig{Output: "s", Device: "d", Triggers: []MIDITrigger{{Number: 1, File: "a.wav"}, {Event: "note", Number: 1, File: "b.wav"}}}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package audio
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// attributeMigration carries a config attribute renamed or reshaped in a
+// release forward to where the current release reads it.
+type attributeMigration struct {
+	from, to string
+	release  string // first release reading to
+	// convert maps the old value to the new one, nil to keep it as it is.
+	// For attributes reshaped in place it reports false when the value
+	// already has the current shape.
+	convert func(old interface{}) (interface{}, bool, error)
+}
+
+// configMigrations holds each model's attribute migrations, oldest first.
+// Entries stay for as long as configs written before their release may be
+// deployed.
+var configMigrations = map[resource.Model][]attributeMigration{}
+
+// configLogger reports deprecated attributes, which are migrated before any
+// resource exists to log them.
+var configLogger = serverVerbosity.track("config", logging.NewLogger("audio-config"))
+
+// MigrateAttributes returns a copy of attrs, a configuration of model, with
+// attributes from older releases moved to their current names and shapes,
+// and a deprecation warning for each one moved. A config setting both an
+// old attribute and its replacement is an error.
+func MigrateAttributes(model resource.Model, attrs map[string]interface{}) (map[string]interface{}, []string, error) {
+	out := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	var warnings []string
+	for _, m := range configMigrations[model] {
+		old, ok := out[m.from]
+		if !ok {
+			continue
+		}
+		if _, set := out[m.to]; set && m.from != m.to {
+			return nil, warnings, fmt.Errorf("%s: both %q and %q, which replaced it in %s, are set", model, m.from, m.to, m.release)
+		}
+		v, changed := old, m.from != m.to
+		if m.convert != nil {
+			var err error
+			if v, changed, err = m.convert(old); err != nil {
+				return nil, warnings, fmt.Errorf("%s: cannot migrate %q: %w", model, m.from, err)
+			}
+		}
+		if !changed {
+			continue
+		}
+		delete(out, m.from)
+		out[m.to] = v
+		if m.from == m.to {
+			warnings = append(warnings, fmt.Sprintf("%s: the form of %q used before %s is deprecated, it was read as %s", model, m.from, m.release, jsonString(v)))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s: %q is deprecated since %s, use %q", model, m.from, m.release, m.to))
+		}
+	}
+	return out, warnings, nil
+}
+
+func jsonString(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// migratingConverter decodes a model's attributes into its config after
+// migrating them, so Validate sees the current attributes whichever release
+// the config was written for.
+func migratingConverter[C any](model resource.Model) func(utils.AttributeMap) (C, error) {
+	return func(attrs utils.AttributeMap) (C, error) {
+		var cfg C
+		migrated, warnings, err := MigrateAttributes(model, attrs)
+		if err != nil {
+			return cfg, err
+		}
+		for _, w := range warnings {
+			configLogger.Warn(w)
+		}
+		b, err := json.Marshal(migrated)
+		if err != nil {
+			return cfg, err
+		}
+		err = json.Unmarshal(b, &cfg)
+		return cfg, err
+	}
+}
@@ -0,0 +1,74 @@
+package audio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+func TestMigrateAttributes(t *testing.T) {
+	model := resource.NewModel("olivia", "audio", "migration_test")
+	configMigrations[model] = []attributeMigration{
+		{from: "file", to: "path", release: "0.4.0"},
+		// a device string became a selector
+		{from: "device", to: "device", release: "0.5.0", convert: func(old interface{}) (interface{}, bool, error) {
+			switch v := old.(type) {
+			case string:
+				return map[string]interface{}{"name": v}, true, nil
+			case map[string]interface{}:
+				return v, false, nil
+			default:
+				return nil, false, errors.New("device must be a name or a selector")
+			}
+		}},
+	}
+	defer delete(configMigrations, model)
+
+	attrs := map[string]interface{}{"file": "a.wav", "device": "hw:1,0", "loop": true}
+	got, warnings, err := MigrateAttributes(model, attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["path"] != "a.wav" || got["loop"] != true || got["file"] != nil {
+		t.Errorf("migrated to %v", got)
+	}
+	if sel, _ := got["device"].(map[string]interface{}); sel["name"] != "hw:1,0" {
+		t.Errorf("device migrated to %v", got["device"])
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], `"file" is deprecated since 0.4.0, use "path"`) {
+		t.Errorf("got warnings %q", warnings)
+	}
+	if attrs["file"] != "a.wav" {
+		t.Error("the original attributes were changed")
+	}
+
+	// current configs pass through without warnings
+	if _, warnings, err := MigrateAttributes(model, got); err != nil || len(warnings) != 0 {
+		t.Errorf("current config gave %q, %v", warnings, err)
+	}
+	if _, _, err := MigrateAttributes(model, map[string]interface{}{"file": "a.wav", "path": "b.wav"}); err == nil {
+		t.Error("a config setting an old attribute and its replacement was accepted")
+	}
+	if _, _, err := MigrateAttributes(model, map[string]interface{}{"device": 3.0}); err == nil {
+		t.Error("a device of the wrong type was accepted")
+	}
+}
+
+func TestMigratingConverterValidates(t *testing.T) {
+	configMigrations[FileSourceModel] = []attributeMigration{{from: "file", to: "path", release: "0.4.0"}}
+	defer delete(configMigrations, FileSourceModel)
+
+	cfg, err := migratingConverter[*FileSourceConfig](FileSourceModel)(utils.AttributeMap{"file": "a.wav", "chunk_ms": 20.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Path != "a.wav" || cfg.ChunkMs != 20 {
+		t.Errorf("converted to %+v", cfg)
+	}
+	if _, _, err := cfg.Validate("components.mic.attributes"); err != nil {
+		t.Errorf("the migrated config doesn't validate: %v", err)
+	}
+}
@@ -0,0 +1,333 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// MiniaudioModel captures and plays through miniaudio, by way of malgo.
+// miniaudio is a single C file compiled into the module, talking to the
+// platform's audio system itself, so unlike the other models it needs no
+// libraries or headers on the board: a lighter choice for embedded ARM boards
+// and cross-compiled builds. With full_duplex it runs capture and playback on
+// one device clock, for echo cancellation and anything else that needs the
+// two directions sample-aligned. It needs a build with the miniaudio tag;
+// other builds know the model but fail to construct it.
+var MiniaudioModel = resource.NewModel("olivia", "audio", "miniaudio")
+
+// Defaults for MiniaudioConfig.
+const (
+	defaultMiniaudioPeriod  = 10 * time.Millisecond
+	defaultMiniaudioPeriods = 3
+	// miniaudioDefaultDevice is the device of a direction using the
+	// backend's default
+	miniaudioDefaultDevice = "default"
+)
+
+// miniaudioBackends are the backends miniaudio can be limited to.
+var miniaudioBackends = []string{"wasapi", "dsound", "winmm", "coreaudio", "pulseaudio", "alsa", "jack", "aaudio", "opensl"}
+
+// MiniaudioConfig is the configuration of the miniaudio model. Devices are
+// named as miniaudio lists them, which list_devices shows.
+type MiniaudioConfig struct {
+	// Backend limits miniaudio to one of the platform's audio systems, such
+	// as "alsa" or "pulseaudio". Empty tries each in miniaudio's order and
+	// uses the first that works.
+	Backend string `json:"backend,omitempty"`
+	// CaptureDevice and PlaybackDevice are device names, or enough of one
+	// to tell it apart, ignoring case. The backend's default if empty;
+	// "none" turns a direction off.
+	CaptureDevice    string `json:"capture_device,omitempty"`
+	PlaybackDevice   string `json:"playback_device,omitempty"`
+	SampleRate       int    `json:"sample_rate,omitempty"`       // 48 kHz if zero
+	Channels         int    `json:"channels,omitempty"`          // capture channels, 1 if zero
+	PlaybackChannels int    `json:"playback_channels,omitempty"` // 2 if zero
+	PeriodMs         int    `json:"period_ms,omitempty"`         // 10ms if zero
+	Periods          int    `json:"periods,omitempty"`           // periods buffered, 3 if zero
+	// FullDuplex opens capture and playback as one device, started when
+	// either is first used and stopped once neither is.
+	FullDuplex bool `json:"full_duplex,omitempty"`
+}
+
+// Validate checks the miniaudio configuration.
+func (c *MiniaudioConfig) Validate(path string) ([]string, []string, error) {
+	if c.SampleRate < 0 || c.Channels < 0 || c.PlaybackChannels < 0 || c.PeriodMs < 0 || c.Periods < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate, channels, playback_channels, period_ms and periods cannot be negative"))
+	}
+	if c.Backend != "" && !slices.Contains(miniaudioBackends, c.Backend) {
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("unknown backend %q, expected one of %v", c.Backend, miniaudioBackends))
+	}
+	if c.CaptureDevice == noPCMDevice && c.PlaybackDevice == noPCMDevice {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("capture_device and playback_device cannot both be none"))
+	}
+	if c.FullDuplex && (c.CaptureDevice == noPCMDevice || c.PlaybackDevice == noPCMDevice) {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("full_duplex needs both capture and playback"))
+	}
+	return nil, nil, nil
+}
+
+// miniaudioDevice is a device as miniaudio lists it. Devices that capture
+// and play are listed once for each direction.
+type miniaudioDevice struct {
+	ID      string // opaque, only good for opening the device
+	Name    string
+	Capture bool
+	Default bool // the backend's default for the device's direction
+}
+
+// miniaudioStream is what to open: one direction of a device, or both
+// directions of a full-duplex device.
+type miniaudioStream struct {
+	backend           string
+	capture, playback *miniaudioDevice // nil for a direction not opened
+	captureParams     pcmParams
+	playbackParams    pcmParams
+}
+
+// openMiniaudio opens a stream, returning a pcm for each direction opened,
+// and listMiniaudioDevices lists a backend's devices, set by builds with
+// miniaudio support. The halves of a full-duplex stream keep the device
+// running until both are closed.
+var (
+	openMiniaudio        func(s miniaudioStream) (capture, playback pcmDevice, err error)
+	listMiniaudioDevices func(backend string) ([]miniaudioDevice, error)
+)
+
+func init() {
+	registerModel(MiniaudioModel, resource.Registration[Audio, *MiniaudioConfig]{
+		AttributeMapConverter: migratingConverter[*MiniaudioConfig](MiniaudioModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*MiniaudioConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewMiniaudio(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+// NewMiniaudio returns a resource on the configured miniaudio devices,
+// looked up when the resource is built.
+func NewMiniaudio(name resource.Name, cfg MiniaudioConfig, logger logging.Logger) (Audio, error) {
+	if openMiniaudio == nil {
+		return nil, errors.New("this build has no miniaudio support, build with -tags miniaudio")
+	}
+	devices, err := listMiniaudioDevices(cfg.Backend)
+	if err != nil {
+		return nil, err
+	}
+	rate := cfg.SampleRate
+	if rate == 0 {
+		rate = defaultPCMSampleRate
+	}
+	channels, playbackChannels := cfg.Channels, cfg.PlaybackChannels
+	if channels == 0 {
+		channels = defaultPCMChannels
+	}
+	if playbackChannels == 0 {
+		playbackChannels = defaultPCMPlaybackChannels
+	}
+	period := time.Duration(cfg.PeriodMs) * time.Millisecond
+	if period == 0 {
+		period = defaultMiniaudioPeriod
+	}
+	periods := cfg.Periods
+	if periods == 0 {
+		periods = defaultMiniaudioPeriods
+	}
+	periodFrames := max(1, int(period.Seconds()*float64(rate)))
+
+	chosen := map[bool]*miniaudioDevice{}
+	a := &pcmAudio{
+		Named:   name.AsNamed(),
+		backend: "miniaudio",
+		open: func(_ string, capture bool, p pcmParams) (pcmDevice, error) {
+			s := miniaudioStream{backend: cfg.Backend}
+			if capture {
+				s.capture, s.captureParams = chosen[true], p
+			} else {
+				s.playback, s.playbackParams = chosen[false], p
+			}
+			c, pl, err := openMiniaudio(s)
+			if capture {
+				return c, err
+			}
+			return pl, err
+		},
+		listDevices:    func() (deviceList, error) { return miniaudioDeviceList(cfg.Backend) },
+		captureParams:  pcmParams{rate: rate, channels: channels, periodFrames: periodFrames, bufferFrames: periodFrames * periods},
+		playbackParams: pcmParams{rate: rate, channels: playbackChannels, periodFrames: periodFrames, bufferFrames: periodFrames * periods},
+		logger:         logger,
+		readers:        map[chan pcmBlock]struct{}{},
+	}
+	for _, capture := range []bool{true, false} {
+		configured := cfg.PlaybackDevice
+		if capture {
+			configured = cfg.CaptureDevice
+		}
+		if configured == noPCMDevice {
+			continue
+		}
+		d, err := chooseMiniaudioDevice(devices, configured, capture)
+		if err != nil {
+			return nil, err
+		}
+		chosen[capture] = &d
+		if capture {
+			a.capture = d.Name
+		} else {
+			a.playback = d.Name
+		}
+	}
+	if cfg.FullDuplex {
+		duplex := &miniaudioDuplex{open: func() (pcmDevice, pcmDevice, error) {
+			return openMiniaudio(miniaudioStream{
+				backend:        cfg.Backend,
+				capture:        chosen[true],
+				playback:       chosen[false],
+				captureParams:  a.captureParams,
+				playbackParams: a.playbackParams,
+			})
+		}}
+		a.open = func(_ string, capture bool, _ pcmParams) (pcmDevice, error) { return duplex.openHalf(capture) }
+	}
+	return a, nil
+}
+
+// chooseMiniaudioDevice returns the device for one direction: the one
+// named, else the only one whose name contains configured ignoring case, or
+// the default if configured is empty or "default".
+func chooseMiniaudioDevice(devices []miniaudioDevice, configured string, capture bool) (miniaudioDevice, error) {
+	direction := playbackDirection
+	if capture {
+		direction = captureDirection
+	}
+	var names []string
+	var matches []miniaudioDevice
+	for _, d := range devices {
+		if d.Capture != capture {
+			continue
+		}
+		names = append(names, fmt.Sprintf("%q", d.Name))
+		switch {
+		case configured == "" || configured == miniaudioDefaultDevice:
+			if d.Default {
+				return d, nil
+			}
+		case d.Name == configured:
+			return d, nil
+		case strings.Contains(strings.ToLower(d.Name), strings.ToLower(configured)):
+			matches = append(matches, d)
+		}
+	}
+	switch {
+	case len(names) == 0:
+		return miniaudioDevice{}, fmt.Errorf("no %s devices found", direction)
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return miniaudioDevice{}, fmt.Errorf("%d %s devices match %q, have %s", len(matches), direction, configured, strings.Join(names, ", "))
+	case configured == "" || configured == miniaudioDefaultDevice:
+		return miniaudioDevice{}, fmt.Errorf("no default %s device", direction)
+	default:
+		return miniaudioDevice{}, fmt.Errorf("no %s device %q, have %s", direction, configured, strings.Join(names, ", "))
+	}
+}
+
+// miniaudioDeviceList lists the devices of backend.
+func miniaudioDeviceList(backend string) (deviceList, error) {
+	devices, err := listMiniaudioDevices(backend)
+	if err != nil {
+		return deviceList{}, err
+	}
+	driver := backend
+	if driver == "" {
+		driver = "miniaudio"
+	}
+	var list deviceList
+	// a device listed for both directions under one id is listed once
+	index := map[string]int{}
+	for _, d := range devices {
+		i, ok := index[d.ID]
+		if !ok {
+			i, index[d.ID] = len(list.devices), len(list.devices)
+			list.devices = append(list.devices, SoundDevice{ID: d.ID, Name: d.Name, Driver: driver})
+		}
+		if d.Capture {
+			list.devices[i].Capture = true
+		} else {
+			list.devices[i].Playback = true
+		}
+		switch {
+		case d.Default && d.Capture:
+			list.defaultCapture = d.ID
+		case d.Default:
+			list.defaultPlayback = d.ID
+		}
+	}
+	return list, nil
+}
+
+// miniaudioDuplex shares one full-duplex device between a resource's
+// capture and playback, which open and close their halves of it
+// independently. The device is opened with the first half and closed with
+// the last; a half that isn't open meanwhile captures into an overrun or
+// plays silence.
+type miniaudioDuplex struct {
+	open func() (capture, playback pcmDevice, err error)
+
+	mu     sync.Mutex
+	halves map[bool]pcmDevice // by capture, nil while the device is closed
+	inUse  map[bool]bool
+}
+
+func (d *miniaudioDuplex) openHalf(capture bool) (pcmDevice, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.inUse[capture] {
+		return nil, errors.New("already open")
+	}
+	if d.halves == nil {
+		c, p, err := d.open()
+		if err != nil {
+			return nil, err
+		}
+		d.halves, d.inUse = map[bool]pcmDevice{true: c, false: p}, map[bool]bool{}
+	}
+	d.inUse[capture] = true
+	return &duplexHalf{pcmDevice: d.halves[capture], duplex: d, capture: capture}, nil
+}
+
+// duplexHalf is one direction of a miniaudioDuplex, which closing hands
+// back.
+type duplexHalf struct {
+	pcmDevice
+	duplex  *miniaudioDuplex
+	capture bool
+	closed  bool
+}
+
+func (h *duplexHalf) close() error {
+	d := h.duplex
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	h.closed = true
+	d.inUse[h.capture] = false
+	if d.inUse[!h.capture] {
+		return nil
+	}
+	err := errors.Join(d.halves[true].close(), d.halves[false].close())
+	d.halves = nil
+	return err
+}
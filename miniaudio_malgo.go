@@ -0,0 +1,314 @@
+//go:build miniaudio
+
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gen2brain/malgo"
+)
+
+func init() {
+	openMiniaudio = openMalgo
+	listMiniaudioDevices = malgoDevices
+}
+
+var malgoBackends = map[string]malgo.Backend{
+	"wasapi":     malgo.BackendWasapi,
+	"dsound":     malgo.BackendDsound,
+	"winmm":      malgo.BackendWinmm,
+	"coreaudio":  malgo.BackendCoreaudio,
+	"pulseaudio": malgo.BackendPulseaudio,
+	"alsa":       malgo.BackendAlsa,
+	"jack":       malgo.BackendJack,
+	"aaudio":     malgo.BackendAaudio,
+	"opensl":     malgo.BackendOpensl,
+}
+
+var (
+	errMiniaudioOverrun = errors.New("capture overrun")
+	errMiniaudioStopped = errors.New("miniaudio stopped the device, it was likely unplugged")
+)
+
+// A miniaudio context is initialized once for each backend used and kept,
+// as devices hold on to it.
+var malgoContexts struct {
+	mu   sync.Mutex
+	ctxs map[string]*malgo.AllocatedContext
+}
+
+func malgoContext(backend string) (*malgo.AllocatedContext, error) {
+	malgoContexts.mu.Lock()
+	defer malgoContexts.mu.Unlock()
+	if ctx, ok := malgoContexts.ctxs[backend]; ok {
+		return ctx, nil
+	}
+	var backends []malgo.Backend
+	if backend != "" {
+		b, ok := malgoBackends[backend]
+		if !ok {
+			return nil, fmt.Errorf("unknown miniaudio backend %q", backend)
+		}
+		backends = []malgo.Backend{b}
+	}
+	ctx, err := malgo.InitContext(backends, malgo.ContextConfig{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("miniaudio: %w", err)
+	}
+	if malgoContexts.ctxs == nil {
+		malgoContexts.ctxs = map[string]*malgo.AllocatedContext{}
+	}
+	malgoContexts.ctxs[backend] = ctx
+	return ctx, nil
+}
+
+func malgoDevices(backend string) ([]miniaudioDevice, error) {
+	ctx, err := malgoContext(backend)
+	if err != nil {
+		return nil, err
+	}
+	var devices []miniaudioDevice
+	for _, kind := range []malgo.DeviceType{malgo.Capture, malgo.Playback} {
+		infos, err := ctx.Devices(kind)
+		if err != nil {
+			return nil, fmt.Errorf("miniaudio: %w", err)
+		}
+		for _, info := range infos {
+			devices = append(devices, miniaudioDevice{
+				ID:      info.ID.String(),
+				Name:    info.Name(),
+				Capture: kind == malgo.Capture,
+				Default: info.IsDefault != 0,
+			})
+		}
+	}
+	return devices, nil
+}
+
+// malgoDeviceID looks up the miniaudio id of a listed device.
+func malgoDeviceID(ctx *malgo.AllocatedContext, d *miniaudioDevice) (*malgo.DeviceID, error) {
+	kind := malgo.Playback
+	if d.Capture {
+		kind = malgo.Capture
+	}
+	infos, err := ctx.Devices(kind)
+	if err != nil {
+		return nil, fmt.Errorf("miniaudio: %w", err)
+	}
+	for _, info := range infos {
+		if info.ID.String() == d.ID {
+			id := info.ID
+			return &id, nil
+		}
+	}
+	return nil, fmt.Errorf("%q is gone", d.Name)
+}
+
+// malgoStream is a miniaudio device. Its callback moves audio between the
+// device and a capture and a playback queue, which the pcm halves block
+// on. Playback that runs out is padded with silence, as a full-duplex
+// device plays while only capture is used.
+type malgoStream struct {
+	dev *malgo.Device
+
+	mu       sync.Mutex
+	changed  *sync.Cond // signalled whenever the callback ran or the device stopped
+	in, out  []byte
+	inMax    int // capture bytes queued before an overrun
+	outMax   int // playback bytes queued before write blocks
+	overrun  bool
+	stopped  bool
+	closing  bool
+	openHalf int
+}
+
+func openMalgo(s miniaudioStream) (pcmDevice, pcmDevice, error) {
+	ctx, err := malgoContext(s.backend)
+	if err != nil {
+		return nil, nil, err
+	}
+	var cfg malgo.DeviceConfig
+	switch {
+	case s.capture != nil && s.playback != nil:
+		cfg = malgo.DefaultDeviceConfig(malgo.Duplex)
+	case s.capture != nil:
+		cfg = malgo.DefaultDeviceConfig(malgo.Capture)
+	default:
+		cfg = malgo.DefaultDeviceConfig(malgo.Playback)
+	}
+	st := &malgoStream{}
+	st.changed = sync.NewCond(&st.mu)
+	// both directions run at the same rate and period
+	p := s.playbackParams
+	if s.capture != nil {
+		p = s.captureParams
+		id, err := malgoDeviceID(ctx, s.capture)
+		if err != nil {
+			return nil, nil, err
+		}
+		cfg.Capture.DeviceID = id.Pointer()
+		cfg.Capture.Format = malgo.FormatS16
+		cfg.Capture.Channels = uint32(s.captureParams.channels)
+		st.inMax = 2 * s.captureParams.channels * s.captureParams.bufferFrames
+	}
+	if s.playback != nil {
+		id, err := malgoDeviceID(ctx, s.playback)
+		if err != nil {
+			return nil, nil, err
+		}
+		cfg.Playback.DeviceID = id.Pointer()
+		cfg.Playback.Format = malgo.FormatS16
+		cfg.Playback.Channels = uint32(s.playbackParams.channels)
+		st.outMax = 2 * s.playbackParams.channels * s.playbackParams.bufferFrames
+	}
+	cfg.SampleRate = uint32(p.rate)
+	cfg.PeriodSizeInFrames = uint32(p.periodFrames)
+	cfg.Periods = uint32(max(1, p.bufferFrames/p.periodFrames))
+
+	st.dev, err = malgo.InitDevice(ctx.Context, cfg, malgo.DeviceCallbacks{Data: st.transfer, Stop: st.stop})
+	if err != nil {
+		return nil, nil, fmt.Errorf("miniaudio: %w", err)
+	}
+	if err := st.dev.Start(); err != nil {
+		st.dev.Uninit()
+		return nil, nil, fmt.Errorf("miniaudio: %w", err)
+	}
+	var capture, playback pcmDevice
+	if s.capture != nil {
+		st.openHalf++
+		capture = &malgoHalf{s: st, capture: true, channels: s.captureParams.channels}
+	}
+	if s.playback != nil {
+		st.openHalf++
+		playback = &malgoHalf{s: st, channels: s.playbackParams.channels}
+	}
+	return capture, playback, nil
+}
+
+// transfer is the device callback.
+func (s *malgoStream) transfer(output, input []byte, _ uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(input) > 0 && !s.overrun {
+		if len(s.in)+len(input) > s.inMax {
+			s.in, s.overrun = s.in[:0], true
+		} else {
+			s.in = append(s.in, input...)
+		}
+	}
+	if len(output) > 0 {
+		n := copy(output, s.out)
+		clear(output[n:])
+		s.out = s.out[:copy(s.out, s.out[n:])]
+	}
+	s.changed.Broadcast()
+}
+
+// stop is called when the device stops, which it only does by itself when
+// it goes away.
+func (s *malgoStream) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closing {
+		s.stopped = true
+	}
+	s.changed.Broadcast()
+}
+
+// malgoHalf is one direction of a malgoStream.
+type malgoHalf struct {
+	s        *malgoStream
+	capture  bool
+	channels int
+	closed   bool
+}
+
+func (h *malgoHalf) read(buf []int16) (int, error) {
+	s := h.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	need := 2 * len(buf)
+	for len(s.in) < need && !s.overrun && !s.stopped {
+		s.changed.Wait()
+	}
+	switch {
+	case s.stopped:
+		return 0, errMiniaudioStopped
+	case s.overrun:
+		return 0, errMiniaudioOverrun
+	}
+	for i := range buf {
+		buf[i] = int16(binary.LittleEndian.Uint16(s.in[2*i:]))
+	}
+	s.in = s.in[:copy(s.in, s.in[need:])]
+	return len(buf) / h.channels, nil
+}
+
+func (h *malgoHalf) write(buf []int16) (int, error) {
+	s := h.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.out) > 0 && len(s.out)+2*len(buf) > s.outMax && !s.stopped {
+		s.changed.Wait()
+	}
+	if s.stopped {
+		return 0, errMiniaudioStopped
+	}
+	for _, v := range buf {
+		s.out = binary.LittleEndian.AppendUint16(s.out, uint16(v))
+	}
+	return len(buf) / h.channels, nil
+}
+
+func (h *malgoHalf) recover(err error) error {
+	if !errors.Is(err, errMiniaudioOverrun) {
+		return err
+	}
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	h.s.overrun = false
+	return nil
+}
+
+func (h *malgoHalf) drain() error {
+	s := h.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.out) > 0 && !s.stopped {
+		s.changed.Wait()
+	}
+	if s.stopped {
+		return errMiniaudioStopped
+	}
+	return nil
+}
+
+// close closes the half, and the device with the last half open.
+func (h *malgoHalf) close() error {
+	s := h.s
+	s.mu.Lock()
+	if h.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	s.openHalf--
+	last := s.openHalf == 0
+	if last {
+		s.closing = true
+	}
+	s.mu.Unlock()
+	if !last {
+		return nil
+	}
+	// stopping waits for the callback, so it can't hold the lock
+	err := s.dev.Stop()
+	s.dev.Uninit()
+	if err != nil {
+		return fmt.Errorf("miniaudio: %w", err)
+	}
+	return nil
+}
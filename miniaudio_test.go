@@ -0,0 +1,169 @@
+package audio
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// a Raspberry Pi with a USB headset, as miniaudio lists it on ALSA
+var piMiniaudioDevices = []miniaudioDevice{
+	{ID: "6877", Name: "bcm2835 Headphones", Default: true},
+	{ID: "6831", Name: "USB Headset", Capture: true, Default: true},
+	{ID: "6831", Name: "USB Headset"},
+	{ID: "6832", Name: "USB Headset Sidetone", Capture: true},
+}
+
+func TestChooseMiniaudioDevice(t *testing.T) {
+	for _, tc := range []struct {
+		device  string
+		capture bool
+		want    string
+		err     string
+	}{
+		{"", true, "USB Headset", ""},
+		{"default", false, "bcm2835 Headphones", ""},
+		{"USB Headset", true, "USB Headset", ""},
+		{"sidetone", true, "USB Headset Sidetone", ""},
+		{"usb", false, "USB Headset", ""},
+		{"usb", true, "", "2 capture devices match"},
+		{"hdmi", false, "", "no playback device"},
+	} {
+		d, err := chooseMiniaudioDevice(piMiniaudioDevices, tc.device, tc.capture)
+		switch {
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%q: got %v, want an error with %q", tc.device, err, tc.err)
+		case tc.err == "" && err != nil:
+			t.Errorf("%q: %v", tc.device, err)
+		case tc.err == "" && (d.Name != tc.want || d.Capture != tc.capture):
+			t.Errorf("%q: chose %+v, want %q", tc.device, d, tc.want)
+		}
+	}
+	if _, err := chooseMiniaudioDevice(piMiniaudioDevices[:1], "", true); err == nil || !strings.Contains(err.Error(), "no capture devices") {
+		t.Errorf("chose a capture device among none: %v", err)
+	}
+}
+
+func TestMiniaudioFullDuplex(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		opened []miniaudioStream
+		halves []*fakePCM
+	)
+	prevOpen, prevList := openMiniaudio, listMiniaudioDevices
+	openMiniaudio = func(s miniaudioStream) (pcmDevice, pcmDevice, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		opened = append(opened, s)
+		var capture, playback *fakePCM
+		if s.capture != nil {
+			p := s.captureParams
+			capture = &fakePCM{p: p, period: time.Duration(p.periodFrames) * time.Second / time.Duration(p.rate), fail: map[int]bool{}}
+			halves = append(halves, capture)
+		}
+		if s.playback != nil {
+			playback = &fakePCM{p: s.playbackParams, fail: map[int]bool{}}
+			halves = append(halves, playback)
+		}
+		return capture, playback, nil
+	}
+	listMiniaudioDevices = func(string) ([]miniaudioDevice, error) { return piMiniaudioDevices, nil }
+	defer func() { openMiniaudio, listMiniaudioDevices = prevOpen, prevList }()
+
+	a, err := NewMiniaudio(Named("pi"), MiniaudioConfig{Backend: "alsa", SampleRate: 16000, FullDuplex: true}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	capture := func() {
+		t.Helper()
+		chunks, err := a.GetAudio(ctx, Pcm16.String(), 0.25, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, audio, _ := drain(t, chunks); audio != 250*time.Millisecond {
+			t.Errorf("captured %v, want 250ms", audio)
+		}
+	}
+	capture()
+	clip, _ := encodePCM(tone(16000, 160, 440, 0.5), Pcm16)
+	if err := a.Play(ctx, clip, Pcm16.String(), 16000, 1); err != nil {
+		t.Fatal(err)
+	}
+	// capture stopped and started again while playback held the device
+	capture()
+
+	mu.Lock()
+	if len(opened) != 1 || opened[0].capture.Name != "USB Headset" || opened[0].playback.Name != "bcm2835 Headphones" {
+		t.Errorf("opened %+v, want one duplex stream", opened)
+	}
+	if opened[0].captureParams.rate != 16000 || opened[0].playbackParams.channels != 2 || opened[0].captureParams.periodFrames != 160 {
+		t.Errorf("opened with %+v and %+v", opened[0].captureParams, opened[0].playbackParams)
+	}
+	mu.Unlock()
+
+	if err := a.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, h := range halves {
+		h.mu.Lock()
+		if !h.closed {
+			t.Error("a half is still open")
+		}
+		h.mu.Unlock()
+	}
+	if len(halves[1].written) != 2*160 {
+		t.Errorf("played %d samples, want 320", len(halves[1].written))
+	}
+}
+
+func TestMiniaudioDevices(t *testing.T) {
+	prevOpen, prevList := openMiniaudio, listMiniaudioDevices
+	openMiniaudio = func(miniaudioStream) (pcmDevice, pcmDevice, error) { return nil, nil, errFakeXrun }
+	listMiniaudioDevices = func(string) ([]miniaudioDevice, error) { return piMiniaudioDevices, nil }
+	defer func() { openMiniaudio, listMiniaudioDevices = prevOpen, prevList }()
+	a, err := NewMiniaudio(Named("pi"), MiniaudioConfig{}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := a.DoCommand(context.Background(), map[string]interface{}{"list_devices": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the headset is listed once, for both directions
+	if devices := resp["devices"].([]interface{}); len(devices) != 3 || resp["default_capture"] != "6831" || resp["default_playback"] != "6877" {
+		t.Errorf("listed %v", resp)
+	}
+}
+
+func TestMiniaudioConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg MiniaudioConfig
+		ok  bool
+	}{
+		{MiniaudioConfig{}, true},
+		{MiniaudioConfig{Backend: "pulseaudio", CaptureDevice: "USB", PlaybackDevice: "none"}, true},
+		{MiniaudioConfig{Backend: "oss"}, false},
+		{MiniaudioConfig{CaptureDevice: "none", PlaybackDevice: "none"}, false},
+		{MiniaudioConfig{PlaybackDevice: "none", FullDuplex: true}, false},
+		{MiniaudioConfig{Periods: -1}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+
+	prev := openMiniaudio
+	openMiniaudio = nil
+	defer func() { openMiniaudio = prev }()
+	if _, err := NewMiniaudio(Named("pi"), MiniaudioConfig{}, logging.NewTestLogger(t)); err == nil {
+		t.Error("built without miniaudio support")
+	}
+}
@@ -0,0 +1,127 @@
+package audio
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/braheezy/shine-mp3/pkg/mp3"
+)
+
+// mp3SampleRates are the rates MPEG-1 and MPEG-2 layer III can carry.
+var mp3SampleRates = map[int]bool{
+	44100: true, 48000: true, 32000: true,
+	22050: true, 24000: true, 16000: true,
+	11025: true, 12000: true, 8000: true,
+}
+
+// mp3FallbackRate is what audio at rates MP3 can't carry is resampled to.
+const mp3FallbackRate = 44100
+
+// mp3Bitrates are the bitrates in kbps of the frame header's bitrate
+// indexes 1 to 14, for MPEG-1 rates and for the lower MPEG-2 and 2.5 ones.
+var (
+	mp3BitratesMPEG1 = []int{32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320}
+	mp3BitratesMPEG2 = []int{8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160}
+)
+
+// mp3Encoder encodes interleaved float32 samples to stereo MP3 frames at
+// 128 kbps. The encoder only works on whole frames, so samples are buffered
+// until one is complete.
+type mp3Encoder struct {
+	enc          *mp3.Encoder
+	resampler    *resampler // nil when the input rate is carried as-is
+	inputRate    int        // rate of the samples passed to encode
+	sampleRate   int        // rate of the encoded stream
+	channels     int        // channels of the samples passed to encode
+	frameSamples int        // samples per channel in one frame
+	pending      []int16
+}
+
+// mp3Channels is what every stream is encoded as; the encoder's mono mode
+// writes frames decoders reject.
+const mp3Channels = 2
+
+// newMP3Encoder returns an encoder for audio at sampleRate with the given
+// number of channels. Rates MP3 can't carry are resampled to
+// mp3FallbackRate.
+func newMP3Encoder(sampleRate, channels int) *mp3Encoder {
+	e := &mp3Encoder{inputRate: sampleRate, sampleRate: sampleRate, channels: channels}
+	if !mp3SampleRates[sampleRate] {
+		e.sampleRate = mp3FallbackRate
+		e.resampler = newResampler(sampleRate, e.sampleRate, channels)
+	}
+	e.enc = mp3.NewEncoder(e.sampleRate, mp3Channels)
+	e.frameSamples = int(e.enc.Mpeg.GranulesPerFrame) * mp3.GRANULE_SIZE
+	return e
+}
+
+// bitrates returns the bitrates in kbps the encoder can switch to, lowest
+// first.
+func (e *mp3Encoder) bitrates() []int {
+	ladder := mp3BitratesMPEG2
+	if e.enc.Mpeg.Version == mp3.MPEG_I {
+		ladder = mp3BitratesMPEG1
+	}
+	var out []int
+	for _, kbps := range ladder {
+		if _, err := mp3.CheckConfig(e.sampleRate, kbps); err == nil {
+			out = append(out, kbps)
+		}
+	}
+	return out
+}
+
+// setBitrate switches the frames encoded from now on to kbps, one of
+// bitrates. Every frame header carries its own bitrate, so decoders follow
+// the switch as they would VBR.
+func (e *mp3Encoder) setBitrate(kbps int) error {
+	m := &e.enc.Mpeg
+	if int(m.Bitrate) == kbps {
+		return nil
+	}
+	if _, err := mp3.CheckConfig(e.sampleRate, kbps); err != nil {
+		return fmt.Errorf("cannot encode mp3 at %d Hz and %d kbps: %w", e.sampleRate, kbps, err)
+	}
+	ladder := mp3BitratesMPEG2
+	if m.Version == mp3.MPEG_I {
+		ladder = mp3BitratesMPEG1
+	}
+	// the encoder works out its frame size once, in NewEncoder
+	slots := float64(m.GranulesPerFrame*mp3.GRANULE_SIZE) / float64(e.sampleRate) * float64(kbps) * 1000 / float64(m.BitsPerSlot)
+	m.Bitrate, m.BitrateIndex = int64(kbps), int64(slices.Index(ladder, kbps)+1)
+	m.WholeSlotsPerFrame = int64(slots)
+	m.FracSlotsPerFrame = slots - float64(m.WholeSlotsPerFrame)
+	m.SlotLag, m.Padding = -m.FracSlotsPerFrame, 0
+	return nil
+}
+
+// frameDuration is how much audio one MP3 frame holds.
+func (e *mp3Encoder) frameDuration() time.Duration {
+	return time.Duration(e.frameSamples) * time.Second / time.Duration(e.sampleRate)
+}
+
+// encode buffers samples and returns the frames that are complete, along
+// with how many frames that is.
+func (e *mp3Encoder) encode(samples []float32) ([]byte, int) {
+	if e.resampler != nil {
+		samples = e.resampler.process(samples)
+	}
+	for _, s := range remix(samples, e.channels, mp3Channels) {
+		e.pending = append(e.pending, int16(clip(s)*32767))
+	}
+	var out []byte
+	frames := 0
+	frameLen := e.frameSamples * mp3Channels
+	for len(e.pending) >= frameLen {
+		data, n := e.enc.EncodeBufferInterleaved(e.pending[:frameLen])
+		// the encoder reuses its output buffer, so copy before the next frame
+		out = append(out, data[:n]...)
+		e.pending = e.pending[frameLen:]
+		frames++
+	}
+	if len(e.pending) == 0 {
+		e.pending = nil
+	}
+	return out, frames
+}
@@ -0,0 +1,546 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Defaults for MQTTConfig.
+const (
+	defaultMQTTCooldown  = 5 * time.Second
+	defaultMQTTKeepAlive = 30 * time.Second
+	mqttAckTimeout       = 10 * time.Second
+	// QoS 1 messages that can be waiting for PUBACK before publish blocks
+	mqttMaxInflight = 16
+	// format the events are detected on and clips are encoded in
+	mqttSampleRate = 16000
+)
+
+// MQTTConfig describes the broker audio events are published to and which
+// events are sent.
+//
+// Every event is a JSON object published on Topic/<event>:
+//
+//	{"resource": "mic", "event": "speech", "time": "...", "level_dbfs": -23.5, "clip": "<base64 WAV>"}
+//
+// where event is one of Conditions or "level_exceeded", and clip is only
+// present when ClipDuration is set.
+type MQTTConfig struct {
+	// Broker is tcp://host:port, or ssl://host:port for TLS. Credentials in
+	// the URL are used when Password is empty.
+	Broker   string
+	ClientID string // random if empty
+	Username string
+	Password string
+
+	Topic  string // audio/<resource name> if empty
+	QoS    byte   // 0 or 1
+	Retain bool
+
+	// Conditions are the detectors (see DetectorNames) that publish an event
+	// when they start matching, "sound" if empty.
+	Conditions []string
+	// LevelThresholdDBFS publishes level_exceeded when the RMS level rises
+	// above it. Zero disables the event.
+	LevelThresholdDBFS float64
+	// Cooldown is the shortest time between two events of the same kind,
+	// 5s if zero.
+	Cooldown time.Duration
+	// ClipDuration attaches a 16 kHz mono WAV clip of about this length,
+	// starting shortly before the event, to every event. Events with clips are
+	// published once the clip has been recorded.
+	ClipDuration time.Duration
+}
+
+type mqttEvent struct {
+	Resource  string    `json:"resource"`
+	Event     string    `json:"event"`
+	Time      time.Time `json:"time"`
+	LevelDBFS float64   `json:"level_dbfs"`
+	Clip      string    `json:"clip,omitempty"`
+}
+
+// PublishMQTTEvents publishes events derived from the capture of a to an
+// MQTT broker until ctx is done, the capture ends or the broker connection
+// drops.
+func PublishMQTTEvents(ctx context.Context, a Audio, cfg MQTTConfig) error {
+	if cfg.QoS > 1 {
+		return fmt.Errorf("unsupported mqtt qos %d, expected 0 or 1", cfg.QoS)
+	}
+	if cfg.Topic == "" {
+		cfg.Topic = "audio/" + a.Name().ShortName()
+	}
+	if len(cfg.Conditions) == 0 {
+		cfg.Conditions = []string{"sound"}
+	}
+	if cfg.Cooldown == 0 {
+		cfg.Cooldown = defaultMQTTCooldown
+	}
+	detectors := make([]Detector, len(cfg.Conditions))
+	for i, c := range cfg.Conditions {
+		d, err := newDetector(c)
+		if err != nil {
+			return err
+		}
+		detectors[i] = d
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chunks, err := sharedCaptureHub.open(ctx, a, captureRequest{
+		target: AudioInfo{Format: Pcm16, SampleRate: mqttSampleRate, Channels: 1},
+	})
+	if err != nil {
+		return err
+	}
+	client, err := dialMQTT(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer client.close()
+
+	publish := func(ev mqttEvent) error {
+		payload, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		return client.publish(ctx, cfg.Topic+"/"+ev.Event, payload, cfg.QoS, cfg.Retain)
+	}
+
+	// the recent audio kept for the start of clips
+	var recent []byte
+	preRoll := int(defaultPreRoll.Seconds()*mqttSampleRate) * 2
+	clipBytes := int(cfg.ClipDuration.Seconds()*mqttSampleRate) * 2
+	type pendingClip struct {
+		ev   mqttEvent
+		data []byte
+	}
+	var clips []*pendingClip
+
+	active := map[string]bool{}
+	last := map[string]time.Time{}
+	for {
+		var chunk *AudioChunk
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-client.done:
+			return client.err
+		case c, ok := <-chunks:
+			if !ok {
+				return nil
+			}
+			chunk = c
+		}
+		if chunk.Err != nil {
+			return chunk.Err
+		}
+		samples, err := decodePCM(chunk.AudioData, Pcm16)
+		if err != nil {
+			return err
+		}
+		level := dbfs(rms(samples))
+		now := time.Now()
+
+		var fired []string
+		trigger := func(event string, matched bool) {
+			if matched && !active[event] && now.Sub(last[event]) >= cfg.Cooldown {
+				fired = append(fired, event)
+				last[event] = now
+			}
+			active[event] = matched
+		}
+		for i, d := range detectors {
+			trigger(cfg.Conditions[i], d.Detect(samples, *chunk.Info))
+		}
+		if cfg.LevelThresholdDBFS != 0 {
+			trigger("level_exceeded", level > cfg.LevelThresholdDBFS)
+		}
+
+		for _, clip := range clips {
+			clip.data = append(clip.data, chunk.AudioData...)
+		}
+		kept := clips[:0]
+		for _, clip := range clips {
+			if len(clip.data) < clipBytes {
+				kept = append(kept, clip)
+				continue
+			}
+			var wav bytes.Buffer
+			if err := writeWAVHeader(&wav, newWAVHeader(mqttSampleRate, 1, 16, uint32(len(clip.data)))); err != nil {
+				return err
+			}
+			wav.Write(clip.data)
+			clip.ev.Clip = base64.StdEncoding.EncodeToString(wav.Bytes())
+			if err := publish(clip.ev); err != nil {
+				return err
+			}
+		}
+		clips = kept
+
+		recent = append(recent, chunk.AudioData...)
+		if len(recent) > preRoll {
+			recent = recent[len(recent)-preRoll:]
+		}
+		for _, event := range fired {
+			ev := mqttEvent{Resource: a.Name().ShortName(), Event: event, Time: now, LevelDBFS: level}
+			if clipBytes == 0 {
+				if err := publish(ev); err != nil {
+					return err
+				}
+				continue
+			}
+			// the pre-roll already ends with this chunk
+			clips = append(clips, &pendingClip{ev: ev, data: append([]byte(nil), recent...)})
+		}
+	}
+}
+
+// mqttClient is a minimal MQTT 3.1.1 publisher: it connects, publishes at
+// QoS 0 or 1 and keeps the connection alive, and never subscribes.
+//
+// QoS 1 messages are acknowledged asynchronously. Up to mqttMaxInflight can
+// be unacknowledged at once, so publishing only waits on the broker when it
+// falls that far behind, and a message that isn't acknowledged within
+// ackTimeout drops the connection.
+type mqttClient struct {
+	conn       net.Conn
+	keepAlive  time.Duration
+	ackTimeout time.Duration
+
+	writeMu  sync.Mutex
+	inflight chan struct{} // holds a token per unacknowledged message
+	mu       sync.Mutex
+	nextID   uint16
+	pending  map[uint16]time.Time // when each unacknowledged message was sent
+
+	done    chan struct{} // closed when the connection is lost
+	errOnce sync.Once
+	err     error // why, readable once done is closed
+}
+
+// MQTT control packet types, shifted into the fixed header.
+const (
+	mqttConnect    = 1 << 4
+	mqttConnAck    = 2 << 4
+	mqttPublish    = 3 << 4
+	mqttPubAck     = 4 << 4
+	mqttPingReq    = 12 << 4
+	mqttPingResp   = 13 << 4
+	mqttDisconnect = 14 << 4
+)
+
+func dialMQTT(ctx context.Context, cfg MQTTConfig) (*mqttClient, error) {
+	u, err := url.Parse(cfg.Broker)
+	if err != nil {
+		return nil, err
+	}
+	host, useTLS := u.Host, false
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "1883")
+		}
+	case "ssl", "tls", "mqtts":
+		useTLS = true
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "8883")
+		}
+	default:
+		return nil, fmt.Errorf("mqtt broker must be tcp:// or ssl://, got %q", u.Scheme)
+	}
+
+	var conn net.Conn
+	if useTLS {
+		d := tls.Dialer{Config: &tls.Config{ServerName: u.Hostname()}}
+		conn, err = d.DialContext(ctx, "tcp", host)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, password := cfg.Username, cfg.Password
+	if password == "" && u.User != nil {
+		user = u.User.Username()
+		password, _ = u.User.Password()
+	}
+	clientID := cfg.ClientID
+	if clientID == "" {
+		var id [8]byte
+		if _, err := rand.Read(id[:]); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		clientID = "audioapi-" + hex.EncodeToString(id[:])
+	}
+
+	flags := byte(0x02) // clean session
+	var payload []byte
+	payload = appendMQTTString(payload, clientID)
+	if user != "" {
+		flags |= 0x80
+		payload = appendMQTTString(payload, user)
+	}
+	if password != "" {
+		flags |= 0x40
+		payload = appendMQTTString(payload, password)
+	}
+	body := appendMQTTString(nil, "MQTT")
+	body = append(body, 4, flags) // protocol level 3.1.1
+	body = binary.BigEndian.AppendUint16(body, uint16(defaultMQTTKeepAlive.Seconds()))
+	body = append(body, payload...)
+
+	// the broker answers CONNECT with CONNACK before anything else
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(mqttAckTimeout))
+	}
+	if _, err := conn.Write(mqttPacket(mqttConnect, body)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	kind, ack, err := readMQTTPacket(br)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("mqtt broker closed the connection: %w", err)
+	}
+	if kind != mqttConnAck || len(ack) != 2 {
+		conn.Close()
+		return nil, fmt.Errorf("mqtt broker sent packet type %d instead of CONNACK", kind>>4)
+	}
+	if ack[1] != 0 {
+		conn.Close()
+		return nil, fmt.Errorf("mqtt broker refused the connection: %s", mqttConnAckReason(ack[1]))
+	}
+	conn.SetDeadline(time.Time{})
+
+	return newMQTTClient(conn, br, mqttAckTimeout), nil
+}
+
+// newMQTTClient runs a client on a connection the broker has accepted.
+func newMQTTClient(conn net.Conn, br *bufio.Reader, ackTimeout time.Duration) *mqttClient {
+	c := &mqttClient{
+		conn:       conn,
+		keepAlive:  defaultMQTTKeepAlive,
+		ackTimeout: ackTimeout,
+		inflight:   make(chan struct{}, mqttMaxInflight),
+		pending:    map[uint16]time.Time{},
+		done:       make(chan struct{}),
+	}
+	go c.read(br)
+	go c.ping()
+	return c
+}
+
+func mqttConnAckReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	default:
+		return fmt.Sprintf("return code %d", code)
+	}
+}
+
+// publish sends one message. At QoS 1 it only waits when the in-flight
+// window is full; the acknowledgement is handled by the reader.
+func (c *mqttClient) publish(ctx context.Context, topic string, payload []byte, qos byte, retain bool) error {
+	header := byte(mqttPublish) | qos<<1
+	if retain {
+		header |= 0x01
+	}
+	body := appendMQTTString(nil, topic)
+	if qos == 0 {
+		return c.write(mqttPacket(header, append(body, payload...)))
+	}
+
+	select {
+	case c.inflight <- struct{}{}:
+	case <-c.done:
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	c.mu.Lock()
+	c.nextID++
+	for c.nextID == 0 || !c.pending[c.nextID].IsZero() {
+		c.nextID++
+	}
+	id := c.nextID
+	c.pending[id] = time.Now()
+	c.mu.Unlock()
+
+	body = binary.BigEndian.AppendUint16(body, id)
+	if err := c.write(mqttPacket(header, append(body, payload...))); err != nil {
+		c.acked(id)
+		return err
+	}
+	return nil
+}
+
+// acked frees the window slot of message id, if it is still pending.
+func (c *mqttClient) acked(id uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pending[id]; ok {
+		delete(c.pending, id)
+		<-c.inflight
+	}
+}
+
+// fail drops the connection, recording err as the reason unless one was
+// already recorded.
+func (c *mqttClient) fail(err error) {
+	c.errOnce.Do(func() { c.err = err })
+	c.conn.Close()
+}
+
+func (c *mqttClient) write(packet []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+func (c *mqttClient) read(br *bufio.Reader) {
+	defer close(c.done)
+	for {
+		kind, body, err := readMQTTPacket(br)
+		if err != nil {
+			c.fail(fmt.Errorf("mqtt connection lost: %w", err))
+			return
+		}
+		switch {
+		case kind == mqttPubAck && len(body) == 2:
+			c.acked(binary.BigEndian.Uint16(body))
+		case kind == mqttPingResp:
+			// only shows the connection is alive
+		}
+	}
+}
+
+// ping keeps the connection alive and drops it when a message goes
+// unacknowledged for too long.
+func (c *mqttClient) ping() {
+	keepAlive := time.NewTicker(c.keepAlive / 2)
+	defer keepAlive.Stop()
+	acks := time.NewTicker(c.ackTimeout / 4)
+	defer acks.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-keepAlive.C:
+			if err := c.write(mqttPacket(mqttPingReq, nil)); err != nil {
+				c.fail(fmt.Errorf("mqtt connection lost: %w", err))
+				return
+			}
+		case now := <-acks.C:
+			c.mu.Lock()
+			var late bool
+			for _, sent := range c.pending {
+				late = late || now.Sub(sent) > c.ackTimeout
+			}
+			c.mu.Unlock()
+			if late {
+				c.fail(errors.New("mqtt broker did not acknowledge a message"))
+				return
+			}
+		}
+	}
+}
+
+// close waits up to the ack timeout for outstanding QoS 1 messages, then
+// disconnects cleanly and waits for the reader to finish.
+func (c *mqttClient) close() {
+	timer := time.NewTimer(c.ackTimeout)
+	defer timer.Stop()
+drain:
+	for i := 0; i < cap(c.inflight); i++ {
+		select {
+		case c.inflight <- struct{}{}:
+		case <-c.done:
+			break drain
+		case <-timer.C:
+			break drain
+		}
+	}
+	c.write(mqttPacket(mqttDisconnect, nil))
+	c.conn.Close()
+	<-c.done
+}
+
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	// remaining length is a base-128 varint
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+func readMQTTPacket(br *bufio.Reader) (byte, []byte, error) {
+	header, err := br.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, shift := 0, 0
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, nil, errors.New("malformed mqtt remaining length")
+		}
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(br, body); err != nil {
+		return 0, nil, err
+	}
+	return header & 0xf0, body, nil
+}
+
+func appendMQTTString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
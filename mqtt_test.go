@@ -0,0 +1,202 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMQTTRemainingLength(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{16383, []byte{0xff, 0x7f}},
+		{16384, []byte{0x80, 0x80, 0x01}},
+		{2097151, []byte{0xff, 0xff, 0x7f}},
+		{2097152, []byte{0x80, 0x80, 0x80, 0x01}},
+	} {
+		packet := mqttPacket(mqttPublish|0x02, make([]byte, tc.n))
+		if got := packet[1 : 1+len(tc.want)]; !bytes.Equal(got, tc.want) {
+			t.Errorf("remaining length %d encoded as % x, want % x", tc.n, got, tc.want)
+		}
+		kind, body, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(packet)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kind != mqttPublish || len(body) != tc.n {
+			t.Errorf("read back type %#x with %d bytes, want %#x with %d", kind, len(body), mqttPublish, tc.n)
+		}
+	}
+	malformed := []byte{mqttPublish, 0xff, 0xff, 0xff, 0xff, 0x01}
+	if _, _, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(malformed))); err == nil {
+		t.Fatal("accepted a five byte remaining length")
+	}
+}
+
+// fakeBroker accepts one MQTT connection and answers CONNECT with returnCode.
+type fakeBroker struct {
+	t        *testing.T
+	listener net.Listener
+	conn     chan net.Conn
+	connect  chan []byte
+}
+
+func newFakeBroker(t *testing.T, returnCode byte) *fakeBroker {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &fakeBroker{t: t, listener: l, conn: make(chan net.Conn, 1), connect: make(chan []byte, 1)}
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		_, body, err := readMQTTPacket(bufio.NewReader(conn))
+		if err != nil {
+			conn.Close()
+			return
+		}
+		b.connect <- body
+		conn.Write(mqttPacket(mqttConnAck, []byte{0, returnCode}))
+		b.conn <- conn
+	}()
+	t.Cleanup(func() { l.Close() })
+	return b
+}
+
+func (b *fakeBroker) url() string { return "tcp://user:secret@" + b.listener.Addr().String() }
+
+// readPublish reads up to the next PUBLISH and returns its packet id.
+func readPublish(r *bufio.Reader) (uint16, error) {
+	for {
+		kind, body, err := readMQTTPacket(r)
+		if err != nil {
+			return 0, err
+		}
+		if kind == mqttPublish {
+			n := int(binary.BigEndian.Uint16(body))
+			return binary.BigEndian.Uint16(body[2+n:]), nil
+		}
+	}
+}
+
+func TestMQTTConnect(t *testing.T) {
+	b := newFakeBroker(t, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c, err := dialMQTT(ctx, MQTTConfig{Broker: b.url(), ClientID: "robot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.close()
+
+	want := []byte{0, 4, 'M', 'Q', 'T', 'T', 4, 0xC2, 0, 30}
+	want = appendMQTTString(want, "robot")
+	want = appendMQTTString(want, "user")
+	want = appendMQTTString(want, "secret")
+	if got := <-b.connect; !bytes.Equal(got, want) {
+		t.Fatalf("CONNECT\n got % x\nwant % x", got, want)
+	}
+}
+
+func TestMQTTConnectRefused(t *testing.T) {
+	b := newFakeBroker(t, 4)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := dialMQTT(ctx, MQTTConfig{Broker: b.url()})
+	if err == nil || !strings.Contains(err.Error(), "bad user name or password") {
+		t.Fatalf("got %v, want the CONNACK reason", err)
+	}
+}
+
+func TestMQTTQoS1Window(t *testing.T) {
+	b := newFakeBroker(t, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c, err := dialMQTT(ctx, MQTTConfig{Broker: b.url()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := <-b.conn
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	// a full window goes out without any acknowledgement
+	ids := make(chan uint16, mqttMaxInflight+1)
+	go func() {
+		defer close(ids)
+		for i := 0; i <= mqttMaxInflight; i++ {
+			id, err := readPublish(r)
+			if err != nil {
+				return
+			}
+			ids <- id
+		}
+	}()
+	for i := 0; i < mqttMaxInflight; i++ {
+		if err := c.publish(ctx, "audio/mic/sound", []byte("{}"), 1, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the next one waits for the broker to catch up
+	published := make(chan error, 1)
+	go func() { published <- c.publish(ctx, "audio/mic/sound", []byte("{}"), 1, false) }()
+	select {
+	case err := <-published:
+		t.Fatalf("publish beyond the window returned %v without waiting", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	first := <-ids
+	conn.Write(mqttPacket(mqttPubAck, binary.BigEndian.AppendUint16(nil, first)))
+	if err := <-published; err != nil {
+		t.Fatal(err)
+	}
+
+	// acknowledge the rest so close doesn't wait
+	for i := 0; i < mqttMaxInflight; i++ {
+		conn.Write(mqttPacket(mqttPubAck, binary.BigEndian.AppendUint16(nil, <-ids)))
+	}
+	closed := make(chan struct{})
+	go func() {
+		c.close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close waited although every message was acknowledged")
+	}
+}
+
+func TestMQTTAckTimeout(t *testing.T) {
+	client, broker := net.Pipe()
+	defer broker.Close()
+	go io.Copy(io.Discard, broker)
+	c := newMQTTClient(client, bufio.NewReader(client), 100*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := c.publish(ctx, "audio/mic/sound", []byte("{}"), 1, false); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		t.Fatal("an unacknowledged message did not drop the connection")
+	}
+	if c.err == nil || !strings.Contains(c.err.Error(), "acknowledge") {
+		t.Fatalf("got %v, want the missing acknowledgement", c.err)
+	}
+}
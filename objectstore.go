@@ -0,0 +1,520 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// Object store providers.
+const (
+	ObjectStoreS3  = "s3"
+	ObjectStoreGCS = "gcs" // through GCS's S3-compatible XML API with HMAC keys
+)
+
+// Defaults for ObjectStoreConfig.
+const (
+	defaultUploadPartSize    = 8 << 20
+	minUploadPartSize        = 5 << 20 // S3's minimum for all but the last part
+	defaultUploadMaxAttempts = 10
+	uploadRetryBase          = time.Second
+	uploadRetryMax           = time.Minute
+	uploadQueueSize          = 64
+)
+
+// ObjectStoreConfig is an object-store destination for recordings.
+type ObjectStoreConfig struct {
+	Provider string `json:"provider"` // s3 or gcs
+	Bucket   string `json:"bucket"`
+	Prefix   string `json:"prefix,omitempty"` // prepended to every object key
+	Region   string `json:"region,omitempty"` // us-east-1 if empty; GCS uses "auto"
+	// Endpoint overrides the service URL, for S3-compatible stores such as
+	// MinIO. Buckets are then addressed path-style.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Credentials. For GCS these are HMAC keys. Empty keys fall back to
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	SessionToken    string `json:"session_token,omitempty"`
+
+	PartSize          int  `json:"part_size_bytes,omitempty"`     // multipart part size, 8 MiB if zero
+	MaxAttempts       int  `json:"max_attempts,omitempty"`        // tries per upload, 10 if zero
+	DeleteAfterUpload bool `json:"delete_after_upload,omitempty"` // remove the local file once stored
+}
+
+// Validate checks the destination.
+func (c *ObjectStoreConfig) Validate(path string) ([]string, []string, error) {
+	switch {
+	case c.Provider != ObjectStoreS3 && c.Provider != ObjectStoreGCS:
+		return nil, nil, resource.NewConfigValidationError(path,
+			fmt.Errorf("unsupported object store provider %q, expected %s or %s", c.Provider, ObjectStoreS3, ObjectStoreGCS))
+	case c.Bucket == "":
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "bucket")
+	case c.PartSize != 0 && c.PartSize < minUploadPartSize:
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("part_size_bytes must be at least %d", minUploadPartSize))
+	case c.MaxAttempts < 0:
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("max_attempts cannot be negative"))
+	}
+	return nil, nil, nil
+}
+
+// ObjectUploader uploads completed recording files to an object store in the
+// background. Every file is retried with backoff, and files larger than one
+// part go up as multipart uploads whose progress is kept next to the file,
+// so an upload interrupted by a restart resumes where it stopped.
+type ObjectUploader struct {
+	cfg    ObjectStoreConfig
+	base   *url.URL
+	client *http.Client
+	logger logging.Logger
+
+	queue   chan uploadJob
+	pending sync.WaitGroup // queued or uploading files
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+type uploadJob struct {
+	path string
+	key  string
+}
+
+// NewObjectUploader starts an uploader for cfg.
+func NewObjectUploader(cfg ObjectStoreConfig, logger logging.Logger) (*ObjectUploader, error) {
+	if _, _, err := cfg.Validate("object_store"); err != nil {
+		return nil, err
+	}
+	if cfg.AccessKeyID == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("object store credentials are missing")
+	}
+	if cfg.PartSize == 0 {
+		cfg.PartSize = defaultUploadPartSize
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = defaultUploadMaxAttempts
+	}
+
+	var endpoint string
+	switch {
+	case cfg.Endpoint != "":
+		endpoint = strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket
+	case cfg.Provider == ObjectStoreGCS:
+		endpoint = "https://storage.googleapis.com/" + cfg.Bucket
+		if cfg.Region == "" {
+			cfg.Region = "auto"
+		}
+	default:
+		if cfg.Region == "" {
+			cfg.Region = "us-east-1"
+		}
+		endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+	}
+	if cfg.Region == "" {
+		// S3-compatible stores mostly ignore the region but it is part of the signature
+		cfg.Region = "us-east-1"
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	u := &ObjectUploader{
+		cfg:    cfg,
+		base:   base,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		logger: logger,
+		queue:  make(chan uploadJob, uploadQueueSize),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go u.run(ctx)
+	return u, nil
+}
+
+// Upload queues the file at path to be stored as Prefix+name. It returns an
+// error only if the queue is full; upload failures are logged.
+func (u *ObjectUploader) Upload(path, name string) error {
+	u.pending.Add(1)
+	select {
+	case u.queue <- uploadJob{path: path, key: u.cfg.Prefix + name}:
+		return nil
+	default:
+		u.pending.Done()
+		return fmt.Errorf("upload queue is full, %s stays local", path)
+	}
+}
+
+// Flush waits until every queued file has been uploaded or given up on, or
+// ctx is done.
+func (u *ObjectUploader) Flush(ctx context.Context) error {
+	idle := make(chan struct{})
+	go func() {
+		u.pending.Wait()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops uploading. Queued files stay on disk, and partly uploaded
+// ones resume when they are queued again.
+func (u *ObjectUploader) Close() error {
+	u.cancel()
+	<-u.done
+	return nil
+}
+
+func (u *ObjectUploader) run(ctx context.Context) {
+	defer close(u.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-u.queue:
+			u.uploadWithRetry(ctx, job)
+			u.pending.Done()
+		}
+	}
+}
+
+func (u *ObjectUploader) uploadWithRetry(ctx context.Context, job uploadJob) {
+	backoff := uploadRetryBase
+	for attempt := 1; ; attempt++ {
+		err := u.upload(ctx, job)
+		if err == nil {
+			u.logger.Debugw("uploaded recording", "path", job.path, "key", job.key)
+			if u.cfg.DeleteAfterUpload {
+				if err := os.Remove(job.path); err != nil {
+					u.logger.Warnw("failed to remove uploaded recording", "path", job.path, "error", err)
+				}
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		var perm *permanentUploadError
+		if errors.As(err, &perm) || attempt == u.cfg.MaxAttempts {
+			u.logger.Errorw("giving up uploading recording", "path", job.path, "key", job.key, "attempts", attempt, "error", err)
+			return
+		}
+		u.logger.Warnw("recording upload failed, retrying", "path", job.path, "retry_in", backoff.String(), "error", err)
+		if sleepCtx(ctx, backoff) != nil {
+			return
+		}
+		backoff = min(2*backoff, uploadRetryMax)
+	}
+}
+
+// permanentUploadError is a failure retrying won't fix, such as bad
+// credentials or a missing bucket.
+type permanentUploadError struct{ err error }
+
+func (e *permanentUploadError) Error() string { return e.err.Error() }
+func (e *permanentUploadError) Unwrap() error { return e.err }
+
+// multipartState is the progress of a multipart upload, saved next to the
+// file being uploaded.
+type multipartState struct {
+	Key      string         `json:"key"`
+	UploadID string         `json:"upload_id"`
+	Size     int64          `json:"size"`
+	ETags    map[int]string `json:"etags"` // by part number
+}
+
+func uploadStatePath(path string) string { return path + ".upload" }
+
+func (u *ObjectUploader) upload(ctx context.Context, job uploadJob) error {
+	f, err := os.Open(job.path)
+	if err != nil {
+		return &permanentUploadError{err}
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() <= int64(u.cfg.PartSize) {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		_, err = u.do(ctx, http.MethodPut, job.key, nil, data)
+		return err
+	}
+
+	state := &multipartState{}
+	if b, err := os.ReadFile(uploadStatePath(job.path)); err == nil {
+		if json.Unmarshal(b, state) != nil || state.Key != job.key || state.Size != fi.Size() {
+			state = &multipartState{}
+		}
+	}
+	if state.UploadID == "" {
+		resp, err := u.do(ctx, http.MethodPost, job.key, url.Values{"uploads": {""}}, nil)
+		if err != nil {
+			return err
+		}
+		var init struct {
+			UploadID string `xml:"UploadId"`
+		}
+		if err := xml.Unmarshal(resp, &init); err != nil || init.UploadID == "" {
+			return fmt.Errorf("object store did not start a multipart upload: %s", resp)
+		}
+		state = &multipartState{Key: job.key, UploadID: init.UploadID, Size: fi.Size(), ETags: map[int]string{}}
+		if err := saveMultipartState(job.path, state); err != nil {
+			return err
+		}
+	}
+
+	parts := int((fi.Size() + int64(u.cfg.PartSize) - 1) / int64(u.cfg.PartSize))
+	buf := make([]byte, u.cfg.PartSize)
+	for n := 1; n <= parts; n++ {
+		if state.ETags[n] != "" {
+			continue
+		}
+		size, err := f.ReadAt(buf, int64(n-1)*int64(u.cfg.PartSize))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		q := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {state.UploadID}}
+		etag, err := u.putPart(ctx, job.key, q, buf[:size])
+		if err != nil {
+			var se *objectStoreError
+			if errors.As(err, &se) && se.Code == "NoSuchUpload" {
+				// the store expired the upload, start over next attempt
+				os.Remove(uploadStatePath(job.path))
+			}
+			return err
+		}
+		state.ETags[n] = etag
+		if err := saveMultipartState(job.path, state); err != nil {
+			return err
+		}
+	}
+
+	var complete bytes.Buffer
+	complete.WriteString("<CompleteMultipartUpload>")
+	for n := 1; n <= parts; n++ {
+		fmt.Fprintf(&complete, "<Part><PartNumber>%d</PartNumber><ETag>%s</ETag></Part>", n, xmlEscape(state.ETags[n]))
+	}
+	complete.WriteString("</CompleteMultipartUpload>")
+	resp, err := u.do(ctx, http.MethodPost, job.key, url.Values{"uploadId": {state.UploadID}}, complete.Bytes())
+	if err != nil {
+		return err
+	}
+	// S3 can report a failed completion inside a 200 response
+	if bytes.Contains(resp, []byte("<Error>")) {
+		return parseObjectStoreError(http.StatusOK, resp)
+	}
+	os.Remove(uploadStatePath(job.path))
+	return nil
+}
+
+func (u *ObjectUploader) putPart(ctx context.Context, key string, q url.Values, data []byte) (string, error) {
+	req, err := u.newRequest(ctx, http.MethodPut, key, q, data)
+	if err != nil {
+		return "", err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return "", parseObjectStoreError(resp.StatusCode, body)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+func saveMultipartState(path string, state *multipartState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(uploadStatePath(path), b, 0o600)
+}
+
+// do sends a signed request and returns the response body of a 2xx reply.
+func (u *ObjectUploader) do(ctx context.Context, method, key string, q url.Values, body []byte) ([]byte, error) {
+	req, err := u.newRequest(ctx, method, key, q, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, parseObjectStoreError(resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+func (u *ObjectUploader) newRequest(ctx context.Context, method, key string, q url.Values, body []byte) (*http.Request, error) {
+	target := *u.base
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + key
+	target.RawPath = strings.TrimSuffix(target.EscapedPath(), "/") + "/" + awsEscapePath(key)
+	target.RawQuery = awsCanonicalQuery(q)
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	signAWSv4(req, body, u.cfg.AccessKeyID, u.cfg.SecretAccessKey, u.cfg.SessionToken, u.cfg.Region, "s3", time.Now())
+	return req, nil
+}
+
+// objectStoreError is an S3-style XML error reply.
+type objectStoreError struct {
+	Status  int    `xml:"-"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *objectStoreError) Error() string {
+	return fmt.Sprintf("object store returned %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+func parseObjectStoreError(status int, body []byte) error {
+	e := &objectStoreError{Status: status}
+	if xml.Unmarshal(body, e) != nil || e.Code == "" {
+		e.Code, e.Message = http.StatusText(status), strings.TrimSpace(string(body))
+	}
+	switch status {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		if e.Code != "NoSuchUpload" && e.Code != "RequestTimeout" {
+			return &permanentUploadError{e}
+		}
+	}
+	return e
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// signAWSv4 signs req with AWS Signature Version 4 over every header it
+// carries plus host.
+func signAWSv4(req *http.Request, body []byte, accessKey, secretKey, sessionToken, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	day := amzDate[:8]
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", k, strings.TrimSpace(headers[k]))
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := strings.Join([]string{day, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{day, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// awsEscape percent-encodes everything but the unreserved characters, as
+// SigV4 requires.
+func awsEscape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (keepSlash && c == '/') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func awsEscapePath(key string) string { return awsEscape(key, true) }
+
+func awsCanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			parts = append(parts, awsEscape(k, false)+"="+awsEscape(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Ogg page header types.
+const (
+	oggBeginOfStream = 0x02
+	oggEndOfStream   = 0x04
+)
+
+var errOggPacketTooLarge = errors.New("packet too large for a single ogg page")
+
+// oggWriter writes one logical Ogg bitstream, one packet per page.
+type oggWriter struct {
+	w      io.Writer
+	serial uint32
+	seq    uint32
+}
+
+func newOggWriter(w io.Writer, serial uint32) *oggWriter {
+	return &oggWriter{w: w, serial: serial}
+}
+
+// writePacket writes packet on its own page ending at granule.
+func (o *oggWriter) writePacket(packet []byte, granule int64, headerType byte) error {
+	segments := len(packet)/255 + 1
+	if segments > 255 {
+		return errOggPacketTooLarge
+	}
+	page := make([]byte, 27+segments, 27+segments+len(packet))
+	copy(page, "OggS")
+	page[5] = headerType
+	binary.LittleEndian.PutUint64(page[6:], uint64(granule))
+	binary.LittleEndian.PutUint32(page[14:], o.serial)
+	binary.LittleEndian.PutUint32(page[18:], o.seq)
+	page[26] = byte(segments)
+	for i := 0; i < segments-1; i++ {
+		page[27+i] = 255
+	}
+	page[27+segments-1] = byte(len(packet) % 255)
+	page = append(page, packet...)
+	binary.LittleEndian.PutUint32(page[22:], oggCRC(page))
+	o.seq++
+	_, err := o.w.Write(page)
+	return err
+}
+
+// close ends the logical bitstream with an empty end-of-stream page at the
+// granule position of the last packet.
+func (o *oggWriter) close(granule int64) error {
+	return o.writePacket(nil, granule, oggEndOfStream)
+}
+
+// writeOpusHeaders writes the OpusHead and OpusTags pages that start an Ogg
+// Opus stream (RFC 7845 section 5).
+func (o *oggWriter) writeOpusHeaders(head []byte) error {
+	if err := o.writePacket(head, 0, oggBeginOfStream); err != nil {
+		return err
+	}
+	const vendor = "audioapi"
+	tags := make([]byte, 0, 16+len(vendor))
+	tags = append(tags, "OpusTags"...)
+	tags = binary.LittleEndian.AppendUint32(tags, uint32(len(vendor)))
+	tags = append(tags, vendor...)
+	tags = binary.LittleEndian.AppendUint32(tags, 0) // no user comments
+	return o.writePacket(tags, 0, 0)
+}
+
+// oggCRC is the page checksum: the MPEG CRC-32 polynomial with a zero
+// initial value.
+func oggCRC(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = crc<<8 ^ crc32MPEGTable[byte(crc>>24)^b]
+	}
+	return crc
+}
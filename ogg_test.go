@@ -0,0 +1,190 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+type oggPage struct {
+	headerType byte
+	granule    int64
+	serial     uint32
+	seq        uint32
+	lacing     []byte
+	packet     []byte
+}
+
+// parseOggPages splits b into pages, checking each page's capture pattern,
+// version and CRC.
+func parseOggPages(t *testing.T, b []byte) []oggPage {
+	t.Helper()
+	var pages []oggPage
+	for len(b) > 0 {
+		if len(b) < 27 || string(b[:4]) != "OggS" || b[4] != 0 {
+			t.Fatalf("page %d has a bad header % x", len(pages), b[:min(27, len(b))])
+		}
+		segments := int(b[26])
+		size := 27 + segments
+		for _, l := range b[27 : 27+segments] {
+			size += int(l)
+		}
+		page := append([]byte(nil), b[:size]...)
+		crc := binary.LittleEndian.Uint32(page[22:])
+		binary.LittleEndian.PutUint32(page[22:], 0)
+		if got := oggCRC(page); got != crc {
+			t.Fatalf("page %d has CRC %#08x, computed %#08x", len(pages), crc, got)
+		}
+		pages = append(pages, oggPage{
+			headerType: b[5],
+			granule:    int64(binary.LittleEndian.Uint64(b[6:])),
+			serial:     binary.LittleEndian.Uint32(b[14:]),
+			seq:        binary.LittleEndian.Uint32(b[18:]),
+			lacing:     b[27 : 27+segments],
+			packet:     b[27+segments : size],
+		})
+		b = b[size:]
+	}
+	return pages
+}
+
+func TestOggCRC(t *testing.T) {
+	// CRC-32 with polynomial 0x04c11db7, zero initial value and no final
+	// xor, the check value of CRC-32/POSIX without its inversion
+	if got := oggCRC([]byte("123456789")); got != 0x89A1897F {
+		t.Fatalf("got %#08x, want 0x89a1897f", got)
+	}
+}
+
+func TestOggWriter(t *testing.T) {
+	var buf bytes.Buffer
+	o := newOggWriter(&buf, 0xDEADBEEF)
+	head := opusHead(48000, 2)
+	if err := o.writeOpusHeaders(head); err != nil {
+		t.Fatal(err)
+	}
+	small := []byte{0xFC, 1, 2, 3}
+	exact := bytes.Repeat([]byte{0xFC}, 510)
+	if err := o.writePacket(small, 960, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.writePacket(exact, 1920, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.close(1920); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.writePacket(make([]byte, 255*255), 0, 0); !errors.Is(err, errOggPacketTooLarge) {
+		t.Fatalf("got %v for an oversized packet, want errOggPacketTooLarge", err)
+	}
+
+	pages := parseOggPages(t, buf.Bytes())
+	want := []struct {
+		headerType byte
+		granule    int64
+		lacing     []byte
+	}{
+		{oggBeginOfStream, 0, []byte{19}},
+		{0, 0, []byte{byte(16 + len("audioapi"))}},
+		{0, 960, []byte{4}},
+		// a packet that is a multiple of 255 ends with a zero lacing value
+		{0, 1920, []byte{255, 255, 0}},
+		{oggEndOfStream, 1920, []byte{0}},
+	}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages, want %d", len(pages), len(want))
+	}
+	for i, w := range want {
+		p := pages[i]
+		if p.serial != 0xDEADBEEF || p.seq != uint32(i) {
+			t.Fatalf("page %d has serial %#x and sequence %d", i, p.serial, p.seq)
+		}
+		if p.headerType != w.headerType || p.granule != w.granule || !bytes.Equal(p.lacing, w.lacing) {
+			t.Fatalf("page %d: type %#x granule %d lacing %v, want %+v", i, p.headerType, p.granule, p.lacing, w)
+		}
+	}
+	if !bytes.Equal(pages[0].packet, head) || !bytes.HasPrefix(pages[1].packet, []byte("OpusTags")) {
+		t.Fatal("stream does not start with OpusHead and OpusTags")
+	}
+	if !bytes.Equal(pages[3].packet, exact) {
+		t.Fatal("multi-segment packet was not written whole")
+	}
+}
+
+func TestStreamOggOpus(t *testing.T) {
+	packet := []byte{1 << 3, 0xAA} // one 20ms SILK frame
+	chunks := make(chan *AudioChunk, 2)
+	chunks <- &AudioChunk{AudioData: packet}
+	chunks <- &AudioChunk{AudioData: packet}
+	close(chunks)
+
+	var buf bytes.Buffer
+	first := &AudioChunk{AudioData: packet, Header: &StreamHeader{Codec: "opus", Extradata: opusHead(16000, 1)}}
+	if err := streamOggOpus(context.Background(), &buf, first, chunks); err != nil {
+		t.Fatal(err)
+	}
+	pages := parseOggPages(t, buf.Bytes())
+	if len(pages) != 6 {
+		t.Fatalf("got %d pages, want headers, three packets and end of stream", len(pages))
+	}
+	if !bytes.Equal(pages[0].packet, opusHead(16000, 1)) {
+		t.Fatal("the resource's OpusHead was not used")
+	}
+	for i, granule := range []int64{960, 1920, 2880} {
+		if pages[2+i].granule != granule {
+			t.Fatalf("packet %d ends at granule %d, want %d", i, pages[2+i].granule, granule)
+		}
+	}
+	if last := pages[5]; last.headerType != oggEndOfStream || last.granule != 2880 {
+		t.Fatalf("last page has type %#x and granule %d, want end of stream at 2880", last.headerType, last.granule)
+	}
+}
+
+func TestFirstOpusChunk(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		chunk *AudioChunk
+		ok    bool
+	}{
+		{"opus packet", &AudioChunk{AudioData: []byte{8}}, true},
+		{"opus with header", &AudioChunk{AudioData: []byte{8}, Header: &StreamHeader{Codec: "opus"}}, true},
+		{"raw pcm", &AudioChunk{AudioData: make([]byte, 4), Info: &AudioInfo{Format: Pcm16, SampleRate: 48000, Channels: 1}}, false},
+		{"mp3", &AudioChunk{AudioData: []byte{0xFF, 0xFB}, Header: &StreamHeader{Codec: "mp3"}}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			chunks := make(chan *AudioChunk, 1)
+			chunks <- tc.chunk
+			_, err := firstOpusChunk(context.Background(), chunks)
+			if tc.ok && err != nil {
+				t.Fatal(err)
+			}
+			if !tc.ok && !errors.Is(err, errIcecastNeedsOpus) {
+				t.Fatalf("got %v, want errIcecastNeedsOpus", err)
+			}
+		})
+	}
+}
+
+func TestIcecastConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		cfg   IcecastConfig
+		codec string
+		ok    bool
+	}{
+		{IcecastConfig{URL: "http://icecast.local:8000/robot.mp3"}, IcecastCodecMP3, true},
+		{IcecastConfig{URL: "http://icecast.local:8000/robot.opus", Codec: IcecastCodecOpus}, IcecastCodecOpus, true},
+		{IcecastConfig{URL: "https://icecast.local/robot.mp3"}, "", false},
+		{IcecastConfig{URL: "http://icecast.local/robot.aac", Codec: "aac"}, "", false},
+	} {
+		cfg := tc.cfg
+		_, err := cfg.validate()
+		if (err == nil) != tc.ok {
+			t.Errorf("%+v: got error %v, want ok %v", tc.cfg, err, tc.ok)
+		}
+		if tc.ok && cfg.Codec != tc.codec {
+			t.Errorf("%+v: codec %q, want %q", tc.cfg, cfg.Codec, tc.codec)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package audio
+
+import "time"
+
+// GetAudioOptions holds the optional GetAudio parameters. Implementations
+// collect them with NewGetAudioOptions.
+type GetAudioOptions struct {
+	// SampleRate requested for the stream, 0 for the source rate.
+	SampleRate int
+	// Channels requested for the stream, 0 for the source channel count.
+	Channels int
+	// RequestID names the stream so it can be paused and resumed through
+	// StreamController while it runs.
+	RequestID string
+	// OnlyWhen limits the stream to audio captured while one of these
+	// conditions holds (see DetectorNames), plus PreRoll before and PostRoll
+	// after each match. Zero rolls use the server defaults.
+	OnlyWhen []string
+	PreRoll  time.Duration
+	PostRoll time.Duration
+	// VAD names the engine (see VADEngines) that sets AudioChunk.Speech on
+	// every chunk, empty for none.
+	VAD string
+	// SpeechOnly delivers only chunks the VAD found speech in.
+	SpeechOnly bool
+	// TrimSilence drops chunks once the level has stayed below
+	// SilenceThresholdDBFS for SilenceHangover. Zero values use the server
+	// defaults. Audio dropped by SpeechOnly or TrimSilence is reported as
+	// Gap and counts against the requested duration.
+	TrimSilence          bool
+	SilenceThresholdDBFS float64
+	SilenceHangover      time.Duration
+	// NoiseSuppression names the engine (see NoiseSuppressors) that removes
+	// background noise before any other processing, empty for none.
+	NoiseSuppression string
+	// AGC raises or lowers the level toward AGCTargetDBFS, zero for the
+	// server default.
+	AGC           bool
+	AGCTargetDBFS float64
+	// AlsoSaveAs names a recording the server saves the delivered stream
+	// to, empty for none.
+	AlsoSaveAs string
+	// Strict fails the stream rather than converting audio the source
+	// doesn't capture in exactly the requested format.
+	Strict bool
+	// Resumable has the server keep the capture for a while after the
+	// client drops, so the stream can be resumed from ResumeToken, the
+	// AudioChunk.ResumeToken of the last chunk received.
+	Resumable   bool
+	ResumeToken string
+	// ChunkDuration regroups the stream into chunks this long, zero for
+	// chunks as the source captures them.
+	ChunkDuration time.Duration
+	// DropPolicy is what the server does once the client is BufferChunks
+	// chunks behind the capture: DropOldest (the default), DropNewest or
+	// BlockWhenFull. Zero BufferChunks uses the server default of 50.
+	DropPolicy   string
+	BufferChunks int
+	// Compression names the wire compression of the stream's messages,
+	// GzipCompression or SnappyCompression, empty for none.
+	Compression string
+	// BatchChunks sends up to this many chunks in one message, each waiting
+	// at most BatchDelay for the rest, zero for the server default of one
+	// second. Zero or one BatchChunks sends every chunk on its own.
+	BatchChunks int
+	BatchDelay  time.Duration
+	// AdaptiveBitrate has the server encode mp3 at a bitrate between
+	// MinBitrateKbps and MaxBitrateKbps that keeps up with the client, 32
+	// and 128 if zero.
+	AdaptiveBitrate bool
+	MinBitrateKbps  int
+	MaxBitrateKbps  int
+	// EndOfStream closes a stream the server ended cleanly with an empty
+	// chunk marked End.
+	EndOfStream bool
+	// Reconnect has the client re-open the stream when it fails with one
+	// of its codes, see WithReconnect.
+	Reconnect *RetryPolicy
+	// Heartbeat has the server send a heartbeat whenever the stream has
+	// sent nothing for this long, zero for none. See WithHeartbeat.
+	Heartbeat time.Duration
+	// Checksums has the server send the CRC-32 of each chunk's audio for
+	// the client to verify, see WithChecksums.
+	Checksums bool
+}
+
+// GetAudioOption configures a GetAudio call.
+type GetAudioOption func(*GetAudioOptions)
+
+// NewGetAudioOptions applies opts to a zero GetAudioOptions.
+func NewGetAudioOptions(opts ...GetAudioOption) GetAudioOptions {
+	var o GetAudioOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithSampleRate requests audio resampled to rate.
+func WithSampleRate(rate int) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.SampleRate = rate
+	}
+}
+
+// WithChannels requests audio mixed to n channels.
+func WithChannels(n int) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.Channels = n
+	}
+}
+
+// WithRequestID names the stream so it can be controlled with StreamController.
+func WithRequestID(id string) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.RequestID = id
+	}
+}
+
+// WithOnlyWhen delivers audio only while one of conditions holds, such as
+// "speech" or "alarm". Skipped stretches are reported in AudioChunk.Gap.
+func WithOnlyWhen(conditions ...string) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.OnlyWhen = conditions
+	}
+}
+
+// WithRoll sets how much audio WithOnlyWhen keeps before and after a match.
+func WithRoll(pre, post time.Duration) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.PreRoll = pre
+		o.PostRoll = post
+	}
+}
+
+// WithVAD annotates every chunk with whether it contains speech, detected by
+// the named engine: "energy", or "webrtc" in builds with the webrtcvad tag.
+func WithVAD(engine string) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.VAD = engine
+	}
+}
+
+// WithSpeechOnly delivers only chunks containing speech, with the engine
+// from WithVAD or "energy". Skipped stretches are reported in AudioChunk.Gap.
+func WithSpeechOnly() GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.SpeechOnly = true
+	}
+}
+
+// WithSilenceTrim drops silent stretches longer than hangover, where silence
+// is audio below thresholdDBFS. Zero arguments use the server defaults of
+// -50 dBFS and 300ms. Skipped stretches are reported in AudioChunk.Gap.
+func WithSilenceTrim(thresholdDBFS float64, hangover time.Duration) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.TrimSilence = true
+		o.SilenceThresholdDBFS = thresholdDBFS
+		o.SilenceHangover = hangover
+	}
+}
+
+// WithNoiseSuppression removes steady background noise with the named
+// engine: "spectral", or "rnnoise" in builds with the rnnoise tag.
+func WithNoiseSuppression(engine string) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.NoiseSuppression = engine
+	}
+}
+
+// WithAGC applies automatic gain control, holding speech near targetDBFS
+// whatever the talker's distance from the microphone. Zero uses the server
+// default of -20 dBFS.
+func WithAGC(targetDBFS float64) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.AGC = true
+		o.AGCTargetDBFS = targetDBFS
+	}
+}
+
+// WithAlsoSaveAs has the server save the stream exactly as it is delivered,
+// in the same codec and with the same gaps, as the named recording in its
+// recording store, so what a live consumer received can be checked later.
+func WithAlsoSaveAs(name string) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.AlsoSaveAs = name
+	}
+}
+
+// WithStrict delivers audio only as the source captures it, failing the
+// stream with a FailedPrecondition error naming what differs if that isn't
+// exactly the requested codec and the WithSampleRate and WithChannels
+// values, instead of converting it. Over the API the codec must be given.
+func WithStrict() GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.Strict = true
+	}
+}
+
+// WithResumable has the server keep capturing for 30 seconds after the
+// client drops, buffering the last 30 seconds of the stream, and set
+// AudioChunk.ResumeToken on every chunk.
+func WithResumable() GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.Resumable = true
+	}
+}
+
+// WithResumeToken continues a resumable stream right after the chunk that
+// carried token, replaying what was captured since so nothing is lost. The
+// stream keeps the format and options it was started with; the other
+// arguments are ignored. A token fails with FAILED_PRECONDITION once the
+// stream has been dropped for longer than 30 seconds, or the chunk after it
+// is no longer buffered.
+func WithResumeToken(token string) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.ResumeToken = token
+	}
+}
+
+// WithChunkDuration regroups a raw PCM stream into chunks of d, from 10ms
+// for low latency up to 500ms for fewer, bigger messages. Chunks only come
+// out shorter before a gap, a format change or the end of the stream; the
+// StreamHeader reports the frames in a full chunk.
+func WithChunkDuration(d time.Duration) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.ChunkDuration = d
+	}
+}
+
+// WithDropPolicy sets what the server does once the client falls
+// bufferChunks chunks behind the capture, zero for the default of 50.
+// DropOldest and DropNewest drop chunks, reported as AudioChunk.Gap and
+// counted in AudioChunk.Dropped; BlockWhenFull waits for the client, holding
+// up the capture of every other stream sharing it.
+func WithDropPolicy(policy string, bufferChunks int) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.DropPolicy = policy
+		o.BufferChunks = bufferChunks
+	}
+}
+
+// WithCompression has the server compress the stream on the wire with
+// GzipCompression or SnappyCompression, trading CPU on both ends for
+// bandwidth whatever the codec. It suits raw PCM over metered links; codecs
+// such as opus barely shrink further.
+func WithCompression(name string) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.Compression = name
+	}
+}
+
+// WithBatching has the server send up to chunks chunks, from 2 to 100, in
+// one message, waiting at most maxDelay for a batch to fill, zero for one
+// second. It cuts the per-message overhead of short chunks on satellite
+// and cellular links at the cost of latency. The chunks come out of the
+// stream one by one with their own timestamps, as they would unbatched.
+func WithBatching(chunks int, maxDelay time.Duration) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.BatchChunks = chunks
+		o.BatchDelay = maxDelay
+	}
+}
+
+// WithAdaptiveBitrate has the server encode an mp3 stream itself from the
+// shared capture, lowering the bitrate while chunks queue up for a slow
+// link and raising it again once the link keeps up, between minKbps and
+// maxKbps, zero for 32 and 128. Each chunk reports its bitrate in
+// AudioChunk.BitrateKbps. Opus streams are passed through from the
+// resource, which picks their bitrate, so only "mp3" is accepted.
+func WithAdaptiveBitrate(minKbps, maxKbps int) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.AdaptiveBitrate = true
+		o.MinBitrateKbps = minKbps
+		o.MaxBitrateKbps = maxKbps
+	}
+}
+
+// WithEndOfStream has the client close a stream the server ended cleanly,
+// at its requested duration or when the capture ended, with an empty chunk
+// marked AudioChunk.End. Without it the channel just closes, whereas a
+// stream that failed always ends with a chunk carrying a *StreamError.
+func WithEndOfStream() GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.EndOfStream = true
+	}
+}
+
+// WithReconnect has the client re-open the stream when it fails with one
+// of p.Codes, Unavailable if empty, so the channel goes on instead of
+// ending. The stream is made resumable and picks up right after the last
+// chunk received, losing nothing; once that can no longer be resumed it is
+// started over. The first chunk after a reconnection is marked
+// AudioChunk.Reconnected, with the audio lost meanwhile, if any, in its
+// Gap. Attempts back off as p sets, and p.MaxAttempts bounds them after
+// each failure, the failed stream included, below 2 for as long as ctx
+// lasts. A stream started over counts its duration afresh.
+func WithReconnect(p RetryPolicy) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.Resumable = true
+		o.Reconnect = &p
+	}
+}
+
+// WithHeartbeat has the server send a heartbeat whenever the stream has
+// been quiet for interval, from one second to five minutes, so that NATs
+// and proxies don't drop the connection of a stream held back by
+// WithOnlyWhen, WithSpeechOnly or WithTrimSilence. Heartbeats aren't
+// delivered. A server that sends nothing, heartbeats included, for three
+// intervals is taken to be unreachable: the stream fails with Unavailable,
+// which WithReconnect retries.
+func WithHeartbeat(interval time.Duration) GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.Heartbeat = interval
+	}
+}
+
+// WithChecksums has the server send the CRC-32 of each chunk's audio and
+// the client verify it, to catch audio corrupted on the way by a proxy or
+// bad memory. A chunk that fails ends the stream with
+// ErrChecksumMismatch rather than being delivered, and one from a server
+// that doesn't send checksums with ErrUnsupported.
+func WithChecksums() GetAudioOption {
+	return func(o *GetAudioOptions) {
+		o.Checksums = true
+	}
+}
@@ -0,0 +1,159 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// bytesPerSample returns the width of one sample for the raw PCM formats.
+func bytesPerSample(format AudioFormat) (int, error) {
+	switch format {
+	case Pcm16:
+		return 2, nil
+	case Pcm32, Pcm32Float:
+		return 4, nil
+	default:
+		return 0, fmt.Errorf("%s is not a raw pcm format", format)
+	}
+}
+
+// decodePCM converts little-endian interleaved PCM to float32 samples in [-1, 1].
+func decodePCM(data []byte, format AudioFormat) ([]float32, error) {
+	return decodePCMTo(nil, data, format)
+}
+
+// decodePCMTo is decodePCM into samples when it is big enough.
+func decodePCMTo(samples []float32, data []byte, format AudioFormat) ([]float32, error) {
+	width, err := bytesPerSample(format)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(data) / width; cap(samples) >= n {
+		samples = samples[:n]
+	} else {
+		samples = make([]float32, n)
+	}
+	for i := range samples {
+		b := data[i*width:]
+		switch format {
+		case Pcm16:
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(b))) / 32768
+		case Pcm32:
+			samples[i] = float32(float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648)
+		case Pcm32Float:
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		}
+	}
+	return samples, nil
+}
+
+// encodePCM converts float32 samples to little-endian interleaved PCM,
+// clipping anything outside [-1, 1].
+func encodePCM(samples []float32, format AudioFormat) ([]byte, error) {
+	return encodePCMTo(nil, samples, format)
+}
+
+// encodePCMTo is encodePCM into data when it is big enough.
+func encodePCMTo(data []byte, samples []float32, format AudioFormat) ([]byte, error) {
+	width, err := bytesPerSample(format)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(samples) * width; cap(data) >= n {
+		data = data[:n]
+	} else {
+		data = make([]byte, n)
+	}
+	for i, s := range samples {
+		b := data[i*width:]
+		switch format {
+		case Pcm16:
+			binary.LittleEndian.PutUint16(b, uint16(int16(clip(s)*32767)))
+		case Pcm32:
+			binary.LittleEndian.PutUint32(b, uint32(int32(float64(clip(s))*2147483647)))
+		case Pcm32Float:
+			binary.LittleEndian.PutUint32(b, math.Float32bits(s))
+		}
+	}
+	return data, nil
+}
+
+func clip(s float32) float32 {
+	if s > 1 {
+		return 1
+	}
+	if s < -1 {
+		return -1
+	}
+	return s
+}
+
+// remix converts interleaved samples between channel counts. Downmixing
+// averages the source channels, upmixing copies the mono mix to every output
+// channel.
+func remix(samples []float32, from, to int) []float32 {
+	if from == to || from <= 0 || to <= 0 {
+		return samples
+	}
+	frames := len(samples) / from
+	out := make([]float32, frames*to)
+	for f := 0; f < frames; f++ {
+		var sum float32
+		for c := 0; c < from; c++ {
+			sum += samples[f*from+c]
+		}
+		mix := sum / float32(from)
+		for c := 0; c < to; c++ {
+			out[f*to+c] = mix
+		}
+	}
+	return out
+}
+
+// resampler does streaming linear-interpolation sample rate conversion. It
+// keeps the last input frame and the fractional read position between calls
+// so consecutive chunks join without clicks.
+type resampler struct {
+	channels int
+	step     float64 // input frames advanced per output frame
+	pos      float64 // read position relative to the start of the next input
+	last     []float32
+}
+
+func newResampler(fromRate, toRate, channels int) *resampler {
+	return &resampler{
+		channels: channels,
+		step:     float64(fromRate) / float64(toRate),
+		last:     make([]float32, channels),
+	}
+}
+
+func (r *resampler) process(in []float32) []float32 {
+	ch := r.channels
+	frames := len(in) / ch
+	if frames == 0 {
+		return nil
+	}
+	// frame -1 is the last frame of the previous call
+	frame := func(i, c int) float32 {
+		if i < 0 {
+			return r.last[c]
+		}
+		return in[i*ch+c]
+	}
+
+	out := make([]float32, 0, int(float64(frames)/r.step+1)*ch)
+	for r.pos < float64(frames-1) {
+		i := int(math.Floor(r.pos))
+		frac := float32(r.pos - float64(i))
+		for c := 0; c < ch; c++ {
+			a, b := frame(i, c), frame(i+1, c)
+			out = append(out, a+(b-a)*frac)
+		}
+		r.pos += r.step
+	}
+	r.pos -= float64(frames)
+	copy(r.last, in[(frames-1)*ch:])
+	return out
+}
@@ -0,0 +1,679 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// Defaults of the models on pcm streams.
+const (
+	defaultPCMSampleRate       = 48000
+	defaultPCMChannels         = 1
+	defaultPCMPlaybackChannels = 2
+	// noPCMDevice turns a direction off
+	noPCMDevice = "none"
+	// recoveries in a row a stream tries before it gives up
+	maxPCMRecoveries = 5
+	// how a stream on a lost sound server is redialed
+	redialWait       = 100 * time.Millisecond // doubling up to maxRedialWait
+	maxRedialWait    = 2 * time.Second
+	reconnectTimeout = 10 * time.Second
+)
+
+// pcmParams is the format and buffering a pcm is opened with.
+type pcmParams struct {
+	rate, channels, periodFrames, bufferFrames int
+}
+
+// pcmDevice is an open capture or playback stream of a sound server or
+// driver, transferring interleaved 16-bit frames.
+type pcmDevice interface {
+	// read blocks until buf is full or an error, and returns the frames read.
+	read(buf []int16) (int, error)
+	// write blocks until buf is queued or an error, and returns the frames
+	// written.
+	write(buf []int16) (int, error)
+	// recover resumes after an error from read or write, an xrun or a lost
+	// connection. It fails for errors that can't be recovered from.
+	recover(err error) error
+	// drain waits for queued playback to finish and readies the pcm for more.
+	drain() error
+	close() error
+}
+
+// pcmBlock is one period of capture.
+type pcmBlock struct {
+	samples []float32
+	at      time.Time
+	gap     time.Duration // capture lost just before this block
+	err     error
+}
+
+// pcmAudio is a resource on pcm streams opened by a backend. It captures
+// while anything is reading and keeps the playback stream open from the
+// first Play until it is closed.
+type pcmAudio struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	backend string // for messages
+	open    func(name string, capture bool, p pcmParams) (pcmDevice, error)
+
+	capture, playback string // device names, empty when off
+	// listDevices, if set, lists the backend's devices in place of ALSA's
+	listDevices    func() (deviceList, error)
+	captureParams  pcmParams
+	playbackParams pcmParams
+	logger         logging.Logger
+
+	mu        sync.Mutex
+	readers   map[chan pcmBlock]struct{}
+	capturing chan struct{} // closed when the capture loop exits, nil while stopped
+	closed    bool
+	reopen    bool // the capture loop is to open the device again, see ResetDevice
+
+	playMu             sync.Mutex
+	out                pcmDevice // nil until the first Play
+	recoveringPlayback bool      // reopenPlayback is opening it again
+
+	// capture and playback errors recovered from, see Xruns
+	overruns, underruns atomic.Int64
+
+	captureHealth, playbackHealth deviceHealth
+	// reopenWait is the wait before a lost device's first reopen,
+	// deviceReopenWait if zero
+	reopenWait time.Duration
+}
+
+// GetAudio streams the capture device in the requested raw pcm format. A
+// positive durationSeconds ends the stream after that much audio.
+func (a *pcmAudio) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if a.capture == "" {
+		return nil, errorf(ErrNoDevice, "%s has no capture device", a.backend)
+	}
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, err
+	}
+	o := NewGetAudioOptions(opts...)
+	conv := transcoderFor(format, o)
+
+	in := make(chan pcmBlock, loopbackReaderBuffer)
+	if err := a.addReader(in); err != nil {
+		return nil, err
+	}
+	p := a.captureParams
+	remaining := -1
+	if durationSeconds > 0 {
+		remaining = int(float64(durationSeconds) * float64(p.rate))
+	}
+	out := make(chan *AudioChunk)
+	go func() {
+		defer close(out)
+		defer a.removeReader(in)
+		var seq int64
+		for remaining != 0 {
+			var block pcmBlock
+			select {
+			case <-ctx.Done():
+				return
+			case b, ok := <-in:
+				if !ok {
+					return
+				}
+				block = b
+			}
+			chunk := &AudioChunk{Err: block.err}
+			if block.err == nil {
+				samples := block.samples
+				if frames := len(samples) / p.channels; remaining > 0 && frames > remaining {
+					samples = samples[:remaining*p.channels]
+				}
+				if remaining > 0 {
+					remaining -= len(samples) / p.channels
+				}
+				info := AudioInfo{Format: Pcm32Float, SampleRate: p.rate, Channels: p.channels}
+				if chunk, err = conv.convertSamples(samples, &AudioChunk{Sequence: seq, Info: &info, Timestamp: block.at, Gap: block.gap}); err != nil {
+					chunk = &AudioChunk{Err: err}
+				}
+				seq++
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+			if chunk.Err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// addReader subscribes in to capture, opening the device if nothing was
+// capturing.
+func (a *pcmAudio) addReader(in chan pcmBlock) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return ErrClosed
+	}
+	if a.capturing == nil {
+		dev, err := a.open(a.capture, true, a.captureParams)
+		if err != nil {
+			err = deviceError(err, "cannot open %s for capture", a.capture)
+			a.setHealth(true, DeviceUnavailable, err.Error())
+			return err
+		}
+		a.setHealth(true, DeviceHealthy, "")
+		a.capturing = make(chan struct{})
+		go a.captureLoop(dev, a.capturing)
+	}
+	a.readers[in] = struct{}{}
+	return nil
+}
+
+func (a *pcmAudio) removeReader(in chan pcmBlock) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.readers, in)
+}
+
+// captureLoop reads periods and hands them to the readers until none are
+// left, then closes the device so it is free as soon as nothing captures.
+// Overruns are recovered from, and the lost time is reported as a gap. A
+// device that can't be recovered, unplugged or in an xrun storm, is opened
+// again with backoff while the streams wait, and the outage is reported as
+// a gap too; the streams end only once the reopens are spent.
+func (a *pcmAudio) captureLoop(dev pcmDevice, done chan struct{}) {
+	p := a.captureParams
+	buf := make([]int16, p.periodFrames*p.channels)
+	period := time.Duration(p.periodFrames) * time.Second / time.Duration(p.rate)
+	var (
+		next       time.Time // capture time of the frame after the last period
+		failures   int
+		reopens    int // since the last period read
+		pendingGap bool
+	)
+	// stopLocked ends every stream, with err if capture failed, and closes
+	// the device, if it's open, before anything can open it again.
+	stopLocked := func(err error) {
+		for r := range a.readers {
+			if err != nil {
+				select {
+				case r <- pcmBlock{err: err}:
+				default:
+				}
+			}
+			close(r)
+			delete(a.readers, r)
+		}
+		if dev != nil {
+			if cerr := dev.close(); cerr != nil {
+				a.logger.Debugw("cannot close capture", "backend", a.backend, "device", a.capture, "error", cerr)
+			}
+		}
+		a.capturing = nil
+		close(done)
+	}
+	// reopenDevice closes the device, lost to err, and opens it again after
+	// a backoff, reporting whether capture carries on. It stops capture once
+	// the reopens are spent or nothing is left reading.
+	reopenDevice := func(err error) bool {
+		if reopens == 0 {
+			a.setHealth(true, DeviceRecovering, err.Error())
+		}
+		if dev != nil {
+			if cerr := dev.close(); cerr != nil {
+				a.logger.Debugw("cannot close capture", "backend", a.backend, "device", a.capture, "error", cerr)
+			}
+			dev = nil
+		}
+		for ; reopens < maxDeviceReopens; reopens++ {
+			if !a.waitToReopen(min(a.reopenBackoff()<<reopens, maxDeviceReopenWait), true) {
+				a.setHealth(true, DeviceUnavailable, err.Error())
+				a.mu.Lock()
+				stopLocked(nil)
+				a.mu.Unlock()
+				return false
+			}
+			a.captureHealth.reopening()
+			reopened, oerr := a.open(a.capture, true, p)
+			if oerr == nil {
+				reopens++
+				dev = reopened
+				return true
+			}
+			err = deviceError(oerr, "cannot open %s for capture again", a.capture)
+			a.logger.Warnw("cannot open the capture device again", "backend", a.backend, "device", a.capture, "attempt", reopens+1, "error", oerr)
+		}
+		a.setHealth(true, DeviceUnavailable, err.Error())
+		a.mu.Lock()
+		stopLocked(err)
+		a.mu.Unlock()
+		return false
+	}
+	for {
+		a.mu.Lock()
+		if len(a.readers) == 0 || a.closed {
+			stopLocked(nil)
+			a.mu.Unlock()
+			return
+		}
+		reset := a.reopen
+		a.reopen = false
+		a.mu.Unlock()
+		if reset {
+			if cerr := dev.close(); cerr != nil {
+				a.logger.Debugw("cannot close capture", "backend", a.backend, "device", a.capture, "error", cerr)
+			}
+			var err error
+			if dev, err = a.open(a.capture, true, p); err != nil {
+				dev = nil
+				if !reopenDevice(deviceError(err, "cannot open %s for capture again", a.capture)) {
+					return
+				}
+			}
+			pendingGap = true
+		}
+
+		n, err := dev.read(buf)
+		if err != nil {
+			failures++
+			a.logger.Warnw("capture error, recovering", "backend", a.backend, "device", a.capture, "error", err)
+			if failures <= maxPCMRecoveries {
+				rerr := dev.recover(err)
+				if rerr == nil {
+					a.overruns.Add(1)
+					pendingGap = true
+					continue
+				}
+				err = fmt.Errorf("cannot recover capture on %s: %w", a.capture, rerr)
+			} else {
+				err = fmt.Errorf("capture on %s failed: %w", a.capture, err)
+			}
+			if !reopenDevice(err) {
+				return
+			}
+			failures, pendingGap = 0, true
+			continue
+		}
+		failures = 0
+		if reopens > 0 {
+			reopens = 0
+			a.setHealth(true, DeviceHealthy, "")
+		}
+		// the period just read ended now
+		at := time.Now().Add(-time.Duration(n) * time.Second / time.Duration(p.rate))
+		var gap time.Duration
+		if pendingGap && !next.IsZero() && at.After(next) {
+			gap = at.Sub(next)
+		} else if !next.IsZero() && at.Sub(next).Abs() < period {
+			// keep timestamps continuous against scheduling jitter
+			at = next
+		}
+		pendingGap = false
+		next = at.Add(time.Duration(n) * time.Second / time.Duration(p.rate))
+
+		samples := make([]float32, n*p.channels)
+		for i, s := range buf[:n*p.channels] {
+			samples[i] = float32(s) / 32768
+		}
+		a.mu.Lock()
+		for r := range a.readers {
+			select {
+			case r <- pcmBlock{samples: samples, at: at, gap: gap}:
+			default:
+				a.logger.Debugw("reader fell behind, dropping a period", "name", a.Name())
+			}
+		}
+		a.mu.Unlock()
+	}
+}
+
+// waitToReopen waits d before a lost device is opened again, and reports
+// whether the resource is still open and, for capture, anything still
+// reads.
+func (a *pcmAudio) waitToReopen(d time.Duration, capture bool) bool {
+	for end := time.Now().Add(d); ; time.Sleep(min(deviceReopenPoll, time.Until(end))) {
+		a.mu.Lock()
+		waiting := !a.closed && (!capture || len(a.readers) > 0)
+		a.mu.Unlock()
+		if !waiting || !time.Now().Before(end) {
+			return waiting
+		}
+	}
+}
+
+func (a *pcmAudio) reopenBackoff() time.Duration {
+	if a.reopenWait > 0 {
+		return a.reopenWait
+	}
+	return deviceReopenWait
+}
+
+// setHealth moves the capture or playback device to state, for reason,
+// logging the device's loss and recovery and posting them to the server's
+// webhooks.
+func (a *pcmAudio) setHealth(capture bool, state, reason string) {
+	health, device := &a.playbackHealth, a.playback
+	if capture {
+		health, device = &a.captureHealth, a.capture
+	}
+	was := health.set(state, reason)
+	if was == state {
+		return
+	}
+	ev := WebhookEvent{Resource: a.Name().ShortName(), Timestamp: time.Now(), Device: device, Reason: reason}
+	switch {
+	case was == DeviceHealthy:
+		a.logger.Warnw("lost the device", "backend", a.backend, "device", device, "state", state, "reason", reason)
+		ev.Type = WebhookDeviceLost
+	case state == DeviceHealthy:
+		a.logger.Infow("the device is back", "backend", a.backend, "device", device)
+		ev.Type = WebhookDeviceRecovered
+	default:
+		a.logger.Warnw("the device is still lost", "backend", a.backend, "device", device, "state", state, "reason", reason)
+		return
+	}
+	ServerWebhooks.Notify(ev)
+}
+
+// Play converts raw pcm to the playback device's format and returns once it
+// has been played out. Underruns are recovered from and the clip carries on.
+func (a *pcmAudio) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	if a.playback == "" {
+		return errorf(ErrNoDevice, "%s has no playback device", a.backend)
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return fmt.Errorf("%s can only play raw pcm: %w", a.backend, err)
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	p := a.playbackParams
+	if err := checkStrictPlayback(ctx, AudioInfo{Format: Pcm16, SampleRate: p.rate, Channels: p.channels}, format, sampleRate, channels); err != nil {
+		return err
+	}
+	samples, err := decodePCM(data, format)
+	if err != nil {
+		return err
+	}
+	samples = remix(samples, channels, p.channels)
+	if sampleRate != p.rate {
+		samples = newResampler(sampleRate, p.rate, p.channels).process(samples)
+	}
+	frames := make([]int16, len(samples))
+	for i, s := range samples {
+		frames[i] = int16(clip(s) * 32767)
+	}
+
+	a.playMu.Lock()
+	defer a.playMu.Unlock()
+	if a.out == nil {
+		a.mu.Lock()
+		closed := a.closed
+		a.mu.Unlock()
+		if closed {
+			return ErrClosed
+		}
+		if a.out, err = a.open(a.playback, false, p); err != nil {
+			a.out = nil
+			err = fmt.Errorf("cannot open %s for playback: %w", a.playback, err)
+			if !a.recoveringPlayback {
+				a.setHealth(false, DeviceUnavailable, err.Error())
+			}
+			return err
+		}
+		a.setHealth(false, DeviceHealthy, "")
+	}
+	step := p.periodFrames * p.channels
+	failures := 0
+	for len(frames) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := a.out.write(frames[:min(step, len(frames))])
+		if err != nil {
+			failures++
+			a.logger.Warnw("playback error, recovering", "backend", a.backend, "device", a.playback, "error", err)
+			if failures <= maxPCMRecoveries {
+				rerr := a.out.recover(err)
+				if rerr == nil {
+					a.underruns.Add(1)
+					continue
+				}
+				err = fmt.Errorf("cannot recover playback on %s: %w", a.playback, rerr)
+			} else {
+				err = fmt.Errorf("playback on %s failed: %w", a.playback, err)
+			}
+			a.lostPlayback(err)
+			return err
+		}
+		failures = 0
+		frames = frames[n*p.channels:]
+	}
+	return a.out.drain()
+}
+
+// lostPlayback closes the playback device, lost to err, and opens it again
+// in the background so the next Play finds it ready. It's called with
+// playMu held.
+func (a *pcmAudio) lostPlayback(err error) {
+	if cerr := a.out.close(); cerr != nil {
+		a.logger.Debugw("cannot close playback", "backend", a.backend, "device", a.playback, "error", cerr)
+	}
+	a.out = nil
+	if a.recoveringPlayback {
+		return
+	}
+	a.recoveringPlayback = true
+	a.setHealth(false, DeviceRecovering, err.Error())
+	go a.reopenPlayback(err)
+}
+
+// reopenPlayback opens the lost playback device again with backoff, until
+// it opens, a Play opens it or the reopens are spent.
+func (a *pcmAudio) reopenPlayback(err error) {
+	defer func() {
+		a.playMu.Lock()
+		a.recoveringPlayback = false
+		a.playMu.Unlock()
+	}()
+	for reopens := 0; reopens < maxDeviceReopens; reopens++ {
+		if !a.waitToReopen(min(a.reopenBackoff()<<reopens, maxDeviceReopenWait), false) {
+			return
+		}
+		// Close takes playMu after marking the resource closed, so nothing
+		// opened here is left open
+		a.playMu.Lock()
+		a.mu.Lock()
+		closed := a.closed
+		a.mu.Unlock()
+		if a.out != nil || closed {
+			a.playMu.Unlock()
+			return
+		}
+		a.playbackHealth.reopening()
+		dev, oerr := a.open(a.playback, false, a.playbackParams)
+		if oerr == nil {
+			a.out = dev
+			a.setHealth(false, DeviceHealthy, "")
+			a.playMu.Unlock()
+			return
+		}
+		a.playMu.Unlock()
+		err = deviceError(oerr, "cannot open %s for playback again", a.playback)
+		a.logger.Warnw("cannot open the playback device again", "backend", a.backend, "device", a.playback, "attempt", reopens+1, "error", oerr)
+	}
+	a.setHealth(false, DeviceUnavailable, err.Error())
+}
+
+// ResetDevice closes the devices and opens them again: capture in place,
+// with the time it takes reported as a gap, and playback on the next Play.
+// It reports which it reset.
+func (a *pcmAudio) ResetDevice(ctx context.Context) (capture, playback bool, err error) {
+	a.mu.Lock()
+	if a.capturing != nil {
+		a.reopen, capture = true, true
+	}
+	a.mu.Unlock()
+	a.playMu.Lock()
+	defer a.playMu.Unlock()
+	if a.out == nil {
+		return capture, false, nil
+	}
+	err = a.out.close()
+	a.out = nil
+	return capture, true, err
+}
+
+// Health returns the health of the devices as capture and Play last saw
+// them.
+func (a *pcmAudio) Health(ctx context.Context) (Health, error) {
+	return Health{Capture: a.captureHealth.get(a.capture), Playback: a.playbackHealth.get(a.playback)}, nil
+}
+
+// Xruns returns how many times capture and playback recovered from an
+// error, an xrun or a lost connection to the sound server.
+func (a *pcmAudio) Xruns() (overruns, underruns int64) {
+	return a.overruns.Load(), a.underruns.Load()
+}
+
+// Properties returns the raw pcm formats Play takes. It converts any rate
+// and channel count to the device's.
+func (a *pcmAudio) Properties(ctx context.Context) (Properties, error) {
+	return Properties{PlaybackFormats: []AudioFormat{Pcm16, Pcm32, Pcm32Float}}, nil
+}
+
+func (a *pcmAudio) backendDevices() (deviceList, error) {
+	if a.listDevices == nil {
+		return alsaDeviceList()
+	}
+	return a.listDevices()
+}
+
+func (a *pcmAudio) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, a, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close stops capture and closes both devices, returning once they can be
+// opened again.
+func (a *pcmAudio) Close(ctx context.Context) error {
+	a.mu.Lock()
+	a.closed = true
+	capturing := a.capturing
+	a.mu.Unlock()
+	if capturing != nil {
+		select {
+		case <-capturing:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	a.playMu.Lock()
+	defer a.playMu.Unlock()
+	if a.out == nil {
+		return nil
+	}
+	err := a.out.close()
+	a.out = nil
+	return err
+}
+
+// reconnectingPCM is a stream that is dialed again when its connection is
+// lost, so a sound server restarting only costs the audio missed while it
+// was down.
+type reconnectingPCM struct {
+	pcmDevice // nil once closed
+	dial      func() (pcmDevice, error)
+	device    string
+	logger    logging.Logger
+}
+
+// dialReconnecting dials a stream that dials itself again when its
+// connection is lost.
+func dialReconnecting(dial func() (pcmDevice, error), device string, logger logging.Logger) (pcmDevice, error) {
+	dev, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	return &reconnectingPCM{pcmDevice: dev, dial: dial, device: device, logger: logger}, nil
+}
+
+func (r *reconnectingPCM) recover(err error) error {
+	if r.pcmDevice == nil {
+		return err
+	}
+	if r.pcmDevice.recover(err) == nil {
+		return nil
+	}
+	r.logger.Warnw("lost the sound server, reconnecting", "device", r.device, "error", err)
+	r.pcmDevice.close()
+	r.pcmDevice = nil
+	deadline := time.Now().Add(reconnectTimeout)
+	wait := redialWait
+	for {
+		dev, derr := r.dial()
+		if derr == nil {
+			r.logger.Infow("reconnected to the sound server", "device", r.device)
+			r.pcmDevice = dev
+			return nil
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return fmt.Errorf("cannot reconnect to the sound server: %w", derr)
+		}
+		time.Sleep(wait)
+		wait = min(2*wait, maxRedialWait)
+	}
+}
+
+func (r *reconnectingPCM) read(buf []int16) (int, error) {
+	if r.pcmDevice == nil {
+		return 0, ErrClosed
+	}
+	return r.pcmDevice.read(buf)
+}
+
+func (r *reconnectingPCM) write(buf []int16) (int, error) {
+	if r.pcmDevice == nil {
+		return 0, ErrClosed
+	}
+	return r.pcmDevice.write(buf)
+}
+
+func (r *reconnectingPCM) drain() error {
+	if r.pcmDevice == nil {
+		return ErrClosed
+	}
+	return r.pcmDevice.drain()
+}
+
+func (r *reconnectingPCM) close() error {
+	if r.pcmDevice == nil {
+		return nil
+	}
+	err := r.pcmDevice.close()
+	r.pcmDevice = nil
+	return err
+}
@@ -0,0 +1,147 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// PipeWireModel captures and plays through a PipeWire graph natively, without
+// going through its ALSA or PulseAudio emulation. Its streams are nodes the
+// session manager links like any other application's, so they follow the
+// default devices, can be pinned to a node, and carry a latency hint for the
+// graph's quantum. It needs a build with the pipewire tag and
+// libpipewire-0.3; other builds know the model but fail to construct it.
+var PipeWireModel = resource.NewModel("olivia", "audio", "pipewire")
+
+// Defaults for PipeWireConfig.
+const (
+	defaultPipeWireLatency = 20 * time.Millisecond
+	// pipewireDefaultNode is the device of a stream left to the session
+	// manager to link
+	pipewireDefaultNode = "default"
+)
+
+// PipeWireConfig is the configuration of the pipewire model.
+type PipeWireConfig struct {
+	Remote string `json:"remote,omitempty"` // the user's daemon if empty
+	// ApplicationName names the streams' nodes, which the session manager
+	// remembers routing for. "viam-<resource name>" if empty.
+	ApplicationName string `json:"application_name,omitempty"`
+	// CaptureNode and PlaybackNode pin the streams to a node by node.name or
+	// object.serial, as listed by wpctl status or pw-cli ls Node. Empty ones
+	// follow the default source and sink; "none" turns a direction off.
+	CaptureNode  string `json:"capture_node,omitempty"`
+	PlaybackNode string `json:"playback_node,omitempty"`
+	// Role is the media.role of the streams, like "Communication" or
+	// "Notification", for session manager policies such as ducking.
+	Role             string `json:"role,omitempty"`
+	SampleRate       int    `json:"sample_rate,omitempty"`       // 48 kHz if zero
+	Channels         int    `json:"channels,omitempty"`          // capture channels, 1 if zero
+	PlaybackChannels int    `json:"playback_channels,omitempty"` // 2 if zero
+	// LatencyMs is the node.latency hint, the quantum the streams ask the
+	// graph for. The graph runs at the smallest quantum any node asks for,
+	// within the limits of its configuration. 20ms if zero.
+	LatencyMs int `json:"latency_ms,omitempty"`
+}
+
+// Validate checks the pipewire configuration.
+func (c *PipeWireConfig) Validate(path string) ([]string, []string, error) {
+	if c.SampleRate < 0 || c.Channels < 0 || c.PlaybackChannels < 0 || c.LatencyMs < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate, channels, playback_channels and latency_ms cannot be negative"))
+	}
+	if c.CaptureNode == noPCMDevice && c.PlaybackNode == noPCMDevice {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("capture_node and playback_node cannot both be none"))
+	}
+	return nil, nil, nil
+}
+
+// pipewireStream is the node a pipewire stream is created as.
+type pipewireStream struct {
+	application string
+	target      string // node to link to, empty for the default
+	role        string
+	capture     bool
+}
+
+// dialPipeWire connects a stream to a PipeWire daemon, set by builds with
+// PipeWire support. An empty remote is the user's. The stream asks for a
+// quantum of p's period.
+var dialPipeWire func(remote string, s pipewireStream, p pcmParams) (pcmDevice, error)
+
+func init() {
+	registerModel(PipeWireModel, resource.Registration[Audio, *PipeWireConfig]{
+		AttributeMapConverter: migratingConverter[*PipeWireConfig](PipeWireModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*PipeWireConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewPipeWire(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+// NewPipeWire returns a resource on the configured PipeWire nodes. Streams
+// are connected when they are first used and reconnected if the daemon
+// restarts.
+func NewPipeWire(name resource.Name, cfg PipeWireConfig, logger logging.Logger) (Audio, error) {
+	if dialPipeWire == nil {
+		return nil, errors.New("this build has no PipeWire support, build with -tags pipewire and libpipewire-0.3")
+	}
+	rate := cfg.SampleRate
+	if rate == 0 {
+		rate = defaultPCMSampleRate
+	}
+	channels, playbackChannels := cfg.Channels, cfg.PlaybackChannels
+	if channels == 0 {
+		channels = defaultPCMChannels
+	}
+	if playbackChannels == 0 {
+		playbackChannels = defaultPCMPlaybackChannels
+	}
+	latency := time.Duration(cfg.LatencyMs) * time.Millisecond
+	if latency == 0 {
+		latency = defaultPipeWireLatency
+	}
+	// a quantum per period, and a few of them buffered against scheduling
+	period := max(1, int(latency.Seconds()*float64(rate)))
+	app := cfg.ApplicationName
+	if app == "" {
+		app = "viam-" + name.ShortName()
+	}
+
+	a := &pcmAudio{
+		Named:   name.AsNamed(),
+		backend: "pipewire",
+		open: func(device string, capture bool, p pcmParams) (pcmDevice, error) {
+			s := pipewireStream{application: app, role: cfg.Role, capture: capture}
+			if device != pipewireDefaultNode {
+				s.target = device
+			}
+			return dialReconnecting(func() (pcmDevice, error) { return dialPipeWire(cfg.Remote, s, p) }, device, logger)
+		},
+		capture:        pipewireNode(cfg.CaptureNode),
+		playback:       pipewireNode(cfg.PlaybackNode),
+		captureParams:  pcmParams{rate: rate, channels: channels, periodFrames: period, bufferFrames: 4 * period},
+		playbackParams: pcmParams{rate: rate, channels: playbackChannels, periodFrames: period, bufferFrames: 4 * period},
+		logger:         logger,
+		readers:        map[chan pcmBlock]struct{}{},
+	}
+	return a, nil
+}
+
+// pipewireNode returns the device for one direction, empty for none.
+func pipewireNode(configured string) string {
+	switch configured {
+	case noPCMDevice:
+		return ""
+	case "":
+		return pipewireDefaultNode
+	default:
+		return configured
+	}
+}
@@ -0,0 +1,323 @@
+//go:build pipewire
+
+package audio
+
+/*
+#cgo pkg-config: libpipewire-0.3
+#include <errno.h>
+#include <stdlib.h>
+#include <string.h>
+#include <pipewire/pipewire.h>
+#include <spa/param/audio/format-utils.h>
+
+// vpw is a stream with a ring buffer between its process callback and
+// blocking reads and writes. Everything in it is guarded by the thread
+// loop's lock, which the loop holds while it calls back.
+typedef struct {
+	struct pw_thread_loop *loop;
+	struct pw_stream *stream;
+	enum pw_stream_state state;
+	uint8_t *ring;
+	size_t size, head, fill, frame;
+	int capture;
+	int overrun;
+	int failed;
+} vpw;
+
+static void ring_put(vpw *v, const uint8_t *p, size_t n) {
+	size_t tail = (v->head + v->fill) % v->size;
+	size_t first = n < v->size - tail ? n : v->size - tail;
+	memcpy(v->ring + tail, p, first);
+	memcpy(v->ring, p + first, n - first);
+	v->fill += n;
+}
+
+static void ring_get(vpw *v, uint8_t *p, size_t n) {
+	size_t first = n < v->size - v->head ? n : v->size - v->head;
+	memcpy(p, v->ring + v->head, first);
+	memcpy(p + first, v->ring, n - first);
+	v->head = (v->head + n) % v->size;
+	v->fill -= n;
+}
+
+static void on_state_changed(void *data, enum pw_stream_state old, enum pw_stream_state state, const char *error) {
+	vpw *v = data;
+	v->state = state;
+	if (state == PW_STREAM_STATE_ERROR || (state == PW_STREAM_STATE_UNCONNECTED && old != PW_STREAM_STATE_UNCONNECTED)) {
+		v->failed = 1;
+	}
+	pw_thread_loop_signal(v->loop, false);
+}
+
+static void on_process(void *data) {
+	vpw *v = data;
+	struct pw_buffer *b = pw_stream_dequeue_buffer(v->stream);
+	if (b == NULL) {
+		return;
+	}
+	struct spa_data *d = &b->buffer->datas[0];
+	if (d->data != NULL) {
+		if (v->capture) {
+			uint32_t off = SPA_MIN(d->chunk->offset, d->maxsize);
+			size_t n = SPA_MIN(d->chunk->size, d->maxsize - off);
+			if (n > v->size - v->fill) {
+				v->overrun = 1;
+				n = v->size - v->fill;
+			}
+			n -= n % v->frame;
+			ring_put(v, (uint8_t *)d->data + off, n);
+		} else {
+			size_t n = d->maxsize;
+			if (b->requested > 0) {
+				n = SPA_MIN(n, b->requested * v->frame);
+			}
+			n -= n % v->frame;
+			// the graph keeps running between clips, so running dry is silence
+			size_t have = SPA_MIN(n, v->fill);
+			ring_get(v, d->data, have);
+			memset((uint8_t *)d->data + have, 0, n - have);
+			d->chunk->offset = 0;
+			d->chunk->stride = v->frame;
+			d->chunk->size = n;
+		}
+	}
+	pw_stream_queue_buffer(v->stream, b);
+	pw_thread_loop_signal(v->loop, false);
+}
+
+static const struct pw_stream_events vpw_events = {
+	PW_VERSION_STREAM_EVENTS,
+	.state_changed = on_state_changed,
+	.process = on_process,
+};
+
+static void vpw_free(vpw *v) {
+	if (v->loop != NULL) {
+		pw_thread_loop_stop(v->loop);
+		if (v->stream != NULL) {
+			pw_stream_destroy(v->stream);
+		}
+		pw_thread_loop_destroy(v->loop);
+	}
+	free(v->ring);
+	free(v);
+}
+
+static int vpw_open(vpw **out, const char *remote, const char *app, const char *target, const char *role,
+		int capture, int rate, int channels, int period, int buffer) {
+	vpw *v = calloc(1, sizeof(vpw));
+	if (v == NULL) {
+		return -ENOMEM;
+	}
+	v->capture = capture;
+	v->frame = 2 * channels;
+	v->size = (size_t)buffer * v->frame;
+	v->ring = malloc(v->size);
+	v->loop = pw_thread_loop_new(app, NULL);
+	if (v->ring == NULL || v->loop == NULL) {
+		vpw_free(v);
+		return -ENOMEM;
+	}
+
+	struct pw_properties *props = pw_properties_new(
+		PW_KEY_MEDIA_TYPE, "Audio",
+		PW_KEY_MEDIA_CATEGORY, capture ? "Capture" : "Playback",
+		PW_KEY_APP_NAME, app,
+		PW_KEY_NODE_NAME, app,
+		NULL);
+	pw_properties_setf(props, PW_KEY_NODE_LATENCY, "%d/%d", period, rate);
+	if (remote[0] != '\0') {
+		pw_properties_set(props, PW_KEY_REMOTE_NAME, remote);
+	}
+	if (target[0] != '\0') {
+		pw_properties_set(props, PW_KEY_TARGET_OBJECT, target);
+	}
+	if (role[0] != '\0') {
+		pw_properties_set(props, PW_KEY_MEDIA_ROLE, role);
+	}
+
+	uint8_t pod[1024];
+	struct spa_pod_builder builder = SPA_POD_BUILDER_INIT(pod, sizeof(pod));
+	struct spa_audio_info_raw info = {.format = SPA_AUDIO_FORMAT_S16_LE, .rate = rate, .channels = channels};
+	const struct spa_pod *params[1] = {spa_format_audio_raw_build(&builder, SPA_PARAM_EnumFormat, &info)};
+
+	int res = pw_thread_loop_start(v->loop);
+	if (res < 0) {
+		vpw_free(v);
+		return res;
+	}
+	pw_thread_loop_lock(v->loop);
+	v->stream = pw_stream_new_simple(pw_thread_loop_get_loop(v->loop), capture ? "capture" : "playback", props, &vpw_events, v);
+	if (v->stream == NULL) {
+		res = -errno;
+	} else {
+		res = pw_stream_connect(v->stream, capture ? PW_DIRECTION_INPUT : PW_DIRECTION_OUTPUT, PW_ID_ANY,
+			PW_STREAM_FLAG_AUTOCONNECT | PW_STREAM_FLAG_MAP_BUFFERS, params, 1);
+	}
+	// wait for the session manager to link the node
+	while (res >= 0 && !v->failed && v->state != PW_STREAM_STATE_PAUSED && v->state != PW_STREAM_STATE_STREAMING) {
+		if (pw_thread_loop_timed_wait(v->loop, 5) != 0) {
+			res = -ETIMEDOUT;
+		}
+	}
+	if (res >= 0 && v->failed) {
+		res = -ECONNREFUSED;
+	}
+	pw_thread_loop_unlock(v->loop);
+	if (res < 0) {
+		vpw_free(v);
+		return res;
+	}
+	*out = v;
+	return 0;
+}
+
+// vpw_read fills p, failing with -EPIPE if capture overran since the last
+// recovery.
+static int vpw_read(vpw *v, uint8_t *p, size_t n) {
+	int res = 0;
+	pw_thread_loop_lock(v->loop);
+	while (v->fill < n && !v->failed && !v->overrun) {
+		pw_thread_loop_wait(v->loop);
+	}
+	if (v->failed) {
+		res = -ECONNRESET;
+	} else if (v->overrun) {
+		res = -EPIPE;
+	} else {
+		ring_get(v, p, n);
+	}
+	pw_thread_loop_unlock(v->loop);
+	return res;
+}
+
+// vpw_write queues as much of p as fits once there is room, and returns
+// how much that was.
+static long vpw_write(vpw *v, const uint8_t *p, size_t n) {
+	long res;
+	pw_thread_loop_lock(v->loop);
+	while (v->fill == v->size && !v->failed) {
+		pw_thread_loop_wait(v->loop);
+	}
+	if (v->failed) {
+		res = -ECONNRESET;
+	} else {
+		size_t m = SPA_MIN(n, v->size - v->fill);
+		m -= m % v->frame;
+		ring_put(v, p, m);
+		res = m;
+	}
+	pw_thread_loop_unlock(v->loop);
+	return res;
+}
+
+static int vpw_drain(vpw *v) {
+	int res = 0;
+	pw_thread_loop_lock(v->loop);
+	while (v->fill > 0 && !v->failed) {
+		pw_thread_loop_wait(v->loop);
+	}
+	if (v->failed) {
+		res = -ECONNRESET;
+	}
+	pw_thread_loop_unlock(v->loop);
+	return res;
+}
+
+// vpw_recover drops the capture buffered before an overrun. A failed stream
+// can't be recovered.
+static int vpw_recover(vpw *v) {
+	int res = 0;
+	pw_thread_loop_lock(v->loop);
+	if (v->failed) {
+		res = -ECONNRESET;
+	} else {
+		v->overrun = 0;
+		v->head = 0;
+		v->fill = 0;
+	}
+	pw_thread_loop_unlock(v->loop);
+	return res;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+func init() {
+	dialPipeWire = dialPipeWireStream
+}
+
+var pipewireInit sync.Once
+
+// pipewireStreamDevice is a native PipeWire stream. It recovers from
+// overruns itself; reconnectingPCM dials it again when the daemon goes away.
+type pipewireStreamDevice struct {
+	v        *C.vpw
+	channels int
+}
+
+// pipewireError turns a negative errno from the stream into an error.
+func pipewireError(res C.long) error {
+	return syscall.Errno(-res)
+}
+
+func dialPipeWireStream(remote string, s pipewireStream, p pcmParams) (pcmDevice, error) {
+	pipewireInit.Do(func() { C.pw_init(nil, nil) })
+	cremote, capp, ctarget, crole := C.CString(remote), C.CString(s.application), C.CString(s.target), C.CString(s.role)
+	defer C.free(unsafe.Pointer(cremote))
+	defer C.free(unsafe.Pointer(capp))
+	defer C.free(unsafe.Pointer(ctarget))
+	defer C.free(unsafe.Pointer(crole))
+	capture := C.int(0)
+	if s.capture {
+		capture = 1
+	}
+	var v *C.vpw
+	if res := C.vpw_open(&v, cremote, capp, ctarget, crole, capture, C.int(p.rate), C.int(p.channels), C.int(p.periodFrames), C.int(p.bufferFrames)); res < 0 {
+		return nil, pipewireError(C.long(res))
+	}
+	return &pipewireStreamDevice{v: v, channels: p.channels}, nil
+}
+
+func (d *pipewireStreamDevice) read(buf []int16) (int, error) {
+	if res := C.vpw_read(d.v, (*C.uint8_t)(unsafe.Pointer(&buf[0])), C.size_t(2*len(buf))); res < 0 {
+		return 0, pipewireError(C.long(res))
+	}
+	return len(buf) / d.channels, nil
+}
+
+func (d *pipewireStreamDevice) write(buf []int16) (int, error) {
+	res := C.vpw_write(d.v, (*C.uint8_t)(unsafe.Pointer(&buf[0])), C.size_t(2*len(buf)))
+	if res < 0 {
+		return 0, pipewireError(res)
+	}
+	return int(res) / (2 * d.channels), nil
+}
+
+func (d *pipewireStreamDevice) recover(err error) error {
+	if !errors.Is(err, syscall.EPIPE) {
+		return err
+	}
+	if res := C.vpw_recover(d.v); res < 0 {
+		return pipewireError(C.long(res))
+	}
+	return nil
+}
+
+func (d *pipewireStreamDevice) drain() error {
+	if res := C.vpw_drain(d.v); res < 0 {
+		return pipewireError(C.long(res))
+	}
+	return nil
+}
+
+func (d *pipewireStreamDevice) close() error {
+	C.vpw_free(d.v)
+	return nil
+}
@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestPipeWireStreams(t *testing.T) {
+	var mu sync.Mutex
+	var dialed []pipewireStream
+	var params []pcmParams
+	prev := dialPipeWire
+	dialPipeWire = func(remote string, s pipewireStream, p pcmParams) (pcmDevice, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dialed = append(dialed, s)
+		params = append(params, p)
+		return &fakePCM{p: p, period: 10 * time.Millisecond, fail: map[int]bool{}}, nil
+	}
+	defer func() { dialPipeWire = prev }()
+
+	a, err := NewPipeWire(Named("mic"), PipeWireConfig{
+		PlaybackNode: "alsa_output.usb-speaker.analog-stereo",
+		Role:         "Communication",
+		SampleRate:   16000,
+		LatencyMs:    10,
+	}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), 0.03, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatal(c.Err)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("got %d chunks, want 3", n)
+	}
+	clip, _ := encodePCM(tone(16000, 1600, 440, 0.5), Pcm16)
+	if err := a.Play(ctx, clip, Pcm16.String(), 16000, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(dialed) != 2 {
+		t.Fatalf("dialed %d streams, want 2", len(dialed))
+	}
+	// capture follows the default source, playback is pinned
+	capture, playback := dialed[0], dialed[1]
+	if !capture.capture || capture.target != "" || playback.capture || playback.target != "alsa_output.usb-speaker.analog-stereo" {
+		t.Errorf("dialed %+v and %+v", capture, playback)
+	}
+	if capture.application != "viam-mic" || capture.role != "Communication" {
+		t.Errorf("stream is %q with role %q", capture.application, capture.role)
+	}
+	// the 10ms latency hint at 16 kHz
+	if params[0].periodFrames != 160 || params[1].channels != 2 {
+		t.Errorf("opened capture with %+v and playback with %+v", params[0], params[1])
+	}
+}
+
+func TestPipeWireConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg PipeWireConfig
+		ok  bool
+	}{
+		{PipeWireConfig{}, true},
+		{PipeWireConfig{CaptureNode: "42", PlaybackNode: "none", LatencyMs: 5}, true},
+		{PipeWireConfig{CaptureNode: "none", PlaybackNode: "none"}, false},
+		{PipeWireConfig{SampleRate: -1}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+
+	prev := dialPipeWire
+	dialPipeWire = nil
+	defer func() { dialPipeWire = prev }()
+	if _, err := NewPipeWire(Named("mic"), PipeWireConfig{}, logging.NewTestLogger(t)); err == nil {
+		t.Error("built without PipeWire support")
+	}
+}
@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// playbackBlock is how much audio a PlaybackWriter plays with each Play.
+// Longer blocks leave fewer gaps between calls but start later.
+const playbackBlock = 200 * time.Millisecond
+
+// PlaybackWriter plays the raw PCM written to it, so a decoder's output
+// can be copied straight to a speaker. It cuts the audio into blocks of
+// 200ms and plays them one after another, filling the next while the one
+// before plays, so writes are held back to the pace of playback. It is not
+// safe for concurrent use.
+type PlaybackWriter struct {
+	ctx   context.Context
+	audio Audio
+	info  AudioInfo
+	frame int // bytes per frame
+	buf   []byte
+
+	blocks chan []byte
+	done   chan struct{} // closed when the blocks have played or one failed
+	err    error         // why playing stopped, set before done is closed
+	closed bool
+}
+
+// NewPlaybackWriter returns a writer playing audio in the format info on a,
+// typically a client, until ctx is done. Only raw PCM can be written, as
+// encoded audio can't be cut at any byte.
+func NewPlaybackWriter(ctx context.Context, a Audio, info AudioInfo) (*PlaybackWriter, error) {
+	width, err := bytesPerSample(info.Format)
+	if err != nil {
+		return nil, errorf(ErrUnsupportedCodec, "%w", err)
+	}
+	if info.SampleRate <= 0 || info.Channels <= 0 {
+		return nil, errorf(ErrInvalidArgument, "cannot play %d Hz, %d channels", info.SampleRate, info.Channels)
+	}
+	frame := width * info.Channels
+	w := &PlaybackWriter{
+		ctx:    ctx,
+		audio:  a,
+		info:   info,
+		frame:  frame,
+		blocks: make(chan []byte, 1),
+		done:   make(chan struct{}),
+	}
+	w.buf = make([]byte, 0, w.blockSize())
+	go w.play()
+	return w, nil
+}
+
+func (w *PlaybackWriter) blockSize() int {
+	return max(1, int(playbackBlock*time.Duration(w.info.SampleRate)/time.Second)) * w.frame
+}
+
+func (w *PlaybackWriter) play() {
+	defer close(w.done)
+	for block := range w.blocks {
+		if err := w.audio.Play(w.ctx, block, w.info.Format.String(), w.info.SampleRate, w.info.Channels); err != nil {
+			w.err = err
+			return
+		}
+	}
+}
+
+// Write queues p to be played, blocking while a block plays and the next
+// is already waiting. It returns the error that stopped playback, if any.
+func (w *PlaybackWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	var n int
+	for len(p) > 0 {
+		k := min(len(p), cap(w.buf)-len(w.buf))
+		w.buf = append(w.buf, p[:k]...)
+		p, n = p[k:], n+k
+		if len(w.buf) == cap(w.buf) {
+			if err := w.send(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// send queues the block being filled and starts the next.
+func (w *PlaybackWriter) send() error {
+	select {
+	case w.blocks <- w.buf:
+	case <-w.done:
+		return w.err
+	}
+	w.buf = make([]byte, 0, cap(w.buf))
+	return nil
+}
+
+// Close plays what is left and returns once everything written has played,
+// or with the error that stopped it. Audio ending partway through a frame
+// fails with ErrInvalidArgument after the whole frames have played. Cancel
+// the writer's context to stop playback sooner.
+func (w *PlaybackWriter) Close() error {
+	if w.closed {
+		<-w.done
+		return w.err
+	}
+	w.closed = true
+	partial := len(w.buf) % w.frame
+	w.buf = w.buf[:len(w.buf)-partial]
+	var err error
+	if len(w.buf) > 0 {
+		err = w.send()
+	}
+	close(w.blocks)
+	<-w.done
+	if err == nil {
+		err = w.err
+	}
+	if err == nil && partial != 0 {
+		err = errorf(ErrInvalidArgument, "the audio ends %d bytes into a %d byte frame", partial, w.frame)
+	}
+	return err
+}
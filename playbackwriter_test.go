@@ -0,0 +1,66 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestPlaybackWriter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("speaker"), FakeConfig{Unpaced: true}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	c := serveAudio(t, f)
+
+	// half a second of audio plays as two whole blocks and the rest
+	clip, _ := encodePCM(tone(8000, 4000, 440, 0.5), Pcm16)
+	w, err := NewPlaybackWriter(ctx, c, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(w, bytes.NewReader(clip)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var played []byte
+	var sizes []int
+	for _, p := range f.Plays() {
+		if p.Codec != "pcm16" || p.SampleRate != 8000 || p.Channels != 1 {
+			t.Errorf("played %s, %d Hz, %d channels", p.Codec, p.SampleRate, p.Channels)
+		}
+		played = append(played, p.Data...)
+		sizes = append(sizes, len(p.Data))
+	}
+	if !bytes.Equal(played, clip) || len(sizes) != 3 || sizes[0] != 3200 || sizes[2] != 1600 {
+		t.Errorf("played %d bytes in blocks of %v, want %d", len(played), sizes, len(clip))
+	}
+	if _, err := w.Write(clip); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("wrote to a closed writer: %v", err)
+	}
+
+	// audio ending mid-frame plays its whole frames
+	f.ResetPlays()
+	w, _ = NewPlaybackWriter(ctx, c, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	w.Write(clip[:101])
+	if err := w.Close(); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("closed a partial frame with %v", err)
+	}
+	if p, ok := f.LastPlay(); !ok || len(p.Data) != 100 {
+		t.Errorf("played %+v", p)
+	}
+
+	if _, err := NewPlaybackWriter(ctx, c, AudioInfo{Format: Mp3, SampleRate: 44100, Channels: 2}); !errors.Is(err, ErrUnsupportedCodec) {
+		t.Errorf("made an mp3 writer: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PropertiesGetter is implemented by resources that know what their
+// devices play. The server checks Play and PreparePlayback calls against
+// it before they reach the device, so that a clip it can't play fails at
+// once with ErrInvalidArgument or ErrUnsupportedCodec, listing what it can.
+type PropertiesGetter interface {
+	Properties(ctx context.Context) (Properties, error)
+}
+
+// playbackProperties returns what a plays, nothing in particular if it
+// can't tell.
+func playbackProperties(ctx context.Context, a Audio) Properties {
+	if g, ok := a.(PropertiesGetter); ok {
+		if props, err := g.Properties(ctx); err == nil {
+			return props
+		}
+	}
+	return Properties{}
+}
+
+type convertPlaybackKey struct{}
+
+// ConvertPlayback returns a context under which the server converts a raw
+// pcm clip the device doesn't play to the nearest format it does, instead
+// of failing Play. Clients send it to the server with the request.
+// StrictPlayback takes precedence.
+func ConvertPlayback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, convertPlaybackKey{}, true)
+}
+
+func isConvertPlayback(ctx context.Context) bool {
+	convert, _ := ctx.Value(convertPlaybackKey{}).(bool)
+	return convert
+}
+
+// checkPlayback returns an error if a clip in codec, sampleRate and
+// channels is invalid, or isn't one props says the resource named name
+// plays. Codecs it doesn't know are left to the resource, unless props
+// lists the formats it plays.
+func checkPlayback(name string, props Properties, codec string, sampleRate, channels int) error {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		if len(props.PlaybackFormats) == 0 {
+			return nil
+		}
+		return errorf(ErrUnsupportedCodec, "%s can't play codec %q, expected one of %v", name, codec, props.PlaybackFormats)
+	}
+	if len(props.PlaybackFormats) > 0 && !slices.Contains(props.PlaybackFormats, format) {
+		return errorf(ErrUnsupportedCodec, "%s can't play %s, expected one of %v", name, format, props.PlaybackFormats)
+	}
+	// encoded clips carry their own rate and channels
+	if _, err := bytesPerSample(format); err != nil {
+		return nil
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	var differs []string
+	if len(props.PlaybackSampleRates) > 0 && !slices.Contains(props.PlaybackSampleRates, sampleRate) {
+		differs = append(differs, fmt.Sprintf("%d Hz, expected one of %v", sampleRate, props.PlaybackSampleRates))
+	}
+	if len(props.PlaybackChannels) > 0 && !slices.Contains(props.PlaybackChannels, channels) {
+		differs = append(differs, fmt.Sprintf("%d channels, expected one of %v", channels, props.PlaybackChannels))
+	}
+	if len(differs) > 0 {
+		return errorf(ErrInvalidArgument, "%s can't play %s", name, strings.Join(differs, "; "))
+	}
+	return nil
+}
+
+// convertPlayback converts a raw pcm clip to the format nearest it that
+// props says the device plays: its own format if listed, else the first raw
+// pcm one, and the closest rate and channel count. It returns false for
+// clips it can't convert.
+func convertPlayback(props Properties, data []byte, codec string, sampleRate, channels int) ([]byte, AudioInfo, bool) {
+	from, err := formatFromCodec(codec)
+	if err != nil || sampleRate <= 0 || channels <= 0 {
+		return nil, AudioInfo{}, false
+	}
+	if _, err := bytesPerSample(from); err != nil {
+		return nil, AudioInfo{}, false
+	}
+	to := AudioInfo{Format: from, SampleRate: nearest(props.PlaybackSampleRates, sampleRate), Channels: nearest(props.PlaybackChannels, channels)}
+	if len(props.PlaybackFormats) > 0 && !slices.Contains(props.PlaybackFormats, from) {
+		i := slices.IndexFunc(props.PlaybackFormats, func(f AudioFormat) bool {
+			_, err := bytesPerSample(f)
+			return err == nil
+		})
+		if i < 0 {
+			return nil, AudioInfo{}, false
+		}
+		to.Format = props.PlaybackFormats[i]
+	}
+	samples, err := decodePCM(data, from)
+	if err != nil {
+		return nil, AudioInfo{}, false
+	}
+	samples = remix(samples, channels, to.Channels)
+	if sampleRate != to.SampleRate {
+		samples = newResampler(sampleRate, to.SampleRate, to.Channels).process(samples)
+	}
+	if data, err = encodePCM(samples, to.Format); err != nil {
+		return nil, AudioInfo{}, false
+	}
+	return data, to, true
+}
+
+// nearest returns the value of values closest to v, the higher of two as
+// close, or v itself if values is empty.
+func nearest(values []int, v int) int {
+	if len(values) == 0 || slices.Contains(values, v) {
+		return v
+	}
+	distance := func(c int) int { return max(c-v, v-c) }
+	best := values[0]
+	for _, c := range values[1:] {
+		if d, bd := distance(c), distance(best); d < bd || d == bd && c > best {
+			best = c
+		}
+	}
+	return best
+}
@@ -0,0 +1,82 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestCheckPlayback(t *testing.T) {
+	props := Properties{PlaybackFormats: []AudioFormat{Pcm16, Mp3}, PlaybackSampleRates: []int{44100, 48000}, PlaybackChannels: []int{2}}
+	for _, tc := range []struct {
+		codec          string
+		rate, channels int
+		want           error
+		msg            string
+	}{
+		{"pcm16", 48000, 2, nil, ""},
+		{"mp3", 0, 0, nil, ""},
+		{"pcm16", 0, 2, ErrInvalidArgument, "0 Hz"},
+		{"pcm16", 16000, 1, ErrInvalidArgument, "16000 Hz, expected one of [44100 48000]; 1 channels, expected one of [2]"},
+		{"pcm32", 48000, 2, ErrUnsupportedCodec, "expected one of [pcm16 mp3]"},
+		{"flac", 48000, 2, ErrUnsupportedCodec, `"flac"`},
+	} {
+		err := checkPlayback("speaker", props, tc.codec, tc.rate, tc.channels)
+		if !errors.Is(err, tc.want) || tc.want != nil && !strings.Contains(err.Error(), tc.msg) {
+			t.Errorf("%s at %d Hz, %d channels: %v", tc.codec, tc.rate, tc.channels, err)
+		}
+	}
+	// without properties only invalid clips fail, and unknown codecs are the resource's call
+	if err := checkPlayback("speaker", Properties{}, "flac", 0, 0); err != nil {
+		t.Error(err)
+	}
+
+	if got := nearest([]int{8000, 16000, 48000}, 22050); got != 16000 {
+		t.Errorf("nearest to 22050 Hz is %d", got)
+	}
+	if got := nearest([]int{1, 3}, 2); got != 3 {
+		t.Errorf("nearest to 2 channels is %d", got)
+	}
+}
+
+func TestPlaybackValidation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("fake"), FakeConfig{Unpaced: true, PlaybackSampleRates: []int{48000}, PlaybackChannels: []int{2}}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	c := serveAudio(t, f)
+	clip, _ := encodePCM(tone(16000, 1600, 440, 0.5), Pcm16)
+
+	err = c.Play(ctx, clip, "pcm16", 16000, 1)
+	if !errors.Is(err, ErrInvalidArgument) || !strings.Contains(err.Error(), "expected one of [48000]") {
+		t.Errorf("an unplayable clip failed with %v", err)
+	}
+	if _, ok := f.LastPlay(); ok {
+		t.Error("an unplayable clip reached the device")
+	}
+	if err := c.Play(ctx, clip, "pcm16", 16000, 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("a clip without channels failed with %v", err)
+	}
+	if err := c.Play(StrictPlayback(ConvertPlayback(ctx)), clip, "pcm16", 16000, 1); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("a strict clip was converted: %v", err)
+	}
+
+	if err := c.Play(ConvertPlayback(ctx), clip, "pcm16", 16000, 1); err != nil {
+		t.Fatal(err)
+	}
+	play, _ := f.LastPlay()
+	if play.Codec != "pcm16" || play.SampleRate != 48000 || play.Channels != 2 {
+		t.Errorf("converted to %s at %d Hz, %d channels", play.Codec, play.SampleRate, play.Channels)
+	}
+	// a tenth of a second either way
+	if frames := len(play.Data) / 4; frames < 4790 || frames > 4810 {
+		t.Errorf("converted clip has %d frames", frames)
+	}
+}
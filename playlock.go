@@ -0,0 +1,70 @@
+package audio
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/status"
+)
+
+// playbackLocks serializes the playback the server starts on each resource:
+// concurrent Play and CommitPlayback calls play their clips one after
+// another, in about the order they came in, rather than mixed or cut into
+// each other as a resource playing them at once might. A clip committed for
+// later joins the queue when it is due. GetAudio streams and every other
+// call run alongside them and each other.
+type playbackLocks struct {
+	mu    sync.Mutex
+	locks map[string]*playbackLock // by resource name
+}
+
+type playbackLock struct {
+	slot  chan struct{} // holds a value while a clip plays
+	users int           // holding or waiting for slot
+}
+
+func newPlaybackLocks() *playbackLocks {
+	return &playbackLocks{locks: map[string]*playbackLock{}}
+}
+
+// acquire waits for the resource's previous clips to play out, or for ctx,
+// and returns the function that lets the next one play.
+func (p *playbackLocks) acquire(ctx context.Context, resource string) (func(), error) {
+	p.mu.Lock()
+	l, ok := p.locks[resource]
+	if !ok {
+		l = &playbackLock{slot: make(chan struct{}, 1)}
+		p.locks[resource] = l
+	}
+	l.users++
+	p.mu.Unlock()
+
+	select {
+	case l.slot <- struct{}{}:
+		return func() {
+			<-l.slot
+			p.leave(resource, l)
+		}, nil
+	case <-ctx.Done():
+		p.leave(resource, l)
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// users returns how many Play calls of resource are playing or waiting to.
+func (p *playbackLocks) users(resource string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if l, ok := p.locks[resource]; ok {
+		return l.users
+	}
+	return 0
+}
+
+func (p *playbackLocks) leave(resource string, l *playbackLock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if l.users--; l.users == 0 {
+		delete(p.locks, resource)
+	}
+}
@@ -0,0 +1,154 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPlaybackLocks(t *testing.T) {
+	p := newPlaybackLocks()
+	release, err := p.acquire(context.Background(), "speaker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// other resources play meanwhile
+	other, err := p.acquire(context.Background(), "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.acquire(ctx, "speaker"); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("acquired a busy resource: %v", err)
+	}
+	acquired := make(chan func())
+	go func() {
+		next, err := p.acquire(context.Background(), "speaker")
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- next
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("acquired a busy resource")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	(<-acquired)()
+	if n := len(p.locks); n != 0 {
+		t.Errorf("%d locks kept after their resources were released", n)
+	}
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	fake, err := NewFake(Named("fake"), FakeConfig{SampleRate: 8000, ChunkMs: 10}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fake.Close(context.Background())
+	c := serveAudio(t, fake)
+	clip, err := encodePCM(tone(8000, 400, 440, 0.5), Pcm16) // 50ms
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// streams, clips and the other calls all at once, for the race detector
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch, err := c.GetAudio(ctx, "pcm16", 0.2, 0, 0)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for chunk := range ch {
+				if chunk.Err != nil {
+					t.Error(chunk.Err)
+				}
+			}
+		}()
+	}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Play(ctx, clip, Pcm16.String(), 8000, 1); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				if _, err := c.(LatencyStatsGetter).GetLatencyStats(ctx); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the clips played one after another
+	plays := fake.Plays()
+	if len(plays) != 4 {
+		t.Fatalf("played %d clips, want 4", len(plays))
+	}
+	for i := 1; i < len(plays); i++ {
+		if d := plays[i].At.Sub(plays[i-1].At); d < 45*time.Millisecond {
+			t.Errorf("clip %d started %v after the one before, which plays for 50ms", i, d)
+		}
+	}
+}
+
+func TestScheduledCommitDoesNotHoldPlayback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	clock := NewManualClock(time.Unix(1000, 0))
+	fake, err := NewFake(Named("fake"), FakeConfig{SampleRate: 8000, Unpaced: true, Clock: clock}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fake.Close(context.Background())
+	c := serveAudio(t, fake)
+	clip, err := encodePCM(tone(8000, 400, 440, 0.5), Pcm16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, err := c.(PlaybackPreparer).PreparePlayback(ctx, clip, Pcm16.String(), 8000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := clock.Now().Add(time.Minute)
+	committed := make(chan error, 1)
+	go func() { committed <- c.(PlaybackPreparer).CommitPlayback(ctx, handle, at) }()
+	waitForSleeper(t, clock, at)
+
+	// other clips play while the committed one waits for its start
+	playCtx, playCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer playCancel()
+	if err := c.Play(playCtx, clip, Pcm16.String(), 8000, 1); err != nil {
+		t.Fatalf("a clip waited for one committed a minute ahead: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if err := <-committed; err != nil {
+		t.Fatal(err)
+	}
+	if plays := fake.Plays(); len(plays) != 2 || !plays[1].At.Equal(at) {
+		t.Errorf("played %+v", plays)
+	}
+}
@@ -0,0 +1,105 @@
+package audio
+
+import (
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/mem"
+	"google.golang.org/protobuf/encoding/protowire"
+	protov2 "google.golang.org/protobuf/proto"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// chunkBuffers holds the AudioData of released chunks for the next chunks
+// to borrow. A stream's chunks are all about the same size, so after the
+// first few a continuous stream stops allocating audio buffers at all.
+var chunkBuffers sync.Pool
+
+// borrowBuffer returns n bytes from a released chunk if one is big enough.
+func borrowBuffer(n int) []byte {
+	if b, ok := chunkBuffers.Get().(*[]byte); ok && cap(*b) >= n {
+		return (*b)[:n]
+	}
+	return make([]byte, n)
+}
+
+// returnBuffer hands b to the next borrowBuffer; nothing may use it after.
+func returnBuffer(b []byte) {
+	if cap(b) == 0 {
+		return
+	}
+	b = b[:0]
+	chunkBuffers.Put(&b)
+}
+
+// borrowPCM is encodePCM into a borrowed buffer.
+func borrowPCM(samples []float32, format AudioFormat) ([]byte, error) {
+	width, err := bytesPerSample(format)
+	if err != nil {
+		return nil, err
+	}
+	return encodePCMTo(borrowBuffer(len(samples)*width), samples, format)
+}
+
+// Release hands the chunk's AudioData back to be reused by later chunks,
+// sparing the garbage collector a buffer per chunk on long streams. Call it
+// once done with a chunk from GetAudio, and never touch AudioData after;
+// chunks that are still shared elsewhere aren't recycled, so Release is
+// safe to call on any chunk and doing without it only costs allocations.
+func (c *AudioChunk) Release() {
+	if c == nil || !c.pooled {
+		return
+	}
+	returnBuffer(c.AudioData)
+	c.AudioData, c.pooled = nil, false
+}
+
+// chunkCodec receives AudioChunks with their audio in borrowed buffers, and
+// everything else as the proto codec does.
+type chunkCodec struct {
+	encoding.CodecV2
+}
+
+// pooledChunks is the call option streams of AudioChunks receive with.
+var pooledChunks = grpc.ForceCodecV2(chunkCodec{encoding.GetCodecV2(proto.Name)})
+
+func (c chunkCodec) Unmarshal(data mem.BufferSlice, v any) error {
+	msg, ok := v.(*pb.AudioChunk)
+	if !ok {
+		return c.CodecV2.Unmarshal(data, v)
+	}
+	buf := data.MaterializeToBuffer(mem.DefaultBufferPool())
+	defer buf.Free()
+	// audio_data is copied into a borrowed buffer, and the fields around it
+	// unmarshalled on their own
+	b := buf.ReadOnlyData()
+	var audio, rest []byte
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("failed to unmarshal audio chunk: %w", protowire.ParseError(n))
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if m < 0 {
+			return fmt.Errorf("failed to unmarshal audio chunk: %w", protowire.ParseError(m))
+		}
+		if num == 1 && typ == protowire.BytesType {
+			audio, _ = protowire.ConsumeBytes(b[n:])
+		} else {
+			rest = append(rest, b[:n+m]...)
+		}
+		b = b[n+m:]
+	}
+	if err := protov2.Unmarshal(rest, msg); err != nil {
+		return err
+	}
+	if len(audio) > 0 {
+		msg.AudioData = borrowBuffer(len(audio))
+		copy(msg.AudioData, audio)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/mem"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+func TestChunkCodec(t *testing.T) {
+	speech := true
+	want := &pb.AudioChunk{
+		AudioData:   []byte{1, 2, 3, 4},
+		Info:        &pb.AudioInfo{Codec: "pcm16", SampleRate: 8000, NumChannels: 1},
+		Sequence:    7,
+		Speech:      &speech,
+		ResumeToken: "token",
+	}
+	data, err := proto.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got pb.AudioChunk
+	if err := (chunkCodec{}).Unmarshal(mem.BufferSlice{mem.SliceBuffer(data)}, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&got, want) {
+		t.Errorf("got %v, want %v", &got, want)
+	}
+	// the audio is a copy, not the received message
+	data[bytes.Index(data, want.AudioData)] = 9
+	if got.AudioData[0] != 1 {
+		t.Error("the chunk's audio aliases the message it came in")
+	}
+	if err := (chunkCodec{}).Unmarshal(mem.BufferSlice{mem.SliceBuffer(data[:len(data)-1])}, &got); err == nil {
+		t.Error("unmarshalled a truncated chunk")
+	}
+}
+
+func TestReleaseChunks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 { return tone(8000, 80, 200+float64(i)*20, 0.5) }
+	c := serveAudio(t, src)
+
+	// one stream gets the shared chunks as they are, the other converted
+	// ones of its own; releasing every chunk mustn't touch the other's audio
+	var wg sync.WaitGroup
+	got := map[string][]byte{}
+	var mu sync.Mutex
+	for _, codec := range []string{"pcm16", "pcm32_float"} {
+		ch, err := c.GetAudio(ctx, codec, 0, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var data []byte
+			for chunk := range ch {
+				if chunk.Err != nil {
+					t.Error(chunk.Err)
+					return
+				}
+				data = append(data, chunk.AudioData...)
+				chunk.Release()
+				if chunk.AudioData != nil {
+					t.Error("released chunk kept its audio")
+				}
+			}
+			mu.Lock()
+			got[codec] = data
+			mu.Unlock()
+		}()
+	}
+	// both streams subscribe before the capture starts
+	time.Sleep(200 * time.Millisecond)
+	close(src.start)
+	wg.Wait()
+
+	for codec, format := range map[string]AudioFormat{"pcm16": Pcm16, "pcm32_float": Pcm32Float} {
+		var want []byte
+		for i := range src.n {
+			data, _ := encodePCM(src.samples(i), Pcm16)
+			if format != Pcm16 {
+				samples, _ := decodePCM(data, Pcm16)
+				data, _ = encodePCM(samples, format)
+			}
+			want = append(want, data...)
+		}
+		if !bytes.Equal(got[codec], want) {
+			t.Errorf("%s stream has %d bytes, want the %d captured", codec, len(got[codec]), len(want))
+		}
+	}
+
+	// chunks that were never borrowed keep their audio
+	chunk := &AudioChunk{AudioData: []byte{1}}
+	chunk.Release()
+	if chunk.AudioData == nil {
+		t.Error("released a chunk that wasn't borrowed")
+	}
+}
@@ -0,0 +1,230 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// PortAudioModel captures and plays through PortAudio, which wraps each
+// platform's own audio system behind one API. It is the model to start with
+// on Linux, macOS and Windows alike; the alsa, pulse, pipewire, coreaudio and
+// wasapi models talk to one system directly, for lower latency or features
+// PortAudio hides, such as following default device changes. It needs a
+// build with the portaudio tag and libportaudio; other builds know the model
+// but fail to construct it.
+var PortAudioModel = resource.NewModel("olivia", "audio", "portaudio")
+
+// Defaults for PortAudioConfig.
+const (
+	defaultPortAudioLatency = 40 * time.Millisecond
+	// portAudioPeriods is how many transfers the latency is split into
+	portAudioPeriods = 4
+	// portAudioDefaultDevice is the device of a direction using the host
+	// API's default
+	portAudioDefaultDevice = "default"
+)
+
+// PortAudioConfig is the configuration of the portaudio model. Devices are
+// named as PortAudio lists them, which list_devices shows for the resource's
+// host API.
+type PortAudioConfig struct {
+	// HostAPI picks which of the platform's audio systems to go through
+	// when it has several, such as "Windows WASAPI", "MME" or "ALSA". The
+	// default host API if empty.
+	HostAPI string `json:"host_api,omitempty"`
+	// CaptureDevice and PlaybackDevice are device names, or enough of one
+	// to tell it apart, ignoring case. The host API's default if empty;
+	// "none" turns a direction off.
+	CaptureDevice    string `json:"capture_device,omitempty"`
+	PlaybackDevice   string `json:"playback_device,omitempty"`
+	SampleRate       int    `json:"sample_rate,omitempty"`       // 48 kHz if zero
+	Channels         int    `json:"channels,omitempty"`          // capture channels, 1 if zero
+	PlaybackChannels int    `json:"playback_channels,omitempty"` // 2 if zero
+	LatencyMs        int    `json:"latency_ms,omitempty"`        // suggested latency, 40ms if zero
+}
+
+// Validate checks the portaudio configuration.
+func (c *PortAudioConfig) Validate(path string) ([]string, []string, error) {
+	if c.SampleRate < 0 || c.Channels < 0 || c.PlaybackChannels < 0 || c.LatencyMs < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate, channels, playback_channels and latency_ms cannot be negative"))
+	}
+	if c.CaptureDevice == noPCMDevice && c.PlaybackDevice == noPCMDevice {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("capture_device and playback_device cannot both be none"))
+	}
+	return nil, nil, nil
+}
+
+// portAudioDevice is a device as PortAudio lists it.
+type portAudioDevice struct {
+	Index           int
+	Name, HostAPI   string
+	Inputs, Outputs int // most channels in each direction
+	// DefaultInput and DefaultOutput are set on the defaults of the
+	// device's host API, and DefaultHostAPI on devices of the default host
+	// API.
+	DefaultInput, DefaultOutput, DefaultHostAPI bool
+}
+
+// openPortAudio opens a stream on a device by index, and
+// listPortAudioDevices lists them, set by builds with PortAudio support.
+var (
+	openPortAudio        func(device int, capture bool, p pcmParams) (pcmDevice, error)
+	listPortAudioDevices func() ([]portAudioDevice, error)
+)
+
+func init() {
+	registerModel(PortAudioModel, resource.Registration[Audio, *PortAudioConfig]{
+		AttributeMapConverter: migratingConverter[*PortAudioConfig](PortAudioModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*PortAudioConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewPortAudio(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+// NewPortAudio returns a resource on the configured PortAudio devices.
+// Devices are looked up when the resource is built, so one that isn't
+// there fails the configuration rather than the first stream.
+func NewPortAudio(name resource.Name, cfg PortAudioConfig, logger logging.Logger) (Audio, error) {
+	if openPortAudio == nil {
+		return nil, errors.New("this build has no PortAudio support, build with -tags portaudio and libportaudio")
+	}
+	devices, err := listPortAudioDevices()
+	if err != nil {
+		return nil, err
+	}
+	rate := cfg.SampleRate
+	if rate == 0 {
+		rate = defaultPCMSampleRate
+	}
+	channels, playbackChannels := cfg.Channels, cfg.PlaybackChannels
+	if channels == 0 {
+		channels = defaultPCMChannels
+	}
+	if playbackChannels == 0 {
+		playbackChannels = defaultPCMPlaybackChannels
+	}
+	latency := time.Duration(cfg.LatencyMs) * time.Millisecond
+	if latency == 0 {
+		latency = defaultPortAudioLatency
+	}
+	buffer := max(2*portAudioPeriods, int(latency.Seconds()*float64(rate)))
+
+	// the streams of each direction open the device chosen for it
+	chosen := map[bool]portAudioDevice{}
+	a := &pcmAudio{
+		Named:   name.AsNamed(),
+		backend: "portaudio",
+		open: func(_ string, capture bool, p pcmParams) (pcmDevice, error) {
+			return openPortAudio(chosen[capture].Index, capture, p)
+		},
+		listDevices:    func() (deviceList, error) { return portAudioDeviceList(cfg.HostAPI) },
+		captureParams:  pcmParams{rate: rate, channels: channels, periodFrames: buffer / portAudioPeriods, bufferFrames: buffer},
+		playbackParams: pcmParams{rate: rate, channels: playbackChannels, periodFrames: buffer / portAudioPeriods, bufferFrames: buffer},
+		logger:         logger,
+		readers:        map[chan pcmBlock]struct{}{},
+	}
+	for _, capture := range []bool{true, false} {
+		configured := cfg.PlaybackDevice
+		if capture {
+			configured = cfg.CaptureDevice
+		}
+		if configured == noPCMDevice {
+			continue
+		}
+		d, err := choosePortAudioDevice(devices, cfg.HostAPI, configured, capture)
+		if err != nil {
+			return nil, err
+		}
+		chosen[capture] = d
+		if capture {
+			a.capture = d.Name
+		} else {
+			a.playback = d.Name
+		}
+	}
+	return a, nil
+}
+
+// onHostAPI reports whether d belongs to hostAPI, the default one if empty.
+func (d portAudioDevice) onHostAPI(hostAPI string) bool {
+	if hostAPI == "" {
+		return d.DefaultHostAPI
+	}
+	return strings.EqualFold(d.HostAPI, hostAPI)
+}
+
+// choosePortAudioDevice returns the device of hostAPI for one direction:
+// the one named, else the only one whose name contains configured ignoring
+// case, or the host API's default if configured is empty or "default".
+func choosePortAudioDevice(devices []portAudioDevice, hostAPI, configured string, capture bool) (portAudioDevice, error) {
+	direction := playbackDirection
+	if capture {
+		direction = captureDirection
+	}
+	var candidates, matches []portAudioDevice
+	for _, d := range devices {
+		if d.onHostAPI(hostAPI) && ((capture && d.Inputs > 0) || (!capture && d.Outputs > 0)) {
+			candidates = append(candidates, d)
+		}
+	}
+	for _, d := range candidates {
+		switch {
+		case configured == "" || configured == portAudioDefaultDevice:
+			if (capture && d.DefaultInput) || (!capture && d.DefaultOutput) {
+				return d, nil
+			}
+		case d.Name == configured:
+			return d, nil
+		case strings.Contains(strings.ToLower(d.Name), strings.ToLower(configured)):
+			matches = append(matches, d)
+		}
+	}
+	names := make([]string, len(candidates))
+	for i, d := range candidates {
+		names[i] = fmt.Sprintf("%q", d.Name)
+	}
+	switch {
+	case len(candidates) == 0:
+		return portAudioDevice{}, fmt.Errorf("no %s devices found", direction)
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return portAudioDevice{}, fmt.Errorf("%d %s devices match %q, have %s", len(matches), direction, configured, strings.Join(names, ", "))
+	case configured == "" || configured == portAudioDefaultDevice:
+		return portAudioDevice{}, fmt.Errorf("no default %s device", direction)
+	default:
+		return portAudioDevice{}, fmt.Errorf("no %s device %q, have %s", direction, configured, strings.Join(names, ", "))
+	}
+}
+
+// portAudioDeviceList lists the devices of hostAPI.
+func portAudioDeviceList(hostAPI string) (deviceList, error) {
+	devices, err := listPortAudioDevices()
+	if err != nil {
+		return deviceList{}, err
+	}
+	var list deviceList
+	for _, d := range devices {
+		if !d.onHostAPI(hostAPI) {
+			continue
+		}
+		list.devices = append(list.devices, SoundDevice{ID: d.Name, Name: d.Name, Driver: d.HostAPI, Capture: d.Inputs > 0, Playback: d.Outputs > 0})
+		if d.DefaultInput {
+			list.defaultCapture = d.Name
+		}
+		if d.DefaultOutput {
+			list.defaultPlayback = d.Name
+		}
+	}
+	return list, nil
+}
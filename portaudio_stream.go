@@ -0,0 +1,178 @@
+//go:build portaudio
+
+package audio
+
+/*
+#cgo pkg-config: portaudio-2.0
+#include <portaudio.h>
+#include <stdlib.h>
+
+static PaError vpa_open(PaStream **stream, int device, int capture, int channels, double rate,
+		unsigned long period, double latency) {
+	PaStreamParameters params = {
+		.device = device,
+		.channelCount = channels,
+		.sampleFormat = paInt16,
+		.suggestedLatency = latency,
+	};
+	PaError err = Pa_OpenStream(stream, capture ? &params : NULL, capture ? NULL : &params, rate, period,
+		paClipOff, NULL, NULL);
+	if (err != paNoError) {
+		return err;
+	}
+	if ((err = Pa_StartStream(*stream)) != paNoError) {
+		Pa_CloseStream(*stream);
+	}
+	return err;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+func init() {
+	openPortAudio = openPortAudioStream
+	listPortAudioDevices = portAudioDevices
+}
+
+// PortAudio is initialized once, when first used, and stays initialized
+// as it only lists the devices present then.
+var portAudioInit struct {
+	once sync.Once
+	err  error
+}
+
+func initPortAudio() error {
+	portAudioInit.once.Do(func() { portAudioInit.err = portAudioResult(C.Pa_Initialize()) })
+	return portAudioInit.err
+}
+
+var errPortAudioOverflow = errors.New("capture overflow")
+
+// portAudioError is a PaError.
+type portAudioError C.PaError
+
+func (e portAudioError) Error() string {
+	return "portaudio: " + C.GoString(C.Pa_GetErrorText(C.PaError(e)))
+}
+
+func portAudioResult(err C.PaError) error {
+	switch {
+	case err >= 0:
+		return nil
+	case err == C.paUnanticipatedHostError:
+		// the host API's own error says more
+		info := C.Pa_GetLastHostErrorInfo()
+		return fmt.Errorf("portaudio: host error %d: %s", int(info.errorCode), C.GoString(info.errorText))
+	default:
+		return portAudioError(err)
+	}
+}
+
+func portAudioDevices() ([]portAudioDevice, error) {
+	if err := initPortAudio(); err != nil {
+		return nil, err
+	}
+	n := C.Pa_GetDeviceCount()
+	if n < 0 {
+		return nil, portAudioResult(C.PaError(n))
+	}
+	defaultAPI := C.Pa_GetDefaultHostApi()
+	devices := make([]portAudioDevice, 0, int(n))
+	for i := C.PaDeviceIndex(0); i < n; i++ {
+		info := C.Pa_GetDeviceInfo(i)
+		if info == nil {
+			continue
+		}
+		api := C.Pa_GetHostApiInfo(info.hostApi)
+		if api == nil {
+			continue
+		}
+		devices = append(devices, portAudioDevice{
+			Index:          int(i),
+			Name:           C.GoString(info.name),
+			HostAPI:        C.GoString(api.name),
+			Inputs:         int(info.maxInputChannels),
+			Outputs:        int(info.maxOutputChannels),
+			DefaultInput:   api.defaultInputDevice == i,
+			DefaultOutput:  api.defaultOutputDevice == i,
+			DefaultHostAPI: info.hostApi == defaultAPI,
+		})
+	}
+	return devices, nil
+}
+
+// portAudioStream is a blocking PortAudio stream. Overflows leave it
+// running; a device that goes away can't be recovered from, as PortAudio
+// doesn't notice devices coming back.
+type portAudioStream struct {
+	s        unsafe.Pointer // *PaStream
+	channels int
+}
+
+func openPortAudioStream(device int, capture bool, p pcmParams) (pcmDevice, error) {
+	if err := initPortAudio(); err != nil {
+		return nil, err
+	}
+	c := C.int(0)
+	if capture {
+		c = 1
+	}
+	latency := float64(p.bufferFrames) / float64(p.rate)
+	var s unsafe.Pointer
+	err := C.vpa_open(&s, C.int(device), c, C.int(p.channels), C.double(p.rate), C.ulong(p.periodFrames), C.double(latency))
+	if err := portAudioResult(err); err != nil {
+		return nil, err
+	}
+	return &portAudioStream{s: s, channels: p.channels}, nil
+}
+
+func (s *portAudioStream) read(buf []int16) (int, error) {
+	frames := len(buf) / s.channels
+	err := C.Pa_ReadStream(s.s, unsafe.Pointer(&buf[0]), C.ulong(frames))
+	if err == C.paInputOverflowed {
+		return 0, errPortAudioOverflow
+	}
+	if err := portAudioResult(err); err != nil {
+		return 0, err
+	}
+	return frames, nil
+}
+
+func (s *portAudioStream) write(buf []int16) (int, error) {
+	frames := len(buf) / s.channels
+	err := C.Pa_WriteStream(s.s, unsafe.Pointer(&buf[0]), C.ulong(frames))
+	// an underflow happened before the write, whose audio is queued
+	if err == C.paOutputUnderflowed {
+		return frames, nil
+	}
+	if err := portAudioResult(err); err != nil {
+		return 0, err
+	}
+	return frames, nil
+}
+
+func (s *portAudioStream) recover(err error) error {
+	if !errors.Is(err, errPortAudioOverflow) {
+		return err
+	}
+	return nil
+}
+
+// drain stops the stream, which plays out what was written, and starts it
+// again.
+func (s *portAudioStream) drain() error {
+	if err := portAudioResult(C.Pa_StopStream(s.s)); err != nil {
+		return err
+	}
+	return portAudioResult(C.Pa_StartStream(s.s))
+}
+
+func (s *portAudioStream) close() error {
+	return portAudioResult(C.Pa_CloseStream(s.s))
+}
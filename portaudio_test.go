@@ -0,0 +1,140 @@
+package audio
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// a Windows machine lists its devices once for each host API
+var windowsPortAudioDevices = []portAudioDevice{
+	{Index: 0, Name: "Microphone (USB Audio Device)", HostAPI: "MME", Inputs: 2, DefaultInput: true, DefaultHostAPI: true},
+	{Index: 1, Name: "Microphone Array (Realtek(R) Audio)", HostAPI: "MME", Inputs: 2, DefaultHostAPI: true},
+	{Index: 2, Name: "Speakers (Realtek(R) Audio)", HostAPI: "MME", Outputs: 2, DefaultOutput: true, DefaultHostAPI: true},
+	{Index: 3, Name: "Microphone (USB Audio Device)", HostAPI: "Windows WASAPI", Inputs: 1},
+	{Index: 4, Name: "Microphone Array (Realtek(R) Audio)", HostAPI: "Windows WASAPI", Inputs: 4, DefaultInput: true},
+	{Index: 5, Name: "Speakers (Realtek(R) Audio)", HostAPI: "Windows WASAPI", Outputs: 2, DefaultOutput: true},
+}
+
+func TestChoosePortAudioDevice(t *testing.T) {
+	for _, tc := range []struct {
+		hostAPI, device string
+		capture         bool
+		want            int
+		err             string
+	}{
+		{"", "", true, 0, ""},
+		{"", "default", false, 2, ""},
+		{"windows wasapi", "", true, 4, ""},
+		{"Windows WASAPI", "usb", true, 3, ""},
+		{"", "Microphone Array (Realtek(R) Audio)", true, 1, ""},
+		{"", "microphone", true, 0, "2 capture devices match"},
+		{"", "speakers", true, 0, "no capture device"},
+		{"ASIO", "", false, 0, "no playback devices"},
+	} {
+		d, err := choosePortAudioDevice(windowsPortAudioDevices, tc.hostAPI, tc.device, tc.capture)
+		switch {
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%q on %q: got %v, want an error with %q", tc.device, tc.hostAPI, err, tc.err)
+		case tc.err == "" && err != nil:
+			t.Errorf("%q on %q: %v", tc.device, tc.hostAPI, err)
+		case tc.err == "" && d.Index != tc.want:
+			t.Errorf("%q on %q: chose %d, want %d", tc.device, tc.hostAPI, d.Index, tc.want)
+		}
+	}
+}
+
+func TestPortAudioDevices(t *testing.T) {
+	var mu sync.Mutex
+	opened := map[bool]int{}
+	var played *fakePCM
+	prevOpen, prevList := openPortAudio, listPortAudioDevices
+	openPortAudio = func(device int, capture bool, p pcmParams) (pcmDevice, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		opened[capture] = device
+		f := &fakePCM{p: p, period: time.Duration(p.periodFrames) * time.Second / time.Duration(p.rate), fail: map[int]bool{}}
+		if !capture {
+			played = f
+		}
+		return f, nil
+	}
+	listPortAudioDevices = func() ([]portAudioDevice, error) { return windowsPortAudioDevices, nil }
+	defer func() { openPortAudio, listPortAudioDevices = prevOpen, prevList }()
+
+	a, err := NewPortAudio(Named("kiosk"), PortAudioConfig{HostAPI: "Windows WASAPI", CaptureDevice: "USB", SampleRate: 16000}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), 0.25, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames int
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatal(c.Err)
+		}
+		frames += len(c.AudioData) / 2
+	}
+	if frames != 4000 {
+		t.Errorf("captured %d frames, want 4000", frames)
+	}
+	clip, _ := encodePCM(tone(16000, 160, 440, 0.5), Pcm16)
+	if err := a.Play(ctx, clip, Pcm16.String(), 16000, 1); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if opened[true] != 3 || opened[false] != 5 {
+		t.Errorf("opened %d for capture and %d for playback, want 3 and 5", opened[true], opened[false])
+	}
+	played.mu.Lock()
+	if len(played.written) != 2*160 || played.drained != 1 {
+		t.Errorf("played %d samples and drained %d times, want 320 and once", len(played.written), played.drained)
+	}
+	played.mu.Unlock()
+	mu.Unlock()
+
+	// the devices listed are the host API's
+	resp, err := a.DoCommand(ctx, map[string]interface{}{"list_devices": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if devices := resp["devices"].([]interface{}); len(devices) != 3 || resp["default_capture"] != "Microphone Array (Realtek(R) Audio)" {
+		t.Errorf("listed %v", resp)
+	}
+
+	if _, err := NewPortAudio(Named("kiosk"), PortAudioConfig{CaptureDevice: "Webcam"}, logging.NewTestLogger(t)); err == nil {
+		t.Error("built on a device that isn't there")
+	}
+}
+
+func TestPortAudioConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg PortAudioConfig
+		ok  bool
+	}{
+		{PortAudioConfig{}, true},
+		{PortAudioConfig{HostAPI: "ALSA", CaptureDevice: "USB", PlaybackDevice: "none"}, true},
+		{PortAudioConfig{CaptureDevice: "none", PlaybackDevice: "none"}, false},
+		{PortAudioConfig{LatencyMs: -1}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+
+	prev := openPortAudio
+	openPortAudio = nil
+	defer func() { openPortAudio = prev }()
+	if _, err := NewPortAudio(Named("kiosk"), PortAudioConfig{}, logging.NewTestLogger(t)); err == nil {
+		t.Error("built without PortAudio support")
+	}
+}
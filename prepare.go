@@ -0,0 +1,229 @@
+package audio
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// PlaybackPreparer is implemented by Audio resources and clients that can
+// get a clip ready ahead of time, so that cues synchronized with motion
+// start within a few milliseconds of CommitPlayback instead of paying for
+// the upload and decode when they are due.
+type PlaybackPreparer interface {
+	// PreparePlayback uploads and decodes a clip and returns its handle.
+	// Handles that are never committed or released expire after a minute.
+	PreparePlayback(ctx context.Context, data []byte, codec string, sampleRate, channels int) (string, error)
+	// CommitPlayback starts a prepared clip at the given time, or at once if
+	// it is zero, and returns when it has played out like Play. A handle can
+	// only be committed once.
+	CommitPlayback(ctx context.Context, handle string, at time.Time) error
+	// ReleasePlayback discards a prepared clip that won't be played.
+	ReleasePlayback(ctx context.Context, handle string) error
+}
+
+// preparedTTL is how long a prepared clip waits for its commit.
+const preparedTTL = time.Minute
+
+// preparedClip is a clip waiting for its commit. Resources that prepare
+// natively keep the decoded samples, the generic path the original upload.
+type preparedClip struct {
+	resource   string // the one it was checked for, and the only one it plays on
+	data       []byte
+	codec      string
+	sampleRate int
+	channels   int
+	samples    []float32 // in the device format
+	expires    time.Time
+}
+
+// preparedClips holds prepared clips by handle.
+type preparedClips struct {
+	mu    sync.Mutex
+	clips map[string]*preparedClip
+}
+
+func newPreparedClips() *preparedClips {
+	return &preparedClips{clips: map[string]*preparedClip{}}
+}
+
+// add stores clip and returns its handle, dropping clips that expired.
+func (p *preparedClips) add(clip *preparedClip) string {
+	handle := strings.ToLower(rand.Text())
+	now := time.Now()
+	clip.expires = now.Add(preparedTTL)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for h, c := range p.clips {
+		if now.After(c.expires) {
+			delete(p.clips, h)
+		}
+	}
+	p.clips[handle] = clip
+	return handle
+}
+
+// take removes and returns the clip for handle prepared on resource. A
+// handle of another resource's clip is left for that resource.
+func (p *preparedClips) take(resource, handle string) (*preparedClip, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clip, ok := p.clips[handle]
+	if ok && time.Now().After(clip.expires) {
+		delete(p.clips, handle)
+		ok = false
+	}
+	if !ok || clip.resource != resource {
+		return nil, errorf(ErrNotFound, "no prepared playback with handle %q on %q", handle, resource)
+	}
+	delete(p.clips, handle)
+	return clip, nil
+}
+
+// prepareGeneric checks a clip for a resource without native preparation.
+// The server can only decode raw pcm itself, which is cheap enough for the
+// resource to redo at commit time; other codecs would leave their decode
+// cost on the commit, so they need a resource that prepares natively.
+func prepareGeneric(resource string, data []byte, codec string, sampleRate, channels int) (*preparedClip, error) {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return nil, errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, errorf(ErrUnsupportedCodec, "cannot prepare %s playback: the resource has no native support for preparing it", codec)
+	}
+	if _, err := decodePCM(data, format); err != nil {
+		return nil, errorf(ErrInvalidArgument, "%w", err)
+	}
+	return &preparedClip{resource: resource, data: data, codec: codec, sampleRate: sampleRate, channels: channels}, nil
+}
+
+func (s *audioServer) PreparePlayback(ctx context.Context, req *pb.PreparePlaybackRequest) (*pb.PreparePlaybackResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	codec, rate, channels := req.GetInfo().GetCodec(), int(req.GetInfo().GetSampleRate()), int(req.GetInfo().GetNumChannels())
+	if err := checkPlayback(req.Name, playbackProperties(ctx, a), codec, rate, channels); err != nil {
+		return nil, err
+	}
+	if pp, ok := a.(PlaybackPreparer); ok {
+		handle, err := pp.PreparePlayback(ctx, req.AudioData, codec, rate, channels)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.PreparePlaybackResponse{Handle: handle}, nil
+	}
+	clip, err := prepareGeneric(req.Name, req.AudioData, codec, rate, channels)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PreparePlaybackResponse{Handle: s.prepared.add(clip)}, nil
+}
+
+func (s *audioServer) CommitPlayback(ctx context.Context, req *pb.CommitPlaybackRequest) (*pb.CommitPlaybackResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	var at time.Time
+	if req.StartTimeNanoseconds != 0 {
+		at = time.Unix(0, req.StartTimeNanoseconds)
+	}
+	pp, native := a.(PlaybackPreparer)
+	var clip *preparedClip
+	if !native {
+		if clip, err = s.prepared.take(req.Name, req.Handle); err != nil {
+			return nil, err
+		}
+	}
+	// the playback lock is taken once the clip is due, so a clip scheduled
+	// ahead doesn't hold up the resource's other playback meanwhile, nor the
+	// cues committed for the same trigger. A native commit still gets the
+	// time to place the clip on the device's own timeline.
+	if !at.IsZero() {
+		clock := clockOf(a)
+		if err := clock.Sleep(ctx, at.Sub(clock.Now())); err != nil {
+			return nil, err
+		}
+	}
+	release, err := s.playback.acquire(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	detail := "clip prepared by the resource"
+	if !native {
+		detail = fmt.Sprintf("%s, %d Hz, %d channels, %d bytes", clip.codec, clip.sampleRate, clip.channels, len(clip.data))
+	}
+	if err := s.audit(ctx, req.Name, AuditPlay, req.Handle, detail, nil); err != nil {
+		return nil, err
+	}
+	e := StreamEvent{Resource: req.Name, Direction: playbackDirection}
+	if native {
+		playbackDone := observeStream(ctx, a, e)
+		err = pp.CommitPlayback(ctx, req.Handle, at)
+		playbackDone(err)
+	} else {
+		e.Codec, e.SampleRate, e.Channels = clip.codec, clip.sampleRate, clip.channels
+		playbackDone := observeStream(ctx, a, e)
+		err = a.Play(ctx, clip.data, clip.codec, clip.sampleRate, clip.channels)
+		playbackDone(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CommitPlaybackResponse{}, nil
+}
+
+func (s *audioServer) ReleasePlayback(ctx context.Context, req *pb.ReleasePlaybackRequest) (*pb.ReleasePlaybackResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if pp, ok := a.(PlaybackPreparer); ok {
+		if err := pp.ReleasePlayback(ctx, req.Handle); err != nil {
+			return nil, err
+		}
+		return &pb.ReleasePlaybackResponse{}, nil
+	}
+	if _, err := s.prepared.take(req.Name, req.Handle); err != nil {
+		return nil, err
+	}
+	return &pb.ReleasePlaybackResponse{}, nil
+}
+
+func (c *audioClient) PreparePlayback(ctx context.Context, data []byte, codec string, sampleRate, channels int) (string, error) {
+	resp, err := c.client.PreparePlayback(ctx, &pb.PreparePlaybackRequest{
+		Name:      c.name,
+		AudioData: data,
+		Info:      &pb.AudioInfo{Codec: codec, SampleRate: int32(sampleRate), NumChannels: int32(channels)},
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Handle, nil
+}
+
+func (c *audioClient) CommitPlayback(ctx context.Context, handle string, at time.Time) error {
+	req := &pb.CommitPlaybackRequest{Name: c.name, Handle: handle}
+	if !at.IsZero() {
+		req.StartTimeNanoseconds = at.UnixNano()
+	}
+	_, err := c.client.CommitPlayback(ctx, req)
+	return err
+}
+
+func (c *audioClient) ReleasePlayback(ctx context.Context, handle string) error {
+	_, err := c.client.ReleasePlayback(ctx, &pb.ReleasePlaybackRequest{Name: c.name, Handle: handle})
+	return err
+}
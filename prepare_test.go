@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// waitForSleeper blocks until something is sleeping on c until at.
+func waitForSleeper(t *testing.T, c *ManualClock, at time.Time) {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		c.mu.Lock()
+		for _, due := range c.waiters {
+			if due.Equal(at) {
+				c.mu.Unlock()
+				return
+			}
+		}
+		c.mu.Unlock()
+	}
+	t.Fatalf("nothing is sleeping until %v", at)
+}
+
+func TestLoopbackCommitPlaybackLatency(t *testing.T) {
+	const (
+		rate    = 48000
+		latency = 20 * time.Millisecond
+	)
+	clock := NewManualClock(time.Unix(1000, 0))
+	a := NewLoopback(Named("loop"), LoopbackConfig{SampleRate: rate, LatencyMs: int(latency / time.Millisecond), Clock: clock}, logging.NewTestLogger(t))
+	defer a.Close(context.Background())
+	l := a.(*loopback)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm32Float.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clip := make([]float32, rate/100)
+	for i := range clip {
+		clip[i] = 0.5
+	}
+	data, err := encodePCM(clip, Pcm32Float)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, err := l.PreparePlayback(ctx, data, Pcm32Float.String(), rate, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// commit off the chunk grid, so only a sample-accurate start lands on it
+	at := clock.Now().Add(55*time.Millisecond + 123*time.Microsecond)
+	committed := make(chan error, 1)
+	go func() { committed <- l.CommitPlayback(ctx, handle, at) }()
+	waitForSleeper(t, clock, at)
+	for clock.Now().Before(at) {
+		clock.Advance(time.Millisecond)
+	}
+	for queued := false; !queued; time.Sleep(time.Millisecond) {
+		l.mu.Lock()
+		queued = len(l.pending) > 0
+		l.mu.Unlock()
+		if ctx.Err() != nil {
+			t.Fatal("commit never queued the clip")
+		}
+	}
+	for end := at.Add(latency + 100*time.Millisecond); clock.Now().Before(end); {
+		clock.Advance(10 * time.Millisecond)
+	}
+
+	var first time.Time
+	for first.IsZero() {
+		var chunk *AudioChunk
+		select {
+		case chunk = <-chunks:
+		case <-ctx.Done():
+			t.Fatal("clip never came out of capture")
+		}
+		samples, err := decodePCM(chunk.AudioData, Pcm32Float)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, s := range samples {
+			if s != 0 {
+				first = chunk.Timestamp.Add(time.Duration(i) * time.Second / rate)
+				break
+			}
+		}
+	}
+	if err := <-committed; err != nil {
+		t.Fatal(err)
+	}
+	if d := first.Sub(at.Add(latency)); d < 0 || d > time.Millisecond {
+		t.Fatalf("first sample %v after commit time plus latency, want within 1ms", d)
+	}
+}
+
+func TestPrepareGenericRejectsUndecodableCodecs(t *testing.T) {
+	if _, err := prepareGeneric("speaker", []byte{0xff, 0xfb}, Mp3.String(), 44100, 1); !errors.Is(err, ErrUnsupportedCodec) {
+		t.Fatalf("mp3 prepared without native support: %v", err)
+	}
+	clip, err := prepareGeneric("speaker", make([]byte, 480*2), Pcm16.String(), 48000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clip.codec != Pcm16.String() || len(clip.data) != 960 {
+		t.Fatalf("unexpected clip %+v", clip)
+	}
+
+	// a handle only commits on the resource it was checked for
+	clips := newPreparedClips()
+	handle := clips.add(clip)
+	if _, err := clips.take("other", handle); !errors.Is(err, ErrNotFound) {
+		t.Errorf("took another resource's clip: %v", err)
+	}
+	if _, err := clips.take("speaker", handle); err != nil {
+		t.Error(err)
+	}
+}
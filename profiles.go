@@ -0,0 +1,506 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// ProfilesModel wraps another Audio and renders everything played through it
+// with a named environment profile, so a robot can be quieter in an office
+// and louder and brighter on a factory floor. The profile is switched with
+// SetProfile or follows the ambient noise reported by a linked sensor.
+var ProfilesModel = resource.NewModel("olivia", "audio", "profiles")
+
+// ProfileSwitcher is implemented by Audio resources and clients that render
+// output through environment profiles.
+type ProfileSwitcher interface {
+	// SetProfile renders output with the named profile until the next call.
+	// An empty name goes back to choosing the profile from the linked sensor.
+	SetProfile(ctx context.Context, name string) error
+	GetProfile(ctx context.Context) (ProfileStatus, error)
+}
+
+// ProfileStatus is the profile output is currently rendered with.
+type ProfileStatus struct {
+	Profile   string
+	Profiles  []string // every profile that can be set
+	Automatic bool     // chosen from the linked sensor rather than set
+}
+
+// OutputProfile is how a robot sounds in one environment. Processing runs
+// gain, EQ, then compression.
+type OutputProfile struct {
+	GainDB       float64             `json:"gain_db,omitempty"`
+	EQ           []EQBand            `json:"eq,omitempty"`
+	Compressor   *CompressorConfig   `json:"compressor,omitempty"`
+	Notification NotificationPattern `json:"notification,omitempty"`
+}
+
+// EQBand is one filter of an EQ chain, in a profile or the eq model.
+type EQBand struct {
+	Type        string  `json:"type"` // lowshelf, highshelf, peaking, lowpass or highpass
+	FrequencyHz float64 `json:"frequency_hz"`
+	GainDB      float64 `json:"gain_db,omitempty"`
+	Q           float64 `json:"q,omitempty"` // 0.707 if zero
+}
+
+// CompressorConfig evens out loud and quiet passages so speech stays
+// intelligible over noise without the peaks clipping.
+type CompressorConfig struct {
+	ThresholdDBFS float64 `json:"threshold_dbfs"`
+	Ratio         float64 `json:"ratio"`
+	AttackMs      float64 `json:"attack_ms,omitempty"`
+	ReleaseMs     float64 `json:"release_ms,omitempty"`
+	MakeupDB      float64 `json:"makeup_db,omitempty"`
+}
+
+// NotificationPattern shapes how each played clip is announced: an optional
+// attention chime before it and a number of repeats.
+type NotificationPattern struct {
+	ChimeHz float64 `json:"chime_hz,omitempty"` // no chime if zero
+	ChimeMs int     `json:"chime_ms,omitempty"` // 150ms if zero
+	Repeat  int     `json:"repeat,omitempty"`   // extra times the clip is played
+	GapMs   int     `json:"gap_ms,omitempty"`   // silence after the chime and between repeats
+}
+
+// DefaultProfiles are available to every profiles resource. Configured
+// profiles with the same names replace them.
+var DefaultProfiles = map[string]OutputProfile{
+	"quiet_office": {
+		GainDB: -12,
+		EQ:     []EQBand{{Type: "highpass", FrequencyHz: 120}},
+	},
+	"factory_floor": {
+		EQ: []EQBand{
+			{Type: "highpass", FrequencyHz: 250},
+			{Type: "peaking", FrequencyHz: 2500, GainDB: 6, Q: 1},
+		},
+		Compressor:   &CompressorConfig{ThresholdDBFS: -20, Ratio: 4, AttackMs: 1, ReleaseMs: 100, MakeupDB: 8},
+		Notification: NotificationPattern{ChimeHz: 1760, ChimeMs: 150, Repeat: 1, GapMs: 300},
+	},
+}
+
+// Defaults for ProfilesConfig.
+const (
+	defaultProfileChimeMs = 150
+	defaultProfilePoll    = 2 * time.Second
+)
+
+// ProfileRule selects a profile while the linked sensor reads at least Above.
+type ProfileRule struct {
+	Above   float64 `json:"above"`
+	Profile string  `json:"profile"`
+}
+
+// ProfilesConfig is the configuration of the profiles model.
+type ProfilesConfig struct {
+	Output   string                   `json:"output"` // Audio resource to play through
+	Profiles map[string]OutputProfile `json:"profiles,omitempty"`
+	Default  string                   `json:"default,omitempty"` // quiet_office if empty
+
+	// Sensor and SensorKey name a reading, typically ambient noise in dB,
+	// that picks a profile with Rules while none is set explicitly.
+	Sensor         string        `json:"sensor,omitempty"`
+	SensorKey      string        `json:"sensor_key,omitempty"`
+	Rules          []ProfileRule `json:"rules,omitempty"`
+	Hysteresis     float64       `json:"hysteresis,omitempty"` // how far below a rule the reading has to fall to leave it
+	PollIntervalMs int           `json:"poll_interval_ms,omitempty"`
+
+	// AdaptiveVolume raises and lowers playback gain with the ambient noise
+	// on top of the profile.
+	AdaptiveVolume *AdaptiveVolumeConfig `json:"adaptive_volume,omitempty"`
+}
+
+func (c *ProfilesConfig) profiles() map[string]OutputProfile {
+	all := make(map[string]OutputProfile, len(DefaultProfiles)+len(c.Profiles))
+	for name, p := range DefaultProfiles {
+		all[name] = p
+	}
+	for name, p := range c.Profiles {
+		all[name] = p
+	}
+	return all
+}
+
+// Validate checks the profiles configuration and returns the output and
+// sensor as dependencies.
+func (c *ProfilesConfig) Validate(path string) ([]string, []string, error) {
+	if c.Output == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "output")
+	}
+	profiles := c.profiles()
+	if c.Default != "" {
+		if _, ok := profiles[c.Default]; !ok {
+			return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("unknown default profile %q", c.Default))
+		}
+	}
+	for name, p := range c.Profiles {
+		if p.Compressor != nil && p.Compressor.Ratio < 1 {
+			return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("profile %q: compressor ratio must be at least 1", name))
+		}
+		if err := checkEQ(p.EQ); err != nil {
+			return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("profile %q: %w", name, err))
+		}
+	}
+	deps := []string{c.Output}
+	if c.Sensor != "" {
+		if c.SensorKey == "" {
+			return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "sensor_key")
+		}
+		if len(c.Rules) == 0 {
+			return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "rules")
+		}
+		for _, r := range c.Rules {
+			if _, ok := profiles[r.Profile]; !ok {
+				return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("rule uses unknown profile %q", r.Profile))
+			}
+		}
+		deps = append(deps, c.Sensor)
+	}
+	if c.AdaptiveVolume != nil {
+		if err := c.AdaptiveVolume.validate(); err != nil {
+			return nil, nil, resource.NewConfigValidationError(path+".adaptive_volume", err)
+		}
+		if c.AdaptiveVolume.Input != "" {
+			deps = append(deps, c.AdaptiveVolume.Input)
+		}
+	}
+	return deps, nil, nil
+}
+
+func init() {
+	registerModel(ProfilesModel, resource.Registration[Audio, *ProfilesConfig]{
+		AttributeMapConverter: migratingConverter[*ProfilesConfig](ProfilesModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*ProfilesConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			output, err := resource.FromDependencies[Audio](deps, Named(cfg.Output))
+			if err != nil {
+				return nil, err
+			}
+			var s sensor.Sensor
+			if cfg.Sensor != "" {
+				if s, err = sensor.FromDependencies(deps, cfg.Sensor); err != nil {
+					return nil, err
+				}
+			}
+			input := output
+			if cfg.AdaptiveVolume != nil && cfg.AdaptiveVolume.Input != "" {
+				if input, err = resource.FromDependencies[Audio](deps, Named(cfg.AdaptiveVolume.Input)); err != nil {
+					return nil, err
+				}
+			}
+			return NewProfiles(conf.ResourceName(), output, input, s, *cfg, logger)
+		},
+	})
+}
+
+type profiled struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	output   Audio
+	profiles map[string]OutputProfile
+	adaptive *adaptiveVolume // nil without adaptive volume
+	logger   logging.Logger
+
+	mu        sync.Mutex
+	set       string // explicitly set profile, empty when automatic
+	automatic string // profile chosen from the sensor
+	cancel    context.CancelFunc
+	workers   sync.WaitGroup
+}
+
+// NewProfiles returns a resource playing through output with environment
+// profiles. s may be nil if the profile isn't chosen from a sensor, and input
+// is only metered with cfg.AdaptiveVolume.
+func NewProfiles(name resource.Name, output, input Audio, s sensor.Sensor, cfg ProfilesConfig, logger logging.Logger) (Audio, error) {
+	if cfg.Default == "" {
+		cfg.Default = "quiet_office"
+	}
+	p := &profiled{
+		Named:     name.AsNamed(),
+		output:    output,
+		profiles:  cfg.profiles(),
+		logger:    logger,
+		automatic: cfg.Default,
+	}
+	if _, ok := p.profiles[cfg.Default]; !ok {
+		return nil, fmt.Errorf("unknown default profile %q", cfg.Default)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	if cfg.AdaptiveVolume != nil {
+		if input == nil {
+			input = output
+		}
+		p.adaptive = newAdaptiveVolume(*cfg.AdaptiveVolume, logger)
+		p.workers.Add(1)
+		go func() {
+			defer p.workers.Done()
+			p.adaptive.meter(ctx, input)
+		}()
+	}
+	if s != nil {
+		if cfg.PollIntervalMs == 0 {
+			cfg.PollIntervalMs = int(defaultProfilePoll / time.Millisecond)
+		}
+		rules := append([]ProfileRule(nil), cfg.Rules...)
+		sort.Slice(rules, func(i, j int) bool { return rules[i].Above < rules[j].Above })
+		p.workers.Add(1)
+		go func() {
+			defer p.workers.Done()
+			p.follow(ctx, s, cfg.SensorKey, rules, cfg.Hysteresis, time.Duration(cfg.PollIntervalMs)*time.Millisecond, cfg.Default)
+		}()
+	}
+	return p, nil
+}
+
+// follow polls the sensor and picks the profile of the highest rule the
+// reading reaches, or fallback below every rule, only dropping to a lower
+// rule once the reading is hysteresis below the current one.
+func (p *profiled) follow(ctx context.Context, s sensor.Sensor, key string, rules []ProfileRule, hysteresis float64, interval time.Duration, fallback string) {
+	current := -1
+	for {
+		readings, err := s.Readings(ctx, nil)
+		if err == nil {
+			var value float64
+			if value, err = readingValue(readings, key); err == nil {
+				next := -1
+				for i, r := range rules {
+					if value >= r.Above {
+						next = i
+					}
+				}
+				if next < current && value >= rules[current].Above-hysteresis {
+					next = current
+				}
+				if next != current {
+					profile := fallback
+					if next >= 0 {
+						profile = rules[next].Profile
+					}
+					p.mu.Lock()
+					p.automatic = profile
+					p.mu.Unlock()
+					p.logger.Infof("%s reads %g, switching to profile %q", key, value, profile)
+				}
+				current = next
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			p.logger.Warnf("failed to read ambient level: %v", err)
+		}
+		if sleepCtx(ctx, interval) != nil {
+			return
+		}
+	}
+}
+
+func readingValue(readings map[string]interface{}, key string) (float64, error) {
+	switch v := readings[key].(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case nil:
+		return 0, fmt.Errorf("sensor has no reading %q", key)
+	default:
+		return 0, fmt.Errorf("reading %q is a %T, not a number", key, v)
+	}
+}
+
+func (p *profiled) SetProfile(ctx context.Context, name string) error {
+	if _, ok := p.profiles[name]; !ok && name != "" {
+		return errorf(ErrNotFound, "unknown profile %q", name)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.set = name
+	return nil
+}
+
+func (p *profiled) GetProfile(ctx context.Context) (ProfileStatus, error) {
+	names := make([]string, 0, len(p.profiles))
+	for name := range p.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.set != "" {
+		return ProfileStatus{Profile: p.set, Profiles: names}, nil
+	}
+	return ProfileStatus{Profile: p.automatic, Profiles: names, Automatic: true}, nil
+}
+
+func (p *profiled) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	return p.output.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+}
+
+// Play renders raw pcm audio with the current profile and plays it on the
+// output.
+func (p *profiled) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return fmt.Errorf("profiles can only render raw pcm: %w", err)
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	samples, err := decodePCM(data, format)
+	if err != nil {
+		return err
+	}
+	status, _ := p.GetProfile(ctx)
+	rendered, err := renderProfile(p.profiles[status.Profile], samples, sampleRate, channels)
+	if err != nil {
+		return err
+	}
+	if p.adaptive != nil {
+		gain := float32(math.Pow(10, p.adaptive.start()/20))
+		defer p.adaptive.done()
+		for i, s := range rendered {
+			rendered[i] = clip(s * gain)
+		}
+	}
+	out, err := encodePCM(rendered, format)
+	if err != nil {
+		return err
+	}
+	return p.output.Play(ctx, out, codec, sampleRate, channels)
+}
+
+// renderProfile applies profile to interleaved samples, returning a new
+// buffer with its notification pattern.
+func renderProfile(profile OutputProfile, samples []float32, rate, channels int) ([]float32, error) {
+	n := profile.Notification
+	gap := make([]float32, n.GapMs*rate/1000*channels)
+	var out []float32
+	if n.ChimeHz > 0 {
+		ms := n.ChimeMs
+		if ms == 0 {
+			ms = defaultProfileChimeMs
+		}
+		out = append(out, remix(chime(rate, ms, n.ChimeHz), 1, channels)...)
+		out = append(out, gap...)
+	}
+	for i := 0; i <= n.Repeat; i++ {
+		if i > 0 {
+			out = append(out, gap...)
+		}
+		out = append(out, samples...)
+	}
+
+	if profile.GainDB != 0 {
+		gain := float32(math.Pow(10, profile.GainDB/20))
+		for i := range out {
+			out[i] *= gain
+		}
+	}
+	for _, band := range profile.EQ {
+		f, err := newBiquad(band.Type, rate, band.FrequencyHz, band.GainDB, band.Q, channels)
+		if err != nil {
+			return nil, err
+		}
+		f.process(out)
+	}
+	if profile.Compressor != nil {
+		newCompressor(*profile.Compressor, rate, channels).process(out)
+	}
+	for i, s := range out {
+		out[i] = clip(s)
+	}
+	return out, nil
+}
+
+// chime is a half scale sine with a decaying envelope.
+func chime(rate, ms int, freq float64) []float32 {
+	out := make([]float32, ms*rate/1000)
+	for i := range out {
+		t := float64(i) / float64(rate)
+		fade := math.Min(1, float64(i)/(0.005*float64(rate))) // 5ms attack against clicks
+		out[i] = float32(0.5 * fade * math.Exp(-4*t*1000/float64(ms)) * math.Sin(2*math.Pi*freq*t))
+	}
+	return out
+}
+
+func (p *profiled) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, p, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (p *profiled) Close(ctx context.Context) error {
+	p.cancel()
+	p.workers.Wait()
+	return nil
+}
+
+func (s *audioServer) SetProfile(ctx context.Context, req *pb.SetProfileRequest) (*pb.SetProfileResponse, error) {
+	ps, err := s.profileSwitcher(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ps.SetProfile(ctx, req.Profile); err != nil {
+		return nil, err
+	}
+	return &pb.SetProfileResponse{}, nil
+}
+
+func (s *audioServer) GetProfile(ctx context.Context, req *pb.GetProfileRequest) (*pb.GetProfileResponse, error) {
+	ps, err := s.profileSwitcher(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	status, err := ps.GetProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetProfileResponse{Profile: status.Profile, Profiles: status.Profiles, Automatic: status.Automatic}, nil
+}
+
+func (s *audioServer) profileSwitcher(name string) (ProfileSwitcher, error) {
+	a, err := s.resource(name)
+	if err != nil {
+		return nil, err
+	}
+	ps, ok := a.(ProfileSwitcher)
+	if !ok {
+		return nil, errorf(ErrUnsupported, "%s does not support output profiles", name)
+	}
+	return ps, nil
+}
+
+func (c *audioClient) SetProfile(ctx context.Context, name string) error {
+	_, err := c.client.SetProfile(ctx, &pb.SetProfileRequest{Name: c.name, Profile: name})
+	return err
+}
+
+func (c *audioClient) GetProfile(ctx context.Context) (ProfileStatus, error) {
+	resp, err := c.client.GetProfile(ctx, &pb.GetProfileRequest{Name: c.name})
+	if err != nil {
+		return ProfileStatus{}, err
+	}
+	return ProfileStatus{Profile: resp.Profile, Profiles: resp.Profiles, Automatic: resp.Automatic}, nil
+}
@@ -0,0 +1,164 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestProfilesConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  ProfilesConfig
+		deps []string
+	}{
+		{"output only", ProfilesConfig{Output: "speaker"}, []string{"speaker"}},
+		{"sensor", ProfilesConfig{Output: "speaker", Sensor: "noise", SensorKey: "db", Rules: []ProfileRule{{Above: 70, Profile: "factory_floor"}}}, []string{"speaker", "noise"}},
+		{"adaptive input", ProfilesConfig{Output: "speaker", AdaptiveVolume: &AdaptiveVolumeConfig{Input: "mic"}}, []string{"speaker", "mic"}},
+		{"no output", ProfilesConfig{}, nil},
+		{"unknown default", ProfilesConfig{Output: "speaker", Default: "library"}, nil},
+		{"bad eq", ProfilesConfig{Output: "speaker", Profiles: map[string]OutputProfile{"x": {EQ: []EQBand{{Type: "notch", FrequencyHz: 50}}}}}, nil},
+		{"expanding compressor", ProfilesConfig{Output: "speaker", Profiles: map[string]OutputProfile{"x": {Compressor: &CompressorConfig{Ratio: 0.5}}}}, nil},
+		{"sensor without key", ProfilesConfig{Output: "speaker", Sensor: "noise", Rules: []ProfileRule{{Profile: "quiet_office"}}}, nil},
+		{"sensor without rules", ProfilesConfig{Output: "speaker", Sensor: "noise", SensorKey: "db"}, nil},
+		{"rule with unknown profile", ProfilesConfig{Output: "speaker", Sensor: "noise", SensorKey: "db", Rules: []ProfileRule{{Profile: "library"}}}, nil},
+		{"bad adaptive volume", ProfilesConfig{Output: "speaker", AdaptiveVolume: &AdaptiveVolumeConfig{MaxStepDB: -1}}, nil},
+	} {
+		deps, _, err := tc.cfg.Validate("profiles")
+		if (err == nil) != (tc.deps != nil) {
+			t.Errorf("%s: got error %v", tc.name, err)
+			continue
+		}
+		if len(deps) != len(tc.deps) {
+			t.Errorf("%s: got dependencies %v, want %v", tc.name, deps, tc.deps)
+			continue
+		}
+		for i := range deps {
+			if deps[i] != tc.deps[i] {
+				t.Errorf("%s: got dependencies %v, want %v", tc.name, deps, tc.deps)
+			}
+		}
+	}
+}
+
+func TestRenderProfileNotification(t *testing.T) {
+	const rate, channels = 8000, 2
+	clip := make([]float32, 800*channels) // 100ms
+	for i := range clip {
+		clip[i] = 0.25
+	}
+	out, err := renderProfile(OutputProfile{
+		GainDB:       -6,
+		Notification: NotificationPattern{ChimeHz: 1000, Repeat: 2, GapMs: 50},
+	}, clip, rate, channels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// chime, gap, then the clip three times with gaps between
+	chimeLen, gap := defaultProfileChimeMs*rate/1000*channels, 400*channels
+	if want := chimeLen + gap + 3*len(clip) + 2*gap; len(out) != want {
+		t.Fatalf("rendered %d samples, want %d", len(out), want)
+	}
+	if dbfs(peak(out[:chimeLen])) < -20 || out[chimeLen+gap/2] != 0 {
+		t.Fatal("chime missing or gap not silent")
+	}
+	if got := out[chimeLen+gap]; math.Abs(float64(got)-0.25*math.Pow(10, -6.0/20)) > 1e-6 {
+		t.Fatalf("clip rendered at %v, want -6 dB", got)
+	}
+
+	loud := make([]float32, 800)
+	for i := range loud {
+		loud[i] = 0.9
+	}
+	out, err = renderProfile(OutputProfile{GainDB: 12}, loud, rate, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peak(out) > 1 {
+		t.Fatal("rendered output clipped past full scale")
+	}
+}
+
+// stepSensor hands out one reading per value sent on values.
+type stepSensor struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+	values chan float64
+}
+
+func (s *stepSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	select {
+	case v := <-s.values:
+		return map[string]interface{}{"db": v}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *stepSensor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return nil, resource.ErrDoUnimplemented
+}
+
+func TestProfilesFollowSensor(t *testing.T) {
+	s := &stepSensor{Named: sensor.Named("noise").AsNamed(), values: make(chan float64)}
+	a, err := NewProfiles(Named("profiles"), newBurstSource(0, AudioInfo{}), nil, s, ProfilesConfig{
+		SensorKey: "db",
+		Rules: []ProfileRule{
+			{Above: 80, Profile: "factory_floor"},
+			{Above: 60, Profile: "loud_office"},
+		},
+		Profiles:       map[string]OutputProfile{"loud_office": {GainDB: -3}},
+		Hysteresis:     5,
+		PollIntervalMs: 1,
+	}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	p := a.(*profiled)
+
+	for _, step := range []struct {
+		reading float64
+		want    string
+	}{
+		{40, "quiet_office"},
+		{65, "loud_office"},
+		{85, "factory_floor"},
+		{77, "factory_floor"}, // within the hysteresis
+		{74, "loud_office"},
+		{57, "loud_office"},
+		{50, "quiet_office"}, // below every rule goes back to the default
+	} {
+		s.values <- step.reading
+		// the reading has been acted on once the next one is asked for
+		s.values <- step.reading
+		status, err := p.GetProfile(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Profile != step.want || !status.Automatic {
+			t.Fatalf("at %g got %+v, want %s", step.reading, status, step.want)
+		}
+	}
+
+	if err := p.SetProfile(context.Background(), "factory_floor"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := p.GetProfile(context.Background()); status.Profile != "factory_floor" || status.Automatic {
+		t.Fatalf("set profile not used: %+v", status)
+	}
+	if err := p.SetProfile(context.Background(), "library"); err == nil {
+		t.Fatal("unknown profile set")
+	}
+	if err := p.SetProfile(context.Background(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := p.GetProfile(context.Background()); status.Profile != "quiet_office" || !status.Automatic {
+		t.Fatalf("clearing the profile didn't go back to the sensor: %+v", status)
+	}
+}
@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// PulseModel captures and plays through a PulseAudio server, or PipeWire's
+// PulseAudio service, on desktop Linux. Unlike the alsa model it shares the
+// devices with other applications and lets the sound server route it. It
+// needs a build with the pulse tag and libpulse-simple; other builds know
+// the model but fail to construct it.
+var PulseModel = resource.NewModel("olivia", "audio", "pulse")
+
+// Defaults for PulseConfig.
+const (
+	defaultPulseLatency = 40 * time.Millisecond
+	pulseDefaultSource  = "@DEFAULT_SOURCE@"
+	pulseDefaultSink    = "@DEFAULT_SINK@"
+)
+
+// PulseConfig is the configuration of the pulse model. Sources and sinks are
+// PulseAudio names as listed by pactl; empty ones follow the server's
+// default, including when it changes.
+type PulseConfig struct {
+	Server string `json:"server,omitempty"` // the user's server if empty
+	// ApplicationName is the application the streams belong to in the
+	// server's mixer, which remembers their routing per application.
+	// "viam-<resource name>" if empty.
+	ApplicationName  string `json:"application_name,omitempty"`
+	Source           string `json:"source,omitempty"`            // "none" turns capture off
+	Sink             string `json:"sink,omitempty"`              // "none" turns playback off
+	SampleRate       int    `json:"sample_rate,omitempty"`       // 48 kHz if zero
+	Channels         int    `json:"channels,omitempty"`          // capture channels, 1 if zero
+	PlaybackChannels int    `json:"playback_channels,omitempty"` // 2 if zero
+	LatencyMs        int    `json:"latency_ms,omitempty"`        // server side buffering, 40ms if zero
+}
+
+// Validate checks the pulse configuration.
+func (c *PulseConfig) Validate(path string) ([]string, []string, error) {
+	if c.SampleRate < 0 || c.Channels < 0 || c.PlaybackChannels < 0 || c.LatencyMs < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate, channels, playback_channels and latency_ms cannot be negative"))
+	}
+	if c.Source == noPCMDevice && c.Sink == noPCMDevice {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("source and sink cannot both be none"))
+	}
+	return nil, nil, nil
+}
+
+// dialPulse connects a stream to a PulseAudio server, set by builds with
+// PulseAudio support. An empty server is the user's.
+var dialPulse func(server, application, device string, capture bool, p pcmParams) (pcmDevice, error)
+
+func init() {
+	registerModel(PulseModel, resource.Registration[Audio, *PulseConfig]{
+		AttributeMapConverter: migratingConverter[*PulseConfig](PulseModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*PulseConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewPulse(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+// NewPulse returns a resource on the configured PulseAudio source and sink.
+// Streams are connected when they are first used and reconnected if the
+// server restarts.
+func NewPulse(name resource.Name, cfg PulseConfig, logger logging.Logger) (Audio, error) {
+	if dialPulse == nil {
+		return nil, errors.New("this build has no PulseAudio support, build with -tags pulse and libpulse")
+	}
+	rate := cfg.SampleRate
+	if rate == 0 {
+		rate = defaultPCMSampleRate
+	}
+	channels, playbackChannels := cfg.Channels, cfg.PlaybackChannels
+	if channels == 0 {
+		channels = defaultPCMChannels
+	}
+	if playbackChannels == 0 {
+		playbackChannels = defaultPCMPlaybackChannels
+	}
+	latency := time.Duration(cfg.LatencyMs) * time.Millisecond
+	if latency == 0 {
+		latency = defaultPulseLatency
+	}
+	buffer := max(4, int(latency.Seconds()*float64(rate)))
+	app := cfg.ApplicationName
+	if app == "" {
+		app = "viam-" + name.ShortName()
+	}
+
+	a := &pcmAudio{
+		Named:   name.AsNamed(),
+		backend: "pulse",
+		open: func(device string, capture bool, p pcmParams) (pcmDevice, error) {
+			return dialReconnecting(func() (pcmDevice, error) { return dialPulse(cfg.Server, app, device, capture, p) }, device, logger)
+		},
+		capture:        pulseDevice(cfg.Source, pulseDefaultSource),
+		playback:       pulseDevice(cfg.Sink, pulseDefaultSink),
+		captureParams:  pcmParams{rate: rate, channels: channels, periodFrames: buffer / 4, bufferFrames: buffer},
+		playbackParams: pcmParams{rate: rate, channels: playbackChannels, periodFrames: buffer / 4, bufferFrames: buffer},
+		logger:         logger,
+		readers:        map[chan pcmBlock]struct{}{},
+	}
+	return a, nil
+}
+
+// pulseDevice returns the device for one direction, empty for none.
+func pulseDevice(configured, def string) string {
+	switch configured {
+	case noPCMDevice:
+		return ""
+	case "":
+		return def
+	default:
+		return configured
+	}
+}
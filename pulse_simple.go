@@ -0,0 +1,98 @@
+//go:build pulse
+
+package audio
+
+/*
+#cgo LDFLAGS: -lpulse-simple -lpulse
+#include <stdlib.h>
+#include <pulse/simple.h>
+#include <pulse/error.h>
+*/
+import "C"
+
+import "unsafe"
+
+func init() {
+	dialPulse = dialPulseSimple
+}
+
+// pulseSimple is a stream on the blocking libpulse-simple API. It can't
+// recover from anything itself; reconnectingPCM dials it again.
+type pulseSimple struct {
+	s        *C.pa_simple
+	channels int
+}
+
+// pulseError is an error code from libpulse.
+type pulseError C.int
+
+func (e pulseError) Error() string {
+	return C.GoString(C.pa_strerror(C.int(e)))
+}
+
+func dialPulseSimple(server, application, device string, capture bool, p pcmParams) (pcmDevice, error) {
+	spec := C.pa_sample_spec{format: C.PA_SAMPLE_S16LE, rate: C.uint32_t(p.rate), channels: C.uint8_t(p.channels)}
+	frameBytes := C.uint32_t(2 * p.channels)
+	// -1 leaves a field to the server
+	attr := C.pa_buffer_attr{maxlength: ^C.uint32_t(0), tlength: ^C.uint32_t(0), prebuf: ^C.uint32_t(0), minreq: ^C.uint32_t(0), fragsize: ^C.uint32_t(0)}
+	dir := C.pa_stream_direction_t(C.PA_STREAM_PLAYBACK)
+	streamName := "playback"
+	if capture {
+		dir = C.PA_STREAM_RECORD
+		streamName = "capture"
+		attr.fragsize = C.uint32_t(p.periodFrames) * frameBytes
+	} else {
+		attr.tlength = C.uint32_t(p.bufferFrames) * frameBytes
+		attr.minreq = C.uint32_t(p.periodFrames) * frameBytes
+	}
+
+	var cserver *C.char
+	if server != "" {
+		cserver = C.CString(server)
+		defer C.free(unsafe.Pointer(cserver))
+	}
+	capp, cdev, cstream := C.CString(application), C.CString(device), C.CString(streamName)
+	defer C.free(unsafe.Pointer(capp))
+	defer C.free(unsafe.Pointer(cdev))
+	defer C.free(unsafe.Pointer(cstream))
+
+	var code C.int
+	s := C.pa_simple_new(cserver, capp, dir, cdev, cstream, &spec, nil, &attr, &code)
+	if s == nil {
+		return nil, pulseError(code)
+	}
+	return &pulseSimple{s: s, channels: p.channels}, nil
+}
+
+func (p *pulseSimple) read(buf []int16) (int, error) {
+	var code C.int
+	if C.pa_simple_read(p.s, unsafe.Pointer(&buf[0]), C.size_t(2*len(buf)), &code) < 0 {
+		return 0, pulseError(code)
+	}
+	return len(buf) / p.channels, nil
+}
+
+func (p *pulseSimple) write(buf []int16) (int, error) {
+	var code C.int
+	if C.pa_simple_write(p.s, unsafe.Pointer(&buf[0]), C.size_t(2*len(buf)), &code) < 0 {
+		return 0, pulseError(code)
+	}
+	return len(buf) / p.channels, nil
+}
+
+func (p *pulseSimple) recover(err error) error {
+	return err
+}
+
+func (p *pulseSimple) drain() error {
+	var code C.int
+	if C.pa_simple_drain(p.s, &code) < 0 {
+		return pulseError(code)
+	}
+	return nil
+}
+
+func (p *pulseSimple) close() error {
+	C.pa_simple_free(p.s)
+	return nil
+}
@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestPulseSurvivesServerRestart(t *testing.T) {
+	var mu sync.Mutex
+	var dials []string
+	prev := dialPulse
+	dialPulse = func(server, application, device string, capture bool, p pcmParams) (pcmDevice, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dials = append(dials, application+" "+device)
+		switch len(dials) {
+		case 1:
+			// the server goes away during the third read
+			return &fakePCM{p: p, period: 10 * time.Millisecond, fail: map[int]bool{3: true}, lost: true}, nil
+		case 2:
+			return nil, errors.New("connection refused")
+		default:
+			return &fakePCM{p: p, period: 10 * time.Millisecond, fail: map[int]bool{}}, nil
+		}
+	}
+	defer func() { dialPulse = prev }()
+
+	a, err := NewPulse(Named("mic"), PulseConfig{Sink: "none", SampleRate: 8000, LatencyMs: 40}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), 0.05, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	var gap time.Duration
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatal(c.Err)
+		}
+		gap += c.Gap
+		n++
+	}
+	if n != 5 {
+		t.Fatalf("got %d chunks, want 5", n)
+	}
+	// a lost read, a failed dial and the wait before the next
+	if gap < redialWait {
+		t.Errorf("restart left a %v gap, want at least %v", gap, redialWait)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(dials) != 3 || dials[2] != "viam-mic "+pulseDefaultSource {
+		t.Errorf("dialed %q, want the default source three times", dials)
+	}
+}
+
+func TestPulseConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg PulseConfig
+		ok  bool
+	}{
+		{PulseConfig{}, true},
+		{PulseConfig{Source: "alsa_input.usb-mic", Sink: "none", LatencyMs: 20}, true},
+		{PulseConfig{Source: "none", Sink: "none"}, false},
+		{PulseConfig{LatencyMs: -1}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+}
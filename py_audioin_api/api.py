@@ -1,9 +1,14 @@
 import abc
+import json
+import zlib
 from typing import Sequence
 
 from grpclib.client import Channel
+from grpclib.const import Status
+from grpclib.exceptions import GRPCError
 from grpclib.server import Stream
 
+from viam.logging import getLogger
 from viam.resource.types import RESOURCE_TYPE_COMPONENT, API
 from viam.components.component_base import ComponentBase
 from viam.resource.rpc_service_base import ResourceRPCServiceBase
@@ -16,13 +21,85 @@ from .grpc.audio_pb2 import (
     AudioInfo,
     PlayResponse,
     PropertiesRequest,
-    PropertiesResponse
-
-
+    PropertiesResponse,
+    PauseStreamRequest,
+    PauseStreamResponse,
+    ResumeStreamRequest,
+    ResumeStreamResponse,
+    SetProfileRequest,
+    SetProfileResponse,
+    GetProfileRequest,
+    GetProfileResponse,
+    PreparePlaybackRequest,
+    PreparePlaybackResponse,
+    CommitPlaybackRequest,
+    CommitPlaybackResponse,
+    ReleasePlaybackRequest,
+    ReleasePlaybackResponse,
+    SetEQRequest,
+    SetEQResponse,
+    GetEQRequest,
+    GetEQResponse,
+    GetLevelsRequest,
+    GetLevelsResponse,
+    StreamSpectrumRequest,
+    SpectrumFrame,
+    GetSpectrogramRequest,
+    GetSpectrogramResponse,
+    CaptureClipRequest,
+    CaptureClipResponse,
+    StreamImpulsesRequest,
+    ImpulseEvent,
+    GetLevelStatsRequest,
+    GetLevelStatsResponse,
+    ListHistoryRequest,
+    ListStreamHistoryResponse,
+    ListEventsResponse,
+    ListActiveStreamsRequest,
+    ListActiveStreamsResponse,
+    ListRecordingsRequest,
+    ListRecordingsResponse,
+    GetRecordingStatsRequest,
+    GetRecordingStatsResponse,
+    GetRecordingRequest,
+    GetRecordingResponse,
+    StreamRecordingRequest,
+    StartRecordingRequest,
+    StartRecordingResponse,
+    StopRecordingRequest,
+    StopRecordingResponse,
+    ListSegmentsRequest,
+    ListSegmentsResponse,
+    StartRecordingSessionRequest,
+    StartRecordingSessionResponse,
+    StopRecordingSessionRequest,
+    StopRecordingSessionResponse,
+    GetRecordingSessionRequest,
+    GetRecordingSessionResponse,
+    ScheduleRecordingRequest,
+    ScheduleRecordingResponse,
+    CancelScheduledRecordingRequest,
+    CancelScheduledRecordingResponse,
+    GetTriggerStateRequest,
+    GetTriggerStateResponse,
+    ListDevicesRequest,
+    ListDevicesResponse,
+    GetLatencyStatsRequest,
+    GetLatencyStatsResponse,
+    GetStatsRequest,
+    GetStatsResponse,
+    GetHealthRequest,
+    GetHealthResponse,
+    ListAuditLogRequest,
+    ListAuditLogResponse,
+    DoCommandRequest,
+    DoCommandResponse,
 )
 
 from viam.streams import StreamWithIterator
 
+LOGGER = getLogger(__name__)
+
 AudioStream = Stream[AudioChunk]
 
 class Audio(ComponentBase):
@@ -48,6 +125,139 @@ class AudioRPCService(AudioServiceBase, ResourceRPCServiceBase):
     async def Properties(self, stream: Stream[PropertiesRequest, PropertiesResponse]):
         return await super().Properties(stream)
 
+    # GetAudio streams aren't served from python yet, so there is nothing to pause or resume
+    async def PauseStream(self, stream: Stream[PauseStreamRequest, PauseStreamResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "PauseStream is not supported by python audio resources")
+
+    async def ResumeStream(self, stream: Stream[ResumeStreamRequest, ResumeStreamResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ResumeStream is not supported by python audio resources")
+
+    # output profiles live in the go processing chain
+    async def SetProfile(self, stream: Stream[SetProfileRequest, SetProfileResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "SetProfile is not supported by python audio resources")
+
+    async def GetProfile(self, stream: Stream[GetProfileRequest, GetProfileResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetProfile is not supported by python audio resources")
+
+    # clips can't be prepared ahead of time without a resource side api for it
+    async def PreparePlayback(self, stream: Stream[PreparePlaybackRequest, PreparePlaybackResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "PreparePlayback is not supported by python audio resources")
+
+    async def CommitPlayback(self, stream: Stream[CommitPlaybackRequest, CommitPlaybackResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "CommitPlayback is not supported by python audio resources")
+
+    async def ReleasePlayback(self, stream: Stream[ReleasePlaybackRequest, ReleasePlaybackResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ReleasePlayback is not supported by python audio resources")
+
+    # the eq chain lives in the go processing chain
+    async def SetEQ(self, stream: Stream[SetEQRequest, SetEQResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "SetEQ is not supported by python audio resources")
+
+    async def GetEQ(self, stream: Stream[GetEQRequest, GetEQResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetEQ is not supported by python audio resources")
+
+    # levels are metered from the go capture hub
+    async def GetLevels(self, stream: Stream[GetLevelsRequest, GetLevelsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetLevels is not supported by python audio resources")
+
+    async def StreamLevels(self, stream: Stream[GetLevelsRequest, GetLevelsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "StreamLevels is not supported by python audio resources")
+
+    # spectra are computed from the go capture hub
+    async def StreamSpectrum(self, stream: Stream[StreamSpectrumRequest, SpectrumFrame]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "StreamSpectrum is not supported by python audio resources")
+
+    # loop recordings are kept by the go server
+    async def GetSpectrogram(self, stream: Stream[GetSpectrogramRequest, GetSpectrogramResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetSpectrogram is not supported by python audio resources")
+
+    # pre-roll is kept by the go clip model
+    async def CaptureClip(self, stream: Stream[CaptureClipRequest, CaptureClipResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "CaptureClip is not supported by python audio resources")
+
+    # impulses are detected on the go capture hub
+    async def StreamImpulses(self, stream: Stream[StreamImpulsesRequest, ImpulseEvent]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "StreamImpulses is not supported by python audio resources")
+
+    # level statistics are kept by the go server
+    async def GetLevelStats(self, stream: Stream[GetLevelStatsRequest, GetLevelStatsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetLevelStats is not supported by python audio resources")
+
+    # stream and event history is kept by the go server
+    async def ListStreamHistory(self, stream: Stream[ListHistoryRequest, ListStreamHistoryResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ListStreamHistory is not supported by python audio resources")
+
+    async def ListEvents(self, stream: Stream[ListHistoryRequest, ListEventsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ListEvents is not supported by python audio resources")
+
+    # active streams, the recording store, recordings and devices are the go server's
+    async def ListActiveStreams(self, stream: Stream[ListActiveStreamsRequest, ListActiveStreamsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ListActiveStreams is not supported by python audio resources")
+
+    async def ListRecordings(self, stream: Stream[ListRecordingsRequest, ListRecordingsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ListRecordings is not supported by python audio resources")
+
+    async def GetRecordingStats(self, stream: Stream[GetRecordingStatsRequest, GetRecordingStatsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetRecordingStats is not supported by python audio resources")
+
+    async def GetRecording(self, stream: Stream[GetRecordingRequest, GetRecordingResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetRecording is not supported by python audio resources")
+
+    async def StreamRecording(self, stream: Stream[StreamRecordingRequest, AudioChunk]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "StreamRecording is not supported by python audio resources")
+
+    async def StartRecording(self, stream: Stream[StartRecordingRequest, StartRecordingResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "StartRecording is not supported by python audio resources")
+
+    async def StopRecording(self, stream: Stream[StopRecordingRequest, StopRecordingResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "StopRecording is not supported by python audio resources")
+
+    async def ListSegments(self, stream: Stream[ListSegmentsRequest, ListSegmentsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ListSegments is not supported by python audio resources")
+
+    async def StartRecordingSession(self, stream: Stream[StartRecordingSessionRequest, StartRecordingSessionResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "StartRecordingSession is not supported by python audio resources")
+
+    async def StopRecordingSession(self, stream: Stream[StopRecordingSessionRequest, StopRecordingSessionResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "StopRecordingSession is not supported by python audio resources")
+
+    async def GetRecordingSession(self, stream: Stream[GetRecordingSessionRequest, GetRecordingSessionResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetRecordingSession is not supported by python audio resources")
+
+    async def ScheduleRecording(self, stream: Stream[ScheduleRecordingRequest, ScheduleRecordingResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ScheduleRecording is not supported by python audio resources")
+
+    async def CancelScheduledRecording(self, stream: Stream[CancelScheduledRecordingRequest, CancelScheduledRecordingResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "CancelScheduledRecording is not supported by python audio resources")
+
+    async def GetTriggerState(self, stream: Stream[GetTriggerStateRequest, GetTriggerStateResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetTriggerState is not supported by python audio resources")
+
+    async def ListDevices(self, stream: Stream[ListDevicesRequest, ListDevicesResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ListDevices is not supported by python audio resources")
+
+    # latency is measured by the go server's GetAudio
+    async def GetLatencyStats(self, stream: Stream[GetLatencyStatsRequest, GetLatencyStatsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetLatencyStats is not supported by python audio resources")
+
+    # stats are counted by the go server
+    async def GetStats(self, stream: Stream[GetStatsRequest, GetStatsResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetStats is not supported by python audio resources")
+
+    async def GetHealth(self, stream: Stream[GetHealthRequest, GetHealthResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "GetHealth is not supported by python audio resources")
+
+    async def ListAuditLog(self, stream: Stream[ListAuditLogRequest, ListAuditLogResponse]) -> None:
+        raise GRPCError(Status.UNIMPLEMENTED, "ListAuditLog is not supported by python audio resources")
+
+    # dump_state, list_sessions and reset_device are the go server's; other commands go to the resource
+    async def DoCommand(self, stream: Stream[DoCommandRequest, DoCommandResponse]) -> None:
+        request = await stream.recv_message()
+        assert request is not None
+        resource = self.get_resource(request.name)
+        result = await resource.do_command(json.loads(request.command_json))
+        await stream.send_message(DoCommandResponse(result_json=json.dumps(result)))
+
 
 class AudioClient(Audio):
     def __init__(self, name: str, channel: Channel) -> None:
@@ -55,15 +265,32 @@ class AudioClient(Audio):
         self.client = AudioServiceStub(channel)
         super().__init__(name)
 
-    async def get_audio(self, durationSeconds: int, codec:str, maxDurationSeconds: int, previousTimestamp:int) -> AudioStream:
-        request = GetAudioRequest(name=self.name, duration_seconds=durationSeconds, codec = codec, max_duration_seconds= maxDurationSeconds, previous_timestamp = previousTimestamp)
+    # previousTimestamp is unused; resume a dropped resumable stream with the resume_token of its last chunk
+    async def get_audio(self, durationSeconds: int, codec:str, maxDurationSeconds: int, previousTimestamp:int = 0, sample_rate: int = 0, num_channels: int = 0, request_id: str = "", only_when: Sequence[str] = (), pre_roll_seconds: float = 0, post_roll_seconds: float = 0, resumable: bool = False, resume_token: str = "", chunk_duration_seconds: float = 0, drop_policy: str = "", buffer_chunks: int = 0, compression: str = "", batch_chunks: int = 0, batch_max_delay_seconds: float = 0, adaptive_bitrate: bool = False, min_bitrate_kbps: int = 0, max_bitrate_kbps: int = 0, heartbeat_seconds: float = 0, checksums: bool = False) -> AudioStream:
+        request = GetAudioRequest(name=self.name, duration_seconds=durationSeconds, codec = codec, max_duration_seconds= maxDurationSeconds, sample_rate = sample_rate, num_channels = num_channels, request_id = request_id, only_when = only_when, pre_roll_seconds = pre_roll_seconds, post_roll_seconds = post_roll_seconds, resumable = resumable, resume_token = resume_token, chunk_duration_seconds = chunk_duration_seconds, drop_policy = drop_policy, buffer_chunks = buffer_chunks, compression = compression, batch_chunks = batch_chunks, batch_max_delay_seconds = batch_max_delay_seconds, adaptive_bitrate = adaptive_bitrate, min_bitrate_kbps = min_bitrate_kbps, max_bitrate_kbps = max_bitrate_kbps, heartbeat_seconds = heartbeat_seconds, checksums = checksums)
+        # with checksums, a chunk whose audio was corrupted on the way ends the stream rather than play as noise
+        def verified(chunk: AudioChunk) -> AudioChunk:
+            if checksums and not chunk.HasField("crc32"):
+                raise GRPCError(Status.UNIMPLEMENTED, f"the server sent chunk {chunk.sequence} without a checksum")
+            if checksums and zlib.crc32(chunk.audio_data) != chunk.crc32:
+                raise GRPCError(Status.DATA_LOSS, f"chunk {chunk.sequence} doesn't match its checksum")
+            return chunk
+
         async def read():
             audio_stream: Stream[GetAudioRequest, AudioChunk]
             async with self.client.GetAudio.open() as audio_stream:
                 try:
                     await audio_stream.send_message(request, end=True)
                     async for audioChunk in audio_stream:
-                        yield audioChunk
+                        # heartbeats only keep the connection open
+                        if audioChunk.heartbeat:
+                            continue
+                        # batched chunks come out one by one, as they would unbatched
+                        if audioChunk.batch:
+                            for chunk in audioChunk.batch:
+                                yield verified(chunk)
+                        else:
+                            yield verified(audioChunk)
                 except Exception as e:
                     raise (e)
 
@@ -83,7 +310,11 @@ class AudioClient(Audio):
             info=audio_info
         )
 
-        print("Sending play request with audio info")
+        LOGGER.debug("sending play request to %s: %s", self.name, audio_info)
         await self.client.Play(request)
 
+    async def pause_stream(self, request_id: str):
+        await self.client.PauseStream(PauseStreamRequest(name=self.name, request_id=request_id))
 
+    async def resume_stream(self, request_id: str):
+        await self.client.ResumeStream(ResumeStreamRequest(name=self.name, request_id=request_id))
@@ -23,6 +23,158 @@ class AudioServiceBase(abc.ABC):
     async def Play(self, stream: 'grpclib.server.Stream[audio_pb2.PlayRequest, audio_pb2.PlayResponse]') -> None:
         pass
 
+    @abc.abstractmethod
+    async def PauseStream(self, stream: 'grpclib.server.Stream[audio_pb2.PauseStreamRequest, audio_pb2.PauseStreamResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ResumeStream(self, stream: 'grpclib.server.Stream[audio_pb2.ResumeStreamRequest, audio_pb2.ResumeStreamResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def PreparePlayback(self, stream: 'grpclib.server.Stream[audio_pb2.PreparePlaybackRequest, audio_pb2.PreparePlaybackResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def CommitPlayback(self, stream: 'grpclib.server.Stream[audio_pb2.CommitPlaybackRequest, audio_pb2.CommitPlaybackResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ReleasePlayback(self, stream: 'grpclib.server.Stream[audio_pb2.ReleasePlaybackRequest, audio_pb2.ReleasePlaybackResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def SetProfile(self, stream: 'grpclib.server.Stream[audio_pb2.SetProfileRequest, audio_pb2.SetProfileResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetProfile(self, stream: 'grpclib.server.Stream[audio_pb2.GetProfileRequest, audio_pb2.GetProfileResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def SetEQ(self, stream: 'grpclib.server.Stream[audio_pb2.SetEQRequest, audio_pb2.SetEQResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetEQ(self, stream: 'grpclib.server.Stream[audio_pb2.GetEQRequest, audio_pb2.GetEQResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetLevels(self, stream: 'grpclib.server.Stream[audio_pb2.GetLevelsRequest, audio_pb2.GetLevelsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def StreamLevels(self, stream: 'grpclib.server.Stream[audio_pb2.GetLevelsRequest, audio_pb2.GetLevelsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def StreamSpectrum(self, stream: 'grpclib.server.Stream[audio_pb2.StreamSpectrumRequest, audio_pb2.SpectrumFrame]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetSpectrogram(self, stream: 'grpclib.server.Stream[audio_pb2.GetSpectrogramRequest, audio_pb2.GetSpectrogramResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def CaptureClip(self, stream: 'grpclib.server.Stream[audio_pb2.CaptureClipRequest, audio_pb2.CaptureClipResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def StreamImpulses(self, stream: 'grpclib.server.Stream[audio_pb2.StreamImpulsesRequest, audio_pb2.ImpulseEvent]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetLevelStats(self, stream: 'grpclib.server.Stream[audio_pb2.GetLevelStatsRequest, audio_pb2.GetLevelStatsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ListStreamHistory(self, stream: 'grpclib.server.Stream[audio_pb2.ListHistoryRequest, audio_pb2.ListStreamHistoryResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ListEvents(self, stream: 'grpclib.server.Stream[audio_pb2.ListHistoryRequest, audio_pb2.ListEventsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ListAuditLog(self, stream: 'grpclib.server.Stream[audio_pb2.ListAuditLogRequest, audio_pb2.ListAuditLogResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ListActiveStreams(self, stream: 'grpclib.server.Stream[audio_pb2.ListActiveStreamsRequest, audio_pb2.ListActiveStreamsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ListRecordings(self, stream: 'grpclib.server.Stream[audio_pb2.ListRecordingsRequest, audio_pb2.ListRecordingsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetRecordingStats(self, stream: 'grpclib.server.Stream[audio_pb2.GetRecordingStatsRequest, audio_pb2.GetRecordingStatsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetRecording(self, stream: 'grpclib.server.Stream[audio_pb2.GetRecordingRequest, audio_pb2.GetRecordingResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def StreamRecording(self, stream: 'grpclib.server.Stream[audio_pb2.StreamRecordingRequest, audio_pb2.AudioChunk]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def StartRecording(self, stream: 'grpclib.server.Stream[audio_pb2.StartRecordingRequest, audio_pb2.StartRecordingResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def StopRecording(self, stream: 'grpclib.server.Stream[audio_pb2.StopRecordingRequest, audio_pb2.StopRecordingResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ListSegments(self, stream: 'grpclib.server.Stream[audio_pb2.ListSegmentsRequest, audio_pb2.ListSegmentsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def StartRecordingSession(self, stream: 'grpclib.server.Stream[audio_pb2.StartRecordingSessionRequest, audio_pb2.StartRecordingSessionResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def StopRecordingSession(self, stream: 'grpclib.server.Stream[audio_pb2.StopRecordingSessionRequest, audio_pb2.StopRecordingSessionResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetRecordingSession(self, stream: 'grpclib.server.Stream[audio_pb2.GetRecordingSessionRequest, audio_pb2.GetRecordingSessionResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ScheduleRecording(self, stream: 'grpclib.server.Stream[audio_pb2.ScheduleRecordingRequest, audio_pb2.ScheduleRecordingResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def CancelScheduledRecording(self, stream: 'grpclib.server.Stream[audio_pb2.CancelScheduledRecordingRequest, audio_pb2.CancelScheduledRecordingResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetTriggerState(self, stream: 'grpclib.server.Stream[audio_pb2.GetTriggerStateRequest, audio_pb2.GetTriggerStateResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def ListDevices(self, stream: 'grpclib.server.Stream[audio_pb2.ListDevicesRequest, audio_pb2.ListDevicesResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetLatencyStats(self, stream: 'grpclib.server.Stream[audio_pb2.GetLatencyStatsRequest, audio_pb2.GetLatencyStatsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetStats(self, stream: 'grpclib.server.Stream[audio_pb2.GetStatsRequest, audio_pb2.GetStatsResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def GetHealth(self, stream: 'grpclib.server.Stream[audio_pb2.GetHealthRequest, audio_pb2.GetHealthResponse]') -> None:
+        pass
+
+    @abc.abstractmethod
+    async def DoCommand(self, stream: 'grpclib.server.Stream[audio_pb2.DoCommandRequest, audio_pb2.DoCommandResponse]') -> None:
+        pass
+
     @abc.abstractmethod
     async def Properties(self, stream: 'grpclib.server.Stream[audio_pb2.PropertiesRequest, audio_pb2.PropertiesResponse]') -> None:
         pass
@@ -41,6 +193,234 @@ class AudioServiceBase(abc.ABC):
                 audio_pb2.PlayRequest,
                 audio_pb2.PlayResponse,
             ),
+            '/AudioService/PauseStream': grpclib.const.Handler(
+                self.PauseStream,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.PauseStreamRequest,
+                audio_pb2.PauseStreamResponse,
+            ),
+            '/AudioService/ResumeStream': grpclib.const.Handler(
+                self.ResumeStream,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ResumeStreamRequest,
+                audio_pb2.ResumeStreamResponse,
+            ),
+            '/AudioService/PreparePlayback': grpclib.const.Handler(
+                self.PreparePlayback,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.PreparePlaybackRequest,
+                audio_pb2.PreparePlaybackResponse,
+            ),
+            '/AudioService/CommitPlayback': grpclib.const.Handler(
+                self.CommitPlayback,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.CommitPlaybackRequest,
+                audio_pb2.CommitPlaybackResponse,
+            ),
+            '/AudioService/ReleasePlayback': grpclib.const.Handler(
+                self.ReleasePlayback,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ReleasePlaybackRequest,
+                audio_pb2.ReleasePlaybackResponse,
+            ),
+            '/AudioService/SetProfile': grpclib.const.Handler(
+                self.SetProfile,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.SetProfileRequest,
+                audio_pb2.SetProfileResponse,
+            ),
+            '/AudioService/GetProfile': grpclib.const.Handler(
+                self.GetProfile,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetProfileRequest,
+                audio_pb2.GetProfileResponse,
+            ),
+            '/AudioService/SetEQ': grpclib.const.Handler(
+                self.SetEQ,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.SetEQRequest,
+                audio_pb2.SetEQResponse,
+            ),
+            '/AudioService/GetEQ': grpclib.const.Handler(
+                self.GetEQ,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetEQRequest,
+                audio_pb2.GetEQResponse,
+            ),
+            '/AudioService/GetLevels': grpclib.const.Handler(
+                self.GetLevels,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetLevelsRequest,
+                audio_pb2.GetLevelsResponse,
+            ),
+            '/AudioService/StreamLevels': grpclib.const.Handler(
+                self.StreamLevels,
+                grpclib.const.Cardinality.UNARY_STREAM,
+                audio_pb2.GetLevelsRequest,
+                audio_pb2.GetLevelsResponse,
+            ),
+            '/AudioService/StreamSpectrum': grpclib.const.Handler(
+                self.StreamSpectrum,
+                grpclib.const.Cardinality.UNARY_STREAM,
+                audio_pb2.StreamSpectrumRequest,
+                audio_pb2.SpectrumFrame,
+            ),
+            '/AudioService/GetSpectrogram': grpclib.const.Handler(
+                self.GetSpectrogram,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetSpectrogramRequest,
+                audio_pb2.GetSpectrogramResponse,
+            ),
+            '/AudioService/CaptureClip': grpclib.const.Handler(
+                self.CaptureClip,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.CaptureClipRequest,
+                audio_pb2.CaptureClipResponse,
+            ),
+            '/AudioService/StreamImpulses': grpclib.const.Handler(
+                self.StreamImpulses,
+                grpclib.const.Cardinality.UNARY_STREAM,
+                audio_pb2.StreamImpulsesRequest,
+                audio_pb2.ImpulseEvent,
+            ),
+            '/AudioService/GetLevelStats': grpclib.const.Handler(
+                self.GetLevelStats,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetLevelStatsRequest,
+                audio_pb2.GetLevelStatsResponse,
+            ),
+            '/AudioService/ListStreamHistory': grpclib.const.Handler(
+                self.ListStreamHistory,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ListHistoryRequest,
+                audio_pb2.ListStreamHistoryResponse,
+            ),
+            '/AudioService/ListEvents': grpclib.const.Handler(
+                self.ListEvents,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ListHistoryRequest,
+                audio_pb2.ListEventsResponse,
+            ),
+            '/AudioService/ListAuditLog': grpclib.const.Handler(
+                self.ListAuditLog,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ListAuditLogRequest,
+                audio_pb2.ListAuditLogResponse,
+            ),
+            '/AudioService/ListActiveStreams': grpclib.const.Handler(
+                self.ListActiveStreams,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ListActiveStreamsRequest,
+                audio_pb2.ListActiveStreamsResponse,
+            ),
+            '/AudioService/ListRecordings': grpclib.const.Handler(
+                self.ListRecordings,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ListRecordingsRequest,
+                audio_pb2.ListRecordingsResponse,
+            ),
+            '/AudioService/GetRecordingStats': grpclib.const.Handler(
+                self.GetRecordingStats,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetRecordingStatsRequest,
+                audio_pb2.GetRecordingStatsResponse,
+            ),
+            '/AudioService/GetRecording': grpclib.const.Handler(
+                self.GetRecording,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetRecordingRequest,
+                audio_pb2.GetRecordingResponse,
+            ),
+            '/AudioService/StreamRecording': grpclib.const.Handler(
+                self.StreamRecording,
+                grpclib.const.Cardinality.UNARY_STREAM,
+                audio_pb2.StreamRecordingRequest,
+                audio_pb2.AudioChunk,
+            ),
+            '/AudioService/StartRecording': grpclib.const.Handler(
+                self.StartRecording,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.StartRecordingRequest,
+                audio_pb2.StartRecordingResponse,
+            ),
+            '/AudioService/StopRecording': grpclib.const.Handler(
+                self.StopRecording,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.StopRecordingRequest,
+                audio_pb2.StopRecordingResponse,
+            ),
+            '/AudioService/ListSegments': grpclib.const.Handler(
+                self.ListSegments,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ListSegmentsRequest,
+                audio_pb2.ListSegmentsResponse,
+            ),
+            '/AudioService/StartRecordingSession': grpclib.const.Handler(
+                self.StartRecordingSession,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.StartRecordingSessionRequest,
+                audio_pb2.StartRecordingSessionResponse,
+            ),
+            '/AudioService/StopRecordingSession': grpclib.const.Handler(
+                self.StopRecordingSession,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.StopRecordingSessionRequest,
+                audio_pb2.StopRecordingSessionResponse,
+            ),
+            '/AudioService/GetRecordingSession': grpclib.const.Handler(
+                self.GetRecordingSession,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetRecordingSessionRequest,
+                audio_pb2.GetRecordingSessionResponse,
+            ),
+            '/AudioService/ScheduleRecording': grpclib.const.Handler(
+                self.ScheduleRecording,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ScheduleRecordingRequest,
+                audio_pb2.ScheduleRecordingResponse,
+            ),
+            '/AudioService/CancelScheduledRecording': grpclib.const.Handler(
+                self.CancelScheduledRecording,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.CancelScheduledRecordingRequest,
+                audio_pb2.CancelScheduledRecordingResponse,
+            ),
+            '/AudioService/GetTriggerState': grpclib.const.Handler(
+                self.GetTriggerState,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetTriggerStateRequest,
+                audio_pb2.GetTriggerStateResponse,
+            ),
+            '/AudioService/ListDevices': grpclib.const.Handler(
+                self.ListDevices,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.ListDevicesRequest,
+                audio_pb2.ListDevicesResponse,
+            ),
+            '/AudioService/GetLatencyStats': grpclib.const.Handler(
+                self.GetLatencyStats,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetLatencyStatsRequest,
+                audio_pb2.GetLatencyStatsResponse,
+            ),
+            '/AudioService/GetStats': grpclib.const.Handler(
+                self.GetStats,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetStatsRequest,
+                audio_pb2.GetStatsResponse,
+            ),
+            '/AudioService/GetHealth': grpclib.const.Handler(
+                self.GetHealth,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.GetHealthRequest,
+                audio_pb2.GetHealthResponse,
+            ),
+            '/AudioService/DoCommand': grpclib.const.Handler(
+                self.DoCommand,
+                grpclib.const.Cardinality.UNARY_UNARY,
+                audio_pb2.DoCommandRequest,
+                audio_pb2.DoCommandResponse,
+            ),
             '/AudioService/Properties': grpclib.const.Handler(
                 self.Properties,
                 grpclib.const.Cardinality.UNARY_UNARY,
@@ -65,6 +445,234 @@ class AudioServiceStub:
             audio_pb2.PlayRequest,
             audio_pb2.PlayResponse,
         )
+        self.PauseStream = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/PauseStream',
+            audio_pb2.PauseStreamRequest,
+            audio_pb2.PauseStreamResponse,
+        )
+        self.ResumeStream = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ResumeStream',
+            audio_pb2.ResumeStreamRequest,
+            audio_pb2.ResumeStreamResponse,
+        )
+        self.PreparePlayback = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/PreparePlayback',
+            audio_pb2.PreparePlaybackRequest,
+            audio_pb2.PreparePlaybackResponse,
+        )
+        self.CommitPlayback = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/CommitPlayback',
+            audio_pb2.CommitPlaybackRequest,
+            audio_pb2.CommitPlaybackResponse,
+        )
+        self.ReleasePlayback = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ReleasePlayback',
+            audio_pb2.ReleasePlaybackRequest,
+            audio_pb2.ReleasePlaybackResponse,
+        )
+        self.SetProfile = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/SetProfile',
+            audio_pb2.SetProfileRequest,
+            audio_pb2.SetProfileResponse,
+        )
+        self.GetProfile = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetProfile',
+            audio_pb2.GetProfileRequest,
+            audio_pb2.GetProfileResponse,
+        )
+        self.SetEQ = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/SetEQ',
+            audio_pb2.SetEQRequest,
+            audio_pb2.SetEQResponse,
+        )
+        self.GetEQ = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetEQ',
+            audio_pb2.GetEQRequest,
+            audio_pb2.GetEQResponse,
+        )
+        self.GetLevels = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetLevels',
+            audio_pb2.GetLevelsRequest,
+            audio_pb2.GetLevelsResponse,
+        )
+        self.StreamLevels = grpclib.client.UnaryStreamMethod(
+            channel,
+            '/AudioService/StreamLevels',
+            audio_pb2.GetLevelsRequest,
+            audio_pb2.GetLevelsResponse,
+        )
+        self.StreamSpectrum = grpclib.client.UnaryStreamMethod(
+            channel,
+            '/AudioService/StreamSpectrum',
+            audio_pb2.StreamSpectrumRequest,
+            audio_pb2.SpectrumFrame,
+        )
+        self.GetSpectrogram = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetSpectrogram',
+            audio_pb2.GetSpectrogramRequest,
+            audio_pb2.GetSpectrogramResponse,
+        )
+        self.CaptureClip = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/CaptureClip',
+            audio_pb2.CaptureClipRequest,
+            audio_pb2.CaptureClipResponse,
+        )
+        self.StreamImpulses = grpclib.client.UnaryStreamMethod(
+            channel,
+            '/AudioService/StreamImpulses',
+            audio_pb2.StreamImpulsesRequest,
+            audio_pb2.ImpulseEvent,
+        )
+        self.GetLevelStats = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetLevelStats',
+            audio_pb2.GetLevelStatsRequest,
+            audio_pb2.GetLevelStatsResponse,
+        )
+        self.ListStreamHistory = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ListStreamHistory',
+            audio_pb2.ListHistoryRequest,
+            audio_pb2.ListStreamHistoryResponse,
+        )
+        self.ListEvents = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ListEvents',
+            audio_pb2.ListHistoryRequest,
+            audio_pb2.ListEventsResponse,
+        )
+        self.ListAuditLog = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ListAuditLog',
+            audio_pb2.ListAuditLogRequest,
+            audio_pb2.ListAuditLogResponse,
+        )
+        self.ListActiveStreams = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ListActiveStreams',
+            audio_pb2.ListActiveStreamsRequest,
+            audio_pb2.ListActiveStreamsResponse,
+        )
+        self.ListRecordings = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ListRecordings',
+            audio_pb2.ListRecordingsRequest,
+            audio_pb2.ListRecordingsResponse,
+        )
+        self.GetRecordingStats = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetRecordingStats',
+            audio_pb2.GetRecordingStatsRequest,
+            audio_pb2.GetRecordingStatsResponse,
+        )
+        self.GetRecording = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetRecording',
+            audio_pb2.GetRecordingRequest,
+            audio_pb2.GetRecordingResponse,
+        )
+        self.StreamRecording = grpclib.client.UnaryStreamMethod(
+            channel,
+            '/AudioService/StreamRecording',
+            audio_pb2.StreamRecordingRequest,
+            audio_pb2.AudioChunk,
+        )
+        self.StartRecording = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/StartRecording',
+            audio_pb2.StartRecordingRequest,
+            audio_pb2.StartRecordingResponse,
+        )
+        self.StopRecording = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/StopRecording',
+            audio_pb2.StopRecordingRequest,
+            audio_pb2.StopRecordingResponse,
+        )
+        self.ListSegments = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ListSegments',
+            audio_pb2.ListSegmentsRequest,
+            audio_pb2.ListSegmentsResponse,
+        )
+        self.StartRecordingSession = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/StartRecordingSession',
+            audio_pb2.StartRecordingSessionRequest,
+            audio_pb2.StartRecordingSessionResponse,
+        )
+        self.StopRecordingSession = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/StopRecordingSession',
+            audio_pb2.StopRecordingSessionRequest,
+            audio_pb2.StopRecordingSessionResponse,
+        )
+        self.GetRecordingSession = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetRecordingSession',
+            audio_pb2.GetRecordingSessionRequest,
+            audio_pb2.GetRecordingSessionResponse,
+        )
+        self.ScheduleRecording = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ScheduleRecording',
+            audio_pb2.ScheduleRecordingRequest,
+            audio_pb2.ScheduleRecordingResponse,
+        )
+        self.CancelScheduledRecording = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/CancelScheduledRecording',
+            audio_pb2.CancelScheduledRecordingRequest,
+            audio_pb2.CancelScheduledRecordingResponse,
+        )
+        self.GetTriggerState = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetTriggerState',
+            audio_pb2.GetTriggerStateRequest,
+            audio_pb2.GetTriggerStateResponse,
+        )
+        self.ListDevices = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/ListDevices',
+            audio_pb2.ListDevicesRequest,
+            audio_pb2.ListDevicesResponse,
+        )
+        self.GetLatencyStats = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetLatencyStats',
+            audio_pb2.GetLatencyStatsRequest,
+            audio_pb2.GetLatencyStatsResponse,
+        )
+        self.GetStats = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetStats',
+            audio_pb2.GetStatsRequest,
+            audio_pb2.GetStatsResponse,
+        )
+        self.GetHealth = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/GetHealth',
+            audio_pb2.GetHealthRequest,
+            audio_pb2.GetHealthResponse,
+        )
+        self.DoCommand = grpclib.client.UnaryUnaryMethod(
+            channel,
+            '/AudioService/DoCommand',
+            audio_pb2.DoCommandRequest,
+            audio_pb2.DoCommandResponse,
+        )
         self.Properties = grpclib.client.UnaryUnaryMethod(
             channel,
             '/AudioService/Properties',
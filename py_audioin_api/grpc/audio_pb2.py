@@ -25,7 +25,7 @@ _sym_db = _symbol_database.Default()
 from google.api import annotations_pb2 as google_dot_api_dot_annotations__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x61udio.proto\x1a\x1cgoogle/api/annotations.proto\"e\n\tAudioInfo\x12\x14\n\x05\x63odec\x18\x01 \x01(\tR\x05\x63odec\x12\x1f\n\x0bsample_rate\x18\x02 \x01(\x05R\nsampleRate\x12!\n\x0cnum_channels\x18\x03 \x01(\x05R\x0bnumChannels\"\xe6\x01\n\x0fGetAudioRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12)\n\x10\x64uration_seconds\x18\x02 \x01(\x02R\x0f\x64urationSeconds\x12\x14\n\x05\x63odec\x18\x03 \x01(\tR\x05\x63odec\x12\x1d\n\nrequest_id\x18\x04 \x01(\tR\trequestId\x12\x30\n\x14max_duration_seconds\x18\x05 \x01(\x02R\x12maxDurationSeconds\x12-\n\x12previous_timestamp\x18\x06 \x01(\x02R\x11previousTimestamp\"\xe3\x01\n\nAudioChunk\x12\x1d\n\naudio_data\x18\x01 \x01(\x0cR\taudioData\x12\x1e\n\x04info\x18\x02 \x01(\x0b\x32\n.AudioInfoR\x04info\x12\x1a\n\x08sequence\x18\x03 \x01(\x05R\x08sequence\x12>\n\x1bstart_timestamp_nanoseconds\x18\x04 \x01(\x03R\x19startTimestampNanoseconds\x12:\n\x19\x65nd_timestamp_nanoseconds\x18\x05 \x01(\x03R\x17\x65ndTimestampNanoseconds\"`\n\x0bPlayRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\naudio_data\x18\x02 \x01(\x0cR\taudioData\x12\x1e\n\x04info\x18\x03 \x01(\x0b\x32\n.AudioInfoR\x04info\"\"\n\x0cPlayResponse\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"\'\n\x11PropertiesRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"\x83\x01\n\x12PropertiesResponse\x12)\n\x10supported_codecs\x18\x01 \x03(\tR\x0fsupportedCodecs\x12\x1f\n\x0bsample_rate\x18\x02 \x03(\x05R\nsampleRate\x12!\n\x0cnum_channels\x18\x03 \x01(\x05R\x0bnumChannels2\xb7\x02\n\x0c\x41udioService\x12\x61\n\x08GetAudio\x12\x10.GetAudioRequest\x1a\x0b.AudioChunk\"4\x82\xd3\xe4\x93\x02.\",/olivia/api/v1/service/audio/{name}/GetAudio0\x01\x12U\n\x04Play\x12\x0c.PlayRequest\x1a\r.PlayResponse\"0\x82\xd3\xe4\x93\x02*\"(/olivia/api/v1/service/audio/{name}/play\x12m\n\nProperties\x12\x12.PropertiesRequest\x1a\x13.PropertiesResponse\"6\x82\xd3\xe4\x93\x02\x30\"./olivia/api/v1/service/audio/{name}/propertiesB\tZ\x07./audiob\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x61udio.proto\x1a\x1cgoogle/api/annotations.proto\"e\n\tAudioInfo\x12\x14\n\x05\x63odec\x18\x01 \x01(\tR\x05\x63odec\x12\x1f\n\x0bsample_rate\x18\x02 \x01(\x05R\nsampleRate\x12!\n\x0cnum_channels\x18\x03 \x01(\x05R\x0bnumChannels\"\xf2\t\n\x0fGetAudioRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12)\n\x10\x64uration_seconds\x18\x02 \x01(\x02R\x0f\x64urationSeconds\x12\x14\n\x05\x63odec\x18\x03 \x01(\tR\x05\x63odec\x12\x1d\n\nrequest_id\x18\x04 \x01(\tR\trequestId\x12\x30\n\x14max_duration_seconds\x18\x05 \x01(\x02R\x12maxDurationSeconds\x12\x1f\n\x0bsample_rate\x18\x07 \x01(\x05R\nsampleRate\x12!\n\x0cnum_channels\x18\x08 \x01(\x05R\x0bnumChannels\x12\x1b\n\tonly_when\x18\t \x03(\tR\x08onlyWhen\x12(\n\x10pre_roll_seconds\x18\n \x01(\x02R\x0epreRollSeconds\x12*\n\x11post_roll_seconds\x18\x0b \x01(\x02R\x0fpostRollSeconds\x12\x10\n\x03vad\x18\x0c \x01(\tR\x03vad\x12\x1f\n\x0bspeech_only\x18\r \x01(\x08R\nspeechOnly\x12!\n\x0ctrim_silence\x18\x0e \x01(\x08R\x0btrimSilence\x12\x34\n\x16silence_threshold_dbfs\x18\x0f \x01(\x02R\x14silenceThresholdDbfs\x12\x38\n\x18silence_hangover_seconds\x18\x10 \x01(\x02R\x16silenceHangoverSeconds\x12+\n\x11noise_suppression\x18\x11 \x01(\tR\x10noiseSuppression\x12\x10\n\x03\x61gc\x18\x12 \x01(\x08R\x03\x61gc\x12&\n\x0f\x61gc_target_dbfs\x18\x13 \x01(\x02R\ragcTargetDbfs\x12 \n\x0c\x61lso_save_as\x18\x14 \x01(\tR\nalsoSaveAs\x12\x16\n\x06strict\x18\x15 \x01(\x08R\x06strict\x12\x1c\n\tresumable\x18\x16 \x01(\x08R\tresumable\x12!\n\x0cresume_token\x18\x17 \x01(\tR\x0bresumeToken\x12\x34\n\x16\x63hunk_duration_seconds\x18\x18 \x01(\x02R\x14\x63hunkDurationSeconds\x12\x1f\n\x0b\x64rop_policy\x18\x19 \x01(\tR\ndropPolicy\x12#\n\rbuffer_chunks\x18\x1a \x01(\x05R\x0c\x62ufferChunks\x12 \n\x0b\x63ompression\x18\x1b \x01(\tR\x0b\x63ompression\x12!\n\x0c\x62\x61tch_chunks\x18\x1c \x01(\x05R\x0b\x62\x61tchChunks\x12\x35\n\x17\x62\x61tch_max_delay_seconds\x18\x1d \x01(\x02R\x14\x62\x61tchMaxDelaySeconds\x12)\n\x10\x61\x64\x61ptive_bitrate\x18\x1e \x01(\x08R\x0f\x61\x64\x61ptiveBitrate\x12(\n\x10min_bitrate_kbps\x18\x1f \x01(\x05R\x0eminBitrateKbps\x12(\n\x10max_bitrate_kbps\x18  \x01(\x05R\x0emaxBitrateKbps\x12+\n\x11heartbeat_seconds\x18! \x01(\x02R\x10heartbeatSeconds\x12\x1c\n\tchecksums\x18\" \x01(\x08R\tchecksumsJ\x04\x08\x06\x10\x07R\x12previous_timestamp\"\x93\x05\n\nAudioChunk\x12\x1d\n\naudio_data\x18\x01 \x01(\x0cR\taudioData\x12\x1e\n\x04info\x18\x02 \x01(\x0b\x32\n.AudioInfoR\x04info\x12\x1a\n\x08sequence\x18\x03 \x01(\x05R\x08sequence\x12>\n\x1bstart_timestamp_nanoseconds\x18\x04 \x01(\x03R\x19startTimestampNanoseconds\x12:\n\x19\x65nd_timestamp_nanoseconds\x18\x05 \x01(\x03R\x17\x65ndTimestampNanoseconds\x12\'\n\x0fgap_nanoseconds\x18\x06 \x01(\x03R\x0egapNanoseconds\x12%\n\x06header\x18\x07 \x01(\x0b\x32\r.StreamHeaderR\x06header\x12\x1b\n\x06speech\x18\x08 \x01(\x08H\x00R\x06speech\x88\x01\x01\x12%\n\x08timecode\x18\t \x01(\x0b\x32\t.TimecodeR\x08timecode\x12!\n\x0cresume_token\x18\n \x01(\tR\x0bresumeToken\x12%\n\x0e\x64ropped_chunks\x18\x0b \x01(\x03R\rdroppedChunks\x12!\n\x05\x62\x61tch\x18\x0c \x03(\x0b\x32\x0b.AudioChunkR\x05\x62\x61tch\x12<\n\x1asent_timestamp_nanoseconds\x18\r \x01(\x03R\x18sentTimestampNanoseconds\x12!\n\x0c\x62itrate_kbps\x18\x0e \x01(\x05R\x0b\x62itrateKbps\x12\x1c\n\theartbeat\x18\x0f \x01(\x08R\theartbeat\x12\x19\n\x05\x63rc32\x18\x10 \x01(\x07H\x01R\x05\x63rc32\x88\x01\x01\x42\t\n\x07_speechB\x08\n\x06_crc32\"o\n\x0cStreamHeader\x12\x1e\n\x04info\x18\x01 \x01(\x0b\x32\n.AudioInfoR\x04info\x12\x1c\n\textradata\x18\x02 \x01(\x0cR\textradata\x12!\n\x0c\x63hunk_frames\x18\x03 \x01(\x05R\x0b\x63hunkFrames\"\xf6\x01\n\x08Timecode\x12\x14\n\x05hours\x18\x01 \x01(\x05R\x05hours\x12\x18\n\x07minutes\x18\x02 \x01(\x05R\x07minutes\x12\x18\n\x07seconds\x18\x03 \x01(\x05R\x07seconds\x12\x16\n\x06\x66rames\x18\x04 \x01(\x05R\x06\x66rames\x12\x1d\n\ndrop_frame\x18\x05 \x01(\x08R\tdropFrame\x12\x1d\n\nframe_rate\x18\x06 \x01(\x05R\tframeRate\x12\x1b\n\tuser_bits\x18\x07 \x01(\rR\x08userBits\x12-\n\x12offset_nanoseconds\x18\x08 \x01(\x03R\x11offsetNanoseconds\"\xb9\x01\n\x0bPlayRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\naudio_data\x18\x02 \x01(\x0cR\taudioData\x12\x1e\n\x04info\x18\x03 \x01(\x0b\x32\n.AudioInfoR\x04info\x12%\n\x0enormalize_lufs\x18\x04 \x01(\x02R\rnormalizeLufs\x12\x16\n\x06strict\x18\x05 \x01(\x08R\x06strict\x12\x18\n\x07\x63onvert\x18\x06 \x01(\x08R\x07\x63onvert\"\"\n\x0cPlayResponse\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"G\n\x12PauseStreamRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nrequest_id\x18\x02 \x01(\tR\trequestId\"\x15\n\x13PauseStreamResponse\"H\n\x13ResumeStreamRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nrequest_id\x18\x02 \x01(\tR\trequestId\"\x16\n\x14ResumeStreamResponse\"k\n\x16PreparePlaybackRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\naudio_data\x18\x02 \x01(\x0cR\taudioData\x12\x1e\n\x04info\x18\x03 \x01(\x0b\x32\n.AudioInfoR\x04info\"1\n\x17PreparePlaybackResponse\x12\x16\n\x06handle\x18\x01 \x01(\tR\x06handle\"y\n\x15\x43ommitPlaybackRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n\x06handle\x18\x02 \x01(\tR\x06handle\x12\x34\n\x16start_time_nanoseconds\x18\x03 \x01(\x03R\x14startTimeNanoseconds\"\x18\n\x16\x43ommitPlaybackResponse\"D\n\x16ReleasePlaybackRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n\x06handle\x18\x02 \x01(\tR\x06handle\"\x19\n\x17ReleasePlaybackResponse\"A\n\x11SetProfileRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n\x07profile\x18\x02 \x01(\tR\x07profile\"\x14\n\x12SetProfileResponse\"\'\n\x11GetProfileRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"h\n\x12GetProfileResponse\x12\x18\n\x07profile\x18\x01 \x01(\tR\x07profile\x12\x1a\n\x08profiles\x18\x02 \x03(\tR\x08profiles\x12\x1c\n\tautomatic\x18\x03 \x01(\x08R\tautomatic\"f\n\x06\x45QBand\x12\x12\n\x04type\x18\x01 \x01(\tR\x04type\x12!\n\x0c\x66requency_hz\x18\x02 \x01(\x02R\x0b\x66requencyHz\x12\x17\n\x07gain_db\x18\x03 \x01(\x02R\x06gainDb\x12\x0c\n\x01q\x18\x04 \x01(\x02R\x01q\"A\n\x0cSetEQRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\x05\x62\x61nds\x18\x02 \x03(\x0b\x32\x07.EQBandR\x05\x62\x61nds\"\x0f\n\rSetEQResponse\"\"\n\x0cGetEQRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\".\n\rGetEQResponse\x12\x1d\n\x05\x62\x61nds\x18\x01 \x03(\x0b\x32\x07.EQBandR\x05\x62\x61nds\"M\n\x10GetLevelsRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12%\n\x0ewindow_seconds\x18\x02 \x01(\x02R\rwindowSeconds\"l\n\x0c\x43hannelLevel\x12\x10\n\x03rms\x18\x01 \x01(\x02R\x03rms\x12\x12\n\x04peak\x18\x02 \x01(\x02R\x04peak\x12\x19\n\x08rms_dbfs\x18\x03 \x01(\x02R\x07rmsDbfs\x12\x1b\n\tpeak_dbfs\x18\x04 \x01(\x02R\x08peakDbfs\"s\n\x11GetLevelsResponse\x12)\n\x08\x63hannels\x18\x01 \x03(\x0b\x32\r.ChannelLevelR\x08\x63hannels\x12\x33\n\x15timestamp_nanoseconds\x18\x02 \x01(\x03R\x14timestampNanoseconds\"a\n\x15StreamSpectrumRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n\x08\x66\x66t_size\x18\x02 \x01(\x05R\x07\x66\x66tSize\x12\x19\n\x08hop_size\x18\x03 \x01(\x05R\x07hopSize\"{\n\rSpectrumFrame\x12\x1e\n\nmagnitudes\x18\x01 \x03(\x02R\nmagnitudes\x12\x15\n\x06\x62in_hz\x18\x02 \x01(\x02R\x05\x62inHz\x12\x33\n\x15timestamp_nanoseconds\x18\x03 \x01(\x03R\x14timestampNanoseconds\"\xd2\x01\n\x15GetSpectrogramRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n\x07seconds\x18\x02 \x01(\x01R\x07seconds\x12\x14\n\x05width\x18\x03 \x01(\x05R\x05width\x12\x16\n\x06height\x18\x04 \x01(\x05R\x06height\x12\x19\n\x08\x66\x66t_size\x18\x05 \x01(\x05R\x07\x66\x66tSize\x12\x1d\n\nfloor_dbfs\x18\x06 \x01(\x01R\tfloorDbfs\x12#\n\rlog_frequency\x18\x07 \x01(\x08R\x0clogFrequency\"\xbe\x01\n\x16GetSpectrogramResponse\x12\x10\n\x03png\x18\x01 \x01(\x0cR\x03png\x12>\n\x1bstart_timestamp_nanoseconds\x18\x02 \x01(\x03R\x19startTimestampNanoseconds\x12\x31\n\x14\x64uration_nanoseconds\x18\x03 \x01(\x03R\x13\x64urationNanoseconds\x12\x1f\n\x0bsample_rate\x18\x04 \x01(\x05R\nsampleRate\"\x94\x01\n\x12\x43\x61ptureClipRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12(\n\x10pre_roll_seconds\x18\x02 \x01(\x01R\x0epreRollSeconds\x12*\n\x11post_roll_seconds\x18\x03 \x01(\x01R\x0fpostRollSeconds\x12\x14\n\x05\x63odec\x18\x04 \x01(\tR\x05\x63odec\"\xd8\x01\n\x13\x43\x61ptureClipResponse\x12\x1d\n\naudio_data\x18\x01 \x01(\x0cR\taudioData\x12\x1e\n\x04info\x18\x02 \x01(\x0b\x32\n.AudioInfoR\x04info\x12>\n\x1bstart_timestamp_nanoseconds\x18\x03 \x01(\x03R\x19startTimestampNanoseconds\x12\x42\n\x1dtrigger_timestamp_nanoseconds\x18\x04 \x01(\x03R\x1btriggerTimestampNanoseconds\"\xb9\x01\n\x15StreamImpulsesRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n\x07rise_db\x18\x02 \x01(\x02R\x06riseDb\x12\"\n\rmin_peak_dbfs\x18\x03 \x01(\x02R\x0bminPeakDbfs\x12\x30\n\x14max_duration_seconds\x18\x04 \x01(\x02R\x12maxDurationSeconds\x12\x1d\n\nsave_clips\x18\x05 \x01(\x08R\tsaveClips\"\xfd\x01\n\x0cImpulseEvent\x12\x33\n\x15timestamp_nanoseconds\x18\x01 \x01(\x03R\x14timestampNanoseconds\x12)\n\x10\x64uration_seconds\x18\x02 \x01(\x02R\x0f\x64urationSeconds\x12\x1b\n\tpeak_dbfs\x18\x03 \x01(\x02R\x08peakDbfs\x12\x17\n\x07rise_db\x18\x04 \x01(\x02R\x06riseDb\x12\'\n\x0f\x62\x61\x63kground_dbfs\x18\x05 \x01(\x02R\x0e\x62\x61\x63kgroundDbfs\x12\x1a\n\x08kurtosis\x18\x06 \x01(\x02R\x08kurtosis\x12\x12\n\x04\x63lip\x18\x07 \x01(\tR\x04\x63lip\"\x98\x01\n\x14GetLevelStatsRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n\x06period\x18\x02 \x01(\tR\x06period\x12+\n\x11start_nanoseconds\x18\x03 \x01(\x03R\x10startNanoseconds\x12\'\n\x0f\x65nd_nanoseconds\x18\x04 \x01(\x03R\x0e\x65ndNanoseconds\"\x8a\x02\n\x10LevelStatsBucket\x12+\n\x11start_nanoseconds\x18\x01 \x01(\x03R\x10startNanoseconds\x12\'\n\x0f\x63overed_seconds\x18\x02 \x01(\x02R\x0e\x63overedSeconds\x12\x19\n\x08leq_dbfs\x18\x03 \x01(\x02R\x07leqDbfs\x12\x19\n\x08l10_dbfs\x18\x04 \x01(\x02R\x07l10Dbfs\x12\x19\n\x08l50_dbfs\x18\x05 \x01(\x02R\x07l50Dbfs\x12\x19\n\x08l90_dbfs\x18\x06 \x01(\x02R\x07l90Dbfs\x12\x19\n\x08max_dbfs\x18\x07 \x01(\x02R\x07maxDbfs\x12\x19\n\x08min_dbfs\x18\x08 \x01(\x02R\x07minDbfs\"D\n\x15GetLevelStatsResponse\x12+\n\x07\x62uckets\x18\x01 \x03(\x0b\x32\x11.LevelStatsBucketR\x07\x62uckets\"\xab\x02\n\x12ListHistoryRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n\tpage_size\x18\x02 \x01(\x05R\x08pageSize\x12\x1d\n\npage_token\x18\x03 \x01(\tR\tpageToken\x12\x1c\n\tdirection\x18\x04 \x01(\tR\tdirection\x12\x1d\n\nrequest_id\x18\x05 \x01(\tR\trequestId\x12\x18\n\x07subject\x18\x06 \x01(\tR\x07subject\x12\x12\n\x04kind\x18\x07 \x01(\tR\x04kind\x12+\n\x11\x61\x66ter_nanoseconds\x18\x08 \x01(\x03R\x10\x61\x66terNanoseconds\x12-\n\x12\x62\x65\x66ore_nanoseconds\x18\t \x01(\x03R\x11\x62\x65\x66oreNanoseconds\"\xf2\x02\n\x0cStreamRecord\x12\x1c\n\tdirection\x18\x01 \x01(\tR\tdirection\x12\x14\n\x05\x63odec\x18\x02 \x01(\tR\x05\x63odec\x12\x18\n\x07profile\x18\x03 \x01(\tR\x07profile\x12\x1d\n\nrequest_id\x18\x04 \x01(\tR\trequestId\x12\x18\n\x07subject\x18\x05 \x01(\tR\x07subject\x12+\n\x11start_nanoseconds\x18\x06 \x01(\x03R\x10startNanoseconds\x12\'\n\x0f\x65nd_nanoseconds\x18\x07 \x01(\x03R\x0e\x65ndNanoseconds\x12\x14\n\x05\x62ytes\x18\x08 \x01(\x03R\x05\x62ytes\x12\x1a\n\x08messages\x18\t \x01(\x03R\x08messages\x12\x14\n\x05\x65rror\x18\n \x01(\tR\x05\x65rror\x12\x16\n\x06paused\x18\x0b \x01(\x08R\x06paused\x12%\n\x0e\x64ropped_chunks\x18\x0c \x01(\x03R\rdroppedChunks\"l\n\x19ListStreamHistoryResponse\x12\'\n\x07records\x18\x01 \x03(\x0b\x32\r.StreamRecordR\x07records\x12&\n\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xb2\x01\n\x0b\x45ventRecord\x12\x12\n\x04kind\x18\x01 \x01(\tR\x04kind\x12\x33\n\x15timestamp_nanoseconds\x18\x02 \x01(\x03R\x14timestampNanoseconds\x12)\n\x10\x64uration_seconds\x18\x03 \x01(\x02R\x0f\x64urationSeconds\x12\x1b\n\tpeak_dbfs\x18\x04 \x01(\x02R\x08peakDbfs\x12\x12\n\x04\x63lip\x18\x05 \x01(\tR\x04\x63lip\"b\n\x12ListEventsResponse\x12$\n\x06\x65vents\x18\x01 \x03(\x0b\x32\x0c.EventRecordR\x06\x65vents\x12&\n\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xf3\x01\n\x13ListAuditLogRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n\tpage_size\x18\x02 \x01(\x05R\x08pageSize\x12\x1d\n\npage_token\x18\x03 \x01(\tR\tpageToken\x12\x16\n\x06\x61\x63tion\x18\x04 \x01(\tR\x06\x61\x63tion\x12\x18\n\x07subject\x18\x05 \x01(\tR\x07subject\x12+\n\x11\x61\x66ter_nanoseconds\x18\x06 \x01(\x03R\x10\x61\x66terNanoseconds\x12-\n\x12\x62\x65\x66ore_nanoseconds\x18\x07 \x01(\x03R\x11\x62\x65\x66oreNanoseconds\"\xf1\x01\n\x0b\x41uditRecord\x12\x10\n\x03seq\x18\x01 \x01(\x04R\x03seq\x12)\n\x10time_nanoseconds\x18\x02 \x01(\x03R\x0ftimeNanoseconds\x12\x16\n\x06\x61\x63tion\x18\x03 \x01(\tR\x06\x61\x63tion\x12\x18\n\x07subject\x18\x04 \x01(\tR\x07subject\x12\x12\n\x04peer\x18\x05 \x01(\tR\x04peer\x12\x1d\n\nrequest_id\x18\x06 \x01(\tR\trequestId\x12\x16\n\x06\x64\x65tail\x18\x07 \x01(\tR\x06\x64\x65tail\x12\x14\n\x05\x65rror\x18\x08 \x01(\tR\x05\x65rror\x12\x12\n\x04hash\x18\t \x01(\tR\x04hash\"f\n\x14ListAuditLogResponse\x12&\n\x07records\x18\x01 \x03(\x0b\x32\x0c.AuditRecordR\x07records\x12&\n\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x9d\x02\n\x18ListActiveStreamsRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n\tpage_size\x18\x02 \x01(\x05R\x08pageSize\x12\x1d\n\npage_token\x18\x03 \x01(\tR\tpageToken\x12\x1c\n\tdirection\x18\x04 \x01(\tR\tdirection\x12\x1d\n\nrequest_id\x18\x05 \x01(\tR\trequestId\x12\x18\n\x07subject\x18\x06 \x01(\tR\x07subject\x12+\n\x11\x61\x66ter_nanoseconds\x18\x07 \x01(\x03R\x10\x61\x66terNanoseconds\x12-\n\x12\x62\x65\x66ore_nanoseconds\x18\x08 \x01(\x03R\x11\x62\x65\x66oreNanoseconds\"l\n\x19ListActiveStreamsResponse\x12\'\n\x07streams\x18\x01 \x03(\x0b\x32\r.StreamRecordR\x07streams\x12&\n\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xc9\x03\n\x15ListRecordingsRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n\tpage_size\x18\x02 \x01(\x05R\x08pageSize\x12\x1d\n\npage_token\x18\x03 \x01(\tR\tpageToken\x12\x16\n\x06prefix\x18\x04 \x01(\tR\x06prefix\x12\x16\n\x06\x66ormat\x18\x05 \x01(\tR\x06\x66ormat\x12+\n\x11\x61\x66ter_nanoseconds\x18\x06 \x01(\x03R\x10\x61\x66terNanoseconds\x12-\n\x12\x62\x65\x66ore_nanoseconds\x18\x07 \x01(\x03R\x11\x62\x65\x66oreNanoseconds\x12\x14\n\x05\x63odec\x18\x08 \x01(\tR\x05\x63odec\x12\x38\n\x18min_duration_nanoseconds\x18\t \x01(\x03R\x16minDurationNanoseconds\x12\x38\n\x18max_duration_nanoseconds\x18\n \x01(\x03R\x16maxDurationNanoseconds\x12$\n\x0emin_size_bytes\x18\x0b \x01(\x03R\x0cminSizeBytes\x12$\n\x0emax_size_bytes\x18\x0c \x01(\x03R\x0cmaxSizeBytes\"\xac\x02\n\x0fStoredRecording\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n\x06\x66ormat\x18\x02 \x01(\tR\x06\x66ormat\x12\x1d\n\nsize_bytes\x18\x03 \x01(\x03R\tsizeBytes\x12\x31\n\x14modified_nanoseconds\x18\x04 \x01(\x03R\x13modifiedNanoseconds\x12\x0e\n\x02id\x18\x05 \x01(\tR\x02id\x12\x14\n\x05\x63odec\x18\x06 \x01(\tR\x05\x63odec\x12\x1f\n\x0bsample_rate\x18\x07 \x01(\x05R\nsampleRate\x12!\n\x0cnum_channels\x18\x08 \x01(\x05R\x0bnumChannels\x12\x31\n\x14\x64uration_nanoseconds\x18\t \x01(\x03R\x13\x64urationNanoseconds\"9\n\x13GetRecordingRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n\x02id\x18\x02 \x01(\tR\x02id\"F\n\x14GetRecordingResponse\x12.\n\trecording\x18\x01 \x01(\x0b\x32\x10.StoredRecordingR\trecording\"w\n\x16StreamRecordingRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n\x05\x63odec\x18\x03 \x01(\tR\x05\x63odec\x12#\n\rstart_seconds\x18\x04 \x01(\x01R\x0cstartSeconds\"r\n\x16ListRecordingsResponse\x12\x30\n\nrecordings\x18\x01 \x03(\x0b\x32\x10.StoredRecordingR\nrecordings\x12&\n\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\",\n\x16GetLatencyStatsRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"\xb5\x01\n\x17GetLatencyStatsResponse\x12\x16\n\x06\x63hunks\x18\x01 \x01(\x03R\x06\x63hunks\x12\x1f\n\x0bp50_seconds\x18\x02 \x01(\x01R\np50Seconds\x12\x1f\n\x0bp95_seconds\x18\x03 \x01(\x01R\np95Seconds\x12\x1f\n\x0bp99_seconds\x18\x04 \x01(\x01R\np99Seconds\x12\x1f\n\x0bmax_seconds\x18\x05 \x01(\x01R\nmaxSeconds\"%\n\x0fGetStatsRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"\xb5\x03\n\x10GetStatsResponse\x12%\n\x0euptime_seconds\x18\x01 \x01(\x01R\ruptimeSeconds\x12%\n\x0e\x61\x63tive_streams\x18\x02 \x01(\x05R\ractiveStreams\x12!\n\x0c\x61\x63tive_plays\x18\x03 \x01(\x05R\x0b\x61\x63tivePlays\x12\'\n\x0f\x61\x63tive_sessions\x18\x04 \x01(\x05R\x0e\x61\x63tiveSessions\x12%\n\x0e\x62ytes_captured\x18\x05 \x01(\x03R\rbytesCaptured\x12!\n\x0c\x62ytes_played\x18\x06 \x01(\x03R\x0b\x62ytesPlayed\x12%\n\x0e\x64ropped_chunks\x18\x07 \x01(\x03R\rdroppedChunks\x12\x1a\n\x08overruns\x18\x08 \x01(\x03R\x08overruns\x12\x1c\n\tunderruns\x18\t \x01(\x03R\tunderruns\x12\x1d\n\nlast_error\x18\n \x01(\tR\tlastError\x12=\n\x1blast_error_time_nanoseconds\x18\x0b \x01(\x03R\x18lastErrorTimeNanoseconds\"&\n\x10GetHealthRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"\x92\x01\n\x0c\x44\x65viceHealth\x12\x14\n\x05state\x18\x01 \x01(\tR\x05state\x12\x16\n\x06reason\x18\x02 \x01(\tR\x06reason\x12+\n\x11since_nanoseconds\x18\x03 \x01(\x03R\x10sinceNanoseconds\x12\'\n\x0freopen_attempts\x18\x04 \x01(\x05R\x0ereopenAttempts\"}\n\x11GetHealthResponse\x12\x14\n\x05ready\x18\x01 \x01(\x08R\x05ready\x12\'\n\x07\x63\x61pture\x18\x02 \x01(\x0b\x32\r.DeviceHealthR\x07\x63\x61pture\x12)\n\x08playback\x18\x03 \x01(\x0b\x32\r.DeviceHealthR\x08playback\"I\n\x10\x44oCommandRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12!\n\x0c\x63ommand_json\x18\x02 \x01(\tR\x0b\x63ommandJson\"4\n\x11\x44oCommandResponse\x12\x1f\n\x0bresult_json\x18\x01 \x01(\tR\nresultJson\".\n\x18GetRecordingStatsRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"\x9f\x03\n\x19GetRecordingStatsResponse\x12\x1e\n\nrecordings\x18\x01 \x01(\x05R\nrecordings\x12\x1f\n\x0btotal_bytes\x18\x02 \x01(\x03R\ntotalBytes\x12-\n\x12oldest_nanoseconds\x18\x03 \x01(\x03R\x11oldestNanoseconds\x12-\n\x12newest_nanoseconds\x18\x04 \x01(\x03R\x11newestNanoseconds\x12&\n\x0f\x64isk_free_bytes\x18\x05 \x01(\x03R\rdiskFreeBytes\x12&\n\x0f\x64isk_size_bytes\x18\x06 \x01(\x03R\rdiskSizeBytes\x12&\n\x0fmax_age_seconds\x18\x07 \x01(\x01R\rmaxAgeSeconds\x12\x1b\n\tmax_bytes\x18\x08 \x01(\x03R\x08maxBytes\x12+\n\x11pruned_recordings\x18\t \x01(\x05R\x10prunedRecordings\x12!\n\x0cpruned_bytes\x18\n \x01(\x03R\x0bprunedBytes\"\x93\x01\n\x15StartRecordingRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\'\n\x0fsegment_seconds\x18\x02 \x01(\x01R\x0esegmentSeconds\x12\x16\n\x06\x66ormat\x18\x03 \x01(\tR\x06\x66ormat\x12%\n\x0enormalize_lufs\x18\x04 \x01(\x01R\rnormalizeLufs\";\n\x16StartRecordingResponse\x12!\n\x0crecording_id\x18\x01 \x01(\tR\x0brecordingId\"M\n\x14StopRecordingRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12!\n\x0crecording_id\x18\x02 \x01(\tR\x0brecordingId\"F\n\x15StopRecordingResponse\x12-\n\x08segments\x18\x01 \x03(\x0b\x32\x11.RecordingSegmentR\x08segments\"L\n\x13ListSegmentsRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12!\n\x0crecording_id\x18\x02 \x01(\tR\x0brecordingId\"\xb5\x01\n\x10RecordingSegment\x12\x12\n\x04\x66ile\x18\x01 \x01(\tR\x04\x66ile\x12>\n\x1bstart_timestamp_nanoseconds\x18\x02 \x01(\x03R\x19startTimestampNanoseconds\x12\x31\n\x14\x64uration_nanoseconds\x18\x03 \x01(\x03R\x13\x64urationNanoseconds\x12\x1a\n\x08\x63omplete\x18\x04 \x01(\x08R\x08\x63omplete\"s\n\x14ListSegmentsResponse\x12-\n\x08segments\x18\x01 \x03(\x0b\x32\x11.RecordingSegmentR\x08segments\x12\x16\n\x06\x61\x63tive\x18\x02 \x01(\x08R\x06\x61\x63tive\x12\x14\n\x05\x65rror\x18\x03 \x01(\tR\x05\x65rror\"\\\n\x0eRecordingTrack\x12\x1a\n\x08resource\x18\x01 \x01(\tR\x08resource\x12\x1a\n\x08\x63hannels\x18\x02 \x03(\x05R\x08\x63hannels\x12\x12\n\x04name\x18\x03 \x01(\tR\x04name\"\x9c\x01\n\x1cStartRecordingSessionRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\'\n\x06tracks\x18\x02 \x03(\x0b\x32\x0f.RecordingTrackR\x06tracks\x12\'\n\x0fsegment_seconds\x18\x03 \x01(\x01R\x0esegmentSeconds\x12\x16\n\x06\x66ormat\x18\x04 \x01(\tR\x06\x66ormat\">\n\x1dStartRecordingSessionResponse\x12\x1d\n\nsession_id\x18\x01 \x01(\tR\tsessionId\"P\n\x1bStopRecordingSessionRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nsession_id\x18\x02 \x01(\tR\tsessionId\"K\n\x1cStopRecordingSessionResponse\x12+\n\x07session\x18\x01 \x01(\x0b\x32\x11.RecordingSessionR\x07session\"O\n\x1aGetRecordingSessionRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nsession_id\x18\x02 \x01(\tR\tsessionId\"J\n\x1bGetRecordingSessionResponse\x12+\n\x07session\x18\x01 \x01(\x0b\x32\x11.RecordingSessionR\x07session\"\x90\x01\n\x10RecordingSession\x12>\n\x1bstart_timestamp_nanoseconds\x18\x01 \x01(\x03R\x19startTimestampNanoseconds\x12$\n\x06tracks\x18\x02 \x03(\x0b\x32\x0c.TrackStatusR\x06tracks\x12\x16\n\x06\x61\x63tive\x18\x03 \x01(\x08R\x06\x61\x63tive\"\xa8\x01\n\x0bTrackStatus\x12%\n\x05track\x18\x01 \x01(\x0b\x32\x0f.RecordingTrackR\x05track\x12-\n\x08segments\x18\x02 \x03(\x0b\x32\x11.RecordingSegmentR\x08segments\x12-\n\x12offset_nanoseconds\x18\x03 \x01(\x03R\x11offsetNanoseconds\x12\x14\n\x05\x65rror\x18\x04 \x01(\tR\x05\x65rror\";\n\x0fRecordingWindow\x12\x14\n\x05start\x18\x01 \x01(\tR\x05start\x12\x12\n\x04stop\x18\x02 \x01(\tR\x04stop\"\xdf\x01\n\x18ScheduleRecordingRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12*\n\x07windows\x18\x02 \x03(\x0b\x32\x10.RecordingWindowR\x07windows\x12\x1b\n\ttime_zone\x18\x03 \x01(\tR\x08timeZone\x12\'\n\x0fsegment_seconds\x18\x04 \x01(\x01R\x0esegmentSeconds\x12\x16\n\x06\x66ormat\x18\x05 \x01(\tR\x06\x66ormat\x12%\n\x0enormalize_lufs\x18\x06 \x01(\x01R\rnormalizeLufs\"z\n\x19ScheduleRecordingResponse\x12\x1f\n\x0bschedule_id\x18\x01 \x01(\tR\nscheduleId\x12<\n\x1anext_timestamp_nanoseconds\x18\x02 \x01(\x03R\x18nextTimestampNanoseconds\"V\n\x1f\x43\x61ncelScheduledRecordingRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n\x0bschedule_id\x18\x02 \x01(\tR\nscheduleId\"Q\n CancelScheduledRecordingResponse\x12-\n\x08segments\x18\x01 \x03(\x0b\x32\x11.RecordingSegmentR\x08segments\",\n\x16GetTriggerStateRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"\x92\x02\n\x17GetTriggerStateResponse\x12\x16\n\x06\x61\x63tive\x18\x01 \x01(\x08R\x06\x61\x63tive\x12\x1d\n\nlevel_dbfs\x18\x02 \x01(\x01R\tlevelDbfs\x12+\n\x11since_nanoseconds\x18\x03 \x01(\x03R\x10sinceNanoseconds\x12\x1a\n\x08triggers\x18\x04 \x01(\x05R\x08triggers\x12%\n\x0ethreshold_dbfs\x18\x05 \x01(\x01R\rthresholdDbfs\x12!\n\x0crelease_dbfs\x18\x06 \x01(\x01R\x0breleaseDbfs\x12-\n\x08segments\x18\x07 \x03(\x0b\x32\x11.RecordingSegmentR\x08segments\"\x9e\x01\n\x12ListDevicesRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n\tpage_size\x18\x02 \x01(\x05R\x08pageSize\x12\x1d\n\npage_token\x18\x03 \x01(\tR\tpageToken\x12\x1c\n\tdirection\x18\x04 \x01(\tR\tdirection\x12\x1a\n\x08\x63ontains\x18\x05 \x01(\tR\x08\x63ontains\"\xce\x01\n\x06\x44\x65vice\x12\x0e\n\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n\x06\x64river\x18\x03 \x01(\tR\x06\x64river\x12\x18\n\x07\x63\x61pture\x18\x04 \x01(\x08R\x07\x63\x61pture\x12\x1a\n\x08playback\x18\x05 \x01(\x08R\x08playback\x12\'\n\x0f\x64\x65\x66\x61ult_capture\x18\x06 \x01(\x08R\x0e\x64\x65\x66\x61ultCapture\x12)\n\x10\x64\x65\x66\x61ult_playback\x18\x07 \x01(\x08R\x0f\x64\x65\x66\x61ultPlayback\"`\n\x13ListDevicesResponse\x12!\n\x07\x64\x65vices\x18\x01 \x03(\x0b\x32\x07.DeviceR\x07\x64\x65vices\x12&\n\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\'\n\x11PropertiesRequest\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"\x83\x01\n\x12PropertiesResponse\x12)\n\x10supported_codecs\x18\x01 \x03(\tR\x0fsupportedCodecs\x12\x1f\n\x0bsample_rate\x18\x02 \x03(\x05R\nsampleRate\x12!\n\x0cnum_channels\x18\x03 \x01(\x05R\x0bnumChannels2\xbb\'\n\x0c\x41udioService\x12\x61\n\x08GetAudio\x12\x10.GetAudioRequest\x1a\x0b.AudioChunk\"4\x82\xd3\xe4\x93\x02.\",/olivia/api/v1/service/audio/{name}/GetAudio0\x01\x12U\n\x04Play\x12\x0c.PlayRequest\x1a\r.PlayResponse\"0\x82\xd3\xe4\x93\x02*\"(/olivia/api/v1/service/audio/{name}/play\x12r\n\x0bPauseStream\x12\x13.PauseStreamRequest\x1a\x14.PauseStreamResponse\"8\x82\xd3\xe4\x93\x02\x32\"0/olivia/api/v1/service/audio/{name}/pause_stream\x12v\n\x0cResumeStream\x12\x14.ResumeStreamRequest\x1a\x15.ResumeStreamResponse\"9\x82\xd3\xe4\x93\x02\x33\"1/olivia/api/v1/service/audio/{name}/resume_stream\x12\x82\x01\n\x0fPreparePlayback\x12\x17.PreparePlaybackRequest\x1a\x18.PreparePlaybackResponse\"<\x82\xd3\xe4\x93\x02\x36\"4/olivia/api/v1/service/audio/{name}/prepare_playback\x12~\n\x0e\x43ommitPlayback\x12\x16.CommitPlaybackRequest\x1a\x17.CommitPlaybackResponse\";\x82\xd3\xe4\x93\x02\x35\"3/olivia/api/v1/service/audio/{name}/commit_playback\x12\x82\x01\n\x0fReleasePlayback\x12\x17.ReleasePlaybackRequest\x1a\x18.ReleasePlaybackResponse\"<\x82\xd3\xe4\x93\x02\x36\"4/olivia/api/v1/service/audio/{name}/release_playback\x12n\n\nSetProfile\x12\x12.SetProfileRequest\x1a\x13.SetProfileResponse\"7\x82\xd3\xe4\x93\x02\x31\"//olivia/api/v1/service/audio/{name}/set_profile\x12n\n\nGetProfile\x12\x12.GetProfileRequest\x1a\x13.GetProfileResponse\"7\x82\xd3\xe4\x93\x02\x31\"//olivia/api/v1/service/audio/{name}/get_profile\x12Z\n\x05SetEQ\x12\r.SetEQRequest\x1a\x0e.SetEQResponse\"2\x82\xd3\xe4\x93\x02,\"*/olivia/api/v1/service/audio/{name}/set_eq\x12Z\n\x05GetEQ\x12\r.GetEQRequest\x1a\x0e.GetEQResponse\"2\x82\xd3\xe4\x93\x02,\"*/olivia/api/v1/service/audio/{name}/get_eq\x12j\n\tGetLevels\x12\x11.GetLevelsRequest\x1a\x12.GetLevelsResponse\"6\x82\xd3\xe4\x93\x02\x30\"./olivia/api/v1/service/audio/{name}/get_levels\x12r\n\x0cStreamLevels\x12\x11.GetLevelsRequest\x1a\x12.GetLevelsResponse\"9\x82\xd3\xe4\x93\x02\x33\"1/olivia/api/v1/service/audio/{name}/stream_levels0\x01\x12w\n\x0eStreamSpectrum\x12\x16.StreamSpectrumRequest\x1a\x0e.SpectrumFrame\";\x82\xd3\xe4\x93\x02\x35\"3/olivia/api/v1/service/audio/{name}/stream_spectrum0\x01\x12z\n\x0eGetSpectrogram\x12\x16.GetSpectrogramRequest\x1a\x17.GetSpectrogramResponse\"7\x82\xd3\xe4\x93\x02\x31\"//olivia/api/v1/service/audio/{name}/spectrogram\x12r\n\x0b\x43\x61ptureClip\x12\x13.CaptureClipRequest\x1a\x14.CaptureClipResponse\"8\x82\xd3\xe4\x93\x02\x32\"0/olivia/api/v1/service/audio/{name}/capture_clip\x12v\n\x0eStreamImpulses\x12\x16.StreamImpulsesRequest\x1a\r.ImpulseEvent\";\x82\xd3\xe4\x93\x02\x35\"3/olivia/api/v1/service/audio/{name}/stream_impulses0\x01\x12{\n\rGetLevelStats\x12\x15.GetLevelStatsRequest\x1a\x16.GetLevelStatsResponse\";\x82\xd3\xe4\x93\x02\x35\"3/olivia/api/v1/service/audio/{name}/get_level_stats\x12\x85\x01\n\x11ListStreamHistory\x12\x13.ListHistoryRequest\x1a\x1a.ListStreamHistoryResponse\"?\x82\xd3\xe4\x93\x02\x39\"7/olivia/api/v1/service/audio/{name}/list_stream_history\x12o\n\nListEvents\x12\x13.ListHistoryRequest\x1a\x13.ListEventsResponse\"7\x82\xd3\xe4\x93\x02\x31\"//olivia/api/v1/service/audio/{name}/list_events\x12w\n\x0cListAuditLog\x12\x14.ListAuditLogRequest\x1a\x15.ListAuditLogResponse\":\x82\xd3\xe4\x93\x02\x34\"2/olivia/api/v1/service/audio/{name}/list_audit_log\x12\x8b\x01\n\x11ListActiveStreams\x12\x19.ListActiveStreamsRequest\x1a\x1a.ListActiveStreamsResponse\"?\x82\xd3\xe4\x93\x02\x39\"7/olivia/api/v1/service/audio/{name}/list_active_streams\x12~\n\x0eListRecordings\x12\x16.ListRecordingsRequest\x1a\x17.ListRecordingsResponse\";\x82\xd3\xe4\x93\x02\x35\"3/olivia/api/v1/service/audio/{name}/list_recordings\x12\x8b\x01\n\x11GetRecordingStats\x12\x19.GetRecordingStatsRequest\x1a\x1a.GetRecordingStatsResponse\"?\x82\xd3\xe4\x93\x02\x39\"7/olivia/api/v1/service/audio/{name}/get_recording_stats\x12v\n\x0cGetRecording\x12\x14.GetRecordingRequest\x1a\x15.GetRecordingResponse\"9\x82\xd3\xe4\x93\x02\x33\"1/olivia/api/v1/service/audio/{name}/get_recording\x12w\n\x0fStreamRecording\x12\x17.StreamRecordingRequest\x1a\x0b.AudioChunk\"<\x82\xd3\xe4\x93\x02\x36\"4/olivia/api/v1/service/audio/{name}/stream_recording0\x01\x12~\n\x0eStartRecording\x12\x16.StartRecordingRequest\x1a\x17.StartRecordingResponse\";\x82\xd3\xe4\x93\x02\x35\"3/olivia/api/v1/service/audio/{name}/start_recording\x12z\n\rStopRecording\x12\x15.StopRecordingRequest\x1a\x16.StopRecordingResponse\":\x82\xd3\xe4\x93\x02\x34\"2/olivia/api/v1/service/audio/{name}/stop_recording\x12v\n\x0cListSegments\x12\x14.ListSegmentsRequest\x1a\x15.ListSegmentsResponse\"9\x82\xd3\xe4\x93\x02\x33\"1/olivia/api/v1/service/audio/{name}/list_segments\x12\x9b\x01\n\x15StartRecordingSession\x12\x1d.StartRecordingSessionRequest\x1a\x1e.StartRecordingSessionResponse\"C\x82\xd3\xe4\x93\x02=\";/olivia/api/v1/service/audio/{name}/start_recording_session\x12\x97\x01\n\x14StopRecordingSession\x12\x1c.StopRecordingSessionRequest\x1a\x1d.StopRecordingSessionResponse\"B\x82\xd3\xe4\x93\x02<\":/olivia/api/v1/service/audio/{name}/stop_recording_session\x12\x93\x01\n\x13GetRecordingSession\x12\x1b.GetRecordingSessionRequest\x1a\x1c.GetRecordingSessionResponse\"A\x82\xd3\xe4\x93\x02;\"9/olivia/api/v1/service/audio/{name}/get_recording_session\x12\x8a\x01\n\x11ScheduleRecording\x12\x19.ScheduleRecordingRequest\x1a\x1a.ScheduleRecordingResponse\">\x82\xd3\xe4\x93\x02\x38\"6/olivia/api/v1/service/audio/{name}/schedule_recording\x12\xa7\x01\n\x18\x43\x61ncelScheduledRecording\x12 .CancelScheduledRecordingRequest\x1a!.CancelScheduledRecordingResponse\"F\x82\xd3\xe4\x93\x02@\">/olivia/api/v1/service/audio/{name}/cancel_scheduled_recording\x12\x83\x01\n\x0fGetTriggerState\x12\x17.GetTriggerStateRequest\x1a\x18.GetTriggerStateResponse\"=\x82\xd3\xe4\x93\x02\x37\"5/olivia/api/v1/service/audio/{name}/get_trigger_state\x12r\n\x0bListDevices\x12\x13.ListDevicesRequest\x1a\x14.ListDevicesResponse\"8\x82\xd3\xe4\x93\x02\x32\"0/olivia/api/v1/service/audio/{name}/list_devices\x12\x83\x01\n\x0fGetLatencyStats\x12\x17.GetLatencyStatsRequest\x1a\x18.GetLatencyStatsResponse\"=\x82\xd3\xe4\x93\x02\x37\"5/olivia/api/v1/service/audio/{name}/get_latency_stats\x12\x66\n\x08GetStats\x12\x10.GetStatsRequest\x1a\x11.GetStatsResponse\"5\x82\xd3\xe4\x93\x02/\"-/olivia/api/v1/service/audio/{name}/get_stats\x12j\n\tGetHealth\x12\x11.GetHealthRequest\x1a\x12.GetHealthResponse\"6\x82\xd3\xe4\x93\x02\x30\"./olivia/api/v1/service/audio/{name}/get_health\x12j\n\tDoCommand\x12\x11.DoCommandRequest\x1a\x12.DoCommandResponse\"6\x82\xd3\xe4\x93\x02\x30\"./olivia/api/v1/service/audio/{name}/do_command\x12m\n\nProperties\x12\x12.PropertiesRequest\x1a\x13.PropertiesResponse\"6\x82\xd3\xe4\x93\x02\x30\"./olivia/api/v1/service/audio/{name}/propertiesB\tZ\x07./audiob\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -37,22 +37,274 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_AUDIOSERVICE'].methods_by_name['GetAudio']._serialized_options = b'\202\323\344\223\002.\",/olivia/api/v1/service/audio/{name}/GetAudio'
   _globals['_AUDIOSERVICE'].methods_by_name['Play']._loaded_options = None
   _globals['_AUDIOSERVICE'].methods_by_name['Play']._serialized_options = b'\202\323\344\223\002*\"(/olivia/api/v1/service/audio/{name}/play'
+  _globals['_AUDIOSERVICE'].methods_by_name['PauseStream']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['PauseStream']._serialized_options = b'\202\323\344\223\0022\"0/olivia/api/v1/service/audio/{name}/pause_stream'
+  _globals['_AUDIOSERVICE'].methods_by_name['ResumeStream']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ResumeStream']._serialized_options = b'\202\323\344\223\0023\"1/olivia/api/v1/service/audio/{name}/resume_stream'
+  _globals['_AUDIOSERVICE'].methods_by_name['PreparePlayback']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['PreparePlayback']._serialized_options = b'\202\323\344\223\0026\"4/olivia/api/v1/service/audio/{name}/prepare_playback'
+  _globals['_AUDIOSERVICE'].methods_by_name['CommitPlayback']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['CommitPlayback']._serialized_options = b'\202\323\344\223\0025\"3/olivia/api/v1/service/audio/{name}/commit_playback'
+  _globals['_AUDIOSERVICE'].methods_by_name['ReleasePlayback']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ReleasePlayback']._serialized_options = b'\202\323\344\223\0026\"4/olivia/api/v1/service/audio/{name}/release_playback'
+  _globals['_AUDIOSERVICE'].methods_by_name['SetProfile']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['SetProfile']._serialized_options = b'\202\323\344\223\0021\"//olivia/api/v1/service/audio/{name}/set_profile'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetProfile']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetProfile']._serialized_options = b'\202\323\344\223\0021\"//olivia/api/v1/service/audio/{name}/get_profile'
+  _globals['_AUDIOSERVICE'].methods_by_name['SetEQ']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['SetEQ']._serialized_options = b'\202\323\344\223\002,\"*/olivia/api/v1/service/audio/{name}/set_eq'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetEQ']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetEQ']._serialized_options = b'\202\323\344\223\002,\"*/olivia/api/v1/service/audio/{name}/get_eq'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetLevels']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetLevels']._serialized_options = b'\202\323\344\223\0020\"./olivia/api/v1/service/audio/{name}/get_levels'
+  _globals['_AUDIOSERVICE'].methods_by_name['StreamLevels']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['StreamLevels']._serialized_options = b'\202\323\344\223\0023\"1/olivia/api/v1/service/audio/{name}/stream_levels'
+  _globals['_AUDIOSERVICE'].methods_by_name['StreamSpectrum']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['StreamSpectrum']._serialized_options = b'\202\323\344\223\0025\"3/olivia/api/v1/service/audio/{name}/stream_spectrum'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetSpectrogram']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetSpectrogram']._serialized_options = b'\202\323\344\223\0021\"//olivia/api/v1/service/audio/{name}/spectrogram'
+  _globals['_AUDIOSERVICE'].methods_by_name['CaptureClip']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['CaptureClip']._serialized_options = b'\202\323\344\223\0022\"0/olivia/api/v1/service/audio/{name}/capture_clip'
+  _globals['_AUDIOSERVICE'].methods_by_name['StreamImpulses']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['StreamImpulses']._serialized_options = b'\202\323\344\223\0025\"3/olivia/api/v1/service/audio/{name}/stream_impulses'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetLevelStats']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetLevelStats']._serialized_options = b'\202\323\344\223\0025\"3/olivia/api/v1/service/audio/{name}/get_level_stats'
+  _globals['_AUDIOSERVICE'].methods_by_name['ListStreamHistory']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ListStreamHistory']._serialized_options = b'\202\323\344\223\0029\"7/olivia/api/v1/service/audio/{name}/list_stream_history'
+  _globals['_AUDIOSERVICE'].methods_by_name['ListEvents']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ListEvents']._serialized_options = b'\202\323\344\223\0021\"//olivia/api/v1/service/audio/{name}/list_events'
+  _globals['_AUDIOSERVICE'].methods_by_name['ListAuditLog']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ListAuditLog']._serialized_options = b'\202\323\344\223\0024\"2/olivia/api/v1/service/audio/{name}/list_audit_log'
+  _globals['_AUDIOSERVICE'].methods_by_name['ListActiveStreams']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ListActiveStreams']._serialized_options = b'\202\323\344\223\0029\"7/olivia/api/v1/service/audio/{name}/list_active_streams'
+  _globals['_AUDIOSERVICE'].methods_by_name['ListRecordings']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ListRecordings']._serialized_options = b'\202\323\344\223\0025\"3/olivia/api/v1/service/audio/{name}/list_recordings'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetRecordingStats']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetRecordingStats']._serialized_options = b'\202\323\344\223\0029\"7/olivia/api/v1/service/audio/{name}/get_recording_stats'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetRecording']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetRecording']._serialized_options = b'\202\323\344\223\0023\"1/olivia/api/v1/service/audio/{name}/get_recording'
+  _globals['_AUDIOSERVICE'].methods_by_name['StreamRecording']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['StreamRecording']._serialized_options = b'\202\323\344\223\0026\"4/olivia/api/v1/service/audio/{name}/stream_recording'
+  _globals['_AUDIOSERVICE'].methods_by_name['StartRecording']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['StartRecording']._serialized_options = b'\202\323\344\223\0025\"3/olivia/api/v1/service/audio/{name}/start_recording'
+  _globals['_AUDIOSERVICE'].methods_by_name['StopRecording']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['StopRecording']._serialized_options = b'\202\323\344\223\0024\"2/olivia/api/v1/service/audio/{name}/stop_recording'
+  _globals['_AUDIOSERVICE'].methods_by_name['ListSegments']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ListSegments']._serialized_options = b'\202\323\344\223\0023\"1/olivia/api/v1/service/audio/{name}/list_segments'
+  _globals['_AUDIOSERVICE'].methods_by_name['StartRecordingSession']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['StartRecordingSession']._serialized_options = b'\202\323\344\223\002=\";/olivia/api/v1/service/audio/{name}/start_recording_session'
+  _globals['_AUDIOSERVICE'].methods_by_name['StopRecordingSession']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['StopRecordingSession']._serialized_options = b'\202\323\344\223\002<\":/olivia/api/v1/service/audio/{name}/stop_recording_session'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetRecordingSession']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetRecordingSession']._serialized_options = b'\202\323\344\223\002;\"9/olivia/api/v1/service/audio/{name}/get_recording_session'
+  _globals['_AUDIOSERVICE'].methods_by_name['ScheduleRecording']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ScheduleRecording']._serialized_options = b'\202\323\344\223\0028\"6/olivia/api/v1/service/audio/{name}/schedule_recording'
+  _globals['_AUDIOSERVICE'].methods_by_name['CancelScheduledRecording']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['CancelScheduledRecording']._serialized_options = b'\202\323\344\223\002@\">/olivia/api/v1/service/audio/{name}/cancel_scheduled_recording'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetTriggerState']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetTriggerState']._serialized_options = b'\202\323\344\223\0027\"5/olivia/api/v1/service/audio/{name}/get_trigger_state'
+  _globals['_AUDIOSERVICE'].methods_by_name['ListDevices']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['ListDevices']._serialized_options = b'\202\323\344\223\0022\"0/olivia/api/v1/service/audio/{name}/list_devices'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetLatencyStats']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetLatencyStats']._serialized_options = b'\202\323\344\223\0027\"5/olivia/api/v1/service/audio/{name}/get_latency_stats'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetStats']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetStats']._serialized_options = b'\202\323\344\223\002/\"-/olivia/api/v1/service/audio/{name}/get_stats'
+  _globals['_AUDIOSERVICE'].methods_by_name['GetHealth']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['GetHealth']._serialized_options = b'\202\323\344\223\0020\"./olivia/api/v1/service/audio/{name}/get_health'
+  _globals['_AUDIOSERVICE'].methods_by_name['DoCommand']._loaded_options = None
+  _globals['_AUDIOSERVICE'].methods_by_name['DoCommand']._serialized_options = b'\202\323\344\223\0020\"./olivia/api/v1/service/audio/{name}/do_command'
   _globals['_AUDIOSERVICE'].methods_by_name['Properties']._loaded_options = None
   _globals['_AUDIOSERVICE'].methods_by_name['Properties']._serialized_options = b'\202\323\344\223\0020\"./olivia/api/v1/service/audio/{name}/properties'
   _globals['_AUDIOINFO']._serialized_start=45
   _globals['_AUDIOINFO']._serialized_end=146
   _globals['_GETAUDIOREQUEST']._serialized_start=149
-  _globals['_GETAUDIOREQUEST']._serialized_end=379
-  _globals['_AUDIOCHUNK']._serialized_start=382
-  _globals['_AUDIOCHUNK']._serialized_end=609
-  _globals['_PLAYREQUEST']._serialized_start=611
-  _globals['_PLAYREQUEST']._serialized_end=707
-  _globals['_PLAYRESPONSE']._serialized_start=709
-  _globals['_PLAYRESPONSE']._serialized_end=743
-  _globals['_PROPERTIESREQUEST']._serialized_start=745
-  _globals['_PROPERTIESREQUEST']._serialized_end=784
-  _globals['_PROPERTIESRESPONSE']._serialized_start=787
-  _globals['_PROPERTIESRESPONSE']._serialized_end=918
-  _globals['_AUDIOSERVICE']._serialized_start=921
-  _globals['_AUDIOSERVICE']._serialized_end=1232
+  _globals['_GETAUDIOREQUEST']._serialized_end=1415
+  _globals['_AUDIOCHUNK']._serialized_start=1418
+  _globals['_AUDIOCHUNK']._serialized_end=2077
+  _globals['_STREAMHEADER']._serialized_start=2079
+  _globals['_STREAMHEADER']._serialized_end=2190
+  _globals['_TIMECODE']._serialized_start=2193
+  _globals['_TIMECODE']._serialized_end=2439
+  _globals['_PLAYREQUEST']._serialized_start=2442
+  _globals['_PLAYREQUEST']._serialized_end=2627
+  _globals['_PLAYRESPONSE']._serialized_start=2629
+  _globals['_PLAYRESPONSE']._serialized_end=2663
+  _globals['_PAUSESTREAMREQUEST']._serialized_start=2665
+  _globals['_PAUSESTREAMREQUEST']._serialized_end=2736
+  _globals['_PAUSESTREAMRESPONSE']._serialized_start=2738
+  _globals['_PAUSESTREAMRESPONSE']._serialized_end=2759
+  _globals['_RESUMESTREAMREQUEST']._serialized_start=2761
+  _globals['_RESUMESTREAMREQUEST']._serialized_end=2833
+  _globals['_RESUMESTREAMRESPONSE']._serialized_start=2835
+  _globals['_RESUMESTREAMRESPONSE']._serialized_end=2857
+  _globals['_PREPAREPLAYBACKREQUEST']._serialized_start=2859
+  _globals['_PREPAREPLAYBACKREQUEST']._serialized_end=2966
+  _globals['_PREPAREPLAYBACKRESPONSE']._serialized_start=2968
+  _globals['_PREPAREPLAYBACKRESPONSE']._serialized_end=3017
+  _globals['_COMMITPLAYBACKREQUEST']._serialized_start=3019
+  _globals['_COMMITPLAYBACKREQUEST']._serialized_end=3140
+  _globals['_COMMITPLAYBACKRESPONSE']._serialized_start=3142
+  _globals['_COMMITPLAYBACKRESPONSE']._serialized_end=3166
+  _globals['_RELEASEPLAYBACKREQUEST']._serialized_start=3168
+  _globals['_RELEASEPLAYBACKREQUEST']._serialized_end=3236
+  _globals['_RELEASEPLAYBACKRESPONSE']._serialized_start=3238
+  _globals['_RELEASEPLAYBACKRESPONSE']._serialized_end=3263
+  _globals['_SETPROFILEREQUEST']._serialized_start=3265
+  _globals['_SETPROFILEREQUEST']._serialized_end=3330
+  _globals['_SETPROFILERESPONSE']._serialized_start=3332
+  _globals['_SETPROFILERESPONSE']._serialized_end=3352
+  _globals['_GETPROFILEREQUEST']._serialized_start=3354
+  _globals['_GETPROFILEREQUEST']._serialized_end=3393
+  _globals['_GETPROFILERESPONSE']._serialized_start=3395
+  _globals['_GETPROFILERESPONSE']._serialized_end=3499
+  _globals['_EQBAND']._serialized_start=3501
+  _globals['_EQBAND']._serialized_end=3603
+  _globals['_SETEQREQUEST']._serialized_start=3605
+  _globals['_SETEQREQUEST']._serialized_end=3670
+  _globals['_SETEQRESPONSE']._serialized_start=3672
+  _globals['_SETEQRESPONSE']._serialized_end=3687
+  _globals['_GETEQREQUEST']._serialized_start=3689
+  _globals['_GETEQREQUEST']._serialized_end=3723
+  _globals['_GETEQRESPONSE']._serialized_start=3725
+  _globals['_GETEQRESPONSE']._serialized_end=3771
+  _globals['_GETLEVELSREQUEST']._serialized_start=3773
+  _globals['_GETLEVELSREQUEST']._serialized_end=3850
+  _globals['_CHANNELLEVEL']._serialized_start=3852
+  _globals['_CHANNELLEVEL']._serialized_end=3960
+  _globals['_GETLEVELSRESPONSE']._serialized_start=3962
+  _globals['_GETLEVELSRESPONSE']._serialized_end=4077
+  _globals['_STREAMSPECTRUMREQUEST']._serialized_start=4079
+  _globals['_STREAMSPECTRUMREQUEST']._serialized_end=4176
+  _globals['_SPECTRUMFRAME']._serialized_start=4178
+  _globals['_SPECTRUMFRAME']._serialized_end=4301
+  _globals['_GETSPECTROGRAMREQUEST']._serialized_start=4304
+  _globals['_GETSPECTROGRAMREQUEST']._serialized_end=4514
+  _globals['_GETSPECTROGRAMRESPONSE']._serialized_start=4517
+  _globals['_GETSPECTROGRAMRESPONSE']._serialized_end=4707
+  _globals['_CAPTURECLIPREQUEST']._serialized_start=4710
+  _globals['_CAPTURECLIPREQUEST']._serialized_end=4858
+  _globals['_CAPTURECLIPRESPONSE']._serialized_start=4861
+  _globals['_CAPTURECLIPRESPONSE']._serialized_end=5077
+  _globals['_STREAMIMPULSESREQUEST']._serialized_start=5080
+  _globals['_STREAMIMPULSESREQUEST']._serialized_end=5265
+  _globals['_IMPULSEEVENT']._serialized_start=5268
+  _globals['_IMPULSEEVENT']._serialized_end=5521
+  _globals['_GETLEVELSTATSREQUEST']._serialized_start=5524
+  _globals['_GETLEVELSTATSREQUEST']._serialized_end=5676
+  _globals['_LEVELSTATSBUCKET']._serialized_start=5679
+  _globals['_LEVELSTATSBUCKET']._serialized_end=5945
+  _globals['_GETLEVELSTATSRESPONSE']._serialized_start=5947
+  _globals['_GETLEVELSTATSRESPONSE']._serialized_end=6015
+  _globals['_LISTHISTORYREQUEST']._serialized_start=6018
+  _globals['_LISTHISTORYREQUEST']._serialized_end=6317
+  _globals['_STREAMRECORD']._serialized_start=6320
+  _globals['_STREAMRECORD']._serialized_end=6690
+  _globals['_LISTSTREAMHISTORYRESPONSE']._serialized_start=6692
+  _globals['_LISTSTREAMHISTORYRESPONSE']._serialized_end=6800
+  _globals['_EVENTRECORD']._serialized_start=6803
+  _globals['_EVENTRECORD']._serialized_end=6981
+  _globals['_LISTEVENTSRESPONSE']._serialized_start=6983
+  _globals['_LISTEVENTSRESPONSE']._serialized_end=7081
+  _globals['_LISTAUDITLOGREQUEST']._serialized_start=7084
+  _globals['_LISTAUDITLOGREQUEST']._serialized_end=7327
+  _globals['_AUDITRECORD']._serialized_start=7330
+  _globals['_AUDITRECORD']._serialized_end=7571
+  _globals['_LISTAUDITLOGRESPONSE']._serialized_start=7573
+  _globals['_LISTAUDITLOGRESPONSE']._serialized_end=7675
+  _globals['_LISTACTIVESTREAMSREQUEST']._serialized_start=7678
+  _globals['_LISTACTIVESTREAMSREQUEST']._serialized_end=7963
+  _globals['_LISTACTIVESTREAMSRESPONSE']._serialized_start=7965
+  _globals['_LISTACTIVESTREAMSRESPONSE']._serialized_end=8073
+  _globals['_LISTRECORDINGSREQUEST']._serialized_start=8076
+  _globals['_LISTRECORDINGSREQUEST']._serialized_end=8533
+  _globals['_STOREDRECORDING']._serialized_start=8536
+  _globals['_STOREDRECORDING']._serialized_end=8836
+  _globals['_GETRECORDINGREQUEST']._serialized_start=8838
+  _globals['_GETRECORDINGREQUEST']._serialized_end=8895
+  _globals['_GETRECORDINGRESPONSE']._serialized_start=8897
+  _globals['_GETRECORDINGRESPONSE']._serialized_end=8967
+  _globals['_STREAMRECORDINGREQUEST']._serialized_start=8969
+  _globals['_STREAMRECORDINGREQUEST']._serialized_end=9088
+  _globals['_LISTRECORDINGSRESPONSE']._serialized_start=9090
+  _globals['_LISTRECORDINGSRESPONSE']._serialized_end=9204
+  _globals['_GETLATENCYSTATSREQUEST']._serialized_start=9206
+  _globals['_GETLATENCYSTATSREQUEST']._serialized_end=9250
+  _globals['_GETLATENCYSTATSRESPONSE']._serialized_start=9253
+  _globals['_GETLATENCYSTATSRESPONSE']._serialized_end=9434
+  _globals['_GETSTATSREQUEST']._serialized_start=9436
+  _globals['_GETSTATSREQUEST']._serialized_end=9473
+  _globals['_GETSTATSRESPONSE']._serialized_start=9476
+  _globals['_GETSTATSRESPONSE']._serialized_end=9913
+  _globals['_GETHEALTHREQUEST']._serialized_start=9915
+  _globals['_GETHEALTHREQUEST']._serialized_end=9953
+  _globals['_DEVICEHEALTH']._serialized_start=9956
+  _globals['_DEVICEHEALTH']._serialized_end=10102
+  _globals['_GETHEALTHRESPONSE']._serialized_start=10104
+  _globals['_GETHEALTHRESPONSE']._serialized_end=10229
+  _globals['_DOCOMMANDREQUEST']._serialized_start=10231
+  _globals['_DOCOMMANDREQUEST']._serialized_end=10304
+  _globals['_DOCOMMANDRESPONSE']._serialized_start=10306
+  _globals['_DOCOMMANDRESPONSE']._serialized_end=10358
+  _globals['_GETRECORDINGSTATSREQUEST']._serialized_start=10360
+  _globals['_GETRECORDINGSTATSREQUEST']._serialized_end=10406
+  _globals['_GETRECORDINGSTATSRESPONSE']._serialized_start=10409
+  _globals['_GETRECORDINGSTATSRESPONSE']._serialized_end=10824
+  _globals['_STARTRECORDINGREQUEST']._serialized_start=10827
+  _globals['_STARTRECORDINGREQUEST']._serialized_end=10974
+  _globals['_STARTRECORDINGRESPONSE']._serialized_start=10976
+  _globals['_STARTRECORDINGRESPONSE']._serialized_end=11035
+  _globals['_STOPRECORDINGREQUEST']._serialized_start=11037
+  _globals['_STOPRECORDINGREQUEST']._serialized_end=11114
+  _globals['_STOPRECORDINGRESPONSE']._serialized_start=11116
+  _globals['_STOPRECORDINGRESPONSE']._serialized_end=11186
+  _globals['_LISTSEGMENTSREQUEST']._serialized_start=11188
+  _globals['_LISTSEGMENTSREQUEST']._serialized_end=11264
+  _globals['_RECORDINGSEGMENT']._serialized_start=11267
+  _globals['_RECORDINGSEGMENT']._serialized_end=11448
+  _globals['_LISTSEGMENTSRESPONSE']._serialized_start=11450
+  _globals['_LISTSEGMENTSRESPONSE']._serialized_end=11565
+  _globals['_RECORDINGTRACK']._serialized_start=11567
+  _globals['_RECORDINGTRACK']._serialized_end=11659
+  _globals['_STARTRECORDINGSESSIONREQUEST']._serialized_start=11662
+  _globals['_STARTRECORDINGSESSIONREQUEST']._serialized_end=11818
+  _globals['_STARTRECORDINGSESSIONRESPONSE']._serialized_start=11820
+  _globals['_STARTRECORDINGSESSIONRESPONSE']._serialized_end=11882
+  _globals['_STOPRECORDINGSESSIONREQUEST']._serialized_start=11884
+  _globals['_STOPRECORDINGSESSIONREQUEST']._serialized_end=11964
+  _globals['_STOPRECORDINGSESSIONRESPONSE']._serialized_start=11966
+  _globals['_STOPRECORDINGSESSIONRESPONSE']._serialized_end=12041
+  _globals['_GETRECORDINGSESSIONREQUEST']._serialized_start=12043
+  _globals['_GETRECORDINGSESSIONREQUEST']._serialized_end=12122
+  _globals['_GETRECORDINGSESSIONRESPONSE']._serialized_start=12124
+  _globals['_GETRECORDINGSESSIONRESPONSE']._serialized_end=12198
+  _globals['_RECORDINGSESSION']._serialized_start=12201
+  _globals['_RECORDINGSESSION']._serialized_end=12345
+  _globals['_TRACKSTATUS']._serialized_start=12348
+  _globals['_TRACKSTATUS']._serialized_end=12516
+  _globals['_RECORDINGWINDOW']._serialized_start=12518
+  _globals['_RECORDINGWINDOW']._serialized_end=12577
+  _globals['_SCHEDULERECORDINGREQUEST']._serialized_start=12580
+  _globals['_SCHEDULERECORDINGREQUEST']._serialized_end=12803
+  _globals['_SCHEDULERECORDINGRESPONSE']._serialized_start=12805
+  _globals['_SCHEDULERECORDINGRESPONSE']._serialized_end=12927
+  _globals['_CANCELSCHEDULEDRECORDINGREQUEST']._serialized_start=12929
+  _globals['_CANCELSCHEDULEDRECORDINGREQUEST']._serialized_end=13015
+  _globals['_CANCELSCHEDULEDRECORDINGRESPONSE']._serialized_start=13017
+  _globals['_CANCELSCHEDULEDRECORDINGRESPONSE']._serialized_end=13098
+  _globals['_GETTRIGGERSTATEREQUEST']._serialized_start=13100
+  _globals['_GETTRIGGERSTATEREQUEST']._serialized_end=13144
+  _globals['_GETTRIGGERSTATERESPONSE']._serialized_start=13147
+  _globals['_GETTRIGGERSTATERESPONSE']._serialized_end=13421
+  _globals['_LISTDEVICESREQUEST']._serialized_start=13424
+  _globals['_LISTDEVICESREQUEST']._serialized_end=13582
+  _globals['_DEVICE']._serialized_start=13585
+  _globals['_DEVICE']._serialized_end=13791
+  _globals['_LISTDEVICESRESPONSE']._serialized_start=13793
+  _globals['_LISTDEVICESRESPONSE']._serialized_end=13889
+  _globals['_PROPERTIESREQUEST']._serialized_start=13891
+  _globals['_PROPERTIESREQUEST']._serialized_end=13930
+  _globals['_PROPERTIESRESPONSE']._serialized_start=13933
+  _globals['_PROPERTIESRESPONSE']._serialized_end=14064
+  _globals['_AUDIOSERVICE']._serialized_start=14067
+  _globals['_AUDIOSERVICE']._serialized_end=19118
 # @@protoc_insertion_point(module_scope)
@@ -44,13 +44,113 @@ class GetAudioRequest(google.protobuf.message.Message):
     CODEC_FIELD_NUMBER: builtins.int
     REQUEST_ID_FIELD_NUMBER: builtins.int
     MAX_DURATION_SECONDS_FIELD_NUMBER: builtins.int
-    PREVIOUS_TIMESTAMP_FIELD_NUMBER: builtins.int
+    SAMPLE_RATE_FIELD_NUMBER: builtins.int
+    NUM_CHANNELS_FIELD_NUMBER: builtins.int
+    ONLY_WHEN_FIELD_NUMBER: builtins.int
+    PRE_ROLL_SECONDS_FIELD_NUMBER: builtins.int
+    POST_ROLL_SECONDS_FIELD_NUMBER: builtins.int
+    VAD_FIELD_NUMBER: builtins.int
+    SPEECH_ONLY_FIELD_NUMBER: builtins.int
+    TRIM_SILENCE_FIELD_NUMBER: builtins.int
+    SILENCE_THRESHOLD_DBFS_FIELD_NUMBER: builtins.int
+    SILENCE_HANGOVER_SECONDS_FIELD_NUMBER: builtins.int
+    NOISE_SUPPRESSION_FIELD_NUMBER: builtins.int
+    AGC_FIELD_NUMBER: builtins.int
+    AGC_TARGET_DBFS_FIELD_NUMBER: builtins.int
+    ALSO_SAVE_AS_FIELD_NUMBER: builtins.int
+    STRICT_FIELD_NUMBER: builtins.int
+    RESUMABLE_FIELD_NUMBER: builtins.int
+    RESUME_TOKEN_FIELD_NUMBER: builtins.int
+    CHUNK_DURATION_SECONDS_FIELD_NUMBER: builtins.int
+    DROP_POLICY_FIELD_NUMBER: builtins.int
+    BUFFER_CHUNKS_FIELD_NUMBER: builtins.int
+    COMPRESSION_FIELD_NUMBER: builtins.int
+    BATCH_CHUNKS_FIELD_NUMBER: builtins.int
+    BATCH_MAX_DELAY_SECONDS_FIELD_NUMBER: builtins.int
+    ADAPTIVE_BITRATE_FIELD_NUMBER: builtins.int
+    MIN_BITRATE_KBPS_FIELD_NUMBER: builtins.int
+    MAX_BITRATE_KBPS_FIELD_NUMBER: builtins.int
+    HEARTBEAT_SECONDS_FIELD_NUMBER: builtins.int
+    CHECKSUMS_FIELD_NUMBER: builtins.int
     name: builtins.str
     duration_seconds: builtins.float
     codec: builtins.str
     request_id: builtins.str
     max_duration_seconds: builtins.float
-    previous_timestamp: builtins.float
+    sample_rate: builtins.int
+    """0 keeps the source rate"""
+    num_channels: builtins.int
+    """0 keeps the source channel count"""
+    pre_roll_seconds: builtins.float
+    """audio kept from before an only_when match, defaults to 0.5"""
+    post_roll_seconds: builtins.float
+    """audio kept after an only_when match ends, defaults to 1"""
+    vad: builtins.str
+    """voice activity detector that sets speech on every chunk ("energy", "webrtc"), empty for none"""
+    speech_only: builtins.bool
+    """only deliver chunks containing speech, using vad or "energy\""""
+    trim_silence: builtins.bool
+    """drop stretches quieter than silence_threshold_dbfs for longer than the hangover"""
+    silence_threshold_dbfs: builtins.float
+    """defaults to -50"""
+    silence_hangover_seconds: builtins.float
+    """defaults to 0.3"""
+    noise_suppression: builtins.str
+    """engine removing background noise first ("spectral", "rnnoise"), empty for none"""
+    agc: builtins.bool
+    """automatic gain control holding the level near agc_target_dbfs"""
+    agc_target_dbfs: builtins.float
+    """defaults to -20"""
+    also_save_as: builtins.str
+    """also save the chunks as delivered under this name in the server's recording store"""
+    strict: builtins.bool
+    """fail with FAILED_PRECONDITION instead of converting when the source doesn't capture exactly the
+    requested codec, sample_rate and num_channels; codec must be set
+    """
+    resumable: builtins.bool
+    """keep the capture going for a while after the client drops, so the stream can be resumed
+    with the resume_token of the last chunk received
+    """
+    resume_token: builtins.str
+    """continue the resumable stream right after the chunk that carried this token; the other
+    fields but name are ignored, and an expired token fails with FAILED_PRECONDITION
+    """
+    chunk_duration_seconds: builtins.float
+    """regroup the audio into chunks this long (0.01 to 0.5), the last one of a stream or before a
+    gap or format change shorter; 0 delivers chunks as the source captures them. Needs a raw pcm codec
+    """
+    drop_policy: builtins.str
+    """what to do when the client falls more than buffer_chunks behind the capture: "drop_oldest" (the
+    default) or "drop_newest" chunks, reported as gaps, or "block", which holds up the capture
+    """
+    buffer_chunks: builtins.int
+    """defaults to 50"""
+    compression: builtins.str
+    """compress the stream's messages on the wire, whatever the codec: "gzip" or "snappy", empty for none.
+    The client has to accept the compression in grpc-accept-encoding
+    """
+    batch_chunks: builtins.int
+    """send up to this many chunks (2 to 100) in one message's batch, cutting the per-message overhead of
+    short chunks on high-latency links; 0 or 1 sends every chunk on its own
+    """
+    batch_max_delay_seconds: builtins.float
+    """longest a chunk waits for its batch to fill, defaults to 1"""
+    adaptive_bitrate: builtins.bool
+    """encode mp3 on the server from the shared capture, stepping the bitrate between min_bitrate_kbps
+    and max_bitrate_kbps (32 and 128 by default) to keep up with the client; needs codec "mp3\"
+    """
+    min_bitrate_kbps: builtins.int
+    max_bitrate_kbps: builtins.int
+    heartbeat_seconds: builtins.float
+    """sends an empty message marked heartbeat whenever the stream has sent nothing for this long,
+    so that NATs and proxies keep the connection of a quiet stream open; from 1 to 300, 0 for none
+    """
+    checksums: builtins.bool
+    """sets crc32 on each chunk, for the client to catch audio corrupted on the way"""
+    @property
+    def only_when(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]:
+        """only deliver audio while one of these conditions holds ("sound", "speech", "alarm", "impulse")"""
+
     def __init__(
         self,
         *,
@@ -59,9 +159,36 @@ class GetAudioRequest(google.protobuf.message.Message):
         codec: builtins.str = ...,
         request_id: builtins.str = ...,
         max_duration_seconds: builtins.float = ...,
-        previous_timestamp: builtins.float = ...,
+        sample_rate: builtins.int = ...,
+        num_channels: builtins.int = ...,
+        only_when: collections.abc.Iterable[builtins.str] | None = ...,
+        pre_roll_seconds: builtins.float = ...,
+        post_roll_seconds: builtins.float = ...,
+        vad: builtins.str = ...,
+        speech_only: builtins.bool = ...,
+        trim_silence: builtins.bool = ...,
+        silence_threshold_dbfs: builtins.float = ...,
+        silence_hangover_seconds: builtins.float = ...,
+        noise_suppression: builtins.str = ...,
+        agc: builtins.bool = ...,
+        agc_target_dbfs: builtins.float = ...,
+        also_save_as: builtins.str = ...,
+        strict: builtins.bool = ...,
+        resumable: builtins.bool = ...,
+        resume_token: builtins.str = ...,
+        chunk_duration_seconds: builtins.float = ...,
+        drop_policy: builtins.str = ...,
+        buffer_chunks: builtins.int = ...,
+        compression: builtins.str = ...,
+        batch_chunks: builtins.int = ...,
+        batch_max_delay_seconds: builtins.float = ...,
+        adaptive_bitrate: builtins.bool = ...,
+        min_bitrate_kbps: builtins.int = ...,
+        max_bitrate_kbps: builtins.int = ...,
+        heartbeat_seconds: builtins.float = ...,
+        checksums: builtins.bool = ...,
     ) -> None: ...
-    def ClearField(self, field_name: typing.Literal["codec", b"codec", "duration_seconds", b"duration_seconds", "max_duration_seconds", b"max_duration_seconds", "name", b"name", "previous_timestamp", b"previous_timestamp", "request_id", b"request_id"]) -> None: ...
+    def ClearField(self, field_name: typing.Literal["adaptive_bitrate", b"adaptive_bitrate", "agc", b"agc", "agc_target_dbfs", b"agc_target_dbfs", "also_save_as", b"also_save_as", "batch_chunks", b"batch_chunks", "batch_max_delay_seconds", b"batch_max_delay_seconds", "buffer_chunks", b"buffer_chunks", "checksums", b"checksums", "chunk_duration_seconds", b"chunk_duration_seconds", "codec", b"codec", "compression", b"compression", "drop_policy", b"drop_policy", "duration_seconds", b"duration_seconds", "heartbeat_seconds", b"heartbeat_seconds", "max_bitrate_kbps", b"max_bitrate_kbps", "max_duration_seconds", b"max_duration_seconds", "min_bitrate_kbps", b"min_bitrate_kbps", "name", b"name", "noise_suppression", b"noise_suppression", "num_channels", b"num_channels", "only_when", b"only_when", "post_roll_seconds", b"post_roll_seconds", "pre_roll_seconds", b"pre_roll_seconds", "request_id", b"request_id", "resumable", b"resumable", "resume_token", b"resume_token", "sample_rate", b"sample_rate", "silence_hangover_seconds", b"silence_hangover_seconds", "silence_threshold_dbfs", b"silence_threshold_dbfs", "speech_only", b"speech_only", "strict", b"strict", "trim_silence", b"trim_silence", "vad", b"vad"]) -> None: ...
 
 global___GetAudioRequest = GetAudioRequest
 
@@ -74,13 +201,56 @@ class AudioChunk(google.protobuf.message.Message):
     SEQUENCE_FIELD_NUMBER: builtins.int
     START_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
     END_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    GAP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    HEADER_FIELD_NUMBER: builtins.int
+    SPEECH_FIELD_NUMBER: builtins.int
+    TIMECODE_FIELD_NUMBER: builtins.int
+    RESUME_TOKEN_FIELD_NUMBER: builtins.int
+    DROPPED_CHUNKS_FIELD_NUMBER: builtins.int
+    BATCH_FIELD_NUMBER: builtins.int
+    SENT_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    BITRATE_KBPS_FIELD_NUMBER: builtins.int
+    HEARTBEAT_FIELD_NUMBER: builtins.int
+    CRC32_FIELD_NUMBER: builtins.int
     audio_data: builtins.bytes
     sequence: builtins.int
-    """Sequence number"""
+    """counts the chunks sent from 0; on resumable streams their place in the capture, carrying on across resumes"""
     start_timestamp_nanoseconds: builtins.int
     end_timestamp_nanoseconds: builtins.int
+    gap_nanoseconds: builtins.int
+    """audio skipped right before this chunk, e.g. while the stream was paused"""
+    speech: builtins.bool
+    """whether the chunk contains speech, unset unless the request named a vad"""
+    resume_token: builtins.str
+    """resumes the stream after this chunk, set on resumable streams"""
+    dropped_chunks: builtins.int
+    """chunks the stream dropped so far because the client fell behind"""
+    sent_timestamp_nanoseconds: builtins.int
+    """when the server handed the chunk to the transport; with end_timestamp_nanoseconds this is
+    the chunk's capture-to-send latency, unset on chunks without capture timestamps
+    """
+    bitrate_kbps: builtins.int
+    """the chunk's bitrate on streams requested with adaptive_bitrate"""
+    heartbeat: builtins.bool
+    """an empty message keeping a quiet stream alive, see heartbeat_seconds"""
+    crc32: builtins.int
+    """the IEEE CRC-32 of audio_data, set on streams requested with checksums"""
     @property
     def info(self) -> global___AudioInfo: ...
+    @property
+    def header(self) -> global___StreamHeader:
+        """set on the first chunk of a stream and whenever the format changes"""
+
+    @property
+    def timecode(self) -> global___Timecode:
+        """the last LTC frame read in the chunk, unset when there was none"""
+
+    @property
+    def batch(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___AudioChunk]:
+        """on streams requested with batch_chunks, the chunks sent in this message in order, each with its
+        own timestamps; the other fields are then unset
+        """
+
     def __init__(
         self,
         *,
@@ -89,12 +259,94 @@ class AudioChunk(google.protobuf.message.Message):
         sequence: builtins.int = ...,
         start_timestamp_nanoseconds: builtins.int = ...,
         end_timestamp_nanoseconds: builtins.int = ...,
+        gap_nanoseconds: builtins.int = ...,
+        header: global___StreamHeader | None = ...,
+        speech: builtins.bool | None = ...,
+        timecode: global___Timecode | None = ...,
+        resume_token: builtins.str = ...,
+        dropped_chunks: builtins.int = ...,
+        batch: collections.abc.Iterable[global___AudioChunk] | None = ...,
+        sent_timestamp_nanoseconds: builtins.int = ...,
+        bitrate_kbps: builtins.int = ...,
+        heartbeat: builtins.bool = ...,
+        crc32: builtins.int | None = ...,
     ) -> None: ...
-    def HasField(self, field_name: typing.Literal["info", b"info"]) -> builtins.bool: ...
-    def ClearField(self, field_name: typing.Literal["audio_data", b"audio_data", "end_timestamp_nanoseconds", b"end_timestamp_nanoseconds", "info", b"info", "sequence", b"sequence", "start_timestamp_nanoseconds", b"start_timestamp_nanoseconds"]) -> None: ...
+    def HasField(self, field_name: typing.Literal["_crc32", b"_crc32", "_speech", b"_speech", "crc32", b"crc32", "header", b"header", "info", b"info", "speech", b"speech", "timecode", b"timecode"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["_crc32", b"_crc32", "_speech", b"_speech", "audio_data", b"audio_data", "batch", b"batch", "bitrate_kbps", b"bitrate_kbps", "crc32", b"crc32", "dropped_chunks", b"dropped_chunks", "end_timestamp_nanoseconds", b"end_timestamp_nanoseconds", "gap_nanoseconds", b"gap_nanoseconds", "header", b"header", "heartbeat", b"heartbeat", "info", b"info", "resume_token", b"resume_token", "sent_timestamp_nanoseconds", b"sent_timestamp_nanoseconds", "sequence", b"sequence", "speech", b"speech", "start_timestamp_nanoseconds", b"start_timestamp_nanoseconds", "timecode", b"timecode"]) -> None: ...
+    @typing.overload
+    def WhichOneof(self, oneof_group: typing.Literal["_speech", b"_speech"]) -> typing.Literal["speech"] | None: ...
+    @typing.overload
+    def WhichOneof(self, oneof_group: typing.Literal["_crc32", b"_crc32"]) -> typing.Literal["crc32"] | None: ...
 
 global___AudioChunk = AudioChunk
 
+@typing.final
+class StreamHeader(google.protobuf.message.Message):
+    """StreamHeader describes the audio that follows it so clients can set up
+    decoders and write container files before the first chunk is decoded.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    INFO_FIELD_NUMBER: builtins.int
+    EXTRADATA_FIELD_NUMBER: builtins.int
+    CHUNK_FRAMES_FIELD_NUMBER: builtins.int
+    extradata: builtins.bytes
+    """codec initialization data: OpusHead, FLAC STREAMINFO or AAC AudioSpecificConfig"""
+    chunk_frames: builtins.int
+    """frames in every chunk that follows but short ones before a gap or the end, 0 if they vary"""
+    @property
+    def info(self) -> global___AudioInfo: ...
+    def __init__(
+        self,
+        *,
+        info: global___AudioInfo | None = ...,
+        extradata: builtins.bytes = ...,
+        chunk_frames: builtins.int = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["info", b"info"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["chunk_frames", b"chunk_frames", "extradata", b"extradata", "info", b"info"]) -> None: ...
+
+global___StreamHeader = StreamHeader
+
+@typing.final
+class Timecode(google.protobuf.message.Message):
+    """Timecode is a linear timecode (SMPTE LTC) frame read from the audio."""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    HOURS_FIELD_NUMBER: builtins.int
+    MINUTES_FIELD_NUMBER: builtins.int
+    SECONDS_FIELD_NUMBER: builtins.int
+    FRAMES_FIELD_NUMBER: builtins.int
+    DROP_FRAME_FIELD_NUMBER: builtins.int
+    FRAME_RATE_FIELD_NUMBER: builtins.int
+    USER_BITS_FIELD_NUMBER: builtins.int
+    OFFSET_NANOSECONDS_FIELD_NUMBER: builtins.int
+    hours: builtins.int
+    minutes: builtins.int
+    seconds: builtins.int
+    frames: builtins.int
+    drop_frame: builtins.bool
+    frame_rate: builtins.int
+    """nominal: 24, 25 or 30"""
+    user_bits: builtins.int
+    offset_nanoseconds: builtins.int
+    """from the start of the chunk to the start of the frame, negative when it began earlier"""
+    def __init__(
+        self,
+        *,
+        hours: builtins.int = ...,
+        minutes: builtins.int = ...,
+        seconds: builtins.int = ...,
+        frames: builtins.int = ...,
+        drop_frame: builtins.bool = ...,
+        frame_rate: builtins.int = ...,
+        user_bits: builtins.int = ...,
+        offset_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["drop_frame", b"drop_frame", "frame_rate", b"frame_rate", "frames", b"frames", "hours", b"hours", "minutes", b"minutes", "offset_nanoseconds", b"offset_nanoseconds", "seconds", b"seconds", "user_bits", b"user_bits"]) -> None: ...
+
+global___Timecode = Timecode
+
 @typing.final
 class PlayRequest(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -102,8 +354,21 @@ class PlayRequest(google.protobuf.message.Message):
     NAME_FIELD_NUMBER: builtins.int
     AUDIO_DATA_FIELD_NUMBER: builtins.int
     INFO_FIELD_NUMBER: builtins.int
+    NORMALIZE_LUFS_FIELD_NUMBER: builtins.int
+    STRICT_FIELD_NUMBER: builtins.int
+    CONVERT_FIELD_NUMBER: builtins.int
     name: builtins.str
     audio_data: builtins.bytes
+    normalize_lufs: builtins.float
+    """plays raw pcm scaled to this integrated loudness in LUFS, peaking at
+    most at -1 dBFS; 0 plays the audio as sent
+    """
+    strict: builtins.bool
+    """fail with FAILED_PRECONDITION instead of converting when the device doesn't play exactly info's format"""
+    convert: builtins.bool
+    """convert raw pcm the device doesn't play to the nearest format it does,
+    rather than failing with INVALID_ARGUMENT; ignored when strict
+    """
     @property
     def info(self) -> global___AudioInfo: ...
     def __init__(
@@ -112,9 +377,12 @@ class PlayRequest(google.protobuf.message.Message):
         name: builtins.str = ...,
         audio_data: builtins.bytes = ...,
         info: global___AudioInfo | None = ...,
+        normalize_lufs: builtins.float = ...,
+        strict: builtins.bool = ...,
+        convert: builtins.bool = ...,
     ) -> None: ...
     def HasField(self, field_name: typing.Literal["info", b"info"]) -> builtins.bool: ...
-    def ClearField(self, field_name: typing.Literal["audio_data", b"audio_data", "info", b"info", "name", b"name"]) -> None: ...
+    def ClearField(self, field_name: typing.Literal["audio_data", b"audio_data", "convert", b"convert", "info", b"info", "name", b"name", "normalize_lufs", b"normalize_lufs", "strict", b"strict"]) -> None: ...
 
 global___PlayRequest = PlayRequest
 
@@ -133,6 +401,2147 @@ class PlayResponse(google.protobuf.message.Message):
 
 global___PlayResponse = PlayResponse
 
+@typing.final
+class PauseStreamRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    REQUEST_ID_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    request_id: builtins.str
+    """request_id the GetAudio stream was started with"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        request_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "request_id", b"request_id"]) -> None: ...
+
+global___PauseStreamRequest = PauseStreamRequest
+
+@typing.final
+class PauseStreamResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    def __init__(
+        self,
+    ) -> None: ...
+
+global___PauseStreamResponse = PauseStreamResponse
+
+@typing.final
+class ResumeStreamRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    REQUEST_ID_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    request_id: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        request_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "request_id", b"request_id"]) -> None: ...
+
+global___ResumeStreamRequest = ResumeStreamRequest
+
+@typing.final
+class ResumeStreamResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    def __init__(
+        self,
+    ) -> None: ...
+
+global___ResumeStreamResponse = ResumeStreamResponse
+
+@typing.final
+class PreparePlaybackRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    AUDIO_DATA_FIELD_NUMBER: builtins.int
+    INFO_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    audio_data: builtins.bytes
+    @property
+    def info(self) -> global___AudioInfo: ...
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        audio_data: builtins.bytes = ...,
+        info: global___AudioInfo | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["info", b"info"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["audio_data", b"audio_data", "info", b"info", "name", b"name"]) -> None: ...
+
+global___PreparePlaybackRequest = PreparePlaybackRequest
+
+@typing.final
+class PreparePlaybackResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    HANDLE_FIELD_NUMBER: builtins.int
+    handle: builtins.str
+    """expires after a minute if not committed or released"""
+    def __init__(
+        self,
+        *,
+        handle: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["handle", b"handle"]) -> None: ...
+
+global___PreparePlaybackResponse = PreparePlaybackResponse
+
+@typing.final
+class CommitPlaybackRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    HANDLE_FIELD_NUMBER: builtins.int
+    START_TIME_NANOSECONDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    handle: builtins.str
+    start_time_nanoseconds: builtins.int
+    """unix time to start playing at, 0 for now"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        handle: builtins.str = ...,
+        start_time_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["handle", b"handle", "name", b"name", "start_time_nanoseconds", b"start_time_nanoseconds"]) -> None: ...
+
+global___CommitPlaybackRequest = CommitPlaybackRequest
+
+@typing.final
+class CommitPlaybackResponse(google.protobuf.message.Message):
+    """sent once the clip has played out"""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    def __init__(
+        self,
+    ) -> None: ...
+
+global___CommitPlaybackResponse = CommitPlaybackResponse
+
+@typing.final
+class ReleasePlaybackRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    HANDLE_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    handle: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        handle: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["handle", b"handle", "name", b"name"]) -> None: ...
+
+global___ReleasePlaybackRequest = ReleasePlaybackRequest
+
+@typing.final
+class ReleasePlaybackResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    def __init__(
+        self,
+    ) -> None: ...
+
+global___ReleasePlaybackResponse = ReleasePlaybackResponse
+
+@typing.final
+class SetProfileRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    PROFILE_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    profile: builtins.str
+    """empty hands the choice back to the linked sensor"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        profile: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "profile", b"profile"]) -> None: ...
+
+global___SetProfileRequest = SetProfileRequest
+
+@typing.final
+class SetProfileResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    def __init__(
+        self,
+    ) -> None: ...
+
+global___SetProfileResponse = SetProfileResponse
+
+@typing.final
+class GetProfileRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name"]) -> None: ...
+
+global___GetProfileRequest = GetProfileRequest
+
+@typing.final
+class GetProfileResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    PROFILE_FIELD_NUMBER: builtins.int
+    PROFILES_FIELD_NUMBER: builtins.int
+    AUTOMATIC_FIELD_NUMBER: builtins.int
+    profile: builtins.str
+    """profile output is rendered with"""
+    automatic: builtins.bool
+    """chosen from the linked sensor rather than set"""
+    @property
+    def profiles(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]: ...
+    def __init__(
+        self,
+        *,
+        profile: builtins.str = ...,
+        profiles: collections.abc.Iterable[builtins.str] | None = ...,
+        automatic: builtins.bool = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["automatic", b"automatic", "profile", b"profile", "profiles", b"profiles"]) -> None: ...
+
+global___GetProfileResponse = GetProfileResponse
+
+@typing.final
+class EQBand(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    TYPE_FIELD_NUMBER: builtins.int
+    FREQUENCY_HZ_FIELD_NUMBER: builtins.int
+    GAIN_DB_FIELD_NUMBER: builtins.int
+    Q_FIELD_NUMBER: builtins.int
+    type: builtins.str
+    """lowshelf, highshelf, peaking, lowpass or highpass"""
+    frequency_hz: builtins.float
+    gain_db: builtins.float
+    """only for shelves and peaks"""
+    q: builtins.float
+    """defaults to 0.707"""
+    def __init__(
+        self,
+        *,
+        type: builtins.str = ...,
+        frequency_hz: builtins.float = ...,
+        gain_db: builtins.float = ...,
+        q: builtins.float = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["frequency_hz", b"frequency_hz", "gain_db", b"gain_db", "q", b"q", "type", b"type"]) -> None: ...
+
+global___EQBand = EQBand
+
+@typing.final
+class SetEQRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    BANDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    @property
+    def bands(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___EQBand]:
+        """replaces the whole chain, empty for none"""
+
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        bands: collections.abc.Iterable[global___EQBand] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["bands", b"bands", "name", b"name"]) -> None: ...
+
+global___SetEQRequest = SetEQRequest
+
+@typing.final
+class SetEQResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    def __init__(
+        self,
+    ) -> None: ...
+
+global___SetEQResponse = SetEQResponse
+
+@typing.final
+class GetEQRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name"]) -> None: ...
+
+global___GetEQRequest = GetEQRequest
+
+@typing.final
+class GetEQResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    BANDS_FIELD_NUMBER: builtins.int
+    @property
+    def bands(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___EQBand]: ...
+    def __init__(
+        self,
+        *,
+        bands: collections.abc.Iterable[global___EQBand] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["bands", b"bands"]) -> None: ...
+
+global___GetEQResponse = GetEQResponse
+
+@typing.final
+class GetLevelsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    WINDOW_SECONDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    window_seconds: builtins.float
+    """capture each report covers, defaults to 0.1"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        window_seconds: builtins.float = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "window_seconds", b"window_seconds"]) -> None: ...
+
+global___GetLevelsRequest = GetLevelsRequest
+
+@typing.final
+class ChannelLevel(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RMS_FIELD_NUMBER: builtins.int
+    PEAK_FIELD_NUMBER: builtins.int
+    RMS_DBFS_FIELD_NUMBER: builtins.int
+    PEAK_DBFS_FIELD_NUMBER: builtins.int
+    rms: builtins.float
+    """linear, 1 is full scale"""
+    peak: builtins.float
+    rms_dbfs: builtins.float
+    peak_dbfs: builtins.float
+    def __init__(
+        self,
+        *,
+        rms: builtins.float = ...,
+        peak: builtins.float = ...,
+        rms_dbfs: builtins.float = ...,
+        peak_dbfs: builtins.float = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["peak", b"peak", "peak_dbfs", b"peak_dbfs", "rms", b"rms", "rms_dbfs", b"rms_dbfs"]) -> None: ...
+
+global___ChannelLevel = ChannelLevel
+
+@typing.final
+class GetLevelsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    CHANNELS_FIELD_NUMBER: builtins.int
+    TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    timestamp_nanoseconds: builtins.int
+    """capture time of the window's first sample, 0 if unknown"""
+    @property
+    def channels(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___ChannelLevel]: ...
+    def __init__(
+        self,
+        *,
+        channels: collections.abc.Iterable[global___ChannelLevel] | None = ...,
+        timestamp_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["channels", b"channels", "timestamp_nanoseconds", b"timestamp_nanoseconds"]) -> None: ...
+
+global___GetLevelsResponse = GetLevelsResponse
+
+@typing.final
+class StreamSpectrumRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    FFT_SIZE_FIELD_NUMBER: builtins.int
+    HOP_SIZE_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    fft_size: builtins.int
+    """samples per frame, a power of two from 64 to 65536, defaults to 1024"""
+    hop_size: builtins.int
+    """samples between the starts of frames, defaults to half the fft size"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        fft_size: builtins.int = ...,
+        hop_size: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["fft_size", b"fft_size", "hop_size", b"hop_size", "name", b"name"]) -> None: ...
+
+global___StreamSpectrumRequest = StreamSpectrumRequest
+
+@typing.final
+class SpectrumFrame(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    MAGNITUDES_FIELD_NUMBER: builtins.int
+    BIN_HZ_FIELD_NUMBER: builtins.int
+    TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    bin_hz: builtins.float
+    timestamp_nanoseconds: builtins.int
+    """capture time of the frame's first sample, 0 if unknown"""
+    @property
+    def magnitudes(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.float]:
+        """fft_size/2+1 bins from 0 Hz of the mono mix, linear with 1 for a full
+        scale sine
+        """
+
+    def __init__(
+        self,
+        *,
+        magnitudes: collections.abc.Iterable[builtins.float] | None = ...,
+        bin_hz: builtins.float = ...,
+        timestamp_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["bin_hz", b"bin_hz", "magnitudes", b"magnitudes", "timestamp_nanoseconds", b"timestamp_nanoseconds"]) -> None: ...
+
+global___SpectrumFrame = SpectrumFrame
+
+@typing.final
+class GetSpectrogramRequest(google.protobuf.message.Message):
+    """A spectrogram is rendered from the loop recording kept for the resource."""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    SECONDS_FIELD_NUMBER: builtins.int
+    WIDTH_FIELD_NUMBER: builtins.int
+    HEIGHT_FIELD_NUMBER: builtins.int
+    FFT_SIZE_FIELD_NUMBER: builtins.int
+    FLOOR_DBFS_FIELD_NUMBER: builtins.int
+    LOG_FREQUENCY_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    seconds: builtins.float
+    """of the newest capture, all that is kept if zero"""
+    width: builtins.int
+    """pixels, 800 if zero"""
+    height: builtins.int
+    """pixels, 256 if zero"""
+    fft_size: builtins.int
+    """1024 if zero"""
+    floor_dbfs: builtins.float
+    """the level shown black, -100 if zero"""
+    log_frequency: builtins.bool
+    """from 20 Hz rather than linear from 0"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        seconds: builtins.float = ...,
+        width: builtins.int = ...,
+        height: builtins.int = ...,
+        fft_size: builtins.int = ...,
+        floor_dbfs: builtins.float = ...,
+        log_frequency: builtins.bool = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["fft_size", b"fft_size", "floor_dbfs", b"floor_dbfs", "height", b"height", "log_frequency", b"log_frequency", "name", b"name", "seconds", b"seconds", "width", b"width"]) -> None: ...
+
+global___GetSpectrogramRequest = GetSpectrogramRequest
+
+@typing.final
+class GetSpectrogramResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    PNG_FIELD_NUMBER: builtins.int
+    START_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    DURATION_NANOSECONDS_FIELD_NUMBER: builtins.int
+    SAMPLE_RATE_FIELD_NUMBER: builtins.int
+    png: builtins.bytes
+    """time left to right, frequency bottom to top"""
+    start_timestamp_nanoseconds: builtins.int
+    """capture time of the first sample, 0 if unknown"""
+    duration_nanoseconds: builtins.int
+    sample_rate: builtins.int
+    def __init__(
+        self,
+        *,
+        png: builtins.bytes = ...,
+        start_timestamp_nanoseconds: builtins.int = ...,
+        duration_nanoseconds: builtins.int = ...,
+        sample_rate: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["duration_nanoseconds", b"duration_nanoseconds", "png", b"png", "sample_rate", b"sample_rate", "start_timestamp_nanoseconds", b"start_timestamp_nanoseconds"]) -> None: ...
+
+global___GetSpectrogramResponse = GetSpectrogramResponse
+
+@typing.final
+class CaptureClipRequest(google.protobuf.message.Message):
+    """A clip is the pre-roll a clip resource has kept up to the request
+    followed by the post-roll captured after it.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    PRE_ROLL_SECONDS_FIELD_NUMBER: builtins.int
+    POST_ROLL_SECONDS_FIELD_NUMBER: builtins.int
+    CODEC_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    pre_roll_seconds: builtins.float
+    """at most the configured pre-roll, all of it if zero"""
+    post_roll_seconds: builtins.float
+    """at most 60, the configured post-roll if zero"""
+    codec: builtins.str
+    """a raw pcm codec, pcm16 if empty"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        pre_roll_seconds: builtins.float = ...,
+        post_roll_seconds: builtins.float = ...,
+        codec: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["codec", b"codec", "name", b"name", "post_roll_seconds", b"post_roll_seconds", "pre_roll_seconds", b"pre_roll_seconds"]) -> None: ...
+
+global___CaptureClipRequest = CaptureClipRequest
+
+@typing.final
+class CaptureClipResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    AUDIO_DATA_FIELD_NUMBER: builtins.int
+    INFO_FIELD_NUMBER: builtins.int
+    START_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    TRIGGER_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    audio_data: builtins.bytes
+    start_timestamp_nanoseconds: builtins.int
+    """capture time of the first sample, 0 if unknown"""
+    trigger_timestamp_nanoseconds: builtins.int
+    """capture time the pre-roll ends at, 0 if unknown"""
+    @property
+    def info(self) -> global___AudioInfo: ...
+    def __init__(
+        self,
+        *,
+        audio_data: builtins.bytes = ...,
+        info: global___AudioInfo | None = ...,
+        start_timestamp_nanoseconds: builtins.int = ...,
+        trigger_timestamp_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["info", b"info"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["audio_data", b"audio_data", "info", b"info", "start_timestamp_nanoseconds", b"start_timestamp_nanoseconds", "trigger_timestamp_nanoseconds", b"trigger_timestamp_nanoseconds"]) -> None: ...
+
+global___CaptureClipResponse = CaptureClipResponse
+
+@typing.final
+class StreamImpulsesRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    RISE_DB_FIELD_NUMBER: builtins.int
+    MIN_PEAK_DBFS_FIELD_NUMBER: builtins.int
+    MAX_DURATION_SECONDS_FIELD_NUMBER: builtins.int
+    SAVE_CLIPS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    rise_db: builtins.float
+    """rise above the background that starts an impulse, defaults to 20"""
+    min_peak_dbfs: builtins.float
+    """quieter impulses are ignored, defaults to -40"""
+    max_duration_seconds: builtins.float
+    """longer is sustained loudness, defaults to 0.5"""
+    save_clips: builtins.bool
+    """save a clip of every impulse in the server's recording store"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        rise_db: builtins.float = ...,
+        min_peak_dbfs: builtins.float = ...,
+        max_duration_seconds: builtins.float = ...,
+        save_clips: builtins.bool = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["max_duration_seconds", b"max_duration_seconds", "min_peak_dbfs", b"min_peak_dbfs", "name", b"name", "rise_db", b"rise_db", "save_clips", b"save_clips"]) -> None: ...
+
+global___StreamImpulsesRequest = StreamImpulsesRequest
+
+@typing.final
+class ImpulseEvent(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    DURATION_SECONDS_FIELD_NUMBER: builtins.int
+    PEAK_DBFS_FIELD_NUMBER: builtins.int
+    RISE_DB_FIELD_NUMBER: builtins.int
+    BACKGROUND_DBFS_FIELD_NUMBER: builtins.int
+    KURTOSIS_FIELD_NUMBER: builtins.int
+    CLIP_FIELD_NUMBER: builtins.int
+    timestamp_nanoseconds: builtins.int
+    """capture time of the onset, 0 if unknown"""
+    duration_seconds: builtins.float
+    peak_dbfs: builtins.float
+    rise_db: builtins.float
+    background_dbfs: builtins.float
+    kurtosis: builtins.float
+    clip: builtins.str
+    """name of the saved clip, empty unless save_clips was set"""
+    def __init__(
+        self,
+        *,
+        timestamp_nanoseconds: builtins.int = ...,
+        duration_seconds: builtins.float = ...,
+        peak_dbfs: builtins.float = ...,
+        rise_db: builtins.float = ...,
+        background_dbfs: builtins.float = ...,
+        kurtosis: builtins.float = ...,
+        clip: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["background_dbfs", b"background_dbfs", "clip", b"clip", "duration_seconds", b"duration_seconds", "kurtosis", b"kurtosis", "peak_dbfs", b"peak_dbfs", "rise_db", b"rise_db", "timestamp_nanoseconds", b"timestamp_nanoseconds"]) -> None: ...
+
+global___ImpulseEvent = ImpulseEvent
+
+@typing.final
+class GetLevelStatsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    PERIOD_FIELD_NUMBER: builtins.int
+    START_NANOSECONDS_FIELD_NUMBER: builtins.int
+    END_NANOSECONDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    period: builtins.str
+    """"hour" or "day", defaults to "hour\""""
+    start_nanoseconds: builtins.int
+    """earliest bucket start, 0 for the oldest kept"""
+    end_nanoseconds: builtins.int
+    """buckets starting before this, 0 for up to now"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        period: builtins.str = ...,
+        start_nanoseconds: builtins.int = ...,
+        end_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["end_nanoseconds", b"end_nanoseconds", "name", b"name", "period", b"period", "start_nanoseconds", b"start_nanoseconds"]) -> None: ...
+
+global___GetLevelStatsRequest = GetLevelStatsRequest
+
+@typing.final
+class LevelStatsBucket(google.protobuf.message.Message):
+    """Level statistics of one hour or day, in dBFS from 125ms windows. Ln is
+    the level exceeded n% of the time.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    START_NANOSECONDS_FIELD_NUMBER: builtins.int
+    COVERED_SECONDS_FIELD_NUMBER: builtins.int
+    LEQ_DBFS_FIELD_NUMBER: builtins.int
+    L10_DBFS_FIELD_NUMBER: builtins.int
+    L50_DBFS_FIELD_NUMBER: builtins.int
+    L90_DBFS_FIELD_NUMBER: builtins.int
+    MAX_DBFS_FIELD_NUMBER: builtins.int
+    MIN_DBFS_FIELD_NUMBER: builtins.int
+    start_nanoseconds: builtins.int
+    covered_seconds: builtins.float
+    """capture that went into the bucket"""
+    leq_dbfs: builtins.float
+    l10_dbfs: builtins.float
+    l50_dbfs: builtins.float
+    l90_dbfs: builtins.float
+    max_dbfs: builtins.float
+    min_dbfs: builtins.float
+    def __init__(
+        self,
+        *,
+        start_nanoseconds: builtins.int = ...,
+        covered_seconds: builtins.float = ...,
+        leq_dbfs: builtins.float = ...,
+        l10_dbfs: builtins.float = ...,
+        l50_dbfs: builtins.float = ...,
+        l90_dbfs: builtins.float = ...,
+        max_dbfs: builtins.float = ...,
+        min_dbfs: builtins.float = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["covered_seconds", b"covered_seconds", "l10_dbfs", b"l10_dbfs", "l50_dbfs", b"l50_dbfs", "l90_dbfs", b"l90_dbfs", "leq_dbfs", b"leq_dbfs", "max_dbfs", b"max_dbfs", "min_dbfs", b"min_dbfs", "start_nanoseconds", b"start_nanoseconds"]) -> None: ...
+
+global___LevelStatsBucket = LevelStatsBucket
+
+@typing.final
+class GetLevelStatsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    BUCKETS_FIELD_NUMBER: builtins.int
+    @property
+    def buckets(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___LevelStatsBucket]:
+        """oldest first"""
+
+    def __init__(
+        self,
+        *,
+        buckets: collections.abc.Iterable[global___LevelStatsBucket] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["buckets", b"buckets"]) -> None: ...
+
+global___GetLevelStatsResponse = GetLevelStatsResponse
+
+@typing.final
+class ListHistoryRequest(google.protobuf.message.Message):
+    """Every list takes a page size and token, and filters that match
+    anything when unset.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    PAGE_SIZE_FIELD_NUMBER: builtins.int
+    PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    DIRECTION_FIELD_NUMBER: builtins.int
+    REQUEST_ID_FIELD_NUMBER: builtins.int
+    SUBJECT_FIELD_NUMBER: builtins.int
+    KIND_FIELD_NUMBER: builtins.int
+    AFTER_NANOSECONDS_FIELD_NUMBER: builtins.int
+    BEFORE_NANOSECONDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    page_size: builtins.int
+    """defaults to 100, at most 1000"""
+    page_token: builtins.str
+    """next_page_token of the previous page, empty for the newest"""
+    direction: builtins.str
+    """streams: "capture" or "playback\""""
+    request_id: builtins.str
+    """streams"""
+    subject: builtins.str
+    """streams"""
+    kind: builtins.str
+    """events"""
+    after_nanoseconds: builtins.int
+    """streams started or events at or after"""
+    before_nanoseconds: builtins.int
+    """streams started or events before"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        page_size: builtins.int = ...,
+        page_token: builtins.str = ...,
+        direction: builtins.str = ...,
+        request_id: builtins.str = ...,
+        subject: builtins.str = ...,
+        kind: builtins.str = ...,
+        after_nanoseconds: builtins.int = ...,
+        before_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["after_nanoseconds", b"after_nanoseconds", "before_nanoseconds", b"before_nanoseconds", "direction", b"direction", "kind", b"kind", "name", b"name", "page_size", b"page_size", "page_token", b"page_token", "request_id", b"request_id", "subject", b"subject"]) -> None: ...
+
+global___ListHistoryRequest = ListHistoryRequest
+
+@typing.final
+class StreamRecord(google.protobuf.message.Message):
+    """A GetAudio stream or Play call. Active ones have no end yet."""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    DIRECTION_FIELD_NUMBER: builtins.int
+    CODEC_FIELD_NUMBER: builtins.int
+    PROFILE_FIELD_NUMBER: builtins.int
+    REQUEST_ID_FIELD_NUMBER: builtins.int
+    SUBJECT_FIELD_NUMBER: builtins.int
+    START_NANOSECONDS_FIELD_NUMBER: builtins.int
+    END_NANOSECONDS_FIELD_NUMBER: builtins.int
+    BYTES_FIELD_NUMBER: builtins.int
+    MESSAGES_FIELD_NUMBER: builtins.int
+    ERROR_FIELD_NUMBER: builtins.int
+    PAUSED_FIELD_NUMBER: builtins.int
+    DROPPED_CHUNKS_FIELD_NUMBER: builtins.int
+    direction: builtins.str
+    """"capture" or "playback\""""
+    codec: builtins.str
+    profile: builtins.str
+    request_id: builtins.str
+    subject: builtins.str
+    """identity of the caller when the server authenticates"""
+    start_nanoseconds: builtins.int
+    end_nanoseconds: builtins.int
+    bytes: builtins.int
+    messages: builtins.int
+    error: builtins.str
+    """empty if it ended cleanly"""
+    paused: builtins.bool
+    """active streams paused with PauseStream"""
+    dropped_chunks: builtins.int
+    """dropped because the client fell behind"""
+    def __init__(
+        self,
+        *,
+        direction: builtins.str = ...,
+        codec: builtins.str = ...,
+        profile: builtins.str = ...,
+        request_id: builtins.str = ...,
+        subject: builtins.str = ...,
+        start_nanoseconds: builtins.int = ...,
+        end_nanoseconds: builtins.int = ...,
+        bytes: builtins.int = ...,
+        messages: builtins.int = ...,
+        error: builtins.str = ...,
+        paused: builtins.bool = ...,
+        dropped_chunks: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["bytes", b"bytes", "codec", b"codec", "direction", b"direction", "dropped_chunks", b"dropped_chunks", "end_nanoseconds", b"end_nanoseconds", "error", b"error", "messages", b"messages", "paused", b"paused", "profile", b"profile", "request_id", b"request_id", "start_nanoseconds", b"start_nanoseconds", "subject", b"subject"]) -> None: ...
+
+global___StreamRecord = StreamRecord
+
+@typing.final
+class ListStreamHistoryResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RECORDS_FIELD_NUMBER: builtins.int
+    NEXT_PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    next_page_token: builtins.str
+    """empty on the last page"""
+    @property
+    def records(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___StreamRecord]:
+        """newest first"""
+
+    def __init__(
+        self,
+        *,
+        records: collections.abc.Iterable[global___StreamRecord] | None = ...,
+        next_page_token: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["next_page_token", b"next_page_token", "records", b"records"]) -> None: ...
+
+global___ListStreamHistoryResponse = ListStreamHistoryResponse
+
+@typing.final
+class EventRecord(google.protobuf.message.Message):
+    """An event reported to a client, such as a StreamImpulses impulse."""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    KIND_FIELD_NUMBER: builtins.int
+    TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    DURATION_SECONDS_FIELD_NUMBER: builtins.int
+    PEAK_DBFS_FIELD_NUMBER: builtins.int
+    CLIP_FIELD_NUMBER: builtins.int
+    kind: builtins.str
+    """"impulse" or "level\""""
+    timestamp_nanoseconds: builtins.int
+    duration_seconds: builtins.float
+    peak_dbfs: builtins.float
+    clip: builtins.str
+    """saved clip in the server's recording store, if any"""
+    def __init__(
+        self,
+        *,
+        kind: builtins.str = ...,
+        timestamp_nanoseconds: builtins.int = ...,
+        duration_seconds: builtins.float = ...,
+        peak_dbfs: builtins.float = ...,
+        clip: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["clip", b"clip", "duration_seconds", b"duration_seconds", "kind", b"kind", "peak_dbfs", b"peak_dbfs", "timestamp_nanoseconds", b"timestamp_nanoseconds"]) -> None: ...
+
+global___EventRecord = EventRecord
+
+@typing.final
+class ListEventsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    EVENTS_FIELD_NUMBER: builtins.int
+    NEXT_PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    next_page_token: builtins.str
+    """empty on the last page"""
+    @property
+    def events(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___EventRecord]:
+        """newest first"""
+
+    def __init__(
+        self,
+        *,
+        events: collections.abc.Iterable[global___EventRecord] | None = ...,
+        next_page_token: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["events", b"events", "next_page_token", b"next_page_token"]) -> None: ...
+
+global___ListEventsResponse = ListEventsResponse
+
+@typing.final
+class ListAuditLogRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    PAGE_SIZE_FIELD_NUMBER: builtins.int
+    PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    ACTION_FIELD_NUMBER: builtins.int
+    SUBJECT_FIELD_NUMBER: builtins.int
+    AFTER_NANOSECONDS_FIELD_NUMBER: builtins.int
+    BEFORE_NANOSECONDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    page_size: builtins.int
+    """defaults to 100, at most 1000"""
+    page_token: builtins.str
+    """empty for the newest"""
+    action: builtins.str
+    subject: builtins.str
+    after_nanoseconds: builtins.int
+    """recorded at or after"""
+    before_nanoseconds: builtins.int
+    """recorded before"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        page_size: builtins.int = ...,
+        page_token: builtins.str = ...,
+        action: builtins.str = ...,
+        subject: builtins.str = ...,
+        after_nanoseconds: builtins.int = ...,
+        before_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["action", b"action", "after_nanoseconds", b"after_nanoseconds", "before_nanoseconds", b"before_nanoseconds", "name", b"name", "page_size", b"page_size", "page_token", b"page_token", "subject", b"subject"]) -> None: ...
+
+global___ListAuditLogRequest = ListAuditLogRequest
+
+@typing.final
+class AuditRecord(google.protobuf.message.Message):
+    """An operation on a resource's audio, as the server's audit log recorded it. Each record's hash
+    chains it to the one before, so records can't be removed or changed unnoticed
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SEQ_FIELD_NUMBER: builtins.int
+    TIME_NANOSECONDS_FIELD_NUMBER: builtins.int
+    ACTION_FIELD_NUMBER: builtins.int
+    SUBJECT_FIELD_NUMBER: builtins.int
+    PEER_FIELD_NUMBER: builtins.int
+    REQUEST_ID_FIELD_NUMBER: builtins.int
+    DETAIL_FIELD_NUMBER: builtins.int
+    ERROR_FIELD_NUMBER: builtins.int
+    HASH_FIELD_NUMBER: builtins.int
+    seq: builtins.int
+    """counts the records of the whole log from 1"""
+    time_nanoseconds: builtins.int
+    action: builtins.str
+    """e.g. "capture_started", "play" or "recording_stopped\""""
+    subject: builtins.str
+    """identity of the caller when the server authenticates"""
+    peer: builtins.str
+    """the caller's address"""
+    request_id: builtins.str
+    """the stream's request id, or the recording's or session's id"""
+    detail: builtins.str
+    error: builtins.str
+    """what the operation failed with, if it did"""
+    hash: builtins.str
+    """hex SHA-256 of the previous record's hash and this record"""
+    def __init__(
+        self,
+        *,
+        seq: builtins.int = ...,
+        time_nanoseconds: builtins.int = ...,
+        action: builtins.str = ...,
+        subject: builtins.str = ...,
+        peer: builtins.str = ...,
+        request_id: builtins.str = ...,
+        detail: builtins.str = ...,
+        error: builtins.str = ...,
+        hash: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["action", b"action", "detail", b"detail", "error", b"error", "hash", b"hash", "peer", b"peer", "request_id", b"request_id", "seq", b"seq", "subject", b"subject", "time_nanoseconds", b"time_nanoseconds"]) -> None: ...
+
+global___AuditRecord = AuditRecord
+
+@typing.final
+class ListAuditLogResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RECORDS_FIELD_NUMBER: builtins.int
+    NEXT_PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    next_page_token: builtins.str
+    """empty on the last page"""
+    @property
+    def records(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___AuditRecord]:
+        """newest first"""
+
+    def __init__(
+        self,
+        *,
+        records: collections.abc.Iterable[global___AuditRecord] | None = ...,
+        next_page_token: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["next_page_token", b"next_page_token", "records", b"records"]) -> None: ...
+
+global___ListAuditLogResponse = ListAuditLogResponse
+
+@typing.final
+class ListActiveStreamsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    PAGE_SIZE_FIELD_NUMBER: builtins.int
+    PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    DIRECTION_FIELD_NUMBER: builtins.int
+    REQUEST_ID_FIELD_NUMBER: builtins.int
+    SUBJECT_FIELD_NUMBER: builtins.int
+    AFTER_NANOSECONDS_FIELD_NUMBER: builtins.int
+    BEFORE_NANOSECONDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    page_size: builtins.int
+    """defaults to 100, at most 1000"""
+    page_token: builtins.str
+    """empty for the newest"""
+    direction: builtins.str
+    """"capture" or "playback\""""
+    request_id: builtins.str
+    subject: builtins.str
+    after_nanoseconds: builtins.int
+    """started at or after"""
+    before_nanoseconds: builtins.int
+    """started before"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        page_size: builtins.int = ...,
+        page_token: builtins.str = ...,
+        direction: builtins.str = ...,
+        request_id: builtins.str = ...,
+        subject: builtins.str = ...,
+        after_nanoseconds: builtins.int = ...,
+        before_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["after_nanoseconds", b"after_nanoseconds", "before_nanoseconds", b"before_nanoseconds", "direction", b"direction", "name", b"name", "page_size", b"page_size", "page_token", b"page_token", "request_id", b"request_id", "subject", b"subject"]) -> None: ...
+
+global___ListActiveStreamsRequest = ListActiveStreamsRequest
+
+@typing.final
+class ListActiveStreamsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    STREAMS_FIELD_NUMBER: builtins.int
+    NEXT_PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    next_page_token: builtins.str
+    """empty on the last page"""
+    @property
+    def streams(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___StreamRecord]:
+        """newest first"""
+
+    def __init__(
+        self,
+        *,
+        streams: collections.abc.Iterable[global___StreamRecord] | None = ...,
+        next_page_token: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["next_page_token", b"next_page_token", "streams", b"streams"]) -> None: ...
+
+global___ListActiveStreamsResponse = ListActiveStreamsResponse
+
+@typing.final
+class ListRecordingsRequest(google.protobuf.message.Message):
+    """Recordings are in the server's recording store, shared by its resources."""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    PAGE_SIZE_FIELD_NUMBER: builtins.int
+    PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    PREFIX_FIELD_NUMBER: builtins.int
+    FORMAT_FIELD_NUMBER: builtins.int
+    AFTER_NANOSECONDS_FIELD_NUMBER: builtins.int
+    BEFORE_NANOSECONDS_FIELD_NUMBER: builtins.int
+    CODEC_FIELD_NUMBER: builtins.int
+    MIN_DURATION_NANOSECONDS_FIELD_NUMBER: builtins.int
+    MAX_DURATION_NANOSECONDS_FIELD_NUMBER: builtins.int
+    MIN_SIZE_BYTES_FIELD_NUMBER: builtins.int
+    MAX_SIZE_BYTES_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    page_size: builtins.int
+    """defaults to 100, at most 1000"""
+    page_token: builtins.str
+    """empty for the first"""
+    prefix: builtins.str
+    """of the recording name"""
+    format: builtins.str
+    """"wav", "flac" or "chunks\""""
+    after_nanoseconds: builtins.int
+    """modified at or after"""
+    before_nanoseconds: builtins.int
+    """modified before"""
+    codec: builtins.str
+    """of the audio, see StoredRecording"""
+    min_duration_nanoseconds: builtins.int
+    max_duration_nanoseconds: builtins.int
+    """0 for no bound"""
+    min_size_bytes: builtins.int
+    max_size_bytes: builtins.int
+    """0 for no bound"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        page_size: builtins.int = ...,
+        page_token: builtins.str = ...,
+        prefix: builtins.str = ...,
+        format: builtins.str = ...,
+        after_nanoseconds: builtins.int = ...,
+        before_nanoseconds: builtins.int = ...,
+        codec: builtins.str = ...,
+        min_duration_nanoseconds: builtins.int = ...,
+        max_duration_nanoseconds: builtins.int = ...,
+        min_size_bytes: builtins.int = ...,
+        max_size_bytes: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["after_nanoseconds", b"after_nanoseconds", "before_nanoseconds", b"before_nanoseconds", "codec", b"codec", "format", b"format", "max_duration_nanoseconds", b"max_duration_nanoseconds", "max_size_bytes", b"max_size_bytes", "min_duration_nanoseconds", b"min_duration_nanoseconds", "min_size_bytes", b"min_size_bytes", "name", b"name", "page_size", b"page_size", "page_token", b"page_token", "prefix", b"prefix"]) -> None: ...
+
+global___ListRecordingsRequest = ListRecordingsRequest
+
+@typing.final
+class StoredRecording(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    FORMAT_FIELD_NUMBER: builtins.int
+    SIZE_BYTES_FIELD_NUMBER: builtins.int
+    MODIFIED_NANOSECONDS_FIELD_NUMBER: builtins.int
+    ID_FIELD_NUMBER: builtins.int
+    CODEC_FIELD_NUMBER: builtins.int
+    SAMPLE_RATE_FIELD_NUMBER: builtins.int
+    NUM_CHANNELS_FIELD_NUMBER: builtins.int
+    DURATION_NANOSECONDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    """as saved, without the extension"""
+    format: builtins.str
+    """"wav", "flac" or "chunks\""""
+    size_bytes: builtins.int
+    modified_nanoseconds: builtins.int
+    id: builtins.str
+    """the file in the store, as GetRecording and StreamRecording take it"""
+    codec: builtins.str
+    """of the audio: "pcm8", "pcm16", "pcm24", "pcm32" or "pcm32_float" in a wav file, "flac", or
+    the codec a stream was saved in
+    """
+    sample_rate: builtins.int
+    num_channels: builtins.int
+    duration_nanoseconds: builtins.int
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        format: builtins.str = ...,
+        size_bytes: builtins.int = ...,
+        modified_nanoseconds: builtins.int = ...,
+        id: builtins.str = ...,
+        codec: builtins.str = ...,
+        sample_rate: builtins.int = ...,
+        num_channels: builtins.int = ...,
+        duration_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["codec", b"codec", "duration_nanoseconds", b"duration_nanoseconds", "format", b"format", "id", b"id", "modified_nanoseconds", b"modified_nanoseconds", "name", b"name", "num_channels", b"num_channels", "sample_rate", b"sample_rate", "size_bytes", b"size_bytes"]) -> None: ...
+
+global___StoredRecording = StoredRecording
+
+@typing.final
+class GetRecordingRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    ID_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    id: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["id", b"id", "name", b"name"]) -> None: ...
+
+global___GetRecordingRequest = GetRecordingRequest
+
+@typing.final
+class GetRecordingResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RECORDING_FIELD_NUMBER: builtins.int
+    @property
+    def recording(self) -> global___StoredRecording: ...
+    def __init__(
+        self,
+        *,
+        recording: global___StoredRecording | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["recording", b"recording"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["recording", b"recording"]) -> None: ...
+
+global___GetRecordingResponse = GetRecordingResponse
+
+@typing.final
+class StreamRecordingRequest(google.protobuf.message.Message):
+    """The recording is streamed as fast as the client reads it, in chunks of
+    100ms; saved streams are replayed chunk for chunk as they were saved.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    ID_FIELD_NUMBER: builtins.int
+    CODEC_FIELD_NUMBER: builtins.int
+    START_SECONDS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    id: builtins.str
+    codec: builtins.str
+    """"pcm16" (the default), "pcm32" or "pcm32_float", ignored for saved streams"""
+    start_seconds: builtins.float
+    """into the recording"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        id: builtins.str = ...,
+        codec: builtins.str = ...,
+        start_seconds: builtins.float = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["codec", b"codec", "id", b"id", "name", b"name", "start_seconds", b"start_seconds"]) -> None: ...
+
+global___StreamRecordingRequest = StreamRecordingRequest
+
+@typing.final
+class ListRecordingsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RECORDINGS_FIELD_NUMBER: builtins.int
+    NEXT_PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    next_page_token: builtins.str
+    """empty on the last page"""
+    @property
+    def recordings(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___StoredRecording]:
+        """by name"""
+
+    def __init__(
+        self,
+        *,
+        recordings: collections.abc.Iterable[global___StoredRecording] | None = ...,
+        next_page_token: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["next_page_token", b"next_page_token", "recordings", b"recordings"]) -> None: ...
+
+global___ListRecordingsResponse = ListRecordingsResponse
+
+@typing.final
+class GetLatencyStatsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name"]) -> None: ...
+
+global___GetLatencyStatsRequest = GetLatencyStatsRequest
+
+@typing.final
+class GetLatencyStatsResponse(google.protobuf.message.Message):
+    """GetLatencyStatsResponse is the capture-to-send latency of the latest chunks the server sent of
+    the resource's GetAudio streams: from the capture of each chunk's last frame to it being sent
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    CHUNKS_FIELD_NUMBER: builtins.int
+    P50_SECONDS_FIELD_NUMBER: builtins.int
+    P95_SECONDS_FIELD_NUMBER: builtins.int
+    P99_SECONDS_FIELD_NUMBER: builtins.int
+    MAX_SECONDS_FIELD_NUMBER: builtins.int
+    chunks: builtins.int
+    """measured, the latest 1000 at most; 0 before any chunk was sent"""
+    p50_seconds: builtins.float
+    p95_seconds: builtins.float
+    p99_seconds: builtins.float
+    max_seconds: builtins.float
+    def __init__(
+        self,
+        *,
+        chunks: builtins.int = ...,
+        p50_seconds: builtins.float = ...,
+        p95_seconds: builtins.float = ...,
+        p99_seconds: builtins.float = ...,
+        max_seconds: builtins.float = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["chunks", b"chunks", "max_seconds", b"max_seconds", "p50_seconds", b"p50_seconds", "p95_seconds", b"p95_seconds", "p99_seconds", b"p99_seconds"]) -> None: ...
+
+global___GetLatencyStatsResponse = GetLatencyStatsResponse
+
+@typing.final
+class GetStatsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name"]) -> None: ...
+
+global___GetStatsRequest = GetStatsRequest
+
+@typing.final
+class GetStatsResponse(google.protobuf.message.Message):
+    """GetStatsResponse is what the server has counted of the resource since it started, and what of
+    it is in progress now
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    UPTIME_SECONDS_FIELD_NUMBER: builtins.int
+    ACTIVE_STREAMS_FIELD_NUMBER: builtins.int
+    ACTIVE_PLAYS_FIELD_NUMBER: builtins.int
+    ACTIVE_SESSIONS_FIELD_NUMBER: builtins.int
+    BYTES_CAPTURED_FIELD_NUMBER: builtins.int
+    BYTES_PLAYED_FIELD_NUMBER: builtins.int
+    DROPPED_CHUNKS_FIELD_NUMBER: builtins.int
+    OVERRUNS_FIELD_NUMBER: builtins.int
+    UNDERRUNS_FIELD_NUMBER: builtins.int
+    LAST_ERROR_FIELD_NUMBER: builtins.int
+    LAST_ERROR_TIME_NANOSECONDS_FIELD_NUMBER: builtins.int
+    uptime_seconds: builtins.float
+    """of the server"""
+    active_streams: builtins.int
+    """GetAudio streams in progress"""
+    active_plays: builtins.int
+    """Play calls in progress"""
+    active_sessions: builtins.int
+    """recording sessions in progress"""
+    bytes_captured: builtins.int
+    """audio sent by GetAudio streams"""
+    bytes_played: builtins.int
+    """audio received by Play"""
+    dropped_chunks: builtins.int
+    """chunks GetAudio streams dropped because their client fell behind"""
+    overruns: builtins.int
+    """times capture overran the device's buffer, 0 if the resource doesn't count them"""
+    underruns: builtins.int
+    """times playback ran the device's buffer dry, 0 if the resource doesn't count them"""
+    last_error: builtins.str
+    """of the last GetAudio stream or Play call that failed, empty if none has"""
+    last_error_time_nanoseconds: builtins.int
+    """unix time of last_error, 0 if none"""
+    def __init__(
+        self,
+        *,
+        uptime_seconds: builtins.float = ...,
+        active_streams: builtins.int = ...,
+        active_plays: builtins.int = ...,
+        active_sessions: builtins.int = ...,
+        bytes_captured: builtins.int = ...,
+        bytes_played: builtins.int = ...,
+        dropped_chunks: builtins.int = ...,
+        overruns: builtins.int = ...,
+        underruns: builtins.int = ...,
+        last_error: builtins.str = ...,
+        last_error_time_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["active_plays", b"active_plays", "active_sessions", b"active_sessions", "active_streams", b"active_streams", "bytes_captured", b"bytes_captured", "bytes_played", b"bytes_played", "dropped_chunks", b"dropped_chunks", "last_error", b"last_error", "last_error_time_nanoseconds", b"last_error_time_nanoseconds", "overruns", b"overruns", "underruns", b"underruns", "uptime_seconds", b"uptime_seconds"]) -> None: ...
+
+global___GetStatsResponse = GetStatsResponse
+
+@typing.final
+class GetHealthRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name"]) -> None: ...
+
+global___GetHealthRequest = GetHealthRequest
+
+@typing.final
+class DeviceHealth(google.protobuf.message.Message):
+    """DeviceHealth is the state of one of a resource's devices as last seen"""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    STATE_FIELD_NUMBER: builtins.int
+    REASON_FIELD_NUMBER: builtins.int
+    SINCE_NANOSECONDS_FIELD_NUMBER: builtins.int
+    REOPEN_ATTEMPTS_FIELD_NUMBER: builtins.int
+    state: builtins.str
+    """healthy, recovering, unavailable or off"""
+    reason: builtins.str
+    """why it isn't healthy, empty if it is"""
+    since_nanoseconds: builtins.int
+    """when it entered the state, 0 if it always was"""
+    reopen_attempts: builtins.int
+    """times it has been opened again since it was lost"""
+    def __init__(
+        self,
+        *,
+        state: builtins.str = ...,
+        reason: builtins.str = ...,
+        since_nanoseconds: builtins.int = ...,
+        reopen_attempts: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["reason", b"reason", "reopen_attempts", b"reopen_attempts", "since_nanoseconds", b"since_nanoseconds", "state", b"state"]) -> None: ...
+
+global___DeviceHealth = DeviceHealth
+
+@typing.final
+class GetHealthResponse(google.protobuf.message.Message):
+    """GetHealthResponse is the health of the resource's devices. A resource is ready when none of its
+    devices is recovering or unavailable
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    READY_FIELD_NUMBER: builtins.int
+    CAPTURE_FIELD_NUMBER: builtins.int
+    PLAYBACK_FIELD_NUMBER: builtins.int
+    ready: builtins.bool
+    @property
+    def capture(self) -> global___DeviceHealth: ...
+    @property
+    def playback(self) -> global___DeviceHealth: ...
+    def __init__(
+        self,
+        *,
+        ready: builtins.bool = ...,
+        capture: global___DeviceHealth | None = ...,
+        playback: global___DeviceHealth | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["capture", b"capture", "playback", b"playback"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["capture", b"capture", "playback", b"playback", "ready", b"ready"]) -> None: ...
+
+global___GetHealthResponse = GetHealthResponse
+
+@typing.final
+class DoCommandRequest(google.protobuf.message.Message):
+    """DoCommandRequest carries a resource's DoCommand. The server handles dump_state, list_sessions
+    and reset_device itself and passes other commands to the resource
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    COMMAND_JSON_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    command_json: builtins.str
+    """a JSON object"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        command_json: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["command_json", b"command_json", "name", b"name"]) -> None: ...
+
+global___DoCommandRequest = DoCommandRequest
+
+@typing.final
+class DoCommandResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RESULT_JSON_FIELD_NUMBER: builtins.int
+    result_json: builtins.str
+    """a JSON object"""
+    def __init__(
+        self,
+        *,
+        result_json: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["result_json", b"result_json"]) -> None: ...
+
+global___DoCommandResponse = DoCommandResponse
+
+@typing.final
+class GetRecordingStatsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name"]) -> None: ...
+
+global___GetRecordingStatsRequest = GetRecordingStatsRequest
+
+@typing.final
+class GetRecordingStatsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RECORDINGS_FIELD_NUMBER: builtins.int
+    TOTAL_BYTES_FIELD_NUMBER: builtins.int
+    OLDEST_NANOSECONDS_FIELD_NUMBER: builtins.int
+    NEWEST_NANOSECONDS_FIELD_NUMBER: builtins.int
+    DISK_FREE_BYTES_FIELD_NUMBER: builtins.int
+    DISK_SIZE_BYTES_FIELD_NUMBER: builtins.int
+    MAX_AGE_SECONDS_FIELD_NUMBER: builtins.int
+    MAX_BYTES_FIELD_NUMBER: builtins.int
+    PRUNED_RECORDINGS_FIELD_NUMBER: builtins.int
+    PRUNED_BYTES_FIELD_NUMBER: builtins.int
+    recordings: builtins.int
+    total_bytes: builtins.int
+    """of the recordings"""
+    oldest_nanoseconds: builtins.int
+    """last modified, 0 without recordings"""
+    newest_nanoseconds: builtins.int
+    disk_free_bytes: builtins.int
+    """on the store's filesystem, 0 if unknown"""
+    disk_size_bytes: builtins.int
+    max_age_seconds: builtins.float
+    """of the retention policy, 0 if unbounded"""
+    max_bytes: builtins.int
+    """0 if unbounded"""
+    pruned_recordings: builtins.int
+    """deleted by the retention policy since the server started"""
+    pruned_bytes: builtins.int
+    def __init__(
+        self,
+        *,
+        recordings: builtins.int = ...,
+        total_bytes: builtins.int = ...,
+        oldest_nanoseconds: builtins.int = ...,
+        newest_nanoseconds: builtins.int = ...,
+        disk_free_bytes: builtins.int = ...,
+        disk_size_bytes: builtins.int = ...,
+        max_age_seconds: builtins.float = ...,
+        max_bytes: builtins.int = ...,
+        pruned_recordings: builtins.int = ...,
+        pruned_bytes: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["disk_free_bytes", b"disk_free_bytes", "disk_size_bytes", b"disk_size_bytes", "max_age_seconds", b"max_age_seconds", "max_bytes", b"max_bytes", "newest_nanoseconds", b"newest_nanoseconds", "oldest_nanoseconds", b"oldest_nanoseconds", "pruned_bytes", b"pruned_bytes", "pruned_recordings", b"pruned_recordings", "recordings", b"recordings", "total_bytes", b"total_bytes"]) -> None: ...
+
+global___GetRecordingStatsResponse = GetRecordingStatsResponse
+
+@typing.final
+class StartRecordingRequest(google.protobuf.message.Message):
+    """A recording writes a resource's capture into the recording store in
+    segments of a fixed length, until it is stopped.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    SEGMENT_SECONDS_FIELD_NUMBER: builtins.int
+    FORMAT_FIELD_NUMBER: builtins.int
+    NORMALIZE_LUFS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    segment_seconds: builtins.float
+    """60 if zero"""
+    format: builtins.str
+    """"wav" (the default) or "flac\""""
+    normalize_lufs: builtins.float
+    """the loudness each segment is scaled to, if negative"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        segment_seconds: builtins.float = ...,
+        format: builtins.str = ...,
+        normalize_lufs: builtins.float = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["format", b"format", "name", b"name", "normalize_lufs", b"normalize_lufs", "segment_seconds", b"segment_seconds"]) -> None: ...
+
+global___StartRecordingRequest = StartRecordingRequest
+
+@typing.final
+class StartRecordingResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RECORDING_ID_FIELD_NUMBER: builtins.int
+    recording_id: builtins.str
+    def __init__(
+        self,
+        *,
+        recording_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["recording_id", b"recording_id"]) -> None: ...
+
+global___StartRecordingResponse = StartRecordingResponse
+
+@typing.final
+class StopRecordingRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    RECORDING_ID_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    recording_id: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        recording_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "recording_id", b"recording_id"]) -> None: ...
+
+global___StopRecordingRequest = StopRecordingRequest
+
+@typing.final
+class StopRecordingResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SEGMENTS_FIELD_NUMBER: builtins.int
+    @property
+    def segments(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___RecordingSegment]:
+        """oldest first"""
+
+    def __init__(
+        self,
+        *,
+        segments: collections.abc.Iterable[global___RecordingSegment] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["segments", b"segments"]) -> None: ...
+
+global___StopRecordingResponse = StopRecordingResponse
+
+@typing.final
+class ListSegmentsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    RECORDING_ID_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    recording_id: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        recording_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "recording_id", b"recording_id"]) -> None: ...
+
+global___ListSegmentsRequest = ListSegmentsRequest
+
+@typing.final
+class RecordingSegment(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    FILE_FIELD_NUMBER: builtins.int
+    START_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    DURATION_NANOSECONDS_FIELD_NUMBER: builtins.int
+    COMPLETE_FIELD_NUMBER: builtins.int
+    file: builtins.str
+    """in the recording store"""
+    start_timestamp_nanoseconds: builtins.int
+    """capture time of the first sample"""
+    duration_nanoseconds: builtins.int
+    complete: builtins.bool
+    """false while it is being written"""
+    def __init__(
+        self,
+        *,
+        file: builtins.str = ...,
+        start_timestamp_nanoseconds: builtins.int = ...,
+        duration_nanoseconds: builtins.int = ...,
+        complete: builtins.bool = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["complete", b"complete", "duration_nanoseconds", b"duration_nanoseconds", "file", b"file", "start_timestamp_nanoseconds", b"start_timestamp_nanoseconds"]) -> None: ...
+
+global___RecordingSegment = RecordingSegment
+
+@typing.final
+class ListSegmentsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SEGMENTS_FIELD_NUMBER: builtins.int
+    ACTIVE_FIELD_NUMBER: builtins.int
+    ERROR_FIELD_NUMBER: builtins.int
+    active: builtins.bool
+    error: builtins.str
+    """why the recording ended on its own, if it did"""
+    @property
+    def segments(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___RecordingSegment]:
+        """oldest first"""
+
+    def __init__(
+        self,
+        *,
+        segments: collections.abc.Iterable[global___RecordingSegment] | None = ...,
+        active: builtins.bool = ...,
+        error: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["active", b"active", "error", b"error", "segments", b"segments"]) -> None: ...
+
+global___ListSegmentsResponse = ListSegmentsResponse
+
+@typing.final
+class RecordingTrack(google.protobuf.message.Message):
+    """A track of a recording session records a resource's capture, or some of
+    its channels, to segments of its own.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RESOURCE_FIELD_NUMBER: builtins.int
+    CHANNELS_FIELD_NUMBER: builtins.int
+    NAME_FIELD_NUMBER: builtins.int
+    resource: builtins.str
+    """the resource the request names if empty"""
+    name: builtins.str
+    """prefixes the track's files, the resource and its channels if empty"""
+    @property
+    def channels(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.int]:
+        """zero based channels of the capture to keep, all if empty"""
+
+    def __init__(
+        self,
+        *,
+        resource: builtins.str = ...,
+        channels: collections.abc.Iterable[builtins.int] | None = ...,
+        name: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["channels", b"channels", "name", b"name", "resource", b"resource"]) -> None: ...
+
+global___RecordingTrack = RecordingTrack
+
+@typing.final
+class StartRecordingSessionRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    TRACKS_FIELD_NUMBER: builtins.int
+    SEGMENT_SECONDS_FIELD_NUMBER: builtins.int
+    FORMAT_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    segment_seconds: builtins.float
+    """60 if zero"""
+    format: builtins.str
+    """"wav" (the default) or "flac\""""
+    @property
+    def tracks(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___RecordingTrack]: ...
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        tracks: collections.abc.Iterable[global___RecordingTrack] | None = ...,
+        segment_seconds: builtins.float = ...,
+        format: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["format", b"format", "name", b"name", "segment_seconds", b"segment_seconds", "tracks", b"tracks"]) -> None: ...
+
+global___StartRecordingSessionRequest = StartRecordingSessionRequest
+
+@typing.final
+class StartRecordingSessionResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SESSION_ID_FIELD_NUMBER: builtins.int
+    session_id: builtins.str
+    def __init__(
+        self,
+        *,
+        session_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["session_id", b"session_id"]) -> None: ...
+
+global___StartRecordingSessionResponse = StartRecordingSessionResponse
+
+@typing.final
+class StopRecordingSessionRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    SESSION_ID_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    session_id: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        session_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "session_id", b"session_id"]) -> None: ...
+
+global___StopRecordingSessionRequest = StopRecordingSessionRequest
+
+@typing.final
+class StopRecordingSessionResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SESSION_FIELD_NUMBER: builtins.int
+    @property
+    def session(self) -> global___RecordingSession: ...
+    def __init__(
+        self,
+        *,
+        session: global___RecordingSession | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["session", b"session"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["session", b"session"]) -> None: ...
+
+global___StopRecordingSessionResponse = StopRecordingSessionResponse
+
+@typing.final
+class GetRecordingSessionRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    SESSION_ID_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    session_id: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        session_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "session_id", b"session_id"]) -> None: ...
+
+global___GetRecordingSessionRequest = GetRecordingSessionRequest
+
+@typing.final
+class GetRecordingSessionResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SESSION_FIELD_NUMBER: builtins.int
+    @property
+    def session(self) -> global___RecordingSession: ...
+    def __init__(
+        self,
+        *,
+        session: global___RecordingSession | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["session", b"session"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["session", b"session"]) -> None: ...
+
+global___GetRecordingSessionResponse = GetRecordingSessionResponse
+
+@typing.final
+class RecordingSession(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    START_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    TRACKS_FIELD_NUMBER: builtins.int
+    ACTIVE_FIELD_NUMBER: builtins.int
+    start_timestamp_nanoseconds: builtins.int
+    """the origin of the tracks' offsets"""
+    active: builtins.bool
+    @property
+    def tracks(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___TrackStatus]:
+        """in the order requested"""
+
+    def __init__(
+        self,
+        *,
+        start_timestamp_nanoseconds: builtins.int = ...,
+        tracks: collections.abc.Iterable[global___TrackStatus] | None = ...,
+        active: builtins.bool = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["active", b"active", "start_timestamp_nanoseconds", b"start_timestamp_nanoseconds", "tracks", b"tracks"]) -> None: ...
+
+global___RecordingSession = RecordingSession
+
+@typing.final
+class TrackStatus(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    TRACK_FIELD_NUMBER: builtins.int
+    SEGMENTS_FIELD_NUMBER: builtins.int
+    OFFSET_NANOSECONDS_FIELD_NUMBER: builtins.int
+    ERROR_FIELD_NUMBER: builtins.int
+    offset_nanoseconds: builtins.int
+    """capture time of the track's first sample after the session start"""
+    error: builtins.str
+    """why the track ended on its own, if it did"""
+    @property
+    def track(self) -> global___RecordingTrack:
+        """with its resource and name filled in"""
+
+    @property
+    def segments(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___RecordingSegment]:
+        """oldest first"""
+
+    def __init__(
+        self,
+        *,
+        track: global___RecordingTrack | None = ...,
+        segments: collections.abc.Iterable[global___RecordingSegment] | None = ...,
+        offset_nanoseconds: builtins.int = ...,
+        error: builtins.str = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["track", b"track"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["error", b"error", "offset_nanoseconds", b"offset_nanoseconds", "segments", b"segments", "track", b"track"]) -> None: ...
+
+global___TrackStatus = TrackStatus
+
+@typing.final
+class RecordingWindow(google.protobuf.message.Message):
+    """A window records from each time start matches to the next time stop
+    does. Both are five field cron expressions: minute, hour, day of month,
+    month and day of week.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    START_FIELD_NUMBER: builtins.int
+    STOP_FIELD_NUMBER: builtins.int
+    start: builtins.str
+    stop: builtins.str
+    def __init__(
+        self,
+        *,
+        start: builtins.str = ...,
+        stop: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["start", b"start", "stop", b"stop"]) -> None: ...
+
+global___RecordingWindow = RecordingWindow
+
+@typing.final
+class ScheduleRecordingRequest(google.protobuf.message.Message):
+    """A schedule records into the server's recording store whenever one of
+    its windows is open, until it is cancelled.
+    """
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    WINDOWS_FIELD_NUMBER: builtins.int
+    TIME_ZONE_FIELD_NUMBER: builtins.int
+    SEGMENT_SECONDS_FIELD_NUMBER: builtins.int
+    FORMAT_FIELD_NUMBER: builtins.int
+    NORMALIZE_LUFS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    time_zone: builtins.str
+    """IANA name the windows are in, the server's local time if empty"""
+    segment_seconds: builtins.float
+    """60 if zero"""
+    format: builtins.str
+    """"wav" (the default) or "flac\""""
+    normalize_lufs: builtins.float
+    """the loudness each segment is scaled to, if negative"""
+    @property
+    def windows(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___RecordingWindow]: ...
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        windows: collections.abc.Iterable[global___RecordingWindow] | None = ...,
+        time_zone: builtins.str = ...,
+        segment_seconds: builtins.float = ...,
+        format: builtins.str = ...,
+        normalize_lufs: builtins.float = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["format", b"format", "name", b"name", "normalize_lufs", b"normalize_lufs", "segment_seconds", b"segment_seconds", "time_zone", b"time_zone", "windows", b"windows"]) -> None: ...
+
+global___ScheduleRecordingRequest = ScheduleRecordingRequest
+
+@typing.final
+class ScheduleRecordingResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SCHEDULE_ID_FIELD_NUMBER: builtins.int
+    NEXT_TIMESTAMP_NANOSECONDS_FIELD_NUMBER: builtins.int
+    schedule_id: builtins.str
+    next_timestamp_nanoseconds: builtins.int
+    """when recording next starts or stops, 0 if never"""
+    def __init__(
+        self,
+        *,
+        schedule_id: builtins.str = ...,
+        next_timestamp_nanoseconds: builtins.int = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["next_timestamp_nanoseconds", b"next_timestamp_nanoseconds", "schedule_id", b"schedule_id"]) -> None: ...
+
+global___ScheduleRecordingResponse = ScheduleRecordingResponse
+
+@typing.final
+class CancelScheduledRecordingRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    SCHEDULE_ID_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    schedule_id: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        schedule_id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name", "schedule_id", b"schedule_id"]) -> None: ...
+
+global___CancelScheduledRecordingRequest = CancelScheduledRecordingRequest
+
+@typing.final
+class CancelScheduledRecordingResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    SEGMENTS_FIELD_NUMBER: builtins.int
+    @property
+    def segments(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___RecordingSegment]:
+        """written by the schedule, oldest first"""
+
+    def __init__(
+        self,
+        *,
+        segments: collections.abc.Iterable[global___RecordingSegment] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["segments", b"segments"]) -> None: ...
+
+global___CancelScheduledRecordingResponse = CancelScheduledRecordingResponse
+
+@typing.final
+class GetTriggerStateRequest(google.protobuf.message.Message):
+    """Resources following their level with a trigger, such as level_trigger."""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["name", b"name"]) -> None: ...
+
+global___GetTriggerStateRequest = GetTriggerStateRequest
+
+@typing.final
+class GetTriggerStateResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ACTIVE_FIELD_NUMBER: builtins.int
+    LEVEL_DBFS_FIELD_NUMBER: builtins.int
+    SINCE_NANOSECONDS_FIELD_NUMBER: builtins.int
+    TRIGGERS_FIELD_NUMBER: builtins.int
+    THRESHOLD_DBFS_FIELD_NUMBER: builtins.int
+    RELEASE_DBFS_FIELD_NUMBER: builtins.int
+    SEGMENTS_FIELD_NUMBER: builtins.int
+    active: builtins.bool
+    level_dbfs: builtins.float
+    """RMS level of the last 100ms of capture"""
+    since_nanoseconds: builtins.int
+    """when active last changed, 0 if it never has"""
+    triggers: builtins.int
+    """times the trigger has fired"""
+    threshold_dbfs: builtins.float
+    release_dbfs: builtins.float
+    """the level it releases below"""
+    @property
+    def segments(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___RecordingSegment]:
+        """recorded while active, oldest first"""
+
+    def __init__(
+        self,
+        *,
+        active: builtins.bool = ...,
+        level_dbfs: builtins.float = ...,
+        since_nanoseconds: builtins.int = ...,
+        triggers: builtins.int = ...,
+        threshold_dbfs: builtins.float = ...,
+        release_dbfs: builtins.float = ...,
+        segments: collections.abc.Iterable[global___RecordingSegment] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["active", b"active", "level_dbfs", b"level_dbfs", "release_dbfs", b"release_dbfs", "segments", b"segments", "since_nanoseconds", b"since_nanoseconds", "threshold_dbfs", b"threshold_dbfs", "triggers", b"triggers"]) -> None: ...
+
+global___GetTriggerStateResponse = GetTriggerStateResponse
+
+@typing.final
+class ListDevicesRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    NAME_FIELD_NUMBER: builtins.int
+    PAGE_SIZE_FIELD_NUMBER: builtins.int
+    PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    DIRECTION_FIELD_NUMBER: builtins.int
+    CONTAINS_FIELD_NUMBER: builtins.int
+    name: builtins.str
+    page_size: builtins.int
+    """defaults to 100, at most 1000"""
+    page_token: builtins.str
+    """empty for the first"""
+    direction: builtins.str
+    """"capture" or "playback\""""
+    contains: builtins.str
+    """in the id or name, ignoring case"""
+    def __init__(
+        self,
+        *,
+        name: builtins.str = ...,
+        page_size: builtins.int = ...,
+        page_token: builtins.str = ...,
+        direction: builtins.str = ...,
+        contains: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["contains", b"contains", "direction", b"direction", "name", b"name", "page_size", b"page_size", "page_token", b"page_token"]) -> None: ...
+
+global___ListDevicesRequest = ListDevicesRequest
+
+@typing.final
+class Device(google.protobuf.message.Message):
+    """A device the resource's backend can open."""
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ID_FIELD_NUMBER: builtins.int
+    NAME_FIELD_NUMBER: builtins.int
+    DRIVER_FIELD_NUMBER: builtins.int
+    CAPTURE_FIELD_NUMBER: builtins.int
+    PLAYBACK_FIELD_NUMBER: builtins.int
+    DEFAULT_CAPTURE_FIELD_NUMBER: builtins.int
+    DEFAULT_PLAYBACK_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    name: builtins.str
+    driver: builtins.str
+    capture: builtins.bool
+    playback: builtins.bool
+    default_capture: builtins.bool
+    default_playback: builtins.bool
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+        name: builtins.str = ...,
+        driver: builtins.str = ...,
+        capture: builtins.bool = ...,
+        playback: builtins.bool = ...,
+        default_capture: builtins.bool = ...,
+        default_playback: builtins.bool = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["capture", b"capture", "default_capture", b"default_capture", "default_playback", b"default_playback", "driver", b"driver", "id", b"id", "name", b"name", "playback", b"playback"]) -> None: ...
+
+global___Device = Device
+
+@typing.final
+class ListDevicesResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    DEVICES_FIELD_NUMBER: builtins.int
+    NEXT_PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    next_page_token: builtins.str
+    """empty on the last page"""
+    @property
+    def devices(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___Device]:
+        """by id"""
+
+    def __init__(
+        self,
+        *,
+        devices: collections.abc.Iterable[global___Device] | None = ...,
+        next_page_token: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["devices", b"devices", "next_page_token", b"next_page_token"]) -> None: ...
+
+global___ListDevicesResponse = ListDevicesResponse
+
 @typing.final
 class PropertiesRequest(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -0,0 +1,131 @@
+package audio
+
+import (
+	"time"
+)
+
+// The chunk durations WithChunkDuration accepts.
+const (
+	minChunkDuration = 10 * time.Millisecond
+	maxChunkDuration = 500 * time.Millisecond
+)
+
+func checkChunkDuration(d time.Duration) error {
+	if d != 0 && (d < minChunkDuration || d > maxChunkDuration) {
+		return errorf(ErrInvalidArgument, "chunk duration must be between %v and %v, got %v", minChunkDuration, maxChunkDuration, d)
+	}
+	return nil
+}
+
+// chunkFrames is how many frames at rate make a chunk of d.
+func chunkFrames(d time.Duration, rate int) int {
+	return max(int((d*time.Duration(rate)+time.Second/2)/time.Second), 1)
+}
+
+// rechunker regroups raw PCM chunks into chunks of a fixed duration. Audio
+// is held back until it fills a chunk, except before a gap or a format
+// change, where what is held goes out short so both stay where they were.
+type rechunker struct {
+	duration time.Duration
+
+	info      *AudioInfo // format of the held audio, nil when nothing is held
+	held      []byte
+	start     time.Time // capture time of the first held frame, zero if unknown
+	gap       time.Duration
+	header    *StreamHeader // goes out with the next chunk
+	speech    *bool         // whether any held audio is speech
+	last      *bool         // whether the last chunk pushed was
+	timecode  *Timecode
+	announced AudioInfo // format of the last header sent
+	seq       int64
+}
+
+func newRechunker(d time.Duration) *rechunker {
+	return &rechunker{duration: d}
+}
+
+// push takes chunk and returns the chunks that are full. Chunks without a
+// format or with an error flush what is held and pass through as they are.
+func (r *rechunker) push(chunk *AudioChunk) []*AudioChunk {
+	if chunk.Err != nil || chunk.Info == nil || len(chunk.AudioData) == 0 {
+		return append(r.flush(), chunk)
+	}
+	width, err := bytesPerSample(chunk.Info.Format)
+	if err != nil || chunk.Info.Channels == 0 || chunk.Info.SampleRate == 0 {
+		return append(r.flush(), chunk)
+	}
+	var ready []*AudioChunk
+	if r.info != nil && (*r.info != *chunk.Info || chunk.Gap > 0 || chunk.Header != nil) {
+		ready = r.flush()
+	}
+	if r.info == nil {
+		r.info, r.start, r.gap = chunk.Info, chunk.Timestamp, chunk.Gap
+		r.speech, r.timecode = nil, nil
+	}
+	if chunk.Header != nil || r.announced != *chunk.Info {
+		h := headerFor(*chunk.Info)
+		if chunk.Header != nil {
+			copied := *chunk.Header
+			h = &copied
+		}
+		h.ChunkFrames = chunkFrames(r.duration, chunk.Info.SampleRate)
+		r.header, r.announced = h, *chunk.Info
+	}
+	if r.last = chunk.Speech; chunk.Speech != nil {
+		speech := *chunk.Speech || (r.speech != nil && *r.speech)
+		r.speech = &speech
+	}
+	if chunk.Timecode != nil {
+		r.timecode = chunk.Timecode
+	}
+	r.held = append(r.held, chunk.AudioData...)
+	chunk.Release()
+
+	frameSize := width * chunk.Info.Channels
+	size := chunkFrames(r.duration, chunk.Info.SampleRate) * frameSize
+	for len(r.held) >= size {
+		ready = append(ready, r.cut(size, frameSize))
+	}
+	if len(r.held) == 0 {
+		r.info = nil
+	}
+	return ready
+}
+
+// flush returns what is held as a short chunk, if anything is.
+func (r *rechunker) flush() []*AudioChunk {
+	if r.info == nil {
+		return nil
+	}
+	defer func() { r.info = nil }()
+	if len(r.held) == 0 {
+		return nil
+	}
+	width, _ := bytesPerSample(r.info.Format)
+	return []*AudioChunk{r.cut(len(r.held), width*r.info.Channels)}
+}
+
+// cut returns the first n held bytes as a chunk.
+func (r *rechunker) cut(n, frameSize int) *AudioChunk {
+	data := borrowBuffer(n)
+	copy(data, r.held)
+	r.held = r.held[:copy(r.held, r.held[n:])]
+	chunk := &AudioChunk{
+		pooled:    true,
+		Sequence:  r.seq,
+		AudioData: data,
+		Info:      r.info,
+		Gap:       r.gap,
+		Header:    r.header,
+		Timestamp: r.start,
+		Speech:    r.speech,
+		Timecode:  r.timecode,
+	}
+	r.seq++
+	// what is left is all from the last chunk pushed
+	r.gap, r.header, r.speech, r.timecode = 0, nil, r.last, nil
+	if !r.start.IsZero() {
+		r.start = r.start.Add(framesDuration(int64(n/frameSize), r.info.SampleRate))
+	}
+	return chunk
+}
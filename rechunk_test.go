@@ -0,0 +1,79 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRechunker(t *testing.T) {
+	info := &AudioInfo{Format: Pcm16, SampleRate: 1000, Channels: 1}
+	at := time.Unix(100, 0)
+	r := newRechunker(50 * time.Millisecond)
+	var out []*AudioChunk
+	// 30ms chunks regrouped into 50ms ones: 30+20, 10+30+10, and the rest
+	for i := range 4 {
+		out = append(out, r.push(&AudioChunk{AudioData: make([]byte, 60), Info: info, Timestamp: at.Add(time.Duration(i) * 30 * time.Millisecond)})...)
+	}
+	// a gap sends the 20ms still held on short
+	out = append(out, r.push(&AudioChunk{AudioData: make([]byte, 60), Info: info, Gap: time.Second, Timestamp: at.Add(1120 * time.Millisecond)})...)
+	out = append(out, r.flush()...)
+
+	want := []struct {
+		bytes int
+		at    time.Duration
+		gap   time.Duration
+	}{{100, 0, 0}, {100, 50 * time.Millisecond, 0}, {40, 100 * time.Millisecond, 0}, {60, 1120 * time.Millisecond, time.Second}}
+	if len(out) != len(want) {
+		t.Fatalf("got %d chunks, want %d", len(out), len(want))
+	}
+	for i, w := range want {
+		c := out[i]
+		if len(c.AudioData) != w.bytes || !c.Timestamp.Equal(at.Add(w.at)) || c.Gap != w.gap || c.Sequence != int64(i) {
+			t.Errorf("chunk %d has %d bytes at %v after a %v gap, want %+v", i, len(c.AudioData), c.Timestamp.Sub(at), c.Gap, w)
+		}
+	}
+	if h := out[0].Header; h == nil || h.ChunkFrames != 50 || h.SampleRate != 1000 {
+		t.Errorf("first chunk has header %+v, want one of 50 frames", h)
+	}
+	if out[1].Header != nil {
+		t.Error("a chunk in the same format repeated the header")
+	}
+}
+
+func TestChunkDuration(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	c := serveAudio(t, src)
+	close(src.start)
+
+	// 500ms captured in 10ms chunks comes out as twelve of 40ms and the rest
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithChunkDuration(40*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sizes []int
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		if len(sizes) == 0 && (chunk.Header == nil || chunk.Header.ChunkFrames != 320) {
+			t.Errorf("first chunk has header %+v, want one of 320 frames", chunk.Header)
+		}
+		sizes = append(sizes, len(chunk.AudioData))
+	}
+	if len(sizes) != 13 || sizes[0] != 640 || sizes[11] != 640 || sizes[12] != 320 {
+		t.Errorf("got chunks of %v bytes", sizes)
+	}
+
+	for _, d := range []time.Duration{5 * time.Millisecond, time.Second} {
+		ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithChunkDuration(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chunk := <-ch; chunk == nil || chunk.Err == nil {
+			t.Errorf("streamed chunks of %v", d)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"context"
+	"sync"
+
+	"go.viam.com/rdk/resource"
+)
+
+// ReconfigureFunc takes a config change in place, from old to new. It
+// returns false, having changed nothing, when the change needs the
+// resource rebuilt. deps are the resource's dependencies as of new, which
+// may have been rebuilt themselves.
+type ReconfigureFunc[C any] func(ctx context.Context, deps resource.Dependencies, old, new C) (bool, error)
+
+// Reconfigurable is embedded by resources, in place of
+// resource.AlwaysRebuild, that can take some config changes without
+// tearing down their streams: the ones apply takes, such as a new level,
+// go through, the others rebuild the resource as before. C is the model's
+// config type, registered as *C.
+type Reconfigurable[C any] struct {
+	mu     sync.Mutex
+	config C
+	apply  ReconfigureFunc[C]
+}
+
+// NewReconfigurable returns a Reconfigurable for a resource built with
+// config.
+func NewReconfigurable[C any](config C, apply ReconfigureFunc[C]) *Reconfigurable[C] {
+	return &Reconfigurable[C]{config: config, apply: apply}
+}
+
+// Reconfigure applies conf in place if it can, and otherwise fails as
+// resource.AlwaysRebuild does so that the resource is rebuilt.
+func (r *Reconfigurable[C]) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	cfg, err := resource.NativeConfig[*C](conf)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	applied, err := r.apply(ctx, deps, r.config, *cfg)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return resource.AlwaysRebuild{}.Reconfigure(ctx, deps, conf)
+	}
+	r.config = *cfg
+	return nil
+}
+
+// Config returns the config the resource was last built or reconfigured
+// with.
+func (r *Reconfigurable[C]) Config() C {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.config
+}
@@ -0,0 +1,117 @@
+package audio
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestReconfigureFake(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("fake"), FakeConfig{SampleRate: 8000, ChunkMs: 10}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	ch, err := f.GetAudio(ctx, Pcm32Float.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loud := func() bool {
+		chunk := <-ch
+		if chunk == nil || chunk.Err != nil {
+			t.Fatalf("the stream ended with %v", chunk)
+		}
+		samples, _ := decodePCM(chunk.AudioData, Pcm32Float)
+		return slices.ContainsFunc(samples, func(s float32) bool { return s != 0 })
+	}
+	if !loud() {
+		t.Fatal("the tone is silent")
+	}
+
+	conf := func(cfg FakeConfig) resource.Config {
+		return resource.Config{Name: "fake", API: API, Model: FakeModel, ConvertedAttributes: &cfg}
+	}
+	if err := f.Reconfigure(ctx, nil, conf(FakeConfig{Signal: "silence", SampleRate: 8000, ChunkMs: 10, PlaybackChannels: []int{2}})); err != nil {
+		t.Fatal(err)
+	}
+	// the stream goes on, with the new signal once the chunk in flight is out
+	loud()
+	if loud() {
+		t.Error("the stream didn't take the new signal")
+	}
+	if props, _ := f.Properties(ctx); !slices.Equal(props.PlaybackChannels, []int{2}) {
+		t.Errorf("plays %v channels", props.PlaybackChannels)
+	}
+
+	if err := f.Reconfigure(ctx, nil, conf(FakeConfig{Signal: "silence", SampleRate: 16000, ChunkMs: 10})); err == nil {
+		t.Error("changed the sample rate in place")
+	}
+	if got := f.Config(); got.SampleRate != 8000 || got.Signal != "silence" {
+		t.Errorf("config is %+v after a rebuild was asked for", got)
+	}
+	if err := f.Reconfigure(ctx, nil, conf(FakeConfig{Signal: "chirp", SampleRate: 8000, ChunkMs: 10})); err == nil {
+		t.Error("took an unknown signal")
+	}
+}
+
+func TestReconfigureEQ(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	a, _ := NewFake(Named("a"), FakeConfig{Unpaced: true}, logger)
+	b, _ := NewFake(Named("b"), FakeConfig{Unpaced: true}, logger)
+	low := []EQBand{{Type: "lowshelf", FrequencyHz: 200, GainDB: 3, Q: 0.7}}
+	res, err := NewEQ(Named("eq"), a, EQConfig{Output: "a", Bands: low}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq := res.(*equalized)
+	conf := func(cfg EQConfig) resource.Config {
+		return resource.Config{Name: "eq", API: API, Model: EQModel, ConvertedAttributes: &cfg}
+	}
+	deps := resource.Dependencies{Named("a"): a, Named("b"): b}
+
+	// bands set at runtime outlive a reconfigure that leaves the config's alone
+	high := []EQBand{{Type: "highshelf", FrequencyHz: 4000, GainDB: -3, Q: 0.7}}
+	if err := eq.SetEQ(ctx, high); err != nil {
+		t.Fatal(err)
+	}
+	if err := eq.Reconfigure(ctx, deps, conf(EQConfig{Output: "b", Bands: low})); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := eq.GetEQ(ctx); !slices.Equal(got, high) {
+		t.Errorf("bands are %v", got)
+	}
+	clip, _ := encodePCM(make([]float32, 160), Pcm16)
+	if err := eq.Play(ctx, clip, "pcm16", 16000, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.LastPlay(); !ok {
+		t.Error("the eq doesn't play through its new output")
+	}
+	if err := eq.Reconfigure(ctx, deps, conf(EQConfig{Output: "b"})); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := eq.GetEQ(ctx); len(got) != 0 {
+		t.Errorf("bands are %v after the config dropped them", got)
+	}
+	if err := eq.Reconfigure(ctx, deps, conf(EQConfig{Output: "c"})); err == nil {
+		t.Error("reconfigured onto a missing output")
+	}
+}
+
+func TestReconfigureClient(t *testing.T) {
+	src := newBurstSource(1, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	c := serveAudio(t, src)
+	if err := c.Reconfigure(context.Background(), nil, resource.Config{Name: "burst", API: API}); err != nil {
+		t.Error(err)
+	}
+	if err := c.Reconfigure(context.Background(), nil, resource.Config{Name: "other", API: API}); err == nil {
+		t.Error("a client was reconfigured onto another resource")
+	}
+}
@@ -0,0 +1,110 @@
+package audio
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// reconnection re-opens the GetAudio stream of a client requested
+// WithReconnect, resuming it after the last chunk received.
+type reconnection struct {
+	c         *audioClient
+	req       *pb.GetAudioRequest // as first requested
+	policy    RetryPolicy
+	heartbeat time.Duration // the interval streams are watched at, zero if they aren't
+	token     string        // of the last chunk received, empty to start over
+	end       time.Time     // capture time the last chunk received ends at, zero if unknown
+	at        time.Time     // when the last chunk was received, zero before the first
+	resumed   bool          // the stream was last re-opened from token
+}
+
+// received keeps what resuming after chunk takes.
+func (r *reconnection) received(chunk *AudioChunk) {
+	if chunk.ResumeToken != "" {
+		r.token = chunk.ResumeToken
+	}
+	r.end, r.at = time.Time{}, chunk.Received
+	if d, ok := chunkDuration(chunk); ok && !chunk.Timestamp.IsZero() {
+		r.end = chunk.Timestamp.Add(d)
+	}
+}
+
+// reconnected marks chunk, the first after the stream was re-opened. A
+// stream started over lost the audio captured since the last chunk, from
+// the capture timestamps when both chunks have them.
+func (r *reconnection) reconnected(chunk *AudioChunk) {
+	chunk.Reconnected = true
+	if r.resumed || r.at.IsZero() {
+		return
+	}
+	if !r.end.IsZero() && !chunk.Timestamp.IsZero() {
+		chunk.Gap += max(chunk.Timestamp.Sub(r.end), 0)
+	} else {
+		chunk.Gap += chunk.Received.Sub(r.at)
+	}
+}
+
+// reopen re-opens a stream that failed with err, backing off between
+// attempts. It returns the new stream and its first message, a nil
+// message if it ended without one, or the error the stream ends with once
+// it can't be re-opened.
+func (r *reconnection) reopen(ctx context.Context, err error) (pb.AudioService_GetAudioClient, *pb.AudioChunk, error) {
+	backoff := r.policy.InitialBackoff
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+	maxBackoff := r.policy.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	// the failed stream was the first attempt
+	for attempt := 2; r.policy.retries(err); attempt++ {
+		if r.policy.MaxAttempts >= 2 && attempt > r.policy.MaxAttempts {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, nil, err
+		}
+		backoff = min(backoff*2, maxBackoff)
+		r.c.logger.Warnw("re-opening GetAudio stream", "resource", r.req.Name, "attempt", attempt, "error", err)
+		var stream pb.AudioService_GetAudioClient
+		var msg *pb.AudioChunk
+		stream, msg, err = r.open(ctx)
+		if code := status.Code(err); r.resumed && (code == codes.FailedPrecondition || code == codes.InvalidArgument) {
+			// the server no longer has the stream, so start it over
+			r.token = ""
+			stream, msg, err = r.open(ctx)
+		}
+		if err == nil {
+			return stream, msg, nil
+		}
+	}
+	return nil, nil, err
+}
+
+// open opens the stream from the token, or as first requested without one,
+// and receives its first message.
+func (r *reconnection) open(ctx context.Context) (pb.AudioService_GetAudioClient, *pb.AudioChunk, error) {
+	req := r.req
+	if r.resumed = r.token != ""; r.resumed {
+		req = proto.CloneOf(r.req)
+		req.ResumeToken = r.token
+	}
+	stream, err := openWatched(ctx, r.c.client, req, r.heartbeat)
+	if err != nil {
+		return nil, nil, streamError(err)
+	}
+	msg, err := stream.Recv()
+	if err != nil {
+		return nil, nil, streamError(err)
+	}
+	return stream, msg, nil
+}
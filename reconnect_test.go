@@ -0,0 +1,135 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// flakyLink drops the next drops streams once they received after
+// messages. With forget set the server forgets every stream, as if its
+// resume window had passed.
+type flakyLink struct {
+	drops  atomic.Int32
+	after  int
+	forget bool
+}
+
+func (l *flakyLink) intercept(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &flakyStream{ClientStream: s, link: l, cancel: cancel}, nil
+}
+
+type flakyStream struct {
+	grpc.ClientStream
+	link     *flakyLink
+	cancel   context.CancelFunc
+	received int
+}
+
+func (s *flakyStream) SendMsg(m any) error {
+	if req, ok := m.(*pb.GetAudioRequest); ok && req.ResumeToken != "" && s.link.forget {
+		req.ResumeToken = base64.RawURLEncoding.EncodeToString(make([]byte, resumeIDSize+8))
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *flakyStream) RecvMsg(m any) error {
+	if s.received == s.link.after && s.link.drops.Add(-1) >= 0 {
+		s.cancel()
+		return status.Error(codes.Unavailable, "link dropped")
+	}
+	s.received++
+	return s.ClientStream.RecvMsg(m)
+}
+
+func TestReconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(30, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 { return tone(8000, 80, 300+float64(i)*20, 0.5) }
+	src.captured = true
+	var want []byte
+	for i := range src.n {
+		data, _ := encodePCM(src.samples(i), Pcm16)
+		want = append(want, data...)
+	}
+	link := &flakyLink{after: 5}
+	c := serveAudio(t, src, WithStreamInterceptor(link.intercept))
+	close(src.start)
+	policy := RetryPolicy{InitialBackoff: 10 * time.Millisecond}
+
+	// a dropped link is resumed without losing or repeating anything
+	link.drops.Store(1)
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithReconnect(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []byte
+	reconnections := 0
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		if chunk.Reconnected {
+			reconnections++
+			if chunk.Gap != 0 {
+				t.Errorf("resumed stream lost %v", chunk.Gap)
+			}
+		}
+		got = append(got, chunk.AudioData...)
+	}
+	if reconnections != 1 || !bytes.Equal(got, want) {
+		t.Errorf("reconnected %d times to %d bytes, want once to the %d captured", reconnections, len(got), len(want))
+	}
+
+	// a stream the server forgot starts over, reporting what was lost
+	link.drops.Store(1)
+	link.forget = true
+	ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0, WithReconnect(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		if chunk.Reconnected && (n != 5 || chunk.Gap <= 0) {
+			t.Errorf("chunk %d marked reconnected after a gap of %v", n, chunk.Gap)
+		}
+		n++
+	}
+	if n != 5+src.n {
+		t.Errorf("got %d chunks, want the 5 before the drop and the %d of the new capture", n, src.n)
+	}
+
+	// attempts give up after the policy's maximum
+	link.drops.Store(10)
+	link.after = 0
+	policy.MaxAttempts = 3
+	ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0, WithReconnect(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || status.Code(chunk.Err) != codes.Unavailable {
+		t.Fatalf("gave up with %+v", chunk)
+	}
+	if left := link.drops.Load(); left != 7 {
+		t.Errorf("made %d attempts, want 3", 10-left)
+	}
+}
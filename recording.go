@@ -0,0 +1,724 @@
+package audio
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+	"go.viam.com/rdk/logging"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+const defaultSegmentDuration = time.Minute
+
+// flacBlockSize is the frames in each FLAC frame of a recorded segment.
+const flacBlockSize = 4096
+
+// RecordingConfig describes where StartRecording writes a resource's capture.
+type RecordingConfig struct {
+	// Dir is the local directory segments are written to.
+	Dir string
+	// SegmentDuration is the length of each file, one minute if zero. A gap
+	// in the capture or a format change also starts a new segment.
+	SegmentDuration time.Duration
+	// Format is "wav" (the default) or "flac", which holds at most eight
+	// channels. A FLAC segment is written as a hidden WAV and encoded once
+	// it is complete.
+	Format string
+	// ObjectStore, if set, uploads every completed segment with retry and
+	// resumable multipart uploads.
+	ObjectStore *ObjectStoreConfig
+	// NormalizeLUFS, if negative, scales each segment to this integrated
+	// loudness once it is complete, see NormalizeLoudness.
+	NormalizeLUFS float64
+}
+
+// Recording is a capture being written to disk by StartRecording.
+type Recording struct {
+	uploader *ObjectUploader
+	logger   logging.Logger
+
+	mu       sync.Mutex
+	segments []RecordingSegment
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error // why the recording ended, readable once done is closed
+}
+
+// RecordingSegment is a file a Recording wrote or is writing.
+type RecordingSegment struct {
+	Path     string
+	Start    time.Time // capture time of the first sample
+	Duration time.Duration
+	Complete bool // false while the segment is being written
+}
+
+// recordingTimeLayout is the capture time in the names of segments.
+const recordingTimeLayout = "20060102T150405.000Z"
+
+// StartRecording records the capture of a as 16-bit segments in cfg.Dir
+// until Stop is called or the capture ends. Segments are named after the
+// resource and the capture time of their first sample.
+func StartRecording(ctx context.Context, a Audio, cfg RecordingConfig, logger logging.Logger) (*Recording, error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	r, err := newRecording(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	chunks, err := sharedCaptureHub.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm16}})
+	if err != nil {
+		cancel()
+		r.abort()
+		return nil, err
+	}
+	r.start(chunks, a.Name().ShortName(), cfg, cancel)
+	return r, nil
+}
+
+// withDefaults checks cfg and fills in its defaults.
+func (cfg RecordingConfig) withDefaults() (RecordingConfig, error) {
+	if cfg.Dir == "" {
+		return cfg, errors.New("recording needs a directory")
+	}
+	switch cfg.Format {
+	case "":
+		cfg.Format = "wav"
+	case "wav", "flac":
+	default:
+		return cfg, fmt.Errorf("invalid recording format %q, want \"wav\" or \"flac\"", cfg.Format)
+	}
+	if cfg.SegmentDuration == 0 {
+		cfg.SegmentDuration = defaultSegmentDuration
+	}
+	if cfg.NormalizeLUFS > 0 {
+		return cfg, fmt.Errorf("target loudness must be below 0 LUFS, got %g", cfg.NormalizeLUFS)
+	}
+	return cfg, nil
+}
+
+// newRecording prepares a recording to cfg, which has its defaults, to be
+// started or aborted.
+func newRecording(cfg RecordingConfig, logger logging.Logger) (*Recording, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	r := &Recording{logger: logger, done: make(chan struct{})}
+	if cfg.ObjectStore != nil {
+		u, err := NewObjectUploader(*cfg.ObjectStore, logger)
+		if err != nil {
+			return nil, err
+		}
+		r.uploader = u
+	}
+	return r, nil
+}
+
+// start records chunks as segments named after name; cancel ends chunks.
+func (r *Recording) start(chunks <-chan *AudioChunk, name string, cfg RecordingConfig, cancel context.CancelFunc) {
+	r.cancel = cancel
+	ServerWebhooks.Notify(WebhookEvent{Type: WebhookRecordingStarted, Resource: name})
+	go func() {
+		defer close(r.done)
+		r.err = r.record(chunks, name, cfg)
+		ServerWebhooks.Notify(r.stoppedEvent(name))
+	}()
+}
+
+// abort releases a recording that wasn't started.
+func (r *Recording) abort() {
+	if r.uploader != nil {
+		r.uploader.Close()
+	}
+}
+
+// Stop ends the recording, finishes the current segment and waits for the
+// segments to be uploaded or ctx to be done. Segments still queued then stay
+// on disk. It returns the error that ended the capture, if any.
+func (r *Recording) Stop(ctx context.Context) error {
+	r.cancel()
+	<-r.done
+	if r.uploader == nil {
+		return r.err
+	}
+	flushErr := r.uploader.Flush(ctx)
+	r.uploader.Close()
+	if r.err != nil {
+		return r.err
+	}
+	return flushErr
+}
+
+// stoppedEvent is posted to webhooks when the recording ends, with its
+// first segment as the clip.
+func (r *Recording) stoppedEvent(name string) WebhookEvent {
+	ev := WebhookEvent{Type: WebhookRecordingStopped, Resource: name}
+	segments := r.Segments()
+	if len(segments) == 0 {
+		return ev
+	}
+	ev.Clip = filepath.Base(segments[0].Path)
+	for _, seg := range segments {
+		ev.DurationSeconds += seg.Duration.Seconds()
+	}
+	return ev
+}
+
+// Segments returns the segments written so far, oldest first, the last
+// incomplete while the recording runs.
+func (r *Recording) Segments() []RecordingSegment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.segments)
+}
+
+// Done is closed when the recording has ended, by Stop or with its capture.
+func (r *Recording) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err returns why the recording ended on its own, once Done is closed.
+func (r *Recording) Err() error {
+	select {
+	case <-r.done:
+		return r.err
+	default:
+		return nil
+	}
+}
+
+// updateSegment sets the last segment to seg, or adds it if it starts a new
+// file.
+func (r *Recording) updateSegment(seg RecordingSegment, started bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if started || len(r.segments) == 0 {
+		r.segments = append(r.segments, seg)
+		return
+	}
+	r.segments[len(r.segments)-1] = seg
+}
+
+// record writes chunks to segments until the capture ends.
+func (r *Recording) record(chunks <-chan *AudioChunk, name string, cfg RecordingConfig) error {
+	var seg *wavSegment
+	finish := func() error {
+		if seg == nil {
+			return nil
+		}
+		path, err := seg.close(cfg.NormalizeLUFS)
+		done := seg.status()
+		done.Path, done.Complete = path, err == nil
+		r.updateSegment(done, false)
+		seg = nil
+		if err != nil {
+			return err
+		}
+		if r.uploader != nil {
+			if err := r.uploader.Upload(path, filepath.Base(path)); err != nil {
+				r.logger.Warnw("failed to queue recording upload", "path", path, "error", err)
+			}
+		}
+		return nil
+	}
+	defer finish()
+
+	var next time.Time // where the previous chunk ended
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return chunk.Err
+		}
+		if chunk.Info == nil || chunk.Info.Channels == 0 || chunk.Info.SampleRate == 0 {
+			return errUnknownSourceFormat
+		}
+		info := *chunk.Info
+		if seg != nil && (chunk.Gap > 0 || info != seg.info) {
+			if err := finish(); err != nil {
+				return err
+			}
+		}
+		// chunks without a capture time continue from the previous one, so
+		// segments started within the same millisecond still get their own name
+		at := chunk.Timestamp
+		switch {
+		case !at.IsZero():
+		case !next.IsZero():
+			at = next.Add(chunk.Gap)
+		default:
+			at = time.Now()
+		}
+		data := chunk.AudioData
+		frameSize := 2 * info.Channels
+		for len(data) > 0 {
+			if seg == nil {
+				frames := int(math.Round(cfg.SegmentDuration.Seconds() * float64(info.SampleRate)))
+				var err error
+				if seg, err = newWAVSegment(cfg.Dir, name, at, info, max(frames, 1), cfg.Format); err != nil {
+					return err
+				}
+				r.updateSegment(seg.status(), true)
+			}
+			n := min(len(data), (seg.frames-seg.written)*frameSize)
+			if err := seg.write(data[:n]); err != nil {
+				return err
+			}
+			data = data[n:]
+			at = at.Add(time.Duration(n/frameSize) * time.Second / time.Duration(info.SampleRate))
+			r.updateSegment(seg.status(), false)
+			if seg.written == seg.frames {
+				if err := finish(); err != nil {
+					return err
+				}
+			}
+		}
+		next = at
+	}
+	return nil
+}
+
+// wavSegment is one pcm16 WAV file being recorded. Its header is rewritten
+// with the final size when it is closed, or it is encoded to flacPath and
+// removed.
+type wavSegment struct {
+	f         *os.File
+	info      AudioInfo
+	start     time.Time
+	frames    int // frames the segment holds when full
+	written   int
+	dataStart int64
+	flacPath  string
+}
+
+func newWAVSegment(dir, name string, at time.Time, info AudioInfo, frames int, format string) (*wavSegment, error) {
+	file := fmt.Sprintf("%s-%s", name, at.UTC().Format(recordingTimeLayout))
+	var flacPath string
+	if format == "flac" {
+		if info.Channels > 8 {
+			return nil, fmt.Errorf("FLAC holds at most 8 channels, the capture has %d", info.Channels)
+		}
+		flacPath = filepath.Join(dir, file+".flac")
+		// hidden from the recording store until it's encoded
+		file = "." + file
+	}
+	f, err := os.Create(filepath.Join(dir, file+".wav"))
+	if err != nil {
+		return nil, err
+	}
+	if err := writeWAVHeader(f, newWAVHeader(info.SampleRate, info.Channels, 16, streamingDataSize)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	start, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &wavSegment{f: f, info: info, start: at, frames: frames, dataStart: start, flacPath: flacPath}, nil
+}
+
+// status describes the segment as written so far.
+func (s *wavSegment) status() RecordingSegment {
+	path := s.f.Name()
+	if s.flacPath != "" {
+		path = s.flacPath
+	}
+	return RecordingSegment{
+		Path:     path,
+		Start:    s.start,
+		Duration: time.Duration(s.written) * time.Second / time.Duration(s.info.SampleRate),
+	}
+}
+
+func (s *wavSegment) write(data []byte) error {
+	if _, err := s.f.Write(data); err != nil {
+		return err
+	}
+	s.written += len(data) / (2 * s.info.Channels)
+	return nil
+}
+
+// close fixes up the header, scales the audio to targetLUFS unless it is
+// zero, and returns the path of the finished file.
+func (s *wavSegment) close(targetLUFS float64) (string, error) {
+	size := uint32(s.written * 2 * s.info.Channels)
+	var err error
+	if targetLUFS != 0 {
+		err = s.normalize(targetLUFS, int(size))
+	}
+	if s.flacPath != "" {
+		if err == nil {
+			err = s.encodeFLAC(int(size))
+		}
+		s.f.Close()
+		if err != nil {
+			// the audio is kept, under the hidden name
+			return s.f.Name(), err
+		}
+		return s.flacPath, os.Remove(s.f.Name())
+	}
+	if err == nil {
+		_, err = s.f.Seek(0, io.SeekStart)
+	}
+	if err == nil {
+		err = writeWAVHeader(s.f, newWAVHeader(s.info.SampleRate, s.info.Channels, 16, size))
+	}
+	if closeErr := s.f.Close(); err == nil {
+		err = closeErr
+	}
+	return s.f.Name(), err
+}
+
+// encodeFLAC writes the size bytes of audio in the segment to flacPath.
+func (s *wavSegment) encodeFLAC(size int) error {
+	out, err := os.Create(s.flacPath)
+	if err != nil {
+		return err
+	}
+	ch := s.info.Channels
+	enc, err := flac.NewEncoder(out, &meta.StreamInfo{
+		BlockSizeMin:  flacBlockSize,
+		BlockSizeMax:  flacBlockSize,
+		SampleRate:    uint32(s.info.SampleRate),
+		NChannels:     uint8(ch),
+		BitsPerSample: 16,
+	})
+	if err != nil {
+		out.Close()
+		os.Remove(s.flacPath)
+		return err
+	}
+	buf := make([]byte, flacBlockSize*2*ch)
+	for off := 0; off < size && err == nil; {
+		n := min(len(buf), size-off)
+		if _, err = s.f.ReadAt(buf[:n], s.dataStart+int64(off)); err != nil {
+			break
+		}
+		frames := n / (2 * ch)
+		f := &frame.Frame{
+			Header: frame.Header{
+				HasFixedBlockSize: true,
+				BlockSize:         uint16(frames),
+				SampleRate:        uint32(s.info.SampleRate),
+				Channels:          frame.Channels(ch - 1), // independent channels
+				BitsPerSample:     16,
+			},
+			Subframes: make([]*frame.Subframe, ch),
+		}
+		for c := range f.Subframes {
+			samples := make([]int32, frames)
+			for i := range samples {
+				at := 2 * (i*ch + c)
+				samples[i] = int32(int16(uint16(buf[at]) | uint16(buf[at+1])<<8))
+			}
+			f.Subframes[c] = &frame.Subframe{SubHeader: frame.SubHeader{Pred: frame.PredVerbatim}, Samples: samples, NSamples: frames}
+		}
+		err = enc.WriteFrame(f)
+		off += n
+	}
+	// closing the encoder fills in the stream info and closes out
+	if closeErr := enc.Close(); err == nil {
+		err = closeErr
+	}
+	out.Close()
+	if err != nil {
+		os.Remove(s.flacPath)
+	}
+	return err
+}
+
+// normalize rewrites the segment's audio at targetLUFS.
+func (s *wavSegment) normalize(targetLUFS float64, size int) error {
+	data := make([]byte, size)
+	if _, err := s.f.ReadAt(data, s.dataStart); err != nil {
+		return err
+	}
+	data, err := NormalizeLoudness(data, Pcm16.String(), s.info.SampleRate, s.info.Channels, targetLUFS)
+	if err != nil {
+		return err
+	}
+	_, err = s.f.WriteAt(data, s.dataStart)
+	return err
+}
+
+// finishedRecordingTTL is how long the segments of a recording that ended
+// can still be listed.
+const finishedRecordingTTL = time.Hour
+
+// RecordingOptions configures a recording started through the server. Its
+// segments go to the server's recording store.
+type RecordingOptions struct {
+	SegmentDuration time.Duration // one minute if zero
+	Format          string        // "wav" (the default) or "flac"
+	NormalizeLUFS   float64       // see RecordingConfig
+}
+
+// RecordingStatus is the state of a recording started through the server.
+type RecordingStatus struct {
+	Segments []RecordingSegment // with paths in the recording store
+	Active   bool
+	Err      string // why the recording ended on its own, if it did
+}
+
+// SegmentedRecorder is implemented by clients of servers that record a
+// resource's capture into their recording store, see StartRecording.
+type SegmentedRecorder interface {
+	// StartRecording starts a recording and returns its ID.
+	StartRecording(ctx context.Context, opts RecordingOptions) (string, error)
+	// StopRecording stops a recording and returns its segments.
+	StopRecording(ctx context.Context, id string) ([]RecordingSegment, error)
+	// ListSegments returns the state of a recording, which can be listed
+	// for an hour after it ends.
+	ListSegments(ctx context.Context, id string) (RecordingStatus, error)
+}
+
+// managedRecording is a recording the server started for a resource.
+type managedRecording struct {
+	resource string
+	rec      *Recording
+	ended    time.Time // when the server saw it had ended
+}
+
+// serverRecordings holds the recordings the server started, by ID.
+type serverRecordings struct {
+	mu         sync.Mutex
+	recordings map[string]*managedRecording
+}
+
+func newServerRecordings() *serverRecordings {
+	return &serverRecordings{recordings: map[string]*managedRecording{}}
+}
+
+// add stores rec and returns its ID, dropping recordings that ended long
+// enough ago.
+func (m *serverRecordings) add(resource string, rec *Recording) string {
+	id := strings.ToLower(rand.Text())
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, r := range m.recordings {
+		if r.ended.IsZero() {
+			select {
+			case <-r.rec.Done():
+				r.ended = now
+			default:
+			}
+		} else if now.Sub(r.ended) > finishedRecordingTTL {
+			delete(m.recordings, id)
+		}
+	}
+	m.recordings[id] = &managedRecording{resource: resource, rec: rec}
+	return id
+}
+
+// get returns the recording id of resource.
+func (m *serverRecordings) get(resource, id string) (*managedRecording, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.recordings[id]
+	if !ok || r.resource != resource {
+		return nil, fmt.Errorf("no recording %q of %s", id, resource)
+	}
+	return r, nil
+}
+
+// list returns resource's recordings by ID.
+func (m *serverRecordings) list(resource string) map[string]*Recording {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	recordings := map[string]*Recording{}
+	for id, r := range m.recordings {
+		if r.resource == resource {
+			recordings[id] = r.rec
+		}
+	}
+	return recordings
+}
+
+func (m *serverRecordings) markEnded(r *managedRecording) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r.ended.IsZero() {
+		r.ended = time.Now()
+	}
+}
+
+// stopAll stops every recording still running, so its last segment is
+// complete.
+func (m *serverRecordings) stopAll(ctx context.Context) error {
+	m.mu.Lock()
+	recordings := make([]*managedRecording, 0, len(m.recordings))
+	for _, r := range m.recordings {
+		recordings = append(recordings, r)
+	}
+	m.mu.Unlock()
+	var errs []error
+	for _, r := range recordings {
+		if err := r.rec.Stop(ctx); err != nil && r.rec.Err() == nil {
+			errs = append(errs, fmt.Errorf("recording of %s: %w", r.resource, err))
+		}
+		m.markEnded(r)
+	}
+	return errors.Join(errs...)
+}
+
+// startAuditedRecording starts a recording no call asked for, as schedules
+// and triggers do, and writes its start to the audit log, stopping it again
+// if that fails.
+func startAuditedRecording(ctx context.Context, a Audio, cfg RecordingConfig, detail string, logger logging.Logger) (*Recording, error) {
+	rec, err := StartRecording(ctx, a, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := auditOperation(ctx, logger, a.Name().ShortName(), AuditRecordingStarted, "", detail, nil); err != nil {
+		rec.Stop(ctx)
+		return nil, err
+	}
+	return rec, nil
+}
+
+// auditRecordingStopped writes the end of a recording started by
+// startAuditedRecording to the audit log.
+func auditRecordingStopped(logger logging.Logger, resource string, rec *Recording, detail string) {
+	detail = fmt.Sprintf("%s, %d segments", detail, len(rec.Segments()))
+	auditOperation(context.Background(), logger, resource, AuditRecordingStopped, "", detail, rec.Err())
+}
+
+func (s *audioServer) StartRecording(ctx context.Context, req *pb.StartRecordingRequest) (*pb.StartRecordingResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ServerRecordings.Dir == "" {
+		return nil, errNoRecordingStore
+	}
+	cfg := RecordingConfig{
+		Dir:             ServerRecordings.Dir,
+		SegmentDuration: time.Duration(req.SegmentSeconds * float64(time.Second)),
+		Format:          req.Format,
+		NormalizeLUFS:   req.NormalizeLufs,
+	}
+	if cfg.SegmentDuration < 0 {
+		return nil, errorf(ErrInvalidArgument, "segment length cannot be negative, got %gs", req.SegmentSeconds)
+	}
+	// the recording outlives the call
+	rec, err := StartRecording(context.Background(), a, cfg, serverVerbosity.sublogger("recording"))
+	if err != nil {
+		return nil, err
+	}
+	id := s.recordings.add(req.Name, rec)
+	if err := s.audit(ctx, req.Name, AuditRecordingStarted, id, req.Format, nil); err != nil {
+		// nothing is recorded without a record of who asked for it
+		rec.Stop(ctx)
+		if r, gerr := s.recordings.get(req.Name, id); gerr == nil {
+			s.recordings.markEnded(r)
+		}
+		return nil, err
+	}
+	s.logger.Infow("recording started", "resource", req.Name, "recording_id", id)
+	return &pb.StartRecordingResponse{RecordingId: id}, nil
+}
+
+func (s *audioServer) StopRecording(ctx context.Context, req *pb.StopRecordingRequest) (*pb.StopRecordingResponse, error) {
+	r, err := s.recordings.get(req.Name, req.RecordingId)
+	if err != nil {
+		return nil, err
+	}
+	// an error that ended the recording earlier is reported by ListSegments
+	if err := r.rec.Stop(ctx); err != nil && r.rec.Err() == nil {
+		return nil, err
+	}
+	s.recordings.markEnded(r)
+	s.audit(ctx, req.Name, AuditRecordingStopped, req.RecordingId, fmt.Sprintf("%d segments", len(r.rec.Segments())), r.rec.Err())
+	s.logger.Infow("recording stopped", "resource", req.Name, "recording_id", req.RecordingId)
+	return &pb.StopRecordingResponse{Segments: segmentsToProto(r.rec.Segments())}, nil
+}
+
+func (s *audioServer) ListSegments(ctx context.Context, req *pb.ListSegmentsRequest) (*pb.ListSegmentsResponse, error) {
+	r, err := s.recordings.get(req.Name, req.RecordingId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListSegmentsResponse{Segments: segmentsToProto(r.rec.Segments())}
+	select {
+	case <-r.rec.Done():
+		if err := r.rec.Err(); err != nil {
+			resp.Error = err.Error()
+		}
+	default:
+		resp.Active = true
+	}
+	return resp, nil
+}
+
+func segmentsToProto(segments []RecordingSegment) []*pb.RecordingSegment {
+	out := make([]*pb.RecordingSegment, len(segments))
+	for i, seg := range segments {
+		out[i] = &pb.RecordingSegment{
+			File:                      filepath.Base(seg.Path),
+			StartTimestampNanoseconds: seg.Start.UnixNano(),
+			DurationNanoseconds:       int64(seg.Duration),
+			Complete:                  seg.Complete,
+		}
+	}
+	return out
+}
+
+func segmentsFromProto(segments []*pb.RecordingSegment) []RecordingSegment {
+	out := make([]RecordingSegment, len(segments))
+	for i, seg := range segments {
+		out[i] = RecordingSegment{
+			Path:     seg.File,
+			Start:    time.Unix(0, seg.StartTimestampNanoseconds),
+			Duration: time.Duration(seg.DurationNanoseconds),
+			Complete: seg.Complete,
+		}
+	}
+	return out
+}
+
+func (c *audioClient) StartRecording(ctx context.Context, opts RecordingOptions) (string, error) {
+	resp, err := c.client.StartRecording(ctx, &pb.StartRecordingRequest{
+		Name:           c.name,
+		SegmentSeconds: opts.SegmentDuration.Seconds(),
+		Format:         opts.Format,
+		NormalizeLufs:  opts.NormalizeLUFS,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.RecordingId, nil
+}
+
+func (c *audioClient) StopRecording(ctx context.Context, id string) ([]RecordingSegment, error) {
+	resp, err := c.client.StopRecording(ctx, &pb.StopRecordingRequest{Name: c.name, RecordingId: id})
+	if err != nil {
+		return nil, err
+	}
+	return segmentsFromProto(resp.Segments), nil
+}
+
+func (c *audioClient) ListSegments(ctx context.Context, id string) (RecordingStatus, error) {
+	resp, err := c.client.ListSegments(ctx, &pb.ListSegmentsRequest{Name: c.name, RecordingId: id})
+	if err != nil {
+		return RecordingStatus{}, err
+	}
+	return RecordingStatus{Segments: segmentsFromProto(resp.Segments), Active: resp.Active, Err: resp.Error}, nil
+}
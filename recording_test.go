@@ -0,0 +1,215 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestRecordingUploadsSegments(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		objects = map[string][]byte{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("unexpected %s request with authorization %q", r.Method, r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		objects[r.URL.Path] = body
+		mu.Unlock()
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	src := newBurstSource(30, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rec, err := StartRecording(ctx, src, RecordingConfig{
+		Dir:             t.TempDir(),
+		SegmentDuration: 100 * time.Millisecond,
+		ObjectStore: &ObjectStoreConfig{
+			Provider: ObjectStoreS3, Bucket: "recordings", Endpoint: srv.URL,
+			AccessKeyID: "AKID", SecretAccessKey: "secret",
+		},
+	}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+	// the burst ends the capture after 300ms of audio
+	for {
+		mu.Lock()
+		n := len(objects)
+		mu.Unlock()
+		if n == 3 || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := rec.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(objects) != 3 {
+		t.Fatalf("uploaded %d segments, want 3", len(objects))
+	}
+	for key, body := range objects {
+		if !strings.HasPrefix(key, "/recordings/burst-") || !strings.HasSuffix(key, ".wav") {
+			t.Errorf("unexpected object key %q", key)
+		}
+		if len(body) != 44+1600 {
+			t.Fatalf("%s is %d bytes, want a 44 byte header and 100ms of audio", key, len(body))
+		}
+		if size := binary.LittleEndian.Uint32(body[40:44]); size != 1600 {
+			t.Errorf("%s header says %d bytes of data, want 1600", key, size)
+		}
+	}
+}
+
+func TestRecordingSplitsOnGaps(t *testing.T) {
+	dir := t.TempDir()
+	r := &Recording{logger: logging.NewTestLogger(t)}
+	info := AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}
+	chunks := make(chan *AudioChunk, 3)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	chunks <- &AudioChunk{AudioData: make([]byte, 160), Info: &info, Timestamp: start}
+	chunks <- &AudioChunk{AudioData: make([]byte, 160), Info: &info, Timestamp: start.Add(10 * time.Millisecond)}
+	chunks <- &AudioChunk{AudioData: make([]byte, 160), Info: &info, Timestamp: start.Add(time.Second), Gap: 980 * time.Millisecond}
+	close(chunks)
+	if err := r.record(chunks, "mic", RecordingConfig{Dir: dir, SegmentDuration: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]int64{
+		"mic-20240101T000000.000Z.wav": 44 + 320,
+		"mic-20240101T000001.000Z.wav": 44 + 160,
+	} {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Size() != want {
+			t.Errorf("%s is %d bytes, want %d", name, fi.Size(), want)
+		}
+	}
+}
+
+func TestRecordingFLACSegments(t *testing.T) {
+	dir := t.TempDir()
+	r := &Recording{logger: logging.NewTestLogger(t)}
+	info := AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 2}
+	samples := remix(tone(8000, 1200, 440, 0.5), 1, 2)
+	data, _ := encodePCM(samples, Pcm16)
+	chunks := make(chan *AudioChunk, 1)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	chunks <- &AudioChunk{AudioData: data, Info: &info, Timestamp: start}
+	close(chunks)
+	if err := r.record(chunks, "mic", RecordingConfig{Dir: dir, SegmentDuration: 100 * time.Millisecond, Format: "flac"}); err != nil {
+		t.Fatal(err)
+	}
+
+	segments := r.Segments()
+	if len(segments) != 2 {
+		t.Fatalf("wrote %d segments, want 2", len(segments))
+	}
+	for i, want := range []time.Duration{100 * time.Millisecond, 50 * time.Millisecond} {
+		if !segments[i].Complete || segments[i].Duration != want {
+			t.Errorf("segment %d is %+v, want %v complete", i, segments[i], want)
+		}
+	}
+	if want := filepath.Join(dir, "mic-20240101T000000.100Z.flac"); segments[1].Path != want || !segments[1].Start.Equal(start.Add(100*time.Millisecond)) {
+		t.Errorf("second segment is %+v, want %s", segments[1], want)
+	}
+	// the hidden WAVs are gone, and the FLACs hold the audio as it was
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("left %d files, want the 2 segments", len(entries))
+	}
+	var got []float32
+	for _, seg := range segments {
+		decoded, fileInfo, err := readAudioFile(seg.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fileInfo.SampleRate != 8000 || fileInfo.Channels != 2 {
+			t.Errorf("%s is %+v", seg.Path, fileInfo)
+		}
+		got = append(got, decoded...)
+	}
+	if len(got) != len(samples) {
+		t.Fatalf("decoded %d samples, want %d", len(got), len(samples))
+	}
+	want, _ := decodePCM(data, Pcm16)
+	for i := range want {
+		if d := got[i] - want[i]; d > 1e-4 || d < -1e-4 {
+			t.Fatalf("sample %d is %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestServerRecording(t *testing.T) {
+	dir := t.TempDir()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = dir
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	src := newBurstSource(25, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	c := serveAudio(t, src).(SegmentedRecorder)
+	if _, err := c.StartRecording(ctx, RecordingOptions{Format: "ogg"}); err == nil {
+		t.Error("started an ogg recording")
+	}
+	id, err := c.StartRecording(ctx, RecordingOptions{SegmentDuration: 100 * time.Millisecond, Format: "flac"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := c.ListSegments(ctx, id)
+	if err != nil || !status.Active || len(status.Segments) != 0 {
+		t.Fatalf("before any audio: %+v, %v", status, err)
+	}
+	close(src.start)
+	// the burst ends the capture after 250ms of audio
+	for status.Active && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+		if status, err = c.ListSegments(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(status.Segments) != 3 || status.Err != "" {
+		t.Fatalf("after the capture ended: %+v", status)
+	}
+	segments, err := c.StopRecording(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, seg := range segments {
+		if !seg.Complete || !strings.HasPrefix(seg.Path, "burst-") || filepath.Ext(seg.Path) != ".flac" {
+			t.Errorf("segment %d is %+v", i, seg)
+		}
+	}
+	if segments[2].Duration != 50*time.Millisecond {
+		t.Errorf("the last segment is %v long, want 50ms", segments[2].Duration)
+	}
+	recordings, _, err := serveAudio(t, src).(RecordingLister).ListRecordings(ctx, 0, "", RecordingFilter{Format: "flac"})
+	if err != nil || len(recordings) != 3 {
+		t.Errorf("the store lists %v: %v", recordings, err)
+	}
+	if _, err := c.ListSegments(ctx, "unknown"); err == nil {
+		t.Error("listed an unknown recording")
+	}
+}
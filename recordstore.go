@@ -0,0 +1,176 @@
+package audio
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// savedStreamExt is the extension of streams saved with WithAlsoSaveAs.
+const savedStreamExt = ".chunks"
+
+// RecordingStore is the directory the server keeps the recordings it makes
+// itself in.
+type RecordingStore struct {
+	Dir string
+	// Retention is what Prune deletes, nothing if it's zero.
+	Retention RetentionPolicy
+
+	mu          sync.Mutex
+	pruned      int // recordings deleted by Prune
+	prunedBytes int64
+	described   map[string]StoredRecording // by ID, see describe
+}
+
+// ServerRecordings is the store the RPC server saves into. Its directory
+// comes from AUDIO_RECORDING_DIR, and saving is refused while it's empty.
+// AUDIO_RECORDING_MAX_AGE (e.g. 720h) and AUDIO_RECORDING_MAX_BYTES set its
+// retention, which the server enforces in the background.
+var ServerRecordings = &RecordingStore{Dir: os.Getenv("AUDIO_RECORDING_DIR"), Retention: retentionFromEnv()}
+
+var errNoRecordingStore = errorf(ErrUnsupported, "the server has no recording store, set AUDIO_RECORDING_DIR")
+
+// recordingFormats are the formats of the store's recordings, by extension.
+var recordingFormats = map[string]string{".wav": "wav", ".flac": "flac", savedStreamExt: "chunks"}
+
+// StoredRecording is a recording in the server's store.
+type StoredRecording struct {
+	Name     string // as saved, without the extension
+	Format   string // "wav", "flac" or "chunks"
+	Size     int64
+	Modified time.Time
+	// What the file says about its audio, set by ListRecordings and
+	// GetRecording. Codec is "pcm8", "pcm16", "pcm24", "pcm32" or
+	// "pcm32_float" for wav, "flac", or the codec a stream was saved in.
+	Codec      string
+	SampleRate int
+	Channels   int
+	Duration   time.Duration
+}
+
+// create opens a new recording called name, refusing names that would leave
+// the store or replace a recording already in it.
+func (s *RecordingStore) create(name, ext string) (*os.File, error) {
+	if s.Dir == "" {
+		return nil, errNoRecordingStore
+	}
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, errorf(ErrInvalidArgument, "invalid recording name %q", name)
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(s.Dir, name+ext), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("recording %q already exists", name)
+	}
+	return f, err
+}
+
+// list returns the recordings in the store by name, then format.
+func (s *RecordingStore) list() ([]StoredRecording, error) {
+	if s.Dir == "" {
+		return nil, errNoRecordingStore
+	}
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		// nothing has been saved yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var recordings []StoredRecording
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		format, ok := recordingFormats[ext]
+		if !ok || e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			// removed since the directory was read
+			continue
+		}
+		recordings = append(recordings, StoredRecording{
+			Name:     strings.TrimSuffix(e.Name(), ext),
+			Format:   format,
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+	slices.SortFunc(recordings, func(a, b StoredRecording) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Format, b.Format))
+	})
+	s.forget(recordings)
+	return recordings, nil
+}
+
+// saveWAV saves interleaved samples as a 16-bit WAV called name.wav.
+func (s *RecordingStore) saveWAV(name string, samples []float32, rate, channels int) error {
+	data, err := encodePCM(samples, Pcm16)
+	if err != nil {
+		return err
+	}
+	f, err := s.create(name, ".wav")
+	if err != nil {
+		return err
+	}
+	if err := writeWAVHeader(f, newWAVHeader(rate, channels, 16, uint32(len(data)))); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// savedStream is a GetAudio stream being saved as the length-delimited
+// AudioChunk messages sent to the client.
+type savedStream struct {
+	*os.File
+}
+
+func (s *RecordingStore) save(name string) (*savedStream, error) {
+	f, err := s.create(name, savedStreamExt)
+	if err != nil {
+		return nil, err
+	}
+	return &savedStream{f}, nil
+}
+
+func (s *savedStream) write(chunk *pb.AudioChunk) error {
+	_, err := protodelim.MarshalTo(s.File, chunk)
+	return err
+}
+
+// ReadSavedStream reads back a stream saved with WithAlsoSaveAs, chunk for
+// chunk as it was delivered.
+func ReadSavedStream(r io.Reader) ([]*AudioChunk, error) {
+	br := bufio.NewReader(r)
+	var chunks []*AudioChunk
+	for {
+		var chunk pb.AudioChunk
+		if err := protodelim.UnmarshalFrom(br, &chunk); err != nil {
+			if errors.Is(err, io.EOF) {
+				return chunks, nil
+			}
+			return chunks, err
+		}
+		chunks = append(chunks, chunkFromProto(&chunk))
+	}
+}
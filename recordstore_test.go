@@ -0,0 +1,98 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// serveAudio serves res over gRPC on a local port and returns a client for it.
+func serveAudio(t *testing.T, res Audio, opts ...ClientOption) Audio {
+	t.Helper()
+	coll, err := resource.NewAPIResourceCollection[Audio](API, map[resource.Name]Audio{res.Name(): res})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer()
+	pb.RegisterAudioServiceServer(gs, NewRPCServiceServer(coll).(pb.AudioServiceServer))
+	go gs.Serve(lis)
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		gs.Stop()
+	})
+	return NewClientFromConn(&rpc.GrpcOverHTTPClientConn{ClientConn: conn}, "", res.Name(), logging.NewTestLogger(t), opts...)
+}
+
+func TestAlsoSaveAs(t *testing.T) {
+	dir := t.TempDir()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = dir
+
+	src := newBurstSource(20, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 { return tone(8000, 80, 500+float64(i)*10, 0.5) }
+	c := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch, err := c.GetAudio(ctx, Pcm32Float.String(), 0, 0, 0, WithAlsoSaveAs("qa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+	var got []*AudioChunk
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		got = append(got, chunk)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "qa"+savedStreamExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := ReadSavedStream(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != len(got) || len(got) == 0 {
+		t.Fatalf("saved %d chunks, client got %d", len(saved), len(got))
+	}
+	for i := range got {
+		if !bytes.Equal(saved[i].AudioData, got[i].AudioData) || saved[i].Gap != got[i].Gap ||
+			*saved[i].Info != *got[i].Info || (saved[i].Header == nil) != (got[i].Header == nil) {
+			t.Fatalf("chunk %d saved as %+v, delivered as %+v", i, saved[i], got[i])
+		}
+	}
+
+	for _, name := range []string{"qa", "../qa", ".hidden"} {
+		ch, err := c.GetAudio(ctx, Pcm16.String(), 0, 0, 0, WithAlsoSaveAs(name))
+		if err == nil {
+			chunk := <-ch
+			err = chunk.Err
+		}
+		if err == nil {
+			t.Fatalf("saving as %q was accepted", name)
+		}
+	}
+}
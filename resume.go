@@ -0,0 +1,267 @@
+package audio
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// resumeWindow is how long a resumable stream is kept after its client
+// drops, and how much of its capture is buffered to be replayed.
+const resumeWindow = 30 * time.Second
+
+// resumeIDSize is the size of the random stream id in a resume token.
+const resumeIDSize = 16
+
+var (
+	errResumeExpired = status.Error(codes.FailedPrecondition, "the resume token has expired, start a new stream")
+	errResumeInvalid = status.Error(codes.InvalidArgument, "invalid resume token")
+	// errResumeBehind ends a resumed stream whose client reads more slowly
+	// than the buffer lets it.
+	errResumeBehind = status.Error(codes.ResourceExhausted, "the client fell more than the resume window behind the capture")
+)
+
+// resumeRegistry keeps the server's resumable streams by id.
+type resumeRegistry struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	streams map[string]*resumableStream
+}
+
+func newResumeRegistry() *resumeRegistry {
+	return &resumeRegistry{window: resumeWindow, streams: map[string]*resumableStream{}}
+}
+
+// resumableStream owns the capture of a resumable GetAudio stream, so it
+// outlives the RPCs that deliver it, and buffers the last window of chunks
+// by capture order.
+type resumableStream struct {
+	id     string
+	req    *pb.GetAudioRequest // as the stream was started
+	window time.Duration
+	cancel context.CancelFunc // stops the capture
+	remove func()
+
+	mu       sync.Mutex
+	chunks   []*AudioChunk // consecutive sequences, oldest first
+	added    []time.Time   // when each of chunks was captured
+	next     int64         // sequence of the next chunk captured
+	ended    bool          // the capture has closed
+	expired  bool
+	wake     chan struct{} // closed when a chunk is added or the capture ends
+	attached int           // counts attachments, so only the latest detaches
+	detach   context.CancelFunc
+	idle     *time.Timer
+}
+
+// start opens a resumable stream for req with open, which is given a
+// context that lasts as long as the stream.
+func (r *resumeRegistry) start(req *pb.GetAudioRequest, open func(ctx context.Context) (<-chan *AudioChunk, error)) (*resumableStream, error) {
+	id := make([]byte, resumeIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	src, err := open(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	rs := &resumableStream{id: string(id), req: req, window: r.window, cancel: cancel, wake: make(chan struct{})}
+	rs.remove = func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.streams, rs.id)
+	}
+	r.mu.Lock()
+	r.streams[rs.id] = rs
+	r.mu.Unlock()
+	go rs.capture(src)
+	return rs, nil
+}
+
+// list describes resource's resumable streams, for list_sessions.
+func (r *resumeRegistry) list(resource string) []map[string]interface{} {
+	r.mu.Lock()
+	streams := make([]*resumableStream, 0, len(r.streams))
+	for _, st := range r.streams {
+		if st.req.Name == resource {
+			streams = append(streams, st)
+		}
+	}
+	r.mu.Unlock()
+	list := []map[string]interface{}{}
+	for _, st := range streams {
+		st.mu.Lock()
+		list = append(list, map[string]interface{}{
+			"id":              st.id,
+			"request_id":      st.req.RequestId,
+			"codec":           st.req.Codec,
+			"buffered_chunks": len(st.chunks),
+			"next_sequence":   st.next,
+			"ended":           st.ended,
+			"attached":        st.detach != nil,
+		})
+		st.mu.Unlock()
+	}
+	return list
+}
+
+// resume finds the stream token was issued by, and the sequence of the
+// chunk it was issued with.
+func (r *resumeRegistry) resume(resource, token string) (*resumableStream, int64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != resumeIDSize+8 {
+		return nil, 0, errResumeInvalid
+	}
+	r.mu.Lock()
+	rs, ok := r.streams[string(b[:resumeIDSize])]
+	r.mu.Unlock()
+	if !ok {
+		return nil, 0, errResumeExpired
+	}
+	if rs.req.Name != resource {
+		return nil, 0, errResumeInvalid
+	}
+	return rs, int64(binary.BigEndian.Uint64(b[resumeIDSize:])), nil
+}
+
+// token returns the token resuming the stream after the chunk with sequence
+// seq.
+func (rs *resumableStream) token(seq int64) string {
+	b := binary.BigEndian.AppendUint64([]byte(rs.id), uint64(seq))
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (rs *resumableStream) capture(src <-chan *AudioChunk) {
+	for chunk := range src {
+		// buffered chunks may be replayed, so their data is never recycled
+		c := *chunk
+		c.pooled = false
+		now := time.Now()
+		rs.mu.Lock()
+		c.Sequence = rs.next
+		rs.next++
+		rs.chunks = append(rs.chunks, &c)
+		rs.added = append(rs.added, now)
+		drop := 0
+		for drop < len(rs.added) && now.Sub(rs.added[drop]) > rs.window {
+			drop++
+		}
+		rs.chunks, rs.added = rs.chunks[drop:], rs.added[drop:]
+		rs.signal()
+		rs.mu.Unlock()
+	}
+	rs.mu.Lock()
+	rs.ended = true
+	rs.signal()
+	rs.mu.Unlock()
+}
+
+// signal wakes the attached reader; rs.mu must be held.
+func (rs *resumableStream) signal() {
+	close(rs.wake)
+	rs.wake = make(chan struct{})
+}
+
+// attach returns the chunks following sequence after, -1 for the whole
+// stream, until ctx is done or the capture ends. A client that attaches
+// again takes over from the previous one, whose server may not have noticed
+// it dropped yet.
+func (rs *resumableStream) attach(ctx context.Context, after int64) (<-chan *AudioChunk, error) {
+	rs.mu.Lock()
+	first := rs.next - int64(len(rs.chunks))
+	if rs.expired || after+1 < first {
+		rs.mu.Unlock()
+		return nil, errResumeExpired
+	}
+	if after >= rs.next {
+		rs.mu.Unlock()
+		return nil, errResumeInvalid
+	}
+	if rs.detach != nil {
+		rs.detach()
+	}
+	if rs.idle != nil {
+		rs.idle.Stop()
+		rs.idle = nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	rs.attached++
+	rs.detach = cancel
+	attachment := rs.attached
+	rs.mu.Unlock()
+
+	out := make(chan *AudioChunk)
+	go func() {
+		defer close(out)
+		defer rs.detached(attachment)
+		next := after + 1
+		for {
+			rs.mu.Lock()
+			first := rs.next - int64(len(rs.chunks))
+			var chunk *AudioChunk
+			if next >= first && next < rs.next {
+				chunk = rs.chunks[next-first]
+			}
+			ended, wake := rs.ended, rs.wake
+			rs.mu.Unlock()
+			switch {
+			case next < first:
+				chunk = &AudioChunk{Err: errResumeBehind}
+			case chunk == nil && ended:
+				return
+			case chunk == nil:
+				select {
+				case <-wake:
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case out <- chunk:
+				if chunk.Err != nil {
+					return
+				}
+				next++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// detached starts the window in which the stream can be resumed, unless it
+// was attached again since.
+func (rs *resumableStream) detached(attachment int) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if attachment != rs.attached {
+		return
+	}
+	rs.detach()
+	rs.detach = nil
+	rs.idle = time.AfterFunc(rs.window, func() {
+		rs.mu.Lock()
+		if rs.detach != nil || rs.expired {
+			rs.mu.Unlock()
+			return
+		}
+		rs.expired = true
+		rs.chunks, rs.added = nil, nil
+		rs.mu.Unlock()
+		rs.remove()
+		rs.cancel()
+	})
+}
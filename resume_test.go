@@ -0,0 +1,118 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+func TestResumeStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(20, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 { return tone(8000, 80, 300+float64(i)*50, 0.5) }
+	var want []byte
+	for i := range src.n {
+		data, _ := encodePCM(src.samples(i), Pcm16)
+		want = append(want, data...)
+	}
+	c := serveAudio(t, src)
+	close(src.start)
+
+	// the first stream drops after a few chunks
+	streamCtx, drop := context.WithCancel(ctx)
+	ch, err := c.GetAudio(streamCtx, "pcm16", 0, 0, 0, WithResumable())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []byte
+	var token string
+	for range 5 {
+		chunk := <-ch
+		if chunk == nil || chunk.Err != nil || chunk.ResumeToken == "" {
+			t.Fatalf("got %+v, want a chunk with a resume token", chunk)
+		}
+		got = append(got, chunk.AudioData...)
+		token = chunk.ResumeToken
+	}
+	drop()
+
+	// the capture went on meanwhile, and resuming delivers the rest of it
+	ch, err = c.GetAudio(ctx, "", 0, 0, 0, WithResumeToken(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		got = append(got, chunk.AudioData...)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("resumed stream has %d bytes, want the %d captured without loss or repeats", len(got), len(want))
+	}
+
+	if ch, err = c.GetAudio(ctx, "", 0, 0, 0, WithResumeToken("nonsense")); err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || chunk.Err == nil {
+		t.Error("resumed from an invalid token")
+	}
+}
+
+func TestResumeExpiry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	r := newResumeRegistry()
+	r.window = 100 * time.Millisecond
+	src := make(chan *AudioChunk, 1)
+	stopped := make(chan struct{})
+	rs, err := r.start(&pb.GetAudioRequest{Name: "mic", Resumable: true}, func(ctx context.Context) (<-chan *AudioChunk, error) {
+		go func() {
+			<-ctx.Done()
+			close(stopped)
+		}()
+		return src, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachCtx, detach := context.WithCancel(ctx)
+	ch, err := rs.attach(attachCtx, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src <- &AudioChunk{AudioData: []byte{1, 2}}
+	chunk := <-ch
+	token := rs.token(chunk.Sequence)
+	if _, _, err := r.resume("other", token); err == nil {
+		t.Error("resumed the stream of another resource")
+	}
+	detach()
+
+	// within the window the stream waits for its client
+	resumed, after, err := r.resume("mic", token)
+	if err != nil || resumed != rs || after != chunk.Sequence {
+		t.Fatalf("resumed %v after %d: %v", resumed, after, err)
+	}
+	select {
+	case <-stopped:
+		t.Fatal("the capture stopped before the window passed")
+	default:
+	}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		t.Fatal("the capture went on after the window")
+	}
+	if _, err := rs.attach(ctx, after); !errors.Is(err, errResumeExpired) {
+		t.Errorf("attached after the window: %v", err)
+	}
+	if _, _, err := r.resume("mic", token); !errors.Is(err, errResumeExpired) {
+		t.Errorf("resumed after the window: %v", err)
+	}
+}
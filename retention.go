@@ -0,0 +1,246 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+const (
+	// retentionGrace is how long after its last write a recording is kept
+	// whatever the policy, so segments still being recorded aren't deleted
+	// from under their recorder.
+	retentionGrace = time.Minute
+	// janitorInterval is how often the server prunes its recording store.
+	janitorInterval = time.Minute
+)
+
+// RetentionPolicy bounds what a recording store keeps, so a robot left
+// recording doesn't fill its disk.
+type RetentionPolicy struct {
+	MaxAge time.Duration // recordings last modified longer ago are deleted, none if zero
+	// MaxBytes is the size the store's recordings are kept to by deleting
+	// the oldest, no limit if zero.
+	MaxBytes int64
+}
+
+func (p RetentionPolicy) enabled() bool { return p.MaxAge > 0 || p.MaxBytes > 0 }
+
+// retentionFromEnv reads the server's retention policy, ignoring values that
+// don't parse.
+func retentionFromEnv() RetentionPolicy {
+	var p RetentionPolicy
+	if v := os.Getenv("AUDIO_RECORDING_MAX_AGE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			serverLogger.Warnw("ignoring AUDIO_RECORDING_MAX_AGE, want a duration such as 720h", "value", v)
+		} else {
+			p.MaxAge = d
+		}
+	}
+	if v := os.Getenv("AUDIO_RECORDING_MAX_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			serverLogger.Warnw("ignoring AUDIO_RECORDING_MAX_BYTES, want a number of bytes", "value", v)
+		} else {
+			p.MaxBytes = n
+		}
+	}
+	return p
+}
+
+// Prune deletes the recordings the store's retention policy no longer allows
+// at now: those older than MaxAge, then the oldest until the rest fit in
+// MaxBytes. It returns those deleted, oldest first.
+func (s *RecordingStore) Prune(now time.Time) ([]StoredRecording, error) {
+	if !s.Retention.enabled() {
+		return nil, nil
+	}
+	recordings, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(recordings, func(a, b StoredRecording) int { return a.Modified.Compare(b.Modified) })
+	var total int64
+	for _, r := range recordings {
+		total += r.Size
+	}
+	var deleted []StoredRecording
+	var freed int64
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.pruned += len(deleted)
+		s.prunedBytes += freed
+	}()
+	for _, r := range recordings {
+		age := now.Sub(r.Modified)
+		expired := s.Retention.MaxAge > 0 && age > s.Retention.MaxAge
+		over := s.Retention.MaxBytes > 0 && total > s.Retention.MaxBytes
+		// the rest are newer, so they are kept too
+		if age < retentionGrace || (!expired && !over) {
+			break
+		}
+		err := os.Remove(filepath.Join(s.Dir, r.Name+recordingExt(r.Format)))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return deleted, err
+		}
+		total -= r.Size
+		if err == nil {
+			deleted = append(deleted, r)
+			freed += r.Size
+		}
+	}
+	return deleted, nil
+}
+
+// recordingExt is the extension of recordings in format.
+func recordingExt(format string) string {
+	for ext, f := range recordingFormats {
+		if f == format {
+			return ext
+		}
+	}
+	return ""
+}
+
+// RunJanitor prunes the store every interval on clock until ctx is done,
+// logging what it deletes.
+func (s *RecordingStore) RunJanitor(ctx context.Context, clock ClockSource, every time.Duration, logger logging.Logger) {
+	for {
+		deleted, err := s.Prune(clock.Now())
+		for _, r := range deleted {
+			logger.Infow("deleted recording by retention policy", "recording", r.Name, "format", r.Format, "bytes", r.Size)
+		}
+		if err != nil && !errors.Is(err, errNoRecordingStore) {
+			logger.Warnw("pruning the recording store", "error", err)
+		}
+		if clock.Sleep(ctx, every) != nil {
+			return
+		}
+	}
+}
+
+var serverJanitor sync.Once
+
+// startServerJanitor prunes ServerRecordings in the background for the life
+// of the process, if it has a retention policy.
+func startServerJanitor() {
+	serverJanitor.Do(func() {
+		if ServerRecordings.Retention.enabled() {
+			go ServerRecordings.RunJanitor(context.Background(), SystemClock, janitorInterval, serverVerbosity.sublogger("recordings"))
+		}
+	})
+}
+
+// RecordingStats describe a recording store and the disk it is on.
+type RecordingStats struct {
+	Recordings int
+	Bytes      int64     // of the recordings
+	Oldest     time.Time // last modified, zero without recordings
+	Newest     time.Time
+	// DiskFree and DiskSize are of the filesystem holding the store, zero if
+	// they are unknown.
+	DiskFree  int64
+	DiskSize  int64
+	Retention RetentionPolicy
+	// Pruned and PrunedBytes count what the retention policy has deleted
+	// since the store was opened.
+	Pruned      int
+	PrunedBytes int64
+}
+
+// diskUsage returns the bytes free to unprivileged users and the size of the
+// filesystem holding dir, set by builds that can find them.
+var diskUsage func(dir string) (free, size int64, err error)
+
+// Stats describes the store.
+func (s *RecordingStore) Stats() (RecordingStats, error) {
+	recordings, err := s.list()
+	if err != nil {
+		return RecordingStats{}, err
+	}
+	stats := RecordingStats{Recordings: len(recordings), Retention: s.Retention}
+	for _, r := range recordings {
+		stats.Bytes += r.Size
+		if stats.Oldest.IsZero() || r.Modified.Before(stats.Oldest) {
+			stats.Oldest = r.Modified
+		}
+		if r.Modified.After(stats.Newest) {
+			stats.Newest = r.Modified
+		}
+	}
+	if diskUsage != nil {
+		// the store is made on the first save, so until then it's the
+		// filesystem of the nearest directory that exists
+		dir := s.Dir
+		for {
+			if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+				break
+			}
+			dir = filepath.Dir(dir)
+		}
+		if free, size, err := diskUsage(dir); err == nil {
+			stats.DiskFree, stats.DiskSize = free, size
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats.Pruned, stats.PrunedBytes = s.pruned, s.prunedBytes
+	return stats, nil
+}
+
+// RecordingStatsGetter is implemented by clients of servers that describe
+// their recording store.
+type RecordingStatsGetter interface {
+	GetRecordingStats(ctx context.Context) (RecordingStats, error)
+}
+
+func (s *audioServer) GetRecordingStats(ctx context.Context, req *pb.GetRecordingStatsRequest) (*pb.GetRecordingStatsResponse, error) {
+	stats, err := ServerRecordings.Stats()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetRecordingStatsResponse{
+		Recordings:        int32(stats.Recordings),
+		TotalBytes:        stats.Bytes,
+		OldestNanoseconds: toUnixNano(stats.Oldest),
+		NewestNanoseconds: toUnixNano(stats.Newest),
+		DiskFreeBytes:     stats.DiskFree,
+		DiskSizeBytes:     stats.DiskSize,
+		MaxAgeSeconds:     stats.Retention.MaxAge.Seconds(),
+		MaxBytes:          stats.Retention.MaxBytes,
+		PrunedRecordings:  int32(stats.Pruned),
+		PrunedBytes:       stats.PrunedBytes,
+	}, nil
+}
+
+func (c *audioClient) GetRecordingStats(ctx context.Context) (RecordingStats, error) {
+	resp, err := c.client.GetRecordingStats(ctx, &pb.GetRecordingStatsRequest{Name: c.name})
+	if err != nil {
+		return RecordingStats{}, err
+	}
+	return RecordingStats{
+		Recordings: int(resp.Recordings),
+		Bytes:      resp.TotalBytes,
+		Oldest:     fromUnixNano(resp.OldestNanoseconds),
+		Newest:     fromUnixNano(resp.NewestNanoseconds),
+		DiskFree:   resp.DiskFreeBytes,
+		DiskSize:   resp.DiskSizeBytes,
+		Retention: RetentionPolicy{
+			MaxAge:   time.Duration(resp.MaxAgeSeconds * float64(time.Second)),
+			MaxBytes: resp.MaxBytes,
+		},
+		Pruned:      int(resp.PrunedRecordings),
+		PrunedBytes: resp.PrunedBytes,
+	}, nil
+}
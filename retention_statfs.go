@@ -0,0 +1,17 @@
+//go:build linux || darwin
+
+package audio
+
+import "syscall"
+
+func init() {
+	diskUsage = statfsUsage
+}
+
+func statfsUsage(dir string) (free, size int64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, 0, err
+	}
+	return int64(st.Bavail) * int64(st.Bsize), int64(st.Blocks) * int64(st.Bsize), nil
+}
@@ -0,0 +1,119 @@
+package audio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// storeWith returns a store holding a recording of each size, a minute
+// apart and the last modified at now.
+func storeWith(t *testing.T, now time.Time, sizes ...int) *RecordingStore {
+	t.Helper()
+	s := &RecordingStore{Dir: t.TempDir()}
+	for i, size := range sizes {
+		path := filepath.Join(s.Dir, string(rune('a'+i))+".wav")
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		at := now.Add(time.Duration(i-len(sizes)+1) * time.Minute)
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	s := storeWith(t, now, 100, 100, 100, 100, 100)
+	if deleted, err := s.Prune(now); err != nil || len(deleted) != 0 {
+		t.Fatalf("without a policy deleted %v: %v", deleted, err)
+	}
+
+	s.Retention = RetentionPolicy{MaxAge: 150 * time.Second}
+	deleted, err := s.Prune(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || deleted[0].Name != "a" || deleted[1].Name != "b" {
+		t.Errorf("deleted %+v, want a and b", deleted)
+	}
+
+	// the newest is still being written, so it's kept over the limit
+	s.Retention = RetentionPolicy{MaxBytes: 50}
+	if deleted, err = s.Prune(now); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || deleted[1].Name != "d" {
+		t.Errorf("deleted %+v, want c and d", deleted)
+	}
+	recordings, err := s.list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recordings) != 1 || recordings[0].Name != "e" {
+		t.Errorf("kept %+v, want e", recordings)
+	}
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Recordings != 1 || stats.Bytes != 100 || stats.Pruned != 4 || stats.PrunedBytes != 400 {
+		t.Errorf("stats %+v", stats)
+	}
+}
+
+func TestRecordingJanitor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	now := time.Now()
+	s := storeWith(t, now, 100, 100, 100)
+	s.Retention = RetentionPolicy{MaxAge: time.Hour}
+	clock := NewManualClock(now)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.RunJanitor(ctx, clock, time.Minute, logging.NewTestLogger(t))
+	}()
+	waitForSleeper(t, clock, now.Add(time.Minute))
+	if stats, _ := s.Stats(); stats.Recordings != 3 {
+		t.Errorf("%d recordings before they expire, want 3", stats.Recordings)
+	}
+	clock.Advance(time.Hour)
+	waitForSleeper(t, clock, now.Add(time.Hour+time.Minute))
+	if stats, _ := s.Stats(); stats.Recordings != 1 {
+		t.Errorf("%d recordings after an hour, want 1", stats.Recordings)
+	}
+	cancel()
+	<-done
+}
+
+func TestGetRecordingStatsRPC(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = ""
+	c := serveAudio(t, newBurstSource(1, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})).(RecordingStatsGetter)
+	if _, err := c.GetRecordingStats(ctx); err == nil {
+		t.Error("got the stats of a server without a store")
+	}
+
+	now := time.Now().Truncate(time.Second)
+	ServerRecordings.Dir = storeWith(t, now, 10, 20).Dir
+	stats, err := c.GetRecordingStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Recordings != 2 || stats.Bytes != 30 || !stats.Oldest.Equal(now.Add(-time.Minute)) || !stats.Newest.Equal(now) {
+		t.Errorf("stats %+v", stats)
+	}
+	if diskUsage != nil && (stats.DiskSize <= 0 || stats.DiskFree > stats.DiskSize) {
+		t.Errorf("disk has %d of %d bytes free", stats.DiskFree, stats.DiskSize)
+	}
+}
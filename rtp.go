@@ -0,0 +1,254 @@
+package audio
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/pion/rtp"
+)
+
+// Codecs RTP output can carry.
+const (
+	RTPCodecPCMU = "pcmu" // G.711 µ-law, 8 kHz mono
+	RTPCodecPCMA = "pcma" // G.711 A-law, 8 kHz mono
+	RTPCodecOpus = "opus" // passed through from a resource that captures opus
+)
+
+const (
+	g711SampleRate   = 8000
+	g711PacketFrames = 160 // 20ms
+	opusClockRate    = 48000
+	defaultOpusPT    = 111
+)
+
+// RTPConfig configures an RTP stream. Zero values pick the defaults: the
+// static payload type for G.711, 111 for opus, and a random SSRC.
+type RTPConfig struct {
+	Codec       string
+	PayloadType uint8
+	SSRC        uint32
+}
+
+func (c RTPConfig) withDefaults() (RTPConfig, error) {
+	switch c.Codec {
+	case "", RTPCodecPCMU:
+		// PCMU's static payload type is 0, which is also the zero value
+		c.Codec = RTPCodecPCMU
+	case RTPCodecPCMA:
+		if c.PayloadType == 0 {
+			c.PayloadType = 8
+		}
+	case RTPCodecOpus:
+		if c.PayloadType == 0 {
+			c.PayloadType = defaultOpusPT
+		}
+	default:
+		return c, fmt.Errorf("unsupported rtp codec %q, expected %s, %s or %s", c.Codec, RTPCodecPCMU, RTPCodecPCMA, RTPCodecOpus)
+	}
+	if c.SSRC == 0 {
+		var b [4]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return c, err
+		}
+		c.SSRC = binary.BigEndian.Uint32(b[:])
+	}
+	return c, nil
+}
+
+// rtpmap returns the SDP rtpmap encoding for the codec.
+func (c RTPConfig) rtpmap() string {
+	switch c.Codec {
+	case RTPCodecPCMA:
+		return "PCMA/8000"
+	case RTPCodecOpus:
+		return "opus/48000/2"
+	default:
+		return "PCMU/8000"
+	}
+}
+
+// StreamRTP sends the capture of a to addr as RTP over UDP until ctx is done
+// or the capture ends. Like StreamIcecast, it doesn't start unless its
+// start can be written to the audit log.
+func StreamRTP(ctx context.Context, a Audio, addr string, cfg RTPConfig) (err error) {
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return err
+	}
+	name, detail := a.Name().ShortName(), "rtp to "+addr
+	if err := auditOperation(ctx, serverLogger, name, AuditCaptureStarted, "", detail, nil); err != nil {
+		return err
+	}
+	defer func() {
+		auditOperation(context.WithoutCancel(ctx), serverLogger, name, AuditCaptureEnded, "", detail, err)
+	}()
+	conn, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return sendRTP(ctx, a, cfg, func(pkt []byte) error {
+		_, err := conn.Write(pkt)
+		return err
+	})
+}
+
+// sendRTP packetizes the capture of a and hands every marshalled packet to
+// send, until ctx is done, the capture ends or send fails.
+func sendRTP(ctx context.Context, a Audio, cfg RTPConfig, send func([]byte) error) error {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var chunks <-chan *AudioChunk
+	if cfg.Codec == RTPCodecOpus {
+		chunks, err = a.GetAudio(ctx, RTPCodecOpus, 0, 0, 0)
+	} else {
+		chunks, err = sharedCaptureHub.open(ctx, a, captureRequest{
+			target: AudioInfo{Format: Pcm16, SampleRate: g711SampleRate, Channels: 1},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	p := &rtpPacketizer{cfg: cfg, marker: true}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case chunk, ok := <-chunks:
+			if !ok {
+				return nil
+			}
+			if chunk.Err != nil {
+				return chunk.Err
+			}
+			pkts, err := p.packetize(chunk)
+			if err != nil {
+				return err
+			}
+			for _, pkt := range pkts {
+				if err := send(pkt); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// rtpPacketizer turns capture chunks into RTP packets. G.711 audio is cut
+// into 20ms packets; each opus chunk is one packet.
+type rtpPacketizer struct {
+	cfg       RTPConfig
+	seq       uint16
+	timestamp uint32
+	marker    bool // set on the first packet after a start or a gap
+	pending   []byte
+}
+
+func (p *rtpPacketizer) packetize(chunk *AudioChunk) ([][]byte, error) {
+	clockRate := g711SampleRate
+	if p.cfg.Codec == RTPCodecOpus {
+		clockRate = opusClockRate
+	}
+	if chunk.Gap > 0 {
+		// keep timestamps on the wall clock so receivers insert silence
+		p.timestamp += uint32(chunk.Gap.Seconds() * float64(clockRate))
+		p.pending = nil
+		p.marker = true
+	}
+
+	if p.cfg.Codec == RTPCodecOpus {
+		frames, err := opusPacketFrames(chunk.AudioData)
+		if err != nil {
+			return nil, err
+		}
+		pkt, err := p.packet(chunk.AudioData)
+		if err != nil {
+			return nil, err
+		}
+		p.timestamp += uint32(frames)
+		return [][]byte{pkt}, nil
+	}
+
+	for i := 0; i+1 < len(chunk.AudioData); i += 2 {
+		sample := int16(binary.LittleEndian.Uint16(chunk.AudioData[i:]))
+		if p.cfg.Codec == RTPCodecPCMA {
+			p.pending = append(p.pending, linearToAlaw(sample))
+		} else {
+			p.pending = append(p.pending, linearToMulaw(sample))
+		}
+	}
+	var pkts [][]byte
+	for len(p.pending) >= g711PacketFrames {
+		pkt, err := p.packet(p.pending[:g711PacketFrames])
+		if err != nil {
+			return nil, err
+		}
+		pkts = append(pkts, pkt)
+		p.pending = p.pending[g711PacketFrames:]
+		p.timestamp += g711PacketFrames
+	}
+	return pkts, nil
+}
+
+func (p *rtpPacketizer) packet(payload []byte) ([]byte, error) {
+	pkt := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         p.marker,
+			PayloadType:    p.cfg.PayloadType,
+			SequenceNumber: p.seq,
+			Timestamp:      p.timestamp,
+			SSRC:           p.cfg.SSRC,
+		},
+		Payload: payload,
+	}
+	p.seq++
+	p.marker = false
+	return pkt.Marshal()
+}
+
+var errBadOpusPacket = errors.New("chunk is not an opus packet")
+
+// opusFrameSamples is the frame length in 48 kHz samples for each TOC config
+// (RFC 6716 section 3.1).
+var opusFrameSamples = [32]int{
+	480, 960, 1920, 2880, // SILK NB
+	480, 960, 1920, 2880, // SILK MB
+	480, 960, 1920, 2880, // SILK WB
+	480, 960, // Hybrid SWB
+	480, 960, // Hybrid FB
+	120, 240, 480, 960, // CELT NB
+	120, 240, 480, 960, // CELT WB
+	120, 240, 480, 960, // CELT SWB
+	120, 240, 480, 960, // CELT FB
+}
+
+// opusPacketFrames returns how many 48 kHz samples an opus packet holds.
+func opusPacketFrames(packet []byte) (int, error) {
+	if len(packet) == 0 {
+		return 0, errBadOpusPacket
+	}
+	toc := packet[0]
+	frameSamples := opusFrameSamples[toc>>3]
+	switch toc & 0x03 {
+	case 0:
+		return frameSamples, nil
+	case 1, 2:
+		return 2 * frameSamples, nil
+	default:
+		if len(packet) < 2 {
+			return 0, errBadOpusPacket
+		}
+		return int(packet[1]&0x3F) * frameSamples, nil
+	}
+}
@@ -0,0 +1,109 @@
+package audio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+func TestRTPPacketizerG711(t *testing.T) {
+	for _, tc := range []struct {
+		codec string
+		pt    uint8
+		code  byte
+	}{
+		{RTPCodecPCMU, 0, 0xce},
+		{RTPCodecPCMA, 8, 0xfa},
+	} {
+		t.Run(tc.codec, func(t *testing.T) {
+			cfg, err := RTPConfig{Codec: tc.codec, SSRC: 0x1234}.withDefaults()
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &rtpPacketizer{cfg: cfg, marker: true}
+			info := &AudioInfo{Format: Pcm16, SampleRate: g711SampleRate, Channels: 1}
+			samples := make([]float32, 250) // 31.25ms, so packets straddle chunks
+			for i := range samples {
+				samples[i] = 1000.0 / 32768
+			}
+			data, err := encodePCM(samples, Pcm16)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var pkts [][]byte
+			for i := 0; i < 4; i++ {
+				chunk := &AudioChunk{AudioData: data, Info: info}
+				if i == 2 {
+					chunk.Gap = 100 * time.Millisecond
+				}
+				out, err := p.packetize(chunk)
+				if err != nil {
+					t.Fatal(err)
+				}
+				pkts = append(pkts, out...)
+			}
+
+			// 250 and 250 frames give three packets with 20 left over, which
+			// the gap discards; then 500 more give three again
+			want := []struct {
+				seq    uint16
+				ts     uint32
+				marker bool
+			}{
+				{0, 0, true}, {1, 160, false}, {2, 320, false},
+				{3, 480 + 800, true}, {4, 640 + 800, false}, {5, 800 + 800, false},
+			}
+			if len(pkts) != len(want) {
+				t.Fatalf("got %d packets, want %d", len(pkts), len(want))
+			}
+			for i, w := range want {
+				var pkt rtp.Packet
+				if err := pkt.Unmarshal(pkts[i]); err != nil {
+					t.Fatal(err)
+				}
+				if pkt.Version != 2 || pkt.PayloadType != tc.pt || pkt.SSRC != 0x1234 {
+					t.Fatalf("packet %d header %+v", i, pkt.Header)
+				}
+				if pkt.SequenceNumber != w.seq || pkt.Timestamp != w.ts || pkt.Marker != w.marker {
+					t.Fatalf("packet %d has seq %d ts %d marker %v, want %+v", i, pkt.SequenceNumber, pkt.Timestamp, pkt.Marker, w)
+				}
+				if len(pkt.Payload) != g711PacketFrames {
+					t.Fatalf("packet %d carries %d bytes", i, len(pkt.Payload))
+				}
+				for _, b := range pkt.Payload {
+					if b != tc.code {
+						t.Fatalf("packet %d payload byte %#02x, want %#02x", i, b, tc.code)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestOpusPacketFrames(t *testing.T) {
+	for _, tc := range []struct {
+		packet []byte
+		want   int
+	}{
+		{[]byte{1 << 3}, 960},              // SILK NB 20ms, one frame
+		{[]byte{31<<3 | 1}, 1920},          // CELT FB 20ms, two equal frames
+		{[]byte{16<<3 | 2}, 240},           // CELT NB 2.5ms, two frames
+		{[]byte{3<<3 | 3, 3}, 3 * 2880},    // SILK NB 60ms, three frames
+		{[]byte{19<<3 | 3, 0x85}, 5 * 960}, // padding flag set, five frames
+	} {
+		got, err := opusPacketFrames(tc.packet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("opusPacketFrames(% x) = %d, want %d", tc.packet, got, tc.want)
+		}
+	}
+	for _, bad := range [][]byte{nil, {3}} {
+		if _, err := opusPacketFrames(bad); err == nil {
+			t.Errorf("opusPacketFrames(% x) accepted a truncated packet", bad)
+		}
+	}
+}
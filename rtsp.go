@@ -0,0 +1,352 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.viam.com/rdk/logging"
+)
+
+// RTSPConfig configures an RTSPServer. RTPPort is the local UDP port RTP is
+// sent from to clients that don't ask for TCP interleaving; zero picks one.
+type RTSPConfig struct {
+	RTP     RTPConfig
+	RTPPort int
+}
+
+// RTSPServer serves rtsp://host/{name} so VLC, ffmpeg and NVRs can pull a
+// resource's capture as RTP. It implements the subset of RTSP those clients
+// use for a single live audio track: OPTIONS, DESCRIBE, SETUP over UDP or
+// interleaved TCP, PLAY and TEARDOWN.
+type RTSPServer struct {
+	lookup func(name string) (Audio, error)
+	cfg    RTSPConfig
+	logger logging.Logger
+
+	mu  sync.Mutex
+	udp *net.UDPConn
+}
+
+// NewRTSPServer returns an RTSP server over the resources lookup resolves.
+func NewRTSPServer(lookup func(name string) (Audio, error), cfg RTSPConfig, logger logging.Logger) (*RTSPServer, error) {
+	if _, err := cfg.RTP.withDefaults(); err != nil {
+		return nil, err
+	}
+	return &RTSPServer{lookup: lookup, cfg: cfg, logger: logger}, nil
+}
+
+// Serve accepts RTSP connections on l until it is closed.
+func (s *RTSPServer) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops the RTP socket. Sessions end when their connections close.
+func (s *RTSPServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.udp == nil {
+		return nil
+	}
+	err := s.udp.Close()
+	s.udp = nil
+	return err
+}
+
+func (s *RTSPServer) udpConn() (*net.UDPConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.udp != nil {
+		return s.udp, nil
+	}
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{Port: s.cfg.RTPPort})
+	if err != nil {
+		return nil, err
+	}
+	s.udp = conn
+	return conn, nil
+}
+
+type rtspRequest struct {
+	method string
+	url    *url.URL
+	header textproto.MIMEHeader
+}
+
+// rtspSession is the state of one RTSP connection. Each connection carries at
+// most one session with a single track.
+type rtspSession struct {
+	server  *RTSPServer
+	conn    net.Conn
+	writeMu sync.Mutex
+
+	id     string
+	audio  Audio
+	name   string
+	cfg    RTPConfig
+	send   func([]byte) error
+	cancel context.CancelFunc
+}
+
+func (s *RTSPServer) serveConn(conn net.Conn) {
+	sess := &rtspSession{server: s, conn: conn}
+	defer func() {
+		sess.stop()
+		conn.Close()
+	}()
+
+	r := textproto.NewReader(bufio.NewReader(conn))
+	for {
+		req, err := readRTSPRequest(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				s.logger.Debugw("rtsp connection closed", "remote", conn.RemoteAddr(), "error", err)
+			}
+			return
+		}
+		if done := sess.handle(req); done {
+			return
+		}
+	}
+}
+
+func readRTSPRequest(r *textproto.Reader) (*rtspRequest, error) {
+	line, err := r.ReadLine()
+	for err == nil && line == "" {
+		line, err = r.ReadLine()
+	}
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Fields(line)
+	if len(parts) != 3 || !strings.HasPrefix(parts[2], "RTSP/") {
+		return nil, fmt.Errorf("malformed rtsp request line %q", line)
+	}
+	u, err := url.Parse(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	header, err := r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := strconv.Atoi(header.Get("Content-Length")); n > 0 {
+		// no method we support takes a body, but it has to be consumed
+		if _, err := io.CopyN(io.Discard, r.R, int64(n)); err != nil {
+			return nil, err
+		}
+	}
+	return &rtspRequest{method: parts[0], url: u, header: header}, nil
+}
+
+// handle answers one request and reports whether the connection is done.
+func (sess *rtspSession) handle(req *rtspRequest) bool {
+	cseq := req.header.Get("CSeq")
+	switch req.method {
+	case "OPTIONS":
+		sess.reply(cseq, 200, "OK", map[string]string{"Public": "OPTIONS, DESCRIBE, SETUP, PLAY, TEARDOWN, GET_PARAMETER"}, "")
+	case "DESCRIBE":
+		name := rtspResourceName(req.url)
+		if _, err := sess.server.lookup(name); err != nil {
+			sess.reply(cseq, 404, "Not Found", nil, "")
+			return false
+		}
+		cfg, err := sess.server.cfg.RTP.withDefaults()
+		if err != nil {
+			sess.reply(cseq, 500, "Internal Server Error", nil, "")
+			return false
+		}
+		sess.reply(cseq, 200, "OK", map[string]string{
+			"Content-Type": "application/sdp",
+			"Content-Base": strings.TrimSuffix(req.url.String(), "/") + "/",
+		}, rtspSDP(name, cfg))
+	case "SETUP":
+		sess.setup(cseq, req)
+	case "PLAY":
+		if sess.send == nil {
+			sess.reply(cseq, 455, "Method Not Valid in This State", nil, "")
+			return false
+		}
+		sess.play(cseq)
+	case "GET_PARAMETER":
+		// clients use it as a keepalive
+		sess.reply(cseq, 200, "OK", map[string]string{"Session": sess.id}, "")
+	case "TEARDOWN":
+		sess.stop()
+		sess.reply(cseq, 200, "OK", map[string]string{"Session": sess.id}, "")
+		return true
+	default:
+		sess.reply(cseq, 501, "Not Implemented", nil, "")
+	}
+	return false
+}
+
+func (sess *rtspSession) setup(cseq string, req *rtspRequest) {
+	if sess.send != nil {
+		sess.reply(cseq, 459, "Aggregate Operation Not Allowed", nil, "")
+		return
+	}
+	name := rtspResourceName(req.url)
+	a, err := sess.server.lookup(name)
+	if err != nil {
+		sess.reply(cseq, 404, "Not Found", nil, "")
+		return
+	}
+	cfg, err := sess.server.cfg.RTP.withDefaults()
+	if err != nil {
+		sess.reply(cseq, 500, "Internal Server Error", nil, "")
+		return
+	}
+
+	transport := req.header.Get("Transport")
+	var reply string
+	switch {
+	case strings.Contains(transport, "RTP/AVP/TCP"):
+		channel := 0
+		if v := rtspTransportParam(transport, "interleaved"); v != "" {
+			channel, _ = strconv.Atoi(strings.SplitN(v, "-", 2)[0])
+		}
+		sess.send = func(pkt []byte) error {
+			// RFC 2326 section 10.12 framing
+			frame := append([]byte{'$', byte(channel), byte(len(pkt) >> 8), byte(len(pkt))}, pkt...)
+			sess.writeMu.Lock()
+			defer sess.writeMu.Unlock()
+			_, err := sess.conn.Write(frame)
+			return err
+		}
+		reply = fmt.Sprintf("RTP/AVP/TCP;unicast;interleaved=%d-%d", channel, channel+1)
+	default:
+		ports := rtspTransportParam(transport, "client_port")
+		port, err := strconv.Atoi(strings.SplitN(ports, "-", 2)[0])
+		if err != nil {
+			sess.reply(cseq, 461, "Unsupported Transport", nil, "")
+			return
+		}
+		udp, err := sess.server.udpConn()
+		if err != nil {
+			sess.reply(cseq, 500, "Internal Server Error", nil, "")
+			return
+		}
+		host, _, _ := net.SplitHostPort(sess.conn.RemoteAddr().String())
+		dest := &net.UDPAddr{IP: net.ParseIP(host), Port: port}
+		sess.send = func(pkt []byte) error {
+			_, err := udp.WriteToUDP(pkt, dest)
+			return err
+		}
+		local := udp.LocalAddr().(*net.UDPAddr).Port
+		reply = fmt.Sprintf("RTP/AVP;unicast;client_port=%d-%d;server_port=%d-%d;ssrc=%08X", port, port+1, local, local+1, cfg.SSRC)
+	}
+
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		sess.reply(cseq, 500, "Internal Server Error", nil, "")
+		return
+	}
+	sess.id = hex.EncodeToString(id[:])
+	sess.audio, sess.name, sess.cfg = a, name, cfg
+	sess.reply(cseq, 200, "OK", map[string]string{"Session": sess.id + ";timeout=60", "Transport": reply}, "")
+}
+
+// play starts the session's stream, once its start is in the audit log.
+func (sess *rtspSession) play(cseq string) {
+	if sess.cancel != nil {
+		sess.reply(cseq, 200, "OK", map[string]string{"Session": sess.id, "Range": "npt=0.000-"}, "")
+		return
+	}
+	logger := sess.server.logger
+	ctx := withPeer(context.Background(), sess.conn.RemoteAddr())
+	if err := auditOperation(ctx, logger, sess.name, AuditCaptureStarted, sess.id, "rtsp", nil); err != nil {
+		sess.reply(cseq, 503, "Service Unavailable", nil, "")
+		return
+	}
+	sess.reply(cseq, 200, "OK", map[string]string{"Session": sess.id, "Range": "npt=0.000-"}, "")
+	streamCtx, cancel := context.WithCancel(ctx)
+	sess.cancel = cancel
+	go func() {
+		err := sendRTP(streamCtx, sess.audio, sess.cfg, sess.send)
+		if errors.Is(err, context.Canceled) {
+			err = nil
+		}
+		auditOperation(ctx, logger, sess.name, AuditCaptureEnded, sess.id, "rtsp", err)
+		if err != nil {
+			logger.Infow("rtsp stream ended", "name", sess.name, "remote", sess.conn.RemoteAddr(), "error", err)
+			// without media the client would wait forever
+			sess.conn.Close()
+		}
+	}()
+}
+
+func (sess *rtspSession) stop() {
+	if sess.cancel != nil {
+		sess.cancel()
+		sess.cancel = nil
+	}
+}
+
+func (sess *rtspSession) reply(cseq string, code int, status string, headers map[string]string, body string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "RTSP/1.0 %d %s\r\nCSeq: %s\r\n", code, status, cseq)
+	for k, v := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+	if body != "" {
+		fmt.Fprintf(&b, "Content-Length: %d\r\n", len(body))
+	}
+	b.WriteString("\r\n")
+	b.WriteString(body)
+
+	sess.writeMu.Lock()
+	defer sess.writeMu.Unlock()
+	if _, err := io.WriteString(sess.conn, b.String()); err != nil {
+		sess.server.logger.Debugw("failed to write rtsp reply", "error", err)
+	}
+}
+
+// rtspResourceName takes the resource name from the first path segment, so
+// both the presentation URL and its track URL resolve to it.
+func rtspResourceName(u *url.URL) string {
+	return strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)[0]
+}
+
+func rtspTransportParam(transport, key string) string {
+	for _, p := range strings.Split(transport, ";") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(p), "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+func rtspSDP(name string, cfg RTPConfig) string {
+	lines := []string{
+		"v=0",
+		"o=- 0 0 IN IP4 0.0.0.0",
+		"s=" + name,
+		"c=IN IP4 0.0.0.0",
+		"t=0 0",
+		"a=control:*",
+		fmt.Sprintf("m=audio 0 RTP/AVP %d", cfg.PayloadType),
+		fmt.Sprintf("a=rtpmap:%d %s", cfg.PayloadType, cfg.rtpmap()),
+		"a=control:track0",
+	}
+	if cfg.Codec != RTPCodecOpus {
+		lines = append(lines, "a=ptime:20")
+	}
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
@@ -0,0 +1,200 @@
+package audio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"go.viam.com/rdk/logging"
+)
+
+// rtspClient drives one RTSP connection the way ffmpeg and VLC do.
+type rtspClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+	cseq int
+}
+
+type rtspResponse struct {
+	code   int
+	header textproto.MIMEHeader
+	body   string
+}
+
+func (c *rtspClient) do(method, url string, headers ...string) rtspResponse {
+	c.t.Helper()
+	c.cseq++
+	req := fmt.Sprintf("%s %s RTSP/1.0\r\nCSeq: %d\r\n", method, url, c.cseq)
+	for _, h := range headers {
+		req += h + "\r\n"
+	}
+	if _, err := io.WriteString(c.conn, req+"\r\n"); err != nil {
+		c.t.Fatal(err)
+	}
+	for {
+		// skip media interleaved ahead of the reply
+		if b, err := c.r.Peek(1); err == nil && b[0] == '$' {
+			c.readInterleaved()
+			continue
+		}
+		break
+	}
+	tp := textproto.NewReader(c.r)
+	line, err := tp.ReadLine()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	parts := strings.SplitN(line, " ", 3)
+	code, _ := strconv.Atoi(parts[1])
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if got := header.Get("CSeq"); got != strconv.Itoa(c.cseq) {
+		c.t.Fatalf("%s reply has CSeq %q, want %d", method, got, c.cseq)
+	}
+	var body []byte
+	if n, _ := strconv.Atoi(header.Get("Content-Length")); n > 0 {
+		body = make([]byte, n)
+		if _, err := io.ReadFull(c.r, body); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	return rtspResponse{code: code, header: header, body: string(body)}
+}
+
+func (c *rtspClient) readInterleaved() (channel byte, pkt []byte) {
+	c.t.Helper()
+	var hdr [4]byte
+	if _, err := io.ReadFull(c.r, hdr[:]); err != nil {
+		c.t.Fatal(err)
+	}
+	if hdr[0] != '$' {
+		c.t.Fatalf("interleaved frame starts with %q", hdr[0])
+	}
+	pkt = make([]byte, int(hdr[2])<<8|int(hdr[3]))
+	if _, err := io.ReadFull(c.r, pkt); err != nil {
+		c.t.Fatal(err)
+	}
+	return hdr[1], pkt
+}
+
+func TestReadRTSPRequest(t *testing.T) {
+	raw := "\r\nDESCRIBE rtsp://robot/mic RTSP/1.0\r\nCSeq: 2\r\nContent-Length: 4\r\n\r\nbodyOPTIONS * RTSP/1.0\r\nCSeq: 3\r\n\r\n"
+	r := textproto.NewReader(bufio.NewReader(strings.NewReader(raw)))
+	req, err := readRTSPRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.method != "DESCRIBE" || rtspResourceName(req.url) != "mic" || req.header.Get("CSeq") != "2" {
+		t.Fatalf("got %+v", req)
+	}
+	// the body was consumed, so the next request parses
+	req, err = readRTSPRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.method != "OPTIONS" || req.header.Get("CSeq") != "3" {
+		t.Fatalf("got %+v", req)
+	}
+	if _, err := readRTSPRequest(r); !errors.Is(err, io.EOF) {
+		t.Fatalf("got %v at the end, want EOF", err)
+	}
+
+	bad := textproto.NewReader(bufio.NewReader(strings.NewReader("GET / HTTP/1.1\r\n\r\n")))
+	if _, err := readRTSPRequest(bad); err == nil {
+		t.Fatal("accepted an HTTP request line")
+	}
+}
+
+func TestRTSPTransportParam(t *testing.T) {
+	transport := "RTP/AVP;unicast;client_port=5000-5001 ; interleaved=2-3"
+	for key, want := range map[string]string{"client_port": "5000-5001", "interleaved": "2-3", "ttl": ""} {
+		if got := rtspTransportParam(transport, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestRTSPSessionInterleaved(t *testing.T) {
+	src := newBurstSource(10, AudioInfo{Format: Pcm16, SampleRate: g711SampleRate, Channels: 1})
+	close(src.start)
+	srv, err := NewRTSPServer(func(name string) (Audio, error) {
+		if name != "mic" {
+			return nil, errors.New("not found")
+		}
+		return src, nil
+	}, RTSPConfig{RTP: RTPConfig{Codec: RTPCodecPCMA, SSRC: 0xCAFE}}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	server, conn := net.Pipe()
+	go srv.serveConn(server)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	c := &rtspClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+
+	if resp := c.do("OPTIONS", "rtsp://robot/mic"); resp.code != 200 || !strings.Contains(resp.header.Get("Public"), "PLAY") {
+		t.Fatalf("OPTIONS: %+v", resp)
+	}
+	if resp := c.do("DESCRIBE", "rtsp://robot/speaker"); resp.code != 404 {
+		t.Fatalf("DESCRIBE of an unknown resource: %+v", resp)
+	}
+	if resp := c.do("PLAY", "rtsp://robot/mic"); resp.code != 455 {
+		t.Fatalf("PLAY before SETUP: %+v", resp)
+	}
+
+	resp := c.do("DESCRIBE", "rtsp://robot/mic", "Accept: application/sdp")
+	if resp.code != 200 || resp.header.Get("Content-Type") != "application/sdp" {
+		t.Fatalf("DESCRIBE: %+v", resp)
+	}
+	for _, line := range []string{"m=audio 0 RTP/AVP 8", "a=rtpmap:8 PCMA/8000", "a=control:track0", "a=ptime:20"} {
+		if !strings.Contains(resp.body, line+"\r\n") {
+			t.Fatalf("SDP is missing %q:\n%s", line, resp.body)
+		}
+	}
+
+	resp = c.do("SETUP", "rtsp://robot/mic/track0", "Transport: RTP/AVP/TCP;unicast;interleaved=4-5")
+	if resp.code != 200 || resp.header.Get("Transport") != "RTP/AVP/TCP;unicast;interleaved=4-5" {
+		t.Fatalf("SETUP: %+v", resp)
+	}
+	session, _, _ := strings.Cut(resp.header.Get("Session"), ";")
+	if session == "" {
+		t.Fatal("SETUP returned no session")
+	}
+	if resp := c.do("SETUP", "rtsp://robot/mic/track0", "Transport: RTP/AVP/TCP;unicast;interleaved=6-7"); resp.code != 459 {
+		t.Fatalf("second SETUP: %+v", resp)
+	}
+
+	if resp := c.do("PLAY", "rtsp://robot/mic", "Session: "+session); resp.code != 200 {
+		t.Fatalf("PLAY: %+v", resp)
+	}
+	// ten 10ms chunks make five 20ms packets
+	for i := 0; i < 5; i++ {
+		channel, data := c.readInterleaved()
+		if channel != 4 {
+			t.Fatalf("packet on channel %d, want 4", channel)
+		}
+		var pkt rtp.Packet
+		if err := pkt.Unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+		if pkt.PayloadType != 8 || pkt.SSRC != 0xCAFE || pkt.SequenceNumber != uint16(i) || pkt.Timestamp != uint32(i*g711PacketFrames) {
+			t.Fatalf("packet %d header %+v", i, pkt.Header)
+		}
+	}
+	if resp := c.do("TEARDOWN", "rtsp://robot/mic", "Session: "+session); resp.code != 200 {
+		t.Fatalf("TEARDOWN: %+v", resp)
+	}
+}
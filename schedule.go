@@ -0,0 +1,629 @@
+package audio
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// scheduleRetry is how long a schedule waits to record again after a
+// recording failed to start or ended on its own inside a window.
+const scheduleRetry = 10 * time.Second
+
+// cronSchedule is a five field cron expression: minute, hour, day of month,
+// month and day of week. Each field is *, a number, a range a-b, either of
+// them followed by /step, or a comma separated list of these. Days of the
+// week run from 0 for Sunday, and 7 is Sunday too. As in cron, a time
+// matches when both day fields do, or either if neither is *.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64 // bit i set if i matches
+	domStar, dowStar              bool
+}
+
+var cronFields = [5]struct {
+	name     string
+	min, max int
+}{{"minute", 0, 59}, {"hour", 0, 23}, {"day of month", 1, 31}, {"month", 1, 12}, {"day of week", 0, 7}}
+
+func parseCron(expr string) (cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("cron expression %q needs 5 fields, got %d", expr, len(fields))
+	}
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return cronSchedule{}, fmt.Errorf("%s of %q: %w", cronFields[i].name, expr, err)
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return cronSchedule{
+		minute: bits[0], hour: bits[1], dom: bits[2], month: bits[3], dow: bits[4],
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		span, stepText, stepped := strings.Cut(part, "/")
+		step := 1
+		if stepped {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+		from, to := lo, hi
+		if span != "*" {
+			a, b, ranged := strings.Cut(span, "-")
+			var err error
+			if from, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("invalid value %q", a)
+			}
+			switch {
+			case ranged:
+				if to, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("invalid value %q", b)
+				}
+			case !stepped:
+				to = from
+			}
+			if from < lo || to > hi || from > to {
+				return 0, fmt.Errorf("%q is outside %d-%d", span, lo, hi)
+			}
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (c cronSchedule) matchesDay(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<t.Weekday()) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first minute after t that matches, in t's location, or
+// the zero time if none does within five years.
+func (c cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case c.month&(1<<t.Month()) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// RecordingWindow is a recurring period to record in, from each time Start
+// matches to the next time Stop does. Both are five field cron expressions,
+// so {"start": "0 18 * * 1-5", "stop": "0 6 * * *"} records weeknights.
+type RecordingWindow struct {
+	Start string `json:"start"`
+	Stop  string `json:"stop"`
+}
+
+// parsedWindow is a RecordingWindow with its expressions parsed.
+type parsedWindow struct {
+	start, stop cronSchedule
+}
+
+func parseWindows(windows []RecordingWindow) ([]parsedWindow, error) {
+	if len(windows) == 0 {
+		return nil, errors.New("a recording schedule needs at least one window")
+	}
+	parsed := make([]parsedWindow, len(windows))
+	for i, w := range windows {
+		if strings.Join(strings.Fields(w.Start), " ") == strings.Join(strings.Fields(w.Stop), " ") {
+			return nil, fmt.Errorf("window %d starts and stops at the same times", i)
+		}
+		var err error
+		if parsed[i].start, err = parseCron(w.Start); err != nil {
+			return nil, fmt.Errorf("window %d start: %w", i, err)
+		}
+		if parsed[i].stop, err = parseCron(w.Stop); err != nil {
+			return nil, fmt.Errorf("window %d stop: %w", i, err)
+		}
+	}
+	return parsed, nil
+}
+
+// windowsAt reports whether any window is open at t, which it is when it
+// stops again before it next starts, and the next time one opens or closes,
+// zero if none ever does.
+func windowsAt(windows []parsedWindow, t time.Time) (bool, time.Time) {
+	var open bool
+	var change time.Time
+	for _, w := range windows {
+		start, stop := w.start.next(t), w.stop.next(t)
+		if !stop.IsZero() && (start.IsZero() || stop.Before(start)) {
+			open = true
+		}
+		for _, at := range []time.Time{start, stop} {
+			if !at.IsZero() && (change.IsZero() || at.Before(change)) {
+				change = at
+			}
+		}
+	}
+	return open, change
+}
+
+// ScheduleConfig describes when ScheduleRecording records and how.
+type ScheduleConfig struct {
+	Windows []RecordingWindow
+	// Location the windows are read in, the robot's local time if nil.
+	Location *time.Location
+	// Recording is how each window is recorded.
+	Recording RecordingConfig
+	// Clock the windows are followed on, the wall clock if nil.
+	Clock ClockSource
+}
+
+// RecordingSchedule records a resource's capture whenever one of its windows
+// is open, until it is cancelled.
+type RecordingSchedule struct {
+	name    string
+	windows []parsedWindow
+	clock   ClockSource
+	logger  logging.Logger
+
+	mu       sync.Mutex
+	rec      *Recording // nil outside a window
+	finished []RecordingSegment
+	next     time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// ScheduleStatus is the state of a RecordingSchedule.
+type ScheduleStatus struct {
+	Recording bool
+	Next      time.Time          // when recording next starts or stops, zero if it never will
+	Segments  []RecordingSegment // written by the schedule, oldest first
+}
+
+// ScheduleRecording records the capture of a with cfg.Recording during
+// every window of cfg until Cancel is called. A window that is open already
+// starts recording at once. Each window is its own recording, and one that
+// ends on its own is started again.
+func ScheduleRecording(a Audio, cfg ScheduleConfig, logger logging.Logger) (*RecordingSchedule, error) {
+	windows, err := parseWindows(cfg.Windows)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Recording.Dir == "" {
+		return nil, errors.New("recording needs a directory")
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &RecordingSchedule{name: a.Name().ShortName(), windows: windows, clock: cfg.Clock, logger: logger, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		s.run(ctx, a, cfg.Recording, cfg.Location)
+	}()
+	return s, nil
+}
+
+// run starts and stops recordings as windows open and close until ctx is
+// done.
+func (s *RecordingSchedule) run(ctx context.Context, a Audio, cfg RecordingConfig, loc *time.Location) {
+	for {
+		now := s.clock.Now()
+		open, change := windowsAt(s.windows, now.In(loc))
+		s.mu.Lock()
+		s.next = change
+		rec := s.rec
+		s.mu.Unlock()
+
+		switch {
+		case open && rec == nil:
+			var err error
+			if rec, err = startAuditedRecording(ctx, a, cfg, "schedule", s.logger); err != nil {
+				s.logger.Warnf("cannot start scheduled recording: %v", err)
+			} else {
+				s.mu.Lock()
+				s.rec = rec
+				s.mu.Unlock()
+			}
+		case !open && rec != nil:
+			if err := rec.Stop(ctx); err != nil {
+				s.logger.Warnf("scheduled recording ended: %v", err)
+			}
+			s.finish()
+			rec = nil
+		}
+
+		wait := 24 * time.Hour // then look again, if nothing ever changes
+		if !change.IsZero() {
+			wait = change.Sub(now)
+		}
+		if open && rec == nil {
+			wait = min(wait, scheduleRetry)
+		}
+		sctx, cancel := context.WithCancel(ctx)
+		if rec != nil {
+			go func() {
+				select {
+				case <-rec.Done():
+					cancel()
+				case <-sctx.Done():
+				}
+			}()
+		}
+		s.clock.Sleep(sctx, wait)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if rec == nil {
+			continue
+		}
+		select {
+		case <-rec.Done():
+			// the capture ended inside the window
+			s.logger.Warnf("scheduled recording ended: %v", rec.Err())
+			s.finish()
+			if s.clock.Sleep(ctx, scheduleRetry) != nil {
+				return
+			}
+		default:
+		}
+	}
+}
+
+// finish keeps the segments of the current recording once it has ended.
+func (s *RecordingSchedule) finish() {
+	s.mu.Lock()
+	rec := s.rec
+	s.finished = append(s.finished, rec.Segments()...)
+	s.rec = nil
+	s.mu.Unlock()
+	auditRecordingStopped(s.logger, s.name, rec, "schedule")
+}
+
+// Status returns the state of the schedule.
+func (s *RecordingSchedule) Status() ScheduleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := ScheduleStatus{Recording: s.rec != nil, Next: s.next, Segments: slices.Clone(s.finished)}
+	if s.rec != nil {
+		status.Segments = append(status.Segments, s.rec.Segments()...)
+	}
+	return status
+}
+
+// Cancel ends the schedule and any recording in progress, see
+// Recording.Stop, and returns the segments the schedule wrote.
+func (s *RecordingSchedule) Cancel(ctx context.Context) ([]RecordingSegment, error) {
+	s.cancel()
+	<-s.done
+	var err error
+	s.mu.Lock()
+	rec := s.rec
+	s.mu.Unlock()
+	if rec != nil {
+		if err = rec.Stop(ctx); errors.Is(err, context.Canceled) {
+			err = nil // the capture ended with the schedule
+		}
+		s.finish()
+	}
+	return s.Status().Segments, err
+}
+
+// ScheduledRecorderModel wraps a microphone and records its capture to the
+// server's recording store during configured windows, such as a warehouse
+// outside working hours.
+var ScheduledRecorderModel = resource.NewModel("olivia", "audio", "scheduled_recorder")
+
+// ScheduledRecorderConfig is the configuration of the scheduled_recorder
+// model.
+type ScheduledRecorderConfig struct {
+	Input   string            `json:"input"` // Audio resource to record
+	Windows []RecordingWindow `json:"windows"`
+	// TimeZone the windows are in, an IANA name such as "America/New_York",
+	// the robot's local time if empty.
+	TimeZone       string  `json:"time_zone,omitempty"`
+	SegmentSeconds float64 `json:"segment_seconds,omitempty"` // 60 if zero
+	Format         string  `json:"format,omitempty"`          // "wav" (the default) or "flac"
+}
+
+// Validate checks the scheduled_recorder configuration and returns the input
+// as a dependency.
+func (c *ScheduledRecorderConfig) Validate(path string) ([]string, []string, error) {
+	if c.Input == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "input")
+	}
+	if _, err := parseWindows(c.Windows); err != nil {
+		return nil, nil, resource.NewConfigValidationError(path, err)
+	}
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		return nil, nil, resource.NewConfigValidationError(path, err)
+	}
+	if c.SegmentSeconds < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("segment_seconds cannot be negative"))
+	}
+	if c.Format != "" && c.Format != "wav" && c.Format != "flac" {
+		return nil, nil, resource.NewConfigValidationError(path, fmt.Errorf("format must be \"wav\" or \"flac\", got %q", c.Format))
+	}
+	return []string{c.Input}, nil, nil
+}
+
+func init() {
+	registerModel(ScheduledRecorderModel, resource.Registration[Audio, *ScheduledRecorderConfig]{
+		AttributeMapConverter: migratingConverter[*ScheduledRecorderConfig](ScheduledRecorderModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*ScheduledRecorderConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			input, err := resource.FromDependencies[Audio](deps, Named(cfg.Input))
+			if err != nil {
+				return nil, err
+			}
+			return NewScheduledRecorder(conf.ResourceName(), input, *cfg, logger)
+		},
+	})
+}
+
+type scheduledRecorder struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	input    Audio
+	schedule *RecordingSchedule
+}
+
+// NewScheduledRecorder returns a resource that passes capture and playback
+// through to input and records it during the windows of cfg.
+func NewScheduledRecorder(name resource.Name, input Audio, cfg ScheduledRecorderConfig, logger logging.Logger) (Audio, error) {
+	if ServerRecordings.Dir == "" {
+		return nil, errNoRecordingStore
+	}
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := ScheduleRecording(input, ScheduleConfig{
+		Windows:  cfg.Windows,
+		Location: loc,
+		Recording: RecordingConfig{
+			Dir:             ServerRecordings.Dir,
+			SegmentDuration: time.Duration(cfg.SegmentSeconds * float64(time.Second)),
+			Format:          cfg.Format,
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &scheduledRecorder{Named: name.AsNamed(), input: input, schedule: schedule}, nil
+}
+
+// GetAudio passes through to the input.
+func (r *scheduledRecorder) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	return r.input.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+}
+
+func (r *scheduledRecorder) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	return r.input.Play(ctx, data, codec, sampleRate, channels)
+}
+
+// DoCommand takes {"schedule_status": true}, which returns whether the
+// resource is recording, when that next changes and the segments written.
+func (r *scheduledRecorder) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, r, cmd); ok {
+		return resp, err
+	}
+	if cmd["schedule_status"] == nil {
+		return nil, resource.ErrDoUnimplemented
+	}
+	status := r.schedule.Status()
+	segments := make([]interface{}, len(status.Segments))
+	for i, seg := range status.Segments {
+		segments[i] = seg.Path
+	}
+	resp := map[string]interface{}{"recording": status.Recording, "segments": segments}
+	if !status.Next.IsZero() {
+		resp["next"] = status.Next.Format(time.RFC3339)
+	}
+	return resp, nil
+}
+
+// Close cancels the schedule, finishing the recording in progress.
+func (r *scheduledRecorder) Close(ctx context.Context) error {
+	_, err := r.schedule.Cancel(ctx)
+	return err
+}
+
+// RecordingScheduleOptions configures a schedule started through the
+// server. Its recordings go to the server's recording store.
+type RecordingScheduleOptions struct {
+	Windows   []RecordingWindow
+	TimeZone  string // IANA name, the server's local time if empty
+	Recording RecordingOptions
+}
+
+// RecordingScheduler is implemented by clients of servers that record a
+// resource's capture into their recording store on a schedule.
+type RecordingScheduler interface {
+	// ScheduleRecording starts a schedule and returns its ID and when it
+	// next starts or stops recording.
+	ScheduleRecording(ctx context.Context, opts RecordingScheduleOptions) (string, time.Time, error)
+	// CancelScheduledRecording ends a schedule and returns the segments it
+	// wrote.
+	CancelScheduledRecording(ctx context.Context, id string) ([]RecordingSegment, error)
+}
+
+// managedSchedule is a schedule the server started for a resource.
+type managedSchedule struct {
+	resource string
+	schedule *RecordingSchedule
+}
+
+// serverSchedules holds the schedules the server started, by ID.
+type serverSchedules struct {
+	mu        sync.Mutex
+	schedules map[string]*managedSchedule
+}
+
+func newServerSchedules() *serverSchedules {
+	return &serverSchedules{schedules: map[string]*managedSchedule{}}
+}
+
+func (m *serverSchedules) add(resource string, s *RecordingSchedule) string {
+	id := strings.ToLower(rand.Text())
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schedules[id] = &managedSchedule{resource: resource, schedule: s}
+	return id
+}
+
+// remove takes schedule id of resource out of the server.
+func (m *serverSchedules) remove(resource, id string) (*RecordingSchedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.schedules[id]
+	if !ok || s.resource != resource {
+		return nil, fmt.Errorf("no recording schedule %q of %s", id, resource)
+	}
+	delete(m.schedules, id)
+	return s.schedule, nil
+}
+
+// cancelAll cancels every schedule, stopping the recordings they run.
+func (m *serverSchedules) cancelAll(ctx context.Context) error {
+	m.mu.Lock()
+	schedules := m.schedules
+	m.schedules = map[string]*managedSchedule{}
+	m.mu.Unlock()
+	var errs []error
+	for _, s := range schedules {
+		if _, err := s.schedule.Cancel(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("schedule of %s: %w", s.resource, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *audioServer) ScheduleRecording(ctx context.Context, req *pb.ScheduleRecordingRequest) (*pb.ScheduleRecordingResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ServerRecordings.Dir == "" {
+		return nil, errNoRecordingStore
+	}
+	loc, err := time.LoadLocation(req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	if req.SegmentSeconds < 0 {
+		return nil, errorf(ErrInvalidArgument, "segment length cannot be negative, got %gs", req.SegmentSeconds)
+	}
+	cfg := ScheduleConfig{
+		Location: loc,
+		Recording: RecordingConfig{
+			Dir:             ServerRecordings.Dir,
+			SegmentDuration: time.Duration(req.SegmentSeconds * float64(time.Second)),
+			Format:          req.Format,
+			NormalizeLUFS:   req.NormalizeLufs,
+		},
+	}
+	for _, w := range req.Windows {
+		cfg.Windows = append(cfg.Windows, RecordingWindow{Start: w.Start, Stop: w.Stop})
+	}
+	sched, err := ScheduleRecording(a, cfg, serverVerbosity.sublogger("recording"))
+	if err != nil {
+		return nil, err
+	}
+	// worked out here, as the schedule may not have looked at its windows yet
+	_, next := windowsAt(sched.windows, time.Now().In(loc))
+	id := s.schedules.add(req.Name, sched)
+	if err := s.audit(ctx, req.Name, AuditRecordingScheduled, id, fmt.Sprintf("%d windows in %s", len(cfg.Windows), loc), nil); err != nil {
+		// no schedule records without a record of who set it up
+		if sched, rerr := s.schedules.remove(req.Name, id); rerr == nil {
+			sched.Cancel(ctx)
+		}
+		return nil, err
+	}
+	s.logger.Infow("recording scheduled", "resource", req.Name, "schedule_id", id, "next", next)
+	return &pb.ScheduleRecordingResponse{
+		ScheduleId:               id,
+		NextTimestampNanoseconds: toUnixNano(next),
+	}, nil
+}
+
+func (s *audioServer) CancelScheduledRecording(ctx context.Context, req *pb.CancelScheduledRecordingRequest) (*pb.CancelScheduledRecordingResponse, error) {
+	sched, err := s.schedules.remove(req.Name, req.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := sched.Cancel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CancelScheduledRecordingResponse{Segments: segmentsToProto(segments)}, nil
+}
+
+func (c *audioClient) ScheduleRecording(ctx context.Context, opts RecordingScheduleOptions) (string, time.Time, error) {
+	req := &pb.ScheduleRecordingRequest{
+		Name:           c.name,
+		TimeZone:       opts.TimeZone,
+		SegmentSeconds: opts.Recording.SegmentDuration.Seconds(),
+		Format:         opts.Recording.Format,
+		NormalizeLufs:  opts.Recording.NormalizeLUFS,
+	}
+	for _, w := range opts.Windows {
+		req.Windows = append(req.Windows, &pb.RecordingWindow{Start: w.Start, Stop: w.Stop})
+	}
+	resp, err := c.client.ScheduleRecording(ctx, req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return resp.ScheduleId, fromUnixNano(resp.NextTimestampNanoseconds), nil
+}
+
+func (c *audioClient) CancelScheduledRecording(ctx context.Context, id string) ([]RecordingSegment, error) {
+	resp, err := c.client.CancelScheduledRecording(ctx, &pb.CancelScheduledRecordingRequest{Name: c.name, ScheduleId: id})
+	if err != nil {
+		return nil, err
+	}
+	return segmentsFromProto(resp.Segments), nil
+}
@@ -0,0 +1,137 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestCronNext(t *testing.T) {
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC) }
+	for _, c := range []struct {
+		expr       string
+		from, want time.Time
+	}{
+		{"*/15 * * * *", at(2, 10, 7), at(2, 10, 15)},
+		{"0 18 * * 1-5", at(5, 19, 0), at(8, 18, 0)}, // Friday evening to Monday
+		{"0 18 * * 1-5", at(8, 18, 0), at(9, 18, 0)}, // strictly after
+		{"0 0 1,15 * 0", at(2, 0, 0), at(7, 0, 0)},   // either day field
+		{"0 0 * * 7", at(2, 0, 0), at(7, 0, 0)},      // 7 is Sunday
+		{"5 4 29 2 *", at(2, 0, 0), time.Date(2024, 2, 29, 4, 5, 0, 0, time.UTC)},
+		{"0 12 31 2 *", at(2, 0, 0), time.Time{}},
+	} {
+		s, err := parseCron(c.expr)
+		if err != nil {
+			t.Fatalf("%q: %v", c.expr, err)
+		}
+		if got := s.next(c.from); !got.Equal(c.want) {
+			t.Errorf("%q after %v is %v, want %v", c.expr, c.from, got, c.want)
+		}
+	}
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "* * 0 * *", "a * * * *"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parsed %q", expr)
+		}
+	}
+}
+
+func TestWindowsAt(t *testing.T) {
+	windows, err := parseWindows([]RecordingWindow{{Start: "0 18 * * *", Stop: "0 6 * * *"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if open, next := windowsAt(windows, day.Add(12*time.Hour)); open || !next.Equal(day.Add(18*time.Hour)) {
+		t.Errorf("at noon open is %v until %v", open, next)
+	}
+	if open, next := windowsAt(windows, day.Add(23*time.Hour)); !open || !next.Equal(day.Add(30*time.Hour)) {
+		t.Errorf("at 11pm open is %v until %v", open, next)
+	}
+	if _, err := parseWindows([]RecordingWindow{{Start: "0 6 * * *", Stop: "0  6 * * *"}}); err == nil {
+		t.Error("parsed a window that stops as it starts")
+	}
+}
+
+func TestRecordingSchedule(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(day.Add(17*time.Hour + 59*time.Minute + 30*time.Second))
+	src := newBurstSource(10, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	cfg := ScheduleConfig{
+		Windows:   []RecordingWindow{{Start: "0 18 * * *", Stop: "0 6 * * *"}},
+		Location:  time.UTC,
+		Recording: RecordingConfig{Dir: t.TempDir()},
+		Clock:     clock,
+	}
+	s, err := ScheduleRecording(src, cfg, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForSleeper(t, clock, day.Add(18*time.Hour))
+	if st := s.Status(); st.Recording || !st.Next.Equal(day.Add(18*time.Hour)) {
+		t.Errorf("before the window recording is %v until %v", st.Recording, st.Next)
+	}
+	clock.Advance(30 * time.Second)
+	waitForSleeper(t, clock, day.Add(30*time.Hour))
+	if st := s.Status(); !st.Recording || !st.Next.Equal(day.Add(30*time.Hour)) {
+		t.Errorf("in the window recording is %v until %v", st.Recording, st.Next)
+	}
+	clock.Advance(12 * time.Hour)
+	waitForSleeper(t, clock, day.Add(42*time.Hour))
+	if st := s.Status(); st.Recording {
+		t.Error("still recording after the window")
+	}
+	if _, err := s.Cancel(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// a window that is open already records at once
+	clock = NewManualClock(day.Add(23 * time.Hour))
+	cfg.Clock = clock
+	if s, err = ScheduleRecording(src, cfg, logging.NewTestLogger(t)); err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+	// the source ends after 10 chunks, so it is retried
+	waitForSleeper(t, clock, day.Add(23*time.Hour+scheduleRetry))
+	segments, err := s.Cancel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 1 || segments[0].Duration != 100*time.Millisecond || !segments[0].Complete {
+		t.Errorf("wrote %+v, want one 100ms segment", segments)
+	}
+	if _, err := ScheduleRecording(src, ScheduleConfig{Windows: cfg.Windows}, logging.NewTestLogger(t)); err == nil {
+		t.Error("scheduled a recording without a directory")
+	}
+}
+
+func TestScheduleRecordingRPC(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = t.TempDir()
+	c := serveAudio(t, newBurstSource(1, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})).(RecordingScheduler)
+
+	opts := RecordingScheduleOptions{Windows: []RecordingWindow{{Start: "0 0 1 1 *", Stop: "0 0 2 1 *"}}, TimeZone: "UTC"}
+	id, next, err := c.ScheduleRecording(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Month() != time.January || next.Day() > 2 || next.Hour() != 0 {
+		t.Errorf("next changes at %v", next)
+	}
+	if _, err := c.CancelScheduledRecording(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CancelScheduledRecording(ctx, id); err == nil {
+		t.Error("cancelled a schedule twice")
+	}
+	opts.Windows[0].Stop = "0 25 * * *"
+	if _, _, err := c.ScheduleRecording(ctx, opts); err == nil {
+		t.Error("scheduled a window stopping at hour 25")
+	}
+}
@@ -0,0 +1,191 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"go.viam.com/rdk/resource"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// DeviceResetter is implemented by resources whose devices can be closed
+// and opened again without rebuilding them, as the pcm backends' can.
+type DeviceResetter interface {
+	// ResetDevice opens the devices again and reports which were open.
+	ResetDevice(ctx context.Context) (capture, playback bool, err error)
+}
+
+// doServerCommand handles the commands the server answers for every
+// resource, about its own state, and reports whether cmd was one of them:
+//
+//	{"dump_state": true}
+//	{"list_sessions": true}
+//	{"reset_device": true}
+//	{"set_log_level": {"level": "debug", "seconds": 600, "chunks": true}}
+//
+// dump_state returns the resource's stats, latency, streams in progress and
+// shared captures; list_sessions its recordings, recording sessions and
+// resumable streams. reset_device opens the resource's devices again, which
+// streams in progress carry on through with a gap, and fails with
+// ErrUnsupported on resources that aren't DeviceResetters. set_log_level
+// changes the log level of the server, its subloggers and its resources
+// without a restart, for seconds if given and otherwise until it is set
+// again, and returns the level; chunks turns logging every chunk sent of
+// the resource on or off, on for at most 5 minutes.
+func (s *audioServer) doServerCommand(ctx context.Context, name string, a Audio, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	switch {
+	case commandFlag(cmd, "dump_state"):
+		return s.dumpState(name, a), true, nil
+	case commandFlag(cmd, "list_sessions"):
+		return s.listSessions(name), true, nil
+	case commandFlag(cmd, "reset_device"):
+		r, ok := a.(DeviceResetter)
+		if !ok {
+			return nil, true, errorf(ErrUnsupported, "%s can't reset its device", name)
+		}
+		capture, playback, err := r.ResetDevice(ctx)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{"capture": capture, "playback": playback}, true, nil
+	case cmd["set_log_level"] != nil:
+		params, err := commandParams(cmd, "set_log_level")
+		if err != nil {
+			return nil, true, err
+		}
+		resp, err := setLogLevelCommand(name, params)
+		return resp, true, err
+	default:
+		return nil, false, nil
+	}
+}
+
+func (s *audioServer) dumpState(name string, a Audio) map[string]interface{} {
+	stats := s.stats(name, a)
+	latency := s.latency.get(name).Stats()
+	streams := []interface{}{}
+	for _, e := range sharedMetrics.activeStreams(name) {
+		stream := map[string]interface{}{
+			"direction":  e.Entry.Direction,
+			"codec":      e.Entry.Codec,
+			"profile":    e.Entry.Profile,
+			"request_id": e.Entry.RequestID,
+			"subject":    e.Entry.Subject,
+			"start":      formatTime(e.Entry.Start),
+			"bytes":      e.Entry.Bytes,
+			"messages":   e.Entry.Messages,
+			"dropped":    e.Entry.Dropped,
+		}
+		if e.Entry.RequestID != "" {
+			if st, err := s.streams.get(name, e.Entry.RequestID); err == nil {
+				stream["paused"] = st.isPaused()
+			}
+		}
+		streams = append(streams, stream)
+	}
+	return map[string]interface{}{
+		"resource": name,
+		"type":     fmt.Sprintf("%T", a),
+		"stats": map[string]interface{}{
+			"uptime_seconds":  stats.Uptime.Seconds(),
+			"active_streams":  stats.ActiveStreams,
+			"active_plays":    stats.ActivePlays,
+			"active_sessions": stats.ActiveSessions,
+			"bytes_captured":  stats.BytesCaptured,
+			"bytes_played":    stats.BytesPlayed,
+			"dropped_chunks":  stats.DroppedChunks,
+			"overruns":        stats.Overruns,
+			"underruns":       stats.Underruns,
+			"last_error":      stats.LastError,
+			"last_error_time": formatTime(stats.LastErrorAt),
+		},
+		"latency": map[string]interface{}{
+			"chunks":      latency.Chunks,
+			"p50_seconds": latency.P50.Seconds(),
+			"p99_seconds": latency.P99.Seconds(),
+			"max_seconds": latency.Max.Seconds(),
+		},
+		"streams":       streams,
+		"captures":      s.hub.captures(a),
+		"plays_waiting": max(s.playback.users(name)-1, 0),
+		"chunk_logging": sharedChunkLogging.enabled(name),
+		"draining":      s.drain.draining(),
+	}
+}
+
+func (s *audioServer) listSessions(name string) map[string]interface{} {
+	recordings := []interface{}{}
+	all := s.recordings.list(name)
+	for _, id := range slices.Sorted(maps.Keys(all)) {
+		rec := all[id]
+		recording := map[string]interface{}{"id": id, "active": !isDone(rec.Done()), "segments": len(rec.Segments())}
+		if err := rec.Err(); err != nil {
+			recording["error"] = err.Error()
+		}
+		recordings = append(recordings, recording)
+	}
+	sessions := []interface{}{}
+	allSessions := s.sessions.list(name)
+	for _, id := range slices.Sorted(maps.Keys(allSessions)) {
+		status := allSessions[id].Status()
+		tracks := []interface{}{}
+		for _, t := range status.Tracks {
+			tracks = append(tracks, map[string]interface{}{"name": t.Name, "resource": t.Resource, "segments": len(t.Segments), "error": t.Err})
+		}
+		sessions = append(sessions, map[string]interface{}{"id": id, "active": status.Active, "start": formatTime(status.Start), "tracks": tracks})
+	}
+	return map[string]interface{}{
+		"recordings":         recordings,
+		"recording_sessions": sessions,
+		"resumable_streams":  s.resumes.list(name),
+	}
+}
+
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatTime formats t for command results, empty if t is zero.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func (s *audioServer) DoCommand(ctx context.Context, req *pb.DoCommandRequest) (*pb.DoCommandResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	var cmd map[string]interface{}
+	if err := json.Unmarshal([]byte(req.CommandJson), &cmd); err != nil {
+		return nil, errorf(ErrInvalidArgument, "invalid command: %w", err)
+	}
+	result, ok, err := s.doServerCommand(ctx, req.Name, a, cmd)
+	if !ok {
+		result, err = a.DoCommand(ctx, cmd)
+		if errors.Is(err, resource.ErrDoUnimplemented) {
+			err = errorf(ErrUnsupported, "%w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode the result of %s's command: %w", req.Name, err)
+	}
+	return &pb.DoCommandResponse{ResultJson: string(data)}, nil
+}
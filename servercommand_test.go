@@ -0,0 +1,198 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestServerCommands(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("commanded"), FakeConfig{}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	c := serveAudio(t, f)
+
+	streamCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	ch, err := c.GetAudio(streamCtx, "pcm16", 0, 0, 0, WithRequestID("dumped"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	state, err := c.DoCommand(ctx, map[string]interface{}{"dump_state": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	streams, _ := state["streams"].([]interface{})
+	captures, _ := state["captures"].([]interface{})
+	if state["resource"] != "commanded" || len(streams) != 1 || len(captures) != 1 {
+		t.Fatalf("dumped %v", state)
+	}
+	if stream := streams[0].(map[string]interface{}); stream["request_id"] != "dumped" || stream["paused"] != false {
+		t.Errorf("dumped stream %v", stream)
+	}
+	if stats := state["stats"].(map[string]interface{}); stats["active_streams"] != 1.0 {
+		t.Errorf("dumped stats %v", stats)
+	}
+
+	sessions, err := c.DoCommand(ctx, map[string]interface{}{"list_sessions": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"recordings", "recording_sessions", "resumable_streams"} {
+		if list, ok := sessions[key].([]interface{}); !ok || len(list) != 0 {
+			t.Errorf("%s listed as %v", key, sessions[key])
+		}
+	}
+
+	// the fake's own commands go through to it
+	if _, err := c.DoCommand(ctx, map[string]interface{}{"plays": true}); err != nil {
+		t.Error(err)
+	}
+	if _, err := c.DoCommand(ctx, map[string]interface{}{"reset_device": true}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("resetting the fake's device failed with %v", err)
+	}
+	if _, err := c.DoCommand(ctx, map[string]interface{}{"no_such_command": true}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("an unknown command failed with %v", err)
+	}
+	// a command given as false isn't run
+	if state, err := c.DoCommand(ctx, map[string]interface{}{"dump_state": false}); state["resource"] != nil || !errors.Is(err, ErrUnsupported) {
+		t.Errorf("dump_state false answered %v, %v", state, err)
+	}
+}
+
+func TestResetDeviceCommand(t *testing.T) {
+	opened := useFakePCM(t, nil)
+	a, err := NewALSA(Named("reset-mic"), ALSAConfig{CaptureDevice: "hw:1,0", PlaybackDevice: "hw:0,0", SampleRate: 8000, PeriodFrames: 80}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	c := serveAudio(t, a)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch, err := c.GetAudio(ctx, Pcm16.String(), 0.2, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	first := opened["hw:1,0"]
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"reset_device": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp["capture"] != true || resp["playback"] != false {
+		t.Errorf("reset %v", resp)
+	}
+	// the stream carries on from the device opened again
+	n := 1
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		n++
+	}
+	if n != 20 {
+		t.Errorf("got %d chunks, want 20", n)
+	}
+	first.mu.Lock()
+	closed := first.closed
+	first.mu.Unlock()
+	if !closed || opened["hw:1,0"] == first {
+		t.Error("the capture device wasn't opened again")
+	}
+}
+
+func TestSetLogLevelCommand(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("verbose"), FakeConfig{}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	c := serveAudio(t, f)
+	was := serverLogger.GetLevel()
+	defer serverVerbosity.set(was, 0)
+
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"set_log_level": map[string]interface{}{"level": "debug", "seconds": 0.2, "chunks": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp["level"] != "debug" || resp["until"] == "" || resp["chunks_until"] == "" {
+		t.Errorf("set %v", resp)
+	}
+	if serverLogger.GetLevel() != logging.DEBUG || !sharedChunkLogging.enabled("verbose") {
+		t.Error("debug logging isn't on")
+	}
+	// the level goes back once the time is up
+	for deadline := time.Now().Add(5 * time.Second); serverLogger.GetLevel() != was; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the log level didn't go back")
+		}
+	}
+
+	if resp, err = c.DoCommand(ctx, map[string]interface{}{"set_log_level": map[string]interface{}{"level": "warn", "chunks": false}}); err != nil {
+		t.Fatal(err)
+	}
+	if resp["level"] != "warn" || resp["until"] != "" || sharedChunkLogging.enabled("verbose") {
+		t.Errorf("set %v", resp)
+	}
+	if resp, err = c.DoCommand(ctx, map[string]interface{}{"set_log_level": true}); err != nil || resp["level"] != "warn" {
+		t.Errorf("got %v, %v", resp, err)
+	}
+	if _, err := c.DoCommand(ctx, map[string]interface{}{"set_log_level": map[string]interface{}{"level": "loud"}}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("an unknown level failed with %v", err)
+	}
+}
+
+func TestSetLogLevelReachesEveryLogger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// the server's own logger observed, to see what its subloggers log
+	server, serverLogs := logging.NewObservedTestLogger(t)
+	server.SetLevel(logging.INFO)
+	defer func(v *logVerbosity) { serverVerbosity = v }(serverVerbosity)
+	serverVerbosity = &logVerbosity{logger: server, loggers: map[string]logging.Logger{}}
+
+	reg, ok := resource.LookupRegistration(API, FakeModel)
+	if !ok {
+		t.Fatal("the fake model isn't registered")
+	}
+	logger, logs := logging.NewObservedTestLogger(t)
+	logger.SetLevel(logging.INFO)
+	res, err := reg.Constructor(ctx, nil, resource.Config{Name: "quiet", API: API, Model: FakeModel, ConvertedAttributes: &FakeConfig{}}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close(ctx)
+	c := serveAudio(t, res.(Audio))
+	sub := serverVerbosity.sublogger("http")
+	logger.Debug("resource before")
+	sub.Debug("sublogger before")
+
+	if _, err := c.DoCommand(ctx, map[string]interface{}{"set_log_level": map[string]interface{}{"level": "debug"}}); err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("resource after")
+	sub.Debug("sublogger after")
+	if logs.FilterMessageSnippet("before").Len() != 0 || logs.FilterMessageSnippet("resource after").Len() != 1 {
+		t.Errorf("the resource logged %v", logs.All())
+	}
+	if serverLogs.FilterMessageSnippet("before").Len() != 0 || serverLogs.FilterMessageSnippet("sublogger after").Len() != 1 {
+		t.Errorf("the sublogger logged %v", serverLogs.All())
+	}
+	// subloggers made afterwards start at the level
+	if level := serverVerbosity.sublogger("rtsp").GetLevel(); level != logging.DEBUG {
+		t.Errorf("a new sublogger is at %v", level)
+	}
+}
@@ -0,0 +1,542 @@
+package audio
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Track is one track of a recording session.
+type Track struct {
+	Source Audio
+	// Channels are the zero based channels of Source's capture the track
+	// keeps, all if empty, so each microphone of an array can be a track.
+	Channels []int
+	// Name prefixes the track's segment files. It defaults to the source's
+	// name, followed by its channels if they are set, as in "array-ch0_1".
+	Name string
+}
+
+// TrackStatus is the state of a track of a recording session.
+type TrackStatus struct {
+	Name     string
+	Resource string
+	Channels []int
+	Segments []RecordingSegment
+	// Offset is the capture time of the track's first sample after the
+	// session start, zero until it is recorded.
+	Offset time.Duration
+	Err    string // why the track ended on its own, if it did
+}
+
+// SessionStatus is the state of a recording session.
+type SessionStatus struct {
+	Start  time.Time     // the origin of the tracks' offsets
+	Tracks []TrackStatus // in the order the tracks were given
+	Active bool
+}
+
+// RecordingSession records several tracks at once, each to its own
+// segments, on a shared timeline. Each source is captured once for all its
+// tracks, so tracks split from one source stay sample aligned; tracks of
+// different sources are aligned by their offsets.
+type RecordingSession struct {
+	start  time.Time
+	cancel context.CancelFunc
+	tracks []*sessionTrack
+	done   chan struct{}
+}
+
+type sessionTrack struct {
+	name     string
+	resource string
+	channels []int
+	rec      *Recording
+	in       chan *AudioChunk
+}
+
+// StartRecordingSession records tracks in cfg.Dir until Stop is called or
+// their captures end.
+func StartRecordingSession(ctx context.Context, tracks []Track, cfg RecordingConfig, logger logging.Logger) (*RecordingSession, error) {
+	if len(tracks) == 0 {
+		return nil, errors.New("a recording session needs a track")
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	s := &RecordingSession{start: time.Now(), done: make(chan struct{})}
+	sources := map[resource.Name]Audio{}
+	names := map[string]bool{}
+	for i, t := range tracks {
+		if t.Source == nil {
+			return nil, fmt.Errorf("track %d has no source", i)
+		}
+		for _, c := range t.Channels {
+			if c < 0 {
+				return nil, fmt.Errorf("track %d: invalid channel %d", i, c)
+			}
+		}
+		name := t.Name
+		if name == "" {
+			name = trackName(t.Source.Name().ShortName(), t.Channels)
+		}
+		if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+			return nil, errorf(ErrInvalidArgument, "invalid track name %q", name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("two tracks are named %q", name)
+		}
+		names[name] = true
+		sources[t.Source.Name()] = t.Source
+		s.tracks = append(s.tracks, &sessionTrack{name: name, resource: t.Source.Name().ShortName(), channels: t.Channels, in: make(chan *AudioChunk)})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	abort := func() {
+		cancel()
+		for _, t := range s.tracks {
+			if t.rec != nil {
+				t.rec.abort()
+			}
+		}
+	}
+	for _, t := range s.tracks {
+		if t.rec, err = newRecording(cfg, logger); err != nil {
+			abort()
+			return nil, err
+		}
+	}
+	captures := map[resource.Name]<-chan *AudioChunk{}
+	for name, a := range sources {
+		if captures[name], err = sharedCaptureHub.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm16}}); err != nil {
+			abort()
+			return nil, err
+		}
+	}
+	s.cancel = cancel
+	for _, t := range s.tracks {
+		t.rec.start(t.in, t.name, cfg, cancel)
+	}
+	for name, chunks := range captures {
+		var split []*sessionTrack
+		for i, t := range tracks {
+			if t.Source.Name() == name {
+				split = append(split, s.tracks[i])
+			}
+		}
+		go splitTracks(chunks, split)
+	}
+	go func() {
+		defer close(s.done)
+		for _, t := range s.tracks {
+			<-t.rec.Done()
+		}
+		// the captures stop with the last track, if it failed
+		cancel()
+	}()
+	return s, nil
+}
+
+// trackName is the default name of a track of source's channels.
+func trackName(source string, channels []int) string {
+	if len(channels) == 0 {
+		return source
+	}
+	s := make([]string, len(channels))
+	for i, c := range channels {
+		s[i] = strconv.Itoa(c)
+	}
+	return source + "-ch" + strings.Join(s, "_")
+}
+
+// splitTracks hands each chunk of a source's capture to its tracks, with
+// only their channels. Chunks without a capture time are stamped here, so
+// the tracks share it.
+func splitTracks(chunks <-chan *AudioChunk, tracks []*sessionTrack) {
+	defer func() {
+		for _, t := range tracks {
+			close(t.in)
+		}
+	}()
+	var next time.Time // where the previous chunk ended
+	for chunk := range chunks {
+		if chunk.Err == nil && chunk.Timestamp.IsZero() {
+			stamped := *chunk
+			if next.IsZero() {
+				stamped.Timestamp = time.Now()
+			} else {
+				stamped.Timestamp = next.Add(chunk.Gap)
+			}
+			chunk = &stamped
+		}
+		if d, ok := chunkDuration(chunk); ok && !chunk.Timestamp.IsZero() {
+			next = chunk.Timestamp.Add(d)
+		}
+		for _, t := range tracks {
+			out := chunk
+			if len(t.channels) > 0 && chunk.Err == nil {
+				out = pickChannels(chunk, t.channels)
+			}
+			// a track that failed stops taking chunks
+			select {
+			case t.in <- out:
+			case <-t.rec.Done():
+			}
+		}
+	}
+}
+
+// pickChannels returns the channels of a pcm16 chunk, in the order given.
+func pickChannels(chunk *AudioChunk, channels []int) *AudioChunk {
+	if chunk.Info == nil || chunk.Info.Channels == 0 {
+		return chunk
+	}
+	from := chunk.Info.Channels
+	for _, c := range channels {
+		if c >= from {
+			return &AudioChunk{Err: fmt.Errorf("the capture has %d channels, no channel %d", from, c)}
+		}
+	}
+	frames := len(chunk.AudioData) / (2 * from)
+	data := make([]byte, 0, frames*2*len(channels))
+	for f := range frames {
+		frame := chunk.AudioData[f*2*from:]
+		for _, c := range channels {
+			data = append(data, frame[2*c], frame[2*c+1])
+		}
+	}
+	out := *chunk
+	info := *chunk.Info
+	info.Channels = len(channels)
+	out.AudioData, out.Info = data, &info
+	return &out
+}
+
+// Status returns the state of the session's tracks.
+func (s *RecordingSession) Status() SessionStatus {
+	status := SessionStatus{Start: s.start}
+	for _, t := range s.tracks {
+		ts := TrackStatus{Name: t.name, Resource: t.resource, Channels: t.channels, Segments: t.rec.Segments()}
+		if len(ts.Segments) > 0 {
+			ts.Offset = ts.Segments[0].Start.Sub(s.start)
+		}
+		if err := t.rec.Err(); err != nil {
+			ts.Err = err.Error()
+		}
+		select {
+		case <-t.rec.Done():
+		default:
+			status.Active = true
+		}
+		status.Tracks = append(status.Tracks, ts)
+	}
+	return status
+}
+
+// Stop ends every track and waits for their segments to be uploaded or ctx
+// to be done. Errors that ended tracks on their own are in Status; Stop
+// returns those of finishing the tracks.
+func (s *RecordingSession) Stop(ctx context.Context) error {
+	s.cancel()
+	var errs []error
+	for _, t := range s.tracks {
+		if err := t.rec.Stop(ctx); err != nil && t.rec.Err() == nil {
+			errs = append(errs, fmt.Errorf("track %s: %w", t.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Done is closed once every track has ended.
+func (s *RecordingSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// TrackOptions is a track of a recording session started through the
+// server, see Track.
+type TrackOptions struct {
+	Resource string // the client's resource if empty
+	Channels []int
+	Name     string
+}
+
+// SessionOptions configures a recording session started through the server.
+// Its segments go to the server's recording store.
+type SessionOptions struct {
+	Tracks          []TrackOptions
+	SegmentDuration time.Duration // one minute if zero
+	Format          string        // "wav" (the default) or "flac"
+}
+
+// SessionRecorder is implemented by clients of servers that record several
+// resources or channels at once, see StartRecordingSession.
+type SessionRecorder interface {
+	// StartRecordingSession starts a session and returns its ID.
+	StartRecordingSession(ctx context.Context, opts SessionOptions) (string, error)
+	// StopRecordingSession stops a session and returns its final state.
+	StopRecordingSession(ctx context.Context, id string) (SessionStatus, error)
+	// GetRecordingSession returns the state of a session, which can be
+	// read for an hour after it ends.
+	GetRecordingSession(ctx context.Context, id string) (SessionStatus, error)
+}
+
+// managedSession is a recording session the server started for a resource.
+type managedSession struct {
+	resource string
+	session  *RecordingSession
+	ended    time.Time // when the server saw it had ended
+}
+
+// serverSessions holds the recording sessions the server started, by ID.
+type serverSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*managedSession
+}
+
+func newServerSessions() *serverSessions {
+	return &serverSessions{sessions: map[string]*managedSession{}}
+}
+
+// add stores session and returns its ID, dropping sessions that ended long
+// enough ago.
+func (m *serverSessions) add(resource string, session *RecordingSession) string {
+	id := strings.ToLower(rand.Text())
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, s := range m.sessions {
+		if s.ended.IsZero() {
+			select {
+			case <-s.session.Done():
+				s.ended = now
+			default:
+			}
+		} else if now.Sub(s.ended) > finishedRecordingTTL {
+			delete(m.sessions, id)
+		}
+	}
+	m.sessions[id] = &managedSession{resource: resource, session: session}
+	return id
+}
+
+// get returns the session id of resource.
+func (m *serverSessions) get(resource, id string) (*managedSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok || s.resource != resource {
+		return nil, fmt.Errorf("no recording session %q of %s", id, resource)
+	}
+	return s, nil
+}
+
+func (m *serverSessions) markEnded(s *managedSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s.ended.IsZero() {
+		s.ended = time.Now()
+	}
+}
+
+// list returns resource's sessions by ID.
+func (m *serverSessions) list(resource string) map[string]*RecordingSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sessions := map[string]*RecordingSession{}
+	for id, s := range m.sessions {
+		if s.resource == resource {
+			sessions[id] = s.session
+		}
+	}
+	return sessions
+}
+
+// active returns how many of resource's sessions are still running.
+func (m *serverSessions) active(resource string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, s := range m.sessions {
+		if s.resource != resource || !s.ended.IsZero() {
+			continue
+		}
+		select {
+		case <-s.session.Done():
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// stopAll stops every session still running.
+func (m *serverSessions) stopAll(ctx context.Context) error {
+	m.mu.Lock()
+	sessions := make([]*managedSession, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.Unlock()
+	var errs []error
+	for _, s := range sessions {
+		if err := s.session.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("session of %s: %w", s.resource, err))
+		}
+		m.markEnded(s)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *audioServer) StartRecordingSession(ctx context.Context, req *pb.StartRecordingSessionRequest) (*pb.StartRecordingSessionResponse, error) {
+	if _, err := s.resource(req.Name); err != nil {
+		return nil, err
+	}
+	if ServerRecordings.Dir == "" {
+		return nil, errNoRecordingStore
+	}
+	tracks := make([]Track, len(req.Tracks))
+	for i, t := range req.Tracks {
+		name := t.Resource
+		if name == "" {
+			name = req.Name
+		}
+		src, err := s.resource(name)
+		if err != nil {
+			return nil, err
+		}
+		tracks[i] = Track{Source: src, Name: t.Name}
+		for _, c := range t.Channels {
+			tracks[i].Channels = append(tracks[i].Channels, int(c))
+		}
+	}
+	cfg := RecordingConfig{
+		Dir:             ServerRecordings.Dir,
+		SegmentDuration: time.Duration(req.SegmentSeconds * float64(time.Second)),
+		Format:          req.Format,
+	}
+	if cfg.SegmentDuration < 0 {
+		return nil, errorf(ErrInvalidArgument, "segment length cannot be negative, got %gs", req.SegmentSeconds)
+	}
+	// the session outlives the call
+	session, err := StartRecordingSession(context.Background(), tracks, cfg, serverVerbosity.sublogger("recording"))
+	if err != nil {
+		return nil, err
+	}
+	id := s.sessions.add(req.Name, session)
+	if err := s.audit(ctx, req.Name, AuditSessionStarted, id, fmt.Sprintf("%d tracks", len(tracks)), nil); err != nil {
+		// nothing is recorded without a record of who asked for it
+		session.Stop(ctx)
+		if m, gerr := s.sessions.get(req.Name, id); gerr == nil {
+			s.sessions.markEnded(m)
+		}
+		return nil, err
+	}
+	s.logger.Infow("recording session started", "resource", req.Name, "session_id", id, "tracks", len(tracks))
+	return &pb.StartRecordingSessionResponse{SessionId: id}, nil
+}
+
+func (s *audioServer) StopRecordingSession(ctx context.Context, req *pb.StopRecordingSessionRequest) (*pb.StopRecordingSessionResponse, error) {
+	m, err := s.sessions.get(req.Name, req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.session.Stop(ctx); err != nil {
+		return nil, err
+	}
+	s.sessions.markEnded(m)
+	s.audit(ctx, req.Name, AuditSessionStopped, req.SessionId, "", nil)
+	s.logger.Infow("recording session stopped", "resource", req.Name, "session_id", req.SessionId)
+	return &pb.StopRecordingSessionResponse{Session: sessionToProto(m.session.Status())}, nil
+}
+
+func (s *audioServer) GetRecordingSession(ctx context.Context, req *pb.GetRecordingSessionRequest) (*pb.GetRecordingSessionResponse, error) {
+	m, err := s.sessions.get(req.Name, req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetRecordingSessionResponse{Session: sessionToProto(m.session.Status())}, nil
+}
+
+func sessionToProto(status SessionStatus) *pb.RecordingSession {
+	out := &pb.RecordingSession{StartTimestampNanoseconds: status.Start.UnixNano(), Active: status.Active}
+	for _, t := range status.Tracks {
+		track := &pb.RecordingTrack{Resource: t.Resource, Name: t.Name}
+		for _, c := range t.Channels {
+			track.Channels = append(track.Channels, int32(c))
+		}
+		out.Tracks = append(out.Tracks, &pb.TrackStatus{
+			Track:             track,
+			Segments:          segmentsToProto(t.Segments),
+			OffsetNanoseconds: int64(t.Offset),
+			Error:             t.Err,
+		})
+	}
+	return out
+}
+
+func sessionFromProto(session *pb.RecordingSession) SessionStatus {
+	out := SessionStatus{Start: time.Unix(0, session.GetStartTimestampNanoseconds()), Active: session.GetActive()}
+	for _, t := range session.GetTracks() {
+		ts := TrackStatus{
+			Name:     t.GetTrack().GetName(),
+			Resource: t.GetTrack().GetResource(),
+			Segments: segmentsFromProto(t.Segments),
+			Offset:   time.Duration(t.OffsetNanoseconds),
+			Err:      t.Error,
+		}
+		for _, c := range t.GetTrack().GetChannels() {
+			ts.Channels = append(ts.Channels, int(c))
+		}
+		out.Tracks = append(out.Tracks, ts)
+	}
+	return out
+}
+
+func (c *audioClient) StartRecordingSession(ctx context.Context, opts SessionOptions) (string, error) {
+	req := &pb.StartRecordingSessionRequest{
+		Name:           c.name,
+		SegmentSeconds: opts.SegmentDuration.Seconds(),
+		Format:         opts.Format,
+	}
+	for _, t := range opts.Tracks {
+		track := &pb.RecordingTrack{Resource: t.Resource, Name: t.Name}
+		for _, ch := range t.Channels {
+			track.Channels = append(track.Channels, int32(ch))
+		}
+		req.Tracks = append(req.Tracks, track)
+	}
+	resp, err := c.client.StartRecordingSession(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.SessionId, nil
+}
+
+func (c *audioClient) StopRecordingSession(ctx context.Context, id string) (SessionStatus, error) {
+	resp, err := c.client.StopRecordingSession(ctx, &pb.StopRecordingSessionRequest{Name: c.name, SessionId: id})
+	if err != nil {
+		return SessionStatus{}, err
+	}
+	return sessionFromProto(resp.Session), nil
+}
+
+func (c *audioClient) GetRecordingSession(ctx context.Context, id string) (SessionStatus, error) {
+	resp, err := c.client.GetRecordingSession(ctx, &pb.GetRecordingSessionRequest{Name: c.name, SessionId: id})
+	if err != nil {
+		return SessionStatus{}, err
+	}
+	return sessionFromProto(resp.Session), nil
+}
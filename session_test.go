@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPickChannels(t *testing.T) {
+	// frames of three channels: 1 2 3, 4 5 6
+	data, _ := encodePCM([]float32{0.1, 0.2, 0.3, 0.4, 0.5, 0.6}, Pcm16)
+	chunk := &AudioChunk{AudioData: data, Info: &AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 3}}
+	want, _ := encodePCM([]float32{0.3, 0.1, 0.6, 0.4}, Pcm16)
+	out := pickChannels(chunk, []int{2, 0})
+	if out.Err != nil || out.Info.Channels != 2 || !bytes.Equal(out.AudioData, want) {
+		t.Errorf("picked %+v", out)
+	}
+	if chunk.Info.Channels != 3 {
+		t.Error("changed the source chunk")
+	}
+	if out = pickChannels(chunk, []int{3}); out.Err == nil {
+		t.Error("picked a channel the capture doesn't have")
+	}
+}
+
+func TestRecordingSession(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = t.TempDir()
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 2})
+	c := serveAudio(t, src).(SessionRecorder)
+
+	if _, err := c.StartRecordingSession(ctx, SessionOptions{Tracks: []TrackOptions{{}, {Channels: []int{0, 1}, Name: "burst"}}}); err == nil {
+		t.Error("started two tracks with the same name")
+	}
+	id, err := c.StartRecordingSession(ctx, SessionOptions{Tracks: []TrackOptions{{}, {Channels: []int{1}}, {Channels: []int{2}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+
+	var status SessionStatus
+	for status.Active = true; status.Active && ctx.Err() == nil; time.Sleep(time.Millisecond) {
+		if status, err = c.GetRecordingSession(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if status, err = c.StopRecordingSession(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Tracks) != 3 || status.Start.IsZero() {
+		t.Fatalf("status %+v, want three tracks", status)
+	}
+	for i, want := range []struct {
+		name     string
+		channels int
+	}{{"burst", 2}, {"burst-ch1", 1}} {
+		track := status.Tracks[i]
+		if track.Name != want.name || track.Resource != "burst" || track.Err != "" || len(track.Segments) != 1 {
+			t.Errorf("track %d is %+v, want %s with one segment", i, track, want.name)
+			continue
+		}
+		seg := track.Segments[0]
+		if !seg.Complete || seg.Duration != 500*time.Millisecond || track.Offset < 0 {
+			t.Errorf("track %s recorded %+v at %v, want 500ms", track.Name, seg, track.Offset)
+		}
+		_, info, err := readAudioFile(filepath.Join(ServerRecordings.Dir, seg.Path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Channels != want.channels {
+			t.Errorf("track %s has %d channels, want %d", track.Name, info.Channels, want.channels)
+		}
+	}
+	// tracks of one capture share their timeline exactly
+	if a, b := status.Tracks[0], status.Tracks[1]; len(a.Segments) > 0 && len(b.Segments) > 0 && a.Offset != b.Offset {
+		t.Errorf("tracks of one source start %v and %v into the session", a.Offset, b.Offset)
+	}
+	if track := status.Tracks[2]; track.Err == "" {
+		t.Errorf("track %+v of a channel the source doesn't have didn't fail", track)
+	}
+}
@@ -0,0 +1,101 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// shutdownGrace is how long the standalone server gives its calls and
+// recordings to finish once it is asked to stop, before cutting them off.
+const shutdownGrace = 10 * time.Second
+
+// serverDrain is set once the server starts shutting down.
+type serverDrain struct {
+	ctx    context.Context // done once draining
+	cancel context.CancelFunc
+}
+
+func newServerDrain() *serverDrain {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &serverDrain{ctx: ctx, cancel: cancel}
+}
+
+func (d *serverDrain) start() {
+	d.cancel()
+}
+
+func (d *serverDrain) draining() bool {
+	return d.ctx.Err() != nil
+}
+
+// drainOptions has the server refuse calls once it is draining, and end
+// the streams it runs with ErrShuttingDown. The calls already running that
+// aren't streams, such as Play, finish as usual.
+func (s *audioServer) drainOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if s.drain.draining() {
+				return nil, ErrShuttingDown
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if s.drain.draining() {
+				return ErrShuttingDown
+			}
+			ctx, cancel := context.WithCancel(ss.Context())
+			defer cancel()
+			stop := context.AfterFunc(s.drain.ctx, cancel)
+			defer stop()
+			err := handler(srv, &drainedStream{ServerStream: ss, ctx: ctx})
+			// the stream ended because of the shutdown rather than its client
+			if s.drain.draining() && ss.Context().Err() == nil {
+				return ErrShuttingDown
+			}
+			return err
+		}),
+	}
+}
+
+// drainedStream is a server stream whose context also ends when the server
+// starts draining.
+type drainedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *drainedStream) Context() context.Context {
+	return s.ctx
+}
+
+// shutdown drains the server: it refuses new calls, ends the running
+// streams, which send what they have batched before they go, and stops the
+// recordings, sessions and schedules it started so their last segments are
+// complete. ctx bounds the uploads of their segments.
+func (s *audioServer) shutdown(ctx context.Context) error {
+	s.drain.start()
+	return errors.Join(
+		s.schedules.cancelAll(ctx),
+		s.sessions.stopAll(ctx),
+		s.recordings.stopAll(ctx),
+	)
+}
+
+// gracefulStop stops gs once the calls it is serving have returned, or cuts
+// them off once ctx is done.
+func gracefulStop(ctx context.Context, gs *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		gs.Stop()
+		<-stopped
+	}
+}
@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+func TestServerShutdown(t *testing.T) {
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	logger := logging.NewTestLogger(t)
+	sim, err := NewSim(Named("sim"), SimConfig{ChunkMs: 10}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close(context.Background())
+
+	coll, err := resource.NewAPIResourceCollection[Audio](API, map[resource.Name]Audio{sim.Name(): sim})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewRPCServiceServer(coll).(*audioServer)
+	gs := grpc.NewServer(server.drainOptions()...)
+	pb.RegisterAudioServiceServer(gs, server)
+	go gs.Serve(lis)
+	defer gs.Stop()
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := NewClientFromConn(&rpc.GrpcOverHTTPClientConn{ClientConn: conn}, "", sim.Name(), logger)
+
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || chunk.Err != nil {
+		t.Fatalf("got %+v", chunk)
+	}
+	id, err := c.(SegmentedRecorder).StartRecording(ctx, RecordingOptions{SegmentDuration: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if err := server.shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// the stream ends with a status its client can retry
+	var last *AudioChunk
+	for chunk := range ch {
+		last = chunk
+	}
+	if last == nil || status.Code(last.Err) != codes.Unavailable {
+		t.Errorf("stream ended with %+v", last)
+	}
+	// the recording is complete
+	r, err := server.recordings.get(sim.Name().ShortName(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segments := r.rec.Segments()
+	if len(segments) == 0 {
+		t.Fatal("recorded nothing")
+	}
+	for _, seg := range segments {
+		if !seg.Complete {
+			t.Errorf("segment %s left incomplete", seg.Path)
+		}
+	}
+	// and nothing new is started
+	if _, err := c.(SegmentedRecorder).ListSegments(ctx, id); status.Code(err) != codes.Unavailable {
+		t.Errorf("call while shutting down returned %v", err)
+	}
+	if ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if chunk := <-ch; chunk == nil || status.Code(chunk.Err) != codes.Unavailable {
+		t.Errorf("stream while shutting down got %+v", chunk)
+	}
+
+	stopCtx, stop := context.WithTimeout(ctx, 2*time.Second)
+	defer stop()
+	gracefulStop(stopCtx, gs)
+	if stopCtx.Err() != nil {
+		t.Error("the server's calls didn't finish after the shutdown")
+	}
+}
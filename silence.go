@@ -0,0 +1,43 @@
+package audio
+
+import "time"
+
+// Defaults for WithSilenceTrim.
+const (
+	defaultSilenceThreshold = -50.0 // dBFS
+	defaultSilenceHangover  = 300 * time.Millisecond
+)
+
+// silenceTrimmer decides which chunks of a stream are silence worth
+// dropping. Quiet chunks are kept until the level has stayed below the
+// threshold for the hangover, so pauses between words survive.
+type silenceTrimmer struct {
+	thresholdDBFS float64
+	hangover      time.Duration
+	quietFor      time.Duration
+}
+
+func newSilenceTrimmer(thresholdDBFS float64, hangover time.Duration) *silenceTrimmer {
+	if thresholdDBFS == 0 {
+		thresholdDBFS = defaultSilenceThreshold
+	}
+	if hangover == 0 {
+		hangover = defaultSilenceHangover
+	}
+	return &silenceTrimmer{thresholdDBFS: thresholdDBFS, hangover: hangover}
+}
+
+// keep reports whether a raw pcm chunk should be delivered.
+func (t *silenceTrimmer) keep(chunk *AudioChunk) (bool, error) {
+	level, err := chunkRMS(chunk)
+	if err != nil {
+		return false, err
+	}
+	if dbfs(level) >= t.thresholdDBFS {
+		t.quietFor = 0
+		return true, nil
+	}
+	dur, _ := chunkDuration(chunk)
+	t.quietFor += dur
+	return t.quietFor <= t.hangover, nil
+}
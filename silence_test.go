@@ -0,0 +1,114 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSilenceTrimmer(t *testing.T) {
+	info := AudioInfo{Format: Pcm32Float, SampleRate: 16000, Channels: 1}
+	chunk := func(level float32) *AudioChunk {
+		samples := make([]float32, 160) // 10ms
+		for i := range samples {
+			samples[i] = level * float32(1-2*(i%2))
+		}
+		data, _ := encodePCM(samples, Pcm32Float)
+		return &AudioChunk{AudioData: data, Info: &info}
+	}
+	tr := newSilenceTrimmer(-40, 30*time.Millisecond)
+	for i, tc := range []struct {
+		level float32
+		want  bool
+	}{
+		{0.1, true},
+		{0.001, true}, // 10ms of hangover
+		{0.001, true},
+		{0.001, true}, // 30ms, the whole hangover
+		{0.001, false},
+		{0, false},
+		{0.02, true}, // -34 dBFS is loud again
+		{0.005, true},
+	} {
+		got, err := tr.keep(chunk(tc.level))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("chunk %d at %v: kept %v, want %v", i, tc.level, got, tc.want)
+		}
+	}
+	if _, err := tr.keep(&AudioChunk{AudioData: make([]byte, 4)}); err == nil {
+		t.Fatal("chunk without a format accepted")
+	}
+
+	defaults := newSilenceTrimmer(0, 0)
+	if defaults.thresholdDBFS != defaultSilenceThreshold || defaults.hangover != defaultSilenceHangover {
+		t.Fatalf("unexpected defaults %+v", defaults)
+	}
+}
+
+// loudThenQuiet makes src send loud chunks for the indexes in [from, to).
+func loudThenQuiet(src *burstSource, from, to int) {
+	n := src.frames
+	src.samples = func(i int) []float32 {
+		samples := make([]float32, n)
+		if i >= from && i < to {
+			for j := range samples {
+				samples[j] = 0.5 * float32(1-2*(j%2))
+			}
+		}
+		return samples
+	}
+}
+
+func TestCaptureHubTrimSilence(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		duration  time.Duration
+		wantAudio time.Duration
+		wantTotal time.Duration // audio and gaps
+	}{
+		// 100ms loud and the 50ms hangover, the rest is reported as a gap
+		{"unlimited", 0, 150 * time.Millisecond, 500 * time.Millisecond},
+		// skipped audio counts against the duration, so the stream ends 300ms in
+		{"limited", 300 * time.Millisecond, 150 * time.Millisecond, 300 * time.Millisecond},
+		{"limit inside the loud part", 120 * time.Millisecond, 120 * time.Millisecond, 120 * time.Millisecond},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 16000, Channels: 1})
+			loudThenQuiet(src, 0, 10)
+			close(src.start)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			ch, err := newCaptureHub().open(ctx, src, captureRequest{
+				target:   AudioInfo{Format: Pcm16, SampleRate: 8000},
+				silence:  newSilenceTrimmer(-40, 50*time.Millisecond),
+				duration: tc.duration,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var last *AudioChunk
+			var audio, gap time.Duration
+			for chunk := range ch {
+				if chunk.Err != nil {
+					t.Fatal(chunk.Err)
+				}
+				d, ok := chunkDuration(chunk)
+				if !ok {
+					t.Fatalf("chunk %+v has no usable format", chunk)
+				}
+				audio += d
+				gap += chunk.Gap
+				last = chunk
+			}
+			if audio != tc.wantAudio || audio+gap != tc.wantTotal {
+				t.Fatalf("got %v of audio and %v of gaps, want %v of audio and %v in total", audio, gap, tc.wantAudio, tc.wantTotal)
+			}
+			if tc.wantTotal > tc.wantAudio && (len(last.AudioData) != 0 || last.Info.SampleRate != 8000 || last.Sequence == 0) {
+				t.Fatalf("trailing gap chunk %+v isn't an empty chunk in the stream's format", last)
+			}
+		})
+	}
+}
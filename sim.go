@@ -0,0 +1,488 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// SimModel is a capture-only Audio for simulation: it renders a scripted
+// scenario of sounds, each with a start time, level and direction, on top of
+// an ambient noise floor, so behaviors driven by audio can be tested end to
+// end in Gazebo or Isaac. Simulators add sounds as they happen with the
+// "trigger" DoCommand.
+var SimModel = resource.NewModel("olivia", "audio", "sim")
+
+// Defaults for SimConfig.
+const (
+	defaultSimSampleRate  = 16000
+	defaultSimNoiseFloor  = -70.0
+	defaultSimArrayRadius = 0.05 // meters
+	defaultSimToneHz      = 1000
+	defaultSimEventLength = time.Second
+	speedOfSound          = 343.0 // meters per second
+)
+
+// SimEvent is one sound in a scenario.
+type SimEvent struct {
+	// AtMs is when the sound starts, from the start of the scenario or, for
+	// triggered sounds, from the trigger.
+	AtMs int `json:"at_ms"`
+	// Sound is "tone", "noise", "chirp" (a 300 Hz to 3 kHz sweep) or "file".
+	Sound       string  `json:"sound"`
+	File        string  `json:"file,omitempty"`         // .wav or .flac for sound "file"
+	FrequencyHz float64 `json:"frequency_hz,omitempty"` // for tones, 1 kHz if zero
+	DurationMs  int     `json:"duration_ms,omitempty"`  // 1s if zero, or the whole file
+	LevelDBFS   float64 `json:"level_dbfs"`             // RMS level at the array
+	// DirectionDeg is the azimuth of the source seen from the array, counter
+	// clockwise from the first microphone.
+	DirectionDeg float64 `json:"direction_deg,omitempty"`
+}
+
+// SimScenario is a timeline of sounds.
+type SimScenario struct {
+	Events []SimEvent `json:"events"`
+	// LoopMs repeats the timeline with this period; zero plays it once.
+	LoopMs int `json:"loop_ms,omitempty"`
+}
+
+// SimConfig is the configuration of the sim model. Channels above one are
+// microphones evenly spaced on a circle, so directions show up as delays
+// between channels.
+type SimConfig struct {
+	SampleRate     int          `json:"sample_rate,omitempty"`      // 16 kHz if zero
+	Channels       int          `json:"channels,omitempty"`         // 1 if zero
+	ArrayRadiusM   float64      `json:"array_radius_m,omitempty"`   // 5cm if zero
+	NoiseFloorDBFS float64      `json:"noise_floor_dbfs,omitempty"` // -70 if zero
+	ChunkMs        int          `json:"chunk_ms,omitempty"`         // 100ms if zero
+	Scenario       *SimScenario `json:"scenario,omitempty"`
+	// ScenarioFile is a JSON SimScenario, used instead of Scenario.
+	ScenarioFile string `json:"scenario_file,omitempty"`
+
+	// Clock paces the simulation, SystemClock if nil. Simulators running
+	// faster or slower than real time pass their own.
+	Clock ClockSource `json:"-"`
+}
+
+// Validate checks the sim configuration.
+func (c *SimConfig) Validate(path string) ([]string, []string, error) {
+	if c.Scenario != nil && c.ScenarioFile != "" {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("set only one of scenario and scenario_file"))
+	}
+	if c.SampleRate < 0 || c.Channels < 0 || c.ChunkMs < 0 || c.ArrayRadiusM < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate, channels, chunk_ms and array_radius_m cannot be negative"))
+	}
+	if c.Scenario != nil {
+		for i, ev := range c.Scenario.Events {
+			if err := ev.validate(); err != nil {
+				return nil, nil, resource.NewConfigValidationError(fmt.Sprintf("%s.scenario.events.%d", path, i), err)
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+func (ev SimEvent) validate() error {
+	switch ev.Sound {
+	case "tone", "noise", "chirp":
+	case "file":
+		if ev.File == "" {
+			return errors.New("sound file needs a file")
+		}
+	default:
+		return fmt.Errorf("unknown sound %q, expected tone, noise, chirp or file", ev.Sound)
+	}
+	if ev.AtMs < 0 || ev.DurationMs < 0 {
+		return errors.New("at_ms and duration_ms cannot be negative")
+	}
+	return nil
+}
+
+func init() {
+	registerModel(SimModel, resource.Registration[Audio, *SimConfig]{
+		AttributeMapConverter: migratingConverter[*SimConfig](SimModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*SimConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewSim(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+type sim struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	info   AudioInfo
+	chunk  int     // frames per chunk
+	noise  float64 // linear RMS of the noise floor
+	radius float64
+	loop   int64 // frames, zero for no loop
+	clock  ClockSource
+	start  time.Time // scenario time zero
+	logger logging.Logger
+
+	fileMu    sync.Mutex // held while a clip decodes, so each path decodes once
+	fileCache map[string][]float32
+
+	mu     sync.Mutex
+	sounds []simSound // sorted by start
+
+	streams streamGroup
+}
+
+// simSound is an event rendered to mono samples at the sim rate.
+type simSound struct {
+	start   int64 // frame
+	samples []float32
+	delays  []int // per channel, in frames
+	looped  bool
+}
+
+// end returns the frame after the last one the sound reaches any channel.
+func (sound simSound) end() int64 {
+	maxDelay := 0
+	for _, d := range sound.delays {
+		maxDelay = max(maxDelay, d)
+	}
+	return sound.start + int64(len(sound.samples)+maxDelay)
+}
+
+// NewSim returns a sim resource for cfg.
+func NewSim(name resource.Name, cfg SimConfig, logger logging.Logger) (Audio, error) {
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = defaultSimSampleRate
+	}
+	if cfg.Channels == 0 {
+		cfg.Channels = 1
+	}
+	if cfg.ArrayRadiusM == 0 {
+		cfg.ArrayRadiusM = defaultSimArrayRadius
+	}
+	if cfg.NoiseFloorDBFS == 0 {
+		cfg.NoiseFloorDBFS = defaultSimNoiseFloor
+	}
+	if cfg.ChunkMs == 0 {
+		cfg.ChunkMs = int(defaultFileSourceChunk / time.Millisecond)
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+	scenario := cfg.Scenario
+	if cfg.ScenarioFile != "" {
+		b, err := os.ReadFile(cfg.ScenarioFile)
+		if err != nil {
+			return nil, err
+		}
+		scenario = &SimScenario{}
+		if err := json.Unmarshal(b, scenario); err != nil {
+			return nil, fmt.Errorf("failed to parse scenario %s: %w", cfg.ScenarioFile, err)
+		}
+	}
+
+	s := &sim{
+		Named:     name.AsNamed(),
+		info:      AudioInfo{Format: Pcm32Float, SampleRate: cfg.SampleRate, Channels: cfg.Channels},
+		chunk:     cfg.SampleRate * cfg.ChunkMs / 1000,
+		noise:     math.Pow(10, cfg.NoiseFloorDBFS/20),
+		radius:    cfg.ArrayRadiusM,
+		clock:     cfg.Clock,
+		start:     cfg.Clock.Now(),
+		logger:    logger,
+		fileCache: map[string][]float32{},
+	}
+	if scenario != nil {
+		s.loop = int64(scenario.LoopMs) * int64(cfg.SampleRate) / 1000
+		for _, ev := range scenario.Events {
+			if err := s.add(ev, 0, scenario.LoopMs > 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+// add renders ev to start AtMs after the frame base.
+func (s *sim) add(ev SimEvent, base int64, looped bool) error {
+	if err := ev.validate(); err != nil {
+		return err
+	}
+	rate := s.info.SampleRate
+	length := int(defaultSimEventLength.Seconds() * float64(rate))
+	if ev.DurationMs > 0 {
+		length = ev.DurationMs * rate / 1000
+	}
+
+	var samples []float32
+	switch ev.Sound {
+	case "tone":
+		freq := ev.FrequencyHz
+		if freq == 0 {
+			freq = defaultSimToneHz
+		}
+		samples = make([]float32, length)
+		for i := range samples {
+			samples[i] = float32(math.Sin(2 * math.Pi * freq * float64(i) / float64(rate)))
+		}
+	case "noise":
+		samples = make([]float32, length)
+		for i := range samples {
+			samples[i] = simNoise(uint64(i) + (uint64(base)+1)<<32) // uncorrelated with the floor
+		}
+	case "chirp":
+		samples = chirp(rate, time.Duration(length)*time.Second/time.Duration(rate), 300, 3000, 1)
+	case "file":
+		clip, err := s.loadFile(ev.File)
+		if err != nil {
+			return err
+		}
+		samples = append([]float32(nil), clip...)
+		if ev.DurationMs > 0 && len(samples) > length {
+			samples = samples[:length]
+		}
+	}
+	// scale to the requested RMS level
+	if level := rms(samples); level > 0 {
+		gain := float32(math.Pow(10, ev.LevelDBFS/20) / level)
+		for i := range samples {
+			samples[i] *= gain
+		}
+	}
+
+	sound := simSound{
+		start:   base + int64(ev.AtMs)*int64(rate)/1000,
+		samples: samples,
+		delays:  make([]int, s.info.Channels),
+		looped:  looped,
+	}
+	if s.info.Channels > 1 {
+		// microphones sit on a circle; the one closest to the source hears it first
+		theta := ev.DirectionDeg * math.Pi / 180
+		for c := range sound.delays {
+			phi := 2 * math.Pi * float64(c) / float64(s.info.Channels)
+			extra := s.radius * (1 - math.Cos(theta-phi))
+			sound.delays[c] = int(math.Round(extra / speedOfSound * float64(rate)))
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// streams start at the current frame, so sounds that ended before it
+	// can't be heard again unless they loop
+	now := s.now()
+	kept := s.sounds[:0]
+	for _, old := range s.sounds {
+		if old.looped || old.end() > now {
+			kept = append(kept, old)
+		}
+	}
+	s.sounds = append(kept, sound)
+	sort.SliceStable(s.sounds, func(i, j int) bool { return s.sounds[i].start < s.sounds[j].start })
+	return nil
+}
+
+// loadFile decodes a clip to mono at the sim rate, once per path.
+func (s *sim) loadFile(path string) ([]float32, error) {
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	if clip, ok := s.fileCache[path]; ok {
+		return clip, nil
+	}
+	clip, err := readMonoFile(path, s.info.SampleRate)
+	if err != nil {
+		return nil, err
+	}
+	s.fileCache[path] = clip
+	return clip, nil
+}
+
+// readMonoFile decodes a recording to mono at rate.
+func readMonoFile(path string, rate int) ([]float32, error) {
+	samples, info, err := readAudioFile(path)
+	if err != nil {
+		return nil, err
+	}
+	clip := mono(samples, info.Channels)
+	if info.SampleRate != rate {
+		// pad so the resampler's one frame of lookahead doesn't cut the end
+		clip = newResampler(info.SampleRate, rate, 1).process(append(clip, 0))
+	}
+	return clip, nil
+}
+
+// render mixes the frames [from, from+n) of the simulation.
+func (s *sim) render(from int64, n int) []float32 {
+	ch := s.info.Channels
+	out := make([]float32, n*ch)
+	for i := 0; i < n; i++ {
+		noise := float32(s.noise) * simNoise(uint64(from+int64(i)))
+		for c := 0; c < ch; c++ {
+			out[i*ch+c] = noise
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sound := range s.sounds {
+		length := sound.end() - sound.start
+		starts := []int64{sound.start}
+		if sound.looped && s.loop > 0 {
+			// every repetition that overlaps the block
+			starts = starts[:0]
+			first := max(0, (from-sound.start-length)/s.loop)
+			for k := first; sound.start+k*s.loop < from+int64(n); k++ {
+				starts = append(starts, sound.start+k*s.loop)
+			}
+		}
+		for _, start := range starts {
+			if start >= from+int64(n) || start+length <= from {
+				continue
+			}
+			for c := 0; c < ch; c++ {
+				offset := start + int64(sound.delays[c])
+				lo, hi := max(from, offset), min(from+int64(n), offset+int64(len(sound.samples)))
+				for f := lo; f < hi; f++ {
+					out[int(f-from)*ch+c] += sound.samples[f-offset]
+				}
+			}
+		}
+	}
+	return out
+}
+
+// simNoise is white noise with an RMS of one as a function of the frame
+// index, so every stream renders the same world.
+func simNoise(frame uint64) float32 {
+	// splitmix64
+	z := frame + 0x9E3779B97F4A7C15
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	z ^= z >> 31
+	return (float32(z>>40)/float32(1<<24) - 0.5) * 2 * 1.7320508
+}
+
+// now returns the current frame of the simulation.
+func (s *sim) now() int64 {
+	return int64(s.clock.Now().Sub(s.start).Seconds() * float64(s.info.SampleRate))
+}
+
+// GetAudio streams the simulated microphone from the current scenario time
+// in the requested raw pcm format.
+func (s *sim) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, err
+	}
+	o := NewGetAudioOptions(opts...)
+	conv := transcoderFor(format, o)
+	remaining := int64(-1)
+	if durationSeconds > 0 {
+		remaining = int64(float64(durationSeconds) * float64(s.info.SampleRate))
+	}
+	ctx, done, err := s.streams.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		// pace on the scenario timeline so timestamps match the rendered frames
+		frame := s.now()
+		pacer := newSamplePacer(s.clock, s.info.SampleRate)
+		pacer.start = s.start
+		pacer.frames = frame
+		for seq := int64(0); remaining != 0; seq++ {
+			n := s.chunk
+			if remaining > 0 && int64(n) > remaining {
+				n = int(remaining)
+			}
+			at, err := pacer.wait(ctx, n)
+			if err != nil {
+				return
+			}
+			info := s.info
+			chunk, err := conv.convertSamples(s.render(frame, n), &AudioChunk{Sequence: seq, Info: &info, Timestamp: at})
+			if err != nil {
+				chunk, remaining = &AudioChunk{Err: err}, 0
+			}
+			frame += int64(n)
+			if remaining > 0 {
+				remaining -= int64(n)
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Play is accepted and takes as long as the audio would, like a speaker
+// that the simulated microphones don't hear.
+func (s *sim) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return err
+	}
+	width, err := bytesPerSample(format)
+	if err != nil {
+		return err
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	frames := len(data) / (width * channels)
+	return s.clock.Sleep(ctx, time.Duration(frames)*time.Second/time.Duration(sampleRate))
+}
+
+func (s *sim) deviceClock() ClockSource { return s.clock }
+
+// DoCommand supports {"trigger": <SimEvent>}, which adds a sound starting
+// at_ms from now, and the commands every built-in model accepts.
+func (s *sim) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, s, cmd); ok {
+		return resp, err
+	}
+	raw, ok := cmd["trigger"]
+	if !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var ev SimEvent
+	if err := json.Unmarshal(b, &ev); err != nil {
+		return nil, fmt.Errorf("invalid trigger: %w", err)
+	}
+	if err := s.add(ev, s.now(), false); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"triggered": ev.Sound}, nil
+}
+
+// Close ends every stream and waits for them to return.
+func (s *sim) Close(ctx context.Context) error {
+	return s.streams.close(ctx)
+}
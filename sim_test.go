@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestSimPrunesFinishedTriggers(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	a, err := NewSim(Named("sim"), SimConfig{SampleRate: 8000, Clock: clock}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := a.(*sim)
+	trigger := map[string]interface{}{"trigger": map[string]interface{}{"sound": "tone", "duration_ms": 100, "level_dbfs": -20}}
+	for i := 0; i < 50; i++ {
+		if _, err := s.DoCommand(context.Background(), trigger); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(200 * time.Millisecond)
+	}
+	s.mu.Lock()
+	n := len(s.sounds)
+	s.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("%d triggered sounds kept, want only the one still playing", n)
+	}
+	// the one still playing is audible
+	if level := dbfs(rms(s.render(s.now()-1600, 800))); level < -25 {
+		t.Fatalf("last trigger rendered at %.1f dBFS", level)
+	}
+}
+
+func TestSimKeepsLoopedSounds(t *testing.T) {
+	clock := NewManualClock(time.Unix(1000, 0))
+	a, err := NewSim(Named("sim"), SimConfig{SampleRate: 8000, Clock: clock, Scenario: &SimScenario{
+		Events: []SimEvent{{Sound: "tone", DurationMs: 100, LevelDBFS: -20}},
+		LoopMs: 500,
+	}}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := a.(*sim)
+	clock.Advance(10 * time.Second)
+	if _, err := s.DoCommand(context.Background(), map[string]interface{}{"trigger": map[string]interface{}{"sound": "noise", "level_dbfs": -40}}); err != nil {
+		t.Fatal(err)
+	}
+	// 10s is a loop boundary, so the looped tone plays under the new noise
+	if level := dbfs(rms(s.render(s.now(), 800))); level < -25 {
+		t.Fatalf("looped tone pruned, block is %.1f dBFS", level)
+	}
+}
+
+func TestSimFileTriggersConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	writeTestWAV(t, dir, "clip.wav", 8000, 800)
+	a, err := NewSim(Named("sim"), SimConfig{SampleRate: 16000}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger := map[string]interface{}{"trigger": map[string]interface{}{"sound": "file", "file": dir + "/clip.wav"}}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := a.DoCommand(context.Background(), trigger); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	s := a.(*sim)
+	if len(s.fileCache) != 1 {
+		t.Fatalf("%d cached clips, want 1", len(s.fileCache))
+	}
+}
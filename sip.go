@@ -0,0 +1,909 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"go.viam.com/rdk/logging"
+)
+
+// Defaults and timers for the SIP bridge.
+const (
+	defaultSIPPort    = 5060
+	defaultSIPExpires = 300 * time.Second
+	sipT1             = 500 * time.Millisecond // RFC 3261 round-trip estimate
+	sipTransactionTTL = 64 * sipT1
+	// decoded downlink audio buffered ahead of Play, in 20ms packets
+	sipPlayBuffer = 50
+)
+
+// SIPConfig describes the SIP account a bridge registers and answers calls
+// on.
+type SIPConfig struct {
+	// Registrar is the registrar or PBX, host or host:port.
+	Registrar string
+	Username  string
+	Password  string
+	Domain    string // the registrar's host if empty
+
+	// ListenAddr is the local UDP address for SIP, :5060 if empty.
+	ListenAddr string
+	// PublicHost is the address advertised in Contact and SDP. If empty it
+	// is the local address used to reach the registrar.
+	PublicHost string
+	RTPPort    int           // local RTP port, zero picks one per call
+	Expires    time.Duration // registration lifetime, 5 minutes if zero
+	Codec      string        // RTPCodecPCMU (default) or RTPCodecPCMA
+}
+
+// ServeSIP registers a as a SIP endpoint and answers incoming calls with it
+// until ctx is done, making the robot an intercom: call audio is played on a
+// and a's capture is sent back to the caller. One call is connected at a
+// time; others get busy. The registration is removed on return.
+//
+// Only UDP signalling and G.711 media are supported, which every PBX and
+// softphone accepts.
+func ServeSIP(ctx context.Context, a Audio, cfg SIPConfig, logger logging.Logger) error {
+	b, err := newSIPBridge(a, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer b.conn.Close()
+	go b.read()
+
+	if err := b.register(ctx, b.cfg.Expires); err != nil {
+		return err
+	}
+	defer func() {
+		b.hangUp(nil)
+		// unregister on the way out even though ctx is done
+		unregCtx, cancel := context.WithTimeout(context.Background(), 4*sipT1)
+		defer cancel()
+		if err := b.register(unregCtx, 0); err != nil {
+			logger.Debugw("failed to unregister sip endpoint", "error", err)
+		}
+	}()
+
+	// refresh well before the registration lapses
+	ticker := time.NewTicker(b.cfg.Expires / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.closed:
+			return b.readErr
+		case <-ticker.C:
+			if err := b.register(ctx, b.cfg.Expires); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type sipBridge struct {
+	audio  Audio
+	cfg    SIPConfig
+	logger logging.Logger
+
+	conn      *net.UDPConn
+	registrar *net.UDPAddr
+	host      string // advertised address
+	port      int
+
+	regCallID string
+	regTag    string
+
+	mu      sync.Mutex
+	cseq    int
+	pending map[string]chan *sipMessage // final responses by transaction key
+	call    *sipCall
+
+	closed  chan struct{}
+	readErr error
+}
+
+// sipCall is the one connected dialog.
+type sipCall struct {
+	callID   string
+	from, to string // our From and To for requests we send in the dialog
+	target   string // remote Contact URI
+	remote   *net.UDPAddr
+	cseq     int
+	invite   *sipMessage // kept to retransmit the 200 OK until ACK
+	answer   []byte
+	acked    chan struct{}
+	ackOnce  sync.Once
+	rtpConn  *net.UDPConn
+	cancel   context.CancelFunc
+}
+
+func newSIPBridge(a Audio, cfg SIPConfig, logger logging.Logger) (*sipBridge, error) {
+	if cfg.Registrar == "" || cfg.Username == "" {
+		return nil, errors.New("sip bridge needs a registrar and a username")
+	}
+	if cfg.Codec == "" {
+		cfg.Codec = RTPCodecPCMU
+	}
+	if cfg.Codec != RTPCodecPCMU && cfg.Codec != RTPCodecPCMA {
+		return nil, fmt.Errorf("unsupported sip codec %q, expected %s or %s", cfg.Codec, RTPCodecPCMU, RTPCodecPCMA)
+	}
+	if cfg.Expires == 0 {
+		cfg.Expires = defaultSIPExpires
+	}
+	regHost := cfg.Registrar
+	if _, _, err := net.SplitHostPort(regHost); err != nil {
+		regHost = net.JoinHostPort(regHost, strconv.Itoa(defaultSIPPort))
+	}
+	registrar, err := net.ResolveUDPAddr("udp", regHost)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Domain == "" {
+		cfg.Domain, _, _ = net.SplitHostPort(regHost)
+	}
+	if cfg.ListenAddr == "" {
+		cfg.ListenAddr = fmt.Sprintf(":%d", defaultSIPPort)
+	}
+	laddr, err := net.ResolveUDPAddr("udp", cfg.ListenAddr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", laddr)
+	if err != nil {
+		return nil, err
+	}
+
+	host := cfg.PublicHost
+	if host == "" {
+		// the kernel picks the interface that routes to the registrar
+		probe, err := net.DialUDP("udp", nil, registrar)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		host = probe.LocalAddr().(*net.UDPAddr).IP.String()
+		probe.Close()
+	}
+	return &sipBridge{
+		audio:     a,
+		cfg:       cfg,
+		logger:    logger,
+		conn:      conn,
+		registrar: registrar,
+		host:      host,
+		port:      conn.LocalAddr().(*net.UDPAddr).Port,
+		regCallID: sipRandom() + "@" + host,
+		regTag:    sipRandom(),
+		pending:   map[string]chan *sipMessage{},
+		closed:    make(chan struct{}),
+	}, nil
+}
+
+func (b *sipBridge) aor() string {
+	return fmt.Sprintf("sip:%s@%s", b.cfg.Username, b.cfg.Domain)
+}
+
+func (b *sipBridge) contact() string {
+	return fmt.Sprintf("<sip:%s@%s>", b.cfg.Username, net.JoinHostPort(b.host, strconv.Itoa(b.port)))
+}
+
+func (b *sipBridge) nextCSeq() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cseq++
+	return b.cseq
+}
+
+// register sends REGISTER with the given lifetime, answering one digest
+// challenge. Zero expires removes the registration.
+func (b *sipBridge) register(ctx context.Context, expires time.Duration) error {
+	uri := "sip:" + b.cfg.Domain
+	build := func() *sipMessage {
+		m := b.newRequest("REGISTER", uri, b.regCallID, b.nextCSeq())
+		m.add("From", fmt.Sprintf("<%s>;tag=%s", b.aor(), b.regTag))
+		m.add("To", fmt.Sprintf("<%s>", b.aor()))
+		m.add("Contact", b.contact())
+		m.add("Expires", strconv.Itoa(int(expires.Seconds())))
+		return m
+	}
+	req := build()
+	resp, err := b.transact(ctx, req, b.registrar)
+	if err != nil {
+		return err
+	}
+	if resp.status == 401 || resp.status == 407 {
+		challenge, authHeader := resp.get("WWW-Authenticate"), "Authorization"
+		if resp.status == 407 {
+			challenge, authHeader = resp.get("Proxy-Authenticate"), "Proxy-Authorization"
+		}
+		auth, err := sipDigest(challenge, "REGISTER", uri, b.cfg.Username, b.cfg.Password)
+		if err != nil {
+			return err
+		}
+		req = build()
+		req.add(authHeader, auth)
+		if resp, err = b.transact(ctx, req, b.registrar); err != nil {
+			return err
+		}
+	}
+	if resp.status != 200 {
+		return fmt.Errorf("sip registration failed: %d %s", resp.status, resp.reason)
+	}
+	return nil
+}
+
+func (b *sipBridge) newRequest(method, uri, callID string, cseq int) *sipMessage {
+	m := &sipMessage{method: method, uri: uri}
+	m.add("Via", fmt.Sprintf("SIP/2.0/UDP %s;branch=z9hG4bK%s;rport", net.JoinHostPort(b.host, strconv.Itoa(b.port)), sipRandom()))
+	m.add("Max-Forwards", "70")
+	m.add("Call-ID", callID)
+	m.add("CSeq", fmt.Sprintf("%d %s", cseq, method))
+	m.add("User-Agent", "audioapi")
+	return m
+}
+
+// transact sends a request, retransmitting it as RFC 3261 does over UDP,
+// and returns the final response.
+func (b *sipBridge) transact(ctx context.Context, req *sipMessage, to *net.UDPAddr) (*sipMessage, error) {
+	key := req.get("Call-ID") + " " + req.get("CSeq")
+	final := make(chan *sipMessage, 1)
+	b.mu.Lock()
+	b.pending[key] = final
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.pending, key)
+		b.mu.Unlock()
+	}()
+
+	data := req.bytes()
+	deadline := time.NewTimer(sipTransactionTTL)
+	defer deadline.Stop()
+	for interval := sipT1; ; interval = min(2*interval, 4*time.Second) {
+		if _, err := b.conn.WriteToUDP(data, to); err != nil {
+			return nil, err
+		}
+		retransmit := time.NewTimer(interval)
+		select {
+		case resp := <-final:
+			retransmit.Stop()
+			return resp, nil
+		case <-retransmit.C:
+		case <-deadline.C:
+			retransmit.Stop()
+			return nil, fmt.Errorf("no response to sip %s", req.method)
+		case <-b.closed:
+			retransmit.Stop()
+			return nil, b.readErr
+		case <-ctx.Done():
+			retransmit.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (b *sipBridge) read() {
+	defer close(b.closed)
+	buf := make([]byte, 65535)
+	for {
+		n, from, err := b.conn.ReadFromUDP(buf)
+		if err != nil {
+			b.readErr = err
+			return
+		}
+		msg, err := parseSIPMessage(buf[:n])
+		if err != nil {
+			// keepalives are bare CRLFs
+			if len(bytes.TrimSpace(buf[:n])) > 0 {
+				b.logger.Debugw("ignoring malformed sip message", "from", from, "error", err)
+			}
+			continue
+		}
+		if msg.method == "" {
+			if msg.status >= 200 {
+				b.mu.Lock()
+				ch := b.pending[msg.get("Call-ID")+" "+msg.get("CSeq")]
+				b.mu.Unlock()
+				if ch != nil {
+					select {
+					case ch <- msg:
+					default:
+					}
+				}
+			}
+			continue
+		}
+		b.handleRequest(msg, from)
+	}
+}
+
+func (b *sipBridge) handleRequest(req *sipMessage, from *net.UDPAddr) {
+	switch req.method {
+	case "INVITE":
+		b.handleInvite(req, from)
+	case "ACK":
+		b.mu.Lock()
+		if c := b.call; c != nil && c.callID == req.get("Call-ID") {
+			c.ackOnce.Do(func() { close(c.acked) })
+		}
+		b.mu.Unlock()
+	case "BYE":
+		b.mu.Lock()
+		c := b.call
+		ours := c != nil && c.callID == req.get("Call-ID")
+		if ours {
+			b.call = nil
+		}
+		b.mu.Unlock()
+		if !ours {
+			b.respond(req, from, 481, "Call/Transaction Does Not Exist", "", nil)
+			return
+		}
+		b.respond(req, from, 200, "OK", "", nil)
+		c.end()
+		b.logger.Infow("sip call ended by the caller", "call_id", c.callID)
+	case "CANCEL":
+		// calls are answered right away, so there is nothing left to cancel
+		b.respond(req, from, 200, "OK", "", nil)
+	case "OPTIONS":
+		b.respond(req, from, 200, "OK", "", nil)
+	default:
+		b.respond(req, from, 501, "Not Implemented", "", nil)
+	}
+}
+
+func (b *sipBridge) handleInvite(req *sipMessage, from *net.UDPAddr) {
+	callID := req.get("Call-ID")
+	b.mu.Lock()
+	current := b.call
+	b.mu.Unlock()
+	if current != nil {
+		if current.callID == callID {
+			// a retransmitted INVITE gets the same answer
+			b.respond(req, from, 200, "OK", current.to, current.answer)
+			return
+		}
+		b.respond(req, from, 486, "Busy Here", "", nil)
+		return
+	}
+
+	offer, err := parseSDPOffer(req.body)
+	if err != nil {
+		b.respond(req, from, 488, "Not Acceptable Here", "", nil)
+		return
+	}
+	cfg := RTPConfig{Codec: b.cfg.Codec}
+	cfg, err = cfg.withDefaults()
+	if err != nil {
+		b.respond(req, from, 500, "Server Internal Error", "", nil)
+		return
+	}
+	if !offer.offers(cfg.PayloadType) {
+		// fall back to the other G.711 law
+		alt := RTPConfig{Codec: RTPCodecPCMA, SSRC: cfg.SSRC}
+		if cfg.Codec == RTPCodecPCMA {
+			alt.Codec = RTPCodecPCMU
+		}
+		if alt, err = alt.withDefaults(); err != nil || !offer.offers(alt.PayloadType) {
+			b.respond(req, from, 488, "Not Acceptable Here", "", nil)
+			return
+		}
+		cfg = alt
+	}
+
+	rtpConn, err := net.ListenUDP("udp", &net.UDPAddr{Port: b.cfg.RTPPort})
+	if err != nil {
+		b.respond(req, from, 500, "Server Internal Error", "", nil)
+		return
+	}
+	// the call both captures and plays, neither without its audit record
+	auditCtx := withPeer(context.Background(), from)
+	name, detail := b.audio.Name().ShortName(), "sip call from "+req.get("From")
+	for _, action := range []string{AuditCaptureStarted, AuditPlay} {
+		if err := auditOperation(auditCtx, b.logger, name, action, callID, detail, nil); err != nil {
+			rtpConn.Close()
+			b.respond(req, from, 503, "Service Unavailable", "", nil)
+			return
+		}
+	}
+	local := req.get("To")
+	if sipTag(local) == "" {
+		// an INVITE inside an existing dialog already carries our tag
+		local += ";tag=" + sipRandom()
+	}
+	c := &sipCall{
+		callID:  callID,
+		from:    local,
+		to:      req.get("From"),
+		target:  sipURI(req.get("Contact")),
+		remote:  from,
+		invite:  req,
+		acked:   make(chan struct{}),
+		rtpConn: rtpConn,
+	}
+	if c.target == "" {
+		c.target = sipURI(req.get("From"))
+	}
+	c.answer = []byte(b.sdpAnswer(rtpConn.LocalAddr().(*net.UDPAddr).Port, cfg))
+	b.mu.Lock()
+	b.call = c
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	b.logger.Infow("answering sip call", "call_id", callID, "from", c.to, "codec", cfg.Codec)
+	b.respond(req, from, 200, "OK", c.from, c.answer)
+	go b.retransmitOK(ctx, c)
+	go b.runCall(ctx, c, cfg, &net.UDPAddr{IP: offer.ip, Port: offer.port})
+}
+
+// retransmitOK repeats the 200 OK until the caller acknowledges it.
+func (b *sipBridge) retransmitOK(ctx context.Context, c *sipCall) {
+	deadline := time.NewTimer(sipTransactionTTL)
+	defer deadline.Stop()
+	for interval := sipT1; ; interval = min(2*interval, 4*time.Second) {
+		retransmit := time.NewTimer(interval)
+		select {
+		case <-retransmit.C:
+			b.respond(c.invite, c.remote, 200, "OK", c.from, c.answer)
+			continue
+		case <-c.acked:
+		case <-deadline.C:
+			b.logger.Infow("sip call was never acknowledged, hanging up", "call_id", c.callID)
+			b.hangUp(c)
+		case <-ctx.Done():
+		}
+		retransmit.Stop()
+		return
+	}
+}
+
+// runCall carries the media of one call until it ends: the capture goes out
+// as RTP and received RTP is played.
+func (b *sipBridge) runCall(ctx context.Context, c *sipCall, cfg RTPConfig, remote *net.UDPAddr) {
+	downlink := make(chan []byte, sipPlayBuffer)
+	go b.receiveRTP(ctx, c, cfg, downlink)
+	go func() {
+		for {
+			var data []byte
+			select {
+			case <-ctx.Done():
+				return
+			case pcm := <-downlink:
+				data = pcm
+			}
+			// play whatever arrived meanwhile in one go
+			for more := true; more; {
+				select {
+				case pcm := <-downlink:
+					data = append(data, pcm...)
+				default:
+					more = false
+				}
+			}
+			if err := b.audio.Play(ctx, data, Pcm16.String(), g711SampleRate, 1); err != nil && ctx.Err() == nil {
+				b.logger.Warnw("failed to play sip call audio", "call_id", c.callID, "error", err)
+			}
+		}
+	}()
+
+	err := sendRTP(ctx, b.audio, cfg, func(pkt []byte) error {
+		_, err := c.rtpConn.WriteToUDP(pkt, remote)
+		return err
+	})
+	ended := ctx.Err() == nil
+	if !ended {
+		err = nil
+	}
+	auditOperation(withPeer(context.Background(), c.remote), b.logger, b.audio.Name().ShortName(), AuditCaptureEnded, c.callID, "sip call", err)
+	if ended {
+		b.logger.Infow("sip call uplink ended, hanging up", "call_id", c.callID, "error", err)
+		b.hangUp(c)
+	}
+}
+
+func (b *sipBridge) receiveRTP(ctx context.Context, c *sipCall, cfg RTPConfig, downlink chan<- []byte) {
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := c.rtpConn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		var pkt rtp.Packet
+		if err := pkt.Unmarshal(buf[:n]); err != nil || pkt.PayloadType != cfg.PayloadType {
+			// comfort noise and DTMF events are ignored
+			continue
+		}
+		pcm := make([]byte, 2*len(pkt.Payload))
+		for i, v := range pkt.Payload {
+			s := mulawToLinear(v)
+			if cfg.Codec == RTPCodecPCMA {
+				s = alawToLinear(v)
+			}
+			binary.LittleEndian.PutUint16(pcm[2*i:], uint16(s))
+		}
+		select {
+		case downlink <- pcm:
+		case <-ctx.Done():
+			return
+		default:
+			// Play is falling behind, drop rather than add delay
+		}
+	}
+}
+
+// hangUp ends call c, or the current call if c is nil, sending BYE to the
+// caller. It does nothing if that call is already over.
+func (b *sipBridge) hangUp(c *sipCall) {
+	b.mu.Lock()
+	if c == nil {
+		c = b.call
+	}
+	if c == nil || b.call != c {
+		b.mu.Unlock()
+		return
+	}
+	b.call = nil
+	b.mu.Unlock()
+
+	c.end()
+	c.cseq++
+	bye := b.newRequest("BYE", c.target, c.callID, c.cseq)
+	bye.add("From", c.from)
+	bye.add("To", c.to)
+	ctx, cancel := context.WithTimeout(context.Background(), 4*sipT1)
+	defer cancel()
+	if _, err := b.transact(ctx, bye, c.remote); err != nil {
+		b.logger.Debugw("sip BYE was not answered", "call_id", c.callID, "error", err)
+	}
+}
+
+func (c *sipCall) end() {
+	c.cancel()
+	c.rtpConn.Close()
+}
+
+// respond answers req. toHeader replaces the To header so the answer carries
+// our tag; body, if any, is SDP.
+func (b *sipBridge) respond(req *sipMessage, to *net.UDPAddr, status int, reason, toHeader string, body []byte) {
+	resp := &sipMessage{status: status, reason: reason, body: body}
+	for _, via := range req.getAll("Via") {
+		// RFC 3581: tell the caller where its request came from
+		if strings.Contains(via, ";rport") && !strings.Contains(via, ";rport=") {
+			via = strings.Replace(via, ";rport", fmt.Sprintf(";rport=%d;received=%s", to.Port, to.IP), 1)
+		}
+		resp.add("Via", via)
+	}
+	resp.add("From", req.get("From"))
+	if toHeader == "" {
+		toHeader = req.get("To")
+	}
+	resp.add("To", toHeader)
+	resp.add("Call-ID", req.get("Call-ID"))
+	resp.add("CSeq", req.get("CSeq"))
+	if status == 200 && req.method == "INVITE" {
+		resp.add("Contact", b.contact())
+		resp.add("Content-Type", "application/sdp")
+	}
+	if req.method == "OPTIONS" {
+		resp.add("Allow", "INVITE, ACK, BYE, CANCEL, OPTIONS")
+	}
+	resp.add("User-Agent", "audioapi")
+	if _, err := b.conn.WriteToUDP(resp.bytes(), to); err != nil {
+		b.logger.Debugw("failed to send sip response", "error", err)
+	}
+}
+
+func (b *sipBridge) sdpAnswer(port int, cfg RTPConfig) string {
+	id := strconv.FormatInt(time.Now().Unix(), 10)
+	lines := []string{
+		"v=0",
+		fmt.Sprintf("o=audioapi %s %s IN IP4 %s", id, id, b.host),
+		"s=audioapi",
+		"c=IN IP4 " + b.host,
+		"t=0 0",
+		fmt.Sprintf("m=audio %d RTP/AVP %d", port, cfg.PayloadType),
+		fmt.Sprintf("a=rtpmap:%d %s", cfg.PayloadType, cfg.rtpmap()),
+		"a=ptime:20",
+		"a=sendrecv",
+	}
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// sdpOffer is the part of a caller's SDP the bridge uses.
+type sdpOffer struct {
+	ip           net.IP
+	port         int
+	payloadTypes []uint8
+}
+
+func (o sdpOffer) offers(pt uint8) bool {
+	for _, p := range o.payloadTypes {
+		if p == pt {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSDPOffer reads the connection address and the first audio stream.
+func parseSDPOffer(body []byte) (sdpOffer, error) {
+	var o sdpOffer
+	var sessionIP, mediaIP net.IP
+	inAudio, found := false, false
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "m="):
+			fields := strings.Fields(line[2:])
+			inAudio = !found && len(fields) >= 4 && fields[0] == "audio"
+			if !inAudio {
+				continue
+			}
+			found = true
+			port, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return o, fmt.Errorf("bad sdp media line %q", line)
+			}
+			o.port = port
+			for _, f := range fields[3:] {
+				if pt, err := strconv.Atoi(f); err == nil {
+					o.payloadTypes = append(o.payloadTypes, uint8(pt))
+				}
+			}
+		case strings.HasPrefix(line, "c="):
+			fields := strings.Fields(line[2:])
+			if len(fields) < 3 {
+				continue
+			}
+			ip := net.ParseIP(strings.SplitN(fields[2], "/", 2)[0])
+			if inAudio {
+				mediaIP = ip
+			} else if !found {
+				sessionIP = ip
+			}
+		}
+	}
+	o.ip = mediaIP
+	if o.ip == nil {
+		o.ip = sessionIP
+	}
+	if !found || o.ip == nil || o.port == 0 {
+		return o, errors.New("sdp offer has no audio stream")
+	}
+	return o, nil
+}
+
+// sipMessage is a parsed SIP request or response. Requests have a method.
+type sipMessage struct {
+	method string
+	uri    string
+	status int
+	reason string
+	header [][2]string // in order, names as sent
+	body   []byte
+}
+
+// sipCompactHeaders maps RFC 3261 compact header names to their long forms.
+var sipCompactHeaders = map[string]string{
+	"v": "Via", "f": "From", "t": "To", "i": "Call-ID", "m": "Contact",
+	"l": "Content-Length", "c": "Content-Type", "k": "Supported", "s": "Subject",
+}
+
+func (m *sipMessage) add(name, value string) {
+	m.header = append(m.header, [2]string{name, value})
+}
+
+func (m *sipMessage) get(name string) string {
+	values := m.getAll(name)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (m *sipMessage) getAll(name string) []string {
+	var values []string
+	for _, h := range m.header {
+		if strings.EqualFold(h[0], name) {
+			values = append(values, h[1])
+		}
+	}
+	return values
+}
+
+func (m *sipMessage) bytes() []byte {
+	var b bytes.Buffer
+	if m.method != "" {
+		fmt.Fprintf(&b, "%s %s SIP/2.0\r\n", m.method, m.uri)
+	} else {
+		fmt.Fprintf(&b, "SIP/2.0 %d %s\r\n", m.status, m.reason)
+	}
+	for _, h := range m.header {
+		fmt.Fprintf(&b, "%s: %s\r\n", h[0], h[1])
+	}
+	fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n", len(m.body))
+	b.Write(m.body)
+	return b.Bytes()
+}
+
+func parseSIPMessage(data []byte) (*sipMessage, error) {
+	head, body, ok := bytes.Cut(data, []byte("\r\n\r\n"))
+	if !ok {
+		return nil, errors.New("sip message has no end of headers")
+	}
+	lines := strings.Split(string(head), "\r\n")
+	m := &sipMessage{}
+	start := strings.SplitN(lines[0], " ", 3)
+	if len(start) < 3 {
+		return nil, fmt.Errorf("malformed sip start line %q", lines[0])
+	}
+	if start[0] == "SIP/2.0" {
+		status, err := strconv.Atoi(start[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed sip status line %q", lines[0])
+		}
+		m.status, m.reason = status, start[2]
+	} else {
+		if start[2] != "SIP/2.0" {
+			return nil, fmt.Errorf("malformed sip request line %q", lines[0])
+		}
+		m.method, m.uri = start[0], start[1]
+	}
+	for _, line := range lines[1:] {
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(m.header) > 0 {
+			// folded continuation of the previous header
+			m.header[len(m.header)-1][1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed sip header %q", line)
+		}
+		name = strings.TrimSpace(name)
+		if long, ok := sipCompactHeaders[strings.ToLower(name)]; ok {
+			name = long
+		}
+		if strings.EqualFold(name, "Content-Length") {
+			continue
+		}
+		if strings.EqualFold(name, "Via") {
+			// several Vias can share one line
+			for _, via := range strings.Split(value, ",") {
+				m.add("Via", strings.TrimSpace(via))
+			}
+			continue
+		}
+		m.add(name, strings.TrimSpace(value))
+	}
+	m.body = body
+	return m, nil
+}
+
+// sipURI takes the URI out of a name-addr such as "Bob" <sip:bob@host>;tag=1.
+func sipURI(nameAddr string) string {
+	if i := strings.IndexByte(nameAddr, '<'); i >= 0 {
+		if j := strings.IndexByte(nameAddr[i:], '>'); j >= 0 {
+			return nameAddr[i+1 : i+j]
+		}
+	}
+	uri, _, _ := strings.Cut(nameAddr, ";")
+	return strings.TrimSpace(uri)
+}
+
+// sipTag returns the tag parameter of a From or To header, ignoring
+// parameters of the URI inside angle brackets.
+func sipTag(nameAddr string) string {
+	params := nameAddr
+	if i := strings.LastIndexByte(nameAddr, '>'); i >= 0 {
+		params = nameAddr[i+1:]
+	}
+	for _, p := range strings.Split(params, ";") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(p), "="); ok && strings.EqualFold(k, "tag") {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseAuthParams splits the comma separated name=value pairs of an
+// authentication challenge. Values may be quoted strings, which can contain
+// commas and backslash escapes (RFC 2617 section 1.2).
+func parseAuthParams(s string) (map[string]string, error) {
+	params := map[string]string{}
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return params, nil
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("malformed auth parameter %q", s)
+		}
+		name := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " \t")
+		var value strings.Builder
+		if strings.HasPrefix(s, `"`) {
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated quoted string in auth parameter %q", name)
+			}
+			s = s[i+1:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			value.WriteString(strings.TrimSpace(s[:end]))
+			s = s[end:]
+		}
+		params[name] = value.String()
+	}
+}
+
+// sipDigest answers a Digest challenge (RFC 2617, MD5 with or without
+// qop=auth).
+func sipDigest(challenge, method, uri, username, password string) (string, error) {
+	return sipDigestWithCnonce(challenge, method, uri, username, password, sipRandom())
+}
+
+func sipDigestWithCnonce(challenge, method, uri, username, password, cnonce string) (string, error) {
+	scheme, params, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	if !strings.EqualFold(scheme, "Digest") {
+		return "", fmt.Errorf("unsupported sip auth scheme %q", scheme)
+	}
+	p, err := parseAuthParams(params)
+	if err != nil {
+		return "", err
+	}
+	if alg := p["algorithm"]; alg != "" && !strings.EqualFold(alg, "MD5") {
+		return "", fmt.Errorf("unsupported sip digest algorithm %q", alg)
+	}
+	md5hex := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	ha1 := md5hex(username + ":" + p["realm"] + ":" + password)
+	ha2 := md5hex(method + ":" + uri)
+	auth := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", algorithm=MD5`, username, p["realm"], p["nonce"], uri)
+
+	qopAuth := false
+	for _, q := range strings.Split(p["qop"], ",") {
+		qopAuth = qopAuth || strings.TrimSpace(q) == "auth"
+	}
+	if qopAuth {
+		nc := "00000001"
+		auth += fmt.Sprintf(`, qop=auth, nc=%s, cnonce="%s", response="%s"`, nc, cnonce,
+			md5hex(strings.Join([]string{ha1, p["nonce"], nc, cnonce, "auth", ha2}, ":")))
+	} else {
+		auth += fmt.Sprintf(`, response="%s"`, md5hex(ha1+":"+p["nonce"]+":"+ha2))
+	}
+	if opaque, ok := p["opaque"]; ok {
+		auth += fmt.Sprintf(`, opaque="%s"`, opaque)
+	}
+	return auth, nil
+}
+
+// sipRandom returns a fresh token for tags, branches and Call-IDs.
+func sipRandom() string {
+	return strings.ToLower(rand.Text())
+}
@@ -0,0 +1,197 @@
+package audio
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestParseSIPMessage(t *testing.T) {
+	raw := "INVITE sip:robot@pbx.local SIP/2.0\r\n" +
+		"Via: SIP/2.0/UDP 10.0.0.2:5060;branch=z9hG4bK1, SIP/2.0/UDP 10.0.0.1;branch=z9hG4bK2\r\n" +
+		"f: \"Alice\" <sip:alice@pbx.local>;tag=a1\r\n" +
+		"t: <sip:robot@pbx.local>\r\n" +
+		"i: call-1@10.0.0.2\r\n" +
+		"Subject: a folded\r\n" +
+		" header\r\n" +
+		"l: 4\r\n" +
+		"\r\n" +
+		"v=0\n"
+	m, err := parseSIPMessage([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.method != "INVITE" || m.uri != "sip:robot@pbx.local" {
+		t.Fatalf("request line parsed as %q %q", m.method, m.uri)
+	}
+	for name, want := range map[string]string{
+		"From":    `"Alice" <sip:alice@pbx.local>;tag=a1`,
+		"to":      "<sip:robot@pbx.local>",
+		"Call-ID": "call-1@10.0.0.2",
+		"Subject": "a folded header",
+	} {
+		if got := m.get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if vias := m.getAll("Via"); len(vias) != 2 || !strings.HasSuffix(vias[1], "branch=z9hG4bK2") {
+		t.Errorf("got Vias %q, want both from the shared line", vias)
+	}
+	if string(m.body) != "v=0\n" {
+		t.Errorf("body %q", m.body)
+	}
+
+	resp, err := parseSIPMessage([]byte("SIP/2.0 401 Unauthorized\r\nCSeq: 1 REGISTER\r\n\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.status != 401 || resp.reason != "Unauthorized" || resp.method != "" {
+		t.Fatalf("status line parsed as %d %q", resp.status, resp.reason)
+	}
+
+	for _, bad := range []string{
+		"INVITE sip:robot@pbx.local SIP/2.0\r\nVia: x\r\n",
+		"INVITE sip:robot@pbx.local HTTP/1.1\r\n\r\n",
+		"SIP/2.0 OK fine\r\n\r\n",
+		"INVITE sip:robot@pbx.local SIP/2.0\r\nno colon\r\n\r\n",
+	} {
+		if _, err := parseSIPMessage([]byte(bad)); err == nil {
+			t.Errorf("accepted %q", bad)
+		}
+	}
+}
+
+func TestSIPNameAddr(t *testing.T) {
+	for _, tc := range []struct {
+		nameAddr, uri, tag string
+	}{
+		{`"Bob" <sip:bob@host;transport=udp>;tag=1928301774`, "sip:bob@host;transport=udp", "1928301774"},
+		{"<sip:bob@host;tag=uri-param>", "sip:bob@host;tag=uri-param", ""},
+		{"sip:bob@host;tag=abc", "sip:bob@host", "abc"},
+		{"sip:bob@host", "sip:bob@host", ""},
+	} {
+		if got := sipURI(tc.nameAddr); got != tc.uri {
+			t.Errorf("sipURI(%q) = %q, want %q", tc.nameAddr, got, tc.uri)
+		}
+		if got := sipTag(tc.nameAddr); got != tc.tag {
+			t.Errorf("sipTag(%q) = %q, want %q", tc.nameAddr, got, tc.tag)
+		}
+	}
+}
+
+func TestParseAuthParams(t *testing.T) {
+	got, err := parseAuthParams(`realm="a, \"quoted\" realm", qop="auth,auth-int" ,nonce=abc,stale=FALSE`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"realm": `a, "quoted" realm`, "qop": "auth,auth-int", "nonce": "abc", "stale": "FALSE"}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+	for _, bad := range []string{`realm="unterminated`, `realm`} {
+		if _, err := parseAuthParams(bad); err == nil {
+			t.Errorf("accepted %q", bad)
+		}
+	}
+}
+
+func TestSIPDigest(t *testing.T) {
+	// RFC 2617 section 3.5
+	const challenge = `Digest realm="testrealm@host.com", qop="auth,auth-int", ` +
+		`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`
+	auth, err := sipDigestWithCnonce(challenge, "GET", "/dir/index.html", "Mufasa", "Circle Of Life", "0a4f113b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`username="Mufasa"`, `realm="testrealm@host.com"`, `uri="/dir/index.html"`,
+		`qop=auth`, `nc=00000001`, `cnonce="0a4f113b"`,
+		`response="6629fae49393a05397450978507c4ef1"`, `opaque="5ccc069c403ebaf9f0171e9517f40e41"`,
+	} {
+		if !strings.Contains(auth, want) {
+			t.Errorf("authorization %q is missing %s", auth, want)
+		}
+	}
+
+	// the same challenge without qop uses the RFC 2069 response
+	auth, err = sipDigestWithCnonce(`Digest realm="testrealm@host.com", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093"`,
+		"GET", "/dir/index.html", "Mufasa", "Circle Of Life", "unused")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(auth, `response="670fd8c2df070c60b045671b8b24ff02"`) || strings.Contains(auth, "qop") {
+		t.Errorf("authorization %q, want the response without qop", auth)
+	}
+
+	for _, bad := range []string{`Basic realm="x"`, `Digest realm="x", nonce="y", algorithm=SHA-256`} {
+		if _, err := sipDigest(bad, "REGISTER", "sip:pbx", "u", "p"); err == nil {
+			t.Errorf("answered %q", bad)
+		}
+	}
+}
+
+func TestSIPInviteKeepsDialogTag(t *testing.T) {
+	b, err := newSIPBridge(newBurstSource(0, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}), SIPConfig{
+		Registrar:  "127.0.0.1:1",
+		Username:   "robot",
+		ListenAddr: "127.0.0.1:0",
+		PublicHost: "127.0.0.1",
+	}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.conn.Close()
+	caller, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caller.Close()
+
+	for _, tc := range []struct {
+		to      string
+		keepTag string
+	}{
+		{"<sip:robot@127.0.0.1>", ""},
+		{"<sip:robot@127.0.0.1>;tag=ours", "ours"},
+	} {
+		invite := &sipMessage{method: "INVITE", uri: "sip:robot@127.0.0.1", body: []byte("v=0\r\nc=IN IP4 127.0.0.1\r\nm=audio 4000 RTP/AVP 0\r\n")}
+		invite.add("Via", "SIP/2.0/UDP 127.0.0.1;branch=z9hG4bK1")
+		invite.add("From", "<sip:alice@127.0.0.1>;tag=theirs")
+		invite.add("To", tc.to)
+		invite.add("Call-ID", "call-"+tc.keepTag)
+		invite.add("CSeq", "1 INVITE")
+		b.handleInvite(invite, caller.LocalAddr().(*net.UDPAddr))
+
+		caller.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, 4096)
+		n, err := caller.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := parseSIPMessage(buf[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		to := resp.get("To")
+		if resp.status != 200 || strings.Count(to, ";tag=") != 1 {
+			t.Fatalf("got %d with To %q, want 200 with one tag", resp.status, to)
+		}
+		if tc.keepTag != "" && sipTag(to) != tc.keepTag {
+			t.Fatalf("To %q replaced the dialog's tag %q", to, tc.keepTag)
+		}
+
+		b.mu.Lock()
+		c := b.call
+		b.call = nil
+		b.mu.Unlock()
+		c.end()
+	}
+}
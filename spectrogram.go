@@ -0,0 +1,403 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"math/cmplx"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Limits and defaults for loop recordings and spectrograms.
+const (
+	defaultLoopLength        = 30 * time.Second
+	maxLoopLength            = 5 * time.Minute
+	defaultSpectrogramWidth  = 800
+	defaultSpectrogramHeight = 256
+	maxSpectrogramWidth      = 4096
+	maxSpectrogramHeight     = 2048
+	defaultSpectrogramFloor  = -100.0
+	minSpectrogramHz         = 20.0 // the bottom of a log frequency axis
+)
+
+// LoopRecordingConfig sets how much capture StartLoopRecording keeps.
+type LoopRecordingConfig struct {
+	// Length is the capture kept, 30s if zero and at most 5 minutes.
+	Length time.Duration
+}
+
+// LoopRecording keeps the newest capture of a resource in memory, mixed to
+// mono, so it can be looked at after the fact without recording to disk.
+type LoopRecording struct {
+	name   string
+	length time.Duration
+	logger logging.Logger
+
+	mu   sync.Mutex
+	ring sampleRing
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error // why the capture ended, readable once done is closed
+}
+
+// loopRecordingsRunning holds the loop recordings being kept, by resource
+// name, for the GetSpectrogram RPC.
+var loopRecordingsRunning = struct {
+	sync.Mutex
+	m map[string]*LoopRecording
+}{m: map[string]*LoopRecording{}}
+
+// StartLoopRecording keeps the last cfg.Length of the capture of a in
+// memory until Stop is called or the capture ends. The server answers
+// GetSpectrogram for a from it until Stop.
+func StartLoopRecording(ctx context.Context, a Audio, cfg LoopRecordingConfig, logger logging.Logger) (*LoopRecording, error) {
+	if cfg.Length < 0 || cfg.Length > maxLoopLength {
+		return nil, fmt.Errorf("loop length must be from 0 to %v, got %v", maxLoopLength, cfg.Length)
+	}
+	if cfg.Length == 0 {
+		cfg.Length = defaultLoopLength
+	}
+	name := a.Name().ShortName()
+	l := &LoopRecording{name: name, ring: sampleRing{length: cfg.Length}, logger: logger, done: make(chan struct{})}
+
+	loopRecordingsRunning.Lock()
+	if _, ok := loopRecordingsRunning.m[name]; ok {
+		loopRecordingsRunning.Unlock()
+		return nil, fmt.Errorf("a loop recording is already kept for %q", name)
+	}
+	loopRecordingsRunning.m[name] = l
+	loopRecordingsRunning.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	chunks, err := sharedCaptureHub.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm32Float, Channels: 1}})
+	if err != nil {
+		cancel()
+		l.unregister()
+		return nil, err
+	}
+	l.cancel = cancel
+	go func() {
+		defer close(l.done)
+		for chunk := range chunks {
+			err := chunk.Err
+			if err == nil {
+				err = l.add(chunk)
+			}
+			if err != nil {
+				l.err = err
+				cancel()
+				for range chunks {
+				}
+				return
+			}
+		}
+	}()
+	return l, nil
+}
+
+func (l *LoopRecording) unregister() {
+	loopRecordingsRunning.Lock()
+	defer loopRecordingsRunning.Unlock()
+	if loopRecordingsRunning.m[l.name] == l {
+		delete(loopRecordingsRunning.m, l.name)
+	}
+}
+
+// add appends a mono pcm chunk to the ring.
+func (l *LoopRecording) add(chunk *AudioChunk) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _, err := l.ring.add(chunk)
+	return err
+}
+
+// Last returns up to d of the newest capture, all of it if d is zero, with
+// its rate and the capture time of its first sample, zero if unknown.
+func (l *LoopRecording) Last(d time.Duration) ([]float32, int, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	samples, info, start := l.ring.last(d)
+	return samples, info.SampleRate, start
+}
+
+// sampleRing holds the newest length of continuous interleaved capture,
+// decoded to float samples. A change of format or a gap starts it over.
+type sampleRing struct {
+	length time.Duration
+	info   AudioInfo // of the samples held, always Pcm32Float
+	buf    []float32
+	next   int       // where the next sample goes
+	count  int       // samples held
+	end    time.Time // capture time just after the newest sample, zero if unknown
+}
+
+// add appends a raw pcm chunk and returns its samples, and whether the ring
+// started over for it.
+func (r *sampleRing) add(chunk *AudioChunk) ([]float32, bool, error) {
+	if chunk.Info == nil || chunk.Info.SampleRate == 0 || chunk.Info.Channels == 0 {
+		return nil, false, errUnknownSourceFormat
+	}
+	samples, err := decodePCM(chunk.AudioData, chunk.Info.Format)
+	if err != nil {
+		return nil, false, err
+	}
+	info := AudioInfo{Format: Pcm32Float, SampleRate: chunk.Info.SampleRate, Channels: chunk.Info.Channels}
+	reset := info != r.info || chunk.Gap > 0
+	if reset {
+		if info != r.info {
+			frames := max(1, int(r.length.Seconds()*float64(info.SampleRate)))
+			r.info, r.buf = info, make([]float32, frames*info.Channels)
+		}
+		r.next, r.count = 0, 0
+	}
+	kept := samples
+	if len(kept) > len(r.buf) {
+		kept = kept[len(kept)-len(r.buf):]
+	}
+	n := copy(r.buf[r.next:], kept)
+	copy(r.buf, kept[n:])
+	r.next = (r.next + len(kept)) % len(r.buf)
+	r.count = min(r.count+len(kept), len(r.buf))
+	r.end = time.Time{}
+	if !chunk.Timestamp.IsZero() {
+		r.end = chunk.Timestamp.Add(r.duration(len(samples)))
+	}
+	return samples, reset, nil
+}
+
+// last returns up to d of the newest samples, all of them if d is zero, with
+// their format and the capture time of the first, zero if unknown.
+func (r *sampleRing) last(d time.Duration) ([]float32, AudioInfo, time.Time) {
+	n := r.count
+	if d > 0 {
+		n = min(n, int(d.Seconds()*float64(r.info.SampleRate))*r.info.Channels)
+	}
+	out := make([]float32, n)
+	from := (r.next - n + len(r.buf)) % max(len(r.buf), 1)
+	copied := copy(out, r.buf[from:min(from+n, len(r.buf))])
+	copy(out[copied:], r.buf)
+	var start time.Time
+	if !r.end.IsZero() {
+		start = r.end.Add(-r.duration(n))
+	}
+	return out, r.info, start
+}
+
+// duration returns how long n interleaved samples last.
+func (r *sampleRing) duration(n int) time.Duration {
+	if r.info.SampleRate == 0 {
+		return 0
+	}
+	return time.Duration(n/r.info.Channels) * time.Second / time.Duration(r.info.SampleRate)
+}
+
+// Stop ends the loop recording and returns the error that ended the capture,
+// if any.
+func (l *LoopRecording) Stop(ctx context.Context) error {
+	l.cancel()
+	<-l.done
+	l.unregister()
+	return l.err
+}
+
+// SpectrogramOptions sets how a spectrogram is rendered. Zero values take
+// the defaults.
+type SpectrogramOptions struct {
+	Duration time.Duration // of the newest capture, all that is kept if zero
+	// Width and Height are the image's size in pixels, 800 by 256 if zero.
+	// Time runs left to right and frequency bottom to top.
+	Width, Height int
+	FFTSize       int     // samples in each column's frame, 1024 if zero
+	FloorDBFS     float64 // the level shown black, -100 if zero
+	LogFrequency  bool    // a log frequency axis from 20 Hz rather than linear from 0
+}
+
+func (o SpectrogramOptions) withDefaults() (SpectrogramOptions, error) {
+	if o.Width == 0 {
+		o.Width = defaultSpectrogramWidth
+	}
+	if o.Height == 0 {
+		o.Height = defaultSpectrogramHeight
+	}
+	if o.FloorDBFS == 0 {
+		o.FloorDBFS = defaultSpectrogramFloor
+	}
+	fft, err := SpectrumOptions{FFTSize: o.FFTSize}.withDefaults()
+	if err != nil {
+		return o, err
+	}
+	o.FFTSize = fft.FFTSize
+	switch {
+	case o.Duration < 0:
+		return o, errorf(ErrInvalidArgument, "duration cannot be negative, got %v", o.Duration)
+	case o.Width < 0 || o.Width > maxSpectrogramWidth || o.Height < 0 || o.Height > maxSpectrogramHeight:
+		return o, fmt.Errorf("spectrograms are at most %dx%d pixels, got %dx%d", maxSpectrogramWidth, maxSpectrogramHeight, o.Width, o.Height)
+	case o.FloorDBFS > 0:
+		return o, errorf(ErrInvalidArgument, "the floor must be below full scale, got %v dBFS", o.FloorDBFS)
+	}
+	return o, nil
+}
+
+// Spectrogram is a rendered spectrogram and the capture it shows.
+type Spectrogram struct {
+	PNG        []byte
+	Start      time.Time // capture time of the first sample, zero if unknown
+	Duration   time.Duration
+	SampleRate int
+}
+
+// SpectrogramRenderer is implemented by clients that can have the server
+// render its loop recording of a resource, to see the audio around a robot
+// without streaming or downloading it.
+type SpectrogramRenderer interface {
+	GetSpectrogram(ctx context.Context, opts SpectrogramOptions) (Spectrogram, error)
+}
+
+// renderSpectrogram draws mono samples at rate, each column the spectrum
+// of a frame centered on its time.
+func renderSpectrogram(samples []float32, rate int, opts SpectrogramOptions) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	frames := newSpectrumFrames(SpectrumOptions{FFTSize: opts.FFTSize})
+	bins := opts.FFTSize/2 + 1
+	binHz := float64(rate) / float64(opts.FFTSize)
+	nyquist := float64(rate) / 2
+
+	// the bins each row covers, top row highest
+	lo, hi := make([]int, opts.Height), make([]int, opts.Height)
+	for y := range lo {
+		freq := func(edge float64) float64 {
+			if opts.LogFrequency && nyquist > minSpectrogramHz {
+				return minSpectrogramHz * math.Pow(nyquist/minSpectrogramHz, edge)
+			}
+			return edge * nyquist
+		}
+		top, bottom := 1-float64(y)/float64(opts.Height), 1-float64(y+1)/float64(opts.Height)
+		lo[y] = min(bins-1, int(math.Round(freq(bottom)/binHz)))
+		hi[y] = max(lo[y]+1, min(bins, int(math.Round(freq(top)/binHz))))
+	}
+
+	x := make([]complex128, opts.FFTSize)
+	mags := make([]float64, bins)
+	for col := 0; col < opts.Width; col++ {
+		center := int((float64(col) + 0.5) * float64(len(samples)) / float64(opts.Width))
+		from := center - opts.FFTSize/2
+		for i := range x {
+			var s float64
+			if j := from + i; j >= 0 && j < len(samples) {
+				s = float64(samples[j])
+			}
+			x[i] = complex(s*frames.window[i], 0)
+		}
+		fft(x, false)
+		for i := range mags {
+			mags[i] = cmplx.Abs(x[i]) * frames.scale
+		}
+		for y := range lo {
+			var m float64
+			for _, v := range mags[lo[y]:hi[y]] {
+				m = max(m, v)
+			}
+			img.SetRGBA(col, y, spectrogramColor((dbfs(m)-opts.FloorDBFS)/-opts.FloorDBFS))
+		}
+	}
+	return img
+}
+
+// spectrogramStops is the color map, from the floor to full scale.
+var spectrogramStops = []color.RGBA{
+	{0, 0, 0, 255},
+	{87, 16, 110, 255},
+	{188, 55, 84, 255},
+	{249, 142, 9, 255},
+	{252, 255, 164, 255},
+}
+
+// spectrogramColor maps t from 0 to 1 onto the color map.
+func spectrogramColor(t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t)) * float64(len(spectrogramStops)-1)
+	i := min(int(t), len(spectrogramStops)-2)
+	f := t - float64(i)
+	a, b := spectrogramStops[i], spectrogramStops[i+1]
+	mix := func(a, b uint8) uint8 { return uint8(math.Round(float64(a) + f*(float64(b)-float64(a)))) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+// Spectrogram renders the newest opts.Duration of the loop recording.
+func (l *LoopRecording) Spectrogram(opts SpectrogramOptions) (Spectrogram, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return Spectrogram{}, err
+	}
+	samples, rate, start := l.Last(opts.Duration)
+	if len(samples) == 0 {
+		return Spectrogram{}, errors.New("nothing has been captured yet")
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, renderSpectrogram(samples, rate, opts)); err != nil {
+		return Spectrogram{}, err
+	}
+	return Spectrogram{
+		PNG:        buf.Bytes(),
+		Start:      start,
+		Duration:   time.Duration(len(samples)) * time.Second / time.Duration(rate),
+		SampleRate: rate,
+	}, nil
+}
+
+func (s *audioServer) GetSpectrogram(ctx context.Context, req *pb.GetSpectrogramRequest) (*pb.GetSpectrogramResponse, error) {
+	loopRecordingsRunning.Lock()
+	loop := loopRecordingsRunning.m[req.Name]
+	loopRecordingsRunning.Unlock()
+	if loop == nil {
+		return nil, fmt.Errorf("no loop recording is kept for %q", req.Name)
+	}
+	sg, err := loop.Spectrogram(SpectrogramOptions{
+		Duration:     time.Duration(req.Seconds * float64(time.Second)),
+		Width:        int(req.Width),
+		Height:       int(req.Height),
+		FFTSize:      int(req.FftSize),
+		FloorDBFS:    req.FloorDbfs,
+		LogFrequency: req.LogFrequency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetSpectrogramResponse{
+		Png:                       sg.PNG,
+		StartTimestampNanoseconds: toUnixNano(sg.Start),
+		DurationNanoseconds:       int64(sg.Duration),
+		SampleRate:                int32(sg.SampleRate),
+	}, nil
+}
+
+func (c *audioClient) GetSpectrogram(ctx context.Context, opts SpectrogramOptions) (Spectrogram, error) {
+	resp, err := c.client.GetSpectrogram(ctx, &pb.GetSpectrogramRequest{
+		Name:         c.name,
+		Seconds:      opts.Duration.Seconds(),
+		Width:        int32(opts.Width),
+		Height:       int32(opts.Height),
+		FftSize:      int32(opts.FFTSize),
+		FloorDbfs:    opts.FloorDBFS,
+		LogFrequency: opts.LogFrequency,
+	})
+	if err != nil {
+		return Spectrogram{}, err
+	}
+	return Spectrogram{
+		PNG:        resp.Png,
+		Start:      fromUnixNano(resp.StartTimestampNanoseconds),
+		Duration:   time.Duration(resp.DurationNanoseconds),
+		SampleRate: int(resp.SampleRate),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestLoopRecordingRing(t *testing.T) {
+	l := &LoopRecording{ring: sampleRing{length: time.Second}}
+	info := AudioInfo{Format: Pcm32Float, SampleRate: 10, Channels: 1}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	add := func(at time.Duration, gap time.Duration, samples ...float32) {
+		t.Helper()
+		data, _ := encodePCM(samples, Pcm32Float)
+		if err := l.add(&AudioChunk{AudioData: data, Info: &info, Timestamp: start.Add(at), Gap: gap}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(0, 0, 0, 1, 2, 3, 4, 5)
+	add(600*time.Millisecond, 0, 6, 7, 8, 9, 10, 11, 12)
+	samples, rate, at := l.Last(0)
+	if rate != 10 || len(samples) != 10 || samples[0] != 3 || samples[9] != 12 || !at.Equal(start.Add(300*time.Millisecond)) {
+		t.Errorf("kept %v at %d Hz from %v", samples, rate, at)
+	}
+	if samples, _, at := l.Last(300 * time.Millisecond); len(samples) != 3 || samples[0] != 10 || !at.Equal(start.Add(time.Second)) {
+		t.Errorf("the last 300ms are %v from %v", samples, at)
+	}
+	// a gap starts over
+	add(2*time.Second, time.Second, 20, 21)
+	if samples, _, _ := l.Last(0); len(samples) != 2 || samples[0] != 20 {
+		t.Errorf("after a gap kept %v", samples)
+	}
+}
+
+func TestSpectrogram(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.samples = func(i int) []float32 { return tone(8000, 80, 1000, 0.5) }
+	c := serveAudio(t, src).(SpectrogramRenderer)
+	if _, err := c.GetSpectrogram(ctx, SpectrogramOptions{}); err == nil {
+		t.Error("rendered a spectrogram without a loop recording")
+	}
+
+	loop, err := StartLoopRecording(ctx, src, LoopRecordingConfig{Length: time.Second}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loop.Stop(ctx)
+	if _, err := StartLoopRecording(ctx, src, LoopRecordingConfig{}, logging.NewTestLogger(t)); err == nil {
+		t.Error("kept two loop recordings of one resource")
+	}
+	close(src.start)
+	for ctx.Err() == nil {
+		if samples, _, _ := loop.Last(0); len(samples) == 4000 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	sg, err := c.GetSpectrogram(ctx, SpectrogramOptions{Duration: 250 * time.Millisecond, Width: 50, Height: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sg.SampleRate != 8000 || sg.Duration != 250*time.Millisecond {
+		t.Errorf("rendered %v at %d Hz", sg.Duration, sg.SampleRate)
+	}
+	img, err := png.Decode(bytes.NewReader(sg.PNG))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 50 || b.Dy() != 100 {
+		t.Fatalf("rendered %v, want 50x100", b)
+	}
+	// 1 kHz of 4 kHz is a quarter of the way up
+	brightest, best := 0, uint32(0)
+	for y := 0; y < 100; y++ {
+		r, g, _, _ := img.At(25, y).RGBA()
+		if r+g > best {
+			brightest, best = y, r+g
+		}
+	}
+	if brightest < 73 || brightest > 76 {
+		t.Errorf("the brightest row is %d, want about 75", brightest)
+	}
+
+	if _, err := c.GetSpectrogram(ctx, SpectrogramOptions{Width: 10000}); err == nil {
+		t.Error("rendered a spectrogram 10000 pixels wide")
+	}
+}
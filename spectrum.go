@@ -0,0 +1,243 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/cmplx"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Limits and defaults for spectrum frames.
+const (
+	defaultFFTSize = 1024
+	minFFTSize     = 64
+	maxFFTSize     = 65536
+)
+
+// Spectrum is the magnitude spectrum of one frame of capture, mixed to mono.
+type Spectrum struct {
+	Timestamp time.Time // capture time of the frame's first sample, zero if unknown
+	// Magnitudes has FFTSize/2+1 bins from 0 Hz, linear with 1 for a full
+	// scale sine.
+	Magnitudes []float32
+	BinHz      float64
+	Err        error // set on the last frame of a stream that failed
+}
+
+// Peak returns the frequency and magnitude of the strongest bin.
+func (s Spectrum) Peak() (frequencyHz, magnitude float64) {
+	if len(s.Magnitudes) == 0 {
+		return 0, 0
+	}
+	best := 0
+	for i, m := range s.Magnitudes {
+		if m > s.Magnitudes[best] {
+			best = i
+		}
+	}
+	return float64(best) * s.BinHz, float64(s.Magnitudes[best])
+}
+
+// SpectrumOptions sets the frames of a spectrum stream. Zero values take the
+// defaults: 1024 sample frames starting every 512 samples.
+type SpectrumOptions struct {
+	FFTSize int
+	HopSize int
+}
+
+// withDefaults fills in and checks the options.
+func (o SpectrumOptions) withDefaults() (SpectrumOptions, error) {
+	if o.FFTSize == 0 {
+		o.FFTSize = defaultFFTSize
+	}
+	if o.HopSize == 0 {
+		o.HopSize = o.FFTSize / 2
+	}
+	if o.FFTSize < minFFTSize || o.FFTSize > maxFFTSize || nextPow2(o.FFTSize) != o.FFTSize {
+		return o, errorf(ErrInvalidArgument, "fft size must be a power of two from %d to %d, got %d", minFFTSize, maxFFTSize, o.FFTSize)
+	}
+	if o.HopSize < 0 {
+		return o, errorf(ErrInvalidArgument, "hop size cannot be negative, got %d", o.HopSize)
+	}
+	return o, nil
+}
+
+// SpectrumAnalyzer is implemented by clients that can stream spectra of the
+// capture computed on the robot, for spectrogram views and frequency
+// triggers that don't need the audio itself.
+type SpectrumAnalyzer interface {
+	StreamSpectrum(ctx context.Context, opts SpectrumOptions) (<-chan Spectrum, error)
+}
+
+// spectrumFrames cuts mono samples into overlapping Hann windowed frames.
+type spectrumFrames struct {
+	size, hop int
+	window    []float64
+	scale     float64 // makes a full scale sine 1
+	rate      int
+	buf       []float32
+	start     time.Time // capture time of buf[0], zero if unknown
+	skip      int       // samples still to drop when the hop is longer than a frame
+}
+
+func newSpectrumFrames(opts SpectrumOptions) *spectrumFrames {
+	f := &spectrumFrames{size: opts.FFTSize, hop: opts.HopSize, window: make([]float64, opts.FFTSize)}
+	var sum float64
+	for i := range f.window {
+		f.window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(opts.FFTSize))
+		sum += f.window[i]
+	}
+	f.scale = 2 / sum
+	return f
+}
+
+// add takes a mono pcm chunk and returns the frames it completed.
+func (f *spectrumFrames) add(chunk *AudioChunk) ([]Spectrum, error) {
+	if chunk.Info == nil || chunk.Info.SampleRate == 0 {
+		return nil, errUnknownSourceFormat
+	}
+	if chunk.Info.SampleRate != f.rate {
+		// a frame never spans a rate change
+		f.rate, f.buf, f.skip = chunk.Info.SampleRate, nil, 0
+	}
+	samples, err := decodePCM(chunk.AudioData, chunk.Info.Format)
+	if err != nil {
+		return nil, err
+	}
+	at := chunk.Timestamp
+	if f.skip > 0 {
+		n := min(f.skip, len(samples))
+		f.skip -= n
+		samples = samples[n:]
+		if !at.IsZero() {
+			at = at.Add(f.duration(n))
+		}
+	}
+	if len(f.buf) == 0 {
+		f.start = at
+	}
+	f.buf = append(f.buf, samples...)
+
+	var done []Spectrum
+	x := make([]complex128, f.size)
+	for len(f.buf) >= f.size {
+		for i := range x {
+			x[i] = complex(float64(f.buf[i])*f.window[i], 0)
+		}
+		fft(x, false)
+		s := Spectrum{Timestamp: f.start, Magnitudes: make([]float32, f.size/2+1), BinHz: float64(f.rate) / float64(f.size)}
+		for i := range s.Magnitudes {
+			s.Magnitudes[i] = float32(cmplx.Abs(x[i]) * f.scale)
+		}
+		done = append(done, s)
+
+		drop := min(f.hop, len(f.buf))
+		f.skip = f.hop - drop
+		f.buf = append(f.buf[:0], f.buf[drop:]...)
+		if !f.start.IsZero() {
+			f.start = f.start.Add(f.duration(f.hop))
+		}
+	}
+	return done, nil
+}
+
+func (f *spectrumFrames) duration(samples int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(f.rate)
+}
+
+// analyzeSpectrum streams spectra of a's shared capture until ctx is done or
+// the capture ends.
+func (h *captureHub) analyzeSpectrum(ctx context.Context, a Audio, opts SpectrumOptions) (<-chan Spectrum, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := h.open(ctx, a, captureRequest{target: AudioInfo{Format: Pcm32Float, Channels: 1}})
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Spectrum)
+	go func() {
+		defer close(out)
+		frames := newSpectrumFrames(opts)
+		for chunk := range chunks {
+			var done []Spectrum
+			if chunk.Err != nil {
+				done = []Spectrum{{Err: chunk.Err}}
+			} else if done, err = frames.add(chunk); err != nil {
+				done = []Spectrum{{Err: err}}
+			}
+			for _, s := range done {
+				select {
+				case out <- s:
+				case <-ctx.Done():
+					return
+				}
+				if s.Err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (s *audioServer) StreamSpectrum(req *pb.StreamSpectrumRequest, stream pb.AudioService_StreamSpectrumServer) error {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return err
+	}
+	spectra, err := s.hub.analyzeSpectrum(stream.Context(), a, SpectrumOptions{FFTSize: int(req.FftSize), HopSize: int(req.HopSize)})
+	if err != nil {
+		return err
+	}
+	for sp := range spectra {
+		if sp.Err != nil {
+			return sp.Err
+		}
+		frame := &pb.SpectrumFrame{Magnitudes: sp.Magnitudes, BinHz: float32(sp.BinHz)}
+		if !sp.Timestamp.IsZero() {
+			frame.TimestampNanoseconds = sp.Timestamp.UnixNano()
+		}
+		if err := stream.Send(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *audioClient) StreamSpectrum(ctx context.Context, opts SpectrumOptions) (<-chan Spectrum, error) {
+	stream, err := c.client.StreamSpectrum(ctx, &pb.StreamSpectrumRequest{Name: c.name, FftSize: int32(opts.FFTSize), HopSize: int32(opts.HopSize)})
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Spectrum)
+	go func() {
+		defer close(out)
+		for {
+			frame, err := stream.Recv()
+			sp := Spectrum{Err: err}
+			if err == nil {
+				sp = Spectrum{Magnitudes: frame.Magnitudes, BinHz: float64(frame.BinHz)}
+				if frame.TimestampNanoseconds != 0 {
+					sp.Timestamp = time.Unix(0, frame.TimestampNanoseconds)
+				}
+			} else if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return
+			}
+			select {
+			case out <- sp:
+			case <-ctx.Done():
+				return
+			}
+			if sp.Err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,76 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSpectrumFramesHop(t *testing.T) {
+	info := AudioInfo{Format: Pcm32Float, SampleRate: 1000, Channels: 1}
+	start := time.Unix(100, 0)
+	// a hop longer than the frame skips the samples between frames
+	frames := newSpectrumFrames(SpectrumOptions{FFTSize: 64, HopSize: 100})
+	var got []Spectrum
+	for i := 0; i < 10; i++ {
+		data, _ := encodePCM(make([]float32, 50), info.Format)
+		done, err := frames.add(&AudioChunk{AudioData: data, Info: &info, Timestamp: start.Add(time.Duration(i) * 50 * time.Millisecond)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, done...)
+	}
+	if len(got) != 5 {
+		t.Fatalf("got %d frames from 500 samples, want 5", len(got))
+	}
+	for i, s := range got {
+		if want := start.Add(time.Duration(i) * 100 * time.Millisecond); !s.Timestamp.Equal(want) {
+			t.Errorf("frame %d starts at %v, want %v", i, s.Timestamp, want)
+		}
+		if len(s.Magnitudes) != 33 || s.BinHz != 1000.0/64 {
+			t.Errorf("frame %d has %d bins of %v Hz", i, len(s.Magnitudes), s.BinHz)
+		}
+	}
+}
+
+func TestStreamSpectrum(t *testing.T) {
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: 16000, Channels: 2})
+	src.samples = func(int) []float32 { return tone(16000, 160, 1000, 0.5) }
+	client := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	spectra, err := client.(SpectrumAnalyzer).StreamSpectrum(ctx, SpectrumOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(src.start)
+	var n int
+	for s := range spectra {
+		if s.Err != nil {
+			t.Fatal(s.Err)
+		}
+		n++
+		freq, mag := s.Peak()
+		if freq != 1000 || math.Abs(mag-0.5) > 0.01 {
+			t.Errorf("frame %d peaks at %v Hz with %v, want 1000 Hz with 0.5", n, freq, mag)
+		}
+		if other := s.Magnitudes[int(3000/s.BinHz)]; other > 1e-3 {
+			t.Errorf("frame %d has %v at 3 kHz", n, other)
+		}
+	}
+	// 8000 samples make frames of 1024 every 512
+	if n != 14 {
+		t.Errorf("got %d frames, want 14", n)
+	}
+
+	// a streaming call reports its error with the first frame
+	spectra, err = client.(SpectrumAnalyzer).StreamSpectrum(ctx, SpectrumOptions{FFTSize: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := <-spectra; s.Err == nil {
+		t.Error("an fft size that isn't a power of two was accepted")
+	}
+}
@@ -0,0 +1,104 @@
+package audio
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// processStart is when the server started, for Stats.Uptime.
+var processStart = time.Now()
+
+// Stats is what a server has counted of a resource since it started, and
+// what of it is in progress, for fleet health dashboards.
+type Stats struct {
+	Uptime         time.Duration // of the server
+	ActiveStreams  int           // GetAudio streams in progress
+	ActivePlays    int           // Play calls in progress
+	ActiveSessions int           // recording sessions in progress
+	BytesCaptured  int64         // audio sent by GetAudio streams
+	BytesPlayed    int64         // audio received by Play
+	DroppedChunks  int64         // dropped because a GetAudio client fell behind
+	// Overruns and Underruns are the times capture overran and playback ran
+	// dry the device's buffer, zero on resources that aren't XrunCounters.
+	Overruns  int64
+	Underruns int64
+	// LastError is the error the last failed GetAudio stream or Play call
+	// ended in, at LastErrorAt, empty if none has.
+	LastError   string
+	LastErrorAt time.Time
+}
+
+// XrunCounter is implemented by resources that count the xruns of their
+// devices, as the pcm backends do.
+type XrunCounter interface {
+	// Xruns returns how many times capture overran the device's buffer and
+	// playback ran it dry.
+	Xruns() (overruns, underruns int64)
+}
+
+// StatsGetter is implemented by clients of servers that count the streams
+// of their resources.
+type StatsGetter interface {
+	// GetStats returns what the server has counted of the resource.
+	GetStats(ctx context.Context) (Stats, error)
+}
+
+// stats returns the Stats of a, named name.
+func (s *audioServer) stats(name string, a Audio) Stats {
+	stats := sharedMetrics.resourceStats(name)
+	stats.Uptime = time.Since(processStart)
+	stats.ActiveSessions = s.sessions.active(name)
+	if x, ok := a.(XrunCounter); ok {
+		stats.Overruns, stats.Underruns = x.Xruns()
+	}
+	return stats
+}
+
+func (s *audioServer) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
+	a, err := s.resource(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	stats := s.stats(req.Name, a)
+	resp := &pb.GetStatsResponse{
+		UptimeSeconds:  stats.Uptime.Seconds(),
+		ActiveStreams:  int32(stats.ActiveStreams),
+		ActivePlays:    int32(stats.ActivePlays),
+		ActiveSessions: int32(stats.ActiveSessions),
+		BytesCaptured:  stats.BytesCaptured,
+		BytesPlayed:    stats.BytesPlayed,
+		DroppedChunks:  stats.DroppedChunks,
+		Overruns:       stats.Overruns,
+		Underruns:      stats.Underruns,
+		LastError:      stats.LastError,
+	}
+	if !stats.LastErrorAt.IsZero() {
+		resp.LastErrorTimeNanoseconds = stats.LastErrorAt.UnixNano()
+	}
+	return resp, nil
+}
+
+func (c *audioClient) GetStats(ctx context.Context) (Stats, error) {
+	resp, err := c.client.GetStats(ctx, &pb.GetStatsRequest{Name: c.name})
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := Stats{
+		Uptime:         time.Duration(resp.UptimeSeconds * float64(time.Second)),
+		ActiveStreams:  int(resp.ActiveStreams),
+		ActivePlays:    int(resp.ActivePlays),
+		ActiveSessions: int(resp.ActiveSessions),
+		BytesCaptured:  resp.BytesCaptured,
+		BytesPlayed:    resp.BytesPlayed,
+		DroppedChunks:  resp.DroppedChunks,
+		Overruns:       resp.Overruns,
+		Underruns:      resp.Underruns,
+		LastError:      resp.LastError,
+	}
+	if resp.LastErrorTimeNanoseconds != 0 {
+		stats.LastErrorAt = time.Unix(0, resp.LastErrorTimeNanoseconds)
+	}
+	return stats, nil
+}
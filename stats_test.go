@@ -0,0 +1,70 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// xrunSource is a burst source that has had xruns.
+type xrunSource struct {
+	*playRecorder
+}
+
+func (x xrunSource) Xruns() (overruns, underruns int64) {
+	return 2, 3
+}
+
+func TestGetStats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(4, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	src.Named = Named("stats-mic").AsNamed()
+	c := serveAudio(t, xrunSource{&playRecorder{burstSource: src}})
+	close(src.start)
+
+	stats, err := c.(StatsGetter).GetStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Uptime <= 0 || stats.BytesCaptured != 0 || stats.LastError != "" || !stats.LastErrorAt.IsZero() {
+		t.Errorf("stats before any stream are %+v", stats)
+	}
+
+	ch, err := c.GetAudio(ctx, Pcm16.String(), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+	}
+	if err := c.Play(ctx, make([]byte, 100), Pcm16.String(), 8000, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Play(ctx, make([]byte, 100), Pcm16.String(), 8000, 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("a clip without channels failed with %v", err)
+	}
+
+	// the stream ends on the server just after the client sees it end
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if stats, err = c.(StatsGetter).GetStats(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if stats.ActiveStreams == 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+	// 4 chunks of 10ms
+	want := Stats{BytesCaptured: 4 * 160, BytesPlayed: 100, Overruns: 2, Underruns: 3}
+	got := stats
+	got.Uptime, got.LastError, got.LastErrorAt = 0, "", time.Time{}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if stats.LastError == "" || time.Since(stats.LastErrorAt) > 5*time.Second {
+		t.Errorf("last error is %q at %v", stats.LastError, stats.LastErrorAt)
+	}
+}
@@ -0,0 +1,38 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StreamError is the Err of the last chunk of a stream from a server that
+// failed rather than ended: a GetAudio or StreamRecording stream. Code is
+// the gRPC status code it failed with, Canceled when the caller's context
+// ended it. status.Code and status.FromError see through it.
+type StreamError struct {
+	Code    codes.Code
+	Message string
+	err     error // as received
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("audio stream failed with %s: %s", e.Code, e.Message)
+}
+
+func (e *StreamError) Unwrap() error {
+	return e.err
+}
+
+// streamError returns the error a stream from a server ended with, nil
+// when the server ended it cleanly.
+func streamError(err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+	s := status.Convert(err)
+	return &StreamError{Code: s.Code(), Message: s.Message(), err: err}
+}
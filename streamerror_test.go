@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStreamEnd(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(5, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	c := serveAudio(t, src)
+	close(src.start)
+
+	// a clean end is marked by a last empty chunk when asked for
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0, WithEndOfStream())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks []*AudioChunk
+	for chunk := range ch {
+		chunks = append(chunks, chunk)
+	}
+	if n := len(chunks); n != src.n+1 || !chunks[n-1].End || chunks[n-1].Err != nil || chunks[n-2].End {
+		t.Errorf("stream of %d chunks didn't end with a single end marker", n)
+	}
+
+	// a failure ends the stream with its status
+	ch, err = c.GetAudio(ctx, "pcm16", 0, 0, 0, WithCompression("zip"), WithEndOfStream())
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := <-ch
+	var serr *StreamError
+	if chunk == nil || !errors.As(chunk.Err, &serr) || !strings.Contains(serr.Message, "zip") || status.Code(chunk.Err) != serr.Code {
+		t.Fatalf("failed stream ended with %+v", chunk)
+	}
+	if _, ok := <-ch; ok {
+		t.Error("a failed stream went on after its error")
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	src := newBurstSource(1000, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	c := serveAudio(t, src)
+	close(src.start)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	// nobody reads once the caller gives up, so the stream mustn't wait for them
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case chunk, ok := <-ch:
+			if !ok {
+				return
+			}
+			if chunk.Err != nil && status.Code(chunk.Err) != codes.Canceled {
+				t.Fatalf("cancelled stream failed with %v", chunk.Err)
+			}
+		case <-deadline:
+			t.Fatal("cancelled stream never closed")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"io"
+	"sync"
+)
+
+// StreamReader reads the audio of a GetAudio stream as a continuous run of
+// bytes, so it can be piped into encoders, files or speech SDKs that take
+// an io.Reader. Chunk boundaries, timestamps and gaps are lost; audio
+// skipped in a gap simply isn't there.
+type StreamReader struct {
+	ch   <-chan *AudioChunk
+	cur  *AudioChunk // the chunk being read, nil between chunks
+	off  int         // of the next byte of cur to read
+	info *AudioInfo
+	err  error // returned by every Read once the stream ended
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewStreamReader returns a reader of the audio of the chunks from ch, as
+// GetAudio returns it. Read returns io.EOF once the stream ends, and the
+// error a failed stream ended with.
+func NewStreamReader(ch <-chan *AudioChunk) *StreamReader {
+	return &StreamReader{ch: ch, done: make(chan struct{})}
+}
+
+// Read reads audio, blocking until some has arrived, the stream ends or
+// the reader is closed.
+func (r *StreamReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for r.err == nil {
+		select {
+		case <-r.done:
+			r.release()
+			r.err = io.ErrClosedPipe
+			return 0, r.err
+		default:
+		}
+		if r.cur != nil {
+			n := copy(p, r.cur.AudioData[r.off:])
+			if r.off += n; r.off == len(r.cur.AudioData) {
+				r.release()
+			}
+			return n, nil
+		}
+		select {
+		case chunk, ok := <-r.ch:
+			switch {
+			case !ok:
+				r.err = io.EOF
+			case chunk.Err != nil:
+				r.err = chunk.Err
+			case len(chunk.AudioData) == 0:
+				chunk.Release()
+			default:
+				r.cur, r.off = chunk, 0
+				if chunk.Info != nil {
+					r.info = chunk.Info
+				}
+			}
+		case <-r.done:
+		}
+	}
+	return 0, r.err
+}
+
+func (r *StreamReader) release() {
+	r.cur.Release()
+	r.cur = nil
+}
+
+// Info returns the format of the audio read so far, nil before the first
+// chunk that gave one. Streams that change format, as GetAudio can on a
+// reconfigured resource, change it between reads.
+func (r *StreamReader) Info() *AudioInfo {
+	return r.info
+}
+
+// Close stops reading, unblocking a Read in progress, and discards the
+// rest of the stream as it arrives so its sender isn't held up. It doesn't
+// end the stream: cancel the context it was opened with for that.
+func (r *StreamReader) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.done)
+		go func() {
+			for chunk := range r.ch {
+				chunk.Release()
+			}
+		}()
+	})
+	return nil
+}
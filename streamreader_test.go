@@ -0,0 +1,75 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestStreamReader(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src := newBurstSource(10, AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1})
+	close(src.start)
+	c := serveAudio(t, src)
+
+	ch, err := c.GetAudio(ctx, "pcm16", 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewStreamReader(ch)
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := src.n * src.frames * 2; len(data) != want {
+		t.Errorf("read %d bytes, want %d", len(data), want)
+	}
+	if info := r.Info(); info == nil || info.SampleRate != 8000 {
+		t.Errorf("read audio as %+v", info)
+	}
+
+	// a failed stream fails every read after its audio
+	failed := make(chan *AudioChunk, 3)
+	failed <- &AudioChunk{AudioData: []byte{1, 2, 3}}
+	failed <- &AudioChunk{}
+	failed <- &AudioChunk{Err: ErrDeviceBusy}
+	close(failed)
+	r = NewStreamReader(failed)
+	buf := make([]byte, 2)
+	for _, want := range []int{2, 1, 0, 0} {
+		n, err := r.Read(buf)
+		if n != want || (n == 0) != errors.Is(err, ErrDeviceBusy) {
+			t.Fatalf("read %d bytes, %v, want %d", n, err, want)
+		}
+	}
+}
+
+func TestStreamReaderClose(t *testing.T) {
+	ch := make(chan *AudioChunk)
+	r := NewStreamReader(ch)
+	read := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 8))
+		read <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	r.Close()
+	select {
+	case err := <-read:
+		if !errors.Is(err, io.ErrClosedPipe) {
+			t.Errorf("closed reader read %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close didn't unblock Read")
+	}
+	// the rest of the stream is discarded rather than blocking its sender
+	select {
+	case ch <- &AudioChunk{AudioData: []byte{1}}:
+	case <-time.After(5 * time.Second):
+		t.Error("closed reader blocked the stream")
+	}
+	close(ch)
+}
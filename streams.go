@@ -0,0 +1,182 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// StreamController is implemented by Audio clients whose GetAudio streams can
+// be paused and resumed without tearing them down. Streams are addressed by
+// the ID passed to GetAudio with WithRequestID.
+type StreamController interface {
+	PauseStream(ctx context.Context, requestID string) error
+	ResumeStream(ctx context.Context, requestID string) error
+}
+
+// activeStream is the server side state of one GetAudio stream that was
+// started with a request ID.
+type activeStream struct {
+	mu       sync.Mutex
+	paused   bool
+	pausedAt time.Time
+	skipped  time.Duration // audio dropped while paused, not yet reported
+	owner    *Identity     // who started the stream, nil if the server doesn't authenticate
+}
+
+// streamKey identifies a controllable stream. Request IDs are chosen by
+// clients, so they are only unique per resource.
+type streamKey struct {
+	name      string
+	requestID string
+}
+
+// streamRegistry tracks the controllable GetAudio streams by resource name
+// and request ID.
+type streamRegistry struct {
+	mu      sync.Mutex
+	streams map[streamKey]*activeStream
+}
+
+func newStreamRegistry() *streamRegistry {
+	return &streamRegistry{streams: map[streamKey]*activeStream{}}
+}
+
+func (r *streamRegistry) add(name, requestID string) (*activeStream, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := streamKey{name, requestID}
+	if _, ok := r.streams[key]; ok {
+		return nil, fmt.Errorf("a stream of %q with request id %q is already active", name, requestID)
+	}
+	st := &activeStream{}
+	r.streams[key] = st
+	return st, nil
+}
+
+func (r *streamRegistry) remove(name, requestID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.streams, streamKey{name, requestID})
+}
+
+func (r *streamRegistry) get(name, requestID string) (*activeStream, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st, ok := r.streams[streamKey{name, requestID}]
+	if !ok {
+		return nil, fmt.Errorf("no active stream of %q with request id %q", name, requestID)
+	}
+	return st, nil
+}
+
+func (st *activeStream) setPaused(paused bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.paused == paused {
+		return
+	}
+	st.paused = paused
+	if paused {
+		st.pausedAt = time.Now()
+	}
+}
+
+func (st *activeStream) isPaused() bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.paused
+}
+
+func (st *activeStream) setOwner(id Identity) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.owner = &id
+}
+
+// authorize checks that a request to control the stream comes from whoever
+// started it.
+func (st *activeStream) authorize(ctx context.Context) error {
+	st.mu.Lock()
+	owner := st.owner
+	st.mu.Unlock()
+	if owner == nil {
+		return nil
+	}
+	if id, ok := IdentityFromContext(ctx); !ok || !id.sameAs(*owner) {
+		return errors.New("the stream was started by someone else")
+	}
+	return nil
+}
+
+// admit reports whether chunk should be sent, and if so how much audio was
+// skipped since the last chunk that was.
+func (st *activeStream) admit(chunk *AudioChunk) (bool, time.Duration) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.paused {
+		if d, ok := chunkDuration(chunk); ok {
+			st.skipped += d
+		}
+		st.skipped += chunk.Gap
+		return false, 0
+	}
+	gap := st.skipped
+	if gap == 0 && !st.pausedAt.IsZero() {
+		// the chunks dropped while paused didn't say how long they were
+		gap = time.Since(st.pausedAt)
+	}
+	st.skipped, st.pausedAt = 0, time.Time{}
+	return true, gap
+}
+
+// chunkDuration returns how much audio a raw PCM chunk holds.
+func chunkDuration(chunk *AudioChunk) (time.Duration, bool) {
+	if chunk.Info == nil || chunk.Info.SampleRate == 0 || chunk.Info.Channels == 0 {
+		return 0, false
+	}
+	width, err := bytesPerSample(chunk.Info.Format)
+	if err != nil {
+		return 0, false
+	}
+	frames := len(chunk.AudioData) / (width * chunk.Info.Channels)
+	return time.Duration(frames) * time.Second / time.Duration(chunk.Info.SampleRate), true
+}
+
+func (s *audioServer) PauseStream(ctx context.Context, req *pb.PauseStreamRequest) (*pb.PauseStreamResponse, error) {
+	st, err := s.streams.get(req.Name, req.RequestId)
+	if err != nil {
+		return nil, err
+	}
+	if err := st.authorize(ctx); err != nil {
+		return nil, err
+	}
+	st.setPaused(true)
+	return &pb.PauseStreamResponse{}, nil
+}
+
+func (s *audioServer) ResumeStream(ctx context.Context, req *pb.ResumeStreamRequest) (*pb.ResumeStreamResponse, error) {
+	st, err := s.streams.get(req.Name, req.RequestId)
+	if err != nil {
+		return nil, err
+	}
+	if err := st.authorize(ctx); err != nil {
+		return nil, err
+	}
+	st.setPaused(false)
+	return &pb.ResumeStreamResponse{}, nil
+}
+
+func (c *audioClient) PauseStream(ctx context.Context, requestID string) error {
+	_, err := c.client.PauseStream(ctx, &pb.PauseStreamRequest{Name: c.name, RequestId: requestID})
+	return err
+}
+
+func (c *audioClient) ResumeStream(ctx context.Context, requestID string) error {
+	_, err := c.client.ResumeStream(ctx, &pb.ResumeStreamRequest{Name: c.name, RequestId: requestID})
+	return err
+}
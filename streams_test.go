@@ -0,0 +1,60 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+func TestPauseStreamIsScopedToTheResource(t *testing.T) {
+	s := &audioServer{streams: newStreamRegistry()}
+	mic, err := s.streams.add("mic", "req")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.streams.add("mic", "req"); err == nil {
+		t.Fatal("the same request id was registered twice for one resource")
+	}
+	speaker, err := s.streams.add("speaker", "req")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.PauseStream(context.Background(), &pb.PauseStreamRequest{Name: "speaker", RequestId: "req"}); err != nil {
+		t.Fatal(err)
+	}
+	chunk := &AudioChunk{AudioData: make([]byte, 960), Info: &AudioInfo{Format: Pcm16, SampleRate: 48000, Channels: 1}}
+	if send, _ := mic.admit(chunk); !send {
+		t.Fatal("pausing speaker paused mic")
+	}
+	if send, _ := speaker.admit(chunk); send {
+		t.Fatal("speaker was not paused")
+	}
+
+	if _, err := s.ResumeStream(context.Background(), &pb.ResumeStreamRequest{Name: "speaker", RequestId: "req"}); err != nil {
+		t.Fatal(err)
+	}
+	if send, gap := speaker.admit(chunk); !send || gap != 10*time.Millisecond {
+		t.Fatalf("after resume got send %v gap %v, want the paused chunk as a 10ms gap", send, gap)
+	}
+
+	s.streams.remove("mic", "req")
+	if _, err := s.PauseStream(context.Background(), &pb.PauseStreamRequest{Name: "mic", RequestId: "req"}); err == nil {
+		t.Fatal("paused a stream that ended")
+	}
+}
+
+func TestPausedStreamKeepsDroppedGaps(t *testing.T) {
+	st := &activeStream{}
+	st.setPaused(true)
+	info := &AudioInfo{Format: Pcm16, SampleRate: 8000, Channels: 1}
+	// a chunk after trimmed silence, then the empty chunk ending a trimmed stream
+	st.admit(&AudioChunk{AudioData: make([]byte, 160), Info: info, Gap: 40 * time.Millisecond})
+	st.admit(&AudioChunk{Info: info, Gap: 30 * time.Millisecond})
+	st.setPaused(false)
+	if _, gap := st.admit(&AudioChunk{AudioData: make([]byte, 160), Info: info}); gap != 80*time.Millisecond {
+		t.Fatalf("resumed after a %v gap, want 80ms", gap)
+	}
+}
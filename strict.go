@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// Strict streams and clips are never converted: audio that isn't already in
+// the exact format asked for fails with an ErrFormatMismatch error naming
+// what differs, for pipelines such as forensic capture where a quietly
+// resampled or remixed recording is worse than none.
+
+type strictPlaybackKey struct{}
+
+// StrictPlayback returns a context under which Play fails rather than
+// converting a clip the device can't play exactly as sent. Clients send it
+// to the server with the request.
+func StrictPlayback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, strictPlaybackKey{}, true)
+}
+
+func isStrictPlayback(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictPlaybackKey{}).(bool)
+	return strict
+}
+
+// checkStrictPlayback returns an error if ctx is strict and a clip in
+// format, sampleRate and channels would have to be converted to play on a
+// device playing device.
+func checkStrictPlayback(ctx context.Context, device AudioInfo, format AudioFormat, sampleRate, channels int) error {
+	if !isStrictPlayback(ctx) {
+		return nil
+	}
+	return strictMismatch(playbackDirection, device, &AudioInfo{Format: format, SampleRate: sampleRate, Channels: channels})
+}
+
+// strictMismatch returns an ErrFormatMismatch error if got differs from
+// want, where zero rates and channel counts in want match any. A nil got is
+// audio of unknown format, which matches nothing.
+func strictMismatch(direction string, want AudioInfo, got *AudioInfo) error {
+	if got == nil {
+		return errorf(ErrFormatMismatch, "strict %s: need %s, but the audio has no format information", direction, describeFormat(want))
+	}
+	var differs []string
+	if got.Format != want.Format {
+		differs = append(differs, fmt.Sprintf("codec %s, not %s", got.Format, want.Format))
+	}
+	if want.SampleRate != 0 && got.SampleRate != want.SampleRate {
+		differs = append(differs, fmt.Sprintf("%d Hz, not %d Hz", got.SampleRate, want.SampleRate))
+	}
+	if want.Channels != 0 && got.Channels != want.Channels {
+		differs = append(differs, fmt.Sprintf("%d channels, not %d", got.Channels, want.Channels))
+	}
+	if len(differs) == 0 {
+		return nil
+	}
+	return errorf(ErrFormatMismatch, "strict %s: have %s, need %s (%s)", direction, describeFormat(*got), describeFormat(want), strings.Join(differs, "; "))
+}
+
+func describeFormat(info AudioInfo) string {
+	rate, channels := "any rate", "any channels"
+	if info.SampleRate != 0 {
+		rate = fmt.Sprintf("%d Hz", info.SampleRate)
+	}
+	if info.Channels != 0 {
+		channels = fmt.Sprintf("%d channels", info.Channels)
+	}
+	return fmt.Sprintf("%s, %s, %s", info.Format, rate, channels)
+}
+
+// strictCaptureFormat returns the format a strict GetAudio request must be
+// delivered in.
+func strictCaptureFormat(req *pb.GetAudioRequest) (AudioInfo, error) {
+	if req.Codec == "" {
+		return AudioInfo{}, errorf(ErrFormatMismatch, "strict capture: a codec must be requested")
+	}
+	format, err := formatFromCodec(req.Codec)
+	if err != nil {
+		return AudioInfo{}, errorf(ErrFormatMismatch, "strict capture: %v", err)
+	}
+	return AudioInfo{Format: format, SampleRate: int(req.SampleRate), Channels: int(req.NumChannels)}, nil
+}
@@ -0,0 +1,98 @@
+package audio
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStrictGetAudio(t *testing.T) {
+	src := newBurstSource(5, AudioInfo{Format: Pcm16, SampleRate: 16000, Channels: 2})
+	close(src.start)
+	c := serveAudio(t, src)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// the source's own format is delivered
+	chunks, err := c.GetAudio(ctx, Pcm16.String(), 0, 0, 0, WithStrict(), WithSampleRate(16000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _, _ := drain(t, chunks); n != 5 {
+		t.Errorf("got %d chunks, want 5", n)
+	}
+
+	for _, tc := range []struct {
+		codec string
+		opts  []GetAudioOption
+		want  string
+	}{
+		{Pcm16.String(), []GetAudioOption{WithSampleRate(8000), WithChannels(1)}, "16000 Hz, not 8000 Hz; 2 channels, not 1"},
+		{Pcm32Float.String(), nil, "codec pcm16, not pcm32_float"},
+		// other codecs go to the resource, which sends pcm16 anyway
+		{Mp3.String(), nil, "codec pcm16, not mp3"},
+		{"", nil, "a codec must be requested"},
+		{"opus", nil, `unknown codec "opus"`},
+	} {
+		chunks, err := c.GetAudio(ctx, tc.codec, 0, 0, 0, append(tc.opts, WithStrict())...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got error
+		for chunk := range chunks {
+			if chunk.Err != nil {
+				got = chunk.Err
+			}
+		}
+		if status.Code(got) != codes.FailedPrecondition || !strings.Contains(got.Error(), tc.want) {
+			t.Errorf("%q: got %v, want a failed precondition with %q", tc.codec, got, tc.want)
+		}
+	}
+
+	// without strict the same request is converted
+	chunks, err = c.GetAudio(ctx, Pcm16.String(), 0, 0, 0, WithSampleRate(8000), WithChannels(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	drain(t, chunks)
+}
+
+func TestStrictPlay(t *testing.T) {
+	prevOpen, prevList := openPortAudio, listPortAudioDevices
+	openPortAudio = func(device int, capture bool, p pcmParams) (pcmDevice, error) {
+		return &fakePCM{p: p, fail: map[int]bool{}}, nil
+	}
+	listPortAudioDevices = func() ([]portAudioDevice, error) { return windowsPortAudioDevices, nil }
+	defer func() { openPortAudio, listPortAudioDevices = prevOpen, prevList }()
+	a, err := NewPortAudio(Named("kiosk"), PortAudioConfig{CaptureDevice: noPCMDevice, SampleRate: 48000}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	c := serveAudio(t, a)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mono, _ := encodePCM(tone(16000, 160, 440, 0.5), Pcm16)
+	stereo, _ := encodePCM(remix(tone(48000, 480, 440, 0.5), 1, 2), Pcm16)
+	if err := c.Play(ctx, mono, Pcm16.String(), 16000, 1); err != nil {
+		t.Fatalf("playing with conversion: %v", err)
+	}
+	strict := StrictPlayback(ctx)
+	if err := c.Play(strict, stereo, Pcm16.String(), 48000, 2); err != nil {
+		t.Fatalf("playing in the device format: %v", err)
+	}
+	err = c.Play(strict, mono, Pcm16.String(), 16000, 1)
+	if status.Code(err) != codes.FailedPrecondition || !strings.Contains(err.Error(), "16000 Hz, not 48000 Hz; 1 channels, not 2") {
+		t.Errorf("strict play of a clip to convert: %v", err)
+	}
+	err = c.(NormalizedPlayer).PlayNormalized(strict, mono, Pcm16.String(), 16000, 1, -23)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("strict normalized play of a clip to convert: %v", err)
+	}
+}
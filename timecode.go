@@ -0,0 +1,263 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+
+	pb "github.com/oliviamiller/audioapi-poc/grpc/audioin_api_go/grpc"
+)
+
+// TimecodeModel wraps another Audio to sync it with cameras and recorders
+// by linear timecode. Audio played through it carries time of day LTC on
+// one channel, and capture reads LTC from one channel into each chunk's
+// Timecode.
+var TimecodeModel = resource.NewModel("olivia", "audio", "timecode")
+
+const defaultLTCLevel = -18.0 // dBFS, the usual line-up level for LTC
+
+// TimecodeConfig is the configuration of the timecode model.
+type TimecodeConfig struct {
+	Audio         string  `json:"audio"`                    // Audio resource to capture from and play through
+	InputChannel  int     `json:"input_channel,omitempty"`  // 1-based capture channel carrying LTC, 0 to read none
+	OutputChannel int     `json:"output_channel,omitempty"` // 1-based playback channel to write LTC on, 0 to write none
+	FrameRate     int     `json:"frame_rate,omitempty"`     // of generated LTC: 24, 25 or 30, 30 if zero
+	DropFrame     bool    `json:"drop_frame,omitempty"`     // generate 29.97 drop frame, with frame_rate 30
+	LevelDBFS     float64 `json:"level_dbfs,omitempty"`     // peak of generated LTC, -18 if zero
+}
+
+// Validate checks the timecode configuration and returns the audio as a
+// dependency.
+func (c *TimecodeConfig) Validate(path string) ([]string, []string, error) {
+	if c.Audio == "" {
+		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "audio")
+	}
+	var err error
+	switch {
+	case c.InputChannel < 0 || c.OutputChannel < 0:
+		err = errors.New("channels are counted from 1")
+	case c.InputChannel == 0 && c.OutputChannel == 0:
+		err = errors.New("set input_channel, output_channel or both")
+	case c.FrameRate != 0 && c.FrameRate != 24 && c.FrameRate != 25 && c.FrameRate != 30:
+		err = fmt.Errorf("frame_rate %d isn't 24, 25 or 30", c.FrameRate)
+	case c.DropFrame && c.FrameRate != 0 && c.FrameRate != 30:
+		err = errors.New("drop_frame needs frame_rate 30")
+	case c.LevelDBFS > 0:
+		err = errors.New("level_dbfs must be below full scale")
+	}
+	if err != nil {
+		return nil, nil, resource.NewConfigValidationError(path, err)
+	}
+	return []string{c.Audio}, nil, nil
+}
+
+func init() {
+	registerModel(TimecodeModel, resource.Registration[Audio, *TimecodeConfig]{
+		AttributeMapConverter: migratingConverter[*TimecodeConfig](TimecodeModel),
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*TimecodeConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			a, err := resource.FromDependencies[Audio](deps, Named(cfg.Audio))
+			if err != nil {
+				return nil, err
+			}
+			return NewTimecode(conf.ResourceName(), a, *cfg, logger), nil
+		},
+	})
+}
+
+type timecoded struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	audio  Audio
+	cfg    TimecodeConfig
+	logger logging.Logger
+
+	streams streamGroup
+}
+
+// NewTimecode returns a resource reading and writing LTC on a's channels.
+func NewTimecode(name resource.Name, a Audio, cfg TimecodeConfig, logger logging.Logger) Audio {
+	if cfg.FrameRate == 0 {
+		cfg.FrameRate = 30
+	}
+	if cfg.LevelDBFS == 0 {
+		cfg.LevelDBFS = defaultLTCLevel
+	}
+	return &timecoded{Named: name.AsNamed(), audio: a, cfg: cfg, logger: logger}
+}
+
+// GetAudio captures in the requested codec at the source's own rate and
+// channels, so the LTC channel is read before any conversion, and sets the
+// Timecode of every chunk a frame ended in.
+func (t *timecoded) GetAudio(ctx context.Context, codec string, durationSeconds, maxDuration float32, previousTimestamp int64, opts ...GetAudioOption) (<-chan *AudioChunk, error) {
+	if t.cfg.InputChannel == 0 {
+		return t.audio.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, opts...)
+	}
+	if codec == "" {
+		codec = Pcm16.String()
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return nil, fmt.Errorf("timecode can only be read from raw pcm, got %q", codec)
+	}
+	o := NewGetAudioOptions(opts...)
+	var srcOpts []GetAudioOption
+	if o.Strict {
+		srcOpts = append(srcOpts, WithStrict())
+	}
+	ctx, done, err := t.streams.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	src, err := t.audio.GetAudio(ctx, codec, durationSeconds, maxDuration, previousTimestamp, srcOpts...)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	out := make(chan *AudioChunk)
+	go func() {
+		defer done()
+		defer close(out)
+		conv := transcoderFor(format, o)
+		var dec *ltcDecoder
+		for chunk := range src {
+			if chunk.Err == nil {
+				read, err := t.read(chunk, &dec)
+				if err == nil {
+					read, err = conv.convert(read)
+				}
+				if err != nil {
+					read = &AudioChunk{Err: err}
+				}
+				chunk = read
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+			if chunk.Err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// read returns a copy of chunk with the timecode read from it, starting a
+// new decoder when the rate changes.
+func (t *timecoded) read(chunk *AudioChunk, dec **ltcDecoder) (*AudioChunk, error) {
+	info := chunk.Info
+	if info == nil || info.SampleRate == 0 || info.Channels == 0 {
+		return nil, errUnknownSourceFormat
+	}
+	if t.cfg.InputChannel > info.Channels {
+		return nil, fmt.Errorf("input_channel %d is beyond the source's %d channels", t.cfg.InputChannel, info.Channels)
+	}
+	samples, err := decodePCM(chunk.AudioData, info.Format)
+	if err != nil {
+		return nil, err
+	}
+	if *dec == nil || (*dec).sampleRate != info.SampleRate {
+		*dec = newLTCDecoder(info.SampleRate)
+	}
+	out := *chunk
+	if tc, ok := (*dec).decode(samples, info.Channels, t.cfg.InputChannel-1); ok {
+		out.Timecode = &tc
+	}
+	return &out, nil
+}
+
+// Play writes LTC for the time the clip starts on the output channel of raw
+// pcm clips and plays them. The LTC of consecutive clips joins up, so audio
+// streamed as back to back Play calls carries continuous timecode.
+func (t *timecoded) Play(ctx context.Context, data []byte, codec string, sampleRate, channels int) error {
+	if t.cfg.OutputChannel == 0 {
+		return t.audio.Play(ctx, data, codec, sampleRate, channels)
+	}
+	format, err := formatFromCodec(codec)
+	if err != nil {
+		return err
+	}
+	if _, err := bytesPerSample(format); err != nil {
+		return fmt.Errorf("timecode can only be written on raw pcm: %w", err)
+	}
+	if sampleRate <= 0 || channels <= 0 {
+		return errorf(ErrInvalidArgument, "invalid playback format: %d Hz, %d channels", sampleRate, channels)
+	}
+	if t.cfg.OutputChannel > channels {
+		return fmt.Errorf("output_channel %d is beyond the clip's %d channels", t.cfg.OutputChannel, channels)
+	}
+	samples, err := decodePCM(data, format)
+	if err != nil {
+		return err
+	}
+	gen := &ltcGenerator{
+		rate:  ltcRate{nominal: t.cfg.FrameRate, dropFrame: t.cfg.DropFrame},
+		level: float32(math.Pow(10, t.cfg.LevelDBFS/20)),
+	}
+	gen.fill(samples, channels, t.cfg.OutputChannel-1, sampleRate, clockOf(t.audio).Now())
+	out, err := encodePCM(samples, format)
+	if err != nil {
+		return err
+	}
+	return t.audio.Play(ctx, out, codec, sampleRate, channels)
+}
+
+func (t *timecoded) deviceClock() ClockSource { return clockOf(t.audio) }
+
+func (t *timecoded) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := doBuiltinCommand(ctx, t, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close ends every stream and waits for them to return.
+func (t *timecoded) Close(ctx context.Context) error {
+	return t.streams.close(ctx)
+}
+
+func timecodeToProto(tc *Timecode) *pb.Timecode {
+	if tc == nil {
+		return nil
+	}
+	return &pb.Timecode{
+		Hours:             int32(tc.Hours),
+		Minutes:           int32(tc.Minutes),
+		Seconds:           int32(tc.Seconds),
+		Frames:            int32(tc.Frames),
+		DropFrame:         tc.DropFrame,
+		FrameRate:         int32(tc.FrameRate),
+		UserBits:          tc.UserBits,
+		OffsetNanoseconds: tc.Offset.Nanoseconds(),
+	}
+}
+
+func timecodeFromProto(tc *pb.Timecode) *Timecode {
+	if tc == nil {
+		return nil
+	}
+	return &Timecode{
+		Hours:     int(tc.Hours),
+		Minutes:   int(tc.Minutes),
+		Seconds:   int(tc.Seconds),
+		Frames:    int(tc.Frames),
+		DropFrame: tc.DropFrame,
+		FrameRate: int(tc.FrameRate),
+		UserBits:  tc.UserBits,
+		Offset:    time.Duration(tc.OffsetNanoseconds),
+	}
+}
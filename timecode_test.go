@@ -0,0 +1,120 @@
+package audio
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestTimecodeLoopback(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	dev := NewLoopback(Named("dev"), LoopbackConfig{SampleRate: 48000, Channels: 2}, logger)
+	defer dev.Close(context.Background())
+	tc := NewTimecode(Named("tc"), dev, TimecodeConfig{Audio: "dev", InputChannel: 2, OutputChannel: 2, FrameRate: 25}, logger)
+	c := serveAudio(t, tc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// mono out of a stereo device, the timecode is read before the remix
+	chunks, err := c.GetAudio(ctx, Pcm16.String(), 1, 0, 0, WithChannels(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clip, _ := encodePCM(make([]float32, 2*48000/2), Pcm16)
+	played := time.Now()
+	if err := c.Play(ctx, clip, Pcm16.String(), 48000, 2); err != nil {
+		t.Fatal(err)
+	}
+	var got []*Timecode
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		if chunk.Info.Channels != 1 {
+			t.Fatalf("captured %d channels", chunk.Info.Channels)
+		}
+		if chunk.Timecode != nil {
+			got = append(got, chunk.Timecode)
+		}
+	}
+	// half a second of LTC at 25 fps, less the first frame
+	if len(got) < 10 {
+		t.Fatalf("read %d frames", len(got))
+	}
+	now := time.Date(0, 1, 1, played.Hour(), played.Minute(), played.Second(), 0, time.UTC)
+	for i, tc := range got {
+		if tc.FrameRate != 25 || tc.DropFrame {
+			t.Errorf("frame %d is %+v", i, tc)
+		}
+		at := time.Date(0, 1, 1, tc.Hours, tc.Minutes, tc.Seconds, tc.Frames*int(time.Second)/25, time.UTC)
+		if d := at.Sub(now); d < -2*time.Second || d > 2*time.Second {
+			t.Errorf("frame %d reads %s, played at %s", i, tc, played.Format("15:04:05.00"))
+		}
+		if i > 0 {
+			prev := time.Date(0, 1, 1, got[i-1].Hours, got[i-1].Minutes, got[i-1].Seconds, got[i-1].Frames*int(time.Second)/25, time.UTC)
+			if !at.After(prev) {
+				t.Errorf("frame %d reads %s after %s", i, tc, got[i-1])
+			}
+		}
+	}
+}
+
+func TestTimecodePlay(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	f, err := NewFake(Named("fake"), FakeConfig{Unpaced: true}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := NewTimecode(Named("tc"), f, TimecodeConfig{Audio: "fake", OutputChannel: 1}, logger)
+	ctx := context.Background()
+	// the program on the second channel is untouched
+	program := tone(16000, 1600, 440, 0.5)
+	stereo := make([]float32, 2*len(program))
+	for i, s := range program {
+		stereo[2*i+1] = s
+	}
+	clip, _ := encodePCM(stereo, Pcm16)
+	if err := tc.Play(ctx, clip, Pcm16.String(), 16000, 2); err != nil {
+		t.Fatal(err)
+	}
+	last, _ := f.LastPlay()
+	samples, _ := decodePCM(last.Data, Pcm16)
+	dec := newLTCDecoder(16000)
+	if _, ok := dec.decode(samples, 2, 0); !ok {
+		t.Error("no timecode on the first channel")
+	}
+	for i, s := range program {
+		if d := samples[2*i+1] - s; d > 1e-3 || d < -1e-3 {
+			t.Fatalf("program sample %d is %v, want %v", i, samples[2*i+1], s)
+		}
+	}
+	if err := tc.Play(ctx, clip[:len(clip)/2], Pcm16.String(), 16000, 1); err != nil {
+		t.Error(err)
+	}
+	if err := NewTimecode(Named("tc"), f, TimecodeConfig{Audio: "fake", OutputChannel: 2}, logger).Play(ctx, clip, Pcm16.String(), 16000, 1); err == nil || !strings.Contains(err.Error(), "beyond") {
+		t.Errorf("wrote LTC on a missing channel: %v", err)
+	}
+}
+
+func TestTimecodeConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg TimecodeConfig
+		ok  bool
+	}{
+		{TimecodeConfig{Audio: "mic", InputChannel: 1}, true},
+		{TimecodeConfig{Audio: "mic", OutputChannel: 2, FrameRate: 30, DropFrame: true}, true},
+		{TimecodeConfig{InputChannel: 1}, false},
+		{TimecodeConfig{Audio: "mic"}, false},
+		{TimecodeConfig{Audio: "mic", InputChannel: -1}, false},
+		{TimecodeConfig{Audio: "mic", InputChannel: 1, FrameRate: 29}, false},
+		{TimecodeConfig{Audio: "mic", InputChannel: 1, FrameRate: 25, DropFrame: true}, false},
+		{TimecodeConfig{Audio: "mic", OutputChannel: 1, LevelDBFS: 6}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+}
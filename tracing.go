@@ -0,0 +1,106 @@
+package audio
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// GetAudio and Play are traced with OpenTelemetry: a span for the call,
+// which for GetAudio lasts as long as the stream, and spans within it for
+// opening the device, the first encode of a stream, converting and
+// normalizing clips, waiting for the device and playing on it. Spans go to
+// the global tracer provider, so nothing is recorded until the process sets
+// one with otel.SetTracerProvider.
+//
+// Clients send the trace context of the calling context with each call, in
+// the W3C traceparent and tracestate metadata, and the server continues the
+// trace from it, so a client's spans and the server's join up.
+
+// tracerName is the instrumentation scope of the spans.
+const tracerName = "github.com/oliviamiller/audioapi-poc"
+
+// tracePropagator reads and writes the trace context in call metadata. It's
+// fixed, rather than otel's global propagator, so that traces join up
+// without either side setting one.
+var tracePropagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// metadataCarrier adapts gRPC metadata to the propagator.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// startServerSpan starts the span of a call to the named resource,
+// continuing the trace the client sent.
+func startServerSpan(ctx context.Context, name, resource string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = tracePropagator.Extract(ctx, metadataCarrier(md))
+	}
+	return tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(append([]attribute.KeyValue{attribute.String("audio.resource", resource)}, attrs...)...))
+}
+
+// startSpan starts a span within the one of ctx.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, as failed if err isn't nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingConn sends the trace context of each call's context in its
+// metadata.
+type tracingConn struct {
+	grpc.ClientConnInterface
+}
+
+func (c tracingConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return c.ClientConnInterface.Invoke(injectTrace(ctx), method, args, reply, opts...)
+}
+
+func (c tracingConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.ClientConnInterface.NewStream(injectTrace(ctx), desc, method, opts...)
+}
+
+func injectTrace(ctx context.Context) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	tracePropagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
@@ -0,0 +1,88 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.viam.com/rdk/logging"
+)
+
+func TestTracing(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f, err := NewFake(Named("traced"), FakeConfig{Unpaced: true, PlaybackSampleRates: []int{16000}}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close(ctx)
+	c := serveAudio(t, f)
+
+	ctx, client := provider.Tracer("test").Start(ctx, "client")
+	clip, _ := encodePCM(tone(8000, 800, 440, 0.5), Pcm16)
+	if err := c.Play(ConvertPlayback(ctx), clip, "pcm16", 8000, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Play(ctx, clip, "pcm16", 8000, 1); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("an unplayable clip failed with %v", err)
+	}
+	ch, err := c.GetAudio(ctx, "pcm16", 0.1, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+	}
+	client.End()
+
+	// the server ends the stream's span as it returns, which can be after the
+	// client has seen the stream end
+	var ended []sdktrace.ReadOnlySpan
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		ended = spans.Ended()
+		if slices.ContainsFunc(ended, func(s sdktrace.ReadOnlySpan) bool { return s.Name() == "audio.GetAudio" }) {
+			break
+		}
+	}
+	byName := map[string][]sdktrace.ReadOnlySpan{}
+	for _, s := range ended {
+		byName[s.Name()] = append(byName[s.Name()], s)
+	}
+	for _, name := range []string{"audio.GetAudio", "audio.open_device", "audio.encode", "audio.Play", "audio.convert", "audio.wait_device", "audio.play_device"} {
+		if len(byName[name]) == 0 {
+			t.Errorf("no %s span among %v", name, byName)
+		}
+	}
+	// the server's spans continue the client's trace
+	for _, name := range []string{"audio.Play", "audio.GetAudio"} {
+		for _, s := range byName[name] {
+			if s.Parent().SpanID() != client.SpanContext().SpanID() || s.SpanContext().TraceID() != client.SpanContext().TraceID() {
+				t.Errorf("%s isn't a child of the client's span", name)
+			}
+		}
+	}
+	for _, name := range []string{"audio.open_device", "audio.encode"} {
+		for _, s := range byName[name] {
+			if s.Parent().SpanID() != byName["audio.GetAudio"][0].SpanContext().SpanID() {
+				t.Errorf("%s isn't within the stream's span", name)
+			}
+		}
+	}
+	if plays := byName["audio.Play"]; len(plays) != 2 || plays[0].Status().Code != otelcodes.Unset || plays[1].Status().Code != otelcodes.Error {
+		t.Error("the failed Play isn't marked as an error")
+	}
+}
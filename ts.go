@@ -0,0 +1,193 @@
+package audio
+
+import (
+	"io"
+)
+
+// MPEG transport stream layout used for HLS segments: one program whose only
+// elementary stream is the audio, which also carries the PCR.
+const (
+	tsPacketSize  = 188
+	tsPayloadSize = tsPacketSize - 4
+	tsPATPID      = 0x0000
+	tsPMTPID      = 0x1000
+	tsAudioPID    = 0x0100
+
+	// stream types for MPEG-1 (32 kHz and up) and MPEG-2 (lower rates) audio
+	tsStreamTypeMPEG1Audio = 0x03
+	tsStreamTypeMPEG2Audio = 0x04
+
+	tsAudioStreamID = 0xC0
+)
+
+// tsMuxer writes audio PES packets into an MPEG transport stream.
+type tsMuxer struct {
+	w          io.Writer
+	streamType byte
+	counters   map[uint16]byte
+}
+
+func newTSMuxer(w io.Writer, streamType byte) *tsMuxer {
+	return &tsMuxer{w: w, streamType: streamType, counters: map[uint16]byte{}}
+}
+
+// writeTables writes the PAT and PMT. Every HLS segment starts with them so
+// it can be decoded on its own.
+func (m *tsMuxer) writeTables() error {
+	pat := []byte{
+		0x00,       // table_id
+		0xB0, 0x0D, // section_syntax_indicator, section_length 13
+		0x00, 0x01, // transport_stream_id
+		0xC1,       // version 0, current_next_indicator
+		0x00, 0x00, // section_number, last_section_number
+		0x00, 0x01, // program_number
+		0xE0 | byte(tsPMTPID>>8), byte(tsPMTPID & 0xFF),
+	}
+	if err := m.writeSection(tsPATPID, pat); err != nil {
+		return err
+	}
+
+	pmt := []byte{
+		0x02,       // table_id
+		0xB0, 0x12, // section_syntax_indicator, section_length 18
+		0x00, 0x01, // program_number
+		0xC1,       // version 0, current_next_indicator
+		0x00, 0x00, // section_number, last_section_number
+		0xE0 | byte(tsAudioPID>>8), byte(tsAudioPID & 0xFF), // PCR_PID
+		0xF0, 0x00, // program_info_length
+		m.streamType,
+		0xE0 | byte(tsAudioPID>>8), byte(tsAudioPID & 0xFF),
+		0xF0, 0x00, // ES_info_length
+	}
+	return m.writeSection(tsPMTPID, pmt)
+}
+
+// writeSection writes a PSI section and its CRC in a single packet.
+func (m *tsMuxer) writeSection(pid uint16, section []byte) error {
+	crc := crc32MPEG(section)
+	payload := make([]byte, tsPayloadSize)
+	payload[0] = 0x00 // pointer_field
+	n := copy(payload[1:], section)
+	payload[1+n] = byte(crc >> 24)
+	payload[2+n] = byte(crc >> 16)
+	payload[3+n] = byte(crc >> 8)
+	payload[4+n] = byte(crc)
+	for i := 5 + n; i < len(payload); i++ {
+		payload[i] = 0xFF
+	}
+	_, err := m.writePacket(pid, true, -1, payload)
+	return err
+}
+
+// writePES writes data as one PES packet stamped with pts, in 90 kHz units.
+// The first packet also carries the PCR.
+func (m *tsMuxer) writePES(pts int64, data []byte) error {
+	pes := make([]byte, 0, 14+len(data))
+	pesLen := 8 + len(data) // header extension plus payload
+	pes = append(pes,
+		0x00, 0x00, 0x01, tsAudioStreamID,
+		byte(pesLen>>8), byte(pesLen),
+		0x80, // marker bits
+		0x80, // PTS only
+		0x05, // PES_header_data_length
+		0x21|byte(pts>>29)&0x0E,
+		byte(pts>>22),
+		0x01|byte(pts>>14)&0xFE,
+		byte(pts>>7),
+		0x01|byte(pts<<1)&0xFE,
+	)
+	pes = append(pes, data...)
+
+	pcr := pts
+	for first := true; len(pes) > 0; first = false {
+		n, err := m.writePacket(tsAudioPID, first, pcr, pes)
+		if err != nil {
+			return err
+		}
+		pes = pes[n:]
+		pcr = -1
+	}
+	return nil
+}
+
+// writePacket writes one transport packet carrying as much of payload as fits
+// and returns how many bytes that was. Short payloads are padded with
+// adaptation field stuffing. pcr is in 90 kHz units, negative for none.
+func (m *tsMuxer) writePacket(pid uint16, start bool, pcr int64, payload []byte) (int, error) {
+	var adaptation []byte // adaptation field after its length byte
+	hasAdaptation := false
+	if pcr >= 0 {
+		hasAdaptation = true
+		adaptation = append(adaptation,
+			0x10, // PCR_flag
+			byte(pcr>>25), byte(pcr>>17), byte(pcr>>9), byte(pcr>>1),
+			byte(pcr<<7)|0x7E, 0x00,
+		)
+	}
+	room := tsPayloadSize
+	if hasAdaptation {
+		room -= 1 + len(adaptation)
+	}
+	n := len(payload)
+	if n < room {
+		stuffing := room - n
+		if !hasAdaptation {
+			hasAdaptation = true
+			stuffing-- // the length byte
+			if stuffing > 0 {
+				adaptation = append(adaptation, 0x00)
+				stuffing--
+			}
+		}
+		for ; stuffing > 0; stuffing-- {
+			adaptation = append(adaptation, 0xFF)
+		}
+	} else {
+		n = room
+	}
+
+	pkt := make([]byte, 0, tsPacketSize)
+	flags := byte(0)
+	if start {
+		flags = 0x40 // payload_unit_start_indicator
+	}
+	control := byte(0x10) // payload only
+	if hasAdaptation {
+		control = 0x30
+	}
+	cc := m.counters[pid]
+	m.counters[pid] = (cc + 1) & 0x0F
+	pkt = append(pkt, 0x47, flags|byte(pid>>8)&0x1F, byte(pid), control|cc)
+	if hasAdaptation {
+		pkt = append(pkt, byte(len(adaptation)))
+		pkt = append(pkt, adaptation...)
+	}
+	pkt = append(pkt, payload[:n]...)
+	_, err := m.w.Write(pkt)
+	return n, err
+}
+
+var crc32MPEGTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		c := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if c&0x80000000 != 0 {
+				c = c<<1 ^ 0x04C11DB7
+			} else {
+				c <<= 1
+			}
+		}
+		table[i] = c
+	}
+	return table
+}()
+
+// crc32MPEG is the unreflected CRC-32 PSI sections are checked with.
+func crc32MPEG(data []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc = crc<<8 ^ crc32MPEGTable[byte(crc>>24)^b]
+	}
+	return crc
+}
@@ -0,0 +1,122 @@
+package audio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCRC32MPEG(t *testing.T) {
+	// the CRC-32/MPEG-2 check value
+	if got := crc32MPEG([]byte("123456789")); got != 0x0376E6E7 {
+		t.Fatalf("got %#08x, want 0x0376e6e7", got)
+	}
+}
+
+func TestTSMuxerTables(t *testing.T) {
+	var buf bytes.Buffer
+	m := newTSMuxer(&buf, tsStreamTypeMPEG1Audio)
+	if err := m.writeTables(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 2*tsPacketSize {
+		t.Fatalf("tables took %d bytes, want two packets", buf.Len())
+	}
+	// the PAT ffmpeg writes for one program with its PMT on PID 0x1000
+	wantPAT := []byte{
+		0x47, 0x40, 0x00, 0x10, 0x00,
+		0x00, 0xB0, 0x0D, 0x00, 0x01, 0xC1, 0x00, 0x00, 0x00, 0x01, 0xF0, 0x00,
+		0x2A, 0xB1, 0x04, 0xB2,
+	}
+	pat := buf.Bytes()[:tsPacketSize]
+	if !bytes.Equal(pat[:len(wantPAT)], wantPAT) {
+		t.Fatalf("PAT\n got % x\nwant % x", pat[:len(wantPAT)], wantPAT)
+	}
+	for _, b := range pat[len(wantPAT):] {
+		if b != 0xFF {
+			t.Fatal("PAT is not padded with 0xff")
+		}
+	}
+	pmt := buf.Bytes()[tsPacketSize:]
+	if pid := uint16(pmt[1]&0x1F)<<8 | uint16(pmt[2]); pid != tsPMTPID {
+		t.Fatalf("PMT on PID %#x", pid)
+	}
+	section := pmt[5 : 5+3+18]
+	if crc := crc32MPEG(section); crc != 0 {
+		t.Fatalf("PMT section with its CRC checks to %#x, want 0", crc)
+	}
+}
+
+// decodeTimestamp reads a 33-bit PTS from its 5-byte PES encoding.
+func decodeTimestamp(b []byte) int64 {
+	return int64(b[0]>>1&0x07)<<30 | int64(b[1])<<22 | int64(b[2]>>1)<<15 | int64(b[3])<<7 | int64(b[4]>>1)
+}
+
+func TestTSMuxerPES(t *testing.T) {
+	const pts = int64(1)<<32 | 0x12345678 // uses all 33 bits
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	var buf bytes.Buffer
+	m := newTSMuxer(&buf, tsStreamTypeMPEG1Audio)
+	if err := m.writePES(pts, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.writePES(pts+2160, data[:10]); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len()%tsPacketSize != 0 {
+		t.Fatalf("wrote %d bytes, not whole packets", buf.Len())
+	}
+
+	var pes [][]byte
+	var pcrs []int64
+	for i, off := 0, 0; off < buf.Len(); i, off = i+1, off+tsPacketSize {
+		pkt := buf.Bytes()[off : off+tsPacketSize]
+		if pkt[0] != 0x47 {
+			t.Fatalf("packet %d has sync byte %#x", i, pkt[0])
+		}
+		if pid := uint16(pkt[1]&0x1F)<<8 | uint16(pkt[2]); pid != tsAudioPID {
+			t.Fatalf("packet %d on PID %#x", i, pid)
+		}
+		if cc := int(pkt[3] & 0x0F); cc != i&0x0F {
+			t.Fatalf("packet %d has continuity counter %d", i, cc)
+		}
+		payload := pkt[4:]
+		if pkt[3]&0x20 != 0 {
+			n := int(payload[0])
+			if n > 0 && payload[1]&0x10 != 0 {
+				a := payload[2:]
+				base := int64(a[0])<<25 | int64(a[1])<<17 | int64(a[2])<<9 | int64(a[3])<<1 | int64(a[4]>>7)
+				pcrs = append(pcrs, base)
+			}
+			payload = payload[1+n:]
+		}
+		if pkt[1]&0x40 != 0 {
+			pes = append(pes, nil)
+		}
+		pes[len(pes)-1] = append(pes[len(pes)-1], payload...)
+	}
+
+	if len(pcrs) != 2 || pcrs[0] != pts || pcrs[1] != pts+2160 {
+		t.Fatalf("got PCRs %v, want %d and %d", pcrs, pts, pts+2160)
+	}
+	if len(pes) != 2 {
+		t.Fatalf("got %d PES packets, want 2", len(pes))
+	}
+	for i, want := range [][]byte{data, data[:10]} {
+		p := pes[i]
+		if !bytes.Equal(p[:4], []byte{0, 0, 1, tsAudioStreamID}) {
+			t.Fatalf("PES %d starts % x", i, p[:4])
+		}
+		if n := int(p[4])<<8 | int(p[5]); n != len(p)-6 {
+			t.Fatalf("PES %d length field %d, have %d bytes", i, n, len(p)-6)
+		}
+		if got := decodeTimestamp(p[9:14]); got != pts+int64(i)*2160 {
+			t.Fatalf("PES %d PTS %d", i, got)
+		}
+		if !bytes.Equal(p[14:], want) {
+			t.Fatalf("PES %d payload differs", i)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package audio
+
+import (
+	"math"
+	"sort"
+)
+
+// Voice activity detection runs on 20ms frames of 16 kHz mono pcm16, which
+// every engine accepts, whatever the format of the stream it annotates.
+const (
+	vadRate          = 16000
+	vadFrame         = vadRate / 50
+	vadHangover      = 10 // frames still counted as speech after it stops, so words aren't clipped
+	defaultVADEngine = "energy"
+)
+
+// vadEngine classifies one frame as speech or not.
+type vadEngine interface {
+	isSpeech(frame []int16) (bool, error)
+}
+
+// vadEngines holds the engines WithVAD accepts. Builds with the webrtcvad
+// tag add "webrtc".
+var vadEngines = map[string]func() (vadEngine, error){
+	"energy": func() (vadEngine, error) { return newEnergyVAD(), nil },
+}
+
+// VADEngines lists the voice activity detectors that can be used with
+// WithVAD.
+func VADEngines() []string {
+	names := make([]string, 0, len(vadEngines))
+	for name := range vadEngines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// vad runs an engine over chunks of any raw pcm format. It is also the
+// "speech" Detector for only_when.
+type vad struct {
+	engine    vadEngine
+	hangover  int // frames, zero for the "speech" Detector, whose gate has a post-roll
+	resampler *resampler
+	rate      int
+	pending   []int16
+	hangLeft  int
+	speaking  bool // the decision for the last frame, kept by calls that complete none
+}
+
+func newVAD(engine string) (*vad, error) {
+	if engine == "" {
+		engine = defaultVADEngine
+	}
+	factory, ok := vadEngines[engine]
+	if !ok {
+		return nil, errorf(ErrInvalidArgument, "unknown vad engine %q, expected one of %v", engine, VADEngines())
+	}
+	e, err := factory()
+	if err != nil {
+		return nil, err
+	}
+	return &vad{engine: e, hangover: vadHangover}, nil
+}
+
+// Detect reports whether any frame completed by samples, or the hangover
+// after one, is speech. Audio that doesn't fill a frame waits for the next
+// call, which reports the decision for the last complete frame meanwhile.
+func (v *vad) Detect(samples []float32, info AudioInfo) bool {
+	speech, err := v.process(samples, info)
+	return err == nil && speech
+}
+
+func (v *vad) process(samples []float32, info AudioInfo) (bool, error) {
+	m := mono(samples, info.Channels)
+	if info.SampleRate != vadRate {
+		if v.resampler == nil || v.rate != info.SampleRate {
+			v.resampler, v.rate = newResampler(info.SampleRate, vadRate, 1), info.SampleRate
+		}
+		m = v.resampler.process(m)
+	}
+	for _, s := range m {
+		v.pending = append(v.pending, int16(clip(s)*32767))
+	}
+
+	speech, framed := false, false
+	for len(v.pending) >= vadFrame {
+		frame := v.pending[:vadFrame]
+		ok, err := v.engine.isSpeech(frame)
+		if err != nil {
+			return false, err
+		}
+		v.pending = v.pending[vadFrame:]
+		if ok {
+			v.hangLeft = v.hangover
+		} else if v.hangLeft > 0 {
+			v.hangLeft--
+			ok = true
+		}
+		speech, framed, v.speaking = speech || ok, true, ok
+	}
+	if !framed {
+		speech = v.speaking
+	}
+	// keep the buffer from creeping along the backing array forever
+	v.pending = append(v.pending[:0:0], v.pending...)
+	return speech, nil
+}
+
+// energyVAD is an energy detector with an adaptive noise floor. Frames well
+// above the floor whose zero-crossing rate is in the voiced range count as
+// speech; broadband hiss crosses zero far more often.
+type energyVAD struct {
+	noiseFloorDBFS float64
+}
+
+func newEnergyVAD() *energyVAD {
+	return &energyVAD{noiseFloorDBFS: -60}
+}
+
+func (d *energyVAD) isSpeech(frame []int16) (bool, error) {
+	var sum float64
+	crossings := 0
+	for i, s := range frame {
+		sum += float64(s) * float64(s)
+		if i > 0 && (frame[i-1] >= 0) != (s >= 0) {
+			crossings++
+		}
+	}
+	level := dbfs(math.Sqrt(sum/float64(len(frame))) / 32768)
+	zcr := float64(crossings) / float64(len(frame)-1)
+	speech := level > d.noiseFloorDBFS+9 && level > -55 && zcr < 0.25
+
+	// track the floor quickly downwards and slowly upwards so steady noise is learned
+	if level < d.noiseFloorDBFS {
+		d.noiseFloorDBFS = 0.7*d.noiseFloorDBFS + 0.3*level
+	} else if !speech {
+		d.noiseFloorDBFS = 0.98*d.noiseFloorDBFS + 0.02*level
+	}
+	return speech, nil
+}
+
+// annotate returns a copy of a raw pcm chunk with Speech set.
+func (v *vad) annotate(chunk *AudioChunk) (*AudioChunk, error) {
+	if chunk.Info == nil {
+		return nil, errUnknownSourceFormat
+	}
+	samples, err := decodePCM(chunk.AudioData, chunk.Info.Format)
+	if err != nil {
+		return nil, err
+	}
+	speech, err := v.process(samples, *chunk.Info)
+	if err != nil {
+		return nil, err
+	}
+	annotated := *chunk
+	annotated.Speech = &speech
+	return &annotated, nil
+}
@@ -0,0 +1,150 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// voiced renders an "ah" at a 120 Hz pitch.
+func voiced(rate, n int) []float32 {
+	return harmonics(rate, n, 120, 0.3, func(h int, f float64) float64 {
+		return (1 + 4*resonance(f, 700, 100) + 2*resonance(f, 1200, 150)) / float64(h)
+	})
+}
+
+func hiss(n int, level float32) []float32 {
+	out := make([]float32, n)
+	for i := range out {
+		out[i] = level * simNoise(uint64(i))
+	}
+	return out
+}
+
+func TestEnergyVAD(t *testing.T) {
+	for _, rate := range []int{8000, 16000, 48000} {
+		n := rate / 5
+		for _, tc := range []struct {
+			name    string
+			samples []float32
+			want    bool
+		}{
+			{"voiced speech", voiced(rate, n), true},
+			{"silence", make([]float32, n), false},
+			{"quiet speech", voiced(rate, n)[:n/2], true},
+			{"hiss", hiss(n, 0.3), false},
+		} {
+			if tc.name == "hiss" && rate < vadRate {
+				// upsampled hiss has no energy above 4 kHz and crosses zero like speech
+				continue
+			}
+			v, err := newVAD("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v.Detect(tc.samples, AudioInfo{Format: Pcm32Float, SampleRate: rate, Channels: 1}); got != tc.want {
+				t.Errorf("%d Hz %s: got %v, want %v", rate, tc.name, got, tc.want)
+			}
+		}
+	}
+	if _, err := newVAD("nope"); err == nil {
+		t.Fatal("unknown engine accepted")
+	}
+}
+
+func TestVADHangover(t *testing.T) {
+	info := AudioInfo{Format: Pcm32Float, SampleRate: vadRate, Channels: 1}
+	speech := voiced(vadRate, vadFrame*5)
+	silence := make([]float32, vadFrame)
+	for _, tc := range []struct {
+		name string
+		v    Detector
+		want int // silent frames still reported as speech
+	}{
+		{"vad", func() Detector { v, _ := newVAD(""); return v }(), vadHangover},
+		{"speech detector", detectorFactories["speech"](), 0},
+	} {
+		if !tc.v.Detect(speech, info) {
+			t.Fatalf("%s: speech not detected", tc.name)
+		}
+		held := 0
+		for i := 0; i < 2*vadHangover; i++ {
+			if tc.v.Detect(silence, info) {
+				held++
+			}
+		}
+		if held != tc.want {
+			t.Errorf("%s: %d frames of hangover, want %d", tc.name, held, tc.want)
+		}
+	}
+}
+
+func TestVADKeepsDecisionBetweenFrames(t *testing.T) {
+	// 5ms chunks at 48 kHz only complete a frame every fourth call
+	const rate, chunk = 48000, 240
+	info := AudioInfo{Format: Pcm32Float, SampleRate: rate, Channels: 2}
+	speech := voiced(rate, 40*chunk)
+	v, err := newVAD("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	detected := 0
+	for i := 0; i < 40; i++ {
+		if v.Detect(remix(speech[i*chunk:(i+1)*chunk], 1, 2), info) {
+			detected++
+		}
+	}
+	// the resampler and the first frame hold back the first few chunks
+	if detected < 35 {
+		t.Fatalf("speech detected in %d of 40 chunks", detected)
+	}
+}
+
+func TestCaptureHubSpeechOnly(t *testing.T) {
+	const rate = 16000
+	src := newBurstSource(50, AudioInfo{Format: Pcm16, SampleRate: rate, Channels: 1})
+	speech := voiced(rate, rate/10)
+	src.samples = func(i int) []float32 {
+		if i >= 10 && i < 20 {
+			return speech[(i-10)*rate/100 : (i-9)*rate/100]
+		}
+		return make([]float32, rate/100)
+	}
+	close(src.start)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	v, err := newVAD("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, err := newCaptureHub().open(ctx, src, captureRequest{target: AudioInfo{Format: Pcm16}, vad: v, speechOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var audio, gap time.Duration
+	first := true
+	for chunk := range ch {
+		if chunk.Err != nil {
+			t.Fatal(chunk.Err)
+		}
+		// speech is found once a frame completes, up to 20ms in
+		if first && (chunk.Gap < 100*time.Millisecond || chunk.Gap > 120*time.Millisecond) {
+			t.Fatalf("first chunk follows a %v gap, want the 100ms of leading silence", chunk.Gap)
+		}
+		first = false
+		if len(chunk.AudioData) > 0 && !*chunk.Speech {
+			t.Fatal("chunk without speech delivered")
+		}
+		d, _ := chunkDuration(chunk)
+		audio += d
+		gap += chunk.Gap
+	}
+	// the speech and its hangover
+	if want := 100*time.Millisecond + vadHangover*20*time.Millisecond; audio < want-20*time.Millisecond || audio > want+20*time.Millisecond {
+		t.Fatalf("delivered %v of audio, want about %v", audio, want)
+	}
+	if audio+gap != 500*time.Millisecond {
+		t.Fatalf("audio and gaps add up to %v, want 500ms", audio+gap)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build webrtcvad
+
+package audio
+
+import (
+	"encoding/binary"
+
+	webrtcvad "github.com/maxhawkins/go-webrtcvad"
+)
+
+// webrtcVADMode is the WebRTC aggressiveness, from 0 (most speech kept) to 3
+// (most noise rejected).
+const webrtcVADMode = 3
+
+func init() {
+	vadEngines["webrtc"] = func() (vadEngine, error) {
+		v, err := webrtcvad.New()
+		if err != nil {
+			return nil, err
+		}
+		if err := v.SetMode(webrtcVADMode); err != nil {
+			return nil, err
+		}
+		return &webrtcVAD{vad: v, buf: make([]byte, vadFrame*2)}, nil
+	}
+}
+
+// webrtcVAD is the GMM voice activity detector from WebRTC, through cgo.
+type webrtcVAD struct {
+	vad *webrtcvad.VAD
+	buf []byte
+}
+
+func (w *webrtcVAD) isSpeech(frame []int16) (bool, error) {
+	for i, s := range frame {
+		binary.LittleEndian.PutUint16(w.buf[i*2:], uint16(s))
+	}
+	return w.vad.Process(vadRate, w.buf)
+}
@@ -0,0 +1,154 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// WASAPIModel captures and plays through WASAPI in shared mode on Windows,
+// alongside other applications and converted to and from the mix format by
+// Windows. Directions following the default device move to the new one when
+// it changes. Every Windows build with cgo supports it; other builds know the
+// model but fail to construct it.
+var WASAPIModel = resource.NewModel("olivia", "audio", "wasapi")
+
+// Defaults for WASAPIConfig.
+const (
+	defaultWASAPIBuffer = 20 * time.Millisecond
+	// wasapiDefaultDevice is the device of a direction following the
+	// default endpoint
+	wasapiDefaultDevice = "default"
+)
+
+// WASAPIConfig is the configuration of the wasapi model. Devices are
+// endpoint names as shown in the Sound control panel, or endpoint IDs, both
+// listed by the list_devices command.
+type WASAPIConfig struct {
+	// CaptureDevice and PlaybackDevice follow the default endpoint if
+	// empty, including when it changes; "none" turns a direction off.
+	CaptureDevice    string `json:"capture_device,omitempty"`
+	PlaybackDevice   string `json:"playback_device,omitempty"`
+	SampleRate       int    `json:"sample_rate,omitempty"`       // 48 kHz if zero
+	Channels         int    `json:"channels,omitempty"`          // capture channels, 1 if zero
+	PlaybackChannels int    `json:"playback_channels,omitempty"` // 2 if zero
+	BufferMs         int    `json:"buffer_ms,omitempty"`         // endpoint buffer, 20ms if zero
+}
+
+// Validate checks the wasapi configuration.
+func (c *WASAPIConfig) Validate(path string) ([]string, []string, error) {
+	if c.SampleRate < 0 || c.Channels < 0 || c.PlaybackChannels < 0 || c.BufferMs < 0 {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("sample_rate, channels, playback_channels and buffer_ms cannot be negative"))
+	}
+	if c.CaptureDevice == noPCMDevice && c.PlaybackDevice == noPCMDevice {
+		return nil, nil, resource.NewConfigValidationError(path, errors.New("capture_device and playback_device cannot both be none"))
+	}
+	return nil, nil, nil
+}
+
+// wasapiEndpoint is an active audio endpoint.
+type wasapiEndpoint struct {
+	ID, Name string
+	Capture  bool
+	Default  bool
+}
+
+// openWASAPI opens a stream on an endpoint, and listWASAPIEndpoints lists
+// them, set by builds with WASAPI support. A stream on the default
+// endpoint, an empty device, fails once the default changes.
+var (
+	openWASAPI          func(device string, capture bool, p pcmParams) (pcmDevice, error)
+	listWASAPIEndpoints func() ([]wasapiEndpoint, error)
+)
+
+func init() {
+	registerModel(WASAPIModel, resource.Registration[Audio, *WASAPIConfig]{
+		AttributeMapConverter: migratingConverter[*WASAPIConfig](WASAPIModel),
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (Audio, error) {
+			cfg, err := resource.NativeConfig[*WASAPIConfig](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewWASAPI(conf.ResourceName(), *cfg, logger)
+		},
+	})
+}
+
+// NewWASAPI returns a resource on the configured WASAPI endpoints. Streams
+// are reopened when their endpoint goes away and comes back, and when the
+// default endpoint they follow changes.
+func NewWASAPI(name resource.Name, cfg WASAPIConfig, logger logging.Logger) (Audio, error) {
+	if openWASAPI == nil {
+		return nil, errors.New("this build has no WASAPI support, build on Windows with cgo")
+	}
+	rate := cfg.SampleRate
+	if rate == 0 {
+		rate = defaultPCMSampleRate
+	}
+	channels, playbackChannels := cfg.Channels, cfg.PlaybackChannels
+	if channels == 0 {
+		channels = defaultPCMChannels
+	}
+	if playbackChannels == 0 {
+		playbackChannels = defaultPCMPlaybackChannels
+	}
+	buffer := time.Duration(cfg.BufferMs) * time.Millisecond
+	if buffer == 0 {
+		buffer = defaultWASAPIBuffer
+	}
+	frames := max(4, int(buffer.Seconds()*float64(rate)))
+
+	a := &pcmAudio{
+		Named:   name.AsNamed(),
+		backend: "wasapi",
+		open: func(device string, capture bool, p pcmParams) (pcmDevice, error) {
+			endpoint := device
+			if device == wasapiDefaultDevice {
+				endpoint = ""
+			}
+			return dialReconnecting(func() (pcmDevice, error) { return openWASAPI(endpoint, capture, p) }, device, logger)
+		},
+		listDevices:    wasapiDevices,
+		capture:        wasapiDevice(cfg.CaptureDevice),
+		playback:       wasapiDevice(cfg.PlaybackDevice),
+		captureParams:  pcmParams{rate: rate, channels: channels, periodFrames: frames / 2, bufferFrames: frames},
+		playbackParams: pcmParams{rate: rate, channels: playbackChannels, periodFrames: frames / 2, bufferFrames: frames},
+		logger:         logger,
+		readers:        map[chan pcmBlock]struct{}{},
+	}
+	return a, nil
+}
+
+// wasapiDevice returns the device for one direction, empty for none.
+func wasapiDevice(configured string) string {
+	switch configured {
+	case noPCMDevice:
+		return ""
+	case "":
+		return wasapiDefaultDevice
+	default:
+		return configured
+	}
+}
+
+// wasapiDevices lists the active endpoints.
+func wasapiDevices() (deviceList, error) {
+	endpoints, err := listWASAPIEndpoints()
+	if err != nil {
+		return deviceList{}, err
+	}
+	var list deviceList
+	for _, e := range endpoints {
+		list.devices = append(list.devices, SoundDevice{ID: e.ID, Name: e.Name, Driver: "wasapi", Capture: e.Capture, Playback: !e.Capture})
+		switch {
+		case e.Default && e.Capture:
+			list.defaultCapture = e.ID
+		case e.Default:
+			list.defaultPlayback = e.ID
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,567 @@
+//go:build windows && cgo
+
+package audio
+
+/*
+#cgo LDFLAGS: -lole32
+#define COBJMACROS
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <initguid.h>
+#include <windows.h>
+#include <mmdeviceapi.h>
+#include <audioclient.h>
+#include <functiondiscoverykeys_devpkey.h>
+
+// results of vwa calls besides HRESULT errors, with the customer bit set
+#define VWA_GLITCH ((HRESULT)0xA0010001)
+#define VWA_DEFAULT_CHANGED ((HRESULT)0xA0010002)
+#define VWA_TIMEOUT ((HRESULT)0xA0010003)
+
+// how long to wait for the endpoint before giving up on it
+#define VWA_WAIT_MS 2000
+
+// vwa_notify tells a stream following the default endpoint that it changed.
+typedef struct {
+	IMMNotificationClient iface;
+	LONG refs;
+	volatile LONG changed;
+	EDataFlow flow;
+} vwa_notify;
+
+static HRESULT STDMETHODCALLTYPE notify_query(IMMNotificationClient *self, REFIID riid, void **out);
+
+static ULONG STDMETHODCALLTYPE notify_addref(IMMNotificationClient *self) {
+	return InterlockedIncrement(&((vwa_notify *)self)->refs);
+}
+
+static ULONG STDMETHODCALLTYPE notify_release(IMMNotificationClient *self) {
+	LONG refs = InterlockedDecrement(&((vwa_notify *)self)->refs);
+	if (refs == 0) {
+		free(self);
+	}
+	return refs;
+}
+
+static HRESULT STDMETHODCALLTYPE notify_query(IMMNotificationClient *self, REFIID riid, void **out) {
+	if (IsEqualIID(riid, &IID_IUnknown) || IsEqualIID(riid, &IID_IMMNotificationClient)) {
+		notify_addref(self);
+		*out = self;
+		return S_OK;
+	}
+	*out = NULL;
+	return E_NOINTERFACE;
+}
+
+static HRESULT STDMETHODCALLTYPE notify_state(IMMNotificationClient *self, LPCWSTR id, DWORD state) {
+	return S_OK;
+}
+
+static HRESULT STDMETHODCALLTYPE notify_device(IMMNotificationClient *self, LPCWSTR id) {
+	return S_OK;
+}
+
+static HRESULT STDMETHODCALLTYPE notify_default(IMMNotificationClient *self, EDataFlow flow, ERole role, LPCWSTR id) {
+	vwa_notify *n = (vwa_notify *)self;
+	if (flow == n->flow && role == eConsole) {
+		InterlockedExchange(&n->changed, 1);
+	}
+	return S_OK;
+}
+
+static HRESULT STDMETHODCALLTYPE notify_property(IMMNotificationClient *self, LPCWSTR id, const PROPERTYKEY key) {
+	return S_OK;
+}
+
+static IMMNotificationClientVtbl notify_vtbl = {
+	notify_query, notify_addref, notify_release,
+	notify_state, notify_device, notify_device, notify_default, notify_property,
+};
+
+// vwa is a shared mode stream on an endpoint, read and written from
+// whichever thread calls in. Capture packets are staged in a ring buffer as
+// they rarely match the size of a read.
+typedef struct {
+	IMMDeviceEnumerator *enumerator;
+	vwa_notify *notify; // if following the default endpoint
+	IAudioClient *client;
+	IAudioCaptureClient *capture;
+	IAudioRenderClient *render;
+	HANDLE event;
+	UINT32 buffer_frames;
+	uint8_t *ring;
+	size_t size, head, fill, frame;
+	int started, glitch;
+} vwa;
+
+// com_enumerator joins the calling thread to the multithreaded apartment,
+// which any thread a goroutine lands on may need, and returns the device
+// enumerator.
+static HRESULT com_enumerator(IMMDeviceEnumerator **out) {
+	CoInitializeEx(NULL, COINIT_MULTITHREADED);
+	return CoCreateInstance(&CLSID_MMDeviceEnumerator, NULL, CLSCTX_ALL, &IID_IMMDeviceEnumerator, (void **)out);
+}
+
+static char *to_utf8(LPCWSTR w) {
+	int n = WideCharToMultiByte(CP_UTF8, 0, w, -1, NULL, 0, NULL, NULL);
+	char *s = malloc(n > 0 ? n : 1);
+	s[0] = 0;
+	WideCharToMultiByte(CP_UTF8, 0, w, -1, s, n, NULL, NULL);
+	return s;
+}
+
+// friendly_name returns the endpoint's name, or an empty string.
+static char *friendly_name(IMMDevice *d) {
+	IPropertyStore *props;
+	PROPVARIANT v;
+	char *name = NULL;
+	if (SUCCEEDED(IMMDevice_OpenPropertyStore(d, STGM_READ, &props))) {
+		PropVariantInit(&v);
+		if (SUCCEEDED(IPropertyStore_GetValue(props, &PKEY_Device_FriendlyName, &v)) && v.vt == VT_LPWSTR) {
+			name = to_utf8(v.pwszVal);
+		}
+		PropVariantClear(&v);
+		IPropertyStore_Release(props);
+	}
+	return name ? name : strdup("");
+}
+
+static char *device_id(IMMDevice *d) {
+	LPWSTR id = NULL;
+	char *s;
+	if (FAILED(IMMDevice_GetId(d, &id))) {
+		return strdup("");
+	}
+	s = to_utf8(id);
+	CoTaskMemFree(id);
+	return s;
+}
+
+// find_device returns the active endpoint with an ID or name.
+static HRESULT find_device(IMMDeviceEnumerator *e, EDataFlow flow, const char *want, IMMDevice **out) {
+	IMMDeviceCollection *c;
+	UINT n = 0;
+	HRESULT hr = IMMDeviceEnumerator_EnumAudioEndpoints(e, flow, DEVICE_STATE_ACTIVE, &c);
+	if (FAILED(hr)) {
+		return hr;
+	}
+	IMMDeviceCollection_GetCount(c, &n);
+	*out = NULL;
+	for (UINT i = 0; i < n && *out == NULL; i++) {
+		IMMDevice *d;
+		if (FAILED(IMMDeviceCollection_Item(c, i, &d))) {
+			continue;
+		}
+		char *id = device_id(d), *name = friendly_name(d);
+		if (strcmp(id, want) == 0 || strcmp(name, want) == 0) {
+			*out = d;
+		} else {
+			IMMDevice_Release(d);
+		}
+		free(id);
+		free(name);
+	}
+	IMMDeviceCollection_Release(c);
+	return *out ? S_OK : HRESULT_FROM_WIN32(ERROR_NOT_FOUND);
+}
+
+// vwa_list returns the active endpoints as lines of capture, default, ID
+// and name separated by tabs.
+static HRESULT vwa_list(char **out) {
+	IMMDeviceEnumerator *e;
+	HRESULT hr = com_enumerator(&e);
+	if (FAILED(hr)) {
+		return hr;
+	}
+	size_t cap = 4096, len = 0;
+	char *buf = malloc(cap);
+	buf[0] = 0;
+	for (int capture = 0; capture < 2 && SUCCEEDED(hr); capture++) {
+		EDataFlow flow = capture ? eCapture : eRender;
+		IMMDevice *d;
+		IMMDeviceCollection *c;
+		UINT n = 0;
+		char *def = NULL;
+		if (SUCCEEDED(IMMDeviceEnumerator_GetDefaultAudioEndpoint(e, flow, eConsole, &d))) {
+			def = device_id(d);
+			IMMDevice_Release(d);
+		}
+		if (FAILED(hr = IMMDeviceEnumerator_EnumAudioEndpoints(e, flow, DEVICE_STATE_ACTIVE, &c))) {
+			free(def);
+			break;
+		}
+		IMMDeviceCollection_GetCount(c, &n);
+		for (UINT i = 0; i < n; i++) {
+			if (FAILED(IMMDeviceCollection_Item(c, i, &d))) {
+				continue;
+			}
+			char *id = device_id(d), *name = friendly_name(d);
+			size_t need = strlen(id) + strlen(name) + 8;
+			if (len + need > cap) {
+				cap = 2 * (len + need);
+				buf = realloc(buf, cap);
+			}
+			len += sprintf(buf + len, "%d\t%d\t%s\t%s\n", capture, def && strcmp(id, def) == 0, id, name);
+			free(id);
+			free(name);
+			IMMDevice_Release(d);
+		}
+		IMMDeviceCollection_Release(c);
+		free(def);
+	}
+	IMMDeviceEnumerator_Release(e);
+	if (FAILED(hr)) {
+		free(buf);
+		return hr;
+	}
+	*out = buf;
+	return S_OK;
+}
+
+static void vwa_free(vwa *v) {
+	if (v->client) {
+		IAudioClient_Stop(v->client);
+	}
+	if (v->capture) {
+		IAudioCaptureClient_Release(v->capture);
+	}
+	if (v->render) {
+		IAudioRenderClient_Release(v->render);
+	}
+	if (v->client) {
+		IAudioClient_Release(v->client);
+	}
+	if (v->notify) {
+		IMMDeviceEnumerator_UnregisterEndpointNotificationCallback(v->enumerator, &v->notify->iface);
+		notify_release(&v->notify->iface);
+	}
+	if (v->enumerator) {
+		IMMDeviceEnumerator_Release(v->enumerator);
+	}
+	if (v->event) {
+		CloseHandle(v->event);
+	}
+	free(v->ring);
+	free(v);
+}
+
+// vwa_open starts a stream on the endpoint named device, or on the default
+// one if it is empty, with Windows converting to and from the mix format.
+static HRESULT vwa_open(vwa **out, const char *device, int capture, int rate, int channels, int buffer_frames) {
+	vwa *v = calloc(1, sizeof(vwa));
+	EDataFlow flow = capture ? eCapture : eRender;
+	IMMDevice *d = NULL;
+	HRESULT hr;
+	v->frame = 2 * channels;
+	if (FAILED(hr = com_enumerator(&v->enumerator))) {
+		goto fail;
+	}
+	if (device[0] == 0) {
+		v->notify = calloc(1, sizeof(vwa_notify));
+		v->notify->iface.lpVtbl = &notify_vtbl;
+		v->notify->refs = 1;
+		v->notify->flow = flow;
+		// registered first so a change while opening isn't missed
+		if (FAILED(hr = IMMDeviceEnumerator_RegisterEndpointNotificationCallback(v->enumerator, &v->notify->iface))) {
+			notify_release(&v->notify->iface);
+			v->notify = NULL;
+			goto fail;
+		}
+		hr = IMMDeviceEnumerator_GetDefaultAudioEndpoint(v->enumerator, flow, eConsole, &d);
+	} else {
+		hr = find_device(v->enumerator, flow, device, &d);
+	}
+	if (FAILED(hr)) {
+		goto fail;
+	}
+	hr = IMMDevice_Activate(d, &IID_IAudioClient, CLSCTX_ALL, NULL, (void **)&v->client);
+	IMMDevice_Release(d);
+	if (FAILED(hr)) {
+		goto fail;
+	}
+
+	WAVEFORMATEX format = {
+		.wFormatTag = WAVE_FORMAT_PCM,
+		.nChannels = channels,
+		.nSamplesPerSec = rate,
+		.nAvgBytesPerSec = rate * v->frame,
+		.nBlockAlign = v->frame,
+		.wBitsPerSample = 16,
+	};
+	REFERENCE_TIME duration = (REFERENCE_TIME)buffer_frames * 10000000 / rate;
+	DWORD flags = AUDCLNT_STREAMFLAGS_EVENTCALLBACK | AUDCLNT_STREAMFLAGS_AUTOCONVERTPCM | AUDCLNT_STREAMFLAGS_SRC_DEFAULT_QUALITY;
+	if (FAILED(hr = IAudioClient_Initialize(v->client, AUDCLNT_SHAREMODE_SHARED, flags, duration, 0, &format, NULL))) {
+		goto fail;
+	}
+	v->event = CreateEvent(NULL, FALSE, FALSE, NULL);
+	if (FAILED(hr = IAudioClient_SetEventHandle(v->client, v->event)) ||
+			FAILED(hr = IAudioClient_GetBufferSize(v->client, &v->buffer_frames))) {
+		goto fail;
+	}
+	if (capture) {
+		hr = IAudioClient_GetService(v->client, &IID_IAudioCaptureClient, (void **)&v->capture);
+		v->size = 2 * v->buffer_frames * v->frame;
+		v->ring = malloc(v->size);
+	} else {
+		hr = IAudioClient_GetService(v->client, &IID_IAudioRenderClient, (void **)&v->render);
+	}
+	if (FAILED(hr) || FAILED(hr = IAudioClient_Start(v->client))) {
+		goto fail;
+	}
+	*out = v;
+	return S_OK;
+
+fail:
+	vwa_free(v);
+	return hr;
+}
+
+static int default_changed(vwa *v) {
+	return v->notify && InterlockedCompareExchange(&v->notify->changed, 0, 0);
+}
+
+static void ring_put(vwa *v, const uint8_t *p, size_t n) {
+	size_t tail = (v->head + v->fill) % v->size;
+	size_t first = n < v->size - tail ? n : v->size - tail;
+	if (p) {
+		memcpy(v->ring + tail, p, first);
+		memcpy(v->ring, p + first, n - first);
+	} else {
+		memset(v->ring + tail, 0, first);
+		memset(v->ring, 0, n - first);
+	}
+	v->fill += n;
+}
+
+static void ring_get(vwa *v, uint8_t *p, size_t n) {
+	size_t first = n < v->size - v->head ? n : v->size - v->head;
+	memcpy(p, v->ring + v->head, first);
+	memcpy(p + first, v->ring, n - first);
+	v->head = (v->head + n) % v->size;
+	v->fill -= n;
+}
+
+// vwa_read fills p from the packets captured, or reports a glitch since
+// the last recovery.
+static HRESULT vwa_read(vwa *v, uint8_t *p, size_t n) {
+	while (v->fill < n) {
+		UINT32 packet = 0, frames;
+		BYTE *data;
+		DWORD flags;
+		HRESULT hr;
+		if (default_changed(v)) {
+			return VWA_DEFAULT_CHANGED;
+		}
+		if (v->glitch) {
+			return VWA_GLITCH;
+		}
+		if (FAILED(hr = IAudioCaptureClient_GetNextPacketSize(v->capture, &packet))) {
+			return hr;
+		}
+		if (packet == 0) {
+			if (WaitForSingleObject(v->event, VWA_WAIT_MS) != WAIT_OBJECT_0) {
+				return VWA_TIMEOUT;
+			}
+			continue;
+		}
+		if (FAILED(hr = IAudioCaptureClient_GetBuffer(v->capture, &data, &frames, &flags, NULL, NULL))) {
+			return hr;
+		}
+		size_t bytes = frames * v->frame;
+		// the first packet is always discontinuous
+		if ((flags & AUDCLNT_BUFFERFLAGS_DATA_DISCONTINUITY) && v->started) {
+			v->glitch = 1;
+		}
+		if (bytes > v->size - v->fill) {
+			v->glitch = 1;
+			bytes = v->size - v->fill;
+		}
+		ring_put(v, (flags & AUDCLNT_BUFFERFLAGS_SILENT) ? NULL : data, bytes);
+		IAudioCaptureClient_ReleaseBuffer(v->capture, frames);
+		v->started = 1;
+	}
+	ring_get(v, p, n);
+	return S_OK;
+}
+
+// vwa_write renders as much of p as fits once there is room, and sets
+// written to how many frames that was.
+static HRESULT vwa_write(vwa *v, const uint8_t *p, size_t n, UINT32 *written) {
+	UINT32 padding, room;
+	BYTE *data;
+	HRESULT hr;
+	for (;;) {
+		if (default_changed(v)) {
+			return VWA_DEFAULT_CHANGED;
+		}
+		if (FAILED(hr = IAudioClient_GetCurrentPadding(v->client, &padding))) {
+			return hr;
+		}
+		if ((room = v->buffer_frames - padding) > 0) {
+			break;
+		}
+		if (WaitForSingleObject(v->event, VWA_WAIT_MS) != WAIT_OBJECT_0) {
+			return VWA_TIMEOUT;
+		}
+	}
+	UINT32 frames = n / v->frame < room ? n / v->frame : room;
+	if (FAILED(hr = IAudioRenderClient_GetBuffer(v->render, frames, &data))) {
+		return hr;
+	}
+	memcpy(data, p, frames * v->frame);
+	IAudioRenderClient_ReleaseBuffer(v->render, frames, 0);
+	*written = frames;
+	return S_OK;
+}
+
+// vwa_drain waits for what was written to play. Audio written before the
+// default endpoint changed finishes on the old one.
+static HRESULT vwa_drain(vwa *v) {
+	UINT32 padding;
+	HRESULT hr;
+	for (;;) {
+		if (FAILED(hr = IAudioClient_GetCurrentPadding(v->client, &padding))) {
+			return hr;
+		}
+		if (padding == 0 || default_changed(v)) {
+			return S_OK;
+		}
+		if (WaitForSingleObject(v->event, VWA_WAIT_MS) != WAIT_OBJECT_0) {
+			return VWA_TIMEOUT;
+		}
+	}
+}
+
+// vwa_recover drops the capture staged before a glitch.
+static void vwa_recover(vwa *v) {
+	v->glitch = 0;
+	v->head = 0;
+	v->fill = 0;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+func init() {
+	openWASAPI = openWASAPIStream
+	listWASAPIEndpoints = listEndpoints
+}
+
+var (
+	errWASAPIGlitch         = errors.New("capture glitch")
+	errWASAPIDefaultChanged = errors.New("the default device changed")
+	errWASAPITimeout        = errors.New("the device stopped processing audio")
+)
+
+// wasapiError is an HRESULT from WASAPI or COM.
+type wasapiError C.HRESULT
+
+// wasapiErrors names the HRESULTs seen when a device goes away or won't
+// take a stream.
+var wasapiErrors = map[uint32]string{
+	0x88890004: "the device was removed or disabled",
+	0x8889000A: "the device is in use in exclusive mode",
+	0x88890008: "the device doesn't support the format",
+	0x80070490: "no such device",
+}
+
+func (e wasapiError) Error() string {
+	if msg, ok := wasapiErrors[uint32(e)]; ok {
+		return "wasapi: " + msg
+	}
+	return fmt.Sprintf("wasapi error 0x%08X", uint32(e))
+}
+
+func hresult(hr C.HRESULT) error {
+	switch {
+	case hr >= 0:
+		return nil
+	case hr == C.VWA_GLITCH:
+		return errWASAPIGlitch
+	case hr == C.VWA_DEFAULT_CHANGED:
+		return errWASAPIDefaultChanged
+	case hr == C.VWA_TIMEOUT:
+		return errWASAPITimeout
+	default:
+		return wasapiError(hr)
+	}
+}
+
+// wasapiStream is a shared mode stream. It recovers from glitches itself;
+// when its device goes away or the default it follows changes it has to be
+// reopened.
+type wasapiStream struct {
+	v        *C.vwa
+	channels int
+}
+
+func openWASAPIStream(device string, capture bool, p pcmParams) (pcmDevice, error) {
+	cdev := C.CString(device)
+	defer C.free(unsafe.Pointer(cdev))
+	c := C.int(0)
+	if capture {
+		c = 1
+	}
+	var v *C.vwa
+	if err := hresult(C.vwa_open(&v, cdev, c, C.int(p.rate), C.int(p.channels), C.int(p.bufferFrames))); err != nil {
+		return nil, err
+	}
+	return &wasapiStream{v: v, channels: p.channels}, nil
+}
+
+func listEndpoints() ([]wasapiEndpoint, error) {
+	var out *C.char
+	if err := hresult(C.vwa_list(&out)); err != nil {
+		return nil, err
+	}
+	defer C.free(unsafe.Pointer(out))
+	var endpoints []wasapiEndpoint
+	for _, line := range strings.Split(C.GoString(out), "\n") {
+		f := strings.SplitN(line, "\t", 4)
+		if len(f) < 4 {
+			continue
+		}
+		endpoints = append(endpoints, wasapiEndpoint{ID: f[2], Name: f[3], Capture: f[0] == "1", Default: f[1] == "1"})
+	}
+	return endpoints, nil
+}
+
+func (s *wasapiStream) read(buf []int16) (int, error) {
+	if err := hresult(C.vwa_read(s.v, (*C.uint8_t)(unsafe.Pointer(&buf[0])), C.size_t(2*len(buf)))); err != nil {
+		return 0, err
+	}
+	return len(buf) / s.channels, nil
+}
+
+func (s *wasapiStream) write(buf []int16) (int, error) {
+	var written C.UINT32
+	if err := hresult(C.vwa_write(s.v, (*C.uint8_t)(unsafe.Pointer(&buf[0])), C.size_t(2*len(buf)), &written)); err != nil {
+		return 0, err
+	}
+	return int(written), nil
+}
+
+func (s *wasapiStream) recover(err error) error {
+	if !errors.Is(err, errWASAPIGlitch) {
+		return err
+	}
+	C.vwa_recover(s.v)
+	return nil
+}
+
+func (s *wasapiStream) drain() error {
+	return hresult(C.vwa_drain(s.v))
+}
+
+func (s *wasapiStream) close() error {
+	C.vwa_free(s.v)
+	return nil
+}
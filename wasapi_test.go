@@ -0,0 +1,113 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestWASAPIFollowsDefault(t *testing.T) {
+	var mu sync.Mutex
+	var opened []string
+	prev := openWASAPI
+	openWASAPI = func(device string, capture bool, p pcmParams) (pcmDevice, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		opened = append(opened, device)
+		f := &fakePCM{p: p, period: time.Duration(p.periodFrames) * time.Second / time.Duration(p.rate), fail: map[int]bool{}}
+		if len(opened) == 1 {
+			// the default endpoint changes during the third read
+			f.fail[3] = true
+			f.lost = true
+		}
+		return f, nil
+	}
+	defer func() { openWASAPI = prev }()
+
+	a, err := NewWASAPI(Named("kiosk"), WASAPIConfig{PlaybackDevice: "none", SampleRate: 16000}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chunks, err := a.GetAudio(ctx, Pcm16.String(), 0.25, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames int
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatal(c.Err)
+		}
+		frames += len(c.AudioData) / 2
+	}
+	if frames != 4000 {
+		t.Errorf("captured %d frames, want 4000", frames)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(opened) != 2 || opened[0] != "" || opened[1] != "" {
+		t.Errorf("opened %q, want the default endpoint twice", opened)
+	}
+}
+
+func TestWASAPIListDevices(t *testing.T) {
+	prevOpen, prevList := openWASAPI, listWASAPIEndpoints
+	openWASAPI = func(string, bool, pcmParams) (pcmDevice, error) { return nil, errFakeXrun }
+	listWASAPIEndpoints = func() ([]wasapiEndpoint, error) {
+		return []wasapiEndpoint{
+			{ID: "{0.0.0.00000000}.{speakers}", Name: "Speakers (Realtek(R) Audio)", Default: true},
+			{ID: "{0.0.1.00000000}.{array}", Name: "Microphone Array (Realtek(R) Audio)", Capture: true, Default: true},
+			{ID: "{0.0.1.00000000}.{usb}", Name: "USB Microphone", Capture: true},
+		}, nil
+	}
+	defer func() { openWASAPI, listWASAPIEndpoints = prevOpen, prevList }()
+
+	a, err := NewWASAPI(Named("kiosk"), WASAPIConfig{}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := a.DoCommand(context.Background(), map[string]interface{}{"list_devices": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices := resp["devices"].([]interface{})
+	if len(devices) != 3 || resp["default_capture"] != "{0.0.1.00000000}.{array}" || resp["default_playback"] != "{0.0.0.00000000}.{speakers}" {
+		t.Fatalf("listed %v", resp)
+	}
+	if usb := devices[2].(map[string]interface{}); usb["name"] != "USB Microphone" || usb["capture"] != true || usb["playback"] != false {
+		t.Errorf("listed %v", usb)
+	}
+}
+
+func TestWASAPIConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg WASAPIConfig
+		ok  bool
+	}{
+		{WASAPIConfig{}, true},
+		{WASAPIConfig{CaptureDevice: "USB Microphone", PlaybackDevice: "none"}, true},
+		{WASAPIConfig{CaptureDevice: "none", PlaybackDevice: "none"}, false},
+		{WASAPIConfig{SampleRate: -1}, false},
+	} {
+		if _, _, err := tc.cfg.Validate("path"); (err == nil) != tc.ok {
+			t.Errorf("%+v: got %v", tc.cfg, err)
+		}
+	}
+	for configured, want := range map[string]string{"": "default", "none": "", "USB Microphone": "USB Microphone"} {
+		if got := wasapiDevice(configured); got != want {
+			t.Errorf("device %q is %q, want %q", configured, got, want)
+		}
+	}
+
+	prev := openWASAPI
+	openWASAPI = nil
+	defer func() { openWASAPI = prev }()
+	if _, err := NewWASAPI(Named("kiosk"), WASAPIConfig{}, logging.NewTestLogger(t)); err == nil {
+		t.Error("built without WASAPI support")
+	}
+}
@@ -0,0 +1,39 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// streamingDataSize is written into the size fields of a WAV header whose
+// length is not known up front. Most players treat it as "read until EOF".
+const streamingDataSize = 0xFFFFFFFF
+
+// newWAVHeader builds a PCM WAV header for the given format. Pass
+// streamingDataSize as dataSize for an open-ended stream.
+func newWAVHeader(sampleRate, channels, bitsPerSample int, dataSize uint32) wavHeader {
+	blockAlign := channels * bitsPerSample / 8
+	chunkSize := uint32(streamingDataSize)
+	if dataSize != streamingDataSize {
+		chunkSize = 36 + dataSize
+	}
+	return wavHeader{
+		ChunkID:       [4]byte{'R', 'I', 'F', 'F'},
+		ChunkSize:     chunkSize,
+		Format:        [4]byte{'W', 'A', 'V', 'E'},
+		Subchunk1ID:   [4]byte{'f', 'm', 't', ' '},
+		Subchunk1Size: 16,
+		AudioFormat:   1,
+		NumChannels:   uint16(channels),
+		SampleRate:    uint32(sampleRate),
+		ByteRate:      uint32(sampleRate * blockAlign),
+		BlockAlign:    uint16(blockAlign),
+		BitsPerSample: uint16(bitsPerSample),
+		Subchunk2ID:   [4]byte{'d', 'a', 't', 'a'},
+		Subchunk2Size: dataSize,
+	}
+}
+
+func writeWAVHeader(w io.Writer, h wavHeader) error {
+	return binary.Write(w, binary.LittleEndian, h)
+}
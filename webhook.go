@@ -0,0 +1,211 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// Defaults of a WebhookConfig.
+const (
+	defaultWebhookTimeout  = 5 * time.Second
+	defaultWebhookAttempts = 3
+	// webhookBackoff is the wait before the second attempt, doubled after
+	// each failed attempt.
+	webhookBackoff = time.Second
+	// webhookQueue is how many events wait to be posted before new ones are
+	// dropped.
+	webhookQueue = 100
+)
+
+// Types of WebhookEvent.
+const (
+	WebhookRecordingStarted = "recording_started"
+	WebhookRecordingStopped = "recording_stopped"
+	WebhookImpulse          = "impulse"
+	WebhookLevel            = "level"
+	WebhookDetection        = "detection"
+	WebhookDeviceLost       = "device_lost"
+	WebhookDeviceRecovered  = "device_recovered"
+)
+
+// WebhookConfig configures where a server posts its events.
+type WebhookConfig struct {
+	URLs []string // each event is posted to all of them
+	// BaseURL is where receivers reach the server's HTTP handler. If set,
+	// events with a clip carry its URL under /recordings/.
+	BaseURL string
+	// Secret, if set, signs each body with HMAC-SHA256, sent in the
+	// X-Audio-Signature header as sha256=<hex digest>.
+	Secret   string
+	Timeout  time.Duration // of each attempt, 5s if zero
+	Attempts int           // per URL, 3 if zero
+}
+
+// webhooksFromEnv reads the server's webhooks: AUDIO_WEBHOOK_URLS, comma
+// separated, AUDIO_WEBHOOK_BASE_URL and AUDIO_WEBHOOK_SECRET. URLs that
+// don't parse are ignored.
+func webhooksFromEnv() WebhookConfig {
+	cfg := WebhookConfig{BaseURL: os.Getenv("AUDIO_WEBHOOK_BASE_URL"), Secret: os.Getenv("AUDIO_WEBHOOK_SECRET")}
+	for _, u := range strings.Split(os.Getenv("AUDIO_WEBHOOK_URLS"), ",") {
+		if u = strings.TrimSpace(u); u == "" {
+			continue
+		}
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			serverLogger.Warnw("ignoring webhook URL, want an http or https URL", "url", u)
+			continue
+		}
+		cfg.URLs = append(cfg.URLs, u)
+	}
+	return cfg
+}
+
+// WebhookEvent is the JSON body posted for each event.
+type WebhookEvent struct {
+	Type            string    `json:"type"`
+	Resource        string    `json:"resource"`
+	Timestamp       time.Time `json:"timestamp"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	LevelDBFS       *float64  `json:"level_dbfs,omitempty"` // peak level, for impulse and level events
+	Detector        string    `json:"detector,omitempty"`   // the clip trigger that fired, for detections
+	Clip            string    `json:"clip,omitempty"`       // file name in the server's recording store
+	ClipURL         string    `json:"clip_url,omitempty"`
+	Device          string    `json:"device,omitempty"` // for device events
+	Reason          string    `json:"reason,omitempty"` // why a device was lost
+}
+
+// ServerWebhooks are the webhooks the server posts its recordings and
+// detections to, configured by webhooksFromEnv.
+var ServerWebhooks = NewWebhooks(webhooksFromEnv(), serverVerbosity.sublogger("webhooks"))
+
+// Webhooks posts events to HTTP endpoints in the background, so alerts can
+// be wired up without polling the event history. Events are posted one at a
+// time in the order notified.
+type Webhooks struct {
+	cfg     WebhookConfig
+	client  *http.Client
+	logger  logging.Logger
+	backoff time.Duration
+
+	start sync.Once
+	queue chan WebhookEvent
+}
+
+// NewWebhooks returns webhooks posting to cfg.URLs. Nothing is started until
+// the first event.
+func NewWebhooks(cfg WebhookConfig, logger logging.Logger) *Webhooks {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultWebhookTimeout
+	}
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = defaultWebhookAttempts
+	}
+	return &Webhooks{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		logger:  logger,
+		backoff: webhookBackoff,
+		queue:   make(chan WebhookEvent, webhookQueue),
+	}
+}
+
+// Notify queues ev to be posted, dropping it if the queue is full. It
+// doesn't block, so it can be called from capture loops.
+func (w *Webhooks) Notify(ev WebhookEvent) {
+	if len(w.cfg.URLs) == 0 {
+		return
+	}
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+	if ev.Clip != "" && w.cfg.BaseURL != "" {
+		ev.ClipURL = strings.TrimSuffix(w.cfg.BaseURL, "/") + "/recordings/" + url.PathEscape(ev.Clip)
+	}
+	w.start.Do(func() { go w.run() })
+	select {
+	case w.queue <- ev:
+	default:
+		w.logger.Warnw("webhook queue is full, dropping event", "type", ev.Type, "resource", ev.Resource)
+	}
+}
+
+func (w *Webhooks) run() {
+	for ev := range w.queue {
+		body, err := json.Marshal(ev)
+		if err != nil {
+			w.logger.Warnw("cannot encode webhook event", "type", ev.Type, "error", err)
+			continue
+		}
+		for _, u := range w.cfg.URLs {
+			if err := w.post(u, body); err != nil {
+				w.logger.Warnw("webhook failed", "url", u, "type", ev.Type, "error", err)
+			}
+		}
+	}
+}
+
+// post sends body to u, retrying server errors and failed connections.
+func (w *Webhooks) post(u string, body []byte) error {
+	wait := w.backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		if retry, err = w.postOnce(u, body); err == nil || !retry || attempt == w.cfg.Attempts {
+			return err
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// postOnce makes one attempt at posting body to u, reporting whether a
+// failure is worth retrying.
+func (w *Webhooks) postOnce(u string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.cfg.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Audio-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("%s", resp.Status)
+}
+
+// noteEvent records e in the server's history and posts it to the server's
+// webhooks.
+func noteEvent(e EventRecord) {
+	ServerHistory.recordEvent(e)
+	level := e.PeakDBFS
+	ServerWebhooks.Notify(WebhookEvent{
+		Type:            e.Kind,
+		Resource:        e.Resource,
+		Timestamp:       e.Timestamp,
+		DurationSeconds: e.Duration.Seconds(),
+		LevelDBFS:       &level,
+		Clip:            e.Clip,
+	})
+}
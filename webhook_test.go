@@ -0,0 +1,116 @@
+package audio
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestWebhooks(t *testing.T) {
+	type post struct {
+		signature string
+		body      []byte
+	}
+	posts := make(chan post, 10)
+	failures := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posts <- post{r.Header.Get("X-Audio-Signature"), body}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	w := NewWebhooks(WebhookConfig{URLs: []string{srv.URL}, BaseURL: "http://robot:8080/", Secret: "s3cret"}, logging.NewTestLogger(t))
+	w.backoff = time.Millisecond
+	level := -12.5
+	w.Notify(WebhookEvent{Type: WebhookLevel, Resource: "mic", LevelDBFS: &level, Clip: "mic 1.wav"})
+
+	var got post
+	for i := range 2 {
+		select {
+		case got = <-posts:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%d posts, want the event retried once", i)
+		}
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(got.body)
+	if got.signature != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("signature %q doesn't match the body", got.signature)
+	}
+	var ev WebhookEvent
+	if err := json.Unmarshal(got.body, &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != "level" || ev.Resource != "mic" || ev.LevelDBFS == nil || *ev.LevelDBFS != level || ev.Timestamp.IsZero() {
+		t.Errorf("posted %+v", ev)
+	}
+	if ev.ClipURL != "http://robot:8080/recordings/mic%201.wav" {
+		t.Errorf("clip URL %q", ev.ClipURL)
+	}
+
+	// without URLs nothing is started
+	off := NewWebhooks(WebhookConfig{}, logging.NewTestLogger(t))
+	off.Notify(WebhookEvent{Type: WebhookImpulse})
+	if len(off.queue) != 0 {
+		t.Error("queued an event without webhooks")
+	}
+}
+
+func TestServeRecording(t *testing.T) {
+	defer func(old string) { ServerRecordings.Dir = old }(ServerRecordings.Dir)
+	ServerRecordings.Dir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(ServerRecordings.Dir, "mic.wav"), []byte("RIFF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewHTTPHandler(func(string) (Audio, error) { return nil, errNoRecordingStore }, logging.NewTestLogger(t)))
+	defer srv.Close()
+	for path, want := range map[string]int{
+		"/recordings/mic.wav":      http.StatusOK,
+		"/recordings/other.wav":    http.StatusNotFound,
+		"/recordings/mic.txt":      http.StatusBadRequest,
+		"/recordings/..%2Fmic.wav": http.StatusBadRequest,
+		"/recordings/.hidden.wav":  http.StatusBadRequest,
+	} {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: %s, want %d", path, resp.Status, want)
+		}
+	}
+	// with an authenticator, recordings need a token like every other route
+	secret := []byte("s3cret")
+	authed := httptest.NewServer(AuthHTTPHandler(srv.Config.Handler, &JWTAuthenticator{Key: secret}))
+	defer authed.Close()
+	tok := signJWT(t, secret, map[string]any{"sub": "operator", "exp": time.Now().Add(time.Hour).Unix()})
+	for token, want := range map[string]int{"": http.StatusUnauthorized, tok: http.StatusOK} {
+		req, _ := http.NewRequest(http.MethodGet, authed.URL+"/recordings/mic.wav", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := authed.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("token %q: %s, want %d", token, resp.Status, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/viamrobotics/webrtc/v3"
+	"go.viam.com/rdk/logging"
+)
+
+// AudioTrack exposes a resource's capture as a WebRTC audio track, so it can
+// be added to the same peer connections Viam uses for camera streams. The
+// capture only runs while at least one peer connection has the track bound.
+//
+// G.711 tracks are encoded from the shared pcm capture and play in every
+// browser. Opus tracks pass through packets from a resource that captures
+// opus itself; the shared capture is never encoded to opus.
+type AudioTrack struct {
+	*webrtc.TrackLocalStaticRTP
+
+	audio  Audio
+	cfg    RTPConfig
+	logger logging.Logger
+
+	mu       sync.Mutex
+	bindings int
+	cancel   context.CancelFunc
+	run      int // counts capture runs so a finished one can tell if it was replaced
+}
+
+// NewAudioTrack returns a track for a's capture, identified by streamID in
+// the SDP. The zero RTPConfig gives a PCMU track, which works with any
+// resource that reports its capture format.
+func NewAudioTrack(a Audio, streamID string, cfg RTPConfig, logger logging.Logger) (*AudioTrack, error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	capability := webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypePCMU, ClockRate: g711SampleRate, Channels: 1}
+	switch cfg.Codec {
+	case RTPCodecPCMA:
+		capability.MimeType = webrtc.MimeTypePCMA
+	case RTPCodecOpus:
+		capability = webrtc.RTPCodecCapability{
+			MimeType:    webrtc.MimeTypeOpus,
+			ClockRate:   opusClockRate,
+			Channels:    2,
+			SDPFmtpLine: "minptime=10;useinbandfec=1",
+		}
+	}
+	track, err := webrtc.NewTrackLocalStaticRTP(capability, "audio", streamID)
+	if err != nil {
+		return nil, err
+	}
+	return &AudioTrack{TrackLocalStaticRTP: track, audio: a, cfg: cfg, logger: logger}, nil
+}
+
+// Bind starts the capture when the first peer connection binds the track,
+// refusing the binding if the start can't be written to the audit log.
+func (t *AudioTrack) Bind(ctx webrtc.TrackLocalContext) (webrtc.RTPCodecParameters, error) {
+	params, err := t.TrackLocalStaticRTP.Bind(ctx)
+	if err != nil {
+		return params, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cancel == nil {
+		if err := t.audit(AuditCaptureStarted, nil); err != nil {
+			return webrtc.RTPCodecParameters{}, errors.Join(err, t.TrackLocalStaticRTP.Unbind(ctx))
+		}
+	}
+	t.bindings++
+	if t.cancel == nil {
+		var streamCtx context.Context
+		streamCtx, t.cancel = context.WithCancel(context.Background())
+		t.run++
+		go t.stream(streamCtx, t.run)
+	}
+	return params, nil
+}
+
+// Unbind stops the capture once no peer connection has the track bound.
+func (t *AudioTrack) Unbind(ctx webrtc.TrackLocalContext) error {
+	if err := t.TrackLocalStaticRTP.Unbind(ctx); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.bindings--
+	if t.bindings == 0 {
+		t.stop()
+	}
+	return nil
+}
+
+// Close stops the capture regardless of bindings.
+func (t *AudioTrack) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stop()
+	return nil
+}
+
+func (t *AudioTrack) stop() {
+	if t.cancel != nil {
+		t.cancel()
+		t.cancel = nil
+	}
+}
+
+func (t *AudioTrack) stream(ctx context.Context, run int) {
+	// the track rewrites SSRC and payload type per binding, so the packets
+	// only need the right sequence numbers and timestamps
+	err := sendRTP(ctx, t.audio, t.cfg, func(pkt []byte) error {
+		_, err := t.Write(pkt)
+		return err
+	})
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	t.audit(AuditCaptureEnded, err)
+	if err != nil {
+		t.logger.Warnw("webrtc audio track stopped", "stream", t.StreamID(), "error", err)
+	}
+
+	// let the next Bind start a fresh capture
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.run == run {
+		t.stop()
+	}
+}
+
+// audit records the capture starting or ending in the server's audit log,
+// under the track's stream id.
+func (t *AudioTrack) audit(action string, err error) error {
+	return auditOperation(context.Background(), t.logger, t.audio.Name().ShortName(), action, t.StreamID(), "webrtc", err)
+}
@@ -0,0 +1,32 @@
+package audio
+
+import (
+	"testing"
+
+	"github.com/viamrobotics/webrtc/v3"
+	"go.viam.com/rdk/logging"
+)
+
+func TestNewAudioTrackCodecs(t *testing.T) {
+	src := newBurstSource(0, AudioInfo{Format: Pcm16, SampleRate: 48000, Channels: 1})
+	for _, tc := range []struct {
+		codec     string
+		mimeType  string
+		clockRate uint32
+	}{
+		{"", webrtc.MimeTypePCMU, g711SampleRate},
+		{RTPCodecPCMA, webrtc.MimeTypePCMA, g711SampleRate},
+		{RTPCodecOpus, webrtc.MimeTypeOpus, opusClockRate},
+	} {
+		track, err := NewAudioTrack(src, "mic", RTPConfig{Codec: tc.codec}, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := track.Codec(); c.MimeType != tc.mimeType || c.ClockRate != tc.clockRate {
+			t.Errorf("codec %q gave a %s/%d track, want %s/%d", tc.codec, c.MimeType, c.ClockRate, tc.mimeType, tc.clockRate)
+		}
+	}
+	if _, err := NewAudioTrack(src, "mic", RTPConfig{Codec: "g722"}, logging.NewTestLogger(t)); err == nil {
+		t.Fatal("accepted an unsupported codec")
+	}
+}